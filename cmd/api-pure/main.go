@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -32,6 +33,16 @@ import (
 // @name Authorization
 // @description Enter 'Bearer {token}' to authenticate
 func main() {
+	profile := flag.String("profile", "", "startup profile: dev, demo or prod (overrides ALCHEMORSEL_APP_PROFILE)")
+	flag.Parse()
+
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(*profile, flag.Args()[1:]); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
+	}
+
 	// Create Fx application with dependency injection for pure API
 	app := fx.New(
 		// Application metadata
@@ -39,6 +50,7 @@ func main() {
 		
 		// Provide all dependencies for pure API
 		container.PureAPIModule,
+		container.WithProfile(*profile),
 		
 		// Invoke startup functions
 		fx.Invoke(func() {
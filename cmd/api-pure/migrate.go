@@ -0,0 +1,108 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/infrastructure/persistence/migrations"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+)
+
+// migrationsDir is where `migrate create` writes, relative to the repo root
+const migrationsDir = "internal/infrastructure/persistence/migrations/sql"
+
+const migrateUsage = `usage: api-pure [-profile name] migrate <command>
+
+commands:
+  up           apply every pending migration
+  down         roll back the newest migration
+  reset        roll back every migration
+  status       show the current version and pending migrations
+  version      print the current version
+  force N      mark the database as at version N after a manual fix
+  create NAME  add an empty up and down pair to ` + migrationsDir
+
+// runMigrate runs a migrate subcommand against the configured Postgres
+// database, without starting the server
+func runMigrate(profile string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n\n%s", migrateUsage)
+	}
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return fmt.Errorf("create takes a name\n\n%s", migrateUsage)
+		}
+		paths, err := migrations.CreateMigration(migrationsDir, args[1])
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			fmt.Println("created", path)
+		}
+		return nil
+	}
+
+	cfg, err := config.LoadProfile("", profile)
+	if err != nil {
+		return err
+	}
+	if cfg.Database.Driver != "postgres" {
+		return fmt.Errorf("migrations target postgres; %s databases are migrated at startup", cfg.Database.Driver)
+	}
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	db, err := sql.Open("pgx", cfg.GetDSN())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	migrator, err := migrations.New(db, logger)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		return migrator.Up()
+	case "down":
+		return migrator.Down()
+	case "reset":
+		return migrator.Reset()
+	case "version":
+		version, dirty, err := migrator.Version()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d (dirty: %t)\n", version, dirty)
+		return nil
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version %d (dirty: %t), %d pending\n", status.Version, status.Dirty, len(status.Pending))
+		for _, p := range status.Pending {
+			fmt.Printf("  %06d %s\n", p.Version, p.Name)
+		}
+		return nil
+	case "force":
+		if len(args) != 2 {
+			return fmt.Errorf("force takes a version\n\n%s", migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		return migrator.Force(version)
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", args[0], migrateUsage)
+	}
+}
@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -32,6 +33,9 @@ import (
 // @name Authorization
 // @description Enter 'Bearer {token}' to authenticate
 func main() {
+	profile := flag.String("profile", "", "startup profile: dev, demo or prod (overrides ALCHEMORSEL_APP_PROFILE)")
+	flag.Parse()
+
 	// Create Fx application with dependency injection
 	app := fx.New(
 		// Application metadata
@@ -39,6 +43,7 @@ func main() {
 		
 		// Provide all dependencies
 		container.Module,
+		container.WithProfile(*profile),
 		
 		// Invoke startup functions
 		fx.Invoke(func() {
@@ -3,19 +3,35 @@ package main
 
 import (
 	"context"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"html"
 	"html/template"
 	"log"
 	"math/rand"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/alchemorsel/v3/internal/domain/recipe/units"
+	"github.com/alchemorsel/v3/internal/infrastructure/ai"
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/infrastructure/persistence/migrations"
+	"github.com/alchemorsel/v3/pkg/sqlsafe"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -49,12 +65,73 @@ type Recipe struct {
 	LikesCount      int       `json:"likes_count" gorm:"column:likes_count;default:0"`
 	ViewsCount      int       `json:"views_count" gorm:"column:views_count;default:0"`
 	AverageRating   float64   `json:"average_rating" gorm:"column:average_rating;default:0.0"`
-	Status          string    `json:"status" gorm:"default:'published'"`
+	Status          string    `json:"status" gorm:"default:'draft'"`
 	AIGenerated     bool      `json:"ai_generated" gorm:"column:ai_generated;default:false"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
+// Recipe statuses. Recipes start as drafts; readers other than the author
+// only see published ones.
+const (
+	statusDraft     = "draft"
+	statusPublished = "published"
+	statusArchived  = "archived"
+)
+
+// recipeStatusLabels names the statuses in the order the dashboard groups
+// them
+var recipeStatusLabels = [][2]string{
+	{statusDraft, "Drafts"},
+	{statusPublished, "Published"},
+	{statusArchived, "Archived"},
+}
+
+// recipeStatusBadges labels a single recipe's status on its card
+var recipeStatusBadges = map[string]string{
+	statusDraft:     "Draft",
+	statusPublished: "Published",
+	statusArchived:  "Archived",
+}
+
+// recipeTransition is a status change an author can make: the status it
+// leads to and the ones it may start from
+type recipeTransition struct {
+	to   string
+	from []string
+}
+
+// recipeTransitions are the status changes by action, in the order their
+// buttons are offered
+var recipeTransitions = []struct {
+	action string
+	label  string
+	recipeTransition
+}{
+	{"publish", "Publish", recipeTransition{to: statusPublished, from: []string{statusDraft, statusArchived}}},
+	{"unpublish", "Unpublish", recipeTransition{to: statusDraft, from: []string{statusPublished}}},
+	{"archive", "Archive", recipeTransition{to: statusArchived, from: []string{statusDraft, statusPublished}}},
+}
+
+// allows reports whether the transition can start from status
+func (t recipeTransition) allows(status string) bool {
+	for _, from := range t.from {
+		if from == status {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleTo keeps the recipes a user may see: published ones, and all of
+// their own. Signed out readers only see published recipes.
+func visibleTo(query *gorm.DB, user *User) *gorm.DB {
+	if user == nil {
+		return query.Where("recipes.status = ?", statusPublished)
+	}
+	return query.Where("(recipes.status = ? OR recipes.author_id = ?)", statusPublished, user.ID)
+}
+
 // Ingredient represents a recipe ingredient
 type Ingredient struct {
 	ID         string    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -90,16 +167,51 @@ type RecipeTag struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-// Session represents a user session
-type Session struct {
-	ID        string    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	UserID    string    `json:"user_id" gorm:"type:uuid"`
-	User      User      `json:"user" gorm:"foreignKey:UserID"`
-	Token     string    `json:"token" gorm:"uniqueIndex"`
-	ExpiresAt time.Time `json:"expires_at"`
-	CreatedAt time.Time `json:"created_at"`
+// Follow records that FollowerID follows FollowingID
+type Follow struct {
+	FollowerID  string    `json:"follower_id" gorm:"type:uuid;primaryKey"`
+	FollowingID string    `json:"following_id" gorm:"type:uuid;primaryKey;index"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName shares the API servers' follows table
+func (Follow) TableName() string {
+	return "user_follows"
 }
 
+// Session is one signed-in device. Token holds the SHA-256 of the current
+// refresh token, which is replaced on every refresh; PreviousToken keeps the
+// one it replaced so a stolen, already used token can be recognised.
+type Session struct {
+	ID            string     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID        string     `json:"user_id" gorm:"type:uuid;index"`
+	User          User       `json:"user" gorm:"foreignKey:UserID"`
+	Token         string     `json:"-" gorm:"uniqueIndex"`
+	PreviousToken string     `json:"-" gorm:"index"`
+	UserAgent     string     `json:"user_agent"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RotatedAt     *time.Time `json:"rotated_at"`
+	RevokedAt     *time.Time `json:"revoked_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+const (
+	// accessTokenTTL is how long a session_token JWT is accepted. Revoking
+	// a session also rejects its unexpired access tokens, since every
+	// request checks the session.
+	accessTokenTTL = 15 * time.Minute
+	// refreshTokenTTL is how long a sign-in lasts. Refreshing rotates the
+	// token but does not extend the session.
+	refreshTokenTTL = 30 * 24 * time.Hour
+	// refreshReuseGrace lets concurrent requests that raced on the same
+	// refresh token through without treating the loser as token theft
+	refreshReuseGrace = 30 * time.Second
+)
+
+// errSessionInvalid is returned for refresh tokens that are unknown,
+// expired or revoked
+var errSessionInvalid = errors.New("session expired or revoked")
+
 // RecipeIngredient represents a single ingredient
 type RecipeIngredient struct {
 	Name   string `json:"name"`
@@ -128,6 +240,10 @@ var (
 	templates *template.Template
 	jwtSecret []byte
 	
+	// aiProvider writes recipes and answers questions; nil means the
+	// template engine below is used
+	aiProvider ai.Provider
+	
 	// Recipe creation patterns for intent detection
 	recipeIntentPatterns = []*regexp.Regexp{
 		regexp.MustCompile(`(?i)\b(create|make|generate|cook|recipe for|how to make)\b.*\b(recipe|dish|food)\b`),
@@ -320,20 +436,99 @@ func extractDietaryRequirements(message string) []string {
 	return requirements
 }
 
-// generateRecipe creates a structured recipe based on the AI request
-func generateRecipe(request *AIRecipeRequest, userID string) (*Recipe, error) {
+// aiRecipe is a generated recipe with the rows saved alongside it
+type aiRecipe struct {
+	Recipe       *Recipe
+	Ingredients  []RecipeIngredient
+	Instructions []RecipeInstruction
+	Tags         []string
+	Featured     []string
+}
+
+// generateRecipe creates a structured recipe based on the AI request, using
+// the configured provider and falling back to templates when it fails
+func generateRecipe(ctx context.Context, request *AIRecipeRequest, message, userID string) (*aiRecipe, error) {
 	if request == nil {
 		return nil, fmt.Errorf("invalid recipe request")
 	}
 	
+	if aiProvider != nil {
+		generated, err := generateProviderRecipe(ctx, request, message, userID)
+		if err == nil {
+			return generated, nil
+		}
+		log.Printf("AI provider %s failed, using recipe templates: %v", aiProvider.Name(), err)
+	}
+	
+	return generateTemplateRecipe(request, userID), nil
+}
+
+// generateProviderRecipe asks the AI provider for the recipe, passing along
+// what the intent parser understood
+func generateProviderRecipe(ctx context.Context, request *AIRecipeRequest, message, userID string) (*aiRecipe, error) {
+	prompt := message
+	if request.Cuisine != "" && request.Cuisine != "fusion" {
+		prompt += "\nCuisine: " + request.Cuisine
+	}
+	if request.Difficulty != "" {
+		prompt += "\nDifficulty: " + request.Difficulty
+	}
+	if len(request.DietaryReqs) > 0 {
+		prompt += "\nDietary requirements: " + strings.Join(request.DietaryReqs, ", ")
+	}
+	
+	draft, err := ai.GenerateRecipe(ctx, aiProvider, prompt)
+	if err != nil {
+		return nil, err
+	}
+	
+	cuisine := strings.ToLower(draft.Cuisine)
+	if cuisine == "" {
+		cuisine = request.Cuisine
+	}
+	difficulty := strings.ToLower(draft.Difficulty)
+	if difficulty == "" {
+		difficulty = request.Difficulty
+	}
+	servings := draft.Servings
+	if servings <= 0 {
+		servings = 4
+	}
+	
+	generated := &aiRecipe{
+		Recipe: &Recipe{
+			Title:           draft.Title,
+			Description:     draft.Description,
+			AuthorID:        userID,
+			Cuisine:         cuisine,
+			Difficulty:      difficulty,
+			PrepTimeMinutes: draft.PrepTimeMinutes,
+			CookTimeMinutes: draft.CookTimeMinutes,
+			Servings:        servings,
+			Status:          statusDraft,
+			AIGenerated:     true,
+		},
+		Tags: append([]string{"ai-generated"}, draft.Tags...),
+	}
+	for _, ing := range draft.Ingredients {
+		generated.Ingredients = append(generated.Ingredients, RecipeIngredient{Name: ing.Name, Amount: ing.Amount, Unit: ing.Unit})
+		generated.Featured = append(generated.Featured, ing.Name)
+	}
+	for i, text := range draft.Instructions {
+		generated.Instructions = append(generated.Instructions, RecipeInstruction{Step: i + 1, Text: text})
+	}
+	
+	return generated, nil
+}
+
+// generateTemplateRecipe builds the recipe from the built-in templates
+func generateTemplateRecipe(request *AIRecipeRequest, userID string) *aiRecipe {
 	// Generate recipe title
 	title := generateRecipeTitle(request)
 	
 	// Generate description
 	description := generateRecipeDescription(request)
 	
-	// Note: ingredients, instructions, and tags will be generated when saving the recipe
-	
 	// Get template info for timing
 	templateKey := getTemplateKey(request.MainDish)
 	template := recipeTemplates[templateKey]
@@ -372,11 +567,17 @@ func generateRecipe(request *AIRecipeRequest, userID string) (*Recipe, error) {
 		LikesCount:      0,
 		ViewsCount:      0,
 		AverageRating:   0.0,
-		Status:          "published",
+		Status:          statusDraft,
 		AIGenerated:     true,
 	}
 	
-	return recipe, nil
+	return &aiRecipe{
+		Recipe:       recipe,
+		Ingredients:  generateIngredientsList(request),
+		Instructions: generateInstructions(request),
+		Tags:         generateTags(request),
+		Featured:     request.Ingredients,
+	}
 }
 
 // generateRecipeTitle creates an appropriate title
@@ -686,6 +887,9 @@ func main() {
 	// Initialize templates
 	initTemplates()
 
+	// Initialize AI provider
+	initAIProvider()
+
 	// Setup router
 	r := setupRouter()
 
@@ -714,6 +918,29 @@ func initJWTSecret() {
 	log.Printf("JWT secret initialized (%d bytes)", len(jwtSecret))
 }
 
+// initAIProvider selects the AI chef's backend from the ai section of the
+// configuration, falling back to recipe templates when none is configured
+func initAIProvider() {
+	cfg, err := config.Load("")
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+
+	aiProvider, err = ai.NewProvider(cfg.AI, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize AI provider: %v", err)
+	}
+	if aiProvider == nil {
+		log.Printf("AI chef using recipe templates")
+		return
+	}
+	log.Printf("AI chef using %s", aiProvider.Name())
+}
+
 func initDatabase() {
 	// Get database URL from environment or build from environment variables
 	dbURL := os.Getenv("DATABASE_URL")
@@ -772,11 +999,9 @@ func initDatabase() {
 		}
 	}
 
-	// Auto migrate with error handling for constraint conflicts
-	err = safeAutoMigrate(db)
-	if err != nil {
-		log.Printf("⚠️  Database migration failed: %v", err)
-		log.Printf("📝 Web server will continue without migrations")
+	// A half-migrated schema fails later in confusing ways, so stop here
+	if err := migrateSchema(db); err != nil {
+		log.Fatal("❌ Database migration failed:", err)
 	}
 
 	// Seed demo data
@@ -785,39 +1010,20 @@ func initDatabase() {
 	fmt.Println("✅ Database connected and migrated successfully")
 }
 
-func safeAutoMigrate(db *gorm.DB) error {
-	// Create tables if they don't exist first
-	if !db.Migrator().HasTable(&User{}) {
-		if err := db.Migrator().CreateTable(&User{}); err != nil {
-			return fmt.Errorf("failed to create users table: %w", err)
-		}
-	}
-	
-	if !db.Migrator().HasTable(&Recipe{}) {
-		if err := db.Migrator().CreateTable(&Recipe{}); err != nil {
-			return fmt.Errorf("failed to create recipes table: %w", err)
-		}
-	}
-	
-	if !db.Migrator().HasTable(&Session{}) {
-		if err := db.Migrator().CreateTable(&Session{}); err != nil {
-			return fmt.Errorf("failed to create sessions table: %w", err)
-		}
+// migrateSchema applies the versioned SQL migrations the API servers use.
+// They also create this prototype's sessions table, so the two never
+// disagree about a shared table.
+func migrateSchema(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
 	}
-
-	// Now run AutoMigrate to handle any schema changes
-	// This might fail on constraint operations, so we'll handle it gracefully
-	err := db.AutoMigrate(&User{}, &Recipe{}, &Session{}, &Ingredient{}, &Instruction{}, &RecipeTag{})
+	migrator, err := migrations.New(sqlDB, zap.NewNop())
 	if err != nil {
-		// Log the error but don't fail if it's a constraint issue
-		log.Printf("⚠️  Auto-migration warning (continuing anyway): %v", err)
-		// Check if tables exist and have basic structure
-		if !db.Migrator().HasTable(&User{}) || !db.Migrator().HasTable(&Recipe{}) || !db.Migrator().HasTable(&Session{}) {
-			return fmt.Errorf("tables don't exist and migration failed: %w", err)
-		}
+		return err
 	}
-	
-	return nil
+	defer migrator.Close()
+	return migrator.Up()
 }
 
 func startPostgreSQL() error {
@@ -1029,6 +1235,7 @@ func setupRouter() *chi.Mux {
 	r.Post("/auth/login", handleAuthLogin)
 	r.Post("/auth/register", handleAuthRegister)
 	r.Post("/auth/logout", handleAuthLogout)
+	r.Post("/auth/refresh", handleAuthRefresh)
 
 	// Protected routes - require authentication
 	r.Group(func(r chi.Router) {
@@ -1036,7 +1243,11 @@ func setupRouter() *chi.Mux {
 		r.Get("/dashboard", handleDashboard)
 		r.Get("/recipes/new", handleNewRecipe)
 		r.Post("/recipes", handleCreateRecipe)
+		for _, t := range recipeTransitions {
+			r.Post("/recipes/{id}/"+t.action, handleRecipeStatus(t.action, t.recipeTransition))
+		}
 		r.Get("/profile", handleProfile)
+		r.Post("/auth/logout-all", handleAuthLogoutAll)
 	})
 
 	// HTMX endpoints
@@ -1077,7 +1288,9 @@ func authContextMiddleware(next http.Handler) http.Handler {
 		if cookie, err := r.Cookie("session_token"); err == nil {
 			log.Printf("Found session cookie for %s %s (HTMX: %v)", r.Method, r.URL.Path, isHTMXRequest(r))
 			if claims, err := validateJWT(cookie.Value); err == nil {
-				if dbUser, err := getUserByID(claims.UserID); err == nil {
+				if !sessionActive(claims.SessionID) {
+					log.Printf("Session %s is revoked or expired", claims.SessionID)
+				} else if dbUser, err := getUserByID(claims.UserID); err == nil {
 					user = dbUser
 					log.Printf("Authenticated user: %s (%s) for %s %s", user.Name, user.Email, r.Method, r.URL.Path)
 				} else {
@@ -1089,6 +1302,19 @@ func authContextMiddleware(next http.Handler) http.Handler {
 		} else {
 			log.Printf("No session cookie found for %s %s (HTMX: %v)", r.Method, r.URL.Path, isHTMXRequest(r))
 		}
+
+		// The access token lapses every few minutes; pages keep working by
+		// rotating the refresh token in the background
+		if user == nil && r.URL.Path != "/auth/refresh" && r.URL.Path != "/auth/logout" {
+			if _, err := r.Cookie("refresh_token"); err == nil {
+				if refreshed, err := refreshSession(w, r); err == nil {
+					user = refreshed
+				} else {
+					log.Printf("Session refresh failed: %v", err)
+					clearSessionCookie(w)
+				}
+			}
+		}
 		
 		// Add user to context
 		ctx := context.WithValue(r.Context(), "user", user)
@@ -1124,14 +1350,15 @@ func redirectIfAuthenticated(handler http.HandlerFunc) http.HandlerFunc {
 
 // JWT Claims
 type Claims struct {
-	UserID string    `json:"user_id"`
-	Email  string    `json:"email"`
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	SessionID string `json:"sid"`
 	jwt.StandardClaims
 }
 
 // Auth helpers
 
-func setSessionCookie(w http.ResponseWriter, token string) {
+func setSessionCookie(w http.ResponseWriter, token, refreshToken string) {
 	// Determine if we're in a secure environment
 	secure := false // Set to true in production with HTTPS
 	
@@ -1142,27 +1369,39 @@ func setSessionCookie(w http.ResponseWriter, token string) {
 		HttpOnly: true,
 		Secure:   secure,
 		SameSite: http.SameSiteLaxMode, // Lax mode for HTMX compatibility
-		MaxAge:   86400, // 24 hours - matches JWT expiration
+		MaxAge:   int(accessTokenTTL.Seconds()), // matches JWT expiration
 	})
-}
-
-func clearSessionCookie(w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
-		Name:     "session_token",
-		Value:    "",
+		Name:     "refresh_token",
+		Value:    refreshToken,
 		Path:     "/",
 		HttpOnly: true,
-		Secure:   false, // Set to true in production with HTTPS
-		SameSite: http.SameSiteLaxMode, // Lax mode for HTMX compatibility
-		MaxAge:   -1,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   int(refreshTokenTTL.Seconds()),
 	})
 }
 
-func createJWT(user *User) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+func clearSessionCookie(w http.ResponseWriter) {
+	for _, name := range []string{"session_token", "refresh_token"} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    "",
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   false, // Set to true in production with HTTPS
+			SameSite: http.SameSiteLaxMode, // Lax mode for HTMX compatibility
+			MaxAge:   -1,
+		})
+	}
+}
+
+func createJWT(user *User, sessionID string) (string, error) {
+	expirationTime := time.Now().Add(accessTokenTTL)
 	claims := &Claims{
-		UserID: user.ID,
-		Email:  user.Email,
+		UserID:    user.ID,
+		Email:     user.Email,
+		SessionID: sessionID,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expirationTime.Unix(),
 			IssuedAt:  time.Now().Unix(),
@@ -1205,6 +1444,143 @@ func validateJWT(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
+// newRefreshToken returns a random refresh token and the hash stored for it
+func newRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := cryptorand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// startSession records a new sign-in and sets its cookies
+func startSession(w http.ResponseWriter, r *http.Request, user *User) error {
+	refreshToken, hash, err := newRefreshToken()
+	if err != nil {
+		return err
+	}
+	session := Session{
+		UserID:    user.ID,
+		Token:     hash,
+		UserAgent: truncateString(r.UserAgent(), 255),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+	if err := db.Create(&session).Error; err != nil {
+		return err
+	}
+
+	token, err := createJWT(user, session.ID)
+	if err != nil {
+		return err
+	}
+	setSessionCookie(w, token, refreshToken)
+	return nil
+}
+
+// refreshSession exchanges the refresh_token cookie for a new access token
+// and a new refresh token. Presenting a token that was already rotated away
+// means it was copied, so the whole session is revoked; the exception is a
+// request that raced another one moments after the rotation, which is let
+// through without new cookies.
+func refreshSession(w http.ResponseWriter, r *http.Request) (*User, error) {
+	cookie, err := r.Cookie("refresh_token")
+	if err != nil || cookie.Value == "" {
+		return nil, errSessionInvalid
+	}
+	hash := hashRefreshToken(cookie.Value)
+	now := time.Now()
+
+	var session Session
+	if err := db.Where("token = ?", hash).First(&session).Error; err != nil {
+		if err := db.Where("previous_token = ?", hash).First(&session).Error; err != nil {
+			return nil, errSessionInvalid
+		}
+		if session.RevokedAt == nil && session.RotatedAt != nil && now.Sub(*session.RotatedAt) < refreshReuseGrace {
+			return getUserByID(session.UserID)
+		}
+		log.Printf("Refresh token reused for session %s; revoking it", session.ID)
+		revokeSessions(db.Where("id = ?", session.ID))
+		return nil, errSessionInvalid
+	}
+	if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+		return nil, errSessionInvalid
+	}
+
+	user, err := getUserByID(session.UserID)
+	if err != nil {
+		return nil, errSessionInvalid
+	}
+
+	refreshToken, newHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	// Only one of several concurrent refreshes may rotate the token
+	result := db.Model(&Session{}).
+		Where("id = ? AND token = ? AND revoked_at IS NULL", session.ID, hash).
+		Updates(map[string]interface{}{"token": newHash, "previous_token": hash, "rotated_at": now})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return user, nil
+	}
+
+	token, err := createJWT(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	setSessionCookie(w, token, refreshToken)
+	return user, nil
+}
+
+// sessionActive reports whether an access token's session may still be used
+func sessionActive(sessionID string) bool {
+	if sessionID == "" {
+		return false
+	}
+	var count int64
+	db.Model(&Session{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, time.Now()).
+		Count(&count)
+	return count > 0
+}
+
+// revokeSessions ends the sessions matched by query
+func revokeSessions(query *gorm.DB) error {
+	return query.Model(&Session{}).Where("revoked_at IS NULL").Update("revoked_at", time.Now()).Error
+}
+
+// currentSessionID reads the session of the request's access token, or of
+// its refresh token when the access token has lapsed
+func currentSessionID(r *http.Request) string {
+	if cookie, err := r.Cookie("session_token"); err == nil {
+		if claims, err := validateJWT(cookie.Value); err == nil {
+			return claims.SessionID
+		}
+	}
+	if cookie, err := r.Cookie("refresh_token"); err == nil {
+		var session Session
+		if err := db.Select("id").Where("token = ?", hashRefreshToken(cookie.Value)).First(&session).Error; err == nil {
+			return session.ID
+		}
+	}
+	return ""
+}
+
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
 func getUserByID(id string) (*User, error) {
 	var user User
 	err := db.Where("id = ? AND is_active = ?", id, true).First(&user).Error
@@ -1268,32 +1644,144 @@ func handleRegister(w http.ResponseWriter, r *http.Request) {
 func handleRecipes(w http.ResponseWriter, r *http.Request) {
 	user := getUserFromContext(r.Context())
 	
-	// Get recipes from database
+	sort := r.URL.Query().Get("sort")
+	if _, ok := recipeSorts[sort]; !ok {
+		sort = "newest"
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > maxRecipesPerPage {
+		limit = recipesPerPage
+	}
+	
+	// Get one page of recipes, and one more to know whether another follows
+	query := visibleTo(db.Preload("Author"), user).Order(recipeSorts[sort])
+	if after := r.URL.Query().Get("after"); after != "" {
+		value, id, ok := decodeRecipeCursor(after)
+		if !ok {
+			http.Error(w, "after is not a valid cursor", http.StatusBadRequest)
+			return
+		}
+		query = recipesAfter(query, sort, value, id)
+	}
 	var recipes []Recipe
-	db.Preload("Author").Order("created_at DESC").Find(&recipes)
+	if err := query.Limit(limit + 1).Find(&recipes).Error; err != nil {
+		http.Error(w, "Failed to load recipes", http.StatusInternalServerError)
+		return
+	}
+	
+	nextURL := ""
+	if len(recipes) > limit {
+		recipes = recipes[:limit]
+		next := url.Values{"sort": {sort}, "after": {encodeRecipeCursor(sort, recipes[len(recipes)-1])}}
+		if limit != recipesPerPage {
+			next.Set("limit", strconv.Itoa(limit))
+		}
+		nextURL = "/recipes?" + next.Encode()
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextURL))
+	}
+	
+	// HTMX asks for the next page only, which goes after the cards shown
+	// and replaces the load more button
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(recipeCardsHTML(recipes) + loadMoreHTML(nextURL, true)))
+		return
+	}
 	
 	data := map[string]interface{}{
 		"Title":   "Recipes - Alchemorsel v3",
 		"User":    user,
 		"IsAuthenticated": user != nil,
 		"Recipes": recipes,
+		"Sort":    sort,
+		"NextURL": nextURL,
 	}
 	renderTemplate(w, "recipes", data)
 }
 
+// Recipe listing page sizes; ?limit= may ask for up to maxRecipesPerPage
+const (
+	recipesPerPage    = 20
+	maxRecipesPerPage = 100
+)
+
+// recipeSorts orders the recipe listing. Each ends with the ID so a cursor
+// names a single position.
+var recipeSorts = map[string]string{
+	"newest":    "created_at DESC, id",
+	"rating":    "average_rating DESC, id",
+	"prep_time": "prep_time_minutes, id",
+}
+
+// recipeSortLabels names the orders in the order they are offered
+var recipeSortLabels = [][2]string{
+	{"newest", "Newest"},
+	{"rating", "Highest rated"},
+	{"prep_time", "Quickest to prepare"},
+}
+
+// encodeRecipeCursor is the position after a recipe: its sort key and ID
+func encodeRecipeCursor(sort string, recipe Recipe) string {
+	var value string
+	switch sort {
+	case "rating":
+		value = strconv.FormatFloat(recipe.AverageRating, 'g', -1, 64)
+	case "prep_time":
+		value = strconv.Itoa(recipe.PrepTimeMinutes)
+	default:
+		value = recipe.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(value + "|" + recipe.ID))
+}
+
+func decodeRecipeCursor(cursor string) (value, id string, ok bool) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", false
+	}
+	value, id, ok = strings.Cut(string(data), "|")
+	return value, id, ok && id != ""
+}
+
+// recipesAfter keeps the recipes sorted after a cursor's position
+func recipesAfter(query *gorm.DB, sort, value, id string) *gorm.DB {
+	switch sort {
+	case "rating":
+		rating, _ := strconv.ParseFloat(value, 64)
+		return query.Where("average_rating < ? OR (average_rating = ? AND id > ?)", rating, rating, id)
+	case "prep_time":
+		minutes, _ := strconv.Atoi(value)
+		return query.Where("prep_time_minutes > ? OR (prep_time_minutes = ? AND id > ?)", minutes, minutes, id)
+	default:
+		created, _ := time.Parse(time.RFC3339Nano, value)
+		return query.Where("created_at < ? OR (created_at = ? AND id > ?)", created, created, id)
+	}
+}
+
 func handleRecipeDetail(w http.ResponseWriter, r *http.Request) {
 	user := getUserFromContext(r.Context())
 	recipeID := chi.URLParam(r, "id")
 	
 	var recipe Recipe
-	err := db.Preload("Author").Where("id = ?", recipeID).First(&recipe).Error
+	err := visibleTo(db.Preload("Author"), user).Where("id = ?", recipeID).First(&recipe).Error
 	if err != nil {
 		http.NotFound(w, r)
 		return
 	}
 	
-	// Increment view count
-	db.Model(&recipe).Update("views_count", recipe.ViewsCount+1)
+	// Count the view once per viewer within the window, adding in the
+	// database so concurrent views are not lost. Authors previewing an
+	// unpublished recipe are not readers.
+	viewer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		viewer = host
+	}
+	if user != nil {
+		viewer = user.ID
+	}
+	if recipe.Status == statusPublished && recipeViews.first(recipe.ID+"|"+viewer, time.Now()) {
+		db.Model(&recipe).UpdateColumn("views_count", gorm.Expr("views_count + ?", 1))
+	}
 	
 	data := map[string]interface{}{
 		"Title":  recipe.Title + " - Alchemorsel v3",
@@ -1304,6 +1792,38 @@ func handleRecipeDetail(w http.ResponseWriter, r *http.Request) {
 	renderTemplate(w, "recipe-detail", data)
 }
 
+// viewWindow is how long a viewer counts once per recipe
+const viewWindow = 30 * time.Minute
+
+// viewDedupe remembers who viewed which recipe within the window
+type viewDedupe struct {
+	mu     sync.Mutex
+	seen   map[string]time.Time
+	pruned time.Time
+}
+
+var recipeViews = &viewDedupe{seen: make(map[string]time.Time)}
+
+// first reports whether key has not been seen within the window, and
+// remembers it
+func (d *viewDedupe) first(key string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if now.Sub(d.pruned) > viewWindow {
+		for k, expires := range d.seen {
+			if now.After(expires) {
+				delete(d.seen, k)
+			}
+		}
+		d.pruned = now
+	}
+	if expires, ok := d.seen[key]; ok && now.Before(expires) {
+		return false
+	}
+	d.seen[key] = now.Add(viewWindow)
+	return true
+}
+
 func handleNewRecipe(w http.ResponseWriter, r *http.Request) {
 	user := getUserFromContext(r.Context())
 	data := map[string]interface{}{
@@ -1317,24 +1837,32 @@ func handleNewRecipe(w http.ResponseWriter, r *http.Request) {
 func handleDashboard(w http.ResponseWriter, r *http.Request) {
 	user := getUserFromContext(r.Context())
 	
-	// Get user's recipes
+	// Get user's recipes, grouped by status
 	var userRecipes []Recipe
 	db.Where("author_id = ?", user.ID).Order("created_at DESC").Find(&userRecipes)
+	byStatus := make(map[string][]Recipe)
+	for _, recipe := range userRecipes {
+		byStatus[recipe.Status] = append(byStatus[recipe.Status], recipe)
+	}
 	
 	// Get user stats
 	var totalLikes int64
 	db.Model(&Recipe{}).Where("author_id = ?", user.ID).Select("COALESCE(SUM(likes_count), 0)").Scan(&totalLikes)
+	var followers, following int64
+	db.Model(&Follow{}).Where("following_id = ?", user.ID).Count(&followers)
+	db.Model(&Follow{}).Where("follower_id = ?", user.ID).Count(&following)
 	
 	data := map[string]interface{}{
 		"Title": "Dashboard - Alchemorsel v3",
 		"User":  user,
 		"IsAuthenticated": true,
 		"UserRecipes": userRecipes,
+		"RecipesByStatus": byStatus,
 		"Stats": map[string]interface{}{
 			"RecipeCount": len(userRecipes),
 			"TotalLikes":  totalLikes,
-			"Followers":   0, // Would implement followers system
-			"Following":   0, // Would implement following system
+			"Followers":   followers,
+			"Following":   following,
 		},
 	}
 	renderTemplate(w, "dashboard", data)
@@ -1375,16 +1903,13 @@ func handleAuthLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// Create JWT token
-	token, err := createJWT(user)
-	if err != nil {
+	// Start a session and set its cookies
+	if err := startSession(w, r, user); err != nil {
+		log.Printf("Failed to start session: %v", err)
 		renderError(w, "Login failed")
 		return
 	}
 	
-	// Set secure cookie with better HTMX compatibility
-	setSessionCookie(w, token)
-	
 	if isHTMXRequest(r) {
 		w.Header().Set("HX-Redirect", "/dashboard")
 		return
@@ -1443,16 +1968,13 @@ func handleAuthRegister(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// Create JWT token
-	token, err := createJWT(&user)
-	if err != nil {
+	// Start a session and set its cookies
+	if err := startSession(w, r, &user); err != nil {
+		log.Printf("Failed to start session: %v", err)
 		renderError(w, "Registration successful but login failed")
 		return
 	}
 	
-	// Set secure cookie with better HTMX compatibility
-	setSessionCookie(w, token)
-	
 	if isHTMXRequest(r) {
 		w.Header().Set("HX-Redirect", "/dashboard")
 		return
@@ -1462,6 +1984,13 @@ func handleAuthRegister(w http.ResponseWriter, r *http.Request) {
 }
 
 func handleAuthLogout(w http.ResponseWriter, r *http.Request) {
+	// End this device's session so its refresh token stops working
+	if sessionID := currentSessionID(r); sessionID != "" {
+		if err := revokeSessions(db.Where("id = ?", sessionID)); err != nil {
+			log.Printf("Failed to revoke session %s: %v", sessionID, err)
+		}
+	}
+
 	// Clear cookie
 	clearSessionCookie(w)
 	
@@ -1473,6 +2002,38 @@ func handleAuthLogout(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
 
+// handleAuthLogoutAll signs the user out on every device by revoking all of
+// their sessions
+func handleAuthLogoutAll(w http.ResponseWriter, r *http.Request) {
+	user := getUserFromContext(r.Context())
+	if err := revokeSessions(db.Where("user_id = ?", user.ID)); err != nil {
+		log.Printf("Failed to revoke sessions for %s: %v", user.ID, err)
+		renderError(w, "Could not sign out other devices")
+		return
+	}
+	clearSessionCookie(w)
+
+	if isHTMXRequest(r) {
+		w.Header().Set("HX-Redirect", "/login")
+		return
+	}
+
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// handleAuthRefresh rotates the refresh token and issues a new access
+// token. Scripts call it before the access token lapses; answers 204, or
+// 401 when the session is over.
+func handleAuthRefresh(w http.ResponseWriter, r *http.Request) {
+	if _, err := refreshSession(w, r); err != nil {
+		log.Printf("Session refresh failed: %v", err)
+		clearSessionCookie(w)
+		http.Error(w, "Session expired", http.StatusUnauthorized)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // HTMX handlers
 
 func handleAIChat(w http.ResponseWriter, r *http.Request) {
@@ -1499,7 +2060,7 @@ func handleAIChat(w http.ResponseWriter, r *http.Request) {
 	
 	if isRecipeRequest && user != nil {
 		// Generate recipe using AI
-		recipe, err := generateRecipe(recipeRequest, user.ID)
+		generated, err := generateRecipe(r.Context(), recipeRequest, message, user.ID)
 		if err != nil {
 			log.Printf("Error generating recipe: %v", err)
 			response := "🤖 AI Chef: I had trouble generating that recipe. Please try again with different ingredients or description."
@@ -1511,6 +2072,7 @@ func handleAIChat(w http.ResponseWriter, r *http.Request) {
 				</div>`, response)
 		} else {
 			// Save recipe to database
+			recipe := generated.Recipe
 			err = db.Create(recipe).Error
 			if err != nil {
 				log.Printf("Error saving recipe to database: %v", err)
@@ -1523,20 +2085,27 @@ func handleAIChat(w http.ResponseWriter, r *http.Request) {
 					</div>`, response)
 			} else {
 				// Save ingredients, instructions, and tags
-				ingredients := generateIngredientsList(recipeRequest)
-				for i, ing := range ingredients {
+				for i, ing := range generated.Ingredients {
+					// "1 1/2" and "Tablespoons" become 1.5 tbsp so recipes scale;
+					// amounts like "to taste" are kept as a note instead
 					ingredient := Ingredient{
 						RecipeID:   recipe.ID,
 						Name:       ing.Name,
-						Amount:     1.0, // simplified for now
 						Unit:       ing.Unit,
 						OrderIndex: i + 1,
 					}
+					if q, ok := units.ParseQuantity(ing.Amount, ing.Unit); ok {
+						ingredient.Amount, ingredient.Unit = q.Amount, string(q.Unit)
+					} else if q, ok := units.ParseQuantity(ing.Amount, ""); ok {
+						ingredient.Amount = q.Amount
+					} else {
+						ingredient.Notes = strings.TrimSpace(ing.Amount + " " + ing.Unit)
+						ingredient.Unit = ""
+					}
 					db.Create(&ingredient)
 				}
 				
-				instructions := generateInstructions(recipeRequest)
-				for _, inst := range instructions {
+				for _, inst := range generated.Instructions {
 					instruction := Instruction{
 						RecipeID:    recipe.ID,
 						StepNumber:  inst.Step,
@@ -1545,8 +2114,7 @@ func handleAIChat(w http.ResponseWriter, r *http.Request) {
 					db.Create(&instruction)
 				}
 				
-				tags := generateTags(recipeRequest)
-				for _, tag := range tags {
+				for _, tag := range generated.Tags {
 					recipeTag := RecipeTag{
 						RecipeID: recipe.ID,
 						Tag:      tag,
@@ -1572,16 +2140,17 @@ func handleAIChat(w http.ResponseWriter, r *http.Request) {
 							<a href="/recipes/%s" class="btn btn-primary">View Full Recipe</a>
 							<a href="/dashboard" class="btn">Go to Dashboard</a>
 						</div>
+						<p><small>Saved as a draft only you can see. Publish it from your dashboard when it's ready.</small></p>
 					</div>`,
-					recipe.Title,
+					html.EscapeString(recipe.Title),
 					recipe.Difficulty,
 					recipe.Cuisine,
-					getIngredientPreview(recipeRequest),
+					html.EscapeString(getIngredientPreview(generated.Featured)),
 					recipe.PrepTimeMinutes+recipe.CookTimeMinutes,
-					recipe.Title,
-					recipe.Description,
-					recipe.Cuisine,
-					recipe.Difficulty,
+					html.EscapeString(recipe.Title),
+					html.EscapeString(recipe.Description),
+					html.EscapeString(recipe.Cuisine),
+					html.EscapeString(recipe.Difficulty),
 					recipe.ID)
 				
 				aiResponseHTML = fmt.Sprintf(`
@@ -1618,6 +2187,13 @@ func handleAIChat(w http.ResponseWriter, r *http.Request) {
 		}
 		
 		response := responses[rand.Intn(len(responses))]
+		if aiProvider != nil {
+			if answer, err := ai.Answer(r.Context(), aiProvider, message); err != nil {
+				log.Printf("AI provider %s failed, using canned reply: %v", aiProvider.Name(), err)
+			} else {
+				response = "🤖 AI Chef: " + strings.ReplaceAll(html.EscapeString(answer), "\n", "<br>")
+			}
+		}
 		
 		// Add recipe examples
 		response += `<br><br><strong>Try these examples:</strong>
@@ -1644,20 +2220,20 @@ func handleAIChat(w http.ResponseWriter, r *http.Request) {
 }
 
 // Helper function to get a preview of ingredients for display
-func getIngredientPreview(recipeRequest *AIRecipeRequest) string {
-	if len(recipeRequest.Ingredients) == 0 {
+func getIngredientPreview(ingredients []string) string {
+	if len(ingredients) == 0 {
 		return "fresh ingredients"
 	}
 	
 	var names []string
-	for i, ingredient := range recipeRequest.Ingredients {
+	for i, ingredient := range ingredients {
 		if i >= 3 { // Only show first 3
 			break
 		}
 		names = append(names, ingredient)
 	}
 	
-	if len(recipeRequest.Ingredients) > 3 {
+	if len(ingredients) > 3 {
 		return strings.Join(names, ", ") + " and more"
 	}
 	
@@ -1675,7 +2251,10 @@ func handleRecipeSearch(w http.ResponseWriter, r *http.Request) {
 	
 	// Search recipes in database
 	var recipes []Recipe
-	db.Preload("Author").Where("title ILIKE ? OR description ILIKE ?", "%"+query+"%", "%"+query+"%").Find(&recipes)
+	pattern := "%" + sqlsafe.EscapeLike(query) + "%"
+	visibleTo(db.Preload("Author"), getUserFromContext(r.Context())).
+		Where("("+sqlsafe.ILike("title")+" OR "+sqlsafe.ILike("description")+")", pattern, pattern).
+		Find(&recipes)
 	
 	if len(recipes) == 0 {
 		html := fmt.Sprintf(`<div class="search-results">
@@ -1723,13 +2302,13 @@ func handleRecipeSearch(w http.ResponseWriter, r *http.Request) {
 }
 
 func handleRecipeLike(w http.ResponseWriter, r *http.Request) {
-	_ = getUserFromContext(r.Context()) // TODO: Track individual user likes
+	user := getUserFromContext(r.Context()) // TODO: Track individual user likes
 	recipeID := chi.URLParam(r, "id")
 	
 	// In a real app, you'd track individual likes per user
 	// For now, just increment the like count
 	var recipe Recipe
-	err := db.Where("id = ?", recipeID).First(&recipe).Error
+	err := visibleTo(db, user).Where("id = ?", recipeID).First(&recipe).Error
 	if err != nil {
 		renderHTMXError(w, "Recipe not found")
 		return
@@ -1772,7 +2351,7 @@ func handleCreateRecipe(w http.ResponseWriter, r *http.Request) {
 		PrepTimeMinutes: 0,
 		CookTimeMinutes: 0,
 		Servings:        4,
-		Status:          "published",
+		Status:          statusDraft,
 	}
 	
 	err := db.Create(&recipe).Error
@@ -1784,6 +2363,36 @@ func handleCreateRecipe(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
 }
 
+// handleRecipeStatus moves one of the user's recipes through its lifecycle
+// and returns to the dashboard, which lists it under its new status
+func handleRecipeStatus(action string, transition recipeTransition) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := getUserFromContext(r.Context())
+		recipeID := chi.URLParam(r, "id")
+		
+		// Checking the status in the update itself means two tabs racing
+		// on the same recipe cannot both apply their change
+		result := db.Model(&Recipe{}).
+			Where("id = ? AND author_id = ? AND status IN ?", recipeID, user.ID, transition.from).
+			Update("status", transition.to)
+		if result.Error != nil {
+			renderError(w, "Failed to update the recipe")
+			return
+		}
+		if result.RowsAffected == 0 {
+			var recipe Recipe
+			if err := db.Where("id = ? AND author_id = ?", recipeID, user.ID).First(&recipe).Error; err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			http.Error(w, fmt.Sprintf("Cannot %s a recipe that is %s", action, recipe.Status), http.StatusConflict)
+			return
+		}
+		
+		http.Redirect(w, r, "/dashboard", http.StatusSeeOther)
+	}
+}
+
 // Helper functions
 
 func getUserName(user *User) string {
@@ -1896,6 +2505,95 @@ func renderTemplate(w http.ResponseWriter, templateName string, data interface{}
 	}
 }
 
+// recipeCardsHTML renders the cards of the recipe listing
+func recipeCardsHTML(recipes []Recipe) string {
+	cards := ""
+	for _, recipe := range recipes {
+		aiBadge := ""
+		if recipe.AIGenerated {
+			aiBadge = `<span class="badge ai-badge">AI Generated</span>`
+		}
+		// Only the author is shown their unpublished recipes
+		if recipe.Status != statusPublished {
+			aiBadge += fmt.Sprintf(`<span class="badge">%s</span>`, html.EscapeString(recipeStatusBadges[recipe.Status]))
+		}
+		
+		cards += fmt.Sprintf(`
+			<div class="recipe-card">
+				<h3><a href="/recipes/%s">%s</a></h3>
+				<p>%s</p>
+				<div style="margin: 10px 0;">
+					<span class="badge">%s</span>
+					<span class="badge">%s</span>
+					%s
+				</div>
+				<div style="margin-top: 10px;">
+					<small>👤 %s | ❤️ %d likes | ⭐ %.1f/5 | 👁️ %d views</small>
+				</div>
+			</div>`,
+			recipe.ID, html.EscapeString(recipe.Title), html.EscapeString(recipe.Description),
+			html.EscapeString(recipe.Cuisine), html.EscapeString(recipe.Difficulty), aiBadge,
+			html.EscapeString(recipe.Author.Name), recipe.LikesCount, recipe.AverageRating, recipe.ViewsCount)
+	}
+	return cards
+}
+
+// loadMoreHTML is the button appending the next page of recipes to the
+// grid. A page fetched by HTMX sends it out of band to replace the last
+// one, or to remove it after the last page.
+func loadMoreHTML(nextURL string, outOfBand bool) string {
+	oob := ""
+	if outOfBand {
+		oob = ` hx-swap-oob="true"`
+	}
+	if nextURL == "" {
+		return `<div id="load-more"` + oob + `></div>`
+	}
+	return fmt.Sprintf(`<div id="load-more"%s style="text-align: center; margin: 20px 0;">
+		<a href="%s" class="btn" hx-get="%s" hx-target="#recipe-grid" hx-swap="beforeend">Load more recipes</a>
+	</div>`, oob, html.EscapeString(nextURL), html.EscapeString(nextURL))
+}
+
+// dashboardRecipeHTML renders one of the author's recipes with the status
+// changes they can make from its current status
+func dashboardRecipeHTML(recipe Recipe) string {
+	aiBadge := ""
+	if recipe.AIGenerated {
+		aiBadge = `<span class="badge ai-badge">AI Generated</span>`
+	}
+	
+	actions := ""
+	for _, t := range recipeTransitions {
+		if !t.allows(recipe.Status) {
+			continue
+		}
+		actions += fmt.Sprintf(`<form method="post" action="/recipes/%s/%s" style="display: inline;"><button type="submit" class="btn">%s</button></form>`,
+			recipe.ID, t.action, t.label)
+	}
+	
+	return fmt.Sprintf(`
+		<div class="recipe-card">
+			<h4><a href="/recipes/%s">%s</a></h4>
+			<p>%s</p>
+			<div>
+				<span class="badge">%s</span>
+				<span class="badge">%s</span>
+				%s
+			</div>
+			<div style="margin-top: 10px;">
+				<small>❤️ %d likes | ⭐ %.1f/5 | 👁️ %d views</small>
+			</div>
+			<div style="margin-top: 10px;">
+				<small>Created: %s</small>
+			</div>
+			<div style="margin-top: 10px;">%s</div>
+		</div>`,
+		recipe.ID, html.EscapeString(recipe.Title), html.EscapeString(recipe.Description),
+		html.EscapeString(recipe.Cuisine), html.EscapeString(recipe.Difficulty), aiBadge,
+		recipe.LikesCount, recipe.AverageRating, recipe.ViewsCount,
+		recipe.CreatedAt.Format("Jan 2, 2006"), actions)
+}
+
 func getUserInfoDisplay(user interface{}) string {
 	if u, ok := user.(*User); ok && u != nil {
 		return fmt.Sprintf(`<span class="user-info">Welcome, %s (%s)</span>`, u.Name, u.Role)
@@ -2007,36 +2705,24 @@ func getPageContent(templateName string, data interface{}) string {
 		
 	case "recipes":
 		recipesData, _ := dataMap["Recipes"].([]Recipe)
-		html := `<div class="card"><h2>📖 All Recipes</h2></div><div class="recipe-grid">`
+		sort, _ := dataMap["Sort"].(string)
+		nextURL, _ := dataMap["NextURL"].(string)
+		html := `<div class="card"><h2>📖 All Recipes</h2><nav aria-label="Sort recipes">`
+		for _, option := range recipeSortLabels {
+			current := ""
+			if option[0] == sort {
+				current = ` aria-current="page" style="background: #2c5282;"`
+			}
+			html += fmt.Sprintf(`<a href="/recipes?sort=%s" class="btn"%s>%s</a>`, option[0], current, option[1])
+		}
+		html += `</nav></div><div class="recipe-grid" id="recipe-grid">`
 		
 		if len(recipesData) == 0 {
 			html += `<div class="card"><p>No recipes found. Be the first to <a href="/recipes/new">create one</a>!</p></div>`
 		} else {
-			for _, recipe := range recipesData {
-				aiBadge := ""
-				if recipe.AIGenerated {
-					aiBadge = `<span class="badge ai-badge">AI Generated</span>`
-				}
-				
-				html += fmt.Sprintf(`
-					<div class="recipe-card">
-						<h3><a href="/recipes/%s">%s</a></h3>
-						<p>%s</p>
-						<div style="margin: 10px 0;">
-							<span class="badge">%s</span>
-							<span class="badge">%s</span>
-							%s
-						</div>
-						<div style="margin-top: 10px;">
-							<small>👤 %s | ❤️ %d likes | ⭐ %.1f/5 | 👁️ %d views</small>
-						</div>
-					</div>`,
-					recipe.ID, recipe.Title, recipe.Description,
-					recipe.Cuisine, recipe.Difficulty, aiBadge,
-					recipe.Author.Name, recipe.LikesCount, recipe.AverageRating, recipe.ViewsCount)
-			}
+			html += recipeCardsHTML(recipesData)
 		}
-		html += "</div>"
+		html += "</div>" + loadMoreHTML(nextURL, false)
 		return html
 		
 	case "recipe-form":
@@ -2094,6 +2780,7 @@ func getPageContent(templateName string, data interface{}) string {
 		user := dataMap["User"].(*User)
 		stats := dataMap["Stats"].(map[string]interface{})
 		userRecipes, _ := dataMap["UserRecipes"].([]Recipe)
+		byStatus, _ := dataMap["RecipesByStatus"].(map[string][]Recipe)
 		
 		html := fmt.Sprintf(`
 			<div class="card">
@@ -2131,34 +2818,15 @@ func getPageContent(templateName string, data interface{}) string {
 		
 		if len(userRecipes) == 0 {
 			html += `<p style="margin-top: 20px;">You haven't created any recipes yet. <a href="/recipes/new">Create your first recipe</a>!</p>`
-		} else {
-			html += `<div class="recipe-grid" style="margin-top: 20px;">`
-			for _, recipe := range userRecipes {
-				aiBadge := ""
-				if recipe.AIGenerated {
-					aiBadge = `<span class="badge ai-badge">AI Generated</span>`
-				}
-				
-				html += fmt.Sprintf(`
-					<div class="recipe-card">
-						<h4><a href="/recipes/%s">%s</a></h4>
-						<p>%s</p>
-						<div>
-							<span class="badge">%s</span>
-							<span class="badge">%s</span>
-							%s
-						</div>
-						<div style="margin-top: 10px;">
-							<small>❤️ %d likes | ⭐ %.1f/5 | 👁️ %d views</small>
-						</div>
-						<div style="margin-top: 10px;">
-							<small>Created: %s</small>
-						</div>
-					</div>`,
-					recipe.ID, recipe.Title, recipe.Description,
-					recipe.Cuisine, recipe.Difficulty, aiBadge,
-					recipe.LikesCount, recipe.AverageRating, recipe.ViewsCount,
-					recipe.CreatedAt.Format("Jan 2, 2006"))
+		}
+		for _, group := range recipeStatusLabels {
+			recipes := byStatus[group[0]]
+			if len(recipes) == 0 {
+				continue
+			}
+			html += fmt.Sprintf(`<h4 style="margin-top: 20px;">%s (%d)</h4><div class="recipe-grid">`, group[1], len(recipes))
+			for _, recipe := range recipes {
+				html += dashboardRecipeHTML(recipe)
 			}
 			html += "</div>"
 		}
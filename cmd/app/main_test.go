@@ -0,0 +1,316 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testSchema is the part of the migrated schema the handlers under test
+// touch, with sqlite defaults standing in for gen_random_uuid()
+var testSchema = []string{
+	`CREATE TABLE users (
+		id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+		email TEXT UNIQUE, name TEXT, password_hash TEXT,
+		role TEXT DEFAULT 'user', is_active BOOLEAN DEFAULT true,
+		created_at DATETIME, updated_at DATETIME)`,
+	`CREATE TABLE recipes (
+		id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+		title TEXT, description TEXT, author_id TEXT REFERENCES users(id),
+		cuisine TEXT, difficulty TEXT,
+		prep_time_minutes INTEGER, cook_time_minutes INTEGER, servings INTEGER,
+		likes_count INTEGER DEFAULT 0, views_count INTEGER DEFAULT 0,
+		average_rating REAL DEFAULT 0, status TEXT DEFAULT 'draft',
+		ai_generated BOOLEAN DEFAULT false,
+		created_at DATETIME, updated_at DATETIME)`,
+	`CREATE TABLE sessions (
+		id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+		user_id TEXT REFERENCES users(id),
+		token TEXT UNIQUE, previous_token TEXT, user_agent TEXT,
+		expires_at DATETIME, rotated_at DATETIME, revoked_at DATETIME,
+		created_at DATETIME)`,
+}
+
+// useTestDB points the package at an empty file database, so pooled
+// connections share it, for the length of the test
+func useTestDB(t *testing.T) {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "app.db") + "?_busy_timeout=5000"
+	testDB, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	for _, statement := range testSchema {
+		require.NoError(t, testDB.Exec(statement).Error)
+	}
+
+	previousDB, previousSecret := db, jwtSecret
+	db, jwtSecret = testDB, []byte("test-secret")
+	t.Cleanup(func() { db, jwtSecret = previousDB, previousSecret })
+}
+
+// createUser stores a user named name
+func createUser(t *testing.T, name string) *User {
+	t.Helper()
+	user := &User{Name: name, Email: strings.ToLower(name) + "@example.com", PasswordHash: "x", Role: "user", IsActive: true}
+	require.NoError(t, db.Create(user).Error)
+	return user
+}
+
+// createRecipe stores a recipe by author in status
+func createRecipe(t *testing.T, author *User, title, status string) *Recipe {
+	t.Helper()
+	recipe := &Recipe{Title: title, AuthorID: author.ID, Status: status}
+	require.NoError(t, db.Create(recipe).Error)
+	return recipe
+}
+
+// signIn starts a session for user and returns its cookies
+func signIn(t *testing.T, user *User) []*http.Cookie {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	require.NoError(t, startSession(recorder, httptest.NewRequest(http.MethodPost, "/auth/login", nil), user))
+	return recorder.Result().Cookies()
+}
+
+// serve sends a request through the application's router with cookies
+func serve(method, target string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, nil)
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+	recorder := httptest.NewRecorder()
+	setupRouter().ServeHTTP(recorder, request)
+	return recorder
+}
+
+// cookieNamed picks one cookie out of a response's or request's cookies
+func cookieNamed(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+// transitionByAction finds the transition behind an action's button
+func transitionByAction(t *testing.T, action string) recipeTransition {
+	t.Helper()
+	for _, transition := range recipeTransitions {
+		if transition.action == action {
+			return transition.recipeTransition
+		}
+	}
+	t.Fatalf("no %q transition", action)
+	return recipeTransition{}
+}
+
+func TestRecipeTransitions(t *testing.T) {
+	tests := []struct {
+		action string
+		from   string
+		want   bool
+	}{
+		{"publish", statusDraft, true},
+		{"publish", statusArchived, true},
+		{"publish", statusPublished, false},
+		{"unpublish", statusPublished, true},
+		{"unpublish", statusDraft, false},
+		{"unpublish", statusArchived, false},
+		{"archive", statusDraft, true},
+		{"archive", statusPublished, true},
+		{"archive", statusArchived, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action+" from "+tt.from, func(t *testing.T) {
+			assert.Equal(t, tt.want, transitionByAction(t, tt.action).allows(tt.from))
+		})
+	}
+}
+
+func TestVisibleTo(t *testing.T) {
+	useTestDB(t)
+	owner := createUser(t, "Owner")
+	other := createUser(t, "Other")
+	createRecipe(t, owner, "owner draft", statusDraft)
+	createRecipe(t, owner, "owner published", statusPublished)
+	createRecipe(t, owner, "owner archived", statusArchived)
+	createRecipe(t, other, "other draft", statusDraft)
+	createRecipe(t, other, "other published", statusPublished)
+
+	tests := []struct {
+		name   string
+		viewer *User
+		want   []string
+	}{
+		{"owner", owner, []string{"other published", "owner archived", "owner draft", "owner published"}},
+		{"other user", other, []string{"other draft", "other published", "owner published"}},
+		{"anonymous", nil, []string{"other published", "owner published"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var titles []string
+			require.NoError(t, visibleTo(db.Model(&Recipe{}), tt.viewer).Pluck("title", &titles).Error)
+			sort.Strings(titles)
+			assert.Equal(t, tt.want, titles)
+		})
+	}
+}
+
+func TestRecipeCardsLabelUnpublishedStatus(t *testing.T) {
+	cards := recipeCardsHTML([]Recipe{
+		{ID: "1", Title: "Lemon Bars", Status: statusDraft},
+		{ID: "2", Title: "Shortbread", Status: statusArchived},
+		{ID: "3", Title: "Scones", Status: statusPublished},
+	})
+
+	assert.Contains(t, cards, `<span class="badge">Draft</span>`)
+	assert.Contains(t, cards, `<span class="badge">Archived</span>`)
+	assert.NotContains(t, cards, `<span class="badge">Published</span>`)
+}
+
+func TestHandleRecipeStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     string
+		from       string
+		byOther    bool
+		wantCode   int
+		wantStatus string
+	}{
+		{"publish a draft", "publish", statusDraft, false, http.StatusSeeOther, statusPublished},
+		{"republish an archived recipe", "publish", statusArchived, false, http.StatusSeeOther, statusPublished},
+		{"unpublish", "unpublish", statusPublished, false, http.StatusSeeOther, statusDraft},
+		{"archive a draft", "archive", statusDraft, false, http.StatusSeeOther, statusArchived},
+		{"archive a published recipe", "archive", statusPublished, false, http.StatusSeeOther, statusArchived},
+		{"publish twice", "publish", statusPublished, false, http.StatusConflict, statusPublished},
+		{"unpublish a draft", "unpublish", statusDraft, false, http.StatusConflict, statusDraft},
+		{"archive twice", "archive", statusArchived, false, http.StatusConflict, statusArchived},
+		{"publish someone else's draft", "publish", statusDraft, true, http.StatusNotFound, statusDraft},
+		{"archive someone else's recipe", "archive", statusPublished, true, http.StatusNotFound, statusPublished},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestDB(t)
+			author := createUser(t, "Author")
+			recipe := createRecipe(t, author, "Lemon Bars", tt.from)
+			caller := author
+			if tt.byOther {
+				caller = createUser(t, "Other")
+			}
+
+			response := serve(http.MethodPost, "/recipes/"+recipe.ID+"/"+tt.action, signIn(t, caller))
+			assert.Equal(t, tt.wantCode, response.Code)
+
+			var stored Recipe
+			require.NoError(t, db.First(&stored, "id = ?", recipe.ID).Error)
+			assert.Equal(t, tt.wantStatus, stored.Status)
+		})
+	}
+
+	t.Run("signed out", func(t *testing.T) {
+		useTestDB(t)
+		recipe := createRecipe(t, createUser(t, "Author"), "Lemon Bars", statusDraft)
+
+		response := serve(http.MethodPost, "/recipes/"+recipe.ID+"/publish", nil)
+		assert.Equal(t, http.StatusSeeOther, response.Code)
+		assert.Equal(t, "/login", response.Header().Get("Location"))
+
+		var stored Recipe
+		require.NoError(t, db.First(&stored, "id = ?", recipe.ID).Error)
+		assert.Equal(t, statusDraft, stored.Status)
+	})
+}
+
+func TestRefreshTokenReuse(t *testing.T) {
+	// refreshOnce signs in and rotates the refresh token once, returning the
+	// session, the rotated away cookie and the one that replaced it
+	refreshOnce := func(t *testing.T) (Session, *http.Cookie, *http.Cookie) {
+		t.Helper()
+		user := createUser(t, "Ada")
+		first := cookieNamed(signIn(t, user), "refresh_token")
+
+		response := serve(http.MethodPost, "/auth/refresh", []*http.Cookie{first})
+		require.Equal(t, http.StatusNoContent, response.Code)
+		second := cookieNamed(response.Result().Cookies(), "refresh_token")
+		require.NotNil(t, second)
+		require.NotEqual(t, first.Value, second.Value)
+
+		var session Session
+		require.NoError(t, db.First(&session, "user_id = ?", user.ID).Error)
+		return session, first, second
+	}
+
+	t.Run("after the grace period revokes the session", func(t *testing.T) {
+		useTestDB(t)
+		session, rotated, current := refreshOnce(t)
+		require.NoError(t, db.Model(&Session{}).Where("id = ?", session.ID).
+			Update("rotated_at", time.Now().Add(-refreshReuseGrace-time.Second)).Error)
+
+		response := serve(http.MethodPost, "/auth/refresh", []*http.Cookie{rotated})
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+		require.NoError(t, db.First(&session, "id = ?", session.ID).Error)
+		assert.NotNil(t, session.RevokedAt)
+		response = serve(http.MethodPost, "/auth/refresh", []*http.Cookie{current})
+		assert.Equal(t, http.StatusUnauthorized, response.Code, "the token issued before the reuse dies with the session")
+	})
+
+	t.Run("within the grace period is let through", func(t *testing.T) {
+		useTestDB(t)
+		session, rotated, current := refreshOnce(t)
+
+		response := serve(http.MethodPost, "/auth/refresh", []*http.Cookie{rotated})
+		assert.Equal(t, http.StatusNoContent, response.Code)
+		assert.Nil(t, cookieNamed(response.Result().Cookies(), "refresh_token"), "a racing request does not rotate again")
+
+		require.NoError(t, db.First(&session, "id = ?", session.ID).Error)
+		assert.Nil(t, session.RevokedAt)
+		response = serve(http.MethodPost, "/auth/refresh", []*http.Cookie{current})
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+func TestLogoutAllRevokesEverySession(t *testing.T) {
+	useTestDB(t)
+	user := createUser(t, "Ada")
+	laptop := signIn(t, user)
+	phone := signIn(t, user)
+	bystander := createUser(t, "Grace")
+	bystanderCookies := signIn(t, bystander)
+
+	response := serve(http.MethodPost, "/auth/logout-all", laptop)
+	assert.Equal(t, http.StatusSeeOther, response.Code)
+	assert.Equal(t, "/login", response.Header().Get("Location"))
+
+	var sessions []Session
+	require.NoError(t, db.Find(&sessions, "user_id = ?", user.ID).Error)
+	require.Len(t, sessions, 2)
+	for _, session := range sessions {
+		assert.NotNil(t, session.RevokedAt, "session %s", session.ID)
+	}
+
+	// The other device's unexpired access token and its refresh token both
+	// stop working
+	response = serve(http.MethodGet, "/dashboard", []*http.Cookie{cookieNamed(phone, "session_token")})
+	assert.Equal(t, http.StatusSeeOther, response.Code)
+	assert.Equal(t, "/login", response.Header().Get("Location"))
+	response = serve(http.MethodPost, "/auth/refresh", []*http.Cookie{cookieNamed(phone, "refresh_token")})
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	// Other users stay signed in
+	response = serve(http.MethodPost, "/auth/refresh", []*http.Cookie{cookieNamed(bystanderCookies, "refresh_token")})
+	assert.Equal(t, http.StatusNoContent, response.Code)
+}
@@ -0,0 +1,162 @@
+// Package main provides a command that asks a running Alchemorsel v3 API to
+// warm its query caches, for use in deploy scripts after a rollout
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+)
+
+const (
+	exitCodeSuccess = 0
+	exitCodeFailure = 1
+	exitCodeError   = 2
+)
+
+// Config holds command-line configuration
+type Config struct {
+	URL          string
+	Token        string
+	Wait         bool
+	Timeout      time.Duration
+	PollInterval time.Duration
+	Verbose      bool
+}
+
+// warmupResponse is the API envelope around a warmup report
+type warmupResponse struct {
+	Success bool                      `json:"success"`
+	Data    inbound.CacheWarmupReport `json:"data"`
+	Error   string                    `json:"error"`
+}
+
+func main() {
+	os.Exit(run(parseFlags()))
+}
+
+// parseFlags parses command-line flags
+func parseFlags() Config {
+	config := Config{}
+
+	flag.StringVar(&config.URL, "url", envOr("ALCHEMORSEL_API_URL", "http://localhost:3000"), "API base URL")
+	flag.StringVar(&config.Token, "token", os.Getenv("ALCHEMORSEL_TOKEN"), "Admin bearer token")
+	flag.BoolVar(&config.Wait, "wait", true, "Wait for the warmup to finish")
+	flag.DurationVar(&config.Timeout, "timeout", 3*time.Minute, "How long to wait for the warmup")
+	flag.DurationVar(&config.PollInterval, "poll", time.Second, "Delay between progress checks")
+	flag.BoolVar(&config.Verbose, "verbose", false, "Print every task as it finishes")
+
+	flag.Parse()
+	config.URL = strings.TrimRight(config.URL, "/")
+
+	return config
+}
+
+// run starts a warmup and, with -wait, polls until it finishes
+func run(config Config) int {
+	if config.Token == "" {
+		fmt.Println("An admin token is required: pass -token or set ALCHEMORSEL_TOKEN")
+		return exitCodeError
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+	client := &http.Client{Timeout: 30 * time.Second}
+	endpoint := config.URL + "/api/v1/admin/cache/warm"
+
+	report, err := call(ctx, client, http.MethodPost, endpoint, config.Token)
+	if err != nil {
+		fmt.Printf("Failed to start cache warmup: %v\n", err)
+		return exitCodeError
+	}
+	fmt.Printf("Cache warmup %s (%s trigger)\n", report.Status, report.Trigger)
+	if !config.Wait {
+		return exitCodeSuccess
+	}
+
+	printed := 0
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+	for report.Status == inbound.WarmupRunning {
+		select {
+		case <-ctx.Done():
+			fmt.Printf("Cache warmup still running after %v (%d/%d tasks)\n", config.Timeout, report.Completed, report.Total)
+			return exitCodeFailure
+		case <-ticker.C:
+		}
+
+		if report, err = call(ctx, client, http.MethodGet, endpoint, config.Token); err != nil {
+			fmt.Printf("Failed to read cache warmup progress: %v\n", err)
+			return exitCodeError
+		}
+		if config.Verbose {
+			printed = printTasks(report, printed)
+		}
+	}
+
+	printTasks(report, printed)
+	fmt.Printf("Cache warmup %s: %d/%d tasks\n", report.Status, report.Completed, report.Total)
+	if report.Status != inbound.WarmupDone {
+		return exitCodeFailure
+	}
+	return exitCodeSuccess
+}
+
+// call sends one request to the warmup endpoint and decodes the report
+func call(ctx context.Context, client *http.Client, method, url, token string) (*inbound.CacheWarmupReport, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body warmupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("unexpected response (status %d): %w", resp.StatusCode, err)
+	}
+	if !body.Success {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, body.Error)
+	}
+	return &body.Data, nil
+}
+
+// printTasks prints finished tasks from index from onwards and returns how
+// many tasks have been printed
+func printTasks(report *inbound.CacheWarmupReport, from int) int {
+	for i := from; i < len(report.Tasks); i++ {
+		task := report.Tasks[i]
+		if task.Status != inbound.WarmupDone && task.Status != inbound.WarmupFailed {
+			return i
+		}
+		line := fmt.Sprintf("  %-16s %-6s %4d entries %6dms", task.Name, task.Status, task.Entries, task.DurationMS)
+		if task.Error != "" {
+			line += "  " + task.Error
+		}
+		fmt.Println(line)
+	}
+	return len(report.Tasks)
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,64 @@
+// Package main generates the configuration reference from the schema in
+// internal/infrastructure/config. Run it through go generate:
+//
+//	go generate ./internal/infrastructure/config
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+)
+
+const header = `# Configuration reference
+
+<!-- Generated by cmd/configdoc from internal/infrastructure/config; DO NOT EDIT. -->
+
+Alchemorsel reads ` + "`config.yaml`" + ` from the working directory, ` + "`./config`" + ` or
+` + "`/etc/alchemorsel`" + `. Every key below can be overridden with its environment
+variable; lists take comma-separated values, and lists of objects take JSON.
+Keys that are not listed here, and values that break their rules, stop the
+server at startup. Rules use go-playground/validator syntax.
+
+Admins can read the effective values, with secrets redacted, from
+` + "`GET /api/v1/admin/config`" + `.
+`
+
+const profilesHeader = `
+## Startup profiles
+
+A profile sets a bundle of keys so a server can start with a single switch:
+pass ` + "`-profile`" + ` to ` + "`cmd/api`" + `, ` + "`cmd/api-pure`" + ` or ` + "`cmd/web`" + `, or set
+` + "`ALCHEMORSEL_APP_PROFILE`" + `. Profile values override the config file;
+environment variables still override the profile. ` + "`demo`" + ` needs nothing
+installed: it uses a seeded SQLite file and canned AI answers.
+`
+
+func main() {
+	output := flag.String("o", "", "File to write; stdout when empty")
+	flag.Parse()
+
+	var doc bytes.Buffer
+	doc.WriteString(header)
+	if err := config.WriteMarkdown(&doc, config.Schema()); err != nil {
+		fmt.Fprintf(os.Stderr, "configdoc: %v\n", err)
+		os.Exit(1)
+	}
+	doc.WriteString(profilesHeader)
+	if err := config.WriteProfilesMarkdown(&doc); err != nil {
+		fmt.Fprintf(os.Stderr, "configdoc: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(doc.Bytes())
+		return
+	}
+	if err := os.WriteFile(*output, doc.Bytes(), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "configdoc: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -3,16 +3,21 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/alchemorsel/v3/internal/infrastructure/a11y"
 	"github.com/alchemorsel/v3/internal/infrastructure/performance"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
 )
 
 const (
@@ -42,6 +47,10 @@ type CLIConfig struct {
 	ConfigFile     string
 	Force          bool
 	DryRun         bool
+	BaseURL        string
+	MaxPages       int
+	APIToken       string
+	CheckImages    bool
 }
 
 func main() {
@@ -88,6 +97,10 @@ func parseFlags() CLIConfig {
 	flag.StringVar(&config.ConfigFile, "config", "", "Configuration file path")
 	flag.BoolVar(&config.Force, "force", false, "Force rebuild even if no changes detected")
 	flag.BoolVar(&config.DryRun, "dry-run", false, "Show what would be done without making changes")
+	flag.StringVar(&config.BaseURL, "url", "http://localhost:8080", "Base URL of a running web server (a11y)")
+	flag.IntVar(&config.MaxPages, "max-pages", 50, "Maximum pages to crawl (a11y)")
+	flag.StringVar(&config.APIToken, "token", os.Getenv("ALCHEMORSEL_API_TOKEN"), "API bearer token (structured-data)")
+	flag.BoolVar(&config.CheckImages, "check-images", false, "Fetch recipe images to check size and aspect ratio (structured-data)")
 
 	// Custom usage function
 	flag.Usage = func() {
@@ -100,12 +113,16 @@ func parseFlags() CLIConfig {
 		fmt.Fprintf(os.Stderr, "  report   - Generate detailed optimization report\n")
 		fmt.Fprintf(os.Stderr, "  validate - Validate 14KB compliance\n")
 		fmt.Fprintf(os.Stderr, "  clean    - Clean build artifacts\n")
+		fmt.Fprintf(os.Stderr, "  a11y     - Crawl a running server and report WCAG issues\n")
+		fmt.Fprintf(os.Stderr, "  structured-data - Check published recipes' JSON-LD for rich results\n")
 		fmt.Fprintf(os.Stderr, "\nOptions:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s --command=build --verbose\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --command=watch --project-root=/path/to/project\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --command=report --format=json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --command=a11y --url=http://localhost:8080\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --command=structured-data --url=http://localhost:8080 --token=$TOKEN\n", os.Args[0])
 	}
 
 	flag.Parse()
@@ -158,6 +175,10 @@ func (cli *CLI) execute(ctx context.Context) error {
 		return cli.executeValidate(ctx)
 	case "clean":
 		return cli.executeClean(ctx)
+	case "a11y":
+		return cli.executeA11y(ctx)
+	case "structured-data":
+		return cli.executeStructuredData(ctx)
 	default:
 		return fmt.Errorf("unknown command: %s", cli.config.Command)
 	}
@@ -313,6 +334,139 @@ func (cli *CLI) executeClean(ctx context.Context) error {
 	return nil
 }
 
+// executeA11y crawls a running web server, audits each page plus the theme
+// palettes, and fails when any error-severity issue is found
+func (cli *CLI) executeA11y(ctx context.Context) error {
+	fmt.Printf("♿ Auditing accessibility at %s...\n", cli.config.BaseURL)
+
+	fetch := a11y.HTTPFetcher(&http.Client{Timeout: 10 * time.Second}, cli.config.BaseURL)
+	crawler := a11y.NewCrawler(fetch, a11y.CrawlConfig{
+		StartPaths: []string{"/", "/login", "/register", "/dev/fragments"},
+		MaxPages:   cli.config.MaxPages,
+		Exclude:    []string{"/logout", "/static/", "/api/"},
+	})
+
+	report, err := crawler.Crawl(ctx)
+	if err != nil {
+		return fmt.Errorf("crawl failed: %w", err)
+	}
+	report.Add("theme:light", a11y.CheckPalette("light", performance.LightTheme, a11y.DefaultPalettePairs))
+	report.Add("theme:dark", a11y.CheckPalette("dark", performance.DarkTheme, a11y.DefaultPalettePairs))
+
+	switch cli.config.OutputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	case "html":
+		if err := cli.writeA11yHTMLReport(report); err != nil {
+			return err
+		}
+	default:
+		report.WriteText(os.Stdout)
+	}
+
+	if errors := report.Errors(); errors > 0 {
+		return fmt.Errorf("accessibility audit found %d errors", errors)
+	}
+	fmt.Println("✅ No accessibility errors found")
+	return nil
+}
+
+// executeStructuredData asks a running API server to check the JSON-LD of
+// published recipes and fails when any recipe is ineligible for rich results.
+// Admin tokens cover every recipe, author tokens their own.
+func (cli *CLI) executeStructuredData(ctx context.Context) error {
+	if cli.config.APIToken == "" {
+		return fmt.Errorf("structured-data needs --token or ALCHEMORSEL_API_TOKEN")
+	}
+	fmt.Printf("🔎 Checking recipe structured data at %s...\n", cli.config.BaseURL)
+
+	url := fmt.Sprintf("%s/api/v1/recipes/structured-data?check_images=%t", strings.TrimRight(cli.config.BaseURL, "/"), cli.config.CheckImages)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+cli.config.APIToken)
+
+	resp, err := (&http.Client{Timeout: 5 * time.Minute}).Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Data  inbound.StructuredDataReport `json:"data"`
+		Error string                       `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("invalid response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("structured data check failed (status %d): %s", resp.StatusCode, body.Error)
+	}
+	report := body.Data
+
+	if cli.config.OutputFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("Structured data: %d recipes checked, %d eligible for rich results\n", report.Checked, report.Eligible)
+		for _, recipe := range report.Recipes {
+			fmt.Printf("\n%s (%s)\n", recipe.Title, recipe.RecipeID)
+			for _, issue := range recipe.Issues {
+				fmt.Printf("  [%s] %s %s\n", issue.Severity, issue.Field, issue.Message)
+			}
+		}
+	}
+
+	if ineligible := report.Checked - report.Eligible; ineligible > 0 {
+		return fmt.Errorf("%d recipes are not eligible for rich results", ineligible)
+	}
+	fmt.Println("✅ All checked recipes are eligible for rich results")
+	return nil
+}
+
+// writeA11yHTMLReport writes the audit as a11y-report.html in the project root
+func (cli *CLI) writeA11yHTMLReport(report *a11y.Report) error {
+	var rows strings.Builder
+	for _, issue := range report.Issues {
+		fmt.Fprintf(&rows, "<tr><td>%s</td><td>%s</td><td>%s</td><td><code>%s</code></td><td>%s</td></tr>\n",
+			htmlEscape(issue.Page), issue.Severity, issue.Rule, htmlEscape(issue.Element), htmlEscape(issue.Message))
+	}
+
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="UTF-8"><title>Accessibility Report - Alchemorsel v3</title></head>
+<body style="font-family: sans-serif; padding: 2rem;">
+    <h1>Accessibility Report</h1>
+    <p>%d pages, %d issues (%d errors) - generated %s</p>
+    <table>
+        <thead><tr><th scope="col">Page</th><th scope="col">Severity</th><th scope="col">Rule</th><th scope="col">Element</th><th scope="col">Message</th></tr></thead>
+        <tbody>
+%s        </tbody>
+    </table>
+</body>
+</html>`, len(report.Pages), len(report.Issues), report.Errors(), report.GeneratedAt.Format(time.RFC3339), rows.String())
+
+	reportPath := filepath.Join(cli.config.ProjectRoot, "a11y-report.html")
+	if err := os.WriteFile(reportPath, []byte(html), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	fmt.Printf("📄 Report written to %s\n", reportPath)
+	return nil
+}
+
+// htmlEscape escapes text for the HTML report
+func htmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
+
 // printTextAnalysis prints analysis in text format
 func (cli *CLI) printTextAnalysis(metrics *performance.PerformanceMetrics) error {
 	fmt.Printf("📊 Optimization Analysis\n")
@@ -4,6 +4,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
@@ -31,6 +32,9 @@ import (
 // @BasePath /
 
 func main() {
+	profile := flag.String("profile", "", "startup profile: dev, demo or prod (overrides ALCHEMORSEL_APP_PROFILE)")
+	flag.Parse()
+
 	// Print startup banner
 	fmt.Println(`
  █████╗ ██╗      ██████╗██╗  ██╗███████╗███╗   ███╗ ██████╗ ██████╗ ███████╗███████╗██╗      
@@ -53,7 +57,7 @@ func main() {
 		
 		// Configuration
 		fx.Provide(func() (*config.Config, error) {
-			return config.Load("")
+			return config.LoadProfile("", *profile)
 		}),
 		
 		// Logger
@@ -101,9 +105,14 @@ func registerLifecycleHooks(
 	cfg *config.Config,
 	log *zap.Logger,
 	server *webserver.WebServer,
+	apiClient *webserver.APIClient,
 ) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
+			if err := apiClient.Start(ctx); err != nil {
+				return err
+			}
+
 			// Override port from environment if set
 			if port := os.Getenv("PORT"); port != "" {
 				cfg.Server.Port = parsePort(port)
@@ -117,11 +126,12 @@ func registerLifecycleHooks(
 			log.Info("Starting Web Frontend server",
 				zap.Int("port", cfg.Server.Port),
 				zap.String("environment", cfg.App.Environment),
-				zap.String("api_url", getAPIURL(cfg)),
+				zap.String("api_discovery", cfg.Web.API.Discovery),
+				zap.Any("api_backends", apiClient.Backends()),
 			)
 			
 			fmt.Printf("🚀 Alchemorsel v3 Web Frontend starting on http://localhost:%d\n", cfg.Server.Port)
-			fmt.Printf("🔗 Connected to API Backend at %s\n", getAPIURL(cfg))
+			fmt.Printf("🔗 Connected to %d API Backend instance(s)\n", len(apiClient.Backends()))
 			fmt.Println("🎨 HTMX-powered interactive UI")
 			fmt.Println("🍳 Recipe management with AI capabilities")
 			
@@ -136,6 +146,7 @@ func registerLifecycleHooks(
 		},
 		OnStop: func(ctx context.Context) error {
 			log.Info("Shutting down Web Frontend server...")
+			defer apiClient.Stop()
 			return server.Shutdown(ctx)
 		},
 	})
@@ -150,16 +161,6 @@ func parsePort(portStr string) int {
 	return port
 }
 
-func getAPIURL(cfg *config.Config) string {
-	// Check environment variable first
-	if apiURL := os.Getenv("API_URL"); apiURL != "" {
-		return apiURL
-	}
-	
-	// Default to localhost with API port
-	return fmt.Sprintf("http://localhost:3000")
-}
-
 // initializeWebHealthChecks registers health checks for the web service
 func initializeWebHealthChecks(
 	cfg *config.Config,
@@ -182,11 +183,11 @@ func initializeWebHealthChecks(
 	apiChecker := healthcheck.NewCustomChecker("api_backend", func(ctx context.Context) (healthcheck.Status, string, interface{}) {
 		if apiClient.VerifyConnection(ctx) {
 			return healthcheck.StatusHealthy, "API backend accessible", map[string]interface{}{
-				"api_url": getAPIURL(cfg),
+				"api_backends": apiClient.Backends(),
 			}
 		}
 		return healthcheck.StatusUnhealthy, "API backend not accessible", map[string]interface{}{
-			"api_url": getAPIURL(cfg),
+			"api_backends": apiClient.Backends(),
 		}
 	})
 	
@@ -0,0 +1,30 @@
+// Package access holds the authorization checks services share. The API
+// turns away callers whose token lacks a role before a handler runs; these
+// checks read the role from the account, so a token issued before a
+// demotion stops at the service.
+package access
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// RequireAdmin returns nil when requesterID is an admin's account. action
+// completes "not allowed to …" in the error returned otherwise.
+func RequireAdmin(ctx context.Context, users outbound.UserRepository, requesterID uuid.UUID, action string) error {
+	requester, err := users.FindByID(ctx, requesterID)
+	if err != nil {
+		return errors.NewDatabaseError("find user", err)
+	}
+	if requester == nil {
+		return errors.NewUserNotFoundError(requesterID.String())
+	}
+	if requester.Role() != user.UserRoleAdmin {
+		return errors.NewInsufficientPermissionsError(action)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+// Package accountdeletion erases accounts at their owner's request, after
+// a grace period in which they can change their mind
+package accountdeletion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// eraseBatch bounds the accounts erased per run
+const eraseBatch = 100
+
+// Config sets how long a requested deletion waits
+type Config struct {
+	GracePeriod time.Duration
+}
+
+// Service implements inbound.AccountDeletionService
+type Service struct {
+	deletions     outbound.AccountDeletionRepository
+	eraser        outbound.AccountEraser
+	audit         outbound.AccountAuditRepository
+	userRepo      outbound.UserRepository
+	sessions      outbound.SessionRevoker
+	cache         outbound.CacheRepository
+	invalidations outbound.CacheInvalidationBus
+	cfg           Config
+	now           func() time.Time
+	logger        *zap.Logger
+}
+
+// NewService creates the account deletion service. Users are not signed
+// out when sessions is nil.
+func NewService(
+	deletions outbound.AccountDeletionRepository,
+	eraser outbound.AccountEraser,
+	audit outbound.AccountAuditRepository,
+	userRepo outbound.UserRepository,
+	sessions outbound.SessionRevoker,
+	cache outbound.CacheRepository,
+	invalidations outbound.CacheInvalidationBus,
+	cfg Config,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		deletions:     deletions,
+		eraser:        eraser,
+		audit:         audit,
+		userRepo:      userRepo,
+		sessions:      sessions,
+		cache:         cache,
+		invalidations: invalidations,
+		cfg:           cfg,
+		now:           time.Now,
+		logger:        logger.Named("account-deletion"),
+	}
+}
+
+// RequestDeletion schedules the erasure GracePeriod from now and signs
+// the user out of every device
+func (s *Service) RequestDeletion(ctx context.Context, cmd inbound.RequestAccountDeletionCommand) (*inbound.AccountDeletionStatus, error) {
+	if cmd.Password == "" {
+		return nil, errors.NewBadRequestError("password is required to delete your account")
+	}
+	account, err := s.findUser(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := account.CheckPassword(cmd.Password); err != nil {
+		return nil, errors.NewForbiddenError("password is incorrect")
+	}
+
+	pending, err := s.deletions.Find(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find account deletion", err)
+	}
+	if pending != nil {
+		return statusOf(pending), nil
+	}
+
+	now := s.now()
+	deletion := outbound.AccountDeletion{
+		UserID:       cmd.UserID,
+		RequestedAt:  now,
+		ScheduledFor: now.Add(s.cfg.GracePeriod),
+	}
+	if err := s.deletions.Save(ctx, deletion); err != nil {
+		return nil, errors.NewDatabaseError("schedule account deletion", err)
+	}
+	s.record(ctx, cmd.UserID, &cmd.UserID, outbound.AccountAuditDeletionRequested, map[string]interface{}{
+		"scheduled_for": deletion.ScheduledFor.UTC(),
+	}, now)
+	s.signOut(cmd.UserID)
+
+	s.logger.Info("Account deletion requested",
+		zap.String("user_id", cmd.UserID.String()),
+		zap.Time("scheduled_for", deletion.ScheduledFor))
+	return statusOf(&deletion), nil
+}
+
+// CancelDeletion keeps the account
+func (s *Service) CancelDeletion(ctx context.Context, userID uuid.UUID) (*inbound.AccountDeletionStatus, error) {
+	cancelled, err := s.deletions.Delete(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("cancel account deletion", err)
+	}
+	if !cancelled {
+		return nil, errors.NewNotFoundError("pending account deletion")
+	}
+	s.record(ctx, userID, &userID, outbound.AccountAuditDeletionCancelled, nil, s.now())
+	s.logger.Info("Account deletion cancelled", zap.String("user_id", userID.String()))
+	return &inbound.AccountDeletionStatus{}, nil
+}
+
+// GetDeletionStatus reports the user's pending deletion
+func (s *Service) GetDeletionStatus(ctx context.Context, userID uuid.UUID) (*inbound.AccountDeletionStatus, error) {
+	pending, err := s.deletions.Find(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find account deletion", err)
+	}
+	if pending == nil {
+		return &inbound.AccountDeletionStatus{}, nil
+	}
+	return statusOf(pending), nil
+}
+
+// EraseDueAccounts erases up to one batch of accounts whose grace period
+// has passed. An account that fails is logged and retried next run.
+func (s *Service) EraseDueAccounts(ctx context.Context) (int, error) {
+	now := s.now()
+	due, err := s.deletions.ListDue(ctx, now, eraseBatch)
+	if err != nil {
+		return 0, errors.NewDatabaseError("list due account deletions", err)
+	}
+
+	erased, failed := 0, 0
+	for _, deletion := range due {
+		if ctx.Err() != nil {
+			return erased, ctx.Err()
+		}
+		rows, err := s.eraser.Erase(ctx, deletion.UserID, now)
+		if err != nil {
+			failed++
+			s.logger.Error("Failed to erase account", zap.String("user_id", deletion.UserID.String()), zap.Error(err))
+			continue
+		}
+		erased++
+		s.record(ctx, deletion.UserID, nil, outbound.AccountAuditErased, map[string]interface{}{
+			"requested_at": deletion.RequestedAt.UTC(),
+			"rows":         rows,
+		}, now)
+		// Sessions started during the grace period end with the account
+		s.signOut(deletion.UserID)
+		s.invalidateUser(ctx, deletion.UserID)
+		s.logger.Info("Account erased", zap.String("user_id", deletion.UserID.String()))
+	}
+	if failed > 0 {
+		return erased, errors.NewInternalError(fmt.Sprintf("%d accounts could not be erased", failed))
+	}
+	return erased, nil
+}
+
+// record writes an audit event. The change it records has already been
+// made, so a failure is logged rather than returned.
+func (s *Service) record(ctx context.Context, userID uuid.UUID, actorID *uuid.UUID, action string, detail map[string]interface{}, at time.Time) {
+	if detail == nil {
+		detail = map[string]interface{}{}
+	}
+	encoded, err := json.Marshal(detail)
+	if err != nil {
+		encoded = []byte("{}")
+	}
+	event := outbound.AccountAuditEvent{
+		ID:        uuid.New(),
+		UserID:    userID,
+		ActorID:   actorID,
+		Action:    action,
+		Detail:    string(encoded),
+		CreatedAt: at,
+	}
+	if err := s.audit.Record(ctx, event); err != nil {
+		s.logger.Error("Failed to record account audit event",
+			zap.String("user_id", userID.String()),
+			zap.String("action", action),
+			zap.Error(err))
+	}
+}
+
+// signOut revokes the user's tokens on every device
+func (s *Service) signOut(userID uuid.UUID) {
+	if s.sessions == nil {
+		return
+	}
+	if err := s.sessions.RevokeAllUserTokens(userID.String()); err != nil {
+		s.logger.Warn("Failed to revoke user sessions", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+}
+
+func (s *Service) findUser(ctx context.Context, userID uuid.UUID) (*user.User, error) {
+	accounts, err := s.userRepo.FindByIDs(ctx, []uuid.UUID{userID})
+	if err != nil {
+		return nil, errors.NewDatabaseError("find user", err)
+	}
+	if len(accounts) == 0 {
+		return nil, errors.NewUserNotFoundError(userID.String())
+	}
+	return accounts[0], nil
+}
+
+// invalidateUser drops the cached user here and on the other replicas, so
+// the erased profile is not served from cache
+func (s *Service) invalidateUser(ctx context.Context, userID uuid.UUID) {
+	key := "user:" + userID.String()
+	if s.cache != nil {
+		s.cache.Delete(ctx, key)
+	}
+	if s.invalidations == nil {
+		return
+	}
+	if err := s.invalidations.Publish(ctx, key); err != nil {
+		s.logger.Warn("Failed to broadcast user invalidation", zap.String("key", key), zap.Error(err))
+	}
+}
+
+func statusOf(deletion *outbound.AccountDeletion) *inbound.AccountDeletionStatus {
+	requested, scheduled := deletion.RequestedAt, deletion.ScheduledFor
+	return &inbound.AccountDeletionStatus{Pending: true, RequestedAt: &requested, ScheduledFor: &scheduled}
+}
@@ -0,0 +1,166 @@
+package accountdeletion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type deletionStore struct {
+	pending map[uuid.UUID]outbound.AccountDeletion
+}
+
+func (s *deletionStore) Save(_ context.Context, d outbound.AccountDeletion) error {
+	s.pending[d.UserID] = d
+	return nil
+}
+
+func (s *deletionStore) Find(_ context.Context, userID uuid.UUID) (*outbound.AccountDeletion, error) {
+	d, ok := s.pending[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &d, nil
+}
+
+func (s *deletionStore) Delete(_ context.Context, userID uuid.UUID) (bool, error) {
+	_, ok := s.pending[userID]
+	delete(s.pending, userID)
+	return ok, nil
+}
+
+func (s *deletionStore) ListDue(_ context.Context, now time.Time, _ int) ([]outbound.AccountDeletion, error) {
+	var due []outbound.AccountDeletion
+	for _, d := range s.pending {
+		if !d.ScheduledFor.After(now) {
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+type eraser struct {
+	store  *deletionStore
+	erased []uuid.UUID
+}
+
+func (e *eraser) Erase(_ context.Context, userID uuid.UUID, _ time.Time) (map[string]int64, error) {
+	e.erased = append(e.erased, userID)
+	delete(e.store.pending, userID)
+	return map[string]int64{"users": 1}, nil
+}
+
+type auditLog struct{ events []outbound.AccountAuditEvent }
+
+func (a *auditLog) Record(_ context.Context, event outbound.AccountAuditEvent) error {
+	a.events = append(a.events, event)
+	return nil
+}
+
+func (a *auditLog) ListByUser(_ context.Context, userID uuid.UUID) ([]outbound.AccountAuditEvent, error) {
+	var events []outbound.AccountAuditEvent
+	for _, e := range a.events {
+		if e.UserID == userID {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+type userStore struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *userStore) FindByIDs(_ context.Context, ids []uuid.UUID) ([]*user.User, error) {
+	var found []*user.User
+	for _, id := range ids {
+		if u, ok := s.users[id]; ok {
+			found = append(found, u)
+		}
+	}
+	return found, nil
+}
+
+type revoker struct{ signedOut []string }
+
+func (r *revoker) RevokeAllUserTokens(userID string) error {
+	r.signedOut = append(r.signedOut, userID)
+	return nil
+}
+
+func TestDeletionWaitsOutTheGracePeriodAndCanBeCancelled(t *testing.T) {
+	ctx := context.Background()
+	account, err := user.NewUser("sam@example.com", "Sam Cook", "correct horse battery")
+	require.NoError(t, err)
+	id := account.ID()
+
+	deletions := &deletionStore{pending: map[uuid.UUID]outbound.AccountDeletion{}}
+	erase := &eraser{store: deletions}
+	audit := &auditLog{}
+	sessions := &revoker{}
+	svc := NewService(deletions, erase, audit, &userStore{users: map[uuid.UUID]*user.User{id: account}}, sessions,
+		nil, nil, Config{GracePeriod: 30 * 24 * time.Hour}, zap.NewNop())
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	_, err = svc.RequestDeletion(ctx, inbound.RequestAccountDeletionCommand{UserID: id, Password: "wrong password"})
+	assert.Equal(t, errors.CodeForbidden, errors.Wrap(err, "").Code)
+	assert.Empty(t, deletions.pending)
+
+	status, err := svc.RequestDeletion(ctx, inbound.RequestAccountDeletionCommand{UserID: id, Password: "correct horse battery"})
+	require.NoError(t, err)
+	assert.True(t, status.Pending)
+	assert.Equal(t, now.Add(30*24*time.Hour), *status.ScheduledFor)
+	assert.Equal(t, []string{id.String()}, sessions.signedOut)
+
+	now = now.Add(24 * time.Hour)
+	again, err := svc.RequestDeletion(ctx, inbound.RequestAccountDeletionCommand{UserID: id, Password: "correct horse battery"})
+	require.NoError(t, err)
+	assert.Equal(t, status.ScheduledFor, again.ScheduledFor, "asking again keeps the date")
+
+	erased, err := svc.EraseDueAccounts(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, erased, "the grace period has not passed")
+
+	_, err = svc.CancelDeletion(ctx, id)
+	require.NoError(t, err)
+	_, err = svc.CancelDeletion(ctx, id)
+	assert.Equal(t, errors.CodeNotFound, errors.Wrap(err, "").Code)
+
+	_, err = svc.RequestDeletion(ctx, inbound.RequestAccountDeletionCommand{UserID: id, Password: "correct horse battery"})
+	require.NoError(t, err)
+	now = now.Add(31 * 24 * time.Hour)
+	erased, err = svc.EraseDueAccounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+	assert.Equal(t, []uuid.UUID{id}, erase.erased)
+
+	status, err = svc.GetDeletionStatus(ctx, id)
+	require.NoError(t, err)
+	assert.False(t, status.Pending)
+
+	events, err := audit.ListByUser(ctx, id)
+	require.NoError(t, err)
+	var actions []string
+	for _, e := range events {
+		actions = append(actions, e.Action)
+	}
+	assert.Equal(t, []string{
+		outbound.AccountAuditDeletionRequested,
+		outbound.AccountAuditDeletionCancelled,
+		outbound.AccountAuditDeletionRequested,
+		outbound.AccountAuditErased,
+	}, actions)
+	assert.Nil(t, events[3].ActorID, "erasure is done by the scheduler")
+	assert.JSONEq(t, `{"requested_at":"2026-10-02T12:00:00Z","rows":{"users":1}}`, events[3].Detail)
+}
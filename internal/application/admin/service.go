@@ -0,0 +1,301 @@
+// Package admin runs the admin section: user and recipe management,
+// system totals, and review of AI-generated recipes
+package admin
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	// newWindow is how far back the dashboard counts new users and recipes
+	newWindow = 7 * 24 * time.Hour
+)
+
+// Service implements inbound.AdminService
+type Service struct {
+	admin         outbound.AdminRepository
+	userRepo      outbound.UserRepository
+	recipes       inbound.RecipeService
+	cache         outbound.CacheRepository
+	invalidations outbound.CacheInvalidationBus
+	startedAt     time.Time
+	now           func() time.Time
+	logger        *zap.Logger
+}
+
+// NewService creates the admin service
+func NewService(
+	admin outbound.AdminRepository,
+	userRepo outbound.UserRepository,
+	recipes inbound.RecipeService,
+	cache outbound.CacheRepository,
+	invalidations outbound.CacheInvalidationBus,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		admin:         admin,
+		userRepo:      userRepo,
+		recipes:       recipes,
+		cache:         cache,
+		invalidations: invalidations,
+		startedAt:     time.Now(),
+		now:           time.Now,
+		logger:        logger.Named("admin"),
+	}
+}
+
+// ListUsers pages through users, newest first
+func (s *Service) ListUsers(ctx context.Context, requesterID uuid.UUID, query inbound.AdminUserQuery) (*inbound.AdminUserPage, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "list users"); err != nil {
+		return nil, err
+	}
+
+	filter := outbound.AdminUserFilter{
+		Search: strings.TrimSpace(query.Search),
+		Limit:  pageSize(query.Limit),
+		Offset: query.Offset,
+	}
+	switch query.Status {
+	case "":
+	case "active", "suspended":
+		active := query.Status == "active"
+		filter.Active = &active
+	default:
+		return nil, errors.NewBadRequestError("status must be active or suspended")
+	}
+	switch user.UserRole(query.Role) {
+	case "", user.UserRoleUser, user.UserRoleChef, user.UserRoleAdmin:
+		filter.Role = query.Role
+	default:
+		return nil, errors.NewBadRequestError("role must be user, chef or admin")
+	}
+
+	rows, total, err := s.admin.ListUsers(ctx, filter)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list users", err)
+	}
+	page := &inbound.AdminUserPage{
+		Users:  make([]inbound.AdminUser, len(rows)),
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+	for i, row := range rows {
+		page.Users[i] = inbound.AdminUser{
+			ID:          row.ID,
+			Email:       row.Email,
+			Name:        row.Name,
+			Role:        row.Role,
+			Status:      accountStatus(row.Active),
+			Verified:    row.Verified,
+			Recipes:     row.Recipes,
+			CreatedAt:   row.CreatedAt,
+			LastLoginAt: row.LastLoginAt,
+		}
+	}
+	return page, nil
+}
+
+// SuspendUser deactivates an account. Admins cannot suspend themselves or
+// each other.
+func (s *Service) SuspendUser(ctx context.Context, cmd inbound.AdminUserCommand) (*inbound.AdminUser, error) {
+	return s.setActive(ctx, cmd, false)
+}
+
+// ReinstateUser reactivates a suspended account
+func (s *Service) ReinstateUser(ctx context.Context, cmd inbound.AdminUserCommand) (*inbound.AdminUser, error) {
+	return s.setActive(ctx, cmd, true)
+}
+
+func (s *Service) setActive(ctx context.Context, cmd inbound.AdminUserCommand, active bool) (*inbound.AdminUser, error) {
+	action := "suspend users"
+	if active {
+		action = "reinstate users"
+	}
+	if err := access.RequireAdmin(ctx, s.userRepo, cmd.RequesterID, action); err != nil {
+		return nil, err
+	}
+	if cmd.UserID == cmd.RequesterID {
+		return nil, errors.NewBadRequestError("admins cannot suspend or reinstate themselves")
+	}
+
+	account, err := s.userRepo.FindByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find user", err)
+	}
+	if account == nil {
+		return nil, errors.NewUserNotFoundError(cmd.UserID.String())
+	}
+	if account.Role() == user.UserRoleAdmin {
+		return nil, errors.NewInsufficientPermissionsError("suspend or reinstate an admin")
+	}
+
+	if account.IsActive() != active {
+		if active {
+			account.Activate()
+		} else {
+			account.Deactivate()
+		}
+		if err := s.userRepo.Update(ctx, account); err != nil {
+			return nil, errors.NewDatabaseError("update user", err)
+		}
+		s.invalidateUser(ctx, account.ID().String())
+	}
+
+	s.logger.Info("User status changed by admin",
+		zap.String("admin_id", cmd.RequesterID.String()),
+		zap.String("user_id", account.ID().String()),
+		zap.String("status", accountStatus(active)),
+		zap.String("reason", cmd.Reason),
+	)
+	return &inbound.AdminUser{
+		ID:          account.ID(),
+		Email:       account.Email(),
+		Name:        account.Name(),
+		Role:        string(account.Role()),
+		Status:      accountStatus(account.IsActive()),
+		Verified:    account.IsVerified(),
+		CreatedAt:   account.CreatedAt(),
+		LastLoginAt: account.LastLoginAt(),
+	}, nil
+}
+
+// UnpublishRecipe archives a published recipe through the recipe service,
+// which clears the caches and records the change
+func (s *Service) UnpublishRecipe(ctx context.Context, cmd inbound.AdminRecipeCommand) error {
+	if err := access.RequireAdmin(ctx, s.userRepo, cmd.RequesterID, "unpublish recipes"); err != nil {
+		return err
+	}
+	if err := s.recipes.ArchiveRecipe(ctx, cmd.RecipeID, cmd.RequesterID); err != nil {
+		return err
+	}
+	s.logger.Info("Recipe unpublished by admin",
+		zap.String("admin_id", cmd.RequesterID.String()),
+		zap.String("recipe_id", cmd.RecipeID.String()),
+		zap.String("reason", cmd.Reason),
+	)
+	return nil
+}
+
+// SystemStats counts users, recipes and comments, and describes the
+// replica answering
+func (s *Service) SystemStats(ctx context.Context, requesterID uuid.UUID) (*inbound.SystemStats, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "view system stats"); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	row, err := s.admin.Stats(ctx, now.Add(-newWindow))
+	if err != nil {
+		return nil, errors.NewDatabaseError("count system stats", err)
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	stats := &inbound.SystemStats{
+		Users: inbound.UserStats{
+			Total:     row.Users,
+			Active:    row.ActiveUsers,
+			Suspended: row.Users - row.ActiveUsers,
+			Admins:    row.Admins,
+			New:       row.NewUsers,
+		},
+		Recipes: inbound.RecipeStats{
+			Draft:       row.RecipesByState["draft"],
+			Published:   row.RecipesByState["published"],
+			Archived:    row.RecipesByState["archived"],
+			AIGenerated: row.AIRecipes,
+			New:         row.NewRecipes,
+		},
+		Comments: inbound.CommentStats{Total: row.Comments, Hidden: row.HiddenComments},
+		Runtime: inbound.RuntimeStats{
+			StartedAt:  s.startedAt,
+			Uptime:     now.Sub(s.startedAt).Truncate(time.Second).String(),
+			GoVersion:  runtime.Version(),
+			Goroutines: runtime.NumGoroutine(),
+			HeapMB:     math.Round(float64(mem.HeapAlloc)/(1<<20)*10) / 10,
+		},
+	}
+	for _, n := range row.RecipesByState {
+		stats.Recipes.Total += n
+	}
+	return stats, nil
+}
+
+// ListAIContent pages through AI-generated recipes with their prompts,
+// newest first
+func (s *Service) ListAIContent(ctx context.Context, requesterID uuid.UUID, limit, offset int) (*inbound.AIContentPage, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "review AI content"); err != nil {
+		return nil, err
+	}
+
+	limit = pageSize(limit)
+	rows, total, err := s.admin.ListAIRecipes(ctx, limit, offset)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list AI recipes", err)
+	}
+	page := &inbound.AIContentPage{
+		Recipes: make([]inbound.AIContent, len(rows)),
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}
+	for i, row := range rows {
+		page.Recipes[i] = inbound.AIContent{
+			ID:          row.ID,
+			Title:       row.Title,
+			AuthorID:    row.AuthorID,
+			AuthorEmail: row.AuthorEmail,
+			Status:      row.Status,
+			Model:       row.AIModel,
+			Prompt:      row.AIPrompt,
+			CreatedAt:   row.CreatedAt,
+		}
+	}
+	return page, nil
+}
+
+// invalidateUser drops the cached user here and on the other replicas
+func (s *Service) invalidateUser(ctx context.Context, userID string) {
+	key := "user:" + userID
+	if s.cache != nil {
+		s.cache.Delete(ctx, key)
+	}
+	if s.invalidations == nil {
+		return
+	}
+	if err := s.invalidations.Publish(ctx, key); err != nil {
+		s.logger.Warn("Failed to broadcast user invalidation", zap.String("key", key), zap.Error(err))
+	}
+}
+
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
+
+func accountStatus(active bool) string {
+	if active {
+		return "active"
+	}
+	return "suspended"
+}
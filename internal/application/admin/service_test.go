@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+func (s *stubUsers) Update(ctx context.Context, u *user.User) error {
+	s.users[u.ID()] = u
+	return nil
+}
+
+type stubRecipes struct {
+	inbound.RecipeService
+	archived []uuid.UUID
+}
+
+func (s *stubRecipes) ArchiveRecipe(ctx context.Context, id, userID uuid.UUID) error {
+	s.archived = append(s.archived, id)
+	return nil
+}
+
+func newAccount(role user.UserRole) *user.User {
+	now := time.Now()
+	return user.ReconstructUser(uuid.New(), string(role)+"@example.com", string(role), "", true, true, role, now, now, nil)
+}
+
+func TestSuspendRequiresAdminAndSparesAdmins(t *testing.T) {
+	admin, other, member := newAccount(user.UserRoleAdmin), newAccount(user.UserRoleAdmin), newAccount(user.UserRoleUser)
+	users := &stubUsers{users: map[uuid.UUID]*user.User{admin.ID(): admin, other.ID(): other, member.ID(): member}}
+	recipes := &stubRecipes{}
+	svc := NewService(nil, users, recipes, nil, nil, zap.NewNop())
+	ctx := context.Background()
+
+	_, err := svc.SuspendUser(ctx, inbound.AdminUserCommand{RequesterID: member.ID(), UserID: admin.ID()})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	_, err = svc.SuspendUser(ctx, inbound.AdminUserCommand{RequesterID: admin.ID(), UserID: other.ID()})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions), "admins cannot suspend each other")
+	_, err = svc.SuspendUser(ctx, inbound.AdminUserCommand{RequesterID: admin.ID(), UserID: admin.ID()})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	suspended, err := svc.SuspendUser(ctx, inbound.AdminUserCommand{RequesterID: admin.ID(), UserID: member.ID(), Reason: "spam"})
+	require.NoError(t, err)
+	assert.Equal(t, "suspended", suspended.Status)
+	assert.False(t, users.users[member.ID()].IsActive())
+
+	reinstated, err := svc.ReinstateUser(ctx, inbound.AdminUserCommand{RequesterID: admin.ID(), UserID: member.ID()})
+	require.NoError(t, err)
+	assert.Equal(t, "active", reinstated.Status)
+
+	recipeID := uuid.New()
+	require.NoError(t, svc.UnpublishRecipe(ctx, inbound.AdminRecipeCommand{RequesterID: admin.ID(), RecipeID: recipeID}))
+	assert.Equal(t, []uuid.UUID{recipeID}, recipes.archived)
+	assert.Error(t, svc.UnpublishRecipe(ctx, inbound.AdminRecipeCommand{RequesterID: member.ID(), RecipeID: recipeID}))
+}
@@ -259,6 +259,96 @@ func (s *AIService) ClassifyRecipe(ctx context.Context, rec *recipe.Recipe) (*ou
 	return classification, nil
 }
 
+// SuggestSearchQueries suggests alternatives for a search that found nothing,
+// falling back to the query's individual terms as broader searches
+func (s *AIService) SuggestSearchQueries(ctx context.Context, query string) ([]string, error) {
+	s.logger.Info("Suggesting search queries", zap.String("query", query))
+
+	// Try primary provider
+	suggestions, err := s.client.SuggestSearchQueries(ctx, query)
+	if err != nil || len(suggestions) == 0 {
+		if err != nil {
+			s.logger.Warn("Primary AI provider failed for search suggestions, using fallback",
+				zap.Error(err))
+		}
+
+		suggestions = []string{}
+		if terms := strings.Fields(strings.ToLower(query)); len(terms) > 1 {
+			for _, term := range terms {
+				if len(term) >= 3 && len(suggestions) < 3 {
+					suggestions = append(suggestions, term)
+				}
+			}
+		}
+	}
+
+	return suggestions, nil
+}
+
+// SuggestStepTechniques picks library techniques for each recipe step. A
+// failed provider is logged and yields no suggestions; callers fall back
+// to matching technique names themselves.
+func (s *AIService) SuggestStepTechniques(ctx context.Context, steps []string, techniques []string) ([][]string, error) {
+	s.logger.Info("Suggesting step techniques", zap.Int("steps", len(steps)))
+
+	suggestions, err := s.client.SuggestStepTechniques(ctx, steps, techniques)
+	if err != nil {
+		s.logger.Warn("Primary AI provider failed for technique suggestions", zap.Error(err))
+		return [][]string{}, nil
+	}
+
+	return suggestions, nil
+}
+
+// Translate machine-translates recipe text. Unlike suggestions there is no
+// fallback, so a failed provider is an error for the caller.
+func (s *AIService) Translate(ctx context.Context, texts []string, from, to string) (*outbound.AITranslation, error) {
+	s.logger.Info("Translating recipe text",
+		zap.Int("texts", len(texts)),
+		zap.String("from", from),
+		zap.String("to", to),
+	)
+
+	translation, err := s.client.Translate(ctx, texts, from, to)
+	if err != nil {
+		s.logger.Warn("Primary AI provider failed for translation",
+			zap.String("primary_provider", s.provider),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+// Embed vectorizes texts for search by meaning. Vectors from another
+// provider would not compare with stored ones, so there is no fallback.
+func (s *AIService) Embed(ctx context.Context, texts []string) (*outbound.AIEmbeddings, error) {
+	embeddings, err := s.client.Embed(ctx, texts)
+	if err != nil {
+		s.logger.Warn("Primary AI provider failed for embeddings",
+			zap.String("primary_provider", s.provider),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return embeddings, nil
+}
+
+// SuggestSubstitutes proposes substitutes for an ingredient. A failed
+// provider is logged and yields no suggestions; the knowledge base is
+// still there.
+func (s *AIService) SuggestSubstitutes(ctx context.Context, ingredient string) (*outbound.AISubstitutes, error) {
+	s.logger.Info("Suggesting substitutes", zap.String("ingredient", ingredient))
+
+	suggested, err := s.client.SuggestSubstitutes(ctx, ingredient)
+	if err != nil {
+		s.logger.Warn("Primary AI provider failed for substitute suggestions", zap.Error(err))
+		return &outbound.AISubstitutes{Substitutes: []outbound.AISubstitute{}}, nil
+	}
+
+	return suggested, nil
+}
+
 // generateMockRecipe generates a mock recipe for demo purposes
 func (s *AIService) generateMockRecipe(prompt string, constraints outbound.AIConstraints) (*outbound.AIRecipeResponse, error) {
 	// Create AI request for tracking
@@ -427,6 +517,7 @@ func (s *AIService) createMockRecipeFromPrompt(prompt string, constraints outbou
 		Nutrition:    nutrition,
 		Tags:         tags,
 		Confidence:   0.85, // High confidence for mock recipes
+		Language:     "en",
 	}
 }
 
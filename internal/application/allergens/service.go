@@ -0,0 +1,110 @@
+// Package allergens discloses the major allergens in recipes, for the
+// recipe page panel, the JSON API and exports.
+package allergens
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/allergens"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service implements inbound.AllergenService
+type Service struct {
+	recipes outbound.RecipeAllergenRepository
+}
+
+// NewService creates an allergen service
+func NewService(repo outbound.RecipeAllergenRepository) *Service {
+	return &Service{recipes: repo}
+}
+
+// Disclose works out a recipe's allergen panel from its ingredients
+func (s *Service) Disclose(ctx context.Context, query inbound.AllergenQuery) (*inbound.AllergenDisclosure, error) {
+	id, err := uuid.Parse(query.RecipeID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid recipe ID")
+	}
+
+	r, err := s.recipes.Recipe(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if r == nil || (r.Status != "published" && r.AuthorID != query.RequesterID) {
+		return nil, errors.NewRecipeNotFoundError(query.RecipeID)
+	}
+
+	ingredients := make([]allergens.Ingredient, len(r.Ingredients))
+	for i, line := range r.Ingredients {
+		ingredients[i] = allergens.Ingredient{Name: line.Name, Optional: line.Optional, Notes: line.Notes}
+	}
+	d := allergens.Disclose(ingredients)
+
+	disclosure := &inbound.AllergenDisclosure{
+		RecipeID:   r.ID.String(),
+		Title:      r.Title,
+		Contains:   nonNil(d.Contains()),
+		MayContain: nonNil(d.MayContain()),
+		Panel:      d.Panel(),
+		Allergens:  make([]inbound.AllergenFinding, len(d.Findings)),
+	}
+	for i, f := range d.Findings {
+		sources := make([]inbound.AllergenSource, len(f.Sources))
+		for j, src := range f.Sources {
+			sources[j] = inbound.AllergenSource{Ingredient: src.Ingredient, Reason: string(src.Reason)}
+		}
+		disclosure.Allergens[i] = inbound.AllergenFinding{
+			Allergen: string(f.Allergen),
+			Label:    f.Label,
+			Status:   string(f.Status),
+			Sources:  sources,
+		}
+	}
+	return disclosure, nil
+}
+
+// labelBatch is how many unlabelled recipes are read at a time
+const labelBatch = 100
+
+// LabelMissing labels the recipes saved before allergen labels were stored
+// with them, from their ingredients. Recipes are labelled whenever they
+// are saved, so once this has run it finds none.
+func LabelMissing(ctx context.Context, repo outbound.RecipeAllergenRepository, logger *zap.Logger) error {
+	labelled := 0
+	for {
+		batch, err := repo.Unlabelled(ctx, labelBatch)
+		if err != nil {
+			return err
+		}
+		for _, r := range batch {
+			ingredients := make([]recipe.Ingredient, len(r.Ingredients))
+			for i, line := range r.Ingredients {
+				ingredients[i] = recipe.Ingredient{Name: line.Name, Optional: line.Optional, Notes: line.Notes}
+			}
+			if err := repo.SetLabel(ctx, r.ID, allergens.Label(ingredients)); err != nil {
+				return err
+			}
+		}
+		labelled += len(batch)
+		if len(batch) < labelBatch {
+			break
+		}
+	}
+	if labelled > 0 {
+		logger.Info("Recipe allergens labelled", zap.Int("recipes", labelled))
+	}
+	return nil
+}
+
+// nonNil keeps empty lists as [] in JSON
+func nonNil(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
@@ -0,0 +1,554 @@
+// Package archive moves RUM views and audit rows past their retention out
+// of the database into compressed daily files in blob storage, and reads
+// them back for long-term trends and audits.
+//
+// Views are rolled up per recipe and day, since trends only need the
+// counts; audit rows are kept whole. Files are gzipped JSON lines, one
+// record per line.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultRecipeViewDays and DefaultAuditDays are the hot retentions
+	DefaultRecipeViewDays = 90
+	DefaultAuditDays      = 365
+	// DefaultMaxDaysPerRun bounds the days one run archives per dataset
+	DefaultMaxDaysPerRun = 30
+	// maxHistoryDays and maxAuditDays bound one read of the archive, as
+	// every day is a separate file
+	maxHistoryDays = 3 * 365
+	maxAuditDays   = 31
+	// historyTopN caps the referrer list
+	historyTopN = 20
+
+	day = 24 * time.Hour
+)
+
+// Config sets the retention of each dataset
+type Config struct {
+	RecipeViewDays int
+	AuditDays      int
+	MaxDaysPerRun  int
+	BlobPrefix     string
+}
+
+// Service implements inbound.ArchiveService
+type Service struct {
+	archives   outbound.ArchiveRepository
+	blobs      outbound.BlobStorage
+	recipeRepo outbound.RecipeRepository
+	userRepo   outbound.UserRepository
+	cfg        Config
+	now        func() time.Time
+	logger     *zap.Logger
+
+	mu      sync.Mutex
+	running bool
+}
+
+// NewService creates an archive service
+func NewService(
+	archives outbound.ArchiveRepository,
+	blobs outbound.BlobStorage,
+	recipeRepo outbound.RecipeRepository,
+	userRepo outbound.UserRepository,
+	cfg Config,
+	logger *zap.Logger,
+) *Service {
+	if cfg.RecipeViewDays <= 0 {
+		cfg.RecipeViewDays = DefaultRecipeViewDays
+	}
+	if cfg.AuditDays <= 0 {
+		cfg.AuditDays = DefaultAuditDays
+	}
+	if cfg.MaxDaysPerRun <= 0 {
+		cfg.MaxDaysPerRun = DefaultMaxDaysPerRun
+	}
+	return &Service{
+		archives:   archives,
+		blobs:      blobs,
+		recipeRepo: recipeRepo,
+		userRepo:   userRepo,
+		cfg:        cfg,
+		now:        time.Now,
+		logger:     logger.Named("archive"),
+	}
+}
+
+// viewRecord is one line of a recipe_views file
+type viewRecord struct {
+	RecipeID       uuid.UUID      `json:"recipe_id"`
+	Day            string         `json:"day"`
+	Views          int            `json:"views"`
+	UniqueViewers  int            `json:"unique_viewers"`
+	ScrollDepthSum int            `json:"scroll_depth_sum"`
+	ScrollSamples  int            `json:"scroll_samples"`
+	EngagedMSSum   int64          `json:"engaged_ms_sum"`
+	EngagedSamples int            `json:"engaged_samples"`
+	ReferrerHosts  map[string]int `json:"referrer_hosts,omitempty"`
+}
+
+// captureRecord is one line of a profile_captures file
+type captureRecord struct {
+	ID          uuid.UUID  `json:"id"`
+	Kind        string     `json:"kind"`
+	Seconds     int        `json:"seconds"`
+	Status      string     `json:"status"`
+	RequestedBy uuid.UUID  `json:"requested_by"`
+	IPAddress   string     `json:"ip_address,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	BlobKey     string     `json:"blob_key,omitempty"`
+	SizeBytes   int64      `json:"size_bytes"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// RunTiering archives every dataset's days past retention, oldest first
+func (s *Service) RunTiering(ctx context.Context) (*inbound.ArchiveRunReport, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, errors.NewConflictError("archive tiering is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	report := &inbound.ArchiveRunReport{
+		StartedAt:  s.now().UTC().Format(time.RFC3339),
+		Partitions: []inbound.ArchivedDay{},
+	}
+	today := s.now().UTC().Truncate(day)
+	datasets := []struct {
+		name string
+		days int
+	}{
+		{outbound.ArchiveRecipeViews, s.cfg.RecipeViewDays},
+		{outbound.ArchiveProfileCaptures, s.cfg.AuditDays},
+	}
+
+	var runErr error
+	for _, dataset := range datasets {
+		cutoff := today.AddDate(0, 0, -dataset.days)
+		if err := s.tierDataset(ctx, dataset.name, cutoff, report); err != nil {
+			runErr = err
+			break
+		}
+	}
+	report.FinishedAt = s.now().UTC().Format(time.RFC3339)
+
+	if len(report.Partitions) > 0 {
+		s.logger.Info("Archive tiering finished", zap.Int("partitions", len(report.Partitions)))
+	}
+	return report, runErr
+}
+
+// TriggerTiering runs tiering on an admin's request
+func (s *Service) TriggerTiering(ctx context.Context, requesterID uuid.UUID) (*inbound.ArchiveRunReport, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "run archive tiering"); err != nil {
+		return nil, err
+	}
+	return s.RunTiering(ctx)
+}
+
+// tierDataset archives a dataset's days before cutoff, one day at a time so
+// an interrupted run loses at most the day in progress
+func (s *Service) tierDataset(ctx context.Context, dataset string, cutoff time.Time, report *inbound.ArchiveRunReport) error {
+	for i := 0; i < s.cfg.MaxDaysPerRun; i++ {
+		oldest, err := s.archives.OldestBefore(ctx, dataset, cutoff)
+		if err != nil {
+			return errors.NewDatabaseError("find archivable rows", err)
+		}
+		if oldest == nil {
+			return nil
+		}
+
+		archived, err := s.archiveDay(ctx, dataset, oldest.UTC().Truncate(day))
+		if err != nil {
+			return err
+		}
+		if archived == nil {
+			// The rows went away between the lookup and the read; the next
+			// run starts from whatever is oldest then
+			return nil
+		}
+		report.Partitions = append(report.Partitions, *archived)
+	}
+	return nil
+}
+
+// archiveDay writes one day's file, lists it and only then deletes the hot
+// rows, so a failure leaves the rows in place to be archived again
+func (s *Service) archiveDay(ctx context.Context, dataset string, from time.Time) (*inbound.ArchivedDay, error) {
+	to := from.Add(day)
+
+	var records []interface{}
+	var sourceRows int
+	switch dataset {
+	case outbound.ArchiveRecipeViews:
+		rollups, read, err := s.archives.RollupRecipeViews(ctx, from, to)
+		if err != nil {
+			return nil, errors.NewDatabaseError("roll up recipe views", err)
+		}
+		sourceRows = read
+		for _, rollup := range rollups {
+			records = append(records, toViewRecord(rollup))
+		}
+	case outbound.ArchiveProfileCaptures:
+		captures, err := s.archives.ProfileCapturesBetween(ctx, from, to)
+		if err != nil {
+			return nil, errors.NewDatabaseError("read profile captures", err)
+		}
+		sourceRows = len(captures)
+		for _, capture := range captures {
+			records = append(records, toCaptureRecord(capture))
+		}
+	}
+
+	if sourceRows == 0 {
+		return nil, nil
+	}
+
+	partition := &outbound.ArchivePartition{
+		ID:         uuid.New(),
+		Dataset:    dataset,
+		Day:        from,
+		Records:    len(records),
+		SourceRows: sourceRows,
+	}
+	partition.BlobKey = s.blobKey(partition)
+
+	data, err := encodeRecords(records)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to encode archive")
+	}
+	size, err := s.blobs.Put(ctx, partition.BlobKey, "application/gzip", bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.NewExternalServiceError("blob storage", err)
+	}
+	partition.SizeBytes = size
+	partition.ArchivedAt = s.now().UTC()
+
+	if err := s.archives.SavePartition(ctx, partition); err != nil {
+		return nil, errors.NewDatabaseError("save archive partition", err)
+	}
+	deleted, err := s.archives.DeleteBetween(ctx, dataset, from, to)
+	if err != nil {
+		return nil, errors.NewDatabaseError("delete archived rows", err)
+	}
+	if int(deleted) != sourceRows {
+		s.logger.Warn("Archived and deleted row counts differ",
+			zap.String("dataset", dataset),
+			zap.Time("day", from),
+			zap.Int("archived", sourceRows),
+			zap.Int64("deleted", deleted),
+		)
+	}
+
+	return &inbound.ArchivedDay{
+		Dataset:    dataset,
+		Day:        from.Format("2006-01-02"),
+		Records:    partition.Records,
+		SourceRows: sourceRows,
+		SizeBytes:  size,
+	}, nil
+}
+
+// RecipeViewHistory merges a recipe's rollups from the archived days in the
+// range. Only the author and admins may read it.
+func (s *Service) RecipeViewHistory(ctx context.Context, query inbound.RecipeViewHistoryQuery) (*inbound.RecipeViewHistory, error) {
+	requester, err := s.userRepo.FindByID(ctx, query.RequesterID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find user", err)
+	}
+	if requester == nil {
+		return nil, errors.NewUserNotFoundError(query.RequesterID.String())
+	}
+	entity, err := s.recipeRepo.FindByID(ctx, query.RecipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil {
+		return nil, errors.NewRecipeNotFoundError(query.RecipeID.String())
+	}
+	if entity.AuthorID() != requester.ID() && requester.Role() != user.UserRoleAdmin {
+		return nil, errors.NewInsufficientPermissionsError("view this recipe's analytics")
+	}
+
+	from, to, err := s.historyRange(query.From, query.To, maxHistoryDays)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &inbound.RecipeViewHistory{
+		RecipeID:  query.RecipeID,
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		Daily:     []inbound.DailyViews{},
+		Referrers: []inbound.AnalyticsCount{},
+	}
+	daily := make(map[string]*inbound.DailyViews)
+	referrers := make(map[string]int)
+	var scrollSum, scrollSamples, engagedSamples int
+	var engagedSum int64
+
+	err = s.readPartitions(ctx, outbound.ArchiveRecipeViews, from, to, func(line []byte) error {
+		// Every recipe of the day shares the file; skip others unparsed
+		if !bytes.Contains(line, []byte(query.RecipeID.String())) {
+			return nil
+		}
+		var record viewRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return err
+		}
+		if record.RecipeID != query.RecipeID {
+			return nil
+		}
+
+		point, ok := daily[record.Day]
+		if !ok {
+			point = &inbound.DailyViews{Date: record.Day}
+			daily[record.Day] = point
+		}
+		point.Views += record.Views
+		point.UniqueViewers += record.UniqueViewers
+		history.Views += record.Views
+		history.UniqueViewers += record.UniqueViewers
+		scrollSum += record.ScrollDepthSum
+		scrollSamples += record.ScrollSamples
+		engagedSum += record.EngagedMSSum
+		engagedSamples += record.EngagedSamples
+		for host, count := range record.ReferrerHosts {
+			referrers[host] += count
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, point := range daily {
+		history.Daily = append(history.Daily, *point)
+	}
+	sort.Slice(history.Daily, func(i, j int) bool { return history.Daily[i].Date < history.Daily[j].Date })
+	for host, count := range referrers {
+		history.Referrers = append(history.Referrers, inbound.AnalyticsCount{Key: host, Count: count})
+	}
+	sort.Slice(history.Referrers, func(i, j int) bool {
+		if history.Referrers[i].Count != history.Referrers[j].Count {
+			return history.Referrers[i].Count > history.Referrers[j].Count
+		}
+		return history.Referrers[i].Key < history.Referrers[j].Key
+	})
+	if len(history.Referrers) > historyTopN {
+		history.Referrers = history.Referrers[:historyTopN]
+	}
+	if scrollSamples > 0 {
+		avg := float64(scrollSum) / float64(scrollSamples)
+		history.AverageScrollDepth = &avg
+	}
+	if engagedSamples > 0 {
+		avg := float64(engagedSum) / float64(engagedSamples) / 1000
+		history.AverageEngagedSeconds = &avg
+	}
+
+	return history, nil
+}
+
+// AuditHistory returns the archived profiling audit rows started in the
+// range, oldest first
+func (s *Service) AuditHistory(ctx context.Context, requesterID uuid.UUID, from, to time.Time) ([]inbound.ArchivedCapture, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "read the audit archive"); err != nil {
+		return nil, err
+	}
+	from, to, err := s.historyRange(from, to, maxAuditDays)
+	if err != nil {
+		return nil, err
+	}
+
+	captures := []inbound.ArchivedCapture{}
+	err = s.readPartitions(ctx, outbound.ArchiveProfileCaptures, from, to, func(line []byte) error {
+		var record captureRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return err
+		}
+		captures = append(captures, toArchivedCapture(record))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return captures, nil
+}
+
+// historyRange defaults and checks a read range, aligned to UTC days. An
+// open range ends today and spans the longest allowed read.
+func (s *Service) historyRange(from, to time.Time, maxDays int) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = s.now()
+	}
+	to = to.UTC().Truncate(day).Add(day)
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -maxDays)
+	}
+	from = from.UTC().Truncate(day)
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.NewBadRequestError("from must be before to")
+	}
+	if to.Sub(from) > time.Duration(maxDays)*day {
+		return time.Time{}, time.Time{}, errors.NewBadRequestError(fmt.Sprintf("range must be at most %d days", maxDays))
+	}
+	return from, to, nil
+}
+
+// readPartitions streams every line of the dataset's files for days in
+// [from, to)
+func (s *Service) readPartitions(ctx context.Context, dataset string, from, to time.Time, visit func(line []byte) error) error {
+	partitions, err := s.archives.FindPartitions(ctx, dataset, from, to)
+	if err != nil {
+		return errors.NewDatabaseError("find archive partitions", err)
+	}
+
+	for _, partition := range partitions {
+		if err := s.readPartition(ctx, partition, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) readPartition(ctx context.Context, partition *outbound.ArchivePartition, visit func(line []byte) error) error {
+	body, err := s.blobs.Get(ctx, partition.BlobKey)
+	if err != nil {
+		return errors.NewExternalServiceError("blob storage", err)
+	}
+	defer body.Close()
+
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return errors.NewInternalError("archive partition is not gzip: " + partition.BlobKey)
+	}
+	defer gz.Close()
+
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return errors.NewExternalServiceError("blob storage", err)
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := visit(line); err != nil {
+			s.logger.Error("Unreadable archive record",
+				zap.String("blob_key", partition.BlobKey),
+				zap.Error(err),
+			)
+			return errors.NewInternalError("archive partition is corrupt: " + partition.BlobKey)
+		}
+	}
+	return nil
+}
+
+// blobKey groups files by dataset and day, e.g.
+// archive/recipe_views/2024/05/01/<id>.jsonl.gz. The partition ID keeps a
+// day archived twice from overwriting the first file.
+func (s *Service) blobKey(partition *outbound.ArchivePartition) string {
+	prefix := strings.TrimSuffix(s.cfg.BlobPrefix, "/")
+	if prefix == "" {
+		prefix = "archive"
+	}
+	return fmt.Sprintf("%s/%s/%s/%s.jsonl.gz", prefix, partition.Dataset, partition.Day.Format("2006/01/02"), partition.ID)
+}
+
+// encodeRecords writes the records as gzipped JSON lines
+func encodeRecords(records []interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func toViewRecord(rollup outbound.RecipeViewRollup) viewRecord {
+	return viewRecord{
+		RecipeID:       rollup.RecipeID,
+		Day:            rollup.Day.Format("2006-01-02"),
+		Views:          rollup.Views,
+		UniqueViewers:  rollup.UniqueViewers,
+		ScrollDepthSum: rollup.ScrollDepthSum,
+		ScrollSamples:  rollup.ScrollSamples,
+		EngagedMSSum:   rollup.EngagedMSSum,
+		EngagedSamples: rollup.EngagedSamples,
+		ReferrerHosts:  rollup.ReferrerHosts,
+	}
+}
+
+func toCaptureRecord(capture *outbound.ProfileCapture) captureRecord {
+	return captureRecord{
+		ID:          capture.ID,
+		Kind:        capture.Kind,
+		Seconds:     capture.Seconds,
+		Status:      capture.Status,
+		RequestedBy: capture.RequestedBy,
+		IPAddress:   capture.IPAddress,
+		Reason:      capture.Reason,
+		BlobKey:     capture.BlobKey,
+		SizeBytes:   capture.SizeBytes,
+		Error:       capture.Error,
+		StartedAt:   capture.StartedAt,
+		FinishedAt:  capture.FinishedAt,
+	}
+}
+
+func toArchivedCapture(record captureRecord) inbound.ArchivedCapture {
+	capture := inbound.ArchivedCapture{
+		ID:          record.ID,
+		Kind:        record.Kind,
+		Seconds:     record.Seconds,
+		Status:      record.Status,
+		RequestedBy: record.RequestedBy,
+		IPAddress:   record.IPAddress,
+		Reason:      record.Reason,
+		BlobKey:     record.BlobKey,
+		SizeBytes:   record.SizeBytes,
+		Error:       record.Error,
+		StartedAt:   record.StartedAt.Format(time.RFC3339),
+	}
+	if record.FinishedAt != nil {
+		capture.FinishedAt = record.FinishedAt.Format(time.RFC3339)
+	}
+	return capture
+}
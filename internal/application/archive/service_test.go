@@ -0,0 +1,269 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+type stubRecipes struct {
+	outbound.RecipeRepository
+	recipe *recipe.Recipe
+}
+
+func (s *stubRecipes) FindByID(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error) {
+	if s.recipe != nil && s.recipe.ID() == id {
+		return s.recipe, nil
+	}
+	return nil, nil
+}
+
+type memoryBlobs struct {
+	blobs map[string][]byte
+}
+
+func (m *memoryBlobs) Put(ctx context.Context, key, contentType string, content io.Reader) (int64, error) {
+	data, err := io.ReadAll(content)
+	m.blobs[key] = data
+	return int64(len(data)), err
+}
+
+func (m *memoryBlobs) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(m.blobs[key])), nil
+}
+
+// memoryArchive keeps hot rows per dataset keyed by the time they were
+// recorded
+type memoryArchive struct {
+	views      map[time.Time]outbound.RecipeViewRollup
+	captures   []*outbound.ProfileCapture
+	partitions []*outbound.ArchivePartition
+}
+
+func (m *memoryArchive) OldestBefore(ctx context.Context, dataset string, before time.Time) (*time.Time, error) {
+	var oldest *time.Time
+	consider := func(at time.Time) {
+		if at.Before(before) && (oldest == nil || at.Before(*oldest)) {
+			at := at
+			oldest = &at
+		}
+	}
+	if dataset == outbound.ArchiveRecipeViews {
+		for at := range m.views {
+			consider(at)
+		}
+	} else {
+		for _, capture := range m.captures {
+			if capture.Status != outbound.ProfileCaptureRunning {
+				consider(capture.StartedAt)
+			}
+		}
+	}
+	return oldest, nil
+}
+
+func (m *memoryArchive) RollupRecipeViews(ctx context.Context, from, to time.Time) ([]outbound.RecipeViewRollup, int, error) {
+	var rollups []outbound.RecipeViewRollup
+	read := 0
+	for at, rollup := range m.views {
+		if !at.Before(from) && at.Before(to) {
+			rollups = append(rollups, rollup)
+			read += rollup.Views
+		}
+	}
+	return rollups, read, nil
+}
+
+func (m *memoryArchive) ProfileCapturesBetween(ctx context.Context, from, to time.Time) ([]*outbound.ProfileCapture, error) {
+	var captures []*outbound.ProfileCapture
+	for _, capture := range m.captures {
+		if capture.Status != outbound.ProfileCaptureRunning && !capture.StartedAt.Before(from) && capture.StartedAt.Before(to) {
+			captures = append(captures, capture)
+		}
+	}
+	return captures, nil
+}
+
+func (m *memoryArchive) DeleteBetween(ctx context.Context, dataset string, from, to time.Time) (int64, error) {
+	var deleted int64
+	if dataset == outbound.ArchiveRecipeViews {
+		for at, rollup := range m.views {
+			if !at.Before(from) && at.Before(to) {
+				delete(m.views, at)
+				deleted += int64(rollup.Views)
+			}
+		}
+		return deleted, nil
+	}
+	kept := m.captures[:0]
+	for _, capture := range m.captures {
+		if capture.Status != outbound.ProfileCaptureRunning && !capture.StartedAt.Before(from) && capture.StartedAt.Before(to) {
+			deleted++
+			continue
+		}
+		kept = append(kept, capture)
+	}
+	m.captures = kept
+	return deleted, nil
+}
+
+func (m *memoryArchive) SavePartition(ctx context.Context, partition *outbound.ArchivePartition) error {
+	m.partitions = append(m.partitions, partition)
+	return nil
+}
+
+func (m *memoryArchive) FindPartitions(ctx context.Context, dataset string, from, to time.Time) ([]*outbound.ArchivePartition, error) {
+	var found []*outbound.ArchivePartition
+	for _, partition := range m.partitions {
+		if partition.Dataset == dataset && !partition.Day.Before(from) && partition.Day.Before(to) {
+			found = append(found, partition)
+		}
+	}
+	return found, nil
+}
+
+type fixture struct {
+	svc      *Service
+	archive  *memoryArchive
+	blobs    *memoryBlobs
+	recipe   *recipe.Recipe
+	author   *user.User
+	admin    *user.User
+	stranger *user.User
+}
+
+func newFixture(t *testing.T, now time.Time) *fixture {
+	f := &fixture{
+		archive: &memoryArchive{views: make(map[time.Time]outbound.RecipeViewRollup)},
+		blobs:   &memoryBlobs{blobs: make(map[string][]byte)},
+	}
+	f.author = user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	f.admin = user.ReconstructUser(uuid.New(), "root@example.com", "Root", "", true, true, user.UserRoleAdmin, now, now, nil)
+	f.stranger = user.ReconstructUser(uuid.New(), "sam@example.com", "Sam", "", true, true, user.UserRoleUser, now, now, nil)
+
+	var err error
+	f.recipe, err = recipe.NewRecipe("Shakshuka", "Eggs in spiced tomato", f.author.ID())
+	require.NoError(t, err)
+
+	users := &stubUsers{users: map[uuid.UUID]*user.User{
+		f.author.ID(): f.author, f.admin.ID(): f.admin, f.stranger.ID(): f.stranger,
+	}}
+	f.svc = NewService(f.archive, f.blobs, &stubRecipes{recipe: f.recipe}, users, Config{
+		RecipeViewDays: 90,
+		AuditDays:      365,
+		BlobPrefix:     "archive/",
+	}, zap.NewNop())
+	f.svc.now = func() time.Time { return now }
+	return f
+}
+
+func TestTieringMovesOldViewsAndReadsThemBack(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	old := now.AddDate(0, 0, -120).Truncate(24 * time.Hour)
+	other := uuid.New()
+
+	f.archive.views[old.Add(time.Hour)] = outbound.RecipeViewRollup{
+		RecipeID: f.recipe.ID(), Day: old, Views: 5, UniqueViewers: 3,
+		ScrollDepthSum: 150, ScrollSamples: 2, EngagedMSSum: 60000, EngagedSamples: 2,
+		ReferrerHosts: map[string]int{"news.example.com": 4},
+	}
+	f.archive.views[old.Add(2*time.Hour)] = outbound.RecipeViewRollup{RecipeID: other, Day: old, Views: 7, UniqueViewers: 7}
+	recent := now.AddDate(0, 0, -10)
+	f.archive.views[recent] = outbound.RecipeViewRollup{RecipeID: f.recipe.ID(), Day: recent.Truncate(24 * time.Hour), Views: 1}
+	f.archive.captures = []*outbound.ProfileCapture{
+		{ID: uuid.New(), Kind: "heap", Status: outbound.ProfileCaptureCompleted, RequestedBy: f.admin.ID(), IPAddress: "203.0.113.9", Reason: "leak hunt", StartedAt: now.AddDate(-2, 0, 0)},
+		{ID: uuid.New(), Kind: "cpu", Status: outbound.ProfileCaptureRunning, StartedAt: now.AddDate(-2, 0, 0)},
+	}
+
+	report, err := f.svc.RunTiering(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Partitions, 2)
+	assert.Equal(t, outbound.ArchiveRecipeViews, report.Partitions[0].Dataset)
+	assert.Equal(t, 2, report.Partitions[0].Records, "one rollup per recipe")
+	assert.Equal(t, 12, report.Partitions[0].SourceRows)
+	assert.Equal(t, outbound.ArchiveProfileCaptures, report.Partitions[1].Dataset)
+
+	assert.Len(t, f.archive.views, 1, "only the recent views stay hot")
+	require.Len(t, f.archive.captures, 1, "running captures are never archived")
+	assert.Equal(t, outbound.ProfileCaptureRunning, f.archive.captures[0].Status)
+	for _, partition := range f.archive.partitions {
+		assert.Contains(t, f.blobs.blobs, partition.BlobKey)
+		assert.Regexp(t, `^archive/`+partition.Dataset+`/\d{4}/\d{2}/\d{2}/.+\.jsonl\.gz$`, partition.BlobKey)
+	}
+
+	history, err := f.svc.RecipeViewHistory(context.Background(), inbound.RecipeViewHistoryQuery{
+		RequesterID: f.author.ID(),
+		RecipeID:    f.recipe.ID(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, history.Views)
+	assert.Equal(t, 3, history.UniqueViewers)
+	require.Len(t, history.Daily, 1)
+	assert.Equal(t, old.Format("2006-01-02"), history.Daily[0].Date)
+	require.NotNil(t, history.AverageScrollDepth)
+	assert.Equal(t, 75.0, *history.AverageScrollDepth)
+	require.NotNil(t, history.AverageEngagedSeconds)
+	assert.Equal(t, 30.0, *history.AverageEngagedSeconds)
+	assert.Equal(t, []inbound.AnalyticsCount{{Key: "news.example.com", Count: 4}}, history.Referrers)
+
+	_, err = f.svc.RecipeViewHistory(context.Background(), inbound.RecipeViewHistoryQuery{
+		RequesterID: f.stranger.ID(),
+		RecipeID:    f.recipe.ID(),
+	})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+
+	audit, err := f.svc.AuditHistory(context.Background(), f.admin.ID(), now.AddDate(-2, 0, -1), now.AddDate(-2, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, audit, 1)
+	assert.Equal(t, "203.0.113.9", audit[0].IPAddress)
+	assert.Equal(t, "leak hunt", audit[0].Reason)
+
+	again, err := f.svc.RunTiering(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, again.Partitions)
+}
+
+func TestArchiveReadsAreBounded(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	ctx := context.Background()
+
+	_, err := f.svc.AuditHistory(ctx, f.author.ID(), time.Time{}, time.Time{})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+
+	_, err = f.svc.AuditHistory(ctx, f.admin.ID(), now.AddDate(0, -3, 0), now)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest), "audit reads span at most a month")
+
+	_, err = f.svc.RecipeViewHistory(ctx, inbound.RecipeViewHistoryQuery{
+		RequesterID: f.author.ID(),
+		RecipeID:    f.recipe.ID(),
+		From:        now,
+		To:          now.AddDate(0, 0, -1),
+	})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	_, err = f.svc.TriggerTiering(ctx, f.author.ID())
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+}
@@ -0,0 +1,327 @@
+// Package battle runs remix battles between the AI and community chefs.
+// Readers vote without knowing which entry the AI wrote, and the results
+// decide which prompt template the AI writes its next entries with.
+package battle
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/battle"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// Service implements inbound.BattleService
+type Service struct {
+	battles outbound.BattleRepository
+	ai      outbound.AIService
+	logger  *zap.Logger
+	now     func() time.Time
+	// random returns a number in [0, 1) for template choice and entry order
+	random func() float64
+}
+
+// NewService creates a remix battle service
+func NewService(repo outbound.BattleRepository, ai outbound.AIService, logger *zap.Logger) *Service {
+	return &Service{
+		battles: repo,
+		ai:      ai,
+		logger:  logger.Named("battles"),
+		now:     time.Now,
+		random:  rand.Float64,
+	}
+}
+
+// Start has the AI write its entry with the template the results so far
+// favour
+func (s *Service) Start(ctx context.Context, cmd inbound.StartBattleCommand) (*inbound.BattleView, error) {
+	prompt, err := battle.NormalizePrompt(cmd.Prompt)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	now := s.now().UTC()
+	results, err := s.battles.TemplateResults(ctx, now)
+	if err != nil {
+		return nil, errors.NewDatabaseError("tally battle templates", err)
+	}
+	template := battle.ChooseTemplate(results, s.random)
+
+	generated, err := s.ai.GenerateRecipe(ctx, template.Prompt(prompt), outbound.AIConstraints{})
+	if err != nil {
+		return nil, errors.NewExternalServiceError("AI recipe generation", err)
+	}
+	b, err := battle.New(cmd.ChefID, prompt, template.Name, aiEntry(generated), s.random() < 0.5, now)
+	if err != nil {
+		// The AI's recipe is missing a title, ingredients or steps
+		return nil, errors.NewExternalServiceError("AI recipe generation", err)
+	}
+	if err := s.battles.Create(ctx, b); err != nil {
+		return nil, errors.NewDatabaseError("create battle", err)
+	}
+
+	s.logger.Info("Battle started",
+		zap.String("battle_id", b.ID.String()),
+		zap.String("template", b.Template),
+	)
+	return s.view(b, nil, now), nil
+}
+
+// SubmitEntry records the chef's entry and opens voting
+func (s *Service) SubmitEntry(ctx context.Context, cmd inbound.SubmitBattleEntryCommand) (*inbound.BattleView, error) {
+	b, err := s.find(ctx, cmd.ChefID, cmd.BattleID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	entry := battle.Entry{
+		Title:       cmd.Title,
+		Description: cmd.Description,
+		Ingredients: cmd.Ingredients,
+		Steps:       cmd.Steps,
+	}
+	switch err := b.SubmitChef(cmd.ChefID, entry, now); err {
+	case nil:
+	case battle.ErrNotChef:
+		return nil, errors.NewInsufficientPermissionsError("submit the chef's entry")
+	case battle.ErrEntrySubmitted:
+		return nil, errors.NewConflictError(err.Error())
+	default:
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	if err := s.battles.SubmitChef(ctx, b); err != nil {
+		return nil, errors.NewDatabaseError("save battle entry", err)
+	}
+	return s.view(b, nil, now), nil
+}
+
+// Get reads a battle. Open battles are only visible to their chef.
+func (s *Service) Get(ctx context.Context, query inbound.BattleQuery) (*inbound.BattleView, error) {
+	b, err := s.find(ctx, query.RequesterID, query.BattleID)
+	if err != nil {
+		return nil, err
+	}
+	vote, err := s.vote(ctx, b, query.RequesterID)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(b, vote, s.now().UTC()), nil
+}
+
+// List returns battles newest first. Open battles are listed for their
+// chef only.
+func (s *Service) List(ctx context.Context, query inbound.ListBattlesQuery) ([]inbound.BattleView, error) {
+	filter := outbound.BattleFilter{
+		Status: battle.Status(query.Status),
+		Now:    s.now().UTC(),
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+	switch filter.Status {
+	case "":
+		filter.Status = battle.StatusVoting
+	case battle.StatusVoting, battle.StatusClosed:
+	case battle.StatusOpen:
+		if query.RequesterID == uuid.Nil {
+			return nil, errors.NewUnauthorizedError("sign in to list your open battles")
+		}
+		filter.ChefID = query.RequesterID
+	default:
+		return nil, errors.NewBadRequestError("status must be open, voting or closed")
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	battles, err := s.battles.List(ctx, filter)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list battles", err)
+	}
+	views := make([]inbound.BattleView, len(battles))
+	for i, b := range battles {
+		views[i] = *s.view(b, nil, filter.Now)
+	}
+	return views, nil
+}
+
+// Vote counts a reader's vote. Voting again changes nothing.
+func (s *Service) Vote(ctx context.Context, cmd inbound.BattleVoteCommand) (*inbound.BattleView, error) {
+	b, err := s.find(ctx, cmd.UserID, cmd.BattleID)
+	if err != nil {
+		return nil, err
+	}
+	side, err := b.Side(cmd.Entry)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	now := s.now().UTC()
+	switch err := b.CanVote(cmd.UserID, now); err {
+	case nil:
+	case battle.ErrChefVote:
+		return nil, errors.NewInsufficientPermissionsError("vote in your own battle")
+	default:
+		return nil, errors.NewConflictError(err.Error())
+	}
+
+	vote := outbound.BattleVote{BattleID: b.ID, UserID: cmd.UserID, Side: side, CreatedAt: now}
+	recorded, err := s.battles.RecordVote(ctx, vote)
+	if err != nil {
+		return nil, errors.NewDatabaseError("record battle vote", err)
+	}
+	if !recorded {
+		return nil, errors.NewConflictError("you already voted in this battle")
+	}
+	return s.view(b, &vote, now), nil
+}
+
+// Templates reports the AI's record with each prompt template
+func (s *Service) Templates(ctx context.Context) ([]inbound.BattleTemplateStats, error) {
+	results, err := s.battles.TemplateResults(ctx, s.now().UTC())
+	if err != nil {
+		return nil, errors.NewDatabaseError("tally battle templates", err)
+	}
+	byName := make(map[string]battle.TemplateResult, len(results))
+	for _, r := range results {
+		byName[r.Template] = r
+	}
+
+	stats := make([]inbound.BattleTemplateStats, len(battle.Templates))
+	for i, t := range battle.Templates {
+		r := byName[t.Name]
+		stats[i] = inbound.BattleTemplateStats{
+			Name:        t.Name,
+			Instruction: t.Instruction,
+			Wins:        r.Wins,
+			Losses:      r.Losses,
+			Ties:        r.Ties,
+			Score:       r.Score(),
+			Exploring:   r.Trials() < battle.MinTrials,
+		}
+	}
+	return stats, nil
+}
+
+// find reads a battle the requester may see
+func (s *Service) find(ctx context.Context, requesterID uuid.UUID, battleID string) (*battle.Battle, error) {
+	id, err := uuid.Parse(battleID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid battle ID")
+	}
+	b, err := s.battles.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find battle", err)
+	}
+	if b == nil || (b.Chef == nil && b.ChefID != requesterID) {
+		return nil, errors.NewNotFoundError("battle")
+	}
+	return b, nil
+}
+
+func (s *Service) vote(ctx context.Context, b *battle.Battle, userID uuid.UUID) (*outbound.BattleVote, error) {
+	if userID == uuid.Nil {
+		return nil, nil
+	}
+	vote, err := s.battles.FindVote(ctx, b.ID, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find battle vote", err)
+	}
+	return vote, nil
+}
+
+// view shows a battle without giving away which entry is the AI's until
+// voting ends. Open battles show no entries, so the chef writes theirs
+// without seeing the AI's.
+func (s *Service) view(b *battle.Battle, vote *outbound.BattleVote, now time.Time) *inbound.BattleView {
+	status := b.Status(now)
+	view := &inbound.BattleView{
+		ID:        b.ID.String(),
+		Prompt:    b.Prompt,
+		ChefID:    b.ChefID.String(),
+		Status:    string(status),
+		Entries:   []inbound.BattleEntry{},
+		CreatedAt: b.CreatedAt.Format(time.RFC3339),
+	}
+	if vote != nil {
+		view.YourVote = b.Label(vote.Side)
+	}
+	if status == battle.StatusOpen {
+		return view
+	}
+	view.VotingEndsAt = b.VotingEndsAt.Format(time.RFC3339)
+
+	ai, chef := entryView(b.AI, b.Label(battle.SideAI)), entryView(*b.Chef, b.Label(battle.SideChef))
+	if b.AIFirst {
+		view.Entries = append(view.Entries, ai, chef)
+	} else {
+		view.Entries = append(view.Entries, chef, ai)
+	}
+	if status != battle.StatusClosed {
+		return view
+	}
+
+	for i := range view.Entries {
+		entry := &view.Entries[i]
+		side, _ := b.Side(entry.Label)
+		votes := b.ChefVotes
+		if side == battle.SideAI {
+			votes = b.AIVotes
+		}
+		entry.Side = string(side)
+		entry.Votes = &votes
+	}
+	view.Winner = string(b.Winner())
+	if view.Winner == "" {
+		view.Winner = "tie"
+	}
+	view.Template = b.Template
+	return view
+}
+
+func entryView(e battle.Entry, label string) inbound.BattleEntry {
+	return inbound.BattleEntry{
+		Label:       label,
+		Title:       e.Title,
+		Description: e.Description,
+		Ingredients: e.Ingredients,
+		Steps:       e.Steps,
+	}
+}
+
+// aiEntry writes the AI's ingredients the way a chef would list them
+func aiEntry(r *outbound.AIRecipeResponse) battle.Entry {
+	entry := battle.Entry{
+		Title:       r.Title,
+		Description: r.Description,
+		Steps:       r.Instructions,
+	}
+	for _, ingredient := range r.Ingredients {
+		parts := make([]string, 0, 3)
+		if ingredient.Amount > 0 {
+			parts = append(parts, strconv.FormatFloat(ingredient.Amount, 'f', -1, 64))
+		}
+		if ingredient.Unit != "" {
+			parts = append(parts, ingredient.Unit)
+		}
+		entry.Ingredients = append(entry.Ingredients, strings.Join(append(parts, ingredient.Name), " "))
+	}
+	return entry
+}
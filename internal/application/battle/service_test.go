@@ -0,0 +1,125 @@
+package battle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/battle"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memoryBattles struct {
+	outbound.BattleRepository
+	battles map[uuid.UUID]*battle.Battle
+	votes   map[uuid.UUID]outbound.BattleVote
+}
+
+func (m *memoryBattles) Create(ctx context.Context, b *battle.Battle) error {
+	m.battles[b.ID] = b
+	return nil
+}
+
+func (m *memoryBattles) FindByID(ctx context.Context, id uuid.UUID) (*battle.Battle, error) {
+	return m.battles[id], nil
+}
+
+func (m *memoryBattles) SubmitChef(ctx context.Context, b *battle.Battle) error {
+	return nil
+}
+
+func (m *memoryBattles) RecordVote(ctx context.Context, vote outbound.BattleVote) (bool, error) {
+	if _, ok := m.votes[vote.UserID]; ok {
+		return false, nil
+	}
+	m.votes[vote.UserID] = vote
+	if vote.Side == battle.SideAI {
+		m.battles[vote.BattleID].AIVotes++
+	} else {
+		m.battles[vote.BattleID].ChefVotes++
+	}
+	return true, nil
+}
+
+func (m *memoryBattles) FindVote(ctx context.Context, battleID, userID uuid.UUID) (*outbound.BattleVote, error) {
+	if vote, ok := m.votes[userID]; ok {
+		return &vote, nil
+	}
+	return nil, nil
+}
+
+func (m *memoryBattles) TemplateResults(ctx context.Context, now time.Time) ([]battle.TemplateResult, error) {
+	return nil, nil
+}
+
+type stubAI struct {
+	outbound.AIService
+	prompt string
+}
+
+func (s *stubAI) GenerateRecipe(ctx context.Context, prompt string, constraints outbound.AIConstraints) (*outbound.AIRecipeResponse, error) {
+	s.prompt = prompt
+	return &outbound.AIRecipeResponse{
+		Title:        "Charred Corn Salad",
+		Ingredients:  []outbound.AIIngredient{{Name: "corn", Amount: 2, Unit: "cobs"}, {Name: "lime"}},
+		Instructions: []string{"Char the corn.", "Toss with lime."},
+	}, nil
+}
+
+func TestBattleStaysBlindUntilVotingEnds(t *testing.T) {
+	repo := &memoryBattles{battles: map[uuid.UUID]*battle.Battle{}, votes: map[uuid.UUID]outbound.BattleVote{}}
+	ai := &stubAI{}
+	svc := NewService(repo, ai, zap.NewNop())
+	now := time.Date(2026, 7, 1, 18, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	svc.random = func() float64 { return 0.8 } // seasonal template, chef's entry first
+	ctx := context.Background()
+	chefID, voterID := uuid.New(), uuid.New()
+
+	started, err := svc.Start(ctx, inbound.StartBattleCommand{ChefID: chefID, Prompt: "a summer side dish"})
+	require.NoError(t, err)
+	assert.Equal(t, "open", started.Status)
+	assert.Empty(t, started.Entries, "the chef writes without seeing the AI's entry")
+	assert.Contains(t, ai.prompt, "in season")
+
+	_, err = svc.Get(ctx, inbound.BattleQuery{RequesterID: voterID, BattleID: started.ID})
+	assert.True(t, errors.Is(err, errors.CodeNotFound), "open battles are the chef's alone")
+
+	voting, err := svc.SubmitEntry(ctx, inbound.SubmitBattleEntryCommand{
+		ChefID:      chefID,
+		BattleID:    started.ID,
+		Title:       "Elote Salad",
+		Ingredients: []string{"corn", "cotija"},
+		Steps:       []string{"Grill and dress."},
+	})
+	require.NoError(t, err)
+	require.Len(t, voting.Entries, 2)
+	assert.Equal(t, "Elote Salad", voting.Entries[0].Title)
+	assert.Empty(t, voting.Entries[0].Side)
+	assert.Nil(t, voting.Entries[0].Votes)
+	assert.Equal(t, []string{"2 cobs corn", "lime"}, voting.Entries[1].Ingredients)
+
+	_, err = svc.Vote(ctx, inbound.BattleVoteCommand{UserID: chefID, BattleID: started.ID, Entry: "a"})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	voted, err := svc.Vote(ctx, inbound.BattleVoteCommand{UserID: voterID, BattleID: started.ID, Entry: "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "b", voted.YourVote)
+	assert.Empty(t, voted.Winner)
+	_, err = svc.Vote(ctx, inbound.BattleVoteCommand{UserID: voterID, BattleID: started.ID, Entry: "a"})
+	assert.True(t, errors.Is(err, errors.CodeConflict))
+
+	now = now.Add(battle.VotingPeriod)
+	closed, err := svc.Get(ctx, inbound.BattleQuery{BattleID: started.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "closed", closed.Status)
+	assert.Equal(t, "ai", closed.Winner)
+	assert.Equal(t, "seasonal", closed.Template)
+	assert.Equal(t, "ai", closed.Entries[1].Side)
+	assert.Equal(t, 1, *closed.Entries[1].Votes)
+}
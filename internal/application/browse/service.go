@@ -0,0 +1,171 @@
+// Package browse serves the tag and cuisine browse pages from summaries
+// that are rebuilt on a schedule, so page views never aggregate recipes.
+package browse
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultCountLimit and maxCountLimit bound the tags or cuisines listed
+	defaultCountLimit = 50
+	maxCountLimit     = 200
+	// defaultTopRatedLimit is the top rated recipes listed per cuisine
+	defaultTopRatedLimit = 10
+	// DefaultTopPerCuisine is the recipes kept per cuisine on refresh
+	DefaultTopPerCuisine = 20
+)
+
+// Service implements inbound.BrowseService
+type Service struct {
+	summaries     outbound.BrowseSummaryRepository
+	topPerCuisine int
+	logger        *zap.Logger
+
+	mu         sync.Mutex
+	refreshing bool
+}
+
+// NewService creates a browse service keeping topPerCuisine recipes on each
+// cuisine's top rated list
+func NewService(summaries outbound.BrowseSummaryRepository, topPerCuisine int, logger *zap.Logger) *Service {
+	if topPerCuisine <= 0 {
+		topPerCuisine = DefaultTopPerCuisine
+	}
+	return &Service{
+		summaries:     summaries,
+		topPerCuisine: topPerCuisine,
+		logger:        logger.Named("browse"),
+	}
+}
+
+// ListTags returns the most used tags
+func (s *Service) ListTags(ctx context.Context, limit int) (*inbound.BrowseCounts, error) {
+	counts, err := s.summaries.TagCounts(ctx, countLimit(limit))
+	if err != nil {
+		return nil, errors.NewDatabaseError("list tags", err)
+	}
+	return s.browseCounts(ctx, counts)
+}
+
+// ListCuisines returns the most used cuisines
+func (s *Service) ListCuisines(ctx context.Context, limit int) (*inbound.BrowseCounts, error) {
+	counts, err := s.summaries.CuisineCounts(ctx, countLimit(limit))
+	if err != nil {
+		return nil, errors.NewDatabaseError("list cuisines", err)
+	}
+	return s.browseCounts(ctx, counts)
+}
+
+// TopRatedByCuisine returns a cuisine's best rated recipes, at most as many
+// as a refresh keeps
+func (s *Service) TopRatedByCuisine(ctx context.Context, cuisine string, limit int) (*inbound.BrowseTopRated, error) {
+	cuisine = strings.ToLower(strings.TrimSpace(cuisine))
+	if cuisine == "" {
+		return nil, errors.NewBadRequestError("cuisine is required")
+	}
+	if limit <= 0 {
+		limit = defaultTopRatedLimit
+	}
+	if limit > s.topPerCuisine {
+		limit = s.topPerCuisine
+	}
+
+	recipes, err := s.summaries.TopRatedByCuisine(ctx, cuisine, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list top rated recipes", err)
+	}
+	refreshedAt, err := s.refreshedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	top := &inbound.BrowseTopRated{
+		Cuisine:     cuisine,
+		Recipes:     make([]inbound.BrowseRecipe, len(recipes)),
+		RefreshedAt: refreshedAt,
+	}
+	for i, recipe := range recipes {
+		top.Recipes[i] = inbound.BrowseRecipe{
+			ID:            recipe.RecipeID.String(),
+			Title:         recipe.Title,
+			AverageRating: recipe.AverageRating,
+			Likes:         recipe.Likes,
+			Rank:          recipe.Rank,
+		}
+	}
+	return top, nil
+}
+
+// Refresh rebuilds the summaries. Overlapping refreshes would only redo the
+// same work, so a refresh started while another runs returns at once.
+func (s *Service) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	if s.refreshing {
+		s.mu.Unlock()
+		return nil
+	}
+	s.refreshing = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.refreshing = false
+		s.mu.Unlock()
+	}()
+
+	started := time.Now()
+	summary, err := s.summaries.Refresh(ctx, s.topPerCuisine)
+	if err != nil {
+		return errors.NewDatabaseError("refresh browse summaries", err)
+	}
+
+	s.logger.Info("Browse summaries refreshed",
+		zap.Int("tags", summary.Tags),
+		zap.Int("cuisines", summary.Cuisines),
+		zap.Int("top_rated", summary.TopRated),
+		zap.Duration("duration", time.Since(started)),
+	)
+	return nil
+}
+
+func (s *Service) browseCounts(ctx context.Context, counts []outbound.FacetCount) (*inbound.BrowseCounts, error) {
+	refreshedAt, err := s.refreshedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]inbound.FacetCount, len(counts))
+	for i, count := range counts {
+		items[i] = inbound.FacetCount{Value: count.Value, Count: count.Count}
+	}
+	return &inbound.BrowseCounts{Items: items, RefreshedAt: refreshedAt}, nil
+}
+
+func (s *Service) refreshedAt(ctx context.Context) (string, error) {
+	at, err := s.summaries.RefreshedAt(ctx)
+	if err != nil {
+		return "", errors.NewDatabaseError("read browse refresh time", err)
+	}
+	if at == nil {
+		return "", nil
+	}
+	return at.UTC().Format(time.RFC3339), nil
+}
+
+func countLimit(limit int) int {
+	if limit <= 0 {
+		return defaultCountLimit
+	}
+	if limit > maxCountLimit {
+		return maxCountLimit
+	}
+	return limit
+}
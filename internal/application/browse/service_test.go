@@ -0,0 +1,102 @@
+package browse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubSummaries struct {
+	refreshedAt   *time.Time
+	topPerCuisine int
+	refreshes     int
+	block         chan struct{}
+	cuisine       string
+	limit         int
+}
+
+func (s *stubSummaries) Refresh(ctx context.Context, topPerCuisine int) (*outbound.BrowseSummary, error) {
+	s.refreshes++
+	s.topPerCuisine = topPerCuisine
+	if s.block != nil {
+		<-s.block
+	}
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.refreshedAt = &now
+	return &outbound.BrowseSummary{Tags: 3, Cuisines: 2, TopRated: 4, RefreshedAt: now}, nil
+}
+
+func (s *stubSummaries) TagCounts(ctx context.Context, limit int) ([]outbound.FacetCount, error) {
+	s.limit = limit
+	return []outbound.FacetCount{{Value: "vegan", Count: 12}, {Value: "quick", Count: 5}}, nil
+}
+
+func (s *stubSummaries) CuisineCounts(ctx context.Context, limit int) ([]outbound.FacetCount, error) {
+	s.limit = limit
+	return []outbound.FacetCount{{Value: "italian", Count: 9}}, nil
+}
+
+func (s *stubSummaries) TopRatedByCuisine(ctx context.Context, cuisine string, limit int) ([]outbound.BrowseRecipe, error) {
+	s.cuisine, s.limit = cuisine, limit
+	return []outbound.BrowseRecipe{{RecipeID: uuid.New(), Title: "Cacio e pepe", Cuisine: cuisine, AverageRating: 4.8, Likes: 30, Rank: 1}}, nil
+}
+
+func (s *stubSummaries) RefreshedAt(ctx context.Context) (*time.Time, error) {
+	return s.refreshedAt, nil
+}
+
+func TestBrowseReadsSummaries(t *testing.T) {
+	summaries := &stubSummaries{}
+	svc := NewService(summaries, 5, zap.NewNop())
+	ctx := context.Background()
+
+	tags, err := svc.ListTags(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, defaultCountLimit, summaries.limit)
+	assert.Equal(t, "vegan", tags.Items[0].Value)
+	assert.Empty(t, tags.RefreshedAt, "nothing has been refreshed yet")
+
+	require.NoError(t, svc.Refresh(ctx))
+	assert.Equal(t, 5, summaries.topPerCuisine)
+
+	cuisines, err := svc.ListCuisines(ctx, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, maxCountLimit, summaries.limit)
+	assert.Equal(t, "2026-05-01T12:00:00Z", cuisines.RefreshedAt)
+
+	top, err := svc.TopRatedByCuisine(ctx, " Italian ", 50)
+	require.NoError(t, err)
+	assert.Equal(t, "italian", summaries.cuisine)
+	assert.Equal(t, 5, summaries.limit, "no more recipes than a refresh keeps")
+	require.Len(t, top.Recipes, 1)
+	assert.Equal(t, 1, top.Recipes[0].Rank)
+
+	_, err = svc.TopRatedByCuisine(ctx, " ", 10)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
+
+func TestRefreshSkipsWhileRunning(t *testing.T) {
+	summaries := &stubSummaries{block: make(chan struct{})}
+	svc := NewService(summaries, 0, zap.NewNop())
+
+	done := make(chan error)
+	go func() { done <- svc.Refresh(context.Background()) }()
+	require.Eventually(t, func() bool {
+		svc.mu.Lock()
+		defer svc.mu.Unlock()
+		return svc.refreshing
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, svc.Refresh(context.Background()))
+	close(summaries.block)
+	require.NoError(t, <-done)
+	assert.Equal(t, 1, summaries.refreshes)
+	assert.Equal(t, DefaultTopPerCuisine, summaries.topPerCuisine)
+}
@@ -0,0 +1,157 @@
+// Package clipper saves recipes clipped from web pages with the browser
+// extension as drafts, within a per-user clip limit
+package clipper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Source names clipped recipes in library import results and logs
+const Source = "clipper"
+
+// Item statuses reported by inbound.RecipeService.ImportRecipeLibrary
+const (
+	statusCreated   = "created"
+	statusDuplicate = "duplicate"
+)
+
+// Config limits clips and locates the review page
+type Config struct {
+	MaxClips int // clips a user may send per Window
+	Window   time.Duration
+	SiteURL  string // Base of review links
+}
+
+// Service implements inbound.ClipperService
+type Service struct {
+	recipes inbound.RecipeService
+	clips   outbound.RecipeClipRepository
+	cfg     Config
+	now     func() time.Time
+	logger  *zap.Logger
+}
+
+// NewService creates the clipper service
+func NewService(
+	recipes inbound.RecipeService,
+	clips outbound.RecipeClipRepository,
+	cfg Config,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		recipes: recipes,
+		clips:   clips,
+		cfg:     cfg,
+		now:     time.Now,
+		logger:  logger.Named("clipper"),
+	}
+}
+
+// Clip saves the recipe as a draft through the library importer, so a clip
+// is parsed, checked for duplicates and stored exactly as an imported
+// recipe is. Duplicates count against the limit like new drafts.
+func (s *Service) Clip(ctx context.Context, cmd inbound.ClipCommand) (*inbound.ClipResult, error) {
+	pageURL, err := url.Parse(strings.TrimSpace(cmd.PageURL))
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		return nil, errors.NewBadRequestError("url must be an http or https page address")
+	}
+
+	now := s.now()
+	used, err := s.clips.CountSince(ctx, cmd.UserID, now.Add(-s.cfg.Window))
+	if err != nil {
+		return nil, errors.NewDatabaseError("count clips", err)
+	}
+	if used >= int64(s.cfg.MaxClips) {
+		s.logger.Warn("Clip limit reached", zap.String("user_id", cmd.UserID.String()))
+		return nil, errors.NewAppError(
+			errors.CodeTooManyRequests,
+			fmt.Sprintf("You can clip %d recipes %s; try again later", s.cfg.MaxClips, per(s.cfg.Window)),
+			"",
+		).WithMetadata("limit", s.cfg.MaxClips)
+	}
+
+	imported := cmd.Recipe
+	if imported.SourceURL == "" {
+		imported.SourceURL = pageURL.String()
+	}
+	result, err := s.recipes.ImportRecipeLibrary(ctx, inbound.ImportLibraryCommand{
+		UserID:  cmd.UserID,
+		Source:  Source,
+		Recipes: []inbound.ImportedRecipe{imported},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Items) == 0 {
+		return nil, errors.NewInternalError("the clip was not imported")
+	}
+
+	item := result.Items[0]
+	clip := &inbound.ClipResult{
+		Title:    item.Title,
+		Status:   item.Status,
+		Method:   cmd.Method,
+		Warnings: item.Warnings,
+	}
+	switch {
+	case item.Status == statusCreated && item.RecipeID != nil:
+		clip.RecipeID = *item.RecipeID
+	case item.Status == statusDuplicate && item.DuplicateOf != nil:
+		clip.RecipeID = *item.DuplicateOf
+	default:
+		return nil, errors.NewBadRequestError("The clipped recipe could not be saved: " + item.Error)
+	}
+	clip.ReviewURL = strings.TrimRight(s.cfg.SiteURL, "/") + "/recipes/" + clip.RecipeID.String() + "/edit"
+
+	err = s.clips.Create(ctx, outbound.RecipeClip{
+		ID:        uuid.New(),
+		UserID:    cmd.UserID,
+		RecipeID:  clip.RecipeID,
+		PageURL:   pageURL.String(),
+		Method:    cmd.Method,
+		CreatedAt: now,
+	})
+	if err != nil {
+		// The draft exists; losing the record only loosens the limit
+		s.logger.Error("Failed to record clip", zap.String("recipe_id", clip.RecipeID.String()), zap.Error(err))
+	} else {
+		used++
+	}
+	clip.Remaining = s.cfg.MaxClips - int(used)
+	if clip.Remaining < 0 {
+		clip.Remaining = 0
+	}
+
+	s.logger.Info("Recipe clipped",
+		zap.String("user_id", cmd.UserID.String()),
+		zap.String("recipe_id", clip.RecipeID.String()),
+		zap.String("status", clip.Status),
+		zap.String("method", cmd.Method),
+	)
+	return clip, nil
+}
+
+// per describes a window for messages: "per hour", "every 30 minutes"
+func per(window time.Duration) string {
+	switch {
+	case window == time.Hour:
+		return "per hour"
+	case window == 24*time.Hour:
+		return "per day"
+	case window%time.Hour == 0:
+		return fmt.Sprintf("every %d hours", window/time.Hour)
+	case window%time.Minute == 0:
+		return fmt.Sprintf("every %d minutes", window/time.Minute)
+	}
+	return "every " + window.String()
+}
@@ -0,0 +1,85 @@
+package clipper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// importer creates each new title once and reports repeats as duplicates
+type importer struct {
+	inbound.RecipeService
+	titles map[string]uuid.UUID
+	got    []inbound.ImportLibraryCommand
+}
+
+func (i *importer) ImportRecipeLibrary(_ context.Context, cmd inbound.ImportLibraryCommand) (*inbound.LibraryImportResult, error) {
+	i.got = append(i.got, cmd)
+	title := cmd.Recipes[0].Title
+	if id, ok := i.titles[title]; ok {
+		return &inbound.LibraryImportResult{Items: []inbound.LibraryImportItem{{Title: title, Status: statusDuplicate, DuplicateOf: &id}}}, nil
+	}
+	id := uuid.New()
+	i.titles[title] = id
+	return &inbound.LibraryImportResult{Items: []inbound.LibraryImportItem{{Title: title, Status: statusCreated, RecipeID: &id}}}, nil
+}
+
+type clipStore struct{ clips []outbound.RecipeClip }
+
+func (c *clipStore) Create(_ context.Context, clip outbound.RecipeClip) error {
+	c.clips = append(c.clips, clip)
+	return nil
+}
+
+func (c *clipStore) CountSince(_ context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var n int64
+	for _, clip := range c.clips {
+		if clip.UserID == userID && clip.CreatedAt.After(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestClipCreatesDraftsWithinLimit(t *testing.T) {
+	recipes := &importer{titles: map[string]uuid.UUID{}}
+	store := &clipStore{}
+	svc := NewService(recipes, store, Config{MaxClips: 2, Window: time.Hour, SiteURL: "https://alchemorsel.test/"}, zap.NewNop())
+	userID := uuid.New()
+	clip := func(title string) (*inbound.ClipResult, error) {
+		return svc.Clip(context.Background(), inbound.ClipCommand{
+			UserID:  userID,
+			PageURL: "https://example.com/" + title,
+			Method:  "heuristic",
+			Recipe:  inbound.ImportedRecipe{Title: title, Ingredients: []string{"1 egg"}},
+		})
+	}
+
+	first, err := clip("omelette")
+	require.NoError(t, err)
+	assert.Equal(t, statusCreated, first.Status)
+	assert.Equal(t, "https://alchemorsel.test/recipes/"+first.RecipeID.String()+"/edit", first.ReviewURL)
+	assert.Equal(t, 1, first.Remaining)
+	assert.Equal(t, "https://example.com/omelette", recipes.got[0].Recipes[0].SourceURL)
+
+	again, err := clip("omelette")
+	require.NoError(t, err)
+	assert.Equal(t, statusDuplicate, again.Status)
+	assert.Equal(t, first.RecipeID, again.RecipeID)
+	assert.Equal(t, 0, again.Remaining)
+
+	_, err = clip("frittata")
+	assert.True(t, errors.Is(err, errors.CodeTooManyRequests))
+	assert.Len(t, recipes.got, 2)
+
+	_, err = svc.Clip(context.Background(), inbound.ClipCommand{UserID: uuid.New(), PageURL: "javascript:alert(1)"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
@@ -0,0 +1,282 @@
+package comment
+
+import (
+	"context"
+	"sort"
+
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// React adds a reaction to someone else's comment. Giving the same
+// reaction twice is not an error; reacting to a hidden comment is.
+func (s *Service) React(ctx context.Context, cmd inbound.ReactCommand) (*inbound.CommentReactions, error) {
+	c, err := s.findComment(ctx, cmd.RecipeID, cmd.CommentID, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Visible() {
+		return nil, errors.NewNotFoundError("comment")
+	}
+
+	reaction, err := comment.NewReaction(c, cmd.UserID, comment.ReactionKind(cmd.Kind), s.now())
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	if err := s.feedback.AddReaction(ctx, reaction); err != nil {
+		return nil, errors.NewDatabaseError("add reaction", err)
+	}
+	return s.commentReactions(ctx, c.ID(), cmd.UserID)
+}
+
+// Unreact takes a reaction back
+func (s *Service) Unreact(ctx context.Context, cmd inbound.ReactCommand) (*inbound.CommentReactions, error) {
+	c, err := s.findComment(ctx, cmd.RecipeID, cmd.CommentID, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.feedback.RemoveReaction(ctx, c.ID(), cmd.UserID, comment.ReactionKind(cmd.Kind)); err != nil {
+		return nil, errors.NewDatabaseError("remove reaction", err)
+	}
+	return s.commentReactions(ctx, c.ID(), cmd.UserID)
+}
+
+// ListReviews returns a recipe's written reviews. The helpful order puts
+// reviews with the best helpfulness score first and breaks ties by date.
+func (s *Service) ListReviews(ctx context.Context, query inbound.ListReviewsQuery) ([]inbound.ReviewDTO, error) {
+	switch query.Sort {
+	case "":
+		query.Sort = inbound.ReviewSortHelpful
+	case inbound.ReviewSortHelpful, inbound.ReviewSortNewest:
+	default:
+		return nil, errors.NewBadRequestError("sort must be helpful or newest")
+	}
+	if _, err := s.findRecipe(ctx, query.RecipeID, query.ViewerID); err != nil {
+		return nil, err
+	}
+
+	reviews, err := s.loadReviews(ctx, query.RecipeID, query.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(reviews, func(i, j int) bool {
+		if query.Sort == inbound.ReviewSortHelpful && reviews[i].Helpfulness != reviews[j].Helpfulness {
+			return reviews[i].Helpfulness > reviews[j].Helpfulness
+		}
+		return reviews[i].CreatedAt.After(reviews[j].CreatedAt)
+	})
+	return reviews, nil
+}
+
+// VoteReview records a reader's verdict on a review
+func (s *Service) VoteReview(ctx context.Context, cmd inbound.ReviewVoteCommand) (*inbound.ReviewDTO, error) {
+	entity, err := s.findRecipe(ctx, cmd.RecipeID, cmd.VoterID)
+	if err != nil {
+		return nil, err
+	}
+
+	vote, err := comment.NewHelpfulVote(cmd.RecipeID, entity.AuthorID(), cmd.ReviewerID, cmd.VoterID, cmd.Helpful, s.now())
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	if _, err := s.findReview(ctx, cmd.RecipeID, cmd.ReviewerID, cmd.VoterID); err != nil {
+		return nil, err
+	}
+	if err := s.feedback.SaveHelpfulVote(ctx, vote); err != nil {
+		return nil, errors.NewDatabaseError("save helpful vote", err)
+	}
+	return s.findReview(ctx, cmd.RecipeID, cmd.ReviewerID, cmd.VoterID)
+}
+
+// ClearReviewVote withdraws a reader's vote on a review
+func (s *Service) ClearReviewVote(ctx context.Context, cmd inbound.ReviewVoteCommand) (*inbound.ReviewDTO, error) {
+	if _, err := s.findRecipe(ctx, cmd.RecipeID, cmd.VoterID); err != nil {
+		return nil, err
+	}
+	if _, err := s.findReview(ctx, cmd.RecipeID, cmd.ReviewerID, cmd.VoterID); err != nil {
+		return nil, err
+	}
+	if err := s.feedback.RemoveHelpfulVote(ctx, cmd.RecipeID, cmd.ReviewerID, cmd.VoterID); err != nil {
+		return nil, errors.NewDatabaseError("remove helpful vote", err)
+	}
+	return s.findReview(ctx, cmd.RecipeID, cmd.ReviewerID, cmd.VoterID)
+}
+
+// findRecipe loads a recipe the viewer may see: a published one, or a
+// draft of their own
+func (s *Service) findRecipe(ctx context.Context, recipeID, viewerID uuid.UUID) (*recipe.Recipe, error) {
+	entity, err := s.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil || (entity.Status() != recipe.RecipeStatusPublished && entity.AuthorID() != viewerID) {
+		return nil, errors.NewRecipeNotFoundError(recipeID.String())
+	}
+	return entity, nil
+}
+
+// findComment loads a comment on a recipe the viewer may see
+func (s *Service) findComment(ctx context.Context, recipeID, commentID, viewerID uuid.UUID) (*comment.Comment, error) {
+	if _, err := s.findRecipe(ctx, recipeID, viewerID); err != nil {
+		return nil, err
+	}
+	return s.commentOn(ctx, recipeID, commentID)
+}
+
+// commentOn loads a comment, which must be on the given recipe
+func (s *Service) commentOn(ctx context.Context, recipeID, commentID uuid.UUID) (*comment.Comment, error) {
+	c, err := s.comments.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find comment", err)
+	}
+	if c == nil || c.RecipeID() != recipeID {
+		return nil, errors.NewNotFoundError("comment")
+	}
+	return c, nil
+}
+
+// findReview loads one review with its votes
+func (s *Service) findReview(ctx context.Context, recipeID, reviewerID, viewerID uuid.UUID) (*inbound.ReviewDTO, error) {
+	reviews, err := s.loadReviews(ctx, recipeID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reviews {
+		if reviews[i].ReviewerID == reviewerID {
+			return &reviews[i], nil
+		}
+	}
+	return nil, errors.NewNotFoundError("review")
+}
+
+// loadReviews reads a recipe's reviews and tallies their votes
+func (s *Service) loadReviews(ctx context.Context, recipeID, viewerID uuid.UUID) ([]inbound.ReviewDTO, error) {
+	ratings, err := s.feedback.FindReviews(ctx, recipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list reviews", err)
+	}
+	tallies, err := s.feedback.HelpfulTallies(ctx, recipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("count helpful votes", err)
+	}
+	var mine map[uuid.UUID]bool
+	if viewerID != uuid.Nil {
+		if mine, err = s.feedback.FindHelpfulVotes(ctx, recipeID, viewerID); err != nil {
+			return nil, errors.NewDatabaseError("find helpful votes", err)
+		}
+	}
+
+	reviewers := make(map[uuid.UUID]*user.User)
+	reviews := make([]inbound.ReviewDTO, 0, len(ratings))
+	for _, rating := range ratings {
+		if _, ok := reviewers[rating.UserID]; !ok {
+			reviewers[rating.UserID] = s.findUser(ctx, rating.UserID)
+		}
+		tally := tallies[rating.UserID]
+		dto := inbound.ReviewDTO{
+			RecipeID:    recipeID,
+			ReviewerID:  rating.UserID,
+			Rating:      rating.Value,
+			Content:     rating.Comment,
+			CreatedAt:   rating.CreatedAt,
+			Helpful:     tally.Helpful,
+			NotHelpful:  tally.NotHelpful,
+			Helpfulness: comment.Helpfulness(tally.Helpful, tally.NotHelpful),
+		}
+		if reviewer := reviewers[rating.UserID]; reviewer != nil {
+			dto.ReviewerName = reviewer.Name()
+		}
+		if helpful, voted := mine[rating.UserID]; voted {
+			dto.MyVote = "not_helpful"
+			if helpful {
+				dto.MyVote = "helpful"
+			}
+		}
+		reviews = append(reviews, dto)
+	}
+	return reviews, nil
+}
+
+// commentReactions reads one comment's reactions after a change
+func (s *Service) commentReactions(ctx context.Context, commentID, userID uuid.UUID) (*inbound.CommentReactions, error) {
+	counts, mine, err := s.loadReactions(ctx, []uuid.UUID{commentID}, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := &inbound.CommentReactions{
+		CommentID:   commentID,
+		Reactions:   counts[commentID],
+		MyReactions: mine[commentID],
+	}
+	if result.Reactions == nil {
+		result.Reactions = map[string]int{}
+	}
+	if result.MyReactions == nil {
+		result.MyReactions = []string{}
+	}
+	return result, nil
+}
+
+// loadReactions tallies reactions to comments, and the viewer's own when
+// viewerID is set
+func (s *Service) loadReactions(ctx context.Context, commentIDs []uuid.UUID, viewerID uuid.UUID) (map[uuid.UUID]map[string]int, map[uuid.UUID][]string, error) {
+	counts, err := s.feedback.CountReactions(ctx, commentIDs)
+	if err != nil {
+		return nil, nil, errors.NewDatabaseError("count reactions", err)
+	}
+	byComment := make(map[uuid.UUID]map[string]int, len(counts))
+	for id, kinds := range counts {
+		byComment[id] = make(map[string]int, len(kinds))
+		for kind, n := range kinds {
+			byComment[id][string(kind)] = n
+		}
+	}
+
+	mine := make(map[uuid.UUID][]string)
+	if viewerID == uuid.Nil {
+		return byComment, mine, nil
+	}
+	given, err := s.feedback.FindUserReactions(ctx, commentIDs, viewerID)
+	if err != nil {
+		return nil, nil, errors.NewDatabaseError("find reactions", err)
+	}
+	for id, kinds := range given {
+		for _, kind := range kinds {
+			mine[id] = append(mine[id], string(kind))
+		}
+	}
+	return byComment, mine, nil
+}
+
+// attachReactions fills in the reactions on comment threads. A failure is
+// logged and the comments are shown without them.
+func (s *Service) attachReactions(ctx context.Context, threads []inbound.CommentDTO, comments []*comment.Comment, viewerID uuid.UUID) {
+	if s.feedback == nil || len(comments) == 0 {
+		return
+	}
+	ids := make([]uuid.UUID, len(comments))
+	for i, c := range comments {
+		ids[i] = c.ID()
+	}
+	counts, mine, err := s.loadReactions(ctx, ids, viewerID)
+	if err != nil {
+		s.logger.Warn("Comment reactions not loaded", zap.Error(err))
+		return
+	}
+
+	var fill func(dtos []inbound.CommentDTO)
+	fill = func(dtos []inbound.CommentDTO) {
+		for i := range dtos {
+			dtos[i].Reactions = counts[dtos[i].ID]
+			dtos[i].MyReactions = mine[dtos[i].ID]
+			fill(dtos[i].Replies)
+		}
+	}
+	fill(threads)
+}
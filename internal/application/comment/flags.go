@@ -0,0 +1,128 @@
+package comment
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FlagComment records a reader's flag for the moderators' queue. Flagging
+// a comment twice keeps the first flag.
+func (s *Service) FlagComment(ctx context.Context, cmd inbound.FlagCommentCommand) error {
+	c, err := s.findComment(ctx, cmd.RecipeID, cmd.CommentID, cmd.UserID)
+	if err != nil {
+		return err
+	}
+
+	flag, err := comment.NewFlag(c, cmd.UserID, comment.FlagReason(cmd.Reason), cmd.Note, s.now())
+	if err != nil {
+		if stderrors.Is(err, comment.ErrNotFlaggable) {
+			return errors.NewConflictError(err.Error())
+		}
+		return errors.NewBadRequestError(err.Error())
+	}
+	if err := s.moderation.SaveFlag(ctx, flag); err != nil {
+		return errors.NewDatabaseError("flag comment", err)
+	}
+
+	s.logger.Info("Comment flagged",
+		zap.String("comment_id", c.ID().String()),
+		zap.String("reason", string(flag.Reason)),
+	)
+	return nil
+}
+
+// ListFlaggedComments returns the flag queue, most flagged first
+func (s *Service) ListFlaggedComments(ctx context.Context, requesterID uuid.UUID, limit int) ([]inbound.FlaggedCommentDTO, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "review flagged comments"); err != nil {
+		return nil, err
+	}
+
+	flagged, err := s.moderation.FindFlagged(ctx, listLimit(limit))
+	if err != nil {
+		return nil, errors.NewDatabaseError("list flagged comments", err)
+	}
+
+	authors := make(map[uuid.UUID]*user.User)
+	dtos := make([]inbound.FlaggedCommentDTO, 0, len(flagged))
+	for _, f := range flagged {
+		c, err := s.comments.FindByID(ctx, f.CommentID)
+		if err != nil {
+			return nil, errors.NewDatabaseError("find comment", err)
+		}
+		if c == nil {
+			continue
+		}
+		if _, ok := authors[c.AuthorID()]; !ok {
+			authors[c.AuthorID()] = s.findUser(ctx, c.AuthorID())
+		}
+
+		reasons := make(map[string]int, len(f.Reasons))
+		for reason, n := range f.Reasons {
+			reasons[string(reason)] = n
+		}
+		dtos = append(dtos, inbound.FlaggedCommentDTO{
+			Comment:        commentToDTO(c, authors[c.AuthorID()]),
+			Flags:          f.Flags,
+			Reasons:        reasons,
+			FirstFlaggedAt: f.FirstFlaggedAt,
+		})
+	}
+	return dtos, nil
+}
+
+// ReviewFlaggedComment settles a flagged comment. Hiding it is final, like
+// a moderator removing a comment an author hid; dismissing keeps it. Either
+// way its flags are cleared.
+func (s *Service) ReviewFlaggedComment(ctx context.Context, cmd inbound.ReviewFlaggedCommentCommand) (*inbound.CommentDTO, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, cmd.ModeratorID, "review flagged comments"); err != nil {
+		return nil, err
+	}
+	reason, err := comment.NormalizeReason(cmd.Reason)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	c, err := s.comments.FindByID(ctx, cmd.CommentID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find comment", err)
+	}
+	if c == nil {
+		return nil, errors.NewNotFoundError("comment")
+	}
+
+	action := comment.ActionDismiss
+	if cmd.Hide {
+		action = comment.ActionRemove
+		if err := c.Hide(cmd.ModeratorID, reason, s.now()); err != nil {
+			return nil, errors.NewConflictError(err.Error())
+		}
+		if err := c.Remove(); err != nil {
+			return nil, errors.NewConflictError(err.Error())
+		}
+		if err := s.comments.UpdateModeration(ctx, c); err != nil {
+			return nil, errors.NewDatabaseError("hide comment", err)
+		}
+	}
+	if err := s.moderation.ClearFlags(ctx, c.ID()); err != nil {
+		return nil, errors.NewDatabaseError("clear comment flags", err)
+	}
+
+	if err := s.audit(ctx, commentEvent(c, cmd.ModeratorID, action, reason)); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Flagged comment reviewed",
+		zap.String("comment_id", c.ID().String()),
+		zap.String("action", string(action)),
+	)
+
+	dto := commentToDTO(c, s.findUser(ctx, c.AuthorID()))
+	return &dto, nil
+}
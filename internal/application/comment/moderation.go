@@ -0,0 +1,400 @@
+package comment
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// Discussion returns whether a recipe's comments are locked
+func (s *Service) Discussion(ctx context.Context, recipeID, viewerID uuid.UUID) (*inbound.DiscussionDTO, error) {
+	if _, err := s.findRecipe(ctx, recipeID, viewerID); err != nil {
+		return nil, err
+	}
+	d, err := s.findDiscussion(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	return discussionToDTO(recipeID, d), nil
+}
+
+// LockDiscussion locks or unlocks a recipe's comments. Asking for the state
+// the discussion is already in changes nothing and is not audited.
+func (s *Service) LockDiscussion(ctx context.Context, cmd inbound.LockDiscussionCommand) (*inbound.DiscussionDTO, error) {
+	if _, err := s.ownRecipe(ctx, cmd.RecipeID, cmd.UserID); err != nil {
+		return nil, err
+	}
+	reason, err := comment.NormalizeReason(cmd.Reason)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	d, err := s.findDiscussion(ctx, cmd.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		d = &comment.Discussion{RecipeID: cmd.RecipeID}
+	}
+	if d.Locked == cmd.Locked {
+		return discussionToDTO(cmd.RecipeID, d), nil
+	}
+
+	action := comment.ActionUnlock
+	d.Locked, d.LockedBy, d.LockedAt = false, nil, nil
+	if cmd.Locked {
+		now := s.now()
+		action = comment.ActionLock
+		d.Locked, d.LockedBy, d.LockedAt = true, &cmd.UserID, &now
+	}
+	if err := s.moderation.SaveDiscussion(ctx, d); err != nil {
+		return nil, errors.NewDatabaseError("save discussion", err)
+	}
+
+	recipeID := cmd.RecipeID
+	if err := s.audit(ctx, comment.ModerationEvent{
+		ActorID:  cmd.UserID,
+		Action:   action,
+		RecipeID: &recipeID,
+		Reason:   reason,
+	}); err != nil {
+		return nil, err
+	}
+	return discussionToDTO(cmd.RecipeID, d), nil
+}
+
+// HideComment hides a comment on the author's recipe and queues it for
+// moderator review
+func (s *Service) HideComment(ctx context.Context, cmd inbound.HideCommentCommand) (*inbound.CommentDTO, error) {
+	if _, err := s.ownRecipe(ctx, cmd.RecipeID, cmd.UserID); err != nil {
+		return nil, err
+	}
+	c, err := s.commentOn(ctx, cmd.RecipeID, cmd.CommentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Hide(cmd.UserID, cmd.Reason, s.now()); err != nil {
+		if stderrors.Is(err, comment.ErrAlreadyHidden) {
+			return nil, errors.NewConflictError(err.Error())
+		}
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	if err := s.comments.UpdateModeration(ctx, c); err != nil {
+		return nil, errors.NewDatabaseError("hide comment", err)
+	}
+
+	if err := s.audit(ctx, commentEvent(c, cmd.UserID, comment.ActionHide, c.HideReason())); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Comment hidden by recipe author",
+		zap.String("comment_id", c.ID().String()),
+		zap.String("recipe_id", c.RecipeID().String()),
+	)
+
+	dto := commentToDTO(c, s.findUser(ctx, c.AuthorID()))
+	return &dto, nil
+}
+
+// ListHiddenComments returns the moderator review queue, oldest first
+func (s *Service) ListHiddenComments(ctx context.Context, requesterID uuid.UUID, limit int) ([]inbound.CommentDTO, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "review hidden comments"); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.comments.FindByModerationStatus(ctx, comment.StatusHidden, listLimit(limit))
+	if err != nil {
+		return nil, errors.NewDatabaseError("list hidden comments", err)
+	}
+
+	authors := make(map[uuid.UUID]*user.User)
+	dtos := make([]inbound.CommentDTO, len(comments))
+	for i, c := range comments {
+		if _, ok := authors[c.AuthorID()]; !ok {
+			authors[c.AuthorID()] = s.findUser(ctx, c.AuthorID())
+		}
+		dtos[i] = commentToDTO(c, authors[c.AuthorID()])
+	}
+	return dtos, nil
+}
+
+// ReviewHiddenComment restores a hidden comment or removes it for good
+func (s *Service) ReviewHiddenComment(ctx context.Context, cmd inbound.ReviewHiddenCommentCommand) (*inbound.CommentDTO, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, cmd.ModeratorID, "review hidden comments"); err != nil {
+		return nil, err
+	}
+	reason, err := comment.NormalizeReason(cmd.Reason)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	c, err := s.comments.FindByID(ctx, cmd.CommentID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find comment", err)
+	}
+	if c == nil {
+		return nil, errors.NewNotFoundError("comment")
+	}
+
+	action := comment.ActionRemove
+	if cmd.Restore {
+		action = comment.ActionRestore
+		err = c.Restore()
+	} else {
+		err = c.Remove()
+	}
+	if err != nil {
+		return nil, errors.NewConflictError(err.Error())
+	}
+	if err := s.comments.UpdateModeration(ctx, c); err != nil {
+		return nil, errors.NewDatabaseError("review comment", err)
+	}
+
+	if err := s.audit(ctx, commentEvent(c, cmd.ModeratorID, action, reason)); err != nil {
+		return nil, err
+	}
+
+	dto := commentToDTO(c, s.findUser(ctx, c.AuthorID()))
+	return &dto, nil
+}
+
+// BlockCommenter stops a user from commenting on the author's recipes.
+// Blocking someone again updates the reason.
+func (s *Service) BlockCommenter(ctx context.Context, cmd inbound.BlockCommenterCommand) (*inbound.BlockedCommenterDTO, error) {
+	block, err := comment.NewBlock(cmd.AuthorID, cmd.UserID, cmd.Reason, s.now())
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	blocked := s.findUser(ctx, cmd.UserID)
+	if blocked == nil {
+		return nil, errors.NewUserNotFoundError(cmd.UserID.String())
+	}
+
+	if err := s.moderation.SaveBlock(ctx, block); err != nil {
+		return nil, errors.NewDatabaseError("block commenter", err)
+	}
+	subjectID := cmd.UserID
+	if err := s.audit(ctx, comment.ModerationEvent{
+		ActorID:   cmd.AuthorID,
+		Action:    comment.ActionBlock,
+		SubjectID: &subjectID,
+		Reason:    block.Reason,
+	}); err != nil {
+		return nil, err
+	}
+
+	dto := blockToDTO(*block, blocked)
+	return &dto, nil
+}
+
+// UnblockCommenter lets a blocked user comment on the author's recipes again
+func (s *Service) UnblockCommenter(ctx context.Context, cmd inbound.BlockCommenterCommand) error {
+	reason, err := comment.NormalizeReason(cmd.Reason)
+	if err != nil {
+		return errors.NewBadRequestError(err.Error())
+	}
+	deleted, err := s.moderation.DeleteBlock(ctx, cmd.AuthorID, cmd.UserID)
+	if err != nil {
+		return errors.NewDatabaseError("unblock commenter", err)
+	}
+	if !deleted {
+		return errors.NewNotFoundError("block")
+	}
+
+	subjectID := cmd.UserID
+	return s.audit(ctx, comment.ModerationEvent{
+		ActorID:   cmd.AuthorID,
+		Action:    comment.ActionUnblock,
+		SubjectID: &subjectID,
+		Reason:    reason,
+	})
+}
+
+// ListBlockedCommenters returns the users an author blocked, newest first
+func (s *Service) ListBlockedCommenters(ctx context.Context, authorID uuid.UUID) ([]inbound.BlockedCommenterDTO, error) {
+	blocks, err := s.moderation.FindBlocks(ctx, authorID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list blocked commenters", err)
+	}
+	dtos := make([]inbound.BlockedCommenterDTO, len(blocks))
+	for i, block := range blocks {
+		dtos[i] = blockToDTO(block, s.findUser(ctx, block.UserID))
+	}
+	return dtos, nil
+}
+
+// ModerationLog returns audit events, newest first. Admins see every event;
+// authors see those on one of their recipes, or their own actions when no
+// recipe is given.
+func (s *Service) ModerationLog(ctx context.Context, query inbound.ModerationLogQuery) ([]inbound.ModerationEventDTO, error) {
+	filter := outbound.ModerationEventFilter{
+		RecipeID: query.RecipeID,
+		Limit:    listLimit(query.Limit),
+	}
+
+	if err := access.RequireAdmin(ctx, s.userRepo, query.RequesterID, "view the moderation log"); err != nil {
+		if !errors.Is(err, errors.CodeInsufficientPermissions) {
+			return nil, err
+		}
+		if query.RecipeID != nil {
+			if _, err := s.ownRecipe(ctx, *query.RecipeID, query.RequesterID); err != nil {
+				return nil, err
+			}
+		} else {
+			filter.ActorID = &query.RequesterID
+		}
+	}
+
+	events, err := s.moderation.FindEvents(ctx, filter)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list moderation events", err)
+	}
+	dtos := make([]inbound.ModerationEventDTO, len(events))
+	for i, event := range events {
+		dtos[i] = inbound.ModerationEventDTO{
+			ID:        event.ID,
+			ActorID:   event.ActorID,
+			Action:    string(event.Action),
+			RecipeID:  event.RecipeID,
+			CommentID: event.CommentID,
+			SubjectID: event.SubjectID,
+			Reason:    event.Reason,
+			CreatedAt: event.CreatedAt,
+		}
+	}
+	return dtos, nil
+}
+
+// checkCanComment refuses comments on a locked discussion, except from the
+// recipe's author, and from users the author blocked. The refusals are
+// domain errors so email replies can be bounced with them.
+func (s *Service) checkCanComment(ctx context.Context, entity *recipe.Recipe, userID uuid.UUID) error {
+	if userID == entity.AuthorID() {
+		return nil
+	}
+	d, err := s.findDiscussion(ctx, entity.ID())
+	if err != nil {
+		return err
+	}
+	if d != nil && d.Locked {
+		return comment.ErrDiscussionLocked
+	}
+	blocked, err := s.moderation.IsBlocked(ctx, entity.AuthorID(), userID)
+	if err != nil {
+		return errors.NewDatabaseError("check comment block", err)
+	}
+	if blocked {
+		return comment.ErrBlocked
+	}
+	return nil
+}
+
+// maskModerated blanks out hidden and removed comments for everyone but
+// the recipe's author and the comment's own author. Deleted comments have
+// no content left to show anyone.
+func maskModerated(threads []inbound.CommentDTO, recipeAuthorID, viewerID uuid.UUID) {
+	for i := range threads {
+		dto := &threads[i]
+		privileged := viewerID != uuid.Nil && (viewerID == recipeAuthorID || viewerID == dto.AuthorID)
+		deleted := dto.Status == string(comment.StatusDeleted)
+		if dto.Status != string(comment.StatusVisible) && (!privileged || deleted) {
+			dto.Content = ""
+			dto.HideReason = ""
+			dto.HiddenAt = nil
+		}
+		maskModerated(dto.Replies, recipeAuthorID, viewerID)
+	}
+}
+
+func (s *Service) findDiscussion(ctx context.Context, recipeID uuid.UUID) (*comment.Discussion, error) {
+	d, err := s.moderation.FindDiscussion(ctx, recipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find discussion", err)
+	}
+	return d, nil
+}
+
+// ownRecipe loads a recipe the user wrote
+func (s *Service) ownRecipe(ctx context.Context, recipeID, userID uuid.UUID) (*recipe.Recipe, error) {
+	entity, err := s.findRecipe(ctx, recipeID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if entity.AuthorID() != userID {
+		return nil, errors.NewForbiddenError("only the recipe's author can moderate its comments")
+	}
+	return entity, nil
+}
+
+// audit records a moderation action. The action has already been saved, so
+// a failure here is reported to the caller rather than left unaudited.
+func (s *Service) audit(ctx context.Context, event comment.ModerationEvent) error {
+	event.ID = uuid.New()
+	event.CreatedAt = s.now()
+	if err := s.moderation.RecordEvent(ctx, &event); err != nil {
+		s.logger.Error("Failed to record moderation event",
+			zap.String("action", string(event.Action)),
+			zap.String("actor_id", event.ActorID.String()),
+			zap.Error(err),
+		)
+		return errors.NewDatabaseError("record moderation event", err)
+	}
+	return nil
+}
+
+func commentEvent(c *comment.Comment, actorID uuid.UUID, action comment.Action, reason string) comment.ModerationEvent {
+	recipeID, commentID, subjectID := c.RecipeID(), c.ID(), c.AuthorID()
+	return comment.ModerationEvent{
+		ActorID:   actorID,
+		Action:    action,
+		RecipeID:  &recipeID,
+		CommentID: &commentID,
+		SubjectID: &subjectID,
+		Reason:    reason,
+	}
+}
+
+func discussionToDTO(recipeID uuid.UUID, d *comment.Discussion) *inbound.DiscussionDTO {
+	dto := &inbound.DiscussionDTO{RecipeID: recipeID}
+	if d != nil {
+		dto.Locked = d.Locked
+		dto.LockedAt = d.LockedAt
+	}
+	return dto
+}
+
+func blockToDTO(block comment.Block, blocked *user.User) inbound.BlockedCommenterDTO {
+	dto := inbound.BlockedCommenterDTO{
+		UserID:    block.UserID,
+		Reason:    block.Reason,
+		CreatedAt: block.CreatedAt,
+	}
+	if blocked != nil {
+		dto.UserName = blocked.Name()
+	}
+	return dto
+}
+
+func listLimit(limit int) int {
+	if limit <= 0 {
+		return defaultListLimit
+	}
+	if limit > maxListLimit {
+		return maxListLimit
+	}
+	return limit
+}
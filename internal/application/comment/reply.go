@@ -0,0 +1,330 @@
+package comment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// replyLocalPrefix starts the local part of every reply-to address
+	replyLocalPrefix = "reply+"
+	// replyMACSize is how much of the HMAC the address carries; 80 bits keeps
+	// the local part within the 64 characters mail servers accept
+	replyMACSize = 10
+)
+
+// tokenEncoding is lowercase so addresses survive case-folding mail servers
+var tokenEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+var (
+	// quoteHeader matches the line mail clients put above the quoted original,
+	// such as "On Mon, 5 Oct 2026 at 09:12, Ada <ada@example.com> wrote:"
+	quoteHeader = regexp.MustCompile(`(?i)^on\s.+\swrote:$`)
+	// originalMessage matches Outlook's separators before the original
+	originalMessage = regexp.MustCompile(`(?i)^(-{2,}\s*original message\s*-{2,}|_{10,})$`)
+	// mobileSignature matches "Sent from my iPhone" and similar
+	mobileSignature = regexp.MustCompile(`(?i)^sent from my\s`)
+)
+
+// bounceSenders are the local parts mail servers send delivery reports from
+var bounceSenders = map[string]bool{"mailer-daemon": true, "postmaster": true}
+
+// spamVerdicts are the provider verdicts treated as spam
+var spamVerdicts = map[string]bool{"spam": true, "yes": true, "true": true}
+
+// HandleInboundReply posts an emailed reply to a notification as a threaded
+// response. Auto-replies are ignored, delivery reports suppress the address
+// that bounced, and spam, forged senders and bad or expired tokens are
+// rejected without answering the sender. So are replies the recipe's
+// moderation refuses: on locked comments or from blocked users.
+func (s *Service) HandleInboundReply(ctx context.Context, email inbound.InboundEmail) (*inbound.InboundReplyResult, error) {
+	if !s.repliesEnabled() {
+		return rejected("replies by email are disabled"), nil
+	}
+	log := s.logger.With(zap.String("message_id", email.MessageID))
+
+	if isAutoReply(email.Headers) {
+		log.Debug("Auto-reply ignored")
+		return &inbound.InboundReplyResult{Status: inbound.InboundReplyIgnored, Reason: "auto-reply"}, nil
+	}
+
+	token, reason, err := s.lookupToken(ctx, email.To)
+	if err != nil {
+		return nil, err
+	}
+
+	if isBounce(email) {
+		if token == nil {
+			return &inbound.InboundReplyResult{Status: inbound.InboundReplyIgnored, Reason: "delivery report for unknown address"}, nil
+		}
+		return s.suppressBounced(ctx, token)
+	}
+
+	if s.isSpam(email) {
+		log.Info("Reply rejected as spam")
+		return rejected("spam"), nil
+	}
+	if token == nil {
+		log.Info("Reply rejected", zap.String("reason", reason))
+		return rejected(reason), nil
+	}
+
+	recipient := s.findUser(ctx, token.UserID)
+	if recipient == nil || !sameAddress(email.From, recipient.Email()) {
+		log.Warn("Reply rejected, sender does not own the reply address",
+			zap.String("token_id", token.ID.String()),
+		)
+		return rejected("sender does not match the notified address"), nil
+	}
+
+	messageID := normalizeMessageID(email.MessageID)
+	if messageID != "" {
+		existing, err := s.comments.FindByEmailMessageID(ctx, messageID)
+		if err != nil {
+			return nil, errors.NewDatabaseError("find comment", err)
+		}
+		if existing != nil {
+			dto := commentToDTO(existing, recipient)
+			return &inbound.InboundReplyResult{Status: inbound.InboundReplyDuplicate, Comment: &dto}, nil
+		}
+	}
+
+	entity, err := s.recipeRepo.FindByID(ctx, token.RecipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil {
+		return rejected("the recipe was deleted"), nil
+	}
+	if err := s.checkCanComment(ctx, entity, token.UserID); err != nil {
+		if isRuleError(err) {
+			return rejected(err.Error()), nil
+		}
+		return nil, err
+	}
+
+	c, err := comment.NewComment(token.RecipeID, token.UserID, ExtractReply(email.Text), comment.SourceEmail)
+	if err != nil {
+		return rejected(err.Error()), nil
+	}
+	c.SetEmailMessageID(messageID)
+
+	var parent *comment.Comment
+	if token.ParentID != nil {
+		if parent, err = s.comments.FindByID(ctx, *token.ParentID); err != nil {
+			return nil, errors.NewDatabaseError("find comment", err)
+		}
+		if parent == nil {
+			return rejected("the comment being answered was deleted"), nil
+		}
+		err = c.ReplyTo(parent)
+	} else if token.ReviewUserID != nil {
+		err = c.ReplyToReview(*token.ReviewUserID)
+	}
+	if err != nil {
+		if isRuleError(err) {
+			return rejected(err.Error()), nil
+		}
+		return nil, errors.Wrap(err, "failed to thread reply")
+	}
+
+	if err := s.comments.Create(ctx, c); err != nil {
+		return nil, errors.NewDatabaseError("create comment", err)
+	}
+	log.Info("Email reply posted",
+		zap.String("comment_id", c.ID().String()),
+		zap.String("recipe_id", c.RecipeID().String()),
+	)
+
+	s.notifyComment(ctx, c, entity, recipient, parent)
+
+	dto := commentToDTO(c, recipient)
+	return &inbound.InboundReplyResult{Status: inbound.InboundReplyPosted, Comment: &dto}, nil
+}
+
+// lookupToken finds the reply token addressed by one of the recipients. A
+// nil token comes with the reason it was not usable.
+func (s *Service) lookupToken(ctx context.Context, recipients []string) (*outbound.ReplyToken, string, error) {
+	id, ok := uuid.Nil, false
+	for _, to := range recipients {
+		if id, ok = parseReplyAddress(s.config.ReplySecret, s.config.ReplyDomain, to); ok {
+			break
+		}
+	}
+	if !ok {
+		return nil, "invalid reply address", nil
+	}
+
+	token, err := s.tokens.FindByID(ctx, id)
+	if err != nil {
+		return nil, "", errors.NewDatabaseError("find reply token", err)
+	}
+	if token == nil {
+		return nil, "invalid reply address", nil
+	}
+	if !s.now().Before(token.ExpiresAt) {
+		return nil, "reply address expired", nil
+	}
+	return token, "", nil
+}
+
+// suppressBounced stops emailing the user whose notification bounced back
+// to its reply address
+func (s *Service) suppressBounced(ctx context.Context, token *outbound.ReplyToken) (*inbound.InboundReplyResult, error) {
+	recipient := s.findUser(ctx, token.UserID)
+	if recipient == nil {
+		return &inbound.InboundReplyResult{Status: inbound.InboundReplyIgnored, Reason: "delivery report for unknown user"}, nil
+	}
+	if err := s.suppressions.Suppress(ctx, recipient.Email(), outbound.SuppressionBounce, "delivery report to reply address"); err != nil {
+		return nil, errors.NewDatabaseError("suppress email", err)
+	}
+	s.logger.Info("Notification bounced, address suppressed", zap.String("user_id", token.UserID.String()))
+	return &inbound.InboundReplyResult{Status: inbound.InboundReplyBounced}, nil
+}
+
+func (s *Service) isSpam(email inbound.InboundEmail) bool {
+	if email.SpamScore != nil && *email.SpamScore >= s.config.SpamThreshold {
+		return true
+	}
+	if spamVerdicts[strings.ToLower(strings.TrimSpace(email.SpamVerdict))] {
+		return true
+	}
+	if flag := header(email.Headers, "X-Spam-Flag"); strings.EqualFold(flag, "yes") {
+		return true
+	}
+	if score, err := strconv.ParseFloat(header(email.Headers, "X-Spam-Score"), 64); err == nil && score >= s.config.SpamThreshold {
+		return true
+	}
+	return false
+}
+
+// replyAddress is the reply-to address for a token: the id and a truncated
+// HMAC of it, so addresses cannot be guessed or altered
+func replyAddress(secret string, id uuid.UUID, domain string) string {
+	raw := append(id[:], replyMAC(secret, id)...)
+	return replyLocalPrefix + tokenEncoding.EncodeToString(raw) + "@" + domain
+}
+
+// parseReplyAddress returns the token id of a reply-to address on our
+// domain whose signature is valid
+func parseReplyAddress(secret, domain, address string) (uuid.UUID, bool) {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		address = parsed.Address
+	}
+	at := strings.LastIndex(address, "@")
+	if at < 0 || !strings.EqualFold(address[at+1:], domain) {
+		return uuid.Nil, false
+	}
+	local := strings.ToLower(address[:at])
+	if !strings.HasPrefix(local, replyLocalPrefix) {
+		return uuid.Nil, false
+	}
+
+	raw, err := tokenEncoding.DecodeString(strings.TrimPrefix(local, replyLocalPrefix))
+	if err != nil || len(raw) != len(uuid.Nil)+replyMACSize {
+		return uuid.Nil, false
+	}
+	id, err := uuid.FromBytes(raw[:len(uuid.Nil)])
+	if err != nil || !hmac.Equal(raw[len(uuid.Nil):], replyMAC(secret, id)) {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func replyMAC(secret string, id uuid.UUID) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(id[:])
+	return mac.Sum(nil)[:replyMACSize]
+}
+
+// ExtractReply returns what the sender wrote above the quoted original,
+// without their signature
+func ExtractReply(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var kept []string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		// Some clients wrap the quote header onto a second line
+		wrappedHeader := i+1 < len(lines) && strings.HasPrefix(strings.ToLower(trimmed), "on ") &&
+			strings.EqualFold(strings.TrimSpace(lines[i+1]), "wrote:")
+		if quoteHeader.MatchString(trimmed) || wrappedHeader || originalMessage.MatchString(trimmed) ||
+			line == "-- " || trimmed == "--" || mobileSignature.MatchString(trimmed) {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(line, " \t"))
+	}
+
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// isAutoReply reports out-of-office and other automatic responses
+func isAutoReply(headers map[string]string) bool {
+	if auto := strings.ToLower(header(headers, "Auto-Submitted")); auto != "" && auto != "no" {
+		return true
+	}
+	switch strings.ToLower(header(headers, "Precedence")) {
+	case "bulk", "junk", "list", "auto_reply":
+		return true
+	}
+	return header(headers, "X-Autoreply") != "" || header(headers, "X-Autorespond") != ""
+}
+
+// isBounce reports delivery status notifications
+func isBounce(email inbound.InboundEmail) bool {
+	if strings.Contains(strings.ToLower(header(email.Headers, "Content-Type")), "report-type=delivery-status") {
+		return true
+	}
+	from := email.From
+	if parsed, err := mail.ParseAddress(from); err == nil {
+		from = parsed.Address
+	}
+	local := from
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		local = from[:at]
+	}
+	return bounceSenders[strings.ToLower(local)]
+}
+
+// header looks up a header case-insensitively
+func header(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func sameAddress(from, expected string) bool {
+	parsed, err := mail.ParseAddress(from)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Address, strings.TrimSpace(expected))
+}
+
+// normalizeMessageID strips the angle brackets some providers keep
+func normalizeMessageID(id string) string {
+	return strings.Trim(strings.TrimSpace(id), "<>")
+}
+
+func rejected(reason string) *inbound.InboundReplyResult {
+	return &inbound.InboundReplyResult{Status: inbound.InboundReplyRejected, Reason: reason}
+}
@@ -0,0 +1,488 @@
+// Package comment implements the comment use cases. Comment and review
+// notifications carry a signed reply-to address, so authors can answer by
+// replying to the email; the reply arrives through the inbound webhook and
+// is posted as a threaded response.
+package comment
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/domain/notification"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultTokenTTL is how long a notification can be replied to
+const DefaultTokenTTL = 30 * 24 * time.Hour
+
+// DefaultSpamThreshold drops replies the provider scores at or above it
+const DefaultSpamThreshold = 5.0
+
+// Config controls reply-by-email. Without a reply domain and secret,
+// notifications are sent without a reply-to address.
+type Config struct {
+	ReplyDomain   string
+	ReplySecret   string
+	TokenTTL      time.Duration
+	SpamThreshold float64
+	// SiteURL is the base of recipe links in notifications
+	SiteURL string
+}
+
+// Service implements inbound.CommentService
+type Service struct {
+	comments     outbound.CommentRepository
+	feedback     outbound.CommentFeedbackRepository
+	moderation   outbound.CommentModerationRepository
+	tokens       outbound.ReplyTokenRepository
+	suppressions outbound.EmailSuppressionRepository
+	recipeRepo   outbound.RecipeRepository
+	userRepo     outbound.UserRepository
+	email        outbound.EmailService
+	inApp        inbound.NotificationService
+	config       Config
+	now          func() time.Time
+	logger       *zap.Logger
+}
+
+// NewService creates a comment service
+func NewService(
+	comments outbound.CommentRepository,
+	feedback outbound.CommentFeedbackRepository,
+	moderation outbound.CommentModerationRepository,
+	tokens outbound.ReplyTokenRepository,
+	suppressions outbound.EmailSuppressionRepository,
+	recipeRepo outbound.RecipeRepository,
+	userRepo outbound.UserRepository,
+	email outbound.EmailService,
+	inApp inbound.NotificationService,
+	cfg Config,
+	logger *zap.Logger,
+) *Service {
+	if cfg.TokenTTL <= 0 {
+		cfg.TokenTTL = DefaultTokenTTL
+	}
+	if cfg.SpamThreshold <= 0 {
+		cfg.SpamThreshold = DefaultSpamThreshold
+	}
+	cfg.ReplyDomain = strings.ToLower(strings.TrimSpace(cfg.ReplyDomain))
+	cfg.SiteURL = strings.TrimRight(cfg.SiteURL, "/")
+
+	return &Service{
+		comments:     comments,
+		feedback:     feedback,
+		moderation:   moderation,
+		tokens:       tokens,
+		suppressions: suppressions,
+		recipeRepo:   recipeRepo,
+		userRepo:     userRepo,
+		email:        email,
+		inApp:        inApp,
+		config:       cfg,
+		now:          time.Now,
+		logger:       logger.Named("comments"),
+	}
+}
+
+// AddComment posts a comment on a published recipe, or on a draft by its
+// author, and notifies the people it concerns. Users the author blocked
+// cannot comment, nor can anyone but the author while comments are locked.
+func (s *Service) AddComment(ctx context.Context, cmd inbound.AddCommentCommand) (*inbound.CommentDTO, error) {
+	entity, err := s.recipeRepo.FindByID(ctx, cmd.RecipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil || (entity.Status() != recipe.RecipeStatusPublished && entity.AuthorID() != cmd.UserID) {
+		return nil, errors.NewRecipeNotFoundError(cmd.RecipeID.String())
+	}
+	if err := s.checkCanComment(ctx, entity, cmd.UserID); err != nil {
+		if isRuleError(err) {
+			return nil, errors.NewForbiddenError(err.Error())
+		}
+		return nil, err
+	}
+
+	c, err := comment.NewComment(cmd.RecipeID, cmd.UserID, cmd.Content, comment.SourceWeb)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	var parent *comment.Comment
+	if cmd.ParentID != nil {
+		if parent, err = s.comments.FindByID(ctx, *cmd.ParentID); err != nil {
+			return nil, errors.NewDatabaseError("find comment", err)
+		}
+		if parent == nil {
+			return nil, errors.NewNotFoundError("comment")
+		}
+		if err := c.ReplyTo(parent); err != nil {
+			return nil, errors.NewBadRequestError(err.Error())
+		}
+	}
+
+	if err := s.comments.Create(ctx, c); err != nil {
+		return nil, errors.NewDatabaseError("create comment", err)
+	}
+
+	author := s.findUser(ctx, cmd.UserID)
+	s.notifyComment(ctx, c, entity, author, parent)
+
+	dto := commentToDTO(c, author)
+	return &dto, nil
+}
+
+// EditComment changes the text of the user's own comment. Users who can
+// no longer comment on the recipe cannot edit either.
+func (s *Service) EditComment(ctx context.Context, cmd inbound.EditCommentCommand) (*inbound.CommentDTO, error) {
+	entity, err := s.findRecipe(ctx, cmd.RecipeID, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.commentOn(ctx, cmd.RecipeID, cmd.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	if c.AuthorID() != cmd.UserID {
+		return nil, errors.NewForbiddenError("only the comment's author can edit it")
+	}
+	if err := s.checkCanComment(ctx, entity, cmd.UserID); err != nil {
+		if isRuleError(err) {
+			return nil, errors.NewForbiddenError(err.Error())
+		}
+		return nil, err
+	}
+
+	if err := c.Edit(cmd.Content, s.now()); err != nil {
+		if stderrors.Is(err, comment.ErrNotEditable) {
+			return nil, errors.NewConflictError(err.Error())
+		}
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	if err := s.comments.UpdateContent(ctx, c); err != nil {
+		return nil, errors.NewDatabaseError("edit comment", err)
+	}
+
+	dto := commentToDTO(c, s.findUser(ctx, c.AuthorID()))
+	return &dto, nil
+}
+
+// DeleteComment deletes a comment. Authors delete their own; admins may
+// delete any, which is audited. Replies stay threaded under the blank.
+func (s *Service) DeleteComment(ctx context.Context, cmd inbound.DeleteCommentCommand) error {
+	c, err := s.findComment(ctx, cmd.RecipeID, cmd.CommentID, cmd.UserID)
+	if err != nil {
+		return err
+	}
+	moderated := c.AuthorID() != cmd.UserID
+	if moderated {
+		if err := access.RequireAdmin(ctx, s.userRepo, cmd.UserID, "delete other users' comments"); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Delete(); err != nil {
+		return errors.NewConflictError(err.Error())
+	}
+	if err := s.comments.UpdateContent(ctx, c); err != nil {
+		return errors.NewDatabaseError("delete comment", err)
+	}
+
+	if moderated {
+		return s.audit(ctx, commentEvent(c, cmd.UserID, comment.ActionDelete, ""))
+	}
+	return nil
+}
+
+// ListComments returns a recipe's comments as threads, oldest first.
+// Hidden and removed comments keep their place in the thread with their
+// content blanked for other readers. Deleted comments keep theirs only
+// while they have replies.
+func (s *Service) ListComments(ctx context.Context, recipeID, viewerID uuid.UUID) ([]inbound.CommentDTO, error) {
+	comments, err := s.comments.FindByRecipe(ctx, recipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list comments", err)
+	}
+
+	authors := make(map[uuid.UUID]*user.User)
+	for _, c := range comments {
+		if _, ok := authors[c.AuthorID()]; !ok {
+			authors[c.AuthorID()] = s.findUser(ctx, c.AuthorID())
+		}
+	}
+
+	threads := pruneDeleted(buildThreads(comments, authors))
+	// The recipe's author sees moderated comments in full
+	var recipeAuthorID uuid.UUID
+	if entity, err := s.recipeRepo.FindByID(ctx, recipeID); err == nil && entity != nil {
+		recipeAuthorID = entity.AuthorID()
+	}
+	maskModerated(threads, recipeAuthorID, viewerID)
+	s.attachReactions(ctx, threads, comments, viewerID)
+	return threads, nil
+}
+
+// NotifyReview emails the recipe author about a review so they can answer
+// it by replying
+func (s *Service) NotifyReview(ctx context.Context, cmd inbound.RateRecipeCommand) error {
+	entity, err := s.recipeRepo.FindByID(ctx, cmd.RecipeID)
+	if err != nil {
+		return errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil || entity.AuthorID() == cmd.UserID {
+		return nil
+	}
+
+	reviewer := s.findUser(ctx, cmd.UserID)
+	recipient := s.findUser(ctx, entity.AuthorID())
+	if recipient == nil {
+		return nil
+	}
+
+	reviewerID := cmd.UserID
+	s.notifyInApp(ctx, recipient.ID(), reviewerID, entity, cmd.Comment)
+	s.notify(ctx, recipient, entity, outbound.ReplyToken{ReviewUserID: &reviewerID}, outbound.CommentNotificationEmail{
+		AuthorName: displayName(reviewer),
+		Rating:     cmd.Rating,
+		Content:    strings.TrimSpace(cmd.Comment),
+	})
+	return nil
+}
+
+// HandleDeliveryEvent suppresses addresses that hard bounced or marked our
+// email as spam. Soft bounces and other events are ignored.
+func (s *Service) HandleDeliveryEvent(ctx context.Context, event inbound.EmailDeliveryEvent) error {
+	var reason string
+	switch event.Type {
+	case inbound.EmailEventComplaint:
+		reason = outbound.SuppressionComplaint
+	case inbound.EmailEventBounce:
+		if !event.Permanent {
+			return nil
+		}
+		reason = outbound.SuppressionBounce
+	default:
+		return nil
+	}
+	if strings.TrimSpace(event.Email) == "" {
+		return errors.NewBadRequestError("email is required")
+	}
+
+	if err := s.suppressions.Suppress(ctx, event.Email, reason, event.Detail); err != nil {
+		return errors.NewDatabaseError("suppress email", err)
+	}
+	s.logger.Info("Email address suppressed", zap.String("reason", reason))
+	return nil
+}
+
+// notifyComment tells the recipe author, and the author of the comment
+// being answered, about a new comment. Each gets a reply-to address that
+// threads their answer under it.
+func (s *Service) notifyComment(ctx context.Context, c *comment.Comment, entity *recipe.Recipe, author *user.User, parent *comment.Comment) {
+	recipients := []uuid.UUID{entity.AuthorID()}
+	if parent != nil {
+		recipients = append(recipients, parent.AuthorID())
+	}
+	if reviewer := c.ReviewUserID(); reviewer != nil {
+		recipients = append(recipients, *reviewer)
+	}
+
+	notified := map[uuid.UUID]bool{c.AuthorID(): true}
+	for _, id := range recipients {
+		if notified[id] {
+			continue
+		}
+		notified[id] = true
+
+		recipient := s.findUser(ctx, id)
+		if recipient == nil {
+			continue
+		}
+		s.notifyInApp(ctx, id, c.AuthorID(), entity, c.Content())
+		parentID := c.ID()
+		s.notify(ctx, recipient, entity, outbound.ReplyToken{ParentID: &parentID}, outbound.CommentNotificationEmail{
+			AuthorName: displayName(author),
+			Content:    c.Content(),
+		})
+	}
+}
+
+// notifyInApp adds an in-app notification, which the recipient may have
+// muted. Failures are logged like email failures.
+func (s *Service) notifyInApp(ctx context.Context, recipientID, authorID uuid.UUID, entity *recipe.Recipe, content string) {
+	if s.inApp == nil {
+		return
+	}
+	err := s.inApp.Notify(ctx, inbound.NotifyCommand{
+		UserID:  recipientID,
+		ActorID: &authorID,
+		Type:    notification.TypeRecipeCommented,
+		Subject: entity.Title(),
+		Message: content,
+		Link:    "/recipes/" + entity.ID().String(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to add comment notification",
+			zap.String("recipe_id", entity.ID().String()),
+			zap.String("user_id", recipientID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// notify sends one notification. Failures are logged rather than returned:
+// the comment or review is already saved.
+func (s *Service) notify(ctx context.Context, recipient *user.User, entity *recipe.Recipe, target outbound.ReplyToken, n outbound.CommentNotificationEmail) {
+	if s.email == nil {
+		return
+	}
+
+	log := s.logger.With(
+		zap.String("recipe_id", entity.ID().String()),
+		zap.String("user_id", recipient.ID().String()),
+	)
+	suppressed, err := s.suppressions.IsSuppressed(ctx, recipient.Email())
+	if err != nil {
+		log.Error("Failed to check email suppression", zap.Error(err))
+		return
+	}
+	if suppressed {
+		log.Debug("Notification skipped, address suppressed")
+		return
+	}
+
+	n.To = recipient.Email()
+	n.Name = recipient.Name()
+	n.RecipeTitle = entity.Title()
+	if s.config.SiteURL != "" {
+		n.RecipeURL = s.config.SiteURL + "/recipes/" + entity.ID().String()
+	}
+
+	if s.repliesEnabled() {
+		now := s.now()
+		target.ID = uuid.New()
+		target.UserID = recipient.ID()
+		target.RecipeID = entity.ID()
+		target.ExpiresAt = now.Add(s.config.TokenTTL)
+		target.CreatedAt = now
+		if err := s.tokens.Create(ctx, target); err != nil {
+			log.Error("Failed to store reply token", zap.Error(err))
+		} else {
+			n.ReplyTo = replyAddress(s.config.ReplySecret, target.ID, s.config.ReplyDomain)
+		}
+	}
+
+	if err := s.email.SendCommentNotification(ctx, n); err != nil {
+		log.Error("Failed to send comment notification", zap.Error(err))
+	}
+}
+
+func (s *Service) repliesEnabled() bool {
+	return s.config.ReplyDomain != "" && s.config.ReplySecret != "" && s.tokens != nil
+}
+
+// findUser loads a user for display, nil when they cannot be loaded
+func (s *Service) findUser(ctx context.Context, id uuid.UUID) *user.User {
+	u, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Debug("User not loaded", zap.String("user_id", id.String()), zap.Error(err))
+		return nil
+	}
+	return u
+}
+
+// buildThreads nests replies under the comment they answer. Replies whose
+// parent is missing are shown at the top level rather than dropped.
+func buildThreads(comments []*comment.Comment, authors map[uuid.UUID]*user.User) []inbound.CommentDTO {
+	present := make(map[uuid.UUID]bool, len(comments))
+	children := make(map[uuid.UUID][]*comment.Comment)
+	for _, c := range comments {
+		present[c.ID()] = true
+	}
+
+	var roots []*comment.Comment
+	for _, c := range comments {
+		if parent := c.ParentID(); parent != nil && present[*parent] {
+			children[*parent] = append(children[*parent], c)
+			continue
+		}
+		roots = append(roots, c)
+	}
+
+	var build func(c *comment.Comment) inbound.CommentDTO
+	build = func(c *comment.Comment) inbound.CommentDTO {
+		dto := commentToDTO(c, authors[c.AuthorID()])
+		for _, child := range children[c.ID()] {
+			dto.Replies = append(dto.Replies, build(child))
+		}
+		return dto
+	}
+
+	threads := make([]inbound.CommentDTO, len(roots))
+	for i, root := range roots {
+		threads[i] = build(root)
+	}
+	return threads
+}
+
+// pruneDeleted drops deleted comments nobody answered
+func pruneDeleted(threads []inbound.CommentDTO) []inbound.CommentDTO {
+	kept := threads[:0]
+	for _, dto := range threads {
+		dto.Replies = pruneDeleted(dto.Replies)
+		if dto.Status == string(comment.StatusDeleted) && len(dto.Replies) == 0 {
+			continue
+		}
+		kept = append(kept, dto)
+	}
+	return kept
+}
+
+func commentToDTO(c *comment.Comment, author *user.User) inbound.CommentDTO {
+	dto := inbound.CommentDTO{
+		ID:           c.ID(),
+		RecipeID:     c.RecipeID(),
+		AuthorID:     c.AuthorID(),
+		ParentID:     c.ParentID(),
+		ReviewUserID: c.ReviewUserID(),
+		Content:      c.Content(),
+		Source:       string(c.Source()),
+		CreatedAt:    c.CreatedAt(),
+		EditedAt:     c.EditedAt(),
+		Status:       string(c.Status()),
+		HideReason:   c.HideReason(),
+		HiddenAt:     c.HiddenAt(),
+	}
+	if author != nil {
+		dto.AuthorName = author.Name()
+	}
+	return dto
+}
+
+func displayName(u *user.User) string {
+	if u == nil || u.Name() == "" {
+		return "Someone"
+	}
+	return u.Name()
+}
+
+// isRuleError reports domain validation errors that should be reported to
+// the sender rather than treated as failures
+func isRuleError(err error) bool {
+	return stderrors.Is(err, comment.ErrEmptyContent) ||
+		stderrors.Is(err, comment.ErrContentTooLong) ||
+		stderrors.Is(err, comment.ErrParentOtherRecipe) ||
+		stderrors.Is(err, comment.ErrAlreadyThreaded) ||
+		stderrors.Is(err, comment.ErrParentHidden) ||
+		stderrors.Is(err, comment.ErrDiscussionLocked) ||
+		stderrors.Is(err, comment.ErrBlocked)
+}
@@ -0,0 +1,764 @@
+package comment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubComments struct {
+	comments []*comment.Comment
+}
+
+func (s *stubComments) Create(ctx context.Context, c *comment.Comment) error {
+	s.comments = append(s.comments, c)
+	return nil
+}
+
+func (s *stubComments) FindByID(ctx context.Context, id uuid.UUID) (*comment.Comment, error) {
+	for _, c := range s.comments {
+		if c.ID() == id {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubComments) FindByRecipe(ctx context.Context, recipeID uuid.UUID) ([]*comment.Comment, error) {
+	var found []*comment.Comment
+	for _, c := range s.comments {
+		if c.RecipeID() == recipeID {
+			found = append(found, c)
+		}
+	}
+	return found, nil
+}
+
+func (s *stubComments) FindByEmailMessageID(ctx context.Context, messageID string) (*comment.Comment, error) {
+	for _, c := range s.comments {
+		if c.EmailMessageID() == messageID {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubComments) UpdateModeration(ctx context.Context, c *comment.Comment) error {
+	return nil
+}
+
+func (s *stubComments) UpdateContent(ctx context.Context, c *comment.Comment) error {
+	return nil
+}
+
+func (s *stubComments) FindByModerationStatus(ctx context.Context, status comment.ModerationStatus, limit int) ([]*comment.Comment, error) {
+	var found []*comment.Comment
+	for _, c := range s.comments {
+		if c.Status() == status {
+			found = append(found, c)
+		}
+	}
+	return found, nil
+}
+
+type memoryFeedback struct {
+	reactions []*comment.Reaction
+	votes     []*comment.HelpfulVote
+	reviews   []recipe.Rating
+}
+
+func (m *memoryFeedback) AddReaction(ctx context.Context, reaction *comment.Reaction) error {
+	for _, r := range m.reactions {
+		if r.CommentID == reaction.CommentID && r.UserID == reaction.UserID && r.Kind == reaction.Kind {
+			return nil
+		}
+	}
+	m.reactions = append(m.reactions, reaction)
+	return nil
+}
+
+func (m *memoryFeedback) RemoveReaction(ctx context.Context, commentID, userID uuid.UUID, kind comment.ReactionKind) error {
+	kept := m.reactions[:0]
+	for _, r := range m.reactions {
+		if r.CommentID != commentID || r.UserID != userID || r.Kind != kind {
+			kept = append(kept, r)
+		}
+	}
+	m.reactions = kept
+	return nil
+}
+
+func (m *memoryFeedback) CountReactions(ctx context.Context, commentIDs []uuid.UUID) (map[uuid.UUID]map[comment.ReactionKind]int, error) {
+	counts := make(map[uuid.UUID]map[comment.ReactionKind]int)
+	for _, r := range m.reactions {
+		if counts[r.CommentID] == nil {
+			counts[r.CommentID] = make(map[comment.ReactionKind]int)
+		}
+		counts[r.CommentID][r.Kind]++
+	}
+	return counts, nil
+}
+
+func (m *memoryFeedback) FindUserReactions(ctx context.Context, commentIDs []uuid.UUID, userID uuid.UUID) (map[uuid.UUID][]comment.ReactionKind, error) {
+	given := make(map[uuid.UUID][]comment.ReactionKind)
+	for _, r := range m.reactions {
+		if r.UserID == userID {
+			given[r.CommentID] = append(given[r.CommentID], r.Kind)
+		}
+	}
+	return given, nil
+}
+
+func (m *memoryFeedback) SaveHelpfulVote(ctx context.Context, vote *comment.HelpfulVote) error {
+	_ = m.RemoveHelpfulVote(ctx, vote.RecipeID, vote.ReviewerID, vote.VoterID)
+	m.votes = append(m.votes, vote)
+	return nil
+}
+
+func (m *memoryFeedback) RemoveHelpfulVote(ctx context.Context, recipeID, reviewerID, voterID uuid.UUID) error {
+	kept := m.votes[:0]
+	for _, v := range m.votes {
+		if v.RecipeID != recipeID || v.ReviewerID != reviewerID || v.VoterID != voterID {
+			kept = append(kept, v)
+		}
+	}
+	m.votes = kept
+	return nil
+}
+
+func (m *memoryFeedback) HelpfulTallies(ctx context.Context, recipeID uuid.UUID) (map[uuid.UUID]outbound.HelpfulTally, error) {
+	tallies := make(map[uuid.UUID]outbound.HelpfulTally)
+	for _, v := range m.votes {
+		tally := tallies[v.ReviewerID]
+		if v.Helpful {
+			tally.Helpful++
+		} else {
+			tally.NotHelpful++
+		}
+		tallies[v.ReviewerID] = tally
+	}
+	return tallies, nil
+}
+
+func (m *memoryFeedback) FindHelpfulVotes(ctx context.Context, recipeID, voterID uuid.UUID) (map[uuid.UUID]bool, error) {
+	votes := make(map[uuid.UUID]bool)
+	for _, v := range m.votes {
+		if v.VoterID == voterID {
+			votes[v.ReviewerID] = v.Helpful
+		}
+	}
+	return votes, nil
+}
+
+func (m *memoryFeedback) FindReviews(ctx context.Context, recipeID uuid.UUID) ([]recipe.Rating, error) {
+	return m.reviews, nil
+}
+
+func (m *memoryFeedback) FindEngagedRecipes(ctx context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+type memoryModeration struct {
+	discussions map[uuid.UUID]comment.Discussion
+	blocks      []comment.Block
+	events      []comment.ModerationEvent
+	flags       []comment.Flag
+}
+
+func (m *memoryModeration) FindDiscussion(ctx context.Context, recipeID uuid.UUID) (*comment.Discussion, error) {
+	if d, ok := m.discussions[recipeID]; ok {
+		return &d, nil
+	}
+	return nil, nil
+}
+
+func (m *memoryModeration) SaveDiscussion(ctx context.Context, discussion *comment.Discussion) error {
+	m.discussions[discussion.RecipeID] = *discussion
+	return nil
+}
+
+func (m *memoryModeration) SaveBlock(ctx context.Context, block *comment.Block) error {
+	m.blocks = append(m.blocks, *block)
+	return nil
+}
+
+func (m *memoryModeration) DeleteBlock(ctx context.Context, authorID, userID uuid.UUID) (bool, error) {
+	for i, b := range m.blocks {
+		if b.AuthorID == authorID && b.UserID == userID {
+			m.blocks = append(m.blocks[:i], m.blocks[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryModeration) IsBlocked(ctx context.Context, authorID, userID uuid.UUID) (bool, error) {
+	for _, b := range m.blocks {
+		if b.AuthorID == authorID && b.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryModeration) FindBlocks(ctx context.Context, authorID uuid.UUID) ([]comment.Block, error) {
+	return m.blocks, nil
+}
+
+func (m *memoryModeration) RecordEvent(ctx context.Context, event *comment.ModerationEvent) error {
+	m.events = append(m.events, *event)
+	return nil
+}
+
+func (m *memoryModeration) FindEvents(ctx context.Context, filter outbound.ModerationEventFilter) ([]comment.ModerationEvent, error) {
+	return m.events, nil
+}
+
+func (m *memoryModeration) SaveFlag(ctx context.Context, flag *comment.Flag) error {
+	for _, f := range m.flags {
+		if f.CommentID == flag.CommentID && f.UserID == flag.UserID {
+			return nil
+		}
+	}
+	m.flags = append(m.flags, *flag)
+	return nil
+}
+
+func (m *memoryModeration) FindFlagged(ctx context.Context, limit int) ([]outbound.FlaggedComment, error) {
+	var flagged []outbound.FlaggedComment
+	index := make(map[uuid.UUID]int)
+	for _, f := range m.flags {
+		i, ok := index[f.CommentID]
+		if !ok {
+			i = len(flagged)
+			index[f.CommentID] = i
+			flagged = append(flagged, outbound.FlaggedComment{CommentID: f.CommentID, Reasons: map[comment.FlagReason]int{}, FirstFlaggedAt: f.CreatedAt})
+		}
+		flagged[i].Flags++
+		flagged[i].Reasons[f.Reason]++
+	}
+	return flagged, nil
+}
+
+func (m *memoryModeration) ClearFlags(ctx context.Context, commentID uuid.UUID) error {
+	kept := m.flags[:0]
+	for _, f := range m.flags {
+		if f.CommentID != commentID {
+			kept = append(kept, f)
+		}
+	}
+	m.flags = kept
+	return nil
+}
+
+type stubTokens struct {
+	tokens map[uuid.UUID]outbound.ReplyToken
+}
+
+func (s *stubTokens) Create(ctx context.Context, token outbound.ReplyToken) error {
+	s.tokens[token.ID] = token
+	return nil
+}
+
+func (s *stubTokens) FindByID(ctx context.Context, id uuid.UUID) (*outbound.ReplyToken, error) {
+	if token, ok := s.tokens[id]; ok {
+		return &token, nil
+	}
+	return nil, nil
+}
+
+type stubSuppressions struct {
+	emails map[string]string
+}
+
+func (s *stubSuppressions) Suppress(ctx context.Context, email, reason, detail string) error {
+	s.emails[email] = reason
+	return nil
+}
+
+func (s *stubSuppressions) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	_, ok := s.emails[email]
+	return ok, nil
+}
+
+type stubRecipes struct {
+	outbound.RecipeRepository
+	recipe *recipe.Recipe
+}
+
+func (s *stubRecipes) FindByID(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error) {
+	if s.recipe != nil && s.recipe.ID() == id {
+		return s.recipe, nil
+	}
+	return nil, nil
+}
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+type stubMailer struct {
+	outbound.EmailService
+	sent []outbound.CommentNotificationEmail
+}
+
+func (s *stubMailer) SendCommentNotification(ctx context.Context, n outbound.CommentNotificationEmail) error {
+	s.sent = append(s.sent, n)
+	return nil
+}
+
+type fixture struct {
+	svc          *Service
+	comments     *stubComments
+	feedback     *memoryFeedback
+	moderation   *memoryModeration
+	tokens       *stubTokens
+	suppressions *stubSuppressions
+	mailer       *stubMailer
+	users        *stubUsers
+	recipe       *recipe.Recipe
+	author       *user.User
+	reviewer     *user.User
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	now := time.Now()
+	author := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	reviewer := user.ReconstructUser(uuid.New(), "grace@example.com", "Grace", "", true, true, user.UserRoleUser, now, now, nil)
+
+	entity, err := recipe.NewRecipe("Lemon Bars", "Bright, buttery squares.", author.ID())
+	require.NoError(t, err)
+
+	f := &fixture{
+		comments:     &stubComments{},
+		feedback:     &memoryFeedback{},
+		moderation:   &memoryModeration{discussions: map[uuid.UUID]comment.Discussion{}},
+		tokens:       &stubTokens{tokens: map[uuid.UUID]outbound.ReplyToken{}},
+		suppressions: &stubSuppressions{emails: map[string]string{}},
+		mailer:       &stubMailer{},
+		recipe:       entity,
+		author:       author,
+		reviewer:     reviewer,
+	}
+	f.users = &stubUsers{users: map[uuid.UUID]*user.User{author.ID(): author, reviewer.ID(): reviewer}}
+	f.svc = NewService(f.comments, f.feedback, f.moderation, f.tokens, f.suppressions, &stubRecipes{recipe: entity}, f.users, f.mailer, nil, Config{
+		ReplyDomain: "reply.alchemorsel.app",
+		ReplySecret: "s3cret",
+		SiteURL:     "https://alchemorsel.app/",
+	}, zap.NewNop())
+	return f
+}
+
+// reviewNotification has the reviewer rate the recipe and returns the
+// reply-to address the author was sent
+func (f *fixture) reviewNotification(t *testing.T) string {
+	t.Helper()
+	require.NoError(t, f.svc.NotifyReview(context.Background(), inbound.RateRecipeCommand{
+		RecipeID: f.recipe.ID(),
+		UserID:   f.reviewer.ID(),
+		Rating:   4,
+		Comment:  "Tart and lovely.",
+	}))
+	require.Len(t, f.mailer.sent, 1)
+	return f.mailer.sent[0].ReplyTo
+}
+
+func TestReplyAddressRoundTrip(t *testing.T) {
+	id := uuid.New()
+	address := replyAddress("s3cret", id, "reply.alchemorsel.app")
+	assert.LessOrEqual(t, len(address[:len(address)-len("@reply.alchemorsel.app")]), 64)
+
+	parsed, ok := parseReplyAddress("s3cret", "reply.alchemorsel.app", "Ada <"+address+">")
+	require.True(t, ok)
+	assert.Equal(t, id, parsed)
+
+	_, ok = parseReplyAddress("other", "reply.alchemorsel.app", address)
+	assert.False(t, ok, "a token signed with another secret is rejected")
+
+	tampered := replyAddress("s3cret", uuid.New(), "reply.alchemorsel.app")
+	forged := address[:len(replyLocalPrefix)+26] + tampered[len(replyLocalPrefix)+26:]
+	_, ok = parseReplyAddress("s3cret", "reply.alchemorsel.app", forged)
+	assert.False(t, ok, "an id with another token's signature is rejected")
+
+	_, ok = parseReplyAddress("s3cret", "evil.example.com", address)
+	assert.False(t, ok)
+}
+
+func TestExtractReply(t *testing.T) {
+	text := "Thanks Grace, glad you liked it!\r\n" +
+		"Try it with Meyer lemons next time.\r\n" +
+		"\r\n" +
+		"-- \r\n" +
+		"Ada\r\n"
+	assert.Equal(t, "Thanks Grace, glad you liked it!\nTry it with Meyer lemons next time.", ExtractReply(text))
+
+	text = "Will do!\n\nOn Mon, 5 Oct 2026 at 09:12, Alchemorsel <noreply@alchemorsel.app>\nwrote:\n> Grace rated \"Lemon Bars\" 4 out of 5"
+	assert.Equal(t, "Will do!", ExtractReply(text))
+
+	text = "Inline answer\n> quoted\nmore\n\nSent from my iPhone"
+	assert.Equal(t, "Inline answer\nmore", ExtractReply(text))
+
+	assert.Equal(t, "", ExtractReply("> only quoted text"))
+}
+
+func TestInboundReplyPostsThreadedResponse(t *testing.T) {
+	f := newFixture(t)
+	address := f.reviewNotification(t)
+	assert.Equal(t, "https://alchemorsel.app/recipes/"+f.recipe.ID().String(), f.mailer.sent[0].RecipeURL)
+	assert.Equal(t, 4, f.mailer.sent[0].Rating)
+
+	email := inbound.InboundEmail{
+		From:      "Ada <ADA@example.com>",
+		To:        []string{address},
+		MessageID: "<abc@mail.example.com>",
+		Text:      "Thank you!\n\nOn Mon, Grace wrote:\n> Tart and lovely.",
+	}
+	result, err := f.svc.HandleInboundReply(context.Background(), email)
+	require.NoError(t, err)
+	require.Equal(t, inbound.InboundReplyPosted, result.Status, result.Reason)
+	assert.Equal(t, "Thank you!", result.Comment.Content)
+	assert.Equal(t, f.reviewer.ID(), *result.Comment.ReviewUserID)
+	assert.Equal(t, string(comment.SourceEmail), result.Comment.Source)
+
+	// The reviewer hears about the response and can answer it in turn
+	require.Len(t, f.mailer.sent, 2)
+	assert.Equal(t, "grace@example.com", f.mailer.sent[1].To)
+	assert.NotEmpty(t, f.mailer.sent[1].ReplyTo)
+
+	result, err = f.svc.HandleInboundReply(context.Background(), email)
+	require.NoError(t, err)
+	assert.Equal(t, inbound.InboundReplyDuplicate, result.Status)
+	assert.Len(t, f.comments.comments, 1)
+
+	result, err = f.svc.HandleInboundReply(context.Background(), inbound.InboundEmail{
+		From:      "grace@example.com",
+		To:        []string{f.mailer.sent[1].ReplyTo},
+		MessageID: "<def@mail.example.com>",
+		Text:      "Will try!",
+	})
+	require.NoError(t, err)
+	require.Equal(t, inbound.InboundReplyPosted, result.Status, result.Reason)
+	assert.Equal(t, f.comments.comments[0].ID(), *result.Comment.ParentID)
+
+	threads, err := f.svc.ListComments(context.Background(), f.recipe.ID(), uuid.Nil)
+	require.NoError(t, err)
+	require.Len(t, threads, 1)
+	require.Len(t, threads[0].Replies, 1)
+	assert.Equal(t, "Grace", threads[0].Replies[0].AuthorName)
+}
+
+func TestInboundReplyRejections(t *testing.T) {
+	f := newFixture(t)
+	address := f.reviewNotification(t)
+	score := 7.5
+
+	tests := []struct {
+		name   string
+		email  inbound.InboundEmail
+		status string
+		reason string
+	}{
+		{
+			name:   "auto-reply",
+			email:  inbound.InboundEmail{From: "ada@example.com", To: []string{address}, Text: "Out of office", Headers: map[string]string{"auto-submitted": "auto-replied"}},
+			status: inbound.InboundReplyIgnored,
+			reason: "auto-reply",
+		},
+		{
+			name:   "spam",
+			email:  inbound.InboundEmail{From: "ada@example.com", To: []string{address}, Text: "Buy now", SpamScore: &score},
+			status: inbound.InboundReplyRejected,
+			reason: "spam",
+		},
+		{
+			name:   "forged sender",
+			email:  inbound.InboundEmail{From: "mallory@example.com", To: []string{address}, Text: "Hi"},
+			status: inbound.InboundReplyRejected,
+			reason: "sender does not match the notified address",
+		},
+		{
+			name:   "unsigned address",
+			email:  inbound.InboundEmail{From: "ada@example.com", To: []string{"reply+aaaa@reply.alchemorsel.app"}, Text: "Hi"},
+			status: inbound.InboundReplyRejected,
+			reason: "invalid reply address",
+		},
+		{
+			name:   "empty reply",
+			email:  inbound.InboundEmail{From: "ada@example.com", To: []string{address}, Text: "> quoted only"},
+			status: inbound.InboundReplyRejected,
+			reason: comment.ErrEmptyContent.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := f.svc.HandleInboundReply(context.Background(), tt.email)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, result.Status)
+			assert.Equal(t, tt.reason, result.Reason)
+		})
+	}
+	assert.Empty(t, f.comments.comments)
+
+	f.svc.now = func() time.Time { return time.Now().Add(DefaultTokenTTL + time.Hour) }
+	result, err := f.svc.HandleInboundReply(context.Background(), inbound.InboundEmail{From: "ada@example.com", To: []string{address}, Text: "Late"})
+	require.NoError(t, err)
+	assert.Equal(t, "reply address expired", result.Reason)
+}
+
+func TestBouncesSuppressNotifications(t *testing.T) {
+	f := newFixture(t)
+	address := f.reviewNotification(t)
+
+	result, err := f.svc.HandleInboundReply(context.Background(), inbound.InboundEmail{
+		From: "MAILER-DAEMON@mx.example.com",
+		To:   []string{address},
+		Text: "Delivery to ada@example.com failed permanently.",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, inbound.InboundReplyBounced, result.Status)
+	assert.Equal(t, outbound.SuppressionBounce, f.suppressions.emails["ada@example.com"])
+
+	f.reviewNotification(t)
+	assert.Len(t, f.mailer.sent, 1, "suppressed addresses are not emailed again")
+
+	require.NoError(t, f.svc.HandleDeliveryEvent(context.Background(), inbound.EmailDeliveryEvent{Type: inbound.EmailEventBounce, Email: "grace@example.com"}))
+	assert.NotContains(t, f.suppressions.emails, "grace@example.com", "soft bounces do not suppress")
+
+	require.NoError(t, f.svc.HandleDeliveryEvent(context.Background(), inbound.EmailDeliveryEvent{Type: inbound.EmailEventComplaint, Email: "grace@example.com"}))
+	assert.Equal(t, outbound.SuppressionComplaint, f.suppressions.emails["grace@example.com"])
+}
+
+func TestReactionsAndHelpfulVotes(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	require.NoError(t, f.recipe.AddIngredient(recipe.Ingredient{Name: "lemons", Amount: 3, Unit: recipe.MeasurementUnitPiece}))
+	require.NoError(t, f.recipe.AddInstruction(recipe.Instruction{Description: "Bake."}))
+	require.NoError(t, f.recipe.SetServings(9))
+	require.NoError(t, f.recipe.Publish())
+
+	// Reactions: never to your own comment, and each kind once
+	posted, err := f.svc.AddComment(ctx, inbound.AddCommentCommand{RecipeID: f.recipe.ID(), UserID: f.reviewer.ID(), Content: "Tangy!"})
+	require.NoError(t, err)
+	react := inbound.ReactCommand{RecipeID: f.recipe.ID(), CommentID: posted.ID, UserID: f.reviewer.ID(), Kind: "heart"}
+	_, err = f.svc.React(ctx, react)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	react.UserID = f.author.ID()
+	_, err = f.svc.React(ctx, react)
+	require.NoError(t, err)
+	reactions, err := f.svc.React(ctx, react)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"heart": 1}, reactions.Reactions)
+
+	threads, err := f.svc.ListComments(ctx, f.recipe.ID(), f.author.ID())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"heart"}, threads[0].MyReactions)
+
+	// Helpful votes: not on your own review, and not by the recipe author
+	reader, critic := uuid.New(), uuid.New()
+	now := time.Now()
+	f.feedback.reviews = []recipe.Rating{
+		{UserID: f.reviewer.ID(), Value: 5, Comment: "Perfect", CreatedAt: now.Add(-time.Hour)},
+		{UserID: critic, Value: 2, Comment: "Too sour", CreatedAt: now},
+	}
+	vote := inbound.ReviewVoteCommand{RecipeID: f.recipe.ID(), ReviewerID: f.reviewer.ID(), VoterID: f.reviewer.ID(), Helpful: true}
+	_, err = f.svc.VoteReview(ctx, vote)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	vote.VoterID = f.author.ID()
+	_, err = f.svc.VoteReview(ctx, vote)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	vote.VoterID = reader
+	review, err := f.svc.VoteReview(ctx, vote)
+	require.NoError(t, err)
+	assert.Equal(t, 1, review.Helpful)
+	assert.Equal(t, "helpful", review.MyVote)
+
+	reviews, err := f.svc.ListReviews(ctx, inbound.ListReviewsQuery{RecipeID: f.recipe.ID()})
+	require.NoError(t, err)
+	require.Len(t, reviews, 2)
+	assert.Equal(t, f.reviewer.ID(), reviews[0].ReviewerID)
+	reviews, err = f.svc.ListReviews(ctx, inbound.ListReviewsQuery{RecipeID: f.recipe.ID(), Sort: inbound.ReviewSortNewest})
+	require.NoError(t, err)
+	assert.Equal(t, critic, reviews[0].ReviewerID)
+
+	review, err = f.svc.ClearReviewVote(ctx, vote)
+	require.NoError(t, err)
+	assert.Zero(t, review.Helpful)
+	assert.Empty(t, review.MyVote)
+}
+
+func TestAuthorModeration(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	now := time.Now()
+	admin := user.ReconstructUser(uuid.New(), "mod@example.com", "Mod", "", true, true, user.UserRoleAdmin, now, now, nil)
+	f.users.users[admin.ID()] = admin
+	require.NoError(t, f.recipe.AddIngredient(recipe.Ingredient{Name: "lemons", Amount: 3, Unit: recipe.MeasurementUnitPiece}))
+	require.NoError(t, f.recipe.AddInstruction(recipe.Instruction{Description: "Bake."}))
+	require.NoError(t, f.recipe.SetServings(9))
+	require.NoError(t, f.recipe.Publish())
+
+	posted, err := f.svc.AddComment(ctx, inbound.AddCommentCommand{RecipeID: f.recipe.ID(), UserID: f.reviewer.ID(), Content: "Rude remark"})
+	require.NoError(t, err)
+
+	// Only the recipe's author hides comments; others then see a blank one
+	hide := inbound.HideCommentCommand{RecipeID: f.recipe.ID(), CommentID: posted.ID, UserID: f.reviewer.ID(), Reason: "off-topic"}
+	_, err = f.svc.HideComment(ctx, hide)
+	assert.True(t, errors.Is(err, errors.CodeForbidden))
+	hide.UserID = f.author.ID()
+	hidden, err := f.svc.HideComment(ctx, hide)
+	require.NoError(t, err)
+	assert.Equal(t, "hidden", hidden.Status)
+
+	threads, err := f.svc.ListComments(ctx, f.recipe.ID(), uuid.Nil)
+	require.NoError(t, err)
+	assert.Empty(t, threads[0].Content)
+	threads, err = f.svc.ListComments(ctx, f.recipe.ID(), f.reviewer.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Rude remark", threads[0].Content)
+
+	parentID := posted.ID
+	_, err = f.svc.AddComment(ctx, inbound.AddCommentCommand{RecipeID: f.recipe.ID(), UserID: f.author.ID(), ParentID: &parentID, Content: "No"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest), "hidden comments cannot be answered")
+
+	// Moderators restore or remove what authors hid
+	review := inbound.ReviewHiddenCommentCommand{CommentID: posted.ID, ModeratorID: f.author.ID()}
+	_, err = f.svc.ReviewHiddenComment(ctx, review)
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	review.ModeratorID = admin.ID()
+	queue, err := f.svc.ListHiddenComments(ctx, admin.ID(), 0)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	removed, err := f.svc.ReviewHiddenComment(ctx, review)
+	require.NoError(t, err)
+	assert.Equal(t, "removed", removed.Status)
+	_, err = f.svc.ReviewHiddenComment(ctx, review)
+	assert.True(t, errors.Is(err, errors.CodeConflict))
+
+	// Locked comments are closed to everyone but the author
+	_, err = f.svc.LockDiscussion(ctx, inbound.LockDiscussionCommand{RecipeID: f.recipe.ID(), UserID: f.author.ID(), Locked: true})
+	require.NoError(t, err)
+	_, err = f.svc.AddComment(ctx, inbound.AddCommentCommand{RecipeID: f.recipe.ID(), UserID: f.reviewer.ID(), Content: "Hello?"})
+	assert.True(t, errors.Is(err, errors.CodeForbidden))
+	_, err = f.svc.AddComment(ctx, inbound.AddCommentCommand{RecipeID: f.recipe.ID(), UserID: f.author.ID(), Content: "Closing this thread."})
+	require.NoError(t, err)
+	_, err = f.svc.LockDiscussion(ctx, inbound.LockDiscussionCommand{RecipeID: f.recipe.ID(), UserID: f.author.ID(), Locked: false})
+	require.NoError(t, err)
+
+	// Blocked users cannot comment on any of the author's recipes
+	_, err = f.svc.BlockCommenter(ctx, inbound.BlockCommenterCommand{AuthorID: f.author.ID(), UserID: f.author.ID()})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	_, err = f.svc.BlockCommenter(ctx, inbound.BlockCommenterCommand{AuthorID: f.author.ID(), UserID: f.reviewer.ID(), Reason: "spam"})
+	require.NoError(t, err)
+	_, err = f.svc.AddComment(ctx, inbound.AddCommentCommand{RecipeID: f.recipe.ID(), UserID: f.reviewer.ID(), Content: "Let me in"})
+	assert.True(t, errors.Is(err, errors.CodeForbidden))
+
+	require.NoError(t, f.svc.UnblockCommenter(ctx, inbound.BlockCommenterCommand{AuthorID: f.author.ID(), UserID: f.reviewer.ID()}))
+	_, err = f.svc.AddComment(ctx, inbound.AddCommentCommand{RecipeID: f.recipe.ID(), UserID: f.reviewer.ID(), Content: "Thanks"})
+	require.NoError(t, err)
+
+	actions := make([]comment.Action, len(f.moderation.events))
+	for i, event := range f.moderation.events {
+		actions[i] = event.Action
+	}
+	assert.Equal(t, []comment.Action{
+		comment.ActionHide, comment.ActionRemove, comment.ActionLock, comment.ActionUnlock, comment.ActionBlock, comment.ActionUnblock,
+	}, actions)
+}
+
+func TestEditDeleteAndFlagComments(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	now := time.Now()
+	admin := user.ReconstructUser(uuid.New(), "mod@example.com", "Mod", "", true, true, user.UserRoleAdmin, now, now, nil)
+	f.users.users[admin.ID()] = admin
+	require.NoError(t, f.recipe.AddIngredient(recipe.Ingredient{Name: "lemons", Amount: 3, Unit: recipe.MeasurementUnitPiece}))
+	require.NoError(t, f.recipe.AddInstruction(recipe.Instruction{Description: "Bake."}))
+	require.NoError(t, f.recipe.SetServings(9))
+	require.NoError(t, f.recipe.Publish())
+
+	root, err := f.svc.AddComment(ctx, inbound.AddCommentCommand{RecipeID: f.recipe.ID(), UserID: f.reviewer.ID(), Content: "Too sour?"})
+	require.NoError(t, err)
+	reply, err := f.svc.AddComment(ctx, inbound.AddCommentCommand{RecipeID: f.recipe.ID(), UserID: f.author.ID(), ParentID: &root.ID, Content: "Add sugar."})
+	require.NoError(t, err)
+	nested, err := f.svc.AddComment(ctx, inbound.AddCommentCommand{RecipeID: f.recipe.ID(), UserID: f.reviewer.ID(), ParentID: &reply.ID, Content: "Thanks!"})
+	require.NoError(t, err)
+	assert.Equal(t, root.ID, *nested.ParentID, "replies to replies join the top-level thread")
+
+	// Only the author edits
+	edit := inbound.EditCommentCommand{RecipeID: f.recipe.ID(), CommentID: root.ID, UserID: f.author.ID(), Content: "Edited"}
+	_, err = f.svc.EditComment(ctx, edit)
+	assert.True(t, errors.Is(err, errors.CodeForbidden))
+	edit.UserID, edit.Content = f.reviewer.ID(), "Too sour for me?"
+	edited, err := f.svc.EditComment(ctx, edit)
+	require.NoError(t, err)
+	assert.Equal(t, "Too sour for me?", edited.Content)
+	assert.NotNil(t, edited.EditedAt)
+
+	// Readers flag others' comments once; moderators hide or dismiss
+	flag := inbound.FlagCommentCommand{RecipeID: f.recipe.ID(), CommentID: reply.ID, UserID: f.author.ID(), Reason: "spam"}
+	assert.True(t, errors.Is(f.svc.FlagComment(ctx, flag), errors.CodeBadRequest), "authors cannot flag their own comment")
+	flag.UserID = f.reviewer.ID()
+	require.NoError(t, f.svc.FlagComment(ctx, flag))
+	require.NoError(t, f.svc.FlagComment(ctx, flag))
+	assert.True(t, errors.Is(f.svc.FlagComment(ctx, inbound.FlagCommentCommand{RecipeID: f.recipe.ID(), CommentID: root.ID, UserID: f.author.ID(), Reason: "rude"}), errors.CodeBadRequest))
+
+	_, err = f.svc.ListFlaggedComments(ctx, f.author.ID(), 0)
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	queue, err := f.svc.ListFlaggedComments(ctx, admin.ID(), 0)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	assert.Equal(t, 1, queue[0].Flags)
+	assert.Equal(t, map[string]int{"spam": 1}, queue[0].Reasons)
+
+	hidden, err := f.svc.ReviewFlaggedComment(ctx, inbound.ReviewFlaggedCommentCommand{CommentID: reply.ID, ModeratorID: admin.ID(), Hide: true, Reason: "spam"})
+	require.NoError(t, err)
+	assert.Equal(t, "removed", hidden.Status)
+	queue, err = f.svc.ListFlaggedComments(ctx, admin.ID(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, queue)
+
+	// Deleting keeps a blank in the thread while it has replies
+	del := inbound.DeleteCommentCommand{RecipeID: f.recipe.ID(), CommentID: root.ID, UserID: f.author.ID()}
+	assert.True(t, errors.Is(f.svc.DeleteComment(ctx, del), errors.CodeInsufficientPermissions))
+	del.UserID = f.reviewer.ID()
+	require.NoError(t, f.svc.DeleteComment(ctx, del))
+	threads, err := f.svc.ListComments(ctx, f.recipe.ID(), f.reviewer.ID())
+	require.NoError(t, err)
+	require.Len(t, threads, 1)
+	assert.Equal(t, "deleted", threads[0].Status)
+	assert.Empty(t, threads[0].Content)
+	assert.Len(t, threads[0].Replies, 2)
+
+	_, err = f.svc.EditComment(ctx, edit)
+	assert.True(t, errors.Is(err, errors.CodeConflict), "deleted comments cannot be edited")
+	require.NoError(t, f.svc.DeleteComment(ctx, inbound.DeleteCommentCommand{RecipeID: f.recipe.ID(), CommentID: nested.ID, UserID: admin.ID()}))
+	require.NoError(t, f.svc.DeleteComment(ctx, inbound.DeleteCommentCommand{RecipeID: f.recipe.ID(), CommentID: reply.ID, UserID: f.author.ID()}))
+	threads, err = f.svc.ListComments(ctx, f.recipe.ID(), uuid.Nil)
+	require.NoError(t, err)
+	assert.Empty(t, threads, "deleted comments without replies are dropped")
+
+	actions := make([]comment.Action, len(f.moderation.events))
+	for i, event := range f.moderation.events {
+		actions[i] = event.Action
+	}
+	assert.Equal(t, []comment.Action{comment.ActionRemove, comment.ActionDelete}, actions)
+}
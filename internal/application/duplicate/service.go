@@ -0,0 +1,402 @@
+// Package duplicate checks recipes after they are published against the
+// recipes of other authors and keeps the flags on near copies for the
+// admins. Recipes at Config.FlagSimilarity or more are flagged; recipes
+// at Config.ArchiveSimilarity or more are also taken down until an admin
+// reviews them. Authors can appeal an upheld flag once, and an admin other
+// than the one who upheld it decides.
+package duplicate
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/application/embedding"
+	"github.com/alchemorsel/v3/internal/domain/duplicate"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	// checkBatchSize is how many queued recipes one run checks
+	checkBatchSize = 20
+)
+
+// Config holds the cosine similarities the check acts on
+type Config struct {
+	// FlagSimilarity opens a flag for the admins to review
+	FlagSimilarity float64
+	// ArchiveSimilarity also takes the recipe down until they do
+	ArchiveSimilarity float64
+}
+
+// Service implements inbound.DuplicateService
+type Service struct {
+	flags      outbound.DuplicateRepository
+	recipeRepo outbound.RecipeRepository
+	recipes    inbound.RecipeService
+	embeddings outbound.RecipeEmbeddingRepository
+	ai         outbound.AIService
+	userRepo   outbound.UserRepository
+	config     Config
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewService creates the duplicate service
+func NewService(
+	flags outbound.DuplicateRepository,
+	recipeRepo outbound.RecipeRepository,
+	recipes inbound.RecipeService,
+	embeddings outbound.RecipeEmbeddingRepository,
+	ai outbound.AIService,
+	userRepo outbound.UserRepository,
+	config Config,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		flags:      flags,
+		recipeRepo: recipeRepo,
+		recipes:    recipes,
+		embeddings: embeddings,
+		ai:         ai,
+		userRepo:   userRepo,
+		config:     config,
+		logger:     logger.Named("duplicate"),
+		now:        time.Now,
+	}
+}
+
+// CheckQueued checks a batch of the recipes publishing queued. A recipe
+// whose check fails stays queued for the next run.
+func (s *Service) CheckQueued(ctx context.Context) (int, error) {
+	queued, err := s.flags.DueChecks(ctx, checkBatchSize)
+	if err != nil {
+		return 0, errors.NewDatabaseError("find queued duplicate checks", err)
+	}
+	for i, recipeID := range queued {
+		if err := s.check(ctx, recipeID); err != nil {
+			return i, err
+		}
+		if err := s.flags.CompleteCheck(ctx, recipeID); err != nil {
+			return i, errors.NewDatabaseError("complete duplicate check", err)
+		}
+	}
+	return len(queued), nil
+}
+
+// check compares a recipe with the nearest published recipe by another
+// author. The recipe is embedded here rather than by the embedding
+// refresh, which runs on an interval and may not have reached it yet.
+func (s *Service) check(ctx context.Context, recipeID uuid.UUID) error {
+	r, err := s.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return errors.NewDatabaseError("find recipe", err)
+	}
+	// Recipes unpublished or deleted since they were queued have nothing
+	// left to copy from
+	if r == nil || r.Status() != recipe.RecipeStatusPublished {
+		return nil
+	}
+
+	result, err := s.ai.Embed(ctx, []string{embedding.RecipeText(r)})
+	if err != nil {
+		return errors.NewExternalServiceError("ai", err)
+	}
+	if len(result.Vectors) != 1 {
+		return errors.NewExternalServiceError("ai", fmt.Errorf("%d vectors for 1 recipe", len(result.Vectors)))
+	}
+	vector := result.Vectors[0]
+	err = s.embeddings.Save(ctx, outbound.RecipeEmbedding{
+		RecipeID:        r.ID(),
+		Model:           result.Model,
+		Vector:          vector,
+		RecipeUpdatedAt: r.UpdatedAt(),
+	})
+	if err != nil {
+		return errors.NewDatabaseError("save recipe embedding", err)
+	}
+
+	authorID := r.AuthorID()
+	matches, err := s.embeddings.FindNearest(ctx, outbound.SearchCriteria{ExcludeAuthorID: &authorID}, result.Model, vector, 1)
+	if err != nil {
+		return errors.NewDatabaseError("find similar recipes", err)
+	}
+	if len(matches) == 0 || matches[0].Similarity < s.config.FlagSimilarity {
+		return nil
+	}
+	match := matches[0]
+
+	// A recipe published again after its flag was reviewed is not flagged
+	// twice for the same original
+	flagged, err := s.flags.HasFlag(ctx, r.ID(), match.RecipeID)
+	if err != nil {
+		return errors.NewDatabaseError("find duplicate flags", err)
+	}
+	if flagged {
+		return nil
+	}
+	original, err := s.recipeRepo.FindByID(ctx, match.RecipeID)
+	if err != nil {
+		return errors.NewDatabaseError("find recipe", err)
+	}
+	if original == nil {
+		return nil
+	}
+
+	archived := match.Similarity >= s.config.ArchiveSimilarity
+	if archived {
+		if err := s.recipes.TakeDownRecipe(ctx, r.ID()); err != nil {
+			if !errors.Is(err, errors.CodeConflict) && !errors.Is(err, errors.CodeNotFound) {
+				return err
+			}
+			archived = false
+		}
+	}
+
+	f := duplicate.NewFlag(r.ID(), original.ID(), authorID, match.Similarity,
+		duplicate.SharedLines(ingredientNames(r), ingredientNames(original)),
+		duplicate.SharedLines(steps(r), steps(original)),
+		archived, s.now().UTC())
+	if err := s.flags.Create(ctx, f); err != nil {
+		return errors.NewDatabaseError("save duplicate flag", err)
+	}
+	s.logger.Warn("Recipe flagged as a near duplicate",
+		zap.String("recipe_id", f.RecipeID.String()),
+		zap.String("original_id", f.OriginalID.String()),
+		zap.Float64("similarity", f.Similarity),
+		zap.Bool("archived", f.Archived),
+	)
+	return nil
+}
+
+// ListFlags pages through the flags awaiting an admin, oldest first
+func (s *Service) ListFlags(ctx context.Context, requesterID uuid.UUID, limit, offset int) (*inbound.DuplicateFlagPage, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "review duplicate flags"); err != nil {
+		return nil, err
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	limit = pageSize(limit)
+	rows, total, err := s.flags.FindAwaitingReview(ctx, offset, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list duplicate flags", err)
+	}
+
+	page := &inbound.DuplicateFlagPage{
+		Flags:  make([]inbound.DuplicateFlagDTO, len(rows)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	var ids []uuid.UUID
+	for i, f := range rows {
+		page.Flags[i] = flagToDTO(f)
+		ids = append(ids, f.RecipeID, f.OriginalID)
+	}
+	if len(ids) == 0 {
+		return page, nil
+	}
+
+	recipes, err := s.recipeRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipes by ids", err)
+	}
+	titles := make(map[uuid.UUID]string, len(recipes))
+	for _, r := range recipes {
+		titles[r.ID()] = r.Title()
+	}
+	for i := range page.Flags {
+		page.Flags[i].RecipeTitle = titles[page.Flags[i].RecipeID]
+		page.Flags[i].OriginalTitle = titles[page.Flags[i].OriginalID]
+	}
+	return page, nil
+}
+
+// ResolveFlag upholds a flag, archiving the recipe if it is still live, or
+// dismisses it, putting back a recipe the check took down
+func (s *Service) ResolveFlag(ctx context.Context, cmd inbound.ResolveDuplicateCommand) (*inbound.DuplicateFlagDTO, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, cmd.ModeratorID, "review duplicate flags"); err != nil {
+		return nil, err
+	}
+	f, err := s.findFlag(ctx, cmd.FlagID)
+	if err != nil {
+		return nil, err
+	}
+
+	verdict := duplicate.StatusDismissed
+	if cmd.Uphold {
+		verdict = duplicate.StatusUpheld
+	}
+	if err := f.Close(verdict, cmd.ModeratorID, cmd.Note, s.now().UTC()); err != nil {
+		if stderrors.Is(err, duplicate.ErrNotOpen) {
+			return nil, errors.NewConflictError(err.Error())
+		}
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	if cmd.Uphold && !f.Archived {
+		err = s.moderate(ctx, s.recipes.TakeDownRecipe, f.RecipeID)
+	} else if !cmd.Uphold && f.Archived {
+		err = s.moderate(ctx, s.recipes.ReinstateRecipe, f.RecipeID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.flags.Update(ctx, f); err != nil {
+		return nil, errors.NewDatabaseError("update duplicate flag", err)
+	}
+
+	s.logger.Info("Duplicate flag reviewed",
+		zap.String("moderator_id", cmd.ModeratorID.String()),
+		zap.String("flag_id", f.ID.String()),
+		zap.String("recipe_id", f.RecipeID.String()),
+		zap.String("verdict", string(verdict)),
+	)
+	dto := flagToDTO(f)
+	return &dto, nil
+}
+
+// AppealFlag files the author's appeal of their recipe's latest flag
+func (s *Service) AppealFlag(ctx context.Context, cmd inbound.AppealDuplicateCommand) (*inbound.DuplicateFlagDTO, error) {
+	f, err := s.flags.FindLatestForRecipe(ctx, cmd.RecipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find duplicate flag", err)
+	}
+	// Other users are not told whether a recipe was flagged
+	if f == nil || f.AuthorID != cmd.AuthorID {
+		return nil, errors.NewNotFoundError("duplicate flag")
+	}
+
+	if err := f.FileAppeal(cmd.AuthorID, cmd.Note, s.now().UTC()); err != nil {
+		if stderrors.Is(err, duplicate.ErrNotUpheld) || stderrors.Is(err, duplicate.ErrAlreadyAppealed) {
+			return nil, errors.NewConflictError(err.Error())
+		}
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	if err := s.flags.Update(ctx, f); err != nil {
+		return nil, errors.NewDatabaseError("update duplicate flag", err)
+	}
+
+	s.logger.Info("Duplicate flag appealed",
+		zap.String("flag_id", f.ID.String()),
+		zap.String("recipe_id", f.RecipeID.String()),
+	)
+	dto := flagToDTO(f)
+	return &dto, nil
+}
+
+// DecideAppeal grants an appeal, restoring the recipe, or denies it
+func (s *Service) DecideAppeal(ctx context.Context, cmd inbound.DecideDuplicateAppealCommand) (*inbound.DuplicateFlagDTO, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, cmd.ModeratorID, "decide duplicate appeals"); err != nil {
+		return nil, err
+	}
+	f, err := s.findFlag(ctx, cmd.FlagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.DecideAppeal(cmd.Grant, cmd.ModeratorID, s.now().UTC()); err != nil {
+		if stderrors.Is(err, duplicate.ErrSameModerator) {
+			return nil, errors.NewInsufficientPermissionsError("decide the appeal of a flag you upheld")
+		}
+		return nil, errors.NewConflictError(err.Error())
+	}
+	if cmd.Grant {
+		if err := s.moderate(ctx, s.recipes.ReinstateRecipe, f.RecipeID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.flags.Update(ctx, f); err != nil {
+		return nil, errors.NewDatabaseError("update duplicate flag", err)
+	}
+
+	s.logger.Info("Duplicate appeal decided",
+		zap.String("moderator_id", cmd.ModeratorID.String()),
+		zap.String("flag_id", f.ID.String()),
+		zap.String("recipe_id", f.RecipeID.String()),
+		zap.String("appeal", string(f.Appeal)),
+	)
+	dto := flagToDTO(f)
+	return &dto, nil
+}
+
+// moderate takes a recipe down or puts it back. A recipe already in the
+// wanted state, or deleted by its author, is left alone.
+func (s *Service) moderate(ctx context.Context, transition func(context.Context, uuid.UUID) error, recipeID uuid.UUID) error {
+	err := transition(ctx, recipeID)
+	if err != nil && !errors.Is(err, errors.CodeConflict) && !errors.Is(err, errors.CodeNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *Service) findFlag(ctx context.Context, id uuid.UUID) (*duplicate.Flag, error) {
+	f, err := s.flags.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find duplicate flag", err)
+	}
+	if f == nil {
+		return nil, errors.NewNotFoundError("duplicate flag")
+	}
+	return f, nil
+}
+
+func ingredientNames(r *recipe.Recipe) []string {
+	names := make([]string, len(r.Ingredients()))
+	for i, ing := range r.Ingredients() {
+		names[i] = ing.Name
+	}
+	return names
+}
+
+func steps(r *recipe.Recipe) []string {
+	lines := make([]string, len(r.Instructions()))
+	for i, step := range r.Instructions() {
+		lines[i] = step.Description
+	}
+	return lines
+}
+
+func flagToDTO(f *duplicate.Flag) inbound.DuplicateFlagDTO {
+	return inbound.DuplicateFlagDTO{
+		ID:                f.ID,
+		RecipeID:          f.RecipeID,
+		OriginalID:        f.OriginalID,
+		AuthorID:          f.AuthorID,
+		Similarity:        f.Similarity,
+		SharedIngredients: f.SharedIngredients,
+		SharedSteps:       f.SharedSteps,
+		Archived:          f.Archived,
+		Status:            string(f.Status),
+		ResolvedBy:        f.ResolvedBy,
+		Resolution:        f.Resolution,
+		ResolvedAt:        f.ResolvedAt,
+		Appeal:            string(f.Appeal),
+		AppealNote:        f.AppealNote,
+		AppealedAt:        f.AppealedAt,
+		AppealDecidedBy:   f.AppealDecidedBy,
+		AppealDecidedAt:   f.AppealDecidedAt,
+		CreatedAt:         f.CreatedAt,
+	}
+}
+
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
@@ -0,0 +1,381 @@
+package duplicate
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/duplicate"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memoryFlags struct {
+	queue []uuid.UUID
+	flags []*duplicate.Flag
+}
+
+func (m *memoryFlags) EnqueueCheck(ctx context.Context, recipeID uuid.UUID, at time.Time) error {
+	for _, id := range m.queue {
+		if id == recipeID {
+			return nil
+		}
+	}
+	m.queue = append(m.queue, recipeID)
+	return nil
+}
+
+func (m *memoryFlags) DueChecks(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	if len(m.queue) > limit {
+		return append([]uuid.UUID(nil), m.queue[:limit]...), nil
+	}
+	return append([]uuid.UUID(nil), m.queue...), nil
+}
+
+func (m *memoryFlags) CompleteCheck(ctx context.Context, recipeID uuid.UUID) error {
+	for i, id := range m.queue {
+		if id == recipeID {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *memoryFlags) Create(ctx context.Context, f *duplicate.Flag) error {
+	copied := *f
+	m.flags = append(m.flags, &copied)
+	return nil
+}
+
+func (m *memoryFlags) FindByID(ctx context.Context, id uuid.UUID) (*duplicate.Flag, error) {
+	for _, f := range m.flags {
+		if f.ID == id {
+			copied := *f
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryFlags) FindLatestForRecipe(ctx context.Context, recipeID uuid.UUID) (*duplicate.Flag, error) {
+	for i := len(m.flags) - 1; i >= 0; i-- {
+		if m.flags[i].RecipeID == recipeID {
+			copied := *m.flags[i]
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryFlags) HasFlag(ctx context.Context, recipeID, originalID uuid.UUID) (bool, error) {
+	for _, f := range m.flags {
+		if f.RecipeID == recipeID && f.OriginalID == originalID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryFlags) FindAwaitingReview(ctx context.Context, offset, limit int) ([]*duplicate.Flag, int64, error) {
+	var waiting []*duplicate.Flag
+	for _, f := range m.flags {
+		if f.Status == duplicate.StatusOpen || f.Appeal == duplicate.AppealPending {
+			copied := *f
+			waiting = append(waiting, &copied)
+		}
+	}
+	return waiting, int64(len(waiting)), nil
+}
+
+func (m *memoryFlags) Update(ctx context.Context, f *duplicate.Flag) error {
+	for i, stored := range m.flags {
+		if stored.ID == f.ID {
+			copied := *f
+			m.flags[i] = &copied
+		}
+	}
+	return nil
+}
+
+// catalogue backs both the recipe repository and the recipe service, so
+// takedowns are seen by the next check
+type catalogue struct {
+	recipes map[uuid.UUID]*recipe.Recipe
+}
+
+type stubRecipeRepo struct {
+	outbound.RecipeRepository
+	*catalogue
+}
+
+func (s stubRecipeRepo) FindByID(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error) {
+	return s.recipes[id], nil
+}
+
+func (s stubRecipeRepo) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*recipe.Recipe, error) {
+	var found []*recipe.Recipe
+	for _, id := range ids {
+		if r, ok := s.recipes[id]; ok {
+			found = append(found, r)
+		}
+	}
+	return found, nil
+}
+
+type stubRecipes struct {
+	inbound.RecipeService
+	*catalogue
+}
+
+func (s stubRecipes) TakeDownRecipe(ctx context.Context, id uuid.UUID) error {
+	return s.move(id, (*recipe.Recipe).Archive)
+}
+
+func (s stubRecipes) ReinstateRecipe(ctx context.Context, id uuid.UUID) error {
+	return s.move(id, (*recipe.Recipe).Unarchive)
+}
+
+func (s stubRecipes) move(id uuid.UUID, transition func(*recipe.Recipe) error) error {
+	r, ok := s.recipes[id]
+	if !ok {
+		return errors.NewRecipeNotFoundError(id.String())
+	}
+	if err := transition(r); err != nil {
+		return errors.NewConflictError("wrong status")
+	}
+	return nil
+}
+
+// stubEmbeddings answers every search with the same nearest recipe
+type stubEmbeddings struct {
+	outbound.RecipeEmbeddingRepository
+	saved    []outbound.RecipeEmbedding
+	nearest  []outbound.EmbeddingMatch
+	excluded []uuid.UUID
+}
+
+func (s *stubEmbeddings) Save(ctx context.Context, e outbound.RecipeEmbedding) error {
+	s.saved = append(s.saved, e)
+	return nil
+}
+
+func (s *stubEmbeddings) FindNearest(ctx context.Context, criteria outbound.SearchCriteria, model string, vector []float32, limit int) ([]outbound.EmbeddingMatch, error) {
+	if criteria.ExcludeAuthorID != nil {
+		s.excluded = append(s.excluded, *criteria.ExcludeAuthorID)
+	}
+	return s.nearest, nil
+}
+
+type stubAI struct {
+	outbound.AIService
+	err error
+}
+
+func (s *stubAI) Embed(ctx context.Context, texts []string) (*outbound.AIEmbeddings, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vectors[i] = []float32{1, 0}
+	}
+	return &outbound.AIEmbeddings{Vectors: vectors, Model: "test-embed"}, nil
+}
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+func publishedRecipe(title string, authorID uuid.UUID, ingredients, steps []string, now time.Time) *recipe.Recipe {
+	snap := recipe.Snapshot{
+		ID:          uuid.New(),
+		Title:       title,
+		AuthorID:    authorID,
+		Status:      recipe.RecipeStatusPublished,
+		PublishedAt: &now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for _, name := range ingredients {
+		snap.Ingredients = append(snap.Ingredients, recipe.Ingredient{ID: uuid.New(), Name: name, Amount: 1})
+	}
+	for i, step := range steps {
+		snap.Instructions = append(snap.Instructions, recipe.Instruction{StepNumber: i + 1, Description: step})
+	}
+	return recipe.Reconstruct(snap)
+}
+
+type fixture struct {
+	svc        *Service
+	flags      *memoryFlags
+	recipes    *catalogue
+	embeddings *stubEmbeddings
+	ai         *stubAI
+	admin      uuid.UUID
+	secondMod  uuid.UUID
+	reader     uuid.UUID
+}
+
+func newFixture(t *testing.T, now time.Time) *fixture {
+	t.Helper()
+	admin := user.ReconstructUser(uuid.New(), "admin@example.com", "Admin", "", true, true, user.UserRoleAdmin, now, now, nil)
+	second := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleAdmin, now, now, nil)
+	reader := user.ReconstructUser(uuid.New(), "bo@example.com", "Bo", "", true, true, user.UserRoleUser, now, now, nil)
+	f := &fixture{
+		flags:      &memoryFlags{},
+		recipes:    &catalogue{recipes: map[uuid.UUID]*recipe.Recipe{}},
+		embeddings: &stubEmbeddings{},
+		ai:         &stubAI{},
+		admin:      admin.ID(),
+		secondMod:  second.ID(),
+		reader:     reader.ID(),
+	}
+	users := &stubUsers{users: map[uuid.UUID]*user.User{admin.ID(): admin, second.ID(): second, reader.ID(): reader}}
+	f.svc = NewService(f.flags, stubRecipeRepo{catalogue: f.recipes}, stubRecipes{catalogue: f.recipes}, f.embeddings, f.ai, users,
+		Config{FlagSimilarity: 0.90, ArchiveSimilarity: 0.97}, zap.NewNop())
+	f.svc.now = func() time.Time { return now }
+	return f
+}
+
+func (f *fixture) add(r *recipe.Recipe) *recipe.Recipe {
+	f.recipes.recipes[r.ID()] = r
+	return r
+}
+
+func TestCheckFlagsNearCopiesAndArchivesCopies(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	ctx := context.Background()
+	original := f.add(publishedRecipe("Lemon tart", uuid.New(), []string{"Lemons", "Butter", "Flour"}, []string{"Make the pastry", "Bake for 20 minutes"}, now))
+	copier := uuid.New()
+	copied := f.add(publishedRecipe("Lemon Tart!", copier, []string{"lemons", "Sugar", " butter "}, []string{"Bake  for 20 minutes"}, now))
+	unrelated := f.add(publishedRecipe("Pea soup", copier, []string{"Peas"}, []string{"Simmer"}, now))
+
+	require.NoError(t, f.flags.EnqueueCheck(ctx, copied.ID(), now))
+	f.embeddings.nearest = []outbound.EmbeddingMatch{{RecipeID: original.ID(), Similarity: 0.98}}
+	checked, err := f.svc.CheckQueued(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, checked)
+	assert.Empty(t, f.flags.queue)
+	require.Len(t, f.embeddings.saved, 1, "the recipe is embedded by the check")
+	assert.Equal(t, copied.ID(), f.embeddings.saved[0].RecipeID)
+	assert.Equal(t, []uuid.UUID{copier}, f.embeddings.excluded, "the author's own recipes are not compared")
+
+	require.Len(t, f.flags.flags, 1)
+	flag := f.flags.flags[0]
+	assert.Equal(t, original.ID(), flag.OriginalID)
+	assert.Equal(t, copier, flag.AuthorID)
+	assert.Equal(t, []string{"lemons", "butter"}, flag.SharedIngredients)
+	assert.Equal(t, []string{"Bake  for 20 minutes"}, flag.SharedSteps)
+	assert.True(t, flag.Archived)
+	assert.Equal(t, recipe.RecipeStatusArchived, copied.Status(), "a copy is taken down at once")
+
+	require.NoError(t, f.flags.EnqueueCheck(ctx, unrelated.ID(), now))
+	f.embeddings.nearest = []outbound.EmbeddingMatch{{RecipeID: original.ID(), Similarity: 0.5}}
+	_, err = f.svc.CheckQueued(ctx)
+	require.NoError(t, err)
+	assert.Len(t, f.flags.flags, 1, "dissimilar recipes are not flagged")
+
+	near := f.add(publishedRecipe("Lemon curd tart", copier, []string{"Lemons"}, []string{"Whisk"}, now))
+	require.NoError(t, f.flags.EnqueueCheck(ctx, near.ID(), now))
+	f.embeddings.nearest = []outbound.EmbeddingMatch{{RecipeID: original.ID(), Similarity: 0.92}}
+	_, err = f.svc.CheckQueued(ctx)
+	require.NoError(t, err)
+	require.Len(t, f.flags.flags, 2)
+	assert.False(t, f.flags.flags[1].Archived)
+	assert.Equal(t, recipe.RecipeStatusPublished, near.Status(), "a near copy stays up until reviewed")
+
+	require.NoError(t, f.flags.EnqueueCheck(ctx, near.ID(), now))
+	_, err = f.svc.CheckQueued(ctx)
+	require.NoError(t, err)
+	assert.Len(t, f.flags.flags, 2, "a recipe is flagged once per original")
+}
+
+func TestCheckKeepsRecipesQueuedWhenEmbeddingFails(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	ctx := context.Background()
+	r := f.add(publishedRecipe("Lemon tart", uuid.New(), []string{"Lemons"}, []string{"Bake"}, now))
+	require.NoError(t, f.flags.EnqueueCheck(ctx, r.ID(), now))
+	f.ai.err = stderrors.New("provider down")
+
+	_, err := f.svc.CheckQueued(ctx)
+	assert.True(t, errors.Is(err, errors.CodeExternalServiceError))
+	assert.Equal(t, []uuid.UUID{r.ID()}, f.flags.queue)
+}
+
+func TestFlagsAreReviewedAndAppealedOnce(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	f := newFixture(t, now)
+	ctx := context.Background()
+	original := f.add(publishedRecipe("Lemon tart", uuid.New(), []string{"Lemons"}, []string{"Bake"}, now))
+	author := uuid.New()
+	archivedCopy := f.add(publishedRecipe("Lemon tart", author, []string{"Lemons"}, []string{"Bake"}, now))
+	nearCopy := f.add(publishedRecipe("Lemon pie", author, []string{"Lemons"}, []string{"Bake"}, now))
+	require.NoError(t, archivedCopy.Archive())
+	archivedFlag := duplicate.NewFlag(archivedCopy.ID(), original.ID(), author, 0.99, nil, nil, true, now)
+	nearFlag := duplicate.NewFlag(nearCopy.ID(), original.ID(), author, 0.92, nil, nil, false, now)
+	require.NoError(t, f.flags.Create(ctx, archivedFlag))
+	require.NoError(t, f.flags.Create(ctx, nearFlag))
+
+	_, err := f.svc.ListFlags(ctx, f.reader, 0, 0)
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	page, err := f.svc.ListFlags(ctx, f.admin, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, page.Flags, 2)
+	assert.Equal(t, "Lemon tart", page.Flags[0].RecipeTitle)
+	assert.Equal(t, "Lemon tart", page.Flags[0].OriginalTitle)
+
+	_, err = f.svc.ResolveFlag(ctx, inbound.ResolveDuplicateCommand{FlagID: archivedFlag.ID, ModeratorID: f.reader})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	dismissed, err := f.svc.ResolveFlag(ctx, inbound.ResolveDuplicateCommand{FlagID: archivedFlag.ID, ModeratorID: f.admin, Note: "Common recipe"})
+	require.NoError(t, err)
+	assert.Equal(t, "dismissed", dismissed.Status)
+	assert.Equal(t, recipe.RecipeStatusPublished, archivedCopy.Status(), "dismissing puts back a recipe the check took down")
+	_, err = f.svc.ResolveFlag(ctx, inbound.ResolveDuplicateCommand{FlagID: archivedFlag.ID, ModeratorID: f.admin, Uphold: true})
+	assert.True(t, errors.Is(err, errors.CodeConflict))
+
+	upheld, err := f.svc.ResolveFlag(ctx, inbound.ResolveDuplicateCommand{FlagID: nearFlag.ID, ModeratorID: f.admin, Uphold: true})
+	require.NoError(t, err)
+	assert.Equal(t, "upheld", upheld.Status)
+	assert.Equal(t, recipe.RecipeStatusArchived, nearCopy.Status(), "upholding takes a live recipe down")
+
+	_, err = f.svc.AppealFlag(ctx, inbound.AppealDuplicateCommand{RecipeID: nearCopy.ID(), AuthorID: f.reader, Note: "Mine"})
+	assert.True(t, errors.Is(err, errors.CodeNotFound), "only the author sees the flag")
+	_, err = f.svc.AppealFlag(ctx, inbound.AppealDuplicateCommand{RecipeID: nearCopy.ID(), AuthorID: author, Note: "  "})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	appealed, err := f.svc.AppealFlag(ctx, inbound.AppealDuplicateCommand{RecipeID: nearCopy.ID(), AuthorID: author, Note: " Family recipe "})
+	require.NoError(t, err)
+	assert.Equal(t, "pending", appealed.Appeal)
+	assert.Equal(t, "Family recipe", appealed.AppealNote)
+	_, err = f.svc.AppealFlag(ctx, inbound.AppealDuplicateCommand{RecipeID: nearCopy.ID(), AuthorID: author, Note: "Again"})
+	assert.True(t, errors.Is(err, errors.CodeConflict), "one appeal per flag")
+
+	page, err = f.svc.ListFlags(ctx, f.admin, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, page.Flags, 1, "appealed flags wait for a decision")
+	assert.Equal(t, nearFlag.ID, page.Flags[0].ID)
+
+	_, err = f.svc.DecideAppeal(ctx, inbound.DecideDuplicateAppealCommand{FlagID: nearFlag.ID, ModeratorID: f.admin, Grant: true})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions), "the admin who upheld the flag cannot decide its appeal")
+	granted, err := f.svc.DecideAppeal(ctx, inbound.DecideDuplicateAppealCommand{FlagID: nearFlag.ID, ModeratorID: f.secondMod, Grant: true})
+	require.NoError(t, err)
+	assert.Equal(t, "granted", granted.Appeal)
+	assert.Equal(t, recipe.RecipeStatusPublished, nearCopy.Status(), "a granted appeal restores the recipe")
+	_, err = f.svc.DecideAppeal(ctx, inbound.DecideDuplicateAppealCommand{FlagID: nearFlag.ID, ModeratorID: f.secondMod})
+	assert.True(t, errors.Is(err, errors.CodeConflict))
+}
@@ -0,0 +1,130 @@
+// Package embedding keeps the recipe vectors behind search by meaning up
+// to date, embedding the title, description and ingredients of published
+// recipes that are new or changed since their vector was made.
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// DefaultBatchSize is how many recipes are sent to the model at once
+const DefaultBatchSize = 32
+
+// Service implements inbound.EmbeddingService
+type Service struct {
+	repo      outbound.RecipeEmbeddingRepository
+	ai        outbound.AIService
+	batchSize int
+	logger    *zap.Logger
+
+	mu         sync.Mutex
+	refreshing bool
+}
+
+// NewService creates an embedding service
+func NewService(repo outbound.RecipeEmbeddingRepository, ai outbound.AIService, batchSize int, logger *zap.Logger) *Service {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Service{
+		repo:      repo,
+		ai:        ai,
+		batchSize: batchSize,
+		logger:    logger.Named("embedding"),
+	}
+}
+
+// RecipeText is the text a recipe is embedded from
+func RecipeText(r *recipe.Recipe) string {
+	parts := []string{r.Title(), r.Description()}
+	for _, ing := range r.Ingredients() {
+		parts = append(parts, ing.Name)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Refresh embeds stale recipes a batch at a time until none are left.
+// Vectors are kept per model, so switching providers re-embeds the
+// catalogue under the new model while searches keep using the old one
+// until it is done.
+func (s *Service) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	if s.refreshing {
+		s.mu.Unlock()
+		return nil
+	}
+	s.refreshing = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.refreshing = false
+		s.mu.Unlock()
+	}()
+
+	started := time.Now()
+	current, err := s.ai.Embed(ctx, nil)
+	if err != nil {
+		return errors.NewExternalServiceError("ai", err)
+	}
+	model := current.Model
+
+	embedded := 0
+	for {
+		stale, err := s.repo.FindStale(ctx, model, s.batchSize)
+		if err != nil {
+			return errors.NewDatabaseError("find stale recipe embeddings", err)
+		}
+		if len(stale) == 0 {
+			break
+		}
+
+		texts := make([]string, len(stale))
+		for i, r := range stale {
+			texts[i] = RecipeText(r)
+		}
+		result, err := s.ai.Embed(ctx, texts)
+		if err != nil {
+			return errors.NewExternalServiceError("ai", err)
+		}
+		if result.Model != model {
+			return errors.NewExternalServiceError("ai", fmt.Errorf("embedding model changed from %s to %s during the refresh", model, result.Model))
+		}
+		if len(result.Vectors) != len(stale) {
+			return errors.NewExternalServiceError("ai", fmt.Errorf("%d vectors for %d recipes", len(result.Vectors), len(stale)))
+		}
+
+		for i, r := range stale {
+			err := s.repo.Save(ctx, outbound.RecipeEmbedding{
+				RecipeID:        r.ID(),
+				Model:           model,
+				Vector:          result.Vectors[i],
+				RecipeUpdatedAt: r.UpdatedAt(),
+			})
+			if err != nil {
+				return errors.NewDatabaseError("save recipe embedding", err)
+			}
+		}
+		embedded += len(stale)
+		if len(stale) < s.batchSize {
+			break
+		}
+	}
+
+	if embedded > 0 {
+		s.logger.Info("Recipe embeddings refreshed",
+			zap.String("model", model),
+			zap.Int("recipes", embedded),
+			zap.Duration("duration", time.Since(started)),
+		)
+	}
+	return nil
+}
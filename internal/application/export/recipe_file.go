@@ -0,0 +1,98 @@
+package export
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/printout"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ExportRecipe renders a recipe the requester can see, published or their
+// own draft, as a file
+func (s *Service) ExportRecipe(ctx context.Context, query inbound.RecipeFileQuery) (*inbound.RecipeFile, error) {
+	format := strings.ToLower(query.Format)
+	switch format {
+	case "":
+		format = inbound.RecipeFileFormatPDF
+	case "md":
+		format = inbound.RecipeFileFormatMarkdown
+	case inbound.RecipeFileFormatPDF, inbound.RecipeFileFormatJSON, inbound.RecipeFileFormatMarkdown:
+	default:
+		return nil, errors.NewBadRequestError("format must be pdf, json or markdown")
+	}
+
+	entity, err := s.recipeRepo.FindByID(ctx, query.RecipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil || (entity.Status() != recipe.RecipeStatusPublished && entity.AuthorID() != query.RequesterID) {
+		return nil, errors.NewRecipeNotFoundError(query.RecipeID.String())
+	}
+
+	credit := printout.Attribution{
+		Author: s.authorName(ctx, entity.AuthorID()),
+		URL:    s.recipeURL(entity.ID()),
+	}
+	if originID := entity.ForkedFromID(); originID != nil {
+		credit.ForkedFrom = s.origin(ctx, *originID)
+	}
+	p := printout.Build(entity, credit)
+
+	name := "recipe-" + entity.ID().String()
+	file := &inbound.RecipeFile{}
+	switch format {
+	case inbound.RecipeFileFormatJSON:
+		file.FileName, file.ContentType, file.Content = name+".json", "application/json", printout.JSON(p)
+	case inbound.RecipeFileFormatMarkdown:
+		file.FileName, file.ContentType, file.Content = name+".md", "text/markdown; charset=utf-8", printout.Markdown(p)
+	default:
+		file.FileName, file.ContentType, file.Content = name+".pdf", "application/pdf", printout.PDF(p)
+	}
+
+	s.logger.Debug("Recipe exported",
+		zap.String("recipe_id", entity.ID().String()),
+		zap.String("format", format),
+		zap.Int("bytes", len(file.Content)),
+	)
+	return file, nil
+}
+
+// origin credits the recipe a fork was copied from, or nil once it is gone
+// or no longer published
+func (s *Service) origin(ctx context.Context, recipeID uuid.UUID) *printout.Origin {
+	original, err := s.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil || original == nil || original.Status() != recipe.RecipeStatusPublished {
+		return nil
+	}
+	return &printout.Origin{
+		Title:  original.Title(),
+		Author: s.authorName(ctx, original.AuthorID()),
+		URL:    s.recipeURL(original.ID()),
+	}
+}
+
+// authorName is the author's display name; a failed lookup leaves the
+// credit out rather than failing the export
+func (s *Service) authorName(ctx context.Context, authorID uuid.UUID) string {
+	author, err := s.userRepo.FindByID(ctx, authorID)
+	if err != nil {
+		s.logger.Debug("Recipe author unavailable", zap.String("author_id", authorID.String()), zap.Error(err))
+		return ""
+	}
+	if author == nil {
+		return ""
+	}
+	return author.Name()
+}
+
+func (s *Service) recipeURL(recipeID uuid.UUID) string {
+	if s.cfg.SiteURL == "" {
+		return ""
+	}
+	return s.cfg.SiteURL + "/recipes/" + recipeID.String()
+}
@@ -0,0 +1,108 @@
+// Package export streams bulk exports of the recipe catalogue to admins
+// and renders single recipes as files. Rows go from a database cursor
+// straight to the caller, so exports of any size run in constant memory.
+package export
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/domain/recipe/allergens"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Config locates the recipe links printed on exported recipes
+type Config struct {
+	SiteURL string // Base of recipe links
+}
+
+// Service implements inbound.ExportService
+type Service struct {
+	recipes    outbound.RecipeExportRepository
+	recipeRepo outbound.RecipeRepository
+	userRepo   outbound.UserRepository
+	cfg        Config
+	logger     *zap.Logger
+}
+
+// NewService creates the export service
+func NewService(
+	recipes outbound.RecipeExportRepository,
+	recipeRepo outbound.RecipeRepository,
+	userRepo outbound.UserRepository,
+	cfg Config,
+	logger *zap.Logger,
+) *Service {
+	cfg.SiteURL = strings.TrimRight(cfg.SiteURL, "/")
+	return &Service{
+		recipes:    recipes,
+		recipeRepo: recipeRepo,
+		userRepo:   userRepo,
+		cfg:        cfg,
+		logger:     logger.Named("export"),
+	}
+}
+
+// ExportRecipes streams the recipes matching query to fn
+func (s *Service) ExportRecipes(ctx context.Context, requesterID uuid.UUID, query inbound.RecipeExportQuery, fn func(*inbound.ExportedRecipe) error) error {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "export recipes"); err != nil {
+		return err
+	}
+	switch query.Status {
+	case "", "draft", "published", "archived":
+	default:
+		return errors.NewBadRequestError("status must be draft, published or archived")
+	}
+
+	rows := 0
+	err := s.recipes.StreamRecipes(ctx, outbound.RecipeExportFilter{
+		Status:       query.Status,
+		UpdatedSince: query.UpdatedSince,
+	}, func(row *outbound.RecipeExportRow) error {
+		rows++
+		return fn(toExportedRecipe(row))
+	})
+	if err != nil {
+		s.logger.Warn("Recipe export stopped", zap.Int("rows", rows), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Recipe export finished", zap.Stringer("requester_id", requesterID), zap.Int("rows", rows))
+	return nil
+}
+
+func toExportedRecipe(row *outbound.RecipeExportRow) *inbound.ExportedRecipe {
+	ingredients := make([]allergens.Ingredient, len(row.Ingredients))
+	for i, line := range row.Ingredients {
+		ingredients[i] = allergens.Ingredient{Name: line.Name, Optional: line.Optional, Notes: line.Notes}
+	}
+	disclosure := allergens.Disclose(ingredients)
+
+	return &inbound.ExportedRecipe{
+		ID:              row.ID,
+		Title:           row.Title,
+		AuthorID:        row.AuthorID,
+		Status:          row.Status,
+		Language:        row.Language,
+		Cuisine:         row.Cuisine,
+		Category:        row.Category,
+		Difficulty:      row.Difficulty,
+		PrepTimeMinutes: row.PrepTimeMinutes,
+		CookTimeMinutes: row.CookTimeMinutes,
+		Servings:        row.Servings,
+		Calories:        row.Calories,
+		Likes:           row.Likes,
+		Views:           row.Views,
+		AverageRating:   row.AverageRating,
+		AIGenerated:     row.AIGenerated,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+		PublishedAt:     row.PublishedAt,
+		Allergens:       disclosure.Contains(),
+		MayContain:      disclosure.MayContain(),
+	}
+}
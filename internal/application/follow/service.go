@@ -0,0 +1,231 @@
+// Package follow lets users follow each other. Profiles show follower and
+// following counts to everyone; a private profile shows its details and
+// follow lists only to its owner and the users the owner follows.
+package follow
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/notification"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Service implements inbound.FollowService
+type Service struct {
+	follows       outbound.FollowRepository
+	userRepo      outbound.UserRepository
+	notifications inbound.NotificationService
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewService creates the follow service. notifications may be nil.
+func NewService(follows outbound.FollowRepository, userRepo outbound.UserRepository, notifications inbound.NotificationService, logger *zap.Logger) *Service {
+	return &Service{
+		follows:       follows,
+		userRepo:      userRepo,
+		notifications: notifications,
+		logger:        logger.Named("follow"),
+		now:           time.Now,
+	}
+}
+
+// Follow makes followerID follow userID
+func (s *Service) Follow(ctx context.Context, followerID, userID uuid.UUID) (*inbound.FollowStatus, error) {
+	if followerID == userID {
+		return nil, errors.NewBadRequestError("you cannot follow yourself")
+	}
+	if _, err := s.findUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	added, err := s.follows.Follow(ctx, followerID, userID, s.now().UTC())
+	if err != nil {
+		return nil, errors.NewDatabaseError("follow user", err)
+	}
+	if added {
+		s.logger.Info("User followed",
+			zap.String("follower_id", followerID.String()),
+			zap.String("user_id", userID.String()),
+		)
+		s.notifyFollowed(ctx, followerID, userID)
+	}
+	return s.status(ctx, userID, true)
+}
+
+// Unfollow stops followerID following userID
+func (s *Service) Unfollow(ctx context.Context, followerID, userID uuid.UUID) (*inbound.FollowStatus, error) {
+	if _, err := s.follows.Unfollow(ctx, followerID, userID); err != nil {
+		return nil, errors.NewDatabaseError("unfollow user", err)
+	}
+	return s.status(ctx, userID, false)
+}
+
+// Profile returns userID's profile as viewerID sees it
+func (s *Service) Profile(ctx context.Context, viewerID *uuid.UUID, userID uuid.UUID) (*inbound.PublicProfile, error) {
+	account, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	followers, following, err := s.follows.Counts(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("count follows", err)
+	}
+
+	profile := &inbound.PublicProfile{
+		ID:        account.ID(),
+		Name:      account.Name(),
+		Private:   account.IsPrivate(),
+		Followers: followers,
+		Following: following,
+	}
+	if viewerID != nil && *viewerID != userID {
+		if profile.IsFollowing, err = s.follows.IsFollowing(ctx, *viewerID, userID); err != nil {
+			return nil, errors.NewDatabaseError("find follow", err)
+		}
+	}
+
+	visible, err := s.visible(ctx, viewerID, account)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		profile.Restricted = true
+		return profile, nil
+	}
+	if details := account.Profile(); details != nil {
+		profile.Avatar = details.Avatar
+		profile.Bio = details.Bio
+		profile.Location = details.Location
+		profile.Website = details.Website
+		profile.CookingLevel = string(details.CookingLevel)
+	}
+	joined := account.CreatedAt()
+	profile.JoinedAt = &joined
+	return profile, nil
+}
+
+// Followers lists who follows userID
+func (s *Service) Followers(ctx context.Context, viewerID *uuid.UUID, userID uuid.UUID, params inbound.PaginationParams) (*inbound.FollowList, error) {
+	return s.list(ctx, viewerID, userID, params, s.follows.Followers)
+}
+
+// Following lists whom userID follows
+func (s *Service) Following(ctx context.Context, viewerID *uuid.UUID, userID uuid.UUID, params inbound.PaginationParams) (*inbound.FollowList, error) {
+	return s.list(ctx, viewerID, userID, params, s.follows.Following)
+}
+
+type listFunc func(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error)
+
+func (s *Service) list(ctx context.Context, viewerID *uuid.UUID, userID uuid.UUID, params inbound.PaginationParams, find listFunc) (*inbound.FollowList, error) {
+	account, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	visible, err := s.visible(ctx, viewerID, account)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, errors.NewForbiddenError("This profile is private")
+	}
+
+	if params.PageSize <= 0 {
+		params.PageSize = defaultPageSize
+	}
+	if params.PageSize > maxPageSize {
+		params.PageSize = maxPageSize
+	}
+	if params.Page < 0 {
+		params.Page = 0
+	}
+	ids, err := find(ctx, userID, params.Page*params.PageSize, params.PageSize)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list follows", err)
+	}
+
+	list := &inbound.FollowList{Users: []inbound.FollowUser{}, Page: params.Page, PageSize: params.PageSize}
+	if len(ids) == 0 {
+		return list, nil
+	}
+	accounts, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find users", err)
+	}
+	byID := make(map[uuid.UUID]*user.User, len(accounts))
+	for _, a := range accounts {
+		byID[a.ID()] = a
+	}
+	for _, id := range ids {
+		if a, ok := byID[id]; ok && a.IsActive() {
+			list.Users = append(list.Users, inbound.FollowUser{ID: id, Name: a.Name()})
+		}
+	}
+	return list, nil
+}
+
+// visible reports whether viewerID may see the account's details: the
+// profile is public, the viewer owns it, or the owner follows the viewer
+func (s *Service) visible(ctx context.Context, viewerID *uuid.UUID, account *user.User) (bool, error) {
+	if !account.IsPrivate() {
+		return true, nil
+	}
+	if viewerID == nil {
+		return false, nil
+	}
+	if *viewerID == account.ID() {
+		return true, nil
+	}
+	followed, err := s.follows.IsFollowing(ctx, account.ID(), *viewerID)
+	if err != nil {
+		return false, errors.NewDatabaseError("find follow", err)
+	}
+	return followed, nil
+}
+
+// findUser loads an active account. Suspended accounts are not found.
+func (s *Service) findUser(ctx context.Context, userID uuid.UUID) (*user.User, error) {
+	accounts, err := s.userRepo.FindByIDs(ctx, []uuid.UUID{userID})
+	if err != nil {
+		return nil, errors.NewDatabaseError("find user", err)
+	}
+	if len(accounts) == 0 || !accounts[0].IsActive() {
+		return nil, errors.NewUserNotFoundError(userID.String())
+	}
+	return accounts[0], nil
+}
+
+// notifyFollowed tells userID about a new follower. Failures are logged:
+// the follow is already saved.
+func (s *Service) notifyFollowed(ctx context.Context, followerID, userID uuid.UUID) {
+	if s.notifications == nil {
+		return
+	}
+	err := s.notifications.Notify(ctx, inbound.NotifyCommand{
+		UserID:  userID,
+		ActorID: &followerID,
+		Type:    notification.TypeNewFollower,
+	})
+	if err != nil {
+		s.logger.Warn("Failed to notify new follower", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+}
+
+func (s *Service) status(ctx context.Context, userID uuid.UUID, following bool) (*inbound.FollowStatus, error) {
+	followers, _, err := s.follows.Counts(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("count follows", err)
+	}
+	return &inbound.FollowStatus{UserID: userID, Following: following, Followers: followers}, nil
+}
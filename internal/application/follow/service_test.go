@@ -0,0 +1,152 @@
+package follow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type edge struct{ from, to uuid.UUID }
+
+type memoryFollows struct {
+	edges []edge
+}
+
+func (m *memoryFollows) Follow(ctx context.Context, followerID, followingID uuid.UUID, at time.Time) (bool, error) {
+	if ok, _ := m.IsFollowing(ctx, followerID, followingID); ok {
+		return false, nil
+	}
+	m.edges = append([]edge{{followerID, followingID}}, m.edges...)
+	return true, nil
+}
+
+func (m *memoryFollows) Unfollow(ctx context.Context, followerID, followingID uuid.UUID) (bool, error) {
+	for i, e := range m.edges {
+		if e == (edge{followerID, followingID}) {
+			m.edges = append(m.edges[:i], m.edges[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryFollows) IsFollowing(ctx context.Context, followerID, followingID uuid.UUID) (bool, error) {
+	for _, e := range m.edges {
+		if e == (edge{followerID, followingID}) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryFollows) Counts(ctx context.Context, userID uuid.UUID) (int, int, error) {
+	var followers, following int
+	for _, e := range m.edges {
+		if e.to == userID {
+			followers++
+		}
+		if e.from == userID {
+			following++
+		}
+	}
+	return followers, following, nil
+}
+
+func (m *memoryFollows) Followers(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, e := range m.edges {
+		if e.to == userID {
+			ids = append(ids, e.from)
+		}
+	}
+	return ids, nil
+}
+
+func (m *memoryFollows) Following(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, e := range m.edges {
+		if e.from == userID {
+			ids = append(ids, e.to)
+		}
+	}
+	return ids, nil
+}
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*user.User, error) {
+	var found []*user.User
+	for _, id := range ids {
+		if u, ok := s.users[id]; ok {
+			found = append(found, u)
+		}
+	}
+	return found, nil
+}
+
+func TestPrivateProfilesShowOnlyToFollowedUsers(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	chef := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	chef.UpdateProfile(&user.UserProfile{Bio: "Pastry"})
+	fan := user.ReconstructUser(uuid.New(), "bo@example.com", "Bo", "", true, true, user.UserRoleUser, now, now, nil)
+	friend := user.ReconstructUser(uuid.New(), "cy@example.com", "Cy", "", true, true, user.UserRoleUser, now, now, nil)
+	svc := NewService(&memoryFollows{}, &stubUsers{users: map[uuid.UUID]*user.User{
+		chef.ID(): chef, fan.ID(): fan, friend.ID(): friend,
+	}}, nil, zap.NewNop())
+	ctx := context.Background()
+	fanID, friendID := fan.ID(), friend.ID()
+
+	_, err := svc.Follow(ctx, fanID, fanID)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	_, err = svc.Follow(ctx, fanID, uuid.New())
+	assert.True(t, errors.Is(err, errors.CodeUserNotFound))
+
+	status, err := svc.Follow(ctx, fanID, chef.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.Followers)
+	status, err = svc.Follow(ctx, fanID, chef.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.Followers, "following twice counts once")
+	_, err = svc.Follow(ctx, chef.ID(), friendID)
+	require.NoError(t, err)
+
+	chef.SetPrivateProfile(true)
+	profile, err := svc.Profile(ctx, &fanID, chef.ID())
+	require.NoError(t, err)
+	assert.True(t, profile.Restricted, "following a private profile does not unlock it")
+	assert.True(t, profile.IsFollowing)
+	assert.Empty(t, profile.Bio)
+	assert.Equal(t, 1, profile.Followers)
+	assert.Equal(t, 1, profile.Following)
+	_, err = svc.Followers(ctx, &fanID, chef.ID(), inbound.PaginationParams{})
+	assert.True(t, errors.Is(err, errors.CodeForbidden))
+
+	profile, err = svc.Profile(ctx, &friendID, chef.ID())
+	require.NoError(t, err)
+	assert.False(t, profile.Restricted, "the owner follows this viewer")
+	assert.Equal(t, "Pastry", profile.Bio)
+	followers, err := svc.Followers(ctx, &friendID, chef.ID(), inbound.PaginationParams{})
+	require.NoError(t, err)
+	assert.Equal(t, []inbound.FollowUser{{ID: fanID, Name: "Bo"}}, followers.Users)
+
+	profile, err = svc.Profile(ctx, nil, chef.ID())
+	require.NoError(t, err)
+	assert.True(t, profile.Restricted)
+
+	status, err = svc.Unfollow(ctx, fanID, chef.ID())
+	require.NoError(t, err)
+	assert.False(t, status.Following)
+	assert.Zero(t, status.Followers)
+}
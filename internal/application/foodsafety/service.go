@@ -0,0 +1,62 @@
+// Package foodsafety checks recipes against food safety rules for authors
+// at publish time and for cooks in cook mode.
+package foodsafety
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/foodsafety"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// Service implements inbound.FoodSafetyService
+type Service struct {
+	recipes outbound.RecipeSafetyRepository
+}
+
+// NewService creates a food safety service
+func NewService(repo outbound.RecipeSafetyRepository) *Service {
+	return &Service{recipes: repo}
+}
+
+// Check runs the food safety rules over a recipe's steps
+func (s *Service) Check(ctx context.Context, query inbound.FoodSafetyQuery) (*inbound.FoodSafetyReport, error) {
+	id, err := uuid.Parse(query.RecipeID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid recipe ID")
+	}
+
+	r, err := s.recipes.Recipe(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if r == nil || (r.Status != "published" && r.AuthorID != query.RequesterID) {
+		return nil, errors.NewRecipeNotFoundError(query.RecipeID)
+	}
+
+	steps := make([]foodsafety.Step, len(r.Steps))
+	for i, step := range r.Steps {
+		steps[i] = foodsafety.Step{Number: i + 1, Text: step.Text, Duration: step.Duration}
+	}
+	issues := foodsafety.Check(r.Ingredients, steps)
+
+	report := &inbound.FoodSafetyReport{
+		RecipeID: r.ID.String(),
+		Title:    r.Title,
+		Safe:     len(issues) == 0,
+		Issues:   make([]inbound.FoodSafetyIssue, len(issues)),
+	}
+	for i, issue := range issues {
+		report.Issues[i] = inbound.FoodSafetyIssue{
+			Step:       issue.Step,
+			Rule:       string(issue.Rule),
+			Food:       issue.Food,
+			Ingredient: issue.Ingredient,
+			Message:    issue.Message,
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,49 @@
+package foodsafety
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRecipes struct {
+	recipe *outbound.SafetyRecipe
+}
+
+func (s *stubRecipes) Recipe(ctx context.Context, recipeID uuid.UUID) (*outbound.SafetyRecipe, error) {
+	if s.recipe == nil || s.recipe.ID != recipeID {
+		return nil, nil
+	}
+	return s.recipe, nil
+}
+
+func TestCheckReportsIssuesToTheAuthor(t *testing.T) {
+	authorID := uuid.New()
+	recipe := &outbound.SafetyRecipe{
+		ID:          uuid.New(),
+		AuthorID:    authorID,
+		Title:       "Grilled Chicken",
+		Status:      "draft",
+		Ingredients: []string{"chicken breasts", "olive oil"},
+		Steps: []outbound.TimelineStep{
+			{Text: "Grill the chicken until the center reaches 60°C."},
+		},
+	}
+	svc := NewService(&stubRecipes{recipe: recipe})
+
+	report, err := svc.Check(context.Background(), inbound.FoodSafetyQuery{RequesterID: authorID, RecipeID: recipe.ID.String()})
+	require.NoError(t, err)
+	assert.False(t, report.Safe)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, 1, report.Issues[0].Step)
+	assert.Equal(t, "internal-temperature", report.Issues[0].Rule)
+
+	_, err = svc.Check(context.Background(), inbound.FoodSafetyQuery{RecipeID: recipe.ID.String()})
+	assert.True(t, errors.Is(err, errors.CodeRecipeNotFound), "drafts are only checked for their author")
+}
@@ -0,0 +1,241 @@
+// Package graph serves traversals of the recipe knowledge graph. The graph
+// is rebuilt on a schedule from the published recipes, so a traversal only
+// reads the adjacency tables.
+package graph
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/graph"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultLimit and maxLimit bound the recipes a traversal returns
+	defaultLimit = 12
+	maxLimit     = 50
+)
+
+// Service implements inbound.RecipeGraphService
+type Service struct {
+	graph  outbound.RecipeGraphRepository
+	logger *zap.Logger
+
+	mu         sync.Mutex
+	refreshing bool
+}
+
+// NewService creates a recipe graph service
+func NewService(repo outbound.RecipeGraphRepository, logger *zap.Logger) *Service {
+	return &Service{
+		graph:  repo,
+		logger: logger.Named("recipe-graph"),
+	}
+}
+
+// RecipeNodes returns the ingredients, techniques and cuisine a recipe
+// links to
+func (s *Service) RecipeNodes(ctx context.Context, recipeID string) (*inbound.GraphNodes, error) {
+	id, err := uuid.Parse(recipeID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid recipe ID")
+	}
+	nodes, err := s.graph.NodesOf(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list recipe graph nodes", err)
+	}
+	refreshedAt, err := s.refreshedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &inbound.GraphNodes{
+		RecipeID:    id.String(),
+		Ingredients: []inbound.GraphNode{},
+		Techniques:  []inbound.GraphNode{},
+		Cuisines:    []inbound.GraphNode{},
+		RefreshedAt: refreshedAt,
+	}
+	for _, node := range nodes {
+		switch graph.Kind(node.Kind) {
+		case graph.Ingredient:
+			result.Ingredients = append(result.Ingredients, graphNode(node))
+		case graph.Technique:
+			result.Techniques = append(result.Techniques, graphNode(node))
+		case graph.Cuisine:
+			result.Cuisines = append(result.Cuisines, graphNode(node))
+		}
+	}
+	return result, nil
+}
+
+// NodeRecipes returns the recipes linked to a node, most liked first
+func (s *Service) NodeRecipes(ctx context.Context, kind, key string, limit int) (*inbound.GraphNodeRecipes, error) {
+	k := graph.Kind(strings.ToLower(kind))
+	if !k.Valid() {
+		return nil, errors.NewBadRequestError("kind must be ingredient, technique or cuisine")
+	}
+	if k == graph.Ingredient {
+		key = graph.IngredientKey(key)
+	} else {
+		key = strings.ToLower(strings.TrimSpace(key))
+	}
+	if key == "" {
+		return nil, errors.NewBadRequestError("key is required")
+	}
+
+	node, err := s.graph.Node(ctx, string(k), key)
+	if err != nil {
+		return nil, errors.NewDatabaseError("read recipe graph node", err)
+	}
+	if node == nil {
+		return nil, errors.NewNotFoundError(string(k))
+	}
+	recipes, err := s.graph.RecipesFor(ctx, string(k), key, recipeLimit(limit))
+	if err != nil {
+		return nil, errors.NewDatabaseError("list recipes for graph node", err)
+	}
+	refreshedAt, err := s.refreshedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &inbound.GraphNodeRecipes{
+		Node:        graphNode(*node),
+		Recipes:     graphRecipes(recipes),
+		RefreshedAt: refreshedAt,
+	}, nil
+}
+
+// Related returns the recipes sharing the most weight of nodes with a
+// recipe
+func (s *Service) Related(ctx context.Context, recipeID string, limit int) (*inbound.RelatedRecipes, error) {
+	id, err := uuid.Parse(recipeID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid recipe ID")
+	}
+	recipes, err := s.graph.Related(ctx, id, recipeLimit(limit))
+	if err != nil {
+		return nil, errors.NewDatabaseError("list related recipes", err)
+	}
+	refreshedAt, err := s.refreshedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &inbound.RelatedRecipes{
+		RecipeID:    id.String(),
+		Recipes:     graphRecipes(recipes),
+		RefreshedAt: refreshedAt,
+	}, nil
+}
+
+// Refresh derives every published recipe's links and swaps in the rebuilt
+// graph. A node's weight falls as more recipes share it, so sharing a rare
+// technique relates two recipes more than sharing onions does. A refresh
+// started while another runs returns at once.
+func (s *Service) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	if s.refreshing {
+		s.mu.Unlock()
+		return nil
+	}
+	s.refreshing = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.refreshing = false
+		s.mu.Unlock()
+	}()
+
+	started := time.Now()
+	type nodeID struct{ kind, key string }
+	counts := make(map[nodeID]*outbound.GraphNode)
+	var edges []outbound.GraphEdge
+	recipes := 0
+	err := s.graph.EachPublished(ctx, func(src outbound.GraphSource) error {
+		recipes++
+		for _, link := range graph.Links(src.Ingredients, src.Instructions, src.Cuisine) {
+			id := nodeID{string(link.Kind), link.Key}
+			node := counts[id]
+			if node == nil {
+				node = &outbound.GraphNode{Kind: id.kind, Key: id.key, Label: link.Label}
+				counts[id] = node
+			} else if link.Label < node.Label {
+				// Keep one spelling however the recipes are read back
+				node.Label = link.Label
+			}
+			node.Recipes++
+			edges = append(edges, outbound.GraphEdge{RecipeID: src.RecipeID, Kind: id.kind, Key: id.key})
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.NewDatabaseError("read recipes for graph", err)
+	}
+
+	nodes := make([]outbound.GraphNode, 0, len(counts))
+	for _, node := range counts {
+		node.Weight = graph.Kind(node.Kind).Weight() * math.Log(1+float64(recipes)/float64(node.Recipes))
+		nodes = append(nodes, *node)
+	}
+	if err := s.graph.Replace(ctx, nodes, edges); err != nil {
+		return errors.NewDatabaseError("replace recipe graph", err)
+	}
+
+	s.logger.Info("Recipe graph refreshed",
+		zap.Int("recipes", recipes),
+		zap.Int("nodes", len(nodes)),
+		zap.Int("edges", len(edges)),
+		zap.Duration("duration", time.Since(started)),
+	)
+	return nil
+}
+
+func (s *Service) refreshedAt(ctx context.Context) (string, error) {
+	at, err := s.graph.RefreshedAt(ctx)
+	if err != nil {
+		return "", errors.NewDatabaseError("read recipe graph refresh time", err)
+	}
+	if at == nil {
+		return "", nil
+	}
+	return at.UTC().Format(time.RFC3339), nil
+}
+
+func recipeLimit(limit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	if limit > maxLimit {
+		return maxLimit
+	}
+	return limit
+}
+
+func graphNode(node outbound.GraphNode) inbound.GraphNode {
+	return inbound.GraphNode{Kind: node.Kind, Key: node.Key, Label: node.Label, Recipes: node.Recipes}
+}
+
+func graphRecipes(recipes []outbound.GraphRecipe) []inbound.GraphRecipe {
+	result := make([]inbound.GraphRecipe, len(recipes))
+	for i, recipe := range recipes {
+		result[i] = inbound.GraphRecipe{
+			ID:            recipe.RecipeID.String(),
+			Title:         recipe.Title,
+			AverageRating: recipe.AverageRating,
+			Likes:         recipe.Likes,
+			Score:         math.Round(recipe.Score*100) / 100,
+		}
+		for _, node := range recipe.Shared {
+			result[i].Shared = append(result[i].Shared, graphNode(node))
+		}
+	}
+	return result
+}
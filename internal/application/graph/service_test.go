@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubGraph struct {
+	sources []outbound.GraphSource
+	nodes   []outbound.GraphNode
+	edges   []outbound.GraphEdge
+	kind    string
+	key     string
+}
+
+func (s *stubGraph) EachPublished(ctx context.Context, fn func(outbound.GraphSource) error) error {
+	for _, src := range s.sources {
+		if err := fn(src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *stubGraph) Replace(ctx context.Context, nodes []outbound.GraphNode, edges []outbound.GraphEdge) error {
+	s.nodes, s.edges = nodes, edges
+	return nil
+}
+
+func (s *stubGraph) NodesOf(ctx context.Context, recipeID uuid.UUID) ([]outbound.GraphNode, error) {
+	return s.nodes, nil
+}
+
+func (s *stubGraph) Node(ctx context.Context, kind, key string) (*outbound.GraphNode, error) {
+	s.kind, s.key = kind, key
+	for _, node := range s.nodes {
+		if node.Kind == kind && node.Key == key {
+			return &node, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubGraph) RecipesFor(ctx context.Context, kind, key string, limit int) ([]outbound.GraphRecipe, error) {
+	return []outbound.GraphRecipe{{RecipeID: uuid.New(), Title: "Braised Leeks"}}, nil
+}
+
+func (s *stubGraph) Related(ctx context.Context, recipeID uuid.UUID, limit int) ([]outbound.GraphRecipe, error) {
+	return nil, nil
+}
+
+func (s *stubGraph) RefreshedAt(ctx context.Context) (*time.Time, error) {
+	return nil, nil
+}
+
+func TestRefreshWeighsRareNodesHigher(t *testing.T) {
+	repo := &stubGraph{sources: []outbound.GraphSource{
+		{RecipeID: uuid.New(), Ingredients: []string{"Leeks", "Salt"}, Instructions: []string{"Braise the leeks."}, Cuisine: "French"},
+		{RecipeID: uuid.New(), Ingredients: []string{"leek"}, Instructions: []string{"Slice thinly."}},
+	}}
+	svc := NewService(repo, zap.NewNop())
+	require.NoError(t, svc.Refresh(context.Background()))
+
+	weights := map[string]float64{}
+	for _, node := range repo.nodes {
+		weights[node.Kind+"/"+node.Key] = node.Weight
+		if node.Key == "leek" {
+			assert.Equal(t, 2, node.Recipes)
+			assert.Equal(t, "Leeks", node.Label)
+		}
+	}
+	assert.Len(t, weights, 3)
+	assert.NotContains(t, weights, "ingredient/salt")
+	assert.Greater(t, weights["technique/braise"], weights["cuisine/french"])
+	assert.Greater(t, weights["cuisine/french"], weights["ingredient/leek"])
+	assert.Len(t, repo.edges, 4)
+
+	nodes, err := svc.RecipeNodes(context.Background(), uuid.NewString())
+	require.NoError(t, err)
+	assert.Len(t, nodes.Techniques, 1)
+}
+
+func TestNodeRecipesNormalizesKeys(t *testing.T) {
+	repo := &stubGraph{nodes: []outbound.GraphNode{{Kind: "ingredient", Key: "leek", Label: "Leeks", Recipes: 2}}}
+	svc := NewService(repo, zap.NewNop())
+	ctx := context.Background()
+
+	result, err := svc.NodeRecipes(ctx, "Ingredient", "Leeks", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "leek", repo.key)
+	assert.Equal(t, "Leeks", result.Node.Label)
+	assert.Len(t, result.Recipes, 1)
+
+	_, err = svc.NodeRecipes(ctx, "ingredient", "parsnips", 0)
+	assert.True(t, errors.Is(err, errors.CodeNotFound))
+	_, err = svc.NodeRecipes(ctx, "utensil", "wok", 0)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
@@ -0,0 +1,430 @@
+// Package guest runs time-boxed guest sessions: visitors without an account
+// chat with the AI chef and keep a few recipes, which move to their account
+// when they register or sign in
+package guest
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/allergens"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+	"github.com/alchemorsel/v3/internal/domain/recipe/instructions"
+	"github.com/alchemorsel/v3/internal/domain/recipe/nutrition"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	maxMessageLength = 1000
+	maxTitleLength   = 200
+	maxRecipeLines   = 100
+	// sessionWindow is the period MaxSessionsPerIP counts over
+	sessionWindow = time.Hour
+)
+
+// Config limits guests
+type Config struct {
+	Enabled          bool
+	TTL              time.Duration
+	MaxRecipes       int
+	MaxMessages      int
+	MaxSessionsPerIP int
+}
+
+// Service implements inbound.GuestService
+type Service struct {
+	repo   outbound.GuestRepository
+	ai     outbound.AIService
+	cfg    Config
+	now    func() time.Time
+	logger *zap.Logger
+}
+
+// NewService creates the guest service
+func NewService(repo outbound.GuestRepository, ai outbound.AIService, cfg Config, logger *zap.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		ai:     ai,
+		cfg:    cfg,
+		now:    time.Now,
+		logger: logger.Named("guest"),
+	}
+}
+
+// StartSession starts a session unless the address started
+// MaxSessionsPerIP in the last hour. Only hashes of the token and address
+// are stored.
+func (s *Service) StartSession(ctx context.Context, clientIP string) (*inbound.GuestSessionResult, error) {
+	if !s.cfg.Enabled {
+		return nil, errors.NewForbiddenError("Guest mode is disabled")
+	}
+
+	now := s.now()
+	ipHash := hash(clientIP)
+	started, err := s.repo.CountSessionsSince(ctx, ipHash, now.Add(-sessionWindow))
+	if err != nil {
+		return nil, errors.NewDatabaseError("count guest sessions", err)
+	}
+	if started >= int64(s.cfg.MaxSessionsPerIP) {
+		return nil, errors.NewAppError(
+			errors.CodeTooManyRequests,
+			"Too many guest sessions from this address; sign up or try again later",
+			"",
+		).WithMetadata("limit", s.cfg.MaxSessionsPerIP)
+	}
+
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return nil, errors.NewInternalError("could not create a guest token")
+	}
+	session := outbound.GuestSession{
+		ID:        uuid.New(),
+		TokenHash: tokenHash,
+		IPHash:    ipHash,
+		ExpiresAt: now.Add(s.cfg.TTL),
+		CreatedAt: now,
+	}
+	if err := s.repo.CreateSession(ctx, session); err != nil {
+		return nil, errors.NewDatabaseError("create guest session", err)
+	}
+
+	s.logger.Info("Guest session started", zap.String("guest_id", session.ID.String()))
+	return &inbound.GuestSessionResult{
+		Token:       token,
+		ExpiresAt:   session.ExpiresAt,
+		MaxRecipes:  s.cfg.MaxRecipes,
+		MaxMessages: s.cfg.MaxMessages,
+	}, nil
+}
+
+// Chat counts the message against MaxMessages and asks the AI chef for a
+// recipe
+func (s *Service) Chat(ctx context.Context, token, message string) (*inbound.GuestChatResult, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, errors.NewBadRequestError("message is required")
+	}
+	if utf8.RuneCountInString(message) > maxMessageLength {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("message must be at most %d characters", maxMessageLength))
+	}
+
+	session, err := s.session(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	counted, err := s.repo.RecordMessage(ctx, session.ID, s.cfg.MaxMessages)
+	if err != nil {
+		return nil, errors.NewDatabaseError("record guest message", err)
+	}
+	if !counted {
+		return nil, errors.NewAppError(
+			errors.CodeTooManyRequests,
+			fmt.Sprintf("Guests can send %d messages; sign up to keep cooking", s.cfg.MaxMessages),
+			"",
+		).WithMetadata("limit", s.cfg.MaxMessages)
+	}
+
+	generated, err := s.ai.GenerateRecipe(ctx, message, outbound.AIConstraints{})
+	if err != nil {
+		return nil, errors.NewExternalServiceError("AI service", err)
+	}
+
+	suggestion := &inbound.GuestRecipeDraft{
+		Title:       generated.Title,
+		Description: generated.Description,
+		Ingredients: make([]string, 0, len(generated.Ingredients)),
+		Directions:  generated.Instructions,
+		Tags:        generated.Tags,
+	}
+	for _, ing := range generated.Ingredients {
+		line := strings.TrimSpace(ing.Unit + " " + ing.Name)
+		if ing.Amount > 0 {
+			line = ingredients.FormatAmount(ing.Amount) + " " + line
+		}
+		suggestion.Ingredients = append(suggestion.Ingredients, line)
+	}
+
+	reply := "How about " + generated.Title + "?"
+	if generated.Description != "" {
+		reply += " " + generated.Description
+	}
+	left := s.cfg.MaxMessages - session.Messages - 1
+	if left < 0 {
+		left = 0
+	}
+	return &inbound.GuestChatResult{Reply: reply, Suggestion: suggestion, MessagesLeft: left}, nil
+}
+
+// SaveRecipe keeps a recipe while the guest has fewer than MaxRecipes
+func (s *Service) SaveRecipe(ctx context.Context, token string, draft inbound.GuestRecipeDraft) (*inbound.GuestRecipeDTO, error) {
+	draft, err := cleanDraft(draft)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.session(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := outbound.GuestRecipe{
+		ID:          uuid.New(),
+		GuestID:     session.ID,
+		Title:       draft.Title,
+		Description: draft.Description,
+		Ingredients: draft.Ingredients,
+		Directions:  draft.Directions,
+		Servings:    draft.Servings,
+		PrepTime:    draft.PrepTime,
+		CookTime:    draft.CookTime,
+		Tags:        draft.Tags,
+		CreatedAt:   s.now(),
+	}
+	ok, err := s.repo.SaveRecipe(ctx, saved, s.cfg.MaxRecipes)
+	if err != nil {
+		return nil, errors.NewDatabaseError("save guest recipe", err)
+	}
+	if !ok {
+		return nil, errors.NewQuotaExceededError("guest recipe", s.cfg.MaxRecipes)
+	}
+
+	dto := toDTO(saved)
+	return &dto, nil
+}
+
+// ListRecipes lists the guest's recipes
+func (s *Service) ListRecipes(ctx context.Context, token string) (*inbound.GuestRecipesResult, error) {
+	session, err := s.session(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := s.repo.ListRecipes(ctx, session.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list guest recipes", err)
+	}
+
+	result := &inbound.GuestRecipesResult{
+		Recipes:   make([]inbound.GuestRecipeDTO, 0, len(saved)),
+		Remaining: s.cfg.MaxRecipes - len(saved),
+		ExpiresAt: session.ExpiresAt,
+	}
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+	for _, r := range saved {
+		result.Recipes = append(result.Recipes, toDTO(r))
+	}
+	return result, nil
+}
+
+// DeleteRecipe deletes one of the guest's recipes, freeing its slot
+func (s *Service) DeleteRecipe(ctx context.Context, token string, recipeID uuid.UUID) error {
+	session, err := s.session(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteRecipe(ctx, session.ID, recipeID)
+	if err != nil {
+		return errors.NewDatabaseError("delete guest recipe", err)
+	}
+	if !deleted {
+		return errors.NewRecipeNotFoundError(recipeID.String())
+	}
+	return nil
+}
+
+// Claim builds the user's drafts from the guest's recipes and hands them
+// to the repository, which stores them and closes the session in one
+// transaction. A recipe that cannot be built is reported and left behind
+// rather than failing the claim.
+func (s *Service) Claim(ctx context.Context, token string, userID uuid.UUID) (*inbound.GuestClaimResult, error) {
+	found, err := s.repo.FindSession(ctx, hash(token))
+	if err != nil {
+		return nil, errors.NewDatabaseError("find guest session", err)
+	}
+	if found == nil {
+		return nil, errors.NewNotFoundError("guest session")
+	}
+	if found.ClaimedBy != nil {
+		if *found.ClaimedBy == userID {
+			return &inbound.GuestClaimResult{RecipeIDs: []uuid.UUID{}}, nil
+		}
+		return nil, errors.NewConflictError("The guest session belongs to another account")
+	}
+
+	now := s.now()
+	if !now.Before(found.ExpiresAt) {
+		return nil, errors.NewNotFoundError("guest session")
+	}
+
+	saved, err := s.repo.ListRecipes(ctx, found.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list guest recipes", err)
+	}
+
+	result := &inbound.GuestClaimResult{RecipeIDs: make([]uuid.UUID, 0, len(saved))}
+	entities := make([]*recipe.Recipe, 0, len(saved))
+	for _, r := range saved {
+		entity, err := buildRecipe(r, userID)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%q was not moved: %v", r.Title, err))
+			continue
+		}
+		entities = append(entities, entity)
+		result.RecipeIDs = append(result.RecipeIDs, entity.ID())
+	}
+
+	claimed, err := s.repo.Claim(ctx, found.ID, userID, entities, now)
+	if err != nil {
+		return nil, errors.NewDatabaseError("claim guest session", err)
+	}
+	if !claimed {
+		return nil, errors.NewConflictError("The guest session was claimed or expired")
+	}
+	result.Claimed = len(entities)
+
+	s.logger.Info("Guest session claimed",
+		zap.String("guest_id", found.ID.String()),
+		zap.String("user_id", userID.String()),
+		zap.Int("recipes", result.Claimed),
+	)
+	return result, nil
+}
+
+// ExpireGuests deletes expired sessions, claimed or not
+func (s *Service) ExpireGuests(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, s.now())
+}
+
+// session finds the live, unclaimed session for a token
+func (s *Service) session(ctx context.Context, token string) (*outbound.GuestSession, error) {
+	if !s.cfg.Enabled {
+		return nil, errors.NewForbiddenError("Guest mode is disabled")
+	}
+	if token == "" {
+		return nil, errors.NewUnauthorizedError("Guest token required")
+	}
+
+	session, err := s.repo.FindSession(ctx, hash(token))
+	if err != nil {
+		return nil, errors.NewDatabaseError("find guest session", err)
+	}
+	if session == nil || session.ClaimedBy != nil || !s.now().Before(session.ExpiresAt) {
+		return nil, errors.NewUnauthorizedError("The guest session has expired; start a new one or sign up")
+	}
+	return session, nil
+}
+
+// cleanDraft trims a recipe a guest saves and checks it has a title and
+// some content
+func cleanDraft(draft inbound.GuestRecipeDraft) (inbound.GuestRecipeDraft, error) {
+	draft.Title = strings.TrimSpace(draft.Title)
+	draft.Description = strings.TrimSpace(draft.Description)
+	draft.Ingredients = nonEmpty(draft.Ingredients)
+	draft.Directions = nonEmpty(draft.Directions)
+	draft.Tags = nonEmpty(draft.Tags)
+
+	switch {
+	case draft.Title == "":
+		return draft, errors.NewBadRequestError("title is required")
+	case utf8.RuneCountInString(draft.Title) > maxTitleLength:
+		return draft, errors.NewBadRequestError(fmt.Sprintf("title must be at most %d characters", maxTitleLength))
+	case len(draft.Ingredients) == 0 && len(draft.Directions) == 0:
+		return draft, errors.NewBadRequestError("a recipe needs ingredients or directions")
+	case len(draft.Ingredients) > maxRecipeLines || len(draft.Directions) > maxRecipeLines:
+		return draft, errors.NewBadRequestError(fmt.Sprintf("a recipe may have at most %d ingredients and %d directions", maxRecipeLines, maxRecipeLines))
+	case draft.Servings < 0 || draft.PrepTime < 0 || draft.CookTime < 0:
+		return draft, errors.NewBadRequestError("servings and times must not be negative")
+	}
+	return draft, nil
+}
+
+// buildRecipe makes the user's draft from a guest recipe, parsing its
+// lines as the library importer does
+func buildRecipe(saved outbound.GuestRecipe, userID uuid.UUID) (*recipe.Recipe, error) {
+	entity, err := recipe.NewRecipe(saved.Title, saved.Description, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Section headers and lines that do not parse or validate are dropped
+	for _, line := range saved.Ingredients {
+		if parsed, err := ingredients.Parse(line); err == nil {
+			_ = entity.AddIngredient(parsed.Ingredient())
+		}
+	}
+	for _, direction := range saved.Directions {
+		for _, step := range instructions.Split(strings.Split(direction, "\n")) {
+			_ = entity.AddInstruction(step.Instruction())
+		}
+	}
+	if len(entity.Ingredients()) == 0 && len(entity.Instructions()) == 0 {
+		return nil, stderrors.New("it has no ingredients or directions")
+	}
+
+	if saved.Servings > 0 {
+		_ = entity.SetServings(saved.Servings)
+	}
+	entity.SetTiming(time.Duration(saved.PrepTime)*time.Minute, time.Duration(saved.CookTime)*time.Minute)
+	entity.SetTags(saved.Tags)
+	entity.SetNutrition(nutrition.Default().Label(entity.Ingredients(), entity.Servings()))
+	entity.SetAllergens(allergens.Label(entity.Ingredients()))
+	return entity, nil
+}
+
+func toDTO(r outbound.GuestRecipe) inbound.GuestRecipeDTO {
+	return inbound.GuestRecipeDTO{
+		ID: r.ID,
+		GuestRecipeDraft: inbound.GuestRecipeDraft{
+			Title:       r.Title,
+			Description: r.Description,
+			Ingredients: r.Ingredients,
+			Directions:  r.Directions,
+			Servings:    r.Servings,
+			PrepTime:    r.PrepTime,
+			CookTime:    r.CookTime,
+			Tags:        r.Tags,
+		},
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func nonEmpty(lines []string) []string {
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+// newToken makes a random URL-safe token and the hash it is stored under
+func newToken() (token, tokenHash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hash(token), nil
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
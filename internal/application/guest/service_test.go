@@ -0,0 +1,173 @@
+package guest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// guestStore keeps sessions and recipes in memory
+type guestStore struct {
+	sessions map[string]*outbound.GuestSession
+	recipes  []outbound.GuestRecipe
+	claimed  []*recipe.Recipe
+}
+
+func (g *guestStore) CreateSession(_ context.Context, session outbound.GuestSession) error {
+	g.sessions[session.TokenHash] = &session
+	return nil
+}
+
+func (g *guestStore) FindSession(_ context.Context, tokenHash string) (*outbound.GuestSession, error) {
+	if session, ok := g.sessions[tokenHash]; ok {
+		copied := *session
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (g *guestStore) CountSessionsSince(_ context.Context, ipHash string, since time.Time) (int64, error) {
+	var n int64
+	for _, session := range g.sessions {
+		if session.IPHash == ipHash && session.CreatedAt.After(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (g *guestStore) byID(id uuid.UUID) *outbound.GuestSession {
+	for _, session := range g.sessions {
+		if session.ID == id {
+			return session
+		}
+	}
+	return nil
+}
+
+func (g *guestStore) RecordMessage(_ context.Context, guestID uuid.UUID, max int) (bool, error) {
+	session := g.byID(guestID)
+	if session.Messages >= max {
+		return false, nil
+	}
+	session.Messages++
+	return true, nil
+}
+
+func (g *guestStore) SaveRecipe(_ context.Context, r outbound.GuestRecipe, max int) (bool, error) {
+	session := g.byID(r.GuestID)
+	if session.Recipes >= max {
+		return false, nil
+	}
+	session.Recipes++
+	g.recipes = append(g.recipes, r)
+	return true, nil
+}
+
+func (g *guestStore) ListRecipes(_ context.Context, guestID uuid.UUID) ([]outbound.GuestRecipe, error) {
+	var found []outbound.GuestRecipe
+	for _, r := range g.recipes {
+		if r.GuestID == guestID {
+			found = append(found, r)
+		}
+	}
+	return found, nil
+}
+
+func (g *guestStore) DeleteRecipe(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (g *guestStore) Claim(_ context.Context, guestID, userID uuid.UUID, recipes []*recipe.Recipe, now time.Time) (bool, error) {
+	session := g.byID(guestID)
+	if session.ClaimedBy != nil || !now.Before(session.ExpiresAt) {
+		return false, nil
+	}
+	session.ClaimedBy = &userID
+	g.claimed = append(g.claimed, recipes...)
+	g.recipes = nil
+	return true, nil
+}
+
+func (g *guestStore) DeleteExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newTestService(cfg Config) (*Service, *guestStore) {
+	store := &guestStore{sessions: map[string]*outbound.GuestSession{}}
+	return NewService(store, nil, cfg, zap.NewNop()), store
+}
+
+var testConfig = Config{Enabled: true, TTL: time.Hour, MaxRecipes: 2, MaxMessages: 5, MaxSessionsPerIP: 2}
+
+func TestGuestRecipesAreCappedAndClaimedOnce(t *testing.T) {
+	ctx := context.Background()
+	svc, store := newTestService(testConfig)
+
+	started, err := svc.StartSession(ctx, "203.0.113.7")
+	require.NoError(t, err)
+
+	draft := inbound.GuestRecipeDraft{
+		Title:       "Lemon Pasta",
+		Ingredients: []string{"200 g spaghetti", "1 lemon"},
+		Directions:  []string{"Boil the pasta.\nToss with lemon."},
+	}
+	for i := 0; i < testConfig.MaxRecipes; i++ {
+		_, err := svc.SaveRecipe(ctx, started.Token, draft)
+		require.NoError(t, err)
+	}
+	_, err = svc.SaveRecipe(ctx, started.Token, draft)
+	assert.Equal(t, errors.CodeQuotaExceeded, errors.GetCode(err))
+
+	userID := uuid.New()
+	claimed, err := svc.Claim(ctx, started.Token, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, claimed.Claimed)
+	require.Len(t, store.claimed, 2)
+	assert.Equal(t, userID, store.claimed[0].AuthorID())
+	assert.Len(t, store.claimed[0].Instructions(), 2)
+
+	// Claiming again as the same user is a no-op; another user conflicts
+	again, err := svc.Claim(ctx, started.Token, userID)
+	require.NoError(t, err)
+	assert.Zero(t, again.Claimed)
+	_, err = svc.Claim(ctx, started.Token, uuid.New())
+	assert.Equal(t, errors.CodeConflict, errors.GetCode(err))
+
+	// The claimed session no longer works as a guest
+	_, err = svc.ListRecipes(ctx, started.Token)
+	assert.Equal(t, errors.CodeUnauthorized, errors.GetCode(err))
+}
+
+func TestGuestSessionsAreLimitedAndExpire(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService(testConfig)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	first, err := svc.StartSession(ctx, "198.51.100.1")
+	require.NoError(t, err)
+	_, err = svc.StartSession(ctx, "198.51.100.1")
+	require.NoError(t, err)
+	_, err = svc.StartSession(ctx, "198.51.100.1")
+	assert.Equal(t, errors.CodeTooManyRequests, errors.GetCode(err))
+
+	now = now.Add(testConfig.TTL)
+	_, err = svc.ListRecipes(ctx, first.Token)
+	assert.Equal(t, errors.CodeUnauthorized, errors.GetCode(err))
+	_, err = svc.Claim(ctx, first.Token, uuid.New())
+	assert.Equal(t, errors.CodeNotFound, errors.GetCode(err))
+
+	disabled, _ := newTestService(Config{})
+	_, err = disabled.StartSession(ctx, "198.51.100.1")
+	assert.Equal(t, errors.CodeForbidden, errors.GetCode(err))
+}
@@ -0,0 +1,370 @@
+// Package imaging stores uploaded images and serves variants of them:
+// resized to a snapped width, encoded in the best format the client
+// accepts, at one of a few quality tiers. Encoded variants are cached on
+// disk and, when small, in the shared cache, so each is encoded once.
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Formats variants are encoded in
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+	FormatAVIF = "avif"
+)
+
+// formatAuto lets the Accept header pick the format
+const formatAuto = "auto"
+
+var contentTypes = map[string]string{
+	FormatJPEG: "image/jpeg",
+	FormatPNG:  "image/png",
+	FormatWebP: "image/webp",
+	FormatAVIF: "image/avif",
+}
+
+// uploadTypes are the originals the transcoder can decode
+var uploadTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Config bounds uploads and the variants served for them
+type Config struct {
+	Enabled       bool
+	Widths        []int
+	QualityTiers  []int
+	MaxUploadSize int64
+	MaxPixels     int
+	CacheTTL      time.Duration
+	MaxCachedSize int // Variants up to this many bytes also go in the shared cache
+	MaxConcurrent int
+}
+
+// Service implements inbound.ImageService
+type Service struct {
+	images     outbound.ImageRepository
+	originals  outbound.BlobStorage
+	variants   outbound.BlobStorage
+	cache      outbound.CacheRepository
+	transcoder outbound.ImageTranscoder
+	cfg        Config
+	slots      chan struct{}
+	now        func() time.Time
+	logger     *zap.Logger
+}
+
+// NewService creates the image service. Originals and variants may share
+// storage; variants are typically a local disk cache. A nil cache keeps
+// variants on disk only.
+func NewService(
+	images outbound.ImageRepository,
+	originals outbound.BlobStorage,
+	variants outbound.BlobStorage,
+	cache outbound.CacheRepository,
+	transcoder outbound.ImageTranscoder,
+	cfg Config,
+	logger *zap.Logger,
+) *Service {
+	cfg.Widths = sortedUnique(cfg.Widths)
+	cfg.QualityTiers = sortedUnique(cfg.QualityTiers)
+	if cfg.MaxConcurrent < 1 {
+		cfg.MaxConcurrent = 1
+	}
+	return &Service{
+		images:     images,
+		originals:  originals,
+		variants:   variants,
+		cache:      cache,
+		transcoder: transcoder,
+		cfg:        cfg,
+		slots:      make(chan struct{}, cfg.MaxConcurrent),
+		now:        time.Now,
+		logger:     logger.Named("imaging"),
+	}
+}
+
+// UploadImage checks the original decodes within the pixel limit and
+// stores it
+func (s *Service) UploadImage(ctx context.Context, cmd inbound.UploadImageCommand) (*inbound.UploadedImage, error) {
+	if !s.cfg.Enabled {
+		return nil, errors.NewForbiddenError("Image uploads are disabled")
+	}
+	if len(cmd.Content) == 0 {
+		return nil, errors.NewBadRequestError("image is empty")
+	}
+	if int64(len(cmd.Content)) > s.cfg.MaxUploadSize {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("image must be at most %d bytes", s.cfg.MaxUploadSize))
+	}
+
+	info, err := s.transcoder.Probe(cmd.Content)
+	if err != nil || !uploadTypes[info.ContentType] {
+		return nil, errors.NewBadRequestError("image must be a JPEG, PNG or GIF")
+	}
+	if info.Width*info.Height > s.cfg.MaxPixels {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("image must have at most %d pixels", s.cfg.MaxPixels))
+	}
+
+	sum := sha256.Sum256(cmd.Content)
+	id := uuid.New()
+	image := &outbound.StoredImage{
+		ID:          id,
+		OwnerID:     cmd.UserID,
+		BlobKey:     "images/originals/" + id.String(),
+		ContentType: info.ContentType,
+		Width:       info.Width,
+		Height:      info.Height,
+		SizeBytes:   int64(len(cmd.Content)),
+		SHA256:      hex.EncodeToString(sum[:]),
+		CreatedAt:   s.now().UTC(),
+	}
+	if _, err := s.originals.Put(ctx, image.BlobKey, image.ContentType, bytes.NewReader(cmd.Content)); err != nil {
+		return nil, errors.NewInternalError("failed to store image").WithCause(err)
+	}
+	if err := s.images.Create(ctx, image); err != nil {
+		return nil, errors.NewDatabaseError("create image", err)
+	}
+
+	s.logger.Info("Image uploaded",
+		zap.String("image_id", id.String()),
+		zap.String("user_id", cmd.UserID.String()),
+		zap.Int("width", info.Width),
+		zap.Int("height", info.Height),
+	)
+	return s.toUploaded(image), nil
+}
+
+// GetVariant snaps the request to a configured width and quality tier,
+// picks the format and serves the variant from the shared cache, the disk
+// cache or a fresh encode, in that order
+func (s *Service) GetVariant(ctx context.Context, id uuid.UUID, req inbound.ImageVariantRequest) (*inbound.ImageVariant, error) {
+	image, err := s.images.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find image", err)
+	}
+	if image == nil {
+		return nil, errors.NewNotFoundError("image")
+	}
+
+	format, err := s.negotiate(req.Format, req.Accept, image.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	opts := outbound.TranscodeOptions{
+		Width:   s.snapWidth(req.Width, image.Width),
+		Format:  format,
+		Quality: s.snapQuality(req.Quality),
+	}
+	if req.Width > 0 && req.Height > 0 {
+		opts.Height = req.Height * opts.Width / req.Width
+	}
+
+	key := fmt.Sprintf("images/variants/%s/%dx%d-q%d.%s", id, opts.Width, opts.Height, opts.Quality, format)
+	content, err := s.variant(ctx, image, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := sha256.Sum256([]byte(key + image.SHA256))
+	return &inbound.ImageVariant{
+		Content:     content,
+		ContentType: contentTypes[format],
+		Format:      format,
+		Quality:     opts.Quality,
+		ETag:        `"` + hex.EncodeToString(tag[:8]) + `"`,
+		UploadedAt:  image.CreatedAt,
+	}, nil
+}
+
+// variant finds or encodes one variant. Cache failures are logged and
+// treated as misses; the image is still served.
+func (s *Service) variant(ctx context.Context, image *outbound.StoredImage, key string, opts outbound.TranscodeOptions) ([]byte, error) {
+	if s.cache != nil {
+		if content, err := s.cache.Get(ctx, key); err == nil && len(content) > 0 {
+			return content, nil
+		}
+	}
+	if content, err := s.readBlob(ctx, s.variants, key); err == nil && len(content) > 0 {
+		s.remember(ctx, key, content)
+		return content, nil
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		return nil, errors.NewAppError(errors.CodeServiceUnavailable, "Image encoding is busy; try again", "").WithCause(ctx.Err())
+	}
+
+	original, err := s.readBlob(ctx, s.originals, image.BlobKey)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to read image").WithCause(err)
+	}
+	started := s.now()
+	content, err := s.transcoder.Transcode(ctx, original, opts)
+	if err != nil {
+		return nil, errors.NewInternalError("failed to encode image").WithCause(err)
+	}
+	s.logger.Debug("Image variant encoded",
+		zap.String("key", key),
+		zap.Int("bytes", len(content)),
+		zap.Duration("took", s.now().Sub(started)),
+	)
+
+	if _, err := s.variants.Put(ctx, key, contentTypes[opts.Format], bytes.NewReader(content)); err != nil {
+		s.logger.Warn("Failed to cache image variant on disk", zap.String("key", key), zap.Error(err))
+	}
+	s.remember(ctx, key, content)
+	return content, nil
+}
+
+// remember keeps small variants in the shared cache
+func (s *Service) remember(ctx context.Context, key string, content []byte) {
+	if s.cache == nil || len(content) > s.cfg.MaxCachedSize {
+		return
+	}
+	if err := s.cache.Set(ctx, key, content, s.cfg.CacheTTL); err != nil {
+		s.logger.Warn("Failed to cache image variant", zap.String("key", key), zap.Error(err))
+	}
+}
+
+func (s *Service) readBlob(ctx context.Context, storage outbound.BlobStorage, key string) ([]byte, error) {
+	body, err := storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// negotiate picks the output format. An explicit format the transcoder
+// cannot encode, like auto, falls back to the first of
+// outbound.ImageFormatPreferences that Accept names, stopping at the
+// baseline formats: PNG for PNG originals and JPEG for everything else.
+func (s *Service) negotiate(requested, accept, originalType string) (string, error) {
+	switch format := strings.ToLower(requested); format {
+	case "jpg":
+		return FormatJPEG, nil
+	case FormatJPEG, FormatPNG:
+		return format, nil
+	case FormatWebP, FormatAVIF:
+		if s.transcoder.Supports(format) {
+			return format, nil
+		}
+	case "", formatAuto:
+	default:
+		return "", errors.NewBadRequestError("f must be auto, jpeg, png, webp or avif")
+	}
+
+	for _, format := range outbound.ImageFormatPreferences {
+		if format == FormatJPEG || format == FormatPNG {
+			break
+		}
+		if strings.Contains(accept, contentTypes[format]) && s.transcoder.Supports(format) {
+			return format, nil
+		}
+	}
+	if originalType == contentTypes[FormatPNG] {
+		return FormatPNG, nil
+	}
+	return FormatJPEG, nil
+}
+
+// snapWidth rounds a requested width up to the next configured width, or
+// picks the largest that fits the original when none was requested. Widths
+// past the original are capped to it, since images are never enlarged.
+func (s *Service) snapWidth(requested, original int) int {
+	widths := s.cfg.Widths
+	width := widths[len(widths)-1]
+	if requested <= 0 {
+		requested = original
+	}
+	for _, w := range widths {
+		if w >= requested {
+			width = w
+			break
+		}
+	}
+	if width > original {
+		return original
+	}
+	return width
+}
+
+// snapQuality picks the tier nearest the requested quality, or the middle
+// tier when none was requested
+func (s *Service) snapQuality(requested int) int {
+	tiers := s.cfg.QualityTiers
+	if requested <= 0 {
+		return tiers[len(tiers)/2]
+	}
+	best := tiers[0]
+	for _, q := range tiers[1:] {
+		if abs(q-requested) < abs(best-requested) {
+			best = q
+		}
+	}
+	return best
+}
+
+func (s *Service) toUploaded(image *outbound.StoredImage) *inbound.UploadedImage {
+	base := "/img/" + image.ID.String()
+	var srcset []string
+	for _, w := range s.cfg.Widths {
+		if w > image.Width {
+			break
+		}
+		srcset = append(srcset, fmt.Sprintf("%s?w=%d %dw", base, w, w))
+	}
+	if len(srcset) == 0 {
+		srcset = append(srcset, fmt.Sprintf("%s %dw", base, image.Width))
+	}
+	return &inbound.UploadedImage{
+		ID:          image.ID,
+		URL:         base,
+		SrcSet:      strings.Join(srcset, ", "),
+		ContentType: image.ContentType,
+		Width:       image.Width,
+		Height:      image.Height,
+		SizeBytes:   image.SizeBytes,
+		CreatedAt:   image.CreatedAt,
+	}
+}
+
+// sortedUnique orders the configured widths or tiers, dropping repeats
+func sortedUnique(values []int) []int {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	unique := sorted[:0]
+	for i, v := range sorted {
+		if i == 0 || v != sorted[i-1] {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,154 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memoryBlobs map[string][]byte
+
+func (m memoryBlobs) Put(_ context.Context, key, _ string, content io.Reader) (int64, error) {
+	data, err := io.ReadAll(content)
+	m[key] = data
+	return int64(len(data)), err
+}
+
+func (m memoryBlobs) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := m[key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+type memoryImages map[uuid.UUID]*outbound.StoredImage
+
+func (m memoryImages) Create(_ context.Context, image *outbound.StoredImage) error {
+	m[image.ID] = image
+	return nil
+}
+
+func (m memoryImages) FindByID(_ context.Context, id uuid.UUID) (*outbound.StoredImage, error) {
+	return m[id], nil
+}
+
+// fakeTranscoder reports every PNG as 1000x500 and encodes the options
+// as text
+type fakeTranscoder struct {
+	webp    bool
+	avif    bool
+	encoded []outbound.TranscodeOptions
+}
+
+func (f *fakeTranscoder) Probe(content []byte) (*outbound.ImageInfo, error) {
+	if !bytes.HasPrefix(content, []byte("PNG")) {
+		return nil, fmt.Errorf("not an image")
+	}
+	return &outbound.ImageInfo{Width: 1000, Height: 500, ContentType: "image/png"}, nil
+}
+
+func (f *fakeTranscoder) Transcode(_ context.Context, _ []byte, opts outbound.TranscodeOptions) ([]byte, error) {
+	f.encoded = append(f.encoded, opts)
+	return []byte(fmt.Sprintf("%+v", opts)), nil
+}
+
+func (f *fakeTranscoder) Supports(format string) bool {
+	return (format != FormatAVIF || f.avif) && (format != FormatWebP || f.webp)
+}
+
+func newTestService(transcoder *fakeTranscoder) *Service {
+	return NewService(memoryImages{}, memoryBlobs{}, memoryBlobs{}, nil, transcoder, Config{
+		Enabled:       true,
+		Widths:        []int{1200, 400, 800},
+		QualityTiers:  []int{85, 50, 70},
+		MaxUploadSize: 1 << 20,
+		MaxPixels:     1 << 20,
+		MaxConcurrent: 1,
+	}, zap.NewNop())
+}
+
+func TestVariantsSnapToConfiguredSizesAndAreEncodedOnce(t *testing.T) {
+	ctx := context.Background()
+	transcoder := &fakeTranscoder{webp: true}
+	svc := newTestService(transcoder)
+
+	uploaded, err := svc.UploadImage(ctx, inbound.UploadImageCommand{UserID: uuid.New(), Content: []byte("PNG pixels")})
+	require.NoError(t, err)
+	assert.Equal(t, "/img/"+uploaded.ID.String(), uploaded.URL)
+	assert.Equal(t, uploaded.URL+"?w=400 400w, "+uploaded.URL+"?w=800 800w", uploaded.SrcSet)
+
+	// The srcset URLs the LCP optimizer writes: w, h, f and q
+	req := inbound.ImageVariantRequest{Width: 750, Height: 375, Format: "webp", Quality: 80}
+	variant, err := svc.GetVariant(ctx, uploaded.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, "image/webp", variant.ContentType)
+	assert.Equal(t, 85, variant.Quality)
+	assert.Equal(t, []outbound.TranscodeOptions{{Width: 800, Height: 400, Format: FormatWebP, Quality: 85}}, transcoder.encoded)
+
+	again, err := svc.GetVariant(ctx, uploaded.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, variant.Content, again.Content)
+	assert.Equal(t, variant.ETag, again.ETag)
+	assert.Len(t, transcoder.encoded, 1, "the second request is served from the disk cache")
+
+	// Widths past the original are capped to it
+	_, err = svc.GetVariant(ctx, uploaded.ID, inbound.ImageVariantRequest{Width: 1920})
+	require.NoError(t, err)
+	assert.Equal(t, outbound.TranscodeOptions{Width: 1000, Format: FormatPNG, Quality: 70}, transcoder.encoded[1])
+}
+
+func TestVariantFormatFallsBackToWhatCanBeEncoded(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(&fakeTranscoder{})
+	uploaded, err := svc.UploadImage(ctx, inbound.UploadImageCommand{UserID: uuid.New(), Content: []byte("PNG pixels")})
+	require.NoError(t, err)
+
+	variant, err := svc.GetVariant(ctx, uploaded.ID, inbound.ImageVariantRequest{Format: "avif", Accept: "image/avif,image/webp,*/*"})
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", variant.ContentType)
+
+	variant, err = svc.GetVariant(ctx, uploaded.ID, inbound.ImageVariantRequest{Format: "jpg"})
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", variant.ContentType)
+
+	_, err = svc.GetVariant(ctx, uploaded.ID, inbound.ImageVariantRequest{Format: "tiff"})
+	assert.Equal(t, errors.CodeBadRequest, errors.GetCode(err))
+	_, err = svc.GetVariant(ctx, uuid.New(), inbound.ImageVariantRequest{})
+	assert.Equal(t, errors.CodeNotFound, errors.GetCode(err))
+	_, err = svc.UploadImage(ctx, inbound.UploadImageCommand{UserID: uuid.New(), Content: []byte("GIF89a")})
+	assert.Equal(t, errors.CodeBadRequest, errors.GetCode(err))
+}
+
+func TestVariantFormatFollowsThePreferenceOrder(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(&fakeTranscoder{webp: true, avif: true})
+	uploaded, err := svc.UploadImage(ctx, inbound.UploadImageCommand{UserID: uuid.New(), Content: []byte("PNG pixels")})
+	require.NoError(t, err)
+
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"image/webp,image/avif,*/*", "image/avif"},
+		{"image/webp,*/*", "image/webp"},
+		{"image/jpeg,image/png", "image/png"},
+		{"", "image/png"},
+	}
+	for _, tt := range tests {
+		variant, err := svc.GetVariant(ctx, uploaded.ID, inbound.ImageVariantRequest{Accept: tt.accept})
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, variant.ContentType, "Accept: %s", tt.accept)
+	}
+}
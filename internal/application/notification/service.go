@@ -0,0 +1,252 @@
+// Package notification delivers in-app notifications. Other services call
+// Notify when something happens to a user; the nav badge polls the unread
+// count and the profile page lets users turn each type off.
+package notification
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alchemorsel/v3/internal/domain/notification"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	// maxMarkRead bounds the ids one MarkRead call accepts
+	maxMarkRead = 100
+)
+
+// Service implements inbound.NotificationService
+type Service struct {
+	notifications outbound.NotificationRepository
+	userRepo      outbound.UserRepository
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewService creates the notification service
+func NewService(notifications outbound.NotificationRepository, userRepo outbound.UserRepository, logger *zap.Logger) *Service {
+	return &Service{
+		notifications: notifications,
+		userRepo:      userRepo,
+		logger:        logger.Named("notification"),
+		now:           time.Now,
+	}
+}
+
+// Notify stores a notification for cmd.UserID
+func (s *Service) Notify(ctx context.Context, cmd inbound.NotifyCommand) error {
+	if cmd.ActorID != nil && *cmd.ActorID == cmd.UserID {
+		return nil
+	}
+
+	ids := []uuid.UUID{cmd.UserID}
+	if cmd.ActorID != nil {
+		ids = append(ids, *cmd.ActorID)
+	}
+	accounts, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return errors.NewDatabaseError("find users", err)
+	}
+	var recipient, actor *user.User
+	for _, a := range accounts {
+		if a.ID() == cmd.UserID {
+			recipient = a
+		} else {
+			actor = a
+		}
+	}
+	if recipient == nil || !recipient.IsActive() || recipient.NotificationMuted(string(cmd.Type)) {
+		return nil
+	}
+
+	n, err := notification.New(cmd.UserID, cmd.ActorID, cmd.Type, title(cmd.Type, actor, cmd.Subject), excerpt(cmd.Message), cmd.Link, s.now().UTC())
+	if err != nil {
+		return errors.NewValidationError(err.Error())
+	}
+	if err := s.notifications.Save(ctx, n); err != nil {
+		return errors.NewDatabaseError("save notification", err)
+	}
+	s.logger.Debug("Notification stored",
+		zap.String("user_id", cmd.UserID.String()),
+		zap.String("type", string(cmd.Type)),
+	)
+	return nil
+}
+
+// List returns a page of userID's notifications
+func (s *Service) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, params inbound.PaginationParams) (*inbound.NotificationList, error) {
+	if params.PageSize <= 0 {
+		params.PageSize = defaultPageSize
+	}
+	if params.PageSize > maxPageSize {
+		params.PageSize = maxPageSize
+	}
+	if params.Page < 0 {
+		params.Page = 0
+	}
+
+	found, err := s.notifications.List(ctx, userID, unreadOnly, params.Page*params.PageSize, params.PageSize)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list notifications", err)
+	}
+	unread, err := s.notifications.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("count notifications", err)
+	}
+
+	list := &inbound.NotificationList{
+		Notifications: make([]inbound.NotificationDTO, 0, len(found)),
+		Unread:        unread,
+		Page:          params.Page,
+		PageSize:      params.PageSize,
+	}
+	for _, n := range found {
+		list.Notifications = append(list.Notifications, inbound.NotificationDTO{
+			ID:        n.ID,
+			Type:      string(n.Type),
+			Title:     n.Title,
+			Message:   n.Message,
+			Link:      n.Link,
+			ActorID:   n.ActorID,
+			Read:      n.IsRead(),
+			CreatedAt: n.CreatedAt,
+		})
+	}
+	return list, nil
+}
+
+// UnreadCount counts userID's unread notifications
+func (s *Service) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	unread, err := s.notifications.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, errors.NewDatabaseError("count notifications", err)
+	}
+	return unread, nil
+}
+
+// MarkRead marks some of userID's notifications read
+func (s *Service) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	if len(ids) == 0 {
+		return 0, errors.NewBadRequestError("ids must not be empty")
+	}
+	if len(ids) > maxMarkRead {
+		return 0, errors.NewBadRequestError("at most 100 notifications can be marked read at once")
+	}
+	if _, err := s.notifications.MarkRead(ctx, userID, ids, s.now().UTC()); err != nil {
+		return 0, errors.NewDatabaseError("mark notifications read", err)
+	}
+	return s.UnreadCount(ctx, userID)
+}
+
+// MarkAllRead marks all of userID's notifications read
+func (s *Service) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	if _, err := s.notifications.MarkAllRead(ctx, userID, s.now().UTC()); err != nil {
+		return errors.NewDatabaseError("mark notifications read", err)
+	}
+	return nil
+}
+
+// Preferences lists whether userID receives each type
+func (s *Service) Preferences(ctx context.Context, userID uuid.UUID) ([]inbound.NotificationPreference, error) {
+	account, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return preferences(account), nil
+}
+
+// SetPreferences turns notification types on or off for userID
+func (s *Service) SetPreferences(ctx context.Context, userID uuid.UUID, enabled map[string]bool) ([]inbound.NotificationPreference, error) {
+	for t := range enabled {
+		if !notification.Type(t).Valid() {
+			return nil, errors.NewBadRequestError(notification.ErrInvalidType.Error())
+		}
+	}
+	account, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for t, on := range enabled {
+		account.SetNotificationMuted(t, !on)
+	}
+	if err := s.userRepo.Update(ctx, account); err != nil {
+		return nil, errors.NewDatabaseError("update notification preferences", err)
+	}
+	s.logger.Info("Notification preferences updated", zap.String("user_id", userID.String()))
+	return preferences(account), nil
+}
+
+func (s *Service) findUser(ctx context.Context, userID uuid.UUID) (*user.User, error) {
+	accounts, err := s.userRepo.FindByIDs(ctx, []uuid.UUID{userID})
+	if err != nil {
+		return nil, errors.NewDatabaseError("find user", err)
+	}
+	if len(accounts) == 0 {
+		return nil, errors.NewUserNotFoundError(userID.String())
+	}
+	return accounts[0], nil
+}
+
+func preferences(account *user.User) []inbound.NotificationPreference {
+	types := notification.Types()
+	prefs := make([]inbound.NotificationPreference, 0, len(types))
+	for _, t := range types {
+		prefs = append(prefs, inbound.NotificationPreference{
+			Type:    string(t),
+			Label:   t.Label(),
+			Enabled: !account.NotificationMuted(string(t)),
+		})
+	}
+	return prefs
+}
+
+// title writes the notification's headline, such as "Ada liked Lemon tart"
+func title(t notification.Type, actor *user.User, subject string) string {
+	name := "Someone"
+	if actor != nil && strings.TrimSpace(actor.Name()) != "" {
+		name = actor.Name()
+	}
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		subject = "your recipe"
+	}
+
+	var headline string
+	switch t {
+	case notification.TypeNewFollower:
+		headline = name + " started following you"
+	case notification.TypeRecipeLiked:
+		headline = name + " liked " + subject
+	case notification.TypeRecipeCommented:
+		headline = name + " commented on " + subject
+	case notification.TypeAIRecipeReady:
+		headline = "Your AI recipe is ready: " + subject
+	default:
+		headline = subject
+	}
+	return truncate(headline, notification.MaxTitleLength)
+}
+
+// excerpt shortens long detail such as a comment to fit the message
+func excerpt(message string) string {
+	return truncate(strings.TrimSpace(message), 280)
+}
+
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
@@ -0,0 +1,127 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/notification"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memoryNotifications struct {
+	saved []*notification.Notification
+}
+
+func (m *memoryNotifications) Save(ctx context.Context, n *notification.Notification) error {
+	m.saved = append([]*notification.Notification{n}, m.saved...)
+	return nil
+}
+
+func (m *memoryNotifications) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, offset, limit int) ([]*notification.Notification, error) {
+	var found []*notification.Notification
+	for _, n := range m.saved {
+		if n.UserID == userID && (!unreadOnly || !n.IsRead()) {
+			found = append(found, n)
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryNotifications) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	found, _ := m.List(ctx, userID, true, 0, 0)
+	return len(found), nil
+}
+
+func (m *memoryNotifications) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int, error) {
+	marked := 0
+	for _, n := range m.saved {
+		for _, id := range ids {
+			if n.ID == id && n.UserID == userID && !n.IsRead() {
+				n.ReadAt = &at
+				marked++
+			}
+		}
+	}
+	return marked, nil
+}
+
+func (m *memoryNotifications) MarkAllRead(ctx context.Context, userID uuid.UUID, at time.Time) (int, error) {
+	marked := 0
+	for _, n := range m.saved {
+		if n.UserID == userID && !n.IsRead() {
+			n.ReadAt = &at
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*user.User, error) {
+	var found []*user.User
+	for _, id := range ids {
+		if u, ok := s.users[id]; ok {
+			found = append(found, u)
+		}
+	}
+	return found, nil
+}
+
+func (s *stubUsers) Update(ctx context.Context, u *user.User) error {
+	s.users[u.ID()] = u
+	return nil
+}
+
+func TestNotifySkipsSelfActionsAndMutedTypes(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	chef := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	fan := user.ReconstructUser(uuid.New(), "bo@example.com", "Bo", "", true, true, user.UserRoleUser, now, now, nil)
+	store := &memoryNotifications{}
+	svc := NewService(store, &stubUsers{users: map[uuid.UUID]*user.User{chef.ID(): chef, fan.ID(): fan}}, zap.NewNop())
+	ctx := context.Background()
+	chefID, fanID := chef.ID(), fan.ID()
+
+	require.NoError(t, svc.Notify(ctx, inbound.NotifyCommand{
+		UserID: chefID, ActorID: &fanID, Type: notification.TypeRecipeLiked, Subject: "Lemon tart", Link: "/recipes/1",
+	}))
+	require.NoError(t, svc.Notify(ctx, inbound.NotifyCommand{
+		UserID: chefID, ActorID: &chefID, Type: notification.TypeRecipeLiked, Subject: "Lemon tart",
+	}))
+	require.Len(t, store.saved, 1, "liking your own recipe does not notify")
+	assert.Equal(t, "Bo liked Lemon tart", store.saved[0].Title)
+
+	prefs, err := svc.SetPreferences(ctx, chefID, map[string]bool{string(notification.TypeNewFollower): false})
+	require.NoError(t, err)
+	assert.False(t, prefs[0].Enabled)
+	assert.True(t, prefs[1].Enabled)
+	require.NoError(t, svc.Notify(ctx, inbound.NotifyCommand{UserID: chefID, ActorID: &fanID, Type: notification.TypeNewFollower}))
+	assert.Len(t, store.saved, 1, "muted types are dropped")
+	_, err = svc.SetPreferences(ctx, chefID, map[string]bool{"newsletter": true})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	require.NoError(t, svc.Notify(ctx, inbound.NotifyCommand{UserID: chefID, Type: notification.TypeAIRecipeReady, Subject: "Miso soup"}))
+	list, err := svc.List(ctx, chefID, false, inbound.PaginationParams{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Unread)
+	assert.Equal(t, "Your AI recipe is ready: Miso soup", list.Notifications[0].Title)
+
+	unread, err := svc.MarkRead(ctx, chefID, []uuid.UUID{list.Notifications[0].ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, unread)
+	require.NoError(t, svc.MarkAllRead(ctx, chefID))
+	unread, err = svc.UnreadCount(ctx, chefID)
+	require.NoError(t, err)
+	assert.Zero(t, unread)
+}
@@ -0,0 +1,262 @@
+// Package offline syncs a user's bookmarks and notes across their devices,
+// and points the devices at the shopping lists and recipe drafts they should
+// refresh. Devices queue changes while offline and push them on reconnect;
+// concurrent edits are merged field by field, last write wins. Pulls page
+// through the user's change sequence from the device's cursor.
+package offline
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/offline"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxPushChanges bounds the changes accepted in one push; a device with
+	// a longer queue sends it in several
+	maxPushChanges   = 200
+	defaultPullLimit = 200
+	maxPullLimit     = 1000
+	// saveAttempts retries a change that raced with another device
+	saveAttempts = 3
+	// DefaultTombstoneRetention is how long deletes are kept for devices
+	// that have been offline. Devices away longer start again from zero.
+	DefaultTombstoneRetention = 30 * 24 * time.Hour
+)
+
+// OutcomeRejected marks a change that was invalid, such as an unknown
+// field. The rest of the push is still applied.
+const OutcomeRejected = "rejected"
+
+// Service implements inbound.SyncService and outbound.SyncFeed
+type Service struct {
+	records   outbound.SyncRepository
+	retention time.Duration
+	now       func() time.Time
+	logger    *zap.Logger
+}
+
+// NewService creates a sync service; retention defaults to
+// DefaultTombstoneRetention
+func NewService(records outbound.SyncRepository, retention time.Duration, logger *zap.Logger) *Service {
+	if retention <= 0 {
+		retention = DefaultTombstoneRetention
+	}
+	return &Service{
+		records:   records,
+		retention: retention,
+		now:       time.Now,
+		logger:    logger.Named("sync"),
+	}
+}
+
+// Push merges each change into its record. Every change gets its own
+// outcome; an invalid one is rejected without failing the others.
+func (s *Service) Push(ctx context.Context, cmd inbound.SyncPushCommand) (*inbound.SyncPushResult, error) {
+	if !offline.ValidDevice(cmd.DeviceID) {
+		return nil, errors.NewBadRequestError(offline.ErrInvalidDevice.Error())
+	}
+	if len(cmd.Changes) == 0 {
+		return nil, errors.NewBadRequestError("at least one change is required")
+	}
+	if len(cmd.Changes) > maxPushChanges {
+		return nil, errors.NewBadRequestError("too many changes in one push")
+	}
+
+	result := &inbound.SyncPushResult{Outcomes: make([]inbound.SyncOutcome, 0, len(cmd.Changes))}
+	for _, c := range cmd.Changes {
+		change := offline.Change{
+			Kind:   offline.Kind(c.Kind),
+			Key:    c.Key,
+			Fields: c.Fields,
+			Delete: c.Delete,
+			At:     c.At,
+		}
+		if err := change.Validate(); err != nil {
+			result.Outcomes = append(result.Outcomes, inbound.SyncOutcome{
+				Kind:    c.Kind,
+				Key:     c.Key,
+				Outcome: OutcomeRejected,
+				Error:   err.Error(),
+			})
+			continue
+		}
+
+		record, outcome, err := s.merge(ctx, cmd.UserID, cmd.DeviceID, change, true)
+		if err != nil {
+			return nil, err
+		}
+		dto := recordToDTO(record)
+		result.Outcomes = append(result.Outcomes, inbound.SyncOutcome{
+			Kind:    c.Kind,
+			Key:     c.Key,
+			Outcome: string(outcome),
+			Record:  &dto,
+		})
+	}
+	return result, nil
+}
+
+// Pull returns the records saved after the cursor and remembers the cursor
+// as how far the device has synced, which holds back purging the
+// tombstones it has yet to see
+func (s *Service) Pull(ctx context.Context, query inbound.SyncPullQuery) (*inbound.SyncPullResult, error) {
+	if !offline.ValidDevice(query.DeviceID) {
+		return nil, errors.NewBadRequestError(offline.ErrInvalidDevice.Error())
+	}
+	if query.Cursor < 0 {
+		return nil, errors.NewBadRequestError("cursor must not be negative")
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultPullLimit
+	}
+	if limit > maxPullLimit {
+		limit = maxPullLimit
+	}
+
+	latest, purged, err := s.records.Horizon(ctx, query.UserID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find sync horizon", err)
+	}
+	result := &inbound.SyncPullResult{Records: []inbound.SyncRecordDTO{}, Cursor: query.Cursor}
+	if query.Cursor > latest || (query.Cursor > 0 && query.Cursor < purged) {
+		result.Reset = true
+		result.Cursor = 0
+		return result, nil
+	}
+
+	if err := s.records.SaveDeviceCursor(ctx, query.UserID, query.DeviceID, query.Cursor, s.now()); err != nil {
+		s.logger.Warn("Failed to save device cursor",
+			zap.String("user_id", query.UserID.String()),
+			zap.Error(err),
+		)
+	}
+	if query.Cursor == latest {
+		return result, nil
+	}
+
+	records, err := s.records.FindSince(ctx, query.UserID, query.Cursor, limit+1)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find sync records", err)
+	}
+	if len(records) > limit {
+		records = records[:limit]
+		result.HasMore = true
+	}
+	for _, record := range records {
+		result.Cursor = record.Seq
+		// A device starting from zero has nothing to delete
+		if query.Cursor == 0 && record.Deleted() {
+			continue
+		}
+		result.Records = append(result.Records, recordToDTO(record))
+	}
+	return result, nil
+}
+
+// PurgeTombstones deletes tombstones older than the retention that every
+// device seen within it has pulled
+func (s *Service) PurgeTombstones(ctx context.Context) (int, error) {
+	purged, err := s.records.PurgeTombstones(ctx, s.now().Add(-s.retention))
+	if err != nil {
+		return purged, errors.NewDatabaseError("purge sync tombstones", err)
+	}
+	return purged, nil
+}
+
+// Touch writes server fields on a pointer record, creating it if needed
+func (s *Service) Touch(ctx context.Context, userID uuid.UUID, kind offline.Kind, key string, fields map[string]interface{}) {
+	change := offline.Change{Kind: kind, Key: key, Fields: make(map[string]json.RawMessage, len(fields))}
+	for name, value := range fields {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			s.logger.Error("Failed to encode sync field",
+				zap.String("kind", string(kind)),
+				zap.String("field", name),
+				zap.Error(err),
+			)
+			return
+		}
+		change.Fields[name] = raw
+	}
+	s.write(ctx, userID, change)
+}
+
+// Remove turns a pointer record into a tombstone. Records that were never
+// written are left alone.
+func (s *Service) Remove(ctx context.Context, userID uuid.UUID, kind offline.Kind, key string) {
+	s.write(ctx, userID, offline.Change{Kind: kind, Key: key, Delete: true})
+}
+
+func (s *Service) write(ctx context.Context, userID uuid.UUID, change offline.Change) {
+	if _, _, err := s.merge(ctx, userID, offline.ServerDevice, change, !change.Delete); err != nil {
+		s.logger.Warn("Failed to update sync record",
+			zap.String("user_id", userID.String()),
+			zap.String("kind", string(change.Kind)),
+			zap.String("key", change.Key),
+			zap.Error(err),
+		)
+	}
+}
+
+// merge applies the change to the stored record and saves it, retrying
+// when another device saved the record first. Without create, a missing
+// record is left missing.
+func (s *Service) merge(ctx context.Context, userID uuid.UUID, device string, change offline.Change, create bool) (*offline.Record, offline.Outcome, error) {
+	for attempt := 1; ; attempt++ {
+		record, err := s.records.FindRecord(ctx, userID, change.Kind, change.Key)
+		if err != nil {
+			return nil, "", errors.NewDatabaseError("find sync record", err)
+		}
+		if record == nil {
+			if !create {
+				return nil, offline.OutcomeUnchanged, nil
+			}
+			record = offline.NewRecord(userID, change.Kind, change.Key)
+		}
+
+		expected := record.Seq
+		outcome, changed := record.Merge(device, change, s.now())
+		if !changed {
+			return record, outcome, nil
+		}
+
+		err = s.records.SaveRecord(ctx, record, expected)
+		if stderrors.Is(err, offline.ErrStaleRecord) {
+			if attempt < saveAttempts {
+				continue
+			}
+			return nil, "", errors.NewConflictError("sync record is busy, please retry")
+		}
+		if err != nil {
+			return nil, "", errors.NewDatabaseError("save sync record", err)
+		}
+		return record, outcome, nil
+	}
+}
+
+func recordToDTO(record *offline.Record) inbound.SyncRecordDTO {
+	dto := inbound.SyncRecordDTO{
+		Kind:       string(record.Kind),
+		Key:        record.Key,
+		Fields:     make(map[string]json.RawMessage, len(record.Fields)),
+		FieldTimes: make(map[string]time.Time, len(record.Fields)),
+		Deleted:    record.Deleted(),
+		Seq:        record.Seq,
+		UpdatedAt:  record.UpdatedAt,
+	}
+	for name, field := range record.Fields {
+		dto.Fields[name] = field.Value
+		dto.FieldTimes[name] = field.At
+	}
+	return dto
+}
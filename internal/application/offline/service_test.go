@@ -0,0 +1,168 @@
+package offline
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/offline"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryRecords keeps one user's records, saved copies only
+type memoryRecords struct {
+	records map[string]offline.Record
+	seq     int64
+	purged  int64
+	cursors map[string]int64
+}
+
+func newMemoryRecords() *memoryRecords {
+	return &memoryRecords{records: make(map[string]offline.Record), cursors: make(map[string]int64)}
+}
+
+func (m *memoryRecords) FindRecord(ctx context.Context, userID uuid.UUID, kind offline.Kind, key string) (*offline.Record, error) {
+	stored, ok := m.records[string(kind)+"/"+key]
+	if !ok {
+		return nil, nil
+	}
+	record := stored
+	record.Fields = make(map[string]offline.Field, len(stored.Fields))
+	for name, field := range stored.Fields {
+		record.Fields[name] = field
+	}
+	return &record, nil
+}
+
+func (m *memoryRecords) SaveRecord(ctx context.Context, record *offline.Record, expectedSeq int64) error {
+	id := string(record.Kind) + "/" + record.Key
+	if m.records[id].Seq != expectedSeq {
+		return offline.ErrStaleRecord
+	}
+	m.seq++
+	record.Seq = m.seq
+	m.records[id] = *record
+	return nil
+}
+
+func (m *memoryRecords) FindSince(ctx context.Context, userID uuid.UUID, cursor int64, limit int) ([]*offline.Record, error) {
+	var records []*offline.Record
+	for seq := cursor + 1; seq <= m.seq && len(records) < limit; seq++ {
+		for _, stored := range m.records {
+			if stored.Seq == seq {
+				record := stored
+				records = append(records, &record)
+			}
+		}
+	}
+	return records, nil
+}
+
+func (m *memoryRecords) Horizon(ctx context.Context, userID uuid.UUID) (int64, int64, error) {
+	return m.seq, m.purged, nil
+}
+
+func (m *memoryRecords) SaveDeviceCursor(ctx context.Context, userID uuid.UUID, deviceID string, cursor int64, seenAt time.Time) error {
+	m.cursors[deviceID] = cursor
+	return nil
+}
+
+func (m *memoryRecords) PurgeTombstones(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func newTestService() (*Service, *memoryRecords) {
+	records := newMemoryRecords()
+	svc := NewService(records, 0, zap.NewNop())
+	svc.now = func() time.Time { return time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) }
+	return svc, records
+}
+
+func noteChange(minute int, fields string) inbound.SyncChange {
+	change := inbound.SyncChange{Kind: "note", Key: "stock", At: time.Date(2026, 10, 18, 9, minute, 0, 0, time.UTC)}
+	if fields == "" {
+		change.Delete = true
+		return change
+	}
+	_ = json.Unmarshal([]byte(fields), &change.Fields)
+	return change
+}
+
+func TestPushMergesOfflineEditsFromTwoDevices(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	result, err := svc.Push(ctx, inbound.SyncPushCommand{UserID: userID, DeviceID: "phone", Changes: []inbound.SyncChange{
+		noteChange(0, `{"title": "Stock", "text": "Roast the bones"}`),
+		noteChange(20, `{"text": "Roast the bones hard"}`),
+		{Kind: "note", Key: "stock", Fields: map[string]json.RawMessage{"colour": json.RawMessage(`"red"`)}},
+	}})
+	require.NoError(t, err)
+	require.Len(t, result.Outcomes, 3)
+	assert.Equal(t, OutcomeRejected, result.Outcomes[2].Outcome)
+	assert.Equal(t, offline.ErrUnknownField.Error(), result.Outcomes[2].Error)
+
+	// The tablet renamed the note at 09:10 and edited the text at 09:05
+	result, err = svc.Push(ctx, inbound.SyncPushCommand{UserID: userID, DeviceID: "tablet", Changes: []inbound.SyncChange{
+		noteChange(10, `{"title": "Brown stock", "text": "Boil the bones"}`),
+	}})
+	require.NoError(t, err)
+	outcome := result.Outcomes[0]
+	assert.Equal(t, string(offline.OutcomeConflict), outcome.Outcome)
+	assert.JSONEq(t, `"Brown stock"`, string(outcome.Record.Fields["title"]))
+	assert.JSONEq(t, `"Roast the bones hard"`, string(outcome.Record.Fields["text"]))
+
+	_, err = svc.Push(ctx, inbound.SyncPushCommand{UserID: userID, DeviceID: offline.ServerDevice, Changes: []inbound.SyncChange{noteChange(30, "")}})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest), "devices cannot pose as the server")
+}
+
+func TestPullPagesThroughChangesAndResetsLostCursors(t *testing.T) {
+	svc, records := newTestService()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	_, err := svc.Push(ctx, inbound.SyncPushCommand{UserID: userID, DeviceID: "phone", Changes: []inbound.SyncChange{
+		noteChange(0, `{"title": "Stock"}`),
+		{Kind: "bookmark", Key: uuid.NewString(), Fields: map[string]json.RawMessage{"pinned": json.RawMessage("true")}},
+		noteChange(5, ""),
+	}})
+	require.NoError(t, err)
+	listID := uuid.NewString()
+	svc.Touch(ctx, userID, offline.KindShoppingList, listID, map[string]interface{}{"version": 4})
+	svc.Remove(ctx, userID, offline.KindDraft, uuid.NewString())
+
+	page, err := svc.Pull(ctx, inbound.SyncPullQuery{UserID: userID, DeviceID: "tablet", Limit: 2})
+	require.NoError(t, err)
+	assert.True(t, page.HasMore)
+	assert.Equal(t, int64(3), page.Cursor)
+	require.Len(t, page.Records, 1, "a fresh device skips the deleted note")
+	assert.Equal(t, "bookmark", page.Records[0].Kind)
+
+	page, err = svc.Pull(ctx, inbound.SyncPullQuery{UserID: userID, DeviceID: "tablet", Cursor: page.Cursor})
+	require.NoError(t, err)
+	assert.False(t, page.HasMore)
+	require.Len(t, page.Records, 1, "removing a draft that was never synced writes nothing")
+	assert.Equal(t, listID, page.Records[0].Key)
+	assert.JSONEq(t, `4`, string(page.Records[0].Fields["version"]))
+	assert.Equal(t, int64(3), records.cursors["tablet"])
+
+	phone, err := svc.Pull(ctx, inbound.SyncPullQuery{UserID: userID, DeviceID: "phone", Cursor: 1})
+	require.NoError(t, err)
+	require.Len(t, phone.Records, 3)
+	assert.True(t, phone.Records[1].Deleted, "devices that saw the note get its tombstone")
+
+	records.purged = 2
+	lost, err := svc.Pull(ctx, inbound.SyncPullQuery{UserID: userID, DeviceID: "phone", Cursor: 1})
+	require.NoError(t, err)
+	assert.True(t, lost.Reset)
+	ahead, err := svc.Pull(ctx, inbound.SyncPullQuery{UserID: userID, DeviceID: "phone", Cursor: 99})
+	require.NoError(t, err)
+	assert.True(t, ahead.Reset)
+}
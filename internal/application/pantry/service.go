@@ -0,0 +1,351 @@
+// Package pantry keeps users' pantry inventory and depletes it when they
+// cook. Marking a recipe as cooked is previewed first: the recipe's
+// ingredients are matched to pantry items and converted to their units,
+// and the cook confirms or edits the amounts before anything is deducted.
+// Items that run low are suggested as restocks and can go straight onto a
+// shopping list; every cook is logged for consumption history.
+package pantry
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/pantry"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+	"github.com/alchemorsel/v3/internal/domain/recipe/units"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxPortions bounds the batch a cook is scaled to
+	maxPortions = 1000
+	// defaultHistoryDays and maxHistoryDays bound consumption history
+	defaultHistoryDays = 30
+	maxHistoryDays     = 365
+)
+
+// Service implements inbound.PantryService
+type Service struct {
+	pantry   outbound.PantryRepository
+	recipes  outbound.RecipeRepository
+	listFeed outbound.ShoppingListFeed
+	now      func() time.Time
+	logger   *zap.Logger
+}
+
+// NewService creates a pantry service. The list feed, if any, adds
+// restocks to the shopping list a cook names.
+func NewService(pantryRepo outbound.PantryRepository, recipes outbound.RecipeRepository, listFeed outbound.ShoppingListFeed, logger *zap.Logger) *Service {
+	return &Service{
+		pantry:   pantryRepo,
+		recipes:  recipes,
+		listFeed: listFeed,
+		now:      time.Now,
+		logger:   logger.Named("pantry"),
+	}
+}
+
+// ListItems returns the user's pantry by name
+func (s *Service) ListItems(ctx context.Context, userID uuid.UUID) ([]inbound.PantryItemDTO, error) {
+	items, err := s.pantry.FindItems(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find pantry items", err)
+	}
+	dtos := make([]inbound.PantryItemDTO, len(items))
+	for i, item := range items {
+		dtos[i] = itemToDTO(item)
+	}
+	return dtos, nil
+}
+
+// SaveItem creates an item, or updates the user's item when ItemID is set.
+// Units are read the way recipes write them, so "grams" is stored as "g".
+func (s *Service) SaveItem(ctx context.Context, cmd inbound.SavePantryItemCommand) (*inbound.PantryItemDTO, error) {
+	var unit recipe.MeasurementUnit
+	if cmd.Unit != "" {
+		parsed, ok := ingredients.ParseUnit(cmd.Unit)
+		if !ok {
+			return nil, errors.NewBadRequestError("unknown unit " + cmd.Unit)
+		}
+		unit = parsed
+	}
+
+	var item *pantry.Item
+	if cmd.ItemID != nil {
+		found, err := s.pantry.FindItem(ctx, cmd.UserID, *cmd.ItemID)
+		if err != nil {
+			return nil, errors.NewDatabaseError("find pantry item", err)
+		}
+		if found == nil {
+			return nil, errors.NewNotFoundError("pantry item")
+		}
+		if err := found.Update(cmd.Name, cmd.Quantity, unit, cmd.Par); err != nil {
+			return nil, errors.NewBadRequestError(err.Error())
+		}
+		item = found
+	} else {
+		count, err := s.pantry.CountItems(ctx, cmd.UserID)
+		if err != nil {
+			return nil, errors.NewDatabaseError("count pantry items", err)
+		}
+		if count >= pantry.MaxItems {
+			return nil, errors.NewBadRequestError(pantry.ErrTooManyItems.Error())
+		}
+		item, err = pantry.NewItem(cmd.UserID, cmd.Name, cmd.Quantity, unit, cmd.Par)
+		if err != nil {
+			return nil, errors.NewBadRequestError(err.Error())
+		}
+	}
+
+	if err := s.pantry.SaveItem(ctx, item); err != nil {
+		return nil, errors.NewDatabaseError("save pantry item", err)
+	}
+	dto := itemToDTO(item)
+	return &dto, nil
+}
+
+// DeleteItem removes an item from the user's pantry
+func (s *Service) DeleteItem(ctx context.Context, userID, itemID uuid.UUID) error {
+	deleted, err := s.pantry.DeleteItem(ctx, userID, itemID)
+	if err != nil {
+		return errors.NewDatabaseError("delete pantry item", err)
+	}
+	if !deleted {
+		return errors.NewNotFoundError("pantry item")
+	}
+	return nil
+}
+
+// PreviewCook matches the recipe, scaled to the portions, against the
+// pantry and lists what would run low afterwards
+func (s *Service) PreviewCook(ctx context.Context, query inbound.CookPreviewQuery) (*inbound.CookPreview, error) {
+	entity, portions, err := s.cookableRecipe(ctx, query.UserID, query.RecipeID, query.Portions)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.pantry.FindItems(ctx, query.UserID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find pantry items", err)
+	}
+
+	servings := entity.Servings()
+	if servings <= 0 {
+		servings = 1
+	}
+	plan := pantry.PlanCook(items, entity.Ingredients(), float64(portions)/float64(servings))
+
+	preview := &inbound.CookPreview{
+		RecipeID:   entity.ID(),
+		Portions:   portions,
+		Deductions: make([]inbound.PantryDeductionDTO, len(plan.Deductions)),
+		Unmatched:  make([]inbound.UnmatchedIngredient, len(plan.Unmatched)),
+		Restock:    []inbound.RestockSuggestionDTO{},
+	}
+	byID := make(map[uuid.UUID]*pantry.Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	for i, d := range plan.Deductions {
+		preview.Deductions[i] = inbound.PantryDeductionDTO{
+			ItemID:     d.ItemID,
+			ItemName:   d.ItemName,
+			Ingredient: d.Ingredient,
+			Amount:     d.Amount,
+			Unit:       string(d.Unit),
+			Short:      d.Short,
+		}
+		after := *byID[d.ItemID]
+		after.Quantity = math.Max(after.Quantity-d.Amount, 0)
+		if restock := after.Restock(d.Amount); restock > 0 {
+			preview.Restock = append(preview.Restock, restockToDTO(&after, restock))
+		}
+	}
+	for i, u := range plan.Unmatched {
+		preview.Unmatched[i] = inbound.UnmatchedIngredient{Ingredient: u.Ingredient, ItemID: u.ItemID, Reason: u.Reason}
+	}
+	return preview, nil
+}
+
+// CompleteCook logs the cook and takes the confirmed amounts from the
+// pantry. Restocks for items that ran low are returned and, when the cook
+// names a shopping list, added to it; a list that cannot be written is
+// logged rather than failing the cook, which is already saved.
+func (s *Service) CompleteCook(ctx context.Context, cmd inbound.CompleteCookCommand) (*inbound.CookResult, error) {
+	if len(cmd.Deductions) > pantry.MaxItems {
+		return nil, errors.NewBadRequestError("too many deductions")
+	}
+	entity, portions, err := s.cookableRecipe(ctx, cmd.UserID, cmd.RecipeID, cmd.Portions)
+	if err != nil {
+		return nil, err
+	}
+
+	cook := pantry.NewCook(cmd.UserID, entity.ID(), portions, s.now().UTC())
+	byID := map[uuid.UUID]*pantry.Item{}
+	if len(cmd.Deductions) > 0 {
+		items, err := s.pantry.FindItems(ctx, cmd.UserID)
+		if err != nil {
+			return nil, errors.NewDatabaseError("find pantry items", err)
+		}
+		for _, item := range items {
+			byID[item.ID] = item
+		}
+	}
+	for _, d := range cmd.Deductions {
+		item, ok := byID[d.ItemID]
+		if !ok {
+			return nil, errors.NewNotFoundError("pantry item")
+		}
+		if err := cook.Take(item, d.Amount); err != nil {
+			return nil, errors.NewBadRequestError(err.Error())
+		}
+	}
+
+	if err := s.pantry.RecordCook(ctx, cook); err != nil {
+		return nil, errors.NewDatabaseError("record cook", err)
+	}
+
+	result := &inbound.CookResult{
+		CookID:   cook.ID,
+		RecipeID: cook.RecipeID,
+		Portions: cook.Portions,
+		CookedAt: cook.CookedAt,
+		Items:    []inbound.PantryItemDTO{},
+		Restock:  []inbound.RestockSuggestionDTO{},
+	}
+	var additions []outbound.ShoppingListAddition
+	for _, used := range cook.Used {
+		item, err := s.pantry.FindItem(ctx, cmd.UserID, used.ItemID)
+		if err != nil {
+			return nil, errors.NewDatabaseError("find pantry item", err)
+		}
+		if item == nil {
+			continue
+		}
+		result.Items = append(result.Items, itemToDTO(item))
+		if restock := item.Restock(used.Amount); restock > 0 {
+			result.Restock = append(result.Restock, restockToDTO(item, restock))
+			additions = append(additions, outbound.ShoppingListAddition{
+				// Derived from the cook so a retried add is applied once
+				OpID:     uuid.NewSHA1(cook.ID, item.ID[:]).String(),
+				Name:     item.Name,
+				Quantity: units.Format(restock, item.Unit),
+			})
+		}
+	}
+
+	if cmd.ShoppingListID != nil && len(additions) > 0 && s.listFeed != nil {
+		added, err := s.listFeed.AddItems(ctx, *cmd.ShoppingListID, cmd.UserID, additions)
+		if err != nil {
+			s.logger.Warn("Failed to add restocks to shopping list",
+				zap.String("user_id", cmd.UserID.String()),
+				zap.String("list_id", cmd.ShoppingListID.String()),
+				zap.Error(err),
+			)
+		}
+		result.AddedToList = added
+	}
+	return result, nil
+}
+
+// ConsumptionHistory totals what the user's cooks took from each item,
+// most used by number of cooks first
+func (s *Service) ConsumptionHistory(ctx context.Context, query inbound.ConsumptionQuery) (*inbound.ConsumptionReport, error) {
+	days := query.Days
+	if days <= 0 {
+		days = defaultHistoryDays
+	}
+	if days > maxHistoryDays {
+		return nil, errors.NewBadRequestError("history covers at most 365 days")
+	}
+	since := s.now().UTC().AddDate(0, 0, -days)
+
+	used, err := s.pantry.FindConsumption(ctx, query.UserID, since)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find pantry consumption", err)
+	}
+	cooks, err := s.pantry.CountCooks(ctx, query.UserID, since)
+	if err != nil {
+		return nil, errors.NewDatabaseError("count cooks", err)
+	}
+
+	// An item's unit can change, so amounts are totalled per unit
+	type key struct {
+		item uuid.UUID
+		unit recipe.MeasurementUnit
+	}
+	totals := map[key]*inbound.ItemConsumption{}
+	var order []key
+	for _, c := range used {
+		k := key{c.ItemID, c.Unit}
+		total, ok := totals[k]
+		if !ok {
+			// Newest first, so the first entry has the latest name
+			total = &inbound.ItemConsumption{ItemID: c.ItemID, Name: c.ItemName, Unit: string(c.Unit), LastUsed: c.CookedAt}
+			totals[k] = total
+			order = append(order, k)
+		}
+		total.Amount += c.Amount
+		total.Cooks++
+	}
+
+	report := &inbound.ConsumptionReport{Since: since, Cooks: cooks, Items: make([]inbound.ItemConsumption, len(order))}
+	for i, k := range order {
+		total := totals[k]
+		total.Amount = math.Round(total.Amount*100) / 100
+		report.Items[i] = *total
+	}
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		return report.Items[i].Cooks > report.Items[j].Cooks
+	})
+	return report, nil
+}
+
+// cookableRecipe finds a recipe the user may cook, published or their own,
+// and the portions to cook, the recipe's servings when not given
+func (s *Service) cookableRecipe(ctx context.Context, userID, recipeID uuid.UUID, portions int) (*recipe.Recipe, int, error) {
+	if portions < 0 || portions > maxPortions {
+		return nil, 0, errors.NewBadRequestError("portions must be between 1 and 1000")
+	}
+	entity, err := s.recipes.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, 0, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil || (entity.Status() != recipe.RecipeStatusPublished && entity.AuthorID() != userID) {
+		return nil, 0, errors.NewRecipeNotFoundError(recipeID.String())
+	}
+	if portions == 0 {
+		portions = entity.Servings()
+	}
+	if portions <= 0 {
+		portions = 1
+	}
+	return entity, portions, nil
+}
+
+func itemToDTO(item *pantry.Item) inbound.PantryItemDTO {
+	return inbound.PantryItemDTO{
+		ID:        item.ID,
+		Name:      item.Name,
+		Quantity:  item.Quantity,
+		Unit:      string(item.Unit),
+		Par:       item.Par,
+		Low:       item.Low(),
+		UpdatedAt: item.UpdatedAt,
+	}
+}
+
+func restockToDTO(item *pantry.Item, quantity float64) inbound.RestockSuggestionDTO {
+	return inbound.RestockSuggestionDTO{
+		ItemID:   item.ID,
+		Name:     item.Name,
+		Quantity: quantity,
+		Unit:     string(item.Unit),
+	}
+}
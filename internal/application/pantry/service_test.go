@@ -0,0 +1,167 @@
+package pantry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/pantry"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memoryPantry struct {
+	outbound.PantryRepository
+	items map[uuid.UUID]pantry.Item
+	cooks []*pantry.Cook
+}
+
+func (m *memoryPantry) FindItems(ctx context.Context, userID uuid.UUID) ([]*pantry.Item, error) {
+	var items []*pantry.Item
+	for _, item := range m.items {
+		if item.UserID == userID {
+			copied := item
+			items = append(items, &copied)
+		}
+	}
+	return items, nil
+}
+
+func (m *memoryPantry) FindItem(ctx context.Context, userID, itemID uuid.UUID) (*pantry.Item, error) {
+	item, ok := m.items[itemID]
+	if !ok || item.UserID != userID {
+		return nil, nil
+	}
+	return &item, nil
+}
+
+func (m *memoryPantry) CountItems(ctx context.Context, userID uuid.UUID) (int, error) {
+	items, _ := m.FindItems(ctx, userID)
+	return len(items), nil
+}
+
+func (m *memoryPantry) SaveItem(ctx context.Context, item *pantry.Item) error {
+	m.items[item.ID] = *item
+	return nil
+}
+
+func (m *memoryPantry) RecordCook(ctx context.Context, cook *pantry.Cook) error {
+	m.cooks = append(m.cooks, cook)
+	for _, used := range cook.Used {
+		item := m.items[used.ItemID]
+		item.Quantity -= used.Amount
+		if item.Quantity < 0 {
+			item.Quantity = 0
+		}
+		m.items[used.ItemID] = item
+	}
+	return nil
+}
+
+func (m *memoryPantry) FindConsumption(ctx context.Context, userID uuid.UUID, since time.Time) ([]pantry.Consumption, error) {
+	var used []pantry.Consumption
+	for i := len(m.cooks) - 1; i >= 0; i-- {
+		used = append(used, m.cooks[i].Used...)
+	}
+	return used, nil
+}
+
+func (m *memoryPantry) CountCooks(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	return len(m.cooks), nil
+}
+
+type stubRecipes struct {
+	outbound.RecipeRepository
+	recipe *recipe.Recipe
+}
+
+func (s *stubRecipes) FindByID(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error) {
+	if s.recipe.ID() != id {
+		return nil, nil
+	}
+	return s.recipe, nil
+}
+
+type recordingFeed struct {
+	additions []outbound.ShoppingListAddition
+}
+
+func (f *recordingFeed) AddItems(ctx context.Context, listID, userID uuid.UUID, items []outbound.ShoppingListAddition) (int, error) {
+	f.additions = append(f.additions, items...)
+	return len(items), nil
+}
+
+func TestCookDepletesConfirmedAmountsAndRestocks(t *testing.T) {
+	userID := uuid.New()
+	pancakes, err := recipe.NewRecipe("Pancakes", "", userID)
+	require.NoError(t, err)
+	require.NoError(t, pancakes.SetServings(2))
+	require.NoError(t, pancakes.AddIngredient(recipe.Ingredient{ID: uuid.New(), Name: "plain flour", Amount: 200, Unit: recipe.MeasurementUnitGram}))
+	require.NoError(t, pancakes.AddIngredient(recipe.Ingredient{ID: uuid.New(), Name: "eggs", Amount: 2, Unit: recipe.MeasurementUnitPiece}))
+	require.NoError(t, pancakes.AddIngredient(recipe.Ingredient{ID: uuid.New(), Name: "milk", Amount: 300, Unit: recipe.MeasurementUnitMilliliter}))
+
+	store := &memoryPantry{items: map[uuid.UUID]pantry.Item{}}
+	feed := &recordingFeed{}
+	svc := NewService(store, &stubRecipes{recipe: pancakes}, feed, zap.NewNop())
+	ctx := context.Background()
+
+	flour, err := svc.SaveItem(ctx, inbound.SavePantryItemCommand{UserID: userID, Name: "flour", Quantity: 250, Unit: "grams", Par: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, "g", flour.Unit)
+	eggs, err := svc.SaveItem(ctx, inbound.SavePantryItemCommand{UserID: userID, Name: "egg", Quantity: 12, Par: 12})
+	require.NoError(t, err)
+	_, err = svc.SaveItem(ctx, inbound.SavePantryItemCommand{UserID: userID, Name: "rice", Unit: "handfuls"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	preview, err := svc.PreviewCook(ctx, inbound.CookPreviewQuery{UserID: userID, RecipeID: pancakes.ID(), Portions: 4})
+	require.NoError(t, err)
+	require.Len(t, preview.Deductions, 2)
+	assert.Equal(t, 400.0, preview.Deductions[0].Amount)
+	assert.Equal(t, 150.0, preview.Deductions[0].Short)
+	assert.Equal(t, 4.0, preview.Deductions[1].Amount)
+	require.Len(t, preview.Unmatched, 1)
+	assert.Equal(t, pantry.ReasonNotInPantry, preview.Unmatched[0].Reason)
+	require.Len(t, preview.Restock, 1, "only the flour runs low")
+	assert.Equal(t, 1000.0, preview.Restock[0].Quantity)
+
+	listID := uuid.New()
+	result, err := svc.CompleteCook(ctx, inbound.CompleteCookCommand{
+		UserID:   userID,
+		RecipeID: pancakes.ID(),
+		Portions: 4,
+		Deductions: []inbound.ConfirmedDeduction{
+			{ItemID: flour.ID, Amount: 250},
+			{ItemID: eggs.ID, Amount: 4},
+		},
+		ShoppingListID: &listID,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 2)
+	assert.Zero(t, result.Items[0].Quantity)
+	assert.True(t, result.Items[0].Low)
+	assert.Equal(t, 8.0, result.Items[1].Quantity)
+	assert.Equal(t, 1, result.AddedToList)
+	require.Len(t, feed.additions, 1)
+	assert.Equal(t, "flour", feed.additions[0].Name)
+
+	_, err = svc.CompleteCook(ctx, inbound.CompleteCookCommand{
+		UserID:     userID,
+		RecipeID:   pancakes.ID(),
+		Deductions: []inbound.ConfirmedDeduction{{ItemID: uuid.New(), Amount: 1}},
+	})
+	assert.True(t, errors.Is(err, errors.CodeNotFound))
+
+	report, err := svc.ConsumptionHistory(ctx, inbound.ConsumptionQuery{UserID: userID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Cooks)
+	assert.Len(t, report.Items, 2)
+
+	_, err = svc.PreviewCook(ctx, inbound.CookPreviewQuery{UserID: uuid.New(), RecipeID: pancakes.ID()})
+	assert.True(t, errors.Is(err, errors.CodeRecipeNotFound), "a draft is only cooked by its author")
+}
@@ -0,0 +1,183 @@
+// Package passwordreset lets users who forgot their password choose a new
+// one with a single-use token mailed to their address
+package passwordreset
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Config limits the tokens
+type Config struct {
+	TokenTTL    time.Duration
+	MaxRequests int // tokens an address may get per Window
+	Window      time.Duration
+}
+
+// Service implements inbound.PasswordResetService
+type Service struct {
+	tokens        outbound.PasswordResetTokenRepository
+	userRepo      outbound.UserRepository
+	email         outbound.EmailService
+	cache         outbound.CacheRepository
+	invalidations outbound.CacheInvalidationBus
+	cfg           Config
+	now           func() time.Time
+	logger        *zap.Logger
+}
+
+// NewService creates the password reset service
+func NewService(
+	tokens outbound.PasswordResetTokenRepository,
+	userRepo outbound.UserRepository,
+	email outbound.EmailService,
+	cache outbound.CacheRepository,
+	invalidations outbound.CacheInvalidationBus,
+	cfg Config,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		tokens:        tokens,
+		userRepo:      userRepo,
+		email:         email,
+		cache:         cache,
+		invalidations: invalidations,
+		cfg:           cfg,
+		now:           time.Now,
+		logger:        logger.Named("password-reset"),
+	}
+}
+
+// RequestReset mails a token to the address if it has an active account
+// and has not had MaxRequests tokens in the last Window. Unknown addresses
+// and dropped requests succeed like any other.
+func (s *Service) RequestReset(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return errors.NewBadRequestError("email is required")
+	}
+
+	account, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil || account == nil || !account.IsActive() {
+		s.logger.Debug("Password reset requested for an address without an active account", zap.Error(err))
+		return nil
+	}
+
+	now := s.now()
+	issued, err := s.tokens.CountSince(ctx, email, now.Add(-s.cfg.Window))
+	if err != nil {
+		return errors.NewDatabaseError("count reset tokens", err)
+	}
+	if issued >= int64(s.cfg.MaxRequests) {
+		s.logger.Warn("Password reset rate limit reached", zap.String("user_id", account.ID().String()))
+		return nil
+	}
+
+	token, hash, err := newToken()
+	if err != nil {
+		return errors.NewInternalError("failed to generate reset token")
+	}
+	err = s.tokens.Create(ctx, outbound.PasswordResetToken{
+		Hash:      hash,
+		UserID:    account.ID(),
+		Email:     email,
+		ExpiresAt: now.Add(s.cfg.TokenTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return errors.NewDatabaseError("store reset token", err)
+	}
+
+	if err := s.email.SendPasswordReset(ctx, account.Email(), token); err != nil {
+		return errors.NewExternalServiceError("email", err)
+	}
+	s.logger.Info("Password reset token sent", zap.String("user_id", account.ID().String()))
+	return nil
+}
+
+// Reset sets the new password and burns the token along with any others
+// the user still holds
+func (s *Service) Reset(ctx context.Context, token, newPassword string) error {
+	invalid := errors.NewBadRequestError("reset token is invalid or has expired")
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return invalid
+	}
+
+	now := s.now()
+	hash := hashToken(token)
+	stored, err := s.tokens.FindByHash(ctx, hash)
+	if err != nil {
+		return errors.NewDatabaseError("find reset token", err)
+	}
+	if stored == nil || stored.UsedAt != nil || !now.Before(stored.ExpiresAt) {
+		return invalid
+	}
+
+	account, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil || account == nil || !account.IsActive() {
+		return invalid
+	}
+	// Check the password before spending the token so a weak one can be
+	// retried
+	if err := account.UpdatePassword(newPassword); err != nil {
+		return errors.NewBadRequestError(err.Error())
+	}
+
+	consumed, err := s.tokens.Consume(ctx, hash, now)
+	if err != nil {
+		return errors.NewDatabaseError("consume reset token", err)
+	}
+	if !consumed {
+		return invalid
+	}
+	if err := s.userRepo.Update(ctx, account); err != nil {
+		return errors.NewDatabaseError("save password", err)
+	}
+	if err := s.tokens.RevokeForUser(ctx, account.ID(), now); err != nil {
+		s.logger.Warn("Failed to revoke remaining reset tokens", zap.String("user_id", account.ID().String()), zap.Error(err))
+	}
+
+	s.invalidateUser(ctx, account.ID().String())
+	s.logger.Info("Password reset", zap.String("user_id", account.ID().String()))
+	return nil
+}
+
+// invalidateUser drops the cached user here and on the other replicas, as
+// the user service does after a password change
+func (s *Service) invalidateUser(ctx context.Context, userID string) {
+	key := "user:" + userID
+	if s.cache != nil {
+		s.cache.Delete(ctx, key)
+	}
+	if s.invalidations == nil {
+		return
+	}
+	if err := s.invalidations.Publish(ctx, key); err != nil {
+		s.logger.Warn("Failed to broadcast user invalidation", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// newToken makes a random URL-safe token and the hash it is stored under
+func newToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,140 @@
+package passwordreset
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	for _, u := range s.users {
+		if u.Email() == email {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+func (s *stubUsers) Update(ctx context.Context, u *user.User) error {
+	s.users[u.ID()] = u
+	return nil
+}
+
+type stubTokens struct {
+	tokens map[string]*outbound.PasswordResetToken
+}
+
+func (s *stubTokens) Create(ctx context.Context, token outbound.PasswordResetToken) error {
+	s.tokens[token.Hash] = &token
+	return nil
+}
+
+func (s *stubTokens) FindByHash(ctx context.Context, hash string) (*outbound.PasswordResetToken, error) {
+	return s.tokens[hash], nil
+}
+
+func (s *stubTokens) Consume(ctx context.Context, hash string, now time.Time) (bool, error) {
+	t := s.tokens[hash]
+	if t == nil || t.UsedAt != nil || !now.Before(t.ExpiresAt) {
+		return false, nil
+	}
+	t.UsedAt = &now
+	return true, nil
+}
+
+func (s *stubTokens) CountSince(ctx context.Context, email string, since time.Time) (int64, error) {
+	var n int64
+	for _, t := range s.tokens {
+		if t.Email == email && t.CreatedAt.After(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *stubTokens) RevokeForUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	for _, t := range s.tokens {
+		if t.UserID == userID && t.UsedAt == nil {
+			t.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+type stubEmail struct {
+	outbound.EmailService
+	sent []string
+}
+
+func (s *stubEmail) SendPasswordReset(ctx context.Context, to string, token string) error {
+	s.sent = append(s.sent, token)
+	return nil
+}
+
+func newTestService(t *testing.T) (*Service, *stubEmail, *user.User) {
+	t.Helper()
+	account, err := user.NewUser("sam@example.com", "Sam", "old-password")
+	require.NoError(t, err)
+	email := &stubEmail{}
+	svc := NewService(
+		&stubTokens{tokens: map[string]*outbound.PasswordResetToken{}},
+		&stubUsers{users: map[uuid.UUID]*user.User{account.ID(): account}},
+		email, nil, nil,
+		Config{TokenTTL: time.Hour, MaxRequests: 2, Window: time.Hour},
+		zap.NewNop(),
+	)
+	return svc, email, account
+}
+
+func TestTokenResetsThePasswordOnce(t *testing.T) {
+	ctx := context.Background()
+	svc, email, account := newTestService(t)
+
+	require.NoError(t, svc.RequestReset(ctx, " Sam@Example.com "))
+	require.Len(t, email.sent, 1)
+	token := email.sent[0]
+
+	// A weak password is refused without spending the token
+	assert.Error(t, svc.Reset(ctx, token, "short"))
+	require.NoError(t, svc.Reset(ctx, token, "new-password"))
+	assert.NoError(t, account.CheckPassword("new-password"))
+
+	assert.Error(t, svc.Reset(ctx, token, "another-password"))
+	assert.Error(t, svc.Reset(ctx, "not-a-token", "another-password"))
+}
+
+func TestRequestsAreLimitedAndDoNotRevealAccounts(t *testing.T) {
+	ctx := context.Background()
+	svc, email, _ := newTestService(t)
+	start := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return start }
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, svc.RequestReset(ctx, "sam@example.com"))
+	}
+	require.NoError(t, svc.RequestReset(ctx, "nobody@example.com"))
+	assert.Len(t, email.sent, 2)
+
+	// Tokens expire, and the window reopens
+	svc.now = func() time.Time { return start.Add(61 * time.Minute) }
+	assert.Error(t, svc.Reset(ctx, email.sent[0], "new-password"))
+	require.NoError(t, svc.RequestReset(ctx, "sam@example.com"))
+	assert.Len(t, email.sent, 3)
+}
@@ -0,0 +1,244 @@
+// Package popularity decays recipe popularity so trending follows recent
+// engagement rather than all-time totals, ranks the published recipes for
+// the trending and top listings, and rotates hidden gems, well rated
+// recipes few people have seen, into the browse and recommendation slots.
+package popularity
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultHalfLife is how long a score takes to halve without new
+	// views or likes
+	DefaultHalfLife = 7 * 24 * time.Hour
+	// DefaultGemRotation is how long a set of hidden gems is featured
+	DefaultGemRotation = 24 * time.Hour
+	// DefaultGemsPerRotation is the hidden gems featured at once
+	DefaultGemsPerRotation = 6
+
+	// scoreFloor drops scores that have decayed to nothing, so the table
+	// only holds recipes with some recent engagement
+	scoreFloor = 0.01
+)
+
+// Config sets the decay and the hidden gem rotation
+type Config struct {
+	HalfLife   time.Duration
+	ViewWeight float64
+	LikeWeight float64
+
+	GemMinRating    float64
+	GemMaxViews     int
+	GemMinAge       time.Duration
+	GemsPerRotation int
+	GemRotation     time.Duration
+}
+
+// Service implements inbound.PopularityService
+type Service struct {
+	repo   outbound.RecipePopularityRepository
+	cfg    Config
+	now    func() time.Time
+	logger *zap.Logger
+
+	mu         sync.Mutex
+	refreshing bool
+}
+
+// NewService creates a popularity service
+func NewService(repo outbound.RecipePopularityRepository, cfg Config, logger *zap.Logger) *Service {
+	if cfg.HalfLife <= 0 {
+		cfg.HalfLife = DefaultHalfLife
+	}
+	if cfg.GemRotation <= 0 {
+		cfg.GemRotation = DefaultGemRotation
+	}
+	if cfg.GemsPerRotation <= 0 {
+		cfg.GemsPerRotation = DefaultGemsPerRotation
+	}
+	return &Service{
+		repo:   repo,
+		cfg:    cfg,
+		now:    time.Now,
+		logger: logger.Named("popularity"),
+	}
+}
+
+// DecayFactor is what a score is multiplied by after elapsed time without
+// new engagement
+func DecayFactor(elapsed, halfLife time.Duration) float64 {
+	if elapsed <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, elapsed.Hours()/halfLife.Hours())
+}
+
+// HiddenGems returns the current rotation, at most as many recipes as a
+// rotation features
+func (s *Service) HiddenGems(ctx context.Context, limit int) (*inbound.HiddenGems, error) {
+	if limit <= 0 || limit > s.cfg.GemsPerRotation {
+		limit = s.cfg.GemsPerRotation
+	}
+	gems, err := s.repo.HiddenGems(ctx, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list hidden gems", err)
+	}
+
+	result := &inbound.HiddenGems{Recipes: make([]inbound.HiddenGem, len(gems))}
+	for i, gem := range gems {
+		result.Recipes[i] = inbound.HiddenGem{
+			ID:            gem.RecipeID.String(),
+			Title:         gem.Title,
+			Cuisine:       gem.Cuisine,
+			AverageRating: gem.AverageRating,
+			Views:         gem.Views,
+		}
+	}
+	if len(gems) > 0 {
+		featuredAt := gems[0].FeaturedAt.UTC()
+		result.FeaturedAt = featuredAt.Format(time.RFC3339)
+		result.NextRotationAt = featuredAt.Add(s.cfg.GemRotation).Format(time.RFC3339)
+	}
+	return result, nil
+}
+
+// Refresh decays every score by the time since the last refresh and adds
+// the views and likes since. The first refresh has nothing to decay and
+// starts from one half-life of activity. Overlapping refreshes would count
+// the same activity twice, so one started while another runs returns at
+// once.
+func (s *Service) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	if s.refreshing {
+		s.mu.Unlock()
+		return nil
+	}
+	s.refreshing = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.refreshing = false
+		s.mu.Unlock()
+	}()
+
+	started := s.now()
+	now := started.UTC()
+	last, err := s.repo.RefreshedAt(ctx)
+	if err != nil {
+		return errors.NewDatabaseError("read popularity refresh time", err)
+	}
+	since, factor := now.Add(-s.cfg.HalfLife), 1.0
+	if last != nil {
+		since, factor = *last, DecayFactor(now.Sub(*last), s.cfg.HalfLife)
+	}
+
+	activity, err := s.repo.Activity(ctx, since, now)
+	if err != nil {
+		return errors.NewDatabaseError("count recipe activity", err)
+	}
+	for i := range activity {
+		activity[i].Score = float64(activity[i].Views)*s.cfg.ViewWeight + float64(activity[i].Likes)*s.cfg.LikeWeight
+	}
+	kept, err := s.repo.Decay(ctx, factor, activity, scoreFloor, now)
+	if err != nil {
+		return errors.NewDatabaseError("decay popularity", err)
+	}
+
+	ranked, err := s.rank(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	rotated, err := s.rotateGems(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Recipe popularity refreshed",
+		zap.Float64("decay_factor", factor),
+		zap.Int("active_recipes", len(activity)),
+		zap.Int("scored_recipes", kept),
+		zap.Int("ranked_recipes", ranked),
+		zap.Int("gems_rotated", rotated),
+		zap.Duration("duration", time.Since(started)),
+	)
+	return nil
+}
+
+// rank orders the published recipes by the freshly decayed score for
+// trending and by all-time likes and views, weighted alike, for top. Ties
+// go to the most recently published.
+func (s *Service) rank(ctx context.Context, now time.Time) (int, error) {
+	candidates, err := s.repo.RankingCandidates(ctx)
+	if err != nil {
+		return 0, errors.NewDatabaseError("list ranking candidates", err)
+	}
+
+	rankings := make([]outbound.RecipeRanking, len(candidates))
+	for i, c := range candidates {
+		rankings[i] = outbound.RecipeRanking{
+			RecipeID:      c.RecipeID,
+			TrendingScore: c.Popularity,
+			TopScore:      float64(c.Views)*s.cfg.ViewWeight + float64(c.Likes)*s.cfg.LikeWeight,
+		}
+	}
+	rankBy := func(score func(i int) float64, setRank func(i, rank int)) {
+		order := make([]int, len(candidates))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(a, b int) bool {
+			x, y := order[a], order[b]
+			if score(x) != score(y) {
+				return score(x) > score(y)
+			}
+			if !candidates[x].PublishedAt.Equal(candidates[y].PublishedAt) {
+				return candidates[x].PublishedAt.After(candidates[y].PublishedAt)
+			}
+			return candidates[x].RecipeID.String() < candidates[y].RecipeID.String()
+		})
+		for position, i := range order {
+			setRank(i, position+1)
+		}
+	}
+	rankBy(func(i int) float64 { return rankings[i].TrendingScore }, func(i, rank int) { rankings[i].TrendingRank = rank })
+	rankBy(func(i int) float64 { return rankings[i].TopScore }, func(i, rank int) { rankings[i].TopRank = rank })
+
+	if err := s.repo.ReplaceRankings(ctx, rankings, now); err != nil {
+		return 0, errors.NewDatabaseError("replace recipe rankings", err)
+	}
+	return len(rankings), nil
+}
+
+// rotateGems features the next hidden gems once the current rotation has
+// run its course. A rotation whose recipes were all unpublished is
+// replaced early.
+func (s *Service) rotateGems(ctx context.Context, now time.Time) (int, error) {
+	current, err := s.repo.HiddenGems(ctx, 1)
+	if err != nil {
+		return 0, errors.NewDatabaseError("list hidden gems", err)
+	}
+	if len(current) > 0 && now.Sub(current[0].FeaturedAt) < s.cfg.GemRotation {
+		return 0, nil
+	}
+
+	gems, err := s.repo.RotateGems(ctx, outbound.GemCriteria{
+		MinRating:       s.cfg.GemMinRating,
+		MaxViews:        s.cfg.GemMaxViews,
+		PublishedBefore: now.Add(-s.cfg.GemMinAge),
+	}, s.cfg.GemsPerRotation, now)
+	if err != nil {
+		return 0, errors.NewDatabaseError("rotate hidden gems", err)
+	}
+	return len(gems), nil
+}
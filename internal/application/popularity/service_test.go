@@ -0,0 +1,134 @@
+package popularity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubPopularity struct {
+	refreshedAt *time.Time
+	since       time.Time
+	activity    []outbound.PopularityActivity
+	factor      float64
+	added       []outbound.PopularityActivity
+	gems        []outbound.HiddenGem
+	rotations   int
+	criteria    outbound.GemCriteria
+	candidates  []outbound.RankingCandidate
+	rankings    []outbound.RecipeRanking
+}
+
+func (s *stubPopularity) Activity(ctx context.Context, since, until time.Time) ([]outbound.PopularityActivity, error) {
+	s.since = since
+	return s.activity, nil
+}
+
+func (s *stubPopularity) Decay(ctx context.Context, factor float64, activity []outbound.PopularityActivity, floor float64, at time.Time) (int, error) {
+	s.factor, s.added = factor, activity
+	s.refreshedAt = &at
+	return len(activity), nil
+}
+
+func (s *stubPopularity) RefreshedAt(ctx context.Context) (*time.Time, error) {
+	return s.refreshedAt, nil
+}
+
+func (s *stubPopularity) RotateGems(ctx context.Context, criteria outbound.GemCriteria, count int, at time.Time) ([]outbound.HiddenGem, error) {
+	s.rotations++
+	s.criteria = criteria
+	s.gems = []outbound.HiddenGem{{RecipeID: uuid.New(), Title: "Sorrel Soup", AverageRating: 4.7, Views: 12, FeaturedAt: at}}
+	return s.gems, nil
+}
+
+func (s *stubPopularity) HiddenGems(ctx context.Context, limit int) ([]outbound.HiddenGem, error) {
+	return s.gems, nil
+}
+
+func (s *stubPopularity) RankingCandidates(ctx context.Context) ([]outbound.RankingCandidate, error) {
+	return s.candidates, nil
+}
+
+func (s *stubPopularity) ReplaceRankings(ctx context.Context, rankings []outbound.RecipeRanking, at time.Time) error {
+	s.rankings = rankings
+	return nil
+}
+
+func TestDecayFactorHalvesEveryHalfLife(t *testing.T) {
+	week := 7 * 24 * time.Hour
+	assert.Equal(t, 1.0, DecayFactor(0, week))
+	assert.InDelta(t, 0.5, DecayFactor(week, week), 1e-9)
+	assert.InDelta(t, 0.25, DecayFactor(2*week, week), 1e-9)
+	// A hundred viral likes a year ago weigh less than one like today
+	assert.Less(t, 100*5*DecayFactor(52*week, week), 5.0)
+}
+
+func TestRefreshDecaysAndRotatesGems(t *testing.T) {
+	repo := &stubPopularity{activity: []outbound.PopularityActivity{{RecipeID: uuid.New(), Views: 10, Likes: 2}}}
+	svc := NewService(repo, Config{
+		HalfLife:     24 * time.Hour,
+		ViewWeight:   1,
+		LikeWeight:   5,
+		GemMinRating: 4.2,
+		GemMaxViews:  200,
+		GemMinAge:    48 * time.Hour,
+	}, zap.NewNop())
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, svc.Refresh(ctx))
+	assert.Equal(t, now.Add(-24*time.Hour), repo.since, "the first refresh counts one half-life of activity")
+	assert.Equal(t, 1.0, repo.factor)
+	assert.Equal(t, 20.0, repo.added[0].Score)
+	assert.Equal(t, 1, repo.rotations)
+	assert.Equal(t, now.Add(-48*time.Hour), repo.criteria.PublishedBefore)
+
+	now = now.Add(12 * time.Hour)
+	require.NoError(t, svc.Refresh(ctx))
+	assert.Equal(t, now.Add(-12*time.Hour), repo.since, "later refreshes pick up where the last one stopped")
+	assert.InDelta(t, 0.7071, repo.factor, 1e-4)
+	assert.Equal(t, 1, repo.rotations, "the rotation runs for a full day")
+
+	gems, err := svc.HiddenGems(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, gems.Recipes, 1)
+	assert.Equal(t, "2026-10-01T12:00:00Z", gems.FeaturedAt)
+	assert.Equal(t, "2026-10-02T12:00:00Z", gems.NextRotationAt)
+
+	now = now.Add(12 * time.Hour)
+	require.NoError(t, svc.Refresh(ctx))
+	assert.Equal(t, 2, repo.rotations)
+}
+
+func TestRefreshRanksTrendingByDecayedScoreAndTopByAllTimeEngagement(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	viral, rising, newest := uuid.New(), uuid.New(), uuid.New()
+	repo := &stubPopularity{candidates: []outbound.RankingCandidate{
+		{RecipeID: viral, PublishedAt: day.AddDate(-1, 0, 0), Likes: 900, Views: 20000, Popularity: 0.5},
+		{RecipeID: rising, PublishedAt: day.AddDate(0, 0, -3), Likes: 40, Views: 300, Popularity: 120},
+		{RecipeID: newest, PublishedAt: day},
+	}}
+	svc := NewService(repo, Config{ViewWeight: 1, LikeWeight: 5}, zap.NewNop())
+	svc.now = func() time.Time { return day }
+
+	require.NoError(t, svc.Refresh(context.Background()))
+	require.Len(t, repo.rankings, 3)
+	ranks := map[uuid.UUID]outbound.RecipeRanking{}
+	for _, ranking := range repo.rankings {
+		ranks[ranking.RecipeID] = ranking
+	}
+	assert.Equal(t, 1, ranks[rising].TrendingRank)
+	assert.Equal(t, 2, ranks[viral].TrendingRank)
+	assert.Equal(t, 3, ranks[newest].TrendingRank)
+	assert.Equal(t, 1, ranks[viral].TopRank)
+	assert.Equal(t, 24500.0, ranks[viral].TopScore)
+	assert.Equal(t, 2, ranks[rising].TopRank)
+	assert.Equal(t, 3, ranks[newest].TopRank)
+}
@@ -0,0 +1,219 @@
+package portability
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/printout"
+	"github.com/google/uuid"
+)
+
+// Archive identification. Version goes up when a field changes meaning;
+// added fields keep it.
+const (
+	ArchiveFormat  = "alchemorsel-account"
+	ArchiveVersion = 1
+)
+
+// manifestName is the archive document inside the zip; the Markdown
+// copies of the recipes beside it are for reading and are not imported
+const manifestName = "account.json"
+
+// maxManifestBytes bounds the decompressed archive document so a crafted
+// zip cannot exhaust memory
+const maxManifestBytes = 64 << 20
+
+// Archive is everything a user keeps on the site
+type Archive struct {
+	Format      string       `json:"format"`
+	Version     int          `json:"version"`
+	ExportedAt  time.Time    `json:"exported_at"`
+	Account     Account      `json:"account"`
+	Preferences Preferences  `json:"preferences"`
+	Recipes     []Recipe     `json:"recipes"`
+	Collections []Collection `json:"collections"`
+	Favorites   []Favorite   `json:"favorites"`
+}
+
+// Account is who the user is. It is exported for the user's records;
+// imports only fill in profile fields that are blank.
+type Account struct {
+	ID           uuid.UUID  `json:"id"`
+	Email        string     `json:"email"`
+	Name         string     `json:"name"`
+	CreatedAt    time.Time  `json:"created_at"`
+	FirstName    string     `json:"first_name,omitempty"`
+	LastName     string     `json:"last_name,omitempty"`
+	Avatar       string     `json:"avatar,omitempty"`
+	Bio          string     `json:"bio,omitempty"`
+	Location     string     `json:"location,omitempty"`
+	Website      string     `json:"website,omitempty"`
+	Birthday     *time.Time `json:"birthday,omitempty"`
+	CookingLevel string     `json:"cooking_level,omitempty"`
+}
+
+// Preferences are the user's settings
+type Preferences struct {
+	Theme               string   `json:"theme,omitempty"`
+	MeasurementSystem   string   `json:"measurement_system,omitempty"`
+	Language            string   `json:"language,omitempty"`
+	Timezone            string   `json:"timezone,omitempty"`
+	DietaryRestrictions []string `json:"dietary_restrictions"`
+	Allergies           []string `json:"allergies"`
+	DislikedIngredients []string `json:"disliked_ingredients"`
+	PreferredCuisines   []string `json:"preferred_cuisines"`
+	EmailNotifications  bool     `json:"email_notifications"`
+	PushNotifications   bool     `json:"push_notifications"`
+	PersonalizedSearch  bool     `json:"personalized_search"`
+	PrivateProfile      bool     `json:"private_profile"`
+	MutedNotifications  []string `json:"muted_notifications"`
+}
+
+// Recipe is one of the user's recipes as printed, with how it is filed
+type Recipe struct {
+	printout.Printout
+	Status      string     `json:"status"`
+	Cuisine     string     `json:"cuisine,omitempty"`
+	Category    string     `json:"category,omitempty"`
+	Difficulty  string     `json:"difficulty,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// Collection is a named list of recipes, in order. Recipes may be the
+// user's own or anyone's published recipes.
+type Collection struct {
+	ID          uuid.UUID   `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Public      bool        `json:"public"`
+	CreatedAt   time.Time   `json:"created_at"`
+	Recipes     []uuid.UUID `json:"recipes"`
+}
+
+// Favorite is a recipe the user saved
+type Favorite struct {
+	RecipeID uuid.UUID `json:"recipe_id"`
+	Title    string    `json:"title,omitempty"`
+	SavedAt  time.Time `json:"saved_at"`
+}
+
+// EncodeJSON writes the archive as one indented JSON document
+func EncodeJSON(a *Archive) ([]byte, error) {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// EncodeZip writes the archive document with a Markdown copy of each
+// recipe under recipes/
+func EncodeZip(a *Archive) ([]byte, error) {
+	manifest, err := EncodeJSON(a)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	write := func(name string, content []byte) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: a.ExportedAt})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	}
+
+	if err := write(manifestName, manifest); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(a.Recipes))
+	for _, r := range a.Recipes {
+		name := recipeFileName(r, names)
+		if err := write("recipes/"+name, printout.Markdown(r.Printout)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode reads an archive made by EncodeZip or EncodeJSON
+func Decode(data []byte) (*Archive, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		manifest, err := readManifest(data)
+		if err != nil {
+			return nil, err
+		}
+		data = manifest
+	}
+
+	var a Archive
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("the archive is not valid JSON: %w", err)
+	}
+	if a.Format != ArchiveFormat {
+		return nil, fmt.Errorf("not an account archive; expected format %q", ArchiveFormat)
+	}
+	if a.Version < 1 || a.Version > ArchiveVersion {
+		return nil, fmt.Errorf("archive version %d is not supported", a.Version)
+	}
+	return &a, nil
+}
+
+func readManifest(data []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("the archive is not a valid zip: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.Name != manifestName {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", manifestName, err)
+		}
+		defer rc.Close()
+		manifest, err := io.ReadAll(io.LimitReader(rc, maxManifestBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", manifestName, err)
+		}
+		if len(manifest) > maxManifestBytes {
+			return nil, fmt.Errorf("%s is larger than %d MB", manifestName, maxManifestBytes>>20)
+		}
+		return manifest, nil
+	}
+	return nil, fmt.Errorf("the zip has no %s", manifestName)
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// recipeFileName names a recipe's Markdown copy after its title, such as
+// "lemon-bars.md", numbering repeats
+func recipeFileName(r Recipe, taken map[string]bool) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(r.Title), "-"), "-")
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	if slug == "" {
+		slug = "recipe"
+	}
+	name := slug + ".md"
+	for n := 2; taken[name]; n++ {
+		name = fmt.Sprintf("%s-%d.md", slug, n)
+	}
+	taken[name] = true
+	return name
+}
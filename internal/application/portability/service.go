@@ -0,0 +1,597 @@
+// Package portability packs a user's recipes, collections and preferences
+// into an archive they can take elsewhere, and restores such an archive
+// into an account.
+package portability
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/notification"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/allergens"
+	"github.com/alchemorsel/v3/internal/domain/recipe/printout"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Source names restored recipes in library import results and logs
+const Source = "alchemorsel"
+
+// Item statuses reported by inbound.RecipeService.ImportRecipeLibrary
+const (
+	statusCreated   = "created"
+	statusDuplicate = "duplicate"
+)
+
+// recipePageSize is the page used to list the user's recipes
+const recipePageSize = 100
+
+// Config locates the recipe links written into archives
+type Config struct {
+	SiteURL string // Base of recipe links
+}
+
+// Service implements inbound.PortabilityService
+type Service struct {
+	recipes       inbound.RecipeService
+	recipeRepo    outbound.RecipeRepository
+	userRepo      outbound.UserRepository
+	collections   outbound.CollectionRepository
+	favorites     outbound.FavoriteRepository
+	cache         outbound.CacheRepository
+	invalidations outbound.CacheInvalidationBus
+	cfg           Config
+	now           func() time.Time
+	logger        *zap.Logger
+}
+
+// NewService creates the portability service
+func NewService(
+	recipes inbound.RecipeService,
+	recipeRepo outbound.RecipeRepository,
+	userRepo outbound.UserRepository,
+	collections outbound.CollectionRepository,
+	favorites outbound.FavoriteRepository,
+	cache outbound.CacheRepository,
+	invalidations outbound.CacheInvalidationBus,
+	cfg Config,
+	logger *zap.Logger,
+) *Service {
+	cfg.SiteURL = strings.TrimRight(cfg.SiteURL, "/")
+	return &Service{
+		recipes:       recipes,
+		recipeRepo:    recipeRepo,
+		userRepo:      userRepo,
+		collections:   collections,
+		favorites:     favorites,
+		cache:         cache,
+		invalidations: invalidations,
+		cfg:           cfg,
+		now:           time.Now,
+		logger:        logger.Named("portability"),
+	}
+}
+
+// ExportAccount packs the user's account, preferences, recipes of every
+// status, collections and saved recipes into an archive
+func (s *Service) ExportAccount(ctx context.Context, query inbound.AccountExportQuery) (*inbound.AccountExport, error) {
+	format := strings.ToLower(query.Format)
+	switch format {
+	case "":
+		format = inbound.AccountArchiveFormatZip
+	case inbound.AccountArchiveFormatZip, inbound.AccountArchiveFormatJSON:
+	default:
+		return nil, errors.NewBadRequestError("format must be zip or json")
+	}
+
+	account, err := s.findUser(ctx, query.UserID)
+	if err != nil {
+		return nil, err
+	}
+	archive := &Archive{
+		Format:      ArchiveFormat,
+		Version:     ArchiveVersion,
+		ExportedAt:  s.now().UTC().Truncate(time.Second),
+		Account:     toAccount(account),
+		Preferences: toPreferences(account),
+		Recipes:     []Recipe{},
+		Collections: []Collection{},
+		Favorites:   []Favorite{},
+	}
+
+	own, err := s.ownRecipes(ctx, account.ID())
+	if err != nil {
+		return nil, errors.NewDatabaseError("find user recipes", err)
+	}
+	for _, entity := range own {
+		archive.Recipes = append(archive.Recipes, s.toRecipe(entity, account.Name()))
+	}
+
+	collections, err := s.collections.ListByUser(ctx, account.ID())
+	if err != nil {
+		return nil, errors.NewDatabaseError("find collections", err)
+	}
+	for _, c := range collections {
+		archive.Collections = append(archive.Collections, Collection{
+			ID:          c.ID,
+			Name:        c.Name,
+			Description: c.Description,
+			Public:      c.IsPublic,
+			CreatedAt:   c.CreatedAt.UTC(),
+			Recipes:     append([]uuid.UUID{}, c.RecipeIDs...),
+		})
+	}
+
+	favorites, err := s.favorites.List(ctx, account.ID())
+	if err != nil {
+		return nil, errors.NewDatabaseError("find saved recipes", err)
+	}
+	titles, err := s.visibleTitles(ctx, account.ID(), favoriteIDs(favorites))
+	if err != nil {
+		return nil, errors.NewDatabaseError("find saved recipes", err)
+	}
+	for _, f := range favorites {
+		archive.Favorites = append(archive.Favorites, Favorite{RecipeID: f.RecipeID, Title: titles[f.RecipeID], SavedAt: f.SavedAt.UTC()})
+	}
+
+	name := "alchemorsel-account-" + archive.ExportedAt.Format("20060102")
+	file := &inbound.AccountExport{}
+	if format == inbound.AccountArchiveFormatJSON {
+		file.FileName, file.ContentType = name+".json", "application/json"
+		file.Content, err = EncodeJSON(archive)
+	} else {
+		file.FileName, file.ContentType = name+".zip", "application/zip"
+		file.Content, err = EncodeZip(archive)
+	}
+	if err != nil {
+		s.logger.Error("Failed to encode account archive", zap.String("user_id", account.ID().String()), zap.Error(err))
+		return nil, errors.NewInternalError("The account archive could not be written")
+	}
+
+	s.logger.Info("Account exported",
+		zap.String("user_id", account.ID().String()),
+		zap.String("format", format),
+		zap.Int("recipes", len(archive.Recipes)),
+		zap.Int("collections", len(archive.Collections)),
+		zap.Int("bytes", len(file.Content)),
+	)
+	return file, nil
+}
+
+// ImportAccount restores an archive. Recipes go through the library
+// importer, so they come back as drafts and titles already in the account
+// are skipped; collections and saved recipes then point at the restored
+// copies. Collections whose name is taken are skipped, and recipes of
+// other users that are no longer published are left out.
+func (s *Service) ImportAccount(ctx context.Context, cmd inbound.AccountImportCommand) (*inbound.AccountImportResult, error) {
+	archive, err := Decode(cmd.Content)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	account, err := s.findUser(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	result := &inbound.AccountImportResult{}
+
+	// Archive recipe IDs to the IDs they have in this account
+	restored := make(map[uuid.UUID]uuid.UUID, len(archive.Recipes))
+	if len(archive.Recipes) > 0 {
+		imported := make([]inbound.ImportedRecipe, len(archive.Recipes))
+		for i, r := range archive.Recipes {
+			imported[i] = toImported(r)
+		}
+		result.Recipes, err = s.recipes.ImportRecipeLibrary(ctx, inbound.ImportLibraryCommand{
+			UserID:         account.ID(),
+			Source:         Source,
+			Recipes:        imported,
+			KeepDuplicates: cmd.KeepDuplicates,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i, item := range result.Recipes.Items {
+			switch {
+			case item.Status == statusCreated && item.RecipeID != nil:
+				restored[archive.Recipes[i].RecipeID] = *item.RecipeID
+			case item.Status == statusDuplicate && item.DuplicateOf != nil:
+				restored[archive.Recipes[i].RecipeID] = *item.DuplicateOf
+			}
+		}
+	}
+
+	// Anything not restored must still be there for this user to see
+	var others []uuid.UUID
+	for _, c := range archive.Collections {
+		others = append(others, c.Recipes...)
+	}
+	for _, f := range archive.Favorites {
+		others = append(others, f.RecipeID)
+	}
+	available, err := s.visibleTitles(ctx, account.ID(), others)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipes", err)
+	}
+	resolve := func(id uuid.UUID) (uuid.UUID, bool) {
+		if restoredID, ok := restored[id]; ok {
+			return restoredID, true
+		}
+		_, ok := available[id]
+		return id, ok
+	}
+
+	if err := s.restoreCollections(ctx, account.ID(), archive.Collections, resolve, result); err != nil {
+		return nil, err
+	}
+
+	missing := 0
+	for _, f := range archive.Favorites {
+		id, ok := resolve(f.RecipeID)
+		if !ok {
+			missing++
+			continue
+		}
+		added, err := s.favorites.Add(ctx, account.ID(), id, f.SavedAt)
+		if err != nil {
+			return nil, errors.NewDatabaseError("save recipe", err)
+		}
+		if added {
+			result.Favorites++
+		}
+	}
+	if missing > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d saved recipes are no longer available", missing))
+	}
+
+	if err := s.restorePreferences(ctx, account, archive, result); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Account imported",
+		zap.String("user_id", account.ID().String()),
+		zap.Int("recipes", len(archive.Recipes)),
+		zap.Int("collections", result.Collections),
+		zap.Int("favorites", result.Favorites),
+		zap.Int("warnings", len(result.Warnings)),
+	)
+	return result, nil
+}
+
+func (s *Service) restoreCollections(
+	ctx context.Context,
+	userID uuid.UUID,
+	collections []Collection,
+	resolve func(uuid.UUID) (uuid.UUID, bool),
+	result *inbound.AccountImportResult,
+) error {
+	if len(collections) == 0 {
+		return nil
+	}
+	existing, err := s.collections.ListByUser(ctx, userID)
+	if err != nil {
+		return errors.NewDatabaseError("find collections", err)
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, c := range existing {
+		taken[strings.ToLower(strings.TrimSpace(c.Name))] = true
+	}
+
+	for _, c := range collections {
+		name := strings.TrimSpace(c.Name)
+		key := strings.ToLower(name)
+		if name == "" || taken[key] {
+			result.CollectionsSkipped++
+			continue
+		}
+		collection := &outbound.Collection{
+			UserID:      userID,
+			Name:        name,
+			Description: strings.TrimSpace(c.Description),
+			IsPublic:    c.Public,
+			CreatedAt:   c.CreatedAt,
+		}
+		for _, id := range c.Recipes {
+			if restoredID, ok := resolve(id); ok {
+				collection.RecipeIDs = append(collection.RecipeIDs, restoredID)
+			}
+		}
+		if err := s.collections.Create(ctx, collection); err != nil {
+			return errors.NewDatabaseError("create collection", err)
+		}
+		taken[key] = true
+		result.Collections++
+		if missing := len(c.Recipes) - len(collection.RecipeIDs); missing > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%d recipes in %q are no longer available", missing, name))
+		}
+	}
+	return nil
+}
+
+// restorePreferences replaces the user's preferences with the archive's,
+// dropping values this site does not know, and fills in blank profile
+// fields
+func (s *Service) restorePreferences(ctx context.Context, account *user.User, archive *Archive, result *inbound.AccountImportResult) error {
+	p := archive.Preferences
+	prefs := &user.UserPreferences{}
+	if current := account.Preferences(); current != nil {
+		copied := *current
+		prefs = &copied
+	}
+	warn := func(format string, args ...interface{}) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	if p.Theme != "" {
+		if theme, err := user.ParseTheme(p.Theme); err == nil {
+			prefs.Theme = theme
+		} else {
+			warn("Skipped unknown theme %q", p.Theme)
+		}
+	}
+	if p.MeasurementSystem != "" {
+		if system, err := user.ParseMeasurementSystem(p.MeasurementSystem); err == nil {
+			prefs.MeasurementSystem = system
+		} else {
+			warn("Skipped unknown measurement system %q", p.MeasurementSystem)
+		}
+	}
+	if p.Language != "" {
+		prefs.Language = p.Language
+	}
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err == nil {
+			prefs.Timezone = p.Timezone
+		} else {
+			warn("Skipped unknown time zone %q", p.Timezone)
+		}
+	}
+
+	prefs.DietaryRestrictions = nil
+	for _, raw := range p.DietaryRestrictions {
+		if restriction, err := user.ParseDietaryRestriction(raw); err == nil {
+			prefs.DietaryRestrictions = append(prefs.DietaryRestrictions, restriction)
+		} else {
+			warn("Skipped unknown diet %q", raw)
+		}
+	}
+	prefs.Allergies = nil
+	for _, raw := range p.Allergies {
+		if allergen, ok := allergens.Parse(raw); ok {
+			prefs.Allergies = append(prefs.Allergies, string(allergen))
+		} else {
+			warn("Skipped unknown allergen %q", raw)
+		}
+	}
+	prefs.MutedNotifications = nil
+	for _, t := range p.MutedNotifications {
+		if notification.Type(t).Valid() {
+			prefs.MutedNotifications = append(prefs.MutedNotifications, t)
+		}
+	}
+	prefs.DislikedIngredients = append([]string(nil), p.DislikedIngredients...)
+	prefs.PreferredCuisines = append([]string(nil), p.PreferredCuisines...)
+	prefs.EmailNotifications = p.EmailNotifications
+	prefs.PushNotifications = p.PushNotifications
+	prefs.DisablePersonalization = !p.PersonalizedSearch
+	prefs.PrivateProfile = p.PrivateProfile
+	account.UpdatePreferences(prefs)
+
+	profile := &user.UserProfile{}
+	if current := account.Profile(); current != nil {
+		copied := *current
+		profile = &copied
+	}
+	a := archive.Account
+	fill := func(field *string, value string) {
+		if strings.TrimSpace(*field) == "" {
+			*field = strings.TrimSpace(value)
+		}
+	}
+	fill(&profile.FirstName, a.FirstName)
+	fill(&profile.LastName, a.LastName)
+	fill(&profile.Bio, a.Bio)
+	fill(&profile.Location, a.Location)
+	fill(&profile.Website, a.Website)
+	if profile.Birthday == nil {
+		profile.Birthday = a.Birthday
+	}
+	if profile.CookingLevel == "" {
+		switch level := user.CookingLevel(a.CookingLevel); level {
+		case user.CookingLevelBeginner, user.CookingLevelIntermediate, user.CookingLevelAdvanced, user.CookingLevelProfessional:
+			profile.CookingLevel = level
+		}
+	}
+	account.UpdateProfile(profile)
+
+	if err := s.userRepo.Update(ctx, account); err != nil {
+		return errors.NewDatabaseError("update preferences", err)
+	}
+	s.invalidateUser(ctx, account.ID())
+	result.Preferences = true
+	return nil
+}
+
+// ownRecipes loads every recipe the user wrote, oldest first, with their
+// ingredients and steps
+func (s *Service) ownRecipes(ctx context.Context, userID uuid.UUID) ([]*recipe.Recipe, error) {
+	var ids []uuid.UUID
+	for offset := 0; ; offset += recipePageSize {
+		page, total, err := s.recipeRepo.FindByUserID(ctx, userID, offset, recipePageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range page {
+			ids = append(ids, r.ID())
+		}
+		if len(page) == 0 || offset+len(page) >= total {
+			break
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	recipes, err := s.recipeRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(recipes, func(i, j int) bool { return recipes[i].CreatedAt().Before(recipes[j].CreatedAt()) })
+	return recipes, nil
+}
+
+// visibleTitles maps the recipes among ids the user can still open, their
+// own or published, to their titles
+func (s *Service) visibleTitles(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]string, error) {
+	titles := make(map[uuid.UUID]string, len(ids))
+	if len(ids) == 0 {
+		return titles, nil
+	}
+	recipes, err := s.recipeRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range recipes {
+		if r.Status() == recipe.RecipeStatusPublished || r.AuthorID() == userID {
+			titles[r.ID()] = r.Title()
+		}
+	}
+	return titles, nil
+}
+
+func (s *Service) toRecipe(entity *recipe.Recipe, author string) Recipe {
+	credit := printout.Attribution{Author: author}
+	if entity.Status() == recipe.RecipeStatusPublished && s.cfg.SiteURL != "" {
+		credit.URL = s.cfg.SiteURL + "/recipes/" + entity.ID().String()
+	}
+	r := Recipe{
+		Printout:   printout.Build(entity, credit),
+		Status:     string(entity.Status()),
+		Cuisine:    string(entity.Cuisine()),
+		Category:   string(entity.Category()),
+		Difficulty: string(entity.Difficulty()),
+		Tags:       entity.Tags(),
+		CreatedAt:  entity.CreatedAt().UTC(),
+	}
+	if published := entity.PublishedAt(); published != nil {
+		at := published.UTC()
+		r.PublishedAt = &at
+	}
+	return r
+}
+
+func (s *Service) findUser(ctx context.Context, userID uuid.UUID) (*user.User, error) {
+	accounts, err := s.userRepo.FindByIDs(ctx, []uuid.UUID{userID})
+	if err != nil {
+		return nil, errors.NewDatabaseError("find user", err)
+	}
+	if len(accounts) == 0 {
+		return nil, errors.NewUserNotFoundError(userID.String())
+	}
+	return accounts[0], nil
+}
+
+// invalidateUser drops the cached user here and on the other replicas, as
+// the user service does after a profile change
+func (s *Service) invalidateUser(ctx context.Context, userID uuid.UUID) {
+	key := "user:" + userID.String()
+	if s.cache != nil {
+		s.cache.Delete(ctx, key)
+	}
+	if s.invalidations == nil {
+		return
+	}
+	if err := s.invalidations.Publish(ctx, key); err != nil {
+		s.logger.Warn("Failed to broadcast user invalidation", zap.String("key", key), zap.Error(err))
+	}
+}
+
+func toAccount(account *user.User) Account {
+	a := Account{
+		ID:        account.ID(),
+		Email:     account.Email(),
+		Name:      account.Name(),
+		CreatedAt: account.CreatedAt().UTC(),
+	}
+	if p := account.Profile(); p != nil {
+		a.FirstName = p.FirstName
+		a.LastName = p.LastName
+		a.Avatar = p.Avatar
+		a.Bio = p.Bio
+		a.Location = p.Location
+		a.Website = p.Website
+		a.Birthday = p.Birthday
+		a.CookingLevel = string(p.CookingLevel)
+	}
+	return a
+}
+
+func toPreferences(account *user.User) Preferences {
+	p := Preferences{
+		DietaryRestrictions: []string{},
+		Allergies:           []string{},
+		DislikedIngredients: []string{},
+		PreferredCuisines:   []string{},
+		MutedNotifications:  []string{},
+		PersonalizedSearch:  account.PersonalizationEnabled(),
+		PrivateProfile:      account.IsPrivate(),
+		MeasurementSystem:   string(account.MeasurementSystem()),
+	}
+	prefs := account.Preferences()
+	if prefs == nil {
+		return p
+	}
+	p.Theme = string(prefs.Theme)
+	p.Language = prefs.Language
+	p.Timezone = prefs.Timezone
+	for _, restriction := range prefs.DietaryRestrictions {
+		p.DietaryRestrictions = append(p.DietaryRestrictions, string(restriction))
+	}
+	p.Allergies = append(p.Allergies, prefs.Allergies...)
+	p.DislikedIngredients = append(p.DislikedIngredients, prefs.DislikedIngredients...)
+	p.PreferredCuisines = append(p.PreferredCuisines, prefs.PreferredCuisines...)
+	p.MutedNotifications = append(p.MutedNotifications, prefs.MutedNotifications...)
+	p.EmailNotifications = prefs.EmailNotifications
+	p.PushNotifications = prefs.PushNotifications
+	return p
+}
+
+// toImported turns an archived recipe back into the lines the library
+// importer reads
+func toImported(r Recipe) inbound.ImportedRecipe {
+	imported := inbound.ImportedRecipe{
+		Title:       r.Title,
+		Description: r.Description,
+		Ingredients: make([]string, len(r.Ingredients)),
+		Directions:  make([]string, len(r.Steps)),
+		Servings:    r.Servings,
+		PrepTime:    r.PrepMinutes,
+		CookTime:    r.CookMinutes,
+		Tags:        r.Tags,
+	}
+	for i, ingredient := range r.Ingredients {
+		imported.Ingredients[i] = ingredient.Text
+		if imported.Ingredients[i] == "" {
+			imported.Ingredients[i] = ingredient.Name
+		}
+	}
+	for i, step := range r.Steps {
+		imported.Directions[i] = step.Text
+	}
+	return imported
+}
+
+func favoriteIDs(favorites []outbound.Favorite) []uuid.UUID {
+	ids := make([]uuid.UUID, len(favorites))
+	for i, f := range favorites {
+		ids[i] = f.RecipeID
+	}
+	return ids
+}
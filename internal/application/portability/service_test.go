@@ -0,0 +1,235 @@
+package portability
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type recipeStore struct {
+	outbound.RecipeRepository
+	recipes []*recipe.Recipe
+}
+
+func (s *recipeStore) FindByUserID(_ context.Context, userID uuid.UUID, offset, limit int) ([]*recipe.Recipe, int, error) {
+	var own []*recipe.Recipe
+	for _, r := range s.recipes {
+		if r.AuthorID() == userID {
+			own = append(own, r)
+		}
+	}
+	if offset >= len(own) {
+		return nil, len(own), nil
+	}
+	end := offset + limit
+	if end > len(own) {
+		end = len(own)
+	}
+	return own[offset:end], len(own), nil
+}
+
+func (s *recipeStore) FindByIDs(_ context.Context, ids []uuid.UUID) ([]*recipe.Recipe, error) {
+	var found []*recipe.Recipe
+	for _, r := range s.recipes {
+		for _, id := range ids {
+			if r.ID() == id {
+				found = append(found, r)
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+// importer saves each recipe under a new ID, as drafts
+type importer struct {
+	inbound.RecipeService
+	store *recipeStore
+}
+
+func (i *importer) ImportRecipeLibrary(_ context.Context, cmd inbound.ImportLibraryCommand) (*inbound.LibraryImportResult, error) {
+	result := &inbound.LibraryImportResult{Source: cmd.Source}
+	for _, imported := range cmd.Recipes {
+		entity, err := recipe.NewRecipe(imported.Title, imported.Description, cmd.UserID)
+		if err != nil {
+			return nil, err
+		}
+		i.store.recipes = append(i.store.recipes, entity)
+		id := entity.ID()
+		result.Items = append(result.Items, inbound.LibraryImportItem{Title: imported.Title, Status: statusCreated, RecipeID: &id})
+		result.Created++
+	}
+	return result, nil
+}
+
+type userStore struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *userStore) FindByIDs(_ context.Context, ids []uuid.UUID) ([]*user.User, error) {
+	var found []*user.User
+	for _, id := range ids {
+		if u, ok := s.users[id]; ok {
+			found = append(found, u)
+		}
+	}
+	return found, nil
+}
+
+func (s *userStore) Update(_ context.Context, u *user.User) error {
+	s.users[u.ID()] = u
+	return nil
+}
+
+type collectionStore struct{ collections []outbound.Collection }
+
+func (s *collectionStore) ListByUser(_ context.Context, userID uuid.UUID) ([]outbound.Collection, error) {
+	var own []outbound.Collection
+	for _, c := range s.collections {
+		if c.UserID == userID {
+			own = append(own, c)
+		}
+	}
+	return own, nil
+}
+
+func (s *collectionStore) Create(_ context.Context, c *outbound.Collection) error {
+	c.ID = uuid.New()
+	s.collections = append(s.collections, *c)
+	return nil
+}
+
+type favoriteStore struct {
+	outbound.FavoriteRepository
+	saved map[uuid.UUID][]outbound.Favorite
+}
+
+func (s *favoriteStore) Add(_ context.Context, userID, recipeID uuid.UUID, at time.Time) (bool, error) {
+	for _, f := range s.saved[userID] {
+		if f.RecipeID == recipeID {
+			return false, nil
+		}
+	}
+	s.saved[userID] = append(s.saved[userID], outbound.Favorite{RecipeID: recipeID, SavedAt: at})
+	return true, nil
+}
+
+func (s *favoriteStore) List(_ context.Context, userID uuid.UUID) ([]outbound.Favorite, error) {
+	return s.saved[userID], nil
+}
+
+func newUser(t *testing.T, email string) *user.User {
+	t.Helper()
+	u, err := user.NewUser(email, "Sam Cook", "correct horse battery")
+	require.NoError(t, err)
+	return u
+}
+
+func newRecipe(t *testing.T, title string, authorID uuid.UUID, published bool) *recipe.Recipe {
+	t.Helper()
+	r, err := recipe.NewRecipe(title, "", authorID)
+	require.NoError(t, err)
+	require.NoError(t, r.AddIngredient(recipe.Ingredient{Name: "flour", Amount: 2, Unit: recipe.MeasurementUnitCup}))
+	require.NoError(t, r.AddInstruction(recipe.Instruction{Description: "Mix and bake."}))
+	if published {
+		require.NoError(t, r.SetServings(4))
+		require.NoError(t, r.Publish())
+	}
+	return r
+}
+
+func TestExportedArchiveRestoresIntoAnotherAccount(t *testing.T) {
+	ctx := context.Background()
+	owner, newcomer, other := newUser(t, "sam@example.com"), newUser(t, "sam@example.org"), newUser(t, "alex@example.com")
+	owner.SetTheme(user.ThemeDark)
+	owner.SetDietaryProfile([]user.DietaryRestriction{user.DietaryRestrictionVegetarian}, []string{"peanuts"}, []string{"olives"})
+	owner.SetPrivateProfile(true)
+
+	mine := newRecipe(t, "Lemon Bars", owner.ID(), false)
+	theirs := newRecipe(t, "Shortbread", other.ID(), true)
+	gone := newRecipe(t, "Secret Scones", other.ID(), false)
+	recipes := &recipeStore{recipes: []*recipe.Recipe{mine, theirs, gone}}
+	users := &userStore{users: map[uuid.UUID]*user.User{owner.ID(): owner, newcomer.ID(): newcomer}}
+	collections := &collectionStore{collections: []outbound.Collection{
+		{ID: uuid.New(), UserID: owner.ID(), Name: "Bakes", IsPublic: true, RecipeIDs: []uuid.UUID{mine.ID(), theirs.ID(), gone.ID()}},
+		{ID: uuid.New(), UserID: owner.ID(), Name: "Someday"},
+		{ID: uuid.New(), UserID: newcomer.ID(), Name: "someday"},
+	}}
+	favorites := &favoriteStore{saved: map[uuid.UUID][]outbound.Favorite{
+		owner.ID(): {{RecipeID: theirs.ID(), SavedAt: time.Now()}},
+	}}
+	svc := NewService(&importer{store: recipes}, recipes, users, collections, favorites, nil, nil,
+		Config{SiteURL: "https://alchemorsel.example/"}, zap.NewNop())
+
+	file, err := svc.ExportAccount(ctx, inbound.AccountExportQuery{UserID: owner.ID()})
+	require.NoError(t, err)
+	assert.Equal(t, "application/zip", file.ContentType)
+	zr, err := zip.NewReader(bytes.NewReader(file.Content), int64(len(file.Content)))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"account.json", "recipes/lemon-bars.md"}, names)
+
+	archive, err := Decode(file.Content)
+	require.NoError(t, err)
+	assert.Equal(t, "sam@example.com", archive.Account.Email)
+	assert.Equal(t, "dark", archive.Preferences.Theme)
+	require.Len(t, archive.Recipes, 1)
+	assert.Equal(t, "draft", archive.Recipes[0].Status)
+	assert.Equal(t, "2 cup flour", archive.Recipes[0].Ingredients[0].Text)
+	assert.Empty(t, archive.Recipes[0].Attribution.URL, "drafts have no public link")
+	require.Len(t, archive.Favorites, 1)
+	assert.Equal(t, "Shortbread", archive.Favorites[0].Title)
+
+	result, err := svc.ImportAccount(ctx, inbound.AccountImportCommand{UserID: newcomer.ID(), Content: file.Content})
+	require.NoError(t, err)
+	require.NotNil(t, result.Recipes)
+	assert.Equal(t, 1, result.Recipes.Created)
+	restoredID := *result.Recipes.Items[0].RecipeID
+	assert.NotEqual(t, mine.ID(), restoredID)
+
+	assert.Equal(t, 1, result.Collections)
+	assert.Equal(t, 1, result.CollectionsSkipped, "the newcomer already has a collection named someday")
+	bakes := collections.collections[len(collections.collections)-1]
+	assert.Equal(t, newcomer.ID(), bakes.UserID)
+	assert.Equal(t, []uuid.UUID{restoredID, theirs.ID()}, bakes.RecipeIDs, "the unpublished recipe is left out")
+	assert.Contains(t, result.Warnings, `1 recipes in "Bakes" are no longer available`)
+
+	assert.Equal(t, 1, result.Favorites)
+	assert.True(t, result.Preferences)
+	prefs := newcomer.Preferences()
+	require.NotNil(t, prefs)
+	assert.Equal(t, user.ThemeDark, prefs.Theme)
+	assert.Equal(t, []user.DietaryRestriction{user.DietaryRestrictionVegetarian}, prefs.DietaryRestrictions)
+	assert.Equal(t, []string{"olives"}, prefs.DislikedIngredients)
+	assert.True(t, newcomer.IsPrivate())
+}
+
+func TestDecodeRejectsOtherDocuments(t *testing.T) {
+	_, err := Decode([]byte(`{"format":"paprika","version":1}`))
+	assert.ErrorContains(t, err, "not an account archive")
+	_, err = Decode([]byte(`{"format":"alchemorsel-account","version":9}`))
+	assert.ErrorContains(t, err, "version 9")
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_, err = zw.Create("recipes/soup.md")
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	_, err = Decode(buf.Bytes())
+	assert.ErrorContains(t, err, "no account.json")
+}
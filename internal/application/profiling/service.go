@@ -0,0 +1,420 @@
+// Package profiling lets admins capture CPU, heap, goroutine and trace
+// profiles from a running server. Captures stream to blob storage, run one
+// at a time with a capped duration, and every request is audited.
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Defaults used when the config leaves them unset
+const (
+	DefaultDuration = 30 * time.Second
+	MaxDuration     = 60 * time.Second
+	// uploadTimeout is added to the capture duration for storing the result
+	uploadTimeout = 2 * time.Minute
+	recentLimit   = 50
+	maxReason     = 500
+)
+
+// snapshotKinds are written at once rather than sampled over a duration
+var snapshotKinds = map[string]bool{"heap": true, "goroutine": true}
+
+// Config holds the profiling guardrails
+type Config struct {
+	Enabled         bool
+	DefaultDuration time.Duration
+	MaxDuration     time.Duration
+	BlobPrefix      string
+}
+
+// Service runs profile captures and records who asked for them
+type Service struct {
+	profiler outbound.Profiler
+	blobs    outbound.BlobStorage
+	captures outbound.ProfileCaptureRepository
+	userRepo outbound.UserRepository
+	cfg      Config
+	now      func() time.Time
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	running bool
+	wg      sync.WaitGroup
+}
+
+// NewService creates a profiling service
+func NewService(
+	profiler outbound.Profiler,
+	blobs outbound.BlobStorage,
+	captures outbound.ProfileCaptureRepository,
+	userRepo outbound.UserRepository,
+	cfg Config,
+	logger *zap.Logger,
+) *Service {
+	if cfg.DefaultDuration <= 0 {
+		cfg.DefaultDuration = DefaultDuration
+	}
+	if cfg.MaxDuration <= 0 {
+		cfg.MaxDuration = MaxDuration
+	}
+	if cfg.DefaultDuration > cfg.MaxDuration {
+		cfg.DefaultDuration = cfg.MaxDuration
+	}
+	return &Service{
+		profiler: profiler,
+		blobs:    blobs,
+		captures: captures,
+		userRepo: userRepo,
+		cfg:      cfg,
+		now:      time.Now,
+		logger:   logger.Named("profiling"),
+	}
+}
+
+// StartCapture begins a capture in the background. Only one capture runs
+// at a time, since the runtime allows a single CPU profile or trace.
+func (s *Service) StartCapture(ctx context.Context, requesterID uuid.UUID, req inbound.ProfileCaptureRequest) (*inbound.ProfileCapture, error) {
+	if !s.cfg.Enabled {
+		return nil, errors.NewAppError(errors.CodeServiceUnavailable, "Profiling is disabled", "")
+	}
+
+	capture := &outbound.ProfileCapture{
+		ID:          uuid.New(),
+		Kind:        strings.ToLower(strings.TrimSpace(req.Kind)),
+		Seconds:     s.seconds(req.Seconds),
+		Status:      outbound.ProfileCaptureRunning,
+		RequestedBy: requesterID,
+		IPAddress:   req.IPAddress,
+		Reason:      strings.TrimSpace(req.Reason),
+		StartedAt:   s.now().UTC(),
+	}
+	if snapshotKinds[capture.Kind] {
+		capture.Seconds = 0
+	}
+	if err := s.authorize(ctx, requesterID, "capture profiles", capture); err != nil {
+		return nil, err
+	}
+
+	return s.begin(ctx, capture, req.Kind)
+}
+
+// StartSystemCapture begins a capture the server asked for itself. It is
+// recorded with no requesting user and follows the same guardrails.
+func (s *Service) StartSystemCapture(ctx context.Context, kind, reason string) (*inbound.ProfileCapture, error) {
+	if !s.cfg.Enabled {
+		return nil, errors.NewAppError(errors.CodeServiceUnavailable, "Profiling is disabled", "")
+	}
+
+	capture := &outbound.ProfileCapture{
+		ID:        uuid.New(),
+		Kind:      kind,
+		Seconds:   s.seconds(0),
+		Status:    outbound.ProfileCaptureRunning,
+		Reason:    strings.TrimSpace(reason),
+		StartedAt: s.now().UTC(),
+	}
+	if snapshotKinds[capture.Kind] {
+		capture.Seconds = 0
+	}
+	return s.begin(ctx, capture, kind)
+}
+
+// ListCaptures returns the latest captures and pprof accesses
+func (s *Service) ListCaptures(ctx context.Context, requesterID uuid.UUID) ([]*inbound.ProfileCapture, error) {
+	if err := s.authorize(ctx, requesterID, "view profiles", nil); err != nil {
+		return nil, err
+	}
+
+	captures, err := s.captures.FindRecent(ctx, recentLimit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find profile captures", err)
+	}
+
+	result := make([]*inbound.ProfileCapture, len(captures))
+	for i, capture := range captures {
+		result[i] = toCaptureDTO(capture)
+	}
+	return result, nil
+}
+
+// GetCapture returns one capture
+func (s *Service) GetCapture(ctx context.Context, requesterID, captureID uuid.UUID) (*inbound.ProfileCapture, error) {
+	capture, err := s.findCapture(ctx, requesterID, captureID)
+	if err != nil {
+		return nil, err
+	}
+	return toCaptureDTO(capture), nil
+}
+
+// OpenCapture streams a completed capture. The caller closes the reader.
+func (s *Service) OpenCapture(ctx context.Context, requesterID, captureID uuid.UUID) (io.ReadCloser, *inbound.ProfileCapture, error) {
+	capture, err := s.findCapture(ctx, requesterID, captureID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if capture.Status != outbound.ProfileCaptureCompleted || capture.BlobKey == "" {
+		return nil, nil, errors.NewConflictError("Profile capture is not complete")
+	}
+
+	content, err := s.blobs.Get(ctx, capture.BlobKey)
+	if err != nil {
+		return nil, nil, errors.NewExternalServiceError("blob storage", err)
+	}
+	return content, toCaptureDTO(capture), nil
+}
+
+// AuthorizeDebug lets admins use the raw pprof endpoints and records each
+// call. Sampled endpoints are held to the same duration cap as captures.
+func (s *Service) AuthorizeDebug(ctx context.Context, requesterID uuid.UUID, req inbound.DebugAccessRequest) error {
+	if !s.cfg.Enabled {
+		return errors.NewAppError(errors.CodeServiceUnavailable, "Profiling is disabled", "")
+	}
+
+	now := s.now().UTC()
+	access := &outbound.ProfileCapture{
+		ID:          uuid.New(),
+		Kind:        "pprof:" + req.Endpoint,
+		Seconds:     req.Seconds,
+		Status:      outbound.ProfileCaptureCompleted,
+		RequestedBy: requesterID,
+		IPAddress:   req.IPAddress,
+		StartedAt:   now,
+		FinishedAt:  &now,
+	}
+	if err := s.authorize(ctx, requesterID, "access pprof", access); err != nil {
+		return err
+	}
+
+	if limit := int(s.cfg.MaxDuration / time.Second); req.Seconds > limit {
+		access.Status = outbound.ProfileCaptureDenied
+		access.Error = fmt.Sprintf("duration over the %ds limit", limit)
+		s.audit(ctx, access)
+		return errors.NewBadRequestError(fmt.Sprintf("seconds must be at most %d", limit))
+	}
+
+	s.audit(ctx, access)
+	s.logger.Info("pprof endpoint accessed",
+		zap.String("endpoint", req.Endpoint),
+		zap.Int("seconds", req.Seconds),
+		zap.String("user_id", requesterID.String()),
+		zap.String("ip", req.IPAddress),
+	)
+	return nil
+}
+
+// Wait blocks until running captures finish
+func (s *Service) Wait() {
+	s.wg.Wait()
+}
+
+// run streams the profile through a pipe into blob storage, so large traces
+// are never held in memory
+func (s *Service) run(capture outbound.ProfileCapture) {
+	defer s.wg.Done()
+	defer s.release()
+
+	duration := time.Duration(capture.Seconds) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), duration+uploadTimeout)
+	defer cancel()
+
+	reader, writer := io.Pipe()
+	captured := make(chan error, 1)
+	go func() {
+		err := s.profiler.Capture(ctx, capture.Kind, duration, writer)
+		writer.CloseWithError(err)
+		captured <- err
+	}()
+
+	size, err := s.blobs.Put(ctx, capture.BlobKey, "application/octet-stream", reader)
+	reader.CloseWithError(err)
+	if captureErr := <-captured; captureErr != nil {
+		err = captureErr
+	}
+
+	finished := s.now().UTC()
+	capture.FinishedAt = &finished
+	capture.SizeBytes = size
+	capture.Status = outbound.ProfileCaptureCompleted
+	if err != nil {
+		capture.Status = outbound.ProfileCaptureFailed
+		capture.Error = err.Error()
+		s.logger.Error("Profile capture failed", zap.String("capture_id", capture.ID.String()), zap.Error(err))
+	} else {
+		s.logger.Info("Profile capture stored",
+			zap.String("capture_id", capture.ID.String()),
+			zap.String("blob_key", capture.BlobKey),
+			zap.Int64("size_bytes", size),
+		)
+	}
+
+	if err := s.captures.Update(context.Background(), &capture); err != nil {
+		s.logger.Error("Failed to record profile capture outcome", zap.String("capture_id", capture.ID.String()), zap.Error(err))
+	}
+}
+
+// begin checks the request and starts the capture if the profiler is free
+func (s *Service) begin(ctx context.Context, capture *outbound.ProfileCapture, requestedKind string) (*inbound.ProfileCapture, error) {
+	if !s.supports(capture.Kind) {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Unsupported profile kind %q; use one of %s", requestedKind, strings.Join(s.profiler.Kinds(), ", ")))
+	}
+	if capture.Reason == "" {
+		return nil, errors.NewBadRequestError("A reason is required to capture a profile")
+	}
+	if len(capture.Reason) > maxReason {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Reason must be at most %d characters", maxReason))
+	}
+
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, errors.NewConflictError("A profile capture is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	capture.BlobKey = s.blobKey(capture)
+	if err := s.captures.Create(ctx, capture); err != nil {
+		s.release()
+		return nil, errors.NewDatabaseError("create profile capture", err)
+	}
+
+	s.logger.Info("Profile capture started",
+		zap.String("capture_id", capture.ID.String()),
+		zap.String("kind", capture.Kind),
+		zap.Int("seconds", capture.Seconds),
+		zap.String("user_id", capture.RequestedBy.String()),
+		zap.String("ip", capture.IPAddress),
+		zap.String("reason", capture.Reason),
+	)
+
+	s.wg.Add(1)
+	go s.run(*capture)
+
+	return toCaptureDTO(capture), nil
+}
+
+// authorize lets admins through. Refused profiling requests are recorded
+// when audit is set.
+func (s *Service) authorize(ctx context.Context, requesterID uuid.UUID, action string, audit *outbound.ProfileCapture) error {
+	requester, err := s.userRepo.FindByID(ctx, requesterID)
+	if err != nil {
+		return errors.NewDatabaseError("find user", err)
+	}
+	if requester == nil {
+		return errors.NewUserNotFoundError(requesterID.String())
+	}
+	if requester.Role() == user.UserRoleAdmin {
+		return nil
+	}
+
+	if audit != nil {
+		audit.Status = outbound.ProfileCaptureDenied
+		audit.Error = "requester is not an admin"
+		audit.BlobKey = ""
+		audit.FinishedAt = &audit.StartedAt
+		s.audit(ctx, audit)
+	}
+	s.logger.Warn("Profiling request denied",
+		zap.String("action", action),
+		zap.String("user_id", requesterID.String()),
+	)
+	return errors.NewInsufficientPermissionsError(action)
+}
+
+// audit records an access. A failed write is logged rather than returned
+// so a database problem cannot block diagnosing it.
+func (s *Service) audit(ctx context.Context, entry *outbound.ProfileCapture) {
+	if err := s.captures.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to record profiling audit entry",
+			zap.String("kind", entry.Kind),
+			zap.String("user_id", entry.RequestedBy.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *Service) findCapture(ctx context.Context, requesterID, captureID uuid.UUID) (*outbound.ProfileCapture, error) {
+	if err := s.authorize(ctx, requesterID, "view profiles", nil); err != nil {
+		return nil, err
+	}
+
+	capture, err := s.captures.FindByID(ctx, captureID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find profile capture", err)
+	}
+	if capture == nil {
+		return nil, errors.NewNotFoundError("profile capture")
+	}
+	return capture, nil
+}
+
+// seconds applies the default and the cap to a requested duration
+func (s *Service) seconds(requested int) int {
+	duration := time.Duration(requested) * time.Second
+	if requested <= 0 {
+		duration = s.cfg.DefaultDuration
+	}
+	if duration > s.cfg.MaxDuration {
+		duration = s.cfg.MaxDuration
+	}
+	return int(duration / time.Second)
+}
+
+func (s *Service) supports(kind string) bool {
+	for _, supported := range s.profiler.Kinds() {
+		if kind == supported {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) release() {
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+}
+
+// blobKey groups captures by day, e.g. profiles/2024/05/01/<id>.cpu.pb.gz
+func (s *Service) blobKey(capture *outbound.ProfileCapture) string {
+	ext := ".pb.gz"
+	if capture.Kind == "trace" {
+		ext = ".out"
+	}
+	return fmt.Sprintf("%s%s/%s.%s%s", s.cfg.BlobPrefix, capture.StartedAt.Format("2006/01/02"), capture.ID, capture.Kind, ext)
+}
+
+func toCaptureDTO(capture *outbound.ProfileCapture) *inbound.ProfileCapture {
+	dto := &inbound.ProfileCapture{
+		ID:          capture.ID,
+		Kind:        capture.Kind,
+		Seconds:     capture.Seconds,
+		Status:      capture.Status,
+		RequestedBy: capture.RequestedBy,
+		Reason:      capture.Reason,
+		SizeBytes:   capture.SizeBytes,
+		Error:       capture.Error,
+		StartedAt:   capture.StartedAt.Format(time.RFC3339),
+	}
+	if capture.BlobKey != "" {
+		dto.FileName = path.Base(capture.BlobKey)
+	}
+	if capture.FinishedAt != nil {
+		dto.FinishedAt = capture.FinishedAt.Format(time.RFC3339)
+	}
+	return dto
+}
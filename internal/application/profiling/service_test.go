@@ -0,0 +1,177 @@
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+type stubProfiler struct {
+	release  chan struct{}
+	duration time.Duration
+}
+
+func (p *stubProfiler) Kinds() []string { return []string{"cpu", "heap", "trace"} }
+
+func (p *stubProfiler) Capture(ctx context.Context, kind string, duration time.Duration, w io.Writer) error {
+	p.duration = duration
+	<-p.release
+	_, err := w.Write([]byte(kind + " profile"))
+	return err
+}
+
+type memoryBlobs struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func (m *memoryBlobs) Put(ctx context.Context, key, contentType string, content io.Reader) (int64, error) {
+	data, err := io.ReadAll(content)
+	m.mu.Lock()
+	m.blobs[key] = data
+	m.mu.Unlock()
+	return int64(len(data)), err
+}
+
+func (m *memoryBlobs) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return io.NopCloser(bytes.NewReader(m.blobs[key])), nil
+}
+
+type memoryCaptures struct {
+	mu       sync.Mutex
+	captures []*outbound.ProfileCapture
+}
+
+func (m *memoryCaptures) Create(ctx context.Context, capture *outbound.ProfileCapture) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *capture
+	m.captures = append(m.captures, &stored)
+	return nil
+}
+
+func (m *memoryCaptures) Update(ctx context.Context, capture *outbound.ProfileCapture) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, stored := range m.captures {
+		if stored.ID == capture.ID {
+			updated := *capture
+			m.captures[i] = &updated
+		}
+	}
+	return nil
+}
+
+func (m *memoryCaptures) FindByID(ctx context.Context, id uuid.UUID) (*outbound.ProfileCapture, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, stored := range m.captures {
+		if stored.ID == id {
+			found := *stored
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryCaptures) FindRecent(ctx context.Context, limit int) ([]*outbound.ProfileCapture, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*outbound.ProfileCapture(nil), m.captures...), nil
+}
+
+func newTestService(t *testing.T) (*Service, *stubProfiler, *memoryCaptures, *user.User, *user.User) {
+	t.Helper()
+	now := time.Now()
+	admin := user.ReconstructUser(uuid.New(), "root@example.com", "Root", "", true, true, user.UserRoleAdmin, now, now, nil)
+	cook := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	profiler := &stubProfiler{release: make(chan struct{})}
+	captures := &memoryCaptures{}
+	svc := NewService(profiler, &memoryBlobs{blobs: map[string][]byte{}}, captures,
+		&stubUsers{users: map[uuid.UUID]*user.User{admin.ID(): admin, cook.ID(): cook}},
+		Config{Enabled: true, MaxDuration: 20 * time.Second, BlobPrefix: "profiles/"}, zap.NewNop())
+	return svc, profiler, captures, admin, cook
+}
+
+func TestCaptureStreamsToBlobStorageOneAtATime(t *testing.T) {
+	svc, profiler, _, admin, _ := newTestService(t)
+	ctx := context.Background()
+
+	capture, err := svc.StartCapture(ctx, admin.ID(), inbound.ProfileCaptureRequest{Kind: "CPU", Seconds: 600, Reason: "slow search"})
+	require.NoError(t, err)
+	assert.Equal(t, outbound.ProfileCaptureRunning, capture.Status)
+	assert.Equal(t, 20, capture.Seconds, "the duration is capped")
+
+	_, err = svc.StartCapture(ctx, admin.ID(), inbound.ProfileCaptureRequest{Kind: "heap", Reason: "memory"})
+	assert.True(t, errors.Is(err, errors.CodeConflict), "a second capture waits for the first")
+
+	_, _, err = svc.OpenCapture(ctx, admin.ID(), capture.ID)
+	assert.True(t, errors.Is(err, errors.CodeConflict), "a running capture cannot be downloaded")
+
+	close(profiler.release)
+	svc.Wait()
+	assert.Equal(t, 20*time.Second, profiler.duration)
+
+	content, done, err := svc.OpenCapture(ctx, admin.ID(), capture.ID)
+	require.NoError(t, err)
+	defer content.Close()
+	data, _ := io.ReadAll(content)
+	assert.Equal(t, "cpu profile", string(data))
+	assert.Equal(t, outbound.ProfileCaptureCompleted, done.Status)
+	assert.EqualValues(t, len(data), done.SizeBytes)
+
+	heap, err := svc.StartCapture(ctx, admin.ID(), inbound.ProfileCaptureRequest{Kind: "heap", Seconds: 10, Reason: "memory"})
+	require.NoError(t, err, "the profiler is free again")
+	assert.Zero(t, heap.Seconds, "snapshots have no duration")
+	svc.Wait()
+}
+
+func TestProfilingIsAdminOnlyAndAudited(t *testing.T) {
+	svc, _, captures, admin, cook := newTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.StartCapture(ctx, cook.ID(), inbound.ProfileCaptureRequest{Kind: "cpu", Reason: "curious", IPAddress: "203.0.113.9"})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	err = svc.AuthorizeDebug(ctx, cook.ID(), inbound.DebugAccessRequest{Endpoint: "heap"})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	_, err = svc.ListCaptures(ctx, cook.ID())
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+
+	err = svc.AuthorizeDebug(ctx, admin.ID(), inbound.DebugAccessRequest{Endpoint: "profile", Seconds: 30})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest), "raw endpoints share the duration cap")
+	require.NoError(t, svc.AuthorizeDebug(ctx, admin.ID(), inbound.DebugAccessRequest{Endpoint: "profile", Seconds: 5}))
+
+	entries, err := svc.ListCaptures(ctx, admin.ID())
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, outbound.ProfileCaptureDenied, entries[0].Status)
+	assert.Equal(t, "curious", entries[0].Reason)
+	assert.Equal(t, "203.0.113.9", captures.captures[0].IPAddress)
+	assert.Equal(t, "pprof:heap", entries[1].Kind)
+	assert.Equal(t, outbound.ProfileCaptureDenied, entries[2].Status)
+	assert.Equal(t, outbound.ProfileCaptureCompleted, entries[3].Status)
+	assert.Equal(t, "pprof:profile", entries[3].Kind)
+}
@@ -0,0 +1,319 @@
+// Package recipe provides per-recipe analytics for authors
+package recipe
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultAnalyticsDays is the stats window when none is requested
+	defaultAnalyticsDays = 30
+	// maxAnalyticsDays bounds the stats window
+	maxAnalyticsDays = 365
+	// analyticsTopN caps the search term and referrer lists
+	analyticsTopN = 20
+	// maxEngagement drops time on page beyond a plausible visit, such as a
+	// tab left open overnight
+	maxEngagement = 4 * time.Hour
+	// maxReferrerLength and the other limits match the recipe_views columns
+	maxReferrerLength  = 2048
+	maxSessionIDLength = 64
+	maxUserAgentLength = 512
+)
+
+// searchParams are the query string keys search engines and our own search
+// pages use for the search text
+var searchParams = []string{"q", "query", "search", "p"}
+
+// RecordRecipeView stores a visit to a recipe page with where it came from.
+// The returned view ID is used to report engagement when the visitor leaves.
+func (s *RecipeService) RecordRecipeView(ctx context.Context, cmd inbound.RecordRecipeViewCommand) (uuid.UUID, error) {
+	entity, err := s.recipeRepo.FindByID(ctx, cmd.RecipeID)
+	if err != nil {
+		return uuid.Nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil {
+		return uuid.Nil, errors.NewRecipeNotFoundError(cmd.RecipeID.String())
+	}
+
+	searchTerm := cmd.SearchTerm
+	if strings.TrimSpace(searchTerm) == "" {
+		searchTerm = referrerSearchTerm(cmd.Referrer)
+	}
+
+	view := outbound.RecipeView{
+		ID:           uuid.New(),
+		RecipeID:     cmd.RecipeID,
+		UserID:       cmd.UserID,
+		SessionID:    truncate(strings.TrimSpace(cmd.SessionID), maxSessionIDLength),
+		UserAgent:    truncate(cmd.UserAgent, maxUserAgentLength),
+		Referrer:     truncate(cmd.Referrer, maxReferrerLength),
+		ReferrerHost: referrerHost(cmd.Referrer),
+		SearchTerm:   normalizeQuery(searchTerm),
+		ViewedAt:     time.Now(),
+	}
+	if err := s.viewAnalytics.RecordView(ctx, view); err != nil {
+		return uuid.Nil, errors.NewDatabaseError("record recipe view", err)
+	}
+	// The view is stored, so a missed count only understates the total. A
+	// viewer opening the recipe again within the window is not counted.
+	if _, err := s.views.Count(ctx, cmd.RecipeID, cmd.UserID, view.SessionID); err != nil {
+		s.logger.Warn("Failed to count recipe view",
+			zap.String("recipe_id", cmd.RecipeID.String()),
+			zap.Error(err),
+		)
+	}
+
+	return view.ID, nil
+}
+
+// RecordRecipeEngagement stores the scroll depth and time on page of a view
+func (s *RecipeService) RecordRecipeEngagement(ctx context.Context, cmd inbound.RecordRecipeEngagementCommand) error {
+	if cmd.ScrollDepth < 0 || cmd.ScrollDepth > 100 {
+		return errors.NewBadRequestError("scroll_depth must be between 0 and 100")
+	}
+	if cmd.EngagedMS < 0 {
+		return errors.NewBadRequestError("engaged_ms must not be negative")
+	}
+
+	engaged := time.Duration(cmd.EngagedMS) * time.Millisecond
+	if engaged > maxEngagement {
+		engaged = maxEngagement
+	}
+
+	if err := s.viewAnalytics.RecordEngagement(ctx, cmd.RecipeID, cmd.ViewID, cmd.ScrollDepth, engaged); err != nil {
+		return errors.NewDatabaseError("record recipe engagement", err)
+	}
+	return nil
+}
+
+// GetRecipeAnalytics builds the author stats page for one recipe over the
+// last days. Only the author and admins may see it.
+func (s *RecipeService) GetRecipeAnalytics(ctx context.Context, query inbound.RecipeAnalyticsQuery) (*inbound.RecipeAnalytics, error) {
+	requester, err := s.userRepo.FindByID(ctx, query.RequesterID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find user", err)
+	}
+	if requester == nil {
+		return nil, errors.NewUserNotFoundError(query.RequesterID.String())
+	}
+
+	entity, err := s.recipeRepo.FindByID(ctx, query.RecipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil {
+		return nil, errors.NewRecipeNotFoundError(query.RecipeID.String())
+	}
+	if entity.AuthorID() != requester.ID() && requester.Role() != user.UserRoleAdmin {
+		return nil, errors.NewInsufficientPermissionsError("view this recipe's analytics")
+	}
+
+	days := query.Days
+	if days <= 0 {
+		days = defaultAnalyticsDays
+	}
+	if days > maxAnalyticsDays {
+		days = maxAnalyticsDays
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, 1-days)
+	stats, err := s.viewAnalytics.RecipeViewStats(ctx, query.RecipeID, since, analyticsTopN)
+	if err != nil {
+		return nil, errors.NewDatabaseError("load recipe analytics", err)
+	}
+
+	analytics := &inbound.RecipeAnalytics{
+		RecipeID:           entity.ID(),
+		Title:              entity.Title(),
+		Since:              since.Format("2006-01-02"),
+		Until:              today.Format("2006-01-02"),
+		Views:              stats.Views,
+		UniqueViewers:      stats.UniqueViewers,
+		Likes:              stats.Likes,
+		Bookmarks:          stats.Bookmarks,
+		LikeConversion:     conversion(stats.Likes, stats.UniqueViewers),
+		BookmarkConversion: conversion(stats.Bookmarks, stats.UniqueViewers),
+		ScrollSamples:      stats.ScrollSamples,
+		Daily:              dailyViews(stats.Daily, since, days),
+		SearchTerms:        analyticsCounts(stats.SearchTerms),
+		Referrers:          analyticsCounts(stats.Referrers),
+	}
+	if stats.ScrollSamples > 0 {
+		depth := math.Round(stats.AvgScrollDepth*10) / 10
+		analytics.AverageScrollDepth = &depth
+	}
+
+	s.logger.Debug("Recipe analytics loaded",
+		zap.String("recipe_id", query.RecipeID.String()),
+		zap.Int("days", days),
+		zap.Int("views", stats.Views),
+	)
+
+	return analytics, nil
+}
+
+// GetAuthorAnalytics totals the activity on the author's recipes over the
+// last days, per day for the charts and per recipe for the table. Recipes
+// are listed most viewed first.
+func (s *RecipeService) GetAuthorAnalytics(ctx context.Context, query inbound.AuthorAnalyticsQuery) (*inbound.AuthorAnalytics, error) {
+	days := query.Days
+	if days <= 0 {
+		days = defaultAnalyticsDays
+	}
+	if days > maxAnalyticsDays {
+		days = maxAnalyticsDays
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, 1-days)
+	stats, err := s.viewAnalytics.AuthorStats(ctx, query.AuthorID, since)
+	if err != nil {
+		return nil, errors.NewDatabaseError("load author analytics", err)
+	}
+
+	analytics := &inbound.AuthorAnalytics{
+		Since:   since.Format("2006-01-02"),
+		Until:   today.Format("2006-01-02"),
+		Daily:   make([]inbound.AuthorDailyStats, days),
+		Recipes: make([]inbound.AuthorRecipeStats, len(stats.Recipes)),
+	}
+	for i := range analytics.Daily {
+		analytics.Daily[i].Date = since.AddDate(0, 0, i).Format("2006-01-02")
+	}
+	byRecipe := make(map[uuid.UUID]*inbound.AuthorRecipeStats, len(stats.Recipes))
+	for i, r := range stats.Recipes {
+		analytics.Recipes[i] = inbound.AuthorRecipeStats{RecipeID: r.ID, Title: r.Title, Rating: r.AverageRating}
+		byRecipe[r.ID] = &analytics.Recipes[i]
+	}
+
+	dayRatings := make([]int, days)
+	recipeRatings := make(map[uuid.UUID]int)
+	totalRatings := 0
+	for _, stat := range stats.Daily {
+		i := int(stat.Day.UTC().Sub(since) / (24 * time.Hour))
+		if i < 0 || i >= days {
+			continue
+		}
+		day := &analytics.Daily[i]
+		day.Views += stat.Views
+		day.Likes += stat.Likes
+		day.Impressions += stat.Impressions
+		day.Ratings += stat.Ratings
+		dayRatings[i] += stat.RatingTotal
+		if r := byRecipe[stat.RecipeID]; r != nil {
+			r.Views += stat.Views
+			r.Likes += stat.Likes
+			r.Impressions += stat.Impressions
+			r.Ratings += stat.Ratings
+			recipeRatings[stat.RecipeID] += stat.RatingTotal
+		}
+		analytics.Views += stat.Views
+		analytics.Likes += stat.Likes
+		analytics.Impressions += stat.Impressions
+		analytics.Ratings += stat.Ratings
+		totalRatings += stat.RatingTotal
+	}
+
+	for i := range analytics.Daily {
+		analytics.Daily[i].AverageRating = averageRating(dayRatings[i], analytics.Daily[i].Ratings)
+	}
+	for i := range analytics.Recipes {
+		r := &analytics.Recipes[i]
+		r.AverageRating = averageRating(recipeRatings[r.RecipeID], r.Ratings)
+	}
+	analytics.AverageRating = averageRating(totalRatings, analytics.Ratings)
+	sort.SliceStable(analytics.Recipes, func(i, j int) bool {
+		return analytics.Recipes[i].Views > analytics.Recipes[j].Views
+	})
+
+	return analytics, nil
+}
+
+// averageRating is total over count to one decimal place, or nil without
+// ratings
+func averageRating(total, count int) *float64 {
+	if count == 0 {
+		return nil
+	}
+	average := math.Round(float64(total)/float64(count)*10) / 10
+	return &average
+}
+
+// dailyViews lists every day of the window, including days without views,
+// so the chart has no gaps
+func dailyViews(stats []outbound.DailyViewStat, since time.Time, days int) []inbound.DailyViews {
+	byDay := make(map[string]outbound.DailyViewStat, len(stats))
+	for _, stat := range stats {
+		byDay[stat.Day.UTC().Format("2006-01-02")] = stat
+	}
+
+	daily := make([]inbound.DailyViews, days)
+	for i := range daily {
+		date := since.AddDate(0, 0, i).Format("2006-01-02")
+		stat := byDay[date]
+		daily[i] = inbound.DailyViews{Date: date, Views: stat.Views, UniqueViewers: stat.UniqueViewers}
+	}
+	return daily
+}
+
+func analyticsCounts(stats []outbound.CountStat) []inbound.AnalyticsCount {
+	counts := make([]inbound.AnalyticsCount, len(stats))
+	for i, stat := range stats {
+		counts[i] = inbound.AnalyticsCount{Key: stat.Key, Count: stat.Count}
+	}
+	return counts
+}
+
+// conversion is the share of viewers who acted, to four decimal places
+func conversion(actions, viewers int) float64 {
+	if viewers == 0 {
+		return 0
+	}
+	return math.Round(float64(actions)/float64(viewers)*10000) / 10000
+}
+
+// referrerHost names where a visit came from, without "www."; empty for
+// direct visits and unparseable referrers
+func referrerHost(referrer string) string {
+	u, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	return truncate(strings.TrimPrefix(strings.ToLower(u.Hostname()), "www."), maxStoredQueryLength)
+}
+
+// referrerSearchTerm reads the search text from a search results referrer
+func referrerSearchTerm(referrer string) string {
+	u, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil {
+		return ""
+	}
+	values := u.Query()
+	for _, key := range searchParams {
+		if term := strings.TrimSpace(values.Get(key)); term != "" {
+			return term
+		}
+	}
+	return ""
+}
+
+func truncate(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max])
+	}
+	return s
+}
@@ -0,0 +1,226 @@
+package recipe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubViewAnalytics struct {
+	views  []outbound.RecipeView
+	stats  outbound.RecipeViewStats
+	since  time.Time
+	recent []uuid.UUID
+	author *outbound.AuthorStats
+}
+
+func (s *stubViewAnalytics) RecordView(ctx context.Context, view outbound.RecipeView) error {
+	s.views = append(s.views, view)
+	return nil
+}
+
+func (s *stubViewAnalytics) RecordEngagement(ctx context.Context, recipeID, viewID uuid.UUID, scrollDepth int, engaged time.Duration) error {
+	return nil
+}
+
+func (s *stubViewAnalytics) RecipeViewStats(ctx context.Context, recipeID uuid.UUID, since time.Time, limit int) (*outbound.RecipeViewStats, error) {
+	s.since = since
+	stats := s.stats
+	return &stats, nil
+}
+
+func (s *stubViewAnalytics) RecentlyViewed(ctx context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	return s.recent, nil
+}
+
+func (s *stubViewAnalytics) AddDailyViews(ctx context.Context, counts []outbound.ViewCount) error {
+	return nil
+}
+
+func (s *stubViewAnalytics) AddDailyImpressions(ctx context.Context, counts []outbound.ViewCount) error {
+	return nil
+}
+
+func (s *stubViewAnalytics) AuthorStats(ctx context.Context, authorID uuid.UUID, since time.Time) (*outbound.AuthorStats, error) {
+	s.since = since
+	return s.author, nil
+}
+
+type stubViewCounter struct {
+	counted     int
+	impressions int
+}
+
+func (s *stubViewCounter) Count(ctx context.Context, recipeID uuid.UUID, userID *uuid.UUID, sessionID string) (bool, error) {
+	s.counted++
+	return true, nil
+}
+
+func (s *stubViewCounter) CountImpressions(ctx context.Context, recipeIDs []uuid.UUID) error {
+	s.impressions += len(recipeIDs)
+	return nil
+}
+
+func (s *stubViewCounter) Flush(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+type stubCounters struct {
+	counts map[string]int
+}
+
+func (s *stubCounters) Increment(ctx context.Context, recipeID uuid.UUID, counter string, delta int) error {
+	s.counts[counter] += delta
+	return nil
+}
+
+func (s *stubCounters) Fold(ctx context.Context, limit int) (int, error) {
+	return 0, nil
+}
+
+func newAnalyticsFixture(t *testing.T) (*RecipeService, *stubViewAnalytics, *user.User, *recipe.Recipe) {
+	t.Helper()
+	now := time.Now()
+	author := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	stranger := user.ReconstructUser(uuid.New(), "bob@example.com", "Bob", "", true, true, user.UserRoleUser, now, now, nil)
+
+	entity, err := recipe.NewRecipe("Lemon Bars", "", author.ID())
+	require.NoError(t, err)
+
+	views := &stubViewAnalytics{}
+	svc := &RecipeService{
+		recipeRepo:    &stubPublishedRecipes{recipes: []*recipe.Recipe{entity}},
+		userRepo:      &stubUsers{users: map[uuid.UUID]*user.User{author.ID(): author, stranger.ID(): stranger}},
+		viewAnalytics: views,
+		counters:      &stubCounters{counts: map[string]int{}},
+		views:         &stubViewCounter{},
+		logger:        zap.NewNop(),
+	}
+	return svc, views, stranger, entity
+}
+
+func TestRecordRecipeViewAttributesReferrer(t *testing.T) {
+	svc, views, _, entity := newAnalyticsFixture(t)
+
+	_, err := svc.RecordRecipeView(context.Background(), inbound.RecordRecipeViewCommand{
+		RecipeID:  entity.ID(),
+		SessionID: "session_1",
+		Referrer:  "https://www.Google.com/search?q=Lemon++Bars&hl=en",
+	})
+	require.NoError(t, err)
+	_, err = svc.RecordRecipeView(context.Background(), inbound.RecordRecipeViewCommand{
+		RecipeID:   entity.ID(),
+		Referrer:   "https://alchemorsel.app/recipes?search=citrus",
+		SearchTerm: "Easy Dessert",
+	})
+	require.NoError(t, err)
+	_, err = svc.RecordRecipeView(context.Background(), inbound.RecordRecipeViewCommand{RecipeID: entity.ID()})
+	require.NoError(t, err)
+
+	require.Len(t, views.views, 3)
+	assert.Equal(t, "google.com", views.views[0].ReferrerHost)
+	assert.Equal(t, "lemon bars", views.views[0].SearchTerm)
+	assert.Equal(t, "alchemorsel.app", views.views[1].ReferrerHost)
+	assert.Equal(t, "easy dessert", views.views[1].SearchTerm, "an explicit term wins over the referrer")
+	assert.Empty(t, views.views[2].ReferrerHost)
+	assert.Equal(t, 3, svc.views.(*stubViewCounter).counted)
+
+	_, err = svc.RecordRecipeView(context.Background(), inbound.RecordRecipeViewCommand{RecipeID: uuid.New()})
+	assert.True(t, errors.Is(err, errors.CodeRecipeNotFound))
+}
+
+func TestRecordRecipeEngagementValidatesDepth(t *testing.T) {
+	svc, _, _, entity := newAnalyticsFixture(t)
+
+	err := svc.RecordRecipeEngagement(context.Background(), inbound.RecordRecipeEngagementCommand{RecipeID: entity.ID(), ViewID: uuid.New(), ScrollDepth: 140})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	err = svc.RecordRecipeEngagement(context.Background(), inbound.RecordRecipeEngagementCommand{RecipeID: entity.ID(), ViewID: uuid.New(), ScrollDepth: 80, EngagedMS: 45000})
+	assert.NoError(t, err)
+}
+
+func TestGetRecipeAnalytics(t *testing.T) {
+	svc, views, stranger, entity := newAnalyticsFixture(t)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	views.stats = outbound.RecipeViewStats{
+		Daily:          []outbound.DailyViewStat{{Day: today, Views: 5, UniqueViewers: 4}},
+		Views:          5,
+		UniqueViewers:  4,
+		Likes:          1,
+		Bookmarks:      3,
+		SearchTerms:    []outbound.CountStat{{Key: "lemon bars", Count: 2}},
+		Referrers:      []outbound.CountStat{{Key: "direct", Count: 3}, {Key: "google.com", Count: 2}},
+		AvgScrollDepth: 62.345,
+		ScrollSamples:  3,
+	}
+
+	analytics, err := svc.GetRecipeAnalytics(context.Background(), inbound.RecipeAnalyticsQuery{
+		RequesterID: entity.AuthorID(),
+		RecipeID:    entity.ID(),
+		Days:        7,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, today.AddDate(0, 0, -6), views.since)
+	require.Len(t, analytics.Daily, 7)
+	assert.Equal(t, 0, analytics.Daily[0].Views)
+	assert.Equal(t, inbound.DailyViews{Date: today.Format("2006-01-02"), Views: 5, UniqueViewers: 4}, analytics.Daily[6])
+	assert.Equal(t, 0.25, analytics.LikeConversion)
+	assert.Equal(t, 0.75, analytics.BookmarkConversion)
+	require.NotNil(t, analytics.AverageScrollDepth)
+	assert.Equal(t, 62.3, *analytics.AverageScrollDepth)
+	assert.Equal(t, []inbound.AnalyticsCount{{Key: "direct", Count: 3}, {Key: "google.com", Count: 2}}, analytics.Referrers)
+
+	_, err = svc.GetRecipeAnalytics(context.Background(), inbound.RecipeAnalyticsQuery{RequesterID: stranger.ID(), RecipeID: entity.ID()})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+}
+
+func TestGetAuthorAnalyticsTotalsPerDayAndRecipe(t *testing.T) {
+	svc, views, _, entity := newAnalyticsFixture(t)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	quiet := uuid.New()
+	views.author = &outbound.AuthorStats{
+		Recipes: []outbound.AuthorRecipe{
+			{ID: quiet, Title: "Anchovy Toast", AverageRating: 3},
+			{ID: entity.ID(), Title: "Lemon Bars", AverageRating: 4.5},
+		},
+		Daily: []outbound.RecipeDayStat{
+			{RecipeID: entity.ID(), Day: today.AddDate(0, 0, -1), Views: 8, Impressions: 30, Ratings: 2, RatingTotal: 9},
+			{RecipeID: quiet, Day: today, Views: 1, Likes: 1, Ratings: 1, RatingTotal: 2},
+			{RecipeID: entity.ID(), Day: today, Views: 4, Likes: 2, Impressions: 10},
+		},
+	}
+
+	analytics, err := svc.GetAuthorAnalytics(context.Background(), inbound.AuthorAnalyticsQuery{AuthorID: entity.AuthorID(), Days: 3})
+	require.NoError(t, err)
+
+	assert.Equal(t, today.AddDate(0, 0, -2), views.since)
+	assert.Equal(t, 13, analytics.Views)
+	assert.Equal(t, 3, analytics.Likes)
+	assert.Equal(t, 40, analytics.Impressions)
+	require.NotNil(t, analytics.AverageRating)
+	assert.Equal(t, 3.7, *analytics.AverageRating)
+
+	require.Len(t, analytics.Daily, 3)
+	assert.Zero(t, analytics.Daily[0].Views)
+	assert.Nil(t, analytics.Daily[0].AverageRating, "days without ratings have no average")
+	assert.Equal(t, 8, analytics.Daily[1].Views)
+	assert.Equal(t, 4.5, *analytics.Daily[1].AverageRating)
+	assert.Equal(t, inbound.AuthorDailyStats{Date: today.Format("2006-01-02"), Views: 5, Likes: 3, Impressions: 10, Ratings: 1, AverageRating: analytics.Daily[2].AverageRating}, analytics.Daily[2])
+
+	require.Len(t, analytics.Recipes, 2)
+	assert.Equal(t, "Lemon Bars", analytics.Recipes[0].Title, "the most viewed recipe comes first")
+	assert.Equal(t, 12, analytics.Recipes[0].Views)
+	assert.Equal(t, 4.5, analytics.Recipes[0].Rating)
+	assert.Equal(t, 1, analytics.Recipes[1].Views)
+}
@@ -0,0 +1,219 @@
+package recipe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/offline"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultChangeLimit = 50
+	maxChangeLimit     = 200
+	// Concurrent saves race for the next revision; the loser retries
+	maxRevisionAttempts = 3
+)
+
+// syncedFields are the RecipeDTO attributes sync clients keep. Counters
+// such as likes and views change too often to be worth a revision.
+var syncedFields = []string{
+	"title", "description", "language", "ingredients", "instructions",
+	"nutrition", "cuisine", "category", "difficulty", "prep_time",
+	"cook_time", "total_time", "servings", "calories", "tags", "images",
+	"status", "published_at",
+}
+
+// listFields are synced as [] rather than null when empty, so loading a
+// recipe with no tags doesn't look like a change
+var listFields = map[string]bool{
+	"ingredients": true, "instructions": true, "tags": true, "images": true,
+}
+
+// GetRecipeChanges returns the change sets after query.Since, oldest first.
+// Drafts are only visible to their author.
+func (s *RecipeService) GetRecipeChanges(ctx context.Context, query inbound.RecipeChangesQuery) (*inbound.RecipeChanges, error) {
+	if query.Since < 0 {
+		return nil, errors.NewBadRequestError("since must not be negative")
+	}
+	entity, err := s.recipeRepo.FindByID(ctx, query.RecipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil || (entity.Status() != recipe.RecipeStatusPublished && entity.AuthorID() != query.RequesterID) {
+		return nil, errors.NewRecipeNotFoundError(query.RecipeID.String())
+	}
+
+	result := &inbound.RecipeChanges{
+		RecipeID: query.RecipeID,
+		Since:    query.Since,
+		Changes:  []inbound.RecipeChangeSet{},
+	}
+	revision, err := s.latestRevision(ctx, query.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+	result.Revision = revision
+	if query.Since > revision {
+		result.Reset = true
+		return result, nil
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultChangeLimit
+	}
+	if limit > maxChangeLimit {
+		limit = maxChangeLimit
+	}
+	if s.changes == nil || query.Since == revision {
+		return result, nil
+	}
+	sets, err := s.changes.FindSince(ctx, query.RecipeID, query.Since, limit+1)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe changes", err)
+	}
+	if len(sets) > limit {
+		sets = sets[:limit]
+		result.HasMore = true
+	}
+	for _, set := range sets {
+		result.Changes = append(result.Changes, inbound.RecipeChangeSet{
+			Revision:  set.Revision,
+			ChangedAt: set.ChangedAt.Format(time.RFC3339),
+			Fields:    set.Fields,
+		})
+	}
+	return result, nil
+}
+
+// latestRevision is 0 until the recipe first changes
+func (s *RecipeService) latestRevision(ctx context.Context, recipeID uuid.UUID) (int64, error) {
+	if s.changes == nil {
+		return 0, nil
+	}
+	latest, err := s.changes.Latest(ctx, recipeID)
+	if err != nil {
+		return 0, errors.NewDatabaseError("find recipe revision", err)
+	}
+	if latest == nil {
+		return 0, nil
+	}
+	return latest.Revision, nil
+}
+
+// recordChanges appends a change set with the synced fields that differ
+// from the last revision. Failures are logged rather than returned since
+// the recipe is already saved; the next change set diffs against the last
+// recorded snapshot, so it picks up whatever this one missed.
+func (s *RecipeService) recordChanges(ctx context.Context, entity *recipe.Recipe) {
+	if s.changes == nil {
+		return
+	}
+	snapshot, err := syncSnapshot(s.entityToDTO(entity))
+	if err != nil {
+		s.logger.Error("Failed to snapshot recipe for sync",
+			zap.String("recipe_id", entity.ID().String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	for attempt := 0; attempt < maxRevisionAttempts; attempt++ {
+		var latest *outbound.RecipeChangeSet
+		latest, err = s.changes.Latest(ctx, entity.ID())
+		if err != nil {
+			break
+		}
+		set := outbound.RecipeChangeSet{
+			RecipeID:  entity.ID(),
+			Revision:  1,
+			Snapshot:  snapshot,
+			ChangedAt: time.Now().UTC(),
+		}
+		var previous map[string]json.RawMessage
+		if latest != nil {
+			set.Revision = latest.Revision + 1
+			previous = latest.Snapshot
+		}
+		set.Fields = changedFields(previous, snapshot)
+		if len(set.Fields) == 0 {
+			return
+		}
+
+		var appended bool
+		appended, err = s.changes.Append(ctx, set)
+		if err != nil {
+			break
+		}
+		if appended {
+			s.syncDraft(ctx, entity, set.Revision)
+			return
+		}
+	}
+	s.logger.Warn("Failed to record recipe changes",
+		zap.String("recipe_id", entity.ID().String()),
+		zap.Error(err),
+	)
+}
+
+// syncDraft points the author's devices at their draft, or leaves a
+// tombstone once it is published or archived so they drop it
+func (s *RecipeService) syncDraft(ctx context.Context, entity *recipe.Recipe, revision int64) {
+	if s.syncFeed == nil {
+		return
+	}
+	key := entity.ID().String()
+	if entity.Status() != recipe.RecipeStatusDraft {
+		s.syncFeed.Remove(ctx, entity.AuthorID(), offline.KindDraft, key)
+		return
+	}
+	s.syncFeed.Touch(ctx, entity.AuthorID(), offline.KindDraft, key, map[string]interface{}{
+		"title":    entity.Title(),
+		"revision": revision,
+	})
+}
+
+// syncSnapshot picks the synced fields out of the recipe's JSON form
+func syncSnapshot(dto *inbound.RecipeDTO) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(dto)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil, err
+	}
+
+	snapshot := make(map[string]json.RawMessage, len(syncedFields))
+	for _, field := range syncedFields {
+		value, ok := all[field]
+		if !ok || string(value) == "null" {
+			// Omitted fields such as published_at are cleared
+			value = json.RawMessage("null")
+			if listFields[field] {
+				value = json.RawMessage("[]")
+			}
+		}
+		snapshot[field] = value
+	}
+	return snapshot, nil
+}
+
+// changedFields returns the fields of after whose values differ from before
+func changedFields(before, after map[string]json.RawMessage) map[string]json.RawMessage {
+	changed := make(map[string]json.RawMessage)
+	for field, value := range after {
+		if previous, ok := before[field]; !ok || !bytes.Equal(previous, value) {
+			changed[field] = value
+		}
+	}
+	return changed
+}
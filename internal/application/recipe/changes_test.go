@@ -0,0 +1,108 @@
+package recipe
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryChanges struct {
+	sets []outbound.RecipeChangeSet
+}
+
+func (m *memoryChanges) Latest(ctx context.Context, recipeID uuid.UUID) (*outbound.RecipeChangeSet, error) {
+	var latest *outbound.RecipeChangeSet
+	for i := range m.sets {
+		if m.sets[i].RecipeID == recipeID {
+			latest = &m.sets[i]
+		}
+	}
+	return latest, nil
+}
+
+func (m *memoryChanges) Append(ctx context.Context, set outbound.RecipeChangeSet) (bool, error) {
+	m.sets = append(m.sets, set)
+	return true, nil
+}
+
+func (m *memoryChanges) FindSince(ctx context.Context, recipeID uuid.UUID, revision int64, limit int) ([]outbound.RecipeChangeSet, error) {
+	var sets []outbound.RecipeChangeSet
+	for _, set := range m.sets {
+		if set.RecipeID == recipeID && set.Revision > revision && len(sets) < limit {
+			sets = append(sets, set)
+		}
+	}
+	return sets, nil
+}
+
+func TestRecipeChangesCarryOnlyChangedFields(t *testing.T) {
+	svc, recipes, _, _, author := newPublishingFixture(t)
+	changes := &memoryChanges{}
+	svc.changes = changes
+	entity := newReadyDraft(t, author.ID())
+	recipes.recipes = append(recipes.recipes, entity)
+	ctx := context.Background()
+	title := "Meyer Lemon Bars"
+
+	_, err := svc.UpdateRecipe(ctx, inbound.UpdateRecipeCommand{RecipeID: entity.ID(), UserID: author.ID(), Title: &title})
+	require.NoError(t, err)
+	require.NoError(t, svc.PublishRecipe(ctx, entity.ID(), author.ID()))
+	_, err = svc.UpdateRecipe(ctx, inbound.UpdateRecipeCommand{RecipeID: entity.ID(), UserID: author.ID(), Title: &title})
+	require.NoError(t, err)
+	require.Len(t, changes.sets, 2, "saving without changes records nothing")
+
+	all, err := svc.GetRecipeChanges(ctx, inbound.RecipeChangesQuery{RecipeID: entity.ID()})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), all.Revision)
+	require.Len(t, all.Changes, 2)
+	assert.JSONEq(t, `"Meyer Lemon Bars"`, string(all.Changes[0].Fields["title"]))
+	assert.Len(t, all.Changes[0].Fields, len(syncedFields), "the first revision carries every field")
+
+	published := all.Changes[1].Fields
+	assert.JSONEq(t, `"published"`, string(published["status"]))
+	assert.Contains(t, published, "published_at")
+	assert.NotContains(t, published, "title")
+
+	page, err := svc.GetRecipeChanges(ctx, inbound.RecipeChangesQuery{RecipeID: entity.ID(), Limit: 1})
+	require.NoError(t, err)
+	assert.True(t, page.HasMore)
+	assert.Equal(t, int64(1), page.Changes[0].Revision)
+
+	current, err := svc.GetRecipeChanges(ctx, inbound.RecipeChangesQuery{RecipeID: entity.ID(), Since: 2})
+	require.NoError(t, err)
+	assert.Empty(t, current.Changes)
+	assert.False(t, current.Reset)
+
+	ahead, err := svc.GetRecipeChanges(ctx, inbound.RecipeChangesQuery{RecipeID: entity.ID(), Since: 9})
+	require.NoError(t, err)
+	assert.True(t, ahead.Reset)
+}
+
+func TestRecipeChangesHideDrafts(t *testing.T) {
+	svc, recipes, _, _, author := newPublishingFixture(t)
+	svc.changes = &memoryChanges{}
+	draft := newReadyDraft(t, author.ID())
+	recipes.recipes = append(recipes.recipes, draft)
+
+	_, err := svc.GetRecipeChanges(context.Background(), inbound.RecipeChangesQuery{RecipeID: draft.ID(), RequesterID: uuid.New()})
+	assert.True(t, errors.Is(err, errors.CodeRecipeNotFound))
+
+	own, err := svc.GetRecipeChanges(context.Background(), inbound.RecipeChangesQuery{RecipeID: draft.ID(), RequesterID: author.ID()})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), own.Revision)
+}
+
+func TestSyncSnapshotTreatsMissingListsAsEmpty(t *testing.T) {
+	snapshot, err := syncSnapshot(&inbound.RecipeDTO{Title: "Toast"})
+	require.NoError(t, err)
+	assert.Equal(t, json.RawMessage("[]"), snapshot["tags"])
+	assert.Equal(t, json.RawMessage("null"), snapshot["published_at"])
+	assert.Empty(t, changedFields(snapshot, snapshot))
+}
@@ -0,0 +1,58 @@
+package recipe
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+)
+
+// searchAfter reads one page of a listing sorted by one of the keyset
+// orders. Total counts every match, and TotalPages is left at zero as the
+// pages are not numbered.
+func (s *RecipeService) searchAfter(ctx context.Context, criteria outbound.SearchCriteria) (*inbound.RecipeList, error) {
+	recipes, next, total, err := s.recipeRepo.SearchAfter(ctx, criteria)
+	if err != nil {
+		return nil, errors.NewDatabaseError("search recipes", err)
+	}
+
+	list := &inbound.RecipeList{
+		Recipes:  make([]inbound.RecipeDTO, len(recipes)),
+		Total:    total,
+		PageSize: criteria.Limit,
+	}
+	for i, r := range recipes {
+		list.Recipes[i] = *s.entityToDTO(r)
+	}
+	if next != nil {
+		list.NextCursor, err = encodeCursor(next)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// encodeCursor makes a cursor opaque to clients, who pass it back as is
+func encodeCursor(cursor *outbound.RecipeCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(token string) (*outbound.RecipeCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.NewBadRequestError("after is not a valid cursor")
+	}
+	var cursor outbound.RecipeCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, errors.NewBadRequestError("after is not a valid cursor")
+	}
+	return &cursor, nil
+}
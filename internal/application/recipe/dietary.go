@@ -0,0 +1,87 @@
+package recipe
+
+import (
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/allergens"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+)
+
+// anyDiet as a diet filter searches without the user's own diets
+const anyDiet = "any"
+
+// searchDietary works out the diet filter of a search. Diets the search
+// names are used as given. Without any, a signed-in user's own diets apply
+// unless they passed diet=any or opted out of personalization; defaulted
+// reports that they did.
+func searchDietary(query inbound.SearchQuery, requester *user.User) (diets []string, defaulted bool) {
+	named := false
+	for _, diet := range query.Dietary {
+		if diet == anyDiet {
+			named = true
+			continue
+		}
+		diets = append(diets, diet)
+	}
+	if named || len(diets) > 0 {
+		return diets, false
+	}
+	if requester == nil || !query.Personalize || !requester.PersonalizationEnabled() {
+		return nil, false
+	}
+	prefs := requester.Preferences()
+	if prefs == nil {
+		return nil, false
+	}
+	for _, restriction := range prefs.DietaryRestrictions {
+		diets = append(diets, normalizeTag(string(restriction)))
+	}
+	return diets, len(diets) > 0
+}
+
+// allergyWarnings names the allergies a recipe contains or may contain,
+// using the recipe's allergen label when it has one. Allergies saved before
+// they were checked against the major allergens are looked for in the
+// ingredient names.
+func allergyWarnings(dto *inbound.RecipeDTO, allergies []string) []string {
+	if len(allergies) == 0 || (dto.Allergens == nil && len(dto.Ingredients) == 0) {
+		return nil
+	}
+
+	found := make(map[allergens.Allergen]bool)
+	if dto.Allergens != nil {
+		for _, name := range append(append([]string{}, dto.Allergens.Contains...), dto.Allergens.MayContain...) {
+			found[allergens.Allergen(name)] = true
+		}
+	} else {
+		ingredients := make([]allergens.Ingredient, len(dto.Ingredients))
+		for i, ing := range dto.Ingredients {
+			ingredients[i] = allergens.Ingredient{Name: ing.Name, Optional: ing.Optional, Notes: ing.Notes}
+		}
+		for _, f := range allergens.Disclose(ingredients).Findings {
+			found[f.Allergen] = true
+		}
+	}
+
+	var warnings []string
+	for _, allergy := range allergies {
+		if a, ok := allergens.Parse(allergy); ok {
+			if found[a] {
+				warnings = append(warnings, string(a))
+			}
+			continue
+		}
+		term := strings.ToLower(strings.TrimSpace(allergy))
+		if term == "" {
+			continue
+		}
+		for _, ing := range dto.Ingredients {
+			if strings.Contains(strings.ToLower(ing.Name), term) {
+				warnings = append(warnings, term)
+				break
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,51 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchDietaryDefaultsToTheUsersDiets(t *testing.T) {
+	requester, err := user.NewUser("cook@example.com", "Cook", "password123")
+	require.NoError(t, err)
+	requester.SetDietaryProfile([]user.DietaryRestriction{user.DietaryRestrictionGlutenFree}, nil, nil)
+
+	diets, defaulted := searchDietary(inbound.SearchQuery{Personalize: true}, requester)
+	assert.Equal(t, []string{"gluten_free"}, diets)
+	assert.True(t, defaulted)
+
+	diets, defaulted = searchDietary(inbound.SearchQuery{Personalize: true, Dietary: []string{"vegan"}}, requester)
+	assert.Equal(t, []string{"vegan"}, diets)
+	assert.False(t, defaulted)
+
+	diets, _ = searchDietary(inbound.SearchQuery{Personalize: true, Dietary: []string{anyDiet}}, requester)
+	assert.Empty(t, diets)
+
+	requester.SetPersonalization(false)
+	diets, _ = searchDietary(inbound.SearchQuery{Personalize: true}, requester)
+	assert.Empty(t, diets)
+
+	diets, _ = searchDietary(inbound.SearchQuery{Personalize: true}, nil)
+	assert.Empty(t, diets)
+}
+
+func TestAllergyWarnings(t *testing.T) {
+	satay := &inbound.RecipeDTO{Ingredients: []inbound.IngredientDTO{
+		{Name: "chicken thighs"},
+		{Name: "peanut butter"},
+		{Name: "toasted sesame seeds", Optional: true},
+		{Name: "fresh coriander"},
+	}}
+
+	assert.Equal(t, []string{"peanuts", "sesame", "coriander"},
+		allergyWarnings(satay, []string{"peanuts", "milk", "sesame", "Coriander"}))
+	assert.Empty(t, allergyWarnings(satay, nil))
+
+	// List reads carry the stored label rather than the ingredients
+	labelled := &inbound.RecipeDTO{Allergens: &inbound.AllergenLabelDTO{Contains: []string{"milk"}, MayContain: []string{"tree-nuts"}}}
+	assert.Equal(t, []string{"milk", "tree-nuts"}, allergyWarnings(labelled, []string{"milk", "tree-nuts", "eggs"}))
+}
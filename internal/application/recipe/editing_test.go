@@ -0,0 +1,93 @@
+package recipe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubDeletableRecipes struct {
+	*stubScheduledRecipes
+	deleted []uuid.UUID
+}
+
+func (s *stubDeletableRecipes) Delete(ctx context.Context, id uuid.UUID) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+func TestUpdateRecipeReplacesListsForAuthorOrAdmin(t *testing.T) {
+	svc, recipes, _, _, author := newPublishingFixture(t)
+	now := time.Now()
+	admin := user.ReconstructUser(uuid.New(), "grace@example.com", "Grace", "", true, true, user.UserRoleAdmin, now, now, nil)
+	stranger := user.ReconstructUser(uuid.New(), "eve@example.com", "Eve", "", true, true, user.UserRoleUser, now, now, nil)
+	users := svc.userRepo.(*stubUsers)
+	users.users[admin.ID()] = admin
+	users.users[stranger.ID()] = stranger
+	entity := newReadyDraft(t, author.ID())
+	recipes.recipes = append(recipes.recipes, entity)
+	ctx := context.Background()
+
+	title := "Grandma's Lemon Bars"
+	difficulty := recipe.DifficultyLevelEasy
+	tags := []string{"baking", "Baking", "citrus"}
+	dto, err := svc.UpdateRecipe(ctx, inbound.UpdateRecipeCommand{
+		RecipeID:     entity.ID(),
+		UserID:       author.ID(),
+		Title:        &title,
+		Difficulty:   &difficulty,
+		Tags:         &tags,
+		Ingredients:  &[]inbound.CreateIngredientCommand{{Name: "lemons", Amount: 4}, {Name: "butter", Amount: 200, Unit: recipe.MeasurementUnitGram}},
+		Instructions: &[]inbound.CreateInstructionCommand{{Description: "Make the crust."}, {Description: "Bake.", Duration: 25}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, title, dto.Title)
+	assert.Equal(t, []string{"baking", "citrus"}, entity.Tags())
+	require.Len(t, entity.Ingredients(), 2, "ingredients are replaced, not appended")
+	require.Len(t, entity.Instructions(), 2)
+	assert.Equal(t, 2, entity.Instructions()[1].StepNumber)
+	assert.Equal(t, 25*time.Minute, entity.Instructions()[1].Duration)
+
+	bad := recipe.DifficultyLevel("impossible")
+	_, err = svc.UpdateRecipe(ctx, inbound.UpdateRecipeCommand{RecipeID: entity.ID(), UserID: author.ID(), Difficulty: &bad})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	empty := []inbound.CreateIngredientCommand{{Name: ""}}
+	_, err = svc.UpdateRecipe(ctx, inbound.UpdateRecipeCommand{RecipeID: entity.ID(), UserID: author.ID(), Ingredients: &empty})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	assert.Len(t, entity.Ingredients(), 2, "a rejected list leaves the old one")
+
+	_, err = svc.UpdateRecipe(ctx, inbound.UpdateRecipeCommand{RecipeID: entity.ID(), UserID: stranger.ID(), Title: &title})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+
+	renamed := "Lemon Squares"
+	_, err = svc.UpdateRecipe(ctx, inbound.UpdateRecipeCommand{RecipeID: entity.ID(), UserID: admin.ID(), Title: &renamed})
+	require.NoError(t, err)
+	assert.Equal(t, renamed, entity.Title())
+}
+
+func TestDeleteRecipeAllowsAuthorOrAdmin(t *testing.T) {
+	svc, scheduled, _, _, author := newPublishingFixture(t)
+	now := time.Now()
+	admin := user.ReconstructUser(uuid.New(), "grace@example.com", "Grace", "", true, true, user.UserRoleAdmin, now, now, nil)
+	svc.userRepo.(*stubUsers).users[admin.ID()] = admin
+	recipes := &stubDeletableRecipes{stubScheduledRecipes: scheduled}
+	svc.recipeRepo = recipes
+	mine := newReadyDraft(t, author.ID())
+	theirs := newReadyDraft(t, uuid.New())
+	scheduled.recipes = []*recipe.Recipe{mine, theirs}
+	ctx := context.Background()
+
+	err := svc.DeleteRecipe(ctx, theirs.ID(), author.ID())
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	require.NoError(t, svc.DeleteRecipe(ctx, mine.ID(), author.ID()))
+	require.NoError(t, svc.DeleteRecipe(ctx, theirs.ID(), admin.ID()))
+	assert.Equal(t, []uuid.UUID{mine.ID(), theirs.ID()}, recipes.deleted)
+}
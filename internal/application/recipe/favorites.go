@@ -0,0 +1,106 @@
+package recipe
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// favoritedToken is the search operator for saved recipes
+var favoritedToken = regexp.MustCompile(`(?i)(?:^|\s)favorited:(true|false)(?:\s|$)`)
+
+// favoritedFilter strips favorited:true or favorited:false from search
+// text, reporting whether saved recipes were asked for
+func favoritedFilter(text string) (string, bool) {
+	match := favoritedToken.FindStringSubmatch(text)
+	if match == nil {
+		return text, false
+	}
+	rest := strings.Join(strings.Fields(favoritedToken.ReplaceAllString(text, " ")), " ")
+	return rest, strings.EqualFold(match[1], "true")
+}
+
+// FavoriteRecipe saves a recipe for the user. Saving again is a no-op.
+func (s *RecipeService) FavoriteRecipe(ctx context.Context, recipeID, userID uuid.UUID) (*inbound.FavoriteStatus, error) {
+	if err := s.requireRecipe(ctx, recipeID); err != nil {
+		return nil, err
+	}
+	added, err := s.favorites.Add(ctx, userID, recipeID, time.Now().UTC())
+	if err != nil {
+		return nil, errors.NewDatabaseError("save favorite", err)
+	}
+	if added {
+		s.logger.Info("Recipe favorited",
+			zap.String("recipe_id", recipeID.String()),
+			zap.String("user_id", userID.String()),
+		)
+	}
+	return s.favoriteStatus(ctx, recipeID, userID, true)
+}
+
+// UnfavoriteRecipe removes a recipe from the user's saved recipes
+func (s *RecipeService) UnfavoriteRecipe(ctx context.Context, recipeID, userID uuid.UUID) (*inbound.FavoriteStatus, error) {
+	if _, err := s.favorites.Remove(ctx, userID, recipeID); err != nil {
+		return nil, errors.NewDatabaseError("remove favorite", err)
+	}
+	return s.favoriteStatus(ctx, recipeID, userID, false)
+}
+
+// GetFavoriteRecipes lists the published recipes the user saved, most
+// recently saved first
+func (s *RecipeService) GetFavoriteRecipes(ctx context.Context, userID uuid.UUID, params inbound.PaginationParams) (*inbound.RecipeList, error) {
+	if params.PageSize <= 0 {
+		params.PageSize = 20
+	}
+	if params.OrderBy == "" {
+		params.OrderBy = "saved"
+	}
+	recipes, total, err := s.recipeRepo.Search(ctx, outbound.SearchCriteria{
+		FavoritedBy: &userID,
+		Offset:      params.Page * params.PageSize,
+		Limit:       params.PageSize,
+		OrderBy:     params.OrderBy,
+		OrderDir:    params.Order,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("list favorites", err)
+	}
+
+	list := &inbound.RecipeList{
+		Recipes:    make([]inbound.RecipeDTO, len(recipes)),
+		Total:      total,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+		TotalPages: (total + params.PageSize - 1) / params.PageSize,
+	}
+	for i, r := range recipes {
+		list.Recipes[i] = *s.entityToDTO(r)
+	}
+	return list, nil
+}
+
+func (s *RecipeService) requireRecipe(ctx context.Context, recipeID uuid.UUID) error {
+	entity, err := s.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil {
+		return errors.NewRecipeNotFoundError(recipeID.String())
+	}
+	return nil
+}
+
+func (s *RecipeService) favoriteStatus(ctx context.Context, recipeID, userID uuid.UUID, favorited bool) (*inbound.FavoriteStatus, error) {
+	count, err := s.favorites.Count(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("count favorites", err)
+	}
+	return &inbound.FavoriteStatus{RecipeID: recipeID, Favorited: favorited, Favorites: count}, nil
+}
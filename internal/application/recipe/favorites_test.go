@@ -0,0 +1,39 @@
+package recipe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFavoritedFilter(t *testing.T) {
+	cases := []struct {
+		text      string
+		rest      string
+		favorited bool
+	}{
+		{"pasta", "pasta", false},
+		{"favorited:true", "", true},
+		{"quick  favorited:TRUE pasta", "quick pasta", true},
+		{"pasta favorited:false", "pasta", false},
+		{"notfavorited:true", "notfavorited:true", false},
+	}
+	for _, tc := range cases {
+		rest, favorited := favoritedFilter(tc.text)
+		assert.Equal(t, tc.rest, rest, tc.text)
+		assert.Equal(t, tc.favorited, favorited, tc.text)
+	}
+}
+
+func TestSearchFavoritesRequiresUser(t *testing.T) {
+	svc := &RecipeService{logger: zap.NewNop()}
+
+	_, err := svc.SearchRecipes(context.Background(), inbound.SearchQuery{Text: "favorited:true soup"})
+	require.Error(t, err)
+	_, err = svc.SearchRecipes(context.Background(), inbound.SearchQuery{Favorited: true})
+	require.Error(t, err)
+}
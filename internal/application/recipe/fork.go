@@ -0,0 +1,87 @@
+package recipe
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ForkRecipe copies a published recipe into a draft owned by the user,
+// linked back to the original, and counts the fork on the original
+func (s *RecipeService) ForkRecipe(ctx context.Context, recipeID, userID uuid.UUID) (*inbound.RecipeDTO, error) {
+	original, err := s.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if original == nil {
+		return nil, errors.NewRecipeNotFoundError(recipeID.String())
+	}
+
+	fork, err := original.Fork(userID)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	if err := s.recipeRepo.Create(ctx, fork); err != nil {
+		return nil, errors.NewDatabaseError("create recipe", err)
+	}
+	s.syncDraft(ctx, fork, 0)
+
+	// The fork is saved either way; a lost count only understates it
+	if err := s.counters.Increment(ctx, recipeID, outbound.RecipeCounterForks, 1); err != nil {
+		s.logger.Warn("Failed to count recipe fork",
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+	}
+	s.invalidateRecipeCache(recipeID)
+
+	for _, event := range fork.Events() {
+		if err := s.publishEvent(ctx, event); err != nil {
+			s.logger.Error("Failed to publish event",
+				zap.String("event", event.EventName()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	s.logger.Info("Recipe forked",
+		zap.String("recipe_id", fork.ID().String()),
+		zap.String("forked_from_id", recipeID.String()),
+		zap.String("user_id", userID.String()),
+	)
+
+	dto := s.entityToDTO(fork)
+	dto.ForkedFrom = s.recipeOrigin(ctx, recipeID)
+	return dto, nil
+}
+
+// recipeOrigin describes the recipe a fork was copied from, or returns nil
+// when it has been deleted or cannot be read. The repository reports a
+// deleted recipe as an error too, so this logs at debug level.
+func (s *RecipeService) recipeOrigin(ctx context.Context, recipeID uuid.UUID) *inbound.RecipeOrigin {
+	original, err := s.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		s.logger.Debug("Forked recipe origin unavailable",
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+		return nil
+	}
+	if original == nil {
+		return nil
+	}
+
+	origin := &inbound.RecipeOrigin{
+		ID:       original.ID(),
+		Title:    original.Title(),
+		AuthorID: original.AuthorID(),
+	}
+	if author, err := s.userRepo.FindByID(ctx, original.AuthorID()); err == nil && author != nil {
+		origin.AuthorName = author.Name()
+	}
+	return origin
+}
@@ -0,0 +1,62 @@
+package recipe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubForkedRecipes struct {
+	stubPublishedRecipes
+}
+
+func (s *stubForkedRecipes) Create(ctx context.Context, r *recipe.Recipe) error {
+	s.recipes = append(s.recipes, r)
+	return nil
+}
+
+func TestForkRecipeCopiesAPublishedRecipeIntoADraft(t *testing.T) {
+	now := time.Now()
+	author := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	cook := user.ReconstructUser(uuid.New(), "bo@example.com", "Bo", "", true, true, user.UserRoleUser, now, now, nil)
+	original := newReadyDraft(t, author.ID())
+	draft := newReadyDraft(t, author.ID())
+	require.NoError(t, original.Publish())
+
+	recipes := &stubForkedRecipes{stubPublishedRecipes{recipes: []*recipe.Recipe{original, draft}}}
+	counters := &stubCounters{counts: map[string]int{}}
+	svc := &RecipeService{
+		recipeRepo: recipes,
+		userRepo:   &stubUsers{users: map[uuid.UUID]*user.User{author.ID(): author, cook.ID(): cook}},
+		cache:      stubCache{},
+		counters:   counters,
+		queries:    newQueryCache(),
+		logger:     zap.NewNop(),
+	}
+
+	_, err := svc.ForkRecipe(context.Background(), draft.ID(), cook.ID())
+	assert.True(t, errors.Is(err, errors.CodeBadRequest), "drafts cannot be forked")
+
+	fork, err := svc.ForkRecipe(context.Background(), original.ID(), cook.ID())
+	require.NoError(t, err)
+	assert.NotEqual(t, original.ID(), fork.ID)
+	assert.Equal(t, cook.ID(), fork.AuthorID)
+	assert.Equal(t, recipe.RecipeStatusDraft, fork.Status)
+	assert.Equal(t, "Lemon Bars", fork.Title)
+	require.Len(t, fork.Ingredients, 1)
+	assert.NotEqual(t, original.Ingredients()[0].ID, fork.Ingredients[0].ID)
+	assert.Equal(t, original.ID(), *fork.ForkedFromID)
+	require.NotNil(t, fork.ForkedFrom)
+	assert.Equal(t, "Ada", fork.ForkedFrom.AuthorName)
+	assert.Equal(t, 1, counters.counts[outbound.RecipeCounterForks])
+	assert.Len(t, recipes.recipes, 3)
+}
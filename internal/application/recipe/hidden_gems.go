@@ -0,0 +1,68 @@
+// Package recipe provides the hidden gem slots in recommendations
+package recipe
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// gemSlotEvery gives every fifth recommendation to a hidden gem, so well
+// rated recipes few people have seen get a turn next to the popular ones
+const gemSlotEvery = 5
+
+// slotHiddenGems puts the current hidden gems into every gemSlotEvery-th
+// position of list, skipping gems already listed and the user's own
+// recipes. The list keeps its length; gems displace the recipes in their
+// slots. Recommendations still work without gems, so failures are only
+// logged.
+func (s *RecipeService) slotHiddenGems(ctx context.Context, userID uuid.UUID, list *inbound.RecipeList) {
+	slots := len(list.Recipes) / gemSlotEvery
+	if s.popularity == nil || slots == 0 {
+		return
+	}
+
+	gems, err := s.popularity.HiddenGems(ctx, 2*slots)
+	if err != nil {
+		s.logger.Warn("Failed to list hidden gems", zap.Error(err))
+		return
+	}
+	listed := make(map[uuid.UUID]bool, len(list.Recipes))
+	for _, dto := range list.Recipes {
+		listed[dto.ID] = true
+	}
+	ids := make([]uuid.UUID, 0, len(gems))
+	for _, gem := range gems {
+		if !listed[gem.RecipeID] {
+			ids = append(ids, gem.RecipeID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	recipes, err := s.recipeRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		s.logger.Warn("Failed to load hidden gems", zap.Error(err))
+		return
+	}
+	byID := make(map[uuid.UUID]*inbound.RecipeDTO, len(recipes))
+	for _, r := range recipes {
+		if r.AuthorID() != userID {
+			byID[r.ID()] = s.entityToDTO(r)
+		}
+	}
+
+	slot := gemSlotEvery - 1
+	for _, id := range ids {
+		if slot >= len(list.Recipes) {
+			return
+		}
+		if dto, ok := byID[id]; ok {
+			list.Recipes[slot] = *dto
+			slot += gemSlotEvery
+		}
+	}
+}
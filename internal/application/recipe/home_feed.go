@@ -0,0 +1,101 @@
+// Package recipe provides the personal recipe lists of the home page
+package recipe
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultHomeFeedLimit is the recipes listed when no limit is asked for
+	defaultHomeFeedLimit = 12
+	// maxHomeFeedLimit bounds a home page list
+	maxHomeFeedLimit = 50
+)
+
+// GetRecentlyViewedRecipes lists the recipes a user opened, most recent
+// first, so they can pick up where they left off. Recipes that were
+// deleted or unpublished since are skipped unless the user wrote them.
+func (s *RecipeService) GetRecentlyViewedRecipes(ctx context.Context, userID uuid.UUID, limit int) ([]inbound.RecipeDTO, error) {
+	ids, err := s.viewAnalytics.RecentlyViewed(ctx, userID, homeFeedLimit(limit))
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recently viewed recipes", err)
+	}
+	if len(ids) == 0 {
+		return []inbound.RecipeDTO{}, nil
+	}
+
+	recipes, err := s.recipeRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipes by ids", err)
+	}
+	byID := make(map[uuid.UUID]*recipe.Recipe, len(recipes))
+	for _, r := range recipes {
+		byID[r.ID()] = r
+	}
+
+	dtos := make([]inbound.RecipeDTO, 0, len(ids))
+	for _, id := range ids {
+		r, ok := byID[id]
+		if !ok || (r.Status() != recipe.RecipeStatusPublished && r.AuthorID() != userID) {
+			continue
+		}
+		dtos = append(dtos, *s.entityToDTO(r))
+	}
+	return dtos, nil
+}
+
+// GetChefActivity lists the newest recipes by the authors whose recipes the
+// user liked, leaving out the user's own recipes and ones they already liked
+func (s *RecipeService) GetChefActivity(ctx context.Context, userID uuid.UUID, limit int) ([]inbound.RecipeDTO, error) {
+	liked, err := s.recipeRepo.FindLikedByUser(ctx, userID, historyLimit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find liked recipes", err)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(liked))
+	chefs := make(map[uuid.UUID]bool)
+	var authorIDs []uuid.UUID
+	for _, r := range liked {
+		seen[r.ID()] = true
+		if author := r.AuthorID(); author != userID && !chefs[author] {
+			chefs[author] = true
+			authorIDs = append(authorIDs, author)
+		}
+	}
+	if len(authorIDs) == 0 {
+		return []inbound.RecipeDTO{}, nil
+	}
+
+	limit = homeFeedLimit(limit)
+	recipes, err := s.recipeRepo.FindLatestByAuthors(ctx, authorIDs, limit+len(seen))
+	if err != nil {
+		return nil, errors.NewDatabaseError("find chef recipes", err)
+	}
+
+	dtos := make([]inbound.RecipeDTO, 0, limit)
+	for _, r := range recipes {
+		if seen[r.ID()] {
+			continue
+		}
+		dtos = append(dtos, *s.entityToDTO(r))
+		if len(dtos) == limit {
+			break
+		}
+	}
+	return dtos, nil
+}
+
+func homeFeedLimit(limit int) int {
+	if limit <= 0 {
+		return defaultHomeFeedLimit
+	}
+	if limit > maxHomeFeedLimit {
+		return maxHomeFeedLimit
+	}
+	return limit
+}
@@ -0,0 +1,95 @@
+package recipe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubHomeFeedRecipes struct {
+	stubPublishedRecipes
+	liked []*recipe.Recipe
+}
+
+func (s *stubHomeFeedRecipes) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*recipe.Recipe, error) {
+	var found []*recipe.Recipe
+	for _, id := range ids {
+		if r, _ := s.FindByID(ctx, id); r != nil {
+			found = append(found, r)
+		}
+	}
+	return found, nil
+}
+
+func (s *stubHomeFeedRecipes) FindLikedByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*recipe.Recipe, error) {
+	return s.liked, nil
+}
+
+func (s *stubHomeFeedRecipes) FindLatestByAuthors(ctx context.Context, authorIDs []uuid.UUID, limit int) ([]*recipe.Recipe, error) {
+	var latest []*recipe.Recipe
+	for _, r := range s.recipes {
+		for _, author := range authorIDs {
+			if r.AuthorID() == author && r.Status() == recipe.RecipeStatusPublished {
+				latest = append(latest, r)
+			}
+		}
+	}
+	return latest, nil
+}
+
+func publishedRecipe(t *testing.T, title string, authorID uuid.UUID) *recipe.Recipe {
+	t.Helper()
+	r, err := recipe.NewRecipe(title, "", authorID)
+	require.NoError(t, err)
+	require.NoError(t, r.AddIngredient(recipe.Ingredient{Name: "lemons", Amount: 3, Unit: recipe.MeasurementUnitPiece}))
+	require.NoError(t, r.AddInstruction(recipe.Instruction{Description: "Bake."}))
+	require.NoError(t, r.SetServings(4))
+	require.NoError(t, r.Publish())
+	return r
+}
+
+func recipeTitles(dtos []inbound.RecipeDTO) []string {
+	titles := make([]string, len(dtos))
+	for i, dto := range dtos {
+		titles[i] = dto.Title
+	}
+	return titles
+}
+
+func TestHomeFeedLists(t *testing.T) {
+	ctx := context.Background()
+	viewer, chef := uuid.New(), uuid.New()
+
+	liked := publishedRecipe(t, "Lemon Bars", chef)
+	fresh := publishedRecipe(t, "Lemon Tart", chef)
+	own := publishedRecipe(t, "Own Curd", viewer)
+	ownDraft, err := recipe.NewRecipe("Own Draft", "", viewer)
+	require.NoError(t, err)
+	strangerDraft, err := recipe.NewRecipe("Secret Draft", "", uuid.New())
+	require.NoError(t, err)
+
+	repo := &stubHomeFeedRecipes{
+		stubPublishedRecipes: stubPublishedRecipes{recipes: []*recipe.Recipe{liked, fresh, own, ownDraft, strangerDraft}},
+		liked:                []*recipe.Recipe{liked, own},
+	}
+	views := &stubViewAnalytics{recent: []uuid.UUID{strangerDraft.ID(), ownDraft.ID(), uuid.New(), fresh.ID()}}
+	svc := &RecipeService{recipeRepo: repo, viewAnalytics: views, logger: zap.NewNop()}
+
+	recent, err := svc.GetRecentlyViewedRecipes(ctx, viewer, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Own Draft", "Lemon Tart"}, recipeTitles(recent), "keeps view order and skips recipes the viewer can no longer open")
+
+	activity, err := svc.GetChefActivity(ctx, viewer, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Lemon Tart"}, recipeTitles(activity), "leaves out liked and own recipes")
+
+	activity, err = svc.GetChefActivity(ctx, chef, 0)
+	require.NoError(t, err)
+	assert.Empty(t, activity)
+}
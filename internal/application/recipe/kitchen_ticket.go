@@ -0,0 +1,120 @@
+// Package recipe provides kitchen tickets for professional kitchens
+package recipe
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ticket"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+)
+
+// GetKitchenTicket scales a recipe to the batch and splits it by station.
+// Kitchen tickets are for chef accounts and admins.
+func (s *RecipeService) GetKitchenTicket(ctx context.Context, query inbound.KitchenTicketQuery) (*inbound.KitchenTicket, error) {
+	t, err := s.kitchenTicket(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &inbound.KitchenTicket{
+		RecipeID:  t.RecipeID,
+		Title:     t.Title,
+		Portions:  t.Portions,
+		Scale:     math.Round(t.Scale*1000) / 1000,
+		Unweighed: t.Unweighed,
+		Stations:  make([]inbound.KitchenTicketStation, len(t.Sections)),
+	}
+	for i, section := range t.Sections {
+		station := inbound.KitchenTicketStation{
+			Station:     string(section.Station),
+			Ingredients: make([]inbound.KitchenTicketLine, len(section.Ingredients)),
+			Steps:       make([]inbound.KitchenTicketStep, len(section.Steps)),
+		}
+		for j, line := range section.Ingredients {
+			station.Ingredients[j] = inbound.KitchenTicketLine{
+				Name:     line.Name,
+				Grams:    line.Grams,
+				Weighed:  line.Weighed,
+				Measure:  line.Measure,
+				Optional: line.Optional,
+			}
+		}
+		for j, step := range section.Steps {
+			dto := inbound.KitchenTicketStep{
+				Number:          step.Number,
+				Text:            step.Text,
+				DurationMinutes: int(math.Ceil(step.Duration.Minutes())),
+			}
+			if step.Temperature != nil {
+				celsius := math.Round(step.Temperature.ToCelsius())
+				dto.TemperatureC = &celsius
+			}
+			station.Steps[j] = dto
+		}
+		result.Stations[i] = station
+	}
+	return result, nil
+}
+
+// PrintKitchenTicket renders the kitchen ticket as a PDF or an ESC/POS job
+// for thermal printers
+func (s *RecipeService) PrintKitchenTicket(ctx context.Context, query inbound.KitchenTicketQuery) (*inbound.PrintedKitchenTicket, error) {
+	format := strings.ToLower(query.Format)
+	if format != inbound.KitchenTicketFormatPDF && format != inbound.KitchenTicketFormatESCPOS {
+		return nil, errors.NewBadRequestError("format must be pdf or escpos")
+	}
+	if query.Columns != 0 && (query.Columns < ticket.MinColumns || query.Columns > ticket.MaxColumns) {
+		return nil, errors.NewBadRequestError("columns must be between 32 and 64")
+	}
+	t, err := s.kitchenTicket(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "kitchen-ticket-" + t.RecipeID.String()
+	if format == inbound.KitchenTicketFormatPDF {
+		return &inbound.PrintedKitchenTicket{
+			FileName:    name + ".pdf",
+			ContentType: "application/pdf",
+			Content:     ticket.PDF(t, query.Columns),
+		}, nil
+	}
+	return &inbound.PrintedKitchenTicket{
+		FileName:    name + ".bin",
+		ContentType: "application/vnd.escpos",
+		Content:     ticket.ESCPOS(t, query.Columns),
+	}, nil
+}
+
+// kitchenTicket checks the requester may print tickets and builds one for
+// a recipe they can see: published, or their own draft
+func (s *RecipeService) kitchenTicket(ctx context.Context, query inbound.KitchenTicketQuery) (ticket.Ticket, error) {
+	if query.Portions < 0 || query.Portions > ticket.MaxPortions {
+		return ticket.Ticket{}, errors.NewBadRequestError("portions must be between 1 and 1000")
+	}
+
+	requester, err := s.userRepo.FindByID(ctx, query.RequesterID)
+	if err != nil {
+		return ticket.Ticket{}, errors.NewDatabaseError("find user", err)
+	}
+	if requester == nil {
+		return ticket.Ticket{}, errors.NewUserNotFoundError(query.RequesterID.String())
+	}
+	if requester.Role() != user.UserRoleChef && requester.Role() != user.UserRoleAdmin {
+		return ticket.Ticket{}, errors.NewInsufficientPermissionsError("print kitchen tickets")
+	}
+
+	entity, err := s.recipeRepo.FindByID(ctx, query.RecipeID)
+	if err != nil {
+		return ticket.Ticket{}, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil || (entity.Status() != recipe.RecipeStatusPublished && entity.AuthorID() != requester.ID()) {
+		return ticket.Ticket{}, errors.NewRecipeNotFoundError(query.RecipeID.String())
+	}
+	return ticket.Build(entity, query.Portions), nil
+}
@@ -0,0 +1,65 @@
+package recipe
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestKitchenTicketForChefs(t *testing.T) {
+	now := time.Now()
+	chef := user.ReconstructUser(uuid.New(), "chef@example.com", "Chef", "", true, true, user.UserRoleChef, now, now, nil)
+	cook := user.ReconstructUser(uuid.New(), "cook@example.com", "Cook", "", true, true, user.UserRoleUser, now, now, nil)
+
+	stew, err := recipe.NewRecipe("Beef Stew", "", chef.ID())
+	require.NoError(t, err)
+	require.NoError(t, stew.SetServings(6))
+	require.NoError(t, stew.AddIngredient(recipe.Ingredient{Name: "beef chuck", Amount: 900, Unit: recipe.MeasurementUnitGram}))
+	require.NoError(t, stew.AddInstruction(recipe.Instruction{
+		Description: "Braise the beef in the oven.",
+		Duration:    150 * time.Minute,
+		Temperature: &recipe.Temperature{Value: 325, Unit: recipe.TemperatureUnitFahrenheit},
+	}))
+
+	svc := &RecipeService{
+		recipeRepo: &stubPublishedRecipes{recipes: []*recipe.Recipe{stew}},
+		userRepo:   &stubUsers{users: map[uuid.UUID]*user.User{chef.ID(): chef, cook.ID(): cook}},
+		logger:     zap.NewNop(),
+	}
+	ctx := context.Background()
+	query := inbound.KitchenTicketQuery{RequesterID: chef.ID(), RecipeID: stew.ID(), Portions: 40}
+
+	ticket, err := svc.GetKitchenTicket(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, 6.667, ticket.Scale)
+	require.Len(t, ticket.Stations, 1)
+	assert.Equal(t, "oven", ticket.Stations[0].Station)
+	assert.Equal(t, 6000.0, ticket.Stations[0].Ingredients[0].Grams)
+	step := ticket.Stations[0].Steps[0]
+	assert.Equal(t, 150, step.DurationMinutes)
+	require.NotNil(t, step.TemperatureC)
+	assert.Equal(t, 163.0, *step.TemperatureC)
+
+	query.Format = "PDF"
+	printed, err := svc.PrintKitchenTicket(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", printed.ContentType)
+	assert.True(t, bytes.HasPrefix(printed.Content, []byte("%PDF")))
+
+	query.Format = "docx"
+	_, err = svc.PrintKitchenTicket(ctx, query)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	_, err = svc.GetKitchenTicket(ctx, inbound.KitchenTicketQuery{RequesterID: cook.ID(), RecipeID: stew.ID()})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+}
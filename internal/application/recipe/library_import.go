@@ -0,0 +1,265 @@
+// Package recipe provides bulk import of recipe manager exports
+package recipe
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+	"github.com/alchemorsel/v3/internal/domain/recipe/instructions"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxLibraryRecipes bounds one import request
+	maxLibraryRecipes = 2000
+	// libraryPageSize is the page used to load the caller's existing titles
+	libraryPageSize = 200
+	maxTitleLength  = 200
+)
+
+// Statuses reported in inbound.LibraryImportItem
+const (
+	LibraryItemCreated   = "created"
+	LibraryItemDuplicate = "duplicate"
+	LibraryItemFailed    = "failed"
+)
+
+// ImportRecipeLibrary saves recipes from another recipe manager as drafts.
+// A recipe whose normalized title matches one the user already has, or one
+// earlier in the same export, is reported as a duplicate and skipped unless
+// KeepDuplicates is set. One bad recipe never fails the whole import.
+func (s *RecipeService) ImportRecipeLibrary(ctx context.Context, cmd inbound.ImportLibraryCommand) (*inbound.LibraryImportResult, error) {
+	if len(cmd.Recipes) == 0 {
+		return nil, errors.NewBadRequestError("The export contains no recipes")
+	}
+	if len(cmd.Recipes) > maxLibraryRecipes {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Import at most %d recipes at a time", maxLibraryRecipes))
+	}
+
+	exists, err := s.userRepo.Exists(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("check user existence", err)
+	}
+	if !exists {
+		return nil, errors.NewUserNotFoundError(cmd.UserID.String())
+	}
+
+	known, err := s.existingTitles(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find user recipes", err)
+	}
+
+	result := &inbound.LibraryImportResult{
+		Source: cmd.Source,
+		Items:  make([]inbound.LibraryImportItem, 0, len(cmd.Recipes)),
+	}
+
+	for _, imported := range cmd.Recipes {
+		if ctx.Err() != nil {
+			s.logger.Warn("Library import interrupted", zap.Int("processed", len(result.Items)), zap.Error(ctx.Err()))
+			break
+		}
+
+		item := inbound.LibraryImportItem{Title: strings.TrimSpace(imported.Title)}
+		key := titleKey(item.Title)
+
+		if id, ok := known[key]; ok && !cmd.KeepDuplicates {
+			item.Status = LibraryItemDuplicate
+			item.DuplicateOf = &id
+			result.Duplicates++
+			result.Items = append(result.Items, item)
+			continue
+		}
+
+		entity, warnings, err := buildLibraryRecipe(imported, cmd.UserID)
+		if err == nil {
+			s.refreshLabels(ctx, entity)
+			if err = s.recipeRepo.Create(ctx, entity); err != nil {
+				s.logger.Error("Failed to save imported recipe", zap.String("title", item.Title), zap.Error(err))
+				err = stderrors.New("the recipe could not be saved")
+			}
+		}
+		if err != nil {
+			item.Status = LibraryItemFailed
+			item.Error = err.Error()
+			result.Failed++
+			result.Items = append(result.Items, item)
+			continue
+		}
+
+		for _, event := range entity.Events() {
+			if err := s.publishEvent(ctx, event); err != nil {
+				s.logger.Error("Failed to publish event",
+					zap.String("event", event.EventName()),
+					zap.Error(err),
+				)
+			}
+		}
+
+		id := entity.ID()
+		item.Status = LibraryItemCreated
+		item.RecipeID = &id
+		item.Warnings = warnings
+		if key != "" {
+			known[key] = id
+		}
+		result.Created++
+		result.Items = append(result.Items, item)
+	}
+
+	s.logger.Info("Recipe library imported",
+		zap.String("user_id", cmd.UserID.String()),
+		zap.String("source", cmd.Source),
+		zap.Int("created", result.Created),
+		zap.Int("duplicates", result.Duplicates),
+		zap.Int("failed", result.Failed),
+	)
+
+	return result, nil
+}
+
+// PreviewImportedRecipe builds the draft ImportRecipeLibrary would save
+// for one recipe, labels included, and checks the title against the
+// user's recipes
+func (s *RecipeService) PreviewImportedRecipe(ctx context.Context, userID uuid.UUID, imported inbound.ImportedRecipe) (*inbound.ImportPreview, error) {
+	entity, warnings, err := buildLibraryRecipe(imported, userID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("The recipe cannot be imported: " + err.Error())
+	}
+	s.refreshLabels(ctx, entity)
+
+	known, err := s.existingTitles(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find user recipes", err)
+	}
+
+	dto := s.entityToDTO(entity)
+	dto.ID = uuid.Nil
+	preview := &inbound.ImportPreview{Recipe: *dto, Warnings: warnings}
+	if id, ok := known[titleKey(entity.Title())]; ok {
+		preview.DuplicateOf = &id
+	}
+	return preview, nil
+}
+
+// existingTitles maps the normalized titles of the user's recipes to their IDs
+func (s *RecipeService) existingTitles(ctx context.Context, userID uuid.UUID) (map[string]uuid.UUID, error) {
+	titles := make(map[string]uuid.UUID)
+	for offset := 0; ; offset += libraryPageSize {
+		recipes, total, err := s.recipeRepo.FindByUserID(ctx, userID, offset, libraryPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range recipes {
+			if key := titleKey(r.Title()); key != "" {
+				titles[key] = r.ID()
+			}
+		}
+		if len(recipes) == 0 || offset+len(recipes) >= total {
+			return titles, nil
+		}
+	}
+}
+
+// buildLibraryRecipe maps an imported recipe onto a draft entity. Lines the
+// parsers reject are skipped and reported as warnings.
+func buildLibraryRecipe(imported inbound.ImportedRecipe, userID uuid.UUID) (*recipe.Recipe, []string, error) {
+	var warnings []string
+
+	title := strings.TrimSpace(imported.Title)
+	if len(title) > maxTitleLength {
+		title = truncateBytes(title, maxTitleLength)
+		warnings = append(warnings, "Title was shortened")
+	}
+
+	description := strings.TrimSpace(imported.Description)
+	if url := strings.TrimSpace(imported.SourceURL); url != "" {
+		description = strings.TrimSpace(description + "\n\nSource: " + url)
+	}
+	if len(description) > maxDescriptionLength {
+		description = truncateBytes(description, maxDescriptionLength)
+		warnings = append(warnings, "Description was shortened")
+	}
+
+	entity, err := recipe.NewRecipe(title, description, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, line := range imported.Ingredients {
+		parsed, err := ingredients.Parse(line)
+		switch {
+		case stderrors.Is(err, ingredients.ErrEmptyLine), stderrors.Is(err, ingredients.ErrSectionHeader):
+			continue
+		case err == nil:
+			err = entity.AddIngredient(parsed.Ingredient())
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Skipped ingredient %q", line))
+		}
+	}
+
+	for _, direction := range imported.Directions {
+		for _, step := range instructions.Split(strings.Split(direction, "\n")) {
+			if err := entity.AddInstruction(step.Instruction()); err != nil {
+				warnings = append(warnings, fmt.Sprintf("Skipped step %d: %v", len(entity.Instructions())+1, err))
+			}
+		}
+	}
+
+	if len(entity.Ingredients()) == 0 && len(entity.Instructions()) == 0 {
+		return nil, nil, stderrors.New("the recipe has no ingredients or directions")
+	}
+
+	if imported.Servings > 0 {
+		if err := entity.SetServings(imported.Servings); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	entity.SetTiming(time.Duration(imported.PrepTime)*time.Minute, time.Duration(imported.CookTime)*time.Minute)
+	entity.SetTags(imported.Tags)
+
+	return entity, warnings, nil
+}
+
+// titleKey folds case, punctuation and spacing so "Mom's Lasagna!" and
+// "moms lasagna" are detected as the same recipe
+func titleKey(title string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// truncateBytes shortens s to at most n bytes without splitting a rune;
+// domain length limits are in bytes
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return strings.TrimSpace(s[:n])
+}
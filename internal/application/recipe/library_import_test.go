@@ -0,0 +1,54 @@
+package recipe
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildLibraryRecipe(t *testing.T) {
+	entity, warnings, err := buildLibraryRecipe(inbound.ImportedRecipe{
+		Title:       "Banana Bread",
+		Description: "Freezes well.",
+		Ingredients: []string{"3 ripe bananas", "", "For the topping:", "2 cups flour", "%%"},
+		Directions:  []string{"1. Mash the bananas.\n2. Fold in the flour.\n3. Bake 1 hr at 350°F."},
+		Servings:    10,
+		PrepTime:    15,
+		CookTime:    60,
+		Tags:        []string{"Baking", "baking", "Breakfast"},
+		SourceURL:   "https://example.com/banana-bread",
+	}, uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Freezes well.\n\nSource: https://example.com/banana-bread", entity.Description())
+	assert.Len(t, entity.Ingredients(), 2)
+	assert.Len(t, entity.Instructions(), 3)
+	assert.Equal(t, 10, entity.Servings())
+	assert.Equal(t, 75*time.Minute, entity.TotalTime())
+	assert.Equal(t, []string{"Baking", "Breakfast"}, entity.Tags())
+	assert.Equal(t, []string{`Skipped ingredient "%%"`}, warnings)
+}
+
+func TestBuildLibraryRecipeTruncatesAndRejectsEmpty(t *testing.T) {
+	entity, warnings, err := buildLibraryRecipe(inbound.ImportedRecipe{
+		Title:       strings.Repeat("é", 150),
+		Ingredients: []string{"1 egg"},
+	}, uuid.New())
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(entity.Title()), maxTitleLength)
+	assert.Contains(t, warnings, "Title was shortened")
+
+	_, _, err = buildLibraryRecipe(inbound.ImportedRecipe{Title: "Empty Recipe"}, uuid.New())
+	assert.Error(t, err)
+}
+
+func TestTitleKey(t *testing.T) {
+	assert.Equal(t, titleKey("moms lasagna"), titleKey("  Mom's   Lasagna!"))
+	assert.Equal(t, "one pot chili", titleKey("One-Pot Chili"))
+	assert.NotEqual(t, titleKey("Chili"), titleKey("Chili 2"))
+}
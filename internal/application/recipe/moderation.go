@@ -0,0 +1,45 @@
+package recipe
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TakeDownRecipe archives a published recipe for the moderators. Unlike
+// ArchiveRecipe it does not check who is asking.
+func (s *RecipeService) TakeDownRecipe(ctx context.Context, recipeID uuid.UUID) error {
+	return s.moderate(ctx, recipeID, (*recipe.Recipe).Archive, "only published recipes can be taken down", "Recipe taken down")
+}
+
+// ReinstateRecipe publishes a recipe taken down by TakeDownRecipe again
+func (s *RecipeService) ReinstateRecipe(ctx context.Context, recipeID uuid.UUID) error {
+	return s.moderate(ctx, recipeID, (*recipe.Recipe).Unarchive, "only archived recipes can be reinstated", "Recipe reinstated")
+}
+
+func (s *RecipeService) moderate(ctx context.Context, recipeID uuid.UUID, transition func(*recipe.Recipe) error, conflict, message string) error {
+	entity, err := s.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil {
+		return errors.NewRecipeNotFoundError(recipeID.String())
+	}
+
+	if err := transition(entity); err != nil {
+		if stderrors.Is(err, recipe.ErrInvalidStatusTransition) {
+			return errors.NewConflictError(conflict)
+		}
+		return errors.Wrap(err, "failed to change recipe status")
+	}
+	if err := s.save(ctx, entity); err != nil {
+		return err
+	}
+
+	s.logger.Info(message, zap.String("recipe_id", recipeID.String()))
+	return nil
+}
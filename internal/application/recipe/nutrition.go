@@ -0,0 +1,82 @@
+package recipe
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/allergens"
+	"github.com/alchemorsel/v3/internal/domain/recipe/nutrition"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// NutritionSource labels the built-in foods seeded into the table
+const NutritionSource = "USDA SR Legacy"
+
+// nutritionReload is how long a loaded table is used before the stored
+// foods are read again, so edits reach every replica
+const nutritionReload = 5 * time.Minute
+
+// SeedIngredientNutrition adds the built-in foods missing from the table.
+// Foods already stored, including edited ones, are left alone.
+func SeedIngredientNutrition(ctx context.Context, repo outbound.IngredientNutritionRepository, logger *zap.Logger) error {
+	added, err := repo.SeedMissing(ctx, nutrition.Reference(), NutritionSource)
+	if err != nil {
+		return err
+	}
+	if added > 0 {
+		logger.Info("Ingredient nutrition seeded", zap.Int("foods", added))
+	}
+	return nil
+}
+
+// nutritionTable serves the foods recipe nutrition is computed from. The
+// built-in table is used without a repository, while it is empty, or when
+// it cannot be read.
+type nutritionTable struct {
+	repo     outbound.IngredientNutritionRepository
+	logger   *zap.Logger
+	mu       sync.Mutex
+	table    *nutrition.Table
+	loadedAt time.Time
+}
+
+func newNutritionTable(repo outbound.IngredientNutritionRepository, logger *zap.Logger) *nutritionTable {
+	return &nutritionTable{repo: repo, logger: logger}
+}
+
+func (t *nutritionTable) get(ctx context.Context) *nutrition.Table {
+	if t == nil || t.repo == nil {
+		return nutrition.Default()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.table != nil && time.Since(t.loadedAt) < nutritionReload {
+		return t.table
+	}
+
+	foods, err := t.repo.List(ctx)
+	switch {
+	case err != nil:
+		t.logger.Warn("Failed to load ingredient nutrition, using built-in foods", zap.Error(err))
+		if t.table != nil {
+			return t.table
+		}
+		return nutrition.Default()
+	case len(foods) == 0:
+		t.table = nutrition.Default()
+	default:
+		t.table = nutrition.NewTable(foods)
+	}
+	t.loadedAt = time.Now()
+	return t.table
+}
+
+// refreshLabels recomputes the per-serving nutrition and the allergen
+// label stored on the recipe from its ingredients
+func (s *RecipeService) refreshLabels(ctx context.Context, entity *recipe.Recipe) {
+	entity.SetNutrition(s.foods.get(ctx).Label(entity.Ingredients(), entity.Servings()))
+	entity.SetAllergens(allergens.Label(entity.Ingredients()))
+}
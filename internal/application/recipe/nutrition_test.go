@@ -0,0 +1,67 @@
+package recipe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/nutrition"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubNutritionFoods struct {
+	foods []nutrition.Food
+	lists int
+}
+
+func (s *stubNutritionFoods) List(context.Context) ([]nutrition.Food, error) {
+	s.lists++
+	return s.foods, nil
+}
+
+func (s *stubNutritionFoods) SeedMissing(_ context.Context, foods []nutrition.Food, _ string) (int, error) {
+	if len(s.foods) > 0 {
+		return 0, nil
+	}
+	s.foods = foods
+	return len(foods), nil
+}
+
+func TestRefreshLabelsUsesStoredFoods(t *testing.T) {
+	entity, err := recipe.NewRecipe("Rice and parsley", "", uuid.New())
+	require.NoError(t, err)
+	require.NoError(t, entity.SetServings(2))
+	require.NoError(t, entity.AddIngredient(recipe.Ingredient{ID: uuid.New(), Name: "white rice", Amount: 200, Unit: recipe.MeasurementUnitGram}))
+	require.NoError(t, entity.AddIngredient(recipe.Ingredient{ID: uuid.New(), Name: "parsley", Amount: 20, Unit: recipe.MeasurementUnitGram}))
+
+	foods := &stubNutritionFoods{}
+	svc := &RecipeService{foods: newNutritionTable(foods, zap.NewNop())}
+	ctx := context.Background()
+
+	svc.refreshLabels(ctx, entity)
+	builtin := entity.NutritionInfo()
+	require.NotNil(t, builtin, "an empty table falls back to the built-in foods")
+	assert.Equal(t, 0.5, builtin.Coverage, "parsley is not a built-in food")
+	assert.Equal(t, builtin.Calories, entity.Calories())
+	require.NotNil(t, entity.Allergens(), "the allergen label is stored alongside")
+	assert.Empty(t, entity.Allergens().Contains)
+
+	require.NoError(t, SeedIngredientNutrition(ctx, foods, zap.NewNop()))
+	parsley, err := nutrition.NewFood("parsley", "parsley", nutrition.Facts{Calories: 36, Protein: 3}, 20)
+	require.NoError(t, err)
+	svc.foods = newNutritionTable(&stubNutritionFoods{foods: append(foods.foods, parsley)}, zap.NewNop())
+
+	svc.refreshLabels(ctx, entity)
+	stored := entity.NutritionInfo()
+	require.NotNil(t, stored)
+	assert.Equal(t, 1.0, stored.Coverage)
+	assert.Equal(t, builtin.Calories+4, stored.Calories, "10 g of parsley a serving")
+
+	require.NoError(t, entity.ReplaceIngredients([]recipe.Ingredient{{ID: uuid.New(), Name: "saffron", Amount: 1, Unit: recipe.MeasurementUnitGram}}))
+	svc.refreshLabels(ctx, entity)
+	assert.Nil(t, entity.NutritionInfo(), "nothing could be estimated")
+	assert.Zero(t, entity.Calories())
+}
@@ -0,0 +1,227 @@
+// Package recipe provides personalized ranking for recipe search
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+)
+
+// Ranking weights for personalized search. The base weight keeps the
+// repository's relevance order dominant so personal signals mostly reorder
+// close calls; avoided ingredients sink a result regardless of position.
+const (
+	baseRankWeight         = 3.0
+	dietaryMatchWeight     = 1.5
+	avoidIngredientWeight  = -4.0
+	preferredCuisineWeight = 1.0
+	likedCuisineWeight     = 2.0
+	cookedCuisineWeight    = 1.0
+	engagedCuisineWeight   = 1.0
+)
+
+// historyLimit bounds how many liked/authored recipes feed the profile
+const historyLimit = 100
+
+// Ranking signal names reported in explanations
+const (
+	SignalBase             = "base"
+	SignalDietary          = "dietary"
+	SignalAvoid            = "avoid_ingredient"
+	SignalPreferredCuisine = "preferred_cuisine"
+	SignalLikedCuisine     = "liked_cuisine"
+	SignalCookedCuisine    = "cooked_cuisine"
+	SignalEngagedCuisine   = "engaged_cuisine"
+)
+
+// searchProfile is the per-user input to personalized ranking
+type searchProfile struct {
+	dietary           []string
+	avoid             []string
+	preferredCuisines map[recipe.CuisineType]bool
+	// likedCuisines, cookedCuisines and engagedCuisines hold each
+	// cuisine's share (0..1) of the user's likes, authored recipes and
+	// recipes whose comments and reviews they reacted to
+	likedCuisines   map[recipe.CuisineType]float64
+	cookedCuisines  map[recipe.CuisineType]float64
+	engagedCuisines map[recipe.CuisineType]float64
+}
+
+// empty reports whether the profile has no signal to rank with
+func (p *searchProfile) empty() bool {
+	return len(p.dietary) == 0 && len(p.avoid) == 0 && len(p.preferredCuisines) == 0 &&
+		len(p.likedCuisines) == 0 && len(p.cookedCuisines) == 0 && len(p.engagedCuisines) == 0
+}
+
+// loadSearchProfile gathers dietary preferences, like history, cooking
+// history and comment engagement. It returns nil when the user opted out of
+// personalization.
+func (s *RecipeService) loadSearchProfile(ctx context.Context, userID uuid.UUID) (*searchProfile, error) {
+	userEntity, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("load user: %w", err)
+	}
+	if userEntity == nil || !userEntity.PersonalizationEnabled() {
+		return nil, nil
+	}
+
+	profile := &searchProfile{preferredCuisines: make(map[recipe.CuisineType]bool)}
+	if prefs := userEntity.Preferences(); prefs != nil {
+		for _, restriction := range prefs.DietaryRestrictions {
+			profile.dietary = append(profile.dietary, normalizeTag(string(restriction)))
+		}
+		for _, term := range append(append([]string{}, prefs.Allergies...), prefs.DislikedIngredients...) {
+			if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+				profile.avoid = append(profile.avoid, term)
+			}
+		}
+		for _, cuisine := range prefs.PreferredCuisines {
+			profile.preferredCuisines[recipe.CuisineType(strings.ToLower(cuisine))] = true
+		}
+	}
+
+	liked, err := s.recipeRepo.FindLikedByUser(ctx, userID, historyLimit)
+	if err != nil {
+		return nil, fmt.Errorf("load liked recipes: %w", err)
+	}
+	profile.likedCuisines = cuisineShares(liked)
+
+	authored, _, err := s.recipeRepo.FindByUserID(ctx, userID, 0, historyLimit)
+	if err != nil {
+		return nil, fmt.Errorf("load authored recipes: %w", err)
+	}
+	profile.cookedCuisines = cuisineShares(authored)
+
+	if s.feedback != nil {
+		engaged, err := s.feedback.FindEngagedRecipes(ctx, userID, historyLimit)
+		if err != nil {
+			return nil, fmt.Errorf("load comment engagement: %w", err)
+		}
+		if len(engaged) > 0 {
+			recipes, err := s.recipeRepo.FindByIDs(ctx, engaged)
+			if err != nil {
+				return nil, fmt.Errorf("load engaged recipes: %w", err)
+			}
+			profile.engagedCuisines = cuisineShares(recipes)
+		}
+	}
+
+	return profile, nil
+}
+
+// cuisineShares returns each cuisine's fraction of recipes
+func cuisineShares(recipes []*recipe.Recipe) map[recipe.CuisineType]float64 {
+	counts := make(map[recipe.CuisineType]float64)
+	total := 0.0
+	for _, r := range recipes {
+		if r.Cuisine() == "" {
+			continue
+		}
+		counts[r.Cuisine()]++
+		total++
+	}
+	for cuisine := range counts {
+		counts[cuisine] /= total
+	}
+	return counts
+}
+
+// explicitSort reports whether a search asked for one of the listings,
+// whose order personalization must not change
+func explicitSort(orderBy string) bool {
+	return outbound.KeysetOrders[orderBy]
+}
+
+// rankRecipes re-orders a page of results by personalized score. With a nil
+// profile only the base factor is applied, which keeps the original order
+// but still lets explain mode describe the ranking.
+func rankRecipes(recipes []inbound.RecipeDTO, profile *searchProfile) ([]inbound.RecipeDTO, []inbound.RankingExplanation) {
+	explanations := make([]inbound.RankingExplanation, len(recipes))
+	for i := range recipes {
+		explanations[i] = scoreRecipe(&recipes[i], i, len(recipes), profile)
+	}
+
+	order := make([]int, len(recipes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return explanations[order[a]].Score > explanations[order[b]].Score
+	})
+
+	ranked := make([]inbound.RecipeDTO, len(recipes))
+	rankedExplanations := make([]inbound.RankingExplanation, len(recipes))
+	for rank, i := range order {
+		ranked[rank] = recipes[i]
+		explanations[i].Rank = rank + 1
+		rankedExplanations[rank] = explanations[i]
+	}
+	return ranked, rankedExplanations
+}
+
+func scoreRecipe(dto *inbound.RecipeDTO, position, total int, profile *searchProfile) inbound.RankingExplanation {
+	base := baseRankWeight * (1 - float64(position)/float64(total))
+	explanation := inbound.RankingExplanation{
+		RecipeID:     dto.ID,
+		OriginalRank: position + 1,
+		Score:        base,
+		Factors: []inbound.RankingFactor{{
+			Signal: SignalBase,
+			Detail: fmt.Sprintf("search relevance position %d of %d", position+1, total),
+			Weight: base,
+		}},
+	}
+	if profile == nil {
+		return explanation
+	}
+
+	add := func(signal, detail string, weight float64) {
+		explanation.Factors = append(explanation.Factors, inbound.RankingFactor{Signal: signal, Detail: detail, Weight: weight})
+		explanation.Score += weight
+	}
+
+	tags := make(map[string]bool, len(dto.Tags))
+	for _, tag := range dto.Tags {
+		tags[normalizeTag(tag)] = true
+	}
+	for _, restriction := range profile.dietary {
+		if tags[restriction] {
+			add(SignalDietary, "matches your "+restriction+" preference", dietaryMatchWeight)
+		}
+	}
+
+	for _, term := range profile.avoid {
+		for _, ingredient := range dto.Ingredients {
+			if strings.Contains(strings.ToLower(ingredient.Name), term) {
+				add(SignalAvoid, "contains "+term, avoidIngredientWeight)
+				break
+			}
+		}
+	}
+
+	if profile.preferredCuisines[dto.Cuisine] {
+		add(SignalPreferredCuisine, string(dto.Cuisine)+" is a preferred cuisine", preferredCuisineWeight)
+	}
+	if share := profile.likedCuisines[dto.Cuisine]; share > 0 {
+		add(SignalLikedCuisine, fmt.Sprintf("%.0f%% of your likes are %s", share*100, dto.Cuisine), likedCuisineWeight*share)
+	}
+	if share := profile.cookedCuisines[dto.Cuisine]; share > 0 {
+		add(SignalCookedCuisine, fmt.Sprintf("%.0f%% of your recipes are %s", share*100, dto.Cuisine), cookedCuisineWeight*share)
+	}
+	if share := profile.engagedCuisines[dto.Cuisine]; share > 0 {
+		add(SignalEngagedCuisine, fmt.Sprintf("%.0f%% of the reviews and comments you reacted to are on %s recipes", share*100, dto.Cuisine), engagedCuisineWeight*share)
+	}
+
+	return explanation
+}
+
+// normalizeTag folds "Gluten-Free" and "gluten free" onto "gluten_free"
+func normalizeTag(tag string) string {
+	return strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(strings.TrimSpace(tag)))
+}
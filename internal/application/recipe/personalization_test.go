@@ -0,0 +1,62 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankRecipesBoostsProfileMatches(t *testing.T) {
+	plain := inbound.RecipeDTO{ID: uuid.New(), Title: "Beef stew", Cuisine: recipe.CuisineTypeFrench}
+	peanut := inbound.RecipeDTO{ID: uuid.New(), Title: "Satay", Ingredients: []inbound.IngredientDTO{{Name: "Peanut butter"}}}
+	vegan := inbound.RecipeDTO{ID: uuid.New(), Title: "Tofu curry", Tags: []string{"Vegan"}, Cuisine: recipe.CuisineTypeIndian}
+
+	profile := &searchProfile{
+		dietary:        []string{"vegan"},
+		avoid:          []string{"peanut"},
+		likedCuisines:  map[recipe.CuisineType]float64{recipe.CuisineTypeIndian: 1},
+		cookedCuisines: map[recipe.CuisineType]float64{},
+		// Reacting to reviews of French recipes lifts the stew, but not
+		// past the recipe that matches two signals
+		engagedCuisines: map[recipe.CuisineType]float64{recipe.CuisineTypeFrench: 1},
+	}
+
+	ranked, explanations := rankRecipes([]inbound.RecipeDTO{plain, peanut, vegan}, profile)
+
+	require.Len(t, ranked, 3)
+	assert.Equal(t, []string{"Tofu curry", "Beef stew", "Satay"},
+		[]string{ranked[0].Title, ranked[1].Title, ranked[2].Title})
+
+	top := explanations[0]
+	assert.Equal(t, vegan.ID, top.RecipeID)
+	assert.Equal(t, 3, top.OriginalRank)
+	assert.Equal(t, 1, top.Rank)
+
+	var signals []string
+	for _, factor := range top.Factors {
+		signals = append(signals, factor.Signal)
+	}
+	assert.Equal(t, []string{SignalBase, SignalDietary, SignalLikedCuisine}, signals)
+	assert.Equal(t, SignalEngagedCuisine, explanations[1].Factors[1].Signal)
+}
+
+func TestRankRecipesWithoutProfileKeepsOrder(t *testing.T) {
+	a := inbound.RecipeDTO{ID: uuid.New(), Title: "A"}
+	b := inbound.RecipeDTO{ID: uuid.New(), Title: "B"}
+
+	ranked, explanations := rankRecipes([]inbound.RecipeDTO{a, b}, nil)
+
+	assert.Equal(t, "A", ranked[0].Title)
+	assert.Equal(t, "B", ranked[1].Title)
+	require.Len(t, explanations[1].Factors, 1)
+	assert.Equal(t, SignalBase, explanations[1].Factors[0].Signal)
+}
+
+func TestNormalizeTag(t *testing.T) {
+	assert.Equal(t, "gluten_free", normalizeTag(" Gluten-Free "))
+	assert.Equal(t, "dairy_free", normalizeTag("dairy free"))
+}
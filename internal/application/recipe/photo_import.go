@@ -0,0 +1,318 @@
+// Package recipe provides recipe import from photos of printed recipes
+package recipe
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+	"github.com/alchemorsel/v3/internal/domain/recipe/instructions"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxImportImageBytes bounds uploaded photos and scans
+	maxImportImageBytes = 10 << 20
+	// lowConfidenceThreshold flags recognized lines for review
+	lowConfidenceThreshold = 0.6
+	// importedTitleFallback is used when no usable title was recognized
+	importedTitleFallback = "Imported recipe"
+	maxDescriptionLength  = 2000
+)
+
+// Sections and reasons reported in inbound.ImportRegion
+const (
+	ImportSectionTitle        = "title"
+	ImportSectionDescription  = "description"
+	ImportSectionIngredients  = "ingredients"
+	ImportSectionInstructions = "instructions"
+
+	ImportReasonLowConfidence = "low_confidence"
+	ImportReasonUnparsed      = "unparsed"
+
+	// importSectionSkip drops notes and nutrition panels from the draft
+	importSectionSkip = "skip"
+)
+
+var importImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+	"image/tiff": true,
+}
+
+// ImportRecipeFromImage runs OCR over a recipe photo, parses the text into
+// ingredients and steps and saves the result as a draft. Lines the OCR was
+// unsure of, or that could not be parsed, are returned for review.
+func (s *RecipeService) ImportRecipeFromImage(ctx context.Context, cmd inbound.ImportRecipeImageCommand) (*inbound.RecipeImport, error) {
+	if s.ocr == nil {
+		return nil, errors.NewAppError(errors.CodeServiceUnavailable, "Photo import is not available", "No OCR provider is configured")
+	}
+	if err := validateImportImage(cmd); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.userRepo.Exists(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("check user existence", err)
+	}
+	if !exists {
+		return nil, errors.NewUserNotFoundError(cmd.UserID.String())
+	}
+
+	result, err := s.ocr.ExtractText(ctx, cmd.Image, cmd.ContentType)
+	if err != nil {
+		return nil, errors.NewExternalServiceError("OCR provider", err)
+	}
+
+	recipeEntity, review, err := buildImportedRecipe(segmentRecipeText(result.Lines), cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	s.refreshLabels(ctx, recipeEntity)
+	if err := s.recipeRepo.Create(ctx, recipeEntity); err != nil {
+		return nil, errors.NewDatabaseError("create imported recipe", err)
+	}
+	s.syncDraft(ctx, recipeEntity, 0)
+
+	for _, event := range recipeEntity.Events() {
+		if err := s.publishEvent(ctx, event); err != nil {
+			s.logger.Error("Failed to publish event",
+				zap.String("event", event.EventName()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	dto := s.entityToDTO(recipeEntity)
+
+	s.logger.Info("Recipe imported from image",
+		zap.String("recipe_id", dto.ID.String()),
+		zap.String("provider", result.Provider),
+		zap.Int("ingredients", len(dto.Ingredients)),
+		zap.Int("instructions", len(dto.Instructions)),
+		zap.Int("review", len(review)),
+	)
+
+	return &inbound.RecipeImport{
+		Recipe:   *dto,
+		Provider: result.Provider,
+		Review:   review,
+	}, nil
+}
+
+func validateImportImage(cmd inbound.ImportRecipeImageCommand) error {
+	if len(cmd.Image) == 0 {
+		return errors.NewBadRequestError("Image is required")
+	}
+	if len(cmd.Image) > maxImportImageBytes {
+		return errors.NewBadRequestError(fmt.Sprintf("Image must be at most %d MB", maxImportImageBytes>>20))
+	}
+	mediaType, _, err := mime.ParseMediaType(cmd.ContentType)
+	if err != nil || !importImageTypes[mediaType] {
+		return errors.NewBadRequestError("Image must be a JPEG, PNG, WebP or TIFF file")
+	}
+	return nil
+}
+
+// recipeText is OCR output split into recipe sections
+type recipeText struct {
+	lines        []outbound.OCRLine
+	title        int
+	description  []int
+	ingredients  []int
+	instructions []int
+}
+
+var (
+	ingredientHeaders  = []string{"ingredients", "ingredient", "what you need", "what you'll need", "you will need", "you'll need", "shopping list"}
+	instructionHeaders = []string{"instructions", "directions", "method", "preparation", "steps", "how to make it", "procedure"}
+	ignoredHeaders     = []string{"notes", "note", "tips", "tip", "nutrition", "nutrition facts", "nutrition information"}
+
+	numberedStep       = regexp.MustCompile(`(?i)^(?:step\s*\d{1,2}\b|\d{1,2}\s*[.)])`)
+	startsWithQuantity = regexp.MustCompile(`^[•·*\-\s]*[\d¼½¾⅓⅔⅛⅜⅝⅞]`)
+)
+
+// segmentRecipeText assigns each line to a section. Explicit headers win;
+// otherwise the first line is the title, lines that parse as measured
+// ingredients open the ingredient list and the first step-like line after it
+// opens the instructions.
+func segmentRecipeText(lines []outbound.OCRLine) recipeText {
+	doc := recipeText{lines: lines, title: -1}
+	section := ""
+
+	for i, line := range lines {
+		text := strings.TrimSpace(line.Text)
+		if header := sectionHeader(text); header != "" {
+			section = header
+			continue
+		}
+		if text == "" {
+			if section == ImportSectionInstructions {
+				doc.instructions = append(doc.instructions, i)
+			}
+			continue
+		}
+
+		switch section {
+		case "":
+			switch {
+			case doc.title < 0:
+				doc.title = i
+			case looksLikeIngredient(text):
+				section = ImportSectionIngredients
+				doc.ingredients = append(doc.ingredients, i)
+			case looksLikeStep(text):
+				section = ImportSectionInstructions
+				doc.instructions = append(doc.instructions, i)
+			default:
+				doc.description = append(doc.description, i)
+			}
+		case ImportSectionIngredients:
+			if looksLikeStep(text) && !looksLikeIngredient(text) {
+				section = ImportSectionInstructions
+				doc.instructions = append(doc.instructions, i)
+				continue
+			}
+			doc.ingredients = append(doc.ingredients, i)
+		case ImportSectionInstructions:
+			doc.instructions = append(doc.instructions, i)
+		}
+	}
+
+	return doc
+}
+
+// sectionHeader maps a heading line to its section
+func sectionHeader(text string) string {
+	key := strings.ToLower(strings.TrimSpace(strings.TrimRight(text, ": ")))
+	for _, h := range ingredientHeaders {
+		if key == h {
+			return ImportSectionIngredients
+		}
+	}
+	for _, h := range instructionHeaders {
+		if key == h {
+			return ImportSectionInstructions
+		}
+	}
+	for _, h := range ignoredHeaders {
+		if key == h {
+			return importSectionSkip
+		}
+	}
+	return ""
+}
+
+// looksLikeIngredient requires a leading numeral so prose starting with
+// "A" or "An" is not read as a quantity
+func looksLikeIngredient(text string) bool {
+	if !startsWithQuantity.MatchString(text) || numberedStep.MatchString(text) {
+		return false
+	}
+	parsed, err := ingredients.Parse(text)
+	return err == nil && parsed.Amount > 0
+}
+
+func looksLikeStep(text string) bool {
+	return numberedStep.MatchString(text) || (len(text) > 60 && strings.HasSuffix(text, "."))
+}
+
+// buildImportedRecipe creates the draft entity and collects review regions
+func buildImportedRecipe(doc recipeText, cmd inbound.ImportRecipeImageCommand) (*recipe.Recipe, []inbound.ImportRegion, error) {
+	review := []inbound.ImportRegion{}
+	flag := func(section string, index, line int, reason string) {
+		review = append(review, inbound.ImportRegion{
+			Section:    section,
+			Index:      index,
+			Text:       strings.TrimSpace(doc.lines[line].Text),
+			Confidence: doc.lines[line].Confidence,
+			Reason:     reason,
+		})
+	}
+
+	title := importedTitleFallback
+	if doc.title >= 0 {
+		candidate := strings.TrimSpace(doc.lines[doc.title].Text)
+		if len(candidate) >= 3 && len(candidate) <= 200 {
+			title = candidate
+			if doc.lines[doc.title].Confidence < lowConfidenceThreshold {
+				flag(ImportSectionTitle, 0, doc.title, ImportReasonLowConfidence)
+			}
+		} else {
+			flag(ImportSectionTitle, -1, doc.title, ImportReasonUnparsed)
+		}
+	}
+
+	var description []string
+	for i, line := range doc.description {
+		description = append(description, strings.TrimSpace(doc.lines[line].Text))
+		if doc.lines[line].Confidence < lowConfidenceThreshold {
+			flag(ImportSectionDescription, i, line, ImportReasonLowConfidence)
+		}
+	}
+	desc := truncateBytes(strings.Join(description, " "), maxDescriptionLength)
+
+	recipeEntity, err := recipe.NewRecipe(title, desc, cmd.UserID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create imported recipe entity")
+	}
+
+	added := 0
+	for _, line := range doc.ingredients {
+		parsed, err := ingredients.Parse(doc.lines[line].Text)
+		if stderrors.Is(err, ingredients.ErrSectionHeader) {
+			continue
+		}
+		if err == nil {
+			err = recipeEntity.AddIngredient(parsed.Ingredient())
+		}
+		if err != nil {
+			flag(ImportSectionIngredients, -1, line, ImportReasonUnparsed)
+			continue
+		}
+		if doc.lines[line].Confidence < lowConfidenceThreshold {
+			flag(ImportSectionIngredients, added, line, ImportReasonLowConfidence)
+		}
+		added++
+	}
+
+	texts := make([]string, len(doc.instructions))
+	for i, line := range doc.instructions {
+		texts[i] = doc.lines[line].Text
+	}
+	steps := 0
+	for _, step := range instructions.Split(texts) {
+		first := doc.instructions[step.Lines[0]]
+		confidence := 1.0
+		for _, i := range step.Lines {
+			if c := doc.lines[doc.instructions[i]].Confidence; c < confidence {
+				confidence, first = c, doc.instructions[i]
+			}
+		}
+
+		if err := recipeEntity.AddInstruction(step.Instruction()); err != nil {
+			flag(ImportSectionInstructions, -1, first, ImportReasonUnparsed)
+			continue
+		}
+		if confidence < lowConfidenceThreshold {
+			flag(ImportSectionInstructions, steps, first, ImportReasonLowConfidence)
+		}
+		steps++
+	}
+
+	if added == 0 && steps == 0 {
+		return nil, nil, errors.NewBadRequestError("No recipe text was recognized in the image")
+	}
+
+	return recipeEntity, review, nil
+}
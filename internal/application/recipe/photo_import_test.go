@@ -0,0 +1,88 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ocrLines(texts ...string) []outbound.OCRLine {
+	lines := make([]outbound.OCRLine, len(texts))
+	for i, text := range texts {
+		lines[i] = outbound.OCRLine{Text: text, Confidence: 0.95}
+	}
+	return lines
+}
+
+func TestImportSegmentsCookbookPage(t *testing.T) {
+	lines := ocrLines(
+		"Lemon Bars",
+		"A bright, buttery classic.",
+		"",
+		"INGREDIENTS",
+		"For the crust:",
+		"1 cup all-purpose flour",
+		"1/2 cup butter, softened",
+		"2 large eggs",
+		"Pinch of salt",
+		"~~%#",
+		"",
+		"Method",
+		"1. Preheat the oven to 350°F.",
+		"2. Press the crust into the pan and",
+		"bake for 20 minutes.",
+		"Notes:",
+		"Keeps for 3 days.",
+	)
+	lines[6].Confidence = 0.42 // "1/2 cup butter"
+	lines[14].Confidence = 0.3 // wrapped part of step 2
+
+	doc := segmentRecipeText(lines)
+	assert.Equal(t, 0, doc.title)
+	assert.Equal(t, []int{1}, doc.description)
+	assert.Equal(t, []int{4, 5, 6, 7, 8, 9}, doc.ingredients)
+	assert.Equal(t, []int{12, 13, 14}, doc.instructions)
+
+	entity, review, err := buildImportedRecipe(doc, inbound.ImportRecipeImageCommand{UserID: uuid.New()})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Lemon Bars", entity.Title())
+	assert.Equal(t, "A bright, buttery classic.", entity.Description())
+	require.Len(t, entity.Ingredients(), 4)
+	assert.Equal(t, "butter", entity.Ingredients()[1].Name)
+	require.Len(t, entity.Instructions(), 2)
+	assert.Equal(t, "Press the crust into the pan and bake for 20 minutes.", entity.Instructions()[1].Description)
+
+	assert.Equal(t, []inbound.ImportRegion{
+		{Section: ImportSectionIngredients, Index: 1, Text: "1/2 cup butter, softened", Confidence: 0.42, Reason: ImportReasonLowConfidence},
+		{Section: ImportSectionIngredients, Index: -1, Text: "~~%#", Confidence: 0.95, Reason: ImportReasonUnparsed},
+		{Section: ImportSectionInstructions, Index: 1, Text: "bake for 20 minutes.", Confidence: 0.3, Reason: ImportReasonLowConfidence},
+	}, review)
+}
+
+func TestImportSegmentsWithoutHeaders(t *testing.T) {
+	doc := segmentRecipeText(ocrLines(
+		"Quick Guacamole",
+		"2 ripe avocados",
+		"1 tbsp lime juice",
+		"Mash the avocados with the lime juice and season generously with salt.",
+	))
+
+	assert.Equal(t, []int{1, 2}, doc.ingredients)
+	assert.Equal(t, []int{3}, doc.instructions)
+}
+
+func TestImportRejectsEmptyText(t *testing.T) {
+	_, _, err := buildImportedRecipe(segmentRecipeText(ocrLines("Smudge")), inbound.ImportRecipeImageCommand{UserID: uuid.New()})
+	assert.Error(t, err)
+}
+
+func TestValidateImportImage(t *testing.T) {
+	assert.NoError(t, validateImportImage(inbound.ImportRecipeImageCommand{Image: []byte{1}, ContentType: "image/jpeg"}))
+	assert.Error(t, validateImportImage(inbound.ImportRecipeImageCommand{Image: []byte{1}, ContentType: "application/pdf"}))
+	assert.Error(t, validateImportImage(inbound.ImportRecipeImageCommand{ContentType: "image/png"}))
+}
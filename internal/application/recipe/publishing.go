@@ -0,0 +1,358 @@
+// Package recipe provides scheduled publishing and new recipe announcements
+package recipe
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// publishBatchSize bounds the drafts published per scheduler run
+	publishBatchSize = 50
+	// announcementBatchSize bounds the deliveries attempted per run
+	announcementBatchSize = 50
+	// maxAnnouncementAttempts gives up on a channel after this many failures
+	maxAnnouncementAttempts = 6
+	// announcementRetryBase is the first retry delay; it doubles per attempt
+	// up to announcementRetryMax
+	announcementRetryBase = time.Minute
+	announcementRetryMax  = time.Hour
+	// maxAnnouncementError matches what is worth keeping of a failure
+	maxAnnouncementError = 1000
+)
+
+// publishRuleErrors are the domain errors an author can fix, reported as bad
+// requests rather than server errors
+var publishRuleErrors = []error{
+	recipe.ErrInvalidStatusTransition,
+	recipe.ErrSchedulePublishInPast,
+	recipe.ErrPublishNotScheduled,
+	recipe.ErrNoIngredients,
+	recipe.ErrNoInstructions,
+	recipe.ErrInvalidServings,
+}
+
+// SchedulePublish sets the time a draft publishes itself. Only the author
+// may schedule, and the draft must already be ready to publish.
+func (s *RecipeService) SchedulePublish(ctx context.Context, cmd inbound.SchedulePublishCommand) (*inbound.RecipeDTO, error) {
+	entity, err := s.authorRecipe(ctx, cmd.RecipeID, cmd.UserID, "schedule this recipe")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := entity.SchedulePublish(cmd.PublishAt); err != nil {
+		return nil, publishError(err, "failed to schedule recipe")
+	}
+	if err := s.save(ctx, entity); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Recipe publish scheduled",
+		zap.String("recipe_id", cmd.RecipeID.String()),
+		zap.Time("publish_at", cmd.PublishAt),
+	)
+
+	return s.entityToDTO(entity), nil
+}
+
+// CancelScheduledPublish keeps a scheduled draft unpublished
+func (s *RecipeService) CancelScheduledPublish(ctx context.Context, recipeID, userID uuid.UUID) (*inbound.RecipeDTO, error) {
+	entity, err := s.authorRecipe(ctx, recipeID, userID, "schedule this recipe")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := entity.CancelScheduledPublish(); err != nil {
+		return nil, publishError(err, "failed to cancel scheduled publish")
+	}
+	if err := s.save(ctx, entity); err != nil {
+		return nil, err
+	}
+
+	return s.entityToDTO(entity), nil
+}
+
+// PublishDueRecipes publishes drafts whose scheduled time has passed and
+// returns how many went live. A draft that can no longer be published, for
+// example because its ingredients were removed, has its schedule cleared so
+// the author can fix it and schedule again.
+func (s *RecipeService) PublishDueRecipes(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := s.recipeRepo.FindScheduledDue(ctx, now, publishBatchSize)
+	if err != nil {
+		return 0, errors.NewDatabaseError("find scheduled recipes", err)
+	}
+
+	published := 0
+	for _, entity := range due {
+		if !entity.IsPublishDue(now) {
+			continue
+		}
+
+		err := s.publish(ctx, entity)
+		if err == nil {
+			published++
+			s.logger.Info("Scheduled recipe published", zap.String("recipe_id", entity.ID().String()))
+			continue
+		}
+		if !errors.Is(err, errors.CodeBadRequest) {
+			return published, err
+		}
+
+		s.logger.Warn("Scheduled recipe could not be published",
+			zap.String("recipe_id", entity.ID().String()),
+			zap.Error(err),
+		)
+		if cancelErr := entity.CancelScheduledPublish(); cancelErr == nil {
+			if saveErr := s.save(ctx, entity); saveErr != nil {
+				return published, saveErr
+			}
+		}
+	}
+
+	return published, nil
+}
+
+// DeliverAnnouncements posts due announcements to their channels and returns
+// how many were sent. Failures are retried with exponential backoff until
+// maxAnnouncementAttempts.
+func (s *RecipeService) DeliverAnnouncements(ctx context.Context) (int, error) {
+	if s.announcements == nil || len(s.posters) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	due, err := s.announcements.FindDue(ctx, now, announcementBatchSize)
+	if err != nil {
+		return 0, errors.NewDatabaseError("find due announcements", err)
+	}
+
+	posters := make(map[string]outbound.RecipePoster, len(s.posters))
+	for _, poster := range s.posters {
+		posters[poster.Channel()] = poster
+	}
+	announcements := make(map[uuid.UUID]*outbound.RecipeAnnouncement)
+
+	sent := 0
+	for _, delivery := range due {
+		announcement, ok := announcements[delivery.RecipeID]
+		if !ok {
+			if announcement, err = s.buildAnnouncement(ctx, delivery.RecipeID); err != nil {
+				return sent, err
+			}
+			announcements[delivery.RecipeID] = announcement
+		}
+
+		poster, configured := posters[delivery.Channel]
+		switch {
+		case announcement == nil:
+			delivery.Status = outbound.AnnouncementFailed
+			delivery.LastError = "recipe is no longer published"
+		case !configured:
+			delivery.Status = outbound.AnnouncementFailed
+			delivery.LastError = "channel is no longer configured"
+		default:
+			delivery = s.attemptDelivery(ctx, poster, *announcement, delivery, now)
+		}
+
+		if delivery.Status == outbound.AnnouncementSent {
+			sent++
+		}
+		if err := s.announcements.Update(ctx, delivery); err != nil {
+			return sent, errors.NewDatabaseError("update announcement", err)
+		}
+	}
+
+	return sent, nil
+}
+
+// attemptDelivery posts one announcement and records the outcome
+func (s *RecipeService) attemptDelivery(
+	ctx context.Context,
+	poster outbound.RecipePoster,
+	announcement outbound.RecipeAnnouncement,
+	delivery outbound.AnnouncementDelivery,
+	now time.Time,
+) outbound.AnnouncementDelivery {
+	delivery.Attempts++
+
+	err := poster.Post(ctx, announcement)
+	if err == nil {
+		sentAt := time.Now()
+		delivery.Status = outbound.AnnouncementSent
+		delivery.SentAt = &sentAt
+		delivery.LastError = ""
+		return delivery
+	}
+
+	delivery.LastError = truncate(err.Error(), maxAnnouncementError)
+	if delivery.Attempts >= maxAnnouncementAttempts {
+		delivery.Status = outbound.AnnouncementFailed
+		s.logger.Error("Recipe announcement failed",
+			zap.String("recipe_id", delivery.RecipeID.String()),
+			zap.String("channel", delivery.Channel),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(err),
+		)
+		return delivery
+	}
+
+	delivery.NextAttemptAt = now.Add(announcementBackoff(delivery.Attempts))
+	s.logger.Warn("Recipe announcement will be retried",
+		zap.String("recipe_id", delivery.RecipeID.String()),
+		zap.String("channel", delivery.Channel),
+		zap.Int("attempts", delivery.Attempts),
+		zap.Time("next_attempt_at", delivery.NextAttemptAt),
+		zap.Error(err),
+	)
+	return delivery
+}
+
+// publish makes the recipe public, saves it, queues it for the duplicate
+// check and queues an announcement on every configured channel
+func (s *RecipeService) publish(ctx context.Context, entity *recipe.Recipe) error {
+	if err := entity.Publish(); err != nil {
+		return publishError(err, "failed to publish recipe")
+	}
+	if err := s.save(ctx, entity); err != nil {
+		return err
+	}
+
+	if s.duplicates != nil {
+		if err := s.duplicates.EnqueueCheck(ctx, entity.ID(), time.Now()); err != nil {
+			// As with announcements, a missed check must not undo the publish
+			s.logger.Error("Failed to queue duplicate check",
+				zap.String("recipe_id", entity.ID().String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if s.announcements == nil || len(s.posters) == 0 {
+		return nil
+	}
+	now := time.Now()
+	deliveries := make([]outbound.AnnouncementDelivery, len(s.posters))
+	for i, poster := range s.posters {
+		deliveries[i] = outbound.AnnouncementDelivery{
+			ID:            uuid.New(),
+			RecipeID:      entity.ID(),
+			Channel:       poster.Channel(),
+			Status:        outbound.AnnouncementPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+	}
+	if err := s.announcements.Enqueue(ctx, deliveries); err != nil {
+		// The recipe is live; a missed announcement must not undo that
+		s.logger.Error("Failed to queue recipe announcements",
+			zap.String("recipe_id", entity.ID().String()),
+			zap.Error(err),
+		)
+	}
+
+	return nil
+}
+
+// buildAnnouncement describes a published recipe for posters; nil when the
+// recipe was deleted or unpublished before its announcement went out
+func (s *RecipeService) buildAnnouncement(ctx context.Context, recipeID uuid.UUID) (*outbound.RecipeAnnouncement, error) {
+	entity, err := s.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil || entity.Status() != recipe.RecipeStatusPublished {
+		return nil, nil
+	}
+
+	announcement := &outbound.RecipeAnnouncement{
+		RecipeID:    entity.ID(),
+		Title:       entity.Title(),
+		Description: entity.Description(),
+		Tags:        entity.Tags(),
+		PublishedAt: entity.UpdatedAt(),
+	}
+	if published := entity.PublishedAt(); published != nil {
+		announcement.PublishedAt = *published
+	}
+	for _, image := range entity.Images() {
+		if announcement.ImageURL == "" || image.IsPrimary {
+			announcement.ImageURL = image.URL
+		}
+	}
+
+	author, err := s.userRepo.FindByID(ctx, entity.AuthorID())
+	if err != nil {
+		return nil, errors.NewDatabaseError("find author", err)
+	}
+	if author != nil {
+		announcement.AuthorName = author.Name()
+	}
+
+	return announcement, nil
+}
+
+// authorRecipe loads a recipe the user is changing and checks they wrote it
+func (s *RecipeService) authorRecipe(ctx context.Context, recipeID, userID uuid.UUID, action string) (*recipe.Recipe, error) {
+	entity, err := s.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil {
+		return nil, errors.NewRecipeNotFoundError(recipeID.String())
+	}
+	if entity.AuthorID() != userID {
+		return nil, errors.NewInsufficientPermissionsError(action)
+	}
+	return entity, nil
+}
+
+// save stores the recipe and dispatches its pending events
+func (s *RecipeService) save(ctx context.Context, entity *recipe.Recipe) error {
+	if err := s.recipeRepo.Update(ctx, entity); err != nil {
+		return errors.NewDatabaseError("update recipe status", err)
+	}
+	s.invalidateRecipeCache(entity.ID())
+	s.recordChanges(ctx, entity)
+
+	for _, event := range entity.Events() {
+		if err := s.publishEvent(ctx, event); err != nil {
+			s.logger.Error("Failed to publish event",
+				zap.String("event", event.EventName()),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// announcementBackoff is the delay before the next attempt after the given
+// number of failures
+func announcementBackoff(attempts int) time.Duration {
+	delay := announcementRetryBase
+	for i := 1; i < attempts && delay < announcementRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > announcementRetryMax {
+		delay = announcementRetryMax
+	}
+	return delay
+}
+
+// publishError reports broken publishing rules as bad requests
+func publishError(err error, message string) error {
+	for _, rule := range publishRuleErrors {
+		if stderrors.Is(err, rule) {
+			return errors.NewBadRequestError(err.Error())
+		}
+	}
+	return errors.Wrap(err, message)
+}
@@ -0,0 +1,223 @@
+package recipe
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubScheduledRecipes struct {
+	stubPublishedRecipes
+	updates int
+}
+
+func (s *stubScheduledRecipes) Update(ctx context.Context, r *recipe.Recipe) error {
+	s.updates++
+	return nil
+}
+
+func (s *stubScheduledRecipes) FindScheduledDue(ctx context.Context, before time.Time, limit int) ([]*recipe.Recipe, error) {
+	var due []*recipe.Recipe
+	for _, r := range s.recipes {
+		if r.ScheduledPublishAt() != nil && !r.ScheduledPublishAt().After(before) {
+			due = append(due, r)
+		}
+	}
+	return due, nil
+}
+
+type stubAnnouncements struct {
+	deliveries []outbound.AnnouncementDelivery
+}
+
+func (s *stubAnnouncements) Enqueue(ctx context.Context, deliveries []outbound.AnnouncementDelivery) error {
+	s.deliveries = append(s.deliveries, deliveries...)
+	return nil
+}
+
+func (s *stubAnnouncements) FindDue(ctx context.Context, now time.Time, limit int) ([]outbound.AnnouncementDelivery, error) {
+	var due []outbound.AnnouncementDelivery
+	for _, d := range s.deliveries {
+		if d.Status == outbound.AnnouncementPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (s *stubAnnouncements) Update(ctx context.Context, delivery outbound.AnnouncementDelivery) error {
+	for i := range s.deliveries {
+		if s.deliveries[i].ID == delivery.ID {
+			s.deliveries[i] = delivery
+		}
+	}
+	return nil
+}
+
+func (s *stubAnnouncements) CountPending(ctx context.Context) (int64, error) {
+	var pending int64
+	for _, d := range s.deliveries {
+		if d.Status == outbound.AnnouncementPending {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+type stubPoster struct {
+	channel string
+	fail    bool
+	posts   []outbound.RecipeAnnouncement
+}
+
+func (s *stubPoster) Channel() string {
+	return s.channel
+}
+
+func (s *stubPoster) Post(ctx context.Context, announcement outbound.RecipeAnnouncement) error {
+	if s.fail {
+		return stderrors.New("503 from channel")
+	}
+	s.posts = append(s.posts, announcement)
+	return nil
+}
+
+type stubCache struct {
+	outbound.CacheRepository
+}
+
+func (stubCache) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func newPublishingFixture(t *testing.T) (*RecipeService, *stubScheduledRecipes, *stubAnnouncements, *stubPoster, *user.User) {
+	t.Helper()
+	now := time.Now()
+	author := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+
+	recipes := &stubScheduledRecipes{}
+	announcements := &stubAnnouncements{}
+	poster := &stubPoster{channel: "mastodon"}
+	svc := &RecipeService{
+		recipeRepo:    recipes,
+		userRepo:      &stubUsers{users: map[uuid.UUID]*user.User{author.ID(): author}},
+		cache:         stubCache{},
+		announcements: announcements,
+		posters:       []outbound.RecipePoster{poster},
+		logger:        zap.NewNop(),
+	}
+	return svc, recipes, announcements, poster, author
+}
+
+func newReadyDraft(t *testing.T, authorID uuid.UUID) *recipe.Recipe {
+	t.Helper()
+	entity, err := recipe.NewRecipe("Lemon Bars", "Bright, buttery squares.", authorID)
+	require.NoError(t, err)
+	require.NoError(t, entity.AddIngredient(recipe.Ingredient{Name: "lemons", Amount: 3, Unit: recipe.MeasurementUnitPiece}))
+	require.NoError(t, entity.AddInstruction(recipe.Instruction{Description: "Bake."}))
+	require.NoError(t, entity.SetServings(9))
+	return entity
+}
+
+func TestSchedulePublish(t *testing.T) {
+	svc, recipes, _, _, author := newPublishingFixture(t)
+	ready := newReadyDraft(t, author.ID())
+	empty, err := recipe.NewRecipe("Empty Draft", "", author.ID())
+	require.NoError(t, err)
+	recipes.recipes = []*recipe.Recipe{ready, empty}
+	publishAt := time.Now().Add(48 * time.Hour)
+
+	_, err = svc.SchedulePublish(context.Background(), inbound.SchedulePublishCommand{RecipeID: empty.ID(), UserID: author.ID(), PublishAt: publishAt})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest), "a draft that cannot publish cannot be scheduled")
+
+	_, err = svc.SchedulePublish(context.Background(), inbound.SchedulePublishCommand{RecipeID: ready.ID(), UserID: author.ID(), PublishAt: time.Now().Add(-time.Minute)})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	_, err = svc.SchedulePublish(context.Background(), inbound.SchedulePublishCommand{RecipeID: ready.ID(), UserID: uuid.New(), PublishAt: publishAt})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+
+	dto, err := svc.SchedulePublish(context.Background(), inbound.SchedulePublishCommand{RecipeID: ready.ID(), UserID: author.ID(), PublishAt: publishAt})
+	require.NoError(t, err)
+	require.NotNil(t, dto.ScheduledPublishAt)
+	assert.Equal(t, publishAt.UTC().Format(time.RFC3339), *dto.ScheduledPublishAt)
+	assert.Equal(t, recipe.RecipeStatusDraft, dto.Status)
+
+	dto, err = svc.CancelScheduledPublish(context.Background(), ready.ID(), author.ID())
+	require.NoError(t, err)
+	assert.Nil(t, dto.ScheduledPublishAt)
+
+	_, err = svc.CancelScheduledPublish(context.Background(), ready.ID(), author.ID())
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
+
+func TestPublishDueRecipesQueuesAnnouncements(t *testing.T) {
+	svc, recipes, announcements, poster, author := newPublishingFixture(t)
+	due := newReadyDraft(t, author.ID())
+	due.SetTags([]string{"dessert"})
+	later := newReadyDraft(t, author.ID())
+	recipes.recipes = []*recipe.Recipe{due, later}
+
+	require.NoError(t, due.SchedulePublish(time.Now().Add(10*time.Millisecond)))
+	require.NoError(t, later.SchedulePublish(time.Now().Add(time.Hour)))
+	time.Sleep(20 * time.Millisecond)
+
+	published, err := svc.PublishDueRecipes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, recipe.RecipeStatusPublished, due.Status())
+	assert.Nil(t, due.ScheduledPublishAt())
+	assert.Equal(t, recipe.RecipeStatusDraft, later.Status())
+
+	require.Len(t, announcements.deliveries, 1)
+	assert.Equal(t, "mastodon", announcements.deliveries[0].Channel)
+	assert.Equal(t, due.ID(), announcements.deliveries[0].RecipeID)
+
+	sent, err := svc.DeliverAnnouncements(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, poster.posts, 1)
+	assert.Equal(t, "Lemon Bars", poster.posts[0].Title)
+	assert.Equal(t, "Ada", poster.posts[0].AuthorName)
+	assert.Equal(t, []string{"dessert"}, poster.posts[0].Tags)
+	assert.Equal(t, outbound.AnnouncementSent, announcements.deliveries[0].Status)
+}
+
+func TestDeliverAnnouncementsRetriesWithBackoff(t *testing.T) {
+	svc, recipes, announcements, poster, author := newPublishingFixture(t)
+	entity := newReadyDraft(t, author.ID())
+	recipes.recipes = []*recipe.Recipe{entity}
+	require.NoError(t, svc.PublishRecipe(context.Background(), entity.ID(), author.ID()))
+	require.Len(t, announcements.deliveries, 1)
+
+	poster.fail = true
+	for attempt := 1; attempt <= maxAnnouncementAttempts; attempt++ {
+		announcements.deliveries[0].NextAttemptAt = time.Now()
+		sent, err := svc.DeliverAnnouncements(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, sent)
+
+		delivery := announcements.deliveries[0]
+		assert.Equal(t, attempt, delivery.Attempts)
+		assert.Equal(t, "503 from channel", delivery.LastError)
+		if attempt < maxAnnouncementAttempts {
+			assert.Equal(t, outbound.AnnouncementPending, delivery.Status)
+			assert.WithinDuration(t, time.Now().Add(announcementBackoff(attempt)), delivery.NextAttemptAt, time.Second)
+		}
+	}
+	assert.Equal(t, outbound.AnnouncementFailed, announcements.deliveries[0].Status)
+
+	assert.Equal(t, time.Minute, announcementBackoff(1))
+	assert.Equal(t, 4*time.Minute, announcementBackoff(3))
+	assert.Equal(t, time.Hour, announcementBackoff(20))
+}
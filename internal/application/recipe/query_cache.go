@@ -0,0 +1,120 @@
+// Package recipe provides the in-memory cache for hot recipe queries
+package recipe
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+)
+
+const (
+	// queryCacheTTL bounds how stale a cached query can be if an
+	// invalidation is missed
+	queryCacheTTL = 5 * time.Minute
+	// queryCacheMaxEntries stops unusual searches from growing the cache
+	queryCacheMaxEntries = 500
+	// searchFacetLimit is how many values of each facet are offered
+	searchFacetLimit = 20
+	// invalidationTimeout bounds broadcasting a change to the other
+	// replicas; those that miss it fall back on queryCacheTTL
+	invalidationTimeout = 2 * time.Second
+)
+
+// queryCache keeps the results of recent list, search and facet queries.
+// Any recipe change clears it, so entries are only ever as old as the last
+// write or the TTL. A nil cache caches nothing.
+type queryCache struct {
+	mu      sync.Mutex
+	entries map[string]queryCacheEntry
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+type queryCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newQueryCache() *queryCache {
+	return &queryCache{
+		entries: make(map[string]queryCacheEntry),
+		ttl:     queryCacheTTL,
+		now:     time.Now,
+	}
+}
+
+func (c *queryCache) get(key string) (interface{}, bool) {
+	if c == nil || key == "" {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *queryCache) set(key string, value interface{}) {
+	if c == nil || key == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= queryCacheMaxEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= queryCacheMaxEntries {
+			return
+		}
+	}
+	c.entries[key] = queryCacheEntry{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *queryCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.entries = make(map[string]queryCacheEntry)
+	c.mu.Unlock()
+}
+
+// queryCacheKey identifies a query by its kind and parameters
+func queryCacheKey(kind string, params interface{}) string {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+	return kind + ":" + string(encoded)
+}
+
+// copyRecipeList lets callers re-rank and annotate a list without touching
+// the cached copy
+func copyRecipeList(list *inbound.RecipeList) *inbound.RecipeList {
+	copied := *list
+	copied.Recipes = append([]inbound.RecipeDTO(nil), list.Recipes...)
+	return &copied
+}
+
+func facetCounts(counts []outbound.FacetCount) []inbound.FacetCount {
+	facets := make([]inbound.FacetCount, len(counts))
+	for i, count := range counts {
+		facets[i] = inbound.FacetCount{Value: count.Value, Count: count.Count}
+	}
+	return facets
+}
@@ -0,0 +1,84 @@
+package recipe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type countingSearchRecipes struct {
+	stubScheduledRecipes
+	searches int
+}
+
+func (s *countingSearchRecipes) Search(ctx context.Context, criteria outbound.SearchCriteria) ([]*recipe.Recipe, int, error) {
+	s.searches++
+	return s.recipes, len(s.recipes), nil
+}
+
+func TestSearchRecipesServesCachedPage(t *testing.T) {
+	entity, err := recipe.NewRecipe("Lemon Bars", "Bright, buttery squares.", uuid.New())
+	require.NoError(t, err)
+	recipes := &countingSearchRecipes{}
+	recipes.recipes = []*recipe.Recipe{entity}
+	views := &stubViewCounter{}
+	svc := &RecipeService{recipeRepo: recipes, cache: stubCache{}, queries: newQueryCache(), views: views, logger: zap.NewNop()}
+	home := inbound.SearchQuery{Pagination: inbound.PaginationParams{Page: 0, PageSize: 20}}
+
+	_, err = svc.SearchRecipes(context.Background(), home)
+	require.NoError(t, err)
+	explained := home
+	explained.Explain = true
+	list, err := svc.SearchRecipes(context.Background(), explained)
+	require.NoError(t, err)
+	assert.NotEmpty(t, list.Explanations)
+	assert.Equal(t, 1, recipes.searches, "the same search is read from the cache")
+
+	list, err = svc.SearchRecipes(context.Background(), home)
+	require.NoError(t, err)
+	assert.Empty(t, list.Explanations, "annotations must not leak into the cached page")
+	assert.Len(t, list.Recipes, 1)
+
+	_, err = svc.SearchRecipes(context.Background(), inbound.SearchQuery{Text: "lemon", Pagination: home.Pagination})
+	require.NoError(t, err)
+	assert.Equal(t, 2, recipes.searches)
+	assert.Equal(t, 1, views.impressions, "only text searches list recipes")
+
+	require.NoError(t, svc.save(context.Background(), entity))
+	_, err = svc.SearchRecipes(context.Background(), home)
+	require.NoError(t, err)
+	assert.Equal(t, 3, recipes.searches, "a recipe change clears the cache")
+}
+
+func TestQueryCacheExpiresAndStaysBounded(t *testing.T) {
+	now := time.Now()
+	cache := newQueryCache()
+	cache.now = func() time.Time { return now }
+
+	cache.set("a", 1)
+	value, ok := cache.get("a")
+	require.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	now = now.Add(queryCacheTTL)
+	_, ok = cache.get("a")
+	assert.False(t, ok)
+
+	for i := 0; i < queryCacheMaxEntries+10; i++ {
+		cache.set(queryCacheKey("search", i), i)
+	}
+	assert.Len(t, cache.entries, queryCacheMaxEntries)
+
+	var disabled *queryCache
+	disabled.set("a", 1)
+	_, ok = disabled.get("a")
+	assert.False(t, ok)
+}
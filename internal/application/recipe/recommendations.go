@@ -0,0 +1,139 @@
+// Package recipe provides the "Recipes you might like" list and the
+// recipes similar to one
+package recipe
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// personalRecommendations pages through the user's precomputed
+// recommendations, explaining each. It returns nil before a refresh has
+// covered the user.
+func (s *RecipeService) personalRecommendations(ctx context.Context, userID uuid.UUID, page inbound.PaginationParams) (*inbound.RecipeList, error) {
+	if s.recommendations == nil {
+		return nil, nil
+	}
+	recommendations, err := s.recommendations.For(ctx, userID)
+	if err != nil {
+		return nil, errors.NewExternalServiceError("recommendations", err)
+	}
+	if len(recommendations) == 0 {
+		return nil, nil
+	}
+
+	start := page.Page * page.PageSize
+	if start > len(recommendations) {
+		start = len(recommendations)
+	}
+	end := start + page.PageSize
+	if page.PageSize <= 0 || end > len(recommendations) {
+		end = len(recommendations)
+	}
+	ids := make([]uuid.UUID, 0, end-start)
+	for _, rec := range recommendations[start:end] {
+		ids = append(ids, rec.RecipeID)
+	}
+	dtos, err := s.recipesInOrder(ctx, ids, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]inbound.Recommendation, len(ids))
+	for _, rec := range recommendations[start:end] {
+		byID[rec.RecipeID] = rec
+	}
+	explanations := make([]inbound.RankingExplanation, len(dtos))
+	for i, dto := range dtos {
+		rec := byID[dto.ID]
+		explanations[i] = inbound.RankingExplanation{
+			RecipeID:     dto.ID,
+			OriginalRank: start + i + 1,
+			Rank:         start + i + 1,
+			Score:        rec.Score,
+			Factors:      rec.Factors,
+		}
+	}
+
+	total := len(recommendations)
+	list := &inbound.RecipeList{
+		Recipes:      dtos,
+		Total:        total,
+		Page:         page.Page,
+		PageSize:     page.PageSize,
+		Personalized: true,
+		Explanations: explanations,
+	}
+	if page.PageSize > 0 {
+		list.TotalPages = (total + page.PageSize - 1) / page.PageSize
+	}
+	return list, nil
+}
+
+// GetSimilarRecipes lists the published recipes sharing the most cuisine,
+// tags and ingredients with a recipe, each explained by what they share
+func (s *RecipeService) GetSimilarRecipes(ctx context.Context, recipeID uuid.UUID, limit int) (*inbound.RecipeList, error) {
+	target, err := s.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if target == nil {
+		return nil, errors.NewRecipeNotFoundError(recipeID.String())
+	}
+	list := &inbound.RecipeList{Recipes: []inbound.RecipeDTO{}, Explanations: []inbound.RankingExplanation{}}
+	if s.recommendations == nil {
+		return list, nil
+	}
+
+	similar, err := s.recommendations.Similar(ctx, recipeID, limit)
+	if err != nil {
+		return nil, errors.NewExternalServiceError("recommendations", err)
+	}
+	ids := make([]uuid.UUID, len(similar))
+	byID := make(map[uuid.UUID]inbound.Recommendation, len(similar))
+	for i, rec := range similar {
+		ids[i] = rec.RecipeID
+		byID[rec.RecipeID] = rec
+	}
+	dtos, err := s.recipesInOrder(ctx, ids, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, dto := range dtos {
+		rec := byID[dto.ID]
+		list.Explanations = append(list.Explanations, inbound.RankingExplanation{
+			RecipeID:     dto.ID,
+			OriginalRank: i + 1,
+			Rank:         i + 1,
+			Score:        rec.Score,
+			Factors:      rec.Factors,
+		})
+	}
+	list.Recipes = dtos
+	list.Total = len(dtos)
+	list.PageSize = len(dtos)
+	list.TotalPages = 1
+	return list, nil
+}
+
+// dropStaleExplanations keeps the explanations of the recipes still
+// listed, after hidden gems took some of the slots
+func dropStaleExplanations(list *inbound.RecipeList) {
+	if len(list.Explanations) == 0 {
+		return
+	}
+	listed := make(map[uuid.UUID]bool, len(list.Recipes))
+	for _, dto := range list.Recipes {
+		listed[dto.ID] = true
+	}
+	kept := list.Explanations[:0]
+	for _, explanation := range list.Explanations {
+		if listed[explanation.RecipeID] {
+			kept = append(kept, explanation)
+		}
+	}
+	list.Explanations = kept
+}
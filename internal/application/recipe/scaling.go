@@ -0,0 +1,105 @@
+package recipe
+
+import (
+	"context"
+	"math"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/units"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// MaxScaledServings caps the serving selector; bigger batches are what
+// kitchen tickets are for
+const MaxScaledServings = 100
+
+// ScaleRecipe recomputes a recipe's ingredients for the requested servings,
+// in the units asked for or the requester's measurement preference.
+// Amounts move to the unit they read best in and round to what a cook can
+// measure. Ingredients without an amount, like salt to taste, stay as they
+// are.
+func (s *RecipeService) ScaleRecipe(ctx context.Context, query inbound.ScaleRecipeQuery) (*inbound.ScaledRecipe, error) {
+	if query.Servings < 0 || query.Servings > MaxScaledServings {
+		return nil, errors.NewBadRequestError("servings must be between 1 and 100")
+	}
+	system, err := s.measuringSystem(ctx, query.RequesterID, query.Units)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := s.recipeRepo.FindByID(ctx, query.RecipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil || (entity.Status() != recipe.RecipeStatusPublished && entity.AuthorID() != query.RequesterID) {
+		return nil, errors.NewRecipeNotFoundError(query.RecipeID.String())
+	}
+
+	original := entity.Servings()
+	if original <= 0 {
+		original = 1
+	}
+	servings := query.Servings
+	if servings == 0 {
+		servings = original
+	}
+	factor := float64(servings) / float64(original)
+
+	result := &inbound.ScaledRecipe{
+		RecipeID:         entity.ID(),
+		Title:            entity.Title(),
+		Servings:         servings,
+		OriginalServings: original,
+		Scale:            math.Round(factor*1000) / 1000,
+		Units:            systemName(system),
+		Ingredients:      make([]inbound.ScaledIngredient, len(entity.Ingredients())),
+	}
+	for i, ingredient := range entity.Ingredients() {
+		scaled := inbound.ScaledIngredient{
+			ID:       ingredient.ID,
+			Name:     ingredient.Name,
+			Optional: ingredient.Optional,
+			Notes:    ingredient.Notes,
+		}
+		if ingredient.Amount > 0 {
+			q := units.Quantity{Amount: ingredient.Amount * factor, Unit: ingredient.Unit}.In(system)
+			scaled.Amount = q.Amount
+			scaled.Unit = string(q.Unit)
+			scaled.Quantity = q.String()
+		}
+		result.Ingredients[i] = scaled
+	}
+	return result, nil
+}
+
+// measuringSystem picks the units to show a recipe in: those asked for,
+// else the signed-in requester's preference, else as written
+func (s *RecipeService) measuringSystem(ctx context.Context, requesterID uuid.UUID, requested string) (units.System, error) {
+	if requested != "" {
+		system, ok := units.ParseSystem(requested)
+		if !ok {
+			return units.SystemAsWritten, errors.NewBadRequestError("units must be one of metric, imperial or original")
+		}
+		return system, nil
+	}
+	if requesterID == uuid.Nil {
+		return units.SystemAsWritten, nil
+	}
+	requester, err := s.userRepo.FindByID(ctx, requesterID)
+	if err != nil || requester == nil {
+		// The preference only changes presentation
+		return units.SystemAsWritten, nil
+	}
+	system, _ := units.ParseSystem(string(requester.MeasurementSystem()))
+	return system, nil
+}
+
+// systemName is how responses name a measuring system
+func systemName(system units.System) string {
+	if system == units.SystemAsWritten {
+		return "original"
+	}
+	return string(system)
+}
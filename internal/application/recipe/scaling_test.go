@@ -0,0 +1,62 @@
+package recipe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestScaleRecipeNormalizesUnits(t *testing.T) {
+	now := time.Now()
+	ada := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	author := ada.ID()
+	draft := newReadyDraft(t, author)
+	require.NoError(t, draft.AddIngredient(recipe.Ingredient{Name: "sugar", Amount: 0.5, Unit: recipe.MeasurementUnitCup}))
+
+	svc := &RecipeService{
+		recipeRepo: &stubPublishedRecipes{recipes: []*recipe.Recipe{draft}},
+		userRepo:   &stubUsers{users: map[uuid.UUID]*user.User{author: ada}},
+		logger:     zap.NewNop(),
+	}
+	ctx := context.Background()
+
+	scaled, err := svc.ScaleRecipe(ctx, inbound.ScaleRecipeQuery{RequesterID: author, RecipeID: draft.ID(), Servings: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 9, scaled.OriginalServings)
+	assert.Equal(t, 0.333, scaled.Scale)
+	require.Len(t, scaled.Ingredients, 2)
+	assert.Equal(t, "1 piece", scaled.Ingredients[0].Quantity)
+	assert.Equal(t, "2 2/3 tbsp", scaled.Ingredients[1].Quantity)
+	assert.Equal(t, "tbsp", scaled.Ingredients[1].Unit)
+
+	scaled, err = svc.ScaleRecipe(ctx, inbound.ScaleRecipeQuery{RequesterID: author, RecipeID: draft.ID()})
+	require.NoError(t, err)
+	assert.Equal(t, 9, scaled.Servings)
+	assert.Equal(t, "1/2 cup", scaled.Ingredients[1].Quantity)
+	assert.Equal(t, "original", scaled.Units, "without a preference recipes stay as written")
+
+	ada.SetMeasurementSystem(user.MeasurementSystemMetric)
+	scaled, err = svc.ScaleRecipe(ctx, inbound.ScaleRecipeQuery{RequesterID: author, RecipeID: draft.ID()})
+	require.NoError(t, err)
+	assert.Equal(t, "metric", scaled.Units)
+	assert.Equal(t, "120 ml", scaled.Ingredients[1].Quantity)
+
+	scaled, err = svc.ScaleRecipe(ctx, inbound.ScaleRecipeQuery{RequesterID: author, RecipeID: draft.ID(), Units: "original"})
+	require.NoError(t, err)
+	assert.Equal(t, "1/2 cup", scaled.Ingredients[1].Quantity, "asking for units overrides the preference")
+
+	_, err = svc.ScaleRecipe(ctx, inbound.ScaleRecipeQuery{RecipeID: draft.ID(), Servings: 3})
+	assert.True(t, errors.Is(err, errors.CodeRecipeNotFound), "drafts are only scaled for their author")
+
+	_, err = svc.ScaleRecipe(ctx, inbound.ScaleRecipeQuery{RequesterID: author, RecipeID: draft.ID(), Servings: MaxScaledServings + 1})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
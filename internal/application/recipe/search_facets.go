@@ -0,0 +1,55 @@
+package recipe
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+)
+
+// searchTimeLimits are the total times in minutes offered as the max time
+// facet
+var searchTimeLimits = []int{15, 30, 60, 120}
+
+// searchDiets are the tags offered as the diet facet, in display order
+var searchDiets = user.DietaryRestrictions
+
+// resultFacets counts the results of a search per filter value. Facets do
+// not depend on the page, so every page of a search shares one entry.
+func (s *RecipeService) resultFacets(ctx context.Context, criteria outbound.SearchCriteria) (*inbound.ResultFacets, error) {
+	criteria.Offset, criteria.Limit, criteria.OrderBy, criteria.OrderDir = 0, 0, "", ""
+	cacheKey := queryCacheKey("result-facets", criteria)
+	if cached, ok := s.queries.get(cacheKey); ok && criteria.FavoritedBy == nil {
+		return cached.(*inbound.ResultFacets), nil
+	}
+
+	counts, err := s.recipeRepo.FindResultFacets(ctx, criteria, searchTimeLimits)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find result facets", err)
+	}
+
+	tagCounts := make(map[string]int, len(counts.Tags))
+	for _, tag := range counts.Tags {
+		tagCounts[tag.Value] = tag.Count
+	}
+	diets := []inbound.FacetCount{}
+	for _, diet := range searchDiets {
+		if count := tagCounts[string(diet)]; count > 0 {
+			diets = append(diets, inbound.FacetCount{Value: string(diet), Count: count})
+		}
+	}
+
+	facets := &inbound.ResultFacets{
+		Cuisines:     facetCounts(counts.Cuisines),
+		Difficulties: facetCounts(counts.Difficulties),
+		MaxTime:      facetCounts(counts.MaxTimes),
+		Diets:        diets,
+		AIGenerated:  facetCounts(counts.AIGenerated),
+	}
+	if criteria.FavoritedBy == nil {
+		s.queries.set(cacheKey, facets)
+	}
+	return facets, nil
+}
@@ -0,0 +1,123 @@
+// Package recipe provides the fallback for searches that find nothing
+package recipe
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxSearchSuggestions caps the corrections offered for one query
+	maxSearchSuggestions = 3
+	// maxStoredQueryLength matches the zero_result_searches column width
+	maxStoredQueryLength = 255
+	// maxZeroResultQueries bounds one analytics report
+	maxZeroResultQueries = 200
+)
+
+// zeroResultFallback asks the AI layer for query corrections and records the
+// miss for taxonomy analysis. Neither step can fail the search itself.
+func (s *RecipeService) zeroResultFallback(ctx context.Context, query inbound.SearchQuery) *inbound.SearchFallback {
+	text := strings.TrimSpace(query.Text)
+	fallback := &inbound.SearchFallback{
+		Query:          text,
+		Suggestions:    []string{},
+		GeneratePrompt: text,
+	}
+
+	if s.aiService != nil {
+		suggestions, err := s.aiService.SuggestSearchQueries(ctx, text)
+		if err != nil {
+			s.logger.Warn("Search suggestions unavailable", zap.String("query", text), zap.Error(err))
+		} else {
+			fallback.Suggestions = cleanSuggestions(text, suggestions)
+		}
+	}
+
+	if s.searchAnalytics != nil {
+		search := outbound.ZeroResultSearch{
+			Query:           truncateQuery(text),
+			NormalizedQuery: normalizeQuery(text),
+			UserID:          query.UserID,
+			Suggestions:     fallback.Suggestions,
+			SearchedAt:      time.Now(),
+		}
+		if err := s.searchAnalytics.RecordZeroResult(ctx, search); err != nil {
+			s.logger.Warn("Failed to record zero-result search", zap.String("query", text), zap.Error(err))
+		}
+	}
+
+	return fallback
+}
+
+// GetZeroResultQueries reports the most frequent zero-result queries of the
+// last days so admins can add missing tags, cuisines and synonyms
+func (s *RecipeService) GetZeroResultQueries(ctx context.Context, requesterID uuid.UUID, days, limit int) ([]inbound.ZeroResultQuery, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "view search analytics"); err != nil {
+		return nil, err
+	}
+
+	if days <= 0 {
+		days = 30
+	}
+	if limit <= 0 || limit > maxZeroResultQueries {
+		limit = maxZeroResultQueries
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	stats, err := s.searchAnalytics.TopZeroResultQueries(ctx, since, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find zero-result searches", err)
+	}
+
+	queries := make([]inbound.ZeroResultQuery, len(stats))
+	for i, stat := range stats {
+		queries[i] = inbound.ZeroResultQuery{
+			Query:    stat.Query,
+			Count:    stat.Count,
+			LastSeen: stat.LastSeen.Format(time.RFC3339),
+		}
+	}
+
+	return queries, nil
+}
+
+// cleanSuggestions drops blanks, duplicates and echoes of the original query
+func cleanSuggestions(query string, suggestions []string) []string {
+	seen := map[string]bool{normalizeQuery(query): true}
+	cleaned := []string{}
+	for _, suggestion := range suggestions {
+		suggestion = strings.TrimSpace(suggestion)
+		key := normalizeQuery(suggestion)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, suggestion)
+		if len(cleaned) == maxSearchSuggestions {
+			break
+		}
+	}
+	return cleaned
+}
+
+// normalizeQuery folds case and whitespace so "Vegan  Tacos" and "vegan tacos"
+// aggregate together
+func normalizeQuery(query string) string {
+	return truncateQuery(strings.Join(strings.Fields(strings.ToLower(query)), " "))
+}
+
+func truncateQuery(query string) string {
+	if runes := []rune(query); len(runes) > maxStoredQueryLength {
+		return string(runes[:maxStoredQueryLength])
+	}
+	return query
+}
@@ -0,0 +1,70 @@
+package recipe
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubSearchAI struct {
+	outbound.AIService
+	suggestions []string
+	err         error
+}
+
+func (s *stubSearchAI) SuggestSearchQueries(ctx context.Context, query string) ([]string, error) {
+	return s.suggestions, s.err
+}
+
+type stubSearchAnalytics struct {
+	outbound.SearchAnalyticsRepository
+	recorded []outbound.ZeroResultSearch
+}
+
+func (s *stubSearchAnalytics) RecordZeroResult(ctx context.Context, search outbound.ZeroResultSearch) error {
+	s.recorded = append(s.recorded, search)
+	return nil
+}
+
+func TestZeroResultFallbackSuggestsAndRecords(t *testing.T) {
+	analytics := &stubSearchAnalytics{}
+	svc := &RecipeService{
+		aiService:       &stubSearchAI{suggestions: []string{"Vegan tacos", " vegan  TACOS ", "", "Tofu Tacos", "Tacos al pastor", "Bean burrito"}},
+		searchAnalytics: analytics,
+		logger:          zap.NewNop(),
+	}
+	userID := uuid.New()
+
+	fallback := svc.zeroResultFallback(context.Background(), inbound.SearchQuery{Text: "  Vegn Tacos ", UserID: &userID})
+
+	assert.Equal(t, "Vegn Tacos", fallback.Query)
+	assert.Equal(t, "Vegn Tacos", fallback.GeneratePrompt)
+	assert.Equal(t, []string{"Vegan tacos", "Tofu Tacos", "Tacos al pastor"}, fallback.Suggestions)
+
+	require.Len(t, analytics.recorded, 1)
+	assert.Equal(t, "vegn tacos", analytics.recorded[0].NormalizedQuery)
+	assert.Equal(t, &userID, analytics.recorded[0].UserID)
+	assert.Equal(t, fallback.Suggestions, analytics.recorded[0].Suggestions)
+}
+
+func TestZeroResultFallbackSurvivesAIFailure(t *testing.T) {
+	analytics := &stubSearchAnalytics{}
+	svc := &RecipeService{
+		aiService:       &stubSearchAI{err: stderrors.New("model offline")},
+		searchAnalytics: analytics,
+		logger:          zap.NewNop(),
+	}
+
+	fallback := svc.zeroResultFallback(context.Background(), inbound.SearchQuery{Text: "quinoa"})
+
+	assert.Empty(t, fallback.Suggestions)
+	assert.Equal(t, "quinoa", fallback.GeneratePrompt)
+	assert.Len(t, analytics.recorded, 1)
+}
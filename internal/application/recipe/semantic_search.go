@@ -0,0 +1,156 @@
+package recipe
+
+import (
+	"context"
+	"sort"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// semanticCandidates is how many recipes each of the vector and the
+	// keyword search contribute to a search by meaning
+	semanticCandidates = 100
+	// meaningWeight is the share of a recipe's score that comes from its
+	// similarity; the rest comes from its keyword rank
+	meaningWeight = 0.7
+)
+
+// searchByMeaning ranks the recipes most alike the search text together
+// with its keyword matches. It returns nil, to search by keyword alone,
+// when the text cannot be embedded or no recipe has a vector yet.
+func (s *RecipeService) searchByMeaning(ctx context.Context, criteria outbound.SearchCriteria, page inbound.PaginationParams) (*inbound.RecipeList, error) {
+	if s.embeddings == nil || s.aiService == nil {
+		return nil, nil
+	}
+	embedded, err := s.aiService.Embed(ctx, []string{criteria.Query})
+	if err != nil || len(embedded.Vectors) != 1 {
+		s.logger.Warn("Search by meaning unavailable, searching by keyword", zap.Error(err))
+		return nil, nil
+	}
+
+	nearest, err := s.embeddings.FindNearest(ctx, criteria, embedded.Model, embedded.Vectors[0], semanticCandidates)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find similar recipes", err)
+	}
+	if len(nearest) == 0 {
+		return nil, nil
+	}
+
+	keyword := criteria
+	keyword.Offset, keyword.Limit, keyword.OrderBy = 0, semanticCandidates, "relevance"
+	matches, _, err := s.recipeRepo.Search(ctx, keyword)
+	if err != nil {
+		return nil, errors.NewDatabaseError("search recipes", err)
+	}
+	keywordIDs := make([]uuid.UUID, len(matches))
+	for i, r := range matches {
+		keywordIDs[i] = r.ID()
+	}
+
+	ranked := blendRankings(nearest, keywordIDs, meaningWeight)
+	start := criteria.Offset
+	if start > len(ranked) {
+		start = len(ranked)
+	}
+	end := start + criteria.Limit
+	if criteria.Limit <= 0 || end > len(ranked) {
+		end = len(ranked)
+	}
+	pageIDs := ranked[start:end]
+
+	dtos, err := s.recipesInOrder(ctx, pageIDs, matches)
+	if err != nil {
+		return nil, err
+	}
+	total := len(ranked)
+	list := &inbound.RecipeList{
+		Recipes:  dtos,
+		Total:    total,
+		Page:     page.Page,
+		PageSize: page.PageSize,
+		Semantic: true,
+	}
+	if page.PageSize > 0 {
+		list.TotalPages = (total + page.PageSize - 1) / page.PageSize
+	}
+	return list, nil
+}
+
+// blendRankings orders the candidates of a search by meaning. Each
+// recipe scores weight times its similarity, scaled so the least similar
+// candidate counts zero and the most similar one, plus the rest times its
+// keyword rank, from one for the best match down towards zero.
+func blendRankings(nearest []outbound.EmbeddingMatch, keyword []uuid.UUID, weight float64) []uuid.UUID {
+	scores := make(map[uuid.UUID]float64, len(nearest)+len(keyword))
+	var order []uuid.UUID
+	add := func(id uuid.UUID, score float64) {
+		if _, seen := scores[id]; !seen {
+			order = append(order, id)
+		}
+		scores[id] += score
+	}
+
+	if len(nearest) > 0 {
+		low, high := nearest[0].Similarity, nearest[0].Similarity
+		for _, match := range nearest {
+			if match.Similarity < low {
+				low = match.Similarity
+			}
+			if match.Similarity > high {
+				high = match.Similarity
+			}
+		}
+		for _, match := range nearest {
+			scaled := 1.0
+			if high > low {
+				scaled = (match.Similarity - low) / (high - low)
+			}
+			add(match.RecipeID, weight*scaled)
+		}
+	}
+	for i, id := range keyword {
+		add(id, (1-weight)*(1-float64(i)/float64(len(keyword))))
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return scores[order[i]] > scores[order[j]] })
+	return order
+}
+
+// recipesInOrder returns the recipes with ids as DTOs in the order of ids,
+// loading those not among the already loaded ones
+func (s *RecipeService) recipesInOrder(ctx context.Context, ids []uuid.UUID, loaded []*recipe.Recipe) ([]inbound.RecipeDTO, error) {
+	byID := make(map[uuid.UUID]*recipe.Recipe, len(loaded))
+	for _, r := range loaded {
+		byID[r.ID()] = r
+	}
+	var missing []uuid.UUID
+	for _, id := range ids {
+		if _, ok := byID[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		found, err := s.recipeRepo.FindByIDs(ctx, missing)
+		if err != nil {
+			return nil, errors.NewDatabaseError("find recipes", err)
+		}
+		for _, r := range found {
+			byID[r.ID()] = r
+		}
+	}
+
+	dtos := make([]inbound.RecipeDTO, 0, len(ids))
+	for _, id := range ids {
+		// A recipe unpublished since it was ranked is left out
+		if r, ok := byID[id]; ok && r.Status() == recipe.RecipeStatusPublished {
+			dtos = append(dtos, *s.entityToDTO(r))
+		}
+	}
+	return dtos, nil
+}
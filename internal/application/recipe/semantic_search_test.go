@@ -0,0 +1,26 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlendRankingsWeighsMeaningOverKeywordRank(t *testing.T) {
+	stew, soup, chili, bars := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	nearest := []outbound.EmbeddingMatch{
+		{RecipeID: stew, Similarity: 0.9},
+		{RecipeID: soup, Similarity: 0.8},
+		{RecipeID: bars, Similarity: 0.1},
+	}
+
+	// Chili only matches the words, so it ranks below stew and soup despite
+	// being the best keyword match; soup's keyword rank lifts it past stew
+	ranked := blendRankings(nearest, []uuid.UUID{chili, soup}, 0.7)
+	assert.Equal(t, []uuid.UUID{soup, stew, chili, bars}, ranked)
+
+	assert.Equal(t, []uuid.UUID{stew}, blendRankings([]outbound.EmbeddingMatch{{RecipeID: stew, Similarity: 0.3}}, nil, 0.7))
+	assert.Empty(t, blendRankings(nil, nil, 0.7))
+}
@@ -5,8 +5,15 @@ package recipe
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/alchemorsel/v3/internal/domain/notification"
+	"github.com/alchemorsel/v3/internal/domain/offline"
 	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/allergens"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+	"github.com/alchemorsel/v3/internal/domain/user"
 	"github.com/alchemorsel/v3/internal/ports/inbound"
 	"github.com/alchemorsel/v3/internal/ports/outbound"
 	"github.com/alchemorsel/v3/pkg/errors"
@@ -16,12 +23,32 @@ import (
 
 // RecipeService implements the recipe use cases
 type RecipeService struct {
-	recipeRepo outbound.RecipeRepository
-	userRepo   outbound.UserRepository
-	cache      outbound.CacheRepository
-	aiService  outbound.AIService
-	events     outbound.MessageBus
-	logger     *zap.Logger
+	recipeRepo      outbound.RecipeRepository
+	userRepo        outbound.UserRepository
+	cache           outbound.CacheRepository
+	aiService       outbound.AIService
+	events          outbound.MessageBus
+	searchAnalytics outbound.SearchAnalyticsRepository
+	viewAnalytics   outbound.RecipeAnalyticsRepository
+	counters        outbound.RecipeCounterRepository
+	views           inbound.ViewService
+	ocr             outbound.OCRService
+	imageProber     outbound.ImageProber
+	announcements   outbound.AnnouncementRepository
+	posters         []outbound.RecipePoster
+	changes         outbound.RecipeChangeRepository
+	syncFeed        outbound.SyncFeed
+	feedback        outbound.CommentFeedbackRepository
+	popularity      outbound.RecipePopularityRepository
+	invalidations   outbound.CacheInvalidationBus
+	foods           *nutritionTable
+	favorites       outbound.FavoriteRepository
+	notifications   inbound.NotificationService
+	embeddings      outbound.RecipeEmbeddingRepository
+	recommendations inbound.RecommendationService
+	duplicates      outbound.DuplicateRepository
+	queries         *queryCache
+	logger          *zap.Logger
 }
 
 // NewRecipeService creates a new recipe service
@@ -31,16 +58,61 @@ func NewRecipeService(
 	cache outbound.CacheRepository,
 	aiService outbound.AIService,
 	events outbound.MessageBus,
+	searchAnalytics outbound.SearchAnalyticsRepository,
+	viewAnalytics outbound.RecipeAnalyticsRepository,
+	counters outbound.RecipeCounterRepository,
+	views inbound.ViewService,
+	ocr outbound.OCRService,
+	imageProber outbound.ImageProber,
+	announcements outbound.AnnouncementRepository,
+	posters []outbound.RecipePoster,
+	changes outbound.RecipeChangeRepository,
+	syncFeed outbound.SyncFeed,
+	feedback outbound.CommentFeedbackRepository,
+	popularity outbound.RecipePopularityRepository,
+	invalidations outbound.CacheInvalidationBus,
+	ingredientNutrition outbound.IngredientNutritionRepository,
+	favorites outbound.FavoriteRepository,
+	notifications inbound.NotificationService,
+	embeddings outbound.RecipeEmbeddingRepository,
+	recommendations inbound.RecommendationService,
+	duplicates outbound.DuplicateRepository,
 	logger *zap.Logger,
 ) inbound.RecipeService {
-	return &RecipeService{
-		recipeRepo: recipeRepo,
-		userRepo:   userRepo,
-		cache:      cache,
-		aiService:  aiService,
-		events:     events,
-		logger:     logger.Named("recipe-service"),
+	s := &RecipeService{
+		recipeRepo:      recipeRepo,
+		userRepo:        userRepo,
+		cache:           cache,
+		aiService:       aiService,
+		events:          events,
+		searchAnalytics: searchAnalytics,
+		viewAnalytics:   viewAnalytics,
+		counters:        counters,
+		views:           views,
+		ocr:             ocr,
+		imageProber:     imageProber,
+		announcements:   announcements,
+		posters:         posters,
+		changes:         changes,
+		syncFeed:        syncFeed,
+		feedback:        feedback,
+		popularity:      popularity,
+		invalidations:   invalidations,
+		foods:           newNutritionTable(ingredientNutrition, logger.Named("nutrition")),
+		favorites:       favorites,
+		notifications:   notifications,
+		embeddings:      embeddings,
+		recommendations: recommendations,
+		duplicates:      duplicates,
+		queries:         newQueryCache(),
+		logger:          logger.Named("recipe-service"),
 	}
+	// A recipe changed on another replica can move it in or out of any
+	// cached list
+	if invalidations != nil {
+		invalidations.Subscribe("recipe:", func(string) { s.queries.clear() })
+	}
+	return s
 }
 
 // CreateRecipe creates a new recipe
@@ -64,6 +136,11 @@ func (s *RecipeService) CreateRecipe(ctx context.Context, cmd inbound.CreateReci
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create recipe entity")
 	}
+	if cmd.Language != "" {
+		if err := recipeEntity.SetLanguage(cmd.Language); err != nil {
+			return nil, errors.NewBadRequestError(err.Error())
+		}
+	}
 	
 	// Add ingredients
 	for _, ingredientCmd := range cmd.Ingredients {
@@ -99,10 +176,20 @@ func (s *RecipeService) CreateRecipe(ctx context.Context, cmd inbound.CreateReci
 		}
 	}
 	
+	// Add images
+	for _, imageURL := range cmd.Images {
+		if err := recipeEntity.AddImage(imageURL, ""); err != nil {
+			return nil, errors.NewBadRequestError(err.Error())
+		}
+	}
+	
+	s.refreshLabels(ctx, recipeEntity)
+	
 	// Save to repository
 	if err := s.recipeRepo.Create(ctx, recipeEntity); err != nil {
 		return nil, errors.NewDatabaseError("create recipe", err)
 	}
+	s.syncDraft(ctx, recipeEntity, 0)
 	
 	// Publish domain events
 	for _, event := range recipeEntity.Events() {
@@ -141,16 +228,17 @@ func (s *RecipeService) UpdateRecipe(ctx context.Context, cmd inbound.UpdateReci
 		return nil, errors.NewRecipeNotFoundError(cmd.RecipeID.String())
 	}
 	
-	// Check authorization (simplified - would use proper authorization service)
-	if recipeEntity.AuthorID() != cmd.UserID {
-		return nil, errors.NewInsufficientPermissionsError("update this recipe")
+	// Check authorization
+	if err := s.authorizeEdit(ctx, recipeEntity, cmd.UserID, "update this recipe"); err != nil {
+		return nil, err
 	}
 	
 	// Apply updates
-	if cmd.Title != nil {
-		if err := recipeEntity.UpdateTitle(*cmd.Title); err != nil {
-			return nil, errors.Wrap(err, "failed to update title")
-		}
+	if err := applyUpdate(recipeEntity, cmd); err != nil {
+		return nil, err
+	}
+	if cmd.Ingredients != nil {
+		s.refreshLabels(ctx, recipeEntity)
 	}
 	
 	// Save changes
@@ -170,6 +258,7 @@ func (s *RecipeService) UpdateRecipe(ctx context.Context, cmd inbound.UpdateReci
 	
 	// Invalidate cache
 	s.invalidateRecipeCache(cmd.RecipeID)
+	s.recordChanges(ctx, recipeEntity)
 	
 	dto := s.entityToDTO(recipeEntity)
 	
@@ -201,24 +290,9 @@ func (s *RecipeService) PublishRecipe(ctx context.Context, recipeID, userID uuid
 		return errors.NewInsufficientPermissionsError("publish this recipe")
 	}
 	
-	// Publish recipe
-	if err := recipeEntity.Publish(); err != nil {
-		return errors.Wrap(err, "failed to publish recipe")
-	}
-	
-	// Save changes
-	if err := s.recipeRepo.Update(ctx, recipeEntity); err != nil {
-		return errors.NewDatabaseError("update recipe status", err)
-	}
-	
-	// Publish events
-	for _, event := range recipeEntity.Events() {
-		if err := s.publishEvent(ctx, event); err != nil {
-			s.logger.Error("Failed to publish event",
-				zap.String("event", event.EventName()),
-				zap.Error(err),
-			)
-		}
+	// Publish, save and queue the announcements
+	if err := s.publish(ctx, recipeEntity); err != nil {
+		return err
 	}
 	
 	s.logger.Info("Recipe published successfully",
@@ -244,9 +318,9 @@ func (s *RecipeService) ArchiveRecipe(ctx context.Context, recipeID, userID uuid
 		return errors.NewRecipeNotFoundError(recipeID.String())
 	}
 	
-	// Check authorization
-	if recipeEntity.AuthorID() != userID {
-		return errors.NewInsufficientPermissionsError("archive this recipe")
+	// Authors archive their own recipes; admins take down anyone's
+	if err := s.authorizeEdit(ctx, recipeEntity, userID, "archive this recipe"); err != nil {
+		return err
 	}
 	
 	// Archive recipe
@@ -269,6 +343,10 @@ func (s *RecipeService) ArchiveRecipe(ctx context.Context, recipeID, userID uuid
 		}
 	}
 	
+	// Invalidate cache
+	s.invalidateRecipeCache(recipeID)
+	s.recordChanges(ctx, recipeEntity)
+	
 	s.logger.Info("Recipe archived successfully",
 		zap.String("recipe_id", recipeID.String()),
 	)
@@ -293,8 +371,8 @@ func (s *RecipeService) DeleteRecipe(ctx context.Context, recipeID, userID uuid.
 	}
 	
 	// Check authorization
-	if recipeEntity.AuthorID() != userID {
-		return errors.NewInsufficientPermissionsError("delete this recipe")
+	if err := s.authorizeEdit(ctx, recipeEntity, userID, "delete this recipe"); err != nil {
+		return err
 	}
 	
 	// Soft delete
@@ -304,6 +382,9 @@ func (s *RecipeService) DeleteRecipe(ctx context.Context, recipeID, userID uuid.
 	
 	// Invalidate cache
 	s.invalidateRecipeCache(recipeID)
+	if s.syncFeed != nil {
+		s.syncFeed.Remove(ctx, recipeEntity.AuthorID(), offline.KindDraft, recipeID.String())
+	}
 	
 	s.logger.Info("Recipe deleted successfully",
 		zap.String("recipe_id", recipeID.String()),
@@ -312,6 +393,88 @@ func (s *RecipeService) DeleteRecipe(ctx context.Context, recipeID, userID uuid.
 	return nil
 }
 
+// RestoreRecipe reverses a soft delete
+func (s *RecipeService) RestoreRecipe(ctx context.Context, recipeID, userID uuid.UUID) error {
+	s.logger.Info("Restoring recipe",
+		zap.String("recipe_id", recipeID.String()),
+		zap.String("user_id", userID.String()),
+	)
+	
+	// Load deleted recipe
+	recipeEntity, err := s.recipeRepo.FindDeletedByID(ctx, recipeID)
+	if err != nil {
+		return errors.NewDatabaseError("find deleted recipe", err)
+	}
+	if recipeEntity == nil {
+		return errors.NewRecipeNotFoundError(recipeID.String())
+	}
+	
+	// Check authorization
+	if err := s.authorizeEdit(ctx, recipeEntity, userID, "restore this recipe"); err != nil {
+		return err
+	}
+	
+	if err := s.recipeRepo.Restore(ctx, recipeID); err != nil {
+		return errors.NewDatabaseError("restore recipe", err)
+	}
+	
+	// Invalidate cache
+	s.invalidateRecipeCache(recipeID)
+	
+	s.logger.Info("Recipe restored successfully",
+		zap.String("recipe_id", recipeID.String()),
+	)
+	
+	return nil
+}
+
+// UnarchiveRecipe publishes an archived recipe again
+func (s *RecipeService) UnarchiveRecipe(ctx context.Context, recipeID, userID uuid.UUID) error {
+	s.logger.Info("Unarchiving recipe",
+		zap.String("recipe_id", recipeID.String()),
+		zap.String("user_id", userID.String()),
+	)
+	
+	// Load recipe
+	recipeEntity, err := s.recipeRepo.FindByID(ctx, recipeID)
+	if err != nil {
+		return errors.NewDatabaseError("find recipe", err)
+	}
+	if recipeEntity == nil {
+		return errors.NewRecipeNotFoundError(recipeID.String())
+	}
+	
+	// Check authorization
+	if recipeEntity.AuthorID() != userID {
+		return errors.NewInsufficientPermissionsError("unarchive this recipe")
+	}
+	
+	if err := recipeEntity.Unarchive(); err != nil {
+		return errors.Wrap(err, "failed to unarchive recipe")
+	}
+	
+	// Save changes
+	if err := s.recipeRepo.Update(ctx, recipeEntity); err != nil {
+		return errors.NewDatabaseError("update recipe status", err)
+	}
+	
+	// Publish events
+	for _, event := range recipeEntity.Events() {
+		if err := s.publishEvent(ctx, event); err != nil {
+			s.logger.Error("Failed to publish event",
+				zap.String("event", event.EventName()),
+				zap.Error(err),
+			)
+		}
+	}
+	
+	// Invalidate cache
+	s.invalidateRecipeCache(recipeID)
+	s.recordChanges(ctx, recipeEntity)
+	
+	return nil
+}
+
 // LikeRecipe likes a recipe
 func (s *RecipeService) LikeRecipe(ctx context.Context, recipeID, userID uuid.UUID) error {
 	s.logger.Info("Liking recipe",
@@ -331,11 +494,28 @@ func (s *RecipeService) LikeRecipe(ctx context.Context, recipeID, userID uuid.UU
 	// Like recipe
 	recipeEntity.Like(userID)
 	
-	// Save changes
-	if err := s.recipeRepo.Update(ctx, recipeEntity); err != nil {
+	// Count the like on a counter shard rather than saving the recipe, so
+	// likes on a popular recipe do not contend for its row
+	if err := s.counters.Increment(ctx, recipeID, outbound.RecipeCounterLikes, 1); err != nil {
 		return errors.NewDatabaseError("update recipe likes", err)
 	}
 	
+	// Like history feeds search personalization; losing it isn't fatal
+	if err := s.recipeRepo.AddLike(ctx, recipeID, userID); err != nil {
+		s.logger.Warn("Failed to record like history",
+			zap.String("recipe_id", recipeID.String()),
+			zap.Error(err),
+		)
+	}
+	
+	s.notify(ctx, inbound.NotifyCommand{
+		UserID:  recipeEntity.AuthorID(),
+		ActorID: &userID,
+		Type:    notification.TypeRecipeLiked,
+		Subject: recipeEntity.Title(),
+		Link:    "/recipes/" + recipeID.String(),
+	})
+	
 	// Publish events
 	for _, event := range recipeEntity.Events() {
 		if err := s.publishEvent(ctx, event); err != nil {
@@ -409,6 +589,12 @@ func (s *RecipeService) GetRecipeByID(ctx context.Context, recipeID uuid.UUID) (
 	}
 	
 	dto := s.entityToDTO(recipeEntity)
+	if dto.Revision, err = s.latestRevision(ctx, recipeID); err != nil {
+		return nil, err
+	}
+	if dto.ForkedFromID != nil {
+		dto.ForkedFrom = s.recipeOrigin(ctx, *dto.ForkedFromID)
+	}
 	
 	// Cache the result
 	s.cacheRecipe(ctx, dto)
@@ -416,6 +602,26 @@ func (s *RecipeService) GetRecipeByID(ctx context.Context, recipeID uuid.UUID) (
 	return dto, nil
 }
 
+// GetRecipesByIDs retrieves several recipes in one repository round trip.
+// Missing IDs are silently skipped; callers compare against the request.
+func (s *RecipeService) GetRecipesByIDs(ctx context.Context, recipeIDs []uuid.UUID) ([]inbound.RecipeDTO, error) {
+	if len(recipeIDs) == 0 {
+		return []inbound.RecipeDTO{}, nil
+	}
+	
+	recipes, err := s.recipeRepo.FindByIDs(ctx, recipeIDs)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipes by ids", err)
+	}
+	
+	recipeDTOs := make([]inbound.RecipeDTO, len(recipes))
+	for i, r := range recipes {
+		recipeDTOs[i] = *s.entityToDTO(r)
+	}
+	
+	return recipeDTOs, nil
+}
+
 // GetRecipesByUser retrieves recipes by user
 func (s *RecipeService) GetRecipesByUser(ctx context.Context, userID uuid.UUID, params inbound.PaginationParams) (*inbound.RecipeList, error) {
 	// Validate user exists
@@ -450,46 +656,194 @@ func (s *RecipeService) GetRecipesByUser(ctx context.Context, userID uuid.UUID,
 
 // SearchRecipes searches recipes
 func (s *RecipeService) SearchRecipes(ctx context.Context, query inbound.SearchQuery) (*inbound.RecipeList, error) {
+	text, favorited := favoritedFilter(query.Text)
+	query.Text = text
+	if (favorited || query.Favorited) && query.UserID == nil {
+		return nil, errors.NewUnauthorizedError("Sign in to search your saved recipes")
+	}
+	
+	// The requester's diets narrow their searches and their allergies are
+	// flagged on the results; a search of saved recipes is not narrowed
+	var requester *user.User
+	if query.UserID != nil {
+		found, err := s.userRepo.FindByID(ctx, *query.UserID)
+		if err != nil {
+			s.logger.Warn("Search dietary profile unavailable",
+				zap.String("user_id", query.UserID.String()),
+				zap.Error(err),
+			)
+		} else {
+			requester = found
+		}
+	}
+	dietary, defaultDiets := query.Dietary, false
+	if !favorited && !query.Favorited {
+		dietary, defaultDiets = searchDietary(query, requester)
+	}
+	
 	// Convert to repository search criteria
 	criteria := outbound.SearchCriteria{
 		Query:      query.Text,
 		Cuisines:   query.Cuisine,
 		Categories: query.Category,
 		Difficulty: query.Difficulty,
-		MaxTime:    &query.MaxTime,
 		Tags:       query.Tags,
+		Dietary:    dietary,
+		AIGenerated: query.AIGenerated,
 		Offset:     query.Pagination.Page * query.Pagination.PageSize,
 		Limit:      query.Pagination.PageSize,
 		OrderBy:    query.Pagination.OrderBy,
 		OrderDir:   query.Pagination.Order,
 	}
+	keyset := outbound.KeysetOrders[criteria.OrderBy] && !query.Semantic
+	if keyset {
+		criteria.Offset = 0
+		if query.After != "" {
+			after, err := decodeCursor(query.After)
+			if err != nil {
+				return nil, err
+			}
+			criteria.After = after
+		}
+	} else if query.After != "" {
+		return nil, errors.NewBadRequestError("after needs a sort of trending, top, new, rating or prep_time")
+	}
 	
-	// Search recipes
-	recipes, total, err := s.recipeRepo.Search(ctx, criteria)
-	if err != nil {
-		return nil, errors.NewDatabaseError("search recipes", err)
+	if query.MaxTime > 0 {
+		criteria.MaxTime = &query.MaxTime
+	}
+	if favorited || query.Favorited {
+		criteria.FavoritedBy = query.UserID
 	}
 	
-	// Convert to DTOs
-	recipeDTOs := make([]inbound.RecipeDTO, len(recipes))
-	for i, r := range recipes {
-		recipeDTOs[i] = *s.entityToDTO(r)
+	// The unpersonalized page is shared by everyone running the same
+	// search; a search of saved recipes is the user's own and changes as
+	// they save more, so it is not cached
+	var list *inbound.RecipeList
+	semantic := query.Semantic && strings.TrimSpace(criteria.Query) != ""
+	cacheKey := queryCacheKey("search", criteria)
+	if semantic {
+		cacheKey = queryCacheKey("semantic", criteria)
+	}
+	if cached, ok := s.queries.get(cacheKey); ok && criteria.FavoritedBy == nil {
+		list = copyRecipeList(cached.(*inbound.RecipeList))
+	} else {
+		if semantic {
+			var err error
+			list, err = s.searchByMeaning(ctx, criteria, query.Pagination)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if list == nil && keyset {
+			var err error
+			list, err = s.searchAfter(ctx, criteria)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if list == nil {
+			recipes, total, err := s.recipeRepo.Search(ctx, criteria)
+			if err != nil {
+				return nil, errors.NewDatabaseError("search recipes", err)
+			}
+			
+			// Convert to DTOs
+			recipeDTOs := make([]inbound.RecipeDTO, len(recipes))
+			for i, r := range recipes {
+				recipeDTOs[i] = *s.entityToDTO(r)
+			}
+			
+			list = &inbound.RecipeList{
+				Recipes:    recipeDTOs,
+				Total:      total,
+				Page:       query.Pagination.Page,
+				PageSize:   query.Pagination.PageSize,
+				TotalPages: (total + query.Pagination.PageSize - 1) / query.Pagination.PageSize,
+			}
+		}
+		// A keyword fallback is not cached as the semantic page, so the
+		// search is ranked by meaning once the vectors are back
+		if criteria.FavoritedBy == nil && list.Semantic == semantic {
+			s.queries.set(cacheKey, copyRecipeList(list))
+		}
+	}
+	total := list.Total
+	
+	// Personalization re-ranks the fetched page; failures fall back to the
+	// unpersonalized order rather than failing the search. A page the
+	// caller asked to sort keeps that order.
+	var profile *searchProfile
+	if query.Personalize && query.UserID != nil && !explicitSort(query.Pagination.OrderBy) {
+		var err error
+		profile, err = s.loadSearchProfile(ctx, *query.UserID)
+		if err != nil {
+			s.logger.Warn("Search personalization unavailable",
+				zap.String("user_id", query.UserID.String()),
+				zap.Error(err),
+			)
+			profile = nil
+		}
+		if profile != nil && profile.empty() {
+			profile = nil
+		}
 	}
 	
-	return &inbound.RecipeList{
-		Recipes:    recipeDTOs,
-		Total:      total,
-		Page:       query.Pagination.Page,
-		PageSize:   query.Pagination.PageSize,
-		TotalPages: (total + query.Pagination.PageSize - 1) / query.Pagination.PageSize,
-	}, nil
+	if profile != nil || query.Explain {
+		ranked, explanations := rankRecipes(list.Recipes, profile)
+		list.Recipes = ranked
+		list.Personalized = profile != nil
+		if query.Explain {
+			list.Explanations = explanations
+		}
+	}
+	
+	if defaultDiets {
+		list.DefaultDiets = dietary
+	}
+	if requester != nil && requester.Preferences() != nil {
+		for i := range list.Recipes {
+			list.Recipes[i].AllergyWarnings = allergyWarnings(&list.Recipes[i], requester.Preferences().Allergies)
+		}
+	}
+	
+	if total == 0 && strings.TrimSpace(query.Text) != "" {
+		list.Fallback = s.zeroResultFallback(ctx, query)
+	}
+	
+	// A text search lists each recipe on the page for the author analytics;
+	// browsing without one does not
+	if strings.TrimSpace(query.Text) != "" && len(list.Recipes) > 0 {
+		ids := make([]uuid.UUID, len(list.Recipes))
+		for i, r := range list.Recipes {
+			ids[i] = r.ID
+		}
+		if err := s.views.CountImpressions(ctx, ids); err != nil {
+			s.logger.Warn("Failed to count search impressions", zap.Error(err))
+		}
+	}
+	
+	if query.Facets {
+		facets, err := s.resultFacets(ctx, criteria)
+		if err != nil {
+			return nil, err
+		}
+		list.Facets = facets
+	}
+	
+	return list, nil
 }
 
 // GetTrendingRecipes retrieves trending recipes
 func (s *RecipeService) GetTrendingRecipes(ctx context.Context, params inbound.PaginationParams) (*inbound.RecipeList, error) {
-	// This would implement trending logic
-	// For now, just return published recipes
-	recipes, total, err := s.recipeRepo.FindPublished(ctx, params.Page*params.PageSize, params.PageSize)
+	cacheKey := queryCacheKey("trending", params)
+	if cached, ok := s.queries.get(cacheKey); ok {
+		return copyRecipeList(cached.(*inbound.RecipeList)), nil
+	}
+	
+	// Ranked by decayed popularity, so recipes that were viral long ago
+	// give way to recent favourites
+	recipes, total, err := s.recipeRepo.FindPopular(ctx, params.Page*params.PageSize, params.PageSize)
 	if err != nil {
 		return nil, errors.NewDatabaseError("find trending recipes", err)
 	}
@@ -500,20 +854,61 @@ func (s *RecipeService) GetTrendingRecipes(ctx context.Context, params inbound.P
 		recipeDTOs[i] = *s.entityToDTO(r)
 	}
 	
-	return &inbound.RecipeList{
+	list := &inbound.RecipeList{
 		Recipes:    recipeDTOs,
 		Total:      total,
 		Page:       params.Page,
 		PageSize:   params.PageSize,
 		TotalPages: (total + params.PageSize - 1) / params.PageSize,
-	}, nil
+	}
+	s.queries.set(cacheKey, copyRecipeList(list))
+	
+	return list, nil
+}
+
+// GetSearchFacets lists the cuisines, categories, difficulties and tags
+// offered as search filters, most used first
+func (s *RecipeService) GetSearchFacets(ctx context.Context) (*inbound.SearchFacets, error) {
+	cacheKey := queryCacheKey("facets", searchFacetLimit)
+	if cached, ok := s.queries.get(cacheKey); ok {
+		return cached.(*inbound.SearchFacets), nil
+	}
+	
+	counts, err := s.recipeRepo.FindSearchFacets(ctx, searchFacetLimit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find search facets", err)
+	}
+	
+	facets := &inbound.SearchFacets{
+		Cuisines:     facetCounts(counts.Cuisines),
+		Categories:   facetCounts(counts.Categories),
+		Difficulties: facetCounts(counts.Difficulties),
+		Tags:         facetCounts(counts.Tags),
+	}
+	s.queries.set(cacheKey, facets)
+	
+	return facets, nil
 }
 
 // GetRecommendedRecipes retrieves recommended recipes for a user
 func (s *RecipeService) GetRecommendedRecipes(ctx context.Context, userID uuid.UUID, params inbound.PaginationParams) (*inbound.RecipeList, error) {
-	// This would implement recommendation logic
-	// For now, just return trending recipes
-	return s.GetTrendingRecipes(ctx, params)
+	// The user's precomputed recommendations, or trending recipes until a
+	// refresh has covered them, with hidden gems rotated into some of the
+	// slots on the first page
+	list, err := s.personalRecommendations(ctx, userID, params)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		if list, err = s.GetTrendingRecipes(ctx, params); err != nil {
+			return nil, err
+		}
+	}
+	if params.Page == 0 {
+		s.slotHiddenGems(ctx, userID, list)
+		dropStaleExplanations(list)
+	}
+	return list, nil
 }
 
 // AI Operations
@@ -539,6 +934,7 @@ func (s *RecipeService) GenerateRecipeWithAI(ctx context.Context, cmd inbound.Ge
 		MaxCalories: cmd.MaxCalories,
 		Dietary:     cmd.Dietary,
 		Cuisine:     string(cmd.Cuisine),
+		Language:    cmd.Language,
 	}
 	
 	aiResponse, err := s.aiService.GenerateRecipe(ctx, cmd.Prompt, constraints)
@@ -552,8 +948,35 @@ func (s *RecipeService) GenerateRecipeWithAI(ctx context.Context, cmd inbound.Ge
 		return nil, errors.Wrap(err, "failed to create AI recipe entity")
 	}
 	
-	// Add AI-generated content
-	// This would set AI-specific fields
+	// Record the language the model wrote in, which may differ from the
+	// one asked for
+	language := aiResponse.Language
+	if language == "" {
+		language = cmd.Language
+	}
+	if language != "" {
+		if err := recipeEntity.SetLanguage(language); err != nil {
+			return nil, errors.NewBadRequestError(err.Error())
+		}
+	}
+	
+	// Add AI-generated ingredients, normalizing amounts and units the model
+	// returned as free text
+	for _, aiIngredient := range aiResponse.Ingredients {
+		parsed, err := parseAIIngredient(aiIngredient)
+		if err != nil {
+			s.logger.Warn("Skipping unparseable AI ingredient",
+				zap.String("name", aiIngredient.Name),
+				zap.Error(err),
+			)
+			continue
+		}
+		if err := recipeEntity.AddIngredient(parsed.Ingredient()); err != nil {
+			return nil, errors.Wrap(err, "failed to add AI ingredient")
+		}
+	}
+	
+	s.refreshLabels(ctx, recipeEntity)
 	
 	// Save to repository
 	if err := s.recipeRepo.Create(ctx, recipeEntity); err != nil {
@@ -562,6 +985,13 @@ func (s *RecipeService) GenerateRecipeWithAI(ctx context.Context, cmd inbound.Ge
 	
 	dto := s.entityToDTO(recipeEntity)
 	
+	s.notify(ctx, inbound.NotifyCommand{
+		UserID:  cmd.UserID,
+		Type:    notification.TypeAIRecipeReady,
+		Subject: dto.Title,
+		Link:    "/recipes/" + dto.ID.String(),
+	})
+	
 	s.logger.Info("AI recipe generated successfully",
 		zap.String("recipe_id", dto.ID.String()),
 		zap.String("title", dto.Title),
@@ -570,6 +1000,21 @@ func (s *RecipeService) GenerateRecipeWithAI(ctx context.Context, cmd inbound.Ge
 	return dto, nil
 }
 
+// notify sends an in-app notification. Failures are logged: the action
+// that caused it already succeeded.
+func (s *RecipeService) notify(ctx context.Context, cmd inbound.NotifyCommand) {
+	if s.notifications == nil {
+		return
+	}
+	if err := s.notifications.Notify(ctx, cmd); err != nil {
+		s.logger.Warn("Failed to send notification",
+			zap.String("user_id", cmd.UserID.String()),
+			zap.String("type", string(cmd.Type)),
+			zap.Error(err),
+		)
+	}
+}
+
 // SuggestIngredientSubstitutes suggests ingredient substitutes
 func (s *RecipeService) SuggestIngredientSubstitutes(ctx context.Context, ingredientID uuid.UUID) ([]inbound.IngredientDTO, error) {
 	// This would implement ingredient substitution logic
@@ -586,12 +1031,122 @@ func (s *RecipeService) AnalyzeNutrition(ctx context.Context, recipeID uuid.UUID
 
 // entityToDTO converts domain entity to DTO
 func (s *RecipeService) entityToDTO(entity *recipe.Recipe) *inbound.RecipeDTO {
-	return &inbound.RecipeDTO{
-		ID:          entity.ID(),
-		Title:       entity.Title(),
-		// Map other fields...
-		// This would be a comprehensive mapping
+	dto := &inbound.RecipeDTO{
+		ID:           entity.ID(),
+		Title:        entity.Title(),
+		Description:  entity.Description(),
+		AuthorID:     entity.AuthorID(),
+		Language:     entity.Language(),
+		Ingredients:  make([]inbound.IngredientDTO, len(entity.Ingredients())),
+		Instructions: make([]inbound.InstructionDTO, len(entity.Instructions())),
+		Cuisine:      entity.Cuisine(),
+		Category:     entity.Category(),
+		Difficulty:   entity.Difficulty(),
+		PrepTime:     int(entity.PrepTime().Minutes()),
+		CookTime:     int(entity.CookTime().Minutes()),
+		TotalTime:    int(entity.TotalTime().Minutes()),
+		Servings:     entity.Servings(),
+		Calories:     entity.Calories(),
+		Tags:         entity.Tags(),
+		Images:       make([]inbound.ImageDTO, len(entity.Images())),
+		Likes:        entity.Likes(),
+		Views:        entity.Views(),
+		Forks:        entity.Forks(),
+		Rating:       entity.AverageRating(),
+		RatingCount:  len(entity.Ratings()),
+		Status:       entity.Status(),
+		AIGenerated:  entity.IsAIGenerated(),
+		CreatedAt:    entity.CreatedAt().Format(time.RFC3339),
+		UpdatedAt:    entity.UpdatedAt().Format(time.RFC3339),
+		ForkedFromID: entity.ForkedFromID(),
 	}
+	
+	for i, ing := range entity.Ingredients() {
+		dto.Ingredients[i] = inbound.IngredientDTO{
+			ID:       ing.ID,
+			Name:     ing.Name,
+			Amount:   ing.Amount,
+			Unit:     ing.Unit,
+			Optional: ing.Optional,
+			Notes:    ing.Notes,
+		}
+	}
+	
+	for i, inst := range entity.Instructions() {
+		dto.Instructions[i] = inbound.InstructionDTO{
+			StepNumber:  inst.StepNumber,
+			Description: inst.Description,
+			Duration:    int(inst.Duration.Minutes()),
+			Images:      inst.Images,
+		}
+		if inst.Temperature != nil {
+			dto.Instructions[i].Temperature = &inbound.TemperatureDTO{
+				Value: inst.Temperature.Value,
+				Unit:  inst.Temperature.Unit,
+			}
+		}
+	}
+	
+	for i, img := range entity.Images() {
+		dto.Images[i] = inbound.ImageDTO{
+			ID:           img.ID,
+			URL:          img.URL,
+			ThumbnailURL: img.ThumbnailURL,
+			Caption:      img.Caption,
+			IsPrimary:    img.IsPrimary,
+		}
+	}
+	
+	if n := entity.NutritionInfo(); n != nil {
+		dto.Nutrition = &inbound.NutritionDTO{
+			Calories:      n.Calories,
+			Protein:       n.Protein,
+			Carbohydrates: n.Carbohydrates,
+			Fat:           n.Fat,
+			Fiber:         n.Fiber,
+			Sugar:         n.Sugar,
+			Sodium:        n.Sodium,
+			Cholesterol:   n.Cholesterol,
+			Coverage:      n.Coverage,
+		}
+	}
+	
+	// Recipes saved before labelling get theirs worked out when the
+	// ingredients were read
+	label := entity.Allergens()
+	if label == nil && len(entity.Ingredients()) > 0 {
+		label = allergens.Label(entity.Ingredients())
+	}
+	if label != nil {
+		dto.Allergens = &inbound.AllergenLabelDTO{Contains: label.Contains, MayContain: label.MayContain}
+	}
+	
+	if published := entity.PublishedAt(); published != nil {
+		formatted := published.Format(time.RFC3339)
+		dto.PublishedAt = &formatted
+	}
+	
+	if scheduled := entity.ScheduledPublishAt(); scheduled != nil {
+		formatted := scheduled.Format(time.RFC3339)
+		dto.ScheduledPublishAt = &formatted
+	}
+	
+	return dto
+}
+
+// parseAIIngredient normalizes an AI ingredient through the shared line
+// parser. Models often put the whole line ("2 cups flour") in the name or
+// spell units out ("Tablespoons"), so the name is tried on its own first.
+func parseAIIngredient(ai outbound.AIIngredient) (ingredients.Parsed, error) {
+	if parsed, err := ingredients.Parse(ai.Name); err == nil && parsed.Amount > 0 {
+		return parsed, nil
+	}
+	
+	line := strings.TrimSpace(ai.Unit + " " + ai.Name)
+	if ai.Amount > 0 {
+		line = ingredients.FormatAmount(ai.Amount) + " " + line
+	}
+	return ingredients.Parse(line)
 }
 
 // publishEvent publishes a domain event
@@ -621,8 +1176,20 @@ func (s *RecipeService) cacheRecipe(ctx context.Context, recipe *inbound.RecipeD
 	s.cache.Set(ctx, key, []byte{}, 3600) // 1 hour
 }
 
-// invalidateRecipeCache invalidates recipe cache
+// invalidateRecipeCache invalidates recipe cache and the cached queries,
+// since any change can move a recipe in or out of a list, then tells the
+// other replicas to do the same
 func (s *RecipeService) invalidateRecipeCache(recipeID uuid.UUID) {
 	key := fmt.Sprintf("recipe:%s", recipeID.String())
 	s.cache.Delete(context.Background(), key)
+	s.queries.clear()
+	
+	if s.invalidations == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), invalidationTimeout)
+	defer cancel()
+	if err := s.invalidations.Publish(ctx, key); err != nil {
+		s.logger.Warn("Failed to broadcast recipe invalidation", zap.String("key", key), zap.Error(err))
+	}
 }
\ No newline at end of file
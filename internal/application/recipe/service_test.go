@@ -0,0 +1,33 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAIIngredient(t *testing.T) {
+	// Structured output with a spelled-out unit
+	parsed, err := parseAIIngredient(outbound.AIIngredient{Name: "olive oil", Amount: 2, Unit: "Tablespoons"})
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, parsed.Amount)
+	assert.Equal(t, recipe.MeasurementUnitTablespoon, parsed.Unit)
+	assert.Equal(t, "olive oil", parsed.Name)
+
+	// The whole line stuffed into the name
+	parsed, err = parseAIIngredient(outbound.AIIngredient{Name: "1 1/2 cups diced carrots"})
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, parsed.Amount)
+	assert.Equal(t, recipe.MeasurementUnitCup, parsed.Unit)
+	assert.Equal(t, "carrots", parsed.Name)
+	assert.Equal(t, "diced", parsed.Notes)
+
+	// Amount without a unit
+	parsed, err = parseAIIngredient(outbound.AIIngredient{Name: "eggs", Amount: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, parsed.Amount)
+	assert.Empty(t, parsed.Unit)
+}
@@ -0,0 +1,197 @@
+// Package recipe provides the structured data check for recipe rich results
+package recipe
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/richresults"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxStructuredDataRecipes bounds one report over published recipes
+	maxStructuredDataRecipes = 5000
+	// structuredDataPageSize is the repository page size while collecting
+	structuredDataPageSize = 200
+	// imageProbeWorkers limits concurrent image fetches
+	imageProbeWorkers = 8
+)
+
+// ValidateStructuredData builds each recipe's JSON-LD and reports what keeps
+// it from rich results. Authors may check their own recipes, admins any.
+func (s *RecipeService) ValidateStructuredData(ctx context.Context, query inbound.StructuredDataQuery) (*inbound.StructuredDataReport, error) {
+	requester, err := s.userRepo.FindByID(ctx, query.RequesterID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find user", err)
+	}
+	if requester == nil {
+		return nil, errors.NewUserNotFoundError(query.RequesterID.String())
+	}
+	isAdmin := requester.Role() == user.UserRoleAdmin
+
+	var recipes []*recipe.Recipe
+	if query.RecipeID != nil {
+		entity, err := s.recipeRepo.FindByID(ctx, *query.RecipeID)
+		if err != nil {
+			return nil, errors.NewDatabaseError("find recipe", err)
+		}
+		if entity == nil {
+			return nil, errors.NewRecipeNotFoundError(query.RecipeID.String())
+		}
+		if !isAdmin && entity.AuthorID() != requester.ID() {
+			return nil, errors.NewInsufficientPermissionsError("check this recipe")
+		}
+		recipes = []*recipe.Recipe{entity}
+	} else {
+		recipes, err = s.publishedRecipesFor(ctx, requester.ID(), isAdmin)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var sizes map[string]richresults.ImageSize
+	if query.CheckImages && s.imageProber != nil {
+		sizes = s.probeImages(ctx, recipes)
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Wrap(err, "structured data check interrupted")
+		}
+	}
+
+	authors := map[uuid.UUID]string{requester.ID(): requester.Name()}
+	report := &inbound.StructuredDataReport{
+		GeneratedAt: time.Now().Format(time.RFC3339),
+		Recipes:     make([]inbound.RecipeStructuredData, 0),
+	}
+	for _, entity := range recipes {
+		doc := richresults.Build(entity, richresults.Options{AuthorName: s.authorName(ctx, authors, entity.AuthorID())})
+		issues := richresults.Validate(doc, sizes)
+
+		result := inbound.RecipeStructuredData{
+			RecipeID: entity.ID(),
+			Title:    entity.Title(),
+			Eligible: richresults.Eligible(issues),
+			Issues:   make([]inbound.StructuredDataIssue, len(issues)),
+		}
+		for i, issue := range issues {
+			result.Issues[i] = inbound.StructuredDataIssue{
+				Field:    issue.Field,
+				Severity: string(issue.Severity),
+				Message:  issue.Message,
+			}
+		}
+
+		report.Checked++
+		if result.Eligible {
+			report.Eligible++
+		}
+		if query.RecipeID != nil {
+			result.Document = doc
+		} else if len(issues) == 0 {
+			continue
+		}
+		report.Recipes = append(report.Recipes, result)
+	}
+
+	s.logger.Info("Structured data checked",
+		zap.String("requester_id", requester.ID().String()),
+		zap.Int("checked", report.Checked),
+		zap.Int("eligible", report.Eligible),
+	)
+
+	return report, nil
+}
+
+// publishedRecipesFor collects the published recipes a report covers: every
+// one for admins, the requester's own otherwise
+func (s *RecipeService) publishedRecipesFor(ctx context.Context, requesterID uuid.UUID, isAdmin bool) ([]*recipe.Recipe, error) {
+	var recipes []*recipe.Recipe
+	for offset := 0; offset < maxStructuredDataRecipes; offset += structuredDataPageSize {
+		page, total, err := s.recipeRepo.FindPublished(ctx, offset, structuredDataPageSize)
+		if err != nil {
+			return nil, errors.NewDatabaseError("find published recipes", err)
+		}
+		for _, entity := range page {
+			if isAdmin || entity.AuthorID() == requesterID {
+				recipes = append(recipes, entity)
+			}
+		}
+		if len(page) < structuredDataPageSize || offset+len(page) >= total {
+			break
+		}
+	}
+	return recipes, nil
+}
+
+// probeImages fetches the dimensions of every distinct image URL. Fetch
+// failures are reported on the image; undecodable formats stay unknown.
+func (s *RecipeService) probeImages(ctx context.Context, recipes []*recipe.Recipe) map[string]richresults.ImageSize {
+	urls := make(chan string)
+	go func() {
+		defer close(urls)
+		seen := make(map[string]bool)
+		for _, entity := range recipes {
+			for _, image := range entity.Images() {
+				if seen[image.URL] {
+					continue
+				}
+				seen[image.URL] = true
+				select {
+				case urls <- image.URL:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		sizes = make(map[string]richresults.ImageSize)
+	)
+	for i := 0; i < imageProbeWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range urls {
+				var size richresults.ImageSize
+				info, err := s.imageProber.ProbeImage(ctx, url)
+				if err != nil {
+					size.Error = err.Error()
+				} else {
+					size.Width, size.Height = info.Width, info.Height
+				}
+				mu.Lock()
+				sizes[url] = size
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return sizes
+}
+
+// authorName resolves and caches author display names; a missing author
+// leaves the field out so the check reports it
+func (s *RecipeService) authorName(ctx context.Context, cache map[uuid.UUID]string, authorID uuid.UUID) string {
+	if name, ok := cache[authorID]; ok {
+		return name
+	}
+	name := ""
+	author, err := s.userRepo.FindByID(ctx, authorID)
+	if err != nil {
+		s.logger.Warn("Failed to load recipe author", zap.String("author_id", authorID.String()), zap.Error(err))
+	} else if author != nil {
+		name = author.Name()
+	}
+	cache[authorID] = name
+	return name
+}
@@ -0,0 +1,130 @@
+package recipe
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+type stubPublishedRecipes struct {
+	outbound.RecipeRepository
+	recipes []*recipe.Recipe
+}
+
+func (s *stubPublishedRecipes) FindByID(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error) {
+	for _, r := range s.recipes {
+		if r.ID() == id {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubPublishedRecipes) FindPublished(ctx context.Context, offset, limit int) ([]*recipe.Recipe, int, error) {
+	if offset >= len(s.recipes) {
+		return nil, len(s.recipes), nil
+	}
+	end := offset + limit
+	if end > len(s.recipes) {
+		end = len(s.recipes)
+	}
+	return s.recipes[offset:end], len(s.recipes), nil
+}
+
+type stubProber struct {
+	sizes map[string]outbound.ImageInfo
+}
+
+func (s *stubProber) ProbeImage(ctx context.Context, url string) (*outbound.ImageInfo, error) {
+	info, ok := s.sizes[url]
+	if !ok {
+		return nil, stderrors.New("status 404")
+	}
+	return &info, nil
+}
+
+func newStructuredDataFixture(t *testing.T) (svc *RecipeService, author, admin *user.User, own, other *recipe.Recipe) {
+	t.Helper()
+	now := time.Now()
+	author = user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	admin = user.ReconstructUser(uuid.New(), "root@example.com", "Root", "", true, true, user.UserRoleAdmin, now, now, nil)
+
+	own, err := recipe.NewRecipe("Lemon Bars", "Bright, buttery squares.", author.ID())
+	require.NoError(t, err)
+	require.NoError(t, own.AddIngredient(recipe.Ingredient{Name: "lemons", Amount: 3, Unit: recipe.MeasurementUnitPiece}))
+	require.NoError(t, own.AddInstruction(recipe.Instruction{Description: "Bake."}))
+	require.NoError(t, own.AddImage("https://cdn.example.com/lemon.jpg", ""))
+	require.NoError(t, own.AddImage("https://cdn.example.com/missing.jpg", ""))
+
+	other, err = recipe.NewRecipe("Someone Else's Soup", "", uuid.New())
+	require.NoError(t, err)
+
+	svc = &RecipeService{
+		recipeRepo: &stubPublishedRecipes{recipes: []*recipe.Recipe{own, other}},
+		userRepo:   &stubUsers{users: map[uuid.UUID]*user.User{author.ID(): author, admin.ID(): admin}},
+		imageProber: &stubProber{sizes: map[string]outbound.ImageInfo{
+			"https://cdn.example.com/lemon.jpg": {Width: 1200, Height: 1200},
+		}},
+		logger: zap.NewNop(),
+	}
+	return svc, author, admin, own, other
+}
+
+func TestValidateStructuredDataForOwnRecipe(t *testing.T) {
+	svc, author, _, lemonBars, _ := newStructuredDataFixture(t)
+	recipeID := lemonBars.ID()
+
+	report, err := svc.ValidateStructuredData(context.Background(), inbound.StructuredDataQuery{
+		RequesterID: author.ID(),
+		RecipeID:    &recipeID,
+		CheckImages: true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, report.Recipes, 1)
+	result := report.Recipes[0]
+	assert.False(t, result.Eligible)
+	assert.Equal(t, "Recipe", result.Document["@type"])
+	assert.Contains(t, result.Issues, inbound.StructuredDataIssue{
+		Field:    "image[1]",
+		Severity: "error",
+		Message:  "could not be fetched: status 404",
+	})
+}
+
+func TestValidateStructuredDataScopesReports(t *testing.T) {
+	svc, author, admin, _, other := newStructuredDataFixture(t)
+
+	report, err := svc.ValidateStructuredData(context.Background(), inbound.StructuredDataQuery{RequesterID: author.ID()})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Checked)
+	assert.Nil(t, report.Recipes[0].Document)
+
+	report, err = svc.ValidateStructuredData(context.Background(), inbound.StructuredDataQuery{RequesterID: admin.ID()})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Checked)
+
+	otherID := other.ID()
+	_, err = svc.ValidateStructuredData(context.Background(), inbound.StructuredDataQuery{RequesterID: author.ID(), RecipeID: &otherID})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+}
@@ -0,0 +1,119 @@
+// Package recipe provides the what-if nutrition and cost simulator
+package recipe
+
+import (
+	"context"
+	stderrors "errors"
+	"math"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+	"github.com/alchemorsel/v3/internal/domain/recipe/nutrition"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// SimulateWhatIf estimates the recipe as saved and with the proposed
+// changes, so readers can see what a swap does before trying it. Nothing
+// is saved. Anyone may simulate a published recipe; drafts only their
+// author.
+func (s *RecipeService) SimulateWhatIf(ctx context.Context, query inbound.WhatIfQuery) (*inbound.WhatIfResult, error) {
+	if len(query.Changes) > nutrition.MaxChanges {
+		return nil, errors.NewBadRequestError(nutrition.ErrTooManyChanges.Error())
+	}
+	changes := make([]nutrition.Change, len(query.Changes))
+	for i, c := range query.Changes {
+		change := nutrition.Change{Remove: c.Remove, Name: c.Name, Amount: c.Amount}
+		if c.IngredientID != nil {
+			change.IngredientID = *c.IngredientID
+		}
+		if c.Unit != nil {
+			var unit recipe.MeasurementUnit
+			if *c.Unit != "" {
+				parsed, ok := ingredients.ParseUnit(*c.Unit)
+				if !ok {
+					return nil, errors.NewBadRequestError("unknown unit " + *c.Unit)
+				}
+				unit = parsed
+			}
+			change.Unit = &unit
+		}
+		changes[i] = change
+	}
+
+	entity, err := s.recipeRepo.FindByID(ctx, query.RecipeID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if entity == nil || (entity.Status() != recipe.RecipeStatusPublished && entity.AuthorID() != query.RequesterID) {
+		return nil, errors.NewRecipeNotFoundError(query.RecipeID.String())
+	}
+
+	saved := entity.Ingredients()
+	proposed, err := nutrition.Apply(saved, changes)
+	if err != nil {
+		if stderrors.Is(err, nutrition.ErrUnknownIngredient) {
+			return nil, errors.NewNotFoundError("ingredient")
+		}
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	foods := s.foods.get(ctx)
+	before := foods.Of(saved, entity.Servings())
+	after := foods.Of(proposed, entity.Servings())
+	return &inbound.WhatIfResult{
+		RecipeID: entity.ID(),
+		Currency: nutrition.Currency,
+		Original: estimateToDTO(before),
+		Proposed: estimateToDTO(after),
+		Difference: inbound.WhatIfDifference{
+			PerServing:     factsToDTO(nutrition.Delta(before.PerServing, after.PerServing)),
+			CostPerServing: math.Round((after.CostPerServing-before.CostPerServing)*100) / 100,
+		},
+	}, nil
+}
+
+func estimateToDTO(e nutrition.Estimate) inbound.NutritionEstimate {
+	dto := inbound.NutritionEstimate{
+		Servings:       e.Servings,
+		Total:          factsToDTO(e.Total),
+		PerServing:     factsToDTO(e.PerServing),
+		Cost:           e.Cost,
+		CostPerServing: e.CostPerServing,
+		Coverage:       e.Coverage,
+		Ingredients:    make([]inbound.EstimatedIngredient, len(e.Lines)),
+	}
+	for i, line := range e.Lines {
+		ingredient := inbound.EstimatedIngredient{
+			Name:      line.Name,
+			Amount:    line.Amount,
+			Unit:      string(line.Unit),
+			Food:      line.Food,
+			Grams:     line.Grams,
+			Nutrition: factsToDTO(line.Facts),
+			Cost:      line.Cost,
+			Reason:    line.Reason,
+		}
+		// Proposed additions have no ID yet
+		if line.IngredientID != uuid.Nil {
+			id := line.IngredientID
+			ingredient.IngredientID = &id
+		}
+		dto.Ingredients[i] = ingredient
+	}
+	return dto
+}
+
+func factsToDTO(f nutrition.Facts) inbound.NutritionDTO {
+	return inbound.NutritionDTO{
+		Calories:      int(f.Calories),
+		Protein:       f.Protein,
+		Carbohydrates: f.Carbohydrates,
+		Fat:           f.Fat,
+		Fiber:         f.Fiber,
+		Sugar:         f.Sugar,
+		Sodium:        f.Sodium,
+		Cholesterol:   f.Cholesterol,
+	}
+}
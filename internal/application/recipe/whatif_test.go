@@ -0,0 +1,57 @@
+package recipe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSimulateWhatIfComparesWithoutSaving(t *testing.T) {
+	authorID := uuid.New()
+	chili, err := recipe.NewRecipe("Chili", "", authorID)
+	require.NoError(t, err)
+	require.NoError(t, chili.SetServings(4))
+	beefID := uuid.New()
+	require.NoError(t, chili.AddIngredient(recipe.Ingredient{ID: beefID, Name: "ground beef", Amount: 1, Unit: recipe.MeasurementUnitPound}))
+	require.NoError(t, chili.AddIngredient(recipe.Ingredient{ID: uuid.New(), Name: "kidney beans", Amount: 2, Unit: recipe.MeasurementUnitCan}))
+
+	svc := &RecipeService{
+		recipeRepo: &stubPublishedRecipes{recipes: []*recipe.Recipe{chili}},
+		logger:     zap.NewNop(),
+	}
+	ctx := context.Background()
+	grams := "grams"
+	amount := 450.0
+
+	result, err := svc.SimulateWhatIf(ctx, inbound.WhatIfQuery{
+		RequesterID: authorID,
+		RecipeID:    chili.ID(),
+		Changes:     []inbound.IngredientChange{{IngredientID: &beefID, Name: "ground turkey", Amount: &amount, Unit: &grams}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "USD", result.Currency)
+	assert.Equal(t, "ground beef", result.Original.Ingredients[0].Food)
+	assert.Equal(t, "ground turkey", result.Proposed.Ingredients[0].Food)
+	assert.Equal(t, 450.0, result.Proposed.Ingredients[0].Grams)
+	assert.Less(t, result.Difference.PerServing.Fat, 0.0)
+	assert.Less(t, result.Difference.CostPerServing, 0.0)
+	assert.Equal(t, "ground beef", chili.Ingredients()[0].Name, "nothing is saved")
+
+	bushels := "bushels"
+	_, err = svc.SimulateWhatIf(ctx, inbound.WhatIfQuery{
+		RequesterID: authorID,
+		RecipeID:    chili.ID(),
+		Changes:     []inbound.IngredientChange{{IngredientID: &beefID, Unit: &bushels}},
+	})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	_, err = svc.SimulateWhatIf(ctx, inbound.WhatIfQuery{RecipeID: chili.ID()})
+	assert.True(t, errors.Is(err, errors.CodeRecipeNotFound), "drafts are only simulated by their author")
+}
@@ -0,0 +1,359 @@
+package recommendation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+)
+
+const (
+	// historyPerUser bounds the recipes of each user that are compared,
+	// keeping the pair counting linear in the number of users. Likes are
+	// read first, then favorites, then views.
+	historyPerUser = 100
+	// profileFeatures bounds the features of a user's taste that
+	// candidates are looked up by
+	profileFeatures = 30
+	// reasonFeatures is how many shared features a reason names
+	reasonFeatures = 2
+
+	// collaborativeWeight and contentWeight split a recommendation's score
+	// between the two signals, each scaled to the user's best candidate
+	collaborativeWeight = 0.6
+	contentWeight       = 0.4
+)
+
+// Signal names reported in a recommendation's factors
+const (
+	SignalCollaborative = "collaborative"
+	SignalContent       = "content"
+)
+
+// interactionWeights is how much each kind of engagement says about taste
+var interactionWeights = map[outbound.InteractionKind]float64{
+	outbound.InteractionFavorite: 4,
+	outbound.InteractionLike:     3,
+	outbound.InteractionView:     1,
+}
+
+// feature is a cuisine, tag or ingredient, keyed by kind so a tag and an
+// ingredient of the same name stay apart
+type feature struct {
+	kind, name string
+}
+
+func (f feature) label() string {
+	if f.kind == "cuisine" {
+		return f.name + " cuisine"
+	}
+	return f.name
+}
+
+// model is one refresh's view of the catalogue and who engaged with what
+type model struct {
+	recipes map[uuid.UUID]outbound.RecipeFeatures
+	// features lists each recipe's features; idf weighs a feature by how
+	// rare it is, so salt does not make every recipe alike
+	features   map[uuid.UUID][]feature
+	idf        map[feature]float64
+	recipeNorm map[uuid.UUID]float64
+	index      map[feature][]uuid.UUID
+	// history is each user's engagement weight per recipe
+	history map[uuid.UUID]map[uuid.UUID]float64
+	// similar is the cosine similarity of two recipes' engagement by user
+	similar map[uuid.UUID]map[uuid.UUID]float64
+}
+
+func buildModel(recipes []outbound.RecipeFeatures, interactions []outbound.Interaction) *model {
+	m := &model{
+		recipes:    make(map[uuid.UUID]outbound.RecipeFeatures, len(recipes)),
+		features:   make(map[uuid.UUID][]feature, len(recipes)),
+		idf:        make(map[feature]float64),
+		recipeNorm: make(map[uuid.UUID]float64, len(recipes)),
+		index:      make(map[feature][]uuid.UUID),
+		history:    make(map[uuid.UUID]map[uuid.UUID]float64),
+		similar:    make(map[uuid.UUID]map[uuid.UUID]float64),
+	}
+
+	for _, r := range recipes {
+		m.recipes[r.RecipeID] = r
+		seen := make(map[feature]bool)
+		add := func(kind, name string) {
+			f := feature{kind: kind, name: strings.ToLower(strings.TrimSpace(name))}
+			if f.name == "" || seen[f] {
+				return
+			}
+			seen[f] = true
+			m.features[r.RecipeID] = append(m.features[r.RecipeID], f)
+			m.index[f] = append(m.index[f], r.RecipeID)
+		}
+		add("cuisine", r.Cuisine)
+		for _, tag := range r.Tags {
+			add("tag", tag)
+		}
+		for _, ingredient := range r.Ingredients {
+			add("ingredient", ingredient)
+		}
+	}
+	for f, ids := range m.index {
+		m.idf[f] = math.Log(1 + float64(len(recipes))/float64(len(ids)))
+	}
+	for id, features := range m.features {
+		sum := 0.0
+		for _, f := range features {
+			sum += m.idf[f] * m.idf[f]
+		}
+		m.recipeNorm[id] = math.Sqrt(sum)
+	}
+
+	for _, in := range interactions {
+		if _, ok := m.recipes[in.RecipeID]; !ok {
+			continue
+		}
+		history := m.history[in.UserID]
+		if history == nil {
+			history = make(map[uuid.UUID]float64)
+			m.history[in.UserID] = history
+		}
+		if _, ok := history[in.RecipeID]; !ok && len(history) >= historyPerUser {
+			continue
+		}
+		history[in.RecipeID] += interactionWeights[in.Kind]
+	}
+
+	norms := make(map[uuid.UUID]float64)
+	dots := make(map[uuid.UUID]map[uuid.UUID]float64)
+	for _, history := range m.history {
+		ids := make([]uuid.UUID, 0, len(history))
+		for id, weight := range history {
+			ids = append(ids, id)
+			norms[id] += weight * weight
+		}
+		for i, a := range ids {
+			for _, b := range ids[i+1:] {
+				product := history[a] * history[b]
+				addPair(dots, a, b, product)
+				addPair(dots, b, a, product)
+			}
+		}
+	}
+	for a, row := range dots {
+		m.similar[a] = make(map[uuid.UUID]float64, len(row))
+		for b, dot := range row {
+			m.similar[a][b] = dot / math.Sqrt(norms[a]*norms[b])
+		}
+	}
+	return m
+}
+
+func addPair(dots map[uuid.UUID]map[uuid.UUID]float64, a, b uuid.UUID, value float64) {
+	row := dots[a]
+	if row == nil {
+		row = make(map[uuid.UUID]float64)
+		dots[a] = row
+	}
+	row[b] += value
+}
+
+// candidate is a recipe being scored for a user
+type candidate struct {
+	id            uuid.UUID
+	collaborative float64
+	because       uuid.UUID
+	strongest     float64
+	content       float64
+	shared        []feature
+}
+
+// recommend scores the published recipes the user has not engaged with
+// or written, and returns the best limit of them
+func (m *model) recommend(userID uuid.UUID, limit int) []inbound.Recommendation {
+	history := m.history[userID]
+	candidates := make(map[uuid.UUID]*candidate)
+	get := func(id uuid.UUID) *candidate {
+		if _, engaged := history[id]; engaged || m.recipes[id].AuthorID == userID {
+			return nil
+		}
+		c := candidates[id]
+		if c == nil {
+			c = &candidate{id: id}
+			candidates[id] = c
+		}
+		return c
+	}
+
+	for engaged, weight := range history {
+		for id, similarity := range m.similar[engaged] {
+			c := get(id)
+			if c == nil {
+				continue
+			}
+			contribution := weight * similarity
+			c.collaborative += contribution
+			if contribution > c.strongest {
+				c.strongest, c.because = contribution, engaged
+			}
+		}
+	}
+
+	// The user's taste is the sum of what they engaged with, each feature
+	// weighed by its rarity; candidates are compared to its strongest
+	// features by cosine similarity
+	taste := make(map[feature]float64)
+	for engaged, weight := range history {
+		for _, f := range m.features[engaged] {
+			taste[f] += weight * m.idf[f]
+		}
+	}
+	strongest := make([]feature, 0, len(taste))
+	for f := range taste {
+		strongest = append(strongest, f)
+	}
+	sort.Slice(strongest, func(i, j int) bool {
+		if taste[strongest[i]] != taste[strongest[j]] {
+			return taste[strongest[i]] > taste[strongest[j]]
+		}
+		return strongest[i].label() < strongest[j].label()
+	})
+	if len(strongest) > profileFeatures {
+		strongest = strongest[:profileFeatures]
+	}
+	tasteNorm := 0.0
+	for _, f := range strongest {
+		tasteNorm += taste[f] * taste[f]
+	}
+	tasteNorm = math.Sqrt(tasteNorm)
+	for _, f := range strongest {
+		for _, id := range m.index[f] {
+			if c := get(id); c != nil {
+				c.content += taste[f] * m.idf[f]
+				c.shared = append(c.shared, f)
+			}
+		}
+	}
+
+	maxCollaborative, maxContent := 0.0, 0.0
+	for _, c := range candidates {
+		if norm := m.recipeNorm[c.id]; norm > 0 && tasteNorm > 0 {
+			c.content /= norm * tasteNorm
+		} else {
+			c.content = 0
+		}
+		maxCollaborative = math.Max(maxCollaborative, c.collaborative)
+		maxContent = math.Max(maxContent, c.content)
+	}
+
+	recommendations := make([]inbound.Recommendation, 0, len(candidates))
+	for _, c := range candidates {
+		rec := inbound.Recommendation{RecipeID: c.id, Factors: []inbound.RankingFactor{}}
+		if c.collaborative > 0 {
+			weight := collaborativeWeight * c.collaborative / maxCollaborative
+			rec.Score += weight
+			rec.Factors = append(rec.Factors, inbound.RankingFactor{
+				Signal: SignalCollaborative,
+				Detail: fmt.Sprintf("often enjoyed by the same people as %s", m.recipes[c.because].Title),
+				Weight: weight,
+			})
+		}
+		if c.content > 0 {
+			weight := contentWeight * c.content / maxContent
+			rec.Score += weight
+			labels := make([]string, 0, reasonFeatures)
+			for _, f := range c.shared {
+				if len(labels) == reasonFeatures {
+					break
+				}
+				labels = append(labels, f.label())
+			}
+			rec.Factors = append(rec.Factors, inbound.RankingFactor{
+				Signal: SignalContent,
+				Detail: fmt.Sprintf("shares %s with recipes you liked", strings.Join(labels, " and ")),
+				Weight: weight,
+			})
+		}
+		if rec.Score > 0 {
+			recommendations = append(recommendations, rec)
+		}
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].RecipeID.String() < recommendations[j].RecipeID.String()
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations
+}
+
+// similarTo scores the published recipes sharing features with a recipe by
+// the cosine of their rarity-weighted features and returns the best limit
+func (m *model) similarTo(recipeID uuid.UUID, limit int) []inbound.Recommendation {
+	norm := m.recipeNorm[recipeID]
+	if norm == 0 {
+		return []inbound.Recommendation{}
+	}
+
+	// Rarest first, so a reason names what sets the recipes apart
+	features := append([]feature(nil), m.features[recipeID]...)
+	sort.Slice(features, func(i, j int) bool {
+		if m.idf[features[i]] != m.idf[features[j]] {
+			return m.idf[features[i]] > m.idf[features[j]]
+		}
+		return features[i].label() < features[j].label()
+	})
+	candidates := make(map[uuid.UUID]*candidate)
+	for _, f := range features {
+		for _, id := range m.index[f] {
+			if id == recipeID {
+				continue
+			}
+			c := candidates[id]
+			if c == nil {
+				c = &candidate{id: id}
+				candidates[id] = c
+			}
+			c.content += m.idf[f] * m.idf[f]
+			c.shared = append(c.shared, f)
+		}
+	}
+
+	similar := make([]inbound.Recommendation, 0, len(candidates))
+	for _, c := range candidates {
+		score := c.content / (norm * m.recipeNorm[c.id])
+		labels := make([]string, 0, reasonFeatures)
+		for _, f := range c.shared {
+			if len(labels) == reasonFeatures {
+				break
+			}
+			labels = append(labels, f.label())
+		}
+		similar = append(similar, inbound.Recommendation{
+			RecipeID: c.id,
+			Score:    score,
+			Factors: []inbound.RankingFactor{{
+				Signal: SignalContent,
+				Detail: fmt.Sprintf("shares %s", strings.Join(labels, " and ")),
+				Weight: score,
+			}},
+		})
+	}
+
+	sort.Slice(similar, func(i, j int) bool {
+		if similar[i].Score != similar[j].Score {
+			return similar[i].Score > similar[j].Score
+		}
+		return similar[i].RecipeID.String() < similar[j].RecipeID.String()
+	})
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar
+}
@@ -0,0 +1,255 @@
+// Package recommendation precomputes the recipes each recently active user
+// might like. Recipes liked, saved or opened by the same people are alike,
+// and so are recipes sharing a cuisine, tags or ingredients; a user's
+// candidates are scored on both against what they engaged with, and the
+// lists are kept in the cache for the recommended recipes endpoint to read.
+// The recipes most alike a given one are worked out on demand from the same
+// features and cached per recipe.
+package recommendation
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultWindow is how far back engagement counts
+	DefaultWindow = 90 * 24 * time.Hour
+	// DefaultPerUser is the recipes kept for each user
+	DefaultPerUser = 50
+	// DefaultTTL is how long a user's list is kept when no refresh
+	// replaces it
+	DefaultTTL = 2 * time.Hour
+	// DefaultSimilarTTL is how long a recipe's similar recipes and the
+	// catalogue they are found in are reused
+	DefaultSimilarTTL = time.Hour
+	// MaxSimilar is the most similar recipes kept for a recipe
+	MaxSimilar = 24
+
+	// interactionLimit bounds each kind of engagement read per refresh
+	interactionLimit = 100000
+	// writeBatch is the users' lists written to the cache at once
+	writeBatch = 500
+	// cacheKeyPrefix scopes the lists in the shared cache
+	cacheKeyPrefix = "recommendations:"
+	// similarKeyPrefix scopes the similar recipes in the shared cache
+	similarKeyPrefix = "similar:"
+)
+
+// Config sets how much engagement counts, how many recipes are kept and
+// for how long
+type Config struct {
+	Window     time.Duration
+	PerUser    int
+	TTL        time.Duration
+	SimilarTTL time.Duration
+}
+
+// Service implements inbound.RecommendationService
+type Service struct {
+	signals outbound.RecommendationSignalRepository
+	cache   outbound.CacheRepository
+	cfg     Config
+	now     func() time.Time
+	logger  *zap.Logger
+
+	mu         sync.Mutex
+	refreshing bool
+
+	// catalogue is the last model built, reused to find similar recipes.
+	// Its lock is held while it is rebuilt so concurrent misses read the
+	// published recipes once.
+	catalogueMu sync.Mutex
+	catalogue   *model
+	builtAt     time.Time
+}
+
+// NewService creates a recommendation service
+func NewService(signals outbound.RecommendationSignalRepository, cache outbound.CacheRepository, cfg Config, logger *zap.Logger) *Service {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultWindow
+	}
+	if cfg.PerUser <= 0 {
+		cfg.PerUser = DefaultPerUser
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+	if cfg.SimilarTTL <= 0 {
+		cfg.SimilarTTL = DefaultSimilarTTL
+	}
+	return &Service{
+		signals: signals,
+		cache:   cache,
+		cfg:     cfg,
+		now:     time.Now,
+		logger:  logger.Named("recommendation"),
+	}
+}
+
+// CacheKey is where a user's recommendations are kept
+func CacheKey(userID uuid.UUID) string {
+	return cacheKeyPrefix + userID.String()
+}
+
+// SimilarKey is where a recipe's similar recipes are kept
+func SimilarKey(recipeID uuid.UUID) string {
+	return similarKeyPrefix + recipeID.String()
+}
+
+// For reads a user's list from the cache. A missing or unreadable entry
+// means no recommendations, so callers fall back to what everyone sees.
+func (s *Service) For(ctx context.Context, userID uuid.UUID) ([]inbound.Recommendation, error) {
+	data, err := s.cache.Get(ctx, CacheKey(userID))
+	if err != nil || len(data) == 0 {
+		return nil, nil
+	}
+	var recommendations []inbound.Recommendation
+	if err := json.Unmarshal(data, &recommendations); err != nil {
+		s.logger.Warn("Discarding unreadable recommendations", zap.String("user_id", userID.String()), zap.Error(err))
+		return nil, nil
+	}
+	return recommendations, nil
+}
+
+// Refresh rebuilds the lists of every user with engagement in the window
+func (s *Service) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	if s.refreshing {
+		s.mu.Unlock()
+		return nil
+	}
+	s.refreshing = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.refreshing = false
+		s.mu.Unlock()
+	}()
+
+	started := s.now()
+	recipes, err := s.published(ctx)
+	if err != nil {
+		return err
+	}
+	interactions, err := s.signals.Interactions(ctx, started.Add(-s.cfg.Window), interactionLimit)
+	if err != nil {
+		return errors.NewDatabaseError("read recipe interactions", err)
+	}
+
+	m := buildModel(recipes, interactions)
+	s.catalogueMu.Lock()
+	s.catalogue, s.builtAt = m, started
+	s.catalogueMu.Unlock()
+
+	items := make(map[string][]byte, writeBatch)
+	written := 0
+	flush := func() error {
+		if len(items) == 0 {
+			return nil
+		}
+		if err := s.cache.MSet(ctx, items, s.cfg.TTL); err != nil {
+			return errors.NewExternalServiceError("cache", err)
+		}
+		written += len(items)
+		items = make(map[string][]byte, writeBatch)
+		return nil
+	}
+	for userID := range m.history {
+		data, err := json.Marshal(m.recommend(userID, s.cfg.PerUser))
+		if err != nil {
+			return err
+		}
+		items[CacheKey(userID)] = data
+		if len(items) == writeBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	s.logger.Info("Recommendations refreshed",
+		zap.Int("users", written),
+		zap.Int("recipes", len(recipes)),
+		zap.Int("interactions", len(interactions)),
+		zap.Duration("duration", s.now().Sub(started)),
+	)
+	return nil
+}
+
+// Similar returns the published recipes most alike a recipe by shared
+// cuisine, tags and ingredients, best first. A recipe that is not
+// published has none.
+func (s *Service) Similar(ctx context.Context, recipeID uuid.UUID, limit int) ([]inbound.Recommendation, error) {
+	if limit <= 0 || limit > MaxSimilar {
+		limit = MaxSimilar
+	}
+	if data, err := s.cache.Get(ctx, SimilarKey(recipeID)); err == nil && len(data) > 0 {
+		var similar []inbound.Recommendation
+		if err := json.Unmarshal(data, &similar); err == nil {
+			return truncate(similar, limit), nil
+		}
+		s.logger.Warn("Discarding unreadable similar recipes", zap.String("recipe_id", recipeID.String()))
+	}
+
+	m, err := s.currentCatalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	similar := m.similarTo(recipeID, MaxSimilar)
+	data, err := json.Marshal(similar)
+	if err != nil {
+		return nil, err
+	}
+	// The list is still served if it cannot be cached
+	if err := s.cache.Set(ctx, SimilarKey(recipeID), data, s.cfg.SimilarTTL); err != nil {
+		s.logger.Warn("Failed to cache similar recipes", zap.String("recipe_id", recipeID.String()), zap.Error(err))
+	}
+	return truncate(similar, limit), nil
+}
+
+// currentCatalogue returns the last model built, rebuilding it from the
+// published recipes once it is older than the similar recipes TTL
+func (s *Service) currentCatalogue(ctx context.Context) (*model, error) {
+	s.catalogueMu.Lock()
+	defer s.catalogueMu.Unlock()
+	if s.catalogue != nil && s.now().Sub(s.builtAt) < s.cfg.SimilarTTL {
+		return s.catalogue, nil
+	}
+	recipes, err := s.published(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.catalogue, s.builtAt = buildModel(recipes, nil), s.now()
+	return s.catalogue, nil
+}
+
+func (s *Service) published(ctx context.Context) ([]outbound.RecipeFeatures, error) {
+	var recipes []outbound.RecipeFeatures
+	err := s.signals.EachPublished(ctx, func(features outbound.RecipeFeatures) error {
+		recipes = append(recipes, features)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("read recipe features", err)
+	}
+	return recipes, nil
+}
+
+func truncate(recommendations []inbound.Recommendation, limit int) []inbound.Recommendation {
+	if len(recommendations) > limit {
+		return recommendations[:limit]
+	}
+	return recommendations
+}
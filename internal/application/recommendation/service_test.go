@@ -0,0 +1,144 @@
+package recommendation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubSignals struct {
+	recipes      []outbound.RecipeFeatures
+	interactions []outbound.Interaction
+}
+
+func (s *stubSignals) Interactions(ctx context.Context, since time.Time, limit int) ([]outbound.Interaction, error) {
+	return s.interactions, nil
+}
+
+func (s *stubSignals) EachPublished(ctx context.Context, fn func(outbound.RecipeFeatures) error) error {
+	for _, r := range s.recipes {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type stubCache struct {
+	outbound.CacheRepository
+	items map[string][]byte
+	ttl   time.Duration
+}
+
+func (c *stubCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, ok := c.items[key]; ok {
+		return value, nil
+	}
+	return nil, assert.AnError
+}
+
+func (c *stubCache) MSet(ctx context.Context, items map[string][]byte, ttl time.Duration) error {
+	for key, value := range items {
+		c.items[key] = value
+	}
+	c.ttl = ttl
+	return nil
+}
+
+func (c *stubCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.items[key] = value
+	c.ttl = ttl
+	return nil
+}
+
+func TestRefreshRecommendsByCoEngagementAndSharedFeatures(t *testing.T) {
+	cook, fan, other := uuid.New(), uuid.New(), uuid.New()
+	carbonara, amatriciana, tacos, ownLasagne, pho := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	signals := &stubSignals{
+		recipes: []outbound.RecipeFeatures{
+			{RecipeID: carbonara, AuthorID: other, Title: "Carbonara", Cuisine: "italian", Tags: []string{"pasta"}, Ingredients: []string{"Spaghetti", "Pancetta"}},
+			{RecipeID: amatriciana, AuthorID: other, Title: "Amatriciana", Cuisine: "Italian", Tags: []string{"Pasta"}, Ingredients: []string{"spaghetti", "tomato"}},
+			{RecipeID: tacos, AuthorID: other, Title: "Tacos", Cuisine: "mexican", Ingredients: []string{"tortilla"}},
+			{RecipeID: ownLasagne, AuthorID: cook, Title: "Lasagne", Cuisine: "italian", Tags: []string{"pasta"}},
+			{RecipeID: pho, AuthorID: other, Title: "Pho", Cuisine: "vietnamese", Ingredients: []string{"rice noodles"}},
+		},
+		interactions: []outbound.Interaction{
+			{UserID: cook, RecipeID: carbonara, Kind: outbound.InteractionLike},
+			{UserID: fan, RecipeID: carbonara, Kind: outbound.InteractionFavorite},
+			{UserID: fan, RecipeID: tacos, Kind: outbound.InteractionLike},
+			{UserID: other, RecipeID: carbonara, Kind: outbound.InteractionView},
+			{UserID: other, RecipeID: tacos, Kind: outbound.InteractionView},
+			{UserID: cook, RecipeID: uuid.New(), Kind: outbound.InteractionLike}, // no longer published
+		},
+	}
+	cache := &stubCache{items: make(map[string][]byte)}
+	svc := NewService(signals, cache, Config{TTL: time.Hour}, zap.NewNop())
+	ctx := context.Background()
+
+	require.NoError(t, svc.Refresh(ctx))
+	assert.Len(t, cache.items, 3)
+	assert.Equal(t, time.Hour, cache.ttl)
+
+	recs, err := svc.For(ctx, cook)
+	require.NoError(t, err)
+	ids := make([]uuid.UUID, len(recs))
+	for i, rec := range recs {
+		ids[i] = rec.RecipeID
+	}
+	assert.ElementsMatch(t, []uuid.UUID{tacos, amatriciana}, ids, "liked, own and unrelated recipes are left out")
+
+	byID := make(map[uuid.UUID]inbound.Recommendation)
+	for _, rec := range recs {
+		byID[rec.RecipeID] = rec
+	}
+	require.Len(t, byID[tacos].Factors, 1)
+	assert.Equal(t, SignalCollaborative, byID[tacos].Factors[0].Signal)
+	assert.Equal(t, "often enjoyed by the same people as Carbonara", byID[tacos].Factors[0].Detail)
+	require.Len(t, byID[amatriciana].Factors, 1)
+	assert.Equal(t, SignalContent, byID[amatriciana].Factors[0].Signal)
+	assert.Contains(t, byID[amatriciana].Factors[0].Detail, "spaghetti")
+
+	recs, err = svc.For(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, recs, "users without activity have no list")
+}
+
+func TestSimilarRanksBySharedFeaturesAndCachesTheList(t *testing.T) {
+	author := uuid.New()
+	carbonara, amatriciana, cacio, tacos := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	signals := &stubSignals{recipes: []outbound.RecipeFeatures{
+		{RecipeID: carbonara, AuthorID: author, Title: "Carbonara", Cuisine: "italian", Tags: []string{"pasta"}, Ingredients: []string{"spaghetti", "pancetta", "egg"}},
+		{RecipeID: amatriciana, AuthorID: author, Title: "Amatriciana", Cuisine: "italian", Tags: []string{"pasta"}, Ingredients: []string{"spaghetti", "pancetta", "tomato"}},
+		{RecipeID: cacio, AuthorID: author, Title: "Cacio e Pepe", Cuisine: "italian", Ingredients: []string{"spaghetti", "pecorino"}},
+		{RecipeID: tacos, AuthorID: author, Title: "Tacos", Cuisine: "mexican", Ingredients: []string{"tortilla", "egg"}},
+	}}
+	cache := &stubCache{items: make(map[string][]byte)}
+	svc := NewService(signals, cache, Config{SimilarTTL: time.Minute}, zap.NewNop())
+	ctx := context.Background()
+
+	similar, err := svc.Similar(ctx, carbonara, 2)
+	require.NoError(t, err)
+	require.Len(t, similar, 2)
+	assert.Equal(t, amatriciana, similar[0].RecipeID)
+	assert.Equal(t, cacio, similar[1].RecipeID)
+	assert.Greater(t, similar[0].Score, similar[1].Score)
+	assert.Equal(t, "shares pancetta and pasta", similar[0].Factors[0].Detail)
+	assert.Equal(t, time.Minute, cache.ttl)
+
+	// The cached list is served, up to the most kept
+	signals.recipes = nil
+	similar, err = svc.Similar(ctx, carbonara, 0)
+	require.NoError(t, err)
+	assert.Len(t, similar, 3)
+
+	similar, err = svc.Similar(ctx, uuid.New(), 5)
+	require.NoError(t, err)
+	assert.Empty(t, similar)
+}
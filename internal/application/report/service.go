@@ -0,0 +1,421 @@
+// Package report lets readers report recipes and comments that break the
+// rules and admins work through the open reports. Content reported by
+// Config.Threshold readers is taken down at once and stays down until an
+// admin resolves or dismisses the reports.
+package report
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/report"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	// summaryLength bounds the comment excerpt shown to admins
+	summaryLength = 120
+)
+
+// Config controls automatic takedowns
+type Config struct {
+	// Threshold is how many open reports take content down; 0 leaves
+	// every decision to the admins
+	Threshold int
+}
+
+// Service implements inbound.ReportService
+type Service struct {
+	reports  outbound.ReportRepository
+	recipes  inbound.RecipeService
+	comments outbound.CommentRepository
+	userRepo outbound.UserRepository
+	config   Config
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+// NewService creates the report service
+func NewService(
+	reports outbound.ReportRepository,
+	recipes inbound.RecipeService,
+	comments outbound.CommentRepository,
+	userRepo outbound.UserRepository,
+	config Config,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		reports:  reports,
+		recipes:  recipes,
+		comments: comments,
+		userRepo: userRepo,
+		config:   config,
+		logger:   logger.Named("report"),
+		now:      time.Now,
+	}
+}
+
+// ReportRecipe reports a published recipe
+func (s *Service) ReportRecipe(ctx context.Context, cmd inbound.ReportContentCommand) (*inbound.ReportDTO, error) {
+	dto, err := s.recipes.GetRecipeByID(ctx, cmd.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	// Drafts and archived recipes are not public, so there is nothing to
+	// report
+	if dto.Status != recipe.RecipeStatusPublished {
+		return nil, errors.NewRecipeNotFoundError(cmd.TargetID.String())
+	}
+	return s.report(ctx, report.TargetRecipe, dto.ID, dto.AuthorID, cmd)
+}
+
+// ReportComment reports a visible comment
+func (s *Service) ReportComment(ctx context.Context, cmd inbound.ReportContentCommand) (*inbound.ReportDTO, error) {
+	c, err := s.comments.FindByID(ctx, cmd.TargetID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find comment", err)
+	}
+	if c == nil || !c.Visible() {
+		return nil, errors.NewNotFoundError("comment")
+	}
+	return s.report(ctx, report.TargetComment, c.RecipeID(), c.AuthorID(), cmd)
+}
+
+func (s *Service) report(ctx context.Context, target report.TargetType, recipeID, authorID uuid.UUID, cmd inbound.ReportContentCommand) (*inbound.ReportDTO, error) {
+	r, err := report.NewReport(target, cmd.TargetID, recipeID, authorID, cmd.ReporterID, report.Reason(cmd.Reason), cmd.Note, s.now().UTC())
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	open, err := s.reports.HasOpen(ctx, target, cmd.TargetID, cmd.ReporterID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find reports", err)
+	}
+	if open {
+		return nil, errors.NewConflictError("you have already reported this " + string(target))
+	}
+	if err := s.reports.Create(ctx, r); err != nil {
+		return nil, errors.NewDatabaseError("save report", err)
+	}
+	s.logger.Info("Content reported",
+		zap.String("target_type", string(target)),
+		zap.String("target_id", cmd.TargetID.String()),
+		zap.String("reason", string(r.Reason)),
+	)
+
+	if err := s.applyThreshold(ctx, r); err != nil {
+		return nil, err
+	}
+	dto := reportToDTO(r)
+	return &dto, nil
+}
+
+// applyThreshold takes the reported content down once enough readers
+// reported it
+func (s *Service) applyThreshold(ctx context.Context, r *report.Report) error {
+	if s.config.Threshold <= 0 {
+		return nil
+	}
+	count, err := s.reports.CountOpen(ctx, r.TargetType, r.TargetID)
+	if err != nil {
+		return errors.NewDatabaseError("count reports", err)
+	}
+	if count < s.config.Threshold {
+		return nil
+	}
+
+	switch r.TargetType {
+	case report.TargetRecipe:
+		err = s.recipes.TakeDownRecipe(ctx, r.TargetID)
+	case report.TargetComment:
+		err = s.hideComment(ctx, r.TargetID)
+	}
+	if err != nil {
+		// Content reported by several readers at once may already be down
+		if errors.Is(err, errors.CodeConflict) {
+			return nil
+		}
+		return err
+	}
+	if err := s.reports.MarkUnpublished(ctx, r.TargetType, r.TargetID); err != nil {
+		return errors.NewDatabaseError("update reports", err)
+	}
+	r.Unpublished = true
+
+	s.logger.Warn("Reported content taken down",
+		zap.String("target_type", string(r.TargetType)),
+		zap.String("target_id", r.TargetID.String()),
+		zap.Int("reports", count),
+	)
+	return nil
+}
+
+func (s *Service) hideComment(ctx context.Context, commentID uuid.UUID) error {
+	c, err := s.findComment(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if err := c.HideForReview("reported by readers", s.now().UTC()); err != nil {
+		return errors.NewConflictError(err.Error())
+	}
+	if err := s.comments.UpdateModeration(ctx, c); err != nil {
+		return errors.NewDatabaseError("hide comment", err)
+	}
+	return nil
+}
+
+// ListOpenReports pages through open reports, oldest first
+func (s *Service) ListOpenReports(ctx context.Context, requesterID uuid.UUID, limit, offset int) (*inbound.ReportPage, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "review reports"); err != nil {
+		return nil, err
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	limit = pageSize(limit)
+	rows, total, err := s.reports.FindOpen(ctx, offset, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list reports", err)
+	}
+
+	page := &inbound.ReportPage{
+		Reports: make([]inbound.ReportDTO, len(rows)),
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}
+	for i, r := range rows {
+		page.Reports[i] = reportToDTO(r)
+	}
+	if err := s.describe(ctx, page.Reports); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// describe fills in what admins need to judge each report: the reported
+// recipe's title or comment's text, and how many readers reported it
+func (s *Service) describe(ctx context.Context, dtos []inbound.ReportDTO) error {
+	var recipeIDs []uuid.UUID
+	for _, dto := range dtos {
+		if dto.TargetType == string(report.TargetRecipe) {
+			recipeIDs = append(recipeIDs, dto.TargetID)
+		}
+	}
+	titles := make(map[uuid.UUID]string, len(recipeIDs))
+	if len(recipeIDs) > 0 {
+		recipes, err := s.recipes.GetRecipesByIDs(ctx, recipeIDs)
+		if err != nil {
+			return err
+		}
+		for _, r := range recipes {
+			titles[r.ID] = r.Title
+		}
+	}
+
+	counts := make(map[uuid.UUID]int)
+	for i := range dtos {
+		dto := &dtos[i]
+		if _, ok := counts[dto.TargetID]; !ok {
+			n, err := s.reports.CountOpen(ctx, report.TargetType(dto.TargetType), dto.TargetID)
+			if err != nil {
+				return errors.NewDatabaseError("count reports", err)
+			}
+			counts[dto.TargetID] = n
+		}
+		dto.OpenReports = counts[dto.TargetID]
+
+		if dto.TargetType == string(report.TargetRecipe) {
+			dto.Summary = titles[dto.TargetID]
+			continue
+		}
+		c, err := s.comments.FindByID(ctx, dto.TargetID)
+		if err != nil {
+			return errors.NewDatabaseError("find comment", err)
+		}
+		if c != nil {
+			dto.Summary = excerpt(c.Content())
+		}
+	}
+	return nil
+}
+
+// ResolveReport upholds a report: a recipe is archived and a comment
+// removed for good
+func (s *Service) ResolveReport(ctx context.Context, cmd inbound.ReviewReportCommand) (*inbound.ReportDTO, error) {
+	return s.review(ctx, cmd, report.StatusResolved)
+}
+
+// DismissReport keeps the reported content up, putting it back if the
+// report threshold took it down
+func (s *Service) DismissReport(ctx context.Context, cmd inbound.ReviewReportCommand) (*inbound.ReportDTO, error) {
+	return s.review(ctx, cmd, report.StatusDismissed)
+}
+
+func (s *Service) review(ctx context.Context, cmd inbound.ReviewReportCommand, verdict report.Status) (*inbound.ReportDTO, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, cmd.ModeratorID, "review reports"); err != nil {
+		return nil, err
+	}
+
+	r, err := s.reports.FindByID(ctx, cmd.ReportID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find report", err)
+	}
+	if r == nil {
+		return nil, errors.NewNotFoundError("report")
+	}
+	if err := r.Close(verdict, cmd.ModeratorID, cmd.Note, s.now().UTC()); err != nil {
+		if stderrors.Is(err, report.ErrNotOpen) {
+			return nil, errors.NewConflictError(err.Error())
+		}
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	if verdict == report.StatusResolved {
+		err = s.takeDown(ctx, r, cmd.ModeratorID)
+	} else if r.Unpublished {
+		err = s.putBack(ctx, r)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	closed, err := s.reports.CloseOpen(ctx, r.TargetType, r.TargetID, r)
+	if err != nil {
+		return nil, errors.NewDatabaseError("close reports", err)
+	}
+	s.logger.Info("Reports reviewed",
+		zap.String("moderator_id", cmd.ModeratorID.String()),
+		zap.String("target_type", string(r.TargetType)),
+		zap.String("target_id", r.TargetID.String()),
+		zap.String("verdict", string(verdict)),
+		zap.Int("closed", closed),
+	)
+
+	dto := reportToDTO(r)
+	return &dto, nil
+}
+
+// takeDown archives a reported recipe or removes a reported comment.
+// Content that is already down stays down.
+func (s *Service) takeDown(ctx context.Context, r *report.Report, moderatorID uuid.UUID) error {
+	if r.TargetType == report.TargetRecipe {
+		err := s.recipes.TakeDownRecipe(ctx, r.TargetID)
+		if err != nil && !errors.Is(err, errors.CodeConflict) && !errors.Is(err, errors.CodeNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	c, err := s.findComment(ctx, r.TargetID)
+	if err != nil {
+		if errors.Is(err, errors.CodeNotFound) {
+			return nil
+		}
+		return err
+	}
+	if c.Visible() {
+		if err := c.Hide(moderatorID, r.Resolution, s.now().UTC()); err != nil {
+			return errors.NewBadRequestError(err.Error())
+		}
+	}
+	if c.Status() != comment.StatusHidden {
+		return nil
+	}
+	if err := c.Remove(); err != nil {
+		return errors.NewConflictError(err.Error())
+	}
+	if err := s.comments.UpdateModeration(ctx, c); err != nil {
+		return errors.NewDatabaseError("remove comment", err)
+	}
+	return nil
+}
+
+// putBack restores content the report threshold took down
+func (s *Service) putBack(ctx context.Context, r *report.Report) error {
+	if r.TargetType == report.TargetRecipe {
+		err := s.recipes.ReinstateRecipe(ctx, r.TargetID)
+		if err != nil && !errors.Is(err, errors.CodeConflict) && !errors.Is(err, errors.CodeNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	c, err := s.findComment(ctx, r.TargetID)
+	if err != nil {
+		if errors.Is(err, errors.CodeNotFound) {
+			return nil
+		}
+		return err
+	}
+	// A comment its recipe's author hid meanwhile waits for the hidden
+	// comment review instead
+	if c.Status() != comment.StatusHidden || c.HiddenBy() != nil {
+		return nil
+	}
+	if err := c.Restore(); err != nil {
+		return errors.NewConflictError(err.Error())
+	}
+	if err := s.comments.UpdateModeration(ctx, c); err != nil {
+		return errors.NewDatabaseError("restore comment", err)
+	}
+	return nil
+}
+
+func (s *Service) findComment(ctx context.Context, commentID uuid.UUID) (*comment.Comment, error) {
+	c, err := s.comments.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find comment", err)
+	}
+	if c == nil {
+		return nil, errors.NewNotFoundError("comment")
+	}
+	return c, nil
+}
+
+func reportToDTO(r *report.Report) inbound.ReportDTO {
+	return inbound.ReportDTO{
+		ID:          r.ID,
+		TargetType:  string(r.TargetType),
+		TargetID:    r.TargetID,
+		RecipeID:    r.RecipeID,
+		ReporterID:  r.ReporterID,
+		Reason:      string(r.Reason),
+		Note:        r.Note,
+		Status:      string(r.Status),
+		Unpublished: r.Unpublished,
+		ResolvedBy:  r.ResolvedBy,
+		Resolution:  r.Resolution,
+		CreatedAt:   r.CreatedAt,
+		ResolvedAt:  r.ResolvedAt,
+	}
+}
+
+func excerpt(text string) string {
+	if utf8.RuneCountInString(text) <= summaryLength {
+		return text
+	}
+	return string([]rune(text)[:summaryLength-1]) + "…"
+}
+
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
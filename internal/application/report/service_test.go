@@ -0,0 +1,220 @@
+package report
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/report"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memoryReports struct {
+	reports []*report.Report
+}
+
+func (m *memoryReports) Create(ctx context.Context, r *report.Report) error {
+	copied := *r
+	m.reports = append(m.reports, &copied)
+	return nil
+}
+
+func (m *memoryReports) FindByID(ctx context.Context, id uuid.UUID) (*report.Report, error) {
+	for _, r := range m.reports {
+		if r.ID == id {
+			copied := *r
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryReports) open(target report.TargetType, targetID uuid.UUID) []*report.Report {
+	var open []*report.Report
+	for _, r := range m.reports {
+		if r.TargetType == target && r.TargetID == targetID && r.Status == report.StatusOpen {
+			open = append(open, r)
+		}
+	}
+	return open
+}
+
+func (m *memoryReports) HasOpen(ctx context.Context, target report.TargetType, targetID, reporterID uuid.UUID) (bool, error) {
+	for _, r := range m.open(target, targetID) {
+		if r.ReporterID == reporterID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryReports) CountOpen(ctx context.Context, target report.TargetType, targetID uuid.UUID) (int, error) {
+	return len(m.open(target, targetID)), nil
+}
+
+func (m *memoryReports) FindOpen(ctx context.Context, offset, limit int) ([]*report.Report, int64, error) {
+	var open []*report.Report
+	for _, r := range m.reports {
+		if r.Status == report.StatusOpen {
+			copied := *r
+			open = append(open, &copied)
+		}
+	}
+	return open, int64(len(open)), nil
+}
+
+func (m *memoryReports) MarkUnpublished(ctx context.Context, target report.TargetType, targetID uuid.UUID) error {
+	for _, r := range m.open(target, targetID) {
+		r.Unpublished = true
+	}
+	return nil
+}
+
+func (m *memoryReports) CloseOpen(ctx context.Context, target report.TargetType, targetID uuid.UUID, verdict *report.Report) (int, error) {
+	open := m.open(target, targetID)
+	for _, r := range open {
+		r.Status, r.ResolvedBy, r.Resolution, r.ResolvedAt = verdict.Status, verdict.ResolvedBy, verdict.Resolution, verdict.ResolvedAt
+	}
+	return len(open), nil
+}
+
+type stubRecipes struct {
+	inbound.RecipeService
+	recipes map[uuid.UUID]*inbound.RecipeDTO
+}
+
+func (s *stubRecipes) GetRecipeByID(ctx context.Context, id uuid.UUID) (*inbound.RecipeDTO, error) {
+	if dto, ok := s.recipes[id]; ok {
+		copied := *dto
+		return &copied, nil
+	}
+	return nil, errors.NewRecipeNotFoundError(id.String())
+}
+
+func (s *stubRecipes) GetRecipesByIDs(ctx context.Context, ids []uuid.UUID) ([]inbound.RecipeDTO, error) {
+	var found []inbound.RecipeDTO
+	for _, id := range ids {
+		if dto, ok := s.recipes[id]; ok {
+			found = append(found, *dto)
+		}
+	}
+	return found, nil
+}
+
+func (s *stubRecipes) TakeDownRecipe(ctx context.Context, id uuid.UUID) error {
+	return s.move(id, recipe.RecipeStatusPublished, recipe.RecipeStatusArchived)
+}
+
+func (s *stubRecipes) ReinstateRecipe(ctx context.Context, id uuid.UUID) error {
+	return s.move(id, recipe.RecipeStatusArchived, recipe.RecipeStatusPublished)
+}
+
+func (s *stubRecipes) move(id uuid.UUID, from, to recipe.RecipeStatus) error {
+	dto := s.recipes[id]
+	if dto.Status != from {
+		return errors.NewConflictError("wrong status")
+	}
+	dto.Status = to
+	return nil
+}
+
+type memoryComments struct {
+	outbound.CommentRepository
+	comments map[uuid.UUID]*comment.Comment
+}
+
+func (m *memoryComments) FindByID(ctx context.Context, id uuid.UUID) (*comment.Comment, error) {
+	return m.comments[id], nil
+}
+
+func (m *memoryComments) UpdateModeration(ctx context.Context, c *comment.Comment) error {
+	m.comments[c.ID()] = c
+	return nil
+}
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+func TestReportsTakeContentDownAtTheThreshold(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	chef := uuid.New()
+	admin := user.ReconstructUser(uuid.New(), "admin@example.com", "Admin", "", true, true, user.UserRoleAdmin, now, now, nil)
+	reader := user.ReconstructUser(uuid.New(), "bo@example.com", "Bo", "", true, true, user.UserRoleUser, now, now, nil)
+	readerID := reader.ID()
+	recipeID := uuid.New()
+	recipes := &stubRecipes{recipes: map[uuid.UUID]*inbound.RecipeDTO{
+		recipeID: {ID: recipeID, Title: "Lemon tart", AuthorID: chef, Status: recipe.RecipeStatusPublished},
+	}}
+	remark := comment.Reconstruct(uuid.New(), recipeID, chef, nil, nil, "Buy cheap pans at example.com", comment.SourceWeb, "", now)
+	comments := &memoryComments{comments: map[uuid.UUID]*comment.Comment{remark.ID(): remark}}
+	reports := &memoryReports{}
+	svc := NewService(reports, recipes, comments, &stubUsers{users: map[uuid.UUID]*user.User{
+		admin.ID(): admin, readerID: reader,
+	}}, Config{Threshold: 2}, zap.NewNop())
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := svc.ReportRecipe(ctx, inbound.ReportContentCommand{TargetID: recipeID, ReporterID: chef, Reason: "spam"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest), "authors cannot report their own recipes")
+	_, err = svc.ReportRecipe(ctx, inbound.ReportContentCommand{TargetID: recipeID, ReporterID: readerID, Reason: "rude"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	first, err := svc.ReportRecipe(ctx, inbound.ReportContentCommand{TargetID: recipeID, ReporterID: readerID, Reason: "copyright", Note: " Copied from a book "})
+	require.NoError(t, err)
+	assert.Equal(t, "Copied from a book", first.Note)
+	assert.False(t, first.Unpublished)
+	_, err = svc.ReportRecipe(ctx, inbound.ReportContentCommand{TargetID: recipeID, ReporterID: readerID, Reason: "spam"})
+	assert.True(t, errors.Is(err, errors.CodeConflict), "one open report per reader")
+
+	second, err := svc.ReportRecipe(ctx, inbound.ReportContentCommand{TargetID: recipeID, ReporterID: uuid.New(), Reason: "copyright"})
+	require.NoError(t, err)
+	assert.True(t, second.Unpublished)
+	assert.Equal(t, recipe.RecipeStatusArchived, recipes.recipes[recipeID].Status)
+	_, err = svc.ReportRecipe(ctx, inbound.ReportContentCommand{TargetID: recipeID, ReporterID: uuid.New(), Reason: "spam"})
+	assert.True(t, errors.Is(err, errors.CodeRecipeNotFound), "taken down recipes cannot be reported")
+
+	_, err = svc.ReportComment(ctx, inbound.ReportContentCommand{TargetID: remark.ID(), ReporterID: readerID, Reason: "spam"})
+	require.NoError(t, err)
+	assert.True(t, remark.Visible(), "one report is below the threshold")
+
+	_, err = svc.ListOpenReports(ctx, readerID, 0, 0)
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	page, err := svc.ListOpenReports(ctx, admin.ID(), 0, 0)
+	require.NoError(t, err)
+	require.Len(t, page.Reports, 3)
+	assert.Equal(t, "Lemon tart", page.Reports[0].Summary)
+	assert.Equal(t, 2, page.Reports[0].OpenReports)
+	assert.Equal(t, "Buy cheap pans at example.com", page.Reports[2].Summary)
+
+	dismissed, err := svc.DismissReport(ctx, inbound.ReviewReportCommand{ReportID: first.ID, ModeratorID: admin.ID(), Note: "Original recipe"})
+	require.NoError(t, err)
+	assert.Equal(t, "dismissed", dismissed.Status)
+	assert.Equal(t, recipe.RecipeStatusPublished, recipes.recipes[recipeID].Status, "dismissing puts the recipe back")
+	_, err = svc.ResolveReport(ctx, inbound.ReviewReportCommand{ReportID: second.ID, ModeratorID: admin.ID()})
+	assert.True(t, errors.Is(err, errors.CodeConflict), "dismissing closed every report on the recipe")
+
+	commentReport := page.Reports[2]
+	resolved, err := svc.ResolveReport(ctx, inbound.ReviewReportCommand{ReportID: commentReport.ID, ModeratorID: admin.ID(), Note: "Spam link"})
+	require.NoError(t, err)
+	assert.Equal(t, "resolved", resolved.Status)
+	assert.Equal(t, comment.StatusRemoved, remark.Status())
+
+	page, err = svc.ListOpenReports(ctx, admin.ID(), 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, page.Reports)
+}
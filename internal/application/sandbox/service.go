@@ -0,0 +1,89 @@
+// Package sandbox reports on the public sandbox: the banner visitors see,
+// when its data is next wiped, and the emails and announcements it captured
+// instead of sending.
+package sandbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Config describes the sandbox
+type Config struct {
+	Enabled   bool
+	Banner    string
+	ResetHour int // UTC hour the data is wiped
+}
+
+// Service implements inbound.SandboxService
+type Service struct {
+	outbox   outbound.SandboxOutbox
+	userRepo outbound.UserRepository
+	cfg      Config
+	now      func() time.Time
+	logger   *zap.Logger
+}
+
+// NewService creates the sandbox service
+func NewService(outbox outbound.SandboxOutbox, userRepo outbound.UserRepository, cfg Config, logger *zap.Logger) *Service {
+	return &Service{
+		outbox:   outbox,
+		userRepo: userRepo,
+		cfg:      cfg,
+		now:      time.Now,
+		logger:   logger.Named("sandbox"),
+	}
+}
+
+// Status reports whether this is a sandbox and when it is next reset
+func (s *Service) Status(ctx context.Context) *inbound.SandboxStatus {
+	if !s.cfg.Enabled {
+		return &inbound.SandboxStatus{}
+	}
+	next := NextReset(s.now(), s.cfg.ResetHour)
+	return &inbound.SandboxStatus{
+		Enabled:     true,
+		Banner:      s.cfg.Banner,
+		NextResetAt: &next,
+	}
+}
+
+// Outbox lists the captured messages for admins
+func (s *Service) Outbox(ctx context.Context, requesterID uuid.UUID) ([]inbound.CapturedMessage, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "read the sandbox outbox"); err != nil {
+		return nil, err
+	}
+	if !s.cfg.Enabled {
+		return nil, errors.NewBadRequestError("sandbox mode is off")
+	}
+
+	captured := s.outbox.Messages()
+	messages := make([]inbound.CapturedMessage, len(captured))
+	for i, m := range captured {
+		messages[i] = inbound.CapturedMessage{
+			Kind:       m.Kind,
+			To:         m.To,
+			Subject:    m.Subject,
+			Body:       m.Body,
+			CapturedAt: m.CapturedAt,
+		}
+	}
+	return messages, nil
+}
+
+// NextReset is the first time after now that the clock reads hour:00 UTC
+func NextReset(now time.Time, hour int) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,69 @@
+package sandbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+type stubOutbox []outbound.CapturedMessage
+
+func (s stubOutbox) Messages() []outbound.CapturedMessage { return s }
+func (s stubOutbox) Clear()                               {}
+
+func TestNextResetIsTheNextOccurrenceOfTheHour(t *testing.T) {
+	before := time.Date(2026, 6, 1, 2, 59, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC), NextReset(before, 3))
+
+	at := time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 6, 2, 3, 0, 0, 0, time.UTC), NextReset(at, 3))
+
+	// Times in other zones are compared in UTC
+	berlin := time.FixedZone("CEST", 2*60*60)
+	assert.Equal(t, time.Date(2026, 6, 2, 3, 0, 0, 0, time.UTC), NextReset(time.Date(2026, 6, 1, 23, 0, 0, 0, berlin), 3))
+}
+
+func TestOutboxIsAdminOnly(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	admin := user.ReconstructUser(uuid.New(), "root@example.com", "Root", "", true, true, user.UserRoleAdmin, now, now, nil)
+	member := user.ReconstructUser(uuid.New(), "sam@example.com", "Sam", "", true, true, user.UserRoleUser, now, now, nil)
+	users := &stubUsers{users: map[uuid.UUID]*user.User{admin.ID(): admin, member.ID(): member}}
+	outbox := stubOutbox{{Kind: "email", To: "sam@example.com", Subject: "Welcome", Body: "Hi", CapturedAt: now}}
+
+	svc := NewService(outbox, users, Config{Enabled: true, Banner: "Sandbox", ResetHour: 3}, zap.NewNop())
+	svc.now = func() time.Time { return now }
+
+	status := svc.Status(context.Background())
+	assert.True(t, status.Enabled)
+	assert.Equal(t, time.Date(2026, 6, 2, 3, 0, 0, 0, time.UTC), *status.NextResetAt)
+
+	messages, err := svc.Outbox(context.Background(), admin.ID())
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Welcome", messages[0].Subject)
+
+	_, err = svc.Outbox(context.Background(), member.ID())
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+
+	off := NewService(outbox, users, Config{}, zap.NewNop())
+	assert.False(t, off.Status(context.Background()).Enabled)
+	_, err = off.Outbox(context.Background(), admin.ID())
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+// Package settings serves the effective configuration reference to admins,
+// so operators can see what a running server was actually configured with.
+package settings
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service implements inbound.ConfigService
+type Service struct {
+	config   outbound.ConfigReader
+	userRepo outbound.UserRepository
+	now      func() time.Time
+	logger   *zap.Logger
+}
+
+// NewService creates a settings service
+func NewService(config outbound.ConfigReader, userRepo outbound.UserRepository, logger *zap.Logger) *Service {
+	return &Service{
+		config:   config,
+		userRepo: userRepo,
+		now:      time.Now,
+		logger:   logger,
+	}
+}
+
+// EffectiveConfig lists the running configuration for admins
+func (s *Service) EffectiveConfig(ctx context.Context, requesterID uuid.UUID) (*inbound.EffectiveConfig, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "view configuration"); err != nil {
+		return nil, err
+	}
+
+	settings := s.config.Settings()
+	reference := &inbound.EffectiveConfig{
+		GeneratedAt: s.now().UTC().Format(time.RFC3339),
+		Settings:    make([]inbound.ConfigSetting, len(settings)),
+	}
+	for i, setting := range settings {
+		reference.Settings[i] = inbound.ConfigSetting{
+			Key:     setting.Key,
+			Value:   setting.Value,
+			Source:  setting.Source,
+			Type:    setting.Type,
+			Default: setting.Default,
+			Rules:   setting.Rules,
+			Env:     setting.Env,
+			Secret:  setting.Secret,
+		}
+	}
+
+	s.logger.Info("Effective configuration read", zap.String("requester_id", requesterID.String()))
+	return reference, nil
+}
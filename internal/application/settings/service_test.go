@@ -0,0 +1,56 @@
+package settings
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+type stubConfig []outbound.ConfigSetting
+
+func (s stubConfig) Settings() []outbound.ConfigSetting {
+	return s
+}
+
+func TestEffectiveConfigIsAdminOnly(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	admin := user.ReconstructUser(uuid.New(), "root@example.com", "Root", "", true, true, user.UserRoleAdmin, now, now, nil)
+	member := user.ReconstructUser(uuid.New(), "sam@example.com", "Sam", "", true, true, user.UserRoleUser, now, now, nil)
+	users := &stubUsers{users: map[uuid.UUID]*user.User{admin.ID(): admin, member.ID(): member}}
+
+	svc := NewService(stubConfig{
+		{Key: "server.port", Env: "ALCHEMORSEL_SERVER_PORT", Type: "int", Default: "8080", Value: 9000, Source: "file"},
+		{Key: "database.password", Type: "string", Secret: true, Value: "********", Source: "env"},
+	}, users, zap.NewNop())
+	svc.now = func() time.Time { return now }
+
+	reference, err := svc.EffectiveConfig(context.Background(), admin.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "2026-06-01T09:00:00Z", reference.GeneratedAt)
+	require.Len(t, reference.Settings, 2)
+	assert.Equal(t, 9000, reference.Settings[0].Value)
+	assert.Equal(t, "file", reference.Settings[0].Source)
+	assert.True(t, reference.Settings[1].Secret)
+
+	_, err = svc.EffectiveConfig(context.Background(), member.ID())
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	_, err = svc.EffectiveConfig(context.Background(), uuid.New())
+	assert.Error(t, err)
+}
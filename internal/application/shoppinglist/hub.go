@@ -0,0 +1,195 @@
+package shoppinglist
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/google/uuid"
+)
+
+const (
+	// subscriberBuffer is how many events a slow subscriber may fall behind
+	// before it is dropped and has to reconnect and replay
+	subscriberBuffer = 64
+	// presenceGrace keeps a user present briefly after their last stream
+	// ends, so routine reconnects do not flicker in other people's lists
+	presenceGrace = 10 * time.Second
+)
+
+// Hub fans list events out to the streams open on this instance and tracks
+// who is viewing each list
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[*subscriber]struct{}
+	// lingering holds users whose last stream closed within presenceGrace
+	lingering map[uuid.UUID]map[uuid.UUID]*lingerer
+	grace     time.Duration
+}
+
+type subscriber struct {
+	listID uuid.UUID
+	userID uuid.UUID
+	name   string
+	events chan inbound.ShoppingListEvent
+	once   sync.Once
+}
+
+type lingerer struct {
+	name  string
+	timer *time.Timer
+}
+
+// NewHub creates an empty hub
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[uuid.UUID]map[*subscriber]struct{}),
+		lingering:   make(map[uuid.UUID]map[uuid.UUID]*lingerer),
+		grace:       presenceGrace,
+	}
+}
+
+// join registers a stream and announces the viewer to everyone on the list
+func (h *Hub) join(listID, userID uuid.UUID, name string) *subscriber {
+	sub := &subscriber{
+		listID: listID,
+		userID: userID,
+		name:   name,
+		events: make(chan inbound.ShoppingListEvent, subscriberBuffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subscribers[listID] == nil {
+		h.subscribers[listID] = make(map[*subscriber]struct{})
+	}
+	h.subscribers[listID][sub] = struct{}{}
+	if l, ok := h.lingering[listID][userID]; ok {
+		l.timer.Stop()
+		delete(h.lingering[listID], userID)
+	}
+	h.broadcastPresenceLocked(listID)
+	return sub
+}
+
+// leave unregisters a stream. The user stays present for the grace period
+// in case they reconnect.
+func (h *Hub) leave(sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(sub)
+
+	for other := range h.subscribers[sub.listID] {
+		if other.userID == sub.userID {
+			return
+		}
+	}
+	if h.lingering[sub.listID] == nil {
+		h.lingering[sub.listID] = make(map[uuid.UUID]*lingerer)
+	}
+	if _, ok := h.lingering[sub.listID][sub.userID]; ok {
+		return
+	}
+	l := &lingerer{name: sub.name}
+	l.timer = time.AfterFunc(h.grace, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.lingering[sub.listID][sub.userID] != l {
+			return
+		}
+		delete(h.lingering[sub.listID], sub.userID)
+		if len(h.lingering[sub.listID]) == 0 {
+			delete(h.lingering, sub.listID)
+		}
+		h.broadcastPresenceLocked(sub.listID)
+	})
+	h.lingering[sub.listID][sub.userID] = l
+}
+
+// publish sends an event to every stream on the list. Streams that cannot
+// keep up are closed rather than allowed to block the others.
+func (h *Hub) publish(listID uuid.UUID, event inbound.ShoppingListEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.publishLocked(listID, event)
+}
+
+// presence lists the users viewing a list, by name
+func (h *Hub) presence(listID uuid.UUID) []inbound.ShoppingListPresence {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.presenceLocked(listID)
+}
+
+// Buffered counts events waiting in stream buffers on this instance, for
+// the leak watchdog
+func (h *Hub) Buffered() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buffered := 0
+	for _, subs := range h.subscribers {
+		for sub := range subs {
+			buffered += len(sub.events)
+		}
+	}
+	return buffered
+}
+
+func (h *Hub) publishLocked(listID uuid.UUID, event inbound.ShoppingListEvent) {
+	for sub := range h.subscribers[listID] {
+		select {
+		case sub.events <- event:
+		default:
+			h.removeLocked(sub)
+		}
+	}
+}
+
+func (h *Hub) removeLocked(sub *subscriber) {
+	if _, ok := h.subscribers[sub.listID][sub]; !ok {
+		return
+	}
+	delete(h.subscribers[sub.listID], sub)
+	if len(h.subscribers[sub.listID]) == 0 {
+		delete(h.subscribers, sub.listID)
+	}
+	sub.once.Do(func() { close(sub.events) })
+}
+
+func (h *Hub) broadcastPresenceLocked(listID uuid.UUID) {
+	h.publishLocked(listID, inbound.ShoppingListEvent{
+		Type: inbound.ShoppingListEventPresence,
+		Data: h.presenceLocked(listID),
+	})
+}
+
+func (h *Hub) presenceLocked(listID uuid.UUID) []inbound.ShoppingListPresence {
+	byUser := make(map[uuid.UUID]*inbound.ShoppingListPresence)
+	for sub := range h.subscribers[listID] {
+		p, ok := byUser[sub.userID]
+		if !ok {
+			p = &inbound.ShoppingListPresence{UserID: sub.userID, Name: sub.name}
+			byUser[sub.userID] = p
+		}
+		p.Sessions++
+	}
+	for userID, l := range h.lingering[listID] {
+		if _, ok := byUser[userID]; !ok {
+			byUser[userID] = &inbound.ShoppingListPresence{UserID: userID, Name: l.name}
+		}
+	}
+
+	presence := make([]inbound.ShoppingListPresence, 0, len(byUser))
+	for _, p := range byUser {
+		presence = append(presence, *p)
+	}
+	sort.Slice(presence, func(i, j int) bool {
+		if presence[i].Name != presence[j].Name {
+			return presence[i].Name < presence[j].Name
+		}
+		return presence[i].UserID.String() < presence[j].UserID.String()
+	})
+	return presence
+}
@@ -0,0 +1,434 @@
+// Package shoppinglist implements shared shopping lists that several people
+// edit at once. Changes are applied as operations, logged with the list
+// version and pushed to everyone viewing the list; clients that were offline
+// send their queued operations on reconnect and replay what they missed
+// from the log.
+package shoppinglist
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/offline"
+	"github.com/alchemorsel/v3/internal/domain/shoppinglist"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxBatchOps bounds the operations accepted in one request; a client
+	// with a longer offline queue sends it in several batches
+	maxBatchOps = 200
+	// replayLimit is the most changes replayed on reconnect before a
+	// snapshot is sent instead
+	replayLimit = 500
+	// saveAttempts retries a batch that raced with another writer
+	saveAttempts = 3
+)
+
+// OutcomeRejected marks an operation that was invalid, such as an empty
+// name or an unknown item. The rest of the batch is still applied.
+const OutcomeRejected = "rejected"
+
+// Service implements inbound.ShoppingListService
+type Service struct {
+	lists    outbound.ShoppingListRepository
+	userRepo outbound.UserRepository
+	hub      *Hub
+	syncFeed outbound.SyncFeed
+	now      func() time.Time
+	logger   *zap.Logger
+}
+
+// NewService creates a shopping list service. The sync feed, if any,
+// points members' devices at lists that changed.
+func NewService(lists outbound.ShoppingListRepository, userRepo outbound.UserRepository, hub *Hub, syncFeed outbound.SyncFeed, logger *zap.Logger) *Service {
+	return &Service{
+		lists:    lists,
+		userRepo: userRepo,
+		hub:      hub,
+		syncFeed: syncFeed,
+		now:      time.Now,
+		logger:   logger.Named("shopping-lists"),
+	}
+}
+
+// CreateList creates an empty list owned by the user
+func (s *Service) CreateList(ctx context.Context, userID uuid.UUID, name string) (*inbound.ShoppingListDTO, error) {
+	list, err := shoppinglist.NewList(userID, name)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	if err := s.lists.Create(ctx, list); err != nil {
+		return nil, errors.NewDatabaseError("create shopping list", err)
+	}
+	s.syncList(ctx, list)
+
+	dto := listToDTO(list)
+	return &dto, nil
+}
+
+// ListLists returns the lists the user owns or shares
+func (s *Service) ListLists(ctx context.Context, userID uuid.UUID) ([]inbound.ShoppingListDTO, error) {
+	lists, err := s.lists.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list shopping lists", err)
+	}
+
+	dtos := make([]inbound.ShoppingListDTO, len(lists))
+	for i, list := range lists {
+		dtos[i] = listToDTO(list)
+	}
+	return dtos, nil
+}
+
+// GetList returns a list with who is viewing it
+func (s *Service) GetList(ctx context.Context, listID, userID uuid.UUID) (*inbound.ShoppingListDTO, error) {
+	list, err := s.editableList(ctx, listID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := listToDTO(list)
+	dto.Presence = s.hub.presence(listID)
+	return &dto, nil
+}
+
+// ShareList lets another registered user edit the list. Only the owner may
+// share it.
+func (s *Service) ShareList(ctx context.Context, cmd inbound.ShareShoppingListCommand) (*inbound.ShoppingListDTO, error) {
+	list, err := s.editableList(ctx, cmd.ListID, cmd.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if list.OwnerID() != cmd.OwnerID {
+		return nil, errors.NewInsufficientPermissionsError("share this shopping list")
+	}
+
+	member, err := s.userRepo.FindByEmail(ctx, strings.TrimSpace(cmd.Email))
+	if err != nil || member == nil {
+		// Lookup failures and unknown addresses read the same so the
+		// endpoint cannot be used to probe for accounts
+		return nil, errors.NewNotFoundError("user")
+	}
+
+	if err := list.AddMember(member.ID()); err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	if err := s.lists.AddMember(ctx, list.ID(), member.ID()); err != nil {
+		return nil, errors.NewDatabaseError("share shopping list", err)
+	}
+	s.syncList(ctx, list)
+
+	s.logger.Info("Shopping list shared",
+		zap.String("list_id", list.ID().String()),
+		zap.String("member_id", member.ID().String()),
+	)
+
+	dto := listToDTO(list)
+	return &dto, nil
+}
+
+// ApplyOperations applies a batch of operations in order. Each operation
+// gets its own outcome; an invalid one is rejected without failing the
+// others. Operations already applied, such as a queue resent after a lost
+// response, are reported as duplicates.
+func (s *Service) ApplyOperations(ctx context.Context, cmd inbound.ApplyShoppingListOpsCommand) (*inbound.ShoppingListSyncResult, error) {
+	if len(cmd.Ops) == 0 {
+		return nil, errors.NewBadRequestError("at least one operation is required")
+	}
+	if len(cmd.Ops) > maxBatchOps {
+		return nil, errors.NewBadRequestError("too many operations in one batch")
+	}
+
+	for attempt := 1; ; attempt++ {
+		result, applied, err := s.applyOnce(ctx, cmd)
+		if stderrors.Is(err, shoppinglist.ErrStaleVersion) {
+			if attempt < saveAttempts {
+				continue
+			}
+			return nil, errors.NewConflictError("shopping list is busy, please retry")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, change := range applied {
+			s.hub.publish(cmd.ListID, inbound.ShoppingListEvent{
+				ID:   change.Version,
+				Type: inbound.ShoppingListEventChange,
+				Data: changeToDTO(change),
+			})
+		}
+		return result, nil
+	}
+}
+
+// applyOnce applies the batch to the stored list and saves it, returning
+// shoppinglist.ErrStaleVersion when another writer saved first
+func (s *Service) applyOnce(ctx context.Context, cmd inbound.ApplyShoppingListOpsCommand) (*inbound.ShoppingListSyncResult, []shoppinglist.Change, error) {
+	list, err := s.editableList(ctx, cmd.ListID, cmd.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	expected := list.Version()
+
+	opIDs := make([]string, len(cmd.Ops))
+	for i, op := range cmd.Ops {
+		opIDs[i] = op.ID
+	}
+	done, err := s.lists.FindAppliedOps(ctx, list.ID(), opIDs)
+	if err != nil {
+		return nil, nil, errors.NewDatabaseError("find applied operations", err)
+	}
+
+	now := s.now()
+	result := &inbound.ShoppingListSyncResult{Changes: make([]inbound.ShoppingListChangeDTO, 0, len(cmd.Ops))}
+	var applied []shoppinglist.Change
+	seen := make(map[string]bool, len(cmd.Ops))
+
+	for _, op := range cmd.Ops {
+		if previous, ok := done[op.ID]; ok || seen[op.ID] {
+			result.Changes = append(result.Changes, duplicateDTO(list, op, previous, cmd.UserID))
+			continue
+		}
+
+		change, err := list.Apply(cmd.UserID, shoppinglist.Operation{
+			ID:          op.ID,
+			Type:        shoppinglist.OpType(op.Type),
+			ItemID:      op.ItemID,
+			Name:        op.Name,
+			Quantity:    op.Quantity,
+			BaseVersion: op.BaseVersion,
+			At:          op.At,
+		}, now)
+		if err != nil {
+			result.Changes = append(result.Changes, inbound.ShoppingListChangeDTO{
+				OpID:    op.ID,
+				Type:    op.Type,
+				UserID:  cmd.UserID,
+				Outcome: OutcomeRejected,
+				Error:   err.Error(),
+				Version: list.Version(),
+			})
+			continue
+		}
+
+		seen[op.ID] = true
+		if change.Outcome == shoppinglist.OutcomeApplied {
+			applied = append(applied, change)
+		}
+		result.Changes = append(result.Changes, changeToDTO(change))
+	}
+
+	if len(applied) > 0 {
+		if err := s.lists.SaveChanges(ctx, list, expected, applied); err != nil {
+			if stderrors.Is(err, shoppinglist.ErrStaleVersion) {
+				return nil, nil, err
+			}
+			return nil, nil, errors.NewDatabaseError("save shopping list", err)
+		}
+		s.syncList(ctx, list)
+	}
+
+	result.Version = list.Version()
+	return result, applied, nil
+}
+
+// syncList points the devices of everyone on the list at its new version
+func (s *Service) syncList(ctx context.Context, list *shoppinglist.List) {
+	if s.syncFeed == nil {
+		return
+	}
+	fields := map[string]interface{}{
+		"name":    list.Name(),
+		"version": list.Version(),
+	}
+	for _, userID := range append([]uuid.UUID{list.OwnerID()}, list.MemberIDs()...) {
+		s.syncFeed.Touch(ctx, userID, offline.KindShoppingList, list.ID().String(), fields)
+	}
+}
+
+// AddItems implements outbound.ShoppingListFeed. The items are added as
+// ordinary operations, so viewers see them live and a retried addition is
+// reported as a duplicate rather than added twice.
+func (s *Service) AddItems(ctx context.Context, listID, userID uuid.UUID, items []outbound.ShoppingListAddition) (int, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+	ops := make([]inbound.ShoppingListOp, len(items))
+	for i, item := range items {
+		ops[i] = inbound.ShoppingListOp{
+			ID:       item.OpID,
+			Type:     string(shoppinglist.OpAdd),
+			Name:     item.Name,
+			Quantity: item.Quantity,
+		}
+	}
+	result, err := s.ApplyOperations(ctx, inbound.ApplyShoppingListOpsCommand{ListID: listID, UserID: userID, Ops: ops})
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for _, change := range result.Changes {
+		if change.Outcome == string(shoppinglist.OutcomeApplied) {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// Subscribe joins the list's live stream. The replay brings the client up
+// to date: the changes after Since, or a snapshot when the client has no
+// version or missed more than the log replays.
+func (s *Service) Subscribe(ctx context.Context, cmd inbound.SubscribeShoppingListCommand) (*inbound.ShoppingListSubscription, error) {
+	list, err := s.editableList(ctx, cmd.ListID, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "Someone"
+	if u, err := s.userRepo.FindByID(ctx, cmd.UserID); err == nil && u != nil {
+		name = u.Name()
+	}
+
+	// Join before building the replay so nothing saved in between is lost;
+	// the caller skips live changes the replay already covered
+	sub := s.hub.join(cmd.ListID, cmd.UserID, name)
+	var once sync.Once
+	subscription := &inbound.ShoppingListSubscription{
+		Events: sub.events,
+		Close:  func() { once.Do(func() { s.hub.leave(sub) }) },
+	}
+
+	replay, err := s.replay(ctx, cmd, list.Version())
+	if err != nil {
+		subscription.Close()
+		return nil, err
+	}
+	subscription.Replay = replay
+	return subscription, nil
+}
+
+// replay builds the events a subscriber starts with. A client claiming a
+// version the list never reached, for example after a restore from backup,
+// gets a snapshot.
+func (s *Service) replay(ctx context.Context, cmd inbound.SubscribeShoppingListCommand, version int64) ([]inbound.ShoppingListEvent, error) {
+	if cmd.Since != nil && *cmd.Since >= 0 && *cmd.Since <= version {
+		changes, err := s.lists.FindChangesSince(ctx, cmd.ListID, *cmd.Since, replayLimit+1)
+		if err != nil {
+			return nil, errors.NewDatabaseError("find shopping list changes", err)
+		}
+		if len(changes) <= replayLimit && (len(changes) == 0 || changes[0].Version == *cmd.Since+1) {
+			events := make([]inbound.ShoppingListEvent, len(changes))
+			for i, change := range changes {
+				events[i] = inbound.ShoppingListEvent{
+					ID:   change.Version,
+					Type: inbound.ShoppingListEventChange,
+					Data: changeToDTO(change),
+				}
+			}
+			return events, nil
+		}
+	}
+
+	list, err := s.lists.FindByID(ctx, cmd.ListID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find shopping list", err)
+	}
+	if list == nil {
+		return nil, errors.NewNotFoundError("shopping list")
+	}
+	dto := listToDTO(list)
+	dto.Presence = s.hub.presence(cmd.ListID)
+	return []inbound.ShoppingListEvent{{
+		ID:   list.Version(),
+		Type: inbound.ShoppingListEventSnapshot,
+		Data: dto,
+	}}, nil
+}
+
+// editableList loads a list the user may edit. Lists the user cannot see
+// read as not found.
+func (s *Service) editableList(ctx context.Context, listID, userID uuid.UUID) (*shoppinglist.List, error) {
+	list, err := s.lists.FindByID(ctx, listID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find shopping list", err)
+	}
+	if list == nil || !list.CanEdit(userID) {
+		return nil, errors.NewNotFoundError("shopping list")
+	}
+	return list, nil
+}
+
+// duplicateDTO reports an operation that was already applied, with the item
+// as it stands now
+func duplicateDTO(list *shoppinglist.List, op inbound.ShoppingListOp, previous shoppinglist.Change, userID uuid.UUID) inbound.ShoppingListChangeDTO {
+	dto := inbound.ShoppingListChangeDTO{
+		OpID:    op.ID,
+		Type:    op.Type,
+		UserID:  userID,
+		Outcome: string(shoppinglist.OutcomeDuplicate),
+		Version: list.Version(),
+	}
+	itemID := op.ItemID
+	if previous.Item.ID != uuid.Nil {
+		itemID = previous.Item.ID
+	}
+	if item, ok := list.Item(itemID); ok {
+		itemDTO := itemToDTO(item)
+		dto.Item = &itemDTO
+	}
+	return dto
+}
+
+func listToDTO(list *shoppinglist.List) inbound.ShoppingListDTO {
+	items := list.Items()
+	dto := inbound.ShoppingListDTO{
+		ID:        list.ID(),
+		Name:      list.Name(),
+		OwnerID:   list.OwnerID(),
+		MemberIDs: list.MemberIDs(),
+		Version:   list.Version(),
+		Items:     make([]inbound.ShoppingListItemDTO, len(items)),
+		CreatedAt: list.CreatedAt(),
+		UpdatedAt: list.UpdatedAt(),
+	}
+	if dto.MemberIDs == nil {
+		dto.MemberIDs = []uuid.UUID{}
+	}
+	for i, item := range items {
+		dto.Items[i] = itemToDTO(item)
+	}
+	return dto
+}
+
+func itemToDTO(item shoppinglist.Item) inbound.ShoppingListItemDTO {
+	return inbound.ShoppingListItemDTO{
+		ID:        item.ID,
+		Name:      item.Name,
+		Quantity:  item.Quantity,
+		Checked:   item.Checked,
+		CheckedBy: item.CheckedBy,
+		Removed:   item.Removed,
+		Version:   item.Version,
+	}
+}
+
+func changeToDTO(change shoppinglist.Change) inbound.ShoppingListChangeDTO {
+	item := itemToDTO(change.Item)
+	return inbound.ShoppingListChangeDTO{
+		OpID:    change.OpID,
+		Type:    string(change.Type),
+		UserID:  change.UserID,
+		Outcome: string(change.Outcome),
+		Item:    &item,
+		Version: change.Version,
+	}
+}
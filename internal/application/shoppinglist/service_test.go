@@ -0,0 +1,225 @@
+package shoppinglist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/shoppinglist"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubLists stores lists by value, so every FindByID returns a fresh copy
+// as a database would
+type stubLists struct {
+	outbound.ShoppingListRepository
+	list    *shoppinglist.List
+	changes []shoppinglist.Change
+	// staleSaves fails this many saves as if another writer got there first
+	staleSaves int
+}
+
+func (s *stubLists) FindByID(ctx context.Context, id uuid.UUID) (*shoppinglist.List, error) {
+	if s.list == nil || s.list.ID() != id {
+		return nil, nil
+	}
+	items := s.list.AllItems()
+	copies := make([]*shoppinglist.Item, len(items))
+	for i := range items {
+		copies[i] = &items[i]
+	}
+	return shoppinglist.Reconstruct(s.list.ID(), s.list.OwnerID(), s.list.Name(), s.list.MemberIDs(), copies, s.list.Version(), s.list.CreatedAt(), s.list.UpdatedAt()), nil
+}
+
+func (s *stubLists) SaveChanges(ctx context.Context, list *shoppinglist.List, expectedVersion int64, changes []shoppinglist.Change) error {
+	if s.staleSaves > 0 || expectedVersion != s.list.Version() {
+		s.staleSaves--
+		return shoppinglist.ErrStaleVersion
+	}
+	s.list = list
+	s.changes = append(s.changes, changes...)
+	return nil
+}
+
+func (s *stubLists) FindChangesSince(ctx context.Context, listID uuid.UUID, version int64, limit int) ([]shoppinglist.Change, error) {
+	var found []shoppinglist.Change
+	for _, change := range s.changes {
+		if change.Version > version && len(found) < limit {
+			found = append(found, change)
+		}
+	}
+	return found, nil
+}
+
+func (s *stubLists) FindAppliedOps(ctx context.Context, listID uuid.UUID, opIDs []string) (map[string]shoppinglist.Change, error) {
+	applied := make(map[string]shoppinglist.Change)
+	for _, change := range s.changes {
+		for _, id := range opIDs {
+			if change.OpID == id {
+				applied[id] = change
+			}
+		}
+	}
+	return applied, nil
+}
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+func newFixture(t *testing.T) (*Service, *stubLists, *user.User) {
+	t.Helper()
+	now := time.Now()
+	owner := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	list, err := shoppinglist.NewList(owner.ID(), "Saturday market")
+	require.NoError(t, err)
+
+	lists := &stubLists{list: list}
+	users := &stubUsers{users: map[uuid.UUID]*user.User{owner.ID(): owner}}
+	return NewService(lists, users, NewHub(), nil, zap.NewNop()), lists, owner
+}
+
+func TestApplyOperationsReportsEachOutcome(t *testing.T) {
+	svc, lists, owner := newFixture(t)
+	ctx := context.Background()
+	listID := lists.list.ID()
+	lemons, milk := uuid.New(), uuid.New()
+
+	sub, err := svc.Subscribe(ctx, inbound.SubscribeShoppingListCommand{ListID: listID, UserID: owner.ID()})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	batch := []inbound.ShoppingListOp{
+		{ID: "c1-1", Type: "add", ItemID: lemons, Name: "Lemons"},
+		{ID: "c1-2", Type: "add", ItemID: milk, Name: "Milk"},
+		{ID: "c1-2", Type: "add", ItemID: milk, Name: "Milk"},
+		{ID: "c1-3", Type: "check", ItemID: lemons, BaseVersion: 1},
+		{ID: "c1-4", Type: "check", ItemID: uuid.New()},
+		{ID: "c1-5", Type: "add", Name: ""},
+	}
+	result, err := svc.ApplyOperations(ctx, inbound.ApplyShoppingListOpsCommand{ListID: listID, UserID: owner.ID(), Ops: batch})
+	require.NoError(t, err)
+
+	outcomes := make([]string, len(result.Changes))
+	for i, change := range result.Changes {
+		outcomes[i] = change.Outcome
+	}
+	assert.Equal(t, []string{"applied", "applied", "duplicate", "applied", OutcomeRejected, OutcomeRejected}, outcomes)
+	assert.Equal(t, shoppinglist.ErrItemNotFound.Error(), result.Changes[4].Error)
+	assert.Equal(t, int64(3), result.Version)
+	assert.Len(t, lists.changes, 3)
+
+	// The offline queue is resent after a lost response
+	result, err = svc.ApplyOperations(ctx, inbound.ApplyShoppingListOpsCommand{ListID: listID, UserID: owner.ID(), Ops: batch[:1]})
+	require.NoError(t, err)
+	assert.Equal(t, "duplicate", result.Changes[0].Outcome)
+	assert.True(t, result.Changes[0].Item.Checked, "duplicates report the item as it stands")
+	assert.Len(t, lists.changes, 3)
+
+	var versions []int64
+	for len(versions) < 3 {
+		event := <-sub.Events
+		if event.Type == inbound.ShoppingListEventChange {
+			versions = append(versions, event.ID)
+		}
+	}
+	assert.Equal(t, []int64{1, 2, 3}, versions)
+
+	_, err = svc.ApplyOperations(ctx, inbound.ApplyShoppingListOpsCommand{ListID: listID, UserID: uuid.New(), Ops: batch})
+	assert.True(t, errors.Is(err, errors.CodeNotFound), "lists not shared with the user read as missing")
+}
+
+func TestApplyOperationsRetriesConcurrentSave(t *testing.T) {
+	svc, lists, owner := newFixture(t)
+	cmd := inbound.ApplyShoppingListOpsCommand{
+		ListID: lists.list.ID(),
+		UserID: owner.ID(),
+		Ops:    []inbound.ShoppingListOp{{ID: "c1-1", Type: "add", Name: "Lemons"}},
+	}
+
+	lists.staleSaves = saveAttempts - 1
+	result, err := svc.ApplyOperations(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Version)
+
+	cmd.Ops[0].ID = "c1-2"
+	lists.staleSaves = saveAttempts
+	_, err = svc.ApplyOperations(context.Background(), cmd)
+	assert.True(t, errors.Is(err, errors.CodeConflict))
+}
+
+func TestSubscribeReplaysMissedChanges(t *testing.T) {
+	svc, lists, owner := newFixture(t)
+	ctx := context.Background()
+	listID := lists.list.ID()
+	_, err := svc.ApplyOperations(ctx, inbound.ApplyShoppingListOpsCommand{ListID: listID, UserID: owner.ID(), Ops: []inbound.ShoppingListOp{
+		{ID: "c1-1", Type: "add", Name: "Lemons"},
+		{ID: "c1-2", Type: "add", Name: "Milk"},
+		{ID: "c1-3", Type: "add", Name: "Eggs"},
+	}})
+	require.NoError(t, err)
+
+	replay := func(since *int64) []inbound.ShoppingListEvent {
+		sub, err := svc.Subscribe(ctx, inbound.SubscribeShoppingListCommand{ListID: listID, UserID: owner.ID(), Since: since})
+		require.NoError(t, err)
+		sub.Close()
+		return sub.Replay
+	}
+	version := func(v int64) *int64 { return &v }
+
+	events := replay(version(1))
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(2), events[0].ID)
+	assert.Equal(t, "Milk", events[0].Data.(inbound.ShoppingListChangeDTO).Item.Name)
+
+	assert.Empty(t, replay(version(3)))
+
+	for _, since := range []*int64{nil, version(7)} {
+		events = replay(since)
+		require.Len(t, events, 1)
+		assert.Equal(t, inbound.ShoppingListEventSnapshot, events[0].Type)
+		assert.Equal(t, int64(3), events[0].ID)
+		assert.Len(t, events[0].Data.(inbound.ShoppingListDTO).Items, 3)
+	}
+}
+
+func TestHubPresenceOutlivesReconnects(t *testing.T) {
+	hub := NewHub()
+	hub.grace = 20 * time.Millisecond
+	listID, ada, grace := uuid.New(), uuid.New(), uuid.New()
+
+	watcher := hub.join(listID, grace, "Grace")
+	first := hub.join(listID, ada, "Ada")
+	second := hub.join(listID, ada, "Ada")
+	assert.Equal(t, []inbound.ShoppingListPresence{
+		{UserID: ada, Name: "Ada", Sessions: 2},
+		{UserID: grace, Name: "Grace", Sessions: 1},
+	}, hub.presence(listID))
+
+	hub.leave(first)
+	hub.leave(second)
+	// Leaving closes the stream once its buffered events are read
+	for range first.events {
+	}
+	assert.Len(t, hub.presence(listID), 2, "Ada stays listed in case of a reconnect")
+
+	require.Eventually(t, func() bool { return len(hub.presence(listID)) == 1 }, time.Second, 5*time.Millisecond)
+
+	var last inbound.ShoppingListEvent
+	for len(watcher.events) > 0 {
+		last = <-watcher.events
+	}
+	assert.Equal(t, []inbound.ShoppingListPresence{{UserID: grace, Name: "Grace", Sessions: 1}}, last.Data)
+}
@@ -0,0 +1,213 @@
+// Package substitution suggests ingredient substitutes. The knowledge base
+// is seeded with common swaps; when it has nothing for an ingredient a
+// signed-in user asks about, a model is asked and its suggestions are kept
+// for the next cook. Suggestions that break the requester's diet, bring in
+// one of their allergens or name something they dislike are left out.
+package substitution
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/allergens"
+	"github.com/alchemorsel/v3/internal/domain/recipe/substitutes"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// BuiltInSource marks the seeded substitutes
+	BuiltInSource = "built-in"
+	// maxIngredientLength matches the stored ingredient names
+	maxIngredientLength = 100
+	// maxSuggested caps what one model answer adds to the knowledge base
+	maxSuggested = 3
+)
+
+// Seed adds the built-in substitutes missing from the knowledge base
+func Seed(ctx context.Context, repo outbound.IngredientSubstituteRepository, logger *zap.Logger) error {
+	added, err := repo.SeedMissing(ctx, substitutes.Reference(), BuiltInSource)
+	if err != nil {
+		return err
+	}
+	if added > 0 {
+		logger.Info("Ingredient substitutes seeded", zap.Int("substitutes", added))
+	}
+	return nil
+}
+
+// Service implements inbound.SubstitutionService
+type Service struct {
+	repo   outbound.IngredientSubstituteRepository
+	users  outbound.UserRepository
+	ai     outbound.AIService
+	logger *zap.Logger
+}
+
+// NewService creates a substitution service. Without an AI service only
+// the knowledge base is consulted.
+func NewService(repo outbound.IngredientSubstituteRepository, users outbound.UserRepository, ai outbound.AIService, logger *zap.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		users:  users,
+		ai:     ai,
+		logger: logger.Named("substitutes"),
+	}
+}
+
+// Substitutes lists what can replace an ingredient for the requester
+func (s *Service) Substitutes(ctx context.Context, query inbound.SubstitutesQuery) (*inbound.IngredientSubstitutes, error) {
+	name := strings.TrimSpace(query.Ingredient)
+	if substitutes.Key(name) == "" {
+		return nil, errors.NewBadRequestError("ingredient is required")
+	}
+	if len(name) > maxIngredientLength {
+		return nil, errors.NewBadRequestError("ingredient must not exceed 100 characters")
+	}
+
+	table, err := s.table(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matched, subs, ok := table.Lookup(name)
+	if !ok && query.RequesterID != uuid.Nil {
+		matched, subs = s.suggest(ctx, name)
+	}
+
+	prefs := s.preferences(ctx, query.RequesterID)
+	result := &inbound.IngredientSubstitutes{
+		Ingredient:  name,
+		Matched:     matched,
+		Substitutes: make([]inbound.IngredientSubstitute, 0, len(subs)),
+	}
+	if prefs != nil {
+		for _, restriction := range prefs.DietaryRestrictions {
+			result.Diets = append(result.Diets, string(restriction))
+		}
+	}
+	for _, sub := range subs {
+		if !suits(sub, prefs) {
+			result.Hidden++
+			continue
+		}
+		contains := make([]string, len(sub.Contains))
+		for i, trait := range sub.Contains {
+			contains[i] = string(trait)
+		}
+		result.Substitutes = append(result.Substitutes, inbound.IngredientSubstitute{
+			Name:      sub.Name,
+			Ratio:     sub.Ratio,
+			Notes:     sub.Notes,
+			Contains:  contains,
+			Suggested: sub.Source != "" && sub.Source != BuiltInSource,
+		})
+	}
+	return result, nil
+}
+
+// table reads the knowledge base, the built-in one while it is unseeded
+func (s *Service) table(ctx context.Context) (*substitutes.Table, error) {
+	subs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list ingredient substitutes", err)
+	}
+	if len(subs) == 0 {
+		return substitutes.Default(), nil
+	}
+	return substitutes.NewTable(subs), nil
+}
+
+// suggest asks the model for substitutes and keeps the sound ones. A model
+// that fails or has nothing to offer leaves the ingredient without any.
+func (s *Service) suggest(ctx context.Context, name string) (string, []substitutes.Substitute) {
+	if s.ai == nil {
+		return "", nil
+	}
+	answer, err := s.ai.SuggestSubstitutes(ctx, name)
+	if err != nil || answer == nil {
+		s.logger.Warn("Substitute suggestions failed", zap.String("ingredient", name), zap.Error(err))
+		return "", nil
+	}
+
+	var subs []substitutes.Substitute
+	for _, suggested := range answer.Substitutes {
+		if len(subs) == maxSuggested {
+			break
+		}
+		contains := make([]substitutes.Trait, 0, len(suggested.Contains))
+		for _, t := range suggested.Contains {
+			if trait, ok := substitutes.ParseTrait(t); ok {
+				contains = append(contains, trait)
+			}
+		}
+		sub, err := substitutes.NewSubstitute(name, suggested.Name, suggested.Ratio, suggested.Notes, contains...)
+		if err != nil || len(sub.Name) > maxIngredientLength {
+			continue
+		}
+		sub.Source = answer.Model
+		subs = append(subs, sub)
+	}
+	if len(subs) == 0 {
+		return "", nil
+	}
+
+	if _, err := s.repo.SeedMissing(ctx, subs, answer.Model); err != nil {
+		// The suggestions still answer this request
+		s.logger.Warn("Failed to keep suggested substitutes", zap.String("ingredient", name), zap.Error(err))
+	}
+	return subs[0].Ingredient, subs
+}
+
+// preferences reads the requester's dietary preferences, none for
+// anonymous readers or when the user cannot be read
+func (s *Service) preferences(ctx context.Context, requesterID uuid.UUID) *user.UserPreferences {
+	if requesterID == uuid.Nil || s.users == nil {
+		return nil
+	}
+	requester, err := s.users.FindByID(ctx, requesterID)
+	if err != nil || requester == nil {
+		return nil
+	}
+	return requester.Preferences()
+}
+
+// suits reports whether a substitute fits the preferences: every dietary
+// restriction, none of the allergies and nothing disliked
+func suits(sub substitutes.Substitute, prefs *user.UserPreferences) bool {
+	if prefs == nil {
+		return true
+	}
+	restrictions := make([]string, len(prefs.DietaryRestrictions))
+	for i, r := range prefs.DietaryRestrictions {
+		restrictions[i] = string(r)
+	}
+	if !sub.Suits(restrictions) {
+		return false
+	}
+
+	name := substitutes.Key(sub.Name)
+	for _, allergy := range prefs.Allergies {
+		allergy = substitutes.Key(allergy)
+		if allergy == "" {
+			continue
+		}
+		if strings.Contains(name, allergy) {
+			return false
+		}
+		for _, a := range allergens.Of(sub.Name) {
+			if string(a) == allergy || substitutes.Key(string(a)) == allergy {
+				return false
+			}
+		}
+	}
+	for _, disliked := range prefs.DislikedIngredients {
+		if d := substitutes.Key(disliked); d != "" && strings.Contains(name, d) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package substitution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/substitutes"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memorySubstitutes struct {
+	subs []substitutes.Substitute
+}
+
+func (m *memorySubstitutes) List(ctx context.Context) ([]substitutes.Substitute, error) {
+	return m.subs, nil
+}
+
+func (m *memorySubstitutes) SeedMissing(ctx context.Context, subs []substitutes.Substitute, source string) (int, error) {
+	for _, s := range subs {
+		s.Source = source
+		m.subs = append(m.subs, s)
+	}
+	return len(subs), nil
+}
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+type stubAI struct {
+	outbound.AIService
+	calls int
+}
+
+func (s *stubAI) SuggestSubstitutes(ctx context.Context, ingredient string) (*outbound.AISubstitutes, error) {
+	s.calls++
+	return &outbound.AISubstitutes{Model: "llama3.2", Substitutes: []outbound.AISubstitute{
+		{Name: "kaffir lime zest", Ratio: "1 tsp per 2 leaves", Contains: []string{}},
+		{Name: ingredient, Ratio: "1 per 1"},
+		{Name: "bay leaf and lime zest", Ratio: "1 bay leaf per 4 leaves", Contains: []string{"unknown"}},
+	}}, nil
+}
+
+func newTestService(t *testing.T) (*Service, *memorySubstitutes, *stubAI, *user.User) {
+	t.Helper()
+	now := time.Now()
+	vegan := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	vegan.UpdatePreferences(&user.UserPreferences{
+		DietaryRestrictions: []user.DietaryRestriction{user.DietaryRestrictionVegan},
+		Allergies:           []string{"tree nuts"},
+		DislikedIngredients: []string{"banana"},
+	})
+
+	repo := &memorySubstitutes{}
+	require.NoError(t, Seed(context.Background(), repo, zap.NewNop()))
+	ai := &stubAI{}
+	users := &stubUsers{users: map[uuid.UUID]*user.User{vegan.ID(): vegan}}
+	return NewService(repo, users, ai, zap.NewNop()), repo, ai, vegan
+}
+
+func names(result *inbound.IngredientSubstitutes) []string {
+	var out []string
+	for _, s := range result.Substitutes {
+		out = append(out, s.Name)
+	}
+	return out
+}
+
+func TestSubstitutesHonorPreferences(t *testing.T) {
+	svc, _, _, vegan := newTestService(t)
+	ctx := context.Background()
+
+	anonymous, err := svc.Substitutes(ctx, inbound.SubstitutesQuery{Ingredient: "2 large eggs"})
+	require.NoError(t, err)
+	assert.Equal(t, "egg", anonymous.Matched)
+	assert.Contains(t, names(anonymous), "mashed banana")
+	assert.Zero(t, anonymous.Hidden)
+
+	forVegan, err := svc.Substitutes(ctx, inbound.SubstitutesQuery{RequesterID: vegan.ID(), Ingredient: "2 large eggs"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vegan"}, forVegan.Diets)
+	assert.NotContains(t, names(forVegan), "mashed banana", "disliked")
+	assert.Contains(t, names(forVegan), "flax egg")
+	assert.Equal(t, 1, forVegan.Hidden)
+
+	milk, err := svc.Substitutes(ctx, inbound.SubstitutesQuery{RequesterID: vegan.ID(), Ingredient: "whole milk"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"oat milk", "soy milk"}, names(milk), "almond milk is a tree nut, butter is dairy")
+}
+
+func TestSubstitutesAskModelOnlyForSignedInUsers(t *testing.T) {
+	svc, repo, ai, vegan := newTestService(t)
+	ctx := context.Background()
+	seeded := len(repo.subs)
+
+	anonymous, err := svc.Substitutes(ctx, inbound.SubstitutesQuery{Ingredient: "makrut lime leaves"})
+	require.NoError(t, err)
+	assert.Empty(t, anonymous.Substitutes)
+	assert.Zero(t, ai.calls)
+
+	suggested, err := svc.Substitutes(ctx, inbound.SubstitutesQuery{RequesterID: vegan.ID(), Ingredient: "Makrut Lime Leaves"})
+	require.NoError(t, err)
+	assert.Equal(t, "makrut lime leaves", suggested.Matched)
+	assert.Equal(t, []string{"kaffir lime zest", "bay leaf and lime zest"}, names(suggested), "the ingredient itself is dropped")
+	assert.True(t, suggested.Substitutes[0].Suggested)
+	assert.Len(t, repo.subs, seeded+2)
+
+	again, err := svc.Substitutes(ctx, inbound.SubstitutesQuery{Ingredient: "makrut lime leaves"})
+	require.NoError(t, err)
+	assert.Len(t, again.Substitutes, 2, "kept for the next cook")
+	assert.Equal(t, 1, ai.calls)
+
+	_, err = svc.Substitutes(ctx, inbound.SubstitutesQuery{Ingredient: "  "})
+	assert.Error(t, err)
+}
@@ -0,0 +1,385 @@
+// Package technique serves the technique library and links recipe steps to
+// it, so cook mode can show how to julienne beside the step that says so.
+package technique
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/domain/recipe/units"
+	"github.com/alchemorsel/v3/internal/domain/technique"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Service implements inbound.TechniqueService
+type Service struct {
+	techniques outbound.TechniqueRepository
+	userRepo   outbound.UserRepository
+	ai         outbound.AIService
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewService creates a technique service. Without an AI service, step
+// suggestions match technique names and aliases in the step text.
+func NewService(repo outbound.TechniqueRepository, userRepo outbound.UserRepository, ai outbound.AIService, logger *zap.Logger) *Service {
+	return &Service{
+		techniques: repo,
+		userRepo:   userRepo,
+		ai:         ai,
+		logger:     logger.Named("techniques"),
+		now:        time.Now,
+	}
+}
+
+// List returns the library in name order
+func (s *Service) List(ctx context.Context) ([]inbound.TechniqueSummary, error) {
+	techniques, err := s.techniques.List(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list techniques", err)
+	}
+	summaries := make([]inbound.TechniqueSummary, len(techniques))
+	for i, t := range techniques {
+		summaries[i] = inbound.TechniqueSummary{
+			Slug:     t.Slug,
+			Name:     t.Name,
+			Summary:  t.Summary,
+			HasVideo: t.Video != nil,
+		}
+	}
+	return summaries, nil
+}
+
+// Get returns a technique's standalone page
+func (s *Service) Get(ctx context.Context, slug string) (*inbound.TechniqueDetail, error) {
+	t, err := s.techniques.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find technique", err)
+	}
+	if t == nil {
+		return nil, errors.NewNotFoundError("technique")
+	}
+	return techniqueDetail(t), nil
+}
+
+// Save creates or replaces a technique; only admins curate the library
+func (s *Service) Save(ctx context.Context, cmd inbound.SaveTechniqueCommand) (*inbound.TechniqueDetail, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, cmd.RequesterID, "edit techniques"); err != nil {
+		return nil, err
+	}
+
+	var video *technique.Video
+	if strings.TrimSpace(cmd.VideoURL) != "" {
+		var err error
+		if video, err = technique.ParseVideo(cmd.VideoURL, cmd.VideoTitle, cmd.VideoDuration); err != nil {
+			return nil, errors.NewBadRequestError(err.Error())
+		}
+	}
+	t, err := technique.New(cmd.Slug, cmd.Name, cmd.Summary, cmd.Steps, cmd.Aliases, video)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	existing, err := s.techniques.FindBySlug(ctx, t.Slug)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find technique", err)
+	}
+	t.UpdatedAt = s.now().UTC()
+	t.CreatedAt = t.UpdatedAt
+	if existing != nil {
+		t.CreatedAt = existing.CreatedAt
+	}
+	if err := s.techniques.Save(ctx, t); err != nil {
+		return nil, errors.NewDatabaseError("save technique", err)
+	}
+
+	s.logger.Info("Technique saved",
+		zap.String("slug", t.Slug),
+		zap.String("user_id", cmd.RequesterID.String()))
+	return techniqueDetail(t), nil
+}
+
+// Delete removes a technique and unlinks it from every recipe step
+func (s *Service) Delete(ctx context.Context, requesterID uuid.UUID, slug string) error {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "delete techniques"); err != nil {
+		return err
+	}
+	deleted, err := s.techniques.Delete(ctx, slug)
+	if err != nil {
+		return errors.NewDatabaseError("delete technique", err)
+	}
+	if !deleted {
+		return errors.NewNotFoundError("technique")
+	}
+	s.logger.Info("Technique deleted", zap.String("slug", slug), zap.String("user_id", requesterID.String()))
+	return nil
+}
+
+// RecipeSteps returns a recipe's numbered steps with their techniques.
+// Temperatures in the steps are converted to the units asked for, else the
+// requester's measurement preference.
+func (s *Service) RecipeSteps(ctx context.Context, requesterID uuid.UUID, recipeID, requestedUnits string) (*inbound.RecipeSteps, error) {
+	system, ok := units.ParseSystem(requestedUnits)
+	if !ok {
+		return nil, errors.NewBadRequestError("units must be one of metric, imperial or original")
+	}
+	r, err := s.recipe(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if r.Status != "published" && r.AuthorID != requesterID {
+		return nil, errors.NewRecipeNotFoundError(recipeID)
+	}
+	steps, err := s.recipeSteps(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if requestedUnits == "" && requesterID != uuid.Nil {
+		if requester, err := s.userRepo.FindByID(ctx, requesterID); err == nil && requester != nil {
+			system, _ = units.ParseSystem(string(requester.MeasurementSystem()))
+		}
+	}
+	for i := range steps.Steps {
+		steps.Steps[i].Text = units.ConvertTemperatures(steps.Steps[i].Text, system)
+	}
+	return steps, nil
+}
+
+// LinkStep sets the techniques of one step, numbered from 1, replacing any
+// suggestions on it. No slugs unlinks the step.
+func (s *Service) LinkStep(ctx context.Context, requesterID uuid.UUID, recipeID string, step int, slugs []string) (*inbound.RecipeSteps, error) {
+	r, err := s.authoredRecipe(ctx, requesterID, recipeID, "link techniques to this recipe")
+	if err != nil {
+		return nil, err
+	}
+	if step < 1 || step > len(r.Steps) {
+		return nil, errors.NewBadRequestError("recipe has no such step")
+	}
+
+	links := make([]outbound.StepTechniqueLink, 0, len(slugs))
+	seen := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		if len(links) == technique.MaxLinksPerStep {
+			return nil, errors.NewBadRequestError(technique.ErrTooManyLinks.Error())
+		}
+		t, err := s.techniques.FindBySlug(ctx, slug)
+		if err != nil {
+			return nil, errors.NewDatabaseError("find technique", err)
+		}
+		if t == nil {
+			return nil, errors.NewBadRequestError("unknown technique: " + slug)
+		}
+		links = append(links, outbound.StepTechniqueLink{
+			RecipeID: r.ID,
+			Step:     step,
+			Slug:     slug,
+			Source:   technique.SourceAuthor,
+			Position: len(links),
+		})
+	}
+
+	if err := s.techniques.ReplaceStepLinks(ctx, r.ID, []int{step}, links); err != nil {
+		return nil, errors.NewDatabaseError("link step techniques", err)
+	}
+	return s.recipeSteps(ctx, r)
+}
+
+// SuggestLinks proposes techniques for the steps the author has not linked,
+// asking the AI service first and matching names in the step text when it
+// has no answer
+func (s *Service) SuggestLinks(ctx context.Context, requesterID uuid.UUID, recipeID string) (*inbound.RecipeSteps, error) {
+	r, err := s.authoredRecipe(ctx, requesterID, recipeID, "suggest techniques for this recipe")
+	if err != nil {
+		return nil, err
+	}
+	library, err := s.techniques.List(ctx)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list techniques", err)
+	}
+	existing, err := s.techniques.StepLinks(ctx, r.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list step techniques", err)
+	}
+
+	authored := make(map[int]bool)
+	for _, link := range existing {
+		if link.Source == technique.SourceAuthor {
+			authored[link.Step] = true
+		}
+	}
+	var steps []int
+	var texts []string
+	for i, text := range r.Steps {
+		if !authored[i+1] {
+			steps = append(steps, i+1)
+			texts = append(texts, text)
+		}
+	}
+	if len(steps) == 0 || len(library) == 0 {
+		return s.recipeSteps(ctx, r)
+	}
+
+	suggested := s.suggest(ctx, texts, library)
+	var links []outbound.StepTechniqueLink
+	for i, step := range steps {
+		for position, slug := range suggested[i] {
+			links = append(links, outbound.StepTechniqueLink{
+				RecipeID: r.ID,
+				Step:     step,
+				Slug:     slug,
+				Source:   technique.SourceSuggested,
+				Position: position,
+			})
+		}
+	}
+	if err := s.techniques.ReplaceStepLinks(ctx, r.ID, steps, links); err != nil {
+		return nil, errors.NewDatabaseError("link step techniques", err)
+	}
+
+	s.logger.Info("Step techniques suggested",
+		zap.String("recipe_id", r.ID.String()),
+		zap.Int("steps", len(steps)),
+		zap.Int("links", len(links)))
+	return s.recipeSteps(ctx, r)
+}
+
+// suggest returns the slugs each step calls for. The AI service answers by
+// technique name; names it invents are dropped.
+func (s *Service) suggest(ctx context.Context, texts []string, library []*technique.Technique) [][]string {
+	bySlug := make(map[string]string, len(library))
+	names := make([]string, len(library))
+	for i, t := range library {
+		names[i] = t.Name
+		bySlug[strings.ToLower(t.Name)] = t.Slug
+	}
+
+	if s.ai != nil {
+		answer, err := s.ai.SuggestStepTechniques(ctx, texts, names)
+		if err == nil && len(answer) == len(texts) {
+			suggested := make([][]string, len(texts))
+			for i, stepNames := range answer {
+				seen := make(map[string]bool)
+				for _, name := range stepNames {
+					slug, ok := bySlug[strings.ToLower(strings.TrimSpace(name))]
+					if ok && !seen[slug] && len(suggested[i]) < technique.MaxLinksPerStep {
+						seen[slug] = true
+						suggested[i] = append(suggested[i], slug)
+					}
+				}
+			}
+			return suggested
+		}
+	}
+
+	suggested := make([][]string, len(texts))
+	for i, text := range texts {
+		suggested[i] = technique.Suggest(text, library)
+	}
+	return suggested
+}
+
+func (s *Service) recipeSteps(ctx context.Context, r *outbound.TechniqueRecipe) (*inbound.RecipeSteps, error) {
+	links, err := s.techniques.StepLinks(ctx, r.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list step techniques", err)
+	}
+	library := make(map[string]*technique.Technique)
+	if len(links) > 0 {
+		techniques, err := s.techniques.List(ctx)
+		if err != nil {
+			return nil, errors.NewDatabaseError("list techniques", err)
+		}
+		for _, t := range techniques {
+			library[t.Slug] = t
+		}
+	}
+
+	result := &inbound.RecipeSteps{
+		RecipeID: r.ID.String(),
+		Title:    r.Title,
+		Steps:    make([]inbound.RecipeStep, len(r.Steps)),
+	}
+	for i, text := range r.Steps {
+		result.Steps[i] = inbound.RecipeStep{Number: i + 1, Text: text, Techniques: []inbound.StepTechnique{}}
+	}
+	// Links to steps the recipe no longer has are kept until the author
+	// relinks them, but not shown
+	for _, link := range links {
+		t, ok := library[link.Slug]
+		if !ok || link.Step < 1 || link.Step > len(result.Steps) {
+			continue
+		}
+		step := &result.Steps[link.Step-1]
+		step.Techniques = append(step.Techniques, inbound.StepTechnique{
+			Slug:    t.Slug,
+			Name:    t.Name,
+			Summary: t.Summary,
+			Source:  string(link.Source),
+			Video:   techniqueVideo(t.Video),
+		})
+	}
+	return result, nil
+}
+
+func (s *Service) recipe(ctx context.Context, recipeID string) (*outbound.TechniqueRecipe, error) {
+	id, err := uuid.Parse(recipeID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid recipe ID")
+	}
+	r, err := s.techniques.RecipeSteps(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if r == nil {
+		return nil, errors.NewRecipeNotFoundError(recipeID)
+	}
+	return r, nil
+}
+
+func (s *Service) authoredRecipe(ctx context.Context, requesterID uuid.UUID, recipeID, action string) (*outbound.TechniqueRecipe, error) {
+	r, err := s.recipe(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if r.AuthorID != requesterID {
+		return nil, errors.NewInsufficientPermissionsError(action)
+	}
+	return r, nil
+}
+
+func techniqueDetail(t *technique.Technique) *inbound.TechniqueDetail {
+	return &inbound.TechniqueDetail{
+		Slug:      t.Slug,
+		Name:      t.Name,
+		Summary:   t.Summary,
+		Steps:     append([]string{}, t.Steps...),
+		Aliases:   append([]string{}, t.Aliases...),
+		Video:     techniqueVideo(t.Video),
+		UpdatedAt: t.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func techniqueVideo(v *technique.Video) *inbound.TechniqueVideo {
+	if v == nil {
+		return nil
+	}
+	return &inbound.TechniqueVideo{
+		Provider: v.Provider,
+		Title:    v.Title,
+		EmbedURL: v.EmbedURL(),
+		WatchURL: v.WatchURL(),
+		Duration: v.Duration,
+		Start:    v.Start,
+	}
+}
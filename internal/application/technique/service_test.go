@@ -0,0 +1,186 @@
+package technique
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/technique"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+type stubAI struct {
+	outbound.AIService
+	answer [][]string
+}
+
+func (s *stubAI) SuggestStepTechniques(ctx context.Context, steps []string, techniques []string) ([][]string, error) {
+	return s.answer, nil
+}
+
+type memoryTechniques struct {
+	techniques map[string]*technique.Technique
+	recipes    map[uuid.UUID]*outbound.TechniqueRecipe
+	links      []outbound.StepTechniqueLink
+}
+
+func (m *memoryTechniques) List(ctx context.Context) ([]*technique.Technique, error) {
+	var list []*technique.Technique
+	for _, t := range m.techniques {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (m *memoryTechniques) FindBySlug(ctx context.Context, slug string) (*technique.Technique, error) {
+	return m.techniques[slug], nil
+}
+
+func (m *memoryTechniques) Save(ctx context.Context, t *technique.Technique) error {
+	m.techniques[t.Slug] = t
+	return nil
+}
+
+func (m *memoryTechniques) Delete(ctx context.Context, slug string) (bool, error) {
+	_, ok := m.techniques[slug]
+	delete(m.techniques, slug)
+	return ok, nil
+}
+
+func (m *memoryTechniques) RecipeSteps(ctx context.Context, recipeID uuid.UUID) (*outbound.TechniqueRecipe, error) {
+	return m.recipes[recipeID], nil
+}
+
+func (m *memoryTechniques) StepLinks(ctx context.Context, recipeID uuid.UUID) ([]outbound.StepTechniqueLink, error) {
+	return m.links, nil
+}
+
+func (m *memoryTechniques) ReplaceStepLinks(ctx context.Context, recipeID uuid.UUID, steps []int, links []outbound.StepTechniqueLink) error {
+	replaced := make(map[int]bool)
+	for _, step := range steps {
+		replaced[step] = true
+	}
+	kept := links
+	for _, link := range m.links {
+		if !replaced[link.Step] {
+			kept = append(kept, link)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].Step < kept[j].Step || kept[i].Step == kept[j].Step && kept[i].Position < kept[j].Position
+	})
+	m.links = kept
+	return nil
+}
+
+func newFixture(t *testing.T) (*Service, *memoryTechniques, uuid.UUID, uuid.UUID) {
+	julienne, err := technique.New("", "Julienne", "Cut into thin matchsticks.", nil, []string{"matchsticks"}, nil)
+	require.NoError(t, err)
+	proof, err := technique.New("proofing-dough", "Proofing dough", "Let yeasted dough rise.", nil, []string{"let rise"}, nil)
+	require.NoError(t, err)
+
+	authorID, recipeID := uuid.New(), uuid.New()
+	repo := &memoryTechniques{
+		techniques: map[string]*technique.Technique{julienne.Slug: julienne, proof.Slug: proof},
+		recipes: map[uuid.UUID]*outbound.TechniqueRecipe{recipeID: {
+			ID:       recipeID,
+			AuthorID: authorID,
+			Title:    "Carrot Rolls",
+			Status:   "draft",
+			Steps:    []string{"Cut the carrots into matchsticks.", "Cover the dough and let rise.", "Bake."},
+		}},
+	}
+	return NewService(repo, &stubUsers{}, nil, zap.NewNop()), repo, authorID, recipeID
+}
+
+func TestRecipeStepsHidesDraftsFromOthers(t *testing.T) {
+	svc, _, authorID, recipeID := newFixture(t)
+
+	steps, err := svc.RecipeSteps(context.Background(), authorID, recipeID.String(), "")
+	require.NoError(t, err)
+	require.Len(t, steps.Steps, 3)
+	assert.Equal(t, 2, steps.Steps[1].Number)
+	assert.Empty(t, steps.Steps[1].Techniques)
+
+	_, err = svc.RecipeSteps(context.Background(), uuid.Nil, recipeID.String(), "")
+	assert.True(t, errors.Is(err, errors.CodeRecipeNotFound))
+}
+
+func TestSuggestLinksKeepsAuthorLinks(t *testing.T) {
+	svc, repo, authorID, recipeID := newFixture(t)
+	ctx := context.Background()
+
+	_, err := svc.LinkStep(ctx, authorID, recipeID.String(), 2, []string{"julienne"})
+	require.NoError(t, err)
+	_, err = svc.LinkStep(ctx, uuid.New(), recipeID.String(), 2, nil)
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	_, err = svc.LinkStep(ctx, authorID, recipeID.String(), 4, nil)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	steps, err := svc.SuggestLinks(ctx, authorID, recipeID.String())
+	require.NoError(t, err)
+	require.Len(t, steps.Steps[0].Techniques, 1)
+	assert.Equal(t, "julienne", steps.Steps[0].Techniques[0].Slug)
+	assert.Equal(t, string(technique.SourceSuggested), steps.Steps[0].Techniques[0].Source)
+	require.Len(t, steps.Steps[1].Techniques, 1, "the author's link is not replaced")
+	assert.Equal(t, "julienne", steps.Steps[1].Techniques[0].Slug)
+	assert.Equal(t, string(technique.SourceAuthor), steps.Steps[1].Techniques[0].Source)
+	assert.Empty(t, steps.Steps[2].Techniques)
+
+	// An AI answer wins over name matching; unknown names are dropped
+	svc.ai = &stubAI{answer: [][]string{{"proofing DOUGH", "Sous vide"}, {}}}
+	steps, err = svc.SuggestLinks(ctx, authorID, recipeID.String())
+	require.NoError(t, err)
+	require.Len(t, steps.Steps[0].Techniques, 1)
+	assert.Equal(t, "proofing-dough", steps.Steps[0].Techniques[0].Slug)
+	assert.Len(t, repo.links, 2)
+}
+
+func TestSaveIsAdminOnly(t *testing.T) {
+	svc, repo, authorID, _ := newFixture(t)
+	now := time.Now()
+	adminID := uuid.New()
+	svc.userRepo = &stubUsers{users: map[uuid.UUID]*user.User{
+		authorID: user.ReconstructUser(authorID, "cook@example.com", "Cook", "", true, true, user.UserRoleUser, now, now, nil),
+		adminID:  user.ReconstructUser(adminID, "root@example.com", "Root", "", true, true, user.UserRoleAdmin, now, now, nil),
+	}}
+
+	cmd := inbound.SaveTechniqueCommand{
+		RequesterID: authorID,
+		Name:        "Blanching",
+		Summary:     "Boil briefly, then plunge into ice water.",
+		VideoURL:    "https://youtu.be/dQw4w9WgXcQ",
+	}
+	_, err := svc.Save(context.Background(), cmd)
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+
+	cmd.RequesterID = adminID
+	detail, err := svc.Save(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "blanching", detail.Slug)
+	require.NotNil(t, detail.Video)
+	assert.Equal(t, "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ", detail.Video.EmbedURL)
+	assert.Contains(t, repo.techniques, "blanching")
+
+	cmd.VideoURL = "https://example.com/video"
+	_, err = svc.Save(context.Background(), cmd)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+}
@@ -0,0 +1,98 @@
+// Package timeline plans recipe timelines: when to start each step so the
+// food is ready at the time the cook chose.
+package timeline
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/timeline"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// maxLead bounds how far ahead a timeline may be planned
+const maxLead = 7 * 24 * time.Hour
+
+// Service implements inbound.RecipeTimelineService
+type Service struct {
+	recipes outbound.RecipeTimelineRepository
+	now     func() time.Time
+}
+
+// NewService creates a recipe timeline service
+func NewService(repo outbound.RecipeTimelineRepository) *Service {
+	return &Service{recipes: repo, now: time.Now}
+}
+
+// Plan schedules the recipe's steps backwards from the serve time. Steps
+// after the completed ones that can no longer make it start now, and the
+// timeline reports how late the food will be.
+func (s *Service) Plan(ctx context.Context, query inbound.TimelineQuery) (*inbound.RecipeTimeline, error) {
+	id, err := uuid.Parse(query.RecipeID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid recipe ID")
+	}
+	if query.CompletedSteps < 0 {
+		return nil, errors.NewBadRequestError("completed steps must not be negative")
+	}
+	now := s.now().UTC().Truncate(time.Minute)
+	if query.ServeAt.After(now.Add(maxLead)) {
+		return nil, errors.NewBadRequestError("serve time must be within a week")
+	}
+
+	r, err := s.recipes.Steps(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe steps", err)
+	}
+	if r == nil || (r.Status != "published" && r.AuthorID != query.RequesterID) {
+		return nil, errors.NewRecipeNotFoundError(query.RecipeID)
+	}
+
+	steps := make([]timeline.Step, len(r.Steps))
+	for i, step := range r.Steps {
+		steps[i] = timeline.NewStep(i+1, step.Text, step.Duration)
+	}
+	completed := query.CompletedSteps
+	if completed > len(steps) {
+		completed = len(steps)
+	}
+
+	serveAt := query.ServeAt.UTC()
+	if serveAt.IsZero() {
+		serveAt = timeline.Forward(steps[completed:], now).ServeAt
+	}
+	plan, behind := timeline.Replan(steps, serveAt, completed, now)
+
+	result := &inbound.RecipeTimeline{
+		RecipeID:       r.ID.String(),
+		Title:          r.Title,
+		ServeAt:        plan.ServeAt.Format(time.RFC3339),
+		RequestedAt:    serveAt.Format(time.RFC3339),
+		StartAt:        plan.Start.Format(time.RFC3339),
+		TotalMinutes:   minutes(plan.Total()),
+		ActiveMinutes:  minutes(plan.Active),
+		BehindMinutes:  minutes(behind),
+		CompletedSteps: completed,
+		Steps:          make([]inbound.TimelineSlot, len(plan.Slots)),
+	}
+	for i, slot := range plan.Slots {
+		result.Steps[i] = inbound.TimelineSlot{
+			Number:   slot.Step.Number,
+			Text:     slot.Step.Text,
+			StartAt:  slot.Start.Format(time.RFC3339),
+			EndAt:    slot.End.Format(time.RFC3339),
+			Minutes:  minutes(slot.Step.Duration),
+			HandsOff: slot.Step.HandsOff,
+			Parallel: slot.Step.Parallel,
+		}
+	}
+	return result, nil
+}
+
+func minutes(d time.Duration) int {
+	return int(math.Ceil(d.Minutes()))
+}
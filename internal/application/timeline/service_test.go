@@ -0,0 +1,65 @@
+package timeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRecipes struct {
+	recipe *outbound.TimelineRecipe
+}
+
+func (s *stubRecipes) Steps(ctx context.Context, recipeID uuid.UUID) (*outbound.TimelineRecipe, error) {
+	if s.recipe == nil || s.recipe.ID != recipeID {
+		return nil, nil
+	}
+	return s.recipe, nil
+}
+
+func TestPlanReportsLateServing(t *testing.T) {
+	authorID := uuid.New()
+	recipe := &outbound.TimelineRecipe{
+		ID:       uuid.New(),
+		AuthorID: authorID,
+		Title:    "Fusion Tacos",
+		Status:   "draft",
+		Steps: []outbound.TimelineStep{
+			{Text: "Marinate beef in Korean BBQ sauce for 30 minutes", Duration: 30 * time.Minute},
+			{Text: "Grill beef until cooked through", Duration: 8 * time.Minute},
+			{Text: "Meanwhile, warm tortillas on a griddle", Duration: 2 * time.Minute},
+			{Text: "Assemble tacos"},
+		},
+	}
+	svc := NewService(&stubRecipes{recipe: recipe})
+	now := time.Date(2026, 10, 18, 18, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	serveAt := now.Add(time.Hour)
+
+	plan, err := svc.Plan(context.Background(), inbound.TimelineQuery{RequesterID: authorID, RecipeID: recipe.ID.String(), ServeAt: serveAt})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-18T18:15:00Z", plan.StartAt)
+	assert.Equal(t, 45, plan.TotalMinutes)
+	assert.Equal(t, 15, plan.ActiveMinutes)
+	assert.Zero(t, plan.BehindMinutes)
+	assert.True(t, plan.Steps[0].HandsOff)
+	assert.True(t, plan.Steps[2].Parallel)
+	assert.Equal(t, 5, plan.Steps[3].Minutes, "a step without a time takes the default")
+
+	// Still marinating ten minutes before serving
+	now = serveAt.Add(-10 * time.Minute)
+	plan, err = svc.Plan(context.Background(), inbound.TimelineQuery{RequesterID: authorID, RecipeID: recipe.ID.String(), ServeAt: serveAt, CompletedSteps: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 5, plan.BehindMinutes)
+	assert.Equal(t, "2026-10-18T19:05:00Z", plan.ServeAt)
+
+	_, err = svc.Plan(context.Background(), inbound.TimelineQuery{RecipeID: recipe.ID.String()})
+	assert.True(t, errors.Is(err, errors.CodeRecipeNotFound), "drafts are only planned for their author")
+}
@@ -0,0 +1,323 @@
+// Package translation machine-translates recipes into other languages,
+// keeps the corrections their authors make, and reports per field who
+// wrote each translation and whether the recipe changed since.
+package translation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/translation"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxFieldLength caps an edited field, as long as a recipe description
+const maxFieldLength = 2000
+
+// Service implements inbound.TranslationService
+type Service struct {
+	recipes outbound.RecipeTranslationRepository
+	ai      outbound.AIService
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+// NewService creates a recipe translation service
+func NewService(repo outbound.RecipeTranslationRepository, ai outbound.AIService, logger *zap.Logger) *Service {
+	return &Service{
+		recipes: repo,
+		ai:      ai,
+		logger:  logger.Named("translations"),
+		now:     time.Now,
+	}
+}
+
+// Languages lists the languages a recipe is translated into
+func (s *Service) Languages(ctx context.Context, query inbound.TranslationQuery) (*inbound.RecipeLanguages, error) {
+	source, err := s.source(ctx, query.RequesterID, query.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	languages, err := s.recipes.Languages(ctx, source.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("list recipe translations", err)
+	}
+
+	result := &inbound.RecipeLanguages{
+		RecipeID:     source.ID.String(),
+		Language:     sourceLanguage(source),
+		Translations: make([]inbound.TranslationSummary, 0, len(languages)),
+	}
+	for _, language := range languages {
+		t, err := s.translation(ctx, source, language)
+		if err != nil {
+			return nil, err
+		}
+		summary := inbound.TranslationSummary{
+			Language: language,
+			Name:     translation.Name(language),
+			Complete: t.Complete,
+		}
+		for _, field := range t.Fields {
+			switch field.Provenance {
+			case string(translation.ProvenanceMachine):
+				summary.Machine++
+			case string(translation.ProvenanceHuman):
+				summary.Human++
+			default:
+				continue
+			}
+			summary.Fields++
+			if field.Stale {
+				summary.Stale++
+			}
+		}
+		result.Translations = append(result.Translations, summary)
+	}
+	return result, nil
+}
+
+// Translation reads a recipe in another language
+func (s *Service) Translation(ctx context.Context, query inbound.TranslationQuery) (*inbound.RecipeTranslation, error) {
+	source, err := s.source(ctx, query.RequesterID, query.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+	language, err := targetLanguage(source, query.Language)
+	if err != nil {
+		return nil, err
+	}
+	return s.translation(ctx, source, language)
+}
+
+// Translate machine-translates the missing and stale machine fields of a
+// recipe. Anyone signed in may ask for a translation of a recipe they can
+// read.
+func (s *Service) Translate(ctx context.Context, cmd inbound.TranslateRecipeCommand) (*inbound.RecipeTranslation, error) {
+	source, err := s.source(ctx, cmd.UserID, cmd.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+	language, err := targetLanguage(source, cmd.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.existing(ctx, source.ID, language)
+	if err != nil {
+		return nil, err
+	}
+	translated := make(map[string]translation.Translated, len(existing))
+	for key, field := range existing {
+		translated[key] = translation.Translated{
+			Field:      field.Field,
+			Text:       field.Text,
+			SourceHash: field.SourceHash,
+			Provenance: translation.Provenance(field.Provenance),
+		}
+	}
+	pending := translation.Pending(sourceText(source).Fields(), translated)
+	if len(pending) == 0 {
+		return s.translation(ctx, source, language)
+	}
+
+	texts := make([]string, len(pending))
+	for i, field := range pending {
+		texts[i] = field.Text
+	}
+	machine, err := s.ai.Translate(ctx, texts, sourceLanguage(source), language)
+	if err == nil && len(machine.Texts) != len(pending) {
+		err = fmt.Errorf("got %d translations for %d texts", len(machine.Texts), len(pending))
+	}
+	if err != nil {
+		return nil, errors.NewExternalServiceError("AI translation", err)
+	}
+
+	now := s.now().UTC()
+	fields := make([]outbound.TranslatedField, 0, len(pending))
+	for i, field := range pending {
+		text := strings.TrimSpace(machine.Texts[i])
+		if text == "" {
+			continue
+		}
+		fields = append(fields, outbound.TranslatedField{
+			RecipeID:   source.ID,
+			Language:   language,
+			Field:      field.Field,
+			Text:       text,
+			SourceHash: translation.Hash(field.Text),
+			Provenance: string(translation.ProvenanceMachine),
+			Model:      machine.Model,
+			UpdatedAt:  now,
+		})
+	}
+	if err := s.recipes.Save(ctx, fields); err != nil {
+		return nil, errors.NewDatabaseError("save recipe translation", err)
+	}
+
+	s.logger.Info("Recipe machine-translated",
+		zap.String("recipe_id", source.ID.String()),
+		zap.String("language", language),
+		zap.Int("fields", len(fields)),
+	)
+	return s.translation(ctx, source, language)
+}
+
+// EditField replaces one translated field with the author's own text,
+// which machine translation then leaves alone
+func (s *Service) EditField(ctx context.Context, cmd inbound.EditTranslationCommand) (*inbound.RecipeTranslation, error) {
+	source, err := s.source(ctx, cmd.UserID, cmd.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+	if source.AuthorID != cmd.UserID {
+		return nil, errors.NewInsufficientPermissionsError("edit this recipe's translations")
+	}
+	language, err := targetLanguage(source, cmd.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	field, err := sourceText(source).Field(cmd.Field)
+	if err != nil {
+		return nil, errors.NewNotFoundError("translation field")
+	}
+	text := strings.TrimSpace(cmd.Text)
+	if text == "" {
+		return nil, errors.NewBadRequestError("text is required")
+	}
+	if len(text) > maxFieldLength {
+		return nil, errors.NewBadRequestError("text must not exceed 2000 characters")
+	}
+
+	editorID := cmd.UserID
+	err = s.recipes.Save(ctx, []outbound.TranslatedField{{
+		RecipeID:   source.ID,
+		Language:   language,
+		Field:      field.Field,
+		Text:       text,
+		SourceHash: translation.Hash(field.Text),
+		Provenance: string(translation.ProvenanceHuman),
+		EditorID:   &editorID,
+		UpdatedAt:  s.now().UTC(),
+	}})
+	if err != nil {
+		return nil, errors.NewDatabaseError("save recipe translation", err)
+	}
+	return s.translation(ctx, source, language)
+}
+
+// source reads a recipe the requester may see
+func (s *Service) source(ctx context.Context, requesterID uuid.UUID, recipeID string) (*outbound.TranslationSource, error) {
+	id, err := uuid.Parse(recipeID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid recipe ID")
+	}
+
+	source, err := s.recipes.Source(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe", err)
+	}
+	if source == nil || (source.Status != "published" && source.AuthorID != requesterID) {
+		return nil, errors.NewRecipeNotFoundError(recipeID)
+	}
+	return source, nil
+}
+
+func (s *Service) existing(ctx context.Context, recipeID uuid.UUID, language string) (map[string]outbound.TranslatedField, error) {
+	fields, err := s.recipes.Fields(ctx, recipeID, language)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find recipe translation", err)
+	}
+	existing := make(map[string]outbound.TranslatedField, len(fields))
+	for _, field := range fields {
+		existing[field.Field] = field
+	}
+	return existing, nil
+}
+
+// translation assembles the recipe in language, field by field
+func (s *Service) translation(ctx context.Context, source *outbound.TranslationSource, language string) (*inbound.RecipeTranslation, error) {
+	existing, err := s.existing(ctx, source.ID, language)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &inbound.RecipeTranslation{
+		RecipeID:       source.ID.String(),
+		SourceLanguage: sourceLanguage(source),
+		Language:       language,
+		Ingredients:    make([]string, len(source.Ingredients)),
+		Steps:          make([]string, len(source.Steps)),
+		Complete:       true,
+	}
+	text := func(field, sourceText string) string {
+		if t, ok := existing[field]; ok {
+			return t.Text
+		}
+		return sourceText
+	}
+	result.Title = text(translation.FieldTitle, source.Title)
+	result.Description = text(translation.FieldDescription, source.Description)
+	for i, ingredient := range source.Ingredients {
+		result.Ingredients[i] = text(translation.IngredientField(i+1), ingredient)
+	}
+	for i, step := range source.Steps {
+		result.Steps[i] = text(translation.StepField(i+1), step)
+	}
+
+	for _, field := range sourceText(source).Fields() {
+		status := inbound.TranslatedFieldStatus{Field: field.Field, Source: field.Text}
+		t, ok := existing[field.Field]
+		if ok {
+			status.Text = t.Text
+			status.Provenance = t.Provenance
+			status.Model = t.Model
+			status.EditorID = t.EditorID
+			status.Stale = t.SourceHash != translation.Hash(field.Text)
+			status.UpdatedAt = t.UpdatedAt.Format(time.RFC3339)
+		}
+		if !ok || status.Stale {
+			result.Complete = false
+		}
+		result.Fields = append(result.Fields, status)
+	}
+	return result, nil
+}
+
+func sourceText(source *outbound.TranslationSource) translation.Source {
+	return translation.Source{
+		Title:       source.Title,
+		Description: source.Description,
+		Ingredients: source.Ingredients,
+		Steps:       source.Steps,
+	}
+}
+
+func sourceLanguage(source *outbound.TranslationSource) string {
+	if source.Language == "" {
+		return recipe.DefaultLanguage
+	}
+	return source.Language
+}
+
+// targetLanguage checks a requested language is supported and not the one
+// the recipe is written in
+func targetLanguage(source *outbound.TranslationSource, tag string) (string, error) {
+	language, ok := translation.Lookup(tag)
+	if !ok {
+		return "", errors.NewBadRequestError("unsupported language: " + tag)
+	}
+	if from, ok := translation.Lookup(sourceLanguage(source)); ok && from.Tag == language.Tag {
+		return "", errors.NewBadRequestError("the recipe is already written in " + language.Name)
+	}
+	return language.Tag, nil
+}
@@ -0,0 +1,111 @@
+package translation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memoryTranslations struct {
+	source *outbound.TranslationSource
+	fields map[string]outbound.TranslatedField
+}
+
+func (m *memoryTranslations) Source(ctx context.Context, recipeID uuid.UUID) (*outbound.TranslationSource, error) {
+	if m.source == nil || m.source.ID != recipeID {
+		return nil, nil
+	}
+	return m.source, nil
+}
+
+func (m *memoryTranslations) Fields(ctx context.Context, recipeID uuid.UUID, language string) ([]outbound.TranslatedField, error) {
+	var fields []outbound.TranslatedField
+	for _, field := range m.fields {
+		if field.RecipeID == recipeID && field.Language == language {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+func (m *memoryTranslations) Languages(ctx context.Context, recipeID uuid.UUID) ([]string, error) {
+	return []string{"es"}, nil
+}
+
+func (m *memoryTranslations) Save(ctx context.Context, fields []outbound.TranslatedField) error {
+	for _, field := range fields {
+		m.fields[field.Language+"/"+field.Field] = field
+	}
+	return nil
+}
+
+type stubAI struct {
+	outbound.AIService
+	calls [][]string
+}
+
+func (s *stubAI) Translate(ctx context.Context, texts []string, from, to string) (*outbound.AITranslation, error) {
+	s.calls = append(s.calls, texts)
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		translated[i] = to + ": " + text
+	}
+	return &outbound.AITranslation{Texts: translated, Model: "stub"}, nil
+}
+
+func TestTranslateKeepsHumanEdits(t *testing.T) {
+	authorID := uuid.New()
+	repo := &memoryTranslations{
+		source: &outbound.TranslationSource{
+			ID:          uuid.New(),
+			AuthorID:    authorID,
+			Status:      "published",
+			Title:       "Lentil Soup",
+			Ingredients: []string{"red lentils"},
+			Steps:       []string{"Simmer for 20 minutes."},
+		},
+		fields: map[string]outbound.TranslatedField{},
+	}
+	ai := &stubAI{}
+	svc := NewService(repo, ai, zap.NewNop())
+	ctx := context.Background()
+	recipeID := repo.source.ID.String()
+
+	translated, err := svc.Translate(ctx, inbound.TranslateRecipeCommand{UserID: uuid.New(), RecipeID: recipeID, Language: "es-MX"})
+	require.NoError(t, err)
+	assert.Equal(t, "es", translated.Language)
+	assert.Equal(t, "en", translated.SourceLanguage)
+	assert.Equal(t, "es: Lentil Soup", translated.Title)
+	assert.True(t, translated.Complete)
+
+	edited, err := svc.EditField(ctx, inbound.EditTranslationCommand{UserID: authorID, RecipeID: recipeID, Language: "es", Field: "steps.1", Text: "Cocina a fuego lento 20 minutos."})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Cocina a fuego lento 20 minutos."}, edited.Steps)
+
+	// The author changes the recipe; the machine title is retranslated and
+	// the human step is only flagged
+	repo.source.Title = "Red Lentil Soup"
+	repo.source.Steps = []string{"Simmer for 25 minutes."}
+	translated, err = svc.Translate(ctx, inbound.TranslateRecipeCommand{UserID: authorID, RecipeID: recipeID, Language: "es"})
+	require.NoError(t, err)
+	require.Len(t, ai.calls, 2)
+	assert.Equal(t, []string{"Red Lentil Soup"}, ai.calls[1])
+	assert.Equal(t, "Cocina a fuego lento 20 minutos.", translated.Steps[0])
+	assert.False(t, translated.Complete)
+	step := translated.Fields[len(translated.Fields)-1]
+	assert.Equal(t, "human", step.Provenance)
+	assert.True(t, step.Stale)
+
+	_, err = svc.EditField(ctx, inbound.EditTranslationCommand{UserID: uuid.New(), RecipeID: recipeID, Language: "es", Field: "title", Text: "Sopa"})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions), "only the author edits translations")
+
+	_, err = svc.Translate(ctx, inbound.TranslateRecipeCommand{UserID: authorID, RecipeID: recipeID, Language: "en-GB"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest), "the recipe is already in English")
+}
@@ -0,0 +1,158 @@
+// Package undo provides short-lived undo tokens for destructive actions.
+// A handler performs the action, records it here and hands the token to the
+// client; redeeming the token before it expires runs the registered restorer.
+package undo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultTTL is how long an undo token stays redeemable
+const DefaultTTL = 10 * time.Second
+
+// Kind identifies the action being undone
+type Kind string
+
+const (
+	KindRecipeDelete    Kind = "recipe.delete"
+	KindRecipeUnpublish Kind = "recipe.unpublish"
+)
+
+// Action is a recorded destructive action that can still be reverted
+type Action struct {
+	Token     string    `json:"token"`
+	Kind      Kind      `json:"kind"`
+	UserID    uuid.UUID `json:"user_id"`
+	SubjectID uuid.UUID `json:"subject_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RestoreFunc reverts an action, normally through the soft-delete layer
+type RestoreFunc func(ctx context.Context, action Action) error
+
+// Service keeps pending undo actions in memory until they expire
+type Service struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	actions   map[string]Action
+	restorers map[Kind]RestoreFunc
+	now       func() time.Time
+	logger    *zap.Logger
+}
+
+// NewService creates an undo service; ttl <= 0 uses DefaultTTL
+func NewService(ttl time.Duration, logger *zap.Logger) *Service {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Service{
+		ttl:       ttl,
+		actions:   make(map[string]Action),
+		restorers: make(map[Kind]RestoreFunc),
+		now:       time.Now,
+		logger:    logger.Named("undo-service"),
+	}
+}
+
+// TTL returns how long tokens stay valid
+func (s *Service) TTL() time.Duration {
+	return s.ttl
+}
+
+// Register installs the restorer for a kind of action
+func (s *Service) Register(kind Kind, restore RestoreFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.restorers[kind] = restore
+}
+
+// Record stores a completed action and returns its undo token
+func (s *Service) Record(kind Kind, userID, subjectID uuid.UUID) (*Action, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, errors.NewInternalError("failed to create undo token").WithCause(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.restorers[kind]; !ok {
+		return nil, errors.NewInternalError(fmt.Sprintf("no undo restorer registered for %s", kind))
+	}
+
+	s.purgeLocked()
+
+	action := Action{
+		Token:     token,
+		Kind:      kind,
+		UserID:    userID,
+		SubjectID: subjectID,
+		ExpiresAt: s.now().Add(s.ttl),
+	}
+	s.actions[token] = action
+	return &action, nil
+}
+
+// Undo redeems a token for the user who recorded it. Each token can be
+// redeemed once; a failed restore leaves it redeemable until it expires.
+func (s *Service) Undo(ctx context.Context, token string, userID uuid.UUID) (*Action, error) {
+	s.mu.Lock()
+	action, ok := s.actions[token]
+	if !ok || !s.now().Before(action.ExpiresAt) {
+		delete(s.actions, token)
+		s.mu.Unlock()
+		return nil, errors.NewNotFoundError("undo token").WithMetadata("reason", "unknown or expired")
+	}
+	if action.UserID != userID {
+		s.mu.Unlock()
+		return nil, errors.NewInsufficientPermissionsError("undo this action")
+	}
+	restore := s.restorers[action.Kind]
+	// Claim the token so concurrent requests can't restore twice
+	delete(s.actions, token)
+	s.mu.Unlock()
+
+	if err := restore(ctx, action); err != nil {
+		s.mu.Lock()
+		if s.now().Before(action.ExpiresAt) {
+			s.actions[token] = action
+		}
+		s.mu.Unlock()
+		return nil, errors.Wrap(err, "failed to undo action")
+	}
+
+	s.logger.Info("Action undone",
+		zap.String("kind", string(action.Kind)),
+		zap.String("subject_id", action.SubjectID.String()),
+		zap.String("user_id", userID.String()),
+	)
+
+	return &action, nil
+}
+
+// purgeLocked drops expired actions; callers must hold mu
+func (s *Service) purgeLocked() {
+	now := s.now()
+	for token, action := range s.actions {
+		if !now.Before(action.ExpiresAt) {
+			delete(s.actions, token)
+		}
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
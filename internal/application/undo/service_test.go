@@ -0,0 +1,83 @@
+package undo
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUndoRestoresOnce(t *testing.T) {
+	svc := NewService(time.Minute, zap.NewNop())
+	var restored []uuid.UUID
+	svc.Register(KindRecipeDelete, func(ctx context.Context, action Action) error {
+		restored = append(restored, action.SubjectID)
+		return nil
+	})
+
+	user, recipe := uuid.New(), uuid.New()
+	action, err := svc.Record(KindRecipeDelete, user, recipe)
+	require.NoError(t, err)
+
+	_, err = svc.Undo(context.Background(), action.Token, uuid.New())
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+
+	_, err = svc.Undo(context.Background(), action.Token, user)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{recipe}, restored)
+
+	_, err = svc.Undo(context.Background(), action.Token, user)
+	assert.True(t, errors.Is(err, errors.CodeNotFound))
+}
+
+func TestUndoExpires(t *testing.T) {
+	svc := NewService(time.Second, zap.NewNop())
+	svc.Register(KindRecipeUnpublish, func(ctx context.Context, action Action) error { return nil })
+
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	user := uuid.New()
+	action, err := svc.Record(KindRecipeUnpublish, user, uuid.New())
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Second)
+	_, err = svc.Undo(context.Background(), action.Token, user)
+	assert.True(t, errors.Is(err, errors.CodeNotFound))
+}
+
+func TestUndoFailedRestoreKeepsToken(t *testing.T) {
+	svc := NewService(time.Minute, zap.NewNop())
+	fail := true
+	svc.Register(KindRecipeDelete, func(ctx context.Context, action Action) error {
+		if fail {
+			return stderrors.New("db down")
+		}
+		return nil
+	})
+
+	user := uuid.New()
+	action, err := svc.Record(KindRecipeDelete, user, uuid.New())
+	require.NoError(t, err)
+
+	_, err = svc.Undo(context.Background(), action.Token, user)
+	require.Error(t, err)
+
+	fail = false
+	_, err = svc.Undo(context.Background(), action.Token, user)
+	assert.NoError(t, err)
+}
+
+func TestRecordRequiresRestorer(t *testing.T) {
+	svc := NewService(0, zap.NewNop())
+	assert.Equal(t, DefaultTTL, svc.TTL())
+
+	_, err := svc.Record(KindRecipeDelete, uuid.New(), uuid.New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+// Package uploadscan scans uploaded photos and imported files for malware
+// before they are processed. Infected files are kept in a quarantine area
+// of blob storage for review rather than discarded, and every scan is
+// recorded with the file's SHA-256 so repeat uploads can be traced.
+package uploadscan
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+	// maxFileNameLength matches the file_name column
+	maxFileNameLength = 255
+	// scannerNone is recorded for uploads taken while scanning is disabled
+	scannerNone = "none"
+)
+
+// Config sets what happens around a scan
+type Config struct {
+	// FailOpen accepts uploads the scanner could not check
+	FailOpen         bool
+	QuarantinePrefix string
+}
+
+// Service implements inbound.UploadScanService
+type Service struct {
+	scanner  outbound.VirusScanner
+	scans    outbound.UploadScanRepository
+	blobs    outbound.BlobStorage
+	userRepo outbound.UserRepository
+	cfg      Config
+	now      func() time.Time
+	logger   *zap.Logger
+}
+
+// NewService creates the upload scan service. A nil scanner disables
+// scanning; uploads are then recorded as skipped.
+func NewService(
+	scanner outbound.VirusScanner,
+	scans outbound.UploadScanRepository,
+	blobs outbound.BlobStorage,
+	userRepo outbound.UserRepository,
+	cfg Config,
+	logger *zap.Logger,
+) *Service {
+	if cfg.QuarantinePrefix == "" {
+		cfg.QuarantinePrefix = "quarantine/"
+	}
+	return &Service{
+		scanner:  scanner,
+		scans:    scans,
+		blobs:    blobs,
+		userRepo: userRepo,
+		cfg:      cfg,
+		now:      time.Now,
+		logger:   logger.Named("upload-scan"),
+	}
+}
+
+// ScanUpload scans one upload and records the result
+func (s *Service) ScanUpload(ctx context.Context, cmd inbound.ScanUploadCommand) error {
+	sum := sha256.Sum256(cmd.Content)
+	scan := &outbound.UploadScan{
+		ID:          uuid.New(),
+		UserID:      cmd.UserID,
+		Source:      cmd.Source,
+		FileName:    truncate(cmd.FileName, maxFileNameLength),
+		ContentType: cmd.ContentType,
+		SizeBytes:   int64(len(cmd.Content)),
+		SHA256:      hex.EncodeToString(sum[:]),
+		ScannedAt:   s.now().UTC(),
+	}
+
+	var rejection error
+	if s.scanner == nil {
+		scan.Scanner = scannerNone
+		scan.Verdict = outbound.UploadScanSkipped
+	} else {
+		scan.Scanner = s.scanner.Name()
+		start := time.Now()
+		result, err := s.scanner.Scan(ctx, cmd.Content)
+		scan.Duration = time.Since(start)
+
+		switch {
+		case err != nil:
+			scan.Verdict = outbound.UploadScanFailed
+			scan.Error = err.Error()
+			s.logger.Error("Upload could not be scanned",
+				zap.String("scan_id", scan.ID.String()),
+				zap.String("scanner", scan.Scanner),
+				zap.Bool("fail_open", s.cfg.FailOpen),
+				zap.Error(err),
+			)
+			if !s.cfg.FailOpen {
+				rejection = errors.NewAppError(errors.CodeServiceUnavailable, "Uploads cannot be scanned right now, try again later", "")
+			}
+		case result.Infected:
+			scan.Verdict = outbound.UploadScanInfected
+			scan.Signature = result.Signature
+			scan.QuarantineKey = s.quarantine(ctx, scan, cmd.Content)
+			s.logger.Warn("Infected upload rejected",
+				zap.String("scan_id", scan.ID.String()),
+				zap.String("user_id", scan.UserID.String()),
+				zap.String("source", scan.Source),
+				zap.String("signature", scan.Signature),
+				zap.String("sha256", scan.SHA256),
+			)
+			rejection = errors.NewAppError(errors.CodeValidationFailed, "The file was rejected by the virus scan", "")
+		default:
+			scan.Verdict = outbound.UploadScanClean
+		}
+	}
+
+	// The verdict stands even when it cannot be recorded
+	if err := s.scans.Save(ctx, scan); err != nil {
+		s.logger.Error("Failed to record upload scan",
+			zap.String("scan_id", scan.ID.String()),
+			zap.String("verdict", scan.Verdict),
+			zap.Error(err),
+		)
+	}
+	return rejection
+}
+
+// quarantine keeps an infected file for review and returns its key, or
+// an empty key when it could not be stored
+func (s *Service) quarantine(ctx context.Context, scan *outbound.UploadScan, content []byte) string {
+	key := fmt.Sprintf("%s%s/%s", s.cfg.QuarantinePrefix, scan.ScannedAt.Format("2006/01/02"), scan.ID)
+	if _, err := s.blobs.Put(ctx, key, "application/octet-stream", bytes.NewReader(content)); err != nil {
+		s.logger.Error("Failed to quarantine infected upload",
+			zap.String("scan_id", scan.ID.String()),
+			zap.Error(err),
+		)
+		return ""
+	}
+	return key
+}
+
+// ListUploadScans returns the latest scans, newest first
+func (s *Service) ListUploadScans(ctx context.Context, requesterID uuid.UUID, query inbound.UploadScanQuery) ([]*inbound.UploadScan, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "view upload scans"); err != nil {
+		return nil, err
+	}
+	switch query.Verdict {
+	case "", outbound.UploadScanClean, outbound.UploadScanInfected, outbound.UploadScanFailed, outbound.UploadScanSkipped:
+	default:
+		return nil, errors.NewBadRequestError("verdict must be clean, infected, failed or skipped")
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	scans, err := s.scans.FindRecent(ctx, query.Verdict, limit)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find upload scans", err)
+	}
+	result := make([]*inbound.UploadScan, len(scans))
+	for i, scan := range scans {
+		result[i] = toScanDTO(scan)
+	}
+	return result, nil
+}
+
+func toScanDTO(scan *outbound.UploadScan) *inbound.UploadScan {
+	return &inbound.UploadScan{
+		ID:          scan.ID,
+		UserID:      scan.UserID,
+		Source:      scan.Source,
+		FileName:    scan.FileName,
+		ContentType: scan.ContentType,
+		SizeBytes:   scan.SizeBytes,
+		SHA256:      scan.SHA256,
+		Scanner:     scan.Scanner,
+		Verdict:     scan.Verdict,
+		Signature:   scan.Signature,
+		Quarantined: scan.QuarantineKey != "",
+		Error:       scan.Error,
+		DurationMS:  scan.Duration.Milliseconds(),
+		ScannedAt:   scan.ScannedAt.Format(time.RFC3339),
+	}
+}
+
+// truncate cuts s to at most n bytes without splitting a character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
@@ -0,0 +1,123 @@
+package uploadscan
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubScanner struct {
+	err error
+}
+
+func (s *stubScanner) Name() string                   { return "stub" }
+func (s *stubScanner) Ping(ctx context.Context) error { return s.err }
+
+func (s *stubScanner) Scan(ctx context.Context, content []byte) (*outbound.VirusScanResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if strings.Contains(string(content), "EICAR") {
+		return &outbound.VirusScanResult{Infected: true, Signature: "Eicar-Test-Signature"}, nil
+	}
+	return &outbound.VirusScanResult{}, nil
+}
+
+type memoryScans struct {
+	scans []*outbound.UploadScan
+}
+
+func (m *memoryScans) Save(ctx context.Context, scan *outbound.UploadScan) error {
+	m.scans = append(m.scans, scan)
+	return nil
+}
+
+func (m *memoryScans) FindRecent(ctx context.Context, verdict string, limit int) ([]*outbound.UploadScan, error) {
+	var out []*outbound.UploadScan
+	for i := len(m.scans) - 1; i >= 0 && len(out) < limit; i-- {
+		if verdict == "" || m.scans[i].Verdict == verdict {
+			out = append(out, m.scans[i])
+		}
+	}
+	return out, nil
+}
+
+type memoryBlobs struct {
+	blobs map[string][]byte
+}
+
+func (m *memoryBlobs) Put(ctx context.Context, key, contentType string, content io.Reader) (int64, error) {
+	data, err := io.ReadAll(content)
+	m.blobs[key] = data
+	return int64(len(data)), err
+}
+
+func (m *memoryBlobs) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return nil, stderrors.New("not used")
+}
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+func TestScanUploadQuarantinesInfectedFiles(t *testing.T) {
+	scanner := &stubScanner{}
+	scans := &memoryScans{}
+	blobs := &memoryBlobs{blobs: map[string][]byte{}}
+	now := time.Now()
+	admin := user.ReconstructUser(uuid.New(), "root@example.com", "Root", "", true, true, user.UserRoleAdmin, now, now, nil)
+	member := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	users := &stubUsers{users: map[uuid.UUID]*user.User{admin.ID(): admin, member.ID(): member}}
+	svc := NewService(scanner, scans, blobs, users, Config{QuarantinePrefix: "quarantine/"}, zap.NewNop())
+	ctx := context.Background()
+	upload := func(content string) error {
+		return svc.ScanUpload(ctx, inbound.ScanUploadCommand{
+			UserID:   member.ID(),
+			Source:   inbound.UploadSourceRecipeLibrary,
+			FileName: "export.paprikarecipes",
+			Content:  []byte(content),
+		})
+	}
+
+	require.NoError(t, upload("a clean export"))
+	err := upload("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*")
+	assert.True(t, errors.Is(err, errors.CodeValidationFailed))
+
+	infected := scans.scans[1]
+	assert.Equal(t, outbound.UploadScanInfected, infected.Verdict)
+	assert.Equal(t, "Eicar-Test-Signature", infected.Signature)
+	assert.Len(t, infected.SHA256, 64)
+	require.Contains(t, blobs.blobs, infected.QuarantineKey)
+	assert.True(t, strings.HasPrefix(infected.QuarantineKey, "quarantine/"))
+
+	// Uploads are refused while the scanner is down unless failing open
+	scanner.err = stderrors.New("connection refused")
+	assert.True(t, errors.Is(upload("another export"), errors.CodeServiceUnavailable))
+	svc.cfg.FailOpen = true
+	assert.NoError(t, upload("another export"))
+	assert.Equal(t, outbound.UploadScanFailed, scans.scans[3].Verdict)
+
+	listed, err := svc.ListUploadScans(ctx, admin.ID(), inbound.UploadScanQuery{Verdict: outbound.UploadScanInfected})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.True(t, listed[0].Quarantined)
+	_, err = svc.ListUploadScans(ctx, member.ID(), inbound.UploadScanQuery{})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+}
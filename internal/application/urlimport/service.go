@@ -0,0 +1,134 @@
+// Package urlimport imports recipes from web pages by address, through the
+// same draft builder as recipe library imports
+package urlimport
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Source names recipes imported from a URL in library import results and logs
+const Source = "url"
+
+// Item statuses reported by inbound.RecipeService.ImportRecipeLibrary
+const (
+	statusCreated   = "created"
+	statusDuplicate = "duplicate"
+)
+
+// Service implements inbound.URLImportService
+type Service struct {
+	recipes inbound.RecipeService
+	pages   outbound.RecipePageReader
+	logger  *zap.Logger
+}
+
+// NewService creates the URL import service
+func NewService(recipes inbound.RecipeService, pages outbound.RecipePageReader, logger *zap.Logger) *Service {
+	return &Service{
+		recipes: recipes,
+		pages:   pages,
+		logger:  logger.Named("url-import"),
+	}
+}
+
+// Preview reads the page and maps its recipe onto a draft without saving it
+func (s *Service) Preview(ctx context.Context, cmd inbound.ImportURLCommand) (*inbound.URLImportPreview, error) {
+	page, err := s.read(ctx, cmd.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := s.recipes.PreviewImportedRecipe(ctx, cmd.UserID, toImported(page))
+	if err != nil {
+		return nil, err
+	}
+	return &inbound.URLImportPreview{
+		SourceURL:     page.URL,
+		Method:        page.Method,
+		ImportPreview: *preview,
+	}, nil
+}
+
+// Import reads the page again and saves its recipe through the library
+// importer, so it is parsed, checked for duplicates and stored exactly as
+// a recipe from an export is
+func (s *Service) Import(ctx context.Context, cmd inbound.ImportURLCommand) (*inbound.URLImportResult, error) {
+	page, err := s.read(ctx, cmd.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.recipes.ImportRecipeLibrary(ctx, inbound.ImportLibraryCommand{
+		UserID:  cmd.UserID,
+		Source:  Source,
+		Recipes: []inbound.ImportedRecipe{toImported(page)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Items) == 0 {
+		return nil, errors.NewInternalError("the page was not imported")
+	}
+
+	item := result.Items[0]
+	imported := &inbound.URLImportResult{
+		Title:     item.Title,
+		Status:    item.Status,
+		Method:    page.Method,
+		SourceURL: page.URL,
+		Warnings:  item.Warnings,
+	}
+	switch {
+	case item.Status == statusCreated && item.RecipeID != nil:
+		imported.RecipeID = *item.RecipeID
+	case item.Status == statusDuplicate && item.DuplicateOf != nil:
+		imported.RecipeID = *item.DuplicateOf
+	default:
+		return nil, errors.NewBadRequestError("The recipe could not be saved: " + item.Error)
+	}
+
+	s.logger.Info("Recipe imported from URL",
+		zap.String("user_id", cmd.UserID.String()),
+		zap.String("recipe_id", imported.RecipeID.String()),
+		zap.String("status", imported.Status),
+		zap.String("method", imported.Method),
+	)
+	return imported, nil
+}
+
+// read checks the address and reads the recipe off the page. Reader
+// errors describe what went wrong with the page, so they are passed on.
+func (s *Service) read(ctx context.Context, rawURL string) (*outbound.RecipePage, error) {
+	pageURL, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		return nil, errors.NewBadRequestError("url must be an http or https page address")
+	}
+
+	page, err := s.pages.ReadRecipePage(ctx, pageURL.String())
+	if err != nil {
+		s.logger.Info("Recipe page not imported", zap.String("url", pageURL.String()), zap.Error(err))
+		return nil, errors.NewBadRequestError("Could not import a recipe from that page: " + err.Error())
+	}
+	return page, nil
+}
+
+func toImported(page *outbound.RecipePage) inbound.ImportedRecipe {
+	return inbound.ImportedRecipe{
+		Title:       page.Title,
+		Description: page.Description,
+		Ingredients: page.Ingredients,
+		Directions:  page.Directions,
+		Servings:    page.Servings,
+		PrepTime:    page.PrepTime,
+		CookTime:    page.CookTime,
+		Tags:        page.Tags,
+		SourceURL:   page.URL,
+	}
+}
@@ -0,0 +1,85 @@
+package urlimport
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// importer creates each new title once and reports repeats as duplicates
+type importer struct {
+	inbound.RecipeService
+	titles map[string]uuid.UUID
+	got    []inbound.ImportLibraryCommand
+}
+
+func (i *importer) ImportRecipeLibrary(_ context.Context, cmd inbound.ImportLibraryCommand) (*inbound.LibraryImportResult, error) {
+	i.got = append(i.got, cmd)
+	title := cmd.Recipes[0].Title
+	if id, ok := i.titles[title]; ok {
+		return &inbound.LibraryImportResult{Items: []inbound.LibraryImportItem{{Title: title, Status: statusDuplicate, DuplicateOf: &id}}}, nil
+	}
+	id := uuid.New()
+	i.titles[title] = id
+	return &inbound.LibraryImportResult{Items: []inbound.LibraryImportItem{{Title: title, Status: statusCreated, RecipeID: &id}}}, nil
+}
+
+func (i *importer) PreviewImportedRecipe(_ context.Context, _ uuid.UUID, imported inbound.ImportedRecipe) (*inbound.ImportPreview, error) {
+	preview := &inbound.ImportPreview{Recipe: inbound.RecipeDTO{Title: imported.Title}}
+	if id, ok := i.titles[imported.Title]; ok {
+		preview.DuplicateOf = &id
+	}
+	return preview, nil
+}
+
+// pages serves one recipe per address and no recipe anywhere else
+type pages map[string]outbound.RecipePage
+
+func (p pages) ReadRecipePage(_ context.Context, url string) (*outbound.RecipePage, error) {
+	page, ok := p[url]
+	if !ok {
+		return nil, stderrors.New("no recipe found on the page")
+	}
+	return &page, nil
+}
+
+func TestImportPreviewsThenSavesDrafts(t *testing.T) {
+	recipes := &importer{titles: map[string]uuid.UUID{}}
+	svc := NewService(recipes, pages{
+		"https://example.com/dal": {URL: "https://example.com/recipes/dal", Method: "microdata", Title: "Dal", Ingredients: []string{"1 cup lentils"}},
+	}, zap.NewNop())
+	cmd := inbound.ImportURLCommand{UserID: uuid.New(), URL: " https://example.com/dal "}
+
+	preview, err := svc.Preview(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/recipes/dal", preview.SourceURL)
+	assert.Equal(t, "microdata", preview.Method)
+	assert.Equal(t, "Dal", preview.Recipe.Title)
+	assert.Nil(t, preview.DuplicateOf)
+	assert.Empty(t, recipes.got)
+
+	first, err := svc.Import(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.Equal(t, statusCreated, first.Status)
+	assert.Equal(t, Source, recipes.got[0].Source)
+	assert.Equal(t, "https://example.com/recipes/dal", recipes.got[0].Recipes[0].SourceURL)
+
+	again, err := svc.Import(context.Background(), cmd)
+	require.NoError(t, err)
+	assert.Equal(t, statusDuplicate, again.Status)
+	assert.Equal(t, first.RecipeID, again.RecipeID)
+
+	_, err = svc.Preview(context.Background(), inbound.ImportURLCommand{UserID: cmd.UserID, URL: "https://example.com/about"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	_, err = svc.Import(context.Background(), inbound.ImportURLCommand{UserID: cmd.UserID, URL: "file:///etc/passwd"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	assert.Len(t, recipes.got, 2)
+}
@@ -4,8 +4,10 @@ package user
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/alchemorsel/v3/internal/domain/recipe/allergens"
 	"github.com/alchemorsel/v3/internal/domain/user"
 	"github.com/alchemorsel/v3/internal/ports/outbound"
 	"github.com/golang-jwt/jwt/v4"
@@ -15,24 +17,27 @@ import (
 
 // UserService implements user management use cases
 type UserService struct {
-	userRepo    outbound.UserRepository
-	cache       outbound.CacheRepository
-	jwtSecret   string
-	logger      *zap.Logger
+	userRepo      outbound.UserRepository
+	cache         outbound.CacheRepository
+	invalidations outbound.CacheInvalidationBus
+	jwtSecret     string
+	logger        *zap.Logger
 }
 
 // NewUserService creates a new user service
 func NewUserService(
 	userRepo outbound.UserRepository,
 	cache outbound.CacheRepository,
+	invalidations outbound.CacheInvalidationBus,
 	jwtSecret string,
 	logger *zap.Logger,
 ) *UserService {
 	return &UserService{
-		userRepo:  userRepo,
-		cache:     cache,
-		jwtSecret: jwtSecret,
-		logger:    logger.Named("user-service"),
+		userRepo:      userRepo,
+		cache:         cache,
+		invalidations: invalidations,
+		jwtSecret:     jwtSecret,
+		logger:        logger.Named("user-service"),
 	}
 }
 
@@ -51,12 +56,15 @@ type LoginCommand struct {
 
 // UserDTO represents user data transfer object
 type UserDTO struct {
-	ID         uuid.UUID `json:"id"`
-	Email      string    `json:"email"`
-	Name       string    `json:"name"`
-	IsVerified bool      `json:"is_verified"`
-	Role       string    `json:"role"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID                 uuid.UUID `json:"id"`
+	Email              string    `json:"email"`
+	Name               string    `json:"name"`
+	IsVerified         bool      `json:"is_verified"`
+	Role               string    `json:"role"`
+	Theme              string    `json:"theme"`
+	PersonalizedSearch bool      `json:"personalized_search"`
+	PrivateProfile     bool      `json:"private_profile"`
+	CreatedAt          time.Time `json:"created_at"`
 }
 
 // AuthResponse contains authentication response data
@@ -172,6 +180,25 @@ func (s *UserService) GetUserByID(ctx context.Context, userID uuid.UUID) (*UserD
 	return &dto, nil
 }
 
+// GetUsersByIDs retrieves several users in one repository round trip.
+// Missing IDs are skipped so callers can report them individually.
+func (s *UserService) GetUsersByIDs(ctx context.Context, userIDs []uuid.UUID) ([]UserDTO, error) {
+	if len(userIDs) == 0 {
+		return []UserDTO{}, nil
+	}
+
+	userEntities, err := s.userRepo.FindByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+
+	dtos := make([]UserDTO, 0, len(userEntities))
+	for _, u := range userEntities {
+		dtos = append(dtos, s.entityToDTO(u))
+	}
+	return dtos, nil
+}
+
 // GetUserByEmail retrieves a user by email
 func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*UserDTO, error) {
 	userEntity, err := s.userRepo.FindByEmail(ctx, email)
@@ -196,6 +223,7 @@ func (s *UserService) UpdateProfile(ctx context.Context, userID uuid.UUID, profi
 		return fmt.Errorf("failed to update profile: %w", err)
 	}
 
+	s.invalidateUser(ctx, userID)
 	s.logger.Info("User profile updated", zap.String("user_id", userID.String()))
 	return nil
 }
@@ -213,10 +241,193 @@ func (s *UserService) UpdatePreferences(ctx context.Context, userID uuid.UUID, p
 		return fmt.Errorf("failed to update preferences: %w", err)
 	}
 
+	s.invalidateUser(ctx, userID)
 	s.logger.Info("User preferences updated", zap.String("user_id", userID.String()))
 	return nil
 }
 
+// SetTheme persists the user's theme preference
+func (s *UserService) SetTheme(ctx context.Context, userID uuid.UUID, theme user.Theme) error {
+	userEntity, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	userEntity.SetTheme(theme)
+
+	if err := s.userRepo.Update(ctx, userEntity); err != nil {
+		return fmt.Errorf("failed to update theme: %w", err)
+	}
+
+	s.invalidateUser(ctx, userID)
+	s.logger.Info("User theme updated",
+		zap.String("user_id", userID.String()),
+		zap.String("theme", string(theme)))
+	return nil
+}
+
+// SetMeasurementSystem persists the units the user reads recipes in
+func (s *UserService) SetMeasurementSystem(ctx context.Context, userID uuid.UUID, system user.MeasurementSystem) error {
+	userEntity, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	userEntity.SetMeasurementSystem(system)
+
+	if err := s.userRepo.Update(ctx, userEntity); err != nil {
+		return fmt.Errorf("failed to update measurement system: %w", err)
+	}
+
+	s.invalidateUser(ctx, userID)
+	s.logger.Info("User measurement system updated",
+		zap.String("user_id", userID.String()),
+		zap.String("units", string(system)))
+	return nil
+}
+
+// SetPersonalization enables or disables personalized search ranking
+func (s *UserService) SetPersonalization(ctx context.Context, userID uuid.UUID, enabled bool) error {
+	userEntity, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	userEntity.SetPersonalization(enabled)
+
+	if err := s.userRepo.Update(ctx, userEntity); err != nil {
+		return fmt.Errorf("failed to update personalization: %w", err)
+	}
+
+	s.invalidateUser(ctx, userID)
+	s.logger.Info("User search personalization updated",
+		zap.String("user_id", userID.String()),
+		zap.Bool("enabled", enabled))
+	return nil
+}
+
+// DietaryProfile is what a user eats: the diets they follow, the allergens
+// they must avoid and the ingredients they would rather not see
+type DietaryProfile struct {
+	DietaryRestrictions []string `json:"dietary_restrictions"`
+	Allergies           []string `json:"allergies"`
+	DislikedIngredients []string `json:"disliked_ingredients"`
+}
+
+// Disliked ingredients are free text, so their number and length are capped
+const (
+	maxDislikedIngredients = 30
+	maxIngredientLength    = 60
+)
+
+// Normalize validates the profile and returns it in canonical form: diet
+// and allergen names as stored, disliked ingredients trimmed and lower
+// case, with repeats dropped
+func (p DietaryProfile) Normalize() (DietaryProfile, error) {
+	normalized := DietaryProfile{DietaryRestrictions: []string{}, Allergies: []string{}, DislikedIngredients: []string{}}
+	seen := make(map[string]bool)
+	keep := func(list *[]string, value string) {
+		if !seen[value] {
+			seen[value] = true
+			*list = append(*list, value)
+		}
+	}
+
+	for _, raw := range p.DietaryRestrictions {
+		restriction, err := user.ParseDietaryRestriction(raw)
+		if err != nil {
+			return DietaryProfile{}, err
+		}
+		keep(&normalized.DietaryRestrictions, string(restriction))
+	}
+	for _, raw := range p.Allergies {
+		allergen, ok := allergens.Parse(raw)
+		if !ok {
+			return DietaryProfile{}, fmt.Errorf("unknown allergen %q", raw)
+		}
+		keep(&normalized.Allergies, string(allergen))
+	}
+	for _, raw := range p.DislikedIngredients {
+		ingredient := strings.ToLower(strings.Join(strings.Fields(raw), " "))
+		if ingredient == "" {
+			continue
+		}
+		if len(ingredient) > maxIngredientLength {
+			return DietaryProfile{}, fmt.Errorf("disliked ingredients must be at most %d characters", maxIngredientLength)
+		}
+		keep(&normalized.DislikedIngredients, ingredient)
+	}
+	if len(normalized.DislikedIngredients) > maxDislikedIngredients {
+		return DietaryProfile{}, fmt.Errorf("at most %d disliked ingredients can be saved", maxDislikedIngredients)
+	}
+	return normalized, nil
+}
+
+// GetDietaryProfile returns the user's diets, allergies and disliked
+// ingredients
+func (s *UserService) GetDietaryProfile(ctx context.Context, userID uuid.UUID) (*DietaryProfile, error) {
+	userEntity, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	profile := &DietaryProfile{DietaryRestrictions: []string{}, Allergies: []string{}, DislikedIngredients: []string{}}
+	if prefs := userEntity.Preferences(); prefs != nil {
+		for _, restriction := range prefs.DietaryRestrictions {
+			profile.DietaryRestrictions = append(profile.DietaryRestrictions, string(restriction))
+		}
+		profile.Allergies = append(profile.Allergies, prefs.Allergies...)
+		profile.DislikedIngredients = append(profile.DislikedIngredients, prefs.DislikedIngredients...)
+	}
+	return profile, nil
+}
+
+// SetDietaryProfile replaces the user's dietary profile. The profile must
+// have been normalized.
+func (s *UserService) SetDietaryProfile(ctx context.Context, userID uuid.UUID, profile DietaryProfile) error {
+	userEntity, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	restrictions := make([]user.DietaryRestriction, len(profile.DietaryRestrictions))
+	for i, restriction := range profile.DietaryRestrictions {
+		restrictions[i] = user.DietaryRestriction(restriction)
+	}
+	userEntity.SetDietaryProfile(restrictions, profile.Allergies, profile.DislikedIngredients)
+
+	if err := s.userRepo.Update(ctx, userEntity); err != nil {
+		return fmt.Errorf("failed to update dietary profile: %w", err)
+	}
+
+	s.invalidateUser(ctx, userID)
+	s.logger.Info("User dietary profile updated",
+		zap.String("user_id", userID.String()),
+		zap.Int("restrictions", len(restrictions)),
+		zap.Int("allergies", len(profile.Allergies)))
+	return nil
+}
+
+// SetPrivateProfile makes the user's profile private or public
+func (s *UserService) SetPrivateProfile(ctx context.Context, userID uuid.UUID, private bool) error {
+	userEntity, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	userEntity.SetPrivateProfile(private)
+
+	if err := s.userRepo.Update(ctx, userEntity); err != nil {
+		return fmt.Errorf("failed to update profile privacy: %w", err)
+	}
+
+	s.invalidateUser(ctx, userID)
+	s.logger.Info("User profile privacy updated",
+		zap.String("user_id", userID.String()),
+		zap.Bool("private", private))
+	return nil
+}
+
 // ChangePassword changes user password
 func (s *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) error {
 	userEntity, err := s.userRepo.FindByID(ctx, userID)
@@ -238,10 +449,25 @@ func (s *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, curr
 		return fmt.Errorf("failed to save password: %w", err)
 	}
 
+	s.invalidateUser(ctx, userID)
 	s.logger.Info("User password changed", zap.String("user_id", userID.String()))
 	return nil
 }
 
+// invalidateUser drops the cached user here and on the other replicas
+func (s *UserService) invalidateUser(ctx context.Context, userID uuid.UUID) {
+	key := "user:" + userID.String()
+	if s.cache != nil {
+		s.cache.Delete(ctx, key)
+	}
+	if s.invalidations == nil {
+		return
+	}
+	if err := s.invalidations.Publish(ctx, key); err != nil {
+		s.logger.Warn("Failed to broadcast user invalidation", zap.String("key", key), zap.Error(err))
+	}
+}
+
 // ValidateToken validates a JWT token and returns user claims
 func (s *UserService) ValidateToken(tokenString string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
@@ -311,12 +537,20 @@ func (s *UserService) generateTokens(userEntity *user.User) (string, string, err
 
 // entityToDTO converts user entity to DTO
 func (s *UserService) entityToDTO(userEntity *user.User) UserDTO {
+	theme := user.ThemeSystem
+	if prefs := userEntity.Preferences(); prefs != nil && prefs.Theme != "" {
+		theme = prefs.Theme
+	}
+
 	return UserDTO{
-		ID:         userEntity.ID(),
-		Email:      userEntity.Email(),
-		Name:       userEntity.Name(),
-		IsVerified: userEntity.IsVerified(),
-		Role:       string(userEntity.Role()),
-		CreatedAt:  userEntity.CreatedAt(),
+		ID:                 userEntity.ID(),
+		Email:              userEntity.Email(),
+		Name:               userEntity.Name(),
+		IsVerified:         userEntity.IsVerified(),
+		Role:               string(userEntity.Role()),
+		Theme:              string(theme),
+		PersonalizedSearch: userEntity.PersonalizationEnabled(),
+		PrivateProfile:     userEntity.IsPrivate(),
+		CreatedAt:          userEntity.CreatedAt(),
 	}
 }
\ No newline at end of file
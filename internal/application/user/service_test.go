@@ -0,0 +1,26 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDietaryProfileNormalize(t *testing.T) {
+	profile, err := DietaryProfile{
+		DietaryRestrictions: []string{"Gluten-free", "vegan", "gluten free"},
+		Allergies:           []string{"nuts", "Dairy", "milk"},
+		DislikedIngredients: []string{"  Blue   Cheese ", "", "blue cheese", "Olives"},
+	}.Normalize()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"gluten_free", "vegan"}, profile.DietaryRestrictions)
+	assert.Equal(t, []string{"tree-nuts", "milk"}, profile.Allergies)
+	assert.Equal(t, []string{"blue cheese", "olives"}, profile.DislikedIngredients)
+
+	_, err = DietaryProfile{DietaryRestrictions: []string{"carnivore"}}.Normalize()
+	assert.Error(t, err)
+	_, err = DietaryProfile{Allergies: []string{"strawberries"}}.Normalize()
+	assert.Error(t, err)
+}
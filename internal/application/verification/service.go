@@ -0,0 +1,419 @@
+// Package verification runs author verification. Chefs and brands claim a
+// badge with proof, admins review the claims, and readers report badges
+// they believe are fake; an upheld report revokes the badge.
+package verification
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/domain/verification"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+	// maxBadgeLookups bounds one Badges call, a page of recipe cards
+	maxBadgeLookups = 200
+)
+
+// Service implements inbound.VerificationService
+type Service struct {
+	repo     outbound.VerificationRepository
+	userRepo outbound.UserRepository
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+// NewService creates the author verification service
+func NewService(repo outbound.VerificationRepository, userRepo outbound.UserRepository, logger *zap.Logger) *Service {
+	return &Service{
+		repo:     repo,
+		userRepo: userRepo,
+		logger:   logger.Named("verification"),
+		now:      time.Now,
+	}
+}
+
+// SubmitClaim files a claim unless the author has an open one or is
+// still waiting out a rejection or revocation
+func (s *Service) SubmitClaim(ctx context.Context, cmd inbound.SubmitVerificationClaimCommand) (*inbound.VerificationClaim, error) {
+	now := s.now().UTC()
+	claim, err := verification.NewClaim(cmd.UserID, verification.Kind(cmd.Kind), cmd.DisplayName, cmd.Statement, cmd.Evidence, now)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	latest, err := s.repo.FindLatestClaim(ctx, cmd.UserID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find verification claim", err)
+	}
+	if latest != nil {
+		if latest.Open() {
+			return nil, errors.NewConflictError("you already have a " + string(latest.Status) + " verification claim")
+		}
+		if until := latest.CanResubmitAt(); now.Before(until) {
+			return nil, errors.NewConflictError("you can claim verification again after " + until.Format(time.RFC3339))
+		}
+	}
+
+	if err := s.repo.SaveClaim(ctx, claim); err != nil {
+		return nil, errors.NewDatabaseError("save verification claim", err)
+	}
+	s.logger.Info("Verification claim submitted",
+		zap.String("claim_id", claim.ID.String()),
+		zap.String("user_id", claim.UserID.String()),
+		zap.String("kind", string(claim.Kind)),
+	)
+	return toClaimDTO(claim), nil
+}
+
+// MyClaim returns the author's latest claim
+func (s *Service) MyClaim(ctx context.Context, userID uuid.UUID) (*inbound.VerificationClaim, error) {
+	claim, err := s.repo.FindLatestClaim(ctx, userID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find verification claim", err)
+	}
+	if claim == nil {
+		return nil, nil
+	}
+	return toClaimDTO(claim), nil
+}
+
+// Badge returns an author's badge
+func (s *Service) Badge(ctx context.Context, userID uuid.UUID) (*inbound.VerificationBadge, error) {
+	badges, err := s.Badges(ctx, []uuid.UUID{userID})
+	if err != nil {
+		return nil, err
+	}
+	return badges[userID], nil
+}
+
+// Badges returns the badges of the verified authors among userIDs
+func (s *Service) Badges(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*inbound.VerificationBadge, error) {
+	seen := make(map[uuid.UUID]bool, len(userIDs))
+	var ids []uuid.UUID
+	for _, id := range userIDs {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) > maxBadgeLookups {
+		return nil, errors.NewBadRequestError("too many authors in one badge lookup")
+	}
+
+	badges := make(map[uuid.UUID]*inbound.VerificationBadge)
+	if len(ids) == 0 {
+		return badges, nil
+	}
+	claims, err := s.repo.FindVerified(ctx, ids)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find verified authors", err)
+	}
+	for _, claim := range claims {
+		if !claim.Verified() {
+			continue
+		}
+		badges[claim.UserID] = toBadgeDTO(claim)
+	}
+	return badges, nil
+}
+
+// ReportClaim files a report against an author's badge
+func (s *Service) ReportClaim(ctx context.Context, cmd inbound.ReportVerificationCommand) (*inbound.VerificationReport, error) {
+	claim, err := s.repo.FindLatestClaim(ctx, cmd.AuthorID)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find verification claim", err)
+	}
+	if claim == nil || !claim.Verified() {
+		return nil, errors.NewNotFoundError("verified author")
+	}
+
+	report, err := verification.NewReport(claim, cmd.ReporterID, verification.Reason(cmd.Reason), cmd.Details, s.now().UTC())
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	open, err := s.repo.FindReports(ctx, outbound.VerificationReportFilter{
+		Status:     verification.ReportOpen,
+		ClaimID:    claim.ID,
+		ReporterID: cmd.ReporterID,
+		Limit:      1,
+	})
+	if err != nil {
+		return nil, errors.NewDatabaseError("find verification reports", err)
+	}
+	if len(open) > 0 {
+		return nil, errors.NewConflictError("you have already reported this author")
+	}
+
+	if err := s.repo.SaveReport(ctx, report); err != nil {
+		return nil, errors.NewDatabaseError("save verification report", err)
+	}
+	s.logger.Info("Verification report filed",
+		zap.String("report_id", report.ID.String()),
+		zap.String("claim_id", claim.ID.String()),
+		zap.String("reason", string(report.Reason)),
+	)
+	return toReportDTO(report), nil
+}
+
+// ListClaims returns claims oldest first; pending by default
+func (s *Service) ListClaims(ctx context.Context, requesterID uuid.UUID, query inbound.VerificationClaimQuery) ([]*inbound.VerificationClaim, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "review verification claims"); err != nil {
+		return nil, err
+	}
+	status := verification.Status(query.Status)
+	switch status {
+	case "":
+		status = verification.StatusPending
+	case verification.StatusPending, verification.StatusApproved, verification.StatusRejected, verification.StatusRevoked:
+	default:
+		return nil, errors.NewBadRequestError("status must be pending, approved, rejected or revoked")
+	}
+
+	claims, err := s.repo.FindClaims(ctx, status, listLimit(query.Limit))
+	if err != nil {
+		return nil, errors.NewDatabaseError("list verification claims", err)
+	}
+	dtos := make([]*inbound.VerificationClaim, 0, len(claims))
+	for _, claim := range claims {
+		dtos = append(dtos, toClaimDTO(claim))
+	}
+	return dtos, nil
+}
+
+// ReviewClaim applies an admin's decision to a claim
+func (s *Service) ReviewClaim(ctx context.Context, cmd inbound.ReviewVerificationClaimCommand) (*inbound.VerificationClaim, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, cmd.ReviewerID, "review verification claims"); err != nil {
+		return nil, err
+	}
+	claim, err := s.findClaim(ctx, cmd.ClaimID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	switch cmd.Decision {
+	case inbound.VerificationApprove:
+		err = claim.Approve(cmd.ReviewerID, cmd.Note, now)
+	case inbound.VerificationReject:
+		err = claim.Reject(cmd.ReviewerID, cmd.Note, now)
+	case inbound.VerificationRevoke:
+		err = claim.Revoke(cmd.ReviewerID, cmd.Note, now)
+	default:
+		return nil, errors.NewBadRequestError("decision must be approve, reject or revoke")
+	}
+	switch err {
+	case nil:
+	case verification.ErrNotPending, verification.ErrNotVerified:
+		return nil, errors.NewConflictError(err.Error())
+	default:
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	if err := s.repo.SaveClaim(ctx, claim); err != nil {
+		return nil, errors.NewDatabaseError("save verification claim", err)
+	}
+	s.logger.Info("Verification claim reviewed",
+		zap.String("claim_id", claim.ID.String()),
+		zap.String("reviewer_id", cmd.ReviewerID.String()),
+		zap.String("status", string(claim.Status)),
+	)
+	return toClaimDTO(claim), nil
+}
+
+// ListReports returns reports oldest first; open by default
+func (s *Service) ListReports(ctx context.Context, requesterID uuid.UUID, query inbound.VerificationReportQuery) ([]*inbound.VerificationReport, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "review verification reports"); err != nil {
+		return nil, err
+	}
+	status := verification.ReportStatus(query.Status)
+	switch status {
+	case "":
+		status = verification.ReportOpen
+	case verification.ReportOpen, verification.ReportDismissed, verification.ReportUpheld:
+	default:
+		return nil, errors.NewBadRequestError("status must be open, dismissed or upheld")
+	}
+
+	reports, err := s.repo.FindReports(ctx, outbound.VerificationReportFilter{Status: status, Limit: listLimit(query.Limit)})
+	if err != nil {
+		return nil, errors.NewDatabaseError("list verification reports", err)
+	}
+	dtos := make([]*inbound.VerificationReport, 0, len(reports))
+	for _, report := range reports {
+		dtos = append(dtos, toReportDTO(report))
+	}
+	return dtos, nil
+}
+
+// ResolveReport dismisses or upholds a report. Upholding revokes the badge
+// and upholds the badge's other open reports with it, since they are about
+// the same claim.
+func (s *Service) ResolveReport(ctx context.Context, cmd inbound.ResolveVerificationReportCommand) (*inbound.VerificationReport, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, cmd.ResolverID, "review verification reports"); err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(cmd.ReportID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid report ID")
+	}
+	report, err := s.repo.FindReport(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find verification report", err)
+	}
+	if report == nil {
+		return nil, errors.NewNotFoundError("verification report")
+	}
+
+	now := s.now().UTC()
+	switch err := report.Resolve(cmd.ResolverID, cmd.Uphold, cmd.Note, now); err {
+	case nil:
+	case verification.ErrReportResolved:
+		return nil, errors.NewConflictError(err.Error())
+	default:
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	if cmd.Uphold {
+		if err := s.revokeForReport(ctx, report, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.SaveReport(ctx, report); err != nil {
+		return nil, errors.NewDatabaseError("save verification report", err)
+	}
+	s.logger.Info("Verification report resolved",
+		zap.String("report_id", report.ID.String()),
+		zap.String("resolver_id", cmd.ResolverID.String()),
+		zap.String("status", string(report.Status)),
+	)
+	return toReportDTO(report), nil
+}
+
+// revokeForReport revokes the reported claim, if still approved, and
+// closes the claim's other open reports
+func (s *Service) revokeForReport(ctx context.Context, report *verification.Report, now time.Time) error {
+	claim, err := s.repo.FindClaim(ctx, report.ClaimID)
+	if err != nil {
+		return errors.NewDatabaseError("find verification claim", err)
+	}
+	if claim != nil && claim.Verified() {
+		note := "Revoked after a report of " + string(report.Reason)
+		if report.Resolution != "" {
+			note = report.Resolution
+		}
+		if err := claim.Revoke(*report.ResolverID, note, now); err != nil {
+			return errors.NewBadRequestError(err.Error())
+		}
+		if err := s.repo.SaveClaim(ctx, claim); err != nil {
+			return errors.NewDatabaseError("save verification claim", err)
+		}
+	}
+
+	others, err := s.repo.FindReports(ctx, outbound.VerificationReportFilter{
+		Status:  verification.ReportOpen,
+		ClaimID: report.ClaimID,
+	})
+	if err != nil {
+		return errors.NewDatabaseError("find verification reports", err)
+	}
+	for _, other := range others {
+		if other.ID == report.ID {
+			continue
+		}
+		if err := other.Resolve(*report.ResolverID, true, report.Resolution, now); err != nil {
+			continue
+		}
+		if err := s.repo.SaveReport(ctx, other); err != nil {
+			return errors.NewDatabaseError("save verification report", err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) findClaim(ctx context.Context, claimID string) (*verification.Claim, error) {
+	id, err := uuid.Parse(claimID)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid claim ID")
+	}
+	claim, err := s.repo.FindClaim(ctx, id)
+	if err != nil {
+		return nil, errors.NewDatabaseError("find verification claim", err)
+	}
+	if claim == nil {
+		return nil, errors.NewNotFoundError("verification claim")
+	}
+	return claim, nil
+}
+
+func listLimit(limit int) int {
+	if limit <= 0 {
+		return defaultListLimit
+	}
+	if limit > maxListLimit {
+		return maxListLimit
+	}
+	return limit
+}
+
+func toBadgeDTO(claim *verification.Claim) *inbound.VerificationBadge {
+	badge := &inbound.VerificationBadge{
+		Kind:        string(claim.Kind),
+		DisplayName: claim.DisplayName,
+	}
+	if claim.ReviewedAt != nil {
+		badge.VerifiedAt = claim.ReviewedAt.Format(time.RFC3339)
+	}
+	return badge
+}
+
+func toClaimDTO(claim *verification.Claim) *inbound.VerificationClaim {
+	dto := &inbound.VerificationClaim{
+		ID:          claim.ID.String(),
+		UserID:      claim.UserID.String(),
+		Kind:        string(claim.Kind),
+		DisplayName: claim.DisplayName,
+		Statement:   claim.Statement,
+		Evidence:    claim.Evidence,
+		Status:      string(claim.Status),
+		ReviewNote:  claim.ReviewNote,
+		SubmittedAt: claim.SubmittedAt.Format(time.RFC3339),
+	}
+	if dto.Evidence == nil {
+		dto.Evidence = []string{}
+	}
+	if claim.ReviewedAt != nil {
+		dto.ReviewedAt = claim.ReviewedAt.Format(time.RFC3339)
+	}
+	if !claim.Open() {
+		dto.CanResubmitAt = claim.CanResubmitAt().Format(time.RFC3339)
+	}
+	return dto
+}
+
+func toReportDTO(report *verification.Report) *inbound.VerificationReport {
+	dto := &inbound.VerificationReport{
+		ID:         report.ID.String(),
+		ClaimID:    report.ClaimID.String(),
+		AuthorID:   report.SubjectID.String(),
+		ReporterID: report.ReporterID.String(),
+		Reason:     string(report.Reason),
+		Details:    report.Details,
+		Status:     string(report.Status),
+		Resolution: report.Resolution,
+		CreatedAt:  report.CreatedAt.Format(time.RFC3339),
+	}
+	if report.ResolvedAt != nil {
+		dto.ResolvedAt = report.ResolvedAt.Format(time.RFC3339)
+	}
+	return dto
+}
@@ -0,0 +1,186 @@
+package verification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/domain/verification"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memoryVerification struct {
+	claims  []*verification.Claim
+	reports []*verification.Report
+}
+
+func (m *memoryVerification) SaveClaim(ctx context.Context, claim *verification.Claim) error {
+	for i, c := range m.claims {
+		if c.ID == claim.ID {
+			m.claims[i] = claim
+			return nil
+		}
+	}
+	m.claims = append(m.claims, claim)
+	return nil
+}
+
+func (m *memoryVerification) FindClaim(ctx context.Context, id uuid.UUID) (*verification.Claim, error) {
+	for _, c := range m.claims {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryVerification) FindLatestClaim(ctx context.Context, userID uuid.UUID) (*verification.Claim, error) {
+	var latest *verification.Claim
+	for _, c := range m.claims {
+		if c.UserID == userID {
+			latest = c
+		}
+	}
+	return latest, nil
+}
+
+func (m *memoryVerification) FindClaims(ctx context.Context, status verification.Status, limit int) ([]*verification.Claim, error) {
+	var claims []*verification.Claim
+	for _, c := range m.claims {
+		if status == "" || c.Status == status {
+			claims = append(claims, c)
+		}
+	}
+	return claims, nil
+}
+
+func (m *memoryVerification) FindVerified(ctx context.Context, userIDs []uuid.UUID) ([]*verification.Claim, error) {
+	var claims []*verification.Claim
+	for _, c := range m.claims {
+		for _, id := range userIDs {
+			if c.UserID == id && c.Verified() {
+				claims = append(claims, c)
+			}
+		}
+	}
+	return claims, nil
+}
+
+func (m *memoryVerification) SaveReport(ctx context.Context, report *verification.Report) error {
+	for i, r := range m.reports {
+		if r.ID == report.ID {
+			m.reports[i] = report
+			return nil
+		}
+	}
+	m.reports = append(m.reports, report)
+	return nil
+}
+
+func (m *memoryVerification) FindReport(ctx context.Context, id uuid.UUID) (*verification.Report, error) {
+	for _, r := range m.reports {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryVerification) FindReports(ctx context.Context, filter outbound.VerificationReportFilter) ([]*verification.Report, error) {
+	var reports []*verification.Report
+	for _, r := range m.reports {
+		if (filter.Status == "" || r.Status == filter.Status) &&
+			(filter.ClaimID == uuid.Nil || r.ClaimID == filter.ClaimID) &&
+			(filter.ReporterID == uuid.Nil || r.ReporterID == filter.ReporterID) {
+			reports = append(reports, r)
+		}
+	}
+	return reports, nil
+}
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+func TestClaimReviewAndFakeClaimReports(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	admin := user.ReconstructUser(uuid.New(), "root@example.com", "Root", "", true, true, user.UserRoleAdmin, now, now, nil)
+	chef := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	reader := user.ReconstructUser(uuid.New(), "bo@example.com", "Bo", "", true, true, user.UserRoleUser, now, now, nil)
+	repo := &memoryVerification{}
+	svc := NewService(repo, &stubUsers{users: map[uuid.UUID]*user.User{
+		admin.ID(): admin, chef.ID(): chef, reader.ID(): reader,
+	}}, zap.NewNop())
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	claim := inbound.SubmitVerificationClaimCommand{
+		UserID:      chef.ID(),
+		Kind:        "chef",
+		DisplayName: "Head chef, Lupa",
+		Evidence:    []string{"https://lupa.example.com/team"},
+	}
+	insecure := claim
+	insecure.Evidence = []string{"http://lupa.example.com/team"}
+	_, err := svc.SubmitClaim(ctx, insecure)
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+
+	submitted, err := svc.SubmitClaim(ctx, claim)
+	require.NoError(t, err)
+	_, err = svc.SubmitClaim(ctx, claim)
+	assert.True(t, errors.Is(err, errors.CodeConflict))
+
+	// Only admins review, and pending claims carry no badge
+	_, err = svc.ReviewClaim(ctx, inbound.ReviewVerificationClaimCommand{ReviewerID: chef.ID(), ClaimID: submitted.ID, Decision: inbound.VerificationApprove})
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	badge, err := svc.Badge(ctx, chef.ID())
+	require.NoError(t, err)
+	assert.Nil(t, badge)
+
+	_, err = svc.ReviewClaim(ctx, inbound.ReviewVerificationClaimCommand{ReviewerID: admin.ID(), ClaimID: submitted.ID, Decision: inbound.VerificationApprove})
+	require.NoError(t, err)
+	badges, err := svc.Badges(ctx, []uuid.UUID{chef.ID(), reader.ID(), chef.ID()})
+	require.NoError(t, err)
+	require.Len(t, badges, 1)
+	assert.Equal(t, "Head chef, Lupa", badges[chef.ID()].DisplayName)
+
+	// Readers report once; authors cannot report themselves
+	_, err = svc.ReportClaim(ctx, inbound.ReportVerificationCommand{ReporterID: chef.ID(), AuthorID: chef.ID(), Reason: "impersonation"})
+	assert.True(t, errors.Is(err, errors.CodeBadRequest))
+	report, err := svc.ReportClaim(ctx, inbound.ReportVerificationCommand{ReporterID: reader.ID(), AuthorID: chef.ID(), Reason: "impersonation"})
+	require.NoError(t, err)
+	_, err = svc.ReportClaim(ctx, inbound.ReportVerificationCommand{ReporterID: reader.ID(), AuthorID: chef.ID(), Reason: "fake_credentials"})
+	assert.True(t, errors.Is(err, errors.CodeConflict))
+
+	// Upholding the report revokes the badge and starts the cooldown
+	resolved, err := svc.ResolveReport(ctx, inbound.ResolveVerificationReportCommand{ResolverID: admin.ID(), ReportID: report.ID, Uphold: true, Note: "Lupa has no such chef"})
+	require.NoError(t, err)
+	assert.Equal(t, "upheld", resolved.Status)
+	badge, err = svc.Badge(ctx, chef.ID())
+	require.NoError(t, err)
+	assert.Nil(t, badge)
+
+	mine, err := svc.MyClaim(ctx, chef.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "revoked", mine.Status)
+	assert.Equal(t, "Lupa has no such chef", mine.ReviewNote)
+	assert.Equal(t, now.Add(verification.RevokedCooldown).Format(time.RFC3339), mine.CanResubmitAt)
+
+	_, err = svc.SubmitClaim(ctx, claim)
+	assert.True(t, errors.Is(err, errors.CodeConflict))
+	svc.now = func() time.Time { return now.Add(verification.RevokedCooldown) }
+	_, err = svc.SubmitClaim(ctx, claim)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,164 @@
+// Package views counts recipe views once per viewer within a window, so a
+// refresh or a reopened tab does not count again. Counted views are
+// buffered and written to the recipe view counters and the daily totals in
+// batches, rather than a database write per visit. Search listings are
+// buffered the same way for the author analytics.
+package views
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultWindow is how long a viewer counts once per recipe
+const DefaultWindow = 30 * time.Minute
+
+// Config sets the dedupe window
+type Config struct {
+	Window time.Duration
+}
+
+// Service implements inbound.ViewService
+type Service struct {
+	buffer      outbound.ViewBuffer
+	impressions outbound.ViewBuffer
+	counters    outbound.RecipeCounterRepository
+	analytics   outbound.RecipeAnalyticsRepository
+	cfg         Config
+	now         func() time.Time
+	logger      *zap.Logger
+}
+
+// NewService creates a view counting service. Impressions are kept in
+// their own buffer, whose FirstView is not used.
+func NewService(buffer, impressions outbound.ViewBuffer, counters outbound.RecipeCounterRepository, analytics outbound.RecipeAnalyticsRepository, cfg Config, logger *zap.Logger) *Service {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultWindow
+	}
+	return &Service{
+		buffer:      buffer,
+		impressions: impressions,
+		counters:    counters,
+		analytics:   analytics,
+		cfg:         cfg,
+		now:         time.Now,
+		logger:      logger.Named("views"),
+	}
+}
+
+// Count buffers a view unless the viewer already viewed the recipe within
+// the window
+func (s *Service) Count(ctx context.Context, recipeID uuid.UUID, userID *uuid.UUID, sessionID string) (bool, error) {
+	var viewer string
+	switch {
+	case userID != nil:
+		viewer = "u:" + userID.String()
+	case sessionID != "":
+		viewer = "s:" + sessionID
+	}
+	if viewer != "" {
+		first, err := s.buffer.FirstView(ctx, recipeID, viewer, s.cfg.Window)
+		if err != nil {
+			return false, errors.NewExternalServiceError("view buffer", err)
+		}
+		if !first {
+			return false, nil
+		}
+	}
+	if err := s.buffer.Add(ctx, recipeID, s.now(), 1); err != nil {
+		return false, errors.NewExternalServiceError("view buffer", err)
+	}
+	return true, nil
+}
+
+// CountImpressions buffers one search listing of each recipe
+func (s *Service) CountImpressions(ctx context.Context, recipeIDs []uuid.UUID) error {
+	now := s.now()
+	for _, recipeID := range recipeIDs {
+		if err := s.impressions.Add(ctx, recipeID, now, 1); err != nil {
+			return errors.NewExternalServiceError("impression buffer", err)
+		}
+	}
+	return nil
+}
+
+// Flush writes the buffered views and impressions. Counts the daily totals
+// cannot take are put back for the next flush.
+func (s *Service) Flush(ctx context.Context) (int, error) {
+	written, err := s.flushViews(ctx)
+	if impressionsErr := s.flushImpressions(ctx); err == nil {
+		err = impressionsErr
+	}
+	return written, err
+}
+
+// flushViews writes the buffered views to the daily totals, then to the
+// recipe counters. A counter that fails only understates that recipe's
+// total.
+func (s *Service) flushViews(ctx context.Context) (int, error) {
+	counts, err := s.buffer.Drain(ctx)
+	if err != nil {
+		return 0, errors.NewExternalServiceError("view buffer", err)
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	if err := s.analytics.AddDailyViews(ctx, counts); err != nil {
+		s.restore(ctx, s.buffer, counts, "views")
+		return 0, errors.NewDatabaseError("add daily recipe views", err)
+	}
+
+	perRecipe := make(map[uuid.UUID]int)
+	for _, count := range counts {
+		perRecipe[count.RecipeID] += count.Views
+	}
+	written := 0
+	for recipeID, views := range perRecipe {
+		if err := s.counters.Increment(ctx, recipeID, outbound.RecipeCounterViews, views); err != nil {
+			s.logger.Warn("Failed to count recipe views",
+				zap.String("recipe_id", recipeID.String()),
+				zap.Int("views", views),
+				zap.Error(err),
+			)
+			continue
+		}
+		written += views
+	}
+	return written, nil
+}
+
+// flushImpressions writes the buffered search listings to their daily
+// totals
+func (s *Service) flushImpressions(ctx context.Context) error {
+	counts, err := s.impressions.Drain(ctx)
+	if err != nil {
+		return errors.NewExternalServiceError("impression buffer", err)
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+	if err := s.analytics.AddDailyImpressions(ctx, counts); err != nil {
+		s.restore(ctx, s.impressions, counts, "impressions")
+		return errors.NewDatabaseError("add daily recipe impressions", err)
+	}
+	return nil
+}
+
+// restore puts drained counts back for the next flush
+func (s *Service) restore(ctx context.Context, buffer outbound.ViewBuffer, counts []outbound.ViewCount, kind string) {
+	for _, count := range counts {
+		if err := buffer.Add(ctx, count.RecipeID, count.Day, count.Views); err != nil {
+			s.logger.Error("Dropped buffered recipe "+kind,
+				zap.String("recipe_id", count.RecipeID.String()),
+				zap.Int(kind, count.Views),
+				zap.Error(err),
+			)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package views
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/viewbuffer"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubCounters struct {
+	outbound.RecipeCounterRepository
+	views map[uuid.UUID]int
+}
+
+func (c *stubCounters) Increment(ctx context.Context, recipeID uuid.UUID, counter string, delta int) error {
+	if counter == outbound.RecipeCounterViews {
+		c.views[recipeID] += delta
+	}
+	return nil
+}
+
+type stubAnalytics struct {
+	outbound.RecipeAnalyticsRepository
+	daily       []outbound.ViewCount
+	impressions []outbound.ViewCount
+	err         error
+}
+
+func (a *stubAnalytics) AddDailyViews(ctx context.Context, counts []outbound.ViewCount) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.daily = append(a.daily, counts...)
+	return nil
+}
+
+func (a *stubAnalytics) AddDailyImpressions(ctx context.Context, counts []outbound.ViewCount) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.impressions = append(a.impressions, counts...)
+	return nil
+}
+
+func TestCountDedupesEachViewerWithinTheWindow(t *testing.T) {
+	counters := &stubCounters{views: make(map[uuid.UUID]int)}
+	analytics := &stubAnalytics{}
+	svc := NewService(viewbuffer.NewMemory(), viewbuffer.NewMemory(), counters, analytics, Config{Window: time.Minute}, zap.NewNop())
+	today := time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+	svc.now = func() time.Time { return today }
+	ctx := context.Background()
+	recipeID, userID := uuid.New(), uuid.New()
+
+	for _, view := range []struct {
+		userID  *uuid.UUID
+		session string
+		counted bool
+	}{
+		{userID: &userID, session: "abc", counted: true},
+		{userID: &userID, session: "def", counted: false}, // same user, new tab
+		{session: "abc", counted: true},
+		{session: "abc", counted: false},
+		{counted: true}, // anonymous views cannot be told apart
+		{counted: true},
+	} {
+		counted, err := svc.Count(ctx, recipeID, view.userID, view.session)
+		require.NoError(t, err)
+		assert.Equal(t, view.counted, counted)
+	}
+	require.NoError(t, svc.CountImpressions(ctx, []uuid.UUID{recipeID, recipeID}))
+
+	written, err := svc.Flush(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, written)
+	assert.Equal(t, 4, counters.views[recipeID])
+	require.Len(t, analytics.daily, 1)
+	assert.Equal(t, 4, analytics.daily[0].Views)
+	assert.True(t, analytics.daily[0].Day.Equal(time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)))
+	require.Len(t, analytics.impressions, 1)
+	assert.Equal(t, 2, analytics.impressions[0].Views, "impressions are not deduped")
+
+	written, err = svc.Flush(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, written, "the buffer is drained")
+}
+
+func TestFlushKeepsTheViewsWhenTheDailyTotalsFail(t *testing.T) {
+	counters := &stubCounters{views: make(map[uuid.UUID]int)}
+	analytics := &stubAnalytics{err: assert.AnError}
+	svc := NewService(viewbuffer.NewMemory(), viewbuffer.NewMemory(), counters, analytics, Config{}, zap.NewNop())
+	ctx := context.Background()
+	recipeID := uuid.New()
+
+	_, err := svc.Count(ctx, recipeID, nil, "abc")
+	require.NoError(t, err)
+	require.NoError(t, svc.CountImpressions(ctx, []uuid.UUID{recipeID}))
+	_, err = svc.Flush(ctx)
+	require.Error(t, err)
+	assert.Empty(t, counters.views)
+
+	analytics.err = nil
+	written, err := svc.Flush(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, written)
+	assert.Equal(t, 1, counters.views[recipeID])
+	assert.Len(t, analytics.impressions, 1)
+}
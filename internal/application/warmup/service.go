@@ -0,0 +1,210 @@
+// Package warmup fills the recipe query caches after a deploy so the first
+// requests are not slow. Tasks run one at a time in the background and their
+// progress is reported to readiness checks and admins.
+package warmup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/access"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultTimeout bounds a whole warmup run
+const DefaultTimeout = 2 * time.Minute
+
+// Task warms one cache and returns how many entries it loaded
+type Task struct {
+	Name string
+	Warm func(ctx context.Context) (int, error)
+}
+
+// Service runs warmups and keeps the progress of the latest one
+type Service struct {
+	mu       sync.Mutex
+	tasks    []Task
+	userRepo outbound.UserRepository
+	timeout  time.Duration
+	report   inbound.CacheWarmupReport
+	now      func() time.Time
+	logger   *zap.Logger
+}
+
+// NewService creates a warmup service for the given tasks
+func NewService(tasks []Task, userRepo outbound.UserRepository, logger *zap.Logger) *Service {
+	return &Service{
+		tasks:    tasks,
+		userRepo: userRepo,
+		timeout:  DefaultTimeout,
+		report:   inbound.CacheWarmupReport{Status: inbound.WarmupIdle, Total: len(tasks), Tasks: pendingTasks(tasks)},
+		now:      time.Now,
+		logger:   logger.Named("cache-warmup"),
+	}
+}
+
+// RecipeTasks warms the queries behind the home page, the trending list and
+// the search filters. The home page query must match the one ListRecipes
+// runs, or the warmed entry is never read.
+func RecipeTasks(recipes inbound.RecipeService) []Task {
+	return []Task{
+		{
+			Name: "home_page",
+			Warm: func(ctx context.Context) (int, error) {
+				list, err := recipes.SearchRecipes(ctx, inbound.SearchQuery{
+					Pagination: inbound.PaginationParams{Page: 0, PageSize: 20},
+				})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Recipes), nil
+			},
+		},
+		{
+			Name: "trending",
+			Warm: func(ctx context.Context) (int, error) {
+				list, err := recipes.GetTrendingRecipes(ctx, inbound.PaginationParams{Page: 0, PageSize: 20})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Recipes), nil
+			},
+		},
+		{
+			Name: "search_facets",
+			Warm: func(ctx context.Context) (int, error) {
+				facets, err := recipes.GetSearchFacets(ctx)
+				if err != nil {
+					return 0, err
+				}
+				return len(facets.Cuisines) + len(facets.Categories) + len(facets.Difficulties) + len(facets.Tags), nil
+			},
+		},
+	}
+}
+
+// Start warms the caches in the background. It returns false without
+// starting anything when a warmup is already running.
+func (s *Service) Start(trigger string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.report.Status == inbound.WarmupRunning {
+		return false
+	}
+	s.report = inbound.CacheWarmupReport{
+		Status:    inbound.WarmupRunning,
+		Trigger:   trigger,
+		StartedAt: s.now().UTC().Format(time.RFC3339),
+		Total:     len(s.tasks),
+		Tasks:     pendingTasks(s.tasks),
+	}
+
+	go s.run(trigger)
+	return true
+}
+
+// WarmCaches starts a warmup on an admin's request. A warmup that is
+// already running is reported rather than started again.
+func (s *Service) WarmCaches(ctx context.Context, requesterID uuid.UUID) (*inbound.CacheWarmupReport, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "warm caches"); err != nil {
+		return nil, err
+	}
+
+	if s.Start(inbound.WarmupTriggerManual) {
+		s.logger.Info("Cache warmup requested", zap.String("user_id", requesterID.String()))
+	}
+	report := s.Report()
+	return &report, nil
+}
+
+// WarmupReport describes the latest warmup to an admin. Readiness checks
+// read Report directly.
+func (s *Service) WarmupReport(ctx context.Context, requesterID uuid.UUID) (*inbound.CacheWarmupReport, error) {
+	if err := access.RequireAdmin(ctx, s.userRepo, requesterID, "view cache warmups"); err != nil {
+		return nil, err
+	}
+	report := s.Report()
+	return &report, nil
+}
+
+// Report describes the latest warmup
+func (s *Service) Report() inbound.CacheWarmupReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := s.report
+	report.Tasks = append([]inbound.CacheWarmupTask(nil), s.report.Tasks...)
+	return report
+}
+
+// run warms each cache in turn. A failed task is recorded and the rest
+// still run, so one slow query does not leave every cache cold.
+func (s *Service) run(trigger string) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	s.logger.Info("Cache warmup started", zap.String("trigger", trigger), zap.Int("tasks", len(s.tasks)))
+	started := s.now()
+
+	failed := 0
+	for i, task := range s.tasks {
+		s.updateTask(i, func(t *inbound.CacheWarmupTask) { t.Status = inbound.WarmupRunning })
+
+		taskStarted := s.now()
+		entries, err := task.Warm(ctx)
+		if err == nil {
+			err = ctx.Err()
+		}
+		s.updateTask(i, func(t *inbound.CacheWarmupTask) {
+			t.Entries = entries
+			t.DurationMS = s.now().Sub(taskStarted).Milliseconds()
+			t.Status = inbound.WarmupDone
+			if err != nil {
+				t.Status = inbound.WarmupFailed
+				t.Error = err.Error()
+			}
+		})
+		if err != nil {
+			failed++
+			s.logger.Warn("Cache warmup task failed", zap.String("task", task.Name), zap.Error(err))
+		}
+	}
+
+	s.mu.Lock()
+	s.report.Status = inbound.WarmupDone
+	if failed > 0 {
+		s.report.Status = inbound.WarmupFailed
+	}
+	s.report.FinishedAt = s.now().UTC().Format(time.RFC3339)
+	s.mu.Unlock()
+
+	s.logger.Info("Cache warmup finished",
+		zap.String("trigger", trigger),
+		zap.Int("failed", failed),
+		zap.Duration("duration", s.now().Sub(started)),
+	)
+}
+
+// updateTask changes one task's progress and counts it when it finishes
+func (s *Service) updateTask(i int, update func(t *inbound.CacheWarmupTask)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update(&s.report.Tasks[i])
+	if status := s.report.Tasks[i].Status; status == inbound.WarmupDone || status == inbound.WarmupFailed {
+		s.report.Completed++
+	}
+}
+
+func pendingTasks(tasks []Task) []inbound.CacheWarmupTask {
+	pending := make([]inbound.CacheWarmupTask, len(tasks))
+	for i, task := range tasks {
+		pending[i] = inbound.CacheWarmupTask{Name: task.Name, Status: inbound.WarmupPending}
+	}
+	return pending
+}
@@ -0,0 +1,87 @@
+package warmup
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stubUsers struct {
+	outbound.UserRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (s *stubUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return s.users[id], nil
+}
+
+func waitFor(t *testing.T, svc *Service, status string) inbound.CacheWarmupReport {
+	t.Helper()
+	require.Eventually(t, func() bool { return svc.Report().Status == status }, time.Second, time.Millisecond)
+	return svc.Report()
+}
+
+func TestWarmupReportsProgress(t *testing.T) {
+	release := make(chan struct{})
+	svc := NewService([]Task{
+		{Name: "home_page", Warm: func(ctx context.Context) (int, error) { return 20, nil }},
+		{Name: "trending", Warm: func(ctx context.Context) (int, error) {
+			<-release
+			return 0, stderrors.New("database is away")
+		}},
+		{Name: "search_facets", Warm: func(ctx context.Context) (int, error) { return 7, nil }},
+	}, &stubUsers{}, zap.NewNop())
+	assert.Equal(t, inbound.WarmupIdle, svc.Report().Status)
+
+	require.True(t, svc.Start(inbound.WarmupTriggerBoot))
+	assert.False(t, svc.Start(inbound.WarmupTriggerManual), "a running warmup is not started twice")
+
+	require.Eventually(t, func() bool { return svc.Report().Completed == 1 }, time.Second, time.Millisecond)
+	report := svc.Report()
+	assert.Equal(t, inbound.WarmupRunning, report.Status)
+	assert.Equal(t, inbound.WarmupTriggerBoot, report.Trigger)
+	assert.Equal(t, inbound.WarmupDone, report.Tasks[0].Status)
+	assert.Equal(t, 20, report.Tasks[0].Entries)
+	assert.Equal(t, inbound.WarmupPending, report.Tasks[2].Status)
+
+	close(release)
+	report = waitFor(t, svc, inbound.WarmupFailed)
+	assert.Equal(t, 3, report.Completed)
+	assert.Equal(t, "database is away", report.Tasks[1].Error)
+	assert.Equal(t, inbound.WarmupDone, report.Tasks[2].Status, "later tasks still run after a failure")
+	assert.NotEmpty(t, report.FinishedAt)
+}
+
+func TestWarmCachesRequiresAdmin(t *testing.T) {
+	now := time.Now()
+	admin := user.ReconstructUser(uuid.New(), "root@example.com", "Root", "", true, true, user.UserRoleAdmin, now, now, nil)
+	cook := user.ReconstructUser(uuid.New(), "ada@example.com", "Ada", "", true, true, user.UserRoleUser, now, now, nil)
+	svc := NewService([]Task{
+		{Name: "home_page", Warm: func(ctx context.Context) (int, error) { return 1, nil }},
+	}, &stubUsers{users: map[uuid.UUID]*user.User{admin.ID(): admin, cook.ID(): cook}}, zap.NewNop())
+
+	_, err := svc.WarmCaches(context.Background(), cook.ID())
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+	assert.Equal(t, inbound.WarmupIdle, svc.Report().Status)
+	_, err = svc.WarmupReport(context.Background(), cook.ID())
+	assert.True(t, errors.Is(err, errors.CodeInsufficientPermissions))
+
+	report, err := svc.WarmCaches(context.Background(), admin.ID())
+	require.NoError(t, err)
+	assert.Equal(t, inbound.WarmupTriggerManual, report.Trigger)
+	waitFor(t, svc, inbound.WarmupDone)
+
+	report, err = svc.WarmupReport(context.Background(), admin.ID())
+	require.NoError(t, err)
+	assert.Equal(t, inbound.WarmupDone, report.Status)
+}
@@ -0,0 +1,257 @@
+// Package battle contains remix battles: the AI and a community chef each
+// write a recipe for the same prompt, and readers vote blind for the one
+// they would rather cook. Finished battles tune the prompt template the AI
+// writes its next entries with.
+package battle
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxPromptLength bounds the prompt both sides cook to, in characters
+	MaxPromptLength = 500
+	// MaxTitleLength matches the recipes.title column
+	MaxTitleLength = 255
+	// MaxDescriptionLength bounds an entry's description
+	MaxDescriptionLength = 2000
+	// MaxItems bounds the ingredients and the steps of an entry
+	MaxItems = 50
+	// MaxItemLength bounds one ingredient or step
+	MaxItemLength = 1000
+	// VotingPeriod is how long a battle takes votes once the chef's entry
+	// is in
+	VotingPeriod = 7 * 24 * time.Hour
+)
+
+// Domain errors for battle operations
+var (
+	ErrEmptyPrompt       = errors.New("battle prompt must not be empty")
+	ErrPromptTooLong     = errors.New("battle prompt must not exceed 500 characters")
+	ErrEmptyTitle        = errors.New("entry title must not be empty")
+	ErrTitleTooLong      = errors.New("entry title must not exceed 255 characters")
+	ErrDescriptionLength = errors.New("entry description must not exceed 2000 characters")
+	ErrNoIngredients     = errors.New("entry must have at least one ingredient")
+	ErrNoSteps           = errors.New("entry must have at least one step")
+	ErrTooManyItems      = errors.New("entry must not have more than 50 ingredients or steps")
+	ErrItemTooLong       = errors.New("entry ingredients and steps must not exceed 1000 characters")
+	ErrNotChef           = errors.New("only the battle's chef may submit the community entry")
+	ErrEntrySubmitted    = errors.New("the chef's entry is already in")
+	ErrNotVoting         = errors.New("the battle is not taking votes")
+	ErrChefVote          = errors.New("the chef may not vote in their own battle")
+	ErrInvalidLabel      = errors.New("vote for entry a or b")
+)
+
+// Side is who wrote an entry
+type Side string
+
+const (
+	SideAI   Side = "ai"
+	SideChef Side = "chef"
+)
+
+// Status is where a battle is in its life
+type Status string
+
+const (
+	// StatusOpen battles wait for the chef's entry
+	StatusOpen Status = "open"
+	// StatusVoting battles show both entries, unlabelled, for votes
+	StatusVoting Status = "voting"
+	// StatusClosed battles reveal who wrote which entry and who won
+	StatusClosed Status = "closed"
+)
+
+// Entry is one side's recipe
+type Entry struct {
+	Title       string
+	Description string
+	Ingredients []string
+	Steps       []string
+}
+
+// Normalize trims an entry, drops blank ingredients and steps, and
+// validates what is left
+func (e Entry) Normalize() (Entry, error) {
+	entry := Entry{
+		Title:       strings.TrimSpace(e.Title),
+		Description: strings.TrimSpace(e.Description),
+	}
+	switch {
+	case entry.Title == "":
+		return Entry{}, ErrEmptyTitle
+	case utf8.RuneCountInString(entry.Title) > MaxTitleLength:
+		return Entry{}, ErrTitleTooLong
+	case utf8.RuneCountInString(entry.Description) > MaxDescriptionLength:
+		return Entry{}, ErrDescriptionLength
+	}
+
+	var err error
+	if entry.Ingredients, err = items(e.Ingredients); err != nil {
+		return Entry{}, err
+	}
+	if entry.Steps, err = items(e.Steps); err != nil {
+		return Entry{}, err
+	}
+	switch {
+	case len(entry.Ingredients) == 0:
+		return Entry{}, ErrNoIngredients
+	case len(entry.Steps) == 0:
+		return Entry{}, ErrNoSteps
+	}
+	return entry, nil
+}
+
+func items(raw []string) ([]string, error) {
+	var kept []string
+	for _, item := range raw {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		if utf8.RuneCountInString(item) > MaxItemLength {
+			return nil, ErrItemTooLong
+		}
+		kept = append(kept, item)
+	}
+	if len(kept) > MaxItems {
+		return nil, ErrTooManyItems
+	}
+	return kept, nil
+}
+
+// Battle pits the AI's recipe for a prompt against a community chef's.
+// Voters see the entries as "a" and "b"; AIFirst, drawn when the battle
+// starts, says which is the AI's.
+type Battle struct {
+	ID     uuid.UUID
+	Prompt string
+	ChefID uuid.UUID
+	// Template is the prompt template the AI entry was written with
+	Template string
+	AI       Entry
+	// Chef is nil until the chef submits
+	Chef    *Entry
+	AIFirst bool
+	// VotingEndsAt is nil until the chef submits
+	VotingEndsAt *time.Time
+	AIVotes      int
+	ChefVotes    int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// New starts a battle for a chef with the AI's entry already written
+func New(chefID uuid.UUID, prompt, template string, ai Entry, aiFirst bool, now time.Time) (*Battle, error) {
+	prompt, err := NormalizePrompt(prompt)
+	if err != nil {
+		return nil, err
+	}
+	if ai, err = ai.Normalize(); err != nil {
+		return nil, err
+	}
+	return &Battle{
+		ID:        uuid.New(),
+		Prompt:    prompt,
+		ChefID:    chefID,
+		Template:  template,
+		AI:        ai,
+		AIFirst:   aiFirst,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// NormalizePrompt trims and validates a battle prompt
+func NormalizePrompt(prompt string) (string, error) {
+	prompt = strings.TrimSpace(prompt)
+	switch {
+	case prompt == "":
+		return "", ErrEmptyPrompt
+	case utf8.RuneCountInString(prompt) > MaxPromptLength:
+		return "", ErrPromptTooLong
+	}
+	return prompt, nil
+}
+
+// Status reports where the battle is at now
+func (b *Battle) Status(now time.Time) Status {
+	switch {
+	case b.VotingEndsAt == nil:
+		return StatusOpen
+	case now.Before(*b.VotingEndsAt):
+		return StatusVoting
+	default:
+		return StatusClosed
+	}
+}
+
+// SubmitChef records the chef's entry and opens voting
+func (b *Battle) SubmitChef(userID uuid.UUID, entry Entry, now time.Time) error {
+	if userID != b.ChefID {
+		return ErrNotChef
+	}
+	if b.Chef != nil {
+		return ErrEntrySubmitted
+	}
+	entry, err := entry.Normalize()
+	if err != nil {
+		return err
+	}
+	ends := now.Add(VotingPeriod)
+	b.Chef = &entry
+	b.VotingEndsAt = &ends
+	b.UpdatedAt = now
+	return nil
+}
+
+// CanVote checks a user may vote in the battle at now
+func (b *Battle) CanVote(userID uuid.UUID, now time.Time) error {
+	if b.Status(now) != StatusVoting {
+		return ErrNotVoting
+	}
+	if userID == b.ChefID {
+		return ErrChefVote
+	}
+	return nil
+}
+
+// Side says whose entry a voter's label is
+func (b *Battle) Side(label string) (Side, error) {
+	switch strings.ToLower(strings.TrimSpace(label)) {
+	case "a":
+		if b.AIFirst {
+			return SideAI, nil
+		}
+		return SideChef, nil
+	case "b":
+		if b.AIFirst {
+			return SideChef, nil
+		}
+		return SideAI, nil
+	}
+	return "", ErrInvalidLabel
+}
+
+// Label is the label voters see on a side's entry
+func (b *Battle) Label(side Side) string {
+	if (side == SideAI) == b.AIFirst {
+		return "a"
+	}
+	return "b"
+}
+
+// Winner is the side with more votes, empty on a tie
+func (b *Battle) Winner() Side {
+	switch {
+	case b.AIVotes > b.ChefVotes:
+		return SideAI
+	case b.ChefVotes > b.AIVotes:
+		return SideChef
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package battle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBattleVotingIsBlind(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	chefID := uuid.New()
+	ai := Entry{Title: "Miso Salmon", Ingredients: []string{"salmon", " "}, Steps: []string{"Roast at 220C for 12 minutes."}}
+	b, err := New(chefID, "  a weeknight salmon dinner ", "classic", ai, false, now)
+	require.NoError(t, err)
+	assert.Equal(t, "a weeknight salmon dinner", b.Prompt)
+	assert.Equal(t, []string{"salmon"}, b.AI.Ingredients)
+	assert.Equal(t, StatusOpen, b.Status(now))
+
+	chef := Entry{Title: "Salmon en Papillote", Ingredients: []string{"salmon", "lemon"}, Steps: []string{"Fold into parchment and bake."}}
+	assert.Equal(t, ErrNotChef, b.SubmitChef(uuid.New(), chef, now))
+	require.NoError(t, b.SubmitChef(chefID, chef, now))
+	assert.Equal(t, ErrEntrySubmitted, b.SubmitChef(chefID, chef, now))
+	assert.Equal(t, StatusVoting, b.Status(now.Add(time.Hour)))
+
+	side, err := b.Side("A")
+	require.NoError(t, err)
+	assert.Equal(t, SideChef, side, "the AI entry is second when AIFirst is false")
+	assert.Equal(t, "b", b.Label(SideAI))
+	_, err = b.Side("c")
+	assert.Equal(t, ErrInvalidLabel, err)
+
+	assert.Equal(t, ErrChefVote, b.CanVote(chefID, now))
+	assert.NoError(t, b.CanVote(uuid.New(), now))
+	assert.Equal(t, ErrNotVoting, b.CanVote(uuid.New(), now.Add(VotingPeriod)))
+	assert.Equal(t, StatusClosed, b.Status(now.Add(VotingPeriod)))
+}
+
+func TestChooseTemplate(t *testing.T) {
+	never := func() float64 { return 0.99 }
+	results := []TemplateResult{
+		{Template: "classic", Wins: 3, Losses: 3},
+		{Template: "chef-notes", Wins: 5, Losses: 1},
+		{Template: "home-cook", Wins: 1, Losses: 4, Ties: 1},
+	}
+	assert.Equal(t, "seasonal", ChooseTemplate(results, never).Name, "untried templates go first")
+
+	results = append(results, TemplateResult{Template: "seasonal", Wins: 4, Losses: 4})
+	assert.Equal(t, "chef-notes", ChooseTemplate(results, never).Name)
+
+	draws := []float64{0.1, 0.6}
+	explore := func() float64 {
+		r := draws[0]
+		draws = draws[1:]
+		return r
+	}
+	assert.Equal(t, "home-cook", ChooseTemplate(results, explore).Name)
+}
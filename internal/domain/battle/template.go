@@ -0,0 +1,105 @@
+package battle
+
+import "strings"
+
+const (
+	// MinTrials is how many decided battles a template gets before its
+	// results count against it
+	MinTrials = 5
+	// Exploration is the share of battles written with a random template
+	// rather than the best one so far
+	Exploration = 0.2
+)
+
+// Template is a way of asking the AI for a battle entry. Its instruction
+// is added to the battle prompt.
+type Template struct {
+	Name        string
+	Instruction string
+}
+
+// Templates are the prompt templates the AI writes battle entries with
+var Templates = []Template{
+	{Name: "classic"},
+	{Name: "chef-notes", Instruction: "Write it the way a restaurant chef would for their line cooks: precise quantities, exact temperatures and cues for doneness."},
+	{Name: "home-cook", Instruction: "Write it for a busy home cook: supermarket ingredients, as few pans as possible and plain language."},
+	{Name: "seasonal", Instruction: "Build it around what is in season and let one ingredient be the star."},
+}
+
+// LookupTemplate finds a template by name
+func LookupTemplate(name string) (Template, bool) {
+	for _, t := range Templates {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Template{}, false
+}
+
+// Prompt is the battle prompt as the AI is given it
+func (t Template) Prompt(prompt string) string {
+	if t.Instruction == "" {
+		return prompt
+	}
+	return strings.TrimSpace(prompt) + "\n\n" + t.Instruction
+}
+
+// TemplateResult is how the AI fared in closed battles written with a
+// template
+type TemplateResult struct {
+	Template string
+	Wins     int
+	Losses   int
+	Ties     int
+}
+
+// Trials is the number of closed battles the template was used in
+func (r TemplateResult) Trials() int {
+	return r.Wins + r.Losses + r.Ties
+}
+
+// Score is the template's win rate with ties as half a win, smoothed
+// towards one half so a single early win does not crown a template
+func (r TemplateResult) Score() float64 {
+	return (float64(r.Wins) + float64(r.Ties)/2 + 1) / float64(r.Trials()+2)
+}
+
+// ChooseTemplate picks the template for the next AI entry. Templates with
+// fewer than MinTrials results are tried first; after that the best
+// scoring template is used, except for an Exploration share of battles
+// that get a random one. random returns a number in [0, 1).
+func ChooseTemplate(results []TemplateResult, random func() float64) Template {
+	byName := make(map[string]TemplateResult, len(results))
+	for _, r := range results {
+		byName[r.Template] = r
+	}
+
+	var untried []Template
+	for _, t := range Templates {
+		if byName[t.Name].Trials() < MinTrials {
+			untried = append(untried, t)
+		}
+	}
+	if len(untried) > 0 {
+		return untried[pick(len(untried), random())]
+	}
+	if random() < Exploration {
+		return Templates[pick(len(Templates), random())]
+	}
+
+	best := Templates[0]
+	for _, t := range Templates[1:] {
+		if byName[t.Name].Score() > byName[best.Name].Score() {
+			best = t
+		}
+	}
+	return best
+}
+
+func pick(n int, r float64) int {
+	i := int(r * float64(n))
+	if i >= n {
+		i = n - 1
+	}
+	return i
+}
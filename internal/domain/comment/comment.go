@@ -0,0 +1,206 @@
+// Package comment contains the domain model for recipe comments and the
+// threaded responses to comments and reviews
+package comment
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxContentLength bounds a comment, in characters
+const MaxContentLength = 2000
+
+// Domain errors for comment operations
+var (
+	ErrEmptyContent      = errors.New("comment must not be empty")
+	ErrContentTooLong    = errors.New("comment must not exceed 2000 characters")
+	ErrParentOtherRecipe = errors.New("reply must be on the same recipe as the comment it answers")
+	ErrAlreadyThreaded   = errors.New("comment already answers another comment or review")
+	ErrNotEditable       = errors.New("only visible comments can be edited")
+	ErrAlreadyDeleted    = errors.New("the comment was deleted")
+)
+
+// Source records how a comment was written
+type Source string
+
+const (
+	SourceWeb   Source = "web"
+	SourceEmail Source = "email"
+)
+
+// Comment is a remark on a recipe. It may answer another comment or the
+// review a user left on the recipe; reviews are keyed by their author since
+// each user reviews a recipe once. Threads are one level deep: a reply to
+// a reply answers the top-level comment.
+type Comment struct {
+	id             uuid.UUID
+	recipeID       uuid.UUID
+	authorID       uuid.UUID
+	parentID       *uuid.UUID
+	reviewUserID   *uuid.UUID
+	content        string
+	source         Source
+	emailMessageID string
+	createdAt      time.Time
+	editedAt       *time.Time
+
+	// Moderation state, see moderation.go
+	status     ModerationStatus
+	hiddenBy   *uuid.UUID
+	hideReason string
+	hiddenAt   *time.Time
+}
+
+// NewComment creates a top-level comment with validated content
+func NewComment(recipeID, authorID uuid.UUID, content string, source Source) (*Comment, error) {
+	content, err := normalizeContent(content)
+	if err != nil {
+		return nil, err
+	}
+	if source == "" {
+		source = SourceWeb
+	}
+
+	return &Comment{
+		id:        uuid.New(),
+		recipeID:  recipeID,
+		authorID:  authorID,
+		content:   content,
+		source:    source,
+		createdAt: time.Now(),
+	}, nil
+}
+
+// Reconstruct rebuilds a stored comment without validation
+func Reconstruct(id, recipeID, authorID uuid.UUID, parentID, reviewUserID *uuid.UUID, content string, source Source, emailMessageID string, createdAt time.Time) *Comment {
+	return &Comment{
+		id:             id,
+		recipeID:       recipeID,
+		authorID:       authorID,
+		parentID:       parentID,
+		reviewUserID:   reviewUserID,
+		content:        content,
+		source:         source,
+		emailMessageID: emailMessageID,
+		createdAt:      createdAt,
+	}
+}
+
+// ReplyTo threads the comment under another comment on the same recipe.
+// Answering a reply threads it under that reply's top-level comment.
+func (c *Comment) ReplyTo(parent *Comment) error {
+	if c.parentID != nil || c.reviewUserID != nil {
+		return ErrAlreadyThreaded
+	}
+	if parent.recipeID != c.recipeID {
+		return ErrParentOtherRecipe
+	}
+	if !parent.Visible() {
+		return ErrParentHidden
+	}
+	id := parent.id
+	if parent.parentID != nil {
+		id = *parent.parentID
+	}
+	c.parentID = &id
+	return nil
+}
+
+// Edit replaces the text of a visible comment
+func (c *Comment) Edit(content string, now time.Time) error {
+	if !c.Visible() {
+		return ErrNotEditable
+	}
+	content, err := normalizeContent(content)
+	if err != nil {
+		return err
+	}
+	c.content = content
+	c.editedAt = &now
+	return nil
+}
+
+// SetEditedAt restores when a stored comment was last edited
+func (c *Comment) SetEditedAt(editedAt *time.Time) {
+	c.editedAt = editedAt
+}
+
+// ReplyToReview threads the comment under the review the given user left
+func (c *Comment) ReplyToReview(reviewerID uuid.UUID) error {
+	if c.parentID != nil || c.reviewUserID != nil {
+		return ErrAlreadyThreaded
+	}
+	c.reviewUserID = &reviewerID
+	return nil
+}
+
+// SetEmailMessageID records the Message-ID of the email the comment came
+// from, so a redelivered email is not posted twice
+func (c *Comment) SetEmailMessageID(messageID string) {
+	c.emailMessageID = messageID
+}
+
+// ID returns the comment's unique identifier
+func (c *Comment) ID() uuid.UUID {
+	return c.id
+}
+
+// RecipeID returns the recipe the comment is on
+func (c *Comment) RecipeID() uuid.UUID {
+	return c.recipeID
+}
+
+// AuthorID returns who wrote the comment
+func (c *Comment) AuthorID() uuid.UUID {
+	return c.authorID
+}
+
+// ParentID returns the comment this one answers, if any
+func (c *Comment) ParentID() *uuid.UUID {
+	return c.parentID
+}
+
+// ReviewUserID returns the author of the review this comment answers, if any
+func (c *Comment) ReviewUserID() *uuid.UUID {
+	return c.reviewUserID
+}
+
+// Content returns the comment text
+func (c *Comment) Content() string {
+	return c.content
+}
+
+// Source returns how the comment was written
+func (c *Comment) Source() Source {
+	return c.source
+}
+
+// EmailMessageID returns the Message-ID of the email reply, if any
+func (c *Comment) EmailMessageID() string {
+	return c.emailMessageID
+}
+
+// CreatedAt returns when the comment was posted
+func (c *Comment) CreatedAt() time.Time {
+	return c.createdAt
+}
+
+// EditedAt returns when the comment was last edited, if ever
+func (c *Comment) EditedAt() *time.Time {
+	return c.editedAt
+}
+
+func normalizeContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", ErrEmptyContent
+	}
+	if utf8.RuneCountInString(content) > MaxContentLength {
+		return "", ErrContentTooLong
+	}
+	return content, nil
+}
@@ -0,0 +1,68 @@
+package comment
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxFlagNoteLength bounds the note a reader adds to a flag
+const MaxFlagNoteLength = 500
+
+// Domain errors for flagging comments
+var (
+	ErrSelfFlag          = errors.New("you cannot flag your own comment")
+	ErrInvalidFlagReason = errors.New("reason must be spam, abuse, off_topic or other")
+	ErrFlagNoteLength    = errors.New("note must not exceed 500 characters")
+	ErrNotFlaggable      = errors.New("only visible comments can be flagged")
+)
+
+// FlagReason is why a reader flagged a comment
+type FlagReason string
+
+const (
+	FlagSpam     FlagReason = "spam"
+	FlagAbuse    FlagReason = "abuse"
+	FlagOffTopic FlagReason = "off_topic"
+	FlagOther    FlagReason = "other"
+)
+
+// Valid reports whether r is a known reason
+func (r FlagReason) Valid() bool {
+	switch r {
+	case FlagSpam, FlagAbuse, FlagOffTopic, FlagOther:
+		return true
+	}
+	return false
+}
+
+// Flag is a reader asking moderators to look at a comment. Each reader
+// flags a comment once.
+type Flag struct {
+	CommentID uuid.UUID
+	UserID    uuid.UUID
+	Reason    FlagReason
+	Note      string
+	CreatedAt time.Time
+}
+
+// NewFlag validates a flag on c
+func NewFlag(c *Comment, userID uuid.UUID, reason FlagReason, note string, now time.Time) (*Flag, error) {
+	if c.AuthorID() == userID {
+		return nil, ErrSelfFlag
+	}
+	if !c.Visible() {
+		return nil, ErrNotFlaggable
+	}
+	if !reason.Valid() {
+		return nil, ErrInvalidFlagReason
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > MaxFlagNoteLength {
+		return nil, ErrFlagNoteLength
+	}
+	return &Flag{CommentID: c.ID(), UserID: userID, Reason: reason, Note: note, CreatedAt: now}, nil
+}
@@ -0,0 +1,200 @@
+package comment
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxReasonLength bounds the reason given for a moderation action
+const MaxReasonLength = 500
+
+// Domain errors for author moderation
+var (
+	ErrDiscussionLocked = errors.New("comments on this recipe are locked")
+	ErrBlocked          = errors.New("the recipe's author has blocked you from commenting on their recipes")
+	ErrSelfBlock        = errors.New("you cannot block yourself")
+	ErrAlreadyHidden    = errors.New("the comment is already hidden")
+	ErrNotHidden        = errors.New("only hidden comments can be reviewed")
+	ErrReasonLength     = errors.New("reason must not exceed 500 characters")
+	ErrParentHidden     = errors.New("hidden comments cannot be answered")
+)
+
+// ModerationStatus is whether a comment is shown
+type ModerationStatus string
+
+const (
+	StatusVisible ModerationStatus = "visible"
+	// StatusHidden comments were hidden by the recipe's author and wait
+	// for a moderator to restore or remove them
+	StatusHidden  ModerationStatus = "hidden"
+	StatusRemoved ModerationStatus = "removed"
+	// StatusDeleted comments were deleted by their author or a moderator.
+	// They keep their place so replies stay threaded.
+	StatusDeleted ModerationStatus = "deleted"
+)
+
+// Status returns whether the comment is shown
+func (c *Comment) Status() ModerationStatus {
+	if c.status == "" {
+		return StatusVisible
+	}
+	return c.status
+}
+
+// Visible reports whether readers see the comment's content
+func (c *Comment) Visible() bool {
+	return c.Status() == StatusVisible
+}
+
+// HiddenBy returns who hid the comment, if anyone
+func (c *Comment) HiddenBy() *uuid.UUID {
+	return c.hiddenBy
+}
+
+// HideReason returns why the comment was hidden
+func (c *Comment) HideReason() string {
+	return c.hideReason
+}
+
+// HiddenAt returns when the comment was hidden
+func (c *Comment) HiddenAt() *time.Time {
+	return c.hiddenAt
+}
+
+// SetModeration restores the stored moderation state of a comment
+func (c *Comment) SetModeration(status ModerationStatus, hiddenBy *uuid.UUID, reason string, hiddenAt *time.Time) {
+	c.status = status
+	c.hiddenBy = hiddenBy
+	c.hideReason = reason
+	c.hiddenAt = hiddenAt
+}
+
+// Hide takes the comment out of view until a moderator reviews it
+func (c *Comment) Hide(actorID uuid.UUID, reason string, now time.Time) error {
+	if !c.Visible() {
+		return ErrAlreadyHidden
+	}
+	reason, err := NormalizeReason(reason)
+	if err != nil {
+		return err
+	}
+	c.status = StatusHidden
+	c.hiddenBy = &actorID
+	c.hideReason = reason
+	c.hiddenAt = &now
+	return nil
+}
+
+// HideForReview hides the comment without an acting user, as when enough
+// readers reported it, until a moderator reviews it
+func (c *Comment) HideForReview(reason string, now time.Time) error {
+	if !c.Visible() {
+		return ErrAlreadyHidden
+	}
+	reason, err := NormalizeReason(reason)
+	if err != nil {
+		return err
+	}
+	c.SetModeration(StatusHidden, nil, reason, &now)
+	return nil
+}
+
+// Restore shows a hidden comment again
+func (c *Comment) Restore() error {
+	if c.Status() != StatusHidden {
+		return ErrNotHidden
+	}
+	c.SetModeration(StatusVisible, nil, "", nil)
+	return nil
+}
+
+// Remove confirms a hidden comment should stay out of view
+func (c *Comment) Remove() error {
+	if c.Status() != StatusHidden {
+		return ErrNotHidden
+	}
+	c.status = StatusRemoved
+	return nil
+}
+
+// Delete blanks the comment out for good
+func (c *Comment) Delete() error {
+	if c.Status() == StatusDeleted {
+		return ErrAlreadyDeleted
+	}
+	c.status = StatusDeleted
+	c.content = ""
+	return nil
+}
+
+// Discussion is the comment settings of one recipe
+type Discussion struct {
+	RecipeID uuid.UUID
+	Locked   bool
+	LockedBy *uuid.UUID
+	LockedAt *time.Time
+}
+
+// Block stops a user from commenting on any recipe by an author
+type Block struct {
+	AuthorID  uuid.UUID
+	UserID    uuid.UUID
+	Reason    string
+	CreatedAt time.Time
+}
+
+// NewBlock validates a block
+func NewBlock(authorID, userID uuid.UUID, reason string, now time.Time) (*Block, error) {
+	if authorID == userID {
+		return nil, ErrSelfBlock
+	}
+	reason, err := NormalizeReason(reason)
+	if err != nil {
+		return nil, err
+	}
+	return &Block{AuthorID: authorID, UserID: userID, Reason: reason, CreatedAt: now}, nil
+}
+
+// Action is a moderation step recorded in the audit log
+type Action string
+
+const (
+	ActionLock    Action = "lock"
+	ActionUnlock  Action = "unlock"
+	ActionHide    Action = "hide"
+	ActionRestore Action = "restore"
+	ActionRemove  Action = "remove"
+	ActionBlock   Action = "block"
+	ActionUnblock Action = "unblock"
+	// ActionDelete is a comment deleted by a moderator; ActionDismiss
+	// clears the flags on a comment a moderator decided to keep
+	ActionDelete  Action = "delete"
+	ActionDismiss Action = "dismiss"
+)
+
+// ModerationEvent records who took a moderation action, on what and why.
+// RecipeID and CommentID are set for actions on a discussion or comment;
+// SubjectID is the user whose comment or commenting was acted on.
+type ModerationEvent struct {
+	ID        uuid.UUID
+	ActorID   uuid.UUID
+	Action    Action
+	RecipeID  *uuid.UUID
+	CommentID *uuid.UUID
+	SubjectID *uuid.UUID
+	Reason    string
+	CreatedAt time.Time
+}
+
+// NormalizeReason trims a moderation reason and checks its length
+func NormalizeReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > MaxReasonLength {
+		return "", ErrReasonLength
+	}
+	return reason, nil
+}
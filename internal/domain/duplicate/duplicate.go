@@ -0,0 +1,184 @@
+// Package duplicate holds flags on published recipes that are near copies
+// of another author's recipe, the moderators' verdicts on them and the
+// authors' appeals
+package duplicate
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxNoteLength bounds the note a moderator or author adds to a flag
+const MaxNoteLength = 500
+
+// Domain errors for duplicate flags
+var (
+	ErrNoteLength       = errors.New("note must not exceed 500 characters")
+	ErrNotOpen          = errors.New("the flag has already been reviewed")
+	ErrNotUpheld        = errors.New("only upheld flags can be appealed")
+	ErrNotAuthor        = errors.New("only the recipe's author can appeal its flag")
+	ErrAlreadyAppealed  = errors.New("the flag has already been appealed")
+	ErrNoPendingAppeal  = errors.New("the flag has no appeal waiting for a decision")
+	ErrSameModerator    = errors.New("the appeal must be decided by another admin than the one who upheld the flag")
+	ErrAppealNoteNeeded = errors.New("an appeal needs a note")
+)
+
+// Status is where a flag stands
+type Status string
+
+const (
+	StatusOpen Status = "open"
+	// StatusUpheld flags were found to be copies: the recipe stays down
+	StatusUpheld Status = "upheld"
+	// StatusDismissed flags were rejected: the recipe stays up
+	StatusDismissed Status = "dismissed"
+)
+
+// Appeal is where an author's appeal of an upheld flag stands
+type Appeal string
+
+const (
+	AppealNone    Appeal = ""
+	AppealPending Appeal = "pending"
+	// AppealGranted appeals restored the recipe, and the flag no longer
+	// counts against the author
+	AppealGranted Appeal = "granted"
+	AppealDenied  Appeal = "denied"
+)
+
+// Flag is a published recipe found to be alike another author's recipe.
+// The similarity and the ingredients and steps the two share are kept as
+// they were when the check ran, so a later re-embedding does not change
+// what a verdict was based on. Archived is set when the check took the
+// recipe down at once because it was close enough to count as a copy.
+type Flag struct {
+	ID                uuid.UUID
+	RecipeID          uuid.UUID
+	OriginalID        uuid.UUID
+	AuthorID          uuid.UUID
+	Similarity        float64
+	SharedIngredients []string
+	SharedSteps       []string
+	Archived          bool
+	Status            Status
+	ResolvedBy        *uuid.UUID
+	Resolution        string
+	ResolvedAt        *time.Time
+	Appeal            Appeal
+	AppealNote        string
+	AppealedAt        *time.Time
+	AppealDecidedBy   *uuid.UUID
+	AppealDecidedAt   *time.Time
+	CreatedAt         time.Time
+}
+
+// NewFlag opens a flag on recipeID, by authorID, as a copy of originalID
+func NewFlag(recipeID, originalID, authorID uuid.UUID, similarity float64, sharedIngredients, sharedSteps []string, archived bool, now time.Time) *Flag {
+	return &Flag{
+		ID:                uuid.New(),
+		RecipeID:          recipeID,
+		OriginalID:        originalID,
+		AuthorID:          authorID,
+		Similarity:        similarity,
+		SharedIngredients: sharedIngredients,
+		SharedSteps:       sharedSteps,
+		Archived:          archived,
+		Status:            StatusOpen,
+		CreatedAt:         now,
+	}
+}
+
+// Close records a moderator's verdict on an open flag
+func (f *Flag) Close(status Status, moderatorID uuid.UUID, resolution string, now time.Time) error {
+	if f.Status != StatusOpen {
+		return ErrNotOpen
+	}
+	resolution, err := NormalizeNote(resolution)
+	if err != nil {
+		return err
+	}
+	f.Status = status
+	f.ResolvedBy = &moderatorID
+	f.Resolution = resolution
+	f.ResolvedAt = &now
+	return nil
+}
+
+// FileAppeal records the author's one appeal of an upheld flag
+func (f *Flag) FileAppeal(authorID uuid.UUID, note string, now time.Time) error {
+	if authorID != f.AuthorID {
+		return ErrNotAuthor
+	}
+	if f.Status != StatusUpheld {
+		return ErrNotUpheld
+	}
+	if f.Appeal != AppealNone {
+		return ErrAlreadyAppealed
+	}
+	note, err := NormalizeNote(note)
+	if err != nil {
+		return err
+	}
+	if note == "" {
+		return ErrAppealNoteNeeded
+	}
+	f.Appeal = AppealPending
+	f.AppealNote = note
+	f.AppealedAt = &now
+	return nil
+}
+
+// DecideAppeal grants or denies a pending appeal. The admin who upheld the
+// flag cannot decide its appeal.
+func (f *Flag) DecideAppeal(grant bool, moderatorID uuid.UUID, now time.Time) error {
+	if f.Appeal != AppealPending {
+		return ErrNoPendingAppeal
+	}
+	if f.ResolvedBy != nil && *f.ResolvedBy == moderatorID {
+		return ErrSameModerator
+	}
+	f.Appeal = AppealDenied
+	if grant {
+		f.Appeal = AppealGranted
+	}
+	f.AppealDecidedBy = &moderatorID
+	f.AppealDecidedAt = &now
+	return nil
+}
+
+// SharedLines returns the lines of a that b has too, ignoring case and
+// spacing, in the order of a and without repeats
+func SharedLines(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, line := range b {
+		in[normalizeLine(line)] = true
+	}
+	var shared []string
+	seen := make(map[string]bool)
+	for _, line := range a {
+		key := normalizeLine(line)
+		if key == "" || !in[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		shared = append(shared, strings.TrimSpace(line))
+	}
+	return shared
+}
+
+func normalizeLine(line string) string {
+	return strings.ToLower(strings.Join(strings.Fields(line), " "))
+}
+
+// NormalizeNote trims a note and checks its length
+func NormalizeNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > MaxNoteLength {
+		return "", ErrNoteLength
+	}
+	return note, nil
+}
@@ -0,0 +1,122 @@
+// Package notification contains in-app notifications: a short message
+// telling a user that someone followed them, liked or commented on their
+// recipe, or that an AI recipe they asked for is ready.
+package notification
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxTitleLength bounds a notification's title, in characters
+	MaxTitleLength = 200
+	// MaxMessageLength bounds a notification's body, in characters
+	MaxMessageLength = 1000
+	// MaxLinkLength bounds the in-app path a notification opens
+	MaxLinkLength = 500
+)
+
+// Domain errors for notifications
+var (
+	ErrInvalidType   = errors.New("type must be new_follower, recipe_liked, recipe_commented or ai_recipe_ready")
+	ErrEmptyTitle    = errors.New("notification title must not be empty")
+	ErrTitleLength   = errors.New("notification title must not exceed 200 characters")
+	ErrMessageLength = errors.New("notification message must not exceed 1000 characters")
+	ErrInvalidLink   = errors.New("notification link must be an in-app path of at most 500 characters")
+)
+
+// Type is what a notification is about. Users mute notifications by type.
+type Type string
+
+const (
+	TypeNewFollower     Type = "new_follower"
+	TypeRecipeLiked     Type = "recipe_liked"
+	TypeRecipeCommented Type = "recipe_commented"
+	TypeAIRecipeReady   Type = "ai_recipe_ready"
+)
+
+// Types lists every notification type in the order preferences show them
+func Types() []Type {
+	return []Type{TypeNewFollower, TypeRecipeLiked, TypeRecipeCommented, TypeAIRecipeReady}
+}
+
+// Valid reports whether t is a known type
+func (t Type) Valid() bool {
+	for _, known := range Types() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Label is how preferences describe the type
+func (t Type) Label() string {
+	switch t {
+	case TypeNewFollower:
+		return "New followers"
+	case TypeRecipeLiked:
+		return "Likes on my recipes"
+	case TypeRecipeCommented:
+		return "Comments on my recipes"
+	case TypeAIRecipeReady:
+		return "AI recipes ready"
+	}
+	return string(t)
+}
+
+// Notification is one message to a user. ActorID is who caused it and is
+// nil for system notifications such as a finished AI recipe; Link is the
+// in-app path the notification opens.
+type Notification struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	ActorID   *uuid.UUID
+	Type      Type
+	Title     string
+	Message   string
+	Link      string
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
+
+// New validates and creates an unread notification
+func New(userID uuid.UUID, actorID *uuid.UUID, typ Type, title, message, link string, now time.Time) (*Notification, error) {
+	if !typ.Valid() {
+		return nil, ErrInvalidType
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, ErrEmptyTitle
+	}
+	if utf8.RuneCountInString(title) > MaxTitleLength {
+		return nil, ErrTitleLength
+	}
+	message = strings.TrimSpace(message)
+	if utf8.RuneCountInString(message) > MaxMessageLength {
+		return nil, ErrMessageLength
+	}
+	if link != "" && (!strings.HasPrefix(link, "/") || strings.HasPrefix(link, "//") || len(link) > MaxLinkLength) {
+		return nil, ErrInvalidLink
+	}
+	return &Notification{
+		ID:        uuid.New(),
+		UserID:    userID,
+		ActorID:   actorID,
+		Type:      typ,
+		Title:     title,
+		Message:   message,
+		Link:      link,
+		CreatedAt: now,
+	}, nil
+}
+
+// IsRead reports whether the user has seen the notification
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
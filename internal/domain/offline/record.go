@@ -0,0 +1,287 @@
+// Package offline contains the domain model for keeping a user's devices in
+// sync while they work offline. A record is a small set of named fields;
+// devices push writes stamped with when the user made them, and concurrent
+// writes resolve field by field, last write wins, so edits to different
+// fields of the same note both survive. Deleting a record leaves a
+// tombstone that devices pull like any other change until it is purged.
+package offline
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxKeyLength bounds client-generated record keys such as note IDs
+	MaxKeyLength = 64
+	// MaxDeviceIDLength bounds the ID a device sends with its changes
+	MaxDeviceIDLength = 64
+	// MaxValueBytes bounds one field's JSON value
+	MaxValueBytes = 16 * 1024
+)
+
+// ServerDevice stamps the fields the server writes itself
+const ServerDevice = "server"
+
+// Domain errors for sync changes
+var (
+	ErrUnknownKind    = errors.New("unknown record kind")
+	ErrReadOnlyKind   = errors.New("records of this kind are written by the server")
+	ErrInvalidKey     = errors.New("invalid record key")
+	ErrUnknownField   = errors.New("unknown field")
+	ErrInvalidValue   = errors.New("field value must be valid JSON")
+	ErrValueTooLarge  = errors.New("field value must not exceed 16KB")
+	ErrEmptyChange    = errors.New("change must write fields or delete the record")
+	ErrInvalidDevice  = errors.New("device id is required and must not exceed 64 characters")
+	ErrDeleteOrFields = errors.New("change must not both write fields and delete the record")
+	// ErrStaleRecord is returned by repositories when the record was saved
+	// by someone else since it was loaded
+	ErrStaleRecord = errors.New("record was changed concurrently")
+)
+
+// Kind names what a record holds
+type Kind string
+
+const (
+	// KindBookmark is a saved recipe, keyed by recipe ID
+	KindBookmark Kind = "bookmark"
+	// KindNote is a private note, keyed by an ID the device generates
+	KindNote Kind = "note"
+	// KindShoppingList points at a shopping list the user can edit. The
+	// list itself syncs through its own operations.
+	KindShoppingList Kind = "shopping_list"
+	// KindDraft points at one of the user's recipe drafts, which sync
+	// through the recipe change feed
+	KindDraft Kind = "draft"
+)
+
+// clientFields are the fields devices may write, by kind. Kinds missing
+// here are only written by the server.
+var clientFields = map[Kind]map[string]bool{
+	KindBookmark: {"collection": true, "pinned": true, "tags": true},
+	KindNote:     {"recipe_id": true, "title": true, "text": true},
+}
+
+// Valid reports whether the kind is known
+func (k Kind) Valid() bool {
+	switch k {
+	case KindBookmark, KindNote, KindShoppingList, KindDraft:
+		return true
+	}
+	return false
+}
+
+// Writable reports whether devices may change records of the kind
+func (k Kind) Writable() bool {
+	return clientFields[k] != nil
+}
+
+// Outcome says what became of a change
+type Outcome string
+
+const (
+	// OutcomeApplied changed the record
+	OutcomeApplied Outcome = "applied"
+	// OutcomeUnchanged found the record already as the change left it,
+	// typically a change resent after a lost response
+	OutcomeUnchanged Outcome = "unchanged"
+	// OutcomeConflict lost, at least in part, to later writes; the device
+	// should adopt the record as it stands
+	OutcomeConflict Outcome = "conflict"
+)
+
+// Change is one edit a device made to a record
+type Change struct {
+	Kind Kind
+	Key  string
+	// Fields are the values written. Fields left out keep their values.
+	Fields map[string]json.RawMessage
+	Delete bool
+	// At is when the user made the change, which for offline devices can
+	// be well before it reaches the server. Zero or future times mean now.
+	At time.Time
+}
+
+// Validate checks a change a device pushed
+func (c Change) Validate() error {
+	if !c.Kind.Valid() {
+		return ErrUnknownKind
+	}
+	if !c.Kind.Writable() {
+		return ErrReadOnlyKind
+	}
+	if c.Key == "" || len(c.Key) > MaxKeyLength {
+		return ErrInvalidKey
+	}
+	if c.Kind == KindBookmark {
+		if _, err := uuid.Parse(c.Key); err != nil {
+			return ErrInvalidKey
+		}
+	}
+	if c.Delete && len(c.Fields) > 0 {
+		return ErrDeleteOrFields
+	}
+	if !c.Delete && len(c.Fields) == 0 {
+		return ErrEmptyChange
+	}
+	for name, value := range c.Fields {
+		if !clientFields[c.Kind][name] {
+			return ErrUnknownField
+		}
+		if len(value) > MaxValueBytes {
+			return ErrValueTooLarge
+		}
+		if !json.Valid(value) {
+			return ErrInvalidValue
+		}
+	}
+	return nil
+}
+
+// ValidDevice reports whether a device ID can stamp writes
+func ValidDevice(deviceID string) bool {
+	return deviceID != "" && len(deviceID) <= MaxDeviceIDLength && deviceID != ServerDevice
+}
+
+// Stamp orders writes: the later time wins, and the device ID breaks ties
+// so every replica picks the same winner
+type Stamp struct {
+	At     time.Time
+	Device string
+}
+
+// After reports whether s wins over other
+func (s Stamp) After(other Stamp) bool {
+	if !s.At.Equal(other.At) {
+		return s.At.After(other.At)
+	}
+	return s.Device > other.Device
+}
+
+// Equal reports whether both stamp the same write
+func (s Stamp) Equal(other Stamp) bool {
+	return s.At.Equal(other.At) && s.Device == other.Device
+}
+
+// Field is a field's value and the write that set it
+type Field struct {
+	Value json.RawMessage
+	Stamp
+}
+
+// Record is one thing a user syncs across devices
+type Record struct {
+	UserID uuid.UUID
+	Kind   Kind
+	Key    string
+	Fields map[string]Field
+	// Cleared is the stamp of the latest delete. Fields written before it
+	// are gone, and late writes from before it are rejected.
+	Cleared *Stamp
+	// Seq is the number in the user's change sequence of the record's
+	// last save, zero until it is first saved. Devices pull by it.
+	Seq       int64
+	UpdatedAt time.Time
+}
+
+// NewRecord creates an empty record
+func NewRecord(userID uuid.UUID, kind Kind, key string) *Record {
+	return &Record{
+		UserID: userID,
+		Kind:   kind,
+		Key:    key,
+		Fields: make(map[string]Field),
+	}
+}
+
+// Deleted reports whether the record is a tombstone
+func (r *Record) Deleted() bool {
+	return r.Cleared != nil && len(r.Fields) == 0
+}
+
+// Merge applies a change from the device and reports whether the record
+// changed, which a conflict may still do in part. Each field keeps
+// whichever write is later, so merging the same change again, or changes
+// in any order, ends in the same record.
+func (r *Record) Merge(device string, change Change, now time.Time) (Outcome, bool) {
+	at := change.At
+	if at.IsZero() || at.After(now) {
+		at = now
+	}
+	// Stored times lose precision below a millisecond; stamps must compare
+	// the same after a round trip
+	stamp := Stamp{At: at.UTC().Truncate(time.Millisecond), Device: device}
+
+	if change.Delete {
+		return r.clear(stamp, now)
+	}
+
+	changed, lost := false, false
+	for name, value := range change.Fields {
+		value = compact(value)
+		if r.Cleared != nil && !stamp.After(*r.Cleared) {
+			lost = true
+			continue
+		}
+		current, ok := r.Fields[name]
+		if ok && current.Stamp.Equal(stamp) && bytes.Equal(current.Value, value) {
+			continue
+		}
+		if ok && !stamp.After(current.Stamp) {
+			lost = true
+			continue
+		}
+		r.Fields[name] = Field{Value: value, Stamp: stamp}
+		changed = true
+	}
+
+	if changed {
+		r.UpdatedAt = now
+	}
+	switch {
+	case lost:
+		return OutcomeConflict, changed
+	case changed:
+		return OutcomeApplied, true
+	default:
+		return OutcomeUnchanged, false
+	}
+}
+
+// clear drops the fields written before the delete. Fields written after
+// it survive, so an edit made after the delete on another device wins and
+// the record lives on.
+func (r *Record) clear(stamp Stamp, now time.Time) (Outcome, bool) {
+	if r.Cleared != nil && !stamp.After(*r.Cleared) {
+		if r.Cleared.Equal(stamp) {
+			return OutcomeUnchanged, false
+		}
+		return OutcomeConflict, false
+	}
+
+	r.Cleared = &stamp
+	r.UpdatedAt = now
+	for name, field := range r.Fields {
+		if stamp.After(field.Stamp) {
+			delete(r.Fields, name)
+		}
+	}
+	if len(r.Fields) > 0 {
+		return OutcomeConflict, true
+	}
+	return OutcomeApplied, true
+}
+
+// compact strips insignificant space, so a resent value compares equal
+// to the stored one however the device formatted it
+func compact(value json.RawMessage) json.RawMessage {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, value); err != nil {
+		return value
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,95 @@
+package offline
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var base = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+func at(seconds int) time.Time {
+	return base.Add(time.Duration(seconds) * time.Second)
+}
+
+func write(at time.Time, fields map[string]string) Change {
+	change := Change{Kind: KindNote, Key: "note-1", At: at, Fields: make(map[string]json.RawMessage)}
+	for name, value := range fields {
+		change.Fields[name] = json.RawMessage(value)
+	}
+	return change
+}
+
+// merge drops whether the record changed, which the outcomes here imply
+func merge(r *Record, device string, change Change) Outcome {
+	outcome, _ := r.Merge(device, change, at(600))
+	return outcome
+}
+
+func TestMergeKeepsLatestWritePerField(t *testing.T) {
+	note := NewRecord(uuid.New(), KindNote, "note-1")
+
+	assert.Equal(t, OutcomeApplied, merge(note, "phone", write(at(0), map[string]string{"title": `"Stock"`, "text": `"Roast the bones"`})))
+	// The tablet renamed the note while the phone, offline, edited the text
+	assert.Equal(t, OutcomeApplied, merge(note, "tablet", write(at(30), map[string]string{"title": `"Brown stock"`})))
+	assert.Equal(t, OutcomeApplied, merge(note, "phone", write(at(20), map[string]string{"text": `"Roast the bones hard"`})))
+	assert.JSONEq(t, `"Brown stock"`, string(note.Fields["title"].Value))
+	assert.JSONEq(t, `"Roast the bones hard"`, string(note.Fields["text"].Value))
+
+	// An older title loses; resending the winning write changes nothing
+	assert.Equal(t, OutcomeConflict, merge(note, "phone", write(at(10), map[string]string{"title": `"Stok"`})))
+	assert.Equal(t, OutcomeUnchanged, merge(note, "tablet", write(at(30), map[string]string{"title": `"Brown stock"`})))
+
+	// Same time, different devices: the higher device ID wins everywhere
+	assert.Equal(t, OutcomeConflict, merge(note, "laptop", write(at(30), map[string]string{"title": `"Fond"`})))
+	assert.JSONEq(t, `"Brown stock"`, string(note.Fields["title"].Value))
+}
+
+func TestMergeDeleteLeavesTombstone(t *testing.T) {
+	note := NewRecord(uuid.New(), KindNote, "note-1")
+	merge(note, "phone", write(at(0), map[string]string{"title": `"Stock"`}))
+
+	assert.Equal(t, OutcomeApplied, merge(note, "tablet", Change{Kind: KindNote, Key: "note-1", Delete: true, At: at(10)}))
+	assert.True(t, note.Deleted())
+	assert.Equal(t, OutcomeUnchanged, merge(note, "tablet", Change{Kind: KindNote, Key: "note-1", Delete: true, At: at(10)}))
+
+	// An edit made before the delete stays deleted
+	assert.Equal(t, OutcomeConflict, merge(note, "phone", write(at(5), map[string]string{"text": `"Skim"`})))
+	assert.True(t, note.Deleted())
+
+	// An edit made after it brings the note back
+	assert.Equal(t, OutcomeApplied, merge(note, "phone", write(at(20), map[string]string{"text": `"Skim"`})))
+	assert.False(t, note.Deleted())
+	assert.NotContains(t, note.Fields, "title")
+
+	// A late delete only drops what was written before it
+	assert.Equal(t, OutcomeConflict, merge(note, "laptop", Change{Kind: KindNote, Key: "note-1", Delete: true, At: at(15)}))
+	assert.Contains(t, note.Fields, "text")
+}
+
+func TestChangeValidate(t *testing.T) {
+	recipeID := uuid.NewString()
+	valid := Change{Kind: KindBookmark, Key: recipeID, Fields: map[string]json.RawMessage{"pinned": json.RawMessage("true")}}
+	assert.NoError(t, valid.Validate())
+
+	cases := map[string]struct {
+		change Change
+		err    error
+	}{
+		"unknown kind":    {Change{Kind: "recipe", Key: "x", Delete: true}, ErrUnknownKind},
+		"server kind":     {Change{Kind: KindDraft, Key: recipeID, Delete: true}, ErrReadOnlyKind},
+		"bookmark key":    {Change{Kind: KindBookmark, Key: "soup", Delete: true}, ErrInvalidKey},
+		"unknown field":   {Change{Kind: KindNote, Key: "n", Fields: map[string]json.RawMessage{"color": json.RawMessage(`"red"`)}}, ErrUnknownField},
+		"invalid JSON":    {Change{Kind: KindNote, Key: "n", Fields: map[string]json.RawMessage{"text": json.RawMessage(`{`)}}, ErrInvalidValue},
+		"nothing to do":   {Change{Kind: KindNote, Key: "n"}, ErrEmptyChange},
+		"delete and edit": {Change{Kind: KindNote, Key: "n", Delete: true, Fields: map[string]json.RawMessage{"text": json.RawMessage(`""`)}}, ErrDeleteOrFields},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, tc.change.Validate(), tc.err)
+		})
+	}
+}
@@ -0,0 +1,294 @@
+// Package pantry contains the domain model for a user's pantry inventory
+// and what cooking a recipe takes out of it. Cooking is planned first:
+// each recipe ingredient is matched to a pantry item and converted to the
+// item's unit, the cook confirms or edits the amounts, and only then is
+// the pantry depleted and the consumption logged.
+package pantry
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/units"
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxItems bounds one user's pantry
+	MaxItems = 500
+	// MaxNameLength bounds item names, in characters
+	MaxNameLength = 200
+	// LowStockFraction of an item's par level is when it should be
+	// restocked
+	LowStockFraction = 0.25
+)
+
+// Domain errors for pantry items and cooks
+var (
+	ErrEmptyName         = errors.New("name must not be empty")
+	ErrNameTooLong       = errors.New("name must not exceed 200 characters")
+	ErrNegativeQuantity  = errors.New("quantity must not be negative")
+	ErrNegativePar       = errors.New("par must not be negative")
+	ErrTooManyItems      = errors.New("pantry is full")
+	ErrInvalidDeduction  = errors.New("deduction amount must be positive")
+	ErrDuplicateDeducted = errors.New("an item can only be deducted once per cook")
+)
+
+// Item is something the user keeps in stock. Par is how much they like to
+// keep on hand, zero when they do not track it.
+type Item struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	Quantity  float64
+	Unit      recipe.MeasurementUnit
+	Par       float64
+	UpdatedAt time.Time
+}
+
+// NewItem creates a pantry item
+func NewItem(userID uuid.UUID, name string, quantity float64, unit recipe.MeasurementUnit, par float64) (*Item, error) {
+	item := &Item{ID: uuid.New(), UserID: userID, UpdatedAt: time.Now()}
+	if err := item.Update(name, quantity, unit, par); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Update replaces the item's details
+func (i *Item) Update(name string, quantity float64, unit recipe.MeasurementUnit, par float64) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrEmptyName
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return ErrNameTooLong
+	}
+	if quantity < 0 || math.IsNaN(quantity) || math.IsInf(quantity, 0) {
+		return ErrNegativeQuantity
+	}
+	if par < 0 || math.IsNaN(par) || math.IsInf(par, 0) {
+		return ErrNegativePar
+	}
+	i.Name = name
+	i.Quantity = quantity
+	i.Unit = unit
+	i.Par = par
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// Low reports whether the item has run out or fallen below its restock
+// level
+func (i *Item) Low() bool {
+	if i.Quantity <= 0 {
+		return true
+	}
+	return i.Par > 0 && i.Quantity < i.Par*LowStockFraction
+}
+
+// Restock is how much to buy to get back to par, or zero when the item is
+// not low. Items without a par are restocked by what was last used.
+func (i *Item) Restock(used float64) float64 {
+	if !i.Low() {
+		return 0
+	}
+	if i.Par > 0 {
+		return round(i.Par - i.Quantity)
+	}
+	return round(used)
+}
+
+// Deduction is what cooking takes from one pantry item, in the item's unit
+type Deduction struct {
+	Ingredient string
+	ItemID     uuid.UUID
+	ItemName   string
+	Amount     float64
+	Unit       recipe.MeasurementUnit
+	// Short is how much more the recipe needs than the pantry holds
+	Short float64
+}
+
+// Unmatched is an ingredient with no pantry item, or whose amount cannot
+// be converted to the item's unit
+type Unmatched struct {
+	Ingredient string
+	ItemID     *uuid.UUID
+	Reason     string
+}
+
+// Reasons an ingredient is not deducted
+const (
+	ReasonNotInPantry  = "not_in_pantry"
+	ReasonNoAmount     = "no_amount"
+	ReasonNoConversion = "no_conversion"
+)
+
+// Plan is the proposed depletion for cooking a recipe
+type Plan struct {
+	Deductions []Deduction
+	Unmatched  []Unmatched
+}
+
+// PlanCook matches the recipe's ingredients, scaled by factor, to the
+// pantry and converts each to its item's unit. Ingredients matching the
+// same item are added together.
+func PlanCook(items []*Item, ingredients []recipe.Ingredient, factor float64) Plan {
+	var plan Plan
+	index := map[uuid.UUID]int{}
+	for _, ingredient := range ingredients {
+		if ingredient.Optional {
+			continue
+		}
+		item := Match(items, ingredient.Name)
+		if item == nil {
+			plan.Unmatched = append(plan.Unmatched, Unmatched{Ingredient: ingredient.Name, Reason: ReasonNotInPantry})
+			continue
+		}
+		if ingredient.Amount <= 0 {
+			plan.Unmatched = append(plan.Unmatched, Unmatched{Ingredient: ingredient.Name, ItemID: &item.ID, Reason: ReasonNoAmount})
+			continue
+		}
+		amount, ok := units.Convert(ingredient.Amount*factor, ingredient.Unit, item.Unit, item.Name+" "+ingredient.Name)
+		if !ok {
+			plan.Unmatched = append(plan.Unmatched, Unmatched{Ingredient: ingredient.Name, ItemID: &item.ID, Reason: ReasonNoConversion})
+			continue
+		}
+
+		if i, ok := index[item.ID]; ok {
+			plan.Deductions[i].Ingredient += ", " + ingredient.Name
+			plan.Deductions[i].Amount += amount
+			continue
+		}
+		index[item.ID] = len(plan.Deductions)
+		plan.Deductions = append(plan.Deductions, Deduction{
+			Ingredient: ingredient.Name,
+			ItemID:     item.ID,
+			ItemName:   item.Name,
+			Amount:     amount,
+			Unit:       item.Unit,
+		})
+	}
+
+	for i := range plan.Deductions {
+		d := &plan.Deductions[i]
+		d.Amount = round(d.Amount)
+		for _, item := range items {
+			if item.ID == d.ItemID && d.Amount > item.Quantity {
+				d.Short = round(d.Amount - item.Quantity)
+			}
+		}
+	}
+	return plan
+}
+
+// Match finds the pantry item an ingredient is drawn from: the item whose
+// name words all appear in the ingredient's name, preferring the most
+// specific, so "brown sugar" is not taken from "sugar" when both are kept
+func Match(items []*Item, ingredient string) *Item {
+	have := stems(ingredient)
+	var best *Item
+	bestWords := 0
+	for _, item := range items {
+		want := stems(item.Name)
+		if len(want) == 0 || len(want) <= bestWords {
+			continue
+		}
+		all := true
+		for word := range want {
+			if !have[word] {
+				all = false
+				break
+			}
+		}
+		if all {
+			best, bestWords = item, len(want)
+		}
+	}
+	return best
+}
+
+func stems(name string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 0x7f)
+	})
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[stem(word)] = true
+	}
+	return set
+}
+
+// stem drops plural endings so "eggs" matches "egg"
+func stem(word string) string {
+	switch {
+	case strings.HasSuffix(word, "oes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+// Cook records that a user cooked a recipe and what it took from the
+// pantry
+type Cook struct {
+	ID       uuid.UUID
+	UserID   uuid.UUID
+	RecipeID uuid.UUID
+	Portions int
+	CookedAt time.Time
+	Used     []Consumption
+}
+
+// Consumption is what one cook took from one pantry item. The item's name
+// and unit are kept so history survives the item being deleted.
+type Consumption struct {
+	CookID   uuid.UUID
+	RecipeID uuid.UUID
+	ItemID   uuid.UUID
+	ItemName string
+	Amount   float64
+	Unit     recipe.MeasurementUnit
+	CookedAt time.Time
+}
+
+// NewCook starts the record of a cook; Take adds what it used
+func NewCook(userID, recipeID uuid.UUID, portions int, at time.Time) *Cook {
+	return &Cook{ID: uuid.New(), UserID: userID, RecipeID: recipeID, Portions: portions, CookedAt: at}
+}
+
+// Take records a confirmed amount taken from an item, in the item's unit
+func (c *Cook) Take(item *Item, amount float64) error {
+	if amount <= 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return ErrInvalidDeduction
+	}
+	for _, used := range c.Used {
+		if used.ItemID == item.ID {
+			return ErrDuplicateDeducted
+		}
+	}
+	c.Used = append(c.Used, Consumption{
+		CookID:   c.ID,
+		RecipeID: c.RecipeID,
+		ItemID:   item.ID,
+		ItemName: item.Name,
+		Amount:   round(amount),
+		Unit:     item.Unit,
+		CookedAt: c.CookedAt,
+	})
+	return nil
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,53 @@
+package pantry
+
+import (
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanCookConvertsToItemUnits(t *testing.T) {
+	userID := uuid.New()
+	sugar, err := NewItem(userID, "sugar", 1, recipe.MeasurementUnitKilogram, 0)
+	require.NoError(t, err)
+	brown, err := NewItem(userID, "brown sugar", 500, recipe.MeasurementUnitGram, 1000)
+	require.NoError(t, err)
+	milk, err := NewItem(userID, "milk", 1, recipe.MeasurementUnitLiter, 2)
+	require.NoError(t, err)
+	basil, err := NewItem(userID, "basil", 2, recipe.MeasurementUnitBunch, 0)
+	require.NoError(t, err)
+	items := []*Item{sugar, brown, milk, basil}
+
+	assert.Equal(t, brown, Match(items, "Light Brown Sugar"), "the most specific item wins")
+	assert.Equal(t, sugar, Match(items, "caster sugar"))
+	assert.Nil(t, Match(items, "butter"))
+
+	plan := PlanCook(items, []recipe.Ingredient{
+		{Name: "brown sugar", Amount: 300, Unit: recipe.MeasurementUnitGram},
+		{Name: "light brown sugar", Amount: 100, Unit: recipe.MeasurementUnitGram},
+		{Name: "whole milk", Amount: 2, Unit: recipe.MeasurementUnitCup},
+		{Name: "basil leaves", Amount: 20, Unit: recipe.MeasurementUnitGram},
+		{Name: "sea salt", Amount: 1, Unit: recipe.MeasurementUnitPinch},
+		{Name: "sugar", Amount: 1, Unit: recipe.MeasurementUnitTeaspoon, Optional: true},
+	}, 2)
+
+	require.Len(t, plan.Deductions, 2)
+	assert.Equal(t, "brown sugar, light brown sugar", plan.Deductions[0].Ingredient)
+	assert.Equal(t, 800.0, plan.Deductions[0].Amount)
+	assert.Equal(t, 300.0, plan.Deductions[0].Short)
+	assert.Equal(t, recipe.MeasurementUnitLiter, plan.Deductions[1].Unit)
+	assert.InDelta(t, 0.95, plan.Deductions[1].Amount, 0.01)
+
+	require.Len(t, plan.Unmatched, 2)
+	assert.Equal(t, ReasonNoConversion, plan.Unmatched[0].Reason)
+	assert.Equal(t, ReasonNotInPantry, plan.Unmatched[1].Reason)
+
+	brown.Quantity = 100
+	assert.True(t, brown.Low())
+	assert.Equal(t, 900.0, brown.Restock(800))
+	sugar.Quantity = 0
+	assert.Equal(t, 0.25, sugar.Restock(0.25), "without a par, restock what was used")
+}
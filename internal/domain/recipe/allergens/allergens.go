@@ -0,0 +1,318 @@
+// Package allergens works out which of the major food allergens a recipe
+// contains and which it may contain: through optional ingredients, the
+// substitutes an ingredient offers, and ingredients commonly made on lines
+// shared with an allergen.
+package allergens
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Allergen is one of the fourteen major allergens that labelling rules
+// require to be declared
+type Allergen string
+
+const (
+	Gluten      Allergen = "gluten"
+	Crustaceans Allergen = "crustaceans"
+	Eggs        Allergen = "eggs"
+	Fish        Allergen = "fish"
+	Peanuts     Allergen = "peanuts"
+	Soy         Allergen = "soy"
+	Milk        Allergen = "milk"
+	TreeNuts    Allergen = "tree-nuts"
+	Celery      Allergen = "celery"
+	Mustard     Allergen = "mustard"
+	Sesame      Allergen = "sesame"
+	Sulphites   Allergen = "sulphites"
+	Lupin       Allergen = "lupin"
+	Molluscs    Allergen = "molluscs"
+)
+
+// Status says how sure a disclosure is
+type Status string
+
+const (
+	// Contains means an ingredient every cook uses has the allergen
+	Contains Status = "contains"
+	// MayContain means only some versions of the dish have it
+	MayContain Status = "may-contain"
+)
+
+// Reason is why an ingredient brings an allergen in
+type Reason string
+
+const (
+	// ReasonIngredient is an ingredient made of or with the allergen
+	ReasonIngredient Reason = "ingredient"
+	// ReasonOptional is an optional ingredient with the allergen
+	ReasonOptional Reason = "optional"
+	// ReasonSubstitute is an alternative an ingredient offers ("butter or
+	// margarine", "swap in almond milk")
+	ReasonSubstitute Reason = "substitute"
+	// ReasonCrossContact is an ingredient usually made alongside the
+	// allergen
+	ReasonCrossContact Reason = "cross-contact"
+)
+
+// Definition is an allergen with the words that reveal it in an
+// ingredient name
+type Definition struct {
+	Allergen Allergen
+	Label    string
+	keywords *regexp.Regexp
+	// except are ingredients that share a keyword without the allergen:
+	// coconut milk, nutmeg, eggplant
+	except *regexp.Regexp
+}
+
+// Definitions are the major allergens in the order panels list them
+var Definitions = []Definition{
+	{Gluten, "Cereals containing gluten",
+		words(`wheat|flour|bread(?:crumb)?|panko|pasta|spaghetti|macaroni|noodle|couscous|bulgur|semolina|durum|spelt|rye|barley|malt|farro|seitan|tortilla|pita|cracker|biscuit|soy sauce|beer|ale`),
+		words(`(?:rice|corn|almond|coconut|chickpea|buckwheat|tapioca|potato|cassava|oat|gluten[- ]free|gf) (?:flour|pasta|noodle|bread|tortilla|cracker)|rice noodle|glass noodle|corn tortilla|gluten[- ]free|tamari|ginger ale|root beer`)},
+	{Crustaceans, "Crustaceans",
+		words(`shrimp|prawn|crab|lobster|crayfish|crawfish|langoustine|krill|scampi`),
+		words(`imitation crab|crab ?apple`)},
+	{Eggs, "Eggs",
+		words(`eggs?|egg (?:yolk|white)|yolk|mayonnaise|mayo|meringue|aioli|hollandaise|brioche|custard|egg noodle`),
+		words(`eggplant|egg[- ]free|vegan (?:mayo|mayonnaise)|egg replacer`)},
+	{Fish, "Fish",
+		words(`fish|anchov(?:y|ies)|salmon|tuna|cod|halibut|tilapia|trout|haddock|sardine|mackerel|herring|bass|snapper|pollock|swordfish|bonito|dashi|worcestershire|caesar dressing|fish sauce`),
+		words(`fish[- ]free|vegan (?:fish|worcestershire)`)},
+	{Peanuts, "Peanuts",
+		words(`peanuts?|peanut butter|groundnut|monkey nut|satay`),
+		nil},
+	{Soy, "Soybeans",
+		words(`soy|soya|soybean|tofu|tempeh|edamame|miso|tamari|soy sauce|soy milk|natto|teriyaki|hoisin`),
+		words(`soy[- ]free`)},
+	{Milk, "Milk",
+		words(`milk|butter|buttermilk|cream|cheese|parmesan|parmigiano|pecorino|mozzarella|burrata|cheddar|feta|ricotta|mascarpone|gruy[eè]re|emmental|gouda|brie|camembert|halloumi|gorgonzola|roquefort|stilton|manchego|provolone|yogh?urt|ghee|whey|casein|custard|cr[eè]me fra[iî]che|paneer|quark|kefir|b[eé]chamel|alfredo`),
+		words(`(?:coconut|almond|oat|soy|soya|rice|cashew|hemp|plant|vegan|dairy[- ]free|non[- ]dairy) (?:milk|cream|butter|cheese|yogh?urt)|peanut butter|(?:almond|cashew|nut|apple|cocoa|shea) butter|cream of tartar|butternut|butter bean|dairy[- ]free`)},
+	{TreeNuts, "Nuts",
+		words(`almonds?|hazelnuts?|walnuts?|cashews?|pecans?|pistachios?|macadamias?|brazil nuts?|pine nuts?|nuts?|praline|marzipan|frangipane|nutella|gianduja|pesto|amaretto|almond (?:milk|butter|flour|extract)`),
+		words(`nutmeg|butternut|coconut|peanuts?|groundnut|monkey nut|doughnut|donut|chestnut|water chestnut|tiger nut|nut[- ]free`)},
+	{Celery, "Celery",
+		words(`celery|celeriac|celery (?:salt|seed)`),
+		nil},
+	{Mustard, "Mustard",
+		words(`mustard|mustard (?:seed|powder)|dijon`),
+		nil},
+	{Sesame, "Sesame",
+		words(`sesame|tahini|hummus|houmous|halva|za'?atar|gomasio|furikake`),
+		words(`sesame[- ]free`)},
+	{Sulphites, "Sulphur dioxide and sulphites",
+		words(`wine|sherry|port|vermouth|vinegar|dried (?:apricot|fruit|fig|mango)|raisins?|sultanas?|currants|prunes?|sulphites?|sulfites?|molasses`),
+		words(`rice vinegar|wine[- ]free`)},
+	{Lupin, "Lupin",
+		words(`lupin|lupine`),
+		nil},
+	{Molluscs, "Molluscs",
+		words(`mussels?|clams?|oysters?|scallops?|squid|calamari|octopus|cuttlefish|snails?|escargot|whelks?|abalone|oyster sauce`),
+		words(`oyster mushroom|vegan oyster sauce`)},
+}
+
+// crossContact are ingredients usually processed alongside an allergen
+// they do not contain
+var crossContact = []struct {
+	keywords  *regexp.Regexp
+	allergens []Allergen
+}{
+	{words(`oats?|oatmeal|rolled oats|porridge oats|buckwheat`), []Allergen{Gluten}},
+	{words(`chocolate|cocoa|cacao`), []Allergen{Milk, TreeNuts, Peanuts}},
+	{words(`granola|muesli|trail mix|dried fruit mix`), []Allergen{TreeNuts, Peanuts}},
+	{words(`coconut`), []Allergen{TreeNuts}},
+	{words(`sunflower seeds?|pumpkin seeds?|pepitas|mixed seeds?`), []Allergen{TreeNuts, Sesame}},
+	{words(`curry powder|garam masala|spice mix|spice blend`), []Allergen{Mustard, Celery}},
+	{words(`stock cubes?|bouillon|gravy granules|stock powder`), []Allergen{Celery, Gluten}},
+}
+
+var (
+	// Alternatives in a name: "butter or margarine", "milk/cream"
+	alternativeRe = regexp.MustCompile(`(?i)\s+or\s+|\s*/\s*`)
+	// Substitutes offered in the notes: "or use almond milk", "swap in
+	// tahini", "substitute with peanut butter"
+	substituteRe = regexp.MustCompile(`(?i)\b(?:or(?: use)?|substitute with|swap (?:in|for|with)|replace with|alternatively(?: use)?)\s+([^,;.()]+)`)
+	optionalRe   = regexp.MustCompile(`(?i)\b(?:optional|to serve|for serving|garnish|if (?:you )?like|if desired)\b`)
+)
+
+func words(alternatives string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\b(?:` + alternatives + `)(?:e?s)?\b`)
+}
+
+// Ingredient is one recipe ingredient as disclosure reads it
+type Ingredient struct {
+	Name     string
+	Optional bool
+	Notes    string
+}
+
+// Source is an ingredient that brings an allergen in
+type Source struct {
+	Ingredient string
+	Reason     Reason
+}
+
+// Finding is one allergen a recipe contains or may contain
+type Finding struct {
+	Allergen Allergen
+	Label    string
+	Status   Status
+	Sources  []Source
+}
+
+// Disclosure is what a recipe declares, allergens it contains before
+// those it may contain, each in Definitions order
+type Disclosure struct {
+	Findings []Finding
+}
+
+// Disclose works out the allergens in a recipe. An allergen is contained
+// when an ingredient every cook uses has it, and may be contained when it
+// only comes with an optional ingredient, a substitute or cross-contact.
+func Disclose(ingredients []Ingredient) Disclosure {
+	sources := make(map[Allergen][]Source)
+	add := func(a Allergen, ingredient string, reason Reason) {
+		for _, s := range sources[a] {
+			if s.Ingredient == ingredient && s.Reason == reason {
+				return
+			}
+		}
+		sources[a] = append(sources[a], Source{Ingredient: ingredient, Reason: reason})
+	}
+
+	for _, ing := range ingredients {
+		name := strings.TrimSpace(ing.Name)
+		if name == "" {
+			continue
+		}
+		optional := ing.Optional || optionalRe.MatchString(name) || optionalRe.MatchString(ing.Notes)
+
+		// "butter or margarine" needs butter only if the cook picks it,
+		// so each alternative after the first is a substitute
+		alternatives := alternativeRe.Split(name, -1)
+		for i, alt := range alternatives {
+			reason := ReasonIngredient
+			if optional {
+				reason = ReasonOptional
+			} else if i > 0 {
+				reason = ReasonSubstitute
+			}
+			for _, a := range match(alt) {
+				add(a, name, reason)
+			}
+		}
+		for _, m := range substituteRe.FindAllStringSubmatch(ing.Notes, -1) {
+			for _, a := range match(m[1]) {
+				add(a, strings.TrimSpace(m[1]), ReasonSubstitute)
+			}
+		}
+		for _, rule := range crossContact {
+			if rule.keywords.MatchString(name) {
+				for _, a := range rule.allergens {
+					add(a, name, ReasonCrossContact)
+				}
+			}
+		}
+	}
+
+	var contains, mayContain []Finding
+	for _, def := range Definitions {
+		found := sources[def.Allergen]
+		if len(found) == 0 {
+			continue
+		}
+		finding := Finding{Allergen: def.Allergen, Label: def.Label, Status: MayContain, Sources: found}
+		for _, s := range found {
+			if s.Reason == ReasonIngredient {
+				finding.Status = Contains
+				break
+			}
+		}
+		if finding.Status == Contains {
+			contains = append(contains, finding)
+		} else {
+			mayContain = append(mayContain, finding)
+		}
+	}
+	return Disclosure{Findings: append(contains, mayContain...)}
+}
+
+// Of lists the allergens an ingredient name reveals, in Definitions order
+func Of(name string) []Allergen {
+	return match(name)
+}
+
+// aliases are the everyday names people give an allergen
+var aliases = map[string]Allergen{
+	"wheat": Gluten, "shellfish": Crustaceans, "shrimp": Crustaceans,
+	"egg": Eggs, "peanut": Peanuts, "soya": Soy, "soybeans": Soy,
+	"dairy": Milk, "lactose": Milk, "nuts": TreeNuts, "tree nuts": TreeNuts,
+	"sulfites": Sulphites, "mollusks": Molluscs,
+}
+
+// Parse reads an allergen from its name, label or an everyday alias such
+// as dairy or nuts
+func Parse(name string) (Allergen, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, def := range Definitions {
+		if name == string(def.Allergen) || name == strings.ToLower(def.Label) {
+			return def.Allergen, true
+		}
+	}
+	a, ok := aliases[strings.ReplaceAll(name, "-", " ")]
+	return a, ok
+}
+
+// match finds the allergens an ingredient name reveals
+func match(name string) []Allergen {
+	var found []Allergen
+	for _, def := range Definitions {
+		text := name
+		if def.except != nil {
+			text = def.except.ReplaceAllString(text, " ")
+		}
+		if def.keywords.MatchString(text) {
+			found = append(found, def.Allergen)
+		}
+	}
+	return found
+}
+
+// Contains lists the labels of the allergens the recipe contains
+func (d Disclosure) Contains() []string {
+	return d.labels(Contains)
+}
+
+// MayContain lists the labels of the allergens the recipe may contain
+func (d Disclosure) MayContain() []string {
+	return d.labels(MayContain)
+}
+
+func (d Disclosure) labels(status Status) []string {
+	var labels []string
+	for _, f := range d.Findings {
+		if f.Status == status {
+			labels = append(labels, f.Label)
+		}
+	}
+	return labels
+}
+
+// Panel is the standard allergen statement printed with a recipe:
+// "Contains: Milk, Eggs. May contain: Nuts."
+func (d Disclosure) Panel() string {
+	contains, mayContain := d.Contains(), d.MayContain()
+	if len(contains) == 0 && len(mayContain) == 0 {
+		return "No major allergens found in the ingredients."
+	}
+	var parts []string
+	if len(contains) > 0 {
+		parts = append(parts, "Contains: "+strings.Join(contains, ", ")+".")
+	}
+	if len(mayContain) > 0 {
+		parts = append(parts, "May contain: "+strings.Join(mayContain, ", ")+".")
+	}
+	return strings.Join(parts, " ")
+}
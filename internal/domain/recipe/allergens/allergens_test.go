@@ -0,0 +1,77 @@
+package allergens
+
+import (
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscloseSeparatesContainsFromMayContain(t *testing.T) {
+	d := Disclose([]Ingredient{
+		{Name: "plain flour"},
+		{Name: "unsalted butter or margarine"},
+		{Name: "eggs"},
+		{Name: "Pecorino Romano"},
+		{Name: "rolled oats"},
+		{Name: "coconut milk", Notes: "or use almond milk"},
+		{Name: "toasted sesame seeds", Optional: true},
+		{Name: "nutmeg"},
+		{Name: "eggplant"},
+	})
+
+	assert.Equal(t, []string{"Cereals containing gluten", "Eggs", "Milk"}, d.Contains())
+	assert.Equal(t, []string{"Nuts", "Sesame"}, d.MayContain())
+	assert.Equal(t, "Contains: Cereals containing gluten, Eggs, Milk. May contain: Nuts, Sesame.", d.Panel())
+
+	var nuts Finding
+	for _, f := range d.Findings {
+		if f.Allergen == TreeNuts {
+			nuts = f
+		}
+	}
+	assert.Equal(t, MayContain, nuts.Status)
+	assert.Contains(t, nuts.Sources, Source{Ingredient: "almond milk", Reason: ReasonSubstitute})
+	assert.Contains(t, nuts.Sources, Source{Ingredient: "coconut milk", Reason: ReasonCrossContact})
+}
+
+func TestDiscloseKeepsLookalikesOut(t *testing.T) {
+	d := Disclose([]Ingredient{
+		{Name: "peanut butter"},
+		{Name: "butternut squash"},
+		{Name: "cream of tartar"},
+		{Name: "rice noodles"},
+		{Name: "oyster mushrooms"},
+	})
+
+	assert.Equal(t, []string{"Peanuts"}, d.Contains())
+	assert.Empty(t, d.MayContain())
+
+	assert.Equal(t, "No major allergens found in the ingredients.", Disclose([]Ingredient{{Name: "salt"}}).Panel())
+}
+
+func TestParseAcceptsNamesLabelsAndAliases(t *testing.T) {
+	for name, want := range map[string]Allergen{
+		"peanuts": Peanuts, "Tree-Nuts": TreeNuts, "nuts": TreeNuts,
+		"Dairy": Milk, "Cereals containing gluten": Gluten, " shellfish ": Crustaceans,
+	} {
+		got, ok := Parse(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, ok := Parse("strawberries")
+	assert.False(t, ok)
+}
+
+func TestLabelSplitsContainsFromMayContain(t *testing.T) {
+	label := Label([]recipe.Ingredient{
+		{Name: "spaghetti"},
+		{Name: "eggs"},
+		{Name: "parmesan", Optional: true},
+	})
+
+	assert.Equal(t, []string{"gluten", "eggs"}, label.Contains)
+	assert.Equal(t, []string{"milk"}, label.MayContain)
+	assert.Equal(t, "Nuts", LabelOf("tree-nuts"))
+}
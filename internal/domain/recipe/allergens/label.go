@@ -0,0 +1,32 @@
+package allergens
+
+import "github.com/alchemorsel/v3/internal/domain/recipe"
+
+// Label is the allergen label stored on a recipe made of the ingredients
+func Label(ingredients []recipe.Ingredient) *recipe.AllergenLabel {
+	lines := make([]Ingredient, len(ingredients))
+	for i, ing := range ingredients {
+		lines[i] = Ingredient{Name: ing.Name, Optional: ing.Optional, Notes: ing.Notes}
+	}
+
+	label := &recipe.AllergenLabel{Contains: []string{}, MayContain: []string{}}
+	for _, f := range Disclose(lines).Findings {
+		if f.Status == Contains {
+			label.Contains = append(label.Contains, string(f.Allergen))
+		} else {
+			label.MayContain = append(label.MayContain, string(f.Allergen))
+		}
+	}
+	return label
+}
+
+// LabelOf is the name an allergen is declared under, such as Nuts for
+// tree-nuts; unknown names are returned as they are
+func LabelOf(name string) string {
+	for _, def := range Definitions {
+		if string(def.Allergen) == name {
+			return def.Label
+		}
+	}
+	return name
+}
@@ -3,6 +3,9 @@
 package recipe
 
 import (
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/alchemorsel/v3/internal/domain/shared"
@@ -20,11 +23,13 @@ type Recipe struct {
 	title       string
 	description string
 	authorID    uuid.UUID
+	language    string // BCP 47 tag, like en or pt-BR
 	
 	// Recipe details
 	ingredients    []Ingredient
 	instructions   []Instruction
 	nutritionInfo  *NutritionInfo
+	allergens      *AllergenLabel
 	
 	// Categorization
 	cuisine     CuisineType
@@ -49,24 +54,34 @@ type Recipe struct {
 	// Social features
 	likes       int
 	views       int
+	forks       int
 	ratings     []Rating
 	averageRating float64
 	
+	// Set on a copy made with Fork
+	forkedFromID *uuid.UUID
+	
 	// Media
 	images      []Image
 	videos      []Video
 	
 	// Metadata
-	status      RecipeStatus
-	publishedAt *time.Time
-	createdAt   time.Time
-	updatedAt   time.Time
-	deletedAt   *time.Time
+	status             RecipeStatus
+	publishedAt        *time.Time
+	scheduledPublishAt *time.Time
+	createdAt          time.Time
+	updatedAt          time.Time
+	deletedAt          *time.Time
 	
 	// Domain events to be dispatched
 	events []shared.DomainEvent
 }
 
+// DefaultLanguage is the language of recipes that do not say
+const DefaultLanguage = "en"
+
+var languageTagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
 // NewRecipe creates a new Recipe with validation
 func NewRecipe(title, description string, authorID uuid.UUID) (*Recipe, error) {
 	if err := validateTitle(title); err != nil {
@@ -84,6 +99,7 @@ func NewRecipe(title, description string, authorID uuid.UUID) (*Recipe, error) {
 		title:       title,
 		description: description,
 		authorID:    authorID,
+		language:    DefaultLanguage,
 		status:      RecipeStatusDraft,
 		createdAt:   now,
 		updatedAt:   now,
@@ -121,6 +137,30 @@ func (r *Recipe) AuthorID() uuid.UUID {
 	return r.authorID
 }
 
+// Language returns the language tag the recipe is written in
+func (r *Recipe) Language() string {
+	return r.language
+}
+
+// SetLanguage records the language the recipe is written in. Tags are
+// stored in their canonical case, so "PT-br" becomes "pt-BR".
+func (r *Recipe) SetLanguage(tag string) error {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if !languageTagPattern.MatchString(tag) {
+		return ErrInvalidLanguage
+	}
+	
+	parts := strings.Split(tag, "-")
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+	r.language = strings.Join(parts, "-")
+	r.updatedAt = time.Now()
+	return nil
+}
+
 // Version returns the recipe's version
 func (r *Recipe) Version() int64 {
 	return r.version
@@ -141,6 +181,12 @@ func (r *Recipe) NutritionInfo() *NutritionInfo {
 	return r.nutritionInfo
 }
 
+// Allergens returns the recipe's allergen label, nil for recipes saved
+// before labelling
+func (r *Recipe) Allergens() *AllergenLabel {
+	return r.allergens
+}
+
 // Cuisine returns the recipe's cuisine type
 func (r *Recipe) Cuisine() CuisineType {
 	return r.cuisine
@@ -211,6 +257,16 @@ func (r *Recipe) Views() int {
 	return r.views
 }
 
+// Forks returns how many times the recipe was forked
+func (r *Recipe) Forks() int {
+	return r.forks
+}
+
+// ForkedFromID returns the recipe this one was forked from, if any
+func (r *Recipe) ForkedFromID() *uuid.UUID {
+	return r.forkedFromID
+}
+
 // Ratings returns the recipe ratings
 func (r *Recipe) Ratings() []Rating {
 	return r.ratings
@@ -241,6 +297,11 @@ func (r *Recipe) PublishedAt() *time.Time {
 	return r.publishedAt
 }
 
+// ScheduledPublishAt returns when a draft is due to publish itself, if set
+func (r *Recipe) ScheduledPublishAt() *time.Time {
+	return r.scheduledPublishAt
+}
+
 // CreatedAt returns when the recipe was created
 func (r *Recipe) CreatedAt() time.Time {
 	return r.createdAt
@@ -276,6 +337,122 @@ func (r *Recipe) UpdateTitle(title string) error {
 	return nil
 }
 
+// SetServings sets how many servings the recipe makes
+func (r *Recipe) SetServings(servings int) error {
+	if servings <= 0 {
+		return ErrInvalidServings
+	}
+	
+	r.servings = servings
+	r.updatedAt = time.Now()
+	return nil
+}
+
+// SetTiming sets prep and cook time; total time is their sum
+func (r *Recipe) SetTiming(prepTime, cookTime time.Duration) {
+	if prepTime < 0 {
+		prepTime = 0
+	}
+	if cookTime < 0 {
+		cookTime = 0
+	}
+	
+	r.prepTime = prepTime
+	r.cookTime = cookTime
+	r.totalTime = prepTime + cookTime
+	r.updatedAt = time.Now()
+}
+
+// SetNutrition records the recipe's per-serving nutrition, computed from
+// its ingredients; nil clears it
+func (r *Recipe) SetNutrition(info *NutritionInfo) {
+	r.nutritionInfo = info
+	r.calories = 0
+	if info != nil {
+		r.calories = info.Calories
+	}
+}
+
+// SetAllergens records the allergens the recipe's ingredients declare
+func (r *Recipe) SetAllergens(label *AllergenLabel) {
+	r.allergens = label
+}
+
+// SetTags replaces the recipe tags, dropping blanks and duplicates
+func (r *Recipe) SetTags(tags []string) {
+	seen := make(map[string]bool, len(tags))
+	cleaned := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, tag)
+	}
+	
+	r.tags = cleaned
+	r.updatedAt = time.Now()
+}
+
+// UpdateDescription replaces the recipe description
+func (r *Recipe) UpdateDescription(description string) error {
+	if err := validateDescription(description); err != nil {
+		return err
+	}
+	
+	r.description = description
+	r.updatedAt = time.Now()
+	return nil
+}
+
+// SetClassification sets the cuisine, category and difficulty
+func (r *Recipe) SetClassification(cuisine CuisineType, category CategoryType, difficulty DifficultyLevel) error {
+	switch difficulty {
+	case "", DifficultyLevelEasy, DifficultyLevelMedium, DifficultyLevelHard, DifficultyLevelExpert:
+	default:
+		return ErrInvalidDifficulty
+	}
+	
+	r.cuisine = cuisine
+	r.category = category
+	r.difficulty = difficulty
+	r.updatedAt = time.Now()
+	return nil
+}
+
+// ReplaceIngredients swaps the whole ingredient list, keeping the old one if
+// any new ingredient is invalid
+func (r *Recipe) ReplaceIngredients(ingredients []Ingredient) error {
+	for _, ingredient := range ingredients {
+		if err := ingredient.Validate(); err != nil {
+			return err
+		}
+	}
+	
+	r.ingredients = append([]Ingredient(nil), ingredients...)
+	r.updatedAt = time.Now()
+	return nil
+}
+
+// ReplaceInstructions swaps the whole step list, numbering the steps from 1
+// and keeping the old list if any new step is invalid
+func (r *Recipe) ReplaceInstructions(instructions []Instruction) error {
+	replaced := make([]Instruction, len(instructions))
+	for i, instruction := range instructions {
+		if err := instruction.Validate(); err != nil {
+			return err
+		}
+		instruction.StepNumber = i + 1
+		replaced[i] = instruction
+	}
+	
+	r.instructions = replaced
+	r.updatedAt = time.Now()
+	return nil
+}
+
 // AddIngredient adds a new ingredient to the recipe
 func (r *Recipe) AddIngredient(ingredient Ingredient) error {
 	if err := ingredient.Validate(); err != nil {
@@ -307,6 +484,25 @@ func (r *Recipe) AddInstruction(instruction Instruction) error {
 	return nil
 }
 
+// AddImage attaches a photo; the first image becomes the primary one
+func (r *Recipe) AddImage(rawURL, caption string) error {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidImageURL
+	}
+	
+	r.images = append(r.images, Image{
+		ID:         uuid.New(),
+		URL:        parsed.String(),
+		Caption:    caption,
+		IsPrimary:  len(r.images) == 0,
+		UploadedAt: time.Now(),
+	})
+	r.updatedAt = time.Now()
+	
+	return nil
+}
+
 // Publish publishes the recipe making it publicly visible
 func (r *Recipe) Publish() error {
 	if r.status != RecipeStatusDraft {
@@ -320,6 +516,7 @@ func (r *Recipe) Publish() error {
 	now := time.Now()
 	r.status = RecipeStatusPublished
 	r.publishedAt = &now
+	r.scheduledPublishAt = nil
 	r.updatedAt = now
 	
 	r.addEvent(RecipePublishedEvent{
@@ -330,6 +527,52 @@ func (r *Recipe) Publish() error {
 	return nil
 }
 
+// SchedulePublish sets a future time for the draft to publish itself. The
+// recipe must already be complete enough to publish.
+func (r *Recipe) SchedulePublish(at time.Time) error {
+	if r.status != RecipeStatusDraft {
+		return ErrInvalidStatusTransition
+	}
+	
+	now := time.Now()
+	if !at.After(now) {
+		return ErrSchedulePublishInPast
+	}
+	
+	if err := r.validateForPublishing(); err != nil {
+		return err
+	}
+	
+	at = at.UTC()
+	r.scheduledPublishAt = &at
+	r.updatedAt = now
+	
+	r.addEvent(RecipePublishScheduledEvent{
+		RecipeID:    r.id,
+		PublishAt:   at,
+		ScheduledAt: now,
+	})
+	
+	return nil
+}
+
+// CancelScheduledPublish keeps the draft unpublished
+func (r *Recipe) CancelScheduledPublish() error {
+	if r.scheduledPublishAt == nil {
+		return ErrPublishNotScheduled
+	}
+	
+	r.scheduledPublishAt = nil
+	r.updatedAt = time.Now()
+	
+	return nil
+}
+
+// IsPublishDue reports whether a scheduled draft should be published by now
+func (r *Recipe) IsPublishDue(now time.Time) bool {
+	return r.status == RecipeStatusDraft && r.scheduledPublishAt != nil && !r.scheduledPublishAt.After(now)
+}
+
 // Archive archives the recipe
 func (r *Recipe) Archive() error {
 	if r.status != RecipeStatusPublished {
@@ -347,6 +590,23 @@ func (r *Recipe) Archive() error {
 	return nil
 }
 
+// Unarchive returns an archived recipe to published, e.g. to undo an unpublish
+func (r *Recipe) Unarchive() error {
+	if r.status != RecipeStatusArchived {
+		return ErrInvalidStatusTransition
+	}
+	
+	r.status = RecipeStatusPublished
+	r.updatedAt = time.Now()
+	
+	r.addEvent(RecipeUnarchivedEvent{
+		RecipeID:     r.id,
+		UnarchivedAt: r.updatedAt,
+	})
+	
+	return nil
+}
+
 // Like increments the like count
 func (r *Recipe) Like(userID uuid.UUID) {
 	r.likes++
@@ -4,7 +4,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/alchemorsel/v3/test/testutils"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,14 +13,25 @@ import (
 // RecipeTestSuite provides a test suite for Recipe entity
 type RecipeTestSuite struct {
 	suite.Suite
-	recipeFactory *testutils.RecipeFactory
-	assertions    *testutils.RecipeAssertions
 }
 
-// SetupSuite initializes the test suite
-func (suite *RecipeTestSuite) SetupSuite() {
-	suite.recipeFactory = testutils.NewRecipeFactory(time.Now().UnixNano())
-	suite.assertions = testutils.NewRecipeAssertions(suite.T())
+// newValidRecipe creates a draft recipe that can be published. The
+// testutils factories import this package, so they can't be used here.
+func newValidRecipe() (*Recipe, error) {
+	r, err := NewRecipe("Spaghetti Aglio e Olio", "A classic Italian pasta dish", uuid.New())
+	if err != nil {
+		return nil, err
+	}
+	if err := r.SetServings(4); err != nil {
+		return nil, err
+	}
+	if err := r.AddIngredient(Ingredient{ID: uuid.New(), Name: "Spaghetti", Amount: 1, Unit: MeasurementUnitPound}); err != nil {
+		return nil, err
+	}
+	if err := r.AddInstruction(Instruction{StepNumber: 1, Description: "Cook spaghetti al dente", Duration: 10 * time.Minute}); err != nil {
+		return nil, err
+	}
+	return r, nil
 }
 
 // TestRecipeCreation tests recipe creation scenarios
@@ -38,18 +48,18 @@ func (suite *RecipeTestSuite) TestRecipeCreation() {
 		// Assert
 		require.NoError(suite.T(), err)
 		require.NotNil(suite.T(), recipe)
-		
+
 		assert.Equal(suite.T(), title, recipe.Title())
 		assert.NotEqual(suite.T(), uuid.Nil, recipe.ID())
 		assert.Equal(suite.T(), RecipeStatusDraft, recipe.status)
 		assert.NotZero(suite.T(), recipe.createdAt)
 		assert.NotZero(suite.T(), recipe.updatedAt)
 		assert.Equal(suite.T(), int64(1), recipe.version)
-		
+
 		// Check domain events
 		events := recipe.Events()
 		assert.Len(suite.T(), events, 1)
-		
+
 		createdEvent, ok := events[0].(RecipeCreatedEvent)
 		assert.True(suite.T(), ok, "Should emit RecipeCreatedEvent")
 		assert.Equal(suite.T(), recipe.ID(), createdEvent.RecipeID)
@@ -124,6 +134,7 @@ func (suite *RecipeTestSuite) TestRecipeModification() {
 		recipe, _ := NewRecipe("Original Title", "Description", uuid.New())
 		newTitle := "Updated Title"
 		originalUpdatedAt := recipe.updatedAt
+		recipe.Events() // Clear creation events
 
 		// Act
 		time.Sleep(1 * time.Millisecond) // Ensure time difference
@@ -133,11 +144,11 @@ func (suite *RecipeTestSuite) TestRecipeModification() {
 		require.NoError(suite.T(), err)
 		assert.Equal(suite.T(), newTitle, recipe.Title())
 		assert.True(suite.T(), recipe.updatedAt.After(originalUpdatedAt))
-		
+
 		// Check domain events
 		events := recipe.Events()
 		assert.Len(suite.T(), events, 1) // Only the update event (creation event was consumed)
-		
+
 		titleEvent, ok := events[0].(RecipeTitleUpdatedEvent)
 		assert.True(suite.T(), ok, "Should emit RecipeTitleUpdatedEvent")
 		assert.Equal(suite.T(), "Original Title", titleEvent.OldTitle)
@@ -165,22 +176,23 @@ func (suite *RecipeTestSuite) TestRecipeIngredients() {
 		// Arrange
 		recipe, _ := NewRecipe("Test Recipe", "Description", uuid.New())
 		ingredient := Ingredient{
-			ID:       uuid.New(),
-			Name:     "Spaghetti",
-			Quantity: 1.0,
-			Unit:     "lb",
+			ID:     uuid.New(),
+			Name:   "Spaghetti",
+			Amount: 1.0,
+			Unit:   "lb",
 		}
+		recipe.Events() // Clear creation events
 
 		// Act
 		err := recipe.AddIngredient(ingredient)
 
 		// Assert
 		require.NoError(suite.T(), err)
-		
+
 		// Check domain events
 		events := recipe.Events()
-		assert.Len(suite.T(), events, 1) // Creation event was consumed earlier
-		
+		assert.Len(suite.T(), events, 1)
+
 		ingredientEvent, ok := events[0].(IngredientAddedEvent)
 		assert.True(suite.T(), ok, "Should emit IngredientAddedEvent")
 		assert.Equal(suite.T(), recipe.ID(), ingredientEvent.RecipeID)
@@ -191,10 +203,10 @@ func (suite *RecipeTestSuite) TestRecipeIngredients() {
 		// Arrange
 		recipe, _ := NewRecipe("Test Recipe", "Description", uuid.New())
 		ingredient := Ingredient{
-			ID:       uuid.New(),
-			Name:     "", // Invalid - empty name
-			Quantity: 1.0,
-			Unit:     "lb",
+			ID:     uuid.New(),
+			Name:   "", // Invalid - empty name
+			Amount: 1.0,
+			Unit:   "lb",
 		}
 
 		// Act
@@ -211,7 +223,6 @@ func (suite *RecipeTestSuite) TestRecipeInstructions() {
 		// Arrange
 		recipe, _ := NewRecipe("Test Recipe", "Description", uuid.New())
 		instruction := Instruction{
-			ID:          uuid.New(),
 			Description: "Boil water in a large pot",
 			Duration:    5 * time.Minute,
 		}
@@ -221,19 +232,18 @@ func (suite *RecipeTestSuite) TestRecipeInstructions() {
 
 		// Assert
 		require.NoError(suite.T(), err)
-		assert.Equal(suite.T(), 1, instruction.StepNumber) // Should be set automatically
+		require.Len(suite.T(), recipe.Instructions(), 1)
+		assert.Equal(suite.T(), 1, recipe.Instructions()[0].StepNumber) // Should be set automatically
 	})
 
 	suite.Run("AddMultipleInstructions_ShouldNumberSequentially", func() {
 		// Arrange
 		recipe, _ := NewRecipe("Test Recipe", "Description", uuid.New())
 		instruction1 := Instruction{
-			ID:          uuid.New(),
 			Description: "First step",
 			Duration:    5 * time.Minute,
 		}
 		instruction2 := Instruction{
-			ID:          uuid.New(),
 			Description: "Second step",
 			Duration:    10 * time.Minute,
 		}
@@ -245,8 +255,9 @@ func (suite *RecipeTestSuite) TestRecipeInstructions() {
 		// Assert
 		require.NoError(suite.T(), err1)
 		require.NoError(suite.T(), err2)
-		assert.Equal(suite.T(), 1, instruction1.StepNumber)
-		assert.Equal(suite.T(), 2, instruction2.StepNumber)
+		require.Len(suite.T(), recipe.Instructions(), 2)
+		assert.Equal(suite.T(), 1, recipe.Instructions()[0].StepNumber)
+		assert.Equal(suite.T(), 2, recipe.Instructions()[1].StepNumber)
 	})
 }
 
@@ -254,7 +265,7 @@ func (suite *RecipeTestSuite) TestRecipeInstructions() {
 func (suite *RecipeTestSuite) TestRecipePublishing() {
 	suite.Run("PublishValidRecipe_ShouldPublish", func() {
 		// Arrange
-		recipe, _ := suite.recipeFactory.CreateValidRecipe()
+		recipe, _ := newValidRecipe()
 		recipe.Events() // Clear creation events
 
 		// Act
@@ -264,11 +275,11 @@ func (suite *RecipeTestSuite) TestRecipePublishing() {
 		require.NoError(suite.T(), err)
 		assert.Equal(suite.T(), RecipeStatusPublished, recipe.status)
 		assert.NotNil(suite.T(), recipe.publishedAt)
-		
+
 		// Check domain events
 		events := recipe.Events()
 		assert.Len(suite.T(), events, 1)
-		
+
 		publishedEvent, ok := events[0].(RecipePublishedEvent)
 		assert.True(suite.T(), ok, "Should emit RecipePublishedEvent")
 		assert.Equal(suite.T(), recipe.ID(), publishedEvent.RecipeID)
@@ -292,10 +303,10 @@ func (suite *RecipeTestSuite) TestRecipePublishing() {
 		// Arrange
 		recipe, _ := NewRecipe("Test Recipe", "Description", uuid.New())
 		recipe.AddIngredient(Ingredient{
-			ID:       uuid.New(),
-			Name:     "Test Ingredient",
-			Quantity: 1.0,
-			Unit:     "cup",
+			ID:     uuid.New(),
+			Name:   "Test Ingredient",
+			Amount: 1.0,
+			Unit:   "cup",
 		})
 		// Don't add instructions
 
@@ -310,7 +321,7 @@ func (suite *RecipeTestSuite) TestRecipePublishing() {
 
 	suite.Run("PublishAlreadyPublishedRecipe_ShouldReturnError", func() {
 		// Arrange
-		recipe, _ := suite.recipeFactory.CreateValidRecipe()
+		recipe, _ := newValidRecipe()
 		recipe.Publish() // Publish first time
 
 		// Act
@@ -326,7 +337,7 @@ func (suite *RecipeTestSuite) TestRecipePublishing() {
 func (suite *RecipeTestSuite) TestRecipeArchiving() {
 	suite.Run("ArchivePublishedRecipe_ShouldArchive", func() {
 		// Arrange
-		recipe, _ := suite.recipeFactory.CreateValidRecipe()
+		recipe, _ := newValidRecipe()
 		recipe.Publish()
 		recipe.Events() // Clear events
 
@@ -336,11 +347,11 @@ func (suite *RecipeTestSuite) TestRecipeArchiving() {
 		// Assert
 		require.NoError(suite.T(), err)
 		assert.Equal(suite.T(), RecipeStatusArchived, recipe.status)
-		
+
 		// Check domain events
 		events := recipe.Events()
 		assert.Len(suite.T(), events, 1)
-		
+
 		archivedEvent, ok := events[0].(RecipeArchivedEvent)
 		assert.True(suite.T(), ok, "Should emit RecipeArchivedEvent")
 		assert.Equal(suite.T(), recipe.ID(), archivedEvent.RecipeID)
@@ -358,6 +369,26 @@ func (suite *RecipeTestSuite) TestRecipeArchiving() {
 		assert.Equal(suite.T(), ErrInvalidStatusTransition, err)
 		assert.Equal(suite.T(), RecipeStatusDraft, recipe.status)
 	})
+
+	suite.Run("UnarchiveArchivedRecipe_ShouldRepublish", func() {
+		// Arrange
+		recipe, _ := newValidRecipe()
+		recipe.Publish()
+		recipe.Archive()
+		recipe.Events() // Clear events
+
+		// Act
+		err := recipe.Unarchive()
+
+		// Assert
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), RecipeStatusPublished, recipe.status)
+
+		events := recipe.Events()
+		assert.Len(suite.T(), events, 1)
+		_, ok := events[0].(RecipeUnarchivedEvent)
+		assert.True(suite.T(), ok, "Should emit RecipeUnarchivedEvent")
+	})
 }
 
 // TestRecipeSocialFeatures tests social features like likes and ratings
@@ -374,11 +405,11 @@ func (suite *RecipeTestSuite) TestRecipeSocialFeatures() {
 
 		// Assert
 		assert.Equal(suite.T(), originalLikes+1, recipe.likes)
-		
+
 		// Check domain events
 		events := recipe.Events()
 		assert.Len(suite.T(), events, 1)
-		
+
 		likedEvent, ok := events[0].(RecipeLikedEvent)
 		assert.True(suite.T(), ok, "Should emit RecipeLikedEvent")
 		assert.Equal(suite.T(), recipe.ID(), likedEvent.RecipeID)
@@ -389,7 +420,6 @@ func (suite *RecipeTestSuite) TestRecipeSocialFeatures() {
 		// Arrange
 		recipe, _ := NewRecipe("Test Recipe", "Description", uuid.New())
 		rating := Rating{
-			ID:     uuid.New(),
 			UserID: uuid.New(),
 			Value:  5,
 		}
@@ -401,11 +431,11 @@ func (suite *RecipeTestSuite) TestRecipeSocialFeatures() {
 		// Assert
 		require.NoError(suite.T(), err)
 		assert.Equal(suite.T(), 5.0, recipe.averageRating)
-		
+
 		// Check domain events
 		events := recipe.Events()
 		assert.Len(suite.T(), events, 1)
-		
+
 		ratedEvent, ok := events[0].(RecipeRatedEvent)
 		assert.True(suite.T(), ok, "Should emit RecipeRatedEvent")
 		assert.Equal(suite.T(), recipe.ID(), ratedEvent.RecipeID)
@@ -416,11 +446,11 @@ func (suite *RecipeTestSuite) TestRecipeSocialFeatures() {
 	suite.Run("AddMultipleRatings_ShouldCalculateCorrectAverage", func() {
 		// Arrange
 		recipe, _ := NewRecipe("Test Recipe", "Description", uuid.New())
-		
+
 		// Act - Add ratings of 3, 4, 5
-		recipe.AddRating(Rating{ID: uuid.New(), UserID: uuid.New(), Value: 3})
-		recipe.AddRating(Rating{ID: uuid.New(), UserID: uuid.New(), Value: 4})
-		recipe.AddRating(Rating{ID: uuid.New(), UserID: uuid.New(), Value: 5})
+		recipe.AddRating(Rating{UserID: uuid.New(), Value: 3})
+		recipe.AddRating(Rating{UserID: uuid.New(), Value: 4})
+		recipe.AddRating(Rating{UserID: uuid.New(), Value: 5})
 
 		// Assert
 		expectedAverage := (3.0 + 4.0 + 5.0) / 3.0
@@ -431,7 +461,6 @@ func (suite *RecipeTestSuite) TestRecipeSocialFeatures() {
 		// Arrange
 		recipe, _ := NewRecipe("Test Recipe", "Description", uuid.New())
 		rating := Rating{
-			ID:     uuid.New(),
 			UserID: uuid.New(),
 			Value:  0, // Invalid - should be 1-5
 		}
@@ -469,16 +498,16 @@ func (suite *RecipeTestSuite) TestRecipeEvents() {
 		// Act
 		recipe.Like(userID)
 		recipe.UpdateTitle("New Title")
-		
+
 		events := recipe.Events()
 
 		// Assert
 		assert.Len(suite.T(), events, 2)
-		
+
 		// Verify event types
 		likedEvent, ok1 := events[0].(RecipeLikedEvent)
 		titleEvent, ok2 := events[1].(RecipeTitleUpdatedEvent)
-		
+
 		assert.True(suite.T(), ok1, "First event should be RecipeLikedEvent")
 		assert.True(suite.T(), ok2, "Second event should be RecipeTitleUpdatedEvent")
 		assert.Equal(suite.T(), userID, likedEvent.UserID)
@@ -490,7 +519,7 @@ func (suite *RecipeTestSuite) TestRecipeEvents() {
 func (suite *RecipeTestSuite) TestRecipeValidation() {
 	suite.Run("ValidateForPublishing_ValidRecipe_ShouldPass", func() {
 		// Arrange
-		recipe, _ := suite.recipeFactory.CreateValidRecipe()
+		recipe, _ := newValidRecipe()
 
 		// Act
 		err := recipe.validateForPublishing()
@@ -504,7 +533,6 @@ func (suite *RecipeTestSuite) TestRecipeValidation() {
 		recipe, _ := NewRecipe("Test Recipe", "Description", uuid.New())
 		// Add instructions but no ingredients
 		recipe.AddInstruction(Instruction{
-			ID:          uuid.New(),
 			Description: "Test instruction",
 			Duration:    5 * time.Minute,
 		})
@@ -522,10 +550,10 @@ func (suite *RecipeTestSuite) TestRecipeValidation() {
 		recipe, _ := NewRecipe("Test Recipe", "Description", uuid.New())
 		// Add ingredients but no instructions
 		recipe.AddIngredient(Ingredient{
-			ID:       uuid.New(),
-			Name:     "Test Ingredient",
-			Quantity: 1.0,
-			Unit:     "cup",
+			ID:     uuid.New(),
+			Name:   "Test Ingredient",
+			Amount: 1.0,
+			Unit:   "cup",
 		})
 
 		// Act
@@ -555,12 +583,11 @@ func BenchmarkRecipeCreation(b *testing.B) {
 
 // BenchmarkRecipeAddIngredient benchmarks adding ingredients
 func BenchmarkRecipeAddIngredient(b *testing.B) {
-	recipe, _ := NewRecipe("Benchmark Recipe", "Description", uuid.New())
 	ingredient := Ingredient{
-		ID:       uuid.New(),
-		Name:     "Test Ingredient",
-		Quantity: 1.0,
-		Unit:     "cup",
+		ID:     uuid.New(),
+		Name:   "Test Ingredient",
+		Amount: 1.0,
+		Unit:   "cup",
 	}
 
 	b.ResetTimer()
@@ -576,11 +603,9 @@ func BenchmarkRecipeAddIngredient(b *testing.B) {
 
 // BenchmarkRecipePublish benchmarks recipe publishing
 func BenchmarkRecipePublish(b *testing.B) {
-	factory := testutils.NewRecipeFactory(time.Now().UnixNano())
-
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		recipe, _ := factory.CreateValidRecipe()
+		recipe, _ := newValidRecipe()
 		err := recipe.Publish()
 		if err != nil {
 			b.Fatal(err)
@@ -591,4 +616,4 @@ func BenchmarkRecipePublish(b *testing.B) {
 // TestRecipeTestSuite runs the recipe test suite
 func TestRecipeTestSuite(t *testing.T) {
 	suite.Run(t, new(RecipeTestSuite))
-}
\ No newline at end of file
+}
@@ -12,12 +12,18 @@ var (
 	ErrInvalidServings     = errors.New("servings must be greater than 0")
 	ErrNoIngredients       = errors.New("recipe must have at least one ingredient")
 	ErrNoInstructions      = errors.New("recipe must have at least one instruction")
+	ErrInvalidImageURL     = errors.New("image URL must be an absolute http or https URL")
+	ErrInvalidLanguage     = errors.New("recipe language must be a language tag like en or pt-BR")
+	ErrInvalidDifficulty   = errors.New("difficulty must be easy, medium, hard or expert")
 	
 	// State transition errors
 	ErrInvalidStatusTransition = errors.New("invalid recipe status transition")
 	ErrRecipeNotFound         = errors.New("recipe not found")
 	ErrRecipeAlreadyPublished = errors.New("recipe is already published")
 	ErrRecipeArchived         = errors.New("cannot modify archived recipe")
+	ErrSchedulePublishInPast  = errors.New("scheduled publish time must be in the future")
+	ErrPublishNotScheduled    = errors.New("recipe has no scheduled publish time")
+	ErrForkUnpublished        = errors.New("only published recipes can be forked")
 	
 	// Business rule violations
 	ErrDuplicateIngredient    = errors.New("ingredient already exists in recipe")
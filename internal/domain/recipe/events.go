@@ -54,6 +54,21 @@ func (e RecipePublishedEvent) OccurredAt() time.Time {
 	return e.PublishedAt
 }
 
+// RecipePublishScheduledEvent is raised when a draft is scheduled to publish
+type RecipePublishScheduledEvent struct {
+	RecipeID    uuid.UUID
+	PublishAt   time.Time
+	ScheduledAt time.Time
+}
+
+func (e RecipePublishScheduledEvent) EventName() string {
+	return "recipe.publish.scheduled"
+}
+
+func (e RecipePublishScheduledEvent) OccurredAt() time.Time {
+	return e.ScheduledAt
+}
+
 // RecipeArchivedEvent is raised when a recipe is archived
 type RecipeArchivedEvent struct {
 	RecipeID   uuid.UUID
@@ -68,6 +83,20 @@ func (e RecipeArchivedEvent) OccurredAt() time.Time {
 	return e.ArchivedAt
 }
 
+// RecipeUnarchivedEvent is raised when an archived recipe is published again
+type RecipeUnarchivedEvent struct {
+	RecipeID     uuid.UUID
+	UnarchivedAt time.Time
+}
+
+func (e RecipeUnarchivedEvent) EventName() string {
+	return "recipe.unarchived"
+}
+
+func (e RecipeUnarchivedEvent) OccurredAt() time.Time {
+	return e.UnarchivedAt
+}
+
 // RecipeLikedEvent is raised when a recipe is liked
 type RecipeLikedEvent struct {
 	RecipeID uuid.UUID
@@ -112,4 +141,20 @@ func (e IngredientAddedEvent) EventName() string {
 
 func (e IngredientAddedEvent) OccurredAt() time.Time {
 	return e.AddedAt
-}
\ No newline at end of file
+}
+
+// RecipeForkedEvent is raised when a user forks a recipe into a draft
+type RecipeForkedEvent struct {
+	RecipeID     uuid.UUID
+	ForkedFromID uuid.UUID
+	AuthorID     uuid.UUID
+	ForkedAt     time.Time
+}
+
+func (e RecipeForkedEvent) EventName() string {
+	return "recipe.forked"
+}
+
+func (e RecipeForkedEvent) OccurredAt() time.Time {
+	return e.ForkedAt
+}
@@ -0,0 +1,281 @@
+// Package foodsafety checks recipe steps against food safety rules for the
+// raw ingredients a recipe uses: the internal temperature each must reach
+// and how long it may spend in the danger zone between fridge and cooking
+// temperatures.
+package foodsafety
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/instructions"
+)
+
+// Rule names a food safety rule
+type Rule string
+
+const (
+	// RuleInternalTemperature flags a step cooking to less than the safe
+	// internal temperature
+	RuleInternalTemperature Rule = "internal-temperature"
+	// RuleMissingTemperature flags poultry and ground meat recipes that
+	// never say how hot the meat must get
+	RuleMissingTemperature Rule = "missing-temperature"
+	// RuleDangerZone flags raw food left out for longer than MaxDangerZone
+	RuleDangerZone Rule = "danger-zone"
+)
+
+// MaxDangerZone is how long raw food may spend between 4°C and 60°C
+// (40°F and 140°F) in total
+const MaxDangerZone = 2 * time.Hour
+
+// Food is a kind of raw ingredient with its own safe internal temperature
+type Food struct {
+	Name string
+	// MinInternalC is the safe internal temperature in Celsius
+	MinInternalC float64
+	// NeedsThermometer foods are unsafe when merely looking done, so
+	// recipes must give a temperature
+	NeedsThermometer bool
+	keywords         *regexp.Regexp
+}
+
+// Foods are matched against ingredient names in order, so ground poultry is
+// poultry and minced beef is ground meat rather than beef
+var Foods = []Food{
+	{Name: "poultry", MinInternalC: 74, NeedsThermometer: true, keywords: words(`chicken|turkey|duck|goose|hen|quail|poultry`)},
+	{Name: "ground meat", MinInternalC: 71, NeedsThermometer: true, keywords: words(`ground (?:beef|pork|lamb|veal|meat)|minced? (?:beef|pork|lamb|veal|meat)|mince|(?:ham)?burger|sausage`)},
+	{Name: "pork", MinInternalC: 63, keywords: words(`pork|ham`)},
+	{Name: "beef, lamb and veal", MinInternalC: 63, keywords: words(`beef|steak|brisket|lamb|veal|venison`)},
+	{Name: "fish and seafood", MinInternalC: 63, keywords: words(`fish|salmon|tuna|cod|halibut|tilapia|trout|haddock|shrimp|prawn|scallop|lobster|crab`)},
+	{Name: "eggs", MinInternalC: 71, keywords: words(`eggs?`)},
+}
+
+// notRaw ingredients contain a food's name but are cured, cooked or only
+// flavoured with it
+var notRaw = words(`stock|broth|bouillon|sauce|powder|paste|noodles?|pasta|buns?|cured|smoked|prosciutto|salami|pancetta|bacon|jerky|substitute|replacer|plant-based|vegan`)
+
+func words(alternatives string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\b(?:` + alternatives + `)s?\b`)
+}
+
+var (
+	// 74°C, 165 °F, 165F, 63 degrees C
+	temperatureRe = regexp.MustCompile(`(?i)\b(\d{2,3})(?:\s*(?:°|º|degrees?)\s*|)([CF])\b`)
+	// A temperature in a step with these words is the food's, not the oven's
+	internalRe  = regexp.MustCompile(`(?i)\b(internal|inside|thermometer|registers?|reach(?:es)?|cent(?:er|re)|core|thickest)\b`)
+	roomRe      = regexp.MustCompile(`(?i)\b(room temp(?:erature)?|on the (?:counter|bench)|countertop|unrefrigerated|(?:leave|left|sit|stand) out)\b`)
+	soakRe      = regexp.MustCompile(`(?i)\b(marinat\w*|brin(?:e|ing)|thaw\w*|defrost\w*|soak\w*)\b`)
+	coldRe      = regexp.MustCompile(`(?i)\b(fridge|refrigerat\w*|chill\w*|cold water|ice|cooler)\b`)
+	overnightRe = regexp.MustCompile(`(?i)\bovernight\b`)
+)
+
+// Step is one recipe step as the check reads it
+type Step struct {
+	Number int
+	Text   string
+	// Duration is read from the text when zero
+	Duration time.Duration
+}
+
+// Issue is one broken food safety rule. Step is 0 for the whole recipe.
+type Issue struct {
+	Step       int
+	Rule       Rule
+	Food       string
+	Ingredient string
+	Message    string
+}
+
+// Check finds the raw foods among the ingredients and checks the steps
+// against their rules. Temperatures of 40–95°C in a step mentioning the
+// inside of the food ("until it registers 74°C") are internal; others are
+// the oven's or pan's.
+func Check(ingredients []string, steps []Step) []Issue {
+	found := rawFoods(ingredients)
+	if len(found) == 0 {
+		return nil
+	}
+
+	var issues []Issue
+	safe := make(map[string]bool)
+	for _, step := range steps {
+		if !internalRe.MatchString(step.Text) {
+			continue
+		}
+		for _, celsius := range temperatures(step.Text) {
+			if celsius < 40 || celsius > 95 {
+				continue
+			}
+			for _, f := range foodsIn(step.Text, found) {
+				// Rounded, as 165°F is 73.9°C
+				if math.Round(celsius) >= f.food.MinInternalC {
+					safe[f.food.Name] = true
+					continue
+				}
+				issues = append(issues, Issue{
+					Step:       step.Number,
+					Rule:       RuleInternalTemperature,
+					Food:       f.food.Name,
+					Ingredient: f.ingredient,
+					Message: fmt.Sprintf("Step %d cooks the %s to %s, but %s is only safe at %s or above.",
+						step.Number, f.ingredient, formatC(celsius), f.food.Name, formatC(f.food.MinInternalC)),
+				})
+			}
+		}
+	}
+
+	for _, f := range found {
+		if f.food.NeedsThermometer && !safe[f.food.Name] && !hasIssue(issues, f.food.Name) {
+			issues = append(issues, Issue{
+				Rule:       RuleMissingTemperature,
+				Food:       f.food.Name,
+				Ingredient: f.ingredient,
+				Message: fmt.Sprintf("No step says how hot the %s must get. Add a doneness check: %s in the thickest part.",
+					f.ingredient, formatC(f.food.MinInternalC)),
+			})
+		}
+	}
+
+	var out time.Duration
+	for _, step := range steps {
+		food, ok := leftOut(step.Text, found)
+		if !ok {
+			continue
+		}
+		duration := step.Duration
+		if duration <= 0 {
+			if parsed := instructions.Split([]string{step.Text}); len(parsed) > 0 {
+				duration = parsed[0].Duration
+			}
+		}
+		if duration <= 0 && overnightRe.MatchString(step.Text) {
+			duration = 8 * time.Hour
+		}
+		before := out
+		out += duration
+		if before <= MaxDangerZone && out > MaxDangerZone {
+			issues = append(issues, Issue{
+				Step:       step.Number,
+				Rule:       RuleDangerZone,
+				Food:       food.food.Name,
+				Ingredient: food.ingredient,
+				Message: fmt.Sprintf("By the end of step %d the %s has been out of the fridge for %s; raw food should spend no more than %s between 4°C and 60°C (40°F and 140°F). Do this in the fridge.",
+					step.Number, food.ingredient, formatDuration(out), formatDuration(MaxDangerZone)),
+			})
+		}
+	}
+
+	return issues
+}
+
+type foundFood struct {
+	food       Food
+	ingredient string
+}
+
+// rawFoods matches ingredient names to foods, first ingredient per food
+func rawFoods(ingredients []string) []foundFood {
+	var found []foundFood
+	seen := make(map[string]bool)
+	for _, name := range ingredients {
+		if notRaw.MatchString(name) {
+			continue
+		}
+		for _, food := range Foods {
+			match := food.keywords.FindString(name)
+			if match == "" {
+				continue
+			}
+			if !seen[food.Name] {
+				seen[food.Name] = true
+				found = append(found, foundFood{food: food, ingredient: strings.ToLower(match)})
+			}
+			break
+		}
+	}
+	return found
+}
+
+// leftOut reports whether a step keeps raw food out of the fridge, and
+// which. Room temperature steps hold every raw food the recipe uses;
+// soaking and thawing only the foods they name, and marinating the first.
+func leftOut(text string, found []foundFood) (foundFood, bool) {
+	if coldRe.MatchString(text) {
+		return foundFood{}, false
+	}
+	room, soak := roomRe.MatchString(text), soakRe.FindString(text)
+	if !room && soak == "" {
+		return foundFood{}, false
+	}
+	for _, f := range found {
+		if f.food.keywords.MatchString(text) {
+			return f, true
+		}
+	}
+	if room || strings.HasPrefix(strings.ToLower(soak), "marinat") {
+		return found[0], true
+	}
+	return foundFood{}, false
+}
+
+// foodsIn are the found foods a step names, or the only one when it names
+// none
+func foodsIn(text string, found []foundFood) []foundFood {
+	var named []foundFood
+	for _, f := range found {
+		if f.food.keywords.MatchString(text) {
+			named = append(named, f)
+		}
+	}
+	if len(named) == 0 && len(found) == 1 {
+		return found
+	}
+	return named
+}
+
+func hasIssue(issues []Issue, food string) bool {
+	for _, issue := range issues {
+		if issue.Food == food {
+			return true
+		}
+	}
+	return false
+}
+
+// temperatures are every temperature in text, in Celsius
+func temperatures(text string) []float64 {
+	var values []float64
+	for _, m := range temperatureRe.FindAllStringSubmatch(text, -1) {
+		value, _ := strconv.ParseFloat(m[1], 64)
+		t := recipe.Temperature{Value: value, Unit: recipe.TemperatureUnitCelsius}
+		if strings.EqualFold(m[2], "F") {
+			t.Unit = recipe.TemperatureUnitFahrenheit
+		}
+		values = append(values, t.ToCelsius())
+	}
+	return values
+}
+
+func formatC(celsius float64) string {
+	return fmt.Sprintf("%.0f°C (%.0f°F)", math.Round(celsius), math.Round(celsius*9/5+32))
+}
+
+func formatDuration(d time.Duration) string {
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d minutes", minutes)
+	case minutes == 0 && hours == 1:
+		return "1 hour"
+	case minutes == 0:
+		return fmt.Sprintf("%d hours", hours)
+	default:
+		return fmt.Sprintf("%d h %d min", hours, minutes)
+	}
+}
@@ -0,0 +1,49 @@
+package foodsafety
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckInternalTemperatures(t *testing.T) {
+	issues := Check([]string{"chicken thighs", "pork chops", "chicken stock"}, []Step{
+		{Number: 1, Text: "Roast the chicken at 200°C until a thermometer in the thickest part reads 165°F."},
+		{Number: 2, Text: "Sear the pork chops until the center reaches 55°C."},
+	})
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, 2, issues[0].Step)
+	assert.Equal(t, RuleInternalTemperature, issues[0].Rule)
+	assert.Equal(t, "pork", issues[0].Food)
+	assert.Contains(t, issues[0].Message, "63°C (145°F)")
+}
+
+func TestCheckMissingTemperature(t *testing.T) {
+	issues := Check([]string{"ground beef", "burger buns"}, []Step{
+		{Number: 1, Text: "Shape into patties and grill until browned."},
+	})
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, RuleMissingTemperature, issues[0].Rule)
+	assert.Zero(t, issues[0].Step)
+	assert.Equal(t, "ground meat", issues[0].Food)
+
+	assert.Empty(t, Check([]string{"spaghetti", "fish sauce"}, []Step{{Number: 1, Text: "Boil the pasta."}}), "flavourings are not raw food")
+}
+
+func TestCheckDangerZone(t *testing.T) {
+	issues := Check([]string{"beef brisket", "dried beans"}, []Step{
+		{Number: 1, Text: "Soak the beans overnight."},
+		{Number: 2, Text: "Let the brisket come to room temperature.", Duration: time.Hour},
+		{Number: 3, Text: "Marinate the brisket for 90 minutes."},
+		{Number: 4, Text: "Marinate overnight in the fridge."},
+	})
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, RuleDangerZone, issues[0].Rule)
+	assert.Equal(t, 3, issues[0].Step, "the beans are not raw meat and the fridge is safe")
+	assert.Contains(t, issues[0].Message, "2 h 30 min")
+}
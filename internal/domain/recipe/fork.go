@@ -0,0 +1,81 @@
+package recipe
+
+import (
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/shared"
+	"github.com/google/uuid"
+)
+
+// Fork copies a published recipe into a new draft owned by authorID, linked
+// back to the original. The copy starts with no likes, views or ratings.
+func (r *Recipe) Fork(authorID uuid.UUID) (*Recipe, error) {
+	if r.status != RecipeStatusPublished {
+		return nil, ErrForkUnpublished
+	}
+
+	now := time.Now()
+	origin := r.id
+	fork := &Recipe{
+		id:           uuid.New(),
+		version:      1,
+		title:        r.title,
+		description:  r.description,
+		authorID:     authorID,
+		language:     r.language,
+		ingredients:  make([]Ingredient, len(r.ingredients)),
+		instructions: make([]Instruction, len(r.instructions)),
+		allergens:    r.allergens,
+		cuisine:      r.cuisine,
+		category:     r.category,
+		difficulty:   r.difficulty,
+		tags:         append([]string(nil), r.tags...),
+		prepTime:     r.prepTime,
+		cookTime:     r.cookTime,
+		totalTime:    r.totalTime,
+		servings:     r.servings,
+		calories:     r.calories,
+		images:       make([]Image, len(r.images)),
+		status:       RecipeStatusDraft,
+		forkedFromID: &origin,
+		createdAt:    now,
+		updatedAt:    now,
+		events:       []shared.DomainEvent{},
+	}
+
+	for i, ingredient := range r.ingredients {
+		ingredient.ID = uuid.New()
+		fork.ingredients[i] = ingredient
+	}
+	for i, instruction := range r.instructions {
+		instruction.Images = append([]string(nil), instruction.Images...)
+		if instruction.Temperature != nil {
+			temperature := *instruction.Temperature
+			instruction.Temperature = &temperature
+		}
+		fork.instructions[i] = instruction
+	}
+	for i, image := range r.images {
+		image.ID = uuid.New()
+		fork.images[i] = image
+	}
+	if r.nutritionInfo != nil {
+		info := *r.nutritionInfo
+		fork.nutritionInfo = &info
+	}
+
+	fork.addEvent(RecipeCreatedEvent{
+		RecipeID:  fork.id,
+		AuthorID:  authorID,
+		Title:     fork.title,
+		CreatedAt: now,
+	})
+	fork.addEvent(RecipeForkedEvent{
+		RecipeID:     fork.id,
+		ForkedFromID: origin,
+		AuthorID:     authorID,
+		ForkedAt:     now,
+	})
+
+	return fork, nil
+}
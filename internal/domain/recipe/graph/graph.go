@@ -0,0 +1,191 @@
+// Package graph derives the knowledge graph links of a recipe: the
+// ingredients it uses, the cooking techniques its instructions call for and
+// its cuisine. Recipes sharing these nodes are related.
+package graph
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kind is a type of node recipes link to
+type Kind string
+
+// Node kinds
+const (
+	Ingredient Kind = "ingredient"
+	Technique  Kind = "technique"
+	Cuisine    Kind = "cuisine"
+)
+
+// Valid reports whether k is a known node kind
+func (k Kind) Valid() bool {
+	switch k {
+	case Ingredient, Technique, Cuisine:
+		return true
+	}
+	return false
+}
+
+// Weight is how much sharing a node of this kind relates two recipes.
+// Techniques say more about a dish than any one ingredient does.
+func (k Kind) Weight() float64 {
+	switch k {
+	case Technique:
+		return 3
+	case Cuisine:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Node is an ingredient, technique or cuisine. Key identifies it within its
+// kind; Label is how it is shown.
+type Node struct {
+	Kind  Kind
+	Key   string
+	Label string
+}
+
+// technique is a known technique and the words that mention it
+type technique struct {
+	key     string
+	label   string
+	pattern *regexp.Regexp
+}
+
+// newTechnique matches words on letter boundaries; \b only knows ASCII and
+// would miss "sauté"
+func newTechnique(key, label string, words ...string) technique {
+	return technique{
+		key:     key,
+		label:   label,
+		pattern: regexp.MustCompile(`(?i)(?:^|[^\pL])(?:` + strings.Join(words, "|") + `)(?:$|[^\pL])`),
+	}
+}
+
+// techniques is the vocabulary instructions are matched against
+var techniques = []technique{
+	newTechnique("bake", "Baking", "bake", "baked", "baking"),
+	newTechnique("blanch", "Blanching", "blanch", "blanched", "blanching"),
+	newTechnique("braise", "Braising", "braise", "braised", "braising"),
+	newTechnique("broil", "Broiling", "broil", "broiled", "broiling"),
+	newTechnique("deep-fry", "Deep frying", "deep[- ]fry", "deep[- ]fried", "deep[- ]frying"),
+	newTechnique("deglaze", "Deglazing", "deglaze", "deglazed", "deglazing"),
+	newTechnique("ferment", "Fermenting", "ferment", "fermented", "fermenting"),
+	newTechnique("fold", "Folding", "fold in", "gently fold", "folding in"),
+	newTechnique("grill", "Grilling", "grill", "grilled", "grilling"),
+	newTechnique("knead", "Kneading", "knead", "kneaded", "kneading"),
+	newTechnique("marinate", "Marinating", "marinate", "marinated", "marinating", "marinade"),
+	newTechnique("poach", "Poaching", "poach", "poached", "poaching"),
+	newTechnique("puree", "Pureeing", "puree", "pureed", "blend until smooth"),
+	newTechnique("reduce", "Reducing", "reduce until", "reduce by", "reduced by half", "until reduced", "simmer until thickened"),
+	newTechnique("roast", "Roasting", "roast", "roasted", "roasting"),
+	newTechnique("saute", "Sautéing", "saute", "sauté", "sauteed", "sautéed", "sauteing", "sautéing"),
+	newTechnique("sear", "Searing", "sear", "seared", "searing"),
+	newTechnique("simmer", "Simmering", "simmer", "simmered", "simmering"),
+	newTechnique("sous-vide", "Sous vide", "sous[- ]vide"),
+	newTechnique("steam", "Steaming", "steam", "steamed", "steaming"),
+	newTechnique("stir-fry", "Stir frying", "stir[- ]fry", "stir[- ]fried", "stir[- ]frying"),
+	newTechnique("temper", "Tempering", "temper", "tempered", "tempering"),
+}
+
+// staples are ingredients nearly every recipe uses, which would relate
+// everything to everything
+var staples = map[string]bool{
+	"salt":          true,
+	"kosher salt":   true,
+	"sea salt":      true,
+	"pepper":        true,
+	"black pepper":  true,
+	"water":         true,
+	"ice":           true,
+	"cooking spray": true,
+}
+
+// maxKeyLength matches the recipe_graph_edges.node_key column
+const maxKeyLength = 100
+
+// Links returns the distinct nodes a recipe links to, in kind then key
+// order
+func Links(ingredientNames, instructions []string, cuisine string) []Node {
+	seen := make(map[Node]bool)
+	var nodes []Node
+	add := func(n Node) {
+		key := Node{Kind: n.Kind, Key: n.Key}
+		if n.Key == "" || len(n.Key) > maxKeyLength || seen[key] {
+			return
+		}
+		seen[key] = true
+		nodes = append(nodes, n)
+	}
+
+	for _, name := range ingredientNames {
+		if key := IngredientKey(name); key != "" && !staples[key] {
+			add(Node{Kind: Ingredient, Key: key, Label: strings.TrimSpace(name)})
+		}
+	}
+	for _, n := range Techniques(strings.Join(instructions, "\n")) {
+		add(n)
+	}
+	if key := strings.ToLower(strings.TrimSpace(cuisine)); key != "" {
+		add(Node{Kind: Cuisine, Key: key, Label: strings.ToUpper(key[:1]) + key[1:]})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Kind != nodes[j].Kind {
+			return nodes[i].Kind < nodes[j].Kind
+		}
+		return nodes[i].Key < nodes[j].Key
+	})
+	return nodes
+}
+
+// Techniques returns the techniques text mentions
+func Techniques(text string) []Node {
+	var nodes []Node
+	for _, t := range techniques {
+		if t.pattern.MatchString(text) {
+			nodes = append(nodes, Node{Kind: Technique, Key: t.key, Label: t.label})
+		}
+	}
+	return nodes
+}
+
+// TechniqueLabel returns the label of a known technique key
+func TechniqueLabel(key string) (string, bool) {
+	for _, t := range techniques {
+		if t.key == key {
+			return t.label, true
+		}
+	}
+	return "", false
+}
+
+var nonWord = regexp.MustCompile(`[^\pL\pN]+`)
+
+// IngredientKey normalizes an ingredient name so "Yellow Onions" and
+// "yellow onion" are one node
+func IngredientKey(name string) string {
+	words := strings.Fields(nonWord.ReplaceAllString(strings.ToLower(name), " "))
+	if len(words) == 0 {
+		return ""
+	}
+	words[len(words)-1] = singular(words[len(words)-1])
+	return strings.Join(words, " ")
+}
+
+// singular strips common English plural endings
+func singular(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 4 && strings.HasSuffix(word, "oes"):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us"):
+		return word[:len(word)-1]
+	}
+	return word
+}
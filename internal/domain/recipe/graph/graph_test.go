@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinks(t *testing.T) {
+	nodes := Links(
+		[]string{"Yellow Onions", "yellow onion", "Kosher salt", "Chicken thighs", "Tomatoes", ""},
+		[]string{
+			"Sear the chicken thighs until browned, then set aside.",
+			"Sauté the onions, deglaze with wine and return the chicken.",
+			"Cover and braise for 40 minutes at room temperature... then reduce heat.",
+		},
+		"Italian",
+	)
+
+	assert.Equal(t, []Node{
+		{Kind: Cuisine, Key: "italian", Label: "Italian"},
+		{Kind: Ingredient, Key: "chicken thigh", Label: "Chicken thighs"},
+		{Kind: Ingredient, Key: "tomato", Label: "Tomatoes"},
+		{Kind: Ingredient, Key: "yellow onion", Label: "Yellow Onions"},
+		{Kind: Technique, Key: "braise", Label: "Braising"},
+		{Kind: Technique, Key: "deglaze", Label: "Deglazing"},
+		{Kind: Technique, Key: "saute", Label: "Sautéing"},
+		{Kind: Technique, Key: "sear", Label: "Searing"},
+	}, nodes)
+}
+
+func TestIngredientKey(t *testing.T) {
+	for name, key := range map[string]string{
+		"Cherries":           "cherry",
+		"  Swiss  Chard ":    "swiss chard",
+		"hummus":             "hummus",
+		"molasses":           "molasse",
+		"Potatoes":           "potato",
+		"extra-virgin oil":   "extra virgin oil",
+		"Sun-dried tomatoes": "sun dried tomato",
+		"Jalapeños":          "jalapeño",
+	} {
+		assert.Equal(t, key, IngredientKey(name), name)
+	}
+}
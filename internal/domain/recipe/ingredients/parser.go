@@ -0,0 +1,565 @@
+// Package ingredients parses free-text ingredient lines such as
+// "2 1/2 cups finely chopped yellow onion (about 1 large)" into a structured
+// amount, unit, name and notes. It is shared by recipe import, the editor's
+// paste mode and AI output normalization so all three agree on the result.
+package ingredients
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/google/uuid"
+)
+
+// Parse errors
+var (
+	ErrEmptyLine     = errors.New("ingredient line is empty")
+	ErrSectionHeader = errors.New("line is a section header, not an ingredient")
+	ErrMissingName   = errors.New("ingredient line has no ingredient name")
+)
+
+// Parsed is a structured ingredient line
+type Parsed struct {
+	Amount float64
+	// AmountMax is the upper bound of a range such as "2-3", otherwise 0
+	AmountMax float64
+	Unit      recipe.MeasurementUnit
+	Name      string
+	Notes     string
+	Optional  bool
+}
+
+// Ingredient converts the parsed line into a domain ingredient. Ranges keep
+// the lower bound as the amount and are spelled out in the notes.
+func (p Parsed) Ingredient() recipe.Ingredient {
+	notes := p.Notes
+	if p.AmountMax > p.Amount {
+		notes = joinNotes(FormatAmount(p.Amount)+" to "+FormatAmount(p.AmountMax), notes)
+	}
+	return recipe.Ingredient{
+		ID:       uuid.New(),
+		Name:     p.Name,
+		Amount:   p.Amount,
+		Unit:     p.Unit,
+		Optional: p.Optional,
+		Notes:    notes,
+	}
+}
+
+// String renders the canonical form, e.g. "2 1/2 cup yellow onion (finely chopped)"
+func (p Parsed) String() string {
+	var parts []string
+	if p.Amount > 0 {
+		amount := FormatAmount(p.Amount)
+		if p.AmountMax > p.Amount {
+			amount += "-" + FormatAmount(p.AmountMax)
+		}
+		parts = append(parts, amount)
+	}
+	if p.Unit != "" {
+		parts = append(parts, string(p.Unit))
+	}
+	parts = append(parts, p.Name)
+	notes := p.Notes
+	if p.Optional {
+		notes = joinNotes(notes, "optional")
+	}
+	if notes != "" {
+		parts = append(parts, "("+notes+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Parse converts one free-text ingredient line. Lines ending in a colon
+// ("For the sauce:") are reported as ErrSectionHeader so callers can skip them.
+func Parse(line string) (Parsed, error) {
+	var p Parsed
+
+	text := normalize(line)
+	if text == "" {
+		return p, ErrEmptyLine
+	}
+	if strings.HasSuffix(text, ":") {
+		return p, ErrSectionHeader
+	}
+
+	text, parenNotes := extractParentheticals(text)
+	tokens := splitAttachedUnits(strings.Fields(text))
+
+	amount, amountMax, i := parseQuantity(tokens)
+	p.Amount, p.AmountMax = amount, amountMax
+
+	// Without an amount only "pinch of" and "dash of" read as units, so
+	// "Cloves, whole" stays an ingredient name
+	var notes []string
+	if unit, n, qualifier := matchUnit(tokens[i:]); n > 0 && (i > 0 || unit == recipe.MeasurementUnitPinch || unit == recipe.MeasurementUnitDash) {
+		p.Unit = unit
+		i += n
+		if qualifier != "" {
+			notes = append(notes, qualifier)
+		}
+	}
+	// "2 cups of flour", "a pinch of salt"
+	if i > 0 && i < len(tokens) && strings.EqualFold(tokens[i], "of") {
+		i++
+	}
+
+	name, rest := splitName(strings.Join(tokens[i:], " "))
+	notes = append(notes, rest...)
+	notes = append(notes, parenNotes...)
+
+	var kept []string
+	for _, note := range notes {
+		if isOptionalNote(note) {
+			p.Optional = true
+			continue
+		}
+		kept = append(kept, note)
+	}
+
+	p.Name = name
+	p.Notes = strings.Join(kept, ", ")
+	// OCR noise such as "~~%#" has no letters to name anything
+	if strings.IndexFunc(p.Name, unicode.IsLetter) < 0 {
+		return p, ErrMissingName
+	}
+	return p, nil
+}
+
+// ParseQuantity reads an amount with an optional unit and nothing else,
+// such as "1 1/2 cups", "½ Tablespoon" or "a pinch". Ranges keep their
+// lower bound. It reports false for text that is not a quantity, such as
+// "to taste" or "1 handful".
+func ParseQuantity(text string) (float64, recipe.MeasurementUnit, bool) {
+	tokens := splitAttachedUnits(strings.Fields(normalize(text)))
+	amount, _, i := parseQuantity(tokens)
+	if i == 0 {
+		if unit, n, _ := matchUnit(tokens); n > 0 && n == len(tokens) && (unit == recipe.MeasurementUnitPinch || unit == recipe.MeasurementUnitDash) {
+			return 1, unit, true
+		}
+		return 0, "", false
+	}
+	var unit recipe.MeasurementUnit
+	if u, n, _ := matchUnit(tokens[i:]); n > 0 {
+		unit = u
+		i += n
+	}
+	if i != len(tokens) {
+		return 0, "", false
+	}
+	return amount, unit, true
+}
+
+// ParseUnit maps a unit spelling ("Tablespoons", "lbs.", "fl oz") to its
+// canonical measurement unit
+func ParseUnit(s string) (recipe.MeasurementUnit, bool) {
+	tokens := strings.Fields(s)
+	unit, n, _ := matchUnit(tokens)
+	if n == 0 || n != len(tokens) {
+		return "", false
+	}
+	return unit, true
+}
+
+// FormatAmount renders an amount with kitchen fractions: 2.5 → "2 1/2"
+func FormatAmount(v float64) string {
+	whole := math.Floor(v)
+	frac := v - whole
+	if frac < 0.01 {
+		return strconv.FormatFloat(whole, 'f', -1, 64)
+	}
+	for _, f := range commonFractions {
+		if math.Abs(frac-f.value) < 0.01 {
+			if whole == 0 {
+				return f.text
+			}
+			return strconv.FormatFloat(whole, 'f', -1, 64) + " " + f.text
+		}
+	}
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+var commonFractions = []struct {
+	value float64
+	text  string
+}{
+	{1.0 / 8, "1/8"}, {1.0 / 4, "1/4"}, {1.0 / 3, "1/3"}, {3.0 / 8, "3/8"},
+	{1.0 / 2, "1/2"}, {5.0 / 8, "5/8"}, {2.0 / 3, "2/3"}, {3.0 / 4, "3/4"},
+	{7.0 / 8, "7/8"},
+}
+
+var vulgarFractions = map[rune]string{
+	'½': "1/2", '⅓': "1/3", '⅔': "2/3", '¼': "1/4", '¾': "3/4",
+	'⅕': "1/5", '⅖': "2/5", '⅗': "3/5", '⅘': "4/5", '⅙': "1/6",
+	'⅚': "5/6", '⅛': "1/8", '⅜': "3/8", '⅝': "5/8", '⅞': "7/8",
+}
+
+// normalize strips list bullets and rewrites unicode fractions and dashes
+// into plain ASCII the tokenizer understands
+func normalize(line string) string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimLeft(line, "-*•·▪◦ \t")
+
+	var b strings.Builder
+	runes := []rune(line)
+	for i, r := range runes {
+		switch {
+		case vulgarFractions[r] != "":
+			if i > 0 && unicode.IsDigit(runes[i-1]) {
+				b.WriteByte(' ')
+			}
+			b.WriteString(vulgarFractions[r])
+			if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != '-' {
+				b.WriteByte(' ')
+			}
+		case r == '⁄':
+			b.WriteByte('/')
+		case r == '–' || r == '—':
+			b.WriteByte('-')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// extractParentheticals removes "(...)" and "[...]" groups, returning them as notes
+func extractParentheticals(text string) (string, []string) {
+	var out strings.Builder
+	var notes []string
+	var note strings.Builder
+	depth := 0
+	for _, r := range text {
+		switch {
+		case r == '(' || r == '[':
+			if depth > 0 {
+				note.WriteRune(r)
+			}
+			depth++
+		case (r == ')' || r == ']') && depth > 0:
+			depth--
+			if depth == 0 {
+				if n := strings.TrimSpace(note.String()); n != "" {
+					notes = append(notes, n)
+				}
+				note.Reset()
+				out.WriteByte(' ')
+			} else {
+				note.WriteRune(r)
+			}
+		case depth > 0:
+			note.WriteRune(r)
+		default:
+			out.WriteRune(r)
+		}
+	}
+	// An unclosed group runs to the end of the line
+	if n := strings.TrimSpace(note.String()); n != "" {
+		notes = append(notes, n)
+	}
+
+	cleaned := strings.Join(strings.Fields(out.String()), " ")
+	cleaned = strings.ReplaceAll(cleaned, " ,", ",")
+	return cleaned, notes
+}
+
+var attachedUnit = regexp.MustCompile(`^(\d+(?:\.\d+)?|\d+/\d+)([a-zA-Z]+\.?)$`)
+
+// splitAttachedUnits separates "200g" into "200" "g" when the suffix is a unit
+func splitAttachedUnits(tokens []string) []string {
+	out := make([]string, 0, len(tokens))
+	for _, tok := range tokens {
+		if m := attachedUnit.FindStringSubmatch(tok); m != nil {
+			if _, n, _ := matchUnit([]string{m[2]}); n == 1 {
+				out = append(out, m[1], m[2])
+				continue
+			}
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+var numberWords = map[string]float64{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11,
+	"twelve": 12, "dozen": 12, "half": 0.5,
+}
+
+// vagueAmounts follow "a" without making it a count: "a few sprigs"
+var vagueAmounts = map[string]bool{"few": true, "couple": true, "little": true, "bit": true}
+
+// parseQuantity reads a leading amount or range and returns how many tokens it used
+func parseQuantity(tokens []string) (amount, amountMax float64, used int) {
+	if len(tokens) == 0 {
+		return 0, 0, 0
+	}
+
+	if lo, hi, ok := parseHyphenated(tokens[0]); ok {
+		// "1-1/2" is a mixed number, "2-3" and "1/2-1" are ranges
+		if lo == math.Trunc(lo) && hi < 1 {
+			return lo + hi, 0, 1
+		}
+		// "1-1 1/2" (from "1–1½") completes the upper bound
+		if hi == math.Trunc(hi) && len(tokens) > 1 && strings.Contains(tokens[1], "/") {
+			if frac, ok := parseNumber(tokens[1]); ok && frac < 1 {
+				return lo, hi + frac, 2
+			}
+		}
+		return lo, hi, 1
+	}
+
+	amount, used = parseMixed(tokens)
+	if used == 0 {
+		word := strings.ToLower(tokens[0])
+		value, ok := numberWords[word]
+		if !ok || len(tokens) == 1 {
+			return 0, 0, 0
+		}
+		if (word == "a" || word == "an") && vagueAmounts[strings.ToLower(tokens[1])] {
+			return 0, 0, 0
+		}
+		return value, 0, 1
+	}
+
+	rest := tokens[used:]
+	if len(rest) >= 2 {
+		switch strings.ToLower(rest[0]) {
+		case "-", "to", "or":
+			if hi, n := parseMixed(rest[1:]); n > 0 {
+				return amount, hi, used + 1 + n
+			}
+		}
+	}
+	return amount, 0, used
+}
+
+// parseMixed reads "2", "1.5", "3/4" or "2 1/2"
+func parseMixed(tokens []string) (float64, int) {
+	if len(tokens) == 0 {
+		return 0, 0
+	}
+	whole, ok := parseNumber(tokens[0])
+	if !ok {
+		return 0, 0
+	}
+	if !strings.ContainsAny(tokens[0], "./") && len(tokens) > 1 && strings.Contains(tokens[1], "/") {
+		if frac, ok := parseNumber(tokens[1]); ok && frac < 1 {
+			return whole + frac, 2
+		}
+	}
+	return whole, 1
+}
+
+func parseHyphenated(tok string) (float64, float64, bool) {
+	parts := strings.SplitN(tok, "-", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return 0, 0, false
+	}
+	lo, ok := parseNumber(parts[0])
+	if !ok {
+		return 0, 0, false
+	}
+	hi, ok := parseNumber(parts[1])
+	if !ok {
+		return 0, 0, false
+	}
+	return lo, hi, true
+}
+
+func parseNumber(tok string) (float64, bool) {
+	if num, den, found := strings.Cut(tok, "/"); found {
+		n, err1 := strconv.Atoi(num)
+		d, err2 := strconv.Atoi(den)
+		if err1 != nil || err2 != nil || d == 0 {
+			return 0, false
+		}
+		return float64(n) / float64(d), true
+	}
+	if tok == "" || !unicode.IsDigit(rune(tok[0])) {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(tok, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+var unitAliases = map[string]recipe.MeasurementUnit{
+	"tsp": recipe.MeasurementUnitTeaspoon, "tsps": recipe.MeasurementUnitTeaspoon,
+	"teaspoon": recipe.MeasurementUnitTeaspoon, "teaspoons": recipe.MeasurementUnitTeaspoon,
+	"tbsp": recipe.MeasurementUnitTablespoon, "tbsps": recipe.MeasurementUnitTablespoon,
+	"tbs": recipe.MeasurementUnitTablespoon, "tbl": recipe.MeasurementUnitTablespoon,
+	"tablespoon": recipe.MeasurementUnitTablespoon, "tablespoons": recipe.MeasurementUnitTablespoon,
+	"cup": recipe.MeasurementUnitCup, "cups": recipe.MeasurementUnitCup,
+	"oz": recipe.MeasurementUnitOunce, "ounce": recipe.MeasurementUnitOunce, "ounces": recipe.MeasurementUnitOunce,
+	"ml": recipe.MeasurementUnitMilliliter, "mls": recipe.MeasurementUnitMilliliter,
+	"milliliter": recipe.MeasurementUnitMilliliter, "milliliters": recipe.MeasurementUnitMilliliter,
+	"millilitre": recipe.MeasurementUnitMilliliter, "millilitres": recipe.MeasurementUnitMilliliter,
+	"l": recipe.MeasurementUnitLiter, "liter": recipe.MeasurementUnitLiter, "liters": recipe.MeasurementUnitLiter,
+	"litre": recipe.MeasurementUnitLiter, "litres": recipe.MeasurementUnitLiter,
+	"pt": recipe.MeasurementUnitPint, "pint": recipe.MeasurementUnitPint, "pints": recipe.MeasurementUnitPint,
+	"qt": recipe.MeasurementUnitQuart, "quart": recipe.MeasurementUnitQuart, "quarts": recipe.MeasurementUnitQuart,
+	"gal": recipe.MeasurementUnitGallon, "gallon": recipe.MeasurementUnitGallon, "gallons": recipe.MeasurementUnitGallon,
+	"g": recipe.MeasurementUnitGram, "gr": recipe.MeasurementUnitGram, "gm": recipe.MeasurementUnitGram,
+	"gram": recipe.MeasurementUnitGram, "grams": recipe.MeasurementUnitGram,
+	"kg": recipe.MeasurementUnitKilogram, "kgs": recipe.MeasurementUnitKilogram,
+	"kilogram": recipe.MeasurementUnitKilogram, "kilograms": recipe.MeasurementUnitKilogram,
+	"kilo": recipe.MeasurementUnitKilogram, "kilos": recipe.MeasurementUnitKilogram,
+	"lb": recipe.MeasurementUnitPound, "lbs": recipe.MeasurementUnitPound,
+	"pound": recipe.MeasurementUnitPound, "pounds": recipe.MeasurementUnitPound,
+	"pinch": recipe.MeasurementUnitPinch, "pinches": recipe.MeasurementUnitPinch,
+	"dash": recipe.MeasurementUnitDash, "dashes": recipe.MeasurementUnitDash,
+	"piece": recipe.MeasurementUnitPiece, "pieces": recipe.MeasurementUnitPiece,
+	"pc": recipe.MeasurementUnitPiece, "pcs": recipe.MeasurementUnitPiece,
+	"clove": recipe.MeasurementUnitClove, "cloves": recipe.MeasurementUnitClove,
+	"can": recipe.MeasurementUnitCan, "cans": recipe.MeasurementUnitCan,
+	"tin": recipe.MeasurementUnitCan, "tins": recipe.MeasurementUnitCan,
+	"package": recipe.MeasurementUnitPackage, "packages": recipe.MeasurementUnitPackage,
+	"pkg": recipe.MeasurementUnitPackage, "pkgs": recipe.MeasurementUnitPackage,
+	"packet": recipe.MeasurementUnitPackage, "packets": recipe.MeasurementUnitPackage,
+	"slice": recipe.MeasurementUnitSlice, "slices": recipe.MeasurementUnitSlice,
+	"stick": recipe.MeasurementUnitStick, "sticks": recipe.MeasurementUnitStick,
+	"bunch": recipe.MeasurementUnitBunch, "bunches": recipe.MeasurementUnitBunch,
+	"sprig": recipe.MeasurementUnitSprig, "sprigs": recipe.MeasurementUnitSprig,
+}
+
+// unitQualifiers may precede a unit and are kept as a note: "1 heaping tbsp"
+var unitQualifiers = map[string]bool{"heaping": true, "heaped": true, "level": true, "scant": true, "generous": true, "rounded": true}
+
+// matchUnit recognises a unit at the start of tokens, returning the number of
+// tokens consumed and any qualifier that preceded it
+func matchUnit(tokens []string) (recipe.MeasurementUnit, int, string) {
+	if len(tokens) == 0 {
+		return "", 0, ""
+	}
+
+	if word := strings.ToLower(tokens[0]); unitQualifiers[word] && len(tokens) > 1 {
+		if unit, n, _ := matchUnit(tokens[1:]); n > 0 {
+			return unit, n + 1, word
+		}
+	}
+
+	first := strings.ToLower(strings.TrimSuffix(tokens[0], "."))
+	if (first == "fl" || first == "fluid") && len(tokens) > 1 {
+		switch strings.ToLower(strings.TrimSuffix(tokens[1], ".")) {
+		case "oz", "ounce", "ounces":
+			return recipe.MeasurementUnitOunce, 2, ""
+		}
+	}
+
+	// Single letters are case sensitive in recipe shorthand: T = tbsp, t = tsp
+	switch strings.TrimSuffix(tokens[0], ".") {
+	case "T", "Tb", "Tbsp", "TBSP":
+		return recipe.MeasurementUnitTablespoon, 1, ""
+	case "t":
+		return recipe.MeasurementUnitTeaspoon, 1, ""
+	case "c", "C":
+		return recipe.MeasurementUnitCup, 1, ""
+	}
+
+	if unit, ok := unitAliases[first]; ok {
+		return unit, 1, ""
+	}
+	return "", 0, ""
+}
+
+// leadingPrep words before the name describe preparation: "finely chopped onion"
+var leadingPrep = map[string]bool{
+	"chopped": true, "diced": true, "minced": true, "sliced": true, "grated": true,
+	"shredded": true, "melted": true, "softened": true, "sifted": true, "peeled": true,
+	"cubed": true, "julienned": true, "beaten": true, "whisked": true, "trimmed": true,
+	"rinsed": true, "drained": true, "halved": true, "quartered": true, "packed": true,
+	"cored": true, "seeded": true, "pitted": true, "crumbled": true, "mashed": true,
+	"thawed": true, "deveined": true, "torn": true, "finely": true, "roughly": true,
+	"coarsely": true, "thinly": true, "freshly": true, "lightly": true, "firmly": true,
+	"loosely": true,
+}
+
+// noteStarters open a comma-separated note: "butter, at room temperature"
+var noteStarters = map[string]bool{
+	"to": true, "plus": true, "for": true, "such": true, "at": true, "about": true,
+	"cut": true, "divided": true, "optional": true, "or": true, "if": true,
+	"preferably": true, "room": true, "toasted": true, "cooked": true, "zested": true,
+	"juiced": true, "separated": true, "well": true, "very": true, "as": true,
+	"from": true, "without": true, "with": true, "and": true, "into": true,
+}
+
+// splitName separates the ingredient name from preparation notes
+func splitName(text string) (string, []string) {
+	segments := strings.Split(text, ",")
+	name := strings.TrimSpace(segments[0])
+
+	// A one-word descriptor followed by more words belongs to the name:
+	// "boneless, skinless chicken thighs"
+	var notes []string
+	inNotes := false
+	for _, seg := range segments[1:] {
+		seg = strings.TrimSpace(seg)
+		if seg == "" {
+			continue
+		}
+		if !inNotes && !startsNote(seg) && wordCount(name) == 1 && wordCount(seg) >= 2 {
+			name += ", " + seg
+			continue
+		}
+		inNotes = true
+		notes = append(notes, seg)
+	}
+
+	lower := strings.ToLower(name)
+	if strings.HasSuffix(lower, " to taste") {
+		name = strings.TrimSpace(name[:len(name)-len(" to taste")])
+		notes = append([]string{"to taste"}, notes...)
+	} else if lower == "to taste" {
+		name = ""
+		notes = append([]string{"to taste"}, notes...)
+	}
+
+	words := strings.Fields(name)
+	prep := 0
+	for prep < len(words)-1 {
+		word := strings.ToLower(words[prep])
+		if leadingPrep[word] || (word == "and" && prep > 0 && leadingPrep[strings.ToLower(words[prep+1])]) {
+			prep++
+			continue
+		}
+		break
+	}
+	if prep > 0 {
+		notes = append([]string{strings.Join(words[:prep], " ")}, notes...)
+		name = strings.Join(words[prep:], " ")
+	}
+
+	return strings.Trim(name, " ;-"), notes
+}
+
+func startsNote(seg string) bool {
+	first := strings.ToLower(strings.Fields(seg)[0])
+	return noteStarters[first] || leadingPrep[first]
+}
+
+func wordCount(s string) int {
+	return len(strings.Fields(s))
+}
+
+func isOptionalNote(note string) bool {
+	return strings.EqualFold(strings.TrimSpace(note), "optional")
+}
+
+func joinNotes(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return fmt.Sprintf("%s, %s", a, b)
+}
@@ -0,0 +1,110 @@
+package ingredients
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// corpusCase is one real-world line from testdata/corpus.json
+type corpusCase struct {
+	Line      string  `json:"line"`
+	Amount    float64 `json:"amount"`
+	AmountMax float64 `json:"amount_max"`
+	Unit      string  `json:"unit"`
+	Name      string  `json:"name"`
+	Notes     string  `json:"notes"`
+	Optional  bool    `json:"optional"`
+	Error     string  `json:"error"`
+}
+
+var corpusErrors = map[string]error{
+	"empty":   ErrEmptyLine,
+	"header":  ErrSectionHeader,
+	"no_name": ErrMissingName,
+}
+
+func TestParseCorpus(t *testing.T) {
+	data, err := os.ReadFile("testdata/corpus.json")
+	require.NoError(t, err)
+
+	var cases []corpusCase
+	require.NoError(t, json.Unmarshal(data, &cases))
+
+	for _, tc := range cases {
+		t.Run(tc.Line, func(t *testing.T) {
+			got, err := Parse(tc.Line)
+			if tc.Error != "" {
+				assert.ErrorIs(t, err, corpusErrors[tc.Error])
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tc.Amount, got.Amount, 0.001, "amount")
+			assert.InDelta(t, tc.AmountMax, got.AmountMax, 0.001, "amount max")
+			assert.Equal(t, recipe.MeasurementUnit(tc.Unit), got.Unit, "unit")
+			assert.Equal(t, tc.Name, got.Name, "name")
+			assert.Equal(t, tc.Notes, got.Notes, "notes")
+			assert.Equal(t, tc.Optional, got.Optional, "optional")
+		})
+	}
+}
+
+func TestParsedIngredientAndString(t *testing.T) {
+	p, err := Parse("2-3 tablespoons lemon juice (optional)")
+	require.NoError(t, err)
+
+	ing := p.Ingredient()
+	assert.Equal(t, 2.0, ing.Amount)
+	assert.Equal(t, recipe.MeasurementUnitTablespoon, ing.Unit)
+	assert.Equal(t, "2 to 3", ing.Notes)
+	assert.True(t, ing.Optional)
+	assert.NoError(t, ing.Validate())
+
+	assert.Equal(t, "2-3 tbsp lemon juice (optional)", p.String())
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "2 1/2", FormatAmount(2.5))
+	assert.Equal(t, "1/3", FormatAmount(1.0/3))
+	assert.Equal(t, "4", FormatAmount(4))
+	assert.Equal(t, "0.15", FormatAmount(0.15))
+}
+
+func TestParseUnit(t *testing.T) {
+	unit, ok := ParseUnit("Tablespoons")
+	assert.True(t, ok)
+	assert.Equal(t, recipe.MeasurementUnitTablespoon, unit)
+
+	unit, ok = ParseUnit("fl. oz.")
+	assert.True(t, ok)
+	assert.Equal(t, recipe.MeasurementUnitOunce, unit)
+
+	_, ok = ParseUnit("handful")
+	assert.False(t, ok)
+}
+
+func TestParseQuantity(t *testing.T) {
+	amount, unit, ok := ParseQuantity("1 ½ Tablespoons")
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, amount)
+	assert.Equal(t, recipe.MeasurementUnitTablespoon, unit)
+
+	amount, unit, ok = ParseQuantity("a pinch")
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, amount)
+	assert.Equal(t, recipe.MeasurementUnitPinch, unit)
+
+	amount, unit, ok = ParseQuantity("2-3")
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, amount)
+	assert.Equal(t, recipe.MeasurementUnit(""), unit)
+
+	_, _, ok = ParseQuantity("to taste")
+	assert.False(t, ok)
+	_, _, ok = ParseQuantity("1 handful")
+	assert.False(t, ok)
+}
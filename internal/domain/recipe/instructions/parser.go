@@ -0,0 +1,139 @@
+// Package instructions splits free-text recipe directions into steps. It
+// pairs with the ingredients parser for recipe import.
+package instructions
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+)
+
+// Step is one instruction and the indexes of the input lines it was built
+// from, so callers can carry per-line metadata such as OCR confidence
+type Step struct {
+	Text        string
+	Lines       []int
+	Duration    time.Duration
+	Temperature *recipe.Temperature
+}
+
+// Instruction converts the step into a domain instruction
+func (s Step) Instruction() recipe.Instruction {
+	return recipe.Instruction{
+		Description: s.Text,
+		Duration:    s.Duration,
+		Temperature: s.Temperature,
+	}
+}
+
+var (
+	// "1.", "2)", "3:", "Step 4", "STEP 5 -"
+	stepMarker = regexp.MustCompile(`(?i)^(?:step\s*\d{1,2}\b\s*[.):\-–]?|\d{1,2}\s*[.):]|[•·*\-–])\s*`)
+	durationRe = regexp.MustCompile(`(?i)\b(\d+)(?:\s*(?:-|–|to)\s*(\d+))?\s*(minutes?|mins?|hours?|hrs?)\b`)
+	temperRe   = regexp.MustCompile(`(?i)\b(\d{2,3})\s*(?:°|º|degrees?)\s*([CF])\b`)
+)
+
+// Split groups lines into steps. Blank lines and step markers ("1.", "Step 2",
+// bullets) start a new step. Unmarked text without any blank lines is split
+// at lines ending a sentence; wrapped lines are joined and words hyphenated
+// across a line break are rejoined.
+func Split(lines []string) []Step {
+	marked, blank := false, false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			blank = true
+		} else if stepMarker.MatchString(line) {
+			marked = true
+		}
+	}
+	splitSentences := !marked && !blank
+
+	var (
+		steps   []Step
+		current *Step
+	)
+	flush := func() {
+		if current != nil && current.Text != "" {
+			finish(current)
+			steps = append(steps, *current)
+		}
+		current = nil
+	}
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+
+		if loc := stepMarker.FindStringIndex(line); loc != nil {
+			flush()
+			line = strings.TrimSpace(line[loc[1]:])
+		}
+		if current == nil {
+			current = &Step{}
+		}
+		current.Lines = append(current.Lines, i)
+		current.Text = joinWrapped(current.Text, line)
+
+		if splitSentences && endsSentence(line) {
+			flush()
+		}
+	}
+	flush()
+
+	return steps
+}
+
+// joinWrapped appends a wrapped line, undoing "com-" / "bine" hyphenation
+func joinWrapped(text, line string) string {
+	switch {
+	case text == "":
+		return line
+	case line == "":
+		return text
+	}
+
+	if strings.HasSuffix(text, "-") && len(text) > 1 {
+		before := []rune(text)[len([]rune(text))-2]
+		first := []rune(line)[0]
+		if unicode.IsLetter(before) && unicode.IsLower(first) {
+			return strings.TrimSuffix(text, "-") + line
+		}
+	}
+	return text + " " + line
+}
+
+func endsSentence(line string) bool {
+	return strings.HasSuffix(line, ".") || strings.HasSuffix(line, "!") || strings.HasSuffix(line, "?")
+}
+
+// finish extracts the first duration and oven temperature mentioned in the step
+func finish(s *Step) {
+	if m := durationRe.FindStringSubmatch(s.Text); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] != "" {
+			n, _ = strconv.Atoi(m[2])
+		}
+		unit := time.Minute
+		if strings.HasPrefix(strings.ToLower(m[3]), "h") {
+			unit = time.Hour
+		}
+		s.Duration = time.Duration(n) * unit
+	}
+
+	if m := temperRe.FindStringSubmatch(s.Text); m != nil {
+		value, _ := strconv.ParseFloat(m[1], 64)
+		unit := recipe.TemperatureUnitFahrenheit
+		if strings.EqualFold(m[2], "C") {
+			unit = recipe.TemperatureUnitCelsius
+		}
+		s.Temperature = &recipe.Temperature{Value: value, Unit: unit}
+	}
+}
@@ -0,0 +1,54 @@
+package instructions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitNumberedSteps(t *testing.T) {
+	steps := Split([]string{
+		"1. Preheat the oven to 350°F and grease a",
+		"9-inch pan.",
+		"2) Whisk the flour and sugar; com-",
+		"bine with the eggs.",
+		"Step 3: Bake for 25-30 minutes.",
+	})
+
+	require.Len(t, steps, 3)
+	assert.Equal(t, "Preheat the oven to 350°F and grease a 9-inch pan.", steps[0].Text)
+	assert.Equal(t, []int{0, 1}, steps[0].Lines)
+	assert.Equal(t, &recipe.Temperature{Value: 350, Unit: recipe.TemperatureUnitFahrenheit}, steps[0].Temperature)
+	assert.Equal(t, "Whisk the flour and sugar; combine with the eggs.", steps[1].Text)
+	assert.Equal(t, "Bake for 25-30 minutes.", steps[2].Text)
+	assert.Equal(t, 30*time.Minute, steps[2].Duration)
+}
+
+func TestSplitParagraphs(t *testing.T) {
+	steps := Split([]string{
+		"Melt the butter in a large pan. Add the",
+		"onion and cook until soft.",
+		"",
+		"Stir in the rice and simmer 1 hour.",
+	})
+
+	require.Len(t, steps, 2)
+	assert.Equal(t, "Melt the butter in a large pan. Add the onion and cook until soft.", steps[0].Text)
+	assert.Equal(t, []int{3}, steps[1].Lines)
+	assert.Equal(t, time.Hour, steps[1].Duration)
+}
+
+func TestSplitUnmarkedSentences(t *testing.T) {
+	steps := Split([]string{
+		"Toss the greens with the dressing.",
+		"Top with toasted",
+		"walnuts and serve at once.",
+	})
+
+	require.Len(t, steps, 2)
+	assert.Equal(t, "Top with toasted walnuts and serve at once.", steps[1].Text)
+	assert.Nil(t, steps[1].Temperature)
+}
@@ -0,0 +1,146 @@
+package nutrition
+
+import (
+	"math"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/units"
+	"github.com/google/uuid"
+)
+
+// Reasons an ingredient is left out of an estimate
+const (
+	ReasonOptional = "optional"
+	ReasonNoAmount = "no_amount"
+	ReasonNoWeight = "no_weight"
+	ReasonUnknown  = "unknown_food"
+)
+
+// Line is one ingredient's share of an estimate. Food is the reference
+// food it was estimated as; Reason says why an ingredient was left out.
+type Line struct {
+	IngredientID uuid.UUID
+	Name         string
+	Amount       float64
+	Unit         recipe.MeasurementUnit
+	Food         string
+	Grams        float64
+	Facts        Facts
+	Cost         float64
+	Reason       string
+}
+
+// Estimated reports whether the line counts towards the totals
+func (l Line) Estimated() bool {
+	return l.Reason == ""
+}
+
+// Estimate is a recipe's nutrition and ingredient cost. Coverage is the
+// share of the recipe's required ingredients that could be estimated, so a
+// client can say how far to trust the totals.
+type Estimate struct {
+	Servings       int
+	Total          Facts
+	PerServing     Facts
+	Cost           float64
+	CostPerServing float64
+	Coverage       float64
+	Lines          []Line
+}
+
+// Of estimates ingredients that make the given servings against the
+// built-in reference foods
+func Of(ingredients []recipe.Ingredient, servings int) Estimate {
+	return reference.Of(ingredients, servings)
+}
+
+// Of estimates ingredients that make the given servings. Optional
+// ingredients and those whose food or weight is unknown are listed but not
+// counted.
+func (t *Table) Of(ingredients []recipe.Ingredient, servings int) Estimate {
+	if servings <= 0 {
+		servings = 1
+	}
+	estimate := Estimate{Servings: servings, Lines: make([]Line, len(ingredients))}
+	required, estimated := 0, 0
+	for i, ingredient := range ingredients {
+		line := t.lineFor(ingredient)
+		estimate.Lines[i] = line
+		if ingredient.Optional {
+			continue
+		}
+		required++
+		if !line.Estimated() {
+			continue
+		}
+		estimated++
+		estimate.Total = estimate.Total.Add(line.Facts)
+		estimate.Cost += line.Cost
+	}
+
+	estimate.PerServing = round(estimate.Total.Scale(1 / float64(servings)))
+	estimate.Total = round(estimate.Total)
+	estimate.CostPerServing = cents(estimate.Cost / float64(servings))
+	estimate.Cost = cents(estimate.Cost)
+	estimate.Coverage = 1
+	if required > 0 {
+		estimate.Coverage = math.Round(float64(estimated)/float64(required)*100) / 100
+	}
+	return estimate
+}
+
+func (t *Table) lineFor(ingredient recipe.Ingredient) Line {
+	line := Line{IngredientID: ingredient.ID, Name: ingredient.Name, Amount: ingredient.Amount, Unit: ingredient.Unit}
+	food, ok := t.Lookup(ingredient.Name)
+	if ok {
+		line.Food = food.Name
+	}
+	switch {
+	case ingredient.Optional:
+		line.Reason = ReasonOptional
+		return line
+	case !ok:
+		line.Reason = ReasonUnknown
+		return line
+	case ingredient.Amount <= 0:
+		line.Reason = ReasonNoAmount
+		return line
+	}
+
+	grams, weighed := units.Grams(ingredient.Amount, ingredient.Unit, ingredient.Name)
+	if !weighed {
+		line.Reason = ReasonNoWeight
+		return line
+	}
+	line.Grams = math.Round(grams*10) / 10
+	line.Facts = round(food.Per100g.Scale(grams / 100))
+	line.Cost = cents(food.PricePerKg * grams / 1000)
+	return line
+}
+
+// Delta is the change from one amount to another
+func Delta(from, to Facts) Facts {
+	return round(to.Add(from.Scale(-1)))
+}
+
+// round keeps whole calories and one decimal for the rest
+func round(f Facts) Facts {
+	return Facts{
+		Calories:      math.Round(f.Calories),
+		Protein:       oneDecimal(f.Protein),
+		Carbohydrates: oneDecimal(f.Carbohydrates),
+		Fat:           oneDecimal(f.Fat),
+		Fiber:         oneDecimal(f.Fiber),
+		Sugar:         oneDecimal(f.Sugar),
+		Sodium:        math.Round(f.Sodium),
+		Cholesterol:   math.Round(f.Cholesterol),
+	}
+}
+
+func oneDecimal(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+func cents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
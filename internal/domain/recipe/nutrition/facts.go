@@ -0,0 +1,170 @@
+// Package nutrition estimates a recipe's nutrition and ingredient cost from
+// a reference table of common ingredients, and simulates how proposed
+// swaps or quantity changes move those estimates. Amounts are weighed
+// through the units package, so an ingredient given in cups or pieces is
+// estimated as long as its weight is known.
+package nutrition
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Currency of the reference prices
+const Currency = "USD"
+
+// Facts are nutrients for an amount of food, in the same units as
+// recipe.NutritionInfo: grams, with sodium and cholesterol in milligrams
+type Facts struct {
+	Calories      float64
+	Protein       float64
+	Carbohydrates float64
+	Fat           float64
+	Fiber         float64
+	Sugar         float64
+	Sodium        float64
+	Cholesterol   float64
+}
+
+// Add returns the sum of two amounts
+func (f Facts) Add(o Facts) Facts {
+	return Facts{
+		Calories:      f.Calories + o.Calories,
+		Protein:       f.Protein + o.Protein,
+		Carbohydrates: f.Carbohydrates + o.Carbohydrates,
+		Fat:           f.Fat + o.Fat,
+		Fiber:         f.Fiber + o.Fiber,
+		Sugar:         f.Sugar + o.Sugar,
+		Sodium:        f.Sodium + o.Sodium,
+		Cholesterol:   f.Cholesterol + o.Cholesterol,
+	}
+}
+
+// Scale multiplies every nutrient by factor
+func (f Facts) Scale(factor float64) Facts {
+	return Facts{
+		Calories:      f.Calories * factor,
+		Protein:       f.Protein * factor,
+		Carbohydrates: f.Carbohydrates * factor,
+		Fat:           f.Fat * factor,
+		Fiber:         f.Fiber * factor,
+		Sugar:         f.Sugar * factor,
+		Sodium:        f.Sodium * factor,
+		Cholesterol:   f.Cholesterol * factor,
+	}
+}
+
+// Food is a reference ingredient: nutrients per 100 g and a typical retail
+// price per kilogram
+type Food struct {
+	Name       string
+	Per100g    Facts
+	PricePerKg float64
+	keywords   *regexp.Regexp
+}
+
+// foods are matched against ingredient names in order, so the more
+// specific entries come first: chicken stock is stock rather than chicken,
+// ground turkey comes before turkey and peanut butter before butter. Nutrients follow USDA
+// standard reference values for the raw or dry food; prices are rough US
+// supermarket averages.
+var foods = []Food{
+	{"stock", Facts{7, 0.9, 0.5, 0.2, 0, 0.3, 320, 0}, 1.5, words(`stock|broth`)},
+	{"ground turkey", Facts{150, 19.7, 0, 8.3, 0, 0, 72, 80}, 9.0, words(`(?:ground|minced) turkey|turkey mince`)},
+	{"turkey", Facts{135, 30, 0, 1, 0, 0, 55, 70}, 11.0, words(`turkey`)},
+	{"salmon", Facts{208, 20, 0, 13, 0, 0, 59, 55}, 22.0, words(`salmon`)},
+	{"tuna", Facts{132, 28, 0, 1, 0, 0, 45, 47}, 18.0, words(`tuna`)},
+	{"shrimp", Facts{99, 24, 0.2, 0.3, 0, 0, 111, 189}, 22.0, words(`shrimps?|prawns?`)},
+	{"white fish", Facts{82, 18, 0, 0.7, 0, 0, 54, 43}, 18.0, words(`cod|haddock|tilapia|pollock|white fish`)},
+	{"ground beef", Facts{254, 17.2, 0, 20, 0, 0, 66, 71}, 11.0, words(`(?:ground|minced) beef|beef mince`)},
+	{"beef", Facts{250, 26, 0, 15, 0, 0, 60, 90}, 17.6, words(`beef|steaks?|sirloin|brisket`)},
+	{"chicken breast", Facts{120, 22.5, 0, 2.6, 0, 0, 45, 73}, 9.9, words(`chicken breasts?`)},
+	{"chicken thigh", Facts{177, 19.7, 0, 10.9, 0, 0, 80, 100}, 7.7, words(`chicken thighs?`)},
+	{"chicken", Facts{215, 18.6, 0, 15, 0, 0, 70, 75}, 6.6, words(`chicken`)},
+	{"bacon", Facts{541, 37, 1.4, 42, 0, 0, 1717, 110}, 15.0, words(`bacon|pancetta`)},
+	{"sausage", Facts{301, 12, 2, 27, 0, 1, 749, 70}, 11.0, words(`sausages?|chorizo`)},
+	{"pork", Facts{242, 27, 0, 14, 0, 0, 62, 80}, 8.8, words(`pork|ham`)},
+	{"lamb", Facts{282, 16.6, 0, 23.4, 0, 0, 59, 73}, 20.0, words(`lamb`)},
+	{"tofu", Facts{76, 8, 1.9, 4.8, 0.3, 0.6, 7, 0}, 5.5, words(`tofu`)},
+	{"lentils", Facts{353, 25.8, 60, 1.1, 30.5, 2, 6, 0}, 4.4, words(`lentils?`)},
+	{"beans", Facts{139, 7, 22, 2, 6, 0.5, 240, 0}, 3.3, words(`chickpeas?|garbanzos?|(?:black|kidney|pinto|cannellini|white|navy) beans?`)},
+	{"pasta", Facts{371, 13, 75, 1.5, 3.2, 2.7, 6, 0}, 3.3, words(`egg noodles|pasta|spaghetti|penne|macaroni|linguine|fettuccine|noodles?`)},
+	{"eggs", Facts{143, 12.6, 0.7, 9.5, 0, 0.4, 142, 372}, 5.5, words(`eggs?`)},
+	{"coconut milk", Facts{230, 2.3, 6, 24, 2.2, 3.3, 15, 0}, 4.4, words(`coconut milk|coconut cream`)},
+	{"peanut butter", Facts{588, 25, 20, 50, 6, 9, 17, 0}, 6.6, words(`peanut butter`)},
+	{"butter", Facts{717, 0.9, 0.1, 81, 0, 0.1, 11, 215}, 10.0, words(`butter`)},
+	{"cream", Facts{340, 2.8, 2.7, 36, 0, 2.9, 27, 113}, 6.6, words(`(?:heavy|double|whipping|sour) cream|cream`)},
+	{"yogurt", Facts{61, 3.5, 4.7, 3.3, 0, 4.7, 46, 13}, 3.5, words(`yogh?urt`)},
+	{"milk", Facts{61, 3.2, 4.8, 3.3, 0, 5.1, 43, 10}, 1.0, words(`milk|buttermilk`)},
+	{"cheese", Facts{400, 25, 1.3, 33, 0, 0.5, 650, 100}, 13.0, words(`cheese|cheddar|parmesan|mozzarella|feta|gruyere`)},
+	{"flour", Facts{364, 10, 76, 1, 2.7, 0.3, 2, 0}, 1.1, words(`flour`)},
+	{"sugar", Facts{387, 0, 100, 0, 0, 100, 1, 0}, 1.8, words(`sugar`)},
+	{"honey", Facts{304, 0.3, 82, 0, 0.2, 82, 4, 0}, 11.0, words(`honey|maple syrup|syrup`)},
+	{"rice", Facts{360, 6.6, 79, 0.6, 1.3, 0.1, 5, 0}, 2.2, words(`rice`)},
+	{"bread", Facts{265, 9, 49, 3.2, 2.7, 5, 490, 0}, 5.5, words(`bread|breadcrumbs|buns?|tortillas?`)},
+	{"oats", Facts{389, 16.9, 66, 6.9, 10.6, 1, 2, 0}, 3.3, words(`oats|oatmeal`)},
+	{"sweet potato", Facts{86, 1.6, 20, 0.1, 3, 4.2, 55, 0}, 2.6, words(`sweet potato(?:es)?`)},
+	{"potato", Facts{77, 2, 17, 0.1, 2.2, 0.8, 6, 0}, 1.8, words(`potato(?:es)?`)},
+	{"onion", Facts{40, 1.1, 9.3, 0.1, 1.7, 4.2, 4, 0}, 2.2, words(`onions?|shallots?|leeks?`)},
+	{"garlic", Facts{149, 6.4, 33, 0.5, 2.1, 1, 17, 0}, 8.8, words(`garlic`)},
+	{"tomato", Facts{18, 0.9, 3.9, 0.2, 1.2, 2.6, 5, 0}, 3.5, words(`tomato(?:es)?`)},
+	{"carrot", Facts{41, 0.9, 9.6, 0.2, 2.8, 4.7, 69, 0}, 2.0, words(`carrots?`)},
+	{"black pepper", Facts{251, 10, 64, 3.3, 25, 0.6, 20, 0}, 40.0, words(`black pepper|peppercorns?`)},
+	{"bell pepper", Facts{31, 1, 6, 0.3, 2.1, 4.2, 4, 0}, 5.5, words(`(?:bell|red|green|yellow) peppers?|capsicums?`)},
+	{"leafy greens", Facts{23, 2.9, 3.6, 0.4, 2.2, 0.4, 79, 0}, 8.8, words(`spinach|kale|chard|lettuce|arugula|rocket`)},
+	{"mushrooms", Facts{22, 3.1, 3.3, 0.3, 1, 2, 5, 0}, 8.8, words(`mushrooms?`)},
+	{"broccoli", Facts{34, 2.8, 6.6, 0.4, 2.6, 1.7, 33, 0}, 4.4, words(`broccoli|cauliflower`)},
+	{"citrus", Facts{29, 1.1, 9.3, 0.3, 2.8, 2.5, 2, 0}, 4.4, words(`lemons?|limes?`)},
+	{"apple", Facts{52, 0.3, 14, 0.2, 2.4, 10, 1, 0}, 4.0, words(`apples?`)},
+	{"banana", Facts{89, 1.1, 23, 0.3, 2.6, 12, 1, 0}, 1.3, words(`bananas?`)},
+	{"avocado", Facts{160, 2, 8.5, 14.7, 6.7, 0.7, 7, 0}, 6.6, words(`avocados?`)},
+	{"olive oil", Facts{884, 0, 0, 100, 0, 0, 2, 0}, 9.9, words(`olive oil`)},
+	{"oil", Facts{884, 0, 0, 100, 0, 0, 0, 0}, 3.3, words(`oil`)},
+	{"nuts", Facts{600, 20, 20, 52, 8, 4, 5, 0}, 18.0, words(`almonds?|walnuts?|pecans?|cashews?|peanuts?|hazelnuts?`)},
+	{"cocoa", Facts{228, 19.6, 58, 13.7, 37, 1.8, 21, 0}, 15.0, words(`cocoa`)},
+	{"chocolate", Facts{546, 4.9, 61, 31, 7, 48, 24, 8}, 13.0, words(`chocolate`)},
+	{"soy sauce", Facts{53, 8, 4.9, 0.6, 0.8, 0.4, 5493, 0}, 5.5, words(`soy sauce|tamari`)},
+	{"salt", Facts{0, 0, 0, 0, 0, 0, 38758, 0}, 1.1, words(`salt`)},
+	{"wine", Facts{83, 0.1, 2.6, 0, 0, 0.6, 4, 0}, 8.0, words(`wine`)},
+	{"water", Facts{}, 0, words(`water`)},
+}
+
+const (
+	wordsPrefix = `(?i)\b(?:`
+	wordsSuffix = `)\b`
+)
+
+func words(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(wordsPrefix + pattern + wordsSuffix)
+}
+
+// NewFood creates a reference food matching ingredient names that contain
+// any of the alternatives in keywords, a regular expression such as
+// `almonds?|walnuts?`, as whole words and ignoring case
+func NewFood(name, keywords string, per100g Facts, pricePerKg float64) (Food, error) {
+	re, err := regexp.Compile(wordsPrefix + keywords + wordsSuffix)
+	if err != nil {
+		return Food{}, fmt.Errorf("keywords for %s: %w", name, err)
+	}
+	return Food{Name: name, Per100g: per100g, PricePerKg: pricePerKg, keywords: re}, nil
+}
+
+// Keywords is the pattern the food was created with
+func (f Food) Keywords() string {
+	if f.keywords == nil {
+		return ""
+	}
+	pattern := strings.TrimPrefix(f.keywords.String(), wordsPrefix)
+	return strings.TrimSuffix(pattern, wordsSuffix)
+}
+
+// Reference returns the built-in foods, in match order
+func Reference() []Food {
+	return append([]Food(nil), foods...)
+}
+
+// Lookup finds the reference food an ingredient is, by name
+func Lookup(name string) (Food, bool) {
+	return reference.Lookup(name)
+}
@@ -0,0 +1,80 @@
+package nutrition
+
+import (
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwappingBeefForTurkey(t *testing.T) {
+	beef := recipe.Ingredient{ID: uuid.New(), Name: "lean ground beef", Amount: 500, Unit: recipe.MeasurementUnitGram}
+	onion := recipe.Ingredient{ID: uuid.New(), Name: "onion", Amount: 1, Unit: recipe.MeasurementUnitPiece}
+	parsley := recipe.Ingredient{ID: uuid.New(), Name: "parsley", Amount: 1, Unit: recipe.MeasurementUnitBunch}
+	cheese := recipe.Ingredient{ID: uuid.New(), Name: "grated cheddar", Amount: 50, Unit: recipe.MeasurementUnitGram, Optional: true}
+	ingredients := []recipe.Ingredient{beef, onion, parsley, cheese}
+
+	before := Of(ingredients, 4)
+	assert.Equal(t, 1330.0, before.Total.Calories)
+	assert.Equal(t, 0.67, before.Coverage, "parsley is not in the reference table")
+	assert.Equal(t, ReasonUnknown, before.Lines[2].Reason)
+	assert.Equal(t, ReasonOptional, before.Lines[3].Reason)
+	assert.Equal(t, 150.0, before.Lines[1].Grams, "an onion is weighed as a typical piece")
+
+	turkey := "ground turkey"
+	less := 400.0
+	swapped, err := Apply(ingredients, []Change{
+		{IngredientID: beef.ID, Name: turkey, Amount: &less},
+		{IngredientID: parsley.ID, Remove: true},
+		{Name: "olive oil", Amount: ptr(1.0), Unit: unitPtr(recipe.MeasurementUnitTablespoon)},
+	})
+	require.NoError(t, err)
+	require.Len(t, swapped, 4)
+	assert.Equal(t, "lean ground beef", ingredients[0].Name, "the recipe itself is left alone")
+	assert.Equal(t, turkey, swapped[0].Name)
+	assert.Equal(t, "olive oil", swapped[3].Name)
+
+	after := Of(swapped, 4)
+	assert.Equal(t, 1.0, after.Coverage)
+	assert.Less(t, after.PerServing.Fat, before.PerServing.Fat)
+	assert.Less(t, after.Cost, before.Cost)
+	assert.Equal(t, after.PerServing.Calories-before.PerServing.Calories, Delta(before.PerServing, after.PerServing).Calories)
+
+	_, err = Apply(ingredients, []Change{{IngredientID: beef.ID}, {IngredientID: beef.ID, Remove: true}})
+	assert.ErrorIs(t, err, ErrChangedTwice)
+	_, err = Apply(ingredients, []Change{{IngredientID: uuid.New(), Remove: true}})
+	assert.ErrorIs(t, err, ErrUnknownIngredient)
+	_, err = Apply(ingredients, []Change{{Name: "salt"}})
+	assert.ErrorIs(t, err, ErrEmptyAddition)
+}
+
+func ptr(v float64) *float64 { return &v }
+
+func unitPtr(u recipe.MeasurementUnit) *recipe.MeasurementUnit { return &u }
+
+func TestTableOfStoredFoods(t *testing.T) {
+	for _, food := range Reference() {
+		rebuilt, err := NewFood(food.Name, food.Keywords(), food.Per100g, food.PricePerKg)
+		require.NoError(t, err)
+		assert.Equal(t, food.Keywords(), rebuilt.Keywords())
+	}
+	_, err := NewFood("broken", "(", Facts{}, 0)
+	assert.Error(t, err)
+
+	lentils, err := NewFood("lentils", `lentils?`, Facts{Calories: 352, Protein: 24.6}, 4)
+	require.NoError(t, err)
+	table := NewTable([]Food{lentils})
+	ingredients := []recipe.Ingredient{
+		{ID: uuid.New(), Name: "red lentils", Amount: 200, Unit: recipe.MeasurementUnitGram},
+		{ID: uuid.New(), Name: "salt", Amount: 5, Unit: recipe.MeasurementUnitGram},
+	}
+
+	label := table.Label(ingredients, 2)
+	require.NotNil(t, label)
+	assert.Equal(t, 352, label.Calories)
+	assert.Equal(t, 24.6, label.Protein)
+	assert.Equal(t, 0.5, label.Coverage, "salt is not in this table")
+	assert.Nil(t, table.Label(ingredients[1:], 2))
+}
@@ -0,0 +1,58 @@
+package nutrition
+
+import "github.com/alchemorsel/v3/internal/domain/recipe"
+
+// reference is the table of built-in foods
+var reference = NewTable(foods)
+
+// Table is a list of reference foods, matched against ingredient names in
+// order, so more specific foods should come first
+type Table struct {
+	foods []Food
+}
+
+// NewTable creates a table of foods, in match order
+func NewTable(foods []Food) *Table {
+	return &Table{foods: append([]Food(nil), foods...)}
+}
+
+// Len is the number of foods in the table
+func (t *Table) Len() int {
+	return len(t.foods)
+}
+
+// Lookup finds the first food whose keywords match the ingredient name
+func (t *Table) Lookup(name string) (Food, bool) {
+	for _, food := range t.foods {
+		if food.keywords != nil && food.keywords.MatchString(name) {
+			return food, true
+		}
+	}
+	return Food{}, false
+}
+
+// Default returns the table of built-in foods
+func Default() *Table {
+	return reference
+}
+
+// Label is the per-serving nutrition stored on a recipe made of the
+// ingredients, or nil when none of them could be estimated
+func (t *Table) Label(ingredients []recipe.Ingredient, servings int) *recipe.NutritionInfo {
+	estimate := t.Of(ingredients, servings)
+	if len(ingredients) == 0 || estimate.Coverage == 0 {
+		return nil
+	}
+	per := estimate.PerServing
+	return &recipe.NutritionInfo{
+		Calories:      int(per.Calories),
+		Protein:       per.Protein,
+		Carbohydrates: per.Carbohydrates,
+		Fat:           per.Fat,
+		Fiber:         per.Fiber,
+		Sugar:         per.Sugar,
+		Sodium:        per.Sodium,
+		Cholesterol:   per.Cholesterol,
+		Coverage:      estimate.Coverage,
+	}
+}
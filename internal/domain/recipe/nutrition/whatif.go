@@ -0,0 +1,102 @@
+package nutrition
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/google/uuid"
+)
+
+// MaxChanges bounds the edits simulated at once
+const MaxChanges = 50
+
+// Errors for proposed changes
+var (
+	ErrTooManyChanges    = errors.New("at most 50 changes can be simulated at once")
+	ErrUnknownIngredient = errors.New("the recipe has no such ingredient")
+	ErrChangedTwice      = errors.New("an ingredient can only be changed once")
+	ErrInvalidAmount     = errors.New("amount must not be negative")
+	ErrEmptyAddition     = errors.New("an added ingredient needs a name and an amount")
+)
+
+// Change proposes one edit to a recipe's ingredients. IngredientID names
+// the ingredient to change, or is uuid.Nil to add one. Name swaps the
+// ingredient for another, such as turkey for beef; Amount and Unit change
+// the quantity. Empty fields keep what the recipe has.
+type Change struct {
+	IngredientID uuid.UUID
+	Remove       bool
+	Name         string
+	Amount       *float64
+	Unit         *recipe.MeasurementUnit
+}
+
+// Apply returns the ingredients with the changes made, leaving the given
+// slice untouched. Additions go at the end in the order given.
+func Apply(ingredients []recipe.Ingredient, changes []Change) ([]recipe.Ingredient, error) {
+	if len(changes) > MaxChanges {
+		return nil, ErrTooManyChanges
+	}
+	index := make(map[uuid.UUID]int, len(ingredients))
+	for i, ingredient := range ingredients {
+		index[ingredient.ID] = i
+	}
+
+	changed := make([]recipe.Ingredient, len(ingredients))
+	copy(changed, ingredients)
+	removed := make(map[int]bool)
+	seen := make(map[uuid.UUID]bool, len(changes))
+	var added []recipe.Ingredient
+
+	for _, change := range changes {
+		if change.Amount != nil && (*change.Amount < 0 || math.IsNaN(*change.Amount) || math.IsInf(*change.Amount, 0)) {
+			return nil, ErrInvalidAmount
+		}
+		name := strings.TrimSpace(change.Name)
+
+		if change.IngredientID == uuid.Nil {
+			if change.Remove || name == "" || change.Amount == nil || *change.Amount == 0 {
+				return nil, ErrEmptyAddition
+			}
+			addition := recipe.Ingredient{Name: name, Amount: *change.Amount}
+			if change.Unit != nil {
+				addition.Unit = *change.Unit
+			}
+			added = append(added, addition)
+			continue
+		}
+
+		i, ok := index[change.IngredientID]
+		if !ok {
+			return nil, ErrUnknownIngredient
+		}
+		if seen[change.IngredientID] {
+			return nil, ErrChangedTwice
+		}
+		seen[change.IngredientID] = true
+
+		if change.Remove {
+			removed[i] = true
+			continue
+		}
+		if name != "" {
+			changed[i].Name = name
+		}
+		if change.Amount != nil {
+			changed[i].Amount = *change.Amount
+		}
+		if change.Unit != nil {
+			changed[i].Unit = *change.Unit
+		}
+	}
+
+	result := make([]recipe.Ingredient, 0, len(changed)+len(added))
+	for i, ingredient := range changed {
+		if !removed[i] {
+			result = append(result, ingredient)
+		}
+	}
+	return append(result, added...), nil
+}
@@ -0,0 +1,104 @@
+// Package pdfdoc writes PDF documents set in the standard Type 1 fonts,
+// which every reader has, so recipes print without embedding a font.
+// Text is taken as UTF-8 and written in WinAnsi, which covers the accented
+// letters recipes use; what it cannot hold prints as "?".
+package pdfdoc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Document is a PDF of one or more pages
+type Document struct {
+	pages []*Page
+	fonts []string // base fonts in the order first used, named F1, F2...
+}
+
+// Page is one page of a document, drawn in points from the bottom left
+type Page struct {
+	doc     *Document
+	width   float64
+	height  float64
+	content bytes.Buffer
+}
+
+// New creates an empty document
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage appends a page of the given size
+func (d *Document) AddPage(width, height float64) *Page {
+	page := &Page{doc: d, width: width, height: height}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Pages is the number of pages added so far
+func (d *Document) Pages() int {
+	return len(d.pages)
+}
+
+// fontName is the page resource name of a font
+func (d *Document) fontName(f Font) string {
+	for i, base := range d.fonts {
+		if base == f.base {
+			return fmt.Sprintf("F%d", i+1)
+		}
+	}
+	d.fonts = append(d.fonts, f.base)
+	return fmt.Sprintf("F%d", len(d.fonts))
+}
+
+// Text sets a line of text with its baseline starting at x, y
+func (p *Page) Text(f Font, size, x, y float64, text string) {
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", p.doc.fontName(f), size, x, y, escaper.Replace(Encode(text)))
+}
+
+// Line strokes a straight line width points thick
+func (p *Page) Line(width, x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "%g w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// Bytes writes out the file
+func (d *Document) Bytes() []byte {
+	// Objects 1 and 2 are the catalog and page tree, then come the fonts;
+	// each page then takes two, itself and its content stream
+	objects := []string{"<< /Type /Catalog /Pages 2 0 R >>", ""}
+	resources := make([]string, len(d.fonts))
+	for i, base := range d.fonts {
+		resources[i] = fmt.Sprintf("/F%d %d 0 R", i+1, len(objects)+1)
+		objects = append(objects, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", base))
+	}
+	kids := make([]string, len(d.pages))
+	for i, page := range d.pages {
+		id := len(objects) + 1
+		kids[i] = fmt.Sprintf("%d 0 R", id)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Contents %d 0 R /Resources << /Font << %s >> >> >>",
+				page.width, page.height, id+1, strings.Join(resources, " ")),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages))
+
+	// The second line marks the file as binary, since WinAnsi text is
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
@@ -0,0 +1,56 @@
+package pdfdoc
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentCrossReferencesEveryObject(t *testing.T) {
+	doc := New()
+	first := doc.AddPage(595.28, 841.89)
+	first.Text(Helvetica, 12, 56, 780, "Crème brûlée (serves 4)")
+	first.Line(0.5, 56, 770, 300, 770)
+	doc.AddPage(595.28, 841.89).Text(HelveticaBold, 12, 56, 780, `C:\pantry`)
+	out := doc.Bytes()
+
+	require.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "(Cr\xe8me br\xfbl\xe9e \\(serves 4\\)) Tj")
+	assert.Contains(t, string(out), `(C:\\pantry) Tj`)
+	assert.Contains(t, string(out), "/Type /Pages /Kids [5 0 R 7 0 R] /Count 2")
+	assert.Contains(t, string(out), "/Font << /F1 3 0 R /F2 4 0 R >>")
+
+	xref := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(out, -1)
+	require.Len(t, xref, 8)
+	for i, entry := range xref {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(strconv.Itoa(i+1)+" 0 obj")), "object %d", i+1)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	assert.Equal(t, "1/3 cup ?", Encode("⅓ cup 🍮"))
+	assert.Equal(t, "Cr\xe8me \x95 \x96 ??", Encode("Crème • – 寿司"))
+	assert.Equal(t, "a b", Encode("a\tb\x00"))
+}
+
+func TestWrapFitsTheWidth(t *testing.T) {
+	lines := Helvetica.Wrap(11, "Whisk the yolks with the sugar until pale and thick, then pour in the warm cream a little at a time.", 200)
+	require.Greater(t, len(lines), 1)
+	for _, line := range lines {
+		assert.LessOrEqual(t, Helvetica.Width(11, line), 200.0)
+	}
+
+	// Long words are split between runes, never inside one
+	lines = Courier.Wrap(10, "ééééééééééééééééééééééééé", 60)
+	require.Greater(t, len(lines), 1)
+	for _, line := range lines {
+		assert.Equal(t, "é", line[:2])
+	}
+	assert.Equal(t, 6.0, Courier.Width(10, "é"))
+}
@@ -0,0 +1,128 @@
+package pdfdoc
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Font is one of the standard Type 1 fonts
+type Font struct {
+	base   string
+	widths *[95]int // nil for the monospaced Courier faces
+}
+
+// The standard fonts documents are set in
+var (
+	Helvetica        = Font{"Helvetica", &helveticaWidths}
+	HelveticaBold    = Font{"Helvetica-Bold", &helveticaBoldWidths}
+	HelveticaOblique = Font{"Helvetica-Oblique", &helveticaWidths}
+	Courier          = Font{"Courier", nil}
+	CourierBold      = Font{"Courier-Bold", nil}
+)
+
+// Width is how wide text is set in the font at size, in points
+func (f Font) Width(size float64, text string) float64 {
+	encoded := Encode(text)
+	units := 0
+	for i := 0; i < len(encoded); i++ {
+		switch c := encoded[i]; {
+		case f.widths == nil:
+			units += 600
+		case c >= 32 && c <= 126:
+			units += f.widths[c-32]
+		default:
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// Wrap breaks text into lines no wider than width at spaces. Words wider
+// than a line are split.
+func (f Font) Wrap(size float64, text string, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if f.Width(size, candidate) <= width {
+				line = candidate
+				break
+			}
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+				continue
+			}
+			runes := []rune(word)
+			cut := 1
+			for cut < len(runes) && f.Width(size, string(runes[:cut+1])) <= width {
+				cut++
+			}
+			lines = append(lines, string(runes[:cut]))
+			word = string(runes[cut:])
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// winAnsi maps the characters outside Latin-1 that WinAnsi has room for
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, 'Š': 0x8a, 'Œ': 0x8c, 'Ž': 0x8e,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'™': 0x99, 'š': 0x9a, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// fractions spells out the fractions WinAnsi lacks
+var fractions = strings.NewReplacer("⅓", "1/3", "⅔", "2/3", "⅛", "1/8", "⅜", "3/8", "⅝", "5/8", "⅞", "7/8")
+
+// Encode converts UTF-8 text to WinAnsi, replacing what it cannot hold
+// with "?" and control characters with spaces or nothing
+func Encode(text string) string {
+	text = fractions.Replace(text)
+	var b strings.Builder
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			b.WriteByte(' ')
+		case r < ' ' || r == 0x7f:
+		case r < 0x7f || (r >= 0xa0 && r <= 0xff):
+			b.WriteByte(byte(r))
+		default:
+			if c, ok := winAnsi[r]; ok {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
+
+// Glyph widths of the printable ASCII characters in thousandths of an em,
+// from the Adobe font metrics; the oblique face matches the regular one
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
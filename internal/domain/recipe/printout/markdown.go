@@ -0,0 +1,98 @@
+package printout
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// markdownEscaper keeps recipe text from being read as Markdown syntax
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `|`, `\|`, `#`, `\#`,
+)
+
+// Markdown renders the printout as a Markdown document
+func Markdown(p Printout) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", md(p.Title))
+	if p.Description != "" {
+		for _, paragraph := range paragraphs(p.Description) {
+			fmt.Fprintf(&b, "%s\n\n", md(paragraph))
+		}
+	}
+	if facts := p.Facts(); len(facts) > 0 {
+		fmt.Fprintf(&b, "%s\n\n", strings.Join(facts, " · "))
+	}
+
+	b.WriteString("## Ingredients\n\n")
+	for _, ingredient := range p.Ingredients {
+		fmt.Fprintf(&b, "- %s\n", md(ingredient.Text))
+	}
+	if len(p.Ingredients) == 0 {
+		b.WriteString("No ingredients listed.\n")
+	}
+
+	b.WriteString("\n## Steps\n\n")
+	for _, step := range p.Steps {
+		fmt.Fprintf(&b, "%d. %s", step.Number, md(step.Text))
+		if cue := step.Cue(); cue != "" {
+			fmt.Fprintf(&b, " _(%s)_", cue)
+		}
+		b.WriteString("\n")
+	}
+	if len(p.Steps) == 0 {
+		b.WriteString("No steps listed.\n")
+	}
+
+	if n := p.Nutrition; n != nil {
+		b.WriteString("\n## Nutrition per serving\n\n| Nutrient | Amount |\n| --- | ---: |\n")
+		for _, fact := range n.Facts() {
+			fmt.Fprintf(&b, "| %s | %s |\n", fact.Label, fact.Amount)
+		}
+		if n.Partial() {
+			fmt.Fprintf(&b, "\nSome ingredients could not be counted (%.0f%% were), so the figures are a lower bound.\n", n.Coverage*100)
+		}
+	}
+
+	contains, mayContain := p.AllergenLabels()
+	if len(contains) > 0 || len(mayContain) > 0 {
+		b.WriteString("\n## Allergens\n\n")
+		if len(contains) > 0 {
+			fmt.Fprintf(&b, "**Contains:** %s\n", strings.Join(contains, ", "))
+		}
+		if len(mayContain) > 0 {
+			if len(contains) > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "**May contain:** %s\n", strings.Join(mayContain, ", "))
+		}
+	}
+
+	if credits := p.Credits(); len(credits) > 0 {
+		b.WriteString("\n---\n\n")
+		for i, credit := range credits {
+			if i > 0 {
+				b.WriteString("  \n")
+			}
+			b.WriteString(md(credit))
+		}
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
+
+func md(text string) string {
+	return markdownEscaper.Replace(strings.Join(strings.Fields(text), " "))
+}
+
+// paragraphs splits text on blank lines
+func paragraphs(text string) []string {
+	var out []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			out = append(out, paragraph)
+		}
+	}
+	return out
+}
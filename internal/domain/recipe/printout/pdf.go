@@ -0,0 +1,181 @@
+package printout
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/pdfdoc"
+)
+
+// The PDF is set on A4 pages in the standard Helvetica fonts
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	pageMargin = 56.0
+	footerSize = 8.0
+	leading    = 1.35
+)
+
+var (
+	regular = pdfdoc.Helvetica
+	bold    = pdfdoc.HelveticaBold
+	italic  = pdfdoc.HelveticaOblique
+)
+
+// PDF renders the printout as an A4 document, with the credits and page
+// numbers at the foot of every page
+func PDF(p Printout) []byte {
+	footer := italic.Wrap(footerSize, strings.Join(p.Credits(), " "), pageWidth-2*pageMargin-60)
+	d := &document{doc: pdfdoc.New(), footer: footer, bottom: pageMargin + float64(len(footer)+1)*footerSize*leading}
+	d.newPage()
+
+	d.paragraph(bold, 22, 0, p.Title)
+	if p.Attribution.Author != "" {
+		d.paragraph(italic, 10, 0, "Recipe by "+p.Attribution.Author)
+	}
+	if facts := p.Facts(); len(facts) > 0 {
+		d.space(4)
+		d.paragraph(regular, 10, 0, strings.Join(facts, "  ·  "))
+	}
+	d.rule()
+	for _, paragraph := range paragraphs(p.Description) {
+		d.paragraph(regular, 11, 0, paragraph)
+		d.space(4)
+	}
+
+	d.heading("Ingredients")
+	for _, ingredient := range p.Ingredients {
+		d.item("•", ingredient.Text)
+	}
+	if len(p.Ingredients) == 0 {
+		d.paragraph(italic, 11, 0, "No ingredients listed.")
+	}
+
+	d.heading("Steps")
+	for _, step := range p.Steps {
+		d.item(fmt.Sprintf("%d.", step.Number), step.Text)
+		if cue := step.Cue(); cue != "" {
+			d.paragraph(italic, 9, 18, cue)
+		}
+		d.space(3)
+	}
+	if len(p.Steps) == 0 {
+		d.paragraph(italic, 11, 0, "No steps listed.")
+	}
+
+	if n := p.Nutrition; n != nil {
+		d.heading("Nutrition per serving")
+		for _, fact := range n.Facts() {
+			d.fact(fact)
+		}
+		if n.Partial() {
+			d.space(4)
+			d.paragraph(italic, 9, 0, fmt.Sprintf("Some ingredients could not be counted (%.0f%% were), so the figures are a lower bound.", n.Coverage*100))
+		}
+	}
+
+	contains, mayContain := p.AllergenLabels()
+	if len(contains) > 0 || len(mayContain) > 0 {
+		d.heading("Allergens")
+		if len(contains) > 0 {
+			d.paragraph(regular, 11, 0, "Contains: "+strings.Join(contains, ", "))
+		}
+		if len(mayContain) > 0 {
+			d.paragraph(regular, 11, 0, "May contain: "+strings.Join(mayContain, ", "))
+		}
+	}
+
+	return d.bytes()
+}
+
+// document lays text out top to bottom, starting a page when one fills.
+// Text stops at bottom, above the footer.
+type document struct {
+	doc    *pdfdoc.Document
+	pages  []*pdfdoc.Page
+	y      float64
+	footer []string
+	bottom float64
+}
+
+func (d *document) newPage() {
+	d.pages = append(d.pages, d.doc.AddPage(pageWidth, pageHeight))
+	d.y = pageHeight - pageMargin
+}
+
+func (d *document) page() *pdfdoc.Page {
+	return d.pages[len(d.pages)-1]
+}
+
+// line moves down one line of the given size, starting a new page when
+// the line would run into the footer
+func (d *document) line(size float64) float64 {
+	if d.y-size*leading < d.bottom {
+		d.newPage()
+	}
+	d.y -= size * leading
+	return d.y + size*0.25
+}
+
+func (d *document) space(points float64) {
+	d.y -= points
+}
+
+// paragraph wraps text to the page width less indent
+func (d *document) paragraph(f pdfdoc.Font, size, indent float64, text string) {
+	width := pageWidth - 2*pageMargin - indent
+	for _, line := range f.Wrap(size, text, width) {
+		d.page().Text(f, size, pageMargin+indent, d.line(size), line)
+	}
+}
+
+func (d *document) heading(text string) {
+	d.space(10)
+	if d.y-40 < d.bottom {
+		d.newPage()
+	}
+	d.paragraph(bold, 14, 0, text)
+	d.space(2)
+}
+
+// item is a list entry with its marker hanging in the margin
+func (d *document) item(marker, text string) {
+	const indent, size = 18.0, 11.0
+	lines := regular.Wrap(size, text, pageWidth-2*pageMargin-indent)
+	for i, line := range lines {
+		y := d.line(size)
+		if i == 0 {
+			d.page().Text(regular, size, pageMargin, y, marker)
+		}
+		d.page().Text(regular, size, pageMargin+indent, y, line)
+	}
+}
+
+// fact is a nutrition table row, the amount set flush right
+func (d *document) fact(f Fact) {
+	const size, column = 11.0, 240.0
+	y := d.line(size)
+	d.page().Text(regular, size, pageMargin, y, f.Label)
+	d.page().Text(bold, size, pageMargin+column-bold.Width(size, f.Amount), y, f.Amount)
+	d.page().Line(0.5, pageMargin, y-3, pageMargin+column, y-3)
+}
+
+func (d *document) rule() {
+	d.space(6)
+	d.page().Line(1, pageMargin, d.y, pageWidth-pageMargin, d.y)
+	d.space(10)
+}
+
+// bytes adds the footers and writes out the file
+func (d *document) bytes() []byte {
+	for i, page := range d.pages {
+		y := pageMargin
+		for j := len(d.footer) - 1; j >= 0; j-- {
+			page.Text(italic, footerSize, pageMargin, y, d.footer[j])
+			y += footerSize * leading
+		}
+		number := fmt.Sprintf("%d / %d", i+1, len(d.pages))
+		page.Text(regular, footerSize, pageWidth-pageMargin-regular.Width(footerSize, number), pageMargin, number)
+	}
+	return d.doc.Bytes()
+}
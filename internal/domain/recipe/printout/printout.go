@@ -0,0 +1,266 @@
+// Package printout lays a recipe out for reading away from the site: a
+// clean page with its ingredients, steps, nutrition and credits, exported
+// as PDF, Markdown or JSON.
+package printout
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/allergens"
+	"github.com/alchemorsel/v3/internal/domain/recipe/units"
+	"github.com/google/uuid"
+)
+
+// Printout is a recipe ready to print or save
+type Printout struct {
+	RecipeID    uuid.UUID    `json:"id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	Servings    int          `json:"servings,omitempty"`
+	PrepMinutes int          `json:"prep_minutes,omitempty"`
+	CookMinutes int          `json:"cook_minutes,omitempty"`
+	Ingredients []Ingredient `json:"ingredients"`
+	Steps       []Step       `json:"steps"`
+	// Nutrition is per serving, nil when it has not been worked out
+	Nutrition   *Nutrition  `json:"nutrition,omitempty"`
+	Allergens   []string    `json:"allergens,omitempty"`
+	MayContain  []string    `json:"may_contain,omitempty"`
+	Attribution Attribution `json:"attribution"`
+}
+
+// Ingredient is one ingredient line. Text is the line as printed.
+type Ingredient struct {
+	Text     string  `json:"text"`
+	Amount   float64 `json:"amount,omitempty"`
+	Unit     string  `json:"unit,omitempty"`
+	Name     string  `json:"name"`
+	Notes    string  `json:"notes,omitempty"`
+	Optional bool    `json:"optional,omitempty"`
+}
+
+// Step is one numbered step with its timing and heat, if any
+type Step struct {
+	Number      int    `json:"number"`
+	Text        string `json:"text"`
+	Minutes     int    `json:"minutes,omitempty"`
+	Temperature string `json:"temperature,omitempty"`
+}
+
+// Nutrition is the nutrition per serving. Coverage below 1 means some
+// ingredients could not be counted.
+type Nutrition struct {
+	Calories      int     `json:"calories"`
+	Protein       float64 `json:"protein_g"`
+	Carbohydrates float64 `json:"carbohydrates_g"`
+	Fat           float64 `json:"fat_g"`
+	Fiber         float64 `json:"fiber_g"`
+	Sugar         float64 `json:"sugar_g"`
+	Sodium        float64 `json:"sodium_mg"`
+	Cholesterol   float64 `json:"cholesterol_mg"`
+	Coverage      float64 `json:"coverage,omitempty"`
+}
+
+// Attribution credits the people and places a recipe came from
+type Attribution struct {
+	Author      string  `json:"author,omitempty"`
+	URL         string  `json:"url,omitempty"`
+	ForkedFrom  *Origin `json:"forked_from,omitempty"`
+	AIGenerated bool    `json:"ai_generated,omitempty"`
+}
+
+// Origin is the recipe a fork was adapted from
+type Origin struct {
+	Title  string `json:"title"`
+	Author string `json:"author,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// Fact is one printed line of the nutrition table
+type Fact struct {
+	Label  string
+	Amount string
+}
+
+// Build lays the recipe out with its credits. Recipes saved before
+// allergen labelling get their label worked out from the ingredients.
+func Build(r *recipe.Recipe, credit Attribution) Printout {
+	p := Printout{
+		RecipeID:    r.ID(),
+		Title:       strings.TrimSpace(r.Title()),
+		Description: strings.TrimSpace(r.Description()),
+		Servings:    r.Servings(),
+		PrepMinutes: int(math.Ceil(r.PrepTime().Minutes())),
+		CookMinutes: int(math.Ceil(r.CookTime().Minutes())),
+		Ingredients: make([]Ingredient, len(r.Ingredients())),
+		Steps:       make([]Step, len(r.Instructions())),
+		Attribution: credit,
+	}
+	p.Attribution.AIGenerated = p.Attribution.AIGenerated || r.IsAIGenerated()
+
+	for i, ingredient := range r.Ingredients() {
+		p.Ingredients[i] = Ingredient{
+			Text:     ingredientText(ingredient),
+			Amount:   ingredient.Amount,
+			Unit:     string(ingredient.Unit),
+			Name:     ingredient.Name,
+			Notes:    ingredient.Notes,
+			Optional: ingredient.Optional,
+		}
+	}
+	for i, instruction := range r.Instructions() {
+		step := Step{
+			Number:  instruction.StepNumber,
+			Text:    strings.TrimSpace(instruction.Description),
+			Minutes: int(math.Ceil(instruction.Duration.Minutes())),
+		}
+		if step.Number == 0 {
+			step.Number = i + 1
+		}
+		if t := instruction.Temperature; t != nil {
+			step.Temperature = strconv.FormatFloat(math.Round(t.Value), 'f', -1, 64) + "°" + string(t.Unit)
+		}
+		p.Steps[i] = step
+	}
+
+	if n := r.NutritionInfo(); n != nil {
+		p.Nutrition = &Nutrition{
+			Calories:      n.Calories,
+			Protein:       n.Protein,
+			Carbohydrates: n.Carbohydrates,
+			Fat:           n.Fat,
+			Fiber:         n.Fiber,
+			Sugar:         n.Sugar,
+			Sodium:        n.Sodium,
+			Cholesterol:   n.Cholesterol,
+			Coverage:      n.Coverage,
+		}
+	}
+
+	label := r.Allergens()
+	if label == nil && len(r.Ingredients()) > 0 {
+		label = allergens.Label(r.Ingredients())
+	}
+	if label != nil {
+		p.Allergens = label.Contains
+		p.MayContain = label.MayContain
+	}
+	return p
+}
+
+// JSON renders the printout as an indented JSON document
+func JSON(p Printout) []byte {
+	data, _ := json.MarshalIndent(p, "", "  ")
+	return append(data, '\n')
+}
+
+func ingredientText(ingredient recipe.Ingredient) string {
+	text := ingredient.Name
+	if ingredient.Amount > 0 {
+		text = units.Format(ingredient.Amount, ingredient.Unit) + " " + text
+	}
+	if notes := strings.TrimSpace(ingredient.Notes); notes != "" {
+		text += ", " + notes
+	}
+	if ingredient.Optional {
+		text += " (optional)"
+	}
+	return text
+}
+
+// Facts says how long the recipe takes and how many it serves, such as
+// "Serves 4", "Prep 10 min", "Cook 25 min"
+func (p Printout) Facts() []string {
+	var facts []string
+	if p.Servings > 0 {
+		facts = append(facts, fmt.Sprintf("Serves %d", p.Servings))
+	}
+	if p.PrepMinutes > 0 {
+		facts = append(facts, "Prep "+minutes(p.PrepMinutes))
+	}
+	if p.CookMinutes > 0 {
+		facts = append(facts, "Cook "+minutes(p.CookMinutes))
+	}
+	return facts
+}
+
+// Cue is a step's timing and heat, such as "10 min, 180°C"
+func (s Step) Cue() string {
+	var cues []string
+	if s.Minutes > 0 {
+		cues = append(cues, minutes(s.Minutes))
+	}
+	if s.Temperature != "" {
+		cues = append(cues, s.Temperature)
+	}
+	return strings.Join(cues, ", ")
+}
+
+// Facts lays out the nutrition table, calories first
+func (n Nutrition) Facts() []Fact {
+	grams := func(v float64) string { return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64) + " g" }
+	milligrams := func(v float64) string { return strconv.FormatFloat(math.Round(v), 'f', -1, 64) + " mg" }
+	return []Fact{
+		{"Calories", strconv.Itoa(n.Calories)},
+		{"Fat", grams(n.Fat)},
+		{"Cholesterol", milligrams(n.Cholesterol)},
+		{"Sodium", milligrams(n.Sodium)},
+		{"Carbohydrates", grams(n.Carbohydrates)},
+		{"Fiber", grams(n.Fiber)},
+		{"Sugar", grams(n.Sugar)},
+		{"Protein", grams(n.Protein)},
+	}
+}
+
+// Partial reports whether some ingredients were left out of the figures
+func (n Nutrition) Partial() bool {
+	return n.Coverage > 0 && n.Coverage < 1
+}
+
+// AllergenLabels names the allergens as they are declared, such as Nuts
+func (p Printout) AllergenLabels() (contains, mayContain []string) {
+	for _, name := range p.Allergens {
+		contains = append(contains, allergens.LabelOf(name))
+	}
+	for _, name := range p.MayContain {
+		mayContain = append(mayContain, allergens.LabelOf(name))
+	}
+	return contains, mayContain
+}
+
+// Credits are the attribution sentences printed at the foot of the recipe
+func (p Printout) Credits() []string {
+	a := p.Attribution
+	var credits []string
+	if a.Author != "" {
+		credits = append(credits, "Recipe by "+a.Author+".")
+	}
+	if origin := a.ForkedFrom; origin != nil {
+		credit := `Adapted from "` + origin.Title + `"`
+		if origin.Author != "" {
+			credit += " by " + origin.Author
+		}
+		credits = append(credits, credit+".")
+	}
+	if a.AIGenerated {
+		credits = append(credits, "Created with the Alchemorsel AI chef.")
+	}
+	if a.URL != "" {
+		credits = append(credits, "From Alchemorsel: "+a.URL)
+	}
+	return credits
+}
+
+func minutes(m int) string {
+	if m < 60 {
+		return fmt.Sprintf("%d min", m)
+	}
+	if m%60 == 0 {
+		return fmt.Sprintf("%d h", m/60)
+	}
+	return fmt.Sprintf("%d h %d min", m/60, m%60)
+}
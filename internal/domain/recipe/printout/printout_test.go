@@ -0,0 +1,95 @@
+package printout
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRecipe(t *testing.T, steps int) *recipe.Recipe {
+	t.Helper()
+	r, err := recipe.NewRecipe("Crème Brûlée", "Silky custard under a burnt sugar crust.", uuid.New())
+	require.NoError(t, err)
+	require.NoError(t, r.SetServings(4))
+	for _, ingredient := range []recipe.Ingredient{
+		{Name: "heavy cream", Amount: 2, Unit: recipe.MeasurementUnitCup},
+		{Name: "egg yolks", Amount: 5, Unit: recipe.MeasurementUnitPiece},
+		{Name: "sugar", Amount: 0.5, Unit: recipe.MeasurementUnitCup, Notes: "plus more for the top"},
+		{Name: "vanilla pod", Amount: 1, Optional: true},
+	} {
+		require.NoError(t, r.AddIngredient(ingredient))
+	}
+	require.NoError(t, r.AddInstruction(recipe.Instruction{
+		Description: "Bake the custards in a water bath.",
+		Duration:    40 * time.Minute,
+		Temperature: &recipe.Temperature{Value: 150, Unit: recipe.TemperatureUnitCelsius},
+	}))
+	for i := 1; i < steps; i++ {
+		require.NoError(t, r.AddInstruction(recipe.Instruction{Description: "Keep an eye on the [custards] and *do not* let them boil over while they set in the oven."}))
+	}
+	return r
+}
+
+func TestBuildAndMarkdownCreditTheRecipe(t *testing.T) {
+	p := Build(newTestRecipe(t, 2), Attribution{
+		Author:     "Sam Cook",
+		URL:        "https://alchemorsel.example/recipes/1",
+		ForkedFrom: &Origin{Title: "Classic Custard", Author: "Alex Baker"},
+	})
+
+	assert.Equal(t, "2 cup heavy cream", p.Ingredients[0].Text)
+	assert.Equal(t, "1/2 cup sugar, plus more for the top", p.Ingredients[2].Text)
+	assert.Equal(t, "1 vanilla pod (optional)", p.Ingredients[3].Text)
+	assert.Equal(t, "40 min, 150°C", p.Steps[0].Cue())
+	assert.Contains(t, p.Allergens, "eggs")
+	assert.Equal(t, []string{
+		"Recipe by Sam Cook.",
+		`Adapted from "Classic Custard" by Alex Baker.`,
+		"From Alchemorsel: https://alchemorsel.example/recipes/1",
+	}, p.Credits())
+
+	text := string(Markdown(p))
+	assert.True(t, strings.HasPrefix(text, "# Crème Brûlée\n"))
+	assert.Contains(t, text, "Serves 4\n")
+	assert.Contains(t, text, "- 2 cup heavy cream\n")
+	assert.Contains(t, text, "1. Bake the custards in a water bath. _(40 min, 150°C)_\n")
+	assert.Contains(t, text, `2. Keep an eye on the \[custards\] and \*do not\* let them`)
+	assert.Contains(t, text, "**Contains:** ")
+	assert.Contains(t, text, "Recipe by Sam Cook.  \n")
+
+	var decoded Printout
+	require.NoError(t, json.Unmarshal(JSON(p), &decoded))
+	assert.Equal(t, p.Title, decoded.Title)
+	assert.Equal(t, "Sam Cook", decoded.Attribution.Author)
+}
+
+func TestPDFBreaksLongRecipesOntoPages(t *testing.T) {
+	p := Build(newTestRecipe(t, 60), Attribution{Author: "Sam Cook", URL: "https://alchemorsel.example/recipes/1"})
+	doc := PDF(p)
+
+	require.True(t, bytes.HasPrefix(doc, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(doc, []byte("%%EOF\n")))
+	count := regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`).FindSubmatch(doc)
+	require.NotNil(t, count)
+	pages, _ := strconv.Atoi(string(count[1]))
+	assert.Greater(t, pages, 1)
+	assert.Contains(t, string(doc), "(Cr\xe8me Br\xfbl\xe9e) Tj")
+	assert.Equal(t, pages, bytes.Count(doc, []byte("(Recipe by Sam Cook. From Alchemorsel: https://alchemorsel.example/recipes/1) Tj")))
+
+	// Every object the cross-reference table lists starts where it says
+	xref := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(doc, -1)
+	require.NotEmpty(t, xref)
+	for i, entry := range xref {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(doc[offset:], []byte(strconv.Itoa(i+1)+" 0 obj")), "object %d", i+1)
+	}
+}
@@ -0,0 +1,183 @@
+// Package richresults builds the schema.org Recipe JSON-LD for a recipe and
+// checks a JSON-LD document against Google's Recipe rich result
+// requirements, so authors see what keeps a recipe out of search features.
+package richresults
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+)
+
+// Document is a JSON-LD object as it is embedded in the page
+type Document map[string]interface{}
+
+// Options carry page details that are not part of the recipe aggregate
+type Options struct {
+	// URL is the canonical recipe page
+	URL string
+	// AuthorName is the display name of the recipe author
+	AuthorName string
+}
+
+// Build renders the recipe as a schema.org Recipe. Empty fields are left out
+// rather than emitted blank so validation reports them as missing.
+func Build(r *recipe.Recipe, opts Options) Document {
+	doc := Document{
+		"@context": "https://schema.org",
+		"@type":    "Recipe",
+		"name":     r.Title(),
+	}
+	if opts.URL != "" {
+		doc["url"] = opts.URL
+	}
+	if r.Description() != "" {
+		doc["description"] = r.Description()
+	}
+	if opts.AuthorName != "" {
+		doc["author"] = map[string]interface{}{"@type": "Person", "name": opts.AuthorName}
+	}
+	if published := r.PublishedAt(); published != nil {
+		doc["datePublished"] = published.Format("2006-01-02")
+	}
+
+	var images []interface{}
+	for _, image := range r.Images() {
+		images = append(images, image.URL)
+	}
+	if len(images) > 0 {
+		doc["image"] = images
+	}
+
+	if r.PrepTime() > 0 {
+		doc["prepTime"] = FormatDuration(r.PrepTime())
+	}
+	if r.CookTime() > 0 {
+		doc["cookTime"] = FormatDuration(r.CookTime())
+	}
+	if r.TotalTime() > 0 {
+		doc["totalTime"] = FormatDuration(r.TotalTime())
+	}
+	if r.Servings() > 0 {
+		doc["recipeYield"] = fmt.Sprintf("%d servings", r.Servings())
+	}
+	if r.Category() != "" {
+		doc["recipeCategory"] = string(r.Category())
+	}
+	if r.Cuisine() != "" {
+		doc["recipeCuisine"] = string(r.Cuisine())
+	}
+	if len(r.Tags()) > 0 {
+		doc["keywords"] = strings.Join(r.Tags(), ", ")
+	}
+
+	var lines []interface{}
+	for _, ingredient := range r.Ingredients() {
+		lines = append(lines, ingredientLine(ingredient))
+	}
+	if len(lines) > 0 {
+		doc["recipeIngredient"] = lines
+	}
+
+	var steps []interface{}
+	for _, instruction := range r.Instructions() {
+		steps = append(steps, map[string]interface{}{"@type": "HowToStep", "text": instruction.Description})
+	}
+	if len(steps) > 0 {
+		doc["recipeInstructions"] = steps
+	}
+
+	if calories := r.Calories(); calories > 0 {
+		doc["nutrition"] = map[string]interface{}{
+			"@type":    "NutritionInformation",
+			"calories": fmt.Sprintf("%d calories", calories),
+		}
+	}
+	if ratings := r.Ratings(); len(ratings) > 0 {
+		doc["aggregateRating"] = map[string]interface{}{
+			"@type":       "AggregateRating",
+			"ratingValue": strconv.FormatFloat(r.AverageRating(), 'f', 1, 64),
+			"ratingCount": len(ratings),
+		}
+	}
+	if videos := r.Videos(); len(videos) > 0 {
+		video := videos[0]
+		object := map[string]interface{}{
+			"@type":        "VideoObject",
+			"name":         r.Title(),
+			"contentUrl":   video.URL,
+			"thumbnailUrl": video.ThumbnailURL,
+			"uploadDate":   video.UploadedAt.Format(time.RFC3339),
+		}
+		if video.Caption != "" {
+			object["description"] = video.Caption
+		}
+		if video.Duration > 0 {
+			object["duration"] = FormatDuration(video.Duration)
+		}
+		doc["video"] = object
+	}
+
+	return doc
+}
+
+// ingredientLine spells an ingredient out the way it reads on the page,
+// leaving out the implicit "piece" unit: "2 eggs", not "2 piece eggs"
+func ingredientLine(i recipe.Ingredient) string {
+	parsed := ingredients.Parsed{
+		Amount:   i.Amount,
+		Unit:     i.Unit,
+		Name:     i.Name,
+		Notes:    i.Notes,
+		Optional: i.Optional,
+	}
+	if parsed.Unit == recipe.MeasurementUnitPiece {
+		parsed.Unit = ""
+	}
+	return parsed.String()
+}
+
+// FormatDuration renders a duration in ISO 8601, rounded to the minute:
+// 90 minutes → "PT1H30M"
+func FormatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	hours, minutes := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("PT%dM", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("PT%dH", hours)
+	default:
+		return fmt.Sprintf("PT%dH%dM", hours, minutes)
+	}
+}
+
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// ParseDuration reads an ISO 8601 duration such as "PT1H30M" or "P1DT2H".
+// Years, months and weeks are rejected since recipe times never need them.
+func ParseDuration(s string) (time.Duration, error) {
+	m := isoDuration.FindStringSubmatch(s)
+	if m == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("%q is not an ISO 8601 duration such as PT1H30M", s)
+	}
+
+	var d time.Duration
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute}
+	for i, unit := range units {
+		if m[i+1] != "" {
+			n, _ := strconv.Atoi(m[i+1])
+			d += time.Duration(n) * unit
+		}
+	}
+	if m[4] != "" {
+		seconds, _ := strconv.ParseFloat(m[4], 64)
+		d += time.Duration(seconds * float64(time.Second))
+	}
+	return d, nil
+}
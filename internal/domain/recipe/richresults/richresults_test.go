@@ -0,0 +1,123 @@
+package richresults
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fields(issues []Issue, severity Severity) []string {
+	var out []string
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			out = append(out, issue.Field)
+		}
+	}
+	return out
+}
+
+func TestBuildCompleteRecipeIsEligible(t *testing.T) {
+	r, err := recipe.NewRecipe("Lemon Bars", "Bright, buttery squares.", uuid.New())
+	require.NoError(t, err)
+	require.NoError(t, r.AddIngredient(recipe.Ingredient{Name: "eggs", Amount: 2, Unit: recipe.MeasurementUnitPiece}))
+	require.NoError(t, r.AddIngredient(recipe.Ingredient{Name: "butter", Amount: 0.5, Unit: recipe.MeasurementUnitCup, Notes: "softened"}))
+	require.NoError(t, r.AddInstruction(recipe.Instruction{Description: "Bake the crust."}))
+	require.NoError(t, r.AddImage("https://cdn.example.com/lemon-wide.jpg", ""))
+	require.NoError(t, r.SetServings(16))
+	r.SetTiming(20*time.Minute, 70*time.Minute)
+	r.SetTags([]string{"citrus", "baking"})
+
+	doc := Build(r, Options{AuthorName: "Ada"})
+
+	assert.Equal(t, []interface{}{"2 eggs", "1/2 cup butter (softened)"}, doc["recipeIngredient"])
+	assert.Equal(t, "PT20M", doc["prepTime"])
+	assert.Equal(t, "PT1H10M", doc["cookTime"])
+	assert.Equal(t, "PT1H30M", doc["totalTime"])
+	assert.Equal(t, "citrus, baking", doc["keywords"])
+
+	issues := Validate(doc, map[string]ImageSize{"https://cdn.example.com/lemon-wide.jpg": {Width: 1600, Height: 900}})
+	assert.True(t, Eligible(issues))
+	assert.Empty(t, fields(issues, SeverityError))
+	assert.Contains(t, fields(issues, SeverityWarning), "image", "4x3 and 1x1 crops are missing")
+	assert.Contains(t, fields(issues, SeverityWarning), "datePublished")
+}
+
+func TestValidateReportsRequiredAndMalformedFields(t *testing.T) {
+	var doc Document
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"@context": "https://schema.org",
+		"@type": "Recipe",
+		"name": " ",
+		"image": ["/static/tiny.jpg", "https://cdn.example.com/tiny.jpg"],
+		"author": {"@type": "Person"},
+		"datePublished": "last week",
+		"prepTime": "20 minutes",
+		"cookTime": "PT1H",
+		"recipeInstructions": [{"@type": "HowToStep", "text": ""}],
+		"aggregateRating": {"@type": "AggregateRating", "ratingValue": "4.5"},
+		"video": {"@type": "VideoObject", "name": "Lemon bars"}
+	}`), &doc))
+
+	issues := Validate(doc, map[string]ImageSize{"https://cdn.example.com/tiny.jpg": {Width: 200, Height: 200}})
+
+	assert.False(t, Eligible(issues))
+	assert.ElementsMatch(t, []string{
+		"name",
+		"image[0]",
+		"image[1]",
+		"author.name",
+		"datePublished",
+		"recipeInstructions[0].text",
+		"prepTime",
+		"aggregateRating.ratingCount",
+		"video.thumbnailUrl",
+		"video.uploadDate",
+		"video.contentUrl",
+	}, fields(issues, SeverityError))
+}
+
+func TestValidateMissingImageAndTimes(t *testing.T) {
+	issues := Validate(Document{"@type": "Recipe", "name": "Toast", "prepTime": "PT5M"}, nil)
+
+	assert.Equal(t, []string{"image"}, fields(issues, SeverityError))
+	assert.Contains(t, fields(issues, SeverityWarning), "prepTime", "cookTime should accompany prepTime")
+
+	doc := Document{"@type": "Recipe", "name": "Toast", "image": "https://cdn.example.com/gone.jpg"}
+	issues = Validate(doc, map[string]ImageSize{"https://cdn.example.com/gone.jpg": {Error: "status 404"}})
+	assert.Equal(t, []string{"image[0]"}, fields(issues, SeverityError))
+}
+
+func TestValidateUnknownImageSizeSkipsRatioCheck(t *testing.T) {
+	doc := Document{"@type": "Recipe", "name": "Toast", "image": []interface{}{"https://cdn.example.com/toast.avif"}}
+	issues := Validate(doc, map[string]ImageSize{"https://cdn.example.com/toast.avif": {}})
+
+	assert.Empty(t, fields(issues, SeverityError))
+	assert.NotContains(t, fields(issues, SeverityWarning), "image")
+}
+
+func TestDurations(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"PT45M":     45 * time.Minute,
+		"PT1H30M":   90 * time.Minute,
+		"P1DT2H":    26 * time.Hour,
+		"PT90S":     90 * time.Second,
+		"PT0H20M0S": 20 * time.Minute,
+	} {
+		got, err := ParseDuration(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	for _, bad := range []string{"", "P", "PT", "45", "1:30", "PT1.5H", "P1W"} {
+		_, err := ParseDuration(bad)
+		assert.Error(t, err, bad)
+	}
+
+	assert.Equal(t, "PT45M", FormatDuration(45*time.Minute))
+	assert.Equal(t, "PT2H", FormatDuration(2*time.Hour))
+	assert.Equal(t, "PT1H5M", FormatDuration(65*time.Minute))
+}
@@ -0,0 +1,363 @@
+package richresults
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Severity of a validation issue. Errors make the recipe ineligible for
+// rich results; warnings are recommended fields that limit how it is shown.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is one gap in a recipe's structured data
+type Issue struct {
+	Field    string   `json:"field"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// ImageSize holds the pixel dimensions of a recipe image. Error is set when
+// the image could not be fetched; zero dimensions mean they are unknown.
+type ImageSize struct {
+	Width  int
+	Height int
+	Error  string
+}
+
+// MinImagePixels is Google's minimum image area (width × height)
+const MinImagePixels = 50000
+
+// aspectRatios are the image crops Google asks for, widest first
+var aspectRatios = []struct {
+	name  string
+	ratio float64
+}{
+	{"16x9", 16.0 / 9.0},
+	{"4x3", 4.0 / 3.0},
+	{"1x1", 1},
+}
+
+// Eligible reports whether issues contain no errors
+func Eligible(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks a Recipe document. sizes maps image URLs to their
+// dimensions; images without an entry skip the size and ratio checks.
+func Validate(doc Document, sizes map[string]ImageSize) []Issue {
+	v := &validator{}
+
+	if doc["@type"] != "Recipe" {
+		v.error("@type", "must be \"Recipe\"")
+	}
+	if stringField(doc, "name") == "" {
+		v.error("name", "is required")
+	}
+	v.checkImages(doc, sizes)
+
+	v.recommend(doc, "description", "add a short description of the dish")
+	v.recommend(doc, "recipeCategory", "add a category such as \"dinner\" or \"dessert\"")
+	v.recommend(doc, "recipeCuisine", "add the cuisine, e.g. \"Italian\"")
+	v.recommend(doc, "keywords", "add tags so the recipe matches more searches")
+	v.recommend(doc, "recipeYield", "add the number of servings")
+	v.checkAuthor(doc)
+	v.checkDate(doc)
+	v.checkIngredients(doc)
+	v.checkInstructions(doc)
+	v.checkTimes(doc)
+	v.checkNutrition(doc)
+	v.checkRating(doc)
+	v.checkVideo(doc)
+
+	return v.issues
+}
+
+type validator struct {
+	issues []Issue
+}
+
+func (v *validator) error(field, message string) {
+	v.issues = append(v.issues, Issue{Field: field, Severity: SeverityError, Message: message})
+}
+
+func (v *validator) warn(field, message string) {
+	v.issues = append(v.issues, Issue{Field: field, Severity: SeverityWarning, Message: message})
+}
+
+func (v *validator) recommend(doc Document, field, hint string) {
+	if isEmpty(doc[field]) {
+		v.warn(field, "is missing; "+hint)
+	}
+}
+
+func (v *validator) checkImages(doc Document, sizes map[string]ImageSize) {
+	images := imageURLs(doc["image"])
+	if len(images) == 0 {
+		v.error("image", "is required; add at least one photo of the finished dish")
+		return
+	}
+
+	found := make(map[string]bool)
+	measured := 0
+	for i, raw := range images {
+		field := fmt.Sprintf("image[%d]", i)
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.error(field, fmt.Sprintf("%q is not an absolute, crawlable URL", raw))
+			continue
+		}
+		size, ok := sizes[raw]
+		if ok && size.Error != "" {
+			v.error(field, "could not be fetched: "+size.Error)
+			continue
+		}
+		if !ok || size.Width <= 0 || size.Height <= 0 {
+			continue
+		}
+		measured++
+		if size.Width*size.Height < MinImagePixels {
+			v.error(field, fmt.Sprintf("is %dx%d; images need at least %d pixels (e.g. 300x167)", size.Width, size.Height, MinImagePixels))
+			continue
+		}
+		if name := aspectRatio(size); name != "" {
+			found[name] = true
+		}
+	}
+
+	if measured == 0 {
+		return
+	}
+	var missing []string
+	for _, r := range aspectRatios {
+		if !found[r.name] {
+			missing = append(missing, r.name)
+		}
+	}
+	if len(missing) > 0 {
+		v.warn("image", "add crops with aspect ratio "+strings.Join(missing, ", ")+" for the best placement in results")
+	}
+}
+
+// aspectRatio names the Google crop an image matches within 2%
+func aspectRatio(size ImageSize) string {
+	ratio := float64(size.Width) / float64(size.Height)
+	for _, r := range aspectRatios {
+		if math.Abs(ratio-r.ratio)/r.ratio <= 0.02 {
+			return r.name
+		}
+	}
+	return ""
+}
+
+func (v *validator) checkAuthor(doc Document) {
+	switch author := doc["author"].(type) {
+	case nil:
+		v.warn("author", "is missing; add the author's name")
+	case string:
+		if strings.TrimSpace(author) == "" {
+			v.warn("author", "is missing; add the author's name")
+		}
+	case map[string]interface{}:
+		if stringField(author, "name") == "" {
+			v.error("author.name", "is required when author is given")
+		}
+	}
+}
+
+func (v *validator) checkDate(doc Document) {
+	raw := stringField(doc, "datePublished")
+	if raw == "" {
+		v.warn("datePublished", "is missing; it is set when the recipe is published")
+		return
+	}
+	if _, err := time.Parse("2006-01-02", raw); err == nil {
+		return
+	}
+	if _, err := time.Parse(time.RFC3339, raw); err != nil {
+		v.error("datePublished", fmt.Sprintf("%q is not an ISO 8601 date", raw))
+	}
+}
+
+func (v *validator) checkIngredients(doc Document) {
+	lines := asList(doc["recipeIngredient"])
+	if len(lines) == 0 {
+		v.warn("recipeIngredient", "is missing; list the ingredients")
+		return
+	}
+	for i, line := range lines {
+		if s, ok := line.(string); !ok || strings.TrimSpace(s) == "" {
+			v.error(fmt.Sprintf("recipeIngredient[%d]", i), "must be non-empty text")
+		}
+	}
+}
+
+func (v *validator) checkInstructions(doc Document) {
+	steps := asList(doc["recipeInstructions"])
+	if len(steps) == 0 {
+		v.warn("recipeInstructions", "is missing; add the method as steps")
+		return
+	}
+	for i, step := range steps {
+		field := fmt.Sprintf("recipeInstructions[%d]", i)
+		switch s := step.(type) {
+		case string:
+			if strings.TrimSpace(s) == "" {
+				v.error(field, "must be non-empty text")
+			}
+		case map[string]interface{}:
+			switch s["@type"] {
+			case "HowToStep":
+				if stringField(s, "text") == "" {
+					v.error(field+".text", "is required for a HowToStep")
+				}
+			case "HowToSection":
+				if len(asList(s["itemListElement"])) == 0 {
+					v.error(field+".itemListElement", "is required for a HowToSection")
+				}
+			default:
+				v.error(field+".@type", "must be HowToStep or HowToSection")
+			}
+		default:
+			v.error(field, "must be text or a HowToStep")
+		}
+	}
+}
+
+func (v *validator) checkTimes(doc Document) {
+	durations := make(map[string]time.Duration)
+	for _, field := range []string{"prepTime", "cookTime", "totalTime"} {
+		if _, ok := doc[field]; !ok {
+			continue
+		}
+		raw := stringField(doc, field)
+		d, err := ParseDuration(raw)
+		if err != nil {
+			v.error(field, err.Error())
+			continue
+		}
+		durations[field] = d
+	}
+
+	prep, hasPrep := durations["prepTime"]
+	cook, hasCook := durations["cookTime"]
+	total, hasTotal := durations["totalTime"]
+	switch {
+	case hasPrep != hasCook:
+		v.warn("prepTime", "prepTime and cookTime should be given together")
+	case !hasPrep && !hasTotal:
+		v.warn("totalTime", "is missing; add prep and cook times")
+	}
+	if hasPrep && hasCook && hasTotal && prep+cook != total {
+		v.warn("totalTime", fmt.Sprintf("is %s but prepTime + cookTime is %s", FormatDuration(total), FormatDuration(prep+cook)))
+	}
+}
+
+func (v *validator) checkNutrition(doc Document) {
+	nutrition, _ := doc["nutrition"].(map[string]interface{})
+	if nutrition == nil || isEmpty(nutrition["calories"]) {
+		v.warn("nutrition.calories", "is missing; run nutrition analysis to add calories")
+	}
+}
+
+func (v *validator) checkRating(doc Document) {
+	raw, ok := doc["aggregateRating"]
+	if !ok {
+		v.warn("aggregateRating", "is missing; it appears once the recipe has ratings")
+		return
+	}
+	rating, _ := raw.(map[string]interface{})
+	if rating == nil {
+		v.error("aggregateRating", "must be an AggregateRating object")
+		return
+	}
+	if isEmpty(rating["ratingValue"]) {
+		v.error("aggregateRating.ratingValue", "is required")
+	}
+	if isEmpty(rating["ratingCount"]) && isEmpty(rating["reviewCount"]) {
+		v.error("aggregateRating.ratingCount", "ratingCount or reviewCount is required")
+	}
+}
+
+func (v *validator) checkVideo(doc Document) {
+	raw, ok := doc["video"]
+	if !ok {
+		v.warn("video", "is missing; a how-to video makes the recipe eligible for video results")
+		return
+	}
+	video, _ := raw.(map[string]interface{})
+	if video == nil {
+		v.error("video", "must be a VideoObject")
+		return
+	}
+	for _, field := range []string{"name", "thumbnailUrl", "uploadDate"} {
+		if isEmpty(video[field]) {
+			v.error("video."+field, "is required")
+		}
+	}
+	if isEmpty(video["contentUrl"]) && isEmpty(video["embedUrl"]) {
+		v.error("video.contentUrl", "contentUrl or embedUrl is required")
+	}
+}
+
+// imageURLs accepts the forms schema.org allows: a URL, a list of URLs, or
+// ImageObjects with a url
+func imageURLs(value interface{}) []string {
+	var urls []string
+	for _, item := range asList(value) {
+		switch image := item.(type) {
+		case string:
+			urls = append(urls, image)
+		case map[string]interface{}:
+			urls = append(urls, stringField(image, "url"))
+		}
+	}
+	return urls
+}
+
+// asList treats a single value as a one-element list, as JSON-LD does
+func asList(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	case []string:
+		list := make([]interface{}, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		return list
+	default:
+		return []interface{}{v}
+	}
+}
+
+func stringField(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return strings.TrimSpace(s)
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package recipe
+
+import (
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/shared"
+	"github.com/google/uuid"
+)
+
+// Snapshot is the stored state of a recipe
+type Snapshot struct {
+	ID          uuid.UUID
+	Version     int64
+	Title       string
+	Description string
+	AuthorID    uuid.UUID
+	Language    string
+
+	Ingredients   []Ingredient
+	Instructions  []Instruction
+	NutritionInfo *NutritionInfo
+	Allergens     *AllergenLabel
+
+	Cuisine    CuisineType
+	Category   CategoryType
+	Difficulty DifficultyLevel
+	Tags       []string
+
+	PrepTime  time.Duration
+	CookTime  time.Duration
+	TotalTime time.Duration
+	Servings  int
+	Calories  int
+
+	AIGenerated bool
+	AIPrompt    string
+	AIModel     string
+
+	Likes         int
+	Views         int
+	Forks         int
+	Ratings       []Rating
+	AverageRating float64
+	ForkedFromID  *uuid.UUID
+
+	Images []Image
+	Videos []Video
+
+	Status             RecipeStatus
+	PublishedAt        *time.Time
+	ScheduledPublishAt *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	DeletedAt          *time.Time
+}
+
+// Reconstruct rebuilds a stored recipe without validation or events
+func Reconstruct(s Snapshot) *Recipe {
+	language := s.Language
+	if language == "" {
+		language = DefaultLanguage
+	}
+	return &Recipe{
+		id:                 s.ID,
+		version:            s.Version,
+		title:              s.Title,
+		description:        s.Description,
+		authorID:           s.AuthorID,
+		language:           language,
+		ingredients:        s.Ingredients,
+		instructions:       s.Instructions,
+		nutritionInfo:      s.NutritionInfo,
+		allergens:          s.Allergens,
+		cuisine:            s.Cuisine,
+		category:           s.Category,
+		difficulty:         s.Difficulty,
+		tags:               s.Tags,
+		prepTime:           s.PrepTime,
+		cookTime:           s.CookTime,
+		totalTime:          s.TotalTime,
+		servings:           s.Servings,
+		calories:           s.Calories,
+		aiGenerated:        s.AIGenerated,
+		aiPrompt:           s.AIPrompt,
+		aiModel:            s.AIModel,
+		likes:              s.Likes,
+		views:              s.Views,
+		forks:              s.Forks,
+		forkedFromID:       s.ForkedFromID,
+		ratings:            s.Ratings,
+		averageRating:      s.AverageRating,
+		images:             s.Images,
+		videos:             s.Videos,
+		status:             s.Status,
+		publishedAt:        s.PublishedAt,
+		scheduledPublishAt: s.ScheduledPublishAt,
+		createdAt:          s.CreatedAt,
+		updatedAt:          s.UpdatedAt,
+		deletedAt:          s.DeletedAt,
+		events:             []shared.DomainEvent{},
+	}
+}
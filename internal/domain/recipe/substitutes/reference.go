@@ -0,0 +1,112 @@
+package substitutes
+
+// reference is the table of built-in substitutes
+var reference = NewTable(builtIn)
+
+// builtIn are the common swaps home cooks reach for, in the order they are
+// suggested
+var builtIn = []Substitute{
+	must("butter", "olive oil", "3/4 cup per cup", "For sautéing and savory bakes, not for creaming"),
+	must("butter", "coconut oil", "1 cup per cup", "Solid at room temperature, so it creams like butter"),
+	must("butter", "unsweetened applesauce", "1/2 cup per cup", "In cakes and muffins; the crumb is moister and denser", TraitSugar),
+	must("butter", "ghee", "1 cup per cup", "Clarified butter, kept by most who avoid lactose", TraitDairy),
+
+	must("milk", "oat milk", "1 cup per cup", "Neutral and creamy; thickens sauces well", TraitGrain),
+	must("milk", "soy milk", "1 cup per cup", "Highest in protein, closest in baking", TraitLegume),
+	must("milk", "almond milk", "1 cup per cup", "Thinner; pick unsweetened for savory dishes"),
+	must("milk", "water and butter", "1 cup water plus 1 tbsp butter per cup", "A fallback for baking", TraitDairy),
+
+	must("buttermilk", "milk and lemon juice", "1 cup milk plus 1 tbsp lemon juice per cup", "Stand 5 minutes until it curdles", TraitDairy),
+	must("buttermilk", "plant milk and vinegar", "1 cup plant milk plus 1 tbsp vinegar per cup", "Soy milk curdles best"),
+	must("buttermilk", "yogurt thinned with milk", "3/4 cup yogurt plus 1/4 cup milk per cup", "", TraitDairy),
+
+	must("heavy cream", "coconut cream", "1 cup per cup", "Whips when chilled; tastes of coconut"),
+	must("heavy cream", "milk and butter", "3/4 cup milk plus 1/4 cup melted butter per cup", "For cooking, not whipping", TraitDairy),
+	must("heavy cream", "cashew cream", "1 cup per cup", "Blend soaked cashews with water until smooth"),
+
+	must("sour cream", "greek yogurt", "1 cup per cup", "Tangier and lighter", TraitDairy),
+	must("sour cream", "cashew cream and lemon juice", "1 cup plus 1 tsp lemon juice per cup", ""),
+
+	must("egg", "flax egg", "1 tbsp ground flaxseed plus 3 tbsp water per egg", "Rest 5 minutes to gel; binds but does not lift"),
+	must("egg", "chia egg", "1 tbsp chia seeds plus 3 tbsp water per egg", "Rest 10 minutes; leaves specks"),
+	must("egg", "mashed banana", "1/4 cup per egg", "In sweet bakes; tastes of banana", TraitSugar),
+	must("egg", "aquafaba", "3 tbsp per egg", "Chickpea cooking liquid; whips like egg white", TraitLegume),
+	must("egg", "unsweetened applesauce", "1/4 cup per egg", "In cakes and muffins", TraitSugar),
+
+	must("cheese", "nutritional yeast", "1/4 cup per 1/2 cup grated", "Savory and nutty in sauces and on pasta"),
+	must("parmesan", "nutritional yeast", "3 tbsp per 1/4 cup", "Savory and nutty; does not melt"),
+	must("parmesan", "pecorino", "1 cup per cup", "Saltier and sharper", TraitDairy),
+
+	must("all-purpose flour", "gluten-free flour blend", "1 cup per cup", "Pick one with xanthan gum for cakes and breads"),
+	must("all-purpose flour", "whole wheat flour", "3/4 cup per cup", "Denser and nuttier; add a splash more liquid", TraitGluten, TraitGrain),
+	must("all-purpose flour", "almond flour", "1 cup per cup", "For cookies and quick breads; browns quickly"),
+	must("flour", "gluten-free flour blend", "1 cup per cup", "Pick one with xanthan gum for cakes and breads"),
+	must("flour", "almond flour", "1 cup per cup", "For cookies and quick breads; browns quickly"),
+	must("cornstarch", "arrowroot", "1 tbsp per tbsp", "Thickens at lower heat and stays clear"),
+	must("cornstarch", "all-purpose flour", "2 tbsp per tbsp", "Cook a few minutes longer to lose the raw taste", TraitGluten, TraitGrain),
+	must("breadcrumbs", "crushed crackers", "1 cup per cup", "", TraitGluten, TraitGrain),
+	must("breadcrumbs", "rolled oats", "1 cup per cup", "Pulse briefly for binding meatballs and burgers", TraitGrain),
+	must("breadcrumbs", "almond meal", "1 cup per cup", "For coatings; browns quickly"),
+
+	must("sugar", "honey", "3/4 cup per cup", "Cut the liquid by 1/4 cup and the oven by 25°F", TraitHoney, TraitSugar),
+	must("sugar", "maple syrup", "3/4 cup per cup", "Cut the liquid by 3 tbsp", TraitSugar),
+	must("sugar", "erythritol", "1 cup per cup", "Sugar-free; can taste cool in large amounts"),
+	must("brown sugar", "white sugar and molasses", "1 cup sugar plus 1 tbsp molasses per cup", "", TraitSugar),
+	must("brown sugar", "coconut sugar", "1 cup per cup", "", TraitSugar),
+	must("honey", "maple syrup", "1 cup per cup", "Thinner and less floral", TraitSugar),
+	must("honey", "agave syrup", "1 cup per cup", "Sweeter; use a little less", TraitSugar),
+
+	must("baking powder", "baking soda and cream of tartar", "1/4 tsp soda plus 1/2 tsp cream of tartar per tsp", ""),
+	must("baking soda", "baking powder", "3 tsp per tsp", "Reduce the salt; the bake is less browned"),
+	must("vanilla extract", "maple syrup", "1 tsp per tsp", "", TraitSugar),
+	must("vanilla extract", "almond extract", "1/2 tsp per tsp", "Much stronger"),
+
+	must("soy sauce", "tamari", "1 tbsp per tbsp", "Usually wheat-free; check the label", TraitLegume),
+	must("soy sauce", "coconut aminos", "1 tbsp per tbsp", "Sweeter and less salty; add a pinch of salt"),
+	must("fish sauce", "soy sauce and lime juice", "1 tbsp soy sauce plus a squeeze of lime per tbsp", "", TraitGluten, TraitLegume),
+	must("fish sauce", "seaweed broth", "2 tbsp per tbsp", "Simmer kombu with a little salt"),
+	must("worcestershire sauce", "soy sauce and vinegar", "1 tbsp soy sauce plus 1/4 tsp vinegar per tbsp", "", TraitGluten, TraitLegume),
+
+	must("white wine", "chicken stock and lemon juice", "1 cup stock plus 1 tsp lemon juice per cup", "", TraitMeat),
+	must("white wine", "vegetable stock and white wine vinegar", "1 cup stock plus 1 tbsp vinegar per cup", ""),
+	must("red wine", "beef stock and red wine vinegar", "1 cup stock plus 1 tbsp vinegar per cup", "", TraitMeat),
+	must("red wine", "pomegranate juice", "1 cup per cup", "Fruity; add a splash of vinegar for bite", TraitSugar),
+	must("chicken stock", "vegetable stock", "1 cup per cup", ""),
+	must("beef stock", "mushroom stock", "1 cup per cup", "Rich and savory"),
+
+	must("chicken", "extra-firm tofu", "1 lb per lb", "Press and dry it first for a good sear", TraitLegume),
+	must("chicken", "chickpeas", "1 1/2 cups per lb", "In curries and stews", TraitLegume),
+	must("chicken", "turkey", "1 lb per lb", "Leaner; cook a little less", TraitMeat),
+	must("ground beef", "lentils", "1 1/2 cups cooked per lb", "In sauces, chili and tacos", TraitLegume),
+	must("ground beef", "crumbled tempeh", "1 lb per lb", "Steam 10 minutes first to soften the taste", TraitLegume),
+	must("ground beef", "ground turkey", "1 lb per lb", "Leaner; add a little oil", TraitMeat),
+	must("bacon", "smoked tempeh", "1 lb per lb", "", TraitLegume),
+	must("bacon", "turkey bacon", "1 lb per lb", "", TraitMeat),
+	must("pancetta", "smoked mushrooms", "1 cup chopped per 4 oz", "Sauté shiitake with smoked paprika"),
+	must("shrimp", "king oyster mushrooms", "1 lb per lb", "Slice the stems into rounds and sear"),
+	must("anchovies", "capers", "1 tbsp chopped per 2 fillets", "Briny and salty"),
+
+	must("pasta", "zucchini noodles", "2 cups per 2 oz dry", "Cook briefly or serve raw"),
+	must("pasta", "rice noodles", "2 oz per 2 oz", "", TraitGrain),
+	must("rice", "cauliflower rice", "1 cup per cup cooked", "Cook 5 minutes; do not boil"),
+	must("rice", "quinoa", "1 cup per cup cooked", "More protein; rinse before cooking"),
+
+	must("lemon juice", "lime juice", "1 tbsp per tbsp", ""),
+	must("lemon juice", "white wine vinegar", "1/2 tbsp per tbsp", "Sharper"),
+	must("shallot", "red onion", "1/2 small onion per shallot", "Stronger; chop finely"),
+	must("garlic", "garlic powder", "1/8 tsp per clove", ""),
+	must("fresh herbs", "dried herbs", "1 tsp per tbsp", "Add early so they soften"),
+	must("peanut butter", "sunflower seed butter", "1 tbsp per tbsp", "Nut-free; may turn green in baking, which is harmless"),
+	must("peanut butter", "tahini", "1 tbsp per tbsp", "Less sweet; add a little honey or syrup"),
+	must("mayonnaise", "greek yogurt", "1 cup per cup", "Tangier and lighter", TraitDairy),
+	must("mayonnaise", "mashed avocado", "1 cup per cup", "In sandwiches and dressings"),
+	must("gelatin", "agar agar", "1 tsp powder per tbsp", "Sets firmer and at room temperature"),
+}
+
+func must(ingredient, name, ratio, notes string, contains ...Trait) Substitute {
+	s, err := NewSubstitute(ingredient, name, ratio, notes, contains...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
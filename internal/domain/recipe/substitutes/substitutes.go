@@ -0,0 +1,176 @@
+// Package substitutes is the knowledge base of what a cook can use in place
+// of an ingredient they do not have or do not eat, and which substitutes
+// suit a diet.
+package substitutes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Trait is something a substitute contains that some diets rule out
+type Trait string
+
+const (
+	TraitMeat      Trait = "meat"
+	TraitPork      Trait = "pork"
+	TraitFish      Trait = "fish"
+	TraitShellfish Trait = "shellfish"
+	TraitDairy     Trait = "dairy"
+	TraitEgg       Trait = "egg"
+	TraitHoney     Trait = "honey"
+	TraitGluten    Trait = "gluten"
+	TraitGrain     Trait = "grain"
+	TraitLegume    Trait = "legume"
+	TraitSugar     Trait = "sugar"
+	TraitAlcohol   Trait = "alcohol"
+)
+
+// ruledOut are the traits each dietary restriction excludes, keyed by the
+// user dietary restriction names
+var ruledOut = map[string][]Trait{
+	"vegetarian":  {TraitMeat, TraitPork, TraitFish, TraitShellfish},
+	"vegan":       {TraitMeat, TraitPork, TraitFish, TraitShellfish, TraitDairy, TraitEgg, TraitHoney},
+	"gluten_free": {TraitGluten},
+	"dairy_free":  {TraitDairy},
+	"keto":        {TraitSugar, TraitGrain, TraitGluten},
+	"paleo":       {TraitGrain, TraitGluten, TraitDairy, TraitLegume, TraitSugar},
+	"halal":       {TraitPork, TraitAlcohol},
+	"kosher":      {TraitPork, TraitShellfish},
+}
+
+// traits are every trait, in the order models are told them
+var traits = []Trait{
+	TraitMeat, TraitPork, TraitFish, TraitShellfish, TraitDairy, TraitEgg,
+	TraitHoney, TraitGluten, TraitGrain, TraitLegume, TraitSugar, TraitAlcohol,
+}
+
+// Traits lists the trait names, for prompts and clients
+func Traits() []string {
+	names := make([]string, len(traits))
+	for i, t := range traits {
+		names[i] = string(t)
+	}
+	return names
+}
+
+// ParseTrait validates a trait name
+func ParseTrait(s string) (Trait, bool) {
+	t := Trait(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range traits {
+		if t == known {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// Substitute is one thing to use in place of an ingredient. Ratio says how
+// much of it replaces the ingredient, as in "3/4 cup per cup". Source is
+// where a stored substitute came from: the built-in table or a model.
+type Substitute struct {
+	Ingredient string
+	Name       string
+	Ratio      string
+	Notes      string
+	Contains   []Trait
+	Source     string
+}
+
+// NewSubstitute creates a substitute, keyed by the ingredient's normalized
+// name
+func NewSubstitute(ingredient, name, ratio, notes string, contains ...Trait) (Substitute, error) {
+	s := Substitute{
+		Ingredient: Key(ingredient),
+		Name:       strings.TrimSpace(name),
+		Ratio:      strings.TrimSpace(ratio),
+		Notes:      strings.TrimSpace(notes),
+		Contains:   contains,
+	}
+	if s.Ingredient == "" || s.Name == "" {
+		return Substitute{}, fmt.Errorf("a substitute needs an ingredient and a name")
+	}
+	if Key(s.Name) == s.Ingredient {
+		return Substitute{}, fmt.Errorf("%q cannot substitute for itself", s.Name)
+	}
+	return s, nil
+}
+
+// Suits reports whether the substitute fits every one of the dietary
+// restrictions. Unknown restrictions rule nothing out.
+func (s Substitute) Suits(restrictions []string) bool {
+	for _, restriction := range restrictions {
+		for _, trait := range ruledOut[restriction] {
+			for _, has := range s.Contains {
+				if has == trait {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+var spaces = regexp.MustCompile(`[^a-z0-9']+`)
+
+// Key normalizes an ingredient name for lookup: lower case, single spaced
+func Key(name string) string {
+	return strings.TrimSpace(spaces.ReplaceAllString(strings.ToLower(name), " "))
+}
+
+// Table is a knowledge base of substitutes grouped by ingredient
+type Table struct {
+	byIngredient map[string][]Substitute
+	// keys are the ingredients, longest first, so "buttermilk" and "brown
+	// sugar" match before "butter" and "sugar"
+	keys     []string
+	patterns map[string]*regexp.Regexp
+}
+
+// NewTable creates a table of substitutes, keeping their order within an
+// ingredient
+func NewTable(subs []Substitute) *Table {
+	t := &Table{byIngredient: map[string][]Substitute{}, patterns: map[string]*regexp.Regexp{}}
+	for _, s := range subs {
+		if _, ok := t.byIngredient[s.Ingredient]; !ok {
+			t.keys = append(t.keys, s.Ingredient)
+			t.patterns[s.Ingredient] = regexp.MustCompile(`\b` + regexp.QuoteMeta(s.Ingredient) + `(?:e?s)?\b`)
+		}
+		t.byIngredient[s.Ingredient] = append(t.byIngredient[s.Ingredient], s)
+	}
+	sort.SliceStable(t.keys, func(i, j int) bool { return len(t.keys[i]) > len(t.keys[j]) })
+	return t
+}
+
+// Len is the number of ingredients in the table
+func (t *Table) Len() int {
+	return len(t.keys)
+}
+
+// Lookup finds the substitutes for an ingredient name, matching the most
+// specific ingredient the name mentions: "2 large eggs, beaten" finds egg.
+// It returns the ingredient matched.
+func (t *Table) Lookup(name string) (string, []Substitute, bool) {
+	key := Key(name)
+	if subs, ok := t.byIngredient[key]; ok {
+		return key, subs, true
+	}
+	for _, k := range t.keys {
+		if t.patterns[k].MatchString(key) {
+			return k, t.byIngredient[k], true
+		}
+	}
+	return "", nil, false
+}
+
+// Default returns the table of built-in substitutes
+func Default() *Table {
+	return reference
+}
+
+// Reference returns the built-in substitutes
+func Reference() []Substitute {
+	return append([]Substitute(nil), builtIn...)
+}
@@ -0,0 +1,51 @@
+package substitutes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupMatchesMostSpecificIngredient(t *testing.T) {
+	table := Default()
+
+	for name, want := range map[string]string{
+		"2 large eggs, beaten":     "egg",
+		"Unsalted Butter":          "butter",
+		"buttermilk":               "buttermilk",
+		"smooth peanut butter":     "peanut butter",
+		"packed light brown sugar": "brown sugar",
+		"All-Purpose Flour":        "all purpose flour",
+		"low-sodium chicken stock": "chicken stock",
+	} {
+		matched, subs, ok := table.Lookup(name)
+		require.True(t, ok, name)
+		assert.Equal(t, want, matched, name)
+		assert.NotEmpty(t, subs, name)
+	}
+
+	_, _, ok := table.Lookup("eggplant")
+	assert.False(t, ok, "egg must not match eggplant")
+}
+
+func TestSuitsDietaryRestrictions(t *testing.T) {
+	ghee := must("butter", "ghee", "", "", TraitDairy)
+	oil := must("butter", "coconut oil", "", "")
+	honey := must("sugar", "honey", "", "", TraitHoney, TraitSugar)
+
+	assert.False(t, ghee.Suits([]string{"vegan"}))
+	assert.False(t, ghee.Suits([]string{"gluten_free", "dairy_free"}))
+	assert.True(t, ghee.Suits([]string{"vegetarian"}))
+	assert.True(t, oil.Suits([]string{"vegan", "keto", "paleo"}))
+	assert.False(t, honey.Suits([]string{"vegan"}))
+	assert.False(t, honey.Suits([]string{"keto"}))
+	assert.True(t, honey.Suits([]string{"vegetarian", "unknown"}))
+}
+
+func TestNewSubstituteRejectsItself(t *testing.T) {
+	_, err := NewSubstitute("Butter", "butter", "", "")
+	assert.Error(t, err)
+	_, err = NewSubstitute("", "olive oil", "", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,47 @@
+package ticket
+
+import "bytes"
+
+// ESC/POS commands understood by Epson TM and compatible printers
+var (
+	escInit         = []byte{0x1b, '@'}
+	escCenter       = []byte{0x1b, 'a', 1}
+	escLeft         = []byte{0x1b, 'a', 0}
+	escBoldOn       = []byte{0x1b, 'E', 1}
+	escBoldOff      = []byte{0x1b, 'E', 0}
+	escDoubleSize   = []byte{0x1d, '!', 0x11}
+	escNormalSize   = []byte{0x1d, '!', 0x00}
+	escFeedAndCut   = []byte{0x1d, 'V', 66, 3}
+	escCodePageUSA  = []byte{0x1b, 't', 0}
+	escFeedTailRows = []byte{0x1b, 'd', 3}
+)
+
+// ESCPOS renders the ticket as a raw ESC/POS job: title centred at double
+// size, station headings in bold, then a feed and partial cut
+func ESCPOS(t Ticket, columns int) []byte {
+	var b bytes.Buffer
+	b.Write(escInit)
+	b.Write(escCodePageUSA)
+	for _, row := range Layout(t, columns) {
+		switch row.Style {
+		case StyleTitle:
+			b.Write(escCenter)
+			b.Write(escDoubleSize)
+			b.WriteString(row.Text)
+			b.WriteByte('\n')
+			b.Write(escNormalSize)
+			b.Write(escLeft)
+		case StyleHeading:
+			b.Write(escBoldOn)
+			b.WriteString(row.Text)
+			b.WriteByte('\n')
+			b.Write(escBoldOff)
+		default:
+			b.WriteString(row.Text)
+			b.WriteByte('\n')
+		}
+	}
+	b.Write(escFeedTailRows)
+	b.Write(escFeedAndCut)
+	return b.Bytes()
+}
@@ -0,0 +1,59 @@
+package ticket
+
+import (
+	"github.com/alchemorsel/v3/internal/domain/recipe/pdfdoc"
+)
+
+// The PDF is one page 80mm wide, as long as the ticket, set in the
+// standard Courier fonts so it prints on a thermal printer's PDF driver at
+// the same layout as the ESC/POS slip
+const (
+	pdfPageWidth = 226.77 // 80mm in points
+	pdfMargin    = 6.0
+	pdfLeading   = 1.25
+)
+
+// PDF renders the ticket as a single page PDF
+func PDF(t Ticket, columns int) []byte {
+	if columns < MinColumns || columns > MaxColumns {
+		columns = DefaultColumns
+	}
+	// Courier glyphs are 0.6 em wide, so this size fits the columns
+	size := (pdfPageWidth - 2*pdfMargin) / (0.6 * float64(columns))
+	rows := Layout(t, columns)
+
+	height := 2 * pdfMargin
+	for _, row := range rows {
+		height += rowSize(row, size) * pdfLeading
+	}
+
+	doc := pdfdoc.New()
+	page := doc.AddPage(pdfPageWidth, height)
+	y := height - pdfMargin
+	for _, row := range rows {
+		rowSz := rowSize(row, size)
+		y -= rowSz * pdfLeading
+		if row.Text == "" {
+			continue
+		}
+		font, x := pdfdoc.Courier, pdfMargin
+		switch row.Style {
+		case StyleTitle:
+			font = pdfdoc.CourierBold
+			x = (pdfPageWidth - font.Width(rowSz, row.Text)) / 2
+		case StyleHeading:
+			font = pdfdoc.CourierBold
+		}
+		page.Text(font, rowSz, x, y+rowSz*0.25, row.Text)
+	}
+	return doc.Bytes()
+}
+
+// rowSize is the font size of a row; titles print double size as on the
+// thermal slip
+func rowSize(row Row, size float64) float64 {
+	if row.Style == StyleTitle {
+		return 2 * size
+	}
+	return size
+}
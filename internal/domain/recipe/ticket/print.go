@@ -0,0 +1,195 @@
+package ticket
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultColumns fits font A on an 80mm printer
+	DefaultColumns = 42
+	// MinColumns fits a 58mm printer
+	MinColumns = 32
+	// MaxColumns fits font B on an 80mm printer
+	MaxColumns = 64
+	// weightWidth is the right hand column ingredient weights print in
+	weightWidth = 9
+)
+
+// Style is how a printed row is emphasised
+type Style int
+
+const (
+	StyleNormal Style = iota
+	// StyleTitle rows print double size, so they hold half the columns
+	StyleTitle
+	StyleHeading
+)
+
+// Row is one printed line of a ticket
+type Row struct {
+	Text  string
+	Style Style
+}
+
+// Layout wraps a ticket into rows of at most columns characters, the same
+// for every output so the PDF matches the printed slip
+func Layout(t Ticket, columns int) []Row {
+	if columns < MinColumns || columns > MaxColumns {
+		columns = DefaultColumns
+	}
+	var rows []Row
+	add := func(style Style, text string) {
+		rows = append(rows, Row{Text: text, Style: style})
+	}
+
+	for _, line := range wrap(strings.ToUpper(printable(t.Title)), columns/2, "") {
+		add(StyleTitle, line)
+	}
+	portions := fmt.Sprintf("PORTIONS %d", t.Portions)
+	if t.Scale != 1 {
+		scale := "x" + strconv.FormatFloat(math.Round(t.Scale*100)/100, 'f', -1, 64)
+		portions += strings.Repeat(" ", max(1, columns-len(portions)-len(scale))) + scale
+	}
+	add(StyleNormal, portions)
+	add(StyleNormal, strings.Repeat("=", columns))
+
+	for i, section := range t.Sections {
+		if i > 0 {
+			add(StyleNormal, "")
+		}
+		add(StyleHeading, "[ "+strings.ToUpper(string(section.Station))+" ]")
+		for _, line := range section.Ingredients {
+			name := printable(line.Name)
+			if line.Optional {
+				name = "(opt) " + name
+			}
+			weight := "--"
+			if line.Weighed {
+				weight = FormatGrams(line.Grams)
+			} else {
+				name += " (" + printable(line.Measure) + ")"
+			}
+			wrapped := wrap(name, columns-weightWidth-1, "  ")
+			for j, text := range wrapped {
+				if j == len(wrapped)-1 {
+					text = text + strings.Repeat(" ", max(1, columns-len(text)-len(weight))) + weight
+				}
+				add(StyleNormal, text)
+			}
+		}
+		if len(section.Ingredients) > 0 && len(section.Steps) > 0 {
+			add(StyleNormal, strings.Repeat("-", columns))
+		}
+		for _, step := range section.Steps {
+			text := fmt.Sprintf("%d. %s", step.Number, printable(step.Text))
+			if cue := stepCue(step); cue != "" {
+				text += " [" + cue + "]"
+			}
+			for _, line := range wrap(text, columns, "   ") {
+				add(StyleNormal, line)
+			}
+		}
+	}
+
+	add(StyleNormal, strings.Repeat("=", columns))
+	if t.Unweighed > 0 {
+		for _, line := range wrap("-- no weight on file, weigh out", columns, "   ") {
+			add(StyleNormal, line)
+		}
+	}
+	return rows
+}
+
+// FormatGrams prints a weight in grams, or kilograms from 1000 g
+func FormatGrams(grams float64) string {
+	if grams >= 1000 {
+		return strconv.FormatFloat(math.Round(grams/10)/100, 'f', -1, 64) + " kg"
+	}
+	return strconv.FormatFloat(grams, 'f', -1, 64) + " g"
+}
+
+func stepCue(step Step) string {
+	var cues []string
+	if step.Duration > 0 {
+		cues = append(cues, fmt.Sprintf("%d min", int(math.Ceil(step.Duration.Minutes()))))
+	}
+	if step.Temperature != nil {
+		cues = append(cues, fmt.Sprintf("%.0fC", step.Temperature.ToCelsius()))
+	}
+	return strings.Join(cues, ", ")
+}
+
+// wrap breaks text into lines of at most width characters at spaces,
+// indenting continuation lines. Words longer than a line are split.
+func wrap(text string, width int, indent string) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for {
+			limit := width
+			if len(lines) > 0 {
+				limit -= len(indent)
+			}
+			switch {
+			case line == "" && len(word) <= limit:
+				line = word
+			case line != "" && len(line)+1+len(word) <= limit:
+				line += " " + word
+			case line != "":
+				lines = append(lines, line)
+				line = ""
+				continue
+			default:
+				lines = append(lines, word[:limit])
+				word = word[limit:]
+				continue
+			}
+			break
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	for i := 1; i < len(lines); i++ {
+		lines[i] = indent + lines[i]
+	}
+	return lines
+}
+
+// accents folds the letters recipes commonly use; thermal printers and
+// the PDF's standard fonts print plain ASCII reliably
+var accents = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ä", "a", "ã", "a", "å", "a", "æ", "ae",
+	"ç", "c", "è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i", "ñ", "n",
+	"ò", "o", "ó", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o", "œ", "oe",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ß", "ss",
+	"À", "A", "Á", "A", "Â", "A", "Ä", "A", "Ç", "C", "È", "E", "É", "E",
+	"Ê", "E", "Î", "I", "Ñ", "N", "Ô", "O", "Ö", "O", "Ü", "U",
+	"°", "", "½", "1/2", "¼", "1/4", "¾", "3/4", "–", "-", "—", "-",
+	"‘", "'", "’", "'", "“", `"`, "”", `"`, "…", "...",
+)
+
+// printable folds text to ASCII, replacing what it cannot fold with "?"
+func printable(text string) string {
+	text = accents.Replace(text)
+	var b strings.Builder
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			b.WriteByte(' ')
+		case r < ' ' || r == 0x7f:
+		case r > 0x7f:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,235 @@
+// Package ticket lays a recipe out as a kitchen ticket: scaled to the batch
+// being cooked, every ingredient in grams, and split by the station that
+// uses it, so each cook on the line reads only their part. Tickets print
+// on 80mm thermal printers as ESC/POS or as a narrow PDF.
+package ticket
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/units"
+	"github.com/google/uuid"
+)
+
+// MaxPortions bounds the batch a ticket is scaled to
+const MaxPortions = 1000
+
+// Station is a section of the kitchen line
+type Station string
+
+const (
+	StationPrep  Station = "prep"
+	StationSaute Station = "saute"
+	StationGrill Station = "grill"
+	StationFry   Station = "fry"
+	StationOven  Station = "oven"
+	StationCold  Station = "cold"
+)
+
+// stationRules are tried in order, so a step that chops and then sears
+// belongs to the hot station
+var stationRules = []struct {
+	station Station
+	words   *regexp.Regexp
+}{
+	{StationFry, words(`deep[- ]fry|deep[- ]fried|fryer|fry in (?:hot )?oil`)},
+	{StationGrill, words(`grill|grilled|griddle|char|chargrill|broil|barbecue|bbq`)},
+	{StationOven, words(`bake|baked|roast|roasted|oven|preheat`)},
+	{StationSaute, words(`saute|sauteed|sear|seared|fry|fried|pan|skillet|wok|simmer|boil|poach|blanch|braise|reduce|steam|melt|stir[- ]fry|toast`)},
+	{StationCold, words(`chill|chilled|refrigerate|freeze|dress|garnish|plate|assemble`)},
+	{StationPrep, words(`chop|dice|slice|mince|grate|peel|whisk|mix|combine|knead|marinate|season|toss`)},
+}
+
+func words(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\b(?:` + pattern + `)\b`)
+}
+
+// StationOf returns the station a step's text calls for. Steps that name
+// none stay at the station before them.
+func StationOf(text string, previous Station) Station {
+	text = strings.ReplaceAll(text, "é", "e")
+	for _, rule := range stationRules {
+		if rule.words.MatchString(text) {
+			return rule.station
+		}
+	}
+	if previous == "" {
+		return StationPrep
+	}
+	return previous
+}
+
+// Ticket is a recipe laid out for the line
+type Ticket struct {
+	RecipeID uuid.UUID
+	Title    string
+	Portions int
+	// Scale is the factor from the recipe's servings to Portions
+	Scale    float64
+	Sections []Section
+	// Unweighed counts ingredients with no known weight; they keep their
+	// recipe measure so the cook can weigh them out
+	Unweighed int
+}
+
+// Section is what one station needs and does
+type Section struct {
+	Station     Station
+	Ingredients []Line
+	Steps       []Step
+}
+
+// Line is one ingredient, scaled. Grams is zero when Weighed is false.
+type Line struct {
+	Name     string
+	Grams    float64
+	Weighed  bool
+	Measure  string
+	Optional bool
+}
+
+// Step is one recipe step at its station
+type Step struct {
+	Number      int
+	Text        string
+	Duration    time.Duration
+	Temperature *recipe.Temperature
+}
+
+// Build scales the recipe to portions, zero meaning the recipe's own
+// servings, and splits it by station. Each ingredient goes to the station
+// of the first step that mentions it, or to prep.
+func Build(r *recipe.Recipe, portions int) Ticket {
+	servings := r.Servings()
+	if servings <= 0 {
+		servings = 1
+	}
+	if portions <= 0 {
+		portions = servings
+	}
+	t := Ticket{
+		RecipeID: r.ID(),
+		Title:    r.Title(),
+		Portions: portions,
+		Scale:    float64(portions) / float64(servings),
+	}
+
+	// Sections come in the order their stations first appear
+	index := map[Station]int{}
+	section := func(station Station) *Section {
+		i, ok := index[station]
+		if !ok {
+			i = len(t.Sections)
+			index[station] = i
+			t.Sections = append(t.Sections, Section{Station: station})
+		}
+		return &t.Sections[i]
+	}
+
+	type placed struct {
+		station Station
+		text    string
+	}
+	var steps []placed
+	var station Station
+	for i, instruction := range r.Instructions() {
+		station = StationOf(instruction.Description, station)
+		number := instruction.StepNumber
+		if number == 0 {
+			number = i + 1
+		}
+		s := section(station)
+		s.Steps = append(s.Steps, Step{
+			Number:      number,
+			Text:        strings.TrimSpace(instruction.Description),
+			Duration:    instruction.Duration,
+			Temperature: instruction.Temperature,
+		})
+		steps = append(steps, placed{station, strings.ToLower(instruction.Description)})
+	}
+
+	for _, ingredient := range r.Ingredients() {
+		line := Scale(ingredient, t.Scale)
+		if !line.Weighed {
+			t.Unweighed++
+		}
+		target := StationPrep
+		for _, step := range steps {
+			if mentions(step.text, ingredient.Name) {
+				target = step.station
+				break
+			}
+		}
+		s := section(target)
+		s.Ingredients = append(s.Ingredients, line)
+	}
+	return t
+}
+
+// mentions reports whether a step names the ingredient by any word of its
+// name other than descriptors, so "beef chuck" is found in "brown the beef"
+func mentions(text, name string) bool {
+	for _, word := range strings.Fields(strings.ToLower(name)) {
+		word = stem(strings.Trim(word, ",.;:()"))
+		if len(word) < 3 || descriptors[word] {
+			continue
+		}
+		if regexp.MustCompile(`\b` + regexp.QuoteMeta(word)).MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// descriptors qualify an ingredient rather than name it, stemmed
+var descriptors = map[string]bool{
+	"fresh": true, "dried": true, "large": true, "small": true, "medium": true,
+	"boneless": true, "skinless": true, "chopped": true, "diced": true, "sliced": true,
+	"minced": true, "ground": true, "whole": true, "unsalted": true, "salted": true,
+	"extra": true, "virgin": true, "raw": true, "cooked": true, "frozen": true,
+	"grated": true, "plain": true, "light": true, "dark": true, "hot": true, "cold": true,
+}
+
+// stem drops plural endings; steps are matched on the stem as a prefix, so
+// "berr" finds both berry and berries
+func stem(word string) string {
+	switch {
+	case strings.HasSuffix(word, "oes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies")
+	case strings.HasSuffix(word, "y"):
+		return strings.TrimSuffix(word, "y")
+	case strings.HasSuffix(word, "ss"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+// Scale converts an ingredient to grams at the given factor. Ingredients
+// without a known weight keep their measure, scaled.
+func Scale(ingredient recipe.Ingredient, factor float64) Line {
+	amount := ingredient.Amount * factor
+	line := Line{Name: ingredient.Name, Optional: ingredient.Optional}
+	grams, ok := units.Grams(amount, ingredient.Unit, ingredient.Name)
+	if ok {
+		line.Grams = roundGrams(grams)
+		line.Weighed = true
+		return line
+	}
+	line.Measure = units.Format(amount, ingredient.Unit)
+	return line
+}
+
+func roundGrams(g float64) float64 {
+	if g < 10 {
+		return math.Round(g*10) / 10
+	}
+	return math.Round(g)
+}
@@ -0,0 +1,100 @@
+package ticket
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRecipe(t *testing.T) *recipe.Recipe {
+	t.Helper()
+	r, err := recipe.NewRecipe("Grilled Chicken with Herbed Rice", "", uuid.New())
+	require.NoError(t, err)
+	require.NoError(t, r.SetServings(4))
+	for _, ingredient := range []recipe.Ingredient{
+		{Name: "boneless chicken thighs", Amount: 1, Unit: recipe.MeasurementUnitPound},
+		{Name: "olive oil", Amount: 2, Unit: recipe.MeasurementUnitTablespoon},
+		{Name: "long grain rice", Amount: 1, Unit: recipe.MeasurementUnitCup},
+		{Name: "garlic", Amount: 2, Unit: recipe.MeasurementUnitClove},
+		{Name: "parsley", Amount: 1, Unit: recipe.MeasurementUnitBunch},
+	} {
+		require.NoError(t, r.AddIngredient(ingredient))
+	}
+	for _, step := range []string{
+		"Mince the garlic and chop the parsley.",
+		"Brush the thighs with olive oil and grill over high heat.",
+		"Simmer the rice until tender.",
+		"Stir through the parsley.",
+	} {
+		require.NoError(t, r.AddInstruction(recipe.Instruction{Description: step}))
+	}
+	return r
+}
+
+func TestBuildScalesToWeightsAndSplitsStations(t *testing.T) {
+	ticket := Build(newTestRecipe(t), 10)
+
+	assert.Equal(t, 2.5, ticket.Scale)
+	assert.Equal(t, 1, ticket.Unweighed)
+	require.Len(t, ticket.Sections, 3)
+
+	prep, grill, saute := ticket.Sections[0], ticket.Sections[1], ticket.Sections[2]
+	assert.Equal(t, StationPrep, prep.Station)
+	assert.Equal(t, []Line{
+		{Name: "garlic", Grams: 25, Weighed: true},
+		{Name: "parsley", Measure: "2 1/2 bunch"},
+	}, prep.Ingredients)
+
+	assert.Equal(t, StationGrill, grill.Station)
+	assert.Equal(t, []Line{
+		{Name: "boneless chicken thighs", Grams: 1134, Weighed: true},
+		{Name: "olive oil", Grams: 68, Weighed: true},
+	}, grill.Ingredients)
+
+	assert.Equal(t, StationSaute, saute.Station)
+	assert.Equal(t, []Line{{Name: "long grain rice", Grams: 503, Weighed: true}}, saute.Ingredients)
+	require.Len(t, saute.Steps, 2, "a step naming no station stays where the cook is")
+	assert.Equal(t, 4, saute.Steps[1].Number)
+}
+
+func TestLayoutFitsColumns(t *testing.T) {
+	ticket := Build(newTestRecipe(t), 10)
+	rows := Layout(ticket, MinColumns)
+
+	assert.Equal(t, StyleTitle, rows[0].Style)
+	for _, row := range rows {
+		limit := MinColumns
+		if row.Style == StyleTitle {
+			limit /= 2
+		}
+		assert.LessOrEqual(t, len(row.Text), limit, row.Text)
+	}
+
+	var text []string
+	for _, row := range rows {
+		text = append(text, row.Text)
+	}
+	printed := strings.Join(text, "\n")
+	assert.Contains(t, printed, "[ GRILL ]")
+	assert.Contains(t, printed, "1.13 kg")
+	assert.Contains(t, printed, "parsley (2 1/2 bunch)")
+	assert.Equal(t, "Creme brulee ?", printable("Crème brûlée ☕"))
+}
+
+func TestRenderers(t *testing.T) {
+	ticket := Build(newTestRecipe(t), 0)
+
+	job := ESCPOS(ticket, DefaultColumns)
+	assert.True(t, bytes.HasPrefix(job, []byte{0x1b, '@'}))
+	assert.True(t, bytes.HasSuffix(job, []byte{0x1d, 'V', 66, 3}))
+
+	doc := PDF(ticket, DefaultColumns)
+	assert.True(t, bytes.HasPrefix(doc, []byte("%PDF-1.4")))
+	assert.Contains(t, string(doc), "(GRILLED CHICKEN WITH) Tj")
+	assert.True(t, bytes.HasSuffix(doc, []byte("%%EOF\n")))
+}
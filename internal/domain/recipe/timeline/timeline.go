@@ -0,0 +1,179 @@
+// Package timeline schedules a recipe's steps backwards from the time it
+// should be served. Steps run one after another, except that a step
+// starting "Meanwhile" runs alongside the step before it, and hands-off
+// steps such as marinating or baking leave the cook free for the active
+// steps running alongside them.
+package timeline
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/instructions"
+)
+
+// DefaultStepDuration is assumed for a step that gives no time
+const DefaultStepDuration = 5 * time.Minute
+
+// Step is one recipe step as the planner sees it
+type Step struct {
+	Number   int
+	Text     string
+	Duration time.Duration
+	// HandsOff steps need no attention while they run
+	HandsOff bool
+	// Parallel steps run alongside the step before them
+	Parallel bool
+}
+
+var (
+	parallelRe = regexp.MustCompile(`(?i)^\s*(meanwhile|while|in the meantime|at the same time)\b`)
+	handsOffRe = regexp.MustCompile(`(?i)\b(marinate|marinating|rest|resting|chill|chilling|refrigerate|freeze|proof|proofing|rise|soak|soaking|bake|baking|roast|roasting|simmer|simmering|braise|braising|cool|cooling|set aside|steep|let (?:it |them )?(?:sit|stand))\b`)
+)
+
+// NewStep classifies a step from its text. A zero duration is read from
+// the text ("bake for 25 minutes") or falls back to DefaultStepDuration.
+func NewStep(number int, text string, duration time.Duration) Step {
+	if duration <= 0 {
+		if parsed := instructions.Split([]string{text}); len(parsed) > 0 {
+			duration = parsed[0].Duration
+		}
+	}
+	if duration <= 0 {
+		duration = DefaultStepDuration
+	}
+	return Step{
+		Number:   number,
+		Text:     strings.TrimSpace(text),
+		Duration: duration,
+		HandsOff: handsOffRe.MatchString(text),
+		Parallel: number > 1 && parallelRe.MatchString(text),
+	}
+}
+
+// Slot is when a step runs
+type Slot struct {
+	Step  Step
+	Start time.Time
+	End   time.Time
+}
+
+// Plan is a timed schedule of steps
+type Plan struct {
+	Slots   []Slot
+	Start   time.Time
+	ServeAt time.Time
+	// Active is the time the cook is busy
+	Active time.Duration
+}
+
+// Total is the time from the first step to serving
+func (p Plan) Total() time.Duration {
+	return p.ServeAt.Sub(p.Start)
+}
+
+// Backward schedules steps to finish exactly at serveAt, each starting as
+// late as it can
+func Backward(steps []Step, serveAt time.Time) Plan {
+	stages := stagesOf(steps)
+	plan := Plan{Slots: make([]Slot, 0, len(steps)), ServeAt: serveAt, Start: serveAt}
+
+	// Lay stages out from the last, each ending where the next begins
+	end := serveAt
+	laid := make([][]Slot, len(stages))
+	for i := len(stages) - 1; i >= 0; i-- {
+		laid[i], plan.Active = layStage(stages[i], end, plan.Active)
+		end = end.Add(-stages[i].length())
+	}
+	plan.Start = end
+	for _, slots := range laid {
+		plan.Slots = append(plan.Slots, slots...)
+	}
+	return plan
+}
+
+// Forward schedules steps as soon as possible from start
+func Forward(steps []Step, start time.Time) Plan {
+	var length time.Duration
+	for _, stage := range stagesOf(steps) {
+		length += stage.length()
+	}
+	return Backward(steps, start.Add(length))
+}
+
+// Replan schedules the steps left after the first completed ones. When
+// they can no longer finish by serveAt they start now, and behind is how
+// much later serving will be.
+func Replan(steps []Step, serveAt time.Time, completed int, now time.Time) (plan Plan, behind time.Duration) {
+	if completed < 0 {
+		completed = 0
+	}
+	if completed > len(steps) {
+		completed = len(steps)
+	}
+	remaining := append([]Step(nil), steps[completed:]...)
+	if len(remaining) > 0 {
+		// The step after the last completed one cannot run alongside it
+		remaining[0].Parallel = false
+	}
+
+	plan = Backward(remaining, serveAt)
+	if plan.Start.Before(now) {
+		plan = Forward(remaining, now)
+		behind = plan.ServeAt.Sub(serveAt)
+	}
+	return plan, behind
+}
+
+// stage is a step and the steps running alongside it
+type stage []Step
+
+func stagesOf(steps []Step) []stage {
+	var stages []stage
+	for _, step := range steps {
+		if step.Parallel && len(stages) > 0 {
+			stages[len(stages)-1] = append(stages[len(stages)-1], step)
+			continue
+		}
+		stages = append(stages, stage{step})
+	}
+	return stages
+}
+
+// length is the longer of the active steps done one at a time and the
+// longest hands-off step
+func (s stage) length() time.Duration {
+	var active, handsOff time.Duration
+	for _, step := range s {
+		if step.HandsOff {
+			if step.Duration > handsOff {
+				handsOff = step.Duration
+			}
+			continue
+		}
+		active += step.Duration
+	}
+	if handsOff > active {
+		return handsOff
+	}
+	return active
+}
+
+// layStage ends every hands-off step at end and packs the active steps
+// back to back so the last finishes at end
+func layStage(s stage, end time.Time, active time.Duration) ([]Slot, time.Duration) {
+	slots := make([]Slot, len(s))
+	cursor := end
+	for i := len(s) - 1; i >= 0; i-- {
+		step := s[i]
+		if step.HandsOff {
+			slots[i] = Slot{Step: step, Start: end.Add(-step.Duration), End: end}
+			continue
+		}
+		slots[i] = Slot{Step: step, Start: cursor.Add(-step.Duration), End: cursor}
+		cursor = cursor.Add(-step.Duration)
+		active += step.Duration
+	}
+	return slots, active
+}
@@ -0,0 +1,63 @@
+package timeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var serve = time.Date(2026, 10, 18, 19, 0, 0, 0, time.UTC)
+
+func TestNewStepClassifies(t *testing.T) {
+	marinate := NewStep(1, "Marinate the beef for 30 minutes", 0)
+	assert.Equal(t, 30*time.Minute, marinate.Duration)
+	assert.True(t, marinate.HandsOff)
+	assert.False(t, marinate.Parallel)
+
+	pancetta := NewStep(2, "Meanwhile, cook the pancetta until crispy", 0)
+	assert.Equal(t, DefaultStepDuration, pancetta.Duration)
+	assert.True(t, pancetta.Parallel)
+	assert.False(t, pancetta.HandsOff)
+
+	assert.False(t, NewStep(1, "Meanwhile, boil water", 0).Parallel, "the first step has nothing to run alongside")
+}
+
+func TestBackwardSchedulesParallelSteps(t *testing.T) {
+	steps := []Step{
+		NewStep(1, "Bake the potatoes", 40*time.Minute),
+		NewStep(2, "Meanwhile, chop the herbs", 5*time.Minute),
+		NewStep(3, "Meanwhile, whisk the dressing", 5*time.Minute),
+		NewStep(4, "Plate and serve", 5*time.Minute),
+	}
+	plan := Backward(steps, serve)
+
+	require.Len(t, plan.Slots, 4)
+	assert.Equal(t, serve.Add(-45*time.Minute), plan.Start)
+	assert.Equal(t, 15*time.Minute, plan.Active)
+	// The hands-off bake spans the stage; the active steps run one at a
+	// time and finish as it comes out
+	assert.Equal(t, serve.Add(-45*time.Minute), plan.Slots[0].Start)
+	assert.Equal(t, serve.Add(-15*time.Minute), plan.Slots[1].Start)
+	assert.Equal(t, serve.Add(-10*time.Minute), plan.Slots[2].Start)
+	assert.Equal(t, serve.Add(-5*time.Minute), plan.Slots[3].Start)
+	assert.Equal(t, serve, plan.Slots[3].End)
+}
+
+func TestReplanWhenBehind(t *testing.T) {
+	steps := []Step{
+		NewStep(1, "Marinate the beef", 30*time.Minute),
+		NewStep(2, "Grill the beef", 8*time.Minute),
+		NewStep(3, "Assemble the tacos", 5*time.Minute),
+	}
+
+	plan, behind := Replan(steps, serve, 1, serve.Add(-20*time.Minute))
+	assert.Zero(t, behind)
+	assert.Equal(t, serve.Add(-13*time.Minute), plan.Start)
+
+	plan, behind = Replan(steps, serve, 1, serve.Add(-10*time.Minute))
+	assert.Equal(t, 3*time.Minute, behind)
+	assert.Equal(t, serve.Add(-10*time.Minute), plan.Start)
+	assert.Equal(t, serve.Add(3*time.Minute), plan.ServeAt)
+}
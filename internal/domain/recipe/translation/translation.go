@@ -0,0 +1,203 @@
+// Package translation describes the languages recipes are written and
+// translated in, and the fields of a recipe that are translated one by
+// one: the title, the description, each ingredient and each step. Every
+// translated field records whether a machine or a person wrote it and a
+// hash of the source text, so a changed recipe shows which translations
+// are stale.
+package translation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a language recipes can be generated and translated in
+type Language struct {
+	Tag        string `json:"tag"`
+	Name       string `json:"name"`
+	NativeName string `json:"native_name"`
+}
+
+// Languages are the supported languages, by BCP 47 tag
+var Languages = []Language{
+	{Tag: "en", Name: "English", NativeName: "English"},
+	{Tag: "es", Name: "Spanish", NativeName: "Español"},
+	{Tag: "fr", Name: "French", NativeName: "Français"},
+	{Tag: "de", Name: "German", NativeName: "Deutsch"},
+	{Tag: "it", Name: "Italian", NativeName: "Italiano"},
+	{Tag: "pt", Name: "Portuguese", NativeName: "Português"},
+	{Tag: "nl", Name: "Dutch", NativeName: "Nederlands"},
+	{Tag: "pl", Name: "Polish", NativeName: "Polski"},
+	{Tag: "tr", Name: "Turkish", NativeName: "Türkçe"},
+	{Tag: "hi", Name: "Hindi", NativeName: "हिन्दी"},
+	{Tag: "ja", Name: "Japanese", NativeName: "日本語"},
+	{Tag: "ko", Name: "Korean", NativeName: "한국어"},
+	{Tag: "zh", Name: "Chinese", NativeName: "中文"},
+}
+
+// Lookup finds a supported language by tag. Regional tags fall back to
+// their language, so pt-BR is Portuguese.
+func Lookup(tag string) (Language, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i > 0 {
+		tag = tag[:i]
+	}
+	for _, language := range Languages {
+		if language.Tag == tag {
+			return language, true
+		}
+	}
+	return Language{}, false
+}
+
+// Name is the English name of a language, for prompts and messages, or
+// the tag itself when it is not supported
+func Name(tag string) string {
+	if language, ok := Lookup(tag); ok {
+		return language.Name
+	}
+	return tag
+}
+
+// FromAcceptLanguage picks the supported language a browser prefers from
+// an Accept-Language header, like "fr-CH, fr;q=0.9, en;q=0.8"
+func FromAcceptLanguage(header string) (Language, bool) {
+	type choice struct {
+		tag     string
+		quality float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		c := choice{tag: strings.TrimSpace(fields[0]), quality: 1}
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					c.quality = q
+				}
+			}
+		}
+		if c.tag != "" && c.tag != "*" && c.quality > 0 {
+			choices = append(choices, c)
+		}
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].quality > choices[j].quality })
+
+	for _, c := range choices {
+		if language, ok := Lookup(c.tag); ok {
+			return language, true
+		}
+	}
+	return Language{}, false
+}
+
+// Field keys of a recipe's translatable text. Ingredients and steps are
+// numbered from 1, like "steps.2".
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	ingredientPrefix = "ingredients."
+	stepPrefix       = "steps."
+)
+
+// IngredientField is the key of the nth ingredient, from 1
+func IngredientField(n int) string {
+	return ingredientPrefix + strconv.Itoa(n)
+}
+
+// StepField is the key of the nth step, from 1
+func StepField(n int) string {
+	return stepPrefix + strconv.Itoa(n)
+}
+
+// Provenance says who wrote a translated field
+type Provenance string
+
+const (
+	// ProvenanceMachine fields were translated by the AI
+	ProvenanceMachine Provenance = "machine"
+	// ProvenanceHuman fields were written or corrected by a person, and are
+	// never replaced by machine translation
+	ProvenanceHuman Provenance = "human"
+)
+
+// Source is the recipe text in its own language
+type Source struct {
+	Title       string
+	Description string
+	Ingredients []string
+	Steps       []string
+}
+
+// SourceField is one translatable field of a recipe
+type SourceField struct {
+	Field string
+	Text  string
+}
+
+// Fields lists the recipe's translatable fields in reading order,
+// skipping blank ones
+func (s Source) Fields() []SourceField {
+	var fields []SourceField
+	add := func(field, text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			fields = append(fields, SourceField{Field: field, Text: text})
+		}
+	}
+	add(FieldTitle, s.Title)
+	add(FieldDescription, s.Description)
+	for i, ingredient := range s.Ingredients {
+		add(IngredientField(i+1), ingredient)
+	}
+	for i, step := range s.Steps {
+		add(StepField(i+1), step)
+	}
+	return fields
+}
+
+// Field finds one translatable field by key
+func (s Source) Field(key string) (SourceField, error) {
+	for _, field := range s.Fields() {
+		if field.Field == key {
+			return field, nil
+		}
+	}
+	return SourceField{}, fmt.Errorf("recipe has no field %q to translate", key)
+}
+
+// Hash identifies the source text a translation was made from
+func Hash(text string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(text)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Translated is one translated field
+type Translated struct {
+	Field      string
+	Text       string
+	SourceHash string
+	Provenance Provenance
+}
+
+// Stale reports whether the source text changed since the translation
+func (t Translated) Stale(source SourceField) bool {
+	return t.SourceHash != Hash(source.Text)
+}
+
+// Pending lists the source fields machine translation should fill in:
+// those never translated, and machine translations of older source text.
+// Human translations are kept even when stale, for a person to update.
+func Pending(source []SourceField, existing map[string]Translated) []SourceField {
+	var pending []SourceField
+	for _, field := range source {
+		t, ok := existing[field.Field]
+		if !ok || (t.Provenance == ProvenanceMachine && t.Stale(field)) {
+			pending = append(pending, field)
+		}
+	}
+	return pending
+}
@@ -0,0 +1,44 @@
+package translation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromAcceptLanguage(t *testing.T) {
+	language, ok := FromAcceptLanguage("sv;q=0.95, pt-BR;q=0.9, en;q=0.8, *;q=0.1")
+	require.True(t, ok)
+	assert.Equal(t, "pt", language.Tag, "unsupported languages are skipped and regions fall back")
+
+	language, ok = FromAcceptLanguage("en;q=0.5, de")
+	require.True(t, ok)
+	assert.Equal(t, "de", language.Tag)
+
+	_, ok = FromAcceptLanguage("sv, *")
+	assert.False(t, ok)
+}
+
+func TestPendingKeepsHumanTranslations(t *testing.T) {
+	source := Source{
+		Title:       "Lentil soup",
+		Ingredients: []string{"1 cup red lentils", "  "},
+		Steps:       []string{"Rinse the lentils.", "Simmer for 20 minutes."},
+	}.Fields()
+	require.Len(t, source, 4, "blank text is not translated")
+	assert.Equal(t, StepField(2), source[3].Field)
+
+	existing := map[string]Translated{
+		FieldTitle:         {Text: "Sopa de lentejas", SourceHash: Hash("Lentil soup"), Provenance: ProvenanceMachine},
+		IngredientField(1): {Text: "1 taza de lentejas", SourceHash: Hash("1 cup lentils"), Provenance: ProvenanceHuman},
+		StepField(1):       {Text: "Lava", SourceHash: Hash("Wash the lentils."), Provenance: ProvenanceMachine},
+	}
+
+	var keys []string
+	for _, field := range Pending(source, existing) {
+		keys = append(keys, field.Field)
+	}
+	assert.Equal(t, []string{StepField(1), StepField(2)}, keys)
+	assert.True(t, existing[IngredientField(1)].Stale(source[1]), "stale human edits are kept but flagged")
+}
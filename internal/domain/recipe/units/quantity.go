@@ -0,0 +1,122 @@
+package units
+
+import (
+	"math"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+)
+
+// Quantity is an ingredient amount in a canonical unit. No unit counts
+// pieces, as in "3 eggs".
+type Quantity struct {
+	Amount float64
+	Unit   recipe.MeasurementUnit
+}
+
+// rung is a unit on a ladder: size is how many of the ladder's first unit
+// it holds, and amounts reaching from, in the first unit, use it
+type rung struct {
+	unit recipe.MeasurementUnit
+	size float64
+	from float64
+}
+
+// ladders are the units of one measuring system from smallest to largest.
+// Scaling stays within a ladder, so metric recipes stay metric: an eighth
+// of 1 cup is 2 tbsp, and 4 times 300 g is 1.2 kg.
+var ladders = [][]rung{
+	{{recipe.MeasurementUnitTeaspoon, 1, 0}, {recipe.MeasurementUnitTablespoon, 3, 3}, {recipe.MeasurementUnitCup, 48, 12}},
+	{{recipe.MeasurementUnitMilliliter, 1, 0}, {recipe.MeasurementUnitLiter, 1000, 1000}},
+	{{recipe.MeasurementUnitGram, 1, 0}, {recipe.MeasurementUnitKilogram, 1000, 1000}},
+	{{recipe.MeasurementUnitOunce, 1, 0}, {recipe.MeasurementUnitPound, 16, 16}},
+}
+
+// ParseQuantity normalizes an amount and unit given as text, as AI models
+// and imports give them: "1 1/2" and "Tablespoons", or "a pinch" and no
+// unit. It reports false when the amount is not a number, such as "to
+// taste", or the unit is not one recipes use.
+func ParseQuantity(amount, unit string) (Quantity, bool) {
+	value, canonical, ok := ingredients.ParseQuantity(strings.TrimSpace(amount + " " + unit))
+	if !ok {
+		return Quantity{}, false
+	}
+	return Quantity{Amount: value, Unit: canonical}, true
+}
+
+// Scale multiplies the quantity by factor and then normalizes it
+func (q Quantity) Scale(factor float64) Quantity {
+	return Quantity{Amount: q.Amount * factor, Unit: q.Unit}.Normalize()
+}
+
+// Normalize moves the quantity to the largest unit of its measuring system
+// it reads naturally in, 48 tsp to 1 cup, and rounds it to what a cook can
+// measure: whole grams and millilitres, and kitchen fractions otherwise.
+func (q Quantity) Normalize() Quantity {
+	if q.Amount <= 0 {
+		return q
+	}
+	for _, ladder := range ladders {
+		base, ok := inBase(q, ladder)
+		if !ok {
+			continue
+		}
+		unit := ladder[0]
+		for _, r := range ladder[1:] {
+			if base >= r.from {
+				unit = r
+			}
+		}
+		return Quantity{Amount: round(base/unit.size, unit.unit), Unit: unit.unit}
+	}
+	return Quantity{Amount: round(q.Amount, q.Unit), Unit: q.Unit}
+}
+
+// String renders the quantity the way recipes give it
+func (q Quantity) String() string {
+	return Format(q.Amount, q.Unit)
+}
+
+// inBase converts the quantity to the ladder's first unit
+func inBase(q Quantity, ladder []rung) (float64, bool) {
+	for _, r := range ladder {
+		if r.unit == q.Unit {
+			return q.Amount * r.size, true
+		}
+	}
+	return 0, false
+}
+
+// round keeps grams and millilitres whole, to the nearest 5 from 100, and
+// other units to the nearest eighth or third. Nothing rounds away to zero.
+func round(amount float64, unit recipe.MeasurementUnit) float64 {
+	var rounded float64
+	switch unit {
+	case recipe.MeasurementUnitGram, recipe.MeasurementUnitMilliliter:
+		switch {
+		case amount >= 100:
+			rounded = math.Round(amount/5) * 5
+		case amount >= 10:
+			rounded = math.Round(amount)
+		default:
+			rounded = math.Round(amount*2) / 2
+		}
+		if rounded == 0 {
+			rounded = 0.5
+		}
+	case recipe.MeasurementUnitKilogram, recipe.MeasurementUnitLiter:
+		rounded = math.Round(amount*100) / 100
+	default:
+		eighths := math.Round(amount*8) / 8
+		thirds := math.Round(amount*3) / 3
+		rounded = eighths
+		if math.Abs(thirds-amount) < math.Abs(eighths-amount) {
+			rounded = thirds
+		}
+		if rounded == 0 {
+			rounded = 1.0 / 8
+		}
+	}
+	return rounded
+}
@@ -0,0 +1,38 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantityScale(t *testing.T) {
+	tsp := Quantity{Amount: 1, Unit: recipe.MeasurementUnitTeaspoon}
+	assert.Equal(t, "1 tbsp", tsp.Scale(3).String())
+	assert.Equal(t, "1 cup", tsp.Scale(48).String())
+	assert.Equal(t, "1/3 tsp", tsp.Scale(1.0/3).String())
+
+	cup := Quantity{Amount: 1, Unit: recipe.MeasurementUnitCup}
+	assert.Equal(t, "2 tbsp", cup.Scale(1.0/8).String())
+	assert.Equal(t, "2 2/3 cup", cup.Scale(8.0/3).String())
+
+	assert.Equal(t, "1.2 kg", Quantity{Amount: 300, Unit: recipe.MeasurementUnitGram}.Scale(4).String())
+	assert.Equal(t, "375 g", Quantity{Amount: 1.5, Unit: recipe.MeasurementUnitKilogram}.Scale(0.25).String())
+	assert.Equal(t, "1 1/2 lb", Quantity{Amount: 8, Unit: recipe.MeasurementUnitOunce}.Scale(3).String())
+	assert.Equal(t, "4 1/2", Quantity{Amount: 3}.Scale(1.5).String())
+	assert.Equal(t, "1/8 pinch", Quantity{Amount: 1, Unit: recipe.MeasurementUnitPinch}.Scale(0.01).String())
+}
+
+func TestParseQuantity(t *testing.T) {
+	q, ok := ParseQuantity("1 1/2", "Tablespoons")
+	assert.True(t, ok)
+	assert.Equal(t, Quantity{Amount: 1.5, Unit: recipe.MeasurementUnitTablespoon}, q)
+
+	q, ok = ParseQuantity("a pinch", "")
+	assert.True(t, ok)
+	assert.Equal(t, Quantity{Amount: 1, Unit: recipe.MeasurementUnitPinch}, q)
+
+	_, ok = ParseQuantity("to taste", "")
+	assert.False(t, ok)
+}
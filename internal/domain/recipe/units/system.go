@@ -0,0 +1,119 @@
+package units
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+)
+
+// System is a measuring system recipes can be shown in. The names match
+// the user measurement preference.
+type System string
+
+const (
+	// SystemAsWritten leaves amounts in the units the author used
+	SystemAsWritten System = ""
+	SystemMetric    System = "metric"
+	SystemImperial  System = "imperial"
+)
+
+// ParseSystem validates a measuring system name; blank and "original"
+// keep the recipe as written
+func ParseSystem(s string) (System, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "original":
+		return SystemAsWritten, true
+	case "metric":
+		return SystemMetric, true
+	case "imperial", "us":
+		return SystemImperial, true
+	}
+	return SystemAsWritten, false
+}
+
+// In converts weights and volumes to the system, grams and millilitres for
+// metric and ounces and cups for imperial, and normalizes the result.
+// Counts such as eggs or cloves are the same in both, and as written only
+// normalizes.
+func (q Quantity) In(system System) Quantity {
+	if system == SystemAsWritten {
+		return q.Normalize()
+	}
+	var to recipe.MeasurementUnit
+	switch DimensionOf(q.Unit) {
+	case DimensionWeight:
+		to = recipe.MeasurementUnitGram
+		if system == SystemImperial {
+			to = recipe.MeasurementUnitOunce
+		}
+	case DimensionVolume:
+		to = recipe.MeasurementUnitMilliliter
+		if system == SystemImperial {
+			to = recipe.MeasurementUnitTeaspoon
+		}
+	default:
+		return q.Normalize()
+	}
+	amount, _ := Convert(q.Amount, q.Unit, to, "")
+	return Quantity{Amount: amount, Unit: to}.Normalize()
+}
+
+// Temperature converts a temperature to the system, Celsius for metric and
+// Fahrenheit for imperial. Oven temperatures round the way dials are
+// marked, 350°F to 180°C; lower ones, like a roast's internal temperature,
+// stay exact to the degree.
+func Temperature(t recipe.Temperature, system System) recipe.Temperature {
+	switch system {
+	case SystemMetric:
+		if t.Unit == recipe.TemperatureUnitCelsius {
+			return t
+		}
+		return recipe.Temperature{Value: dial(t.ToCelsius(), 150, 10), Unit: recipe.TemperatureUnitCelsius}
+	case SystemImperial:
+		if t.Unit == recipe.TemperatureUnitFahrenheit {
+			return t
+		}
+		return recipe.Temperature{Value: dial(t.ToCelsius()*9/5+32, 300, 25), Unit: recipe.TemperatureUnitFahrenheit}
+	}
+	return t
+}
+
+// temperatureText finds temperatures written in instructions: "350°F",
+// "180 C", "200 degrees Celsius"
+var temperatureText = regexp.MustCompile(`(?i)\b(\d{2,3})(\s*(?:°|º|degrees?)\s*|)(C|F|celsius|fahrenheit)\b`)
+
+// ConvertTemperatures rewrites the temperatures in an instruction for the
+// system, so "Bake at 350°F" reads "Bake at 175°C" in metric
+func ConvertTemperatures(text string, system System) string {
+	if system == SystemAsWritten {
+		return text
+	}
+	return temperatureText.ReplaceAllStringFunc(text, func(match string) string {
+		m := temperatureText.FindStringSubmatch(match)
+		// "20 c" with no degree sign may well be 20 cups
+		if m[2] == "" && len(m[3]) == 1 && m[3] != strings.ToUpper(m[3]) {
+			return match
+		}
+		value, _ := strconv.ParseFloat(m[1], 64)
+		t := recipe.Temperature{Value: value, Unit: recipe.TemperatureUnitCelsius}
+		if strings.HasPrefix(strings.ToUpper(m[3]), "F") {
+			t.Unit = recipe.TemperatureUnitFahrenheit
+		}
+		converted := Temperature(t, system)
+		if converted == t {
+			return match
+		}
+		return strconv.FormatFloat(converted.Value, 'f', -1, 64) + "°" + string(converted.Unit)
+	})
+}
+
+// dial rounds temperatures from oven up to the oven dial's step
+func dial(v, oven, step float64) float64 {
+	if v < oven {
+		return math.Round(v)
+	}
+	return math.Round(v/step) * step
+}
@@ -0,0 +1,30 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantityIn(t *testing.T) {
+	assert.Equal(t, "3 1/2 oz", Quantity{Amount: 100, Unit: recipe.MeasurementUnitGram}.In(SystemImperial).String())
+	assert.Equal(t, "455 g", Quantity{Amount: 1, Unit: recipe.MeasurementUnitPound}.In(SystemMetric).String())
+	assert.Equal(t, "1 cup", Quantity{Amount: 240, Unit: recipe.MeasurementUnitMilliliter}.In(SystemImperial).String())
+	assert.Equal(t, "15 ml", Quantity{Amount: 1, Unit: recipe.MeasurementUnitTablespoon}.In(SystemMetric).String())
+	assert.Equal(t, "2 clove", Quantity{Amount: 2, Unit: recipe.MeasurementUnitClove}.In(SystemMetric).String())
+	assert.Equal(t, "3/4 cup", Quantity{Amount: 0.75, Unit: recipe.MeasurementUnitCup}.In(SystemAsWritten).String())
+}
+
+func TestConvertTemperatures(t *testing.T) {
+	assert.Equal(t, "Bake at 180°C for 20 minutes.", ConvertTemperatures("Bake at 350°F for 20 minutes.", SystemMetric))
+	assert.Equal(t, "Heat the oven to 400°F.", ConvertTemperatures("Heat the oven to 200 degrees Celsius.", SystemImperial))
+	assert.Equal(t, "Bake at 180°C.", ConvertTemperatures("Bake at 180°C.", SystemMetric))
+	assert.Equal(t, "Add 12 c of stock.", ConvertTemperatures("Add 12 c of stock.", SystemImperial))
+
+	fan := Temperature(recipe.Temperature{Value: 425, Unit: recipe.TemperatureUnitFahrenheit}, SystemMetric)
+	assert.Equal(t, recipe.Temperature{Value: 220, Unit: recipe.TemperatureUnitCelsius}, fan)
+
+	roast := Temperature(recipe.Temperature{Value: 63, Unit: recipe.TemperatureUnitCelsius}, SystemImperial)
+	assert.Equal(t, 145.0, roast.Value, "internal temperatures stay exact")
+}
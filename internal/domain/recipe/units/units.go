@@ -0,0 +1,186 @@
+// Package units converts ingredient amounts between measurement units:
+// weights and volumes directly, volumes to weights through the
+// ingredient's density, and counts such as cloves or eggs through a
+// typical piece weight.
+package units
+
+import (
+	"regexp"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+)
+
+// Dimension is what a unit measures
+type Dimension string
+
+const (
+	DimensionWeight Dimension = "weight"
+	DimensionVolume Dimension = "volume"
+	DimensionCount  Dimension = "count"
+)
+
+// gramsPer converts weight units
+var gramsPer = map[recipe.MeasurementUnit]float64{
+	recipe.MeasurementUnitGram:     1,
+	recipe.MeasurementUnitKilogram: 1000,
+	recipe.MeasurementUnitPound:    453.592,
+	recipe.MeasurementUnitOunce:    28.3495,
+}
+
+// millilitresPer converts volume units, US customary
+var millilitresPer = map[recipe.MeasurementUnit]float64{
+	recipe.MeasurementUnitTeaspoon:   4.92892,
+	recipe.MeasurementUnitTablespoon: 14.7868,
+	recipe.MeasurementUnitCup:        236.588,
+	recipe.MeasurementUnitMilliliter: 1,
+	recipe.MeasurementUnitLiter:      1000,
+	recipe.MeasurementUnitPint:       473.176,
+	recipe.MeasurementUnitQuart:      946.353,
+	recipe.MeasurementUnitGallon:     3785.41,
+}
+
+// densities in grams per millilitre, matched against ingredient names in
+// order so brown sugar is not plain sugar
+var densities = []struct {
+	gramsPerML float64
+	keywords   *regexp.Regexp
+}{
+	{0.53, words(`flour`)},
+	{0.5, words(`(?:powdered|icing|confectioners'?) sugar`)},
+	{0.9, words(`brown sugar`)},
+	{0.85, words(`sugar`)},
+	{0.96, words(`butter`)},
+	{0.92, words(`oil`)},
+	{1.4, words(`honey|syrup|molasses|treacle`)},
+	{1.2, words(`salt`)},
+	{0.42, words(`cocoa`)},
+	{0.36, words(`oats`)},
+	{0.85, words(`rice`)},
+	{1.03, words(`milk|cream|yogh?urt|buttermilk`)},
+	{1.0, words(`water|stock|broth|wine|juice|vinegar|beer`)},
+}
+
+// itemGrams are typical weights of one piece, clove or similar
+var itemGrams = []struct {
+	unit     recipe.MeasurementUnit
+	grams    float64
+	keywords *regexp.Regexp
+}{
+	{recipe.MeasurementUnitClove, 5, words(`garlic`)},
+	{recipe.MeasurementUnitStick, 113, words(`butter`)},
+	{recipe.MeasurementUnitCan, 400, nil},
+	{recipe.MeasurementUnitPinch, 0.4, nil},
+	{recipe.MeasurementUnitDash, 0.6, nil},
+	{recipe.MeasurementUnitPiece, 50, words(`eggs?`)},
+	{recipe.MeasurementUnitPiece, 150, words(`onions?`)},
+	{recipe.MeasurementUnitPiece, 200, words(`potato(?:es)?`)},
+	{recipe.MeasurementUnitPiece, 120, words(`tomato(?:es)?`)},
+	{recipe.MeasurementUnitPiece, 60, words(`carrots?`)},
+	{recipe.MeasurementUnitPiece, 100, words(`lemons?`)},
+	{recipe.MeasurementUnitPiece, 65, words(`limes?`)},
+	{recipe.MeasurementUnitPiece, 180, words(`apples?`)},
+	{recipe.MeasurementUnitPiece, 120, words(`bananas?`)},
+}
+
+func words(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\b(?:` + pattern + `)\b`)
+}
+
+// DimensionOf says what a unit measures; no unit counts pieces
+func DimensionOf(unit recipe.MeasurementUnit) Dimension {
+	if _, ok := gramsPer[unit]; ok {
+		return DimensionWeight
+	}
+	if _, ok := millilitresPer[unit]; ok {
+		return DimensionVolume
+	}
+	return DimensionCount
+}
+
+// Grams converts an amount to grams: weights directly, volumes through the
+// ingredient's density and counts through a typical piece weight. The
+// second result is false when the weight is not known.
+func Grams(amount float64, unit recipe.MeasurementUnit, name string) (float64, bool) {
+	if grams, ok := gramsPer[unit]; ok {
+		return amount * grams, true
+	}
+	if ml, ok := millilitresPer[unit]; ok {
+		if density, ok := densityOf(name); ok {
+			return amount * ml * density, true
+		}
+		return 0, false
+	}
+	if grams, ok := pieceGrams(unit, name); ok {
+		return amount * grams, true
+	}
+	return 0, false
+}
+
+// Convert converts an amount of the named ingredient between units. The
+// second result is false when no conversion is known, such as a bunch of
+// herbs to grams.
+func Convert(amount float64, from, to recipe.MeasurementUnit, name string) (float64, bool) {
+	if countUnit(from) == countUnit(to) {
+		return amount, true
+	}
+	fromDim, toDim := DimensionOf(from), DimensionOf(to)
+	if fromDim == DimensionVolume && toDim == DimensionVolume {
+		return amount * millilitresPer[from] / millilitresPer[to], true
+	}
+	grams, ok := Grams(amount, from, name)
+	if !ok {
+		return 0, false
+	}
+	switch toDim {
+	case DimensionWeight:
+		return grams / gramsPer[to], true
+	case DimensionVolume:
+		density, ok := densityOf(name)
+		if !ok {
+			return 0, false
+		}
+		return grams / density / millilitresPer[to], true
+	default:
+		piece, ok := pieceGrams(to, name)
+		if !ok {
+			return 0, false
+		}
+		return grams / piece, true
+	}
+}
+
+// countUnit treats no unit as pieces, so "3 eggs" and "3 piece egg" agree
+func countUnit(unit recipe.MeasurementUnit) recipe.MeasurementUnit {
+	if unit == "" {
+		return recipe.MeasurementUnitPiece
+	}
+	return unit
+}
+
+func densityOf(name string) (float64, bool) {
+	for _, d := range densities {
+		if d.keywords.MatchString(name) {
+			return d.gramsPerML, true
+		}
+	}
+	return 0, false
+}
+
+func pieceGrams(unit recipe.MeasurementUnit, name string) (float64, bool) {
+	unit = countUnit(unit)
+	for _, item := range itemGrams {
+		if item.unit == unit && (item.keywords == nil || item.keywords.MatchString(name)) {
+			return item.grams, true
+		}
+	}
+	return 0, false
+}
+
+// Format renders an amount with its unit the way recipes give it
+func Format(amount float64, unit recipe.MeasurementUnit) string {
+	if unit == "" {
+		return ingredients.FormatAmount(amount)
+	}
+	return ingredients.FormatAmount(amount) + " " + string(unit)
+}
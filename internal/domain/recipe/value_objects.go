@@ -78,6 +78,15 @@ type NutritionInfo struct {
 	Sugar         float64 // in grams
 	Sodium        float64 // in milligrams
 	Cholesterol   float64 // in milligrams
+	Coverage      float64 // share of required ingredients counted, 0-1
+}
+
+// AllergenLabel is the major allergens a recipe declares, worked out from
+// its ingredients whenever they are saved. Allergens are named as in the
+// allergens package: milk, tree-nuts.
+type AllergenLabel struct {
+	Contains   []string
+	MayContain []string
 }
 
 // Rating represents a user's rating of a recipe
@@ -130,6 +139,9 @@ const (
 	MeasurementUnitOunce      MeasurementUnit = "oz"
 	MeasurementUnitMilliliter MeasurementUnit = "ml"
 	MeasurementUnitLiter      MeasurementUnit = "l"
+	MeasurementUnitPint       MeasurementUnit = "pt"
+	MeasurementUnitQuart      MeasurementUnit = "qt"
+	MeasurementUnitGallon     MeasurementUnit = "gal"
 	
 	// Weight units
 	MeasurementUnitGram     MeasurementUnit = "g"
@@ -140,6 +152,15 @@ const (
 	MeasurementUnitPiece MeasurementUnit = "piece"
 	MeasurementUnitDash  MeasurementUnit = "dash"
 	MeasurementUnitPinch MeasurementUnit = "pinch"
+	
+	// Package and produce units
+	MeasurementUnitClove   MeasurementUnit = "clove"
+	MeasurementUnitCan     MeasurementUnit = "can"
+	MeasurementUnitPackage MeasurementUnit = "package"
+	MeasurementUnitSlice   MeasurementUnit = "slice"
+	MeasurementUnitStick   MeasurementUnit = "stick"
+	MeasurementUnitBunch   MeasurementUnit = "bunch"
+	MeasurementUnitSprig   MeasurementUnit = "sprig"
 )
 
 // TemperatureUnit represents temperature units
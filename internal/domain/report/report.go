@@ -0,0 +1,142 @@
+// Package report holds readers' reports of recipes and comments that break
+// the rules, and the moderators' verdicts on them
+package report
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxNoteLength bounds the note a reader or moderator adds to a report
+const MaxNoteLength = 500
+
+// Domain errors for reports
+var (
+	ErrInvalidTarget = errors.New("only recipes and comments can be reported")
+	ErrInvalidReason = errors.New("reason must be spam, abuse, inappropriate, copyright or other")
+	ErrNoteLength    = errors.New("note must not exceed 500 characters")
+	ErrSelfReport    = errors.New("you cannot report your own content")
+	ErrNotOpen       = errors.New("the report has already been handled")
+)
+
+// TargetType is the kind of content a report is about
+type TargetType string
+
+const (
+	TargetRecipe  TargetType = "recipe"
+	TargetComment TargetType = "comment"
+)
+
+// Valid reports whether t is a known target
+func (t TargetType) Valid() bool {
+	return t == TargetRecipe || t == TargetComment
+}
+
+// Reason is why a reader reported content
+type Reason string
+
+const (
+	ReasonSpam          Reason = "spam"
+	ReasonAbuse         Reason = "abuse"
+	ReasonInappropriate Reason = "inappropriate"
+	ReasonCopyright     Reason = "copyright"
+	ReasonOther         Reason = "other"
+)
+
+// Valid reports whether r is a known reason
+func (r Reason) Valid() bool {
+	switch r {
+	case ReasonSpam, ReasonAbuse, ReasonInappropriate, ReasonCopyright, ReasonOther:
+		return true
+	}
+	return false
+}
+
+// Status is where a report stands
+type Status string
+
+const (
+	StatusOpen Status = "open"
+	// StatusResolved reports were upheld: the content was taken down
+	StatusResolved Status = "resolved"
+	// StatusDismissed reports were rejected: the content stays up
+	StatusDismissed Status = "dismissed"
+)
+
+// Report is a reader asking moderators to take content down. RecipeID is
+// the reported recipe, or the recipe a reported comment is on.
+// Unpublished is set once the content was taken down automatically
+// because enough readers reported it.
+type Report struct {
+	ID          uuid.UUID
+	TargetType  TargetType
+	TargetID    uuid.UUID
+	RecipeID    uuid.UUID
+	ReporterID  uuid.UUID
+	Reason      Reason
+	Note        string
+	Status      Status
+	Unpublished bool
+	ResolvedBy  *uuid.UUID
+	Resolution  string
+	CreatedAt   time.Time
+	ResolvedAt  *time.Time
+}
+
+// NewReport validates a report by reporterID on content written by
+// authorID
+func NewReport(target TargetType, targetID, recipeID, authorID, reporterID uuid.UUID, reason Reason, note string, now time.Time) (*Report, error) {
+	if !target.Valid() {
+		return nil, ErrInvalidTarget
+	}
+	if authorID == reporterID {
+		return nil, ErrSelfReport
+	}
+	if !reason.Valid() {
+		return nil, ErrInvalidReason
+	}
+	note, err := NormalizeNote(note)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{
+		ID:         uuid.New(),
+		TargetType: target,
+		TargetID:   targetID,
+		RecipeID:   recipeID,
+		ReporterID: reporterID,
+		Reason:     reason,
+		Note:       note,
+		Status:     StatusOpen,
+		CreatedAt:  now,
+	}, nil
+}
+
+// Close records a moderator's verdict on an open report
+func (r *Report) Close(status Status, moderatorID uuid.UUID, resolution string, now time.Time) error {
+	if r.Status != StatusOpen {
+		return ErrNotOpen
+	}
+	resolution, err := NormalizeNote(resolution)
+	if err != nil {
+		return err
+	}
+	r.Status = status
+	r.ResolvedBy = &moderatorID
+	r.Resolution = resolution
+	r.ResolvedAt = &now
+	return nil
+}
+
+// NormalizeNote trims a note and checks its length
+func NormalizeNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > MaxNoteLength {
+		return "", ErrNoteLength
+	}
+	return note, nil
+}
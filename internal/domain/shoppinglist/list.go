@@ -0,0 +1,401 @@
+// Package shoppinglist contains the domain model for shared shopping lists.
+// Every change is an operation applied in server order; concurrent check and
+// edit operations are resolved per item by when the user made them, so
+// clients that queued changes offline can replay them on reconnect.
+package shoppinglist
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxItems bounds the items on one list, removed ones excluded
+	MaxItems = 500
+	// MaxNameLength bounds list and item names, in characters
+	MaxNameLength = 200
+	// MaxQuantityLength bounds free-text quantities such as "2 x 400g"
+	MaxQuantityLength = 50
+	// MaxOpIDLength bounds client-generated operation IDs
+	MaxOpIDLength = 64
+)
+
+// Domain errors for shopping list operations
+var (
+	ErrEmptyName        = errors.New("name must not be empty")
+	ErrNameTooLong      = errors.New("name must not exceed 200 characters")
+	ErrQuantityTooLong  = errors.New("quantity must not exceed 50 characters")
+	ErrTooManyItems     = errors.New("shopping list is full")
+	ErrItemNotFound     = errors.New("item not found")
+	ErrUnknownOperation = errors.New("unknown operation")
+	ErrMissingOpID      = errors.New("operation id is required")
+	ErrOpIDTooLong      = errors.New("operation id must not exceed 64 characters")
+	ErrAlreadyMember    = errors.New("user already shares this list")
+	// ErrStaleVersion is returned by repositories when the list changed
+	// since it was loaded
+	ErrStaleVersion = errors.New("shopping list was changed concurrently")
+)
+
+// OpType names an operation on a list item
+type OpType string
+
+const (
+	OpAdd     OpType = "add"
+	OpEdit    OpType = "edit"
+	OpCheck   OpType = "check"
+	OpUncheck OpType = "uncheck"
+	OpRemove  OpType = "remove"
+)
+
+// Outcome says what became of an operation
+type Outcome string
+
+const (
+	// OutcomeApplied changed the list
+	OutcomeApplied Outcome = "applied"
+	// OutcomeUnchanged found the item already in the requested state
+	OutcomeUnchanged Outcome = "unchanged"
+	// OutcomeConflict lost to a later concurrent change; the change carries
+	// the item as it stands so the client can adopt it
+	OutcomeConflict Outcome = "conflict"
+	// OutcomeDuplicate was already applied, typically a retried offline op
+	OutcomeDuplicate Outcome = "duplicate"
+)
+
+// Operation is one change a client made to the list
+type Operation struct {
+	// ID is generated by the client so retries are not applied twice
+	ID       string
+	Type     OpType
+	ItemID   uuid.UUID
+	Name     string
+	Quantity string
+	// BaseVersion is the item version the client last saw. Operations on
+	// an older version are concurrent with the changes since.
+	BaseVersion int64
+	// At is when the user made the change, which for offline clients can be
+	// well before it reaches the server. Zero or future times mean now.
+	At time.Time
+}
+
+// Item is one entry on a list. Removed items are kept so late operations
+// on them resolve against the removal.
+type Item struct {
+	ID        uuid.UUID
+	Name      string
+	Quantity  string
+	Checked   bool
+	CheckedBy *uuid.UUID
+	// CheckedChangedAt and EditedAt are the user times of the last check
+	// and edit, used to order concurrent changes
+	CheckedChangedAt time.Time
+	EditedAt         time.Time
+	Removed          bool
+	AddedBy          uuid.UUID
+	// Version is the list version of the item's last change
+	Version   int64
+	CreatedAt time.Time
+}
+
+// Change is the result of applying an operation
+type Change struct {
+	OpID    string
+	Type    OpType
+	UserID  uuid.UUID
+	Outcome Outcome
+	Item    Item
+	// Version is the list version after the operation
+	Version   int64
+	CreatedAt time.Time
+}
+
+// List is a shopping list its owner shares with other users
+type List struct {
+	id        uuid.UUID
+	ownerID   uuid.UUID
+	name      string
+	memberIDs []uuid.UUID
+	items     []*Item
+	version   int64
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// NewList creates an empty list
+func NewList(ownerID uuid.UUID, name string) (*List, error) {
+	name, err := validName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &List{
+		id:        uuid.New(),
+		ownerID:   ownerID,
+		name:      name,
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+// Reconstruct rebuilds a stored list without validation
+func Reconstruct(id, ownerID uuid.UUID, name string, memberIDs []uuid.UUID, items []*Item, version int64, createdAt, updatedAt time.Time) *List {
+	return &List{
+		id:        id,
+		ownerID:   ownerID,
+		name:      name,
+		memberIDs: memberIDs,
+		items:     items,
+		version:   version,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}
+}
+
+// CanEdit reports whether the user owns or shares the list
+func (l *List) CanEdit(userID uuid.UUID) bool {
+	if userID == l.ownerID {
+		return true
+	}
+	for _, id := range l.memberIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// AddMember shares the list with another user
+func (l *List) AddMember(userID uuid.UUID) error {
+	if l.CanEdit(userID) {
+		return ErrAlreadyMember
+	}
+	l.memberIDs = append(l.memberIDs, userID)
+	l.updatedAt = time.Now()
+	return nil
+}
+
+// Apply applies one operation by the user. Adds are idempotent by item ID.
+// Concurrent check/uncheck and edit operations are last-writer-wins by the
+// time the user made them, with a check winning a tie; removal always wins.
+func (l *List) Apply(userID uuid.UUID, op Operation, now time.Time) (Change, error) {
+	if strings.TrimSpace(op.ID) == "" {
+		return Change{}, ErrMissingOpID
+	}
+	if len(op.ID) > MaxOpIDLength {
+		return Change{}, ErrOpIDTooLong
+	}
+	at := op.At
+	if at.IsZero() || at.After(now) {
+		at = now
+	}
+	change := Change{OpID: op.ID, Type: op.Type, UserID: userID, CreatedAt: now}
+
+	switch op.Type {
+	case OpAdd:
+		return l.add(userID, op, at, change)
+	case OpEdit, OpCheck, OpUncheck, OpRemove:
+	default:
+		return Change{}, ErrUnknownOperation
+	}
+
+	item := l.item(op.ItemID)
+	if item == nil {
+		return Change{}, ErrItemNotFound
+	}
+	if item.Removed {
+		if op.Type == OpRemove {
+			return l.result(change, OutcomeUnchanged, item), nil
+		}
+		return l.result(change, OutcomeConflict, item), nil
+	}
+	concurrent := op.BaseVersion < item.Version
+
+	switch op.Type {
+	case OpCheck, OpUncheck:
+		want := op.Type == OpCheck
+		if item.Checked == want {
+			return l.result(change, OutcomeUnchanged, item), nil
+		}
+		if concurrent && (at.Before(item.CheckedChangedAt) || (at.Equal(item.CheckedChangedAt) && !want)) {
+			return l.result(change, OutcomeConflict, item), nil
+		}
+		item.Checked = want
+		item.CheckedChangedAt = at
+		item.CheckedBy = nil
+		if want {
+			by := userID
+			item.CheckedBy = &by
+		}
+
+	case OpEdit:
+		name, err := validName(op.Name)
+		if err != nil {
+			return Change{}, err
+		}
+		quantity, err := validQuantity(op.Quantity)
+		if err != nil {
+			return Change{}, err
+		}
+		if item.Name == name && item.Quantity == quantity {
+			return l.result(change, OutcomeUnchanged, item), nil
+		}
+		if concurrent && !at.After(item.EditedAt) {
+			return l.result(change, OutcomeConflict, item), nil
+		}
+		item.Name = name
+		item.Quantity = quantity
+		item.EditedAt = at
+
+	case OpRemove:
+		item.Removed = true
+	}
+
+	return l.bump(change, item, now), nil
+}
+
+func (l *List) add(userID uuid.UUID, op Operation, at time.Time, change Change) (Change, error) {
+	if op.ItemID == uuid.Nil {
+		op.ItemID = uuid.New()
+	}
+	if existing := l.item(op.ItemID); existing != nil {
+		return l.result(change, OutcomeDuplicate, existing), nil
+	}
+
+	name, err := validName(op.Name)
+	if err != nil {
+		return Change{}, err
+	}
+	quantity, err := validQuantity(op.Quantity)
+	if err != nil {
+		return Change{}, err
+	}
+	if len(l.Items()) >= MaxItems {
+		return Change{}, ErrTooManyItems
+	}
+
+	item := &Item{
+		ID:               op.ItemID,
+		Name:             name,
+		Quantity:         quantity,
+		CheckedChangedAt: at,
+		EditedAt:         at,
+		AddedBy:          userID,
+		CreatedAt:        change.CreatedAt,
+	}
+	l.items = append(l.items, item)
+	return l.bump(change, item, change.CreatedAt), nil
+}
+
+// bump records an applied change under the next list version
+func (l *List) bump(change Change, item *Item, now time.Time) Change {
+	l.version++
+	l.updatedAt = now
+	item.Version = l.version
+	change.Outcome = OutcomeApplied
+	change.Item = *item
+	change.Version = l.version
+	return change
+}
+
+func (l *List) result(change Change, outcome Outcome, item *Item) Change {
+	change.Outcome = outcome
+	change.Item = *item
+	change.Version = l.version
+	return change
+}
+
+func (l *List) item(id uuid.UUID) *Item {
+	for _, item := range l.items {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// ID returns the list's unique identifier
+func (l *List) ID() uuid.UUID {
+	return l.id
+}
+
+// OwnerID returns who created the list
+func (l *List) OwnerID() uuid.UUID {
+	return l.ownerID
+}
+
+// Name returns the list name
+func (l *List) Name() string {
+	return l.name
+}
+
+// MemberIDs returns the users the list is shared with
+func (l *List) MemberIDs() []uuid.UUID {
+	return l.memberIDs
+}
+
+// Item returns an item, including a removed one
+func (l *List) Item(id uuid.UUID) (Item, bool) {
+	if item := l.item(id); item != nil {
+		return *item, true
+	}
+	return Item{}, false
+}
+
+// Items returns the items not removed, in the order they were added
+func (l *List) Items() []Item {
+	items := make([]Item, 0, len(l.items))
+	for _, item := range l.items {
+		if !item.Removed {
+			items = append(items, *item)
+		}
+	}
+	return items
+}
+
+// AllItems returns every item including removed ones, for persistence
+func (l *List) AllItems() []Item {
+	items := make([]Item, len(l.items))
+	for i, item := range l.items {
+		items[i] = *item
+	}
+	return items
+}
+
+// Version returns the number of changes applied to the list
+func (l *List) Version() int64 {
+	return l.version
+}
+
+// CreatedAt returns when the list was created
+func (l *List) CreatedAt() time.Time {
+	return l.createdAt
+}
+
+// UpdatedAt returns when the list last changed
+func (l *List) UpdatedAt() time.Time {
+	return l.updatedAt
+}
+
+func validName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrEmptyName
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return "", ErrNameTooLong
+	}
+	return name, nil
+}
+
+func validQuantity(quantity string) (string, error) {
+	quantity = strings.TrimSpace(quantity)
+	if utf8.RuneCountInString(quantity) > MaxQuantityLength {
+		return "", ErrQuantityTooLong
+	}
+	return quantity, nil
+}
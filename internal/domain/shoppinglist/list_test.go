@@ -0,0 +1,120 @@
+package shoppinglist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+func at(seconds int) time.Time {
+	return base.Add(time.Duration(seconds) * time.Second)
+}
+
+func newListWithItem(t *testing.T) (*List, uuid.UUID, uuid.UUID) {
+	t.Helper()
+	owner := uuid.New()
+	list, err := NewList(owner, "Saturday market")
+	require.NoError(t, err)
+
+	itemID := uuid.New()
+	change, err := list.Apply(owner, Operation{ID: "add-1", Type: OpAdd, ItemID: itemID, Name: " Lemons ", Quantity: "6", At: at(0)}, at(60))
+	require.NoError(t, err)
+	require.Equal(t, OutcomeApplied, change.Outcome)
+	assert.Equal(t, "Lemons", change.Item.Name)
+	assert.Equal(t, int64(1), change.Version)
+	return list, owner, itemID
+}
+
+func TestApplyResolvesConcurrentChecksByTime(t *testing.T) {
+	list, owner, itemID := newListWithItem(t)
+	partner := uuid.New()
+	require.NoError(t, list.AddMember(partner))
+	now := at(120)
+
+	change, err := list.Apply(owner, Operation{ID: "a", Type: OpCheck, ItemID: itemID, BaseVersion: 1, At: at(10)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeApplied, change.Outcome)
+	assert.Equal(t, &owner, change.Item.CheckedBy)
+
+	// The partner unchecked while offline, before the owner checked it
+	change, err = list.Apply(partner, Operation{ID: "b", Type: OpUncheck, ItemID: itemID, BaseVersion: 1, At: at(5)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeConflict, change.Outcome)
+	assert.True(t, change.Item.Checked)
+	assert.Equal(t, int64(2), list.Version())
+
+	// A later offline uncheck wins
+	change, err = list.Apply(partner, Operation{ID: "c", Type: OpUncheck, ItemID: itemID, BaseVersion: 1, At: at(20)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeApplied, change.Outcome)
+	assert.False(t, change.Item.Checked)
+	assert.Nil(t, change.Item.CheckedBy)
+
+	// On a tie the check wins
+	change, err = list.Apply(owner, Operation{ID: "d", Type: OpCheck, ItemID: itemID, BaseVersion: 2, At: at(20)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeApplied, change.Outcome)
+
+	change, err = list.Apply(owner, Operation{ID: "e", Type: OpCheck, ItemID: itemID, BaseVersion: 4, At: at(30)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeUnchanged, change.Outcome)
+	assert.Equal(t, int64(4), change.Version)
+}
+
+func TestApplyEditsAndRemoval(t *testing.T) {
+	list, owner, itemID := newListWithItem(t)
+	now := at(120)
+
+	change, err := list.Apply(owner, Operation{ID: "a", Type: OpEdit, ItemID: itemID, Name: "Meyer lemons", Quantity: "4", BaseVersion: 1, At: at(30)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeApplied, change.Outcome)
+
+	change, err = list.Apply(owner, Operation{ID: "b", Type: OpEdit, ItemID: itemID, Name: "Limes", BaseVersion: 1, At: at(15)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeConflict, change.Outcome)
+	assert.Equal(t, "Meyer lemons", change.Item.Name)
+
+	_, err = list.Apply(owner, Operation{ID: "c", Type: OpEdit, ItemID: itemID, Name: "  ", BaseVersion: 2}, now)
+	assert.ErrorIs(t, err, ErrEmptyName)
+
+	change, err = list.Apply(owner, Operation{ID: "d", Type: OpRemove, ItemID: itemID, BaseVersion: 1, At: at(1)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeApplied, change.Outcome, "removal wins regardless of time")
+	assert.Empty(t, list.Items())
+	assert.Len(t, list.AllItems(), 1)
+
+	change, err = list.Apply(owner, Operation{ID: "e", Type: OpCheck, ItemID: itemID, BaseVersion: 2, At: at(90)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeConflict, change.Outcome)
+	assert.True(t, change.Item.Removed)
+
+	change, err = list.Apply(owner, Operation{ID: "f", Type: OpRemove, ItemID: itemID}, now)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeUnchanged, change.Outcome)
+
+	change, err = list.Apply(owner, Operation{ID: "g", Type: OpAdd, ItemID: itemID, Name: "Lemons"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeDuplicate, change.Outcome)
+}
+
+func TestApplyValidatesOperations(t *testing.T) {
+	list, owner, itemID := newListWithItem(t)
+	now := at(120)
+
+	_, err := list.Apply(owner, Operation{Type: OpCheck, ItemID: itemID}, now)
+	assert.ErrorIs(t, err, ErrMissingOpID)
+
+	_, err = list.Apply(owner, Operation{ID: "a", Type: "strike", ItemID: itemID}, now)
+	assert.ErrorIs(t, err, ErrUnknownOperation)
+
+	_, err = list.Apply(owner, Operation{ID: "b", Type: OpCheck, ItemID: uuid.New()}, now)
+	assert.ErrorIs(t, err, ErrItemNotFound)
+
+	assert.ErrorIs(t, list.AddMember(owner), ErrAlreadyMember)
+	assert.False(t, list.CanEdit(uuid.New()))
+}
@@ -0,0 +1,178 @@
+// Package technique contains the technique library: how-to guides for
+// cooking techniques such as julienning or proofing dough, optionally with
+// a video, that recipe steps link to.
+package technique
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxNameLength bounds technique names and aliases, in characters
+	MaxNameLength = 120
+	// MaxSummaryLength bounds the one-paragraph summary shown in cook mode
+	MaxSummaryLength = 500
+	// MaxSteps bounds the how-to steps of a technique
+	MaxSteps = 20
+	// MaxStepLength bounds one how-to step
+	MaxStepLength = 1000
+	// MaxAliases bounds the other names a technique goes by
+	MaxAliases = 10
+	// MaxLinksPerStep bounds the techniques one recipe step links to
+	MaxLinksPerStep = 3
+	// maxSlugLength matches the techniques.slug column
+	maxSlugLength = 80
+)
+
+// Domain errors for technique operations
+var (
+	ErrEmptyName       = errors.New("technique name must not be empty")
+	ErrNameTooLong     = errors.New("technique name must not exceed 120 characters")
+	ErrInvalidSlug     = errors.New("technique slug must be lowercase letters, digits and dashes")
+	ErrEmptySummary    = errors.New("technique summary must not be empty")
+	ErrSummaryTooLong  = errors.New("technique summary must not exceed 500 characters")
+	ErrTooManySteps    = errors.New("technique must not have more than 20 steps")
+	ErrStepTooLong     = errors.New("technique step must not exceed 1000 characters")
+	ErrTooManyAliases  = errors.New("technique must not have more than 10 aliases")
+	ErrTooManyLinks    = errors.New("a recipe step must not link more than 3 techniques")
+	ErrUnsupportedURL  = errors.New("video must be a YouTube or Vimeo link")
+	ErrInvalidDuration = errors.New("video duration and start must not be negative")
+)
+
+// Source says who linked a technique to a recipe step
+type Source string
+
+const (
+	// SourceAuthor links were chosen by the recipe's author
+	SourceAuthor Source = "author"
+	// SourceSuggested links were proposed for steps the author has not
+	// linked; the author replaces them by linking the step
+	SourceSuggested Source = "suggested"
+)
+
+// Technique is one entry of the library
+type Technique struct {
+	Slug    string
+	Name    string
+	Summary string
+	Steps   []string
+	// Aliases are other names for the technique, matched in recipe steps
+	// when suggesting links
+	Aliases   []string
+	Video     *Video
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// New validates a technique. An empty slug is derived from the name.
+func New(slug, name, summary string, steps, aliases []string, video *Video) (*Technique, error) {
+	name = strings.TrimSpace(name)
+	summary = strings.TrimSpace(summary)
+	switch {
+	case name == "":
+		return nil, ErrEmptyName
+	case utf8.RuneCountInString(name) > MaxNameLength:
+		return nil, ErrNameTooLong
+	case summary == "":
+		return nil, ErrEmptySummary
+	case utf8.RuneCountInString(summary) > MaxSummaryLength:
+		return nil, ErrSummaryTooLong
+	case len(steps) > MaxSteps:
+		return nil, ErrTooManySteps
+	case len(aliases) > MaxAliases:
+		return nil, ErrTooManyAliases
+	}
+	if slug == "" {
+		slug = Slugify(name)
+	}
+	if len(slug) > maxSlugLength || !slugPattern.MatchString(slug) {
+		return nil, ErrInvalidSlug
+	}
+
+	t := &Technique{Slug: slug, Name: name, Summary: summary, Video: video}
+	for _, step := range steps {
+		if step = strings.TrimSpace(step); step == "" {
+			continue
+		}
+		if utf8.RuneCountInString(step) > MaxStepLength {
+			return nil, ErrStepTooLong
+		}
+		t.Steps = append(t.Steps, step)
+	}
+	for _, alias := range aliases {
+		if alias = strings.TrimSpace(alias); alias == "" {
+			continue
+		}
+		if utf8.RuneCountInString(alias) > MaxNameLength {
+			return nil, ErrNameTooLong
+		}
+		t.Aliases = append(t.Aliases, alias)
+	}
+	return t, nil
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify turns a name into a slug: "Proofing Dough" becomes
+// "proofing-dough"
+func Slugify(name string) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+// Mentions reports whether text names the technique or one of its aliases
+// as whole words
+func (t *Technique) Mentions(text string) bool {
+	text = strings.ToLower(text)
+	for _, name := range append([]string{t.Name}, t.Aliases...) {
+		if containsWords(text, strings.ToLower(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWords finds phrase in text where it is not part of a longer word
+func containsWords(text, phrase string) bool {
+	for start := 0; phrase != ""; {
+		i := strings.Index(text[start:], phrase)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(phrase)
+		if (i == 0 || !isWordByte(text[i-1])) && (end == len(text) || !isWordByte(text[end])) {
+			return true
+		}
+		start = i + 1
+	}
+	return false
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b >= 0x80
+}
+
+// Suggest returns the slugs of the techniques a recipe step mentions, at
+// most MaxLinksPerStep
+func Suggest(step string, library []*Technique) []string {
+	var slugs []string
+	for _, t := range library {
+		if len(slugs) == MaxLinksPerStep {
+			break
+		}
+		if t.Mentions(step) {
+			slugs = append(slugs, t.Slug)
+		}
+	}
+	return slugs
+}
@@ -0,0 +1,62 @@
+package technique
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDerivesSlugAndValidates(t *testing.T) {
+	tech, err := New("", "  Proofing Dough ", "Let yeasted dough rise until doubled.", []string{"Cover the bowl.", " "}, []string{"proof", "let rise"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "proofing-dough", tech.Slug)
+	assert.Equal(t, "Proofing Dough", tech.Name)
+	assert.Equal(t, []string{"Cover the bowl."}, tech.Steps)
+
+	_, err = New("Bad Slug", "Julienne", "Thin matchsticks.", nil, nil, nil)
+	assert.Equal(t, ErrInvalidSlug, err)
+	_, err = New("", "Julienne", "", nil, nil, nil)
+	assert.Equal(t, ErrEmptySummary, err)
+}
+
+func TestSuggestMatchesWholeWords(t *testing.T) {
+	julienne, _ := New("", "Julienne", "Cut into thin matchsticks.", nil, []string{"matchsticks"}, nil)
+	proof, _ := New("proofing-dough", "Proofing dough", "Let it rise.", nil, []string{"proof", "let rise"}, nil)
+	library := []*Technique{julienne, proof}
+
+	assert.Equal(t, []string{"julienne"}, Suggest("Cut the carrots into matchsticks", library))
+	assert.Equal(t, []string{"proofing-dough"}, Suggest("Cover and let rise for an hour.", library))
+	assert.Empty(t, Suggest("Fold in the waterproofing", library))
+}
+
+func TestParseVideo(t *testing.T) {
+	for _, tc := range []struct {
+		url, provider, id string
+		start             int
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=90", ProviderYouTube, "dQw4w9WgXcQ", 90},
+		{"https://youtu.be/dQw4w9WgXcQ?t=1m5s", ProviderYouTube, "dQw4w9WgXcQ", 65},
+		{"https://www.youtube.com/embed/dQw4w9WgXcQ", ProviderYouTube, "dQw4w9WgXcQ", 0},
+		{"https://vimeo.com/76979871#t=30s", ProviderVimeo, "76979871", 30},
+	} {
+		video, err := ParseVideo(tc.url, "", 0)
+		require.NoError(t, err, tc.url)
+		assert.Equal(t, tc.provider, video.Provider, tc.url)
+		assert.Equal(t, tc.id, video.ID, tc.url)
+		assert.Equal(t, tc.start, video.Start, tc.url)
+	}
+
+	video, _ := ParseVideo("https://youtu.be/dQw4w9WgXcQ?t=90", "", 0)
+	assert.Equal(t, "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ?start=90", video.EmbedURL())
+
+	for _, bad := range []string{
+		"javascript:alert(1)",
+		"https://example.com/watch?v=dQw4w9WgXcQ",
+		"https://www.youtube.com/watch?v=short",
+		"https://vimeo.com/abc",
+	} {
+		_, err := ParseVideo(bad, "", 0)
+		assert.Equal(t, ErrUnsupportedURL, err, bad)
+	}
+}
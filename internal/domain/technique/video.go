@@ -0,0 +1,131 @@
+package technique
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Video providers
+const (
+	ProviderYouTube = "youtube"
+	ProviderVimeo   = "vimeo"
+)
+
+// Video is a how-to video embedded with a technique. Only the provider and
+// video ID are kept, so the embed URL is always built here and a stored
+// link can never point the player somewhere else.
+type Video struct {
+	Provider string
+	ID       string
+	Title    string
+	// Duration and Start are in seconds; Start skips an intro
+	Duration int
+	Start    int
+}
+
+var (
+	youTubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	vimeoID   = regexp.MustCompile(`^[0-9]{1,12}$`)
+)
+
+// ParseVideo reads a YouTube or Vimeo link. A t or start parameter on the
+// link becomes Start.
+func ParseVideo(rawURL, title string, duration int) (*Video, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil, ErrUnsupportedURL
+	}
+	if duration < 0 {
+		return nil, ErrInvalidDuration
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	path := strings.Trim(u.Path, "/")
+	video := &Video{Title: strings.TrimSpace(title), Duration: duration}
+	switch host {
+	case "youtube.com", "m.youtube.com", "youtube-nocookie.com":
+		video.Provider = ProviderYouTube
+		switch {
+		case path == "watch":
+			video.ID = u.Query().Get("v")
+		case strings.HasPrefix(path, "embed/"), strings.HasPrefix(path, "shorts/"):
+			video.ID = path[strings.Index(path, "/")+1:]
+		}
+	case "youtu.be":
+		video.Provider = ProviderYouTube
+		video.ID = path
+	case "vimeo.com", "player.vimeo.com":
+		video.Provider = ProviderVimeo
+		video.ID = strings.TrimPrefix(path, "video/")
+	default:
+		return nil, ErrUnsupportedURL
+	}
+
+	if video.Provider == ProviderYouTube && !youTubeID.MatchString(video.ID) ||
+		video.Provider == ProviderVimeo && !vimeoID.MatchString(video.ID) {
+		return nil, ErrUnsupportedURL
+	}
+
+	start := u.Query().Get("t")
+	if start == "" {
+		start = u.Query().Get("start")
+	}
+	if start == "" && video.Provider == ProviderVimeo && strings.HasPrefix(u.Fragment, "t=") {
+		start = strings.TrimPrefix(u.Fragment, "t=")
+	}
+	video.Start = parseStart(start)
+	return video, nil
+}
+
+// parseStart reads "90", "90s" or "1m30s"; anything else starts at zero
+func parseStart(s string) int {
+	if s == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(s); err == nil && seconds > 0 {
+		return seconds
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return int(d.Seconds())
+	}
+	return 0
+}
+
+// EmbedURL is the player URL for an iframe. YouTube videos play from the
+// no-cookie domain so an embed sets no tracking cookies until played.
+func (v Video) EmbedURL() string {
+	switch v.Provider {
+	case ProviderYouTube:
+		embed := "https://www.youtube-nocookie.com/embed/" + v.ID
+		if v.Start > 0 {
+			embed += fmt.Sprintf("?start=%d", v.Start)
+		}
+		return embed
+	case ProviderVimeo:
+		embed := "https://player.vimeo.com/video/" + v.ID
+		if v.Start > 0 {
+			embed += fmt.Sprintf("#t=%ds", v.Start)
+		}
+		return embed
+	}
+	return ""
+}
+
+// WatchURL links to the video on its provider's site
+func (v Video) WatchURL() string {
+	switch v.Provider {
+	case ProviderYouTube:
+		watch := "https://www.youtube.com/watch?v=" + v.ID
+		if v.Start > 0 {
+			watch += fmt.Sprintf("&t=%ds", v.Start)
+		}
+		return watch
+	case ProviderVimeo:
+		return "https://vimeo.com/" + v.ID
+	}
+	return ""
+}
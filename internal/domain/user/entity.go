@@ -48,6 +48,15 @@ type UserPreferences struct {
 	Timezone           string
 	EmailNotifications bool
 	PushNotifications  bool
+	Theme              Theme
+	// DisablePersonalization opts out of dietary and history boosting in search
+	DisablePersonalization bool
+	// PrivateProfile shows the profile and follow lists only to the users
+	// this user follows
+	PrivateProfile bool
+	// MutedNotifications lists the in-app notification types the user has
+	// turned off; every type is on by default
+	MutedNotifications []string
 }
 
 // UserRole represents the role of a user
@@ -83,14 +92,76 @@ const (
 	DietaryRestrictionKosher     DietaryRestriction = "kosher"
 )
 
+// DietaryRestrictions lists every dietary restriction in display order
+var DietaryRestrictions = []DietaryRestriction{
+	DietaryRestrictionVegetarian,
+	DietaryRestrictionVegan,
+	DietaryRestrictionGlutenFree,
+	DietaryRestrictionDairyFree,
+	DietaryRestrictionKeto,
+	DietaryRestrictionPaleo,
+	DietaryRestrictionHalal,
+	DietaryRestrictionKosher,
+}
+
+// ParseDietaryRestriction validates a dietary restriction, reading
+// "Gluten-free" and "gluten free" as gluten_free
+func ParseDietaryRestriction(s string) (DietaryRestriction, error) {
+	restriction := DietaryRestriction(strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(strings.TrimSpace(s))))
+	for _, known := range DietaryRestrictions {
+		if restriction == known {
+			return restriction, nil
+		}
+	}
+	return "", errors.New("unknown dietary restriction \"" + s + "\"")
+}
+
 // MeasurementSystem represents measurement preferences
 type MeasurementSystem string
 
 const (
 	MeasurementSystemMetric   MeasurementSystem = "metric"
 	MeasurementSystemImperial MeasurementSystem = "imperial"
+	// MeasurementSystemOriginal shows recipes in the units their authors used
+	MeasurementSystemOriginal MeasurementSystem = "original"
 )
 
+// ParseMeasurementSystem validates a measurement system name
+func ParseMeasurementSystem(s string) (MeasurementSystem, error) {
+	switch MeasurementSystem(strings.ToLower(strings.TrimSpace(s))) {
+	case MeasurementSystemMetric:
+		return MeasurementSystemMetric, nil
+	case MeasurementSystemImperial:
+		return MeasurementSystemImperial, nil
+	case MeasurementSystemOriginal:
+		return MeasurementSystemOriginal, nil
+	}
+	return "", errors.New("units must be one of metric, imperial or original")
+}
+
+// Theme represents the user's colour scheme preference
+type Theme string
+
+const (
+	// ThemeSystem follows the browser's prefers-color-scheme
+	ThemeSystem Theme = "system"
+	ThemeLight  Theme = "light"
+	ThemeDark   Theme = "dark"
+)
+
+// ParseTheme validates a theme name
+func ParseTheme(s string) (Theme, error) {
+	switch Theme(strings.ToLower(strings.TrimSpace(s))) {
+	case ThemeSystem:
+		return ThemeSystem, nil
+	case ThemeLight:
+		return ThemeLight, nil
+	case ThemeDark:
+		return ThemeDark, nil
+	}
+	return "", errors.New("theme must be one of system, light or dark")
+}
+
 // NewUser creates a new user with validation
 func NewUser(email, name, password string) (*User, error) {
 	if err := validateEmail(email); err != nil {
@@ -125,6 +196,7 @@ func NewUser(email, name, password string) (*User, error) {
 			Language:           "en",
 			EmailNotifications: true,
 			PushNotifications:  true,
+			Theme:              ThemeSystem,
 		},
 		createdAt: now,
 		updatedAt: now,
@@ -146,6 +218,12 @@ func (u *User) Name() string {
 	return u.name
 }
 
+// PasswordHash returns the bcrypt hash of the user's password, for
+// persistence
+func (u *User) PasswordHash() string {
+	return u.passwordHash
+}
+
 // IsActive returns whether the user is active
 func (u *User) IsActive() bool {
 	return u.isActive
@@ -219,6 +297,104 @@ func (u *User) UpdatePreferences(preferences *UserPreferences) {
 	u.updatedAt = time.Now()
 }
 
+// SetTheme updates only the theme preference
+func (u *User) SetTheme(theme Theme) {
+	if u.preferences == nil {
+		u.preferences = &UserPreferences{}
+	}
+	u.preferences.Theme = theme
+	u.updatedAt = time.Now()
+}
+
+// SetMeasurementSystem updates only the units recipes are shown in
+func (u *User) SetMeasurementSystem(system MeasurementSystem) {
+	if u.preferences == nil {
+		u.preferences = &UserPreferences{}
+	}
+	u.preferences.MeasurementSystem = system
+	u.updatedAt = time.Now()
+}
+
+// MeasurementSystem returns the units the user reads recipes in, original
+// when they have not chosen
+func (u *User) MeasurementSystem() MeasurementSystem {
+	if u.preferences == nil || u.preferences.MeasurementSystem == "" {
+		return MeasurementSystemOriginal
+	}
+	return u.preferences.MeasurementSystem
+}
+
+// SetDietaryProfile replaces the user's dietary restrictions, allergies
+// and disliked ingredients
+func (u *User) SetDietaryProfile(restrictions []DietaryRestriction, allergies, disliked []string) {
+	if u.preferences == nil {
+		u.preferences = &UserPreferences{}
+	}
+	u.preferences.DietaryRestrictions = restrictions
+	u.preferences.Allergies = allergies
+	u.preferences.DislikedIngredients = disliked
+	u.updatedAt = time.Now()
+}
+
+// SetPersonalization turns personalized search ranking on or off
+func (u *User) SetPersonalization(enabled bool) {
+	if u.preferences == nil {
+		u.preferences = &UserPreferences{}
+	}
+	u.preferences.DisablePersonalization = !enabled
+	u.updatedAt = time.Now()
+}
+
+// PersonalizationEnabled reports whether search results may be personalized
+func (u *User) PersonalizationEnabled() bool {
+	return u.preferences == nil || !u.preferences.DisablePersonalization
+}
+
+// SetPrivateProfile makes the profile private or public
+func (u *User) SetPrivateProfile(private bool) {
+	if u.preferences == nil {
+		u.preferences = &UserPreferences{}
+	}
+	u.preferences.PrivateProfile = private
+	u.updatedAt = time.Now()
+}
+
+// IsPrivate reports whether the profile is private
+func (u *User) IsPrivate() bool {
+	return u.preferences != nil && u.preferences.PrivateProfile
+}
+
+// SetNotificationMuted turns one in-app notification type off or back on
+func (u *User) SetNotificationMuted(notificationType string, muted bool) {
+	if u.preferences == nil {
+		u.preferences = &UserPreferences{}
+	}
+	kept := make([]string, 0, len(u.preferences.MutedNotifications)+1)
+	for _, t := range u.preferences.MutedNotifications {
+		if t != notificationType {
+			kept = append(kept, t)
+		}
+	}
+	if muted {
+		kept = append(kept, notificationType)
+	}
+	u.preferences.MutedNotifications = kept
+	u.updatedAt = time.Now()
+}
+
+// NotificationMuted reports whether the user turned a notification type off
+func (u *User) NotificationMuted(notificationType string) bool {
+	if u.preferences == nil {
+		return false
+	}
+	for _, t := range u.preferences.MutedNotifications {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
 // Verify marks the user as verified
 func (u *User) Verify() {
 	u.isVerified = true
@@ -0,0 +1,292 @@
+// Package verification contains author verification. Professional chefs
+// and brands claim a verified badge by submitting proof, an admin approves
+// or rejects the claim, and readers can report a badge they believe is
+// fake. Upholding a report revokes the badge.
+package verification
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxDisplayNameLength bounds the name shown on the badge, in
+	// characters
+	MaxDisplayNameLength = 100
+	// MaxStatementLength bounds the claimant's explanation
+	MaxStatementLength = 2000
+	// MaxEvidence bounds the proof links on one claim
+	MaxEvidence = 5
+	// MaxEvidenceLength bounds one proof link
+	MaxEvidenceLength = 2048
+	// MaxNoteLength bounds reviewer notes and report details
+	MaxNoteLength = 2000
+	// RejectedCooldown is how long after a rejection a new claim may be
+	// made
+	RejectedCooldown = 7 * 24 * time.Hour
+	// RevokedCooldown is how long after a revocation a new claim may be
+	// made
+	RevokedCooldown = 90 * 24 * time.Hour
+)
+
+// Domain errors for claims and reports
+var (
+	ErrInvalidKind       = errors.New("kind must be chef or brand")
+	ErrEmptyDisplayName  = errors.New("display name must not be empty")
+	ErrDisplayNameLength = errors.New("display name must not exceed 100 characters")
+	ErrStatementLength   = errors.New("statement must not exceed 2000 characters")
+	ErrNoEvidence        = errors.New("at least one proof link is required")
+	ErrTooMuchEvidence   = errors.New("a claim may have at most 5 proof links")
+	ErrInvalidEvidence   = errors.New("proof links must be https URLs")
+	ErrNoteRequired      = errors.New("a note is required when rejecting or revoking")
+	ErrNoteLength        = errors.New("notes must not exceed 2000 characters")
+	ErrNotPending        = errors.New("only pending claims can be approved or rejected")
+	ErrNotVerified       = errors.New("only verified authors can be reported or revoked")
+	ErrInvalidReason     = errors.New("reason must be impersonation, fake_credentials or other")
+	ErrSelfReport        = errors.New("authors cannot report their own badge")
+	ErrReportResolved    = errors.New("the report is already resolved")
+)
+
+// Kind is what an author is verified as
+type Kind string
+
+const (
+	KindChef  Kind = "chef"
+	KindBrand Kind = "brand"
+)
+
+// Status is where a claim is in review
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+	// StatusRevoked claims were approved, then withdrawn after a report or
+	// an admin's review
+	StatusRevoked Status = "revoked"
+)
+
+// Claim is an author's request for a verified badge. DisplayName is what
+// the badge says, such as "Head chef, Lupa" or a brand name; Evidence are
+// links a reviewer can check, such as a restaurant's staff page.
+type Claim struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Kind        Kind
+	DisplayName string
+	Statement   string
+	Evidence    []string
+	Status      Status
+	// ReviewerID, ReviewNote and ReviewedAt are set by the latest decision
+	ReviewerID  *uuid.UUID
+	ReviewNote  string
+	SubmittedAt time.Time
+	ReviewedAt  *time.Time
+}
+
+// NewClaim validates and creates a pending claim
+func NewClaim(userID uuid.UUID, kind Kind, displayName, statement string, evidence []string, now time.Time) (*Claim, error) {
+	if kind != KindChef && kind != KindBrand {
+		return nil, ErrInvalidKind
+	}
+	displayName = strings.TrimSpace(displayName)
+	statement = strings.TrimSpace(statement)
+	switch {
+	case displayName == "":
+		return nil, ErrEmptyDisplayName
+	case utf8.RuneCountInString(displayName) > MaxDisplayNameLength:
+		return nil, ErrDisplayNameLength
+	case utf8.RuneCountInString(statement) > MaxStatementLength:
+		return nil, ErrStatementLength
+	}
+
+	var links []string
+	for _, link := range evidence {
+		if link = strings.TrimSpace(link); link == "" {
+			continue
+		}
+		u, err := url.Parse(link)
+		if err != nil || u.Scheme != "https" || u.Host == "" || len(link) > MaxEvidenceLength {
+			return nil, ErrInvalidEvidence
+		}
+		links = append(links, link)
+	}
+	switch {
+	case len(links) == 0:
+		return nil, ErrNoEvidence
+	case len(links) > MaxEvidence:
+		return nil, ErrTooMuchEvidence
+	}
+
+	return &Claim{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Kind:        kind,
+		DisplayName: displayName,
+		Statement:   statement,
+		Evidence:    links,
+		Status:      StatusPending,
+		SubmittedAt: now,
+	}, nil
+}
+
+// Verified reports whether the claim grants a badge
+func (c *Claim) Verified() bool {
+	return c.Status == StatusApproved
+}
+
+// Open reports whether the claim is pending or approved, either of which
+// blocks a new claim from the same author
+func (c *Claim) Open() bool {
+	return c.Status == StatusPending || c.Status == StatusApproved
+}
+
+// CanResubmitAt is when the author may claim again after this claim
+func (c *Claim) CanResubmitAt() time.Time {
+	if c.ReviewedAt == nil {
+		return c.SubmittedAt
+	}
+	switch c.Status {
+	case StatusRejected:
+		return c.ReviewedAt.Add(RejectedCooldown)
+	case StatusRevoked:
+		return c.ReviewedAt.Add(RevokedCooldown)
+	}
+	return *c.ReviewedAt
+}
+
+// Approve grants the badge
+func (c *Claim) Approve(reviewerID uuid.UUID, note string, now time.Time) error {
+	if c.Status != StatusPending {
+		return ErrNotPending
+	}
+	return c.decide(StatusApproved, reviewerID, note, false, now)
+}
+
+// Reject turns the claim down; the note tells the author why
+func (c *Claim) Reject(reviewerID uuid.UUID, note string, now time.Time) error {
+	if c.Status != StatusPending {
+		return ErrNotPending
+	}
+	return c.decide(StatusRejected, reviewerID, note, true, now)
+}
+
+// Revoke withdraws a granted badge
+func (c *Claim) Revoke(reviewerID uuid.UUID, note string, now time.Time) error {
+	if c.Status != StatusApproved {
+		return ErrNotVerified
+	}
+	return c.decide(StatusRevoked, reviewerID, note, true, now)
+}
+
+func (c *Claim) decide(status Status, reviewerID uuid.UUID, note string, noteRequired bool, now time.Time) error {
+	note, err := normalizeNote(note, noteRequired)
+	if err != nil {
+		return err
+	}
+	c.Status = status
+	c.ReviewerID = &reviewerID
+	c.ReviewNote = note
+	c.ReviewedAt = &now
+	return nil
+}
+
+// Reason is why a reader believes a badge is fake
+type Reason string
+
+const (
+	ReasonImpersonation   Reason = "impersonation"
+	ReasonFakeCredentials Reason = "fake_credentials"
+	ReasonOther           Reason = "other"
+)
+
+// ReportStatus is where a report is in review
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportDismissed ReportStatus = "dismissed"
+	// ReportUpheld reports revoked the badge
+	ReportUpheld ReportStatus = "upheld"
+)
+
+// Report is a reader's complaint that a verified badge is fake
+type Report struct {
+	ID         uuid.UUID
+	ClaimID    uuid.UUID
+	SubjectID  uuid.UUID
+	ReporterID uuid.UUID
+	Reason     Reason
+	Details    string
+	Status     ReportStatus
+	ResolverID *uuid.UUID
+	Resolution string
+	CreatedAt  time.Time
+	ResolvedAt *time.Time
+}
+
+// NewReport files a report against a verified claim
+func NewReport(claim *Claim, reporterID uuid.UUID, reason Reason, details string, now time.Time) (*Report, error) {
+	if !claim.Verified() {
+		return nil, ErrNotVerified
+	}
+	if reporterID == claim.UserID {
+		return nil, ErrSelfReport
+	}
+	switch reason {
+	case ReasonImpersonation, ReasonFakeCredentials, ReasonOther:
+	default:
+		return nil, ErrInvalidReason
+	}
+	details, err := normalizeNote(details, reason == ReasonOther)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{
+		ID:         uuid.New(),
+		ClaimID:    claim.ID,
+		SubjectID:  claim.UserID,
+		ReporterID: reporterID,
+		Reason:     reason,
+		Details:    details,
+		Status:     ReportOpen,
+		CreatedAt:  now,
+	}, nil
+}
+
+// Resolve closes the report. Upholding it does not revoke the claim by
+// itself; the caller revokes the claim alongside.
+func (r *Report) Resolve(resolverID uuid.UUID, uphold bool, note string, now time.Time) error {
+	if r.Status != ReportOpen {
+		return ErrReportResolved
+	}
+	note, err := normalizeNote(note, false)
+	if err != nil {
+		return err
+	}
+	r.Status = ReportDismissed
+	if uphold {
+		r.Status = ReportUpheld
+	}
+	r.ResolverID = &resolverID
+	r.Resolution = note
+	r.ResolvedAt = &now
+	return nil
+}
+
+func normalizeNote(note string, required bool) (string, error) {
+	note = strings.TrimSpace(note)
+	switch {
+	case required && note == "":
+		return "", ErrNoteRequired
+	case utf8.RuneCountInString(note) > MaxNoteLength:
+		return "", ErrNoteLength
+	}
+	return note, nil
+}
@@ -0,0 +1,297 @@
+// Package a11y provides automated WCAG checks for rendered HTML
+package a11y
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Rule identifiers reported in issues
+const (
+	RuleImageAlt        = "img-alt"
+	RuleFormLabel       = "form-label"
+	RuleControlName     = "control-name"
+	RuleDocumentLang    = "html-lang"
+	RuleContrast        = "color-contrast"
+	RuleAriaHiddenFocus = "aria-hidden-focus"
+)
+
+// Severity of an audit issue
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a single accessibility finding
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Page     string   `json:"page"`
+	Element  string   `json:"element"`
+	Message  string   `json:"message"`
+}
+
+// Report aggregates issues across audited pages
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Pages       []string  `json:"pages"`
+	Issues      []Issue   `json:"issues"`
+}
+
+// Add appends page issues to the report
+func (r *Report) Add(page string, issues []Issue) {
+	r.Pages = append(r.Pages, page)
+	r.Issues = append(r.Issues, issues...)
+}
+
+// Errors returns the number of error-severity issues
+func (r *Report) Errors() int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteText writes a plain-text summary grouped by page
+func (r *Report) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "Accessibility audit: %d pages, %d issues (%d errors)\n", len(r.Pages), len(r.Issues), r.Errors())
+
+	byPage := make(map[string][]Issue)
+	var pages []string
+	for _, issue := range r.Issues {
+		if _, ok := byPage[issue.Page]; !ok {
+			pages = append(pages, issue.Page)
+		}
+		byPage[issue.Page] = append(byPage[issue.Page], issue)
+	}
+	sort.Strings(pages)
+
+	for _, page := range pages {
+		fmt.Fprintf(w, "\n%s\n", page)
+		for _, issue := range byPage[page] {
+			fmt.Fprintf(w, "  [%s] %s: %s (%s)\n", issue.Severity, issue.Rule, issue.Message, issue.Element)
+		}
+	}
+}
+
+// CheckHTML parses a page or fragment and runs every markup rule on it
+func CheckHTML(page string, r io.Reader) ([]Issue, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", page, err)
+	}
+
+	c := &checker{page: page, labelFor: make(map[string]bool)}
+	c.collectLabels(doc)
+	c.walk(doc, false, false)
+	return c.issues, nil
+}
+
+// CheckDocument is CheckHTML for complete pages; it also requires <html lang>
+func CheckDocument(page string, r io.Reader) ([]Issue, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", page, err)
+	}
+
+	c := &checker{page: page, labelFor: make(map[string]bool)}
+	c.collectLabels(doc)
+	if root := findElement(doc, atom.Html); root != nil && strings.TrimSpace(attr(root, "lang")) == "" {
+		c.report(RuleDocumentLang, SeverityError, root, "document is missing a lang attribute")
+	}
+	c.walk(doc, false, false)
+	return c.issues, nil
+}
+
+type checker struct {
+	page     string
+	labelFor map[string]bool
+	issues   []Issue
+}
+
+func (c *checker) report(rule string, severity Severity, n *html.Node, message string) {
+	c.issues = append(c.issues, Issue{
+		Rule:     rule,
+		Severity: severity,
+		Page:     c.page,
+		Element:  describe(n),
+		Message:  message,
+	})
+}
+
+// collectLabels records every <label for="..."> target
+func (c *checker) collectLabels(n *html.Node) {
+	if n.Type == html.ElementNode && n.DataAtom == atom.Label {
+		if id := attr(n, "for"); id != "" {
+			c.labelFor[id] = true
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.collectLabels(child)
+	}
+}
+
+// walk visits elements, tracking whether an ancestor is a <label> or aria-hidden
+func (c *checker) walk(n *html.Node, inLabel, hidden bool) {
+	if n.Type == html.ElementNode {
+		if attr(n, "aria-hidden") == "true" {
+			hidden = true
+		}
+		c.checkElement(n, inLabel, hidden)
+		if n.DataAtom == atom.Label {
+			inLabel = true
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.walk(child, inLabel, hidden)
+	}
+}
+
+func (c *checker) checkElement(n *html.Node, inLabel, hidden bool) {
+	switch n.DataAtom {
+	case atom.Img:
+		if _, ok := attrOK(n, "alt"); !ok && attr(n, "role") != "presentation" {
+			c.report(RuleImageAlt, SeverityError, n, "image has no alt attribute")
+		}
+	case atom.Input, atom.Select, atom.Textarea:
+		if typ := attr(n, "type"); typ == "hidden" || typ == "submit" || typ == "button" {
+			break
+		}
+		if !inLabel && !c.labelFor[attr(n, "id")] && !hasARIAName(n) {
+			c.report(RuleFormLabel, SeverityError, n, "form control has no associated label")
+		}
+	case atom.Button, atom.A:
+		if n.DataAtom == atom.A && attr(n, "href") == "" {
+			break
+		}
+		if !hasARIAName(n) && strings.TrimSpace(textContent(n)) == "" {
+			c.report(RuleControlName, SeverityError, n, "interactive element has no accessible name")
+		}
+		if hidden {
+			c.report(RuleAriaHiddenFocus, SeverityError, n, "focusable element is inside aria-hidden content")
+		}
+	}
+
+	c.checkInlineContrast(n)
+}
+
+// checkInlineContrast evaluates elements whose inline style sets both a text
+// colour and a solid background
+func (c *checker) checkInlineContrast(n *html.Node) {
+	style := attr(n, "style")
+	if style == "" {
+		return
+	}
+
+	decls := parseStyle(style)
+	fgValue, hasFg := decls["color"]
+	bgValue, hasBg := decls["background-color"]
+	if !hasBg {
+		bgValue, hasBg = decls["background"]
+	}
+	if !hasFg || !hasBg {
+		return
+	}
+
+	fg, errFg := ParseColor(fgValue)
+	bg, errBg := ParseColor(bgValue)
+	if errFg != nil || errBg != nil {
+		return
+	}
+
+	if ratio := ContrastRatio(fg, bg); ratio < MinContrastNormalText {
+		c.report(RuleContrast, SeverityWarning, n,
+			fmt.Sprintf("inline colours have contrast %.2f:1, below %.1f:1", ratio, MinContrastNormalText))
+	}
+}
+
+// hasARIAName reports whether an element is named through ARIA or a title
+func hasARIAName(n *html.Node) bool {
+	return strings.TrimSpace(attr(n, "aria-label")) != "" ||
+		strings.TrimSpace(attr(n, "aria-labelledby")) != "" ||
+		strings.TrimSpace(attr(n, "title")) != ""
+}
+
+// textContent concatenates text nodes and image alt text below n
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var visit func(*html.Node)
+	visit = func(node *html.Node) {
+		switch {
+		case node.Type == html.TextNode:
+			b.WriteString(node.Data)
+		case node.Type == html.ElementNode && node.DataAtom == atom.Img:
+			b.WriteString(attr(node, "alt"))
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			visit(child)
+		}
+	}
+	visit(n)
+	return b.String()
+}
+
+func parseStyle(style string) map[string]string {
+	decls := make(map[string]string)
+	for _, decl := range strings.Split(style, ";") {
+		name, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		decls[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+	return decls
+}
+
+func attr(n *html.Node, key string) string {
+	value, _ := attrOK(n, key)
+	return value
+}
+
+func attrOK(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := findElement(child, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// describe renders a short selector-like description of n for reports
+func describe(n *html.Node) string {
+	var b strings.Builder
+	b.WriteString(n.Data)
+	if id := attr(n, "id"); id != "" {
+		b.WriteString("#" + id)
+	}
+	if fragment := attr(n, "data-fragment"); fragment != "" {
+		b.WriteString(`[data-fragment="` + fragment + `"]`)
+	}
+	if name := attr(n, "name"); name != "" {
+		b.WriteString(`[name="` + name + `"]`)
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package a11y
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rules(issues []Issue) []string {
+	var out []string
+	for _, issue := range issues {
+		out = append(out, issue.Rule)
+	}
+	return out
+}
+
+func TestCheckHTMLFindsMarkupIssues(t *testing.T) {
+	markup := `
+<img src="a.png">
+<img src="b.png" alt="">
+<input name="q">
+<label for="email">Email</label><input id="email" name="email">
+<label>Name <input name="name"></label>
+<button></button>
+<button aria-label="Close">×</button>
+<a href="/x"><img src="c.png" alt="Home"></a>
+<div aria-hidden="true"><a href="/y">hidden</a></div>
+<p style="color: #777777; background: #888888">low</p>`
+
+	issues, err := CheckHTML("test", strings.NewReader(markup))
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		RuleImageAlt,
+		RuleFormLabel,
+		RuleControlName,
+		RuleAriaHiddenFocus,
+		RuleContrast,
+	}, rules(issues))
+}
+
+func TestCheckDocumentRequiresLang(t *testing.T) {
+	issues, err := CheckDocument("/", strings.NewReader(`<!DOCTYPE html><html><body></body></html>`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{RuleDocumentLang}, rules(issues))
+}
+
+func TestContrastRatio(t *testing.T) {
+	black, _ := ParseColor("#000")
+	white, _ := ParseColor("white")
+	assert.InDelta(t, 21.0, ContrastRatio(black, white), 0.01)
+
+	grey, err := ParseColor("rgb(119, 119, 119)")
+	require.NoError(t, err)
+	assert.InDelta(t, 4.48, ContrastRatio(grey, white), 0.01)
+
+	_, err = ParseColor("linear-gradient(red, blue)")
+	assert.Error(t, err)
+}
+
+func TestCheckPalette(t *testing.T) {
+	palette := map[string]string{"fg": "#777777", "bg": "#ffffff"}
+	issues := CheckPalette("light", palette, []PalettePair{{"fg", "bg"}})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "theme:light", issues[0].Page)
+}
+
+func TestCrawlerFollowsLocalLinks(t *testing.T) {
+	pages := map[string]string{
+		"/":       `<html lang="en"><body><a href="/a">A</a><a href="https://example.com">ext</a><a href="/logout">out</a></body></html>`,
+		"/a":      `<html lang="en"><body><img src="x.png"><a href="/">home</a></body></html>`,
+		"/logout": `<html><body></body></html>`,
+	}
+	fetch := func(ctx context.Context, path string) (int, []byte, error) {
+		body, ok := pages[path]
+		if !ok {
+			return 404, nil, nil
+		}
+		return 200, []byte(body), nil
+	}
+
+	report, err := NewCrawler(fetch, CrawlConfig{Exclude: []string{"/logout"}}).Crawl(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/", "/a"}, report.Pages)
+	assert.Equal(t, []string{RuleImageAlt}, rules(report.Issues))
+	assert.Equal(t, 1, report.Errors())
+}
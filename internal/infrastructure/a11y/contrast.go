@@ -0,0 +1,133 @@
+// Package a11y provides colour contrast checks for WCAG 2.1
+package a11y
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Minimum contrast ratios from WCAG 2.1 success criterion 1.4.3 (AA)
+const (
+	MinContrastNormalText = 4.5
+	MinContrastLargeText  = 3.0
+)
+
+// RGB is an sRGB colour with 8-bit channels
+type RGB struct {
+	R, G, B uint8
+}
+
+var namedColors = map[string]RGB{
+	"white": {255, 255, 255},
+	"black": {0, 0, 0},
+}
+
+// ParseColor parses #rgb, #rrggbb, rgb(r, g, b) and the named colours white
+// and black. Anything else (gradients, var(), transparency) is rejected so
+// callers can skip declarations that cannot be evaluated statically.
+func ParseColor(value string) (RGB, error) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	v = strings.TrimSuffix(v, "!important")
+	v = strings.TrimSpace(v)
+
+	if c, ok := namedColors[v]; ok {
+		return c, nil
+	}
+
+	if strings.HasPrefix(v, "#") {
+		hex := v[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) != 6 {
+			return RGB{}, fmt.Errorf("unsupported colour %q", value)
+		}
+		n, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return RGB{}, fmt.Errorf("invalid colour %q", value)
+		}
+		return RGB{uint8(n >> 16), uint8(n >> 8), uint8(n)}, nil
+	}
+
+	if strings.HasPrefix(v, "rgb(") && strings.HasSuffix(v, ")") {
+		parts := strings.Split(v[4:len(v)-1], ",")
+		if len(parts) != 3 {
+			return RGB{}, fmt.Errorf("unsupported colour %q", value)
+		}
+		var channels [3]uint8
+		for i, p := range parts {
+			n, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil || n < 0 || n > 255 {
+				return RGB{}, fmt.Errorf("invalid colour %q", value)
+			}
+			channels[i] = uint8(n)
+		}
+		return RGB{channels[0], channels[1], channels[2]}, nil
+	}
+
+	return RGB{}, fmt.Errorf("unsupported colour %q", value)
+}
+
+// luminance returns the WCAG relative luminance of c
+func (c RGB) luminance() float64 {
+	channel := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
+
+// ContrastRatio returns the WCAG contrast ratio between two colours (1 to 21)
+func ContrastRatio(a, b RGB) float64 {
+	la, lb := a.luminance(), b.luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// PalettePair names a foreground/background pair of custom properties
+type PalettePair struct {
+	Foreground string
+	Background string
+}
+
+// DefaultPalettePairs are the text/surface combinations the theme CSS uses
+var DefaultPalettePairs = []PalettePair{
+	{"color-text", "color-bg"},
+	{"color-text", "color-surface"},
+	{"color-text-muted", "color-bg"},
+	{"color-text-muted", "color-surface"},
+	{"color-primary-fg", "color-primary"},
+	{"color-danger", "color-surface"},
+	{"color-success", "color-surface"},
+}
+
+// CheckPalette checks every pair in a generated theme palette against the
+// normal-text AA threshold
+func CheckPalette(theme string, palette map[string]string, pairs []PalettePair) []Issue {
+	var issues []Issue
+	for _, pair := range pairs {
+		fg, errFg := ParseColor(palette[pair.Foreground])
+		bg, errBg := ParseColor(palette[pair.Background])
+		if errFg != nil || errBg != nil {
+			continue
+		}
+
+		if ratio := ContrastRatio(fg, bg); ratio < MinContrastNormalText {
+			issues = append(issues, Issue{
+				Rule:     RuleContrast,
+				Severity: SeverityError,
+				Page:     "theme:" + theme,
+				Element:  fmt.Sprintf("--%s on --%s", pair.Foreground, pair.Background),
+				Message:  fmt.Sprintf("contrast %.2f:1 is below %.1f:1", ratio, MinContrastNormalText),
+			})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,144 @@
+// Package a11y provides a same-origin crawler that audits rendered pages
+package a11y
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Fetcher returns the rendered HTML for a path on the audited site
+type Fetcher func(ctx context.Context, path string) (status int, body []byte, err error)
+
+// HTTPFetcher fetches pages from a running server
+func HTTPFetcher(client *http.Client, baseURL string) Fetcher {
+	base := strings.TrimRight(baseURL, "/")
+	return func(ctx context.Context, path string) (int, []byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+path, nil)
+		if err != nil {
+			return 0, nil, err
+		}
+		req.Header.Set("Accept", "text/html")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+		return resp.StatusCode, body, err
+	}
+}
+
+// CrawlConfig bounds a crawl
+type CrawlConfig struct {
+	StartPaths []string
+	MaxPages   int
+	// Exclude skips paths with any of these prefixes (e.g. /logout)
+	Exclude []string
+}
+
+// Crawler walks same-origin links breadth-first and audits each HTML page
+type Crawler struct {
+	fetch  Fetcher
+	config CrawlConfig
+}
+
+// NewCrawler creates a crawler over fetch
+func NewCrawler(fetch Fetcher, config CrawlConfig) *Crawler {
+	if len(config.StartPaths) == 0 {
+		config.StartPaths = []string{"/"}
+	}
+	if config.MaxPages <= 0 {
+		config.MaxPages = 50
+	}
+	return &Crawler{fetch: fetch, config: config}
+}
+
+// Crawl audits every reachable page up to MaxPages
+func (c *Crawler) Crawl(ctx context.Context) (*Report, error) {
+	report := &Report{GeneratedAt: time.Now()}
+
+	queue := append([]string(nil), c.config.StartPaths...)
+	seen := make(map[string]bool)
+	for _, p := range queue {
+		seen[p] = true
+	}
+
+	for len(queue) > 0 && len(report.Pages) < c.config.MaxPages {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		path := queue[0]
+		queue = queue[1:]
+
+		status, body, err := c.fetch(ctx, path)
+		if err != nil {
+			return report, fmt.Errorf("fetch %s: %w", path, err)
+		}
+		if status >= 300 {
+			continue
+		}
+
+		issues, err := CheckDocument(path, bytes.NewReader(body))
+		if err != nil {
+			return report, err
+		}
+		report.Add(path, issues)
+
+		for _, link := range c.links(body) {
+			if !seen[link] {
+				seen[link] = true
+				queue = append(queue, link)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// links extracts same-origin, non-excluded paths from anchors
+func (c *Crawler) links(body []byte) []string {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+
+	var out []string
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.A {
+			if path, ok := c.localPath(attr(n, "href")); ok {
+				out = append(out, path)
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			visit(child)
+		}
+	}
+	visit(doc)
+	return out
+}
+
+func (c *Crawler) localPath(href string) (string, bool) {
+	u, err := url.Parse(href)
+	if err != nil || u.IsAbs() || u.Host != "" || !strings.HasPrefix(u.Path, "/") {
+		return "", false
+	}
+	for _, prefix := range c.config.Exclude {
+		if strings.HasPrefix(u.Path, prefix) {
+			return "", false
+		}
+	}
+	return u.Path, true
+}
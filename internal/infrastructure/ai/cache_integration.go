@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/alchemorsel/v3/internal/domain/recipe"
 	"github.com/alchemorsel/v3/internal/infrastructure/ai/ollama"
 	"github.com/alchemorsel/v3/internal/infrastructure/cache"
 	"github.com/alchemorsel/v3/internal/ports/outbound"
@@ -122,14 +123,34 @@ func (c *CachedAIService) AnalyzeNutrition(ctx context.Context, ingredients []st
 }
 
 // Pass-through methods for non-cached operations
-func (c *CachedAIService) GenerateDescription(ctx context.Context, recipe *outbound.Recipe) (string, error) {
+func (c *CachedAIService) GenerateDescription(ctx context.Context, recipe *recipe.Recipe) (string, error) {
 	return c.client.GenerateDescription(ctx, recipe)
 }
 
-func (c *CachedAIService) ClassifyRecipe(ctx context.Context, recipe *outbound.Recipe) (*outbound.RecipeClassification, error) {
+func (c *CachedAIService) ClassifyRecipe(ctx context.Context, recipe *recipe.Recipe) (*outbound.RecipeClassification, error) {
 	return c.client.ClassifyRecipe(ctx, recipe)
 }
 
+func (c *CachedAIService) SuggestSearchQueries(ctx context.Context, query string) ([]string, error) {
+	return c.client.SuggestSearchQueries(ctx, query)
+}
+
+func (c *CachedAIService) SuggestStepTechniques(ctx context.Context, steps []string, techniques []string) ([][]string, error) {
+	return c.client.SuggestStepTechniques(ctx, steps, techniques)
+}
+
+func (c *CachedAIService) Translate(ctx context.Context, texts []string, from, to string) (*outbound.AITranslation, error) {
+	return c.client.Translate(ctx, texts, from, to)
+}
+
+func (c *CachedAIService) Embed(ctx context.Context, texts []string) (*outbound.AIEmbeddings, error) {
+	return c.client.Embed(ctx, texts)
+}
+
+func (c *CachedAIService) SuggestSubstitutes(ctx context.Context, ingredient string) (*outbound.AISubstitutes, error) {
+	return c.client.SuggestSubstitutes(ctx, ingredient)
+}
+
 // EnableCache enables or disables caching
 func (c *CachedAIService) EnableCache(enabled bool) {
 	c.enabled = enabled
@@ -130,10 +130,6 @@ func (h *HealthChecker) checkOllamaModels(ctx context.Context, status *AIHealthS
 
 // checkOpenAIHealth checks if OpenAI API is configured and accessible
 func (h *HealthChecker) checkOpenAIHealth(ctx context.Context) error {
-	// Create a context with timeout for health check
-	healthCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
 	if h.openaiClient == nil {
 		return fmt.Errorf("openai client not initialized")
 	}
@@ -0,0 +1,208 @@
+// Package mock provides canned AI answers for demos and local runs
+// Responses depend only on the input, so the same request always gets the
+// same answer and no model or network is needed
+package mock
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// Client implements the AIService interface without calling a model
+type Client struct {
+	logger *zap.Logger
+}
+
+// NewClient creates a new canned AI client
+func NewClient(logger *zap.Logger) *Client {
+	logger.Info("Mock AI client initialized; responses are canned")
+	return &Client{logger: logger}
+}
+
+// dishes maps prompt keywords to a dish and its main ingredient, checked in
+// order so the first match wins
+var dishes = []struct {
+	keyword    string
+	dish       string
+	ingredient outbound.AIIngredient
+}{
+	{"chicken", "Roast Chicken", outbound.AIIngredient{Name: "chicken thighs", Amount: 1.5, Unit: "lb"}},
+	{"pasta", "Pasta", outbound.AIIngredient{Name: "spaghetti", Amount: 12, Unit: "oz"}},
+	{"salmon", "Baked Salmon", outbound.AIIngredient{Name: "salmon fillets", Amount: 2, Unit: "pieces"}},
+	{"soup", "Soup", outbound.AIIngredient{Name: "vegetable stock", Amount: 4, Unit: "cups"}},
+	{"salad", "Salad", outbound.AIIngredient{Name: "mixed greens", Amount: 6, Unit: "cups"}},
+	{"curry", "Curry", outbound.AIIngredient{Name: "coconut milk", Amount: 1, Unit: "can"}},
+	{"taco", "Tacos", outbound.AIIngredient{Name: "corn tortillas", Amount: 8, Unit: "pieces"}},
+}
+
+// GenerateRecipe returns a recipe built from the prompt's keywords
+func (c *Client) GenerateRecipe(ctx context.Context, prompt string, constraints outbound.AIConstraints) (*outbound.AIRecipeResponse, error) {
+	title := "Skillet Supper"
+	ingredients := []outbound.AIIngredient{
+		{Name: "olive oil", Amount: 2, Unit: "tbsp"},
+		{Name: "garlic", Amount: 2, Unit: "cloves"},
+		{Name: "salt", Amount: 1, Unit: "tsp"},
+	}
+	lower := strings.ToLower(prompt)
+	for _, d := range dishes {
+		if strings.Contains(lower, d.keyword) {
+			title = d.dish
+			ingredients = append(ingredients, d.ingredient)
+			break
+		}
+	}
+	if constraints.Cuisine != "" {
+		title = strings.ToUpper(constraints.Cuisine[:1]) + constraints.Cuisine[1:] + " " + title
+	}
+
+	tags := []string{"ai-generated", "demo"}
+	if constraints.Cuisine != "" {
+		tags = append(tags, strings.ToLower(constraints.Cuisine))
+	}
+	tags = append(tags, constraints.Dietary...)
+
+	return &outbound.AIRecipeResponse{
+		Title:       title,
+		Description: fmt.Sprintf("A sample recipe for %q from the demo AI.", prompt),
+		Ingredients: ingredients,
+		Instructions: []string{
+			"Heat the olive oil in a large pan over medium heat.",
+			"Add the garlic and cook for one minute.",
+			"Add the remaining ingredients and cook until done.",
+			"Season with salt to taste and serve.",
+		},
+		Nutrition:  c.nutrition(len(ingredients)),
+		Tags:       tags,
+		Confidence: 0.5,
+		Language:   "en",
+	}, nil
+}
+
+// SuggestIngredients suggests common pairings not already in the list
+func (c *Client) SuggestIngredients(ctx context.Context, partial []string) ([]string, error) {
+	have := make(map[string]bool, len(partial))
+	for _, name := range partial {
+		have[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	var suggestions []string
+	for _, name := range []string{"garlic", "onion", "lemon", "parsley", "black pepper", "butter"} {
+		if !have[name] {
+			suggestions = append(suggestions, name)
+		}
+	}
+	return suggestions, nil
+}
+
+// AnalyzeNutrition estimates nutrition from the number of ingredients
+func (c *Client) AnalyzeNutrition(ctx context.Context, ingredients []string) (*outbound.NutritionInfo, error) {
+	return c.nutrition(len(ingredients)), nil
+}
+
+// GenerateDescription describes the recipe by its title
+func (c *Client) GenerateDescription(ctx context.Context, recipe *recipe.Recipe) (string, error) {
+	return fmt.Sprintf("%s, a sample description from the demo AI.", recipe.Title()), nil
+}
+
+// ClassifyRecipe always returns the same classification
+func (c *Client) ClassifyRecipe(ctx context.Context, recipe *recipe.Recipe) (*outbound.RecipeClassification, error) {
+	return &outbound.RecipeClassification{
+		Cuisine:    "american",
+		Category:   "main-dish",
+		Difficulty: "easy",
+		Dietary:    []string{},
+		Confidence: 0.5,
+	}, nil
+}
+
+// SuggestSearchQueries has no corrections to offer
+func (c *Client) SuggestSearchQueries(ctx context.Context, query string) ([]string, error) {
+	return []string{}, nil
+}
+
+// SuggestStepTechniques leaves technique links to name matching
+func (c *Client) SuggestStepTechniques(ctx context.Context, steps []string, techniques []string) ([][]string, error) {
+	return [][]string{}, nil
+}
+
+// SuggestSubstitutes leaves substitutes to the knowledge base
+func (c *Client) SuggestSubstitutes(ctx context.Context, ingredient string) (*outbound.AISubstitutes, error) {
+	return &outbound.AISubstitutes{Substitutes: []outbound.AISubstitute{}, Model: "mock"}, nil
+}
+
+// Translate marks each text with the target language instead of
+// translating it
+func (c *Client) Translate(ctx context.Context, texts []string, from, to string) (*outbound.AITranslation, error) {
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		translated[i] = fmt.Sprintf("[%s] %s", to, text)
+	}
+	return &outbound.AITranslation{Texts: translated, Model: "mock"}, nil
+}
+
+// embeddingSize is the length of the mock vectors
+const embeddingSize = 64
+
+// concepts folds words alike in meaning onto one concept, so search by
+// meaning finds a stew for "cozy winter dinner" without a model
+var concepts = map[string]string{
+	"cozy": "comfort", "hearty": "comfort", "winter": "comfort", "warming": "comfort",
+	"stew": "comfort", "soup": "comfort", "braise": "comfort", "chili": "comfort",
+	"summer": "fresh", "light": "fresh", "salad": "fresh", "refreshing": "fresh", "lemon": "fresh",
+	"quick": "quick", "easy": "quick", "weeknight": "quick", "fast": "quick",
+	"dessert": "sweet", "cake": "sweet", "cookie": "sweet", "chocolate": "sweet", "treat": "sweet",
+	"spicy": "spicy", "hot": "spicy", "curry": "spicy", "jalapeno": "spicy",
+}
+
+// Embed hashes each word, and the concept it belongs to, into a unit
+// vector, so texts sharing words or concepts are similar
+func (c *Client) Embed(ctx context.Context, texts []string) (*outbound.AIEmbeddings, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vector := make([]float32, embeddingSize)
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) })
+		for _, word := range words {
+			vector[bucket(word)]++
+			if concept, ok := concepts[word]; ok {
+				vector[bucket("concept:"+concept)] += 2
+			}
+		}
+		var norm float64
+		for _, v := range vector {
+			norm += float64(v * v)
+		}
+		if norm > 0 {
+			scale := float32(1 / math.Sqrt(norm))
+			for j := range vector {
+				vector[j] *= scale
+			}
+		}
+		vectors[i] = vector
+	}
+	return &outbound.AIEmbeddings{Vectors: vectors, Model: "mock-embedding"}, nil
+}
+
+func bucket(word string) int {
+	h := fnv.New32a()
+	h.Write([]byte(word))
+	return int(h.Sum32() % embeddingSize)
+}
+
+func (c *Client) nutrition(ingredients int) *outbound.NutritionInfo {
+	return &outbound.NutritionInfo{
+		Calories: 120 * ingredients,
+		Protein:  6 * float64(ingredients),
+		Carbs:    10 * float64(ingredients),
+		Fat:      4 * float64(ingredients),
+		Fiber:    1.5 * float64(ingredients),
+		Sugar:    2 * float64(ingredients),
+		Sodium:   150 * float64(ingredients),
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"time"
 
 	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/substitutes"
+	"github.com/alchemorsel/v3/internal/domain/recipe/translation"
 	"github.com/alchemorsel/v3/internal/ports/outbound"
 	"go.uber.org/zap"
 )
@@ -22,6 +24,8 @@ import (
 type Client struct {
 	baseURL string
 	model   string
+	// embedModel makes the vectors for search by meaning
+	embedModel string
 	client  *http.Client
 	logger  *zap.Logger
 	timeout time.Duration
@@ -40,6 +44,11 @@ func NewClient(logger *zap.Logger) *Client {
 		model = "llama3.2:3b"
 	}
 	
+	embedModel := os.Getenv("ALCHEMORSEL_OLLAMA_EMBED_MODEL")
+	if embedModel == "" {
+		embedModel = "nomic-embed-text"
+	}
+	
 	timeout := 30 * time.Second
 	if timeoutStr := os.Getenv("ALCHEMORSEL_OLLAMA_TIMEOUT"); timeoutStr != "" {
 		if parsedTimeout, err := time.ParseDuration(timeoutStr); err == nil {
@@ -53,8 +62,9 @@ func NewClient(logger *zap.Logger) *Client {
 		zap.Duration("timeout", timeout))
 
 	return &Client{
-		baseURL: baseURL,
-		model:   model,
+		baseURL:    baseURL,
+		model:      model,
+		embedModel: embedModel,
 		client: &http.Client{
 			Timeout: timeout,
 		},
@@ -189,6 +199,8 @@ func (c *Client) GenerateRecipe(ctx context.Context, prompt string, constraints
 		return c.generateFallbackRecipe(prompt, constraints)
 	}
 
+	aiResponse.Language = recipeLanguage(constraints)
+
 	c.logger.Info("Recipe generated successfully via Ollama",
 		zap.String("title", aiResponse.Title),
 		zap.Float64("confidence", aiResponse.Confidence))
@@ -248,6 +260,9 @@ Requirements:`
 	if len(constraints.AvoidIngredients) > 0 {
 		systemPrompt += fmt.Sprintf("\n- Avoid these ingredients: %s", strings.Join(constraints.AvoidIngredients, ", "))
 	}
+	if constraints.Language != "" {
+		systemPrompt += fmt.Sprintf("\n- Write the title, description, ingredient names, units, instructions and tags in %s, keeping the JSON keys in English", translation.Name(constraints.Language))
+	}
 
 	systemPrompt += "\n\nRemember: Respond with ONLY valid JSON. No additional text, explanations, or formatting."
 
@@ -413,6 +428,7 @@ func (c *Client) generateFallbackRecipe(prompt string, constraints outbound.AICo
 		Instructions: instructions,
 		Tags:         tags,
 		Confidence:   0.6, // Lower confidence for fallback recipes
+		Language:     "en", // Fallback recipes are only written in English
 		Nutrition: &outbound.NutritionInfo{
 			Calories: 350,
 			Protein:  20.0,
@@ -457,6 +473,183 @@ func (c *Client) SuggestIngredients(ctx context.Context, partial []string) ([]st
 	return suggestions, nil
 }
 
+// SuggestSearchQueries proposes corrected or broader queries for a search
+// that found nothing. Without a reachable model there are no suggestions.
+func (c *Client) SuggestSearchQueries(ctx context.Context, query string) ([]string, error) {
+	if err := c.HealthCheck(ctx); err != nil {
+		return []string{}, nil
+	}
+
+	prompt := fmt.Sprintf("A recipe search for %q returned no results. Suggest up to 3 corrected or broader search queries, fixing spelling and using common dish, cuisine or ingredient names.\nRespond with ONLY a JSON array of queries, like: [\"query1\", \"query2\"]", query)
+
+	response, err := c.generateSimpleCompletion(ctx, prompt)
+	if err != nil {
+		return []string{}, nil
+	}
+
+	var suggestions []string
+	if err := json.Unmarshal([]byte(response), &suggestions); err != nil {
+		c.logger.Debug("Unparseable search suggestions", zap.String("response", response))
+		return []string{}, nil
+	}
+
+	return suggestions, nil
+}
+
+// SuggestStepTechniques asks the model which of the library's techniques
+// each recipe step calls for. Without a reachable model, or with an answer
+// that does not parse, there are no suggestions.
+func (c *Client) SuggestStepTechniques(ctx context.Context, steps []string, techniques []string) ([][]string, error) {
+	if len(steps) == 0 || len(techniques) == 0 {
+		return [][]string{}, nil
+	}
+	if err := c.HealthCheck(ctx); err != nil {
+		return [][]string{}, nil
+	}
+
+	var numbered strings.Builder
+	for i, step := range steps {
+		fmt.Fprintf(&numbered, "%d. %s\n", i+1, step)
+	}
+	prompt := fmt.Sprintf("Techniques: %s\n\nRecipe steps:\n%s\nFor each step, list the techniques from the list above that the step uses, at most 3, or none.\nRespond with ONLY a JSON array with one array of technique names per step, like: [[\"Julienne\"], []]",
+		strings.Join(techniques, ", "), numbered.String())
+
+	response, err := c.generateSimpleCompletion(ctx, prompt)
+	if err != nil {
+		return [][]string{}, nil
+	}
+
+	var suggestions [][]string
+	if err := json.Unmarshal([]byte(response), &suggestions); err != nil || len(suggestions) != len(steps) {
+		c.logger.Debug("Unparseable technique suggestions", zap.String("response", response))
+		return [][]string{}, nil
+	}
+
+	return suggestions, nil
+}
+
+// Translate asks the model to translate recipe texts. Unlike suggestions a
+// translation cannot be made up, so an unreachable model or an answer that
+// does not parse is an error.
+func (c *Client) Translate(ctx context.Context, texts []string, from, to string) (*outbound.AITranslation, error) {
+	if len(texts) == 0 {
+		return &outbound.AITranslation{Texts: []string{}, Model: c.model}, nil
+	}
+	if err := c.HealthCheck(ctx); err != nil {
+		return nil, fmt.Errorf("ollama unavailable: %w", err)
+	}
+
+	input, err := json.Marshal(texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal texts: %w", err)
+	}
+	prompt := fmt.Sprintf("Translate each recipe text in this JSON array from %s to %s. Keep numbers, quantities and units as they are, and use the cooking terms a home cook in that language would.\n%s\nRespond with ONLY a JSON array of the translations in the same order.",
+		translation.Name(from), translation.Name(to), input)
+
+	response, err := c.generateSimpleCompletion(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("ollama translation failed: %w", err)
+	}
+
+	var translated []string
+	if err := json.Unmarshal([]byte(response), &translated); err != nil || len(translated) != len(texts) {
+		c.logger.Debug("Unparseable translation", zap.String("response", response))
+		return nil, fmt.Errorf("ollama returned an unparseable translation")
+	}
+
+	return &outbound.AITranslation{Texts: translated, Model: c.model}, nil
+}
+
+// EmbedRequest asks the embed API for one vector per input
+type EmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// EmbedResponse holds the vectors in the order of the inputs
+type EmbedResponse struct {
+	Model      string      `json:"model"`
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// Embed vectorizes texts with the embedding model. Vectors cannot be made
+// up, so an unreachable model is an error.
+func (c *Client) Embed(ctx context.Context, texts []string) (*outbound.AIEmbeddings, error) {
+	if len(texts) == 0 {
+		return &outbound.AIEmbeddings{Vectors: [][]float32{}, Model: c.embedModel}, nil
+	}
+
+	jsonBody, err := json.Marshal(EmbedRequest{Model: c.embedModel, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/embed", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var embedResp EmbedResponse
+	if err := json.Unmarshal(body, &embedResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(embedResp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d texts", len(embedResp.Embeddings), len(texts))
+	}
+
+	return &outbound.AIEmbeddings{Vectors: embedResp.Embeddings, Model: c.embedModel}, nil
+}
+
+// SuggestSubstitutes asks the model what a cook could use in place of an
+// ingredient. Without a reachable model, or with an answer that does not
+// parse, there are no suggestions.
+func (c *Client) SuggestSubstitutes(ctx context.Context, ingredient string) (*outbound.AISubstitutes, error) {
+	none := &outbound.AISubstitutes{Substitutes: []outbound.AISubstitute{}, Model: c.model}
+	if err := c.HealthCheck(ctx); err != nil {
+		return none, nil
+	}
+
+	prompt := fmt.Sprintf("Suggest up to 3 common substitutes a home cook could use in place of %q.\nFor each give the name, how much replaces the ingredient, a short note on how it changes the dish, and which of these it contains: %s.\nRespond with ONLY a JSON array, like: [{\"name\": \"coconut oil\", \"ratio\": \"1 cup per cup\", \"notes\": \"Solid at room temperature\", \"contains\": []}]",
+		ingredient, strings.Join(substitutes.Traits(), ", "))
+
+	response, err := c.generateSimpleCompletion(ctx, prompt)
+	if err != nil {
+		return none, nil
+	}
+
+	var suggested []outbound.AISubstitute
+	if err := json.Unmarshal([]byte(response), &suggested); err != nil {
+		c.logger.Debug("Unparseable substitute suggestions", zap.String("response", response))
+		return none, nil
+	}
+
+	none.Substitutes = suggested
+	return none, nil
+}
+
+// recipeLanguage is the language a recipe was asked for in
+func recipeLanguage(constraints outbound.AIConstraints) string {
+	if constraints.Language != "" {
+		return constraints.Language
+	}
+	return "en"
+}
+
 // AnalyzeNutrition analyzes nutrition using Ollama
 func (c *Client) AnalyzeNutrition(ctx context.Context, ingredients []string) (*outbound.NutritionInfo, error) {
 	if err := c.HealthCheck(ctx); err != nil {
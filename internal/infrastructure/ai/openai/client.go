@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/substitutes"
+	"github.com/alchemorsel/v3/internal/domain/recipe/translation"
 	"github.com/alchemorsel/v3/internal/ports/outbound"
 	"go.uber.org/zap"
 )
@@ -137,6 +139,10 @@ func (c *Client) GenerateRecipe(ctx context.Context, prompt string, constraints
 		// Fallback to mock recipe
 		return c.generateMockRecipe(prompt, constraints)
 	}
+	aiResponse.Language = constraints.Language
+	if aiResponse.Language == "" {
+		aiResponse.Language = "en"
+	}
 
 	return aiResponse, nil
 }
@@ -193,6 +199,9 @@ Required JSON format:
 	if len(constraints.AvoidIngredients) > 0 {
 		systemPrompt += fmt.Sprintf("\nAvoid these ingredients: %s", strings.Join(constraints.AvoidIngredients, ", "))
 	}
+	if constraints.Language != "" {
+		systemPrompt += fmt.Sprintf("\nWrite the title, description, ingredient names, units, instructions and tags in %s, keeping the JSON keys in English.", translation.Name(constraints.Language))
+	}
 
 	systemPrompt += "\n\nRemember: Respond with ONLY valid JSON. No additional text or formatting."
 
@@ -215,14 +224,8 @@ func (c *Client) buildUserPrompt(prompt string, constraints outbound.AIConstrain
 
 // callOpenAI makes the actual API call to OpenAI or Ollama
 func (c *Client) callOpenAI(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
-	// Use llama3.2:3b for Ollama, gpt-3.5-turbo for OpenAI
-	model := "gpt-3.5-turbo"
-	if strings.Contains(c.baseURL, "localhost:11434") {
-		model = "llama3.2:3b"
-	}
-	
 	reqBody := ChatCompletionRequest{
-		Model: model,
+		Model: c.chatModel(),
 		Messages: []Message{
 			{Role: "system", Content: systemPrompt},
 			{Role: "user", Content: userPrompt},
@@ -277,6 +280,14 @@ func (c *Client) callOpenAI(ctx context.Context, systemPrompt, userPrompt string
 	return chatResp.Choices[0].Message.Content, nil
 }
 
+// chatModel is llama3.2:3b for Ollama, gpt-3.5-turbo for OpenAI
+func (c *Client) chatModel() string {
+	if strings.Contains(c.baseURL, "localhost:11434") {
+		return "llama3.2:3b"
+	}
+	return "gpt-3.5-turbo"
+}
+
 // parseRecipeResponse parses the JSON response from OpenAI
 func (c *Client) parseRecipeResponse(response string) (*outbound.AIRecipeResponse, error) {
 	// Clean the response - sometimes GPT includes extra text
@@ -359,6 +370,7 @@ func (c *Client) generateMockRecipe(prompt string, constraints outbound.AIConstr
 		Instructions: instructions,
 		Tags:         tags,
 		Confidence:   0.6, // Lower confidence for mock recipes
+		Language:     "en", // Mock recipes are only written in English
 		Nutrition: &outbound.NutritionInfo{
 			Calories: 350,
 			Protein:  20.0,
@@ -468,6 +480,150 @@ func (c *Client) SuggestIngredients(ctx context.Context, partial []string) ([]st
 	return suggestions, nil
 }
 
+func (c *Client) SuggestSearchQueries(ctx context.Context, query string) ([]string, error) {
+	// No correction model is wired up for OpenAI yet
+	return []string{}, nil
+}
+
+func (c *Client) SuggestStepTechniques(ctx context.Context, steps []string, techniques []string) ([][]string, error) {
+	// No technique model is wired up for OpenAI yet
+	return [][]string{}, nil
+}
+
+// Translate asks the model to translate recipe texts. Without an API key
+// there is no model, and a translation cannot be made up.
+func (c *Client) Translate(ctx context.Context, texts []string, from, to string) (*outbound.AITranslation, error) {
+	if len(texts) == 0 {
+		return &outbound.AITranslation{Texts: []string{}, Model: c.chatModel()}, nil
+	}
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("OpenAI API key is not configured")
+	}
+
+	input, err := json.Marshal(texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal texts: %w", err)
+	}
+	systemPrompt := fmt.Sprintf("You translate recipes from %s to %s. Keep numbers, quantities and units as they are, and use the cooking terms a home cook in that language would. Respond with ONLY a JSON array of the translations in the same order.",
+		translation.Name(from), translation.Name(to))
+
+	response, err := c.callOpenAI(ctx, systemPrompt, string(input))
+	if err != nil {
+		return nil, err
+	}
+
+	var translated []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(response)), &translated); err != nil || len(translated) != len(texts) {
+		return nil, fmt.Errorf("OpenAI returned an unparseable translation")
+	}
+
+	return &outbound.AITranslation{Texts: translated, Model: c.chatModel()}, nil
+}
+
+// EmbeddingRequest asks the embeddings endpoint for one vector per input
+type EmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// EmbeddingResponse holds the vectors, each with the index of its input
+type EmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed vectorizes texts with the embeddings endpoint. Without an API key
+// there is no model to make vectors comparable with stored ones.
+func (c *Client) Embed(ctx context.Context, texts []string) (*outbound.AIEmbeddings, error) {
+	if len(texts) == 0 {
+		return &outbound.AIEmbeddings{Vectors: [][]float32{}, Model: c.embeddingModel()}, nil
+	}
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("OpenAI API key is not configured")
+	}
+
+	jsonBody, err := json.Marshal(EmbeddingRequest{Model: c.embeddingModel(), Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/embeddings", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var embedResp EmbeddingResponse
+	if err := json.Unmarshal(body, &embedResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range embedResp.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			vectors[d.Index] = d.Embedding
+		}
+	}
+	for _, vector := range vectors {
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("OpenAI returned fewer embeddings than texts")
+		}
+	}
+
+	return &outbound.AIEmbeddings{Vectors: vectors, Model: c.embeddingModel()}, nil
+}
+
+// SuggestSubstitutes asks the model what a cook could use in place of an
+// ingredient. Without an API key, or with an answer that does not parse,
+// there are no suggestions.
+func (c *Client) SuggestSubstitutes(ctx context.Context, ingredient string) (*outbound.AISubstitutes, error) {
+	none := &outbound.AISubstitutes{Substitutes: []outbound.AISubstitute{}, Model: c.chatModel()}
+	if c.apiKey == "" {
+		return none, nil
+	}
+
+	systemPrompt := fmt.Sprintf("You suggest up to 3 common ingredient substitutes for home cooks. For each give the name, how much replaces the ingredient, a short note on how it changes the dish, and which of these it contains: %s. Respond with ONLY a JSON array of objects with the keys name, ratio, notes and contains.",
+		strings.Join(substitutes.Traits(), ", "))
+
+	response, err := c.callOpenAI(ctx, systemPrompt, ingredient)
+	if err != nil {
+		return none, nil
+	}
+
+	var suggested []outbound.AISubstitute
+	if err := json.Unmarshal([]byte(strings.TrimSpace(response)), &suggested); err != nil {
+		return none, nil
+	}
+
+	none.Substitutes = suggested
+	return none, nil
+}
+
+// embeddingModel is nomic-embed-text for Ollama, text-embedding-3-small for
+// OpenAI
+func (c *Client) embeddingModel() string {
+	if strings.Contains(c.baseURL, "localhost:11434") {
+		return "nomic-embed-text"
+	}
+	return "text-embedding-3-small"
+}
+
 func (c *Client) AnalyzeNutrition(ctx context.Context, ingredients []string) (*outbound.NutritionInfo, error) {
 	// Mock nutrition analysis
 	return &outbound.NutritionInfo{
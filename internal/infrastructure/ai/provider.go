@@ -0,0 +1,271 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"go.uber.org/zap"
+)
+
+// Supported providers
+const (
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+	ProviderOllama    = "ollama"
+	ProviderMock      = "mock"
+)
+
+// Models used when the configuration does not name one
+const (
+	defaultAnthropicModel = "claude-3-5-haiku-latest"
+	defaultOllamaModel    = "llama3.2:3b"
+	defaultOllamaHost     = "http://localhost:11434"
+)
+
+// Message roles
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message is one turn of a conversation
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request is a completion request. JSON asks the backend for a single JSON
+// object where it supports that.
+type Request struct {
+	System   string
+	Messages []Message
+	JSON     bool
+}
+
+// Provider is a large language model backend
+type Provider interface {
+	Name() string
+	Complete(ctx context.Context, req Request) (string, error)
+}
+
+// NewProvider returns the configured provider, or nil when none is
+// configured and callers should use their template fallback. Hosted
+// providers count as configured only once their API key is set.
+func NewProvider(cfg config.AIConfig, logger *zap.Logger) (Provider, error) {
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	base := settings{maxTokens: cfg.MaxTokens, temperature: cfg.Temperature, client: &http.Client{Timeout: timeout}}
+
+	switch strings.ToLower(cfg.Provider) {
+	case ProviderOpenAI:
+		if cfg.OpenAIKey == "" {
+			logger.Info("No OpenAI key configured, using recipe templates")
+			return nil, nil
+		}
+		base.model = cfg.OpenAIModel
+		return &OpenAIProvider{settings: base, apiKey: cfg.OpenAIKey, baseURL: "https://api.openai.com/v1"}, nil
+	case ProviderAnthropic:
+		if cfg.AnthropicKey == "" {
+			logger.Info("No Anthropic key configured, using recipe templates")
+			return nil, nil
+		}
+		base.model = orDefault(cfg.AnthropicModel, defaultAnthropicModel)
+		return &AnthropicProvider{settings: base, apiKey: cfg.AnthropicKey, baseURL: "https://api.anthropic.com/v1"}, nil
+	case ProviderOllama:
+		base.model = orDefault(cfg.OllamaModel, defaultOllamaModel)
+		if cfg.OllamaTimeout > 0 {
+			base.client.Timeout = cfg.OllamaTimeout
+		}
+		return &OllamaProvider{settings: base, host: strings.TrimSuffix(orDefault(cfg.OllamaHost, defaultOllamaHost), "/")}, nil
+	case ProviderMock, "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown AI provider %q", cfg.Provider)
+	}
+}
+
+// settings are shared by every backend
+type settings struct {
+	model       string
+	maxTokens   int
+	temperature float64
+	client      *http.Client
+}
+
+// postJSON sends body to url and decodes a 2xx reply into out
+func (s settings) postJSON(ctx context.Context, url string, headers map[string]string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// OpenAIProvider calls the OpenAI chat completions API
+type OpenAIProvider struct {
+	settings
+	apiKey  string
+	baseURL string
+}
+
+// Name implements Provider
+func (p *OpenAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+// Complete implements Provider
+func (p *OpenAIProvider) Complete(ctx context.Context, req Request) (string, error) {
+	messages := make([]Message, 0, len(req.Messages)+1)
+	if req.System != "" {
+		messages = append(messages, Message{Role: "system", Content: req.System})
+	}
+	messages = append(messages, req.Messages...)
+
+	body := map[string]interface{}{
+		"model":       p.model,
+		"messages":    messages,
+		"max_tokens":  p.maxTokens,
+		"temperature": p.temperature,
+	}
+	if req.JSON {
+		body["response_format"] = map[string]string{"type": "json_object"}
+	}
+
+	var resp struct {
+		Choices []struct {
+			Message Message `json:"message"`
+		} `json:"choices"`
+	}
+	headers := map[string]string{"Authorization": "Bearer " + p.apiKey}
+	if err := p.postJSON(ctx, p.baseURL+"/chat/completions", headers, body, &resp); err != nil {
+		return "", fmt.Errorf("openai: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("openai: no choices in response")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// AnthropicProvider calls the Anthropic messages API
+type AnthropicProvider struct {
+	settings
+	apiKey  string
+	baseURL string
+}
+
+// Name implements Provider
+func (p *AnthropicProvider) Name() string {
+	return ProviderAnthropic
+}
+
+// Complete implements Provider. The API has no JSON mode, so the system
+// prompt carries that instruction.
+func (p *AnthropicProvider) Complete(ctx context.Context, req Request) (string, error) {
+	body := map[string]interface{}{
+		"model":       p.model,
+		"messages":    req.Messages,
+		"max_tokens":  p.maxTokens,
+		"temperature": p.temperature,
+	}
+	if req.System != "" {
+		body["system"] = req.System
+	}
+
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	headers := map[string]string{"x-api-key": p.apiKey, "anthropic-version": "2023-06-01"}
+	if err := p.postJSON(ctx, p.baseURL+"/messages", headers, body, &resp); err != nil {
+		return "", fmt.Errorf("anthropic: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("anthropic: no text in response")
+	}
+	return text.String(), nil
+}
+
+// OllamaProvider calls a local Ollama server's chat API
+type OllamaProvider struct {
+	settings
+	host string
+}
+
+// Name implements Provider
+func (p *OllamaProvider) Name() string {
+	return ProviderOllama
+}
+
+// Complete implements Provider
+func (p *OllamaProvider) Complete(ctx context.Context, req Request) (string, error) {
+	messages := make([]Message, 0, len(req.Messages)+1)
+	if req.System != "" {
+		messages = append(messages, Message{Role: "system", Content: req.System})
+	}
+	messages = append(messages, req.Messages...)
+
+	body := map[string]interface{}{
+		"model":    p.model,
+		"messages": messages,
+		"stream":   false,
+		"options":  map[string]interface{}{"temperature": p.temperature, "num_predict": p.maxTokens},
+	}
+	if req.JSON {
+		body["format"] = "json"
+	}
+
+	var resp struct {
+		Message Message `json:"message"`
+	}
+	if err := p.postJSON(ctx, p.host+"/api/chat", nil, body, &resp); err != nil {
+		return "", fmt.Errorf("ollama: %w", err)
+	}
+	if resp.Message.Content == "" {
+		return "", fmt.Errorf("ollama: empty response")
+	}
+	return resp.Message.Content, nil
+}
@@ -0,0 +1,102 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const pancakes = "Here you go:\n```json\n" + `{"title": "Buttermilk Pancakes", "cuisine": "american", "difficulty": "easy",
+ "ingredients": [{"name": "flour", "amount": "2", "unit": "cups"}], "instructions": ["Whisk", "Fry"]}` + "\n```"
+
+func TestNewProviderFallsBackWhenUnconfigured(t *testing.T) {
+	for _, cfg := range []config.AIConfig{
+		{Provider: ProviderMock},
+		{Provider: ProviderOpenAI},
+		{Provider: ProviderAnthropic},
+	} {
+		provider, err := NewProvider(cfg, zap.NewNop())
+		require.NoError(t, err)
+		assert.Nil(t, provider, cfg.Provider)
+	}
+
+	provider, err := NewProvider(config.AIConfig{Provider: ProviderOllama}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, ProviderOllama, provider.Name())
+
+	_, err = NewProvider(config.AIConfig{Provider: "palm"}, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestProvidersSpeakTheirAPIs(t *testing.T) {
+	var got map[string]interface{}
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		json.NewDecoder(r.Body).Decode(&got)
+		reply := map[string]interface{}{}
+		switch r.URL.Path {
+		case "/chat/completions":
+			reply["choices"] = []interface{}{map[string]interface{}{"message": Message{Role: RoleAssistant, Content: pancakes}}}
+		case "/messages":
+			reply["content"] = []interface{}{map[string]string{"type": "text", "text": pancakes}}
+		case "/api/chat":
+			reply["message"] = Message{Role: RoleAssistant, Content: pancakes}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(reply)
+	}))
+	defer server.Close()
+
+	base := settings{model: "test-model", maxTokens: 100, client: server.Client()}
+	ctx := context.Background()
+
+	openai := &OpenAIProvider{settings: base, apiKey: "sk-test", baseURL: server.URL}
+	draft, err := GenerateRecipe(ctx, openai, "pancakes")
+	require.NoError(t, err)
+	assert.Equal(t, "Buttermilk Pancakes", draft.Title)
+	assert.Equal(t, []string{"Whisk", "Fry"}, draft.Instructions)
+	assert.Equal(t, "Bearer sk-test", header.Get("Authorization"))
+	assert.Equal(t, map[string]interface{}{"type": "json_object"}, got["response_format"])
+	assert.Equal(t, "system", got["messages"].([]interface{})[0].(map[string]interface{})["role"])
+
+	anthropic := &AnthropicProvider{settings: base, apiKey: "ak-test", baseURL: server.URL}
+	_, err = GenerateRecipe(ctx, anthropic, "pancakes")
+	require.NoError(t, err)
+	assert.Equal(t, "ak-test", header.Get("x-api-key"))
+	assert.Equal(t, recipeSystemPrompt, got["system"])
+	assert.Len(t, got["messages"], 1)
+
+	ollama := &OllamaProvider{settings: base, host: server.URL}
+	_, err = GenerateRecipe(ctx, ollama, "pancakes")
+	require.NoError(t, err)
+	assert.Equal(t, "json", got["format"])
+	assert.Equal(t, false, got["stream"])
+}
+
+type cannedProvider string
+
+func (c cannedProvider) Name() string { return "canned" }
+
+func (c cannedProvider) Complete(ctx context.Context, req Request) (string, error) {
+	return string(c), nil
+}
+
+func TestGenerateRecipeRejectsUnusableReplies(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := GenerateRecipe(ctx, cannedProvider("I'd love to help! What would you like?"), "pancakes")
+	assert.Error(t, err)
+
+	_, err = GenerateRecipe(ctx, cannedProvider(`{"title": "Pancakes", "ingredients": []}`), "pancakes")
+	assert.Error(t, err)
+}
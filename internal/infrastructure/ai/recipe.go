@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const recipeSystemPrompt = `You are a professional chef writing recipes for home cooks.
+Reply with one JSON object and nothing else, shaped like:
+{"title": "", "description": "", "cuisine": "", "difficulty": "easy|medium|hard",
+ "prep_time_minutes": 0, "cook_time_minutes": 0, "servings": 0,
+ "ingredients": [{"name": "", "amount": "", "unit": ""}],
+ "instructions": ["one step per entry"], "tags": [""]}
+Respect every dietary requirement you are given.`
+
+const chatSystemPrompt = `You are the AI Chef of a recipe website. Answer cooking questions
+briefly and practically, in plain text without markdown. If asked about anything
+unrelated to food or cooking, politely steer back to cooking.`
+
+// DraftIngredient is an ingredient line of a generated recipe
+type DraftIngredient struct {
+	Name   string `json:"name"`
+	Amount string `json:"amount"`
+	Unit   string `json:"unit"`
+}
+
+// RecipeDraft is a recipe written by a provider, not yet saved
+type RecipeDraft struct {
+	Title           string            `json:"title"`
+	Description     string            `json:"description"`
+	Cuisine         string            `json:"cuisine"`
+	Difficulty      string            `json:"difficulty"`
+	PrepTimeMinutes int               `json:"prep_time_minutes"`
+	CookTimeMinutes int               `json:"cook_time_minutes"`
+	Servings        int               `json:"servings"`
+	Ingredients     []DraftIngredient `json:"ingredients"`
+	Instructions    []string          `json:"instructions"`
+	Tags            []string          `json:"tags"`
+}
+
+// GenerateRecipe asks the provider for a recipe matching prompt and rejects
+// replies that are not a usable recipe
+func GenerateRecipe(ctx context.Context, p Provider, prompt string) (*RecipeDraft, error) {
+	reply, err := p.Complete(ctx, Request{
+		System:   recipeSystemPrompt,
+		Messages: []Message{{Role: RoleUser, Content: prompt}},
+		JSON:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var draft RecipeDraft
+	if err := json.Unmarshal([]byte(jsonObject(reply)), &draft); err != nil {
+		return nil, fmt.Errorf("%s returned a malformed recipe: %w", p.Name(), err)
+	}
+	if strings.TrimSpace(draft.Title) == "" || len(draft.Ingredients) == 0 || len(draft.Instructions) == 0 {
+		return nil, fmt.Errorf("%s returned an incomplete recipe", p.Name())
+	}
+	return &draft, nil
+}
+
+// Answer asks the provider a general cooking question
+func Answer(ctx context.Context, p Provider, question string) (string, error) {
+	reply, err := p.Complete(ctx, Request{
+		System:   chatSystemPrompt,
+		Messages: []Message{{Role: RoleUser, Content: question}},
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(reply), nil
+}
+
+// jsonObject cuts the outermost object out of a reply, which models
+// without a JSON mode often wrap in prose or code fences
+func jsonObject(reply string) string {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return reply
+	}
+	return reply[start : end+1]
+}
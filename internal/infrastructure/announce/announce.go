@@ -0,0 +1,150 @@
+// Package announce posts newly published recipes to webhooks and social
+// networks
+package announce
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// Supported channel types
+const (
+	TypeWebhook  = "webhook"
+	TypeMastodon = "mastodon"
+)
+
+// Default announcement texts, used when a channel sets no template
+const (
+	defaultWebhookTemplate  = `New recipe: {{.Title}} by {{.Author}} {{.URL}}`
+	defaultMastodonTemplate = "{{.Title}} by {{.Author}}\n\n{{.Description}}\n\n{{.URL}}{{if .Hashtags}}\n\n{{.Hashtags}}{{end}}"
+)
+
+// Message is the data channel templates are executed with
+type Message struct {
+	RecipeID    string
+	Title       string
+	Description string
+	URL         string
+	Author      string
+	Tags        []string
+	Hashtags    string
+	ImageURL    string
+	PublishedAt time.Time
+}
+
+// NewPosters builds a poster for every configured channel. Channels with an
+// unknown type, a missing URL or a broken template are skipped with a warning
+// so one bad entry does not stop the others.
+func NewPosters(cfg config.PublishingConfig, logger *zap.Logger) []outbound.RecipePoster {
+	logger = logger.Named("announce")
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	posters := make([]outbound.RecipePoster, 0, len(cfg.Channels))
+	seen := make(map[string]bool, len(cfg.Channels))
+	for _, channel := range cfg.Channels {
+		name := strings.TrimSpace(channel.Name)
+		if name == "" {
+			name = channel.Type
+		}
+		if seen[name] {
+			logger.Warn("Duplicate announcement channel skipped", zap.String("channel", name))
+			continue
+		}
+		if strings.TrimSpace(channel.URL) == "" {
+			logger.Warn("Announcement channel has no URL", zap.String("channel", name))
+			continue
+		}
+
+		var poster outbound.RecipePoster
+		var err error
+		switch strings.ToLower(channel.Type) {
+		case TypeWebhook:
+			poster, err = NewWebhookPoster(name, channel, cfg.SiteURL, client)
+		case TypeMastodon:
+			poster, err = NewMastodonPoster(name, channel, cfg.SiteURL, client)
+		default:
+			err = fmt.Errorf("unknown channel type %q", channel.Type)
+		}
+		if err != nil {
+			logger.Warn("Announcement channel skipped", zap.String("channel", name), zap.Error(err))
+			continue
+		}
+
+		seen[name] = true
+		posters = append(posters, poster)
+	}
+
+	logger.Info("Announcement channels configured", zap.Int("channels", len(posters)))
+	return posters
+}
+
+// renderer executes a channel's template against an announcement
+type renderer struct {
+	siteURL string
+	tmpl    *template.Template
+}
+
+func newRenderer(name, text, fallback, siteURL string) (*renderer, error) {
+	if strings.TrimSpace(text) == "" {
+		text = fallback
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	return &renderer{siteURL: strings.TrimRight(siteURL, "/"), tmpl: tmpl}, nil
+}
+
+// message prepares the template data for an announcement
+func (r *renderer) message(a outbound.RecipeAnnouncement) Message {
+	return Message{
+		RecipeID:    a.RecipeID.String(),
+		Title:       a.Title,
+		Description: a.Description,
+		URL:         r.siteURL + "/recipes/" + a.RecipeID.String(),
+		Author:      a.AuthorName,
+		Tags:        a.Tags,
+		Hashtags:    hashtags(a.Tags),
+		ImageURL:    a.ImageURL,
+		PublishedAt: a.PublishedAt,
+	}
+}
+
+// render produces the announcement text with surrounding blank space trimmed
+func (r *renderer) render(msg Message) (string, error) {
+	var buf bytes.Buffer
+	if err := r.tmpl.Execute(&buf, msg); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// hashtags turns tags into "#quick #weeknight", dropping characters that
+// would end a hashtag early
+func hashtags(tags []string) string {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		var b strings.Builder
+		for _, r := range tag {
+			if r == '_' || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || r > 0x7f {
+				b.WriteRune(r)
+			}
+		}
+		if b.Len() > 0 {
+			out = append(out, "#"+b.String())
+		}
+	}
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,115 @@
+package announce
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func testAnnouncement() outbound.RecipeAnnouncement {
+	return outbound.RecipeAnnouncement{
+		RecipeID:    uuid.MustParse("6f1c2c1e-8a4e-4d51-9a47-0d6c1f0b9e3a"),
+		Title:       "Lemon Bars",
+		Description: "Bright, buttery squares.",
+		AuthorName:  "Ada",
+		Tags:        []string{"dessert", "no-bake", "summer fruit"},
+		PublishedAt: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookPosterSignsPayload(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	poster, err := NewWebhookPoster("discord", config.AnnouncementChannelConfig{
+		URL:      server.URL,
+		Secret:   "s3cret",
+		Template: "{{.Title}} by {{.Author}} {{.Hashtags}} {{.URL}}",
+	}, "https://alchemorsel.app/", server.Client())
+	require.NoError(t, err)
+
+	require.NoError(t, poster.Post(context.Background(), testAnnouncement()))
+
+	assert.Equal(t, "sha256="+Sign([]byte("s3cret"), body), header.Get(HeaderSignature))
+	assert.Equal(t, "recipe.published", header.Get(HeaderEvent))
+
+	var payload WebhookPayload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	url := "https://alchemorsel.app/recipes/6f1c2c1e-8a4e-4d51-9a47-0d6c1f0b9e3a"
+	assert.Equal(t, "Lemon Bars by Ada #dessert #nobake #summerfruit "+url, payload.Text)
+	assert.Equal(t, url, payload.Recipe.URL)
+	assert.Equal(t, "discord", payload.Channel)
+}
+
+func TestWebhookPosterReportsFailedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	poster, err := NewWebhookPoster("hook", config.AnnouncementChannelConfig{URL: server.URL}, "https://alchemorsel.app", server.Client())
+	require.NoError(t, err)
+
+	err = poster.Post(context.Background(), testAnnouncement())
+	assert.EqualError(t, err, "webhook returned status 502")
+}
+
+func TestMastodonPosterKeepsLinkWithinLimit(t *testing.T) {
+	var form map[string][]string
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	poster, err := NewMastodonPoster("mastodon", config.AnnouncementChannelConfig{URL: server.URL, Token: "tok"}, "https://alchemorsel.app", server.Client())
+	require.NoError(t, err)
+
+	announcement := testAnnouncement()
+	announcement.Description = strings.Repeat("Zesty and sweet. ", 60)
+	require.NoError(t, poster.Post(context.Background(), announcement))
+
+	status := form["status"][0]
+	assert.LessOrEqual(t, len([]rune(status)), mastodonMaxChars)
+	assert.Contains(t, status, "https://alchemorsel.app/recipes/6f1c2c1e-8a4e-4d51-9a47-0d6c1f0b9e3a")
+	assert.Contains(t, status, "…")
+	assert.Equal(t, "Bearer tok", auth)
+}
+
+func TestNewPostersSkipsBrokenChannels(t *testing.T) {
+	posters := NewPosters(config.PublishingConfig{
+		SiteURL: "https://alchemorsel.app",
+		Channels: []config.AnnouncementChannelConfig{
+			{Name: "partners", Type: "webhook", URL: "https://hooks.example.com/recipes"},
+			{Name: "partners", Type: "webhook", URL: "https://hooks.example.com/other"},
+			{Name: "no-url", Type: "webhook"},
+			{Name: "bad-template", Type: "webhook", URL: "https://hooks.example.com", Template: "{{.Title"},
+			{Name: "no-token", Type: "mastodon", URL: "https://mastodon.social"},
+			{Name: "fax", Type: "fax", URL: "tel:123"},
+		},
+	}, zap.NewNop())
+
+	require.Len(t, posters, 1)
+	assert.Equal(t, "partners", posters[0].Channel())
+}
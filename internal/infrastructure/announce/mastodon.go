@@ -0,0 +1,112 @@
+package announce
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+)
+
+// mastodonMaxChars is the default status length limit of Mastodon instances
+const mastodonMaxChars = 500
+
+// MastodonPoster posts a status to a Mastodon account. It doubles as the
+// example for adding other social networks: implement outbound.RecipePoster
+// and add a case to NewPosters.
+type MastodonPoster struct {
+	name     string
+	instance string
+	token    string
+	renderer *renderer
+	client   *http.Client
+}
+
+// NewMastodonPoster creates a poster for a Mastodon channel. The channel URL
+// is the instance, such as https://mastodon.social, and the token needs the
+// write:statuses scope.
+func NewMastodonPoster(name string, cfg config.AnnouncementChannelConfig, siteURL string, client *http.Client) (*MastodonPoster, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("mastodon channel needs an access token")
+	}
+	renderer, err := newRenderer(name, cfg.Template, defaultMastodonTemplate, siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MastodonPoster{
+		name:     name,
+		instance: strings.TrimRight(cfg.URL, "/"),
+		token:    cfg.Token,
+		renderer: renderer,
+		client:   client,
+	}, nil
+}
+
+// Channel returns the configured channel name
+func (p *MastodonPoster) Channel() string {
+	return p.name
+}
+
+// Post publishes the status. The recipe ID is sent as the idempotency key so
+// a retry after a lost response does not post twice.
+func (p *MastodonPoster) Post(ctx context.Context, announcement outbound.RecipeAnnouncement) error {
+	status, err := p.status(announcement)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{"status": {status}, "visibility": {"public"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.instance+"/api/v1/statuses", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Idempotency-Key", announcement.RecipeID.String()+"-"+p.name)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post status: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("mastodon returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// status renders the post, shortening the description when the text would
+// go over the length limit so the link is never cut off
+func (p *MastodonPoster) status(announcement outbound.RecipeAnnouncement) (string, error) {
+	msg := p.renderer.message(announcement)
+	status, err := p.renderer.render(msg)
+	if err != nil {
+		return "", err
+	}
+
+	over := len([]rune(status)) - mastodonMaxChars
+	if over <= 0 {
+		return status, nil
+	}
+	description := []rune(msg.Description)
+	if over+1 > len(description) {
+		msg.Description = ""
+	} else {
+		msg.Description = strings.TrimSpace(string(description[:len(description)-over-1])) + "…"
+	}
+	status, err = p.renderer.render(msg)
+	if err != nil {
+		return "", err
+	}
+	if runes := []rune(status); len(runes) > mastodonMaxChars {
+		status = string(runes[:mastodonMaxChars])
+	}
+	return status, nil
+}
@@ -0,0 +1,138 @@
+package announce
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+)
+
+// Webhook request headers
+const (
+	HeaderEvent     = "X-Alchemorsel-Event"
+	HeaderSignature = "X-Alchemorsel-Signature"
+	HeaderDelivery  = "X-Alchemorsel-Delivery"
+)
+
+// webhookEvent names the only event sent today
+const webhookEvent = "recipe.published"
+
+// WebhookPoster posts a JSON announcement to a generic webhook. When a secret
+// is configured the body is signed with HMAC-SHA256 so receivers can verify
+// it came from us.
+type WebhookPoster struct {
+	name     string
+	url      string
+	secret   []byte
+	renderer *renderer
+	client   *http.Client
+}
+
+// WebhookPayload is the JSON body sent to webhooks
+type WebhookPayload struct {
+	Event   string        `json:"event"`
+	Channel string        `json:"channel"`
+	Text    string        `json:"text"`
+	Recipe  WebhookRecipe `json:"recipe"`
+}
+
+// WebhookRecipe describes the published recipe in a webhook payload
+type WebhookRecipe struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	URL         string    `json:"url"`
+	Author      string    `json:"author,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// NewWebhookPoster creates a poster for a webhook channel
+func NewWebhookPoster(name string, cfg config.AnnouncementChannelConfig, siteURL string, client *http.Client) (*WebhookPoster, error) {
+	renderer, err := newRenderer(name, cfg.Template, defaultWebhookTemplate, siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookPoster{
+		name:     name,
+		url:      cfg.URL,
+		secret:   []byte(cfg.Secret),
+		renderer: renderer,
+		client:   client,
+	}, nil
+}
+
+// Channel returns the configured channel name
+func (p *WebhookPoster) Channel() string {
+	return p.name
+}
+
+// Post sends the announcement; any non-2xx response is an error so the
+// delivery is retried
+func (p *WebhookPoster) Post(ctx context.Context, announcement outbound.RecipeAnnouncement) error {
+	msg := p.renderer.message(announcement)
+	text, err := p.renderer.render(msg)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(WebhookPayload{
+		Event:   webhookEvent,
+		Channel: p.name,
+		Text:    text,
+		Recipe: WebhookRecipe{
+			ID:          msg.RecipeID,
+			Title:       msg.Title,
+			Description: msg.Description,
+			URL:         msg.URL,
+			Author:      msg.Author,
+			Tags:        msg.Tags,
+			ImageURL:    msg.ImageURL,
+			PublishedAt: msg.PublishedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, webhookEvent)
+	req.Header.Set(HeaderDelivery, msg.RecipeID)
+	if len(p.secret) > 0 {
+		req.Header.Set(HeaderSignature, "sha256="+Sign(p.secret, body))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body, as sent in the signature header
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,33 @@
+// Package blobstore provides streaming object storage adapters
+package blobstore
+
+import (
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// Supported providers
+const (
+	ProviderLocal = "local"
+	ProviderS3    = "s3"
+)
+
+// NewStorage returns the configured storage provider, falling back to the
+// local disk when S3 cannot be set up
+func NewStorage(storage config.StorageConfig, aws config.AWSConfig, logger *zap.Logger) outbound.BlobStorage {
+	switch strings.ToLower(storage.Provider) {
+	case ProviderS3:
+		s3, err := NewS3(aws)
+		if err == nil {
+			return s3
+		}
+		logger.Warn("S3 blob storage unavailable, falling back to local disk", zap.Error(err))
+	case ProviderLocal, "":
+	default:
+		logger.Warn("Unknown storage provider, falling back to local disk", zap.String("provider", storage.Provider))
+	}
+	return NewLocal(storage.LocalPath)
+}
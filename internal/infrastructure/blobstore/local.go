@@ -0,0 +1,71 @@
+package blobstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores blobs as files under a base directory
+type Local struct {
+	root string
+}
+
+// NewLocal creates local blob storage rooted at dir
+func NewLocal(dir string) *Local {
+	if dir == "" {
+		dir = "./uploads"
+	}
+	return &Local{root: dir}
+}
+
+// Put writes the content to a temporary file and renames it into place, so
+// readers never see a partial blob
+func (l *Local) Put(ctx context.Context, key, contentType string, content io.Reader) (int64, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return 0, fmt.Errorf("create blob directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blob-*")
+	if err != nil {
+		return 0, fmt.Errorf("create blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return written, fmt.Errorf("write blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return written, fmt.Errorf("store blob: %w", err)
+	}
+	return written, nil
+}
+
+// Get opens a stored blob
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// path maps a key inside the root, rejecting keys that would escape it
+func (l *Local) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(l.root, clean), nil
+}
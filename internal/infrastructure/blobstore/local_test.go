@@ -0,0 +1,31 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStoresBlobsUnderRoot(t *testing.T) {
+	store := NewLocal(t.TempDir())
+	ctx := context.Background()
+
+	written, err := store.Put(ctx, "profiles/2024/05/01/cpu.pb.gz", "application/octet-stream", strings.NewReader("profile"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, written)
+
+	content, err := store.Get(ctx, "profiles/2024/05/01/cpu.pb.gz")
+	require.NoError(t, err)
+	defer content.Close()
+	data, _ := io.ReadAll(content)
+	assert.Equal(t, "profile", string(data))
+
+	_, err = store.Put(ctx, "../outside", "text/plain", strings.NewReader("x"))
+	assert.Error(t, err, "keys cannot escape the root")
+	_, err = store.Get(ctx, "")
+	assert.Error(t, err)
+}
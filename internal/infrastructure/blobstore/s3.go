@@ -0,0 +1,82 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// S3 stores blobs in an S3 bucket, uploading in parts as content arrives
+type S3 struct {
+	bucket   string
+	client   *s3.S3
+	uploader *s3manager.Uploader
+}
+
+// NewS3 creates S3 blob storage for the configured bucket
+func NewS3(cfg config.AWSConfig) (*S3, error) {
+	if cfg.S3Bucket == "" {
+		return nil, errors.New("aws.s3_bucket is not set")
+	}
+
+	awsCfg := &aws.Config{Region: aws.String(cfg.Region)}
+	if cfg.Endpoint != "" {
+		awsCfg.Endpoint = aws.String(cfg.Endpoint)
+		awsCfg.S3ForcePathStyle = aws.Bool(true)
+	}
+	if cfg.AccessKeyID != "" {
+		awsCfg.Credentials = credentials.NewStaticCredentials(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken)
+	}
+
+	sess, err := session.NewSession(awsCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &S3{
+		bucket:   cfg.S3Bucket,
+		client:   s3.New(sess),
+		uploader: s3manager.NewUploader(sess),
+	}, nil
+}
+
+// Put uploads the content under key
+func (s *S3) Put(ctx context.Context, key, contentType string, content io.Reader) (int64, error) {
+	counter := &countingReader{r: content}
+	_, err := s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+		Body:        counter,
+	})
+	return counter.n, err
+}
+
+// Get streams a stored object
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -4,6 +4,7 @@ package cache
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -592,6 +593,27 @@ func (c *CacheService) WarmUp(ctx context.Context, loader CacheLoader) error {
 	return nil
 }
 
+// Increment increments a counter kept in Redis alone, resetting its
+// expiration, and returns the new count
+func (c *CacheService) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	if err := c.validateKey(key); err != nil {
+		return 0, err
+	}
+	return c.redis.Increment(ctx, key, expiration)
+}
+
+// Counter reads a counter written by Increment, 0 if it has expired
+func (c *CacheService) Counter(ctx context.Context, key string) (int64, error) {
+	data, err := c.redis.Get(ctx, key)
+	if err == ErrKeyNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
 // Helper methods
 
 func (c *CacheService) validateKey(key string) error {
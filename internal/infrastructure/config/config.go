@@ -10,239 +10,655 @@ import (
 	"github.com/spf13/viper"
 )
 
+//go:generate go run ../../../cmd/configdoc -o ../../../docs/configuration.md
+
+// Every field below is a configuration key. Its tags make up the schema:
+//
+//	mapstructure  the key name under its section
+//	default       the value used when neither the file nor the environment sets it
+//	validate      go-playground/validator rules checked at startup
+//	secret        redacted from the effective-config reference
+//
+// Each key can be overridden by ALCHEMORSEL_<SECTION>_<KEY>, for example
+// ALCHEMORSEL_SERVER_PORT. Keys not declared here are rejected at startup.
+
 // Config holds all application configuration
 type Config struct {
-	App        AppConfig        `mapstructure:"app"`
-	Server     ServerConfig     `mapstructure:"server"`
-	Database   DatabaseConfig   `mapstructure:"database"`
-	Redis      RedisConfig      `mapstructure:"redis"`
-	Auth       AuthConfig       `mapstructure:"auth"`
-	AWS        AWSConfig        `mapstructure:"aws"`
-	AI         AIConfig         `mapstructure:"ai"`
-	Kafka      KafkaConfig      `mapstructure:"kafka"`
-	Monitoring MonitoringConfig `mapstructure:"monitoring"`
-	Email      EmailConfig      `mapstructure:"email"`
-	Storage    StorageConfig    `mapstructure:"storage"`
-	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
-	Features   FeatureFlags     `mapstructure:"features"`
+	App             AppConfig             `mapstructure:"app"`
+	Server          ServerConfig          `mapstructure:"server"`
+	Database        DatabaseConfig        `mapstructure:"database"`
+	Redis           RedisConfig           `mapstructure:"redis"`
+	Auth            AuthConfig            `mapstructure:"auth"`
+	AWS             AWSConfig             `mapstructure:"aws"`
+	AI              AIConfig              `mapstructure:"ai"`
+	OCR             OCRConfig             `mapstructure:"ocr"`
+	VirusScan       VirusScanConfig       `mapstructure:"virus_scan"`
+	Publishing      PublishingConfig      `mapstructure:"publishing"`
+	Kafka           KafkaConfig           `mapstructure:"kafka"`
+	Monitoring      MonitoringConfig      `mapstructure:"monitoring"`
+	Email           EmailConfig           `mapstructure:"email"`
+	Storage         StorageConfig         `mapstructure:"storage"`
+	Profiling       ProfilingConfig       `mapstructure:"profiling"`
+	Browse          BrowseConfig          `mapstructure:"browse"`
+	Graph           GraphConfig           `mapstructure:"graph"`
+	Popularity      PopularityConfig      `mapstructure:"popularity"`
+	Embeddings      EmbeddingsConfig      `mapstructure:"embeddings"`
+	Recommendations RecommendationsConfig `mapstructure:"recommendations"`
+	Archive         ArchiveConfig         `mapstructure:"archive"`
+	Counters        CountersConfig        `mapstructure:"counters"`
+	Views           ViewsConfig           `mapstructure:"views"`
+	Sync            SyncConfig            `mapstructure:"sync"`
+	Canary          CanaryConfig          `mapstructure:"canary"`
+	Shadow          ShadowConfig          `mapstructure:"shadow"`
+	Web             WebConfig             `mapstructure:"web"`
+	Lease           LeaseConfig           `mapstructure:"lease"`
+	Invalidation    InvalidationConfig    `mapstructure:"invalidation"`
+	Sandbox         SandboxConfig         `mapstructure:"sandbox"`
+	Clipper         ClipperConfig         `mapstructure:"clipper"`
+	Guest           GuestConfig           `mapstructure:"guest"`
+	Moderation      ModerationConfig      `mapstructure:"moderation"`
+	Privacy         PrivacyConfig         `mapstructure:"privacy"`
+	Images          ImagesConfig          `mapstructure:"images"`
+	RateLimit       RateLimitConfig       `mapstructure:"rate_limit"`
+	Features        FeatureFlags          `mapstructure:"features"`
+
+	// sources records where Load found each key: default, file or env
+	sources map[string]string
 }
 
 // AppConfig contains application-level configuration
 type AppConfig struct {
-	Name        string `mapstructure:"name"`
-	Version     string `mapstructure:"version"`
-	Environment string `mapstructure:"environment"`
-	Debug       bool   `mapstructure:"debug"`
-	LogLevel    string `mapstructure:"log_level"`
-	LogFormat   string `mapstructure:"log_format"`
+	// Profile names a bundle of settings applied over the config file; see
+	// profiles.go. Binaries also take it as -profile.
+	Profile     string `mapstructure:"profile" validate:"omitempty,oneof=dev demo prod"`
+	Name        string `mapstructure:"name" default:"Alchemorsel" validate:"required"`
+	Version     string `mapstructure:"version" default:"3.0.0"`
+	Environment string `mapstructure:"environment" default:"development" validate:"oneof=development testing staging production"`
+	Debug       bool   `mapstructure:"debug" default:"false"`
+	LogLevel    string `mapstructure:"log_level" default:"info" validate:"oneof=debug info warn error"`
+	LogFormat   string `mapstructure:"log_format" default:"json" validate:"oneof=json console"`
 }
 
 // ServerConfig contains HTTP server configuration
 type ServerConfig struct {
-	Host              string        `mapstructure:"host"`
-	Port              int           `mapstructure:"port"`
-	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
-	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
-	MaxHeaderBytes    int           `mapstructure:"max_header_bytes"`
-	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`
-	EnableCORS        bool          `mapstructure:"enable_cors"`
+	Host              string        `mapstructure:"host" default:"0.0.0.0"`
+	Port              int           `mapstructure:"port" default:"8080" validate:"min=1,max=65535"`
+	ReadTimeout       time.Duration `mapstructure:"read_timeout" default:"15s" validate:"min=0"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout" default:"15s" validate:"min=0"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout" default:"60s" validate:"min=0"`
+	MaxHeaderBytes    int           `mapstructure:"max_header_bytes" default:"1048576" validate:"min=0"` // 1MB
+	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout" default:"30s" validate:"min=0"`
+	EnableCORS        bool          `mapstructure:"enable_cors" default:"true"`
 	AllowedOrigins    []string      `mapstructure:"allowed_origins"`
 	TrustedProxies    []string      `mapstructure:"trusted_proxies"`
-	EnableCompression bool          `mapstructure:"enable_compression"`
-	EnablePprof       bool          `mapstructure:"enable_pprof"`
+	EnableCompression bool          `mapstructure:"enable_compression" default:"true"`
+	EnablePprof       bool          `mapstructure:"enable_pprof" default:"false"`
+	// TemplateRenderBudget is the render time allowed per web template;
+	// slower renders are logged with profiling hints. Zero disables it.
+	TemplateRenderBudget time.Duration `mapstructure:"template_render_budget" default:"50ms" validate:"min=0"`
 }
 
 // DatabaseConfig contains database configuration
 type DatabaseConfig struct {
-	Driver          string        `mapstructure:"driver"`
-	Host            string        `mapstructure:"host"`
-	Port            int           `mapstructure:"port"`
-	Database        string        `mapstructure:"database"`
-	Username        string        `mapstructure:"username"`
-	Password        string        `mapstructure:"password"`
-	SSLMode         string        `mapstructure:"ssl_mode"`
-	MaxOpenConns    int           `mapstructure:"max_open_conns"`
-	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
-	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
-	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
-	LogLevel        string        `mapstructure:"log_level"`
-	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
-	AutoMigrate     bool          `mapstructure:"auto_migrate"`
+	Driver             string        `mapstructure:"driver" default:"postgres" validate:"oneof=postgres sqlite"`
+	Host               string        `mapstructure:"host" default:"localhost"`
+	Port               int           `mapstructure:"port" default:"5432" validate:"min=1,max=65535"`
+	Database           string        `mapstructure:"database" validate:"required"`
+	Username           string        `mapstructure:"username"`
+	Password           string        `mapstructure:"password" secret:"true"`
+	SSLMode            string        `mapstructure:"ssl_mode" default:"disable" validate:"oneof=disable allow prefer require verify-ca verify-full"`
+	MaxOpenConns       int           `mapstructure:"max_open_conns" default:"25" validate:"min=1"`
+	MaxIdleConns       int           `mapstructure:"max_idle_conns" default:"5" validate:"min=0,ltefield=MaxOpenConns"`
+	ConnMaxLifetime    time.Duration `mapstructure:"conn_max_lifetime" default:"1h" validate:"min=0"`
+	ConnMaxIdleTime    time.Duration `mapstructure:"conn_max_idle_time" default:"10m" validate:"min=0"`
+	LogLevel           string        `mapstructure:"log_level" validate:"omitempty,oneof=silent debug info warn error"`
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold" default:"100ms" validate:"min=0"`
+	// AutoMigrate applies pending SQL migrations at startup. Without it a
+	// Postgres server refuses to start until `api-pure migrate up` has run.
+	// SQLite databases are always migrated from the models.
+	AutoMigrate bool `mapstructure:"auto_migrate" default:"false"`
+	Seed        bool `mapstructure:"seed" default:"false"` // Load sample users and recipes into an empty database
 }
 
 // RedisConfig contains Redis configuration
 type RedisConfig struct {
-	Host            string        `mapstructure:"host"`
-	Port            int           `mapstructure:"port"`
-	Password        string        `mapstructure:"password"`
-	Database        int           `mapstructure:"database"`
-	MaxRetries      int           `mapstructure:"max_retries"`
-	MinIdleConns    int           `mapstructure:"min_idle_conns"`
-	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
-	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
-	DialTimeout     time.Duration `mapstructure:"dial_timeout"`
-	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
-	PoolSize        int           `mapstructure:"pool_size"`
-	EnableCluster   bool          `mapstructure:"enable_cluster"`
-	ClusterNodes    []string      `mapstructure:"cluster_nodes"`
+	Host            string        `mapstructure:"host" default:"localhost"`
+	Port            int           `mapstructure:"port" default:"6379" validate:"min=1,max=65535"`
+	Password        string        `mapstructure:"password" secret:"true"`
+	Database        int           `mapstructure:"database" default:"0" validate:"min=0"`
+	MaxRetries      int           `mapstructure:"max_retries" default:"3" validate:"min=0"`
+	MinIdleConns    int           `mapstructure:"min_idle_conns" validate:"min=0"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns" validate:"min=0"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime" validate:"min=0"`
+	DialTimeout     time.Duration `mapstructure:"dial_timeout" validate:"min=0"`
+	ReadTimeout     time.Duration `mapstructure:"read_timeout" validate:"min=0"`
+	WriteTimeout    time.Duration `mapstructure:"write_timeout" validate:"min=0"`
+	PoolSize        int           `mapstructure:"pool_size" default:"10" validate:"min=1"`
+	EnableCluster   bool          `mapstructure:"enable_cluster" default:"false"`
+	ClusterNodes    []string      `mapstructure:"cluster_nodes" validate:"required_if=EnableCluster true"`
 }
 
 // AuthConfig contains authentication configuration
 type AuthConfig struct {
-	JWTSecret           string        `mapstructure:"jwt_secret"`
-	JWTExpiration       time.Duration `mapstructure:"jwt_expiration"`
-	RefreshExpiration   time.Duration `mapstructure:"refresh_expiration"`
-	BCryptCost          int           `mapstructure:"bcrypt_cost"`
-	EnableOAuth         bool          `mapstructure:"enable_oauth"`
-	GoogleClientID      string        `mapstructure:"google_client_id"`
-	GoogleClientSecret  string        `mapstructure:"google_client_secret"`
-	FacebookAppID       string        `mapstructure:"facebook_app_id"`
-	FacebookAppSecret   string        `mapstructure:"facebook_app_secret"`
-	SessionSecret       string        `mapstructure:"session_secret"`
-	SessionMaxAge       int           `mapstructure:"session_max_age"`
+	JWTSecret          string              `mapstructure:"jwt_secret" secret:"true"`
+	JWTExpiration      time.Duration       `mapstructure:"jwt_expiration" default:"24h" validate:"min=1m"`
+	RefreshExpiration  time.Duration       `mapstructure:"refresh_expiration" default:"168h" validate:"gtefield=JWTExpiration"` // 7 days
+	BCryptCost         int                 `mapstructure:"bcrypt_cost" default:"10" validate:"min=4,max=31"`
+	EnableOAuth        bool                `mapstructure:"enable_oauth" default:"false"`
+	GoogleClientID     string              `mapstructure:"google_client_id"`
+	GoogleClientSecret string              `mapstructure:"google_client_secret" secret:"true"`
+	FacebookAppID      string              `mapstructure:"facebook_app_id"`
+	FacebookAppSecret  string              `mapstructure:"facebook_app_secret" secret:"true"`
+	SessionSecret      string              `mapstructure:"session_secret" secret:"true"`
+	SessionMaxAge      int                 `mapstructure:"session_max_age" validate:"min=0"` // Seconds; zero keeps the web session default
+	Service            ServiceAuthConfig   `mapstructure:"service"`
+	PasswordReset      PasswordResetConfig `mapstructure:"password_reset"`
+}
+
+// PasswordResetConfig controls the tokens mailed by POST
+// /auth/forgot-password. Each works once, for TokenTTL, and an address gets
+// at most MaxRequests of them per Window; requests beyond that are dropped
+// silently so the endpoint does not reveal which addresses have accounts.
+type PasswordResetConfig struct {
+	TokenTTL    time.Duration `mapstructure:"token_ttl" default:"1h" validate:"min=5m,max=24h"`
+	MaxRequests int           `mapstructure:"max_requests" default:"3" validate:"min=1"`
+	Window      time.Duration `mapstructure:"window" default:"1h" validate:"min=1m"`
+}
+
+// ServiceAuthConfig controls request signing between cmd/web and the API.
+// The web signs every API call with Secret and names the signed-in user in
+// a signed header instead of forwarding their access token. Both sides must
+// be enabled together.
+type ServiceAuthConfig struct {
+	Enabled        bool          `mapstructure:"enabled" default:"false"`
+	Name           string        `mapstructure:"name" default:"web"` // Service name this process signs as
+	Secret         string        `mapstructure:"secret" secret:"true" validate:"required_if=Enabled true,omitempty,min=32"`
+	PreviousSecret string        `mapstructure:"previous_secret" secret:"true" validate:"omitempty,min=32"` // Still accepted while rotating Secret
+	MaxSkew        time.Duration `mapstructure:"max_skew" default:"30s" validate:"min=1s"`
 }
 
 // AWSConfig contains AWS service configuration
 type AWSConfig struct {
 	Region          string `mapstructure:"region"`
-	AccessKeyID     string `mapstructure:"access_key_id"`
-	SecretAccessKey string `mapstructure:"secret_access_key"`
-	SessionToken    string `mapstructure:"session_token"`
-	Endpoint        string `mapstructure:"endpoint"`
+	AccessKeyID     string `mapstructure:"access_key_id" secret:"true"`
+	SecretAccessKey string `mapstructure:"secret_access_key" secret:"true"`
+	SessionToken    string `mapstructure:"session_token" secret:"true"`
+	Endpoint        string `mapstructure:"endpoint" validate:"omitempty,url"`
 	S3Bucket        string `mapstructure:"s3_bucket"`
-	CloudFrontURL   string `mapstructure:"cloudfront_url"`
+	CloudFrontURL   string `mapstructure:"cloudfront_url" validate:"omitempty,url"`
 }
 
 // AIConfig contains AI service configuration
 type AIConfig struct {
-	Provider           string        `mapstructure:"provider"`
-	OpenAIKey          string        `mapstructure:"openai_key"`
-	OpenAIModel        string        `mapstructure:"openai_model"`
-	AnthropicKey       string        `mapstructure:"anthropic_key"`
-	AnthropicModel     string        `mapstructure:"anthropic_model"`
-	
+	Provider       string `mapstructure:"provider" default:"openai" validate:"omitempty,oneof=openai anthropic ollama mock"`
+	OpenAIKey      string `mapstructure:"openai_key" secret:"true"`
+	OpenAIModel    string `mapstructure:"openai_model" default:"gpt-3.5-turbo"`
+	AnthropicKey   string `mapstructure:"anthropic_key" secret:"true"`
+	AnthropicModel string `mapstructure:"anthropic_model"`
+
 	// Ollama configuration
-	OllamaHost         string        `mapstructure:"ollama_host"`
-	OllamaModel        string        `mapstructure:"ollama_model"`
-	OllamaTimeout      time.Duration `mapstructure:"ollama_timeout"`
-	
-	MaxTokens          int           `mapstructure:"max_tokens"`
-	Temperature        float64       `mapstructure:"temperature"`
-	TimeoutSeconds     int           `mapstructure:"timeout_seconds"`
-	EnableCache        bool          `mapstructure:"enable_cache"`
-	CacheTTL           time.Duration `mapstructure:"cache_ttl"`
+	OllamaHost    string        `mapstructure:"ollama_host" validate:"omitempty,url"`
+	OllamaModel   string        `mapstructure:"ollama_model"`
+	OllamaTimeout time.Duration `mapstructure:"ollama_timeout" validate:"min=0"`
+
+	MaxTokens      int           `mapstructure:"max_tokens" default:"1500" validate:"min=1"`
+	Temperature    float64       `mapstructure:"temperature" default:"0.7" validate:"min=0,max=2"`
+	TimeoutSeconds int           `mapstructure:"timeout_seconds" default:"30" validate:"min=1"`
+	EnableCache    bool          `mapstructure:"enable_cache" default:"true"`
+	CacheTTL       time.Duration `mapstructure:"cache_ttl" default:"1h" validate:"min=0"`
+}
+
+// OCRConfig selects the text recognition provider used by recipe photo import
+type OCRConfig struct {
+	Provider      string        `mapstructure:"provider" default:"tesseract" validate:"omitempty,oneof=tesseract ollama none"`
+	TesseractPath string        `mapstructure:"tesseract_path" default:"tesseract"`
+	Languages     string        `mapstructure:"languages" default:"eng"`
+	OllamaHost    string        `mapstructure:"ollama_host" default:"http://localhost:11434" validate:"url"`
+	OllamaModel   string        `mapstructure:"ollama_model" default:"llava:7b"`
+	Timeout       time.Duration `mapstructure:"timeout" default:"60s" validate:"min=1s"`
+}
+
+// VirusScanConfig selects the scanner that uploaded photos and imported
+// files pass through before they are processed
+type VirusScanConfig struct {
+	Provider         string        `mapstructure:"provider" default:"clamav" validate:"oneof=clamav icap none"`
+	ClamAVAddress    string        `mapstructure:"clamav_address" default:"tcp://localhost:3310"` // clamd socket, tcp://host:port or unix:///path
+	ICAPURL          string        `mapstructure:"icap_url" default:"icap://localhost:1344/avscan"`
+	Timeout          time.Duration `mapstructure:"timeout" default:"30s" validate:"min=1s"`
+	FailOpen         bool          `mapstructure:"fail_open" default:"false"` // Accept uploads unscanned while the scanner is down
+	QuarantinePrefix string        `mapstructure:"quarantine_prefix" default:"quarantine/" validate:"required"`
+}
+
+// PublishingConfig controls scheduled publishing and the channels new
+// recipes are announced on
+type PublishingConfig struct {
+	SchedulerInterval time.Duration               `mapstructure:"scheduler_interval" default:"1m" validate:"min=1s"`
+	SiteURL           string                      `mapstructure:"site_url" default:"http://localhost:8080" validate:"url"` // Base of recipe links in announcements
+	Timeout           time.Duration               `mapstructure:"timeout" default:"10s" validate:"min=1s"`
+	Channels          []AnnouncementChannelConfig `mapstructure:"channels" validate:"dive"` // JSON list when set from the environment
+}
+
+// AnnouncementChannelConfig is one place new recipes are posted to
+type AnnouncementChannelConfig struct {
+	Name     string `mapstructure:"name"`
+	Type     string `mapstructure:"type" validate:"oneof=webhook mastodon"`
+	URL      string `mapstructure:"url" validate:"required,url"` // Webhook endpoint or Mastodon instance
+	Token    string `mapstructure:"token" secret:"true"`
+	Secret   string `mapstructure:"secret" secret:"true"` // Signs webhook bodies
+	Template string `mapstructure:"template"`
 }
 
 // KafkaConfig contains Kafka configuration
 type KafkaConfig struct {
-	Brokers       []string `mapstructure:"brokers"`
-	GroupID       string   `mapstructure:"group_id"`
-	ClientID      string   `mapstructure:"client_id"`
-	EnableSASL    bool     `mapstructure:"enable_sasl"`
-	SASLUsername  string   `mapstructure:"sasl_username"`
-	SASLPassword  string   `mapstructure:"sasl_password"`
-	EnableTLS     bool     `mapstructure:"enable_tls"`
-	RetryMax      int      `mapstructure:"retry_max"`
-	RequiredAcks  int      `mapstructure:"required_acks"`
+	Brokers      []string `mapstructure:"brokers"`
+	GroupID      string   `mapstructure:"group_id"`
+	ClientID     string   `mapstructure:"client_id"`
+	EnableSASL   bool     `mapstructure:"enable_sasl" default:"false"`
+	SASLUsername string   `mapstructure:"sasl_username"`
+	SASLPassword string   `mapstructure:"sasl_password" secret:"true"`
+	EnableTLS    bool     `mapstructure:"enable_tls" default:"false"`
+	RetryMax     int      `mapstructure:"retry_max" validate:"min=0"`
+	RequiredAcks int      `mapstructure:"required_acks" validate:"oneof=-1 0 1"`
 }
 
 // MonitoringConfig contains monitoring configuration
 type MonitoringConfig struct {
-	EnableMetrics     bool     `mapstructure:"enable_metrics"`
-	MetricsPort       int      `mapstructure:"metrics_port"`
-	EnableTracing     bool     `mapstructure:"enable_tracing"`
-	JaegerEndpoint    string   `mapstructure:"jaeger_endpoint"`
-	SamplingRate      float64  `mapstructure:"sampling_rate"`
-	EnableNewRelic    bool     `mapstructure:"enable_newrelic"`
-	NewRelicLicense   string   `mapstructure:"newrelic_license"`
-	NewRelicAppName   string   `mapstructure:"newrelic_app_name"`
-	SentryDSN         string   `mapstructure:"sentry_dsn"`
-	SentryEnvironment string   `mapstructure:"sentry_environment"`
-	HealthCheckPath   string   `mapstructure:"health_check_path"`
-	ReadinessPath     string   `mapstructure:"readiness_path"`
+	EnableMetrics     bool              `mapstructure:"enable_metrics" default:"false"`
+	MetricsPort       int               `mapstructure:"metrics_port" default:"9090" validate:"min=1,max=65535"`
+	EnableTracing     bool              `mapstructure:"enable_tracing" default:"false"`
+	JaegerEndpoint    string            `mapstructure:"jaeger_endpoint" validate:"omitempty,url"`
+	SamplingRate      float64           `mapstructure:"sampling_rate" default:"0.1" validate:"min=0,max=1"`
+	EnableNewRelic    bool              `mapstructure:"enable_newrelic" default:"false"`
+	NewRelicLicense   string            `mapstructure:"newrelic_license" secret:"true"`
+	NewRelicAppName   string            `mapstructure:"newrelic_app_name"`
+	SentryDSN         string            `mapstructure:"sentry_dsn" secret:"true"`
+	SentryEnvironment string            `mapstructure:"sentry_environment"`
+	HealthCheckPath   string            `mapstructure:"health_check_path" default:"/health" validate:"startswith=/"`
+	ReadinessPath     string            `mapstructure:"readiness_path" default:"/ready" validate:"startswith=/"`
 	HealthCheck       HealthCheckConfig `mapstructure:"health_check"`
+	Watchdog          WatchdogConfig    `mapstructure:"watchdog"`
+}
+
+// WatchdogConfig controls the leak watchdog, which alerts when goroutines,
+// heap or in-memory buffers keep growing or pass their limits
+type WatchdogConfig struct {
+	Enabled         bool          `mapstructure:"enabled" default:"true"`
+	Interval        time.Duration `mapstructure:"interval" default:"30s" validate:"min=1s"`
+	Window          int           `mapstructure:"window" default:"10" validate:"min=2"`       // Samples that must keep rising
+	MinGrowth       float64       `mapstructure:"min_growth" default:"0.25" validate:"min=0"` // Rise over the window, as a fraction of its start
+	GoroutineLimit  int           `mapstructure:"goroutine_limit" default:"10000" validate:"min=0"`
+	HeapLimitMB     int           `mapstructure:"heap_limit_mb" default:"1024" validate:"min=0"`
+	BufferLimit     int           `mapstructure:"buffer_limit" default:"1000" validate:"min=0"` // Per buffer or queue; zero disables
+	AlertCooldown   time.Duration `mapstructure:"alert_cooldown" default:"15m" validate:"min=0"`
+	CaptureProfiles bool          `mapstructure:"capture_profiles" default:"false"` // Store a heap or goroutine profile on alert
 }
 
 // HealthCheckConfig contains health check configuration
 type HealthCheckConfig struct {
-	EnableEnterprise     bool          `mapstructure:"enable_enterprise"`
-	EnableMetrics        bool          `mapstructure:"enable_metrics"`
-	EnableCircuitBreaker bool          `mapstructure:"enable_circuit_breaker"`
-	EnableDependencies   bool          `mapstructure:"enable_dependencies"`
-	CacheTTL             time.Duration `mapstructure:"cache_ttl"`
-	Timeout              time.Duration `mapstructure:"timeout"`
+	EnableEnterprise     bool                 `mapstructure:"enable_enterprise" default:"true"`
+	EnableMetrics        bool                 `mapstructure:"enable_metrics" default:"true"`
+	EnableCircuitBreaker bool                 `mapstructure:"enable_circuit_breaker" default:"true"`
+	EnableDependencies   bool                 `mapstructure:"enable_dependencies" default:"true"`
+	CacheTTL             time.Duration        `mapstructure:"cache_ttl" default:"5s" validate:"min=0"`
+	Timeout              time.Duration        `mapstructure:"timeout" default:"10s" validate:"min=1s"`
 	CircuitBreaker       CircuitBreakerConfig `mapstructure:"circuit_breaker"`
 	Metrics              MetricsConfig        `mapstructure:"metrics"`
 }
 
 // CircuitBreakerConfig contains circuit breaker configuration
 type CircuitBreakerConfig struct {
-	FailureThreshold int           `mapstructure:"failure_threshold"`
-	SuccessThreshold int           `mapstructure:"success_threshold"`
-	Timeout          time.Duration `mapstructure:"timeout"`
-	MaxRequests      int           `mapstructure:"max_requests"`
+	FailureThreshold int           `mapstructure:"failure_threshold" default:"5" validate:"min=1"`
+	SuccessThreshold int           `mapstructure:"success_threshold" default:"2" validate:"min=1"`
+	Timeout          time.Duration `mapstructure:"timeout" default:"30s" validate:"min=1s"`
+	MaxRequests      int           `mapstructure:"max_requests" default:"3" validate:"min=1"`
 }
 
 // MetricsConfig contains metrics configuration
 type MetricsConfig struct {
-	Namespace string `mapstructure:"namespace"`
-	Subsystem string `mapstructure:"subsystem"`
-	Enabled   bool   `mapstructure:"enabled"`
+	Namespace string `mapstructure:"namespace" default:"alchemorsel"`
+	Subsystem string `mapstructure:"subsystem" default:"healthcheck"`
+	Enabled   bool   `mapstructure:"enabled" default:"true"`
 }
 
 // EmailConfig contains email service configuration
 type EmailConfig struct {
-	Provider       string `mapstructure:"provider"`
+	Provider       string `mapstructure:"provider" default:"smtp" validate:"omitempty,oneof=smtp log"`
 	SMTPHost       string `mapstructure:"smtp_host"`
-	SMTPPort       int    `mapstructure:"smtp_port"`
+	SMTPPort       int    `mapstructure:"smtp_port" validate:"min=0,max=65535"`
 	SMTPUsername   string `mapstructure:"smtp_username"`
-	SMTPPassword   string `mapstructure:"smtp_password"`
-	FromAddress    string `mapstructure:"from_address"`
+	SMTPPassword   string `mapstructure:"smtp_password" secret:"true"`
+	FromAddress    string `mapstructure:"from_address" validate:"omitempty,email"`
 	FromName       string `mapstructure:"from_name"`
-	SendGridAPIKey string `mapstructure:"sendgrid_api_key"`
-	EnableTLS      bool   `mapstructure:"enable_tls"`
+	SendGridAPIKey string `mapstructure:"sendgrid_api_key" secret:"true"`
+	EnableTLS      bool   `mapstructure:"enable_tls" default:"false"`
+
+	// Replies to comment and review notifications go to
+	// reply+<token>@ReplyDomain and come back through the inbound webhook
+	ReplyDomain   string        `mapstructure:"reply_domain" validate:"omitempty,fqdn"`
+	ReplySecret   string        `mapstructure:"reply_secret" secret:"true" validate:"required_with=ReplyDomain"`
+	ReplyTokenTTL time.Duration `mapstructure:"reply_token_ttl" default:"720h" validate:"min=1h"`
+	InboundSecret string        `mapstructure:"inbound_secret" secret:"true"`
+	SpamThreshold float64       `mapstructure:"spam_threshold" default:"5.0" validate:"min=0"`
 }
 
 // StorageConfig contains file storage configuration
 type StorageConfig struct {
-	Provider        string `mapstructure:"provider"`
-	LocalPath       string `mapstructure:"local_path"`
-	MaxFileSize     int64  `mapstructure:"max_file_size"`
-	AllowedTypes    []string `mapstructure:"allowed_types"`
-	ImageMaxWidth   int    `mapstructure:"image_max_width"`
-	ImageMaxHeight  int    `mapstructure:"image_max_height"`
-	EnableCDN       bool   `mapstructure:"enable_cdn"`
-	CDNBaseURL      string `mapstructure:"cdn_base_url"`
+	Provider       string   `mapstructure:"provider" default:"local" validate:"omitempty,oneof=local s3"`
+	LocalPath      string   `mapstructure:"local_path"`
+	MaxFileSize    int64    `mapstructure:"max_file_size" validate:"min=0"`
+	AllowedTypes   []string `mapstructure:"allowed_types"`
+	ImageMaxWidth  int      `mapstructure:"image_max_width" default:"2048" validate:"min=0"`
+	ImageMaxHeight int      `mapstructure:"image_max_height" default:"2048" validate:"min=0"`
+	EnableCDN      bool     `mapstructure:"enable_cdn" default:"false"`
+	CDNBaseURL     string   `mapstructure:"cdn_base_url" validate:"required_if=EnableCDN true,omitempty,url"`
+}
+
+// ProfilingConfig controls the admin profiling endpoints. Captures are
+// written to the storage provider under BlobPrefix.
+type ProfilingConfig struct {
+	Enabled         bool          `mapstructure:"enabled" default:"true"`
+	DefaultDuration time.Duration `mapstructure:"default_duration" default:"30s" validate:"min=1s,ltefield=MaxDuration"`
+	MaxDuration     time.Duration `mapstructure:"max_duration" default:"60s" validate:"min=1s"`
+	BlobPrefix      string        `mapstructure:"blob_prefix" default:"profiles/" validate:"required"`
+}
+
+// BrowseConfig controls the summaries behind the tag and cuisine browse
+// pages
+type BrowseConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval" default:"10m" validate:"min=1m"`
+	TopPerCuisine   int           `mapstructure:"top_per_cuisine" default:"20" validate:"min=1,max=100"`
+}
+
+// PopularityConfig controls the decayed popularity behind trending and
+// the hidden gem rotation. A recipe's score halves every HalfLife without
+// new views or likes, so old viral recipes give way to recent favourites.
+// Each refresh also re-ranks the published recipes for the trending and
+// top listings, weighing all-time views and likes the same way.
+type PopularityConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval" default:"1h" validate:"min=1m"`
+	HalfLife        time.Duration `mapstructure:"half_life" default:"168h" validate:"min=1h"`
+	ViewWeight      float64       `mapstructure:"view_weight" default:"1" validate:"min=0"`
+	LikeWeight      float64       `mapstructure:"like_weight" default:"5" validate:"min=0"`
+	GemMinRating    float64       `mapstructure:"gem_min_rating" default:"4.2" validate:"min=0,max=5"`
+	GemMaxViews     int           `mapstructure:"gem_max_views" default:"200" validate:"min=0"`
+	GemMinAge       time.Duration `mapstructure:"gem_min_age" default:"168h" validate:"min=0s"` // Newer recipes have few views anyway
+	GemsPerRotation int           `mapstructure:"gems_per_rotation" default:"6" validate:"min=1,max=50"`
+	GemRotation     time.Duration `mapstructure:"gem_rotation" default:"24h" validate:"min=1h"`
+}
+
+// EmbeddingsConfig controls the recipe vectors behind search by meaning.
+// The leader embeds new and changed recipes with the AI provider every
+// RefreshInterval, BatchSize recipes per request.
+type EmbeddingsConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval" default:"5m" validate:"min=1m"`
+	BatchSize       int           `mapstructure:"batch_size" default:"32" validate:"min=1,max=256"`
+}
+
+// RecommendationsConfig controls the "Recipes you might like" lists. The
+// leader rebuilds the list of every user with likes, favorites or views in
+// the last Window every RefreshInterval and keeps PerUser recipes for each.
+// A recipe's similar recipes are cached for SimilarTTL.
+type RecommendationsConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval" default:"1h" validate:"min=5m"`
+	Window          time.Duration `mapstructure:"window" default:"2160h" validate:"min=24h"`
+	PerUser         int           `mapstructure:"per_user" default:"50" validate:"min=1,max=500"`
+	SimilarTTL      time.Duration `mapstructure:"similar_ttl" default:"1h" validate:"min=1m"`
+}
+
+// GraphConfig controls the rebuild of the recipe knowledge graph behind
+// the related recipe sections
+type GraphConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval" default:"15m" validate:"min=1m"`
+}
+
+// CountersConfig controls the sharded recipe likes and views counters.
+// Increments spread over Shards rows per recipe and are folded into the
+// recipe row by the leader every FoldInterval.
+type CountersConfig struct {
+	Shards       int           `mapstructure:"shards" default:"16" validate:"min=1,max=256"`
+	FoldInterval time.Duration `mapstructure:"fold_interval" default:"1m" validate:"min=1s"`
+	FoldBatch    int           `mapstructure:"fold_batch" default:"500" validate:"min=1,max=10000"` // Recipes folded per transaction
+}
+
+// ViewsConfig controls recipe view counting. A viewer is counted once per
+// recipe within DedupeWindow; counts are buffered in Buffer and written to
+// the recipe and its daily totals every FlushInterval.
+type ViewsConfig struct {
+	DedupeWindow  time.Duration `mapstructure:"dedupe_window" default:"30m" validate:"min=1m"`
+	Buffer        string        `mapstructure:"buffer" default:"memory" validate:"oneof=memory redis"` // Use redis when running more than one replica
+	FlushInterval time.Duration `mapstructure:"flush_interval" default:"30s" validate:"min=1s"`
+	KeyPrefix     string        `mapstructure:"key_prefix" default:"alchemorsel:views:"`
+}
+
+// SyncConfig controls device sync. Deletes are kept as tombstones for
+// TombstoneRetention; devices offline for longer start again from zero.
+type SyncConfig struct {
+	TombstoneRetention time.Duration `mapstructure:"tombstone_retention" default:"720h" validate:"min=24h"`
+	PurgeInterval      time.Duration `mapstructure:"purge_interval" default:"1h" validate:"min=1m"`
+}
+
+// CanaryConfig sends a share of traffic on chosen API routes to a
+// candidate handler. The API rereads the config file every ReloadInterval,
+// so setting a split's percent to 0 or removing it rolls the candidate back
+// without a restart.
+type CanaryConfig struct {
+	ReloadInterval time.Duration       `mapstructure:"reload_interval" default:"15s" validate:"min=1s"`
+	Splits         []CanarySplitConfig `mapstructure:"splits" validate:"dive"` // JSON list when set from the environment
+}
+
+// CanarySplitConfig is the rule for one split
+type CanarySplitConfig struct {
+	Name    string   `mapstructure:"name" validate:"required,oneof=recipe-search"`
+	Percent float64  `mapstructure:"percent" validate:"min=0,max=100"` // Share of users sent to the candidate
+	Cohorts []string `mapstructure:"cohorts"`                          // User IDs always sent to the candidate
+}
+
+// ShadowConfig mirrors a sample of anonymous GET requests to another
+// deployment, usually staging, and logs and counts where its responses
+// differ from the ones served. Set it on the deployment being compared
+// against; mirrored requests carry no credentials or cookies.
+type ShadowConfig struct {
+	Enabled      bool          `mapstructure:"enabled" default:"false"`
+	TargetURL    string        `mapstructure:"target_url" validate:"required_if=Enabled true,omitempty,url"`
+	SampleRate   float64       `mapstructure:"sample_rate" default:"0.01" validate:"min=0,max=1"`
+	Timeout      time.Duration `mapstructure:"timeout" default:"5s" validate:"min=100ms"`
+	MaxBodyBytes int64         `mapstructure:"max_body_bytes" default:"1048576" validate:"min=1024"` // Larger responses are not compared
+	Workers      int           `mapstructure:"workers" default:"4" validate:"min=1,max=64"`
+	IgnoreFields []string      `mapstructure:"ignore_fields" default:"request_id,timestamp,generated_at,expires_at"` // JSON keys left out of comparisons
+}
+
+// ArchiveConfig controls moving old RUM views and audit rows to blob
+// storage. Rows older than the retention are rolled up or compressed per
+// day under BlobPrefix and then deleted from the database.
+type ArchiveConfig struct {
+	Enabled        bool          `mapstructure:"enabled" default:"true"`
+	Interval       time.Duration `mapstructure:"interval" default:"6h" validate:"min=1m"`
+	RecipeViewDays int           `mapstructure:"recipe_view_days" default:"90" validate:"min=1"`
+	AuditDays      int           `mapstructure:"audit_days" default:"365" validate:"min=1"`
+	MaxDaysPerRun  int           `mapstructure:"max_days_per_run" default:"30" validate:"min=1"`
+	BlobPrefix     string        `mapstructure:"blob_prefix" default:"archive/" validate:"required"`
+}
+
+// WebConfig contains settings for the cmd/web frontend
+type WebConfig struct {
+	API  WebAPIConfig  `mapstructure:"api"`
+	Home WebHomeConfig `mapstructure:"home"`
+}
+
+// WebHomeConfig lays out the home page from sections, shown in the order
+// listed; leaving a section out hides it. Each section is cached on its
+// own: shared sections for CacheTTL, and sections built for the signed-in
+// user per user for PersonalCacheTTL. An experiment shows its section to a
+// share of signed-in users only.
+type WebHomeConfig struct {
+	Sections         []string                  `mapstructure:"sections" default:"hero,continue-cooking,recommended,saved,trending,chef-activity,seasonal" validate:"unique,dive,oneof=hero trending seasonal chef-activity continue-cooking saved recommended"`
+	CacheTTL         time.Duration             `mapstructure:"cache_ttl" default:"5m" validate:"min=0"`
+	PersonalCacheTTL time.Duration             `mapstructure:"personal_cache_ttl" default:"1m" validate:"min=0"`
+	Experiments      []WebHomeExperimentConfig `mapstructure:"experiments" validate:"dive"` // JSON list when set from the environment
+}
+
+// WebHomeExperimentConfig is the rule for one home page section experiment
+type WebHomeExperimentConfig struct {
+	Section string   `mapstructure:"section" validate:"required,oneof=hero trending seasonal chef-activity continue-cooking saved recommended"`
+	Percent float64  `mapstructure:"percent" validate:"min=0,max=100"` // Share of signed-in users shown the section
+	Cohorts []string `mapstructure:"cohorts"`                          // User IDs always shown the section
+}
+
+// WebAPIConfig controls how the web frontend finds API backend instances
+// and spreads requests across them. Discovery is static (URLs), dns (an SRV
+// record) or consul (passing instances of a Consul service). An instance is
+// taken out of rotation after FailureThreshold consecutive failures and
+// probed again on /health every HealthInterval.
+type WebAPIConfig struct {
+	Discovery        string        `mapstructure:"discovery" default:"static" validate:"oneof=static dns consul"`
+	URLs             []string      `mapstructure:"urls" default:"http://localhost:3000" validate:"required_if=Discovery static,dive,url"`
+	SRVName          string        `mapstructure:"srv_name" validate:"required_if=Discovery dns"`     // e.g. _http._tcp.api.alchemorsel.svc.cluster.local
+	Scheme           string        `mapstructure:"scheme" default:"http" validate:"oneof=http https"` // For instances found by dns or consul
+	ConsulAddress    string        `mapstructure:"consul_address" default:"http://localhost:8500" validate:"required_if=Discovery consul,omitempty,url"`
+	ConsulService    string        `mapstructure:"consul_service" default:"alchemorsel-api" validate:"required_if=Discovery consul"`
+	RefreshInterval  time.Duration `mapstructure:"refresh_interval" default:"30s" validate:"min=1s"`
+	HealthInterval   time.Duration `mapstructure:"health_interval" default:"10s" validate:"min=1s"`
+	FailureThreshold int           `mapstructure:"failure_threshold" default:"3" validate:"min=1"`
+	MaxAttempts      int           `mapstructure:"max_attempts" default:"2" validate:"min=1,max=10"` // Instances tried per idempotent call
+	Timeout          time.Duration `mapstructure:"timeout" default:"30s" validate:"min=1s"`
+}
+
+// InvalidationConfig controls the bus that tells the other replicas which
+// cached recipes and users changed. memory suits a single replica; with
+// several, redis broadcasts over pub/sub on Channel.
+type InvalidationConfig struct {
+	Transport string `mapstructure:"transport" default:"memory" validate:"oneof=memory redis"`
+	Channel   string `mapstructure:"channel" default:"alchemorsel:invalidate" validate:"required"`
+}
+
+// SandboxConfig turns a deployment into a public sandbox. Its data lives
+// in Schema (Postgres) or the SQLite file and is wiped and seeded again
+// every night at ResetHour UTC. Emails and announcement webhooks are kept
+// in an outbox admins can read instead of being sent, AI answers come from
+// the mock provider, and pages show Banner.
+type SandboxConfig struct {
+	Enabled    bool   `mapstructure:"enabled" default:"false"`
+	Schema     string `mapstructure:"schema" default:"sandbox" validate:"required,alphanum,lowercase,max=63"`
+	ResetHour  int    `mapstructure:"reset_hour" default:"3" validate:"min=0,max=23"`
+	Banner     string `mapstructure:"banner" default:"This is a sandbox: emails and webhooks are not sent, AI answers are canned, and every change is wiped nightly." validate:"required"`
+	OutboxSize int    `mapstructure:"outbox_size" default:"200" validate:"min=1"` // Captured messages kept, newest first
+}
+
+// ClipperConfig limits POST /api/v1/recipes/clip, where the browser
+// extension sends pages to be saved as draft recipes. A user gets at most
+// MaxClips per Window.
+type ClipperConfig struct {
+	MaxClips    int           `mapstructure:"max_clips" default:"30" validate:"min=1"`
+	Window      time.Duration `mapstructure:"window" default:"1h" validate:"min=1m"`
+	MaxPageSize int           `mapstructure:"max_page_size" default:"2097152" validate:"min=1024"` // Bytes of page HTML accepted per clip
+}
+
+// GuestConfig controls guest mode. Visitors without an account chat with
+// the AI chef and keep up to MaxRecipes recipes for TTL. Registering or
+// signing in moves the recipes to the account; guests nobody claimed are
+// deleted every SweepInterval once their TTL has passed.
+type GuestConfig struct {
+	Enabled          bool          `mapstructure:"enabled" default:"true"`
+	TTL              time.Duration `mapstructure:"ttl" default:"24h" validate:"min=10m,max=720h"`
+	MaxRecipes       int           `mapstructure:"max_recipes" default:"5" validate:"min=1,max=100"`
+	MaxMessages      int           `mapstructure:"max_messages" default:"20" validate:"min=1"`       // AI chat messages per guest
+	MaxSessionsPerIP int           `mapstructure:"max_sessions_per_ip" default:"5" validate:"min=1"` // Guests one address may start per hour
+	SweepInterval    time.Duration `mapstructure:"sweep_interval" default:"1h" validate:"min=1m"`
+}
+
+// ModerationConfig controls reports of recipes and comments and the
+// duplicate check. Content with ReportThreshold open reports is taken down
+// until an admin reviews it; 0 leaves every takedown to the admins. Every
+// DuplicateCheckInterval, newly published recipes are compared with the
+// nearest recipe by another author: DuplicateFlagSimilarity opens a flag
+// for the admins, DuplicateArchiveSimilarity also takes the recipe down.
+type ModerationConfig struct {
+	ReportThreshold            int           `mapstructure:"report_threshold" default:"5" validate:"min=0"`
+	DuplicateFlagSimilarity    float64       `mapstructure:"duplicate_flag_similarity" default:"0.90" validate:"min=0,max=1"`
+	DuplicateArchiveSimilarity float64       `mapstructure:"duplicate_archive_similarity" default:"0.97" validate:"gtefield=DuplicateFlagSimilarity,max=1"`
+	DuplicateCheckInterval     time.Duration `mapstructure:"duplicate_check_interval" default:"1m" validate:"min=10s"`
+}
+
+// PrivacyConfig controls account deletion. DELETE /api/v1/me signs the
+// user out everywhere and erases the account once DeletionGracePeriod has
+// passed, unless they cancel first. Accounts due for erasure are found
+// every SweepInterval.
+type PrivacyConfig struct {
+	DeletionGracePeriod time.Duration `mapstructure:"deletion_grace_period" default:"720h" validate:"min=0,max=2160h"` // Zero erases on the next sweep
+	SweepInterval       time.Duration `mapstructure:"sweep_interval" default:"1h" validate:"min=1m"`
+}
+
+// ImagesConfig controls uploaded images and the variants served from
+// /img/{id}. Requested widths snap up to Widths and qualities to the nearest
+// of QualityTiers, so each image has a bounded set of variants. WebP and
+// AVIF are encoded by the WebPEncoder and AVIFEncoder commands; when one
+// cannot be found that format falls back to JPEG or PNG. Variants are kept
+// on disk under CacheDir, and those up to MaxCachedSize bytes also in the
+// shared cache for CacheTTL.
+type ImagesConfig struct {
+	Enabled       bool          `mapstructure:"enabled" default:"true"`
+	Widths        []int         `mapstructure:"widths" default:"320,400,640,800,1200,1600,1920" validate:"min=1,dive,min=16,max=4096"`
+	QualityTiers  []int         `mapstructure:"quality_tiers" default:"50,70,85" validate:"min=1,dive,min=1,max=100"`
+	MaxUploadSize int64         `mapstructure:"max_upload_size" default:"10485760" validate:"min=1024"` // Bytes
+	MaxPixels     int           `mapstructure:"max_pixels" default:"40000000" validate:"min=1"`         // Larger originals are refused before decoding
+	CacheDir      string        `mapstructure:"cache_dir" default:"./uploads/img-cache" validate:"required"`
+	CacheTTL      time.Duration `mapstructure:"cache_ttl" default:"24h" validate:"min=1m"`
+	MaxCachedSize int           `mapstructure:"max_cached_size" default:"262144" validate:"min=0"` // Bytes
+	WebPEncoder   string        `mapstructure:"webp_encoder" default:"cwebp"`
+	AVIFEncoder   string        `mapstructure:"avif_encoder" default:"avifenc"`
+	MaxConcurrent int           `mapstructure:"max_concurrent" default:"4" validate:"min=1"` // Variants encoded at once
+}
+
+// LeaseConfig controls the leases that keep scheduled jobs to one replica.
+// Replicas elect a leader through Store; only the leader runs the publishing
+// scheduler, browse refresh and archive tiering. memory suits a single
+// replica.
+type LeaseConfig struct {
+	Store     string        `mapstructure:"store" default:"database" validate:"oneof=database redis memory"`
+	TTL       time.Duration `mapstructure:"ttl" default:"15s" validate:"min=3s"` // A silent leader is replaced after this long
+	KeyPrefix string        `mapstructure:"key_prefix" default:"alchemorsel:lease:"`
 }
 
 // RateLimitConfig contains rate limiting configuration
 type RateLimitConfig struct {
-	Enable          bool          `mapstructure:"enable"`
-	RequestsPerMin  int           `mapstructure:"requests_per_min"`
-	BurstSize       int           `mapstructure:"burst_size"`
-	CleanupInterval time.Duration `mapstructure:"cleanup_interval"`
-	UseRedis        bool          `mapstructure:"use_redis"`
+	Enable          bool          `mapstructure:"enable" default:"false"`
+	RequestsPerMin  int           `mapstructure:"requests_per_min" default:"60" validate:"min=1"`
+	BurstSize       int           `mapstructure:"burst_size" default:"10" validate:"min=1"`
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval" default:"1m" validate:"min=1s"`
+	UseRedis        bool          `mapstructure:"use_redis" default:"false"`
 }
 
 // FeatureFlags contains feature toggles
 type FeatureFlags struct {
-	EnableAIRecipes      bool `mapstructure:"enable_ai_recipes"`
-	EnableSocialFeatures bool `mapstructure:"enable_social_features"`
-	EnablePremium        bool `mapstructure:"enable_premium"`
-	EnableAnalytics      bool `mapstructure:"enable_analytics"`
-	EnableExport         bool `mapstructure:"enable_export"`
-	MaintenanceMode      bool `mapstructure:"maintenance_mode"`
+	EnableAIRecipes      bool `mapstructure:"enable_ai_recipes" default:"true"`
+	EnableSocialFeatures bool `mapstructure:"enable_social_features" default:"true"`
+	EnablePremium        bool `mapstructure:"enable_premium" default:"false"`
+	EnableAnalytics      bool `mapstructure:"enable_analytics" default:"false"`
+	EnableExport         bool `mapstructure:"enable_export" default:"false"`
+	MaintenanceMode      bool `mapstructure:"maintenance_mode" default:"false"`
 }
 
-// Load loads configuration from file and environment variables
+// Load loads configuration from file and environment variables. It fails on
+// keys the schema does not declare and on values that break its rules.
 func Load(configPath string) (*Config, error) {
+	return LoadProfile(configPath, "")
+}
+
+// LoadProfile loads configuration with a startup profile applied over the
+// config file. An empty profile falls back to app.profile, which is usually
+// set by ALCHEMORSEL_APP_PROFILE.
+func LoadProfile(configPath, profile string) (*Config, error) {
 	v := viper.New()
-	
-	// Set default values
-	setDefaults(v)
-	
+
+	// Defaults and environment bindings come from the schema
+	fields := schemaFields()
+	for _, field := range fields {
+		v.SetDefault(field.key, field.defaultValue())
+		if err := v.BindEnv(field.key, field.env); err != nil {
+			return nil, fmt.Errorf("failed to bind %s: %w", field.env, err)
+		}
+	}
+
 	// Set config file
 	if configPath != "" {
 		v.SetConfigFile(configPath)
@@ -253,12 +669,7 @@ func Load(configPath string) (*Config, error) {
 		v.AddConfigPath("./config")
 		v.AddConfigPath("/etc/alchemorsel")
 	}
-	
-	// Enable environment variable override
-	v.SetEnvPrefix("ALCHEMORSEL")
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	v.AutomaticEnv()
-	
+
 	// Read config file
 	if err := v.ReadInConfig(); err != nil {
 		// It's okay if config file doesn't exist, we have defaults
@@ -266,130 +677,63 @@ func Load(configPath string) (*Config, error) {
 			return nil, fmt.Errorf("failed to read config: %w", err)
 		}
 	}
-	
-	// Unmarshal configuration
+
+	// The profile overrides the file for the keys it sets
+	fromFlag := profile != ""
+	if !fromFlag {
+		profile = v.GetString("app.profile")
+	}
+	var profileSet map[string]interface{}
+	if profile != "" {
+		var err error
+		if profileSet, err = profileValues(profile); err != nil {
+			return nil, err
+		}
+		if err := v.MergeConfigMap(nest(profileSet)); err != nil {
+			return nil, fmt.Errorf("failed to apply profile %s: %w", profile, err)
+		}
+		if fromFlag {
+			v.Set("app.profile", profile)
+		}
+	}
+
+	if unknown := unknownKeys(v, fields); len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown configuration keys: %s", strings.Join(unknown, ", "))
+	}
+	if err := decodeEnvLists(v, fields); err != nil {
+		return nil, err
+	}
+
+	// Unmarshal configuration; nested list entries must not carry unknown keys either
 	var config Config
-	if err := v.Unmarshal(&config); err != nil {
+	if err := v.UnmarshalExact(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
-	
+	config.sources = sources(v, fields, profileSet)
+	if fromFlag {
+		config.sources["app.profile"] = SourceFlag
+	}
+
 	// Validate configuration
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
-	
+
 	return &config, nil
 }
 
-// setDefaults sets default configuration values
-func setDefaults(v *viper.Viper) {
-	// App defaults
-	v.SetDefault("app.name", "Alchemorsel")
-	v.SetDefault("app.version", "3.0.0")
-	v.SetDefault("app.environment", "development")
-	v.SetDefault("app.debug", false)
-	v.SetDefault("app.log_level", "info")
-	v.SetDefault("app.log_format", "json")
-	
-	// Server defaults
-	v.SetDefault("server.host", "0.0.0.0")
-	v.SetDefault("server.port", 8080)
-	v.SetDefault("server.read_timeout", "15s")
-	v.SetDefault("server.write_timeout", "15s")
-	v.SetDefault("server.idle_timeout", "60s")
-	v.SetDefault("server.max_header_bytes", 1<<20) // 1MB
-	v.SetDefault("server.shutdown_timeout", "30s")
-	v.SetDefault("server.enable_cors", true)
-	v.SetDefault("server.enable_compression", true)
-	
-	// Database defaults
-	v.SetDefault("database.driver", "postgres")
-	v.SetDefault("database.host", "localhost")
-	v.SetDefault("database.port", 5432)
-	v.SetDefault("database.ssl_mode", "disable")
-	v.SetDefault("database.max_open_conns", 25)
-	v.SetDefault("database.max_idle_conns", 5)
-	v.SetDefault("database.conn_max_lifetime", "1h")
-	v.SetDefault("database.conn_max_idle_time", "10m")
-	v.SetDefault("database.slow_query_threshold", "100ms")
-	
-	// Redis defaults
-	v.SetDefault("redis.host", "localhost")
-	v.SetDefault("redis.port", 6379)
-	v.SetDefault("redis.database", 0)
-	v.SetDefault("redis.max_retries", 3)
-	v.SetDefault("redis.pool_size", 10)
-	
-	// Auth defaults
-	v.SetDefault("auth.jwt_expiration", "24h")
-	v.SetDefault("auth.refresh_expiration", "168h") // 7 days
-	v.SetDefault("auth.bcrypt_cost", 10)
-	
-	// Monitoring defaults
-	v.SetDefault("monitoring.metrics_port", 9090)
-	v.SetDefault("monitoring.sampling_rate", 0.1)
-	v.SetDefault("monitoring.health_check_path", "/health")
-	v.SetDefault("monitoring.readiness_path", "/ready")
-	
-	// Health check defaults
-	v.SetDefault("monitoring.health_check.enable_enterprise", true)
-	v.SetDefault("monitoring.health_check.enable_metrics", true)
-	v.SetDefault("monitoring.health_check.enable_circuit_breaker", true)
-	v.SetDefault("monitoring.health_check.enable_dependencies", true)
-	v.SetDefault("monitoring.health_check.cache_ttl", "5s")
-	v.SetDefault("monitoring.health_check.timeout", "10s")
-	
-	// Circuit breaker defaults
-	v.SetDefault("monitoring.health_check.circuit_breaker.failure_threshold", 5)
-	v.SetDefault("monitoring.health_check.circuit_breaker.success_threshold", 2)
-	v.SetDefault("monitoring.health_check.circuit_breaker.timeout", "30s")
-	v.SetDefault("monitoring.health_check.circuit_breaker.max_requests", 3)
-	
-	// Metrics defaults
-	v.SetDefault("monitoring.health_check.metrics.namespace", "alchemorsel")
-	v.SetDefault("monitoring.health_check.metrics.subsystem", "healthcheck")
-	v.SetDefault("monitoring.health_check.metrics.enabled", true)
-	
-	// AI defaults
-	v.SetDefault("ai.provider", "openai")
-	v.SetDefault("ai.openai_model", "gpt-3.5-turbo")
-	v.SetDefault("ai.max_tokens", 1500)
-	v.SetDefault("ai.temperature", 0.7)
-	v.SetDefault("ai.timeout_seconds", 30)
-	v.SetDefault("ai.enable_cache", true)
-	v.SetDefault("ai.cache_ttl", "1h")
-	
-	// Rate limit defaults
-	v.SetDefault("rate_limit.requests_per_min", 60)
-	v.SetDefault("rate_limit.burst_size", 10)
-	v.SetDefault("rate_limit.cleanup_interval", "1m")
-	
-	// Feature flags defaults
-	v.SetDefault("features.enable_ai_recipes", true)
-	v.SetDefault("features.enable_social_features", true)
-	v.SetDefault("features.enable_analytics", false)
-}
-
-// Validate validates the configuration
+// Validate checks every field against its schema rules, then the rules that
+// span sections
 func (c *Config) Validate() error {
-	// Validate required fields
-	if c.App.Name == "" {
-		return fmt.Errorf("app.name is required")
-	}
-	
-	if c.Database.Database == "" {
-		return fmt.Errorf("database.database is required")
-	}
-	
+	problems := validateSchema(c)
+
 	if c.Auth.JWTSecret == "" && c.App.Environment == "production" {
-		return fmt.Errorf("auth.jwt_secret is required in production")
+		problems = append(problems, "auth.jwt_secret is required in production")
 	}
-	
-	// Validate port ranges
-	if c.Server.Port < 1 || c.Server.Port > 65535 {
-		return fmt.Errorf("server.port must be between 1 and 65535")
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
 	}
-	
 	return nil
 }
 
@@ -405,7 +749,7 @@ func (c *Config) IsDevelopment() bool {
 
 // GetDSN returns the database connection string
 func (c *Config) GetDSN() string {
-	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		c.Database.Host,
 		c.Database.Port,
 		c.Database.Username,
@@ -413,4 +757,10 @@ func (c *Config) GetDSN() string {
 		c.Database.Database,
 		c.Database.SSLMode,
 	)
-}
\ No newline at end of file
+	// A sandbox creates its tables in its own schema; public stays on the
+	// path for extensions
+	if c.Sandbox.Enabled {
+		dsn += " search_path=" + c.Sandbox.Schema + ",public"
+	}
+	return dsn
+}
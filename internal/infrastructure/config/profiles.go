@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Startup profiles bundle the settings for one way of running the server,
+// so contributors and demos pick a name instead of tuning dozens of keys
+const (
+	ProfileDev  = "dev"
+	ProfileDemo = "demo"
+	ProfileProd = "prod"
+)
+
+// profiles maps each profile to the keys it sets. A profile overrides the
+// config file for these keys; environment variables still override both.
+var profiles = map[string]map[string]interface{}{
+	// Local Postgres and Ollama with verbose logs. Uploads go through
+	// unscanned when no local clamd is running.
+	ProfileDev: {
+		"app.environment":       "development",
+		"app.debug":             true,
+		"app.log_level":         "debug",
+		"app.log_format":        "console",
+		"server.enable_pprof":   true,
+		"database.driver":       "postgres",
+		"database.database":     "alchemorsel_dev",
+		"database.auto_migrate": true,
+		"ai.provider":           "ollama",
+		"virus_scan.fail_open":  true,
+		"email.provider":        "log",
+		"rate_limit.enable":     false,
+	},
+	// Nothing to install: a SQLite file with sample users and recipes, and
+	// canned AI answers
+	ProfileDemo: {
+		"app.environment":     "development",
+		"app.log_format":      "console",
+		"database.driver":     "sqlite",
+		"database.database":   "alchemorsel-demo.db",
+		"database.seed":       true,
+		"ai.provider":         "mock",
+		"ocr.provider":        "none",
+		"virus_scan.provider": "none",
+		"email.provider":      "log",
+		"storage.provider":    "local",
+		"storage.local_path":  "./uploads",
+		"archive.enabled":     false,
+		"rate_limit.enable":   false,
+	},
+	// Postgres, Redis and a hosted AI provider with production guardrails
+	ProfileProd: {
+		"app.environment":       "production",
+		"app.debug":             false,
+		"app.log_level":         "info",
+		"app.log_format":        "json",
+		"server.enable_pprof":   false,
+		"database.driver":       "postgres",
+		"database.ssl_mode":     "require",
+		"database.auto_migrate": false,
+		"database.seed":         false,
+		"ai.provider":           "openai",
+		"email.provider":        "smtp",
+		"auth.service.enabled":  true,
+		"rate_limit.enable":     true,
+		"rate_limit.use_redis":  true,
+	},
+}
+
+// Profiles lists the startup profile names, sorted
+func Profiles() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profileValues returns the keys a profile sets
+func profileValues(name string) (map[string]interface{}, error) {
+	values, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q, expected one of %s", name, strings.Join(Profiles(), ", "))
+	}
+	return values, nil
+}
+
+// nest turns dotted keys into the nested sections viper merges
+func nest(flat map[string]interface{}) map[string]interface{} {
+	nested := make(map[string]interface{})
+	for key, value := range flat {
+		parts := strings.Split(key, ".")
+		section := nested
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := section[part].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				section[part] = next
+			}
+			section = next
+		}
+		section[parts[len(parts)-1]] = value
+	}
+	return nested
+}
+
+// WriteProfilesMarkdown documents what each profile sets
+func WriteProfilesMarkdown(w io.Writer) error {
+	for _, name := range Profiles() {
+		values := profiles[name]
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		if _, err := fmt.Fprintf(w, "\n### %s\n\n| Key | Value |\n|---|---|\n", name); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if _, err := fmt.Fprintf(w, "| `%s` | `%v` |\n", key, values[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
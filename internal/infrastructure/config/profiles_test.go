@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEveryProfileLoads(t *testing.T) {
+	t.Setenv("ALCHEMORSEL_AUTH_JWT_SECRET", "a-production-secret-of-reasonable-length")
+	t.Setenv("ALCHEMORSEL_AUTH_SERVICE_SECRET", "a-service-secret-of-reasonable-length")
+	path := writeConfig(t, `
+database:
+  database: "alchemorsel"
+`)
+	for _, name := range Profiles() {
+		cfg, err := LoadProfile(path, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, name, cfg.App.Profile)
+	}
+}
+
+func TestProfileLayering(t *testing.T) {
+	path := writeConfig(t, `
+app:
+  log_level: "warn"
+database:
+  driver: "postgres"
+  database: "alchemorsel"
+server:
+  port: 9000
+`)
+	t.Setenv("ALCHEMORSEL_AI_PROVIDER", "ollama")
+
+	cfg, err := LoadProfile(path, ProfileDemo)
+	require.NoError(t, err)
+	assert.Equal(t, "sqlite", cfg.Database.Driver, "the profile overrides the file")
+	assert.Equal(t, "alchemorsel-demo.db", cfg.Database.Database)
+	assert.True(t, cfg.Database.Seed)
+	assert.Equal(t, "ollama", cfg.AI.Provider, "the environment overrides the profile")
+	assert.Equal(t, 9000, cfg.Server.Port, "keys the profile leaves alone keep the file value")
+	assert.Equal(t, "warn", cfg.App.LogLevel)
+
+	_, source := findSetting(t, cfg, "database.driver")
+	assert.Equal(t, SourceProfile, source)
+	_, source = findSetting(t, cfg, "ai.provider")
+	assert.Equal(t, SourceEnv, source)
+	_, source = findSetting(t, cfg, "app.profile")
+	assert.Equal(t, SourceFlag, source)
+}
+
+func TestProfileFromEnvironment(t *testing.T) {
+	t.Setenv("ALCHEMORSEL_APP_PROFILE", ProfileDemo)
+	cfg, err := Load(writeConfig(t, `
+database:
+  database: "alchemorsel"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "sqlite", cfg.Database.Driver)
+
+	cfg, err = LoadProfile(writeConfig(t, `
+database:
+  database: "alchemorsel"
+`), ProfileDev)
+	require.NoError(t, err)
+	assert.Equal(t, ProfileDev, cfg.App.Profile, "the flag beats the environment")
+	assert.Equal(t, "postgres", cfg.Database.Driver)
+}
+
+func TestUnknownProfile(t *testing.T) {
+	_, err := LoadProfile(writeConfig(t, `
+database:
+  database: "alchemorsel"
+`), "staging")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown profile "staging", expected one of demo, dev, prod`)
+}
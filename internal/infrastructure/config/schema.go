@@ -0,0 +1,362 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/viper"
+)
+
+// envPrefix starts the environment variable bound to every key
+const envPrefix = "ALCHEMORSEL"
+
+// Where a key's effective value came from
+const (
+	SourceDefault = "default"
+	SourceProfile = "profile"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// redacted replaces secret values that are set
+const redacted = "********"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is one key of the schema, found by walking Config
+type field struct {
+	key   string
+	env   string
+	index []int
+	typ   reflect.Type
+	tag   reflect.StructTag
+}
+
+// schemaFields lists every key in Config, in declaration order
+func schemaFields() []field {
+	var fields []field
+	var walk func(t reflect.Type, prefix string, index []int)
+	walk = func(t reflect.Type, prefix string, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name := sf.Tag.Get("mapstructure")
+			if name == "" {
+				continue
+			}
+			key := prefix + name
+			path := append(append([]int(nil), index...), i)
+			if sf.Type.Kind() == reflect.Struct {
+				walk(sf.Type, key+".", path)
+				continue
+			}
+			fields = append(fields, field{
+				key:   key,
+				env:   envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_")),
+				index: path,
+				typ:   sf.Type,
+				tag:   sf.Tag,
+			})
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "", nil)
+	return fields
+}
+
+// defaultValue is what viper falls back to; weak decoding turns the tag
+// text into the field's type
+func (f field) defaultValue() interface{} {
+	raw, ok := f.tag.Lookup("default")
+	if !ok {
+		return reflect.Zero(f.typ).Interface()
+	}
+	if f.typ.Kind() == reflect.Slice {
+		return strings.Split(raw, ",")
+	}
+	return raw
+}
+
+// isObjectList reports whether the key holds a list of sections, such as
+// the announcement channels
+func (f field) isObjectList() bool {
+	return f.typ.Kind() == reflect.Slice && f.typ.Elem().Kind() == reflect.Struct
+}
+
+func (f field) setting() outbound.ConfigSetting {
+	def, ok := f.tag.Lookup("default")
+	if !ok {
+		def = fmt.Sprint(displayValue(reflect.Zero(f.typ), false))
+	}
+	return outbound.ConfigSetting{
+		Key:     f.key,
+		Env:     f.env,
+		Type:    typeName(f.typ),
+		Default: def,
+		Rules:   f.tag.Get("validate"),
+		Secret:  f.tag.Get("secret") == "true",
+	}
+}
+
+// unknownKeys returns the keys set in the file that the schema does not
+// declare, sorted. A whole unknown section is reported once by its name.
+func unknownKeys(v *viper.Viper, fields []field) []string {
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.key] = true
+		for i, r := range f.key {
+			if r == '.' {
+				known[f.key[:i]] = true
+			}
+		}
+	}
+
+	reported := make(map[string]bool)
+	var unknown []string
+	for _, key := range v.AllKeys() {
+		if known[key] {
+			continue
+		}
+		parts := strings.Split(key, ".")
+		name := parts[0]
+		for i := 1; known[name] && i < len(parts); i++ {
+			name += "." + parts[i]
+		}
+		if !reported[name] {
+			reported[name] = true
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// decodeEnvLists parses lists of sections set from the environment, which
+// are given as JSON
+func decodeEnvLists(v *viper.Viper, fields []field) error {
+	for _, f := range fields {
+		raw := os.Getenv(f.env)
+		if !f.isObjectList() || raw == "" {
+			continue
+		}
+		var list []map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &list); err != nil {
+			return fmt.Errorf("%s must be a JSON list: %w", f.env, err)
+		}
+		v.Set(f.key, list)
+	}
+	return nil
+}
+
+// sources records where each key's value came from
+func sources(v *viper.Viper, fields []field, profile map[string]interface{}) map[string]string {
+	found := make(map[string]string, len(fields))
+	for _, f := range fields {
+		_, inProfile := profile[f.key]
+		switch {
+		case os.Getenv(f.env) != "":
+			found[f.key] = SourceEnv
+		case inProfile:
+			found[f.key] = SourceProfile
+		case v.InConfig(f.key):
+			found[f.key] = SourceFile
+		default:
+			found[f.key] = SourceDefault
+		}
+	}
+	return found
+}
+
+var (
+	schemaValidator     *validator.Validate
+	schemaValidatorOnce sync.Once
+)
+
+// validateSchema checks each field's validate rules and describes every
+// failure by its config key
+func validateSchema(c *Config) []string {
+	schemaValidatorOnce.Do(func() {
+		schemaValidator = validator.New()
+		schemaValidator.RegisterTagNameFunc(func(sf reflect.StructField) string {
+			return sf.Tag.Get("mapstructure")
+		})
+	})
+
+	err := schemaValidator.Struct(c)
+	var failures validator.ValidationErrors
+	if !errors.As(err, &failures) {
+		if err != nil {
+			return []string{err.Error()}
+		}
+		return nil
+	}
+
+	problems := make([]string, len(failures))
+	for i, failure := range failures {
+		problems[i] = describe(failure)
+	}
+	return problems
+}
+
+// describe turns a validation failure into a message naming the key
+func describe(failure validator.FieldError) string {
+	key := failure.Namespace()
+	if i := strings.Index(key, "."); i >= 0 {
+		key = key[i+1:]
+	}
+	param := failure.Param()
+
+	switch failure.Tag() {
+	case "required", "required_if", "required_with":
+		return key + " is required"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s, got %v", key, strings.ReplaceAll(param, " ", ", "), failure.Value())
+	case "min", "gte":
+		return fmt.Sprintf("%s must be at least %s, got %v", key, param, failure.Value())
+	case "max", "lte":
+		return fmt.Sprintf("%s must be at most %s, got %v", key, param, failure.Value())
+	case "ltefield":
+		return fmt.Sprintf("%s must not be greater than %s", key, param)
+	case "gtefield":
+		return fmt.Sprintf("%s must not be less than %s", key, param)
+	case "url":
+		return key + " must be a URL"
+	case "email":
+		return key + " must be an email address"
+	case "fqdn":
+		return key + " must be a domain name"
+	case "startswith":
+		return fmt.Sprintf("%s must start with %q", key, param)
+	default:
+		return fmt.Sprintf("%s fails %s=%s", key, failure.Tag(), param)
+	}
+}
+
+// Schema lists every configuration key with its environment variable,
+// default and rules. Fields of list entries follow their list as
+// key[].field, without an environment variable of their own.
+func Schema() []outbound.ConfigSetting {
+	var settings []outbound.ConfigSetting
+	for _, f := range schemaFields() {
+		settings = append(settings, f.setting())
+		if !f.isObjectList() {
+			continue
+		}
+		item := f.typ.Elem()
+		for i := 0; i < item.NumField(); i++ {
+			sf := item.Field(i)
+			entry := field{key: f.key + "[]." + sf.Tag.Get("mapstructure"), typ: sf.Type, tag: sf.Tag}
+			settings = append(settings, entry.setting())
+		}
+	}
+	return settings
+}
+
+// Settings lists every key with its effective value and where the value came
+// from. Secret values are redacted.
+func (c *Config) Settings() []outbound.ConfigSetting {
+	current := reflect.ValueOf(c).Elem()
+	fields := schemaFields()
+	settings := make([]outbound.ConfigSetting, len(fields))
+	for i, f := range fields {
+		setting := f.setting()
+		setting.Value = displayValue(current.FieldByIndex(f.index), setting.Secret)
+		setting.Source = c.sources[f.key]
+		if setting.Source == "" {
+			setting.Source = SourceDefault
+		}
+		settings[i] = setting
+	}
+	return settings
+}
+
+// displayValue renders a value for the reference: durations as text, lists
+// never null, and secrets hidden
+func displayValue(value reflect.Value, secret bool) interface{} {
+	if secret {
+		if value.IsZero() {
+			return ""
+		}
+		return redacted
+	}
+	switch {
+	case value.Type() == durationType:
+		return time.Duration(value.Int()).String()
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Struct:
+		items := make([]map[string]interface{}, value.Len())
+		for i := range items {
+			item := value.Index(i)
+			items[i] = make(map[string]interface{})
+			for j := 0; j < item.NumField(); j++ {
+				sf := item.Type().Field(j)
+				items[i][sf.Tag.Get("mapstructure")] = displayValue(item.Field(j), sf.Tag.Get("secret") == "true")
+			}
+		}
+		return items
+	case value.Kind() == reflect.Slice:
+		if value.Len() == 0 {
+			return []interface{}{}
+		}
+		return value.Interface()
+	default:
+		return value.Interface()
+	}
+}
+
+func typeName(t reflect.Type) string {
+	switch {
+	case t == durationType:
+		return "duration"
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct:
+		return "list of objects"
+	case t.Kind() == reflect.Slice:
+		return "list of " + typeName(t.Elem())
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		return "int"
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return "float"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// WriteMarkdown writes the settings as a reference document, one table per
+// section
+func WriteMarkdown(w io.Writer, settings []outbound.ConfigSetting) error {
+	section := ""
+	for _, setting := range settings {
+		top := strings.SplitN(setting.Key, ".", 2)[0]
+		if top != section {
+			section = top
+			if _, err := fmt.Fprintf(w, "\n## %s\n\n| Key | Type | Default | Rules | Environment |\n|---|---|---|---|---|\n", section); err != nil {
+				return err
+			}
+		}
+		rules := setting.Rules
+		if setting.Secret {
+			rules = strings.TrimPrefix(rules+", secret", ", ")
+		}
+		if _, err := fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s |\n",
+			setting.Key, setting.Type, markdownCode(setting.Default), markdownCode(rules), markdownCode(setting.Env)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markdownCode wraps non-empty table text in backticks, escaping pipes
+func markdownCode(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(text, "|", "\\|") + "`"
+}
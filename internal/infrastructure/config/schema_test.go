@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func findSetting(t *testing.T, cfg *Config, key string) (value interface{}, source string) {
+	for _, setting := range cfg.Settings() {
+		if setting.Key == key {
+			return setting.Value, setting.Source
+		}
+	}
+	t.Fatalf("no setting %s", key)
+	return nil, ""
+}
+
+func TestShippedConfigMatchesSchema(t *testing.T) {
+	_, err := Load("../../../config/config.yaml")
+	require.NoError(t, err)
+}
+
+func TestLoadLayersDefaultsFileAndEnv(t *testing.T) {
+	path := writeConfig(t, `
+database:
+  database: "alchemorsel_test"
+  password: "hunter2"
+server:
+  port: 9000
+publishing:
+  channels: []
+`)
+	t.Setenv("ALCHEMORSEL_SERVER_READ_TIMEOUT", "5s")
+	t.Setenv("ALCHEMORSEL_AI_OPENAI_KEY", "sk-test")
+	t.Setenv("ALCHEMORSEL_PUBLISHING_CHANNELS", `[{"name":"partners","type":"webhook","url":"https://hooks.example.com","secret":"s3cret"}]`)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 9000, cfg.Server.Port)
+	assert.Equal(t, 5*time.Second, cfg.Server.ReadTimeout, "keys without a file value still read the environment")
+	assert.Equal(t, "sk-test", cfg.AI.OpenAIKey)
+	assert.Equal(t, 25, cfg.Database.MaxOpenConns)
+	require.Len(t, cfg.Publishing.Channels, 1)
+	assert.Equal(t, "https://hooks.example.com", cfg.Publishing.Channels[0].URL)
+
+	value, source := findSetting(t, cfg, "server.port")
+	assert.Equal(t, 9000, value)
+	assert.Equal(t, SourceFile, source)
+	value, source = findSetting(t, cfg, "server.read_timeout")
+	assert.Equal(t, "5s", value)
+	assert.Equal(t, SourceEnv, source)
+	_, source = findSetting(t, cfg, "database.max_open_conns")
+	assert.Equal(t, SourceDefault, source)
+
+	value, _ = findSetting(t, cfg, "database.password")
+	assert.Equal(t, redacted, value)
+	value, _ = findSetting(t, cfg, "ai.anthropic_key")
+	assert.Equal(t, "", value, "unset secrets show as empty")
+	value, _ = findSetting(t, cfg, "publishing.channels")
+	require.Len(t, value, 1)
+	assert.Equal(t, redacted, value.([]map[string]interface{})[0]["secret"])
+}
+
+func TestLoadFailsFast(t *testing.T) {
+	_, err := Load(writeConfig(t, `
+database:
+  database: "alchemorsel_test"
+  pasword: "typo"
+tracing:
+  enabled: true
+`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown configuration keys: database.pasword, tracing")
+
+	_, err = Load(writeConfig(t, `
+database:
+  database: "alchemorsel_test"
+publishing:
+  channels:
+    - name: "partners"
+      type: "webhook"
+      url: "https://hooks.example.com"
+      sekret: "typo"
+`))
+	require.Error(t, err, "unknown keys inside list entries are rejected too")
+
+	_, err = Load(writeConfig(t, `
+app:
+  log_level: "verbose"
+server:
+  port: 70000
+profiling:
+  default_duration: "2m"
+`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "app.log_level must be one of debug, info, warn, error, got verbose")
+	assert.Contains(t, err.Error(), "server.port must be at most 65535")
+	assert.Contains(t, err.Error(), "database.database is required")
+	assert.Contains(t, err.Error(), "profiling.default_duration must not be greater than MaxDuration")
+}
+
+func TestSchemaDescribesEveryKey(t *testing.T) {
+	settings := Schema()
+	keys := make(map[string]bool, len(settings))
+	for _, setting := range settings {
+		assert.False(t, keys[setting.Key], "duplicate key %s", setting.Key)
+		keys[setting.Key] = true
+		assert.NotEmpty(t, setting.Type, setting.Key)
+	}
+
+	port := settings[0]
+	for _, setting := range settings {
+		if setting.Key == "server.port" {
+			port = setting
+		}
+	}
+	assert.Equal(t, "ALCHEMORSEL_SERVER_PORT", port.Env)
+	assert.Equal(t, "8080", port.Default)
+	assert.Equal(t, "min=1,max=65535", port.Rules)
+	assert.True(t, keys["monitoring.health_check.circuit_breaker.timeout"])
+}
@@ -4,30 +4,91 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"time"
 
+	"github.com/alchemorsel/v3/internal/application/accountdeletion"
+	"github.com/alchemorsel/v3/internal/application/admin"
+	"github.com/alchemorsel/v3/internal/application/allergens"
+	"github.com/alchemorsel/v3/internal/application/archive"
+	"github.com/alchemorsel/v3/internal/application/browse"
+	"github.com/alchemorsel/v3/internal/application/battle"
+	"github.com/alchemorsel/v3/internal/application/clipper"
+	"github.com/alchemorsel/v3/internal/application/duplicate"
+	"github.com/alchemorsel/v3/internal/application/export"
+	"github.com/alchemorsel/v3/internal/application/follow"
+	"github.com/alchemorsel/v3/internal/application/notification"
+	"github.com/alchemorsel/v3/internal/application/portability"
+	"github.com/alchemorsel/v3/internal/application/report"
+	"github.com/alchemorsel/v3/internal/application/substitution"
+	"github.com/alchemorsel/v3/internal/application/foodsafety"
+	"github.com/alchemorsel/v3/internal/application/graph"
+	"github.com/alchemorsel/v3/internal/application/guest"
+	"github.com/alchemorsel/v3/internal/application/imaging"
+	"github.com/alchemorsel/v3/internal/application/comment"
+	"github.com/alchemorsel/v3/internal/application/profiling"
 	"github.com/alchemorsel/v3/internal/application/recipe"
+	"github.com/alchemorsel/v3/internal/application/recommendation"
+	"github.com/alchemorsel/v3/internal/application/sandbox"
+	"github.com/alchemorsel/v3/internal/application/settings"
+	"github.com/alchemorsel/v3/internal/application/translation"
+	"github.com/alchemorsel/v3/internal/application/offline"
+	"github.com/alchemorsel/v3/internal/application/pantry"
+	"github.com/alchemorsel/v3/internal/application/passwordreset"
+	"github.com/alchemorsel/v3/internal/application/embedding"
+	"github.com/alchemorsel/v3/internal/application/popularity"
+	"github.com/alchemorsel/v3/internal/application/shoppinglist"
+	"github.com/alchemorsel/v3/internal/application/technique"
+	"github.com/alchemorsel/v3/internal/application/timeline"
+	"github.com/alchemorsel/v3/internal/application/uploadscan"
+	"github.com/alchemorsel/v3/internal/application/urlimport"
+	"github.com/alchemorsel/v3/internal/application/verification"
+	"github.com/alchemorsel/v3/internal/application/views"
 	"github.com/alchemorsel/v3/internal/application/user"
+	"github.com/alchemorsel/v3/internal/application/warmup"
+	"github.com/alchemorsel/v3/internal/infrastructure/ai/mock"
+	"github.com/alchemorsel/v3/internal/infrastructure/ai/ollama"
 	"github.com/alchemorsel/v3/internal/infrastructure/ai/openai"
+	"github.com/alchemorsel/v3/internal/infrastructure/announce"
+	"github.com/alchemorsel/v3/internal/infrastructure/blobstore"
 	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/infrastructure/email"
 	"github.com/alchemorsel/v3/internal/infrastructure/http/apiserver"
 	"github.com/alchemorsel/v3/internal/infrastructure/http/server"
+	"github.com/alchemorsel/v3/internal/infrastructure/imagecodec"
+	"github.com/alchemorsel/v3/internal/infrastructure/imageprobe"
+	"github.com/alchemorsel/v3/internal/infrastructure/observability/metrics"
+	"github.com/alchemorsel/v3/internal/infrastructure/ocr"
+	runtimeProfiling "github.com/alchemorsel/v3/internal/infrastructure/profiling"
+	"github.com/alchemorsel/v3/internal/infrastructure/recipeimport"
 	gormRepo "github.com/alchemorsel/v3/internal/infrastructure/persistence/gorm"
+	"github.com/alchemorsel/v3/internal/infrastructure/persistence/migrations"
 	"github.com/alchemorsel/v3/internal/infrastructure/persistence/memory"
 	"github.com/alchemorsel/v3/internal/infrastructure/persistence/postgres"
+	"github.com/alchemorsel/v3/internal/infrastructure/persistence/sqlite"
+	sandboxInfra "github.com/alchemorsel/v3/internal/infrastructure/sandbox"
 	"github.com/alchemorsel/v3/internal/infrastructure/security"
+	"github.com/alchemorsel/v3/internal/infrastructure/viewbuffer"
+	"github.com/alchemorsel/v3/internal/infrastructure/virusscan"
+	"github.com/alchemorsel/v3/internal/infrastructure/watchdog"
 	"github.com/alchemorsel/v3/internal/ports/inbound"
 	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/canary"
 	"github.com/alchemorsel/v3/pkg/healthcheck"
+	"github.com/alchemorsel/v3/pkg/invalidation"
+	"github.com/alchemorsel/v3/pkg/lease"
 	"github.com/alchemorsel/v3/pkg/logger"
+	"github.com/alchemorsel/v3/pkg/sqlsafe"
 	
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 // Module provides all dependency injection modules
@@ -57,10 +118,25 @@ var Module = fx.Options(
 	LifecycleModule,
 )
 
+// Profile is the startup profile picked on the command line
+type Profile string
+
+// WithProfile selects a startup profile, overriding app.profile from the
+// config file and environment
+func WithProfile(name string) fx.Option {
+	return fx.Supply(Profile(name))
+}
+
+type configParams struct {
+	fx.In
+
+	Profile Profile `optional:"true"`
+}
+
 // ConfigModule provides configuration
 var ConfigModule = fx.Provide(
-	func() (*config.Config, error) {
-		return config.Load("")
+	func(p configParams) (*config.Config, error) {
+		return config.LoadProfile("", string(p.Profile))
 	},
 )
 
@@ -81,47 +157,16 @@ var LoggerModule = fx.Provide(
 
 // DatabaseModule provides database connections with PostgreSQL and performance optimization
 var DatabaseModule = fx.Provide(
-	// PostgreSQL database with performance optimization
+	// PostgreSQL database with performance optimization; queries are
+	// counted for /metrics
 	func(cfg *config.Config, log *zap.Logger) (*gorm.DB, error) {
-		// Import PostgreSQL connection manager
-		pgPkg := "github.com/alchemorsel/v3/internal/infrastructure/persistence/postgres"
-		_ = pgPkg // Ensure import
-		
-		// Create PostgreSQL connection manager with optimized settings
-		connectionManager, err := postgres.NewConnectionManager(cfg, log)
+		db, err := openDatabase(cfg, log)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create PostgreSQL connection manager: %w", err)
+			return nil, err
 		}
-
-		db := connectionManager.GetDB()
-		
-		// Auto-migrate models if enabled
-		if cfg.Database.AutoMigrate {
-			if err := db.AutoMigrate(
-				&gormRepo.UserModel{},
-				&gormRepo.RecipeModel{},
-				&gormRepo.RatingModel{},
-				&gormRepo.AIRequestModel{},
-				&gormRepo.RecipeLikeModel{},
-				&gormRepo.UserFollowModel{},
-				&gormRepo.CollectionModel{},
-				&gormRepo.CollectionRecipeModel{},
-				&gormRepo.CommentModel{},
-				&gormRepo.ActivityModel{},
-				&gormRepo.RecipeViewModel{},
-			); err != nil {
-				log.Warn("Failed to auto-migrate database", zap.Error(err))
-			}
+		if err := metrics.InstrumentGORM(db); err != nil {
+			return nil, fmt.Errorf("failed to instrument database: %w", err)
 		}
-
-		log.Info("Connected to PostgreSQL database with performance optimization",
-			zap.String("host", cfg.Database.Host),
-			zap.Int("port", cfg.Database.Port),
-			zap.String("database", cfg.Database.Database),
-			zap.Int("max_open_conns", cfg.Database.MaxOpenConns),
-			zap.Int("max_idle_conns", cfg.Database.MaxIdleConns),
-		)
-
 		return db, nil
 	},
 	
@@ -172,11 +217,106 @@ var DatabaseModule = fx.Provide(
 	},
 )
 
+// openDatabase connects to the configured database, bringing its schema
+// up to date
+func openDatabase(cfg *config.Config, log *zap.Logger) (*gorm.DB, error) {
+	if cfg.Database.Driver == "sqlite" {
+		return openSQLite(cfg, log)
+	}
+
+	// Import PostgreSQL connection manager
+	pgPkg := "github.com/alchemorsel/v3/internal/infrastructure/persistence/postgres"
+	_ = pgPkg // Ensure import
+	
+	// Create PostgreSQL connection manager with optimized settings
+	connectionManager, err := postgres.NewConnectionManager(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PostgreSQL connection manager: %w", err)
+	}
+
+	db := connectionManager.GetDB()
+	
+	// A sandbox keeps its tables in their own schema
+	if cfg.Sandbox.Enabled {
+		if err := sandboxInfra.PrepareSchema(db, cfg.Sandbox.Schema); err != nil {
+			return nil, err
+		}
+	}
+	
+	// Bring the schema up to date, or refuse to start against a stale one
+	if err := migrateSchema(cfg, db, log); err != nil {
+		return nil, err
+	}
+	if cfg.Sandbox.Enabled {
+		if err := sqlite.SeedDatabase(db); err != nil {
+			return nil, fmt.Errorf("failed to seed sandbox: %w", err)
+		}
+	}
+
+	log.Info("Connected to PostgreSQL database with performance optimization",
+		zap.String("host", cfg.Database.Host),
+		zap.Int("port", cfg.Database.Port),
+		zap.String("database", cfg.Database.Database),
+		zap.Int("max_open_conns", cfg.Database.MaxOpenConns),
+		zap.Int("max_idle_conns", cfg.Database.MaxIdleConns),
+	)
+
+	return db, nil
+}
+
+// openSQLite opens the single-file database used by the demo profile,
+// migrating it and adding the sample data when database.seed is set
+func openSQLite(cfg *config.Config, log *zap.Logger) (*gorm.DB, error) {
+	db, err := sqlite.SetupDatabase(cfg.Database.Database, gormlogger.Silent)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Use(sqlsafe.NewAuditor(log)); err != nil {
+		return nil, fmt.Errorf("failed to install query audit: %w", err)
+	}
+	if cfg.Database.Seed || cfg.Sandbox.Enabled {
+		if err := sqlite.SeedDatabase(db); err != nil {
+			return nil, fmt.Errorf("failed to seed database: %w", err)
+		}
+	}
+
+	log.Info("Connected to SQLite database",
+		zap.String("path", cfg.Database.Database),
+		zap.Bool("seeded", cfg.Database.Seed),
+	)
+	return db, nil
+}
+
+// migrateSchema applies the pending SQL migrations when
+// database.auto_migrate is set or the sandbox owns the schema, and otherwise
+// fails unless the schema is already current, so a deploy that skipped
+// `api-pure migrate up` stops here instead of at the first query against a
+// missing column
+func migrateSchema(cfg *config.Config, db *gorm.DB, log *zap.Logger) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	migrator, err := migrations.New(sqlDB, log)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	if cfg.Database.AutoMigrate || cfg.Sandbox.Enabled {
+		return migrator.Up()
+	}
+	if err := migrator.Check(); err != nil {
+		return fmt.Errorf("%w; run `api-pure migrate up` or set database.auto_migrate", err)
+	}
+	return nil
+}
+
 // CacheModule provides caching
 var CacheModule = fx.Provide(
 	func(log *zap.Logger) outbound.CacheRepository {
 		log.Info("Using in-memory cache for demo")
-		return memory.NewCacheRepository()
+		return metrics.InstrumentCache(memory.NewCacheRepository())
 	},
 	
 	// Mock message bus for demo
@@ -252,6 +392,30 @@ var HealthCheckModule = fx.Provide(
 		},
 		fx.ResultTags(`group:"healthcheckers"`),
 	),
+	
+	// Virus scanner checker; uploads are refused or pass unscanned while
+	// it is down, so it degrades health rather than failing it
+	fx.Annotate(
+		func(scanner outbound.VirusScanner, cfg *config.Config) healthcheck.Checker {
+			return healthcheck.NewCustomChecker("virus_scanner", func(ctx context.Context) (healthcheck.Status, string, interface{}) {
+				if scanner == nil {
+					return healthcheck.StatusHealthy, "Virus scanning disabled", nil
+				}
+				metadata := map[string]interface{}{
+					"scanner": scanner.Name(),
+					"fail_open": cfg.VirusScan.FailOpen,
+				}
+				if err := scanner.Ping(ctx); err != nil {
+					if cfg.VirusScan.FailOpen {
+						return healthcheck.StatusDegraded, "Virus scanner unreachable, uploads pass unscanned: " + err.Error(), metadata
+					}
+					return healthcheck.StatusDegraded, "Virus scanner unreachable, uploads are refused: " + err.Error(), metadata
+				}
+				return healthcheck.StatusHealthy, "Virus scanner operational", metadata
+			})
+		},
+		fx.ResultTags(`group:"healthcheckers"`),
+	),
 
 	// Health checker group collector
 	fx.Annotate(
@@ -275,20 +439,306 @@ var RepositoryModule = fx.Provide(
 		gormRepo.NewUserRepository,
 		fx.As(new(outbound.UserRepository)),
 	),
+	
+	// Search analytics repository
+	fx.Annotate(
+		gormRepo.NewSearchAnalyticsRepository,
+		fx.As(new(outbound.SearchAnalyticsRepository)),
+	),
+	
+	// Recipe view analytics repository
+	fx.Annotate(
+		gormRepo.NewRecipeAnalyticsRepository,
+		fx.As(new(outbound.RecipeAnalyticsRepository)),
+	),
+	
+	// Sharded recipe likes and views counters
+	func(db *gorm.DB, cfg *config.Config) outbound.RecipeCounterRepository {
+		return gormRepo.NewRecipeCounterRepository(db, cfg.Counters.Shards)
+	},
+	
+	// Recipe announcement queue
+	fx.Annotate(
+		gormRepo.NewAnnouncementRepository,
+		fx.As(new(outbound.AnnouncementRepository)),
+	),
+	
+	// Field-level recipe change log for sync clients
+	fx.Annotate(
+		gormRepo.NewRecipeChangeRepository,
+		fx.As(new(outbound.RecipeChangeRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewSyncRepository,
+		fx.As(new(outbound.SyncRepository)),
+	),
+	
+	// Comments and reply-by-email
+	fx.Annotate(
+		gormRepo.NewCommentRepository,
+		fx.As(new(outbound.CommentRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewCommentFeedbackRepository,
+		fx.As(new(outbound.CommentFeedbackRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewCommentModerationRepository,
+		fx.As(new(outbound.CommentModerationRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewReplyTokenRepository,
+		fx.As(new(outbound.ReplyTokenRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewEmailSuppressionRepository,
+		fx.As(new(outbound.EmailSuppressionRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewPasswordResetTokenRepository,
+		fx.As(new(outbound.PasswordResetTokenRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewRecipeClipRepository,
+		fx.As(new(outbound.RecipeClipRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewGuestRepository,
+		fx.As(new(outbound.GuestRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewImageRepository,
+		fx.As(new(outbound.ImageRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewIngredientNutritionRepository,
+		fx.As(new(outbound.IngredientNutritionRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewIngredientSubstituteRepository,
+		fx.As(new(outbound.IngredientSubstituteRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewFavoriteRepository,
+		fx.As(new(outbound.FavoriteRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewCollectionRepository,
+		fx.As(new(outbound.CollectionRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewAccountDeletionRepository,
+		fx.As(new(outbound.AccountDeletionRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewAccountAuditRepository,
+		fx.As(new(outbound.AccountAuditRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewAccountEraser,
+		fx.As(new(outbound.AccountEraser)),
+	),
+	fx.Annotate(
+		gormRepo.NewFollowRepository,
+		fx.As(new(outbound.FollowRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewNotificationRepository,
+		fx.As(new(outbound.NotificationRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewReportRepository,
+		fx.As(new(outbound.ReportRepository)),
+	),
+	fx.Annotate(
+		gormRepo.NewDuplicateRepository,
+		fx.As(new(outbound.DuplicateRepository)),
+	),
+	
+	// Recipe vectors for search by meaning
+	fx.Annotate(
+		gormRepo.NewRecipeEmbeddingRepository,
+		fx.As(new(outbound.RecipeEmbeddingRepository)),
+	),
+	
+	// Likes, favorites, views and recipe features for recommendations
+	fx.Annotate(
+		gormRepo.NewRecommendationSignalRepository,
+		fx.As(new(outbound.RecommendationSignalRepository)),
+	),
+	
+	// Shared shopping lists
+	fx.Annotate(
+		gormRepo.NewShoppingListRepository,
+		fx.As(new(outbound.ShoppingListRepository)),
+	),
+	
+	// Pantry inventory and cook logs
+	fx.Annotate(
+		gormRepo.NewPantryRepository,
+		fx.As(new(outbound.PantryRepository)),
+	),
+	
+	// Virus scans of uploaded files
+	fx.Annotate(
+		gormRepo.NewUploadScanRepository,
+		fx.As(new(outbound.UploadScanRepository)),
+	),
+	
+	// Recipe rows streamed for bulk exports
+	fx.Annotate(
+		gormRepo.NewRecipeExportRepository,
+		fx.As(new(outbound.RecipeExportRepository)),
+	),
+	
+	// Author verification claims and fake claim reports
+	fx.Annotate(
+		gormRepo.NewVerificationRepository,
+		fx.As(new(outbound.VerificationRepository)),
+	),
+	
+	// Profiling captures and their audit trail
+	fx.Annotate(
+		gormRepo.NewProfileCaptureRepository,
+		fx.As(new(outbound.ProfileCaptureRepository)),
+	),
+	
+	// Tag and cuisine browse summaries
+	fx.Annotate(
+		gormRepo.NewBrowseSummaryRepository,
+		fx.As(new(outbound.BrowseSummaryRepository)),
+	),
+	
+	// Decayed recipe popularity and the hidden gem rotation
+	fx.Annotate(
+		gormRepo.NewRecipePopularityRepository,
+		fx.As(new(outbound.RecipePopularityRepository)),
+	),
+	
+	// Recipe knowledge graph adjacency tables
+	fx.Annotate(
+		gormRepo.NewRecipeGraphRepository,
+		fx.As(new(outbound.RecipeGraphRepository)),
+	),
+	
+	// Technique library and recipe step links
+	fx.Annotate(
+		gormRepo.NewTechniqueRepository,
+		fx.As(new(outbound.TechniqueRepository)),
+	),
+	
+	// Recipe steps and durations for timelines
+	fx.Annotate(
+		gormRepo.NewRecipeTimelineRepository,
+		fx.As(new(outbound.RecipeTimelineRepository)),
+	),
+	
+	// Recipe ingredients and steps for food safety checks
+	fx.Annotate(
+		gormRepo.NewRecipeSafetyRepository,
+		fx.As(new(outbound.RecipeSafetyRepository)),
+	),
+	
+	// Recipe ingredients for allergen panels
+	fx.Annotate(
+		gormRepo.NewRecipeAllergenRepository,
+		fx.As(new(outbound.RecipeAllergenRepository)),
+	),
+	
+	// User lists and totals for the admin section
+	fx.Annotate(
+		gormRepo.NewAdminRepository,
+		fx.As(new(outbound.AdminRepository)),
+	),
+	
+	// Per-field recipe translations
+	fx.Annotate(
+		gormRepo.NewRecipeTranslationRepository,
+		fx.As(new(outbound.RecipeTranslationRepository)),
+	),
+	
+	// AI vs community remix battles
+	fx.Annotate(
+		gormRepo.NewBattleRepository,
+		fx.As(new(outbound.BattleRepository)),
+	),
+	
+	// RUM and audit days moved to blob storage
+	fx.Annotate(
+		gormRepo.NewArchiveRepository,
+		fx.As(new(outbound.ArchiveRepository)),
+	),
 )
 
 // ServiceModule provides application services
 var ServiceModule = fx.Provide(
-	// AI service
-	func(log *zap.Logger) outbound.AIService {
-		// Use OpenAI client for real AI functionality
-		return openai.NewClient(log)
+	// AI service, with its calls counted for /metrics
+	func(cfg *config.Config, log *zap.Logger) outbound.AIService {
+		if cfg.Sandbox.Enabled {
+			log.Info("Sandbox mode, using the mock AI provider")
+			return metrics.InstrumentAI(mock.NewClient(log), "mock")
+		}
+		switch cfg.AI.Provider {
+		case "mock":
+			return metrics.InstrumentAI(mock.NewClient(log), "mock")
+		case "ollama":
+			return metrics.InstrumentAI(ollama.NewClient(log), "ollama")
+		default:
+			// Use OpenAI client for real AI functionality
+			return metrics.InstrumentAI(openai.NewClient(log), "openai")
+		}
+	},
+	
+	// OCR provider for recipe photo import
+	func(cfg *config.Config, log *zap.Logger) outbound.OCRService {
+		return ocr.NewService(cfg.OCR, log)
+	},
+	
+	// Virus scanner for uploads; nil when scanning is disabled
+	func(cfg *config.Config, log *zap.Logger) (outbound.VirusScanner, error) {
+		return virusscan.NewScanner(cfg.VirusScan, log)
+	},
+	
+	// Image prober for structured data checks
+	imageprobe.NewHTTPProber,
+	
+	// Recipe page reader for import from URL
+	recipeimport.NewPageReader,
+	
+	// Image resizing and re-encoding for /img variants
+	func(cfg *config.Config, log *zap.Logger) outbound.ImageTranscoder {
+		return imagecodec.NewTranscoder(cfg.Images.WebPEncoder, cfg.Images.AVIFEncoder, cfg.Images.MaxPixels, log)
+	},
+	
+	// Emails and announcements a sandbox keeps instead of sending
+	func(cfg *config.Config, log *zap.Logger) *sandboxInfra.Outbox {
+		return sandboxInfra.NewOutbox(cfg.Sandbox.OutboxSize, log)
+	},
+	func(outbox *sandboxInfra.Outbox) outbound.SandboxOutbox {
+		return outbox
+	},
+	
+	// Channels new recipes are announced on
+	func(cfg *config.Config, outbox *sandboxInfra.Outbox, log *zap.Logger) []outbound.RecipePoster {
+		if cfg.Sandbox.Enabled {
+			return sandboxInfra.NewPosters(cfg.Publishing, outbox)
+		}
+		return announce.NewPosters(cfg.Publishing, log)
+	},
+	
+	// Outbound email
+	func(cfg *config.Config, outbox *sandboxInfra.Outbox, log *zap.Logger) outbound.EmailService {
+		if cfg.Sandbox.Enabled {
+			return email.NewCapturingService(cfg.Email, outbox, log)
+		}
+		return email.NewService(cfg.Email, log)
 	},
 	
 	// User service
 	func(
 		userRepo outbound.UserRepository,
 		cache outbound.CacheRepository,
+		invalidations outbound.CacheInvalidationBus,
 		cfg *config.Config,
 		log *zap.Logger,
 	) *user.UserService {
@@ -296,7 +746,7 @@ var ServiceModule = fx.Provide(
 		if jwtSecret == "" {
 			jwtSecret = "demo-secret-key" // Default for demo
 		}
-		return user.NewUserService(userRepo, cache, jwtSecret, log)
+		return user.NewUserService(userRepo, cache, invalidations, jwtSecret, log)
 	},
 	
 	// Recipe service
@@ -305,69 +755,552 @@ var ServiceModule = fx.Provide(
 		fx.As(new(inbound.RecipeService)),
 	),
 	
-	// Auth service (without Redis for now)
-	func(cfg *config.Config, log *zap.Logger) *security.AuthService {
-		return security.NewAuthService(cfg, log, nil)
+	// Comment service
+	func(
+		comments outbound.CommentRepository,
+		feedback outbound.CommentFeedbackRepository,
+		moderation outbound.CommentModerationRepository,
+		tokens outbound.ReplyTokenRepository,
+		suppressions outbound.EmailSuppressionRepository,
+		recipeRepo outbound.RecipeRepository,
+		userRepo outbound.UserRepository,
+		emailService outbound.EmailService,
+		notifications inbound.NotificationService,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.CommentService {
+		return comment.NewService(comments, feedback, moderation, tokens, suppressions, recipeRepo, userRepo, emailService, notifications, comment.Config{
+			ReplyDomain:   cfg.Email.ReplyDomain,
+			ReplySecret:   cfg.Email.ReplySecret,
+			TokenTTL:      cfg.Email.ReplyTokenTTL,
+			SpamThreshold: cfg.Email.SpamThreshold,
+			SiteURL:       cfg.Publishing.SiteURL,
+		}, log)
 	},
-)
-
-// HTTPModule provides HTTP server and handlers
-var HTTPModule = fx.Provide(
-	server.NewServer,
-)
-
-// EventModule provides event handling
-var EventModule = fx.Provide(
-	NewEventDispatcher,
-	NewEventHandlers,
-)
-
-// LifecycleModule provides lifecycle hooks
-var LifecycleModule = fx.Invoke(
-	RegisterLifecycleHooks,
-	InitializeHealthChecks,
-)
-
-// RegisterLifecycleHooks registers application lifecycle hooks
-func RegisterLifecycleHooks(
-	lc fx.Lifecycle,
-	cfg *config.Config,
-	log *zap.Logger,
-	db *gorm.DB,
-	server *server.Server,
-) {
-	lc.Append(fx.Hook{
-		OnStart: func(ctx context.Context) error {
-			log.Info("Starting Alchemorsel application",
-				zap.String("version", cfg.App.Version),
-				zap.String("environment", cfg.App.Environment),
-			)
-			
-			// Start HTTP server
-			go func() {
-				if err := server.Start(); err != nil {
-					log.Fatal("Failed to start HTTP server", zap.Error(err))
-				}
-			}()
-			
-			return nil
-		},
-		OnStop: func(ctx context.Context) error {
-			log.Info("Shutting down Alchemorsel application")
-			
-			// Shutdown HTTP server
-			if err := server.Shutdown(ctx); err != nil {
-				log.Error("Failed to shutdown HTTP server", zap.Error(err))
-			}
-			
-			// Close database connections
-			sqlDB, err := db.DB()
-			if err == nil {
-				if err := sqlDB.Close(); err != nil {
-					log.Error("Failed to close database connection", zap.Error(err))
-				}
-			}
-			
+	
+	// Shopping list service; the hub only reaches streams on this instance.
+	// The same service adds pantry restocks to lists.
+	shoppinglist.NewHub,
+	func(
+		lists outbound.ShoppingListRepository,
+		userRepo outbound.UserRepository,
+		hub *shoppinglist.Hub,
+		syncFeed outbound.SyncFeed,
+		log *zap.Logger,
+	) *shoppinglist.Service {
+		return shoppinglist.NewService(lists, userRepo, hub, syncFeed, log)
+	},
+	func(svc *shoppinglist.Service) inbound.ShoppingListService { return svc },
+	func(svc *shoppinglist.Service) outbound.ShoppingListFeed { return svc },
+	
+	// Pantry inventory, depleted when recipes are marked as cooked
+	func(
+		items outbound.PantryRepository,
+		recipeRepo outbound.RecipeRepository,
+		listFeed outbound.ShoppingListFeed,
+		log *zap.Logger,
+	) inbound.PantryService {
+		return pantry.NewService(items, recipeRepo, listFeed, log)
+	},
+	
+	// Device sync; the same service records the shopping list and draft
+	// pointers other services touch
+	func(records outbound.SyncRepository, cfg *config.Config, log *zap.Logger) *offline.Service {
+		return offline.NewService(records, cfg.Sync.TombstoneRetention, log)
+	},
+	func(svc *offline.Service) inbound.SyncService { return svc },
+	func(svc *offline.Service) outbound.SyncFeed { return svc },
+	
+	// Cache warmup, run on boot and on an admin's request
+	func(
+		recipeService inbound.RecipeService,
+		userRepo outbound.UserRepository,
+		log *zap.Logger,
+	) inbound.CacheWarmupService {
+		return warmup.NewService(warmup.RecipeTasks(recipeService), userRepo, log)
+	},
+	
+	// Blob storage for large generated files such as profiles
+	func(cfg *config.Config, log *zap.Logger) outbound.BlobStorage {
+		return blobstore.NewStorage(cfg.Storage, cfg.AWS, log)
+	},
+	
+	// On-demand profiling for admins
+	func(
+		blobs outbound.BlobStorage,
+		captures outbound.ProfileCaptureRepository,
+		userRepo outbound.UserRepository,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.ProfilingService {
+		return profiling.NewService(runtimeProfiling.NewRuntime(), blobs, captures, userRepo, profiling.Config{
+			Enabled:         cfg.Profiling.Enabled,
+			DefaultDuration: cfg.Profiling.DefaultDuration,
+			MaxDuration:     cfg.Profiling.MaxDuration,
+			BlobPrefix:      cfg.Profiling.BlobPrefix,
+		}, log)
+	},
+	
+	// Virus scanning of uploaded photos and imported files
+	func(
+		scanner outbound.VirusScanner,
+		scans outbound.UploadScanRepository,
+		blobs outbound.BlobStorage,
+		userRepo outbound.UserRepository,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.UploadScanService {
+		return uploadscan.NewService(scanner, scans, blobs, userRepo, uploadscan.Config{
+			FailOpen:         cfg.VirusScan.FailOpen,
+			QuarantinePrefix: cfg.VirusScan.QuarantinePrefix,
+		}, log)
+	},
+	
+	// Author verification badges
+	func(repo outbound.VerificationRepository, userRepo outbound.UserRepository, log *zap.Logger) inbound.VerificationService {
+		return verification.NewService(repo, userRepo, log)
+	},
+	
+	// Browse pages
+	func(summaries outbound.BrowseSummaryRepository, cfg *config.Config, log *zap.Logger) inbound.BrowseService {
+		return browse.NewService(summaries, cfg.Browse.TopPerCuisine, log)
+	},
+	
+	// Trending decay and hidden gems
+	func(repo outbound.RecipePopularityRepository, cfg *config.Config, log *zap.Logger) inbound.PopularityService {
+		return popularity.NewService(repo, popularity.Config{
+			HalfLife:        cfg.Popularity.HalfLife,
+			ViewWeight:      cfg.Popularity.ViewWeight,
+			LikeWeight:      cfg.Popularity.LikeWeight,
+			GemMinRating:    cfg.Popularity.GemMinRating,
+			GemMaxViews:     cfg.Popularity.GemMaxViews,
+			GemMinAge:       cfg.Popularity.GemMinAge,
+			GemsPerRotation: cfg.Popularity.GemsPerRotation,
+			GemRotation:     cfg.Popularity.GemRotation,
+		}, log)
+	},
+	
+	// Recipe vectors for search by meaning
+	func(repo outbound.RecipeEmbeddingRepository, aiService outbound.AIService, cfg *config.Config, log *zap.Logger) inbound.EmbeddingService {
+		return embedding.NewService(repo, aiService, cfg.Embeddings.BatchSize, log)
+	},
+	
+	// "Recipes you might like", precomputed into the cache; a list is kept
+	// for two refreshes so one failed refresh does not empty it. Similar
+	// recipes are cached per recipe as they are asked for.
+	func(signals outbound.RecommendationSignalRepository, cache outbound.CacheRepository, cfg *config.Config, log *zap.Logger) inbound.RecommendationService {
+		return recommendation.NewService(signals, cache, recommendation.Config{
+			Window:     cfg.Recommendations.Window,
+			PerUser:    cfg.Recommendations.PerUser,
+			TTL:        2 * cfg.Recommendations.RefreshInterval,
+			SimilarTTL: cfg.Recommendations.SimilarTTL,
+		}, log)
+	},
+	
+	// Recipe views, each viewer counted once per window, and search
+	// listings, each buffered apart
+	func(
+		counters outbound.RecipeCounterRepository,
+		analytics outbound.RecipeAnalyticsRepository,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.ViewService {
+		return views.NewService(
+			newViewBuffer(cfg, cfg.Views.KeyPrefix),
+			newViewBuffer(cfg, cfg.Views.KeyPrefix+"impressions:"),
+			counters, analytics, views.Config{Window: cfg.Views.DedupeWindow}, log,
+		)
+	},
+	
+	// Related recipes and technique pages
+	func(repo outbound.RecipeGraphRepository, log *zap.Logger) inbound.RecipeGraphService {
+		return graph.NewService(repo, log)
+	},
+	
+	// Technique library and cook mode steps
+	func(
+		repo outbound.TechniqueRepository,
+		userRepo outbound.UserRepository,
+		aiService outbound.AIService,
+		log *zap.Logger,
+	) inbound.TechniqueService {
+		return technique.NewService(repo, userRepo, aiService, log)
+	},
+	
+	// Backward scheduled recipe timelines
+	func(repo outbound.RecipeTimelineRepository) inbound.RecipeTimelineService {
+		return timeline.NewService(repo)
+	},
+	
+	// Food safety checks of recipe steps
+	func(repo outbound.RecipeSafetyRepository) inbound.FoodSafetyService {
+		return foodsafety.NewService(repo)
+	},
+	
+	// Allergen panels worked out from recipe ingredients
+	func(repo outbound.RecipeAllergenRepository) inbound.AllergenService {
+		return allergens.NewService(repo)
+	},
+	
+	// Admin section: users, recipes, totals and AI content review
+	func(
+		repo outbound.AdminRepository,
+		userRepo outbound.UserRepository,
+		recipeService inbound.RecipeService,
+		cache outbound.CacheRepository,
+		invalidations outbound.CacheInvalidationBus,
+		log *zap.Logger,
+	) inbound.AdminService {
+		return admin.NewService(repo, userRepo, recipeService, cache, invalidations, log)
+	},
+	
+	// "Clip this recipe" from the browser extension, limited per user
+	func(
+		recipeService inbound.RecipeService,
+		clips outbound.RecipeClipRepository,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.ClipperService {
+		return clipper.NewService(recipeService, clips, clipper.Config{
+			MaxClips: cfg.Clipper.MaxClips,
+			Window:   cfg.Clipper.Window,
+			SiteURL:  cfg.Publishing.SiteURL,
+		}, log)
+	},
+	
+	// "Import from URL": recipe pages fetched and read on the server
+	func(
+		recipeService inbound.RecipeService,
+		pages outbound.RecipePageReader,
+		log *zap.Logger,
+	) inbound.URLImportService {
+		return urlimport.NewService(recipeService, pages, log)
+	},
+	
+	// Uploaded images, served as resized variants cached on local disk
+	func(
+		images outbound.ImageRepository,
+		blobs outbound.BlobStorage,
+		cache outbound.CacheRepository,
+		transcoder outbound.ImageTranscoder,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.ImageService {
+		return imaging.NewService(images, blobs, blobstore.NewLocal(cfg.Images.CacheDir), cache, transcoder, imaging.Config{
+			Enabled:       cfg.Images.Enabled,
+			Widths:        cfg.Images.Widths,
+			QualityTiers:  cfg.Images.QualityTiers,
+			MaxUploadSize: cfg.Images.MaxUploadSize,
+			MaxPixels:     cfg.Images.MaxPixels,
+			CacheTTL:      cfg.Images.CacheTTL,
+			MaxCachedSize: cfg.Images.MaxCachedSize,
+			MaxConcurrent: cfg.Images.MaxConcurrent,
+		}, log)
+	},
+	
+	// Guest mode: AI chat and a few recipes before signing up
+	func(
+		repo outbound.GuestRepository,
+		aiService outbound.AIService,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.GuestService {
+		return guest.NewService(repo, aiService, guest.Config{
+			Enabled:          cfg.Guest.Enabled,
+			TTL:              cfg.Guest.TTL,
+			MaxRecipes:       cfg.Guest.MaxRecipes,
+			MaxMessages:      cfg.Guest.MaxMessages,
+			MaxSessionsPerIP: cfg.Guest.MaxSessionsPerIP,
+		}, log)
+	},
+	
+	// Followers, following and private profiles
+	func(follows outbound.FollowRepository, userRepo outbound.UserRepository, notifications inbound.NotificationService, log *zap.Logger) inbound.FollowService {
+		return follow.NewService(follows, userRepo, notifications, log)
+	},
+	
+	// In-app notifications; the follow, recipe and comment services send them
+	func(notifications outbound.NotificationRepository, userRepo outbound.UserRepository, log *zap.Logger) inbound.NotificationService {
+		return notification.NewService(notifications, userRepo, log)
+	},
+	
+	// Reader reports of recipes and comments, taken down at a threshold
+	func(
+		reports outbound.ReportRepository,
+		recipes inbound.RecipeService,
+		comments outbound.CommentRepository,
+		userRepo outbound.UserRepository,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.ReportService {
+		return report.NewService(reports, recipes, comments, userRepo, report.Config{
+			Threshold: cfg.Moderation.ReportThreshold,
+		}, log)
+	},
+	
+	// Near copies of other authors' recipes, checked after publishing
+	func(
+		flags outbound.DuplicateRepository,
+		recipeRepo outbound.RecipeRepository,
+		recipes inbound.RecipeService,
+		embeddings outbound.RecipeEmbeddingRepository,
+		aiService outbound.AIService,
+		userRepo outbound.UserRepository,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.DuplicateService {
+		return duplicate.NewService(flags, recipeRepo, recipes, embeddings, aiService, userRepo, duplicate.Config{
+			FlagSimilarity:    cfg.Moderation.DuplicateFlagSimilarity,
+			ArchiveSimilarity: cfg.Moderation.DuplicateArchiveSimilarity,
+		}, log)
+	},
+	
+	// Ingredient substitutes from the knowledge base, filled in by the AI
+	func(
+		repo outbound.IngredientSubstituteRepository,
+		userRepo outbound.UserRepository,
+		aiService outbound.AIService,
+		log *zap.Logger,
+	) inbound.SubstitutionService {
+		return substitution.NewService(repo, userRepo, aiService, log)
+	},
+
+	// Machine translation of recipes with author corrections
+	func(
+		repo outbound.RecipeTranslationRepository,
+		aiService outbound.AIService,
+		log *zap.Logger,
+	) inbound.TranslationService {
+		return translation.NewService(repo, aiService, log)
+	},
+	
+	// Remix battles; results tune the AI's prompt template
+	func(
+		repo outbound.BattleRepository,
+		aiService outbound.AIService,
+		log *zap.Logger,
+	) inbound.BattleService {
+		return battle.NewService(repo, aiService, log)
+	},
+	
+	// Cold storage tiering for old RUM views and audit rows
+	func(
+		archives outbound.ArchiveRepository,
+		blobs outbound.BlobStorage,
+		recipeRepo outbound.RecipeRepository,
+		userRepo outbound.UserRepository,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.ArchiveService {
+		return archive.NewService(archives, blobs, recipeRepo, userRepo, archive.Config{
+			RecipeViewDays: cfg.Archive.RecipeViewDays,
+			AuditDays:      cfg.Archive.AuditDays,
+			MaxDaysPerRun:  cfg.Archive.MaxDaysPerRun,
+			BlobPrefix:     cfg.Archive.BlobPrefix,
+		}, log)
+	},
+	
+	// Effective configuration reference for admins
+	func(cfg *config.Config, userRepo outbound.UserRepository, log *zap.Logger) inbound.ConfigService {
+		return settings.NewService(cfg, userRepo, log)
+	},
+	
+	// Bulk exports for admins and single recipe downloads
+	func(
+		recipes outbound.RecipeExportRepository,
+		recipeRepo outbound.RecipeRepository,
+		userRepo outbound.UserRepository,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.ExportService {
+		return export.NewService(recipes, recipeRepo, userRepo, export.Config{
+			SiteURL: cfg.Publishing.SiteURL,
+		}, log)
+	},
+
+	// Account archives users download and restore
+	func(
+		recipeService inbound.RecipeService,
+		recipeRepo outbound.RecipeRepository,
+		userRepo outbound.UserRepository,
+		collections outbound.CollectionRepository,
+		favorites outbound.FavoriteRepository,
+		cache outbound.CacheRepository,
+		invalidations outbound.CacheInvalidationBus,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.PortabilityService {
+		return portability.NewService(recipeService, recipeRepo, userRepo, collections, favorites, cache, invalidations, portability.Config{
+			SiteURL: cfg.Publishing.SiteURL,
+		}, log)
+	},
+	
+	// Account deletion, erased after the grace period
+	func(
+		deletions outbound.AccountDeletionRepository,
+		eraser outbound.AccountEraser,
+		audit outbound.AccountAuditRepository,
+		userRepo outbound.UserRepository,
+		authService *security.AuthService,
+		cache outbound.CacheRepository,
+		invalidations outbound.CacheInvalidationBus,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.AccountDeletionService {
+		return accountdeletion.NewService(deletions, eraser, audit, userRepo, authService, cache, invalidations, accountdeletion.Config{
+			GracePeriod: cfg.Privacy.DeletionGracePeriod,
+		}, log)
+	},
+	
+	// Forgotten password tokens
+	func(
+		tokens outbound.PasswordResetTokenRepository,
+		userRepo outbound.UserRepository,
+		emailService outbound.EmailService,
+		cache outbound.CacheRepository,
+		invalidations outbound.CacheInvalidationBus,
+		cfg *config.Config,
+		log *zap.Logger,
+	) inbound.PasswordResetService {
+		return passwordreset.NewService(tokens, userRepo, emailService, cache, invalidations, passwordreset.Config{
+			TokenTTL:    cfg.Auth.PasswordReset.TokenTTL,
+			MaxRequests: cfg.Auth.PasswordReset.MaxRequests,
+			Window:      cfg.Auth.PasswordReset.Window,
+		}, log)
+	},
+	
+	// Sandbox banner and captured messages
+	func(outbox outbound.SandboxOutbox, userRepo outbound.UserRepository, cfg *config.Config, log *zap.Logger) inbound.SandboxService {
+		return sandbox.NewService(outbox, userRepo, sandbox.Config{
+			Enabled:   cfg.Sandbox.Enabled,
+			Banner:    cfg.Sandbox.Banner,
+			ResetHour: cfg.Sandbox.ResetHour,
+		}, log)
+	},
+	
+	// Auth service (without Redis for now)
+	func(cfg *config.Config, log *zap.Logger) *security.AuthService {
+		return security.NewAuthService(cfg, log, nil)
+	},
+	
+	// Lease store shared by the replicas
+	func(cfg *config.Config, db *gorm.DB) lease.Store {
+		switch cfg.Lease.Store {
+		case "redis":
+			return lease.NewRedisStore(redis.NewClient(&redis.Options{
+				Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+				Password: cfg.Redis.Password,
+				DB:       cfg.Redis.Database,
+			}), cfg.Lease.KeyPrefix)
+		case "memory":
+			return lease.NewMemoryStore()
+		default:
+			return lease.NewDatabaseStore(db)
+		}
+	},
+	
+	// Cache invalidations shared by the replicas
+	func(cfg *config.Config, log *zap.Logger) *invalidation.Bus {
+		transport := invalidation.NewHub().Transport()
+		if cfg.Invalidation.Transport == "redis" {
+			transport = invalidation.NewRedisTransport(redis.NewClient(&redis.Options{
+				Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+				Password: cfg.Redis.Password,
+				DB:       cfg.Redis.Database,
+			}), cfg.Invalidation.Channel)
+		}
+		metrics := invalidation.NewMetrics("alchemorsel", prometheus.DefaultRegisterer)
+		return invalidation.NewBus(transport, lease.DefaultOwner(), metrics, log)
+	},
+	func(bus *invalidation.Bus) outbound.CacheInvalidationBus {
+		return bus
+	},
+	
+	// Leader election for scheduled jobs
+	func(cfg *config.Config, store lease.Store, log *zap.Logger) *lease.Elector {
+		metrics := lease.NewMetrics("alchemorsel", prometheus.DefaultRegisterer)
+		return lease.NewElector(store, "scheduler", lease.DefaultOwner(), cfg.Lease.TTL, metrics, log)
+	},
+)
+
+// HTTPModule provides HTTP server and handlers
+var HTTPModule = fx.Provide(
+	server.NewServer,
+)
+
+// EventModule provides event handling
+var EventModule = fx.Provide(
+	NewEventDispatcher,
+	NewEventHandlers,
+)
+
+// LifecycleModule provides lifecycle hooks
+var LifecycleModule = fx.Invoke(
+	RegisterLifecycleHooks,
+	RegisterLeaderElection,
+	RegisterPublishingScheduler,
+	RegisterCacheWarmup,
+	RegisterNutritionSeed,
+	RegisterSubstituteSeed,
+	RegisterAllergenBackfill,
+	RegisterLeakWatchdog,
+	RegisterCacheInvalidation,
+	RegisterBrowseRefresh,
+	RegisterPopularityRefresh,
+	RegisterEmbeddingRefresh,
+	RegisterDuplicateCheck,
+	RegisterRecommendationRefresh,
+	RegisterGraphRefresh,
+	RegisterArchiveTiering,
+	RegisterCounterFold,
+	RegisterViewFlush,
+	RegisterSyncPurge,
+	RegisterGuestExpiry,
+	RegisterAccountErasure,
+	RegisterSandboxReset,
+	InitializeHealthChecks,
+)
+
+// RegisterLifecycleHooks registers application lifecycle hooks
+func RegisterLifecycleHooks(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	db *gorm.DB,
+	server *server.Server,
+) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			log.Info("Starting Alchemorsel application",
+				zap.String("version", cfg.App.Version),
+				zap.String("environment", cfg.App.Environment),
+			)
+			
+			// Start HTTP server
+			go func() {
+				if err := server.Start(); err != nil {
+					log.Fatal("Failed to start HTTP server", zap.Error(err))
+				}
+			}()
+			
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Info("Shutting down Alchemorsel application")
+			
+			// Shutdown HTTP server
+			if err := server.Shutdown(ctx); err != nil {
+				log.Error("Failed to shutdown HTTP server", zap.Error(err))
+			}
+			
+			// Close database connections
+			sqlDB, err := db.DB()
+			if err == nil {
+				if err := sqlDB.Close(); err != nil {
+					log.Error("Failed to close database connection", zap.Error(err))
+				}
+			}
+			
 			// Flush logs
 			_ = log.Sync()
 			
@@ -499,11 +1432,40 @@ var PureAPIModule = fx.Options(
 // PureAPIHTTPModule provides HTTP server for pure JSON API
 var PureAPIHTTPModule = fx.Provide(
 	NewPureAPIServer,
+	
+	// Canary splits, starting from the rules in the config file
+	func(cfg *config.Config, log *zap.Logger) *canary.Registry {
+		registry := canary.NewRegistry(canary.NewMetrics("alchemorsel", prometheus.DefaultRegisterer), log)
+		registry.Apply(canaryRules(cfg.Canary))
+		return registry
+	},
 )
 
 // PureAPILifecycleModule provides lifecycle hooks for pure API
 var PureAPILifecycleModule = fx.Invoke(
 	RegisterPureAPILifecycleHooks,
+	RegisterLeaderElection,
+	RegisterPublishingScheduler,
+	RegisterCacheWarmup,
+	RegisterNutritionSeed,
+	RegisterSubstituteSeed,
+	RegisterAllergenBackfill,
+	RegisterLeakWatchdog,
+	RegisterCacheInvalidation,
+	RegisterBrowseRefresh,
+	RegisterPopularityRefresh,
+	RegisterEmbeddingRefresh,
+	RegisterDuplicateCheck,
+	RegisterRecommendationRefresh,
+	RegisterGraphRefresh,
+	RegisterArchiveTiering,
+	RegisterCounterFold,
+	RegisterViewFlush,
+	RegisterSyncPurge,
+	RegisterGuestExpiry,
+	RegisterAccountErasure,
+	RegisterCanaryReload,
+	RegisterSandboxReset,
 	InitializeHealthChecks,
 )
 
@@ -512,19 +1474,89 @@ func NewPureAPIServer(
 	cfg *config.Config,
 	log *zap.Logger,
 	recipeService inbound.RecipeService,
+	commentService inbound.CommentService,
+	shoppingListService inbound.ShoppingListService,
+	warmupService inbound.CacheWarmupService,
+	profilingService inbound.ProfilingService,
+	browseService inbound.BrowseService,
+	popularityService inbound.PopularityService,
+	graphService inbound.RecipeGraphService,
+	techniqueService inbound.TechniqueService,
+	timelineService inbound.RecipeTimelineService,
+	foodSafetyService inbound.FoodSafetyService,
+	translationService inbound.TranslationService,
+	battleService inbound.BattleService,
+	syncService inbound.SyncService,
+	pantryService inbound.PantryService,
+	uploadScanService inbound.UploadScanService,
+	verificationService inbound.VerificationService,
+	archiveService inbound.ArchiveService,
+	configService inbound.ConfigService,
+	exportService inbound.ExportService,
+	sandboxService inbound.SandboxService,
+	passwordResetService inbound.PasswordResetService,
+	allergenService inbound.AllergenService,
+	adminService inbound.AdminService,
+	clipperService inbound.ClipperService,
+	urlImportService inbound.URLImportService,
+	guestService inbound.GuestService,
+	imageService inbound.ImageService,
+	followService inbound.FollowService,
+	notificationService inbound.NotificationService,
+	reportService inbound.ReportService,
+	duplicateService inbound.DuplicateService,
+	substitutionService inbound.SubstitutionService,
+	portabilityService inbound.PortabilityService,
+	accountDeletionService inbound.AccountDeletionService,
 	userService *user.UserService,
 	authService *security.AuthService,
 	aiService outbound.AIService,
 	healthCheck *healthcheck.EnterpriseHealthCheck,
+	canaries *canary.Registry,
 ) *PureAPIServer {
 	return &PureAPIServer{
-		config:        cfg,
-		logger:        log,
-		recipeService: recipeService,
-		userService:   userService,
-		authService:   authService,
-		aiService:     aiService,
-		healthCheck:   healthCheck,
+		config:              cfg,
+		logger:              log,
+		recipeService:       recipeService,
+		commentService:      commentService,
+		shoppingListService: shoppingListService,
+		warmupService:       warmupService,
+		profilingService:    profilingService,
+		browseService:       browseService,
+		popularityService:   popularityService,
+		graphService:        graphService,
+		techniqueService:    techniqueService,
+		timelineService:     timelineService,
+		foodSafetyService:   foodSafetyService,
+		translationService:  translationService,
+		battleService:       battleService,
+		syncService:         syncService,
+		pantryService:       pantryService,
+		uploadScanService:   uploadScanService,
+		verificationService: verificationService,
+		archiveService:      archiveService,
+		configService:       configService,
+		exportService:       exportService,
+		sandboxService:      sandboxService,
+		passwordResetService: passwordResetService,
+		allergenService:     allergenService,
+		adminService:        adminService,
+		clipperService:      clipperService,
+		urlImportService:    urlImportService,
+		guestService:        guestService,
+		imageService:        imageService,
+		followService:       followService,
+		notificationService: notificationService,
+		reportService:       reportService,
+		duplicateService:    duplicateService,
+		substitutionService: substitutionService,
+		portabilityService:  portabilityService,
+		accountDeletionService: accountDeletionService,
+		userService:         userService,
+		authService:         authService,
+		aiService:           aiService,
+		healthCheck:         healthCheck,
+		canaries:            canaries,
 	}
 }
 
@@ -570,39 +1602,1216 @@ func RegisterPureAPILifecycleHooks(
 	})
 }
 
-// parsePort parses a string to int for port, defaults to 3000 if invalid
-func parsePort(portStr string) int {
-	if portStr == "" {
-		return 3000
-	}
-	port := 3000
-	fmt.Sscanf(portStr, "%d", &port)
-	return port
+// RegisterLeaderElection campaigns for the scheduler lease. The first
+// attempt runs before the jobs below start, so a lone replica leads at once.
+func RegisterLeaderElection(lc fx.Lifecycle, elector *lease.Elector) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	lc.Append(fx.Hook{
+		OnStart: func(startCtx context.Context) error {
+			elector.Campaign(startCtx)
+			go func() {
+				defer close(done)
+				elector.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
 }
 
-// PureAPIServer represents a pure JSON API HTTP server (no templates)
-type PureAPIServer struct {
-	config        *config.Config
-	logger        *zap.Logger
-	server        *http.Server
-	recipeService inbound.RecipeService
-	userService   *user.UserService
-	authService   *security.AuthService
-	aiService     outbound.AIService
-	healthCheck   *healthcheck.EnterpriseHealthCheck
+// runLeaderJob runs a scheduled job only on the leader, under the job's own
+// lease. Replicas that are not the leader, or find the job still running
+// elsewhere, skip the run.
+func runLeaderJob(ctx context.Context, elector *lease.Elector, job string, log *zap.Logger, fn func(ctx context.Context) error) error {
+	err := elector.Do(ctx, job, fn)
+	if errors.Is(err, lease.ErrNotLeader) || errors.Is(err, lease.ErrNotAcquired) {
+		log.Debug("Skipping scheduled job on this replica", zap.String("job", job), zap.Error(err))
+		return nil
+	}
+	return err
 }
 
-// Start starts the pure API HTTP server
-func (s *PureAPIServer) Start() error {
-	// Use the existing API server constructor
-	apiServer := apiserver.NewPureAPIServer(
-		s.config,
-		s.logger,
-		s.recipeService,
-		s.userService,
+// RegisterPublishingScheduler publishes scheduled drafts and delivers recipe
+// announcements on the configured interval
+func RegisterPublishingScheduler(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	recipeService inbound.RecipeService,
+	elector *lease.Elector,
+) {
+	interval := cfg.Publishing.SchedulerInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	log = log.Named("publishing-scheduler")
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-stop:
+						return
+					case <-ticker.C:
+						runPublishingScheduler(recipeService, elector, log, interval)
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stop)
+			select {
+			case <-done:
+			case <-ctx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterCacheWarmup warms the query caches once the app has started, so
+// the first requests after a deploy are not slow. The caches live in each
+// process, so every replica warms its own rather than only the leader.
+func RegisterCacheWarmup(lc fx.Lifecycle, warmupService inbound.CacheWarmupService) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			warmupService.Start(inbound.WarmupTriggerBoot)
+			return nil
+		},
+	})
+}
+
+// RegisterNutritionSeed adds the built-in foods missing from the
+// ingredient nutrition table on start. Seeding skips stored names, so
+// every replica can run it.
+func RegisterNutritionSeed(lc fx.Lifecycle, repo outbound.IngredientNutritionRepository, log *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := recipe.SeedIngredientNutrition(ctx, repo, log.Named("nutrition")); err != nil {
+				log.Warn("Failed to seed ingredient nutrition; recipes use the built-in foods", zap.Error(err))
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterSubstituteSeed adds the built-in substitutes missing from the
+// knowledge base on start. Seeding skips stored rows, so every replica can
+// run it.
+func RegisterSubstituteSeed(lc fx.Lifecycle, repo outbound.IngredientSubstituteRepository, log *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := substitution.Seed(ctx, repo, log.Named("substitutes")); err != nil {
+				log.Warn("Failed to seed ingredient substitutes; lookups use the built-in table", zap.Error(err))
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterAllergenBackfill labels the recipes saved before allergen labels
+// were stored, once on start on the leader. It runs in the background, so
+// a large backlog does not hold up the start.
+func RegisterAllergenBackfill(
+	lc fx.Lifecycle,
+	log *zap.Logger,
+	repo outbound.RecipeAllergenRepository,
+	elector *lease.Elector,
+) {
+	log = log.Named("allergen-backfill")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	label := func(ctx context.Context) error {
+		return allergens.LabelMissing(ctx, repo, log)
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				err := runLeaderJob(ctx, elector, "allergen-backfill", log, label)
+				if err != nil && ctx.Err() == nil {
+					log.Error("Allergen backfill failed", zap.Error(err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterLeakWatchdog samples goroutines, heap, shopping list stream
+// buffers and the announcement queue, and alerts on sustained growth
+func RegisterLeakWatchdog(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	hub *shoppinglist.Hub,
+	announcements outbound.AnnouncementRepository,
+	profilingService inbound.ProfilingService,
+) {
+	wcfg := cfg.Monitoring.Watchdog
+	if !wcfg.Enabled {
+		return
+	}
+	
+	gauges := append(watchdog.RuntimeGauges(wcfg),
+		watchdog.Gauge{
+			Name:        "shopping_list_buffered_events",
+			Limit:       float64(wcfg.BufferLimit),
+			ProfileKind: "heap",
+			Read: func(ctx context.Context) (float64, error) {
+				return float64(hub.Buffered()), nil
+			},
+		},
+		watchdog.Gauge{
+			Name:  "announcement_queue",
+			Limit: float64(wcfg.BufferLimit),
+			Read: func(ctx context.Context) (float64, error) {
+				pending, err := announcements.CountPending(ctx)
+				return float64(pending), err
+			},
+		},
+	)
+	dog := watchdog.New(wcfg, gauges, profilingService, log)
+	ctx, cancel := context.WithCancel(context.Background())
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go dog.Run(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// RegisterBrowseRefresh rebuilds the browse summaries at startup and then
+// on every refresh interval
+func RegisterBrowseRefresh(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	browseService inbound.BrowseService,
+	elector *lease.Elector,
+) {
+	interval := cfg.Browse.RefreshInterval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	log = log.Named("browse-refresh")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	refresh := func() {
+		runCtx, stop := context.WithTimeout(ctx, interval)
+		defer stop()
+		err := runLeaderJob(runCtx, elector, "browse-refresh", log, browseService.Refresh)
+		if err != nil && ctx.Err() == nil {
+			log.Error("Browse summary refresh failed", zap.Error(err))
+		}
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				refresh()
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						refresh()
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterGraphRefresh rebuilds the recipe knowledge graph at startup and
+// then on every refresh interval
+func RegisterGraphRefresh(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	graphService inbound.RecipeGraphService,
+	elector *lease.Elector,
+) {
+	interval := cfg.Graph.RefreshInterval
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	log = log.Named("graph-refresh")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	refresh := func() {
+		runCtx, stop := context.WithTimeout(ctx, interval)
+		defer stop()
+		err := runLeaderJob(runCtx, elector, "graph-refresh", log, graphService.Refresh)
+		if err != nil && ctx.Err() == nil {
+			log.Error("Recipe graph refresh failed", zap.Error(err))
+		}
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				refresh()
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						refresh()
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterCacheInvalidation applies the other replicas' invalidations to
+// the in-process cache for as long as the app runs. The bus reconnects
+// after an error; what it misses meanwhile expires with the cache TTLs.
+func RegisterCacheInvalidation(
+	lc fx.Lifecycle,
+	log *zap.Logger,
+	bus *invalidation.Bus,
+	cache outbound.CacheRepository,
+) {
+	log = log.Named("cache-invalidation")
+	bus.Subscribe("", func(key string) {
+		if err := cache.Delete(context.Background(), key); err != nil {
+			log.Warn("Failed to drop invalidated cache entry", zap.String("key", key), zap.Error(err))
+		}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				for {
+					err := bus.Run(ctx)
+					if ctx.Err() != nil {
+						return
+					}
+					log.Error("Cache invalidation listener stopped; reconnecting", zap.Error(err))
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(time.Second):
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterPopularityRefresh decays recipe popularity and rotates the hidden
+// gems at startup and then on every refresh interval
+func RegisterPopularityRefresh(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	popularityService inbound.PopularityService,
+	elector *lease.Elector,
+) {
+	interval := cfg.Popularity.RefreshInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	log = log.Named("popularity-refresh")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	refresh := func() {
+		runCtx, stop := context.WithTimeout(ctx, interval)
+		defer stop()
+		err := runLeaderJob(runCtx, elector, "popularity-refresh", log, popularityService.Refresh)
+		if err != nil && ctx.Err() == nil {
+			log.Error("Recipe popularity refresh failed", zap.Error(err))
+		}
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				refresh()
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						refresh()
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterEmbeddingRefresh embeds new and changed recipes for search by
+// meaning at startup and then on every refresh interval
+func RegisterEmbeddingRefresh(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	embeddingService inbound.EmbeddingService,
+	elector *lease.Elector,
+) {
+	interval := cfg.Embeddings.RefreshInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	log = log.Named("embedding-refresh")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	refresh := func() {
+		runCtx, stop := context.WithTimeout(ctx, interval)
+		defer stop()
+		err := runLeaderJob(runCtx, elector, "embedding-refresh", log, embeddingService.Refresh)
+		if err != nil && ctx.Err() == nil {
+			log.Warn("Recipe embedding refresh failed", zap.Error(err))
+		}
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				refresh()
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						refresh()
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterDuplicateCheck checks newly published recipes for near copies of
+// other authors' recipes on every check interval
+func RegisterDuplicateCheck(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	duplicateService inbound.DuplicateService,
+	elector *lease.Elector,
+) {
+	interval := cfg.Moderation.DuplicateCheckInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	log = log.Named("duplicate-check")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	check := func() {
+		runCtx, stop := context.WithTimeout(ctx, interval)
+		defer stop()
+		err := runLeaderJob(runCtx, elector, "duplicate-check", log, func(ctx context.Context) error {
+			checked, err := duplicateService.CheckQueued(ctx)
+			if checked > 0 {
+				log.Info("Published recipes checked for duplicates", zap.Int("count", checked))
+			}
+			return err
+		})
+		if err != nil && ctx.Err() == nil {
+			log.Warn("Duplicate check failed", zap.Error(err))
+		}
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						check()
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterRecommendationRefresh rebuilds every active user's "Recipes you
+// might like" list at startup and then on every refresh interval
+func RegisterRecommendationRefresh(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	recommendationService inbound.RecommendationService,
+	elector *lease.Elector,
+) {
+	interval := cfg.Recommendations.RefreshInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	log = log.Named("recommendation-refresh")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	refresh := func() {
+		runCtx, stop := context.WithTimeout(ctx, interval)
+		defer stop()
+		err := runLeaderJob(runCtx, elector, "recommendation-refresh", log, recommendationService.Refresh)
+		if err != nil && ctx.Err() == nil {
+			log.Warn("Recommendation refresh failed", zap.Error(err))
+		}
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				refresh()
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						refresh()
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterArchiveTiering moves RUM and audit days past retention to blob
+// storage on every interval. The first run waits one interval so it does
+// not compete with startup.
+func RegisterArchiveTiering(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	archiveService inbound.ArchiveService,
+	elector *lease.Elector,
+) {
+	if !cfg.Archive.Enabled {
+		return
+	}
+	interval := cfg.Archive.Interval
+	if interval <= 0 {
+		interval = 6 * time.Hour
+	}
+	log = log.Named("archive-tiering")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						err := runLeaderJob(ctx, elector, "archive-tiering", log, func(ctx context.Context) error {
+							_, err := archiveService.RunTiering(ctx)
+							return err
+						})
+						if err != nil && ctx.Err() == nil {
+							log.Error("Archive tiering failed", zap.Error(err))
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterSandboxReset wipes the sandbox and seeds it again every night at
+// sandbox.reset_hour UTC, emptying the outbox with it
+func RegisterSandboxReset(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	db *gorm.DB,
+	outbox *sandboxInfra.Outbox,
+	elector *lease.Elector,
+) {
+	if !cfg.Sandbox.Enabled {
+		return
+	}
+	log = log.Named("sandbox-reset")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				for {
+					next := sandbox.NextReset(time.Now(), cfg.Sandbox.ResetHour)
+					timer := time.NewTimer(time.Until(next))
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return
+					case <-timer.C:
+						err := runLeaderJob(ctx, elector, "sandbox-reset", log, func(ctx context.Context) error {
+							return sandboxInfra.Reset(ctx, db)
+						})
+						if err != nil && ctx.Err() == nil {
+							log.Error("Sandbox reset failed", zap.Error(err))
+							continue
+						}
+						// Every replica keeps its own outbox
+						outbox.Clear()
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// canaryRules converts the configured splits into registry rules
+func canaryRules(cfg config.CanaryConfig) map[string]canary.Rule {
+	rules := make(map[string]canary.Rule, len(cfg.Splits))
+	for _, split := range cfg.Splits {
+		rules[split.Name] = canary.Rule{Percent: split.Percent, Cohorts: split.Cohorts}
+	}
+	return rules
+}
+
+// RegisterCanaryReload rereads the config on every reload interval and
+// applies changed canary splits, so a candidate can be rolled back by
+// editing the config file. A config that fails to load keeps the current
+// splits. Every replica reloads on its own.
+func RegisterCanaryReload(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	registry *canary.Registry,
+	p configParams,
+) {
+	interval := cfg.Canary.ReloadInterval
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	log = log.Named("canary-reload")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						fresh, err := config.LoadProfile("", string(p.Profile))
+						if err != nil {
+							log.Warn("Config reload failed, keeping current canary splits", zap.Error(err))
+							continue
+						}
+						registry.Apply(canaryRules(fresh.Canary))
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterCounterFold folds the recipe counter shards into the recipe rows
+// on every interval, draining up to a batch per transaction until no shards
+// are left
+func RegisterCounterFold(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	counters outbound.RecipeCounterRepository,
+	elector *lease.Elector,
+) {
+	interval := cfg.Counters.FoldInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	batch := cfg.Counters.FoldBatch
+	if batch <= 0 {
+		batch = 500
+	}
+	log = log.Named("counter-fold")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	fold := func(ctx context.Context) error {
+		total := 0
+		for {
+			folded, err := counters.Fold(ctx, batch)
+			total += folded
+			if err != nil {
+				return err
+			}
+			if folded < batch {
+				if total > 0 {
+					log.Debug("Recipe counters folded", zap.Int("recipes", total))
+				}
+				return nil
+			}
+		}
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						runCtx, stop := context.WithTimeout(ctx, interval)
+						err := runLeaderJob(runCtx, elector, "counter-fold", log, fold)
+						stop()
+						if err != nil && ctx.Err() == nil {
+							log.Error("Recipe counter fold failed", zap.Error(err))
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// newViewBuffer buffers recipe counts under a key prefix; redis shares the
+// dedupe and the counts across replicas
+func newViewBuffer(cfg *config.Config, prefix string) outbound.ViewBuffer {
+	if cfg.Views.Buffer == "redis" {
+		return viewbuffer.NewRedis(redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.Database,
+		}), prefix)
+	}
+	return viewbuffer.NewMemory()
+}
+
+// RegisterViewFlush writes the buffered recipe views on every interval and
+// once more on shutdown. It runs on every replica, as each may hold views
+// in its own buffer.
+func RegisterViewFlush(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	viewService inbound.ViewService,
+) {
+	interval := cfg.Views.FlushInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	log = log.Named("view-flush")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	flush := func(ctx context.Context) {
+		written, err := viewService.Flush(ctx)
+		if err != nil {
+			log.Error("Recipe view flush failed", zap.Error(err))
+			return
+		}
+		if written > 0 {
+			log.Debug("Recipe views flushed", zap.Int("views", written))
+		}
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						runCtx, stop := context.WithTimeout(ctx, interval)
+						flush(runCtx)
+						stop()
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+				return nil
+			}
+			flush(stopCtx)
+			return nil
+		},
+	})
+}
+
+// RegisterSyncPurge deletes the sync tombstones every device has pulled
+// once they pass the retention, on the leader
+func RegisterSyncPurge(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	syncService inbound.SyncService,
+	elector *lease.Elector,
+) {
+	interval := cfg.Sync.PurgeInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	log = log.Named("sync-purge")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	purge := func(ctx context.Context) error {
+		purged, err := syncService.PurgeTombstones(ctx)
+		if purged > 0 {
+			log.Info("Sync tombstones purged", zap.Int("count", purged))
+		}
+		return err
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						runCtx, stop := context.WithTimeout(ctx, interval)
+						err := runLeaderJob(runCtx, elector, "sync-purge", log, purge)
+						stop()
+						if err != nil && ctx.Err() == nil {
+							log.Error("Sync tombstone purge failed", zap.Error(err))
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterGuestExpiry deletes guest sessions past their expiry with the
+// recipes nobody claimed, on the leader
+func RegisterGuestExpiry(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	guestService inbound.GuestService,
+	elector *lease.Elector,
+) {
+	interval := cfg.Guest.SweepInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	log = log.Named("guest-expiry")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	expire := func(ctx context.Context) error {
+		expired, err := guestService.ExpireGuests(ctx)
+		if expired > 0 {
+			log.Info("Guest sessions expired", zap.Int64("count", expired))
+		}
+		return err
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						runCtx, stop := context.WithTimeout(ctx, interval)
+						err := runLeaderJob(runCtx, elector, "guest-expiry", log, expire)
+						stop()
+						if err != nil && ctx.Err() == nil {
+							log.Error("Guest expiry failed", zap.Error(err))
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// RegisterAccountErasure erases the accounts whose deletion grace period
+// has passed, every privacy.sweep_interval on the leader
+func RegisterAccountErasure(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	log *zap.Logger,
+	deletionService inbound.AccountDeletionService,
+	elector *lease.Elector,
+) {
+	interval := cfg.Privacy.SweepInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	log = log.Named("account-erasure")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	
+	erase := func(ctx context.Context) error {
+		erased, err := deletionService.EraseDueAccounts(ctx)
+		if erased > 0 {
+			log.Info("Accounts erased", zap.Int("count", erased))
+		}
+		return err
+	}
+	
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						runCtx, stop := context.WithTimeout(ctx, interval)
+						err := runLeaderJob(runCtx, elector, "account-erasure", log, erase)
+						stop()
+						if err != nil && ctx.Err() == nil {
+							log.Error("Account erasure failed", zap.Error(err))
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// runPublishingScheduler does one pass on the leader, bounded by the
+// interval so a slow channel cannot stack up runs
+func runPublishingScheduler(recipeService inbound.RecipeService, elector *lease.Elector, log *zap.Logger, interval time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), interval)
+	defer cancel()
+	
+	err := runLeaderJob(ctx, elector, "publishing", log, func(ctx context.Context) error {
+		if published, err := recipeService.PublishDueRecipes(ctx); err != nil {
+			log.Error("Scheduled publishing failed", zap.Error(err))
+		} else if published > 0 {
+			log.Info("Scheduled recipes published", zap.Int("count", published))
+		}
+		
+		if sent, err := recipeService.DeliverAnnouncements(ctx); err != nil {
+			log.Error("Recipe announcements failed", zap.Error(err))
+		} else if sent > 0 {
+			log.Info("Recipe announcements sent", zap.Int("count", sent))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Scheduled publishing did not run", zap.Error(err))
+	}
+}
+
+// parsePort parses a string to int for port, defaults to 3000 if invalid
+func parsePort(portStr string) int {
+	if portStr == "" {
+		return 3000
+	}
+	port := 3000
+	fmt.Sscanf(portStr, "%d", &port)
+	return port
+}
+
+// PureAPIServer represents a pure JSON API HTTP server (no templates)
+type PureAPIServer struct {
+	config              *config.Config
+	logger              *zap.Logger
+	server              *http.Server
+	recipeService       inbound.RecipeService
+	commentService      inbound.CommentService
+	shoppingListService inbound.ShoppingListService
+	warmupService       inbound.CacheWarmupService
+	profilingService    inbound.ProfilingService
+	browseService       inbound.BrowseService
+	popularityService   inbound.PopularityService
+	graphService        inbound.RecipeGraphService
+	techniqueService    inbound.TechniqueService
+	timelineService     inbound.RecipeTimelineService
+	foodSafetyService   inbound.FoodSafetyService
+	translationService  inbound.TranslationService
+	battleService       inbound.BattleService
+	syncService         inbound.SyncService
+	pantryService       inbound.PantryService
+	uploadScanService   inbound.UploadScanService
+	verificationService inbound.VerificationService
+	archiveService      inbound.ArchiveService
+	configService       inbound.ConfigService
+	exportService       inbound.ExportService
+	sandboxService      inbound.SandboxService
+	passwordResetService inbound.PasswordResetService
+	allergenService     inbound.AllergenService
+	adminService        inbound.AdminService
+	clipperService      inbound.ClipperService
+	urlImportService    inbound.URLImportService
+	guestService        inbound.GuestService
+	imageService        inbound.ImageService
+	followService       inbound.FollowService
+	notificationService inbound.NotificationService
+	reportService       inbound.ReportService
+	duplicateService    inbound.DuplicateService
+	substitutionService inbound.SubstitutionService
+	portabilityService  inbound.PortabilityService
+	accountDeletionService inbound.AccountDeletionService
+	userService         *user.UserService
+	authService         *security.AuthService
+	aiService           outbound.AIService
+	healthCheck         *healthcheck.EnterpriseHealthCheck
+	canaries            *canary.Registry
+}
+
+// Start starts the pure API HTTP server
+func (s *PureAPIServer) Start() error {
+	// Use the existing API server constructor
+	apiServer := apiserver.NewPureAPIServer(
+		s.config,
+		s.logger,
+		s.recipeService,
+		s.commentService,
+		s.shoppingListService,
+		s.warmupService,
+		s.profilingService,
+		s.browseService,
+		s.popularityService,
+		s.graphService,
+		s.techniqueService,
+		s.timelineService,
+		s.foodSafetyService,
+		s.translationService,
+		s.battleService,
+		s.syncService,
+		s.pantryService,
+		s.uploadScanService,
+		s.verificationService,
+		s.archiveService,
+		s.configService,
+		s.exportService,
+		s.sandboxService,
+		s.passwordResetService,
+		s.allergenService,
+		s.adminService,
+		s.clipperService,
+		s.urlImportService,
+		s.guestService,
+		s.imageService,
+		s.followService,
+		s.notificationService,
+		s.reportService,
+		s.duplicateService,
+		s.substitutionService,
+		s.portabilityService,
+		s.accountDeletionService,
+		s.userService,
 		s.authService,
 		s.aiService,
 		s.healthCheck,
+		s.canaries,
 	)
 	
 	// Store the server instance for shutdown
@@ -670,6 +2879,10 @@ func InitializeHealthChecks(
 			hc.RegisterDependency(dbDep)
 		}
 		
+		if scanChecker, exists := checkerMap["virus_scanner"]; exists {
+			hc.RegisterDependency(healthcheck.ExternalAPIDependency("virus_scanner", false, nil, scanChecker))
+		}
+		
 		log.Info("Registered health check dependencies")
 	}
 	
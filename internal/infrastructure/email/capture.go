@@ -0,0 +1,56 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// Capturer keeps emails that are not to be delivered, such as those a
+// sandbox sends
+type Capturer interface {
+	CaptureEmail(to, subject, body string)
+}
+
+// NewCapturingService composes emails exactly as NewService does but hands
+// them to capturer instead of a mail server
+func NewCapturingService(cfg config.EmailConfig, capturer Capturer, logger *zap.Logger) outbound.EmailService {
+	return &Service{
+		from:      mail.Address{Name: cfg.FromName, Address: cfg.FromAddress},
+		transport: captureTransport{capturer},
+		logger:    logger.Named("email"),
+	}
+}
+
+// captureTransport reads the composed message back so the capturer sees
+// the decoded subject and body
+type captureTransport struct {
+	capturer Capturer
+}
+
+func (t captureTransport) send(ctx context.Context, from string, to []string, message []byte) error {
+	msg, err := mail.ReadMessage(bytes.NewReader(message))
+	if err != nil {
+		return fmt.Errorf("read captured email: %w", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		return fmt.Errorf("decode captured subject: %w", err)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		return fmt.Errorf("decode captured body: %w", err)
+	}
+
+	t.capturer.CaptureEmail(strings.Join(to, ", "), subject, strings.ReplaceAll(string(body), "\r\n", "\n"))
+	return nil
+}
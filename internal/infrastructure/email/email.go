@@ -0,0 +1,243 @@
+// Package email provides the outbound email adapter for account and
+// notification messages
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// Supported providers
+const (
+	ProviderSMTP = "smtp"
+	ProviderLog  = "log"
+)
+
+// transport hands a finished message to a mail server
+type transport interface {
+	send(ctx context.Context, from string, to []string, message []byte) error
+}
+
+// Service composes plain text emails and sends them through the configured
+// provider
+type Service struct {
+	from      mail.Address
+	transport transport
+	logger    *zap.Logger
+}
+
+// NewService returns the configured email provider. Without an SMTP host
+// messages are only logged, which suits development.
+func NewService(cfg config.EmailConfig, logger *zap.Logger) outbound.EmailService {
+	logger = logger.Named("email")
+	from := mail.Address{Name: cfg.FromName, Address: cfg.FromAddress}
+
+	switch strings.ToLower(cfg.Provider) {
+	case ProviderSMTP, "":
+		if cfg.SMTPHost == "" {
+			logger.Warn("No SMTP host configured, emails will only be logged")
+			return &Service{from: from, transport: logTransport{logger}, logger: logger}
+		}
+		return &Service{from: from, transport: newSMTPTransport(cfg), logger: logger}
+	case ProviderLog:
+		return &Service{from: from, transport: logTransport{logger}, logger: logger}
+	default:
+		logger.Warn("Unknown email provider, emails will only be logged", zap.String("provider", cfg.Provider))
+		return &Service{from: from, transport: logTransport{logger}, logger: logger}
+	}
+}
+
+// SendWelcome greets a newly registered user
+func (s *Service) SendWelcome(ctx context.Context, to string, name string) error {
+	return s.send(ctx, message{
+		to:      to,
+		subject: "Welcome to Alchemorsel",
+		body:    fmt.Sprintf("Hi %s,\n\nWelcome to Alchemorsel! Start by sharing your first recipe.\n", name),
+	})
+}
+
+// SendPasswordReset sends the token that lets a user choose a new password
+func (s *Service) SendPasswordReset(ctx context.Context, to string, token string) error {
+	return s.send(ctx, message{
+		to:      to,
+		subject: "Reset your Alchemorsel password",
+		body: "Someone asked to reset the password for this account.\n\n" +
+			"Your reset code is: " + token + "\n\n" +
+			"If it wasn't you, ignore this email and your password stays the same.\n",
+	})
+}
+
+// SendRecipePublished tells an author their recipe is live
+func (s *Service) SendRecipePublished(ctx context.Context, to string, recipeTitle string) error {
+	return s.send(ctx, message{
+		to:      to,
+		subject: "Your recipe is published",
+		body:    fmt.Sprintf("%q is now live on Alchemorsel.\n", recipeTitle),
+		auto:    true,
+	})
+}
+
+// SendNewFollower tells a user someone followed them
+func (s *Service) SendNewFollower(ctx context.Context, to string, followerName string) error {
+	return s.send(ctx, message{
+		to:      to,
+		subject: followerName + " followed you",
+		body:    followerName + " is now following your recipes on Alchemorsel.\n",
+		auto:    true,
+	})
+}
+
+// SendBulk sends the same message to each recipient separately so addresses
+// are not disclosed to one another. It stops at the first failure.
+func (s *Service) SendBulk(ctx context.Context, recipients []string, subject string, body string) error {
+	for _, to := range recipients {
+		if err := s.send(ctx, message{to: to, subject: subject, body: body, auto: true}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendCommentNotification tells an author about a new comment or review.
+// With a reply-to address the author can answer by replying to the email.
+func (s *Service) SendCommentNotification(ctx context.Context, n outbound.CommentNotificationEmail) error {
+	var subject, intro string
+	if n.Rating > 0 {
+		subject = fmt.Sprintf("%s reviewed %s", n.AuthorName, n.RecipeTitle)
+		intro = fmt.Sprintf("%s rated %q %d out of 5", n.AuthorName, n.RecipeTitle, n.Rating)
+	} else {
+		subject = fmt.Sprintf("%s commented on %s", n.AuthorName, n.RecipeTitle)
+		intro = fmt.Sprintf("%s commented on %q", n.AuthorName, n.RecipeTitle)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n%s", n.Name, intro)
+	if n.Content != "" {
+		body.WriteString(":\n\n")
+		for _, line := range strings.Split(n.Content, "\n") {
+			body.WriteString("    " + line + "\n")
+		}
+	} else {
+		body.WriteString(".\n")
+	}
+	if n.RecipeURL != "" {
+		fmt.Fprintf(&body, "\nSee it on the recipe: %s\n", n.RecipeURL)
+	}
+	if n.ReplyTo != "" {
+		body.WriteString("\nReply to this email to answer; your reply is posted on the recipe under their ")
+		if n.Rating > 0 {
+			body.WriteString("review.\n")
+		} else {
+			body.WriteString("comment.\n")
+		}
+	}
+
+	return s.send(ctx, message{
+		to:      n.To,
+		replyTo: n.ReplyTo,
+		subject: subject,
+		body:    body.String(),
+		auto:    true,
+	})
+}
+
+// message is a plain text email to one recipient
+type message struct {
+	to      string
+	replyTo string
+	subject string
+	body    string
+	// auto marks notifications so auto-responders do not answer them
+	auto bool
+}
+
+func (s *Service) send(ctx context.Context, m message) error {
+	raw, err := s.compose(m, time.Now())
+	if err != nil {
+		return err
+	}
+	to, _ := mail.ParseAddress(m.to)
+	if err := s.transport.send(ctx, s.from.Address, []string{to.Address}, raw); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+	return nil
+}
+
+// compose renders the message in RFC 5322 form. Addresses are parsed and
+// the subject encoded so user-supplied text cannot inject headers.
+func (s *Service) compose(m message, now time.Time) ([]byte, error) {
+	to, err := mail.ParseAddress(m.to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", m.to, err)
+	}
+
+	var buf bytes.Buffer
+	header := func(name, value string) {
+		buf.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", s.from.String())
+	header("To", to.String())
+	if m.replyTo != "" {
+		replyTo, err := mail.ParseAddress(m.replyTo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reply-to %q: %w", m.replyTo, err)
+		}
+		header("Reply-To", replyTo.String())
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", m.subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(s.from.Address))
+	if m.auto {
+		header("Auto-Submitted", "auto-generated")
+	}
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/plain; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	body := quotedprintable.NewWriter(&buf)
+	if _, err := body.Write([]byte(strings.ReplaceAll(m.body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// messageID makes a unique Message-ID on the sender's domain
+func messageID(from string) string {
+	domain := "alchemorsel.local"
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		domain = from[at+1:]
+	}
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+// logTransport writes messages to the log instead of sending them
+type logTransport struct {
+	logger *zap.Logger
+}
+
+func (t logTransport) send(ctx context.Context, from string, to []string, message []byte) error {
+	t.logger.Info("Email not sent, no mail server configured",
+		zap.String("from", from),
+		zap.Strings("to", to),
+		zap.Int("bytes", len(message)),
+	)
+	return nil
+}
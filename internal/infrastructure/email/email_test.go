@@ -0,0 +1,105 @@
+package email
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type recordingTransport struct {
+	to      []string
+	message []byte
+}
+
+func (t *recordingTransport) send(ctx context.Context, from string, to []string, message []byte) error {
+	t.to = to
+	t.message = message
+	return nil
+}
+
+func newTestService() (*Service, *recordingTransport) {
+	transport := &recordingTransport{}
+	return &Service{
+		from:      mail.Address{Name: "Alchemorsel", Address: "noreply@alchemorsel.app"},
+		transport: transport,
+		logger:    zap.NewNop(),
+	}, transport
+}
+
+func TestCommentNotificationCarriesReplyTo(t *testing.T) {
+	svc, transport := newTestService()
+
+	err := svc.SendCommentNotification(context.Background(), outbound.CommentNotificationEmail{
+		To:          "Ada <ada@example.com>",
+		Name:        "Ada",
+		ReplyTo:     "reply+abc@reply.alchemorsel.app",
+		RecipeTitle: "Crème Brûlée",
+		RecipeURL:   "https://alchemorsel.app/recipes/1",
+		AuthorName:  "Grace",
+		Rating:      5,
+		Content:     "Perfect crack on top.",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ada@example.com"}, transport.to)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(transport.message)))
+	require.NoError(t, err)
+	assert.Equal(t, "<reply+abc@reply.alchemorsel.app>", msg.Header.Get("Reply-To"))
+	assert.Equal(t, "auto-generated", msg.Header.Get("Auto-Submitted"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Grace reviewed Crème Brûlée", subject)
+	_, err = time.Parse(time.RFC1123Z, msg.Header.Get("Date"))
+	assert.NoError(t, err)
+
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "Grace rated \"Crème Brûlée\" 5 out of 5:")
+	assert.Contains(t, string(body), "    Perfect crack on top.")
+	assert.Contains(t, string(body), "Reply to this email to answer")
+}
+
+func TestComposeResistsHeaderInjection(t *testing.T) {
+	svc, transport := newTestService()
+
+	err := svc.SendNewFollower(context.Background(), "ada@example.com", "Mallory\r\nBcc: victim@example.com")
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(transport.message)))
+	require.NoError(t, err)
+	assert.Empty(t, msg.Header.Get("Bcc"))
+
+	err = svc.SendWelcome(context.Background(), "ada@example.com\r\nBcc: victim@example.com", "Ada")
+	assert.Error(t, err)
+}
+
+type capturedEmail struct{ to, subject, body string }
+
+type recordingCapturer []capturedEmail
+
+func (c *recordingCapturer) CaptureEmail(to, subject, body string) {
+	*c = append(*c, capturedEmail{to, subject, body})
+}
+
+func TestCapturingServiceDecodesWhatItKeeps(t *testing.T) {
+	var captured recordingCapturer
+	svc := NewCapturingService(config.EmailConfig{FromName: "Alchemorsel", FromAddress: "noreply@alchemorsel.app"}, &captured, zap.NewNop())
+
+	require.NoError(t, svc.SendNewFollower(context.Background(), "sam@example.com", "Zoë"))
+
+	require.Len(t, captured, 1)
+	assert.Equal(t, "sam@example.com", captured[0].to)
+	assert.Equal(t, "Zoë followed you", captured[0].subject)
+	assert.Equal(t, "Zoë is now following your recipes on Alchemorsel.\n", captured[0].body)
+}
@@ -0,0 +1,91 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+)
+
+// smtpDialTimeout bounds connecting to the mail server
+const smtpDialTimeout = 10 * time.Second
+
+// smtpTransport delivers through an SMTP relay, upgrading with STARTTLS
+type smtpTransport struct {
+	host      string
+	addr      string
+	username  string
+	password  string
+	enableTLS bool
+}
+
+func newSMTPTransport(cfg config.EmailConfig) *smtpTransport {
+	port := cfg.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+	return &smtpTransport{
+		host:      cfg.SMTPHost,
+		addr:      net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(port)),
+		username:  cfg.SMTPUsername,
+		password:  cfg.SMTPPassword,
+		enableTLS: cfg.EnableTLS,
+	}
+}
+
+func (t *smtpTransport) send(ctx context.Context, from string, to []string, message []byte) error {
+	dialer := net.Dialer{Timeout: smtpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, t.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if t.enableTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not support STARTTLS", t.host)
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: t.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if t.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", t.username, t.password, t.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,409 @@
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/handlers"
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/infrastructure/observability/metrics"
+	"github.com/go-chi/chi/v5"
+)
+
+// access is the authentication a route requires. The route table states it
+// for every route and mountRoutes turns it into middleware, so a route
+// cannot be added without deciding who may call it.
+type access int
+
+const (
+	// accessPublic routes serve anyone
+	accessPublic access = iota
+	// accessOptional routes serve anyone and identify signed-in users
+	accessOptional
+	// accessUser routes need a valid access token or a signed service call
+	// made for a user
+	accessUser
+	// accessAdmin routes need a signed-in user with the admin role; the
+	// service behind them checks the role on the account as well
+	accessAdmin
+	// accessInternal routes serve other Alchemorsel services, or users
+	// with a valid access token
+	accessInternal
+	// accessSigned routes verify a provider's signature in the handler,
+	// such as mail webhooks
+	accessSigned
+)
+
+func (a access) String() string {
+	switch a {
+	case accessPublic:
+		return "public"
+	case accessOptional:
+		return "optional"
+	case accessUser:
+		return "user"
+	case accessAdmin:
+		return "admin"
+	case accessInternal:
+		return "internal"
+	case accessSigned:
+		return "signed"
+	}
+	return "unknown"
+}
+
+// requiresCredentials reports whether the access level turns away
+// anonymous callers before the handler runs
+func (a access) requiresCredentials() bool {
+	return a == accessUser || a == accessAdmin || a == accessInternal
+}
+
+// route is one entry of a route table
+type route struct {
+	method  string
+	pattern string
+	access  access
+	handler http.HandlerFunc
+	// openWrite says why a state-changing route is open to callers
+	// without credentials. The policy test requires it on every such
+	// route.
+	openWrite string
+}
+
+// mountRoutes registers a route table, wrapping each handler in the
+// middleware its access level calls for
+func (s *PureAPIServer) mountRoutes(r chi.Router, routes []route) {
+	for _, rt := range routes {
+		var h http.Handler = rt.handler
+		switch rt.access {
+		case accessOptional:
+			h = middleware.OptionalAuthenticateAPI(s.authService)(h)
+		case accessUser:
+			h = middleware.AuthenticateAPI(s.authService)(h)
+		case accessAdmin:
+			h = middleware.AuthenticateAdmin(s.authService)(h)
+		case accessInternal:
+			h = middleware.Internal(s.authService)(h)
+		}
+		r.Method(rt.method, rt.pattern, h)
+	}
+}
+
+// rootRoutes are the probes, metrics, API documentation and image variants
+// outside /api/v1
+func (s *PureAPIServer) rootRoutes() []route {
+	imageH := handlers.NewImageAPIHandlers(s.imageService, s.uploadScanService, s.config.Images.MaxUploadSize, s.logger)
+
+	routes := []route{
+		{method: http.MethodGet, pattern: "/health", access: accessPublic, handler: s.handleHealthCheck},
+		{method: http.MethodGet, pattern: "/ready", access: accessPublic, handler: s.handleReadinessCheck},
+		{method: http.MethodGet, pattern: "/live", access: accessPublic, handler: s.handleLivenessCheck},
+
+		// OpenAPI documentation
+		{method: http.MethodGet, pattern: "/api/v1/openapi.yaml", access: accessPublic, handler: s.openAPIHandler.ServeOpenAPISpec},
+		{method: http.MethodGet, pattern: "/api/v1/openapi.json", access: accessPublic, handler: s.openAPIHandler.ServeOpenAPIJSON},
+		{method: http.MethodGet, pattern: "/api/v1/docs", access: accessPublic, handler: s.openAPIHandler.ServeSwaggerUI},
+		{method: http.MethodGet, pattern: "/api/v1/docs/swagger", access: accessPublic, handler: s.openAPIHandler.ServeSwaggerUI},
+		{method: http.MethodGet, pattern: "/api/v1/docs/redoc", access: accessPublic, handler: s.openAPIHandler.ServeRedocUI},
+
+		// Resized variants of uploaded images, for srcset URLs
+		{method: http.MethodGet, pattern: "/img/{id}", access: accessPublic, handler: imageH.ServeImage},
+	}
+	if s.config.Monitoring.EnableMetrics {
+		routes = append(routes, route{method: http.MethodGet, pattern: metrics.Path, access: accessPublic, handler: metrics.Handler().ServeHTTP})
+	}
+	return routes
+}
+
+// apiV1Routes is the route table of /api/v1
+func (s *PureAPIServer) apiV1Routes() []route {
+	h := handlers.NewAPIHandlers(s.recipeService, s.uploadScanService, s.verificationService, s.logger)
+	authH := handlers.NewAuthAPIHandlers(s.userService, s.authService, s.logger)
+	aiH := handlers.NewAIAPIHandlers(s.aiService, s.notificationService, s.userService, s.logger)
+	batchH := handlers.NewBatchAPIHandlers(s.recipeService, s.userService, s.logger)
+	undoH := handlers.NewUndoAPIHandlers(s.recipeService, s.undoService, s.logger)
+	commentH := handlers.NewCommentAPIHandlers(s.recipeService, s.commentService, s.config.Email.InboundSecret, s.logger)
+	listH := handlers.NewShoppingListAPIHandlers(s.shoppingListService, s.logger)
+	warmH := handlers.NewCacheAPIHandlers(s.warmupService, s.logger)
+	profH := handlers.NewProfilingAPIHandlers(s.profilingService, s.logger)
+	browseH := handlers.NewBrowseAPIHandlers(s.browseService, s.popularityService, s.logger)
+	graphH := handlers.NewGraphAPIHandlers(s.graphService, s.logger)
+	techniqueH := handlers.NewTechniqueAPIHandlers(s.techniqueService, s.logger)
+	timelineH := handlers.NewTimelineAPIHandlers(s.timelineService, s.logger)
+	safetyH := handlers.NewFoodSafetyAPIHandlers(s.recipeService, s.foodSafetyService, s.logger)
+	allergenH := handlers.NewAllergenAPIHandlers(s.allergenService, s.logger)
+	translationH := handlers.NewTranslationAPIHandlers(s.translationService, s.logger)
+	battleH := handlers.NewBattleAPIHandlers(s.battleService, s.logger)
+	syncH := handlers.NewSyncAPIHandlers(s.syncService, s.logger)
+	pantryH := handlers.NewPantryAPIHandlers(s.pantryService, s.logger)
+	scanH := handlers.NewUploadScanAPIHandlers(s.uploadScanService, s.logger)
+	verifyH := handlers.NewVerificationAPIHandlers(s.verificationService, s.logger)
+	archiveH := handlers.NewArchiveAPIHandlers(s.archiveService, s.logger)
+	configH := handlers.NewConfigAPIHandlers(s.configService, s.logger)
+	exportH := handlers.NewExportAPIHandlers(s.exportService, s.logger)
+	sandboxH := handlers.NewSandboxAPIHandlers(s.sandboxService, s.logger)
+	resetH := handlers.NewPasswordResetAPIHandlers(s.passwordResetService, s.logger)
+	adminH := handlers.NewAdminAPIHandlers(s.adminService, s.logger)
+	clipperH := handlers.NewClipperAPIHandlers(s.clipperService, s.config.Clipper.MaxPageSize, s.logger)
+	urlImportH := handlers.NewURLImportAPIHandlers(s.urlImportService, s.logger)
+	guestH := handlers.NewGuestAPIHandlers(s.guestService, s.logger)
+	followH := handlers.NewFollowAPIHandlers(s.followService, s.logger)
+	notifyH := handlers.NewNotificationAPIHandlers(s.notificationService, s.logger)
+	reportH := handlers.NewReportAPIHandlers(s.reportService, s.logger)
+	duplicateH := handlers.NewDuplicateAPIHandlers(s.duplicateService, s.logger)
+	substituteH := handlers.NewSubstitutionAPIHandlers(s.substitutionService, s.logger)
+	portabilityH := handlers.NewPortabilityAPIHandlers(s.portabilityService, s.uploadScanService, s.logger)
+	deletionH := handlers.NewAccountDeletionAPIHandlers(s.accountDeletionService, s.logger)
+	imageH := handlers.NewImageAPIHandlers(s.imageService, s.uploadScanService, s.config.Images.MaxUploadSize, s.logger)
+
+	const (
+		get    = http.MethodGet
+		post   = http.MethodPost
+		put    = http.MethodPut
+		delete = http.MethodDelete
+	)
+	return []route{
+		// Authentication
+		{method: post, pattern: "/auth/register", access: accessPublic, handler: authH.Register, openWrite: "creates the caller's account"},
+		{method: post, pattern: "/auth/login", access: accessPublic, handler: authH.Login, openWrite: "exchanges credentials for tokens"},
+		{method: post, pattern: "/auth/logout", access: accessPublic, handler: authH.Logout, openWrite: "revokes the token it is given"},
+		{method: post, pattern: "/auth/refresh", access: accessPublic, handler: authH.RefreshToken, openWrite: "exchanges a refresh token for new tokens"},
+		{method: post, pattern: "/auth/forgot-password", access: accessPublic, handler: resetH.ForgotPassword, openWrite: "mails a reset token, rate limited per address"},
+		{method: post, pattern: "/auth/reset-password", access: accessPublic, handler: resetH.ResetPassword, openWrite: "the mailed single-use token is the credential"},
+		{method: get, pattern: "/auth/profile", access: accessUser, handler: authH.GetProfile},
+		{method: put, pattern: "/auth/profile", access: accessUser, handler: authH.UpdateProfile},
+		{method: put, pattern: "/auth/profile/theme", access: accessUser, handler: authH.UpdateTheme},
+		{method: put, pattern: "/auth/profile/units", access: accessUser, handler: authH.UpdateUnits},
+		{method: get, pattern: "/auth/profile/diet", access: accessUser, handler: authH.GetDietaryProfile},
+		{method: put, pattern: "/auth/profile/diet", access: accessUser, handler: authH.UpdateDietaryProfile},
+		{method: put, pattern: "/auth/profile/personalization", access: accessUser, handler: authH.UpdatePersonalization},
+		{method: put, pattern: "/auth/profile/privacy", access: accessUser, handler: authH.UpdatePrivacy},
+
+		// The caller's data, packed to take elsewhere and restored from
+		// such an archive
+		{method: get, pattern: "/me/export", access: accessUser, handler: portabilityH.ExportAccount},
+		{method: post, pattern: "/me/import", access: accessUser, handler: portabilityH.ImportAccount},
+
+		// Erasing the caller's account after a grace period they can
+		// cancel in
+		{method: delete, pattern: "/me", access: accessUser, handler: deletionH.DeleteAccount},
+		{method: get, pattern: "/me/deletion", access: accessUser, handler: deletionH.GetDeletionStatus},
+		{method: delete, pattern: "/me/deletion", access: accessUser, handler: deletionH.CancelDeletion},
+
+		// Batch reads used by the web frontend to avoid N+1 fetches
+		{method: get, pattern: "/recipes:batchGet", access: accessInternal, handler: batchH.BatchGetRecipes},
+		{method: get, pattern: "/users:batchGet", access: accessInternal, handler: batchH.BatchGetUsers},
+
+		// Browse pages, served from summaries refreshed in the background
+		{method: get, pattern: "/browse/tags", access: accessPublic, handler: browseH.ListTags},
+		{method: get, pattern: "/browse/cuisines", access: accessPublic, handler: browseH.ListCuisines},
+		{method: get, pattern: "/browse/cuisines/{cuisine}/top-rated", access: accessPublic, handler: browseH.TopRatedByCuisine},
+		{method: get, pattern: "/browse/hidden-gems", access: accessPublic, handler: browseH.HiddenGems},
+
+		// Other recipes using an ingredient, technique or cuisine
+		{method: get, pattern: "/graph/{kind}/{key}/recipes", access: accessPublic, handler: graphH.NodeRecipes},
+
+		// Technique library; admins curate it
+		{method: get, pattern: "/techniques", access: accessPublic, handler: techniqueH.ListTechniques},
+		{method: get, pattern: "/techniques/{slug}", access: accessPublic, handler: techniqueH.GetTechnique},
+		{method: put, pattern: "/techniques/{slug}", access: accessAdmin, handler: techniqueH.SaveTechnique},
+		{method: delete, pattern: "/techniques/{slug}", access: accessAdmin, handler: techniqueH.DeleteTechnique},
+
+		// Recipes anyone may read
+		{method: get, pattern: "/recipes", access: accessOptional, handler: s.split("recipe-search", h.ListRecipes, h.RelevanceListRecipes).ServeHTTP},
+		{method: get, pattern: "/recipes/trending", access: accessPublic, handler: h.TrendingRecipes},
+		{method: get, pattern: "/recipes/facets", access: accessPublic, handler: h.SearchFacets},
+		{method: get, pattern: "/recipes/{id}", access: accessPublic, handler: h.GetRecipe},
+		{method: get, pattern: "/recipes/{id}/comments", access: accessOptional, handler: commentH.ListComments},
+		{method: get, pattern: "/recipes/{id}/reviews", access: accessOptional, handler: commentH.ListReviews},
+		{method: get, pattern: "/recipes/{id}/discussion", access: accessOptional, handler: commentH.Discussion},
+		{method: get, pattern: "/recipes/{id}/graph", access: accessPublic, handler: graphH.RecipeNodes},
+		{method: get, pattern: "/recipes/{id}/related", access: accessPublic, handler: graphH.RelatedRecipes},
+		{method: get, pattern: "/recipes/{id}/similar", access: accessPublic, handler: h.SimilarRecipes},
+		{method: get, pattern: "/recipes/{id}/steps", access: accessOptional, handler: techniqueH.RecipeSteps},
+		{method: get, pattern: "/recipes/{id}/timeline", access: accessOptional, handler: timelineH.RecipeTimeline},
+		{method: get, pattern: "/recipes/{id}/timeline.ics", access: accessOptional, handler: timelineH.RecipeTimelineCalendar},
+		{method: get, pattern: "/recipes/{id}/food-safety", access: accessOptional, handler: safetyH.RecipeFoodSafety},
+		{method: get, pattern: "/recipes/{id}/allergens", access: accessOptional, handler: allergenH.RecipeAllergens},
+		{method: get, pattern: "/recipes/{id}/scaled", access: accessOptional, handler: h.ScaleRecipe},
+		{method: get, pattern: "/recipes/{id}/export", access: accessOptional, handler: exportH.ExportRecipe},
+		{method: get, pattern: "/ingredients/{name}/substitutes", access: accessOptional, handler: substituteH.IngredientSubstitutes},
+		{method: get, pattern: "/recipes/{id}/translations", access: accessOptional, handler: translationH.RecipeLanguages},
+		{method: get, pattern: "/recipes/{id}/translations/{lang}", access: accessOptional, handler: translationH.RecipeTranslation},
+		{method: get, pattern: "/recipes/{id}/changes", access: accessOptional, handler: h.RecipeChanges},
+		{method: post, pattern: "/recipes/{id}/what-if", access: accessOptional, handler: h.RecipeWhatIf, openWrite: "computes a scaled copy without saving it"},
+		// View beacons from the recipe page; anonymous visits count too
+		{method: post, pattern: "/recipes/{id}/views", access: accessOptional, handler: h.RecordRecipeView, openWrite: "anonymous visits count as views"},
+		{method: post, pattern: "/recipes/{id}/views/{viewID}/engagement", access: accessPublic, handler: h.RecordRecipeEngagement, openWrite: "updates only the view the page was given an ID for"},
+
+		// Recipes for signed-in users
+		{method: post, pattern: "/recipes", access: accessUser, handler: h.CreateRecipe},
+		{method: post, pattern: "/recipes/import", access: accessUser, handler: urlImportH.ImportRecipeURL},
+		{method: post, pattern: "/recipes/import/photo", access: accessUser, handler: h.ImportRecipePhoto},
+		{method: post, pattern: "/recipes/import/library", access: accessUser, handler: h.ImportRecipeLibrary},
+		{method: post, pattern: "/recipes/clip", access: accessUser, handler: clipperH.ClipRecipe},
+		{method: get, pattern: "/recipes/structured-data", access: accessUser, handler: h.StructuredDataReport},
+		{method: get, pattern: "/recipes/analytics", access: accessUser, handler: h.AuthorAnalytics},
+		{method: get, pattern: "/recipes/recently-viewed", access: accessUser, handler: h.RecentlyViewedRecipes},
+		{method: get, pattern: "/recipes/recommended", access: accessUser, handler: h.RecommendedRecipes},
+		{method: get, pattern: "/recipes/chef-activity", access: accessUser, handler: h.ChefActivity},
+		{method: get, pattern: "/recipes/{id}/structured-data", access: accessUser, handler: h.RecipeStructuredData},
+		{method: get, pattern: "/recipes/{id}/kitchen-ticket", access: accessUser, handler: h.KitchenTicket},
+		{method: get, pattern: "/recipes/{id}/cooked/preview", access: accessUser, handler: pantryH.PreviewCook},
+		{method: post, pattern: "/recipes/{id}/cooked", access: accessUser, handler: pantryH.CompleteCook},
+		{method: get, pattern: "/recipes/{id}/analytics", access: accessUser, handler: h.RecipeAnalytics},
+		{method: get, pattern: "/recipes/{id}/analytics/history", access: accessUser, handler: archiveH.RecipeViewHistory},
+		{method: put, pattern: "/recipes/{id}", access: accessUser, handler: h.UpdateRecipe},
+		{method: delete, pattern: "/recipes/{id}", access: accessUser, handler: undoH.DeleteRecipe},
+		{method: post, pattern: "/recipes/{id}/publish", access: accessUser, handler: safetyH.PublishRecipe},
+		{method: put, pattern: "/recipes/{id}/schedule", access: accessUser, handler: safetyH.SchedulePublish},
+		{method: delete, pattern: "/recipes/{id}/schedule", access: accessUser, handler: h.CancelScheduledPublish},
+		{method: post, pattern: "/recipes/{id}/unpublish", access: accessUser, handler: undoH.UnpublishRecipe},
+		{method: post, pattern: "/recipes/{id}/like", access: accessUser, handler: h.LikeRecipe},
+		{method: post, pattern: "/recipes/{id}/fork", access: accessUser, handler: h.ForkRecipe},
+		{method: post, pattern: "/recipes/{id}/favorite", access: accessUser, handler: h.FavoriteRecipe},
+		{method: delete, pattern: "/recipes/{id}/favorite", access: accessUser, handler: h.UnfavoriteRecipe},
+		{method: post, pattern: "/recipes/{id}/rating", access: accessUser, handler: commentH.RateRecipe},
+		{method: post, pattern: "/recipes/{id}/comments", access: accessUser, handler: commentH.AddComment},
+		{method: put, pattern: "/recipes/{id}/comments/{commentID}", access: accessUser, handler: commentH.EditComment},
+		{method: delete, pattern: "/recipes/{id}/comments/{commentID}", access: accessUser, handler: commentH.DeleteComment},
+		{method: post, pattern: "/recipes/{id}/comments/{commentID}/flag", access: accessUser, handler: commentH.FlagComment},
+		{method: post, pattern: "/recipes/{id}/report", access: accessUser, handler: reportH.ReportRecipe},
+		{method: post, pattern: "/comments/{id}/report", access: accessUser, handler: reportH.ReportComment},
+		{method: post, pattern: "/recipes/{id}/duplicate-appeal", access: accessUser, handler: duplicateH.AppealFlag},
+		{method: post, pattern: "/recipes/{id}/comments/{commentID}/reactions", access: accessUser, handler: commentH.React},
+		{method: delete, pattern: "/recipes/{id}/comments/{commentID}/reactions/{kind}", access: accessUser, handler: commentH.Unreact},
+		{method: post, pattern: "/recipes/{id}/comments/{commentID}/hide", access: accessUser, handler: commentH.HideComment},
+		{method: put, pattern: "/recipes/{id}/discussion/lock", access: accessUser, handler: commentH.LockDiscussion},
+		{method: delete, pattern: "/recipes/{id}/discussion/lock", access: accessUser, handler: commentH.UnlockDiscussion},
+		{method: put, pattern: "/recipes/{id}/reviews/{userID}/vote", access: accessUser, handler: commentH.VoteReview},
+		{method: delete, pattern: "/recipes/{id}/reviews/{userID}/vote", access: accessUser, handler: commentH.ClearReviewVote},
+		{method: put, pattern: "/recipes/{id}/steps/{step}/techniques", access: accessUser, handler: techniqueH.LinkStepTechniques},
+		{method: post, pattern: "/recipes/{id}/steps/suggest-techniques", access: accessUser, handler: techniqueH.SuggestStepTechniques},
+		{method: post, pattern: "/recipes/{id}/translations/{lang}", access: accessUser, handler: translationH.TranslateRecipe},
+		{method: put, pattern: "/recipes/{id}/translations/{lang}/fields/{field}", access: accessUser, handler: translationH.EditTranslationField},
+
+		// Remix battles: the AI and a chef cook to the same prompt and
+		// readers vote blind
+		{method: get, pattern: "/battles", access: accessOptional, handler: battleH.ListBattles},
+		{method: get, pattern: "/battles/templates", access: accessPublic, handler: battleH.BattleTemplates},
+		{method: get, pattern: "/battles/{id}", access: accessOptional, handler: battleH.GetBattle},
+		{method: post, pattern: "/battles", access: accessUser, handler: battleH.StartBattle},
+		{method: put, pattern: "/battles/{id}/chef-entry", access: accessUser, handler: battleH.SubmitChefEntry},
+		{method: post, pattern: "/battles/{id}/votes", access: accessUser, handler: battleH.VoteBattle},
+
+		// Device sync: bookmarks, notes and pointers to shopping lists and
+		// drafts, pushed and pulled by devices that work offline
+		{method: post, pattern: "/sync/push", access: accessUser, handler: syncH.Push},
+		{method: get, pattern: "/sync/pull", access: accessUser, handler: syncH.Pull},
+
+		// Undo tokens returned by destructive actions
+		{method: post, pattern: "/undo/{token}", access: accessUser, handler: undoH.Undo},
+
+		// Blocks recipe authors keep against commenters, and the audit log
+		// of their moderation
+		{method: get, pattern: "/comment-moderation/blocks", access: accessUser, handler: commentH.ListBlockedCommenters},
+		{method: put, pattern: "/comment-moderation/blocks/{userID}", access: accessUser, handler: commentH.BlockCommenter},
+		{method: delete, pattern: "/comment-moderation/blocks/{userID}", access: accessUser, handler: commentH.UnblockCommenter},
+		{method: get, pattern: "/comment-moderation/events", access: accessUser, handler: commentH.ModerationLog},
+
+		// Mail provider webhooks
+		{method: post, pattern: "/email/inbound", access: accessSigned, handler: commentH.InboundEmail, openWrite: "the handler checks the provider's signature"},
+		{method: post, pattern: "/email/events", access: accessSigned, handler: commentH.EmailEvents, openWrite: "the handler checks the provider's signature"},
+
+		// Shared shopping lists; edits stream live over /{id}/events
+		{method: post, pattern: "/shopping-lists", access: accessUser, handler: listH.CreateList},
+		{method: get, pattern: "/shopping-lists", access: accessUser, handler: listH.ListLists},
+		{method: get, pattern: "/shopping-lists/{id}", access: accessUser, handler: listH.GetList},
+		{method: post, pattern: "/shopping-lists/{id}/members", access: accessUser, handler: listH.ShareList},
+		{method: post, pattern: "/shopping-lists/{id}/ops", access: accessUser, handler: listH.ApplyOps},
+		{method: get, pattern: "/shopping-lists/{id}/events", access: accessUser, handler: listH.Events},
+
+		// Pantry inventory, depleted when recipes are marked as cooked
+		{method: get, pattern: "/pantry/items", access: accessUser, handler: pantryH.ListItems},
+		{method: post, pattern: "/pantry/items", access: accessUser, handler: pantryH.CreateItem},
+		{method: put, pattern: "/pantry/items/{id}", access: accessUser, handler: pantryH.UpdateItem},
+		{method: delete, pattern: "/pantry/items/{id}", access: accessUser, handler: pantryH.DeleteItem},
+		{method: get, pattern: "/pantry/consumption", access: accessUser, handler: pantryH.Consumption},
+
+		// AI
+		{method: post, pattern: "/ai/generate-recipe", access: accessUser, handler: aiH.GenerateRecipe},
+		{method: post, pattern: "/ai/suggest-ingredients", access: accessUser, handler: aiH.SuggestIngredients},
+		{method: post, pattern: "/ai/analyze-nutrition", access: accessUser, handler: aiH.AnalyzeNutrition},
+
+		// Admin tools
+		{method: get, pattern: "/analytics/zero-result-searches", access: accessAdmin, handler: h.ZeroResultSearches},
+		{method: get, pattern: "/admin/cache/warm", access: accessAdmin, handler: warmH.WarmupReport},
+		{method: post, pattern: "/admin/cache/warm", access: accessAdmin, handler: warmH.WarmCaches},
+		{method: post, pattern: "/admin/archive/run", access: accessAdmin, handler: archiveH.RunTiering},
+		{method: get, pattern: "/admin/archive/audit", access: accessAdmin, handler: archiveH.AuditHistory},
+		{method: get, pattern: "/admin/upload-scans", access: accessAdmin, handler: scanH.ListScans},
+		{method: get, pattern: "/admin/verification/claims", access: accessAdmin, handler: verifyH.ListClaims},
+		{method: post, pattern: "/admin/verification/claims/{id}/review", access: accessAdmin, handler: verifyH.ReviewClaim},
+		{method: get, pattern: "/admin/verification/reports", access: accessAdmin, handler: verifyH.ListReports},
+		{method: post, pattern: "/admin/verification/reports/{id}/resolve", access: accessAdmin, handler: verifyH.ResolveReport},
+		{method: get, pattern: "/admin/comments/hidden", access: accessAdmin, handler: commentH.ListHiddenComments},
+		{method: post, pattern: "/admin/comments/{commentID}/review", access: accessAdmin, handler: commentH.ReviewHiddenComment},
+		{method: get, pattern: "/admin/comments/flagged", access: accessAdmin, handler: commentH.ListFlaggedComments},
+		{method: post, pattern: "/admin/comments/{commentID}/flags/review", access: accessAdmin, handler: commentH.ReviewFlaggedComment},
+		{method: get, pattern: "/admin/reports", access: accessAdmin, handler: reportH.ListReports},
+		{method: post, pattern: "/admin/reports/{id}/resolve", access: accessAdmin, handler: reportH.ResolveReport},
+		{method: post, pattern: "/admin/reports/{id}/dismiss", access: accessAdmin, handler: reportH.DismissReport},
+		{method: get, pattern: "/admin/duplicates", access: accessAdmin, handler: duplicateH.ListFlags},
+		{method: post, pattern: "/admin/duplicates/{id}/resolve", access: accessAdmin, handler: duplicateH.ResolveFlag},
+		{method: post, pattern: "/admin/duplicates/{id}/appeal", access: accessAdmin, handler: duplicateH.DecideAppeal},
+		{method: get, pattern: "/admin/exports/recipes", access: accessAdmin, handler: exportH.ExportRecipes},
+		{method: get, pattern: "/admin/config", access: accessAdmin, handler: configH.EffectiveConfig},
+		{method: get, pattern: "/admin/sandbox/outbox", access: accessAdmin, handler: sandboxH.Outbox},
+		{method: post, pattern: "/admin/profiles", access: accessAdmin, handler: profH.StartCapture},
+		{method: get, pattern: "/admin/profiles", access: accessAdmin, handler: profH.ListCaptures},
+		{method: get, pattern: "/admin/profiles/{id}", access: accessAdmin, handler: profH.GetCapture},
+		{method: get, pattern: "/admin/profiles/{id}/download", access: accessAdmin, handler: profH.DownloadCapture},
+		{method: get, pattern: "/admin/users", access: accessAdmin, handler: adminH.ListUsers},
+		{method: post, pattern: "/admin/users/{id}/suspend", access: accessAdmin, handler: adminH.SuspendUser},
+		{method: post, pattern: "/admin/users/{id}/reinstate", access: accessAdmin, handler: adminH.ReinstateUser},
+		{method: post, pattern: "/admin/recipes/{id}/unpublish", access: accessAdmin, handler: adminH.UnpublishRecipe},
+		{method: get, pattern: "/admin/stats", access: accessAdmin, handler: adminH.SystemStats},
+		{method: get, pattern: "/admin/ai-content", access: accessAdmin, handler: adminH.ListAIContent},
+
+		// Users. Verified badges are public so profiles and cards can show
+		// them; private profiles are trimmed for the callers they hide from.
+		{method: get, pattern: "/users/{id}/verification", access: accessPublic, handler: verifyH.AuthorBadge},
+		{method: get, pattern: "/users/{id}/profile", access: accessOptional, handler: followH.GetProfile},
+		{method: get, pattern: "/users/{id}/followers", access: accessOptional, handler: followH.Followers},
+		{method: get, pattern: "/users/{id}/following", access: accessOptional, handler: followH.Following},
+		{method: post, pattern: "/users/{id}/follow", access: accessUser, handler: followH.Follow},
+		{method: delete, pattern: "/users/{id}/follow", access: accessUser, handler: followH.Unfollow},
+		{method: get, pattern: "/users/{id}/recipes", access: accessUser, handler: h.GetUserRecipes},
+		{method: get, pattern: "/users/{id}/favorites", access: accessUser, handler: h.GetUserFavorites},
+		{method: post, pattern: "/users/{id}/verification/reports", access: accessUser, handler: verifyH.ReportAuthor},
+
+		// In-app notifications; the web nav polls the unread count
+		{method: get, pattern: "/notifications", access: accessUser, handler: notifyH.List},
+		{method: get, pattern: "/notifications/unread-count", access: accessUser, handler: notifyH.UnreadCount},
+		{method: post, pattern: "/notifications/read", access: accessUser, handler: notifyH.MarkRead},
+		{method: post, pattern: "/notifications/read-all", access: accessUser, handler: notifyH.MarkAllRead},
+		{method: get, pattern: "/notifications/preferences", access: accessUser, handler: notifyH.GetPreferences},
+		{method: put, pattern: "/notifications/preferences", access: accessUser, handler: notifyH.UpdatePreferences},
+
+		// Guest mode: the X-Guest-Token header stands in for an account
+		// until the guest signs up and claims their recipes
+		{method: post, pattern: "/guest/sessions", access: accessPublic, handler: guestH.StartSession, openWrite: "starts a time-boxed guest session, limited per client address"},
+		{method: post, pattern: "/guest/chat", access: accessPublic, handler: guestH.Chat, openWrite: "the guest token is the credential; messages are capped per session"},
+		{method: get, pattern: "/guest/recipes", access: accessPublic, handler: guestH.ListRecipes},
+		{method: post, pattern: "/guest/recipes", access: accessPublic, handler: guestH.SaveRecipe, openWrite: "the guest token is the credential; recipes are capped per session"},
+		{method: delete, pattern: "/guest/recipes/{id}", access: accessPublic, handler: guestH.DeleteRecipe, openWrite: "the guest token is the credential"},
+		{method: post, pattern: "/guest/claim", access: accessUser, handler: guestH.Claim},
+
+		// Image uploads; variants are served from /img/{id}
+		{method: post, pattern: "/images", access: accessUser, handler: imageH.UploadImage},
+
+		// Verification claims: chefs and brands ask for a verified badge
+		{method: post, pattern: "/verification/claims", access: accessUser, handler: verifyH.SubmitClaim},
+		{method: get, pattern: "/verification/claims/mine", access: accessUser, handler: verifyH.MyClaim},
+
+		// Sandbox banner for clients to show
+		{method: get, pattern: "/sandbox", access: accessPublic, handler: sandboxH.Status},
+
+		{method: get, pattern: "/health", access: accessPublic, handler: h.HealthCheck},
+	}
+}
@@ -0,0 +1,126 @@
+package apiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/infrastructure/security"
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newRouteTestServer(t *testing.T) *PureAPIServer {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Auth.JWTSecret = "route-test-secret"
+	cfg.Auth.JWTExpiration = time.Hour
+	log := zap.NewNop()
+	// Nothing listens on the port: token tracking and revocation checks
+	// fail and are logged, which leaves tokens valid
+	tokens := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { tokens.Close() })
+	return NewPureAPIServer(cfg, log,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, security.NewAuthService(cfg, log, tokens), nil, nil, nil)
+}
+
+// tableRoutes lists every route of the server's tables as "METHOD /path"
+func tableRoutes(s *PureAPIServer) map[string]route {
+	routes := map[string]route{}
+	for _, rt := range s.rootRoutes() {
+		routes[rt.method+" "+rt.pattern] = rt
+	}
+	for _, rt := range s.apiV1Routes() {
+		routes[rt.method+" /api/v1"+rt.pattern] = rt
+	}
+	return routes
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func TestRouterServesOnlyTableRoutes(t *testing.T) {
+	s := newRouteTestServer(t)
+	table := tableRoutes(s)
+
+	mounted := map[string]bool{}
+	require.NoError(t, chi.Walk(s.router, func(method, pattern string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if strings.HasPrefix(pattern, "/debug/") {
+			return nil
+		}
+		key := method + " " + strings.TrimSuffix(pattern, "/")
+		mounted[key] = true
+		assert.Contains(t, table, key, "route registered outside the route table")
+		return nil
+	}))
+	for key := range table {
+		assert.True(t, mounted[key], "%s is in the route table but not mounted", key)
+	}
+}
+
+func TestOpenMutatingRoutesAreJustified(t *testing.T) {
+	for key, rt := range tableRoutes(newRouteTestServer(t)) {
+		if !isMutating(rt.method) || rt.access.requiresCredentials() {
+			continue
+		}
+		assert.NotEmpty(t, rt.openWrite, "%s is %s and changes state without saying why", key, rt.access)
+	}
+}
+
+var routeParam = regexp.MustCompile(`\{[^}]+\}`)
+
+func TestProtectedRoutesRejectAnonymousCallers(t *testing.T) {
+	s := newRouteTestServer(t)
+	for key, rt := range tableRoutes(s) {
+		if !rt.access.requiresCredentials() {
+			continue
+		}
+		path := routeParam.ReplaceAllString(strings.SplitN(key, " ", 2)[1], "x")
+		for _, p := range []string{path, path + "/"} {
+			req := httptest.NewRequest(rt.method, p, strings.NewReader("{}"))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			s.router.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, "%s %s is %s but served an anonymous caller", rt.method, p, rt.access)
+		}
+	}
+}
+
+func TestAdminRoutesRejectSignedInNonAdmins(t *testing.T) {
+	s := newRouteTestServer(t)
+	tokens := map[string][]string{
+		"no roles":  nil,
+		"user role": {"user"},
+		"chef role": {"chef"},
+	}
+	for name, roles := range tokens {
+		token, err := s.authService.GenerateAccessToken("u-1", "ada@example.com", roles, "session-1", "", "")
+		require.NoError(t, err)
+
+		for key, rt := range tableRoutes(s) {
+			if rt.access != accessAdmin {
+				continue
+			}
+			path := routeParam.ReplaceAllString(strings.SplitN(key, " ", 2)[1], "x")
+			req := httptest.NewRequest(rt.method, path, strings.NewReader("{}"))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			s.router.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusForbidden, rec.Code, "%s served a token with %s", key, name)
+		}
+	}
+}
@@ -5,22 +5,31 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/alchemorsel/v3/internal/application/undo"
 	"github.com/alchemorsel/v3/internal/application/user"
 	"github.com/alchemorsel/v3/internal/infrastructure/config"
 	"github.com/alchemorsel/v3/internal/infrastructure/http/handlers"
 	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/infrastructure/observability/metrics"
 	"github.com/alchemorsel/v3/internal/infrastructure/security"
 	"github.com/alchemorsel/v3/internal/ports/inbound"
 	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/canary"
 	"github.com/alchemorsel/v3/pkg/healthcheck"
+	"github.com/alchemorsel/v3/pkg/shadow"
+	"github.com/alchemorsel/v3/pkg/svcauth"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
 )
 
+// exportPathPrefix is where the streamed bulk exports live
+const exportPathPrefix = "/api/v1/admin/exports/"
+
 // PureAPIServer represents a pure JSON API HTTP server (no frontend templates)
 type PureAPIServer struct {
 	config        *config.Config
@@ -28,11 +37,48 @@ type PureAPIServer struct {
 	server        *http.Server
 	router        *chi.Mux
 	recipeService inbound.RecipeService
+	commentService inbound.CommentService
+	shoppingListService inbound.ShoppingListService
+	warmupService inbound.CacheWarmupService
+	profilingService inbound.ProfilingService
+	browseService inbound.BrowseService
+	popularityService inbound.PopularityService
+	graphService  inbound.RecipeGraphService
+	techniqueService inbound.TechniqueService
+	timelineService inbound.RecipeTimelineService
+	foodSafetyService inbound.FoodSafetyService
+	translationService inbound.TranslationService
+	battleService inbound.BattleService
+	syncService   inbound.SyncService
+	pantryService inbound.PantryService
+	uploadScanService inbound.UploadScanService
+	verificationService inbound.VerificationService
+	archiveService inbound.ArchiveService
+	configService inbound.ConfigService
+	exportService inbound.ExportService
+	sandboxService inbound.SandboxService
+	passwordResetService inbound.PasswordResetService
+	allergenService inbound.AllergenService
+	adminService inbound.AdminService
+	clipperService inbound.ClipperService
+	urlImportService inbound.URLImportService
+	guestService inbound.GuestService
+	imageService inbound.ImageService
+	followService inbound.FollowService
+	notificationService inbound.NotificationService
+	reportService inbound.ReportService
+	duplicateService inbound.DuplicateService
+	substitutionService inbound.SubstitutionService
+	portabilityService inbound.PortabilityService
+	accountDeletionService inbound.AccountDeletionService
 	userService   *user.UserService
 	authService   *security.AuthService
 	aiService     outbound.AIService
 	healthCheck   *healthcheck.EnterpriseHealthCheck
 	openAPIHandler *OpenAPIHandler
+	undoService   *undo.Service
+	canaries      *canary.Registry
+	mirror        *shadow.Mirror
 }
 
 // NewPureAPIServer creates a new pure API server instance
@@ -40,20 +86,92 @@ func NewPureAPIServer(
 	cfg *config.Config,
 	log *zap.Logger,
 	recipeService inbound.RecipeService,
+	commentService inbound.CommentService,
+	shoppingListService inbound.ShoppingListService,
+	warmupService inbound.CacheWarmupService,
+	profilingService inbound.ProfilingService,
+	browseService inbound.BrowseService,
+	popularityService inbound.PopularityService,
+	graphService inbound.RecipeGraphService,
+	techniqueService inbound.TechniqueService,
+	timelineService inbound.RecipeTimelineService,
+	foodSafetyService inbound.FoodSafetyService,
+	translationService inbound.TranslationService,
+	battleService inbound.BattleService,
+	syncService inbound.SyncService,
+	pantryService inbound.PantryService,
+	uploadScanService inbound.UploadScanService,
+	verificationService inbound.VerificationService,
+	archiveService inbound.ArchiveService,
+	configService inbound.ConfigService,
+	exportService inbound.ExportService,
+	sandboxService inbound.SandboxService,
+	passwordResetService inbound.PasswordResetService,
+	allergenService inbound.AllergenService,
+	adminService inbound.AdminService,
+	clipperService inbound.ClipperService,
+	urlImportService inbound.URLImportService,
+	guestService inbound.GuestService,
+	imageService inbound.ImageService,
+	followService inbound.FollowService,
+	notificationService inbound.NotificationService,
+	reportService inbound.ReportService,
+	duplicateService inbound.DuplicateService,
+	substitutionService inbound.SubstitutionService,
+	portabilityService inbound.PortabilityService,
+	accountDeletionService inbound.AccountDeletionService,
 	userService *user.UserService,
 	authService *security.AuthService,
 	aiService outbound.AIService,
 	healthCheck *healthcheck.EnterpriseHealthCheck,
+	canaries *canary.Registry,
 ) *PureAPIServer {
 	server := &PureAPIServer{
 		config:        cfg,
 		logger:        log,
 		recipeService: recipeService,
+		commentService: commentService,
+		shoppingListService: shoppingListService,
+		warmupService: warmupService,
+		profilingService: profilingService,
+		browseService: browseService,
+		popularityService: popularityService,
+		graphService:  graphService,
+		techniqueService: techniqueService,
+		timelineService: timelineService,
+		foodSafetyService: foodSafetyService,
+		translationService: translationService,
+		battleService: battleService,
+		syncService:   syncService,
+		pantryService: pantryService,
+		uploadScanService: uploadScanService,
+		verificationService: verificationService,
+		archiveService: archiveService,
+		configService: configService,
+		exportService: exportService,
+		sandboxService: sandboxService,
+		passwordResetService: passwordResetService,
+		allergenService: allergenService,
+		adminService: adminService,
+		clipperService: clipperService,
+		urlImportService: urlImportService,
+		guestService: guestService,
+		imageService: imageService,
+		followService: followService,
+		notificationService: notificationService,
+		reportService: reportService,
+		duplicateService: duplicateService,
+		substitutionService: substitutionService,
+		portabilityService: portabilityService,
+		accountDeletionService: accountDeletionService,
 		userService:   userService,
 		authService:   authService,
 		aiService:     aiService,
 		healthCheck:   healthCheck,
 		openAPIHandler: NewOpenAPIHandler(log),
+		undoService:   undo.NewService(undo.DefaultTTL, log),
+		canaries:      canaries,
+		mirror:        middleware.NewShadowMirror(cfg.Shadow, nil, log),
 	}
 
 	server.router = server.setupRoutes()
@@ -75,91 +193,71 @@ func (s *PureAPIServer) setupRoutes() *chi.Mux {
 	// Global middleware for API
 	r.Use(chimiddleware.RequestID)
 	r.Use(chimiddleware.RealIP)
+	r.Use(metrics.Middleware)
 	r.Use(middleware.Logger(s.logger))
 	r.Use(chimiddleware.Recoverer)
 	r.Use(middleware.Security())
 	r.Use(middleware.CORS())
 	
-	// API-specific middleware
-	r.Use(chimiddleware.Timeout(30 * time.Second))
-	r.Use(chimiddleware.Compress(5))
+	// Calls signed by cmd/web carry the signed-in user instead of a token
+	if svc := s.config.Auth.Service; svc.Enabled {
+		r.Use(middleware.ServiceAuth(svcauth.NewVerifier(svc.MaxSkew, svc.Secret, svc.PreviousSecret), s.logger))
+	}
+	
+	// API-specific middleware. Exports stream for as long as the client
+	// keeps reading, so they skip the timeout and compression.
+	r.Use(middleware.ExceptPaths(chimiddleware.Timeout(30*time.Second), exportPathPrefix))
+	r.Use(middleware.ExceptPaths(chimiddleware.Compress(5), exportPathPrefix))
 	r.Use(middleware.JSONOnly()) // Force JSON responses only
+	
+	// Compare a sample of anonymous reads with the shadow target
+	if s.mirror != nil {
+		r.Use(s.mirror.Middleware)
+	}
 
-	// Health check endpoints
-	r.Get("/health", s.handleHealthCheck)
-	r.Get("/ready", s.handleReadinessCheck)
-	r.Get("/live", s.handleLivenessCheck)
+	// Probes and API documentation
+	s.mountRoutes(r, s.rootRoutes())
 
-	// OpenAPI Documentation endpoints
-	r.Get("/api/v1/openapi.yaml", s.openAPIHandler.ServeOpenAPISpec)
-	r.Get("/api/v1/openapi.json", s.openAPIHandler.ServeOpenAPIJSON)
-	r.Get("/api/v1/docs", s.openAPIHandler.ServeSwaggerUI)
-	r.Get("/api/v1/docs/swagger", s.openAPIHandler.ServeSwaggerUI)
-	r.Get("/api/v1/docs/redoc", s.openAPIHandler.ServeRedocUI)
+	// Raw pprof for admins; every request is audited and sampled
+	// profiles share the capture duration cap
+	if s.config.Profiling.Enabled {
+		profH := handlers.NewProfilingAPIHandlers(s.profilingService, s.logger)
+		r.Route("/debug", func(r chi.Router) {
+			r.Use(middleware.AuthenticateAPI(s.authService))
+			r.Use(profH.DebugGuard)
+			r.Mount("/", chimiddleware.Profiler())
+		})
+	}
 
 	// API v1 routes
 	r.Route("/api/v1", func(r chi.Router) {
-		s.setupAPIV1Routes(r)
+		// Routes are registered without a trailing slash
+		r.Use(chimiddleware.StripSlashes)
+		s.mountRoutes(r, s.apiV1Routes())
 	})
 
 	return r
 }
 
-// setupAPIV1Routes configures API v1 endpoints
-func (s *PureAPIServer) setupAPIV1Routes(r chi.Router) {
-	h := handlers.NewAPIHandlers(s.recipeService, s.logger)
-	authH := handlers.NewAuthAPIHandlers(s.userService, s.authService, s.logger)
-	aiH := handlers.NewAIAPIHandlers(s.aiService, s.logger)
-
-	// Authentication routes
-	r.Route("/auth", func(r chi.Router) {
-		r.Post("/register", authH.Register)
-		r.Post("/login", authH.Login) 
-		r.Post("/logout", authH.Logout)
-		r.Post("/refresh", authH.RefreshToken)
-		
-		// Protected auth routes
-		r.Group(func(r chi.Router) {
-			r.Use(middleware.AuthenticateAPI(s.authService))
-			r.Get("/profile", authH.GetProfile)
-			r.Put("/profile", authH.UpdateProfile)
-		})
-	})
-
-	// Recipe routes
-	r.Route("/recipes", func(r chi.Router) {
-		// Public routes
-		r.Get("/", h.ListRecipes)
-		r.Get("/{id}", h.GetRecipe)
-		
-		// Protected routes
-		r.Group(func(r chi.Router) {
-			r.Use(middleware.AuthenticateAPI(s.authService))
-			r.Post("/", h.CreateRecipe)
-			r.Put("/{id}", h.UpdateRecipe)
-			r.Delete("/{id}", h.DeleteRecipe)
-			r.Post("/{id}/like", h.LikeRecipe)
-			r.Post("/{id}/rating", h.RateRecipe)
-		})
-	})
-
-	// AI routes
-	r.Route("/ai", func(r chi.Router) {
-		r.Use(middleware.AuthenticateAPI(s.authService))
-		r.Post("/generate-recipe", aiH.GenerateRecipe)
-		r.Post("/suggest-ingredients", aiH.SuggestIngredients)
-		r.Post("/analyze-nutrition", aiH.AnalyzeNutrition)
-	})
-
-	// User routes  
-	r.Route("/users", func(r chi.Router) {
-		r.Use(middleware.AuthenticateAPI(s.authService))
-		r.Get("/{id}/recipes", h.GetUserRecipes)
-		r.Get("/{id}/favorites", h.GetUserFavorites)
-	})
+// split serves a route from stable, or from candidate for the share of
+// users the named canary split sends there
+func (s *PureAPIServer) split(name string, stable, candidate http.HandlerFunc) http.Handler {
+	if s.canaries == nil {
+		return stable
+	}
+	return s.canaries.Split(name, stable, candidate, canarySubject)
+}
 
-	// Health check
-	r.Get("/health", h.HealthCheck)
+// canarySubject keeps signed-in users on one variant by user ID and
+// anonymous visitors by address
+func canarySubject(r *http.Request) string {
+	if userID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		return userID
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
 }
 
 // Start starts the pure API HTTP server
@@ -180,7 +278,11 @@ func (s *PureAPIServer) Server() *http.Server {
 // Shutdown gracefully shuts down the pure API server
 func (s *PureAPIServer) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down Pure API server...")
-	return s.server.Shutdown(ctx)
+	err := s.server.Shutdown(ctx)
+	if s.mirror != nil {
+		s.mirror.Stop(ctx)
+	}
+	return err
 }
 
 // handleHealthCheck provides enterprise health check endpoint
@@ -238,11 +340,26 @@ func (s *PureAPIServer) handleReadinessCheck(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	
+	// Hold traffic until the boot warmup finishes. A failed warmup only
+	// means cold caches, so it does not keep the instance out of rotation.
+	warmup := s.warmupService.Report()
+	if warmup.Status == inbound.WarmupRunning && warmup.Trigger == inbound.WarmupTriggerBoot {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":       "not_ready",
+			"reason":       "Warming caches",
+			"cache_warmup": warmup,
+		})
+		return
+	}
+	
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"status":    "ready",
-		"timestamp": time.Now(),
+		"status":       "ready",
+		"timestamp":    time.Now(),
+		"cache_warmup": warmup,
 	})
 }
 
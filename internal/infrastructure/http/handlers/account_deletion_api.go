@@ -0,0 +1,93 @@
+// Package handlers provides the account deletion endpoints
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"go.uber.org/zap"
+)
+
+// AccountDeletionAPIHandlers lets users have their account erased
+type AccountDeletionAPIHandlers struct {
+	responder
+	deletions inbound.AccountDeletionService
+}
+
+// NewAccountDeletionAPIHandlers creates the account deletion handlers
+func NewAccountDeletionAPIHandlers(deletions inbound.AccountDeletionService, logger *zap.Logger) *AccountDeletionAPIHandlers {
+	return &AccountDeletionAPIHandlers{
+		deletions: deletions,
+		responder: responder{logger: logger},
+	}
+}
+
+// deleteAccountRequest confirms the deletion with the account's password
+type deleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// DeleteAccount handles DELETE /api/v1/me
+// The account is erased after the grace period unless the user cancels.
+// Every session is signed out straight away.
+func (h *AccountDeletionAPIHandlers) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req deleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	status, err := h.deletions.RequestDeletion(r.Context(), inbound.RequestAccountDeletionCommand{
+		UserID:   userID,
+		Password: req.Password,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusAccepted, APIResponse{
+		Success: true,
+		Data:    status,
+		Message: "Your account will be deleted on " + status.ScheduledFor.UTC().Format("2 January 2006") + ". Sign in before then to cancel.",
+	})
+}
+
+// GetDeletionStatus handles GET /api/v1/me/deletion
+func (h *AccountDeletionAPIHandlers) GetDeletionStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	status, err := h.deletions.GetDeletionStatus(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: status})
+}
+
+// CancelDeletion handles DELETE /api/v1/me/deletion
+func (h *AccountDeletionAPIHandlers) CancelDeletion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	status, err := h.deletions.CancelDeletion(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    status,
+		Message: "Your account will not be deleted",
+	})
+}
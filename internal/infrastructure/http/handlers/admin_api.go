@@ -0,0 +1,238 @@
+// Package handlers provides the admin management API
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxAdminReasonBytes bounds the optional reason sent with an admin action
+const maxAdminReasonBytes = 4 << 10
+
+// AdminActionRequest carries the optional reason recorded with an admin
+// action
+type AdminActionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// AdminAPIHandlers serves /api/v1/admin. The service checks the admin role.
+type AdminAPIHandlers struct {
+	responder
+	admin inbound.AdminService
+}
+
+// NewAdminAPIHandlers creates the admin handlers
+func NewAdminAPIHandlers(admin inbound.AdminService, logger *zap.Logger) *AdminAPIHandlers {
+	return &AdminAPIHandlers{
+		admin:     admin,
+		responder: responder{logger: logger},
+	}
+}
+
+// ListUsers handles GET /api/v1/admin/users?q=&status=&role=&page=&limit=
+func (h *AdminAPIHandlers) ListUsers(w http.ResponseWriter, r *http.Request) {
+	requesterID, ok := h.requesterID(w, r)
+	if !ok {
+		return
+	}
+	limit, page, ok := h.pageParams(w, r)
+	if !ok {
+		return
+	}
+
+	users, err := h.admin.ListUsers(r.Context(), requesterID, inbound.AdminUserQuery{
+		Search: r.URL.Query().Get("q"),
+		Status: r.URL.Query().Get("status"),
+		Role:   r.URL.Query().Get("role"),
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    users,
+		Message: "Users retrieved successfully",
+	})
+}
+
+// SuspendUser handles POST /api/v1/admin/users/{id}/suspend
+func (h *AdminAPIHandlers) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := h.userCommand(w, r)
+	if !ok {
+		return
+	}
+
+	account, err := h.admin.SuspendUser(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    account,
+		Message: "User suspended",
+	})
+}
+
+// ReinstateUser handles POST /api/v1/admin/users/{id}/reinstate
+func (h *AdminAPIHandlers) ReinstateUser(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := h.userCommand(w, r)
+	if !ok {
+		return
+	}
+
+	account, err := h.admin.ReinstateUser(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    account,
+		Message: "User reinstated",
+	})
+}
+
+// UnpublishRecipe handles POST /api/v1/admin/recipes/{id}/unpublish
+func (h *AdminAPIHandlers) UnpublishRecipe(w http.ResponseWriter, r *http.Request) {
+	requesterID, ok := h.requesterID(w, r)
+	if !ok {
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+	req, ok := h.actionRequest(w, r)
+	if !ok {
+		return
+	}
+
+	err = h.admin.UnpublishRecipe(r.Context(), inbound.AdminRecipeCommand{
+		RequesterID: requesterID,
+		RecipeID:    recipeID,
+		Reason:      req.Reason,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Recipe unpublished",
+	})
+}
+
+// SystemStats handles GET /api/v1/admin/stats
+func (h *AdminAPIHandlers) SystemStats(w http.ResponseWriter, r *http.Request) {
+	requesterID, ok := h.requesterID(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.admin.SystemStats(r.Context(), requesterID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    stats,
+		Message: "System stats retrieved successfully",
+	})
+}
+
+// ListAIContent handles GET /api/v1/admin/ai-content?page=&limit=
+func (h *AdminAPIHandlers) ListAIContent(w http.ResponseWriter, r *http.Request) {
+	requesterID, ok := h.requesterID(w, r)
+	if !ok {
+		return
+	}
+	limit, page, ok := h.pageParams(w, r)
+	if !ok {
+		return
+	}
+
+	content, err := h.admin.ListAIContent(r.Context(), requesterID, limit, (page-1)*limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    content,
+		Message: "AI content retrieved successfully",
+	})
+}
+
+func (h *AdminAPIHandlers) userCommand(w http.ResponseWriter, r *http.Request) (inbound.AdminUserCommand, bool) {
+	requesterID, ok := h.requesterID(w, r)
+	if !ok {
+		return inbound.AdminUserCommand{}, false
+	}
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid user ID")
+		return inbound.AdminUserCommand{}, false
+	}
+	req, ok := h.actionRequest(w, r)
+	if !ok {
+		return inbound.AdminUserCommand{}, false
+	}
+	return inbound.AdminUserCommand{RequesterID: requesterID, UserID: userID, Reason: req.Reason}, true
+}
+
+func (h *AdminAPIHandlers) actionRequest(w http.ResponseWriter, r *http.Request) (AdminActionRequest, bool) {
+	var req AdminActionRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdminReasonBytes)).Decode(&req)
+	if err != nil && err != io.EOF {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return req, false
+	}
+	return req, true
+}
+
+func (h *AdminAPIHandlers) pageParams(w http.ResponseWriter, r *http.Request) (limit, page int, ok bool) {
+	limit, err := parseIntParam(r, "limit", 50)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return 0, 0, false
+	}
+	page, err = parseIntParam(r, "page", 1)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return 0, 0, false
+	}
+	return limit, page, true
+}
+
+func (h *AdminAPIHandlers) requesterID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	raw, exists := middleware.GetUserIDFromContext(r.Context())
+	if !exists {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
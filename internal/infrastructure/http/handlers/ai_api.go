@@ -4,26 +4,40 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
+	"github.com/alchemorsel/v3/internal/application/user"
+	"github.com/alchemorsel/v3/internal/domain/notification"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/translation"
 	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
 	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // AIAPIHandlers handles AI API requests
 type AIAPIHandlers struct {
-	aiService outbound.AIService
-	logger    *zap.Logger
+	aiService     outbound.AIService
+	notifications inbound.NotificationService
+	users         *user.UserService
+	logger        *zap.Logger
 }
 
-// NewAIAPIHandlers creates a new AI API handlers instance
+// NewAIAPIHandlers creates a new AI API handlers instance. notifications
+// and users may be nil; without users generation ignores dietary profiles.
 func NewAIAPIHandlers(
 	aiService outbound.AIService,
+	notifications inbound.NotificationService,
+	users *user.UserService,
 	logger *zap.Logger,
 ) *AIAPIHandlers {
 	return &AIAPIHandlers{
-		aiService: aiService,
-		logger:    logger,
+		aiService:     aiService,
+		notifications: notifications,
+		users:         users,
+		logger:        logger,
 	}
 }
 
@@ -34,6 +48,9 @@ type GenerateRecipeRequest struct {
 	Dietary     []string `json:"dietary,omitempty"`
 	Cuisine     string   `json:"cuisine,omitempty"`
 	ServingSize int      `json:"serving_size,omitempty"`
+	// Language is a BCP 47 tag; the Accept-Language header picks it when
+	// empty, then English
+	Language string `json:"language,omitempty"`
 }
 
 // SuggestIngredientsRequest represents ingredient suggestion request
@@ -66,9 +83,16 @@ func (h *AIAPIHandlers) GenerateRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	language, ok := generationLanguage(req.Language, r.Header.Get("Accept-Language"))
+	if !ok {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Unsupported language: "+req.Language)
+		return
+	}
+
 	h.logger.Info("AI recipe generation request",
 		zap.String("user_id", userID),
 		zap.String("prompt", req.Prompt),
+		zap.String("language", language),
 	)
 
 	// Build AI constraints
@@ -77,7 +101,9 @@ func (h *AIAPIHandlers) GenerateRecipe(w http.ResponseWriter, r *http.Request) {
 		Dietary:       req.Dietary,
 		Cuisine:       req.Cuisine,
 		ServingSize:   req.ServingSize,
+		Language:      language,
 	}
+	h.applyDietaryProfile(r, userID, &constraints)
 
 	// Call AI service
 	aiResponse, err := h.aiService.GenerateRecipe(r.Context(), req.Prompt, constraints)
@@ -90,6 +116,8 @@ func (h *AIAPIHandlers) GenerateRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.notifyRecipeReady(r, userID, aiResponse.Title)
+
 	response := APIResponse{
 		Success: true,
 		Data:    aiResponse,
@@ -99,6 +127,78 @@ func (h *AIAPIHandlers) GenerateRecipe(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, response)
 }
 
+// applyDietaryProfile adds the user's diets to the requested ones and has
+// the recipe avoid their allergens and disliked ingredients. Generation
+// goes ahead without them when the profile cannot be loaded.
+func (h *AIAPIHandlers) applyDietaryProfile(r *http.Request, userID string, constraints *outbound.AIConstraints) {
+	if h.users == nil {
+		return
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return
+	}
+	profile, err := h.users.GetDietaryProfile(r.Context(), id)
+	if err != nil {
+		h.logger.Warn("Dietary profile unavailable for generation", zap.String("user_id", userID), zap.Error(err))
+		return
+	}
+
+	constraints.Dietary = appendMissing(constraints.Dietary, profile.DietaryRestrictions...)
+	constraints.AvoidIngredients = appendMissing(constraints.AvoidIngredients, profile.Allergies...)
+	constraints.AvoidIngredients = appendMissing(constraints.AvoidIngredients, profile.DislikedIngredients...)
+}
+
+// appendMissing appends the values list does not hold yet, ignoring case
+func appendMissing(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if strings.EqualFold(existing, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// notifyRecipeReady leaves a notification for users who navigated away
+// while the recipe was generating
+func (h *AIAPIHandlers) notifyRecipeReady(r *http.Request, userID, title string) {
+	if h.notifications == nil {
+		return
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return
+	}
+	err = h.notifications.Notify(r.Context(), inbound.NotifyCommand{
+		UserID:  id,
+		Type:    notification.TypeAIRecipeReady,
+		Subject: title,
+	})
+	if err != nil {
+		h.logger.Warn("Failed to send AI recipe notification", zap.String("user_id", userID), zap.Error(err))
+	}
+}
+
+// generationLanguage is the requested language, else the browser's
+// preferred supported one, else English
+func generationLanguage(requested, acceptLanguage string) (string, bool) {
+	if requested != "" {
+		language, ok := translation.Lookup(requested)
+		return language.Tag, ok
+	}
+	if language, ok := translation.FromAcceptLanguage(acceptLanguage); ok {
+		return language.Tag, true
+	}
+	return recipe.DefaultLanguage, true
+}
+
 // SuggestIngredients handles POST /api/v1/ai/suggest-ingredients
 func (h *AIAPIHandlers) SuggestIngredients(w http.ResponseWriter, r *http.Request) {
 	// Get user info from context
@@ -0,0 +1,48 @@
+// Package handlers provides recipe allergen disclosures
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AllergenAPIHandlers serves recipe allergen panels
+type AllergenAPIHandlers struct {
+	responder
+	allergens inbound.AllergenService
+}
+
+// NewAllergenAPIHandlers creates the allergen handlers
+func NewAllergenAPIHandlers(allergens inbound.AllergenService, logger *zap.Logger) *AllergenAPIHandlers {
+	return &AllergenAPIHandlers{
+		allergens: allergens,
+		responder: responder{logger: logger},
+	}
+}
+
+// RecipeAllergens handles GET /api/v1/recipes/{id}/allergens
+func (h *AllergenAPIHandlers) RecipeAllergens(w http.ResponseWriter, r *http.Request) {
+	query := inbound.AllergenQuery{RecipeID: chi.URLParam(r, "id")}
+	if raw, exists := middleware.GetUserIDFromContext(r.Context()); exists {
+		if id, err := uuid.Parse(raw); err == nil {
+			query.RequesterID = id
+		}
+	}
+
+	disclosure, err := h.allergens.Disclose(r.Context(), query)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    disclosure,
+		Message: disclosure.Panel,
+	})
+}
@@ -0,0 +1,256 @@
+// Package handlers provides recipe view beacons and the author analytics endpoint
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxBeaconBytes bounds view and engagement beacon bodies
+const maxBeaconBytes = 4 << 10
+
+// RecipeViewRequest is the beacon sent when a recipe page is opened
+type RecipeViewRequest struct {
+	SessionID  string `json:"session_id"`
+	Referrer   string `json:"referrer"`
+	SearchTerm string `json:"search_term"`
+}
+
+// RecipeEngagementRequest is the beacon sent when a visitor leaves the page
+type RecipeEngagementRequest struct {
+	ScrollDepth int `json:"scroll_depth"`
+	EngagedMS   int `json:"engaged_ms"`
+}
+
+// RecordRecipeView handles POST /api/v1/recipes/{id}/views
+// Anonymous visits are allowed. The body is optional and may arrive as
+// text/plain from navigator.sendBeacon.
+func (h *APIHandlers) RecordRecipeView(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	var req RecipeViewRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBeaconBytes)).Decode(&req); err != nil && err != io.EOF {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	cmd := inbound.RecordRecipeViewCommand{
+		RecipeID:   recipeID,
+		SessionID:  req.SessionID,
+		UserAgent:  r.UserAgent(),
+		Referrer:   req.Referrer,
+		SearchTerm: req.SearchTerm,
+	}
+	if userID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		if id, err := uuid.Parse(userID); err == nil {
+			cmd.UserID = &id
+		}
+	}
+
+	viewID, err := h.recipeService.RecordRecipeView(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    map[string]uuid.UUID{"view_id": viewID},
+		Message: "View recorded",
+	})
+}
+
+// RecordRecipeEngagement handles POST /api/v1/recipes/{id}/views/{viewID}/engagement
+// Stores the scroll depth (0-100) and time on page the RUM client reports
+// when the visitor leaves.
+func (h *APIHandlers) RecordRecipeEngagement(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+	viewID, err := uuid.Parse(chi.URLParam(r, "viewID"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid view ID")
+		return
+	}
+
+	var req RecipeEngagementRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBeaconBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	err = h.recipeService.RecordRecipeEngagement(r.Context(), inbound.RecordRecipeEngagementCommand{
+		RecipeID:    recipeID,
+		ViewID:      viewID,
+		ScrollDepth: req.ScrollDepth,
+		EngagedMS:   req.EngagedMS,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Engagement recorded",
+	})
+}
+
+// RecipeAnalytics handles GET /api/v1/recipes/{id}/analytics
+// Returns the author stats for the last ?days= (default 30); ?format=csv
+// downloads them as a spreadsheet.
+func (h *APIHandlers) RecipeAnalytics(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+	days, err := parseIntParam(r, "days", 30)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		h.writeErrorJSON(w, http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+
+	analytics, err := h.recipeService.GetRecipeAnalytics(r.Context(), inbound.RecipeAnalyticsQuery{
+		RequesterID: userID,
+		RecipeID:    recipeID,
+		Days:        days,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	if format == "csv" {
+		filename := fmt.Sprintf("recipe-%s-%s.csv", analytics.RecipeID, analytics.Until)
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		w.WriteHeader(http.StatusOK)
+		if err := writeRecipeAnalyticsCSV(w, analytics); err != nil {
+			h.logger.Error("Failed to write analytics CSV", zap.Error(err))
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    analytics,
+		Message: "Recipe analytics retrieved successfully",
+	})
+}
+
+// AuthorAnalytics handles GET /api/v1/recipes/analytics
+// Returns the activity across the caller's own recipes for the last ?days=
+// (default 30), per day and per recipe.
+func (h *APIHandlers) AuthorAnalytics(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+	days, err := parseIntParam(r, "days", 30)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	analytics, err := h.recipeService.GetAuthorAnalytics(r.Context(), inbound.AuthorAnalyticsQuery{
+		AuthorID: userID,
+		Days:     days,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    analytics,
+		Message: "Author analytics retrieved successfully",
+	})
+}
+
+// writeRecipeAnalyticsCSV writes the stats in long form, one
+// section,key,value row per figure, so every section shares a header
+func writeRecipeAnalyticsCSV(w io.Writer, a *inbound.RecipeAnalytics) error {
+	out := csv.NewWriter(w)
+	rows := [][]string{
+		{"section", "key", "value"},
+		{"summary", "recipe_id", a.RecipeID.String()},
+		{"summary", "title", csvSafe(a.Title)},
+		{"summary", "since", a.Since},
+		{"summary", "until", a.Until},
+		{"summary", "views", strconv.Itoa(a.Views)},
+		{"summary", "unique_viewers", strconv.Itoa(a.UniqueViewers)},
+		{"summary", "likes", strconv.Itoa(a.Likes)},
+		{"summary", "bookmarks", strconv.Itoa(a.Bookmarks)},
+		{"summary", "like_conversion", strconv.FormatFloat(a.LikeConversion, 'f', 4, 64)},
+		{"summary", "bookmark_conversion", strconv.FormatFloat(a.BookmarkConversion, 'f', 4, 64)},
+	}
+	if a.AverageScrollDepth != nil {
+		rows = append(rows, []string{"summary", "average_scroll_depth", strconv.FormatFloat(*a.AverageScrollDepth, 'f', 1, 64)})
+	}
+	rows = append(rows, []string{"summary", "scroll_samples", strconv.Itoa(a.ScrollSamples)})
+	for _, day := range a.Daily {
+		rows = append(rows,
+			[]string{"daily_views", day.Date, strconv.Itoa(day.Views)},
+			[]string{"daily_unique_viewers", day.Date, strconv.Itoa(day.UniqueViewers)},
+		)
+	}
+	for _, term := range a.SearchTerms {
+		rows = append(rows, []string{"search_term", csvSafe(term.Key), strconv.Itoa(term.Count)})
+	}
+	for _, referrer := range a.Referrers {
+		rows = append(rows, []string{"referrer", csvSafe(referrer.Key), strconv.Itoa(referrer.Count)})
+	}
+
+	if err := out.WriteAll(rows); err != nil {
+		return err
+	}
+	return out.Error()
+}
+
+// csvSafe stops visitor-supplied text from being read as a spreadsheet
+// formula when the export is opened
+func csvSafe(s string) string {
+	if s != "" && (s[0] == '=' || s[0] == '+' || s[0] == '-' || s[0] == '@') {
+		return "'" + s
+	}
+	return s
+}
@@ -3,26 +3,45 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/infrastructure/recipeimport"
 	"github.com/alchemorsel/v3/internal/ports/inbound"
+	apperrors "github.com/alchemorsel/v3/pkg/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // APIHandlers handles REST API requests
 type APIHandlers struct {
 	recipeService inbound.RecipeService
+	uploadScans   inbound.UploadScanService
+	verification  inbound.VerificationService
 	logger        *zap.Logger
 }
 
-// NewAPIHandlers creates a new API handlers instance
+// NewAPIHandlers creates a new API handlers instance. uploadScans may be
+// nil on servers that do not route the import endpoints, and verification
+// on servers that do not show author badges.
 func NewAPIHandlers(
 	recipeService inbound.RecipeService,
+	uploadScans inbound.UploadScanService,
+	verification inbound.VerificationService,
 	logger *zap.Logger,
 ) *APIHandlers {
 	return &APIHandlers{
 		recipeService: recipeService,
+		uploadScans:   uploadScans,
+		verification:  verification,
 		logger:        logger,
 	}
 }
@@ -36,15 +55,303 @@ type APIResponse struct {
 }
 
 // ListRecipes handles GET /api/v3/recipes
+// Supports ?fields= and ?include= to shape the payload for mobile clients.
+// Authenticated callers get personalized ranking unless ?personalize=false;
+// ?explain=true adds the ranking factors for each result. ?favorited=true,
+// or favorited:true in ?search=, keeps the caller's saved recipes.
+// ?search= takes "quoted phrases" and prefix* words. ?cuisine=,
+// ?difficulty= and ?diet= take comma-separated values, ?max_time= minutes
+// and ?ai_generated= true or false; ?facets=true counts the results per
+// filter value. ?mode=semantic searches by meaning rather than keyword.
+// Without ?diet= a signed-in user's own diets apply; ?diet=any lifts them.
+// ?page= (from 1) and ?limit= or ?page_size= page the list; a list with
+// ?sort= is paged instead by passing its next_cursor as ?after=, and the
+// Link header carries the next page.
 func (h *APIHandlers) ListRecipes(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement recipe listing
+	h.listRecipes(w, r, "")
+}
+
+// RelevanceListRecipes is the candidate for GET /api/v1/recipes behind the
+// recipe-search canary. Searches rank title matches above description
+// matches instead of listing newest first.
+func (h *APIHandlers) RelevanceListRecipes(w http.ResponseWriter, r *http.Request) {
+	h.listRecipes(w, r, "relevance")
+}
+
+// Recipe lists hold defaultListLimit recipes unless the limit or page_size
+// parameter asks for up to maxListLimit. Offset pages stop at maxListPage;
+// deeper reads should sort and follow next_cursor.
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+	maxListPage      = 500
+)
+
+func (h *APIHandlers) listRecipes(w http.ResponseWriter, r *http.Request, orderBy string) {
+	sel, err := ParseFieldSelection(r, DefaultRecipeListFields)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	personalize, err := parseBoolParam(r, "personalize", true)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	explain, err := parseBoolParam(r, "explain", false)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	favorited, err := parseBoolParam(r, "favorited", false)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	facets, err := parseBoolParam(r, "facets", false)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	maxTime, err := parseIntParam(r, "max_time", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limitParam := "limit"
+	if r.URL.Query().Get("page_size") != "" {
+		limitParam = "page_size"
+	}
+	limit, err := parseIntParam(r, limitParam, defaultListLimit)
+	if err != nil || limit > maxListLimit {
+		h.writeErrorJSON(w, http.StatusBadRequest, fmt.Sprintf("%s must be between 1 and %d", limitParam, maxListLimit))
+		return
+	}
+	page, err := parseIntParam(r, "page", 1)
+	if err != nil || page > maxListPage {
+		h.writeErrorJSON(w, http.StatusBadRequest, fmt.Sprintf("page must be between 1 and %d", maxListPage))
+		return
+	}
+	var semantic bool
+	switch mode := r.URL.Query().Get("mode"); mode {
+	case "", "keyword":
+	case "semantic":
+		semantic = true
+	default:
+		h.writeErrorJSON(w, http.StatusBadRequest, "mode must be keyword or semantic")
+		return
+	}
+	switch listing := r.URL.Query().Get("sort"); listing {
+	case "":
+	case "trending", "top", "new", "newest", "rating", "prep_time":
+		if semantic {
+			h.writeErrorJSON(w, http.StatusBadRequest, "sort is not available with mode=semantic")
+			return
+		}
+		if listing == "newest" {
+			listing = "new"
+		}
+		orderBy = listing
+	default:
+		h.writeErrorJSON(w, http.StatusBadRequest, "sort must be trending, top, new, newest, rating or prep_time")
+		return
+	}
+
+	query := inbound.SearchQuery{
+		Text:    r.URL.Query().Get("search"),
+		MaxTime: maxTime,
+		Dietary: parseListParam(r, "diet"),
+		Pagination: inbound.PaginationParams{
+			Page:     page - 1,
+			PageSize: limit,
+			OrderBy:  orderBy,
+		},
+		After:       r.URL.Query().Get("after"),
+		Personalize: personalize,
+		Favorited:   favorited,
+		Explain:     explain,
+		Facets:      facets,
+		Semantic:    semantic,
+	}
+	for _, cuisine := range parseListParam(r, "cuisine") {
+		query.Cuisine = append(query.Cuisine, recipe.CuisineType(cuisine))
+	}
+	for _, difficulty := range parseListParam(r, "difficulty") {
+		query.Difficulty = append(query.Difficulty, recipe.DifficultyLevel(difficulty))
+	}
+	if r.URL.Query().Get("ai_generated") != "" {
+		aiGenerated, err := parseBoolParam(r, "ai_generated", false)
+		if err != nil {
+			h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		query.AIGenerated = &aiGenerated
+	}
+	if userID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		if id, err := uuid.Parse(userID); err == nil {
+			query.UserID = &id
+		}
+	}
+
+	list, err := h.recipeService.SearchRecipes(r.Context(), query)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	authorIDs := make([]uuid.UUID, len(list.Recipes))
+	for i := range list.Recipes {
+		authorIDs[i] = list.Recipes[i].AuthorID
+	}
+	badges := h.authorBadges(r, sel, authorIDs...)
+	for i := range list.Recipes {
+		list.Recipes[i].AuthorBadge = badges[list.Recipes[i].AuthorID]
+	}
+
+	items, err := ShapeRecipes(list.Recipes, sel)
+	if err != nil {
+		h.logger.Error("Failed to shape recipe list", zap.Error(err))
+		h.writeErrorJSON(w, http.StatusInternalServerError, "Failed to build response")
+		return
+	}
+
+	data := map[string]interface{}{
+		"recipes":      items,
+		"total":        list.Total,
+		"page":         list.Page + 1,
+		"page_size":    list.PageSize,
+		"total_pages":  list.TotalPages,
+		"personalized": list.Personalized,
+	}
+	if explain {
+		data["explanations"] = list.Explanations
+	}
+	if list.Fallback != nil {
+		data["fallback"] = list.Fallback
+	}
+	if list.Facets != nil {
+		data["facets"] = list.Facets
+	}
+	if list.Semantic {
+		data["semantic"] = true
+	}
+	if len(list.DefaultDiets) > 0 {
+		data["default_diets"] = list.DefaultDiets
+	}
+	if list.NextCursor != "" {
+		data["next_cursor"] = list.NextCursor
+	}
+	writePageLinks(w, r, list.NextCursor)
+
 	response := APIResponse{
 		Success: true,
-		Data:    []interface{}{},
+		Data:    data,
 		Message: "Recipes retrieved successfully",
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	h.writeShapedJSON(w, http.StatusOK, response, sel)
+}
+
+// TrendingRecipes handles GET /api/v1/recipes/trending
+// Returns the first page of trending recipes, warmed into the query cache on
+// boot.
+func (h *APIHandlers) TrendingRecipes(w http.ResponseWriter, r *http.Request) {
+	list, err := h.recipeService.GetTrendingRecipes(r.Context(), inbound.PaginationParams{
+		Page:     0,
+		PageSize: 20,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    list,
+		Message: "Trending recipes retrieved successfully",
+	})
+}
+
+// RecommendedRecipes handles GET /api/v1/recipes/recommended
+// Returns a page of the user's precomputed recommendations, explained, or
+// of trending recipes until they have some, with hidden gems rotated into
+// some of the slots on the first page.
+func (h *APIHandlers) RecommendedRecipes(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+	page, err := parseIntParam(r, "page", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	list, err := h.recipeService.GetRecommendedRecipes(r.Context(), userID, inbound.PaginationParams{
+		Page:     page,
+		PageSize: 20,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    list,
+		Message: "Recommended recipes retrieved successfully",
+	})
+}
+
+// SimilarRecipes handles GET /api/v1/recipes/{id}/similar
+// Lists the published recipes sharing the most cuisine, tags and
+// ingredients with a recipe, each explained by what they share.
+func (h *APIHandlers) SimilarRecipes(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 6)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	list, err := h.recipeService.GetSimilarRecipes(r.Context(), recipeID, limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    list,
+		Message: "Similar recipes retrieved successfully",
+	})
+}
+
+// SearchFacets handles GET /api/v1/recipes/facets
+// Lists the filter values for the search page with their recipe counts.
+func (h *APIHandlers) SearchFacets(w http.ResponseWriter, r *http.Request) {
+	facets, err := h.recipeService.GetSearchFacets(r.Context())
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    facets,
+		Message: "Search facets retrieved successfully",
+	})
 }
 
 // CreateRecipe handles POST /api/v3/recipes
@@ -59,26 +366,41 @@ func (h *APIHandlers) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetRecipe handles GET /api/v3/recipes/{id}
+// Supports ?fields= and ?include= to shape the payload for mobile clients
 func (h *APIHandlers) GetRecipe(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement recipe retrieval
-	response := APIResponse{
-		Success: true,
-		Data:    map[string]interface{}{"id": "1", "title": "Sample Recipe"},
-		Message: "Recipe retrieved successfully",
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
-}
+	sel, err := ParseFieldSelection(r, DefaultRecipeDetailFields)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dto, err := h.recipeService.GetRecipeByID(r.Context(), recipeID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	dto.AuthorBadge = h.authorBadges(r, sel, dto.AuthorID)[dto.AuthorID]
+	shaped, err := ShapeRecipe(dto, sel)
+	if err != nil {
+		h.logger.Error("Failed to shape recipe", zap.Error(err))
+		h.writeErrorJSON(w, http.StatusInternalServerError, "Failed to build response")
+		return
+	}
 
-// UpdateRecipe handles PUT /api/v3/recipes/{id}
-func (h *APIHandlers) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement recipe update
 	response := APIResponse{
 		Success: true,
-		Message: "Recipe updated successfully",
+		Data:    shaped,
+		Message: "Recipe retrieved successfully",
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	h.writeShapedJSON(w, http.StatusOK, response, sel)
 }
 
 // DeleteRecipe handles DELETE /api/v3/recipes/{id}
@@ -92,15 +414,34 @@ func (h *APIHandlers) DeleteRecipe(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, response)
 }
 
-// LikeRecipe handles POST /api/v3/recipes/{id}/like
+// LikeRecipe handles POST /api/v1/recipes/{id}/like
+// Likes the recipe and returns how many likes it now has.
 func (h *APIHandlers) LikeRecipe(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement recipe like
-	response := APIResponse{
-		Success: true,
-		Message: "Recipe liked successfully",
+	userID, ok := h.requestUserID(w, r)
+	if !ok {
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	if err := h.recipeService.LikeRecipe(r.Context(), recipeID, userID); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	liked, err := h.recipeService.GetRecipeByID(r.Context(), recipeID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    inbound.LikeStatus{RecipeID: recipeID, Liked: true, Likes: liked.Likes},
+		Message: "Recipe liked successfully",
+	})
 }
 
 // HealthCheck handles GET /api/v3/health
@@ -118,18 +459,6 @@ func (h *APIHandlers) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, response)
 }
 
-// writeJSON writes a JSON response
-// RateRecipe handles POST /api/v1/recipes/{id}/rating
-func (h *APIHandlers) RateRecipe(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement recipe rating
-	response := APIResponse{
-		Success: true,
-		Message: "Recipe rated successfully",
-	}
-
-	h.writeJSON(w, http.StatusOK, response)
-}
-
 // GetUserRecipes handles GET /api/v1/users/{id}/recipes
 func (h *APIHandlers) GetUserRecipes(w http.ResponseWriter, r *http.Request) {
 	// TODO: Implement user recipes listing
@@ -142,18 +471,421 @@ func (h *APIHandlers) GetUserRecipes(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, response)
 }
 
-// GetUserFavorites handles GET /api/v1/users/{id}/favorites
-func (h *APIHandlers) GetUserFavorites(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement user favorites listing
-	response := APIResponse{
+// ZeroResultSearches handles GET /api/v1/analytics/zero-result-searches
+// Admin-only report of queries that found nothing, for taxonomy tuning
+func (h *APIHandlers) ZeroResultSearches(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	days, err := parseIntParam(r, "days", 30)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 50)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	queries, err := h.recipeService.GetZeroResultQueries(r.Context(), userID, days, limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
 		Success: true,
-		Data:    []interface{}{},
-		Message: "User favorites retrieved successfully",
+		Data:    queries,
+		Message: "Zero-result searches retrieved successfully",
+	})
+}
+
+// maxPhotoUploadBytes bounds the import request body; the service enforces
+// the tighter per-image limit
+const maxPhotoUploadBytes = 12 << 20
+
+// ImportRecipePhoto handles POST /api/v1/recipes/import/photo
+// Accepts a multipart "image" field or a raw image body and returns the
+// created draft with the lines to review
+func (h *APIHandlers) ImportRecipePhoto(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	r.Body = http.MaxBytesReader(w, r.Body, maxPhotoUploadBytes)
+	image, contentType, err := readUploadedImage(r)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !h.scanUpload(w, r, inbound.ScanUploadCommand{
+		UserID:      userID,
+		Source:      inbound.UploadSourceRecipePhoto,
+		ContentType: contentType,
+		Content:     image,
+	}) {
+		return
+	}
+
+	imported, err := h.recipeService.ImportRecipeFromImage(r.Context(), inbound.ImportRecipeImageCommand{
+		UserID:      userID,
+		Image:       image,
+		ContentType: contentType,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    imported,
+		Message: "Recipe draft imported, review the highlighted lines before publishing",
+	})
+}
+
+// readUploadedImage returns the image bytes and their content type, sniffing
+// the type when the client did not declare one
+func readUploadedImage(r *http.Request) ([]byte, string, error) {
+	var (
+		data        []byte
+		contentType string
+		err         error
+	)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxPhotoUploadBytes); err != nil {
+			return nil, "", fmt.Errorf("invalid upload: %w", err)
+		}
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			return nil, "", fmt.Errorf("image field is required")
+		}
+		defer file.Close()
+		contentType = header.Header.Get("Content-Type")
+		data, err = io.ReadAll(file)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read image: %w", err)
+		}
+	} else {
+		contentType = r.Header.Get("Content-Type")
+		data, err = io.ReadAll(r.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read image: %w", err)
+		}
+	}
+
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+	return data, contentType, nil
+}
+
+// scanUpload runs an upload past the virus scanner before anything parses
+// it, writing the error response and returning false when it is refused
+func (h *APIHandlers) scanUpload(w http.ResponseWriter, r *http.Request, cmd inbound.ScanUploadCommand) bool {
+	if h.uploadScans == nil {
+		return true
+	}
+	if err := h.uploadScans.ScanUpload(r.Context(), cmd); err != nil {
+		h.writeServiceError(w, err)
+		return false
+	}
+	return true
 }
 
+// maxLibraryUploadBytes bounds a library export upload; Paprika archives
+// carry embedded photos and get large quickly
+const maxLibraryUploadBytes = 100 << 20
+
+// ImportRecipeLibrary handles POST /api/v1/recipes/import/library
+// Accepts a multipart "file" field holding a Paprika, Mealie or Nextcloud
+// Cookbook export, with optional "format" and "keep_duplicates" fields
+func (h *APIHandlers) ImportRecipeLibrary(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxLibraryUploadBytes)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid upload: expected multipart form with a file field")
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "file field is required")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Failed to read upload")
+		return
+	}
+	if !h.scanUpload(w, r, inbound.ScanUploadCommand{
+		UserID:      userID,
+		Source:      inbound.UploadSourceRecipeLibrary,
+		FileName:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Content:     data,
+	}) {
+		return
+	}
+
+	recipes, format, err := recipeimport.Decode(r.FormValue("format"), header.Filename, data)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	keepDuplicates, _ := strconv.ParseBool(r.FormValue("keep_duplicates"))
+	result, err := h.recipeService.ImportRecipeLibrary(r.Context(), inbound.ImportLibraryCommand{
+		UserID:         userID,
+		Source:         format,
+		Recipes:        recipes,
+		KeepDuplicates: keepDuplicates,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    result,
+		Message: fmt.Sprintf("Imported %d recipes, skipped %d duplicates, %d failed", result.Created, result.Duplicates, result.Failed),
+	})
+}
+
+// RecipeStructuredData handles GET /api/v1/recipes/{id}/structured-data
+// Returns the recipe's JSON-LD and its gaps against rich result rules.
+// Images are fetched and measured unless check_images=false.
+func (h *APIHandlers) RecipeStructuredData(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+	checkImages, err := parseBoolParam(r, "check_images", true)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.recipeService.ValidateStructuredData(r.Context(), inbound.StructuredDataQuery{
+		RequesterID: userID,
+		RecipeID:    &recipeID,
+		CheckImages: checkImages,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    report.Recipes[0],
+		Message: "Structured data checked",
+	})
+}
+
+// KitchenTicket handles GET /api/v1/recipes/{id}/kitchen-ticket
+// Chefs get the recipe scaled to ?portions=, weighed and split by station.
+// ?format=pdf or ?format=escpos returns it ready for a thermal printer,
+// ?columns= setting the printer's line width.
+func (h *APIHandlers) KitchenTicket(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+	portions, err := parseIntParam(r, "portions", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	columns, err := parseIntParam(r, "columns", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := inbound.KitchenTicketQuery{
+		RequesterID: userID,
+		RecipeID:    recipeID,
+		Portions:    portions,
+		Format:      r.URL.Query().Get("format"),
+		Columns:     columns,
+	}
+	if query.Format == "" || query.Format == "json" {
+		ticket, err := h.recipeService.GetKitchenTicket(r.Context(), query)
+		if err != nil {
+			h.writeServiceError(w, err)
+			return
+		}
+		h.writeJSON(w, http.StatusOK, APIResponse{
+			Success: true,
+			Data:    ticket,
+			Message: "Kitchen ticket prepared",
+		})
+		return
+	}
+
+	printed, err := h.recipeService.PrintKitchenTicket(r.Context(), query)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", printed.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+printed.FileName+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(printed.Content)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(printed.Content); err != nil {
+		h.logger.Warn("Kitchen ticket download interrupted", zap.String("recipe_id", recipeID.String()), zap.Error(err))
+	}
+}
+
+// StructuredDataReport handles GET /api/v1/recipes/structured-data
+// Lists published recipes with structured data gaps: all of them for admins,
+// the caller's own for authors. Images are only measured with check_images=true.
+func (h *APIHandlers) StructuredDataReport(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+	checkImages, err := parseBoolParam(r, "check_images", false)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.recipeService.ValidateStructuredData(r.Context(), inbound.StructuredDataQuery{
+		RequesterID: userID,
+		CheckImages: checkImages,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    report,
+		Message: fmt.Sprintf("%d of %d recipes eligible for rich results", report.Eligible, report.Checked),
+	})
+}
+
+// parseIntParam reads an optional positive integer query parameter
+func parseIntParam(r *http.Request, name string, fallback int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return value, nil
+}
+
+// writePageLinks sets an RFC 5988 Link header pointing to the next page of
+// a list paged by cursor, and back to the first once past it
+func writePageLinks(w http.ResponseWriter, r *http.Request, next string) {
+	link := func(after, rel string) {
+		query := r.URL.Query()
+		query.Del("after")
+		if after != "" {
+			query.Set("after", after)
+		}
+		target := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		w.Header().Add("Link", fmt.Sprintf("<%s>; rel=%q", target.String(), rel))
+	}
+	if next != "" {
+		link(next, "next")
+	}
+	if r.URL.Query().Get("after") != "" {
+		link("", "first")
+	}
+}
+
+// parseListParam reads a query parameter given as comma-separated values,
+// repeated, or both. Values are trimmed and lower-cased.
+func parseListParam(r *http.Request, name string) []string {
+	var values []string
+	for _, raw := range r.URL.Query()[name] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// parseBoolParam reads an optional boolean query parameter
+func parseBoolParam(r *http.Request, name string, fallback bool) (bool, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", name)
+	}
+	return value, nil
+}
+
+// writeJSON writes a JSON response
 func (h *APIHandlers) writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -162,4 +894,60 @@ func (h *APIHandlers) writeJSON(w http.ResponseWriter, status int, data interfac
 		h.logger.Error("Failed to encode JSON response", zap.Error(err))
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
-}
\ No newline at end of file
+}
+
+// writeShapedJSON writes a projected response and reports the applied field set
+func (h *APIHandlers) writeShapedJSON(w http.ResponseWriter, status int, data interface{}, sel FieldSelection) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		h.logger.Error("Failed to encode JSON response", zap.Error(err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if len(body) > firstPacketBudget {
+		h.logger.Debug("Shaped response exceeds first packet budget",
+			zap.Int("bytes", len(body)),
+			zap.Strings("fields", sel.FieldNames()),
+		)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Fields", strings.Join(sel.FieldNames(), ","))
+	w.Header().Add("Vary", "Accept-Encoding")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// writeErrorJSON writes an error response
+func (h *APIHandlers) writeErrorJSON(w http.ResponseWriter, status int, message string) {
+	response := APIResponse{
+		Success: false,
+		Error:   message,
+	}
+	h.writeJSON(w, status, response)
+}
+
+// authorBadges looks up the verified badges of the given authors when the
+// client selected author_badge. Badges only decorate recipes, so a failed
+// lookup is logged and the recipes are served without them.
+func (h *APIHandlers) authorBadges(r *http.Request, sel FieldSelection, authorIDs ...uuid.UUID) map[uuid.UUID]*inbound.VerificationBadge {
+	if h.verification == nil || !sel.Fields["author_badge"] || len(authorIDs) == 0 {
+		return nil
+	}
+	badges, err := h.verification.Badges(r.Context(), authorIDs)
+	if err != nil {
+		h.logger.Warn("Failed to look up author badges", zap.Error(err))
+		return nil
+	}
+	return badges
+}
+
+// writeServiceError maps application errors onto HTTP status codes
+func (h *APIHandlers) writeServiceError(w http.ResponseWriter, err error) {
+	appErr := apperrors.Wrap(err, "request failed")
+	if appErr.StatusCode() >= http.StatusInternalServerError {
+		h.logger.Error("Recipe service error", zap.Error(err))
+	}
+	h.writeErrorJSON(w, appErr.StatusCode(), appErr.Message)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// searchRecorder answers searches with an empty page and keeps the query
+type searchRecorder struct {
+	inbound.RecipeService
+	query inbound.SearchQuery
+}
+
+func (s *searchRecorder) SearchRecipes(ctx context.Context, query inbound.SearchQuery) (*inbound.RecipeList, error) {
+	s.query = query
+	return &inbound.RecipeList{
+		Recipes:    []inbound.RecipeDTO{},
+		Total:      45,
+		Page:       query.Pagination.Page,
+		PageSize:   query.Pagination.PageSize,
+		TotalPages: 3,
+	}, nil
+}
+
+func TestListRecipesPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		wantCode     int
+		wantPage     int
+		wantPageSize int
+	}{
+		{"first page by default", "/api/v1/recipes", http.StatusOK, 1, defaultListLimit},
+		{"page two", "/api/v1/recipes?page=2&page_size=20", http.StatusOK, 2, 20},
+		{"limit sets the page size", "/api/v1/recipes?page=3&limit=15", http.StatusOK, 3, 15},
+		{"page_size wins over limit", "/api/v1/recipes?limit=10&page_size=30", http.StatusOK, 1, 30},
+		{"page zero", "/api/v1/recipes?page=0", http.StatusBadRequest, 0, 0},
+		{"page past the bound", "/api/v1/recipes?page=501", http.StatusBadRequest, 0, 0},
+		{"page that is not a number", "/api/v1/recipes?page=two", http.StatusBadRequest, 0, 0},
+		{"page size past the bound", "/api/v1/recipes?page_size=101", http.StatusBadRequest, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &searchRecorder{}
+			h := NewAPIHandlers(service, nil, nil, zap.NewNop())
+
+			w := httptest.NewRecorder()
+			h.ListRecipes(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			require.Equal(t, tt.wantCode, w.Code, w.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, tt.wantPage-1, service.query.Pagination.Page, "the service counts pages from 0")
+			assert.Equal(t, tt.wantPageSize, service.query.Pagination.PageSize)
+
+			var response struct {
+				Data struct {
+					Page     int `json:"page"`
+					PageSize int `json:"page_size"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantPage, response.Data.Page)
+			assert.Equal(t, tt.wantPageSize, response.Data.PageSize)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Package handlers provides the archive tiering and long-term history endpoints
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ArchiveAPIHandlers runs archive tiering and reads archived days back
+type ArchiveAPIHandlers struct {
+	responder
+	archive inbound.ArchiveService
+}
+
+// NewArchiveAPIHandlers creates the archive handlers
+func NewArchiveAPIHandlers(archive inbound.ArchiveService, logger *zap.Logger) *ArchiveAPIHandlers {
+	return &ArchiveAPIHandlers{
+		archive:   archive,
+		responder: responder{logger: logger},
+	}
+}
+
+// RunTiering handles POST /api/v1/admin/archive/run
+// Archives the days past retention now rather than on the next scheduled run.
+func (h *ArchiveAPIHandlers) RunTiering(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.archive.TriggerTiering(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    report,
+		Message: fmt.Sprintf("Archived %d days", len(report.Partitions)),
+	})
+}
+
+// AuditHistory handles GET /api/v1/admin/archive/audit?from=&to=
+// Dates are YYYY-MM-DD in UTC and both ends are included.
+func (h *ArchiveAPIHandlers) AuditHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	captures, err := h.archive.AuditHistory(r.Context(), userID, from, to)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    captures,
+		Message: "Archived audit records retrieved successfully",
+	})
+}
+
+// RecipeViewHistory handles GET /api/v1/recipes/{id}/analytics/history
+// Returns the archived daily views; recent days come from /analytics.
+func (h *ArchiveAPIHandlers) RecipeViewHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	history, err := h.archive.RecipeViewHistory(r.Context(), inbound.RecipeViewHistoryQuery{
+		RequesterID: userID,
+		RecipeID:    recipeID,
+		From:        from,
+		To:          to,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    history,
+		Message: "Recipe view history retrieved successfully",
+	})
+}
+
+// parseDateRange reads the optional ?from= and ?to= dates
+func parseDateRange(r *http.Request) (time.Time, time.Time, error) {
+	var dates [2]time.Time
+	for i, name := range []string{"from", "to"} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%s must be a date like 2024-05-01", name)
+		}
+		dates[i] = date
+	}
+	return dates[0], dates[1], nil
+}
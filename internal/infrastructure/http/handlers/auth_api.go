@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/alchemorsel/v3/internal/application/user"
+	domainuser "github.com/alchemorsel/v3/internal/domain/user"
 	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
 	"github.com/alchemorsel/v3/internal/infrastructure/security"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -63,9 +65,30 @@ type UserResponse struct {
 	Email    string    `json:"email"`
 	Role     string    `json:"role"`
 	IsActive bool      `json:"is_active"`
+	Theme    string    `json:"theme,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// ThemeRequest is the payload for PUT /api/v1/auth/profile/theme
+type ThemeRequest struct {
+	Theme string `json:"theme"`
+}
+
+// UnitsRequest is the payload for PUT /api/v1/auth/profile/units
+type UnitsRequest struct {
+	Units string `json:"units"`
+}
+
+// PersonalizationRequest is the payload for PUT /api/v1/auth/profile/personalization
+type PersonalizationRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// PrivacyRequest is the payload for PUT /api/v1/auth/profile/privacy
+type PrivacyRequest struct {
+	Private bool `json:"private"`
+}
+
 // Register handles POST /api/v1/auth/register
 func (h *AuthAPIHandlers) Register(w http.ResponseWriter, r *http.Request) {
 	var req RegisterRequest
@@ -223,6 +246,222 @@ func (h *AuthAPIHandlers) UpdateProfile(w http.ResponseWriter, r *http.Request)
 	h.writeJSON(w, http.StatusOK, response)
 }
 
+// UpdateTheme handles PUT /api/v1/auth/profile/theme
+func (h *AuthAPIHandlers) UpdateTheme(w http.ResponseWriter, r *http.Request) {
+	userID, exists := middleware.GetUserIDFromContext(r.Context())
+	if !exists {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user")
+		return
+	}
+
+	var req ThemeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	theme, err := domainuser.ParseTheme(req.Theme)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.userService.SetTheme(r.Context(), id, theme); err != nil {
+		h.logger.Error("Failed to update theme", zap.String("user_id", userID), zap.Error(err))
+		h.writeErrorJSON(w, http.StatusInternalServerError, "Failed to update theme")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    ThemeRequest{Theme: string(theme)},
+		Message: "Theme updated successfully",
+	})
+}
+
+// UpdateUnits handles PUT /api/v1/auth/profile/units. Recipes are shown
+// to the user in these units: metric, imperial, or original for the units
+// the author used.
+func (h *AuthAPIHandlers) UpdateUnits(w http.ResponseWriter, r *http.Request) {
+	userID, exists := middleware.GetUserIDFromContext(r.Context())
+	if !exists {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user")
+		return
+	}
+
+	var req UnitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	system, err := domainuser.ParseMeasurementSystem(req.Units)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.userService.SetMeasurementSystem(r.Context(), id, system); err != nil {
+		h.logger.Error("Failed to update measurement system", zap.String("user_id", userID), zap.Error(err))
+		h.writeErrorJSON(w, http.StatusInternalServerError, "Failed to update units")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    UnitsRequest{Units: string(system)},
+		Message: "Units updated successfully",
+	})
+}
+
+// GetDietaryProfile handles GET /api/v1/auth/profile/diet
+func (h *AuthAPIHandlers) GetDietaryProfile(w http.ResponseWriter, r *http.Request) {
+	userID, exists := middleware.GetUserIDFromContext(r.Context())
+	if !exists {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user")
+		return
+	}
+
+	profile, err := h.userService.GetDietaryProfile(r.Context(), id)
+	if err != nil {
+		h.logger.Error("Failed to load dietary profile", zap.String("user_id", userID), zap.Error(err))
+		h.writeErrorJSON(w, http.StatusInternalServerError, "Failed to load dietary profile")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    profile,
+		Message: "Dietary profile retrieved successfully",
+	})
+}
+
+// UpdateDietaryProfile handles PUT /api/v1/auth/profile/diet. The profile
+// replaces the saved one and applies to search, AI generation and the
+// allergy warnings on recipe cards.
+func (h *AuthAPIHandlers) UpdateDietaryProfile(w http.ResponseWriter, r *http.Request) {
+	userID, exists := middleware.GetUserIDFromContext(r.Context())
+	if !exists {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user")
+		return
+	}
+
+	var req user.DietaryProfile
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	profile, err := req.Normalize()
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.userService.SetDietaryProfile(r.Context(), id, profile); err != nil {
+		h.logger.Error("Failed to update dietary profile", zap.String("user_id", userID), zap.Error(err))
+		h.writeErrorJSON(w, http.StatusInternalServerError, "Failed to update dietary profile")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    profile,
+		Message: "Dietary profile updated successfully",
+	})
+}
+
+// UpdatePersonalization handles PUT /api/v1/auth/profile/personalization
+func (h *AuthAPIHandlers) UpdatePersonalization(w http.ResponseWriter, r *http.Request) {
+	userID, exists := middleware.GetUserIDFromContext(r.Context())
+	if !exists {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user")
+		return
+	}
+
+	var req PersonalizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if err := h.userService.SetPersonalization(r.Context(), id, req.Enabled); err != nil {
+		h.logger.Error("Failed to update personalization", zap.String("user_id", userID), zap.Error(err))
+		h.writeErrorJSON(w, http.StatusInternalServerError, "Failed to update personalization")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    req,
+		Message: "Search personalization updated successfully",
+	})
+}
+
+// UpdatePrivacy handles PUT /api/v1/auth/profile/privacy. A private
+// profile is shown only to the users its owner follows.
+func (h *AuthAPIHandlers) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	userID, exists := middleware.GetUserIDFromContext(r.Context())
+	if !exists {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user")
+		return
+	}
+
+	var req PrivacyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if err := h.userService.SetPrivateProfile(r.Context(), id, req.Private); err != nil {
+		h.logger.Error("Failed to update profile privacy", zap.String("user_id", userID), zap.Error(err))
+		h.writeErrorJSON(w, http.StatusInternalServerError, "Failed to update profile privacy")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    req,
+		Message: "Profile privacy updated successfully",
+	})
+}
+
 // Helper methods
 
 func (h *AuthAPIHandlers) writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -0,0 +1,171 @@
+// Package handlers provides batch read endpoints used by the web frontend
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/application/user"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxBatchSize caps how many IDs a single batchGet call may request
+const maxBatchSize = 100
+
+// BatchAPIHandlers serves :batchGet endpoints that replace per-item fetches
+type BatchAPIHandlers struct {
+	responder
+	recipeService inbound.RecipeService
+	userService   *user.UserService
+}
+
+// NewBatchAPIHandlers creates a new batch API handlers instance
+func NewBatchAPIHandlers(
+	recipeService inbound.RecipeService,
+	userService *user.UserService,
+	logger *zap.Logger,
+) *BatchAPIHandlers {
+	return &BatchAPIHandlers{
+		recipeService: recipeService,
+		userService:   userService,
+		responder:     responder{logger: logger},
+	}
+}
+
+// BatchResult is the payload returned by batchGet endpoints.
+// Items preserve request order; IDs that could not be resolved are listed in NotFound.
+type BatchResult struct {
+	Items    interface{} `json:"items"`
+	NotFound []string    `json:"not_found"`
+}
+
+// UserSummary is the public projection of a user returned by users:batchGet
+type UserSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// BatchGetRecipes handles GET /api/v1/recipes:batchGet?ids=a,b,c
+func (h *BatchAPIHandlers) BatchGetRecipes(w http.ResponseWriter, r *http.Request) {
+	ids, err := parseBatchIDs(r)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sel, err := ParseFieldSelection(r, DefaultRecipeListFields)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	recipes, err := h.recipeService.GetRecipesByIDs(r.Context(), ids)
+	if err != nil {
+		h.logger.Error("Batch recipe lookup failed", zap.Int("count", len(ids)), zap.Error(err))
+		h.writeErrorJSON(w, http.StatusInternalServerError, "Failed to load recipes")
+		return
+	}
+
+	byID := make(map[uuid.UUID]*inbound.RecipeDTO, len(recipes))
+	for i := range recipes {
+		byID[recipes[i].ID] = &recipes[i]
+	}
+
+	items := make([]map[string]interface{}, 0, len(ids))
+	notFound := []string{}
+	for _, id := range ids {
+		dto, ok := byID[id]
+		if !ok {
+			notFound = append(notFound, id.String())
+			continue
+		}
+		shaped, err := ShapeRecipe(dto, sel)
+		if err != nil {
+			h.logger.Error("Failed to shape recipe", zap.Error(err))
+			h.writeErrorJSON(w, http.StatusInternalServerError, "Failed to build response")
+			return
+		}
+		items = append(items, shaped)
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    BatchResult{Items: items, NotFound: notFound},
+		Message: "Recipes retrieved successfully",
+	})
+}
+
+// BatchGetUsers handles GET /api/v1/users:batchGet?ids=a,b,c
+func (h *BatchAPIHandlers) BatchGetUsers(w http.ResponseWriter, r *http.Request) {
+	ids, err := parseBatchIDs(r)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	users, err := h.userService.GetUsersByIDs(r.Context(), ids)
+	if err != nil {
+		h.logger.Error("Batch user lookup failed", zap.Int("count", len(ids)), zap.Error(err))
+		h.writeErrorJSON(w, http.StatusInternalServerError, "Failed to load users")
+		return
+	}
+
+	byID := make(map[uuid.UUID]user.UserDTO, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	items := make([]UserSummary, 0, len(ids))
+	notFound := []string{}
+	for _, id := range ids {
+		u, ok := byID[id]
+		if !ok {
+			notFound = append(notFound, id.String())
+			continue
+		}
+		items = append(items, UserSummary{ID: u.ID.String(), Name: u.Name})
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    BatchResult{Items: items, NotFound: notFound},
+		Message: "Users retrieved successfully",
+	})
+}
+
+// parseBatchIDs reads ?ids= (comma separated or repeated), de-duplicating
+// while keeping the first-seen order
+func parseBatchIDs(r *http.Request) ([]uuid.UUID, error) {
+	var raw []string
+	for _, v := range r.URL.Query()["ids"] {
+		raw = append(raw, splitList(v)...)
+	}
+
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("ids parameter is required")
+	}
+
+	seen := make(map[uuid.UUID]bool, len(raw))
+	ids := make([]uuid.UUID, 0, len(raw))
+	for _, s := range raw {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id %q", s)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if len(ids) > maxBatchSize {
+		return nil, fmt.Errorf("at most %d ids may be requested at once", maxBatchSize)
+	}
+
+	return ids, nil
+}
+
+// Helper methods
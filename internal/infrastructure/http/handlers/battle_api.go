@@ -0,0 +1,212 @@
+// Package handlers provides HTTP handlers for remix battles
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// BattleAPIHandlers serves remix battles between the AI and community
+// chefs
+type BattleAPIHandlers struct {
+	responder
+	battles inbound.BattleService
+}
+
+// NewBattleAPIHandlers creates the battle handlers
+func NewBattleAPIHandlers(battles inbound.BattleService, logger *zap.Logger) *BattleAPIHandlers {
+	return &BattleAPIHandlers{
+		battles:   battles,
+		responder: responder{logger: logger},
+	}
+}
+
+// StartBattleRequest is the prompt both sides cook to
+type StartBattleRequest struct {
+	Prompt string `json:"prompt"`
+}
+
+// ChefEntryRequest is the chef's recipe for their battle
+type ChefEntryRequest struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Ingredients []string `json:"ingredients"`
+	Steps       []string `json:"steps"`
+}
+
+// BattleVoteRequest picks entry "a" or "b"
+type BattleVoteRequest struct {
+	Entry string `json:"entry"`
+}
+
+// StartBattle handles POST /api/v1/battles
+// The AI writes its entry straight away; it stays hidden until the chef
+// submits theirs.
+func (h *BattleAPIHandlers) StartBattle(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req StartBattleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	b, err := h.battles.Start(r.Context(), inbound.StartBattleCommand{ChefID: userID, Prompt: req.Prompt})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    b,
+		Message: "Battle started; submit your entry to open voting",
+	})
+}
+
+// SubmitChefEntry handles PUT /api/v1/battles/{id}/chef-entry
+func (h *BattleAPIHandlers) SubmitChefEntry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req ChefEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	b, err := h.battles.SubmitEntry(r.Context(), inbound.SubmitBattleEntryCommand{
+		ChefID:      userID,
+		BattleID:    chi.URLParam(r, "id"),
+		Title:       req.Title,
+		Description: req.Description,
+		Ingredients: req.Ingredients,
+		Steps:       req.Steps,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    b,
+		Message: "Entry submitted; voting is open",
+	})
+}
+
+// ListBattles handles GET /api/v1/battles?status=voting|closed|open
+func (h *BattleAPIHandlers) ListBattles(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseIntParam(r, "limit", 20)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := parseIntParam(r, "page", 1)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	battles, err := h.battles.List(r.Context(), inbound.ListBattlesQuery{
+		RequesterID: h.requesterID(r),
+		Status:      r.URL.Query().Get("status"),
+		Limit:       limit,
+		Offset:      (page - 1) * limit,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    battles,
+		Message: "Battles retrieved successfully",
+	})
+}
+
+// GetBattle handles GET /api/v1/battles/{id}
+func (h *BattleAPIHandlers) GetBattle(w http.ResponseWriter, r *http.Request) {
+	b, err := h.battles.Get(r.Context(), inbound.BattleQuery{
+		RequesterID: h.requesterID(r),
+		BattleID:    chi.URLParam(r, "id"),
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    b,
+		Message: "Battle retrieved successfully",
+	})
+}
+
+// VoteBattle handles POST /api/v1/battles/{id}/votes
+// Body: {"entry": "a"}. Each reader votes once; the chef may not vote.
+func (h *BattleAPIHandlers) VoteBattle(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req BattleVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	b, err := h.battles.Vote(r.Context(), inbound.BattleVoteCommand{
+		UserID:   userID,
+		BattleID: chi.URLParam(r, "id"),
+		Entry:    req.Entry,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    b,
+		Message: "Vote recorded",
+	})
+}
+
+// BattleTemplates handles GET /api/v1/battles/templates
+func (h *BattleAPIHandlers) BattleTemplates(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.battles.Templates(r.Context())
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    stats,
+		Message: "Battle templates retrieved successfully",
+	})
+}
+
+// requesterID is the signed-in reader, or uuid.Nil
+func (h *BattleAPIHandlers) requesterID(r *http.Request) uuid.UUID {
+	if raw, exists := middleware.GetUserIDFromContext(r.Context()); exists {
+		if id, err := uuid.Parse(raw); err == nil {
+			return id
+		}
+	}
+	return uuid.Nil
+}
@@ -0,0 +1,111 @@
+// Package handlers provides the tag and cuisine browse endpoints
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// BrowseAPIHandlers serves the browse pages from precomputed summaries
+type BrowseAPIHandlers struct {
+	responder
+	browse     inbound.BrowseService
+	popularity inbound.PopularityService
+}
+
+// NewBrowseAPIHandlers creates the browse handlers
+func NewBrowseAPIHandlers(browse inbound.BrowseService, popularity inbound.PopularityService, logger *zap.Logger) *BrowseAPIHandlers {
+	return &BrowseAPIHandlers{
+		browse:     browse,
+		popularity: popularity,
+		responder:  responder{logger: logger},
+	}
+}
+
+// ListTags handles GET /api/v1/browse/tags
+func (h *BrowseAPIHandlers) ListTags(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tags, err := h.browse.ListTags(r.Context(), limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    tags,
+		Message: "Tags retrieved successfully",
+	})
+}
+
+// ListCuisines handles GET /api/v1/browse/cuisines
+func (h *BrowseAPIHandlers) ListCuisines(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cuisines, err := h.browse.ListCuisines(r.Context(), limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    cuisines,
+		Message: "Cuisines retrieved successfully",
+	})
+}
+
+// TopRatedByCuisine handles GET /api/v1/browse/cuisines/{cuisine}/top-rated
+func (h *BrowseAPIHandlers) TopRatedByCuisine(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	top, err := h.browse.TopRatedByCuisine(r.Context(), chi.URLParam(r, "cuisine"), limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    top,
+		Message: "Top rated recipes retrieved successfully",
+	})
+}
+
+// HiddenGems handles GET /api/v1/browse/hidden-gems
+// Lists the well rated, rarely viewed recipes of the current rotation.
+func (h *BrowseAPIHandlers) HiddenGems(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	gems, err := h.popularity.HiddenGems(r.Context(), limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    gems,
+		Message: "Hidden gems retrieved successfully",
+	})
+}
@@ -0,0 +1,65 @@
+// Package handlers provides the admin endpoints for cache warmup
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"go.uber.org/zap"
+)
+
+// CacheAPIHandlers triggers cache warmups and reports their progress
+type CacheAPIHandlers struct {
+	responder
+	warmup inbound.CacheWarmupService
+}
+
+// NewCacheAPIHandlers creates the cache warmup handlers
+func NewCacheAPIHandlers(warmup inbound.CacheWarmupService, logger *zap.Logger) *CacheAPIHandlers {
+	return &CacheAPIHandlers{
+		warmup:    warmup,
+		responder: responder{logger: logger},
+	}
+}
+
+// WarmCaches handles POST /api/v1/admin/cache/warm
+// Starts a warmup in the background and returns its progress; poll
+// GET /api/v1/admin/cache/warm until it is done.
+func (h *CacheAPIHandlers) WarmCaches(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.warmup.WarmCaches(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, APIResponse{
+		Success: true,
+		Data:    report,
+		Message: "Cache warmup started",
+	})
+}
+
+// WarmupReport handles GET /api/v1/admin/cache/warm
+func (h *CacheAPIHandlers) WarmupReport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.warmup.WarmupReport(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    report,
+		Message: "Cache warmup progress retrieved successfully",
+	})
+}
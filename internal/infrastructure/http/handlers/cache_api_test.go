@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// adminOnlyWarmup answers warmup requests from its one admin and refuses
+// everyone else
+type adminOnlyWarmup struct {
+	inbound.CacheWarmupService
+	admin uuid.UUID
+}
+
+func (s *adminOnlyWarmup) WarmCaches(ctx context.Context, requesterID uuid.UUID) (*inbound.CacheWarmupReport, error) {
+	return s.WarmupReport(ctx, requesterID)
+}
+
+func (s *adminOnlyWarmup) WarmupReport(ctx context.Context, requesterID uuid.UUID) (*inbound.CacheWarmupReport, error) {
+	if requesterID != s.admin {
+		return nil, errors.NewInsufficientPermissionsError("view cache warmups")
+	}
+	return &inbound.CacheWarmupReport{Status: inbound.WarmupIdle}, nil
+}
+
+func TestCacheWarmupRequiresAdmin(t *testing.T) {
+	admin, cook := uuid.New(), uuid.New()
+	h := NewCacheAPIHandlers(&adminOnlyWarmup{admin: admin}, zap.NewNop())
+
+	tests := []struct {
+		name     string
+		method   string
+		handler  http.HandlerFunc
+		user     uuid.UUID
+		wantCode int
+	}{
+		{"admin reads progress", http.MethodGet, h.WarmupReport, admin, http.StatusOK},
+		{"non-admin reads progress", http.MethodGet, h.WarmupReport, cook, http.StatusForbidden},
+		{"admin starts a warmup", http.MethodPost, h.WarmCaches, admin, http.StatusAccepted},
+		{"non-admin starts a warmup", http.MethodPost, h.WarmCaches, cook, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/admin/cache/warm", nil)
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), tt.user.String(), "", nil))
+			w := httptest.NewRecorder()
+			tt.handler(w, req)
+			assert.Equal(t, tt.wantCode, w.Code, w.Body.String())
+		})
+	}
+}
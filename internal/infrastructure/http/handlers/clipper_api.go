@@ -0,0 +1,104 @@
+// Package handlers provides the browser extension's clip endpoint
+package handlers
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/infrastructure/recipeimport"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ClipRequest is the payload for POST /api/v1/recipes/clip: the page
+// address and the HTML the extension extracted, either the whole document
+// or the recipe's part of it
+type ClipRequest struct {
+	URL  string `json:"url"`
+	HTML string `json:"html"`
+}
+
+// ClipperAPIHandlers serves the browser extension
+type ClipperAPIHandlers struct {
+	responder
+	clipper     inbound.ClipperService
+	maxPageSize int64
+}
+
+// NewClipperAPIHandlers creates the clipper handlers. Bodies whose HTML is
+// larger than maxPageSize bytes are refused.
+func NewClipperAPIHandlers(clipper inbound.ClipperService, maxPageSize int, logger *zap.Logger) *ClipperAPIHandlers {
+	return &ClipperAPIHandlers{
+		clipper:     clipper,
+		maxPageSize: int64(maxPageSize),
+		responder:   responder{logger: logger},
+	}
+}
+
+// ClipRecipe handles POST /api/v1/recipes/clip. The page is read for
+// schema.org recipe data, falling back to its headings and lists, and
+// saved as a draft the user reviews at review_url.
+func (h *ClipperAPIHandlers) ClipRecipe(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// JSON escaping can double the size of markup
+	r.Body = http.MaxBytesReader(w, r.Body, 2*h.maxPageSize+4<<10)
+	var req ClipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if stderrors.As(err, &tooLarge) {
+			h.writeErrorJSON(w, http.StatusRequestEntityTooLarge, "The clipped page is too large")
+			return
+		}
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if strings.TrimSpace(req.HTML) == "" {
+		h.writeErrorJSON(w, http.StatusBadRequest, "html is required")
+		return
+	}
+	if int64(len(req.HTML)) > h.maxPageSize {
+		h.writeErrorJSON(w, http.StatusRequestEntityTooLarge, "The clipped page is too large")
+		return
+	}
+
+	recipe, method, err := recipeimport.FromHTML(req.URL, []byte(req.HTML))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnprocessableEntity, "No recipe was found in the clipped page")
+		return
+	}
+
+	result, err := h.clipper.Clip(r.Context(), inbound.ClipCommand{
+		UserID:  userID,
+		PageURL: req.URL,
+		Method:  method,
+		Recipe:  recipe,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	status, message := http.StatusCreated, "Recipe clipped as a draft"
+	if result.Status == "duplicate" {
+		status, message = http.StatusOK, "You already have this recipe"
+	}
+	h.writeJSON(w, status, APIResponse{
+		Success: true,
+		Data:    result,
+		Message: message,
+	})
+}
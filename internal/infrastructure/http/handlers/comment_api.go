@@ -0,0 +1,577 @@
+// Package handlers provides recipe comments, reviews and the inbound email
+// webhooks that turn notification replies into comments
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderInboundSignature carries "sha256=" and the hex HMAC-SHA256 of the
+	// webhook body, keyed with the email inbound secret
+	HeaderInboundSignature = "X-Alchemorsel-Signature"
+	// maxInboundEmailBytes bounds a parsed inbound email
+	maxInboundEmailBytes = 1 << 20
+	// maxCommentBytes bounds comment and review bodies
+	maxCommentBytes = 16 << 10
+)
+
+// CommentAPIHandlers serves comments and reviews and receives email replies
+type CommentAPIHandlers struct {
+	responder
+	recipeService inbound.RecipeService
+	comments      inbound.CommentService
+	inboundSecret string
+}
+
+// NewCommentAPIHandlers creates the comment handlers. An empty inbound
+// secret disables the email webhooks.
+func NewCommentAPIHandlers(
+	recipeService inbound.RecipeService,
+	comments inbound.CommentService,
+	inboundSecret string,
+	logger *zap.Logger,
+) *CommentAPIHandlers {
+	return &CommentAPIHandlers{
+		recipeService: recipeService,
+		comments:      comments,
+		inboundSecret: inboundSecret,
+		responder:     responder{logger: logger},
+	}
+}
+
+// AddCommentRequest posts a comment, optionally answering another one
+type AddCommentRequest struct {
+	Content  string     `json:"content"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// EditCommentRequest replaces a comment's text
+type EditCommentRequest struct {
+	Content string `json:"content"`
+}
+
+// FlagCommentRequest flags a comment for moderators
+type FlagCommentRequest struct {
+	Reason string `json:"reason"`
+	Note   string `json:"note"`
+}
+
+// RateRecipeRequest rates a recipe from 1 to 5 with an optional review
+type RateRecipeRequest struct {
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+// ReactRequest adds a reaction to a comment
+type ReactRequest struct {
+	Kind string `json:"kind"`
+}
+
+// ReviewVoteRequest says whether a review was helpful
+type ReviewVoteRequest struct {
+	Helpful bool `json:"helpful"`
+}
+
+// InboundEmailRequest is an email received on a reply address, as parsed
+// by the mail provider's inbound route
+type InboundEmailRequest struct {
+	From        string            `json:"from"`
+	To          []string          `json:"to"`
+	Subject     string            `json:"subject"`
+	MessageID   string            `json:"message_id"`
+	Text        string            `json:"text"`
+	Headers     map[string]string `json:"headers"`
+	SpamScore   *float64          `json:"spam_score"`
+	SpamVerdict string            `json:"spam_verdict"`
+}
+
+// EmailEventsRequest is a batch of delivery events from the mail provider
+type EmailEventsRequest struct {
+	Events []EmailEvent `json:"events"`
+}
+
+// EmailEvent is a bounce or spam complaint about one address
+type EmailEvent struct {
+	Type      string `json:"type"`
+	Email     string `json:"email"`
+	Permanent bool   `json:"permanent"`
+	Detail    string `json:"detail"`
+}
+
+// ListComments handles GET /api/v1/recipes/{id}/comments
+// Returns threads oldest first; responses to reviews carry review_user_id.
+// Signed-in readers also see which reactions are theirs.
+func (h *CommentAPIHandlers) ListComments(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	comments, err := h.comments.ListComments(r.Context(), recipeID, viewerID(r))
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    comments,
+		Message: "Comments retrieved successfully",
+	})
+}
+
+// AddComment handles POST /api/v1/recipes/{id}/comments
+func (h *CommentAPIHandlers) AddComment(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	var req AddCommentRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCommentBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	comment, err := h.comments.AddComment(r.Context(), inbound.AddCommentCommand{
+		RecipeID: recipeID,
+		UserID:   userID,
+		ParentID: req.ParentID,
+		Content:  req.Content,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    comment,
+		Message: "Comment posted",
+	})
+}
+
+// EditComment handles PUT /api/v1/recipes/{id}/comments/{commentID}
+func (h *CommentAPIHandlers) EditComment(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.reactCommand(w, r)
+	if !ok {
+		return
+	}
+	var req EditCommentRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCommentBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	c, err := h.comments.EditComment(r.Context(), inbound.EditCommentCommand{
+		RecipeID:  target.RecipeID,
+		CommentID: target.CommentID,
+		UserID:    target.UserID,
+		Content:   req.Content,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    c,
+		Message: "Comment updated",
+	})
+}
+
+// DeleteComment handles DELETE /api/v1/recipes/{id}/comments/{commentID}
+// Authors delete their own comments; admins may delete any.
+func (h *CommentAPIHandlers) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.reactCommand(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.comments.DeleteComment(r.Context(), inbound.DeleteCommentCommand{
+		RecipeID:  target.RecipeID,
+		CommentID: target.CommentID,
+		UserID:    target.UserID,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Comment deleted",
+	})
+}
+
+// FlagComment handles POST /api/v1/recipes/{id}/comments/{commentID}/flag
+func (h *CommentAPIHandlers) FlagComment(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.reactCommand(w, r)
+	if !ok {
+		return
+	}
+	var req FlagCommentRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCommentBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	err := h.comments.FlagComment(r.Context(), inbound.FlagCommentCommand{
+		RecipeID:  target.RecipeID,
+		CommentID: target.CommentID,
+		UserID:    target.UserID,
+		Reason:    req.Reason,
+		Note:      req.Note,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Thanks, a moderator will take a look",
+	})
+}
+
+// RateRecipe handles POST /api/v1/recipes/{id}/rating
+// Saves the rating and emails the author, who can answer by replying.
+func (h *CommentAPIHandlers) RateRecipe(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	var req RateRecipeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCommentBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if req.Rating < 1 || req.Rating > 5 {
+		h.writeErrorJSON(w, http.StatusBadRequest, "rating must be between 1 and 5")
+		return
+	}
+
+	cmd := inbound.RateRecipeCommand{
+		RecipeID: recipeID,
+		UserID:   userID,
+		Rating:   req.Rating,
+		Comment:  strings.TrimSpace(req.Comment),
+	}
+	if err := h.recipeService.RateRecipe(r.Context(), cmd); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	if err := h.comments.NotifyReview(r.Context(), cmd); err != nil {
+		// The rating is saved; only the author's email was missed
+		h.logger.Error("Failed to notify author of review", zap.Error(err))
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Recipe rated successfully",
+	})
+}
+
+// React handles POST /api/v1/recipes/{id}/comments/{commentID}/reactions
+func (h *CommentAPIHandlers) React(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := h.reactCommand(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	cmd.Kind = req.Kind
+
+	reactions, err := h.comments.React(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    reactions,
+		Message: "Reaction added",
+	})
+}
+
+// Unreact handles DELETE /api/v1/recipes/{id}/comments/{commentID}/reactions/{kind}
+func (h *CommentAPIHandlers) Unreact(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := h.reactCommand(w, r)
+	if !ok {
+		return
+	}
+	cmd.Kind = chi.URLParam(r, "kind")
+
+	reactions, err := h.comments.Unreact(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    reactions,
+		Message: "Reaction removed",
+	})
+}
+
+// ListReviews handles GET /api/v1/recipes/{id}/reviews?sort=helpful|newest
+func (h *CommentAPIHandlers) ListReviews(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	reviews, err := h.comments.ListReviews(r.Context(), inbound.ListReviewsQuery{
+		RecipeID: recipeID,
+		ViewerID: viewerID(r),
+		Sort:     r.URL.Query().Get("sort"),
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    reviews,
+		Message: "Reviews retrieved successfully",
+	})
+}
+
+// VoteReview handles PUT /api/v1/recipes/{id}/reviews/{userID}/vote
+// Replaces the reader's earlier vote on the review.
+func (h *CommentAPIHandlers) VoteReview(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := h.reviewVoteCommand(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReviewVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	cmd.Helpful = req.Helpful
+
+	review, err := h.comments.VoteReview(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    review,
+		Message: "Vote recorded",
+	})
+}
+
+// ClearReviewVote handles DELETE /api/v1/recipes/{id}/reviews/{userID}/vote
+func (h *CommentAPIHandlers) ClearReviewVote(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := h.reviewVoteCommand(w, r)
+	if !ok {
+		return
+	}
+
+	review, err := h.comments.ClearReviewVote(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    review,
+		Message: "Vote removed",
+	})
+}
+
+// InboundEmail handles POST /api/v1/email/inbound
+// Called by the mail provider for mail to reply addresses. Every handled
+// outcome, including rejected spam, answers 200 so the provider does not
+// retry; only server errors do.
+func (h *CommentAPIHandlers) InboundEmail(w http.ResponseWriter, r *http.Request) {
+	body, ok := h.verifiedBody(w, r)
+	if !ok {
+		return
+	}
+
+	var req InboundEmailRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	result, err := h.comments.HandleInboundReply(r.Context(), inbound.InboundEmail{
+		From:        req.From,
+		To:          req.To,
+		Subject:     req.Subject,
+		MessageID:   req.MessageID,
+		Text:        req.Text,
+		Headers:     req.Headers,
+		SpamScore:   req.SpamScore,
+		SpamVerdict: req.SpamVerdict,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    result,
+		Message: "Email processed",
+	})
+}
+
+// EmailEvents handles POST /api/v1/email/events
+// Hard bounces and spam complaints stop further notifications to the address.
+func (h *CommentAPIHandlers) EmailEvents(w http.ResponseWriter, r *http.Request) {
+	body, ok := h.verifiedBody(w, r)
+	if !ok {
+		return
+	}
+
+	var req EmailEventsRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	for _, event := range req.Events {
+		err := h.comments.HandleDeliveryEvent(r.Context(), inbound.EmailDeliveryEvent{
+			Type:      event.Type,
+			Email:     event.Email,
+			Permanent: event.Permanent,
+			Detail:    event.Detail,
+		})
+		if err != nil {
+			h.writeServiceError(w, err)
+			return
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Events processed",
+	})
+}
+
+// verifiedBody reads a webhook body and checks its signature
+func (h *CommentAPIHandlers) verifiedBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if h.inboundSecret == "" {
+		h.writeErrorJSON(w, http.StatusServiceUnavailable, "Inbound email is not configured")
+		return nil, false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInboundEmailBytes))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusRequestEntityTooLarge, "Email too large")
+		return nil, false
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(r.Header.Get(HeaderInboundSignature), "sha256="))
+	mac := hmac.New(sha256.New, []byte(h.inboundSecret))
+	mac.Write(body)
+	if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+		h.logger.Warn("Inbound email webhook with invalid signature", zap.String("remote_addr", r.RemoteAddr))
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid signature")
+		return nil, false
+	}
+
+	return body, true
+}
+
+// reactCommand reads the signed-in user and the comment from the path
+func (h *CommentAPIHandlers) reactCommand(w http.ResponseWriter, r *http.Request) (inbound.ReactCommand, bool) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return inbound.ReactCommand{}, false
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return inbound.ReactCommand{}, false
+	}
+	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid comment ID")
+		return inbound.ReactCommand{}, false
+	}
+	return inbound.ReactCommand{RecipeID: recipeID, CommentID: commentID, UserID: userID}, true
+}
+
+// reviewVoteCommand reads the signed-in voter and the review from the path
+func (h *CommentAPIHandlers) reviewVoteCommand(w http.ResponseWriter, r *http.Request) (inbound.ReviewVoteCommand, bool) {
+	voterID, ok := h.userID(w, r)
+	if !ok {
+		return inbound.ReviewVoteCommand{}, false
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return inbound.ReviewVoteCommand{}, false
+	}
+	reviewerID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid reviewer ID")
+		return inbound.ReviewVoteCommand{}, false
+	}
+	return inbound.ReviewVoteCommand{RecipeID: recipeID, ReviewerID: reviewerID, VoterID: voterID}, true
+}
+
+// viewerID is the signed-in reader on optionally authenticated routes, or
+// uuid.Nil
+func viewerID(r *http.Request) uuid.UUID {
+	if raw, exists := middleware.GetUserIDFromContext(r.Context()); exists {
+		if id, err := uuid.Parse(raw); err == nil {
+			return id
+		}
+	}
+	return uuid.Nil
+}
@@ -0,0 +1,386 @@
+// Package handlers provides the moderation tools recipe authors have over
+// comments on their recipes, and the admin queues of hidden and flagged
+// comments
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ModerationReasonRequest carries the optional reason for a moderation
+// action
+type ModerationReasonRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ReviewHiddenCommentRequest is a moderator's verdict on a hidden comment
+type ReviewHiddenCommentRequest struct {
+	Restore bool   `json:"restore"`
+	Reason  string `json:"reason"`
+}
+
+// ReviewFlaggedCommentRequest is a moderator's verdict on a flagged comment
+type ReviewFlaggedCommentRequest struct {
+	Hide   bool   `json:"hide"`
+	Reason string `json:"reason"`
+}
+
+// Discussion handles GET /api/v1/recipes/{id}/discussion
+func (h *CommentAPIHandlers) Discussion(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	discussion, err := h.comments.Discussion(r.Context(), recipeID, viewerID(r))
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    discussion,
+		Message: "Discussion retrieved successfully",
+	})
+}
+
+// LockDiscussion handles PUT /api/v1/recipes/{id}/discussion/lock
+func (h *CommentAPIHandlers) LockDiscussion(w http.ResponseWriter, r *http.Request) {
+	h.setDiscussionLock(w, r, true)
+}
+
+// UnlockDiscussion handles DELETE /api/v1/recipes/{id}/discussion/lock
+func (h *CommentAPIHandlers) UnlockDiscussion(w http.ResponseWriter, r *http.Request) {
+	h.setDiscussionLock(w, r, false)
+}
+
+func (h *CommentAPIHandlers) setDiscussionLock(w http.ResponseWriter, r *http.Request, locked bool) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+	req, ok := h.reasonRequest(w, r)
+	if !ok {
+		return
+	}
+
+	discussion, err := h.comments.LockDiscussion(r.Context(), inbound.LockDiscussionCommand{
+		RecipeID: recipeID,
+		UserID:   userID,
+		Locked:   locked,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	message := "Comments unlocked"
+	if locked {
+		message = "Comments locked"
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    discussion,
+		Message: message,
+	})
+}
+
+// HideComment handles POST /api/v1/recipes/{id}/comments/{commentID}/hide
+// The recipe's author hides the comment until a moderator reviews it.
+func (h *CommentAPIHandlers) HideComment(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.reactCommand(w, r)
+	if !ok {
+		return
+	}
+	req, ok := h.reasonRequest(w, r)
+	if !ok {
+		return
+	}
+
+	c, err := h.comments.HideComment(r.Context(), inbound.HideCommentCommand{
+		RecipeID:  target.RecipeID,
+		CommentID: target.CommentID,
+		UserID:    target.UserID,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    c,
+		Message: "Comment hidden pending review",
+	})
+}
+
+// ListBlockedCommenters handles GET /api/v1/comment-moderation/blocks
+func (h *CommentAPIHandlers) ListBlockedCommenters(w http.ResponseWriter, r *http.Request) {
+	authorID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	blocks, err := h.comments.ListBlockedCommenters(r.Context(), authorID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    blocks,
+		Message: "Blocked commenters retrieved successfully",
+	})
+}
+
+// BlockCommenter handles PUT /api/v1/comment-moderation/blocks/{userID}
+func (h *CommentAPIHandlers) BlockCommenter(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := h.blockCommand(w, r)
+	if !ok {
+		return
+	}
+
+	block, err := h.comments.BlockCommenter(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    block,
+		Message: "User blocked from commenting on your recipes",
+	})
+}
+
+// UnblockCommenter handles DELETE /api/v1/comment-moderation/blocks/{userID}
+func (h *CommentAPIHandlers) UnblockCommenter(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := h.blockCommand(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.comments.UnblockCommenter(r.Context(), cmd); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "User unblocked",
+	})
+}
+
+// ModerationLog handles GET /api/v1/comment-moderation/events?recipe_id=&limit=
+// Authors see their own actions, or everything on one of their recipes;
+// admins see all events.
+func (h *CommentAPIHandlers) ModerationLog(w http.ResponseWriter, r *http.Request) {
+	requesterID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	query := inbound.ModerationLogQuery{RequesterID: requesterID}
+	if raw := r.URL.Query().Get("recipe_id"); raw != "" {
+		recipeID, err := uuid.Parse(raw)
+		if err != nil {
+			h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+			return
+		}
+		query.RecipeID = &recipeID
+	}
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query.Limit = limit
+
+	events, err := h.comments.ModerationLog(r.Context(), query)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    events,
+		Message: "Moderation events retrieved successfully",
+	})
+}
+
+// ListHiddenComments handles GET /api/v1/admin/comments/hidden?limit=
+func (h *CommentAPIHandlers) ListHiddenComments(w http.ResponseWriter, r *http.Request) {
+	requesterID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comments, err := h.comments.ListHiddenComments(r.Context(), requesterID, limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    comments,
+		Message: "Hidden comments retrieved successfully",
+	})
+}
+
+// ReviewHiddenComment handles POST /api/v1/admin/comments/{commentID}/review
+func (h *CommentAPIHandlers) ReviewHiddenComment(w http.ResponseWriter, r *http.Request) {
+	moderatorID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	var req ReviewHiddenCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	c, err := h.comments.ReviewHiddenComment(r.Context(), inbound.ReviewHiddenCommentCommand{
+		CommentID:   commentID,
+		ModeratorID: moderatorID,
+		Restore:     req.Restore,
+		Reason:      req.Reason,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	message := "Comment removed"
+	if req.Restore {
+		message = "Comment restored"
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    c,
+		Message: message,
+	})
+}
+
+// ListFlaggedComments handles GET /api/v1/admin/comments/flagged?limit=
+func (h *CommentAPIHandlers) ListFlaggedComments(w http.ResponseWriter, r *http.Request) {
+	requesterID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	flagged, err := h.comments.ListFlaggedComments(r.Context(), requesterID, limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    flagged,
+		Message: "Flagged comments retrieved successfully",
+	})
+}
+
+// ReviewFlaggedComment handles POST /api/v1/admin/comments/{commentID}/flags/review
+// Hiding takes the comment out of view for good; otherwise its flags are
+// dismissed.
+func (h *CommentAPIHandlers) ReviewFlaggedComment(w http.ResponseWriter, r *http.Request) {
+	moderatorID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	var req ReviewFlaggedCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	c, err := h.comments.ReviewFlaggedComment(r.Context(), inbound.ReviewFlaggedCommentCommand{
+		CommentID:   commentID,
+		ModeratorID: moderatorID,
+		Hide:        req.Hide,
+		Reason:      req.Reason,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	message := "Flags dismissed"
+	if req.Hide {
+		message = "Comment hidden"
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    c,
+		Message: message,
+	})
+}
+
+// blockCommand reads the signed-in author and the user from the path
+func (h *CommentAPIHandlers) blockCommand(w http.ResponseWriter, r *http.Request) (inbound.BlockCommenterCommand, bool) {
+	authorID, ok := h.userID(w, r)
+	if !ok {
+		return inbound.BlockCommenterCommand{}, false
+	}
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid user ID")
+		return inbound.BlockCommenterCommand{}, false
+	}
+	req, ok := h.reasonRequest(w, r)
+	if !ok {
+		return inbound.BlockCommenterCommand{}, false
+	}
+	return inbound.BlockCommenterCommand{AuthorID: authorID, UserID: userID, Reason: req.Reason}, true
+}
+
+// reasonRequest reads the optional reason body; an empty body gives none
+func (h *CommentAPIHandlers) reasonRequest(w http.ResponseWriter, r *http.Request) (ModerationReasonRequest, bool) {
+	var req ModerationReasonRequest
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCommentBytes)).Decode(&req)
+	if err != nil && err != io.EOF {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return req, false
+	}
+	return req, true
+}
@@ -0,0 +1,44 @@
+// Package handlers provides the effective configuration endpoint
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"go.uber.org/zap"
+)
+
+// ConfigAPIHandlers serves the effective configuration reference
+type ConfigAPIHandlers struct {
+	responder
+	config inbound.ConfigService
+}
+
+// NewConfigAPIHandlers creates the configuration handlers
+func NewConfigAPIHandlers(config inbound.ConfigService, logger *zap.Logger) *ConfigAPIHandlers {
+	return &ConfigAPIHandlers{
+		config:    config,
+		responder: responder{logger: logger},
+	}
+}
+
+// EffectiveConfig handles GET /api/v1/admin/config
+// Lists every key with its value, source, default and rules; secrets are redacted.
+func (h *ConfigAPIHandlers) EffectiveConfig(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	reference, err := h.config.EffectiveConfig(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    reference,
+		Message: "Effective configuration retrieved successfully",
+	})
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ResolveDuplicateRequest is an admin's verdict on a duplicate flag.
+// Verdict is uphold or dismiss.
+type ResolveDuplicateRequest struct {
+	Verdict string `json:"verdict"`
+	Note    string `json:"note"`
+}
+
+// DuplicateAppealRequest is an author's appeal of an upheld flag
+type DuplicateAppealRequest struct {
+	Note string `json:"note"`
+}
+
+// DecideDuplicateAppealRequest is an admin's decision on an appeal.
+// Decision is grant or deny.
+type DecideDuplicateAppealRequest struct {
+	Decision string `json:"decision"`
+}
+
+// DuplicateAPIHandlers serves the duplicate flag queue and authors' appeals
+type DuplicateAPIHandlers struct {
+	responder
+	duplicates inbound.DuplicateService
+}
+
+// NewDuplicateAPIHandlers creates the duplicate flag handlers
+func NewDuplicateAPIHandlers(duplicates inbound.DuplicateService, logger *zap.Logger) *DuplicateAPIHandlers {
+	return &DuplicateAPIHandlers{
+		duplicates: duplicates,
+		responder:  responder{logger: logger},
+	}
+}
+
+// ListFlags handles GET /api/v1/admin/duplicates?page=&limit=
+func (h *DuplicateAPIHandlers) ListFlags(w http.ResponseWriter, r *http.Request) {
+	requesterID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 50)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := parseIntParam(r, "page", 1)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	flags, err := h.duplicates.ListFlags(r.Context(), requesterID, limit, (page-1)*limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    flags,
+		Message: "Duplicate flags retrieved successfully",
+	})
+}
+
+// ResolveFlag handles POST /api/v1/admin/duplicates/{id}/resolve
+func (h *DuplicateAPIHandlers) ResolveFlag(w http.ResponseWriter, r *http.Request) {
+	moderatorID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	flagID, ok := h.flagID(w, r)
+	if !ok {
+		return
+	}
+
+	var req ResolveDuplicateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if req.Verdict != "uphold" && req.Verdict != "dismiss" {
+		h.writeErrorJSON(w, http.StatusBadRequest, "verdict must be uphold or dismiss")
+		return
+	}
+
+	flag, err := h.duplicates.ResolveFlag(r.Context(), inbound.ResolveDuplicateCommand{
+		FlagID:      flagID,
+		ModeratorID: moderatorID,
+		Uphold:      req.Verdict == "uphold",
+		Note:        req.Note,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    flag,
+		Message: "Duplicate flag " + flag.Status,
+	})
+}
+
+// DecideAppeal handles POST /api/v1/admin/duplicates/{id}/appeal
+func (h *DuplicateAPIHandlers) DecideAppeal(w http.ResponseWriter, r *http.Request) {
+	moderatorID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	flagID, ok := h.flagID(w, r)
+	if !ok {
+		return
+	}
+
+	var req DecideDuplicateAppealRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if req.Decision != "grant" && req.Decision != "deny" {
+		h.writeErrorJSON(w, http.StatusBadRequest, "decision must be grant or deny")
+		return
+	}
+
+	flag, err := h.duplicates.DecideAppeal(r.Context(), inbound.DecideDuplicateAppealCommand{
+		FlagID:      flagID,
+		ModeratorID: moderatorID,
+		Grant:       req.Decision == "grant",
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    flag,
+		Message: "Appeal " + flag.Appeal,
+	})
+}
+
+// AppealFlag handles POST /api/v1/recipes/{id}/duplicate-appeal
+func (h *DuplicateAPIHandlers) AppealFlag(w http.ResponseWriter, r *http.Request) {
+	authorID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	var req DuplicateAppealRequest
+	err = json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportBytes)).Decode(&req)
+	if err != nil && err != io.EOF {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	flag, err := h.duplicates.AppealFlag(r.Context(), inbound.AppealDuplicateCommand{
+		RecipeID: recipeID,
+		AuthorID: authorID,
+		Note:     req.Note,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    flag,
+		Message: "Appeal received",
+	})
+}
+
+func (h *DuplicateAPIHandlers) flagID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	flagID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid duplicate flag ID")
+		return uuid.Nil, false
+	}
+	return flagID, true
+}
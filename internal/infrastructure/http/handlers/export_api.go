@@ -0,0 +1,152 @@
+// Package handlers provides the bulk export and recipe download endpoints
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/alchemorsel/v3/pkg/stream"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// recipeExportHeader is the CSV header of a recipe export
+var recipeExportHeader = []string{
+	"id", "title", "author_id", "status", "language", "cuisine", "category", "difficulty",
+	"prep_time_minutes", "cook_time_minutes", "servings", "calories",
+	"likes", "views", "average_rating", "ai_generated",
+	"created_at", "updated_at", "published_at", "allergens", "may_contain",
+}
+
+// ExportAPIHandlers streams bulk exports to admins
+type ExportAPIHandlers struct {
+	responder
+	exports inbound.ExportService
+}
+
+// NewExportAPIHandlers creates the export handlers
+func NewExportAPIHandlers(exports inbound.ExportService, logger *zap.Logger) *ExportAPIHandlers {
+	return &ExportAPIHandlers{
+		exports:   exports,
+		responder: responder{logger: logger},
+	}
+}
+
+// exportStream is what the handlers need from a pkg/stream writer
+type exportStream interface {
+	Started() bool
+	Rows() int
+	Close(failure error) error
+}
+
+// ExportRecipes handles GET /api/v1/admin/exports/recipes?format=&status=&updated_since=
+func (h *ExportAPIHandlers) ExportRecipes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	query := inbound.RecipeExportQuery{Status: r.URL.Query().Get("status")}
+	if raw := r.URL.Query().Get("updated_since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			h.writeErrorJSON(w, http.StatusBadRequest, "updated_since must be an RFC 3339 time")
+			return
+		}
+		query.UpdatedSince = &since
+	}
+
+	filename := "recipes-" + time.Now().UTC().Format("20060102")
+	var (
+		out   exportStream
+		write func(*inbound.ExportedRecipe) error
+	)
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "ndjson":
+		ndjson := stream.NewNDJSON(w, stream.Options{Filename: filename + ".ndjson"})
+		out = ndjson
+		write = func(recipe *inbound.ExportedRecipe) error { return ndjson.Write(recipe) }
+	case "csv":
+		table := stream.NewCSV(w, recipeExportHeader, stream.Options{Filename: filename + ".csv"})
+		out = table
+		write = func(recipe *inbound.ExportedRecipe) error { return table.Write(recipeExportRecord(recipe)) }
+	default:
+		h.writeErrorJSON(w, http.StatusBadRequest, "format must be ndjson or csv")
+		return
+	}
+
+	err := h.exports.ExportRecipes(r.Context(), userID, query, write)
+	if err != nil && !out.Started() {
+		h.writeServiceError(w, err)
+		return
+	}
+	if closeErr := out.Close(err); closeErr != nil {
+		h.logger.Debug("Recipe export not delivered", zap.Int("rows", out.Rows()), zap.Error(closeErr))
+	}
+}
+
+// ExportRecipe handles GET /api/v1/recipes/{id}/export?format=pdf|json|markdown
+// The recipe is laid out for print, with its nutrition and credits, and
+// sent as a download. Drafts are only found by their author.
+func (h *ExportAPIHandlers) ExportRecipe(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+	query := inbound.RecipeFileQuery{RecipeID: recipeID, Format: r.URL.Query().Get("format")}
+	if raw, exists := middleware.GetUserIDFromContext(r.Context()); exists {
+		if id, err := uuid.Parse(raw); err == nil {
+			query.RequesterID = id
+		}
+	}
+
+	file, err := h.exports.ExportRecipe(r.Context(), query)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(file.Content)))
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(file.Content); err != nil {
+		h.logger.Debug("Recipe download interrupted", zap.String("recipe_id", recipeID.String()), zap.Error(err))
+	}
+}
+
+// recipeExportRecord lays a recipe out in recipeExportHeader order
+func recipeExportRecord(recipe *inbound.ExportedRecipe) []string {
+	publishedAt := ""
+	if recipe.PublishedAt != nil {
+		publishedAt = recipe.PublishedAt.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		recipe.ID.String(),
+		csvSafe(recipe.Title),
+		recipe.AuthorID.String(),
+		recipe.Status,
+		recipe.Language,
+		csvSafe(recipe.Cuisine),
+		csvSafe(recipe.Category),
+		recipe.Difficulty,
+		strconv.Itoa(recipe.PrepTimeMinutes),
+		strconv.Itoa(recipe.CookTimeMinutes),
+		strconv.Itoa(recipe.Servings),
+		strconv.Itoa(recipe.Calories),
+		strconv.Itoa(recipe.Likes),
+		strconv.Itoa(recipe.Views),
+		strconv.FormatFloat(recipe.AverageRating, 'f', 2, 64),
+		strconv.FormatBool(recipe.AIGenerated),
+		recipe.CreatedAt.UTC().Format(time.RFC3339),
+		recipe.UpdatedAt.UTC().Format(time.RFC3339),
+		publishedAt,
+		strings.Join(recipe.Allergens, "; "),
+		strings.Join(recipe.MayContain, "; "),
+	}
+}
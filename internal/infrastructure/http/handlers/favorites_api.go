@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// FavoriteRecipe handles POST /api/v1/recipes/{id}/favorite
+// Saves the recipe to the user's private favorites; saving twice is fine.
+func (h *APIHandlers) FavoriteRecipe(w http.ResponseWriter, r *http.Request) {
+	h.setFavorite(w, r, true)
+}
+
+// UnfavoriteRecipe handles DELETE /api/v1/recipes/{id}/favorite
+func (h *APIHandlers) UnfavoriteRecipe(w http.ResponseWriter, r *http.Request) {
+	h.setFavorite(w, r, false)
+}
+
+func (h *APIHandlers) setFavorite(w http.ResponseWriter, r *http.Request, favorite bool) {
+	userID, ok := h.requestUserID(w, r)
+	if !ok {
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	var status *inbound.FavoriteStatus
+	message := "Recipe saved to favorites"
+	if favorite {
+		status, err = h.recipeService.FavoriteRecipe(r.Context(), recipeID, userID)
+	} else {
+		status, err = h.recipeService.UnfavoriteRecipe(r.Context(), recipeID, userID)
+		message = "Recipe removed from favorites"
+	}
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    status,
+		Message: message,
+	})
+}
+
+// GetUserFavorites handles GET /api/v1/users/{id}/favorites
+// Favorites are private: {id} must be the caller or "me". Lists the saved
+// recipes most recently saved first, ?limit= (at most 100) to a page.
+func (h *APIHandlers) GetUserFavorites(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requestUserID(w, r)
+	if !ok {
+		return
+	}
+	if id := chi.URLParam(r, "id"); id != "me" && id != userID.String() {
+		h.writeErrorJSON(w, http.StatusForbidden, "Favorites are private")
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 20)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := parseIntParam(r, "page", 1)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	list, err := h.recipeService.GetFavoriteRecipes(r.Context(), userID, inbound.PaginationParams{
+		Page:     page - 1,
+		PageSize: limit,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    list,
+		Message: "User favorites retrieved successfully",
+	})
+}
+
+// requestUserID reads the signed-in user, answering 401 when there is none
+func (h *APIHandlers) requestUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
@@ -0,0 +1,161 @@
+// Package handlers provides the follow and public profile endpoints
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FollowAPIHandlers serves follows and public profiles
+type FollowAPIHandlers struct {
+	responder
+	follows inbound.FollowService
+}
+
+// NewFollowAPIHandlers creates the follow handlers
+func NewFollowAPIHandlers(follows inbound.FollowService, logger *zap.Logger) *FollowAPIHandlers {
+	return &FollowAPIHandlers{
+		follows:   follows,
+		responder: responder{logger: logger},
+	}
+}
+
+// GetProfile handles GET /api/v1/users/{id}/profile
+// {id} may be "me". Private profiles the caller may not see come back
+// with restricted set and only the name and counts.
+func (h *FollowAPIHandlers) GetProfile(w http.ResponseWriter, r *http.Request) {
+	viewerID := h.requesterID(r)
+	userID, ok := h.targetID(w, r, viewerID)
+	if !ok {
+		return
+	}
+
+	profile, err := h.follows.Profile(r.Context(), viewerID, userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    profile,
+		Message: "Profile retrieved successfully",
+	})
+}
+
+// Follow handles POST /api/v1/users/{id}/follow
+func (h *FollowAPIHandlers) Follow(w http.ResponseWriter, r *http.Request) {
+	h.setFollow(w, r, true)
+}
+
+// Unfollow handles DELETE /api/v1/users/{id}/follow
+func (h *FollowAPIHandlers) Unfollow(w http.ResponseWriter, r *http.Request) {
+	h.setFollow(w, r, false)
+}
+
+func (h *FollowAPIHandlers) setFollow(w http.ResponseWriter, r *http.Request, follow bool) {
+	followerID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var status *inbound.FollowStatus
+	message := "User followed"
+	if follow {
+		status, err = h.follows.Follow(r.Context(), followerID, userID)
+	} else {
+		status, err = h.follows.Unfollow(r.Context(), followerID, userID)
+		message = "User unfollowed"
+	}
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    status,
+		Message: message,
+	})
+}
+
+// Followers handles GET /api/v1/users/{id}/followers?page=&limit=
+func (h *FollowAPIHandlers) Followers(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.follows.Followers, "Followers retrieved successfully")
+}
+
+// Following handles GET /api/v1/users/{id}/following?page=&limit=
+func (h *FollowAPIHandlers) Following(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.follows.Following, "Following retrieved successfully")
+}
+
+type followListFunc func(ctx context.Context, viewerID *uuid.UUID, userID uuid.UUID, params inbound.PaginationParams) (*inbound.FollowList, error)
+
+func (h *FollowAPIHandlers) list(w http.ResponseWriter, r *http.Request, find followListFunc, message string) {
+	viewerID := h.requesterID(r)
+	userID, ok := h.targetID(w, r, viewerID)
+	if !ok {
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 20)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := parseIntParam(r, "page", 1)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	list, err := find(r.Context(), viewerID, userID, inbound.PaginationParams{Page: page - 1, PageSize: limit})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    list,
+		Message: message,
+	})
+}
+
+// targetID reads {id}, resolving "me" to the signed-in caller
+func (h *FollowAPIHandlers) targetID(w http.ResponseWriter, r *http.Request, viewerID *uuid.UUID) (uuid.UUID, bool) {
+	raw := chi.URLParam(r, "id")
+	if raw == "me" {
+		if viewerID == nil {
+			h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+			return uuid.Nil, false
+		}
+		return *viewerID, true
+	}
+	userID, err := uuid.Parse(raw)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid user ID")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// requesterID is the signed-in caller, or nil for anonymous visitors
+func (h *FollowAPIHandlers) requesterID(r *http.Request) *uuid.UUID {
+	if raw, exists := middleware.GetUserIDFromContext(r.Context()); exists {
+		if id, err := uuid.Parse(raw); err == nil {
+			return &id
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Package handlers provides food safety checks and the publish endpoints
+// that warn authors about them
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FoodSafetyAPIHandlers serves recipe food safety checks, and publishes
+// recipes with the food safety warnings the author should fix
+type FoodSafetyAPIHandlers struct {
+	responder
+	recipeService inbound.RecipeService
+	safety        inbound.FoodSafetyService
+}
+
+// NewFoodSafetyAPIHandlers creates the food safety handlers
+func NewFoodSafetyAPIHandlers(recipeService inbound.RecipeService, safety inbound.FoodSafetyService, logger *zap.Logger) *FoodSafetyAPIHandlers {
+	return &FoodSafetyAPIHandlers{
+		recipeService: recipeService,
+		safety:        safety,
+		responder:     responder{logger: logger},
+	}
+}
+
+// PublishedRecipe is a published or scheduled recipe with the food safety
+// rules its steps break. Warnings never stop publishing.
+type PublishedRecipe struct {
+	*inbound.RecipeDTO
+	FoodSafetyWarnings []inbound.FoodSafetyIssue `json:"food_safety_warnings,omitempty"`
+}
+
+// RecipeFoodSafety handles GET /api/v1/recipes/{id}/food-safety
+func (h *FoodSafetyAPIHandlers) RecipeFoodSafety(w http.ResponseWriter, r *http.Request) {
+	query := inbound.FoodSafetyQuery{RecipeID: chi.URLParam(r, "id")}
+	if raw, exists := middleware.GetUserIDFromContext(r.Context()); exists {
+		if id, err := uuid.Parse(raw); err == nil {
+			query.RequesterID = id
+		}
+	}
+
+	report, err := h.safety.Check(r.Context(), query)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	message := "No food safety issues found"
+	if !report.Safe {
+		message = fmt.Sprintf("%d food safety issues found", len(report.Issues))
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    report,
+		Message: message,
+	})
+}
+
+// PublishRecipe handles POST /api/v1/recipes/{id}/publish
+// Publishes a draft now and queues its announcements.
+func (h *FoodSafetyAPIHandlers) PublishRecipe(w http.ResponseWriter, r *http.Request) {
+	userID, recipeID, ok := h.authorRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.recipeService.PublishRecipe(r.Context(), recipeID, userID); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	recipe, err := h.recipeService.GetRecipeByID(r.Context(), recipeID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writePublished(w, r, recipe, userID, "Recipe published successfully")
+}
+
+// SchedulePublish handles PUT /api/v1/recipes/{id}/schedule
+// Body: {"publish_at": "2026-11-01T09:00:00Z"}. Rescheduling replaces the
+// earlier time.
+func (h *FoodSafetyAPIHandlers) SchedulePublish(w http.ResponseWriter, r *http.Request) {
+	userID, recipeID, ok := h.authorRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req SchedulePublishRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if req.PublishAt.IsZero() {
+		h.writeErrorJSON(w, http.StatusBadRequest, "publish_at is required")
+		return
+	}
+
+	recipe, err := h.recipeService.SchedulePublish(r.Context(), inbound.SchedulePublishCommand{
+		RecipeID:  recipeID,
+		UserID:    userID,
+		PublishAt: req.PublishAt,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writePublished(w, r, recipe, userID, "Recipe publish scheduled")
+}
+
+// writePublished answers a publish with the recipe and its food safety
+// warnings. A failed check is logged and the recipe returned without them.
+func (h *FoodSafetyAPIHandlers) writePublished(w http.ResponseWriter, r *http.Request, recipe *inbound.RecipeDTO, userID uuid.UUID, message string) {
+	published := PublishedRecipe{RecipeDTO: recipe}
+	report, err := h.safety.Check(r.Context(), inbound.FoodSafetyQuery{RequesterID: userID, RecipeID: recipe.ID.String()})
+	if err != nil {
+		h.logger.Warn("Food safety check failed", zap.String("recipe_id", recipe.ID.String()), zap.Error(err))
+	} else if !report.Safe {
+		published.FoodSafetyWarnings = report.Issues
+		message = fmt.Sprintf("%s with %d food safety warnings", message, len(report.Issues))
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    published,
+		Message: message,
+	})
+}
+
+// authorRequest reads the signed-in author and the recipe ID
+func (h *FoodSafetyAPIHandlers) authorRequest(w http.ResponseWriter, r *http.Request) (userID, recipeID uuid.UUID, ok bool) {
+	raw, exists := middleware.GetUserIDFromContext(r.Context())
+	if !exists {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(raw)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	recipeID, err = uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, recipeID, true
+}
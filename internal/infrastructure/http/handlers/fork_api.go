@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ForkRecipe handles POST /api/v1/recipes/{id}/fork
+// Copies a published recipe into a draft owned by the caller.
+func (h *APIHandlers) ForkRecipe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requestUserID(w, r)
+	if !ok {
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	fork, err := h.recipeService.ForkRecipe(r.Context(), recipeID, userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    fork,
+		Message: "Recipe forked into a new draft",
+	})
+}
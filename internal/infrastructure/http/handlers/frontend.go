@@ -13,6 +13,7 @@ import (
 
 	"github.com/alchemorsel/v3/internal/application/user"
 	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
 	"github.com/alchemorsel/v3/internal/infrastructure/security"
 	"github.com/alchemorsel/v3/internal/ports/inbound"
 	"github.com/alchemorsel/v3/internal/ports/outbound"
@@ -613,26 +614,10 @@ func (h *FrontendHandlers) createRecipeFromAI(aiResponse *outbound.AIRecipeRespo
 	return domainRecipe, nil
 }
 
-// parseUnit converts string unit to domain measurement unit
+// parseUnit converts string unit to domain measurement unit using the shared
+// ingredient parser's aliases
 func (h *FrontendHandlers) parseUnit(unit string) recipe.MeasurementUnit {
-	unitMap := map[string]recipe.MeasurementUnit{
-		"tsp":   recipe.MeasurementUnitTeaspoon,
-		"tbsp":  recipe.MeasurementUnitTablespoon,
-		"cup":   recipe.MeasurementUnitCup,
-		"cups":  recipe.MeasurementUnitCup,
-		"oz":    recipe.MeasurementUnitOunce,
-		"ml":    recipe.MeasurementUnitMilliliter,
-		"l":     recipe.MeasurementUnitLiter,
-		"g":     recipe.MeasurementUnitGram,
-		"kg":    recipe.MeasurementUnitKilogram,
-		"lb":    recipe.MeasurementUnitPound,
-		"piece": recipe.MeasurementUnitPiece,
-		"pieces": recipe.MeasurementUnitPiece,
-		"dash":  recipe.MeasurementUnitDash,
-		"pinch": recipe.MeasurementUnitPinch,
-	}
-	
-	if mappedUnit, exists := unitMap[strings.ToLower(unit)]; exists {
+	if mappedUnit, ok := ingredients.ParseUnit(unit); ok {
 		return mappedUnit
 	}
 	
@@ -0,0 +1,81 @@
+// Package handlers provides the recipe knowledge graph endpoints
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// GraphAPIHandlers serves traversals of the recipe knowledge graph
+type GraphAPIHandlers struct {
+	responder
+	graph inbound.RecipeGraphService
+}
+
+// NewGraphAPIHandlers creates the recipe graph handlers
+func NewGraphAPIHandlers(graph inbound.RecipeGraphService, logger *zap.Logger) *GraphAPIHandlers {
+	return &GraphAPIHandlers{
+		graph:     graph,
+		responder: responder{logger: logger},
+	}
+}
+
+// RecipeNodes handles GET /api/v1/recipes/{id}/graph
+func (h *GraphAPIHandlers) RecipeNodes(w http.ResponseWriter, r *http.Request) {
+	nodes, err := h.graph.RecipeNodes(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    nodes,
+		Message: "Recipe graph retrieved successfully",
+	})
+}
+
+// RelatedRecipes handles GET /api/v1/recipes/{id}/related
+func (h *GraphAPIHandlers) RelatedRecipes(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	related, err := h.graph.Related(r.Context(), chi.URLParam(r, "id"), limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    related,
+		Message: "Related recipes retrieved successfully",
+	})
+}
+
+// NodeRecipes handles GET /api/v1/graph/{kind}/{key}/recipes
+func (h *GraphAPIHandlers) NodeRecipes(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	recipes, err := h.graph.NodeRecipes(r.Context(), chi.URLParam(r, "kind"), chi.URLParam(r, "key"), limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    recipes,
+		Message: "Recipes retrieved successfully",
+	})
+}
@@ -0,0 +1,143 @@
+// Package handlers provides the guest mode endpoints
+package handlers
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GuestTokenHeader carries the token from POST /api/v1/guest/sessions
+const GuestTokenHeader = "X-Guest-Token"
+
+// GuestChatRequest is the payload for POST /api/v1/guest/chat
+type GuestChatRequest struct {
+	Message string `json:"message"`
+}
+
+// GuestClaimRequest is the payload for POST /api/v1/guest/claim
+type GuestClaimRequest struct {
+	GuestToken string `json:"guest_token"`
+}
+
+// GuestAPIHandlers serves guest mode
+type GuestAPIHandlers struct {
+	responder
+	guests inbound.GuestService
+}
+
+// NewGuestAPIHandlers creates the guest handlers
+func NewGuestAPIHandlers(guests inbound.GuestService, logger *zap.Logger) *GuestAPIHandlers {
+	return &GuestAPIHandlers{guests: guests, responder: responder{logger: logger}}
+}
+
+// StartSession handles POST /api/v1/guest/sessions
+func (h *GuestAPIHandlers) StartSession(w http.ResponseWriter, r *http.Request) {
+	// RealIP has already replaced RemoteAddr with the client's address
+	clientIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		clientIP = r.RemoteAddr
+	}
+
+	session, err := h.guests.StartSession(r.Context(), clientIP)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusCreated, APIResponse{Success: true, Data: session})
+}
+
+// Chat handles POST /api/v1/guest/chat
+func (h *GuestAPIHandlers) Chat(w http.ResponseWriter, r *http.Request) {
+	var req GuestChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	reply, err := h.guests.Chat(r.Context(), r.Header.Get(GuestTokenHeader), req.Message)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: reply})
+}
+
+// ListRecipes handles GET /api/v1/guest/recipes
+func (h *GuestAPIHandlers) ListRecipes(w http.ResponseWriter, r *http.Request) {
+	recipes, err := h.guests.ListRecipes(r.Context(), r.Header.Get(GuestTokenHeader))
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: recipes})
+}
+
+// SaveRecipe handles POST /api/v1/guest/recipes
+func (h *GuestAPIHandlers) SaveRecipe(w http.ResponseWriter, r *http.Request) {
+	var req inbound.GuestRecipeDraft
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	saved, err := h.guests.SaveRecipe(r.Context(), r.Header.Get(GuestTokenHeader), req)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusCreated, APIResponse{Success: true, Data: saved, Message: "Recipe saved for this guest session"})
+}
+
+// DeleteRecipe handles DELETE /api/v1/guest/recipes/{id}
+func (h *GuestAPIHandlers) DeleteRecipe(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	if err := h.guests.DeleteRecipe(r.Context(), r.Header.Get(GuestTokenHeader), recipeID); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Claim handles POST /api/v1/guest/claim. The signed-in user takes over
+// the guest's recipes as drafts.
+func (h *GuestAPIHandlers) Claim(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	var req GuestClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if req.GuestToken == "" {
+		h.writeErrorJSON(w, http.StatusBadRequest, "guest_token is required")
+		return
+	}
+
+	result, err := h.guests.Claim(r.Context(), req.GuestToken, userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{Success: true, Data: result})
+}
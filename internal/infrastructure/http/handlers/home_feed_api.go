@@ -0,0 +1,52 @@
+// Package handlers provides the personal recipe lists shown on the home page
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/google/uuid"
+)
+
+// RecentlyViewedRecipes handles GET /api/v1/recipes/recently-viewed?limit=
+func (h *APIHandlers) RecentlyViewedRecipes(w http.ResponseWriter, r *http.Request) {
+	h.homeFeed(w, r, h.recipeService.GetRecentlyViewedRecipes, "Recently viewed recipes retrieved successfully")
+}
+
+// ChefActivity handles GET /api/v1/recipes/chef-activity?limit=
+// New recipes by the authors of recipes the user liked.
+func (h *APIHandlers) ChefActivity(w http.ResponseWriter, r *http.Request) {
+	h.homeFeed(w, r, h.recipeService.GetChefActivity, "Chef activity retrieved successfully")
+}
+
+func (h *APIHandlers) homeFeed(w http.ResponseWriter, r *http.Request, list func(context.Context, uuid.UUID, int) ([]inbound.RecipeDTO, error), message string) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	recipes, err := list(r.Context(), userID, limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    recipes,
+		Message: message,
+	})
+}
@@ -0,0 +1,116 @@
+// Package handlers provides image uploads and the /img/{id} variants
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ImageAPIHandlers serves uploaded images
+type ImageAPIHandlers struct {
+	responder
+	images        inbound.ImageService
+	uploadScans   inbound.UploadScanService
+	maxUploadSize int64
+}
+
+// NewImageAPIHandlers creates the image handlers. Uploads are scanned for
+// malware first when uploadScans is set.
+func NewImageAPIHandlers(images inbound.ImageService, uploadScans inbound.UploadScanService, maxUploadSize int64, logger *zap.Logger) *ImageAPIHandlers {
+	return &ImageAPIHandlers{
+		images:        images,
+		uploadScans:   uploadScans,
+		maxUploadSize: maxUploadSize,
+		responder:     responder{logger: logger},
+	}
+}
+
+// UploadImage handles POST /api/v1/images. The image is the "image" field
+// of a multipart form; the response carries its /img URL and srcset.
+func (h *ImageAPIHandlers) UploadImage(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// Leave room for the multipart framing
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadSize+64<<10)
+	content, contentType, err := readUploadedImage(r)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if h.uploadScans != nil {
+		if err := h.uploadScans.ScanUpload(r.Context(), inbound.ScanUploadCommand{
+			UserID:      userID,
+			Source:      inbound.UploadSourceImage,
+			ContentType: contentType,
+			Content:     content,
+		}); err != nil {
+			h.writeServiceError(w, err)
+			return
+		}
+	}
+
+	uploaded, err := h.images.UploadImage(r.Context(), inbound.UploadImageCommand{
+		UserID:      userID,
+		ContentType: contentType,
+		Content:     content,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusCreated, APIResponse{Success: true, Data: uploaded})
+}
+
+// ServeImage handles GET /img/{id}. The w, h, f and q query parameters
+// are the width, height, format and quality the srcset asked for; the
+// service snaps them to the configured variants, and picks the format from
+// the Accept header when f is missing or auto.
+func (h *ImageAPIHandlers) ServeImage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusNotFound, "Image not found")
+		return
+	}
+
+	query := r.URL.Query()
+	req := inbound.ImageVariantRequest{Format: query.Get("f"), Accept: r.Header.Get("Accept")}
+	for param, target := range map[string]*int{"w": &req.Width, "h": &req.Height, "q": &req.Quality} {
+		if raw := query.Get(param); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 0 {
+				h.writeErrorJSON(w, http.StatusBadRequest, param+" must be a positive number")
+				return
+			}
+			*target = n
+		}
+	}
+
+	variant, err := h.images.GetVariant(r.Context(), id, req)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	// Variants never change for a URL, but the format depends on Accept
+	w.Header().Set("Content-Type", variant.ContentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("Vary", "Accept")
+	w.Header().Set("ETag", variant.ETag)
+	http.ServeContent(w, r, "", variant.UploadedAt, bytes.NewReader(variant.Content))
+}
@@ -0,0 +1,188 @@
+// Package handlers provides the in-app notification endpoints
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// NotificationAPIHandlers serves the signed-in user's notifications
+type NotificationAPIHandlers struct {
+	responder
+	notifications inbound.NotificationService
+}
+
+// NewNotificationAPIHandlers creates the notification handlers
+func NewNotificationAPIHandlers(notifications inbound.NotificationService, logger *zap.Logger) *NotificationAPIHandlers {
+	return &NotificationAPIHandlers{
+		notifications: notifications,
+		responder:     responder{logger: logger},
+	}
+}
+
+// MarkReadRequest lists the notifications to mark read
+type MarkReadRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+// NotificationPreferencesRequest turns notification types on or off,
+// keyed by type; types left out keep their setting
+type NotificationPreferencesRequest struct {
+	Enabled map[string]bool `json:"enabled"`
+}
+
+// UnreadCountResponse is the number behind the nav badge
+type UnreadCountResponse struct {
+	Unread int `json:"unread"`
+}
+
+// NotificationPreferencesResponse lists every type and whether it is on
+type NotificationPreferencesResponse struct {
+	Preferences []inbound.NotificationPreference `json:"preferences"`
+}
+
+// List handles GET /api/v1/notifications?unread=&page=&limit=
+func (h *NotificationAPIHandlers) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	unreadOnly, err := parseBoolParam(r, "unread", false)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 20)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := parseIntParam(r, "page", 1)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	list, err := h.notifications.List(r.Context(), userID, unreadOnly, inbound.PaginationParams{Page: page - 1, PageSize: limit})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    list,
+		Message: "Notifications retrieved successfully",
+	})
+}
+
+// UnreadCount handles GET /api/v1/notifications/unread-count
+func (h *NotificationAPIHandlers) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	unread, err := h.notifications.UnreadCount(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    UnreadCountResponse{Unread: unread},
+	})
+}
+
+// MarkRead handles POST /api/v1/notifications/read
+func (h *NotificationAPIHandlers) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	var req MarkReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	unread, err := h.notifications.MarkRead(r.Context(), userID, req.IDs)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    UnreadCountResponse{Unread: unread},
+		Message: "Notifications marked read",
+	})
+}
+
+// MarkAllRead handles POST /api/v1/notifications/read-all
+func (h *NotificationAPIHandlers) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.notifications.MarkAllRead(r.Context(), userID); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    UnreadCountResponse{Unread: 0},
+		Message: "Notifications marked read",
+	})
+}
+
+// GetPreferences handles GET /api/v1/notifications/preferences
+func (h *NotificationAPIHandlers) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.notifications.Preferences(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    NotificationPreferencesResponse{Preferences: prefs},
+	})
+}
+
+// UpdatePreferences handles PUT /api/v1/notifications/preferences
+func (h *NotificationAPIHandlers) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	var req NotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	prefs, err := h.notifications.SetPreferences(r.Context(), userID, req.Enabled)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    NotificationPreferencesResponse{Preferences: prefs},
+		Message: "Notification preferences updated",
+	})
+}
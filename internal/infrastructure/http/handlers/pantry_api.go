@@ -0,0 +1,246 @@
+// Package handlers provides HTTP handlers for pantry inventory
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PantryAPIHandlers serve a user's pantry and marking recipes as cooked
+type PantryAPIHandlers struct {
+	responder
+	pantry inbound.PantryService
+}
+
+// NewPantryAPIHandlers creates the pantry handlers
+func NewPantryAPIHandlers(pantry inbound.PantryService, logger *zap.Logger) *PantryAPIHandlers {
+	return &PantryAPIHandlers{
+		pantry:    pantry,
+		responder: responder{logger: logger},
+	}
+}
+
+// PantryItemRequest creates or updates a pantry item
+type PantryItemRequest struct {
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Par      float64 `json:"par"`
+}
+
+// CookRequest marks a recipe as cooked with the deductions the cook
+// confirmed from the preview
+type CookRequest struct {
+	Portions       int                          `json:"portions"`
+	Deductions     []inbound.ConfirmedDeduction `json:"deductions"`
+	ShoppingListID *uuid.UUID                   `json:"shopping_list_id"`
+}
+
+// ListItems handles GET /api/v1/pantry/items
+func (h *PantryAPIHandlers) ListItems(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.pantry.ListItems(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    items,
+		Message: "Pantry retrieved successfully",
+	})
+}
+
+// CreateItem handles POST /api/v1/pantry/items
+func (h *PantryAPIHandlers) CreateItem(w http.ResponseWriter, r *http.Request) {
+	h.saveItem(w, r, nil, http.StatusCreated)
+}
+
+// UpdateItem handles PUT /api/v1/pantry/items/{id}
+func (h *PantryAPIHandlers) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	itemID, ok := h.itemID(w, r)
+	if !ok {
+		return
+	}
+	h.saveItem(w, r, &itemID, http.StatusOK)
+}
+
+func (h *PantryAPIHandlers) saveItem(w http.ResponseWriter, r *http.Request, itemID *uuid.UUID, status int) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req PantryItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	item, err := h.pantry.SaveItem(r.Context(), inbound.SavePantryItemCommand{
+		UserID:   userID,
+		ItemID:   itemID,
+		Name:     req.Name,
+		Quantity: req.Quantity,
+		Unit:     req.Unit,
+		Par:      req.Par,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, status, APIResponse{
+		Success: true,
+		Data:    item,
+		Message: "Pantry item saved",
+	})
+}
+
+// DeleteItem handles DELETE /api/v1/pantry/items/{id}
+func (h *PantryAPIHandlers) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	itemID, ok := h.itemID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.pantry.DeleteItem(r.Context(), userID, itemID); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Pantry item deleted",
+	})
+}
+
+// Consumption handles GET /api/v1/pantry/consumption?days=<n>
+func (h *PantryAPIHandlers) Consumption(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	days, err := parseIntParam(r, "days", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.pantry.ConsumptionHistory(r.Context(), inbound.ConsumptionQuery{UserID: userID, Days: days})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    report,
+		Message: "Consumption retrieved successfully",
+	})
+}
+
+// PreviewCook handles GET /api/v1/recipes/{id}/cooked/preview?portions=<n>
+// It proposes what cooking the recipe takes from the pantry; nothing is
+// changed until the cook confirms.
+func (h *PantryAPIHandlers) PreviewCook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	recipeID, ok := h.recipeID(w, r)
+	if !ok {
+		return
+	}
+	portions, err := parseIntParam(r, "portions", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	preview, err := h.pantry.PreviewCook(r.Context(), inbound.CookPreviewQuery{
+		UserID:   userID,
+		RecipeID: recipeID,
+		Portions: portions,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    preview,
+		Message: "Cook preview ready",
+	})
+}
+
+// CompleteCook handles POST /api/v1/recipes/{id}/cooked
+// The body carries the deductions the cook confirmed; without any the
+// recipe is logged as cooked and the pantry left alone.
+func (h *PantryAPIHandlers) CompleteCook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	recipeID, ok := h.recipeID(w, r)
+	if !ok {
+		return
+	}
+
+	var req CookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	result, err := h.pantry.CompleteCook(r.Context(), inbound.CompleteCookCommand{
+		UserID:         userID,
+		RecipeID:       recipeID,
+		Portions:       req.Portions,
+		Deductions:     req.Deductions,
+		ShoppingListID: req.ShoppingListID,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    result,
+		Message: "Recipe marked as cooked",
+	})
+}
+
+func (h *PantryAPIHandlers) itemID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	itemID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid pantry item ID")
+		return uuid.Nil, false
+	}
+	return itemID, true
+}
+
+func (h *PantryAPIHandlers) recipeID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return uuid.Nil, false
+	}
+	return recipeID, true
+}
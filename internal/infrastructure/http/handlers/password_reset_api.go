@@ -0,0 +1,74 @@
+// Package handlers provides the forgotten password endpoints
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"go.uber.org/zap"
+)
+
+// PasswordResetAPIHandlers serves forgot-password and reset-password
+type PasswordResetAPIHandlers struct {
+	responder
+	resets inbound.PasswordResetService
+}
+
+// NewPasswordResetAPIHandlers creates the password reset handlers
+func NewPasswordResetAPIHandlers(resets inbound.PasswordResetService, logger *zap.Logger) *PasswordResetAPIHandlers {
+	return &PasswordResetAPIHandlers{
+		resets:    resets,
+		responder: responder{logger: logger},
+	}
+}
+
+// ForgotPasswordRequest is the payload for POST /api/v1/auth/forgot-password
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest is the payload for POST /api/v1/auth/reset-password
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ForgotPassword handles POST /api/v1/auth/forgot-password. The answer is
+// the same whether or not the address has an account.
+func (h *PasswordResetAPIHandlers) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if err := h.resets.RequestReset(r.Context(), req.Email); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, APIResponse{
+		Success: true,
+		Message: "If the address has an account, a reset code is on its way",
+	})
+}
+
+// ResetPassword handles POST /api/v1/auth/reset-password
+func (h *PasswordResetAPIHandlers) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if err := h.resets.Reset(r.Context(), req.Token, req.Password); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Password changed, sign in with the new one",
+	})
+}
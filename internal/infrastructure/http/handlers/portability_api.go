@@ -0,0 +1,141 @@
+// Package handlers provides the account archive download and restore
+// endpoints
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"go.uber.org/zap"
+)
+
+// maxAccountArchiveBytes bounds an uploaded account archive
+const maxAccountArchiveBytes = 100 << 20
+
+// PortabilityAPIHandlers lets users take their data with them
+type PortabilityAPIHandlers struct {
+	responder
+	portability inbound.PortabilityService
+	uploadScans inbound.UploadScanService
+}
+
+// NewPortabilityAPIHandlers creates the account archive handlers. Uploaded
+// archives are scanned for malware first when uploadScans is set.
+func NewPortabilityAPIHandlers(portability inbound.PortabilityService, uploadScans inbound.UploadScanService, logger *zap.Logger) *PortabilityAPIHandlers {
+	return &PortabilityAPIHandlers{
+		portability: portability,
+		uploadScans: uploadScans,
+		responder:   responder{logger: logger},
+	}
+}
+
+// ExportAccount handles GET /api/v1/me/export?format=zip|json
+// The caller's recipes, collections, saved recipes and preferences are
+// sent as a download.
+func (h *PortabilityAPIHandlers) ExportAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	file, err := h.portability.ExportAccount(r.Context(), inbound.AccountExportQuery{
+		UserID: userID,
+		Format: r.URL.Query().Get("format"),
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(file.Content)))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(file.Content); err != nil {
+		h.logger.Debug("Account archive download interrupted", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+}
+
+// ImportAccount handles POST /api/v1/me/import
+// Accepts the archive as the "file" field of a multipart form, with an
+// optional "keep_duplicates" field, or the JSON archive as the body with
+// keep_duplicates in the query.
+func (h *PortabilityAPIHandlers) ImportAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAccountArchiveBytes)
+	var (
+		content        []byte
+		fileName       string
+		contentType    = r.Header.Get("Content-Type")
+		keepDuplicates = r.URL.Query().Get("keep_duplicates")
+	)
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			h.writeErrorJSON(w, http.StatusBadRequest, "Invalid upload: expected multipart form with a file field")
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			h.writeErrorJSON(w, http.StatusBadRequest, "file field is required")
+			return
+		}
+		defer file.Close()
+		if content, err = io.ReadAll(file); err != nil {
+			h.writeErrorJSON(w, http.StatusBadRequest, "Failed to read upload")
+			return
+		}
+		fileName, contentType = header.Filename, header.Header.Get("Content-Type")
+		if value := r.FormValue("keep_duplicates"); value != "" {
+			keepDuplicates = value
+		}
+	} else {
+		var err error
+		if content, err = io.ReadAll(r.Body); err != nil {
+			h.writeErrorJSON(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("The archive must be at most %d MB", maxAccountArchiveBytes>>20))
+			return
+		}
+	}
+
+	if h.uploadScans != nil {
+		if err := h.uploadScans.ScanUpload(r.Context(), inbound.ScanUploadCommand{
+			UserID:      userID,
+			Source:      inbound.UploadSourceAccountArchive,
+			FileName:    fileName,
+			ContentType: contentType,
+			Content:     content,
+		}); err != nil {
+			h.writeServiceError(w, err)
+			return
+		}
+	}
+
+	keep, _ := strconv.ParseBool(keepDuplicates)
+	result, err := h.portability.ImportAccount(r.Context(), inbound.AccountImportCommand{
+		UserID:         userID,
+		Content:        content,
+		KeepDuplicates: keep,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	message := fmt.Sprintf("Restored %d collections and %d saved recipes", result.Collections, result.Favorites)
+	if rs := result.Recipes; rs != nil {
+		message = fmt.Sprintf("Restored %d recipes as drafts (%d duplicates skipped, %d failed), %d collections and %d saved recipes",
+			rs.Created, rs.Duplicates, rs.Failed, result.Collections, result.Favorites)
+	}
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    result,
+		Message: message,
+	})
+}
@@ -0,0 +1,172 @@
+// Package handlers provides the admin profiling endpoints
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// pprofDefaultSeconds is what net/http/pprof samples for when a profile
+// request leaves seconds out
+const pprofDefaultSeconds = 30
+
+// ProfilingAPIHandlers starts profile captures, serves the results and
+// guards the raw pprof endpoints
+type ProfilingAPIHandlers struct {
+	responder
+	profiling inbound.ProfilingService
+}
+
+// NewProfilingAPIHandlers creates the profiling handlers
+func NewProfilingAPIHandlers(profiling inbound.ProfilingService, logger *zap.Logger) *ProfilingAPIHandlers {
+	return &ProfilingAPIHandlers{
+		profiling: profiling,
+		responder: responder{logger: logger},
+	}
+}
+
+// StartCapture handles POST /api/v1/admin/profiles
+// The capture runs in the background; poll GET /api/v1/admin/profiles/{id}
+// until it completes, then download it.
+func (h *ProfilingAPIHandlers) StartCapture(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req inbound.ProfileCaptureRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBeaconBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	req.IPAddress = r.RemoteAddr
+
+	capture, err := h.profiling.StartCapture(r.Context(), userID, req)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, APIResponse{
+		Success: true,
+		Data:    capture,
+		Message: "Profile capture started",
+	})
+}
+
+// ListCaptures handles GET /api/v1/admin/profiles
+func (h *ProfilingAPIHandlers) ListCaptures(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	captures, err := h.profiling.ListCaptures(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    captures,
+		Message: "Profile captures retrieved successfully",
+	})
+}
+
+// GetCapture handles GET /api/v1/admin/profiles/{id}
+func (h *ProfilingAPIHandlers) GetCapture(w http.ResponseWriter, r *http.Request) {
+	userID, captureID, ok := h.captureParams(w, r)
+	if !ok {
+		return
+	}
+
+	capture, err := h.profiling.GetCapture(r.Context(), userID, captureID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    capture,
+		Message: "Profile capture retrieved successfully",
+	})
+}
+
+// DownloadCapture handles GET /api/v1/admin/profiles/{id}/download
+// The body is the raw profile, readable with go tool pprof or go tool trace.
+func (h *ProfilingAPIHandlers) DownloadCapture(w http.ResponseWriter, r *http.Request) {
+	userID, captureID, ok := h.captureParams(w, r)
+	if !ok {
+		return
+	}
+
+	content, capture, err := h.profiling.OpenCapture(r.Context(), userID, captureID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+capture.FileName+`"`)
+	if capture.SizeBytes > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(capture.SizeBytes, 10))
+	}
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.Warn("Profile download interrupted", zap.String("capture_id", captureID.String()), zap.Error(err))
+	}
+}
+
+// DebugGuard only lets admins reach /debug/pprof and records each request
+func (h *ProfilingAPIHandlers) DebugGuard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := h.userID(w, r)
+		if !ok {
+			return
+		}
+
+		endpoint := strings.Trim(strings.TrimPrefix(r.URL.Path, "/debug/pprof"), "/")
+		if endpoint == "" {
+			endpoint = "index"
+		}
+		seconds, err := strconv.Atoi(r.URL.Query().Get("seconds"))
+		if err != nil && endpoint == "profile" {
+			seconds = pprofDefaultSeconds
+		}
+
+		err = h.profiling.AuthorizeDebug(r.Context(), userID, inbound.DebugAccessRequest{
+			Endpoint:  endpoint,
+			Seconds:   seconds,
+			IPAddress: r.RemoteAddr,
+		})
+		if err != nil {
+			h.writeServiceError(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *ProfilingAPIHandlers) captureParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	captureID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid profile capture ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, captureID, true
+}
@@ -0,0 +1,47 @@
+// Package handlers provides the scheduled publish endpoints for authors
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// SchedulePublishRequest sets when a draft publishes itself
+type SchedulePublishRequest struct {
+	PublishAt time.Time `json:"publish_at"`
+}
+
+// CancelScheduledPublish handles DELETE /api/v1/recipes/{id}/schedule
+func (h *APIHandlers) CancelScheduledPublish(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	recipe, err := h.recipeService.CancelScheduledPublish(r.Context(), recipeID, userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    recipe,
+		Message: "Scheduled publish cancelled",
+	})
+}
@@ -0,0 +1,55 @@
+// Package handlers provides the recipe change feed for sync clients
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// RecipeChanges handles GET /api/v1/recipes/{id}/changes?since=<revision>
+// Offline and mobile clients send the revision they hold, from a previous
+// call or the recipe's "revision" field, and apply the returned fields in
+// order. Drafts are only visible to their author.
+func (h *APIHandlers) RecipeChanges(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	var since int64
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		since, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || since < 0 {
+			h.writeErrorJSON(w, http.StatusBadRequest, "since must be a revision number")
+			return
+		}
+	}
+	limit, err := parseIntParam(r, "limit", 50)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := inbound.RecipeChangesQuery{RecipeID: recipeID, Since: since, Limit: limit}
+	if rawUserID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		query.RequesterID, _ = uuid.Parse(rawUserID)
+	}
+
+	changes, err := h.recipeService.GetRecipeChanges(r.Context(), query)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    changes,
+		Message: "Recipe changes retrieved successfully",
+	})
+}
@@ -0,0 +1,135 @@
+// Package handlers provides recipe editing for authors and admins
+package handlers
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// UpdateRecipeRequest is the body of PUT /recipes/{id}. Omitted fields are
+// left alone; ingredients, instructions and tags replace the whole list.
+// Ingredients are lines such as "2 cups flour, sifted".
+type UpdateRecipeRequest struct {
+	Title        *string   `json:"title"`
+	Description  *string   `json:"description"`
+	Servings     *int      `json:"servings"`
+	PrepTime     *int      `json:"prep_time"`
+	CookTime     *int      `json:"cook_time"`
+	Difficulty   *string   `json:"difficulty"`
+	Cuisine      *string   `json:"cuisine"`
+	Category     *string   `json:"category"`
+	Tags         *[]string `json:"tags"`
+	Ingredients  *[]string `json:"ingredients"`
+	Instructions *[]string `json:"instructions"`
+}
+
+// UpdateRecipe handles PUT /api/v1/recipes/{id}. Only the author or an
+// admin may edit a recipe.
+func (h *APIHandlers) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	var req UpdateRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	cmd, err := req.command(recipeID, userID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dto, err := h.recipeService.UpdateRecipe(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    dto,
+		Message: "Recipe updated successfully",
+	})
+}
+
+// command parses the ingredient lines and drops blank steps
+func (req UpdateRecipeRequest) command(recipeID, userID uuid.UUID) (inbound.UpdateRecipeCommand, error) {
+	cmd := inbound.UpdateRecipeCommand{
+		RecipeID:    recipeID,
+		UserID:      userID,
+		Title:       req.Title,
+		Description: req.Description,
+		PrepTime:    req.PrepTime,
+		CookTime:    req.CookTime,
+		Servings:    req.Servings,
+		Tags:        req.Tags,
+	}
+	if req.Difficulty != nil {
+		difficulty := recipe.DifficultyLevel(strings.ToLower(*req.Difficulty))
+		cmd.Difficulty = &difficulty
+	}
+	if req.Cuisine != nil {
+		cuisine := recipe.CuisineType(strings.ToLower(*req.Cuisine))
+		cmd.Cuisine = &cuisine
+	}
+	if req.Category != nil {
+		category := recipe.CategoryType(strings.ToLower(*req.Category))
+		cmd.Category = &category
+	}
+
+	if req.Ingredients != nil {
+		list := []inbound.CreateIngredientCommand{}
+		for _, line := range *req.Ingredients {
+			parsed, err := ingredients.Parse(line)
+			switch {
+			case stderrors.Is(err, ingredients.ErrEmptyLine), stderrors.Is(err, ingredients.ErrSectionHeader):
+				continue
+			case err != nil:
+				return cmd, fmt.Errorf("could not read ingredient %q", line)
+			}
+			ingredient := parsed.Ingredient()
+			list = append(list, inbound.CreateIngredientCommand{
+				Name:     ingredient.Name,
+				Amount:   ingredient.Amount,
+				Unit:     ingredient.Unit,
+				Optional: ingredient.Optional,
+				Notes:    ingredient.Notes,
+			})
+		}
+		cmd.Ingredients = &list
+	}
+	if req.Instructions != nil {
+		list := []inbound.CreateInstructionCommand{}
+		for _, step := range *req.Instructions {
+			if step = strings.TrimSpace(step); step != "" {
+				list = append(list, inbound.CreateInstructionCommand{Description: step})
+			}
+		}
+		cmd.Instructions = &list
+	}
+	return cmd, nil
+}
@@ -0,0 +1,52 @@
+// Package handlers provides the what-if nutrition and cost simulator
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// WhatIfRequest is the ingredient changes to simulate
+type WhatIfRequest struct {
+	Changes []inbound.IngredientChange `json:"changes"`
+}
+
+// RecipeWhatIf handles POST /api/v1/recipes/{id}/what-if
+// The recipe page sends the swaps and quantity changes the reader is
+// trying, such as turkey for beef, and shows the estimated nutrition and
+// cost next to the recipe's own. Nothing is saved.
+func (h *APIHandlers) RecipeWhatIf(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	var req WhatIfRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	query := inbound.WhatIfQuery{RecipeID: recipeID, Changes: req.Changes}
+	if rawUserID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		query.RequesterID, _ = uuid.Parse(rawUserID)
+	}
+
+	result, err := h.recipeService.SimulateWhatIf(r.Context(), query)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    result,
+		Message: "What-if estimate ready",
+	})
+}
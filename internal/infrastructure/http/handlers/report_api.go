@@ -0,0 +1,165 @@
+// Package handlers provides reports of recipes and comments and the admin
+// report queue
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxReportBytes bounds a report or review body
+const maxReportBytes = 4 << 10
+
+// ReportRequest is a reader's report. Reason is spam, abuse,
+// inappropriate, copyright or other.
+type ReportRequest struct {
+	Reason string `json:"reason"`
+	Note   string `json:"note"`
+}
+
+// ReviewReportRequest carries the optional note recorded with an admin's
+// verdict
+type ReviewReportRequest struct {
+	Note string `json:"note"`
+}
+
+// ReportAPIHandlers serves content reports and the admin report queue
+type ReportAPIHandlers struct {
+	responder
+	reports inbound.ReportService
+}
+
+// NewReportAPIHandlers creates the report handlers
+func NewReportAPIHandlers(reports inbound.ReportService, logger *zap.Logger) *ReportAPIHandlers {
+	return &ReportAPIHandlers{
+		reports:   reports,
+		responder: responder{logger: logger},
+	}
+}
+
+// ReportRecipe handles POST /api/v1/recipes/{id}/report
+func (h *ReportAPIHandlers) ReportRecipe(w http.ResponseWriter, r *http.Request) {
+	h.report(w, r, "Invalid recipe ID", h.reports.ReportRecipe)
+}
+
+// ReportComment handles POST /api/v1/comments/{id}/report
+func (h *ReportAPIHandlers) ReportComment(w http.ResponseWriter, r *http.Request) {
+	h.report(w, r, "Invalid comment ID", h.reports.ReportComment)
+}
+
+func (h *ReportAPIHandlers) report(w http.ResponseWriter, r *http.Request, invalidID string, send func(ctx context.Context, cmd inbound.ReportContentCommand) (*inbound.ReportDTO, error)) {
+	reporterID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	targetID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, invalidID)
+		return
+	}
+
+	var req ReportRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	report, err := send(r.Context(), inbound.ReportContentCommand{
+		TargetID:   targetID,
+		ReporterID: reporterID,
+		Reason:     req.Reason,
+		Note:       req.Note,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    report,
+		Message: "Report received",
+	})
+}
+
+// ListReports handles GET /api/v1/admin/reports?page=&limit=
+func (h *ReportAPIHandlers) ListReports(w http.ResponseWriter, r *http.Request) {
+	requesterID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 50)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := parseIntParam(r, "page", 1)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reports, err := h.reports.ListOpenReports(r.Context(), requesterID, limit, (page-1)*limit)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    reports,
+		Message: "Reports retrieved successfully",
+	})
+}
+
+// ResolveReport handles POST /api/v1/admin/reports/{id}/resolve
+func (h *ReportAPIHandlers) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, "Report resolved", h.reports.ResolveReport)
+}
+
+// DismissReport handles POST /api/v1/admin/reports/{id}/dismiss
+func (h *ReportAPIHandlers) DismissReport(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, "Report dismissed", h.reports.DismissReport)
+}
+
+func (h *ReportAPIHandlers) review(w http.ResponseWriter, r *http.Request, message string, decide func(ctx context.Context, cmd inbound.ReviewReportCommand) (*inbound.ReportDTO, error)) {
+	moderatorID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	reportID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid report ID")
+		return
+	}
+
+	var req ReviewReportRequest
+	err = json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportBytes)).Decode(&req)
+	if err != nil && err != io.EOF {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	report, err := decide(r.Context(), inbound.ReviewReportCommand{
+		ReportID:    reportID,
+		ModeratorID: moderatorID,
+		Note:        req.Note,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    report,
+		Message: message,
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	apperrors "github.com/alchemorsel/v3/pkg/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// responder writes the APIResponse envelope and reads the signed-in caller.
+// API handler types embed it for these helpers and its logger.
+type responder struct {
+	logger *zap.Logger
+}
+
+func (h responder) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error("Failed to encode JSON response", zap.Error(err))
+	}
+}
+
+func (h responder) writeErrorJSON(w http.ResponseWriter, status int, message string) {
+	h.writeJSON(w, status, APIResponse{Success: false, Error: message})
+}
+
+// writeServiceError answers with the status and message of a service error,
+// logging the ones that are the server's fault
+func (h responder) writeServiceError(w http.ResponseWriter, err error) {
+	appErr := apperrors.Wrap(err, "request failed")
+	if appErr.StatusCode() >= http.StatusInternalServerError {
+		h.logger.Error("API request failed", zap.Error(err))
+	}
+	h.writeErrorJSON(w, appErr.StatusCode(), appErr.Message)
+}
+
+// userID returns the signed-in caller, answering 401 when there is none
+func (h responder) userID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	raw, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(raw)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
@@ -0,0 +1,181 @@
+// Package handlers provides response shaping for sparse fieldsets and includes
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+)
+
+// firstPacketBudget is the TCP initial congestion window payload size (ADR-0006).
+// Default field sets are chosen so a typical list page fits inside it.
+const firstPacketBudget = 14 * 1024
+
+// Relations that can be requested with ?include=
+const (
+	IncludeAuthor      = "author"
+	IncludeIngredients = "ingredients"
+	IncludeTags        = "tags"
+)
+
+// recipeFields lists every selectable top-level recipe attribute (JSON names)
+var recipeFields = map[string]bool{
+	"id": true, "title": true, "description": true, "author_id": true,
+	"author_name": true, "author_badge": true, "language": true, "instructions": true, "nutrition": true,
+	"cuisine": true, "category": true, "difficulty": true, "prep_time": true,
+	"cook_time": true, "total_time": true, "servings": true, "calories": true,
+	"images": true, "likes": true, "views": true, "rating": true,
+	"rating_count": true, "status": true, "ai_generated": true,
+	"created_at": true, "updated_at": true, "published_at": true,
+	"revision": true, "forks": true, "forked_from_id": true, "forked_from": true,
+	"allergens": true, "allergy_warnings": true,
+}
+
+// recipeIncludes lists relations that are only returned when explicitly included
+var recipeIncludes = map[string]bool{
+	IncludeAuthor:      true,
+	IncludeIngredients: true,
+	IncludeTags:        true,
+}
+
+// DefaultRecipeListFields is the compact field set used for list endpoints
+// when the client does not ask for specific fields
+var DefaultRecipeListFields = []string{
+	"id", "title", "author_badge", "cuisine", "difficulty", "total_time", "rating", "likes",
+	"allergens", "allergy_warnings",
+}
+
+// DefaultRecipeDetailFields is the field set used for single recipe reads
+// when the client does not ask for specific fields
+var DefaultRecipeDetailFields = []string{
+	"id", "title", "description", "author_id", "author_name", "author_badge", "language", "instructions",
+	"cuisine", "category", "difficulty", "prep_time", "cook_time", "total_time",
+	"servings", "calories", "nutrition", "images", "likes", "rating", "rating_count",
+	"status", "created_at", "updated_at", "published_at", "revision",
+	"forks", "forked_from", "allergens",
+}
+
+// FieldSelection describes which attributes and relations a client asked for
+type FieldSelection struct {
+	Fields   map[string]bool
+	Includes map[string]bool
+}
+
+// AuthorSummary is the embedded representation of ?include=author
+type AuthorSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ParseFieldSelection reads ?fields= and ?include= from the request.
+// Unknown names are rejected so typos don't silently return empty objects.
+func ParseFieldSelection(r *http.Request, defaults []string) (FieldSelection, error) {
+	sel := FieldSelection{
+		Fields:   make(map[string]bool),
+		Includes: make(map[string]bool),
+	}
+
+	query := r.URL.Query()
+
+	fields := splitList(query.Get("fields"))
+	if len(fields) == 0 {
+		fields = defaults
+	}
+	for _, f := range fields {
+		if !recipeFields[f] {
+			return sel, fmt.Errorf("unknown field %q", f)
+		}
+		sel.Fields[f] = true
+	}
+	// The identifier is always returned so clients can correlate results
+	sel.Fields["id"] = true
+
+	for _, inc := range splitList(query.Get("include")) {
+		if !recipeIncludes[inc] {
+			return sel, fmt.Errorf("unknown include %q", inc)
+		}
+		sel.Includes[inc] = true
+	}
+
+	return sel, nil
+}
+
+// ShapeRecipe projects a recipe DTO onto the selected fields and includes
+func ShapeRecipe(dto *inbound.RecipeDTO, sel FieldSelection) (map[string]interface{}, error) {
+	raw, err := json.Marshal(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	var full map[string]interface{}
+	if err := json.Unmarshal(raw, &full); err != nil {
+		return nil, err
+	}
+
+	shaped := make(map[string]interface{}, len(sel.Fields)+len(sel.Includes))
+	for field := range sel.Fields {
+		if value, ok := full[field]; ok {
+			shaped[field] = value
+		}
+	}
+
+	if sel.Includes[IncludeAuthor] {
+		shaped[IncludeAuthor] = AuthorSummary{
+			ID:   dto.AuthorID.String(),
+			Name: dto.AuthorName,
+		}
+	}
+	if sel.Includes[IncludeIngredients] {
+		shaped[IncludeIngredients] = full["ingredients"]
+	}
+	if sel.Includes[IncludeTags] {
+		shaped[IncludeTags] = full["tags"]
+	}
+
+	return shaped, nil
+}
+
+// ShapeRecipes projects a slice of recipe DTOs onto the selection
+func ShapeRecipes(dtos []inbound.RecipeDTO, sel FieldSelection) ([]map[string]interface{}, error) {
+	shaped := make([]map[string]interface{}, 0, len(dtos))
+	for i := range dtos {
+		item, err := ShapeRecipe(&dtos[i], sel)
+		if err != nil {
+			return nil, err
+		}
+		shaped = append(shaped, item)
+	}
+	return shaped, nil
+}
+
+// FieldNames returns the selected field names in stable order, used for
+// the X-Fields response header so caches and clients can see the projection
+func (s FieldSelection) FieldNames() []string {
+	names := make([]string, 0, len(s.Fields))
+	for f := range s.Fields {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// splitList splits a comma separated query value, trimming blanks
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	parts := strings.Split(value, ",")
+	result := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
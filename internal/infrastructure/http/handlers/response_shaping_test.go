@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFieldSelection(t *testing.T) {
+	t.Run("defaults when no fields requested", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/recipes", nil)
+		sel, err := ParseFieldSelection(r, DefaultRecipeListFields)
+		require.NoError(t, err)
+		assert.Len(t, sel.Fields, len(DefaultRecipeListFields))
+		assert.Empty(t, sel.Includes)
+	})
+
+	t.Run("explicit fields always keep id", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/recipes?fields=title,%20rating&include=author,tags", nil)
+		sel, err := ParseFieldSelection(r, DefaultRecipeListFields)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "rating", "title"}, sel.FieldNames())
+		assert.True(t, sel.Includes[IncludeAuthor])
+		assert.True(t, sel.Includes[IncludeTags])
+	})
+
+	t.Run("unknown names are rejected", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/recipes?fields=titel", nil)
+		_, err := ParseFieldSelection(r, DefaultRecipeListFields)
+		assert.Error(t, err)
+
+		r = httptest.NewRequest("GET", "/api/v1/recipes?include=comments", nil)
+		_, err = ParseFieldSelection(r, DefaultRecipeListFields)
+		assert.Error(t, err)
+	})
+}
+
+func TestShapeRecipe(t *testing.T) {
+	dto := &inbound.RecipeDTO{
+		ID:          uuid.New(),
+		Title:       "Shakshuka",
+		Description: "Eggs poached in spiced tomato sauce",
+		AuthorID:    uuid.New(),
+		AuthorName:  "Ada",
+		Ingredients: []inbound.IngredientDTO{{Name: "egg", Amount: 4}},
+		Tags:        []string{"breakfast"},
+		Rating:      4.5,
+	}
+
+	r := httptest.NewRequest("GET", "/api/v1/recipes/x?fields=title&include=author,ingredients", nil)
+	sel, err := ParseFieldSelection(r, DefaultRecipeDetailFields)
+	require.NoError(t, err)
+
+	shaped, err := ShapeRecipe(dto, sel)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Shakshuka", shaped["title"])
+	assert.Equal(t, dto.ID.String(), shaped["id"])
+	assert.NotContains(t, shaped, "description")
+	assert.NotContains(t, shaped, "rating")
+	assert.NotContains(t, shaped, "tags")
+	assert.Equal(t, AuthorSummary{ID: dto.AuthorID.String(), Name: "Ada"}, shaped["author"])
+	assert.Len(t, shaped["ingredients"], 1)
+}
@@ -0,0 +1,50 @@
+// Package handlers provides the sandbox status and outbox endpoints
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"go.uber.org/zap"
+)
+
+// SandboxAPIHandlers serves the sandbox banner and captured messages
+type SandboxAPIHandlers struct {
+	responder
+	sandbox inbound.SandboxService
+}
+
+// NewSandboxAPIHandlers creates the sandbox handlers
+func NewSandboxAPIHandlers(sandbox inbound.SandboxService, logger *zap.Logger) *SandboxAPIHandlers {
+	return &SandboxAPIHandlers{
+		sandbox:   sandbox,
+		responder: responder{logger: logger},
+	}
+}
+
+// Status handles GET /api/v1/sandbox
+func (h *SandboxAPIHandlers) Status(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    h.sandbox.Status(r.Context()),
+	})
+}
+
+// Outbox handles GET /api/v1/admin/sandbox/outbox
+func (h *SandboxAPIHandlers) Outbox(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	messages, err := h.sandbox.Outbox(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    map[string]interface{}{"messages": messages},
+	})
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ScaleRecipe handles GET /api/v1/recipes/{id}/scaled
+// Returns the ingredients recomputed for ?servings=, the recipe's own
+// servings without it, in ?units= or the caller's measurement preference.
+// Signed-in authors can scale their drafts too.
+func (h *APIHandlers) ScaleRecipe(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+	servings, err := parseIntParam(r, "servings", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := inbound.ScaleRecipeQuery{RecipeID: recipeID, Servings: servings, Units: r.URL.Query().Get("units")}
+	if raw, exists := middleware.GetUserIDFromContext(r.Context()); exists {
+		if id, err := uuid.Parse(raw); err == nil {
+			query.RequesterID = id
+		}
+	}
+
+	scaled, err := h.recipeService.ScaleRecipe(r.Context(), query)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    scaled,
+		Message: "Recipe scaled",
+	})
+}
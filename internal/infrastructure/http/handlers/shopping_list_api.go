@@ -0,0 +1,318 @@
+// Package handlers provides shared shopping lists and their live event stream
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxShoppingListOpsBytes bounds an operation batch, enough for a long
+	// offline queue
+	maxShoppingListOpsBytes = 256 << 10
+	// shoppingListStreamLifetime ends event streams before the server's 30s
+	// write timeout cuts them; clients reconnect with Last-Event-ID and
+	// replay what they missed
+	shoppingListStreamLifetime = 25 * time.Second
+	// shoppingListHeartbeat keeps proxies from closing an idle stream
+	shoppingListHeartbeat = 10 * time.Second
+	// shoppingListRetryMS is the reconnect delay sent to EventSource clients
+	shoppingListRetryMS = 1000
+)
+
+// ShoppingListAPIHandlers serves shared shopping lists
+type ShoppingListAPIHandlers struct {
+	responder
+	lists inbound.ShoppingListService
+}
+
+// NewShoppingListAPIHandlers creates the shopping list handlers
+func NewShoppingListAPIHandlers(lists inbound.ShoppingListService, logger *zap.Logger) *ShoppingListAPIHandlers {
+	return &ShoppingListAPIHandlers{
+		lists:     lists,
+		responder: responder{logger: logger},
+	}
+}
+
+// CreateShoppingListRequest names a new list
+type CreateShoppingListRequest struct {
+	Name string `json:"name"`
+}
+
+// ShareShoppingListRequest shares a list with a registered user
+type ShareShoppingListRequest struct {
+	Email string `json:"email"`
+}
+
+// ShoppingListOpsRequest is a batch of operations, in the order the client
+// made them
+type ShoppingListOpsRequest struct {
+	Ops []inbound.ShoppingListOp `json:"ops"`
+}
+
+// CreateList handles POST /api/v1/shopping-lists
+func (h *ShoppingListAPIHandlers) CreateList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateShoppingListRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBeaconBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	list, err := h.lists.CreateList(r.Context(), userID, req.Name)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    list,
+		Message: "Shopping list created",
+	})
+}
+
+// ListLists handles GET /api/v1/shopping-lists
+func (h *ShoppingListAPIHandlers) ListLists(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	lists, err := h.lists.ListLists(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    lists,
+		Message: "Shopping lists retrieved successfully",
+	})
+}
+
+// GetList handles GET /api/v1/shopping-lists/{id}
+// Includes who is viewing the list right now.
+func (h *ShoppingListAPIHandlers) GetList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	listID, ok := h.listID(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := h.lists.GetList(r.Context(), listID, userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    list,
+		Message: "Shopping list retrieved successfully",
+	})
+}
+
+// ShareList handles POST /api/v1/shopping-lists/{id}/members
+func (h *ShoppingListAPIHandlers) ShareList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	listID, ok := h.listID(w, r)
+	if !ok {
+		return
+	}
+
+	var req ShareShoppingListRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBeaconBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	list, err := h.lists.ShareList(r.Context(), inbound.ShareShoppingListCommand{
+		ListID:  listID,
+		OwnerID: userID,
+		Email:   req.Email,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    list,
+		Message: "Shopping list shared",
+	})
+}
+
+// ApplyOps handles POST /api/v1/shopping-lists/{id}/ops
+// Applies the operations in order and reports an outcome for each, so a
+// client replaying its offline queue can tell which changes lost to
+// someone else's.
+func (h *ShoppingListAPIHandlers) ApplyOps(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	listID, ok := h.listID(w, r)
+	if !ok {
+		return
+	}
+
+	var req ShoppingListOpsRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxShoppingListOpsBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	result, err := h.lists.ApplyOperations(r.Context(), inbound.ApplyShoppingListOpsCommand{
+		ListID: listID,
+		UserID: userID,
+		Ops:    req.Ops,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    result,
+		Message: "Operations applied",
+	})
+}
+
+// Events handles GET /api/v1/shopping-lists/{id}/events
+// Streams change, snapshot and presence events as server-sent events. The
+// stream ends after shoppingListStreamLifetime; EventSource reconnects on
+// its own, sending Last-Event-ID so only missed changes are replayed.
+// Clients without EventSource can pass ?since=<version> instead.
+func (h *ShoppingListAPIHandlers) Events(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	listID, ok := h.listID(w, r)
+	if !ok {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.writeErrorJSON(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	cmd := inbound.SubscribeShoppingListCommand{ListID: listID, UserID: userID}
+	since := r.Header.Get("Last-Event-ID")
+	if since == "" {
+		since = r.URL.Query().Get("since")
+	}
+	if since != "" {
+		version, err := strconv.ParseInt(since, 10, 64)
+		if err != nil || version < 0 {
+			h.writeErrorJSON(w, http.StatusBadRequest, "Invalid event ID")
+			return
+		}
+		cmd.Since = &version
+	}
+
+	sub, err := h.lists.Subscribe(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", shoppingListRetryMS)
+
+	var lastID int64
+	for _, event := range sub.Replay {
+		if err := h.writeEvent(w, event); err != nil {
+			return
+		}
+		lastID = event.ID
+	}
+	flusher.Flush()
+
+	lifetime := time.NewTimer(shoppingListStreamLifetime)
+	defer lifetime.Stop()
+	heartbeat := time.NewTicker(shoppingListHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-lifetime.C:
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case event, open := <-sub.Events:
+			if !open {
+				// Dropped for falling behind; the client reconnects and
+				// catches up from its last event ID
+				return
+			}
+			if event.Type == inbound.ShoppingListEventChange && event.ID <= lastID {
+				continue
+			}
+			if err := h.writeEvent(w, event); err != nil {
+				return
+			}
+			if event.Type != inbound.ShoppingListEventPresence {
+				lastID = event.ID
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes one server-sent event. Presence events carry no ID so
+// they do not move the client's Last-Event-ID.
+func (h *ShoppingListAPIHandlers) writeEvent(w http.ResponseWriter, event inbound.ShoppingListEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		h.logger.Error("Failed to encode shopping list event", zap.Error(err))
+		return err
+	}
+	if event.Type != inbound.ShoppingListEventPresence {
+		if _, err := fmt.Fprintf(w, "id: %d\n", event.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
+
+func (h *ShoppingListAPIHandlers) listID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid shopping list ID")
+		return uuid.Nil, false
+	}
+	return listID, true
+}
@@ -0,0 +1,50 @@
+// Package handlers provides HTTP handlers for ingredient substitutes
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SubstitutionAPIHandlers serve ingredient substitutes
+type SubstitutionAPIHandlers struct {
+	responder
+	substitutes inbound.SubstitutionService
+}
+
+// NewSubstitutionAPIHandlers creates the substitution handlers
+func NewSubstitutionAPIHandlers(substitutes inbound.SubstitutionService, logger *zap.Logger) *SubstitutionAPIHandlers {
+	return &SubstitutionAPIHandlers{
+		substitutes: substitutes,
+		responder:   responder{logger: logger},
+	}
+}
+
+// IngredientSubstitutes handles GET /api/v1/ingredients/{name}/substitutes
+// Signed-in users get only the substitutes that suit their dietary
+// preferences.
+func (h *SubstitutionAPIHandlers) IngredientSubstitutes(w http.ResponseWriter, r *http.Request) {
+	query := inbound.SubstitutesQuery{Ingredient: chi.URLParam(r, "name")}
+	if raw, exists := middleware.GetUserIDFromContext(r.Context()); exists {
+		if id, err := uuid.Parse(raw); err == nil {
+			query.RequesterID = id
+		}
+	}
+
+	result, err := h.substitutes.Substitutes(r.Context(), query)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    result,
+		Message: "Ingredient substitutes retrieved successfully",
+	})
+}
@@ -0,0 +1,107 @@
+// Package handlers provides HTTP handlers for offline device sync
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"go.uber.org/zap"
+)
+
+// SyncAPIHandlers serve the push and pull endpoints devices use to sync
+// bookmarks, notes and pointers to shopping lists and drafts
+type SyncAPIHandlers struct {
+	responder
+	sync inbound.SyncService
+}
+
+// NewSyncAPIHandlers creates the sync handlers
+func NewSyncAPIHandlers(sync inbound.SyncService, logger *zap.Logger) *SyncAPIHandlers {
+	return &SyncAPIHandlers{
+		sync:      sync,
+		responder: responder{logger: logger},
+	}
+}
+
+// SyncPushRequest is the queue of changes a device made
+type SyncPushRequest struct {
+	DeviceID string               `json:"device_id"`
+	Changes  []inbound.SyncChange `json:"changes"`
+}
+
+// Push handles POST /api/v1/sync/push
+// Devices send the changes they queued, with the time the user made each.
+// Every change gets an outcome; on a conflict the device adopts the
+// returned record.
+func (h *SyncAPIHandlers) Push(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req SyncPushRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	result, err := h.sync.Push(r.Context(), inbound.SyncPushCommand{
+		UserID:   userID,
+		DeviceID: req.DeviceID,
+		Changes:  req.Changes,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    result,
+		Message: "Changes synced",
+	})
+}
+
+// Pull handles GET /api/v1/sync/pull?device_id=<id>&cursor=<cursor>
+// Devices send the cursor from their last pull, or none to start over, and
+// keep pulling while has_more is set.
+func (h *SyncAPIHandlers) Pull(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var cursor int64
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		var err error
+		cursor, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || cursor < 0 {
+			h.writeErrorJSON(w, http.StatusBadRequest, "cursor must be a sequence number")
+			return
+		}
+	}
+	limit, err := parseIntParam(r, "limit", 200)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.sync.Pull(r.Context(), inbound.SyncPullQuery{
+		UserID:   userID,
+		DeviceID: r.URL.Query().Get("device_id"),
+		Cursor:   cursor,
+		Limit:    limit,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    result,
+		Message: "Changes retrieved successfully",
+	})
+}
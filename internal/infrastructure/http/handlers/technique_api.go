@@ -0,0 +1,187 @@
+// Package handlers provides the technique library endpoints
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxTechniqueBytes bounds a technique body: twenty long how-to steps
+const maxTechniqueBytes = 64 << 10
+
+// TechniqueAPIHandlers serves the technique library and recipe step links
+type TechniqueAPIHandlers struct {
+	responder
+	techniques inbound.TechniqueService
+}
+
+// NewTechniqueAPIHandlers creates the technique handlers
+func NewTechniqueAPIHandlers(techniques inbound.TechniqueService, logger *zap.Logger) *TechniqueAPIHandlers {
+	return &TechniqueAPIHandlers{
+		techniques: techniques,
+		responder:  responder{logger: logger},
+	}
+}
+
+// LinkStepTechniquesRequest sets the techniques of one recipe step
+type LinkStepTechniquesRequest struct {
+	Techniques []string `json:"techniques"`
+}
+
+// ListTechniques handles GET /api/v1/techniques
+func (h *TechniqueAPIHandlers) ListTechniques(w http.ResponseWriter, r *http.Request) {
+	techniques, err := h.techniques.List(r.Context())
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    techniques,
+		Message: "Techniques retrieved successfully",
+	})
+}
+
+// GetTechnique handles GET /api/v1/techniques/{slug}
+func (h *TechniqueAPIHandlers) GetTechnique(w http.ResponseWriter, r *http.Request) {
+	detail, err := h.techniques.Get(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    detail,
+		Message: "Technique retrieved successfully",
+	})
+}
+
+// SaveTechnique handles PUT /api/v1/techniques/{slug}
+func (h *TechniqueAPIHandlers) SaveTechnique(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var cmd inbound.SaveTechniqueCommand
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTechniqueBytes)).Decode(&cmd); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	cmd.RequesterID = userID
+	cmd.Slug = chi.URLParam(r, "slug")
+
+	detail, err := h.techniques.Save(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    detail,
+		Message: "Technique saved",
+	})
+}
+
+// DeleteTechnique handles DELETE /api/v1/techniques/{slug}
+func (h *TechniqueAPIHandlers) DeleteTechnique(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.techniques.Delete(r.Context(), userID, chi.URLParam(r, "slug")); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Technique deleted",
+	})
+}
+
+// RecipeSteps handles GET /api/v1/recipes/{id}/steps
+// Temperatures in the steps follow ?units= or the caller's preference.
+func (h *TechniqueAPIHandlers) RecipeSteps(w http.ResponseWriter, r *http.Request) {
+	// Anonymous readers see published recipes only
+	requesterID := uuid.Nil
+	if raw, exists := middleware.GetUserIDFromContext(r.Context()); exists {
+		if id, err := uuid.Parse(raw); err == nil {
+			requesterID = id
+		}
+	}
+
+	steps, err := h.techniques.RecipeSteps(r.Context(), requesterID, chi.URLParam(r, "id"), r.URL.Query().Get("units"))
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    steps,
+		Message: "Recipe steps retrieved successfully",
+	})
+}
+
+// LinkStepTechniques handles PUT /api/v1/recipes/{id}/steps/{step}/techniques
+func (h *TechniqueAPIHandlers) LinkStepTechniques(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	step, err := strconv.Atoi(chi.URLParam(r, "step"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid step number")
+		return
+	}
+
+	var req LinkStepTechniquesRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBeaconBytes)).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	steps, err := h.techniques.LinkStep(r.Context(), userID, chi.URLParam(r, "id"), step, req.Techniques)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    steps,
+		Message: "Step techniques linked",
+	})
+}
+
+// SuggestStepTechniques handles POST /api/v1/recipes/{id}/steps/suggest-techniques
+func (h *TechniqueAPIHandlers) SuggestStepTechniques(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	steps, err := h.techniques.SuggestLinks(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    steps,
+		Message: "Step techniques suggested",
+	})
+}
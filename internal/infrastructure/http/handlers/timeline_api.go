@@ -0,0 +1,185 @@
+// Package handlers provides the recipe timeline endpoints
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TimelineAPIHandlers serves backward scheduled recipe timelines
+type TimelineAPIHandlers struct {
+	responder
+	timelines inbound.RecipeTimelineService
+}
+
+// NewTimelineAPIHandlers creates the recipe timeline handlers
+func NewTimelineAPIHandlers(timelines inbound.RecipeTimelineService, logger *zap.Logger) *TimelineAPIHandlers {
+	return &TimelineAPIHandlers{
+		timelines: timelines,
+		responder: responder{logger: logger},
+	}
+}
+
+// RecipeTimeline handles GET /api/v1/recipes/{id}/timeline
+func (h *TimelineAPIHandlers) RecipeTimeline(w http.ResponseWriter, r *http.Request) {
+	timeline, ok := h.plan(w, r)
+	if !ok {
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    timeline,
+		Message: "Recipe timeline planned",
+	})
+}
+
+// RecipeTimelineCalendar handles GET /api/v1/recipes/{id}/timeline.ics: the
+// timeline as one calendar event per step
+func (h *TimelineAPIHandlers) RecipeTimelineCalendar(w http.ResponseWriter, r *http.Request) {
+	timeline, ok := h.plan(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="recipe-`+timeline.RecipeID+`-timeline.ics"`)
+	w.WriteHeader(http.StatusOK)
+	if err := writeTimelineICS(w, timeline, time.Now().UTC()); err != nil {
+		h.logger.Error("Failed to write timeline calendar", zap.Error(err))
+	}
+}
+
+// plan reads serve_at (RFC 3339) and completed (steps done so far)
+func (h *TimelineAPIHandlers) plan(w http.ResponseWriter, r *http.Request) (*inbound.RecipeTimeline, bool) {
+	query := inbound.TimelineQuery{RecipeID: chi.URLParam(r, "id")}
+	if raw, exists := middleware.GetUserIDFromContext(r.Context()); exists {
+		if id, err := uuid.Parse(raw); err == nil {
+			query.RequesterID = id
+		}
+	}
+	if raw := r.URL.Query().Get("serve_at"); raw != "" {
+		serveAt, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			h.writeErrorJSON(w, http.StatusBadRequest, "serve_at must be an RFC 3339 time")
+			return nil, false
+		}
+		query.ServeAt = serveAt
+	}
+	if raw := r.URL.Query().Get("completed"); raw != "" {
+		completed, err := strconv.Atoi(raw)
+		if err != nil || completed < 0 {
+			h.writeErrorJSON(w, http.StatusBadRequest, "completed must be a non-negative integer")
+			return nil, false
+		}
+		query.CompletedSteps = completed
+	}
+
+	timeline, err := h.timelines.Plan(r.Context(), query)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return nil, false
+	}
+	return timeline, true
+}
+
+// writeTimelineICS writes an iCalendar (RFC 5545) file with one event per
+// step, so the cook's calendar reminds them when each step starts
+func writeTimelineICS(w io.Writer, t *inbound.RecipeTimeline, stamp time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Alchemorsel//Recipe Timeline//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + icsText(t.Title),
+	}
+	for _, step := range t.Steps {
+		start, err := time.Parse(time.RFC3339, step.StartAt)
+		if err != nil {
+			return err
+		}
+		end, err := time.Parse(time.RFC3339, step.EndAt)
+		if err != nil {
+			return err
+		}
+		summary := fmt.Sprintf("Step %d: %s", step.Number, step.Text)
+		if step.HandsOff {
+			summary += " (hands-off)"
+		}
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-step-%d@alchemorsel", t.RecipeID, step.Number),
+			"DTSTAMP:"+icsTime(stamp),
+			"DTSTART:"+icsTime(start),
+			"DTEND:"+icsTime(end),
+			"SUMMARY:"+icsText(summary),
+			"DESCRIPTION:"+icsText(t.Title),
+			"END:VEVENT",
+		)
+	}
+	if serveAt, err := time.Parse(time.RFC3339, t.ServeAt); err == nil {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-serve@alchemorsel", t.RecipeID),
+			"DTSTAMP:"+icsTime(stamp),
+			"DTSTART:"+icsTime(serveAt),
+			"DTEND:"+icsTime(serveAt),
+			"SUMMARY:"+icsText("Serve "+t.Title),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, icsFold(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsText escapes a TEXT value
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func icsText(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// icsFold splits lines longer than 75 octets, continuing them with a
+// space, without cutting a UTF-8 sequence
+func icsFold(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+	var b strings.Builder
+	width := limit
+	for len(line) > width {
+		cut := width
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines lose one octet to the leading space
+		width = limit - 1
+	}
+	b.WriteString(line)
+	return b.String()
+}
@@ -0,0 +1,137 @@
+// Package handlers provides HTTP handlers for recipe translations
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TranslationAPIHandlers serves recipe translations: machine translation
+// on request, and corrections by the recipe's author
+type TranslationAPIHandlers struct {
+	responder
+	translations inbound.TranslationService
+}
+
+// NewTranslationAPIHandlers creates the translation handlers
+func NewTranslationAPIHandlers(translations inbound.TranslationService, logger *zap.Logger) *TranslationAPIHandlers {
+	return &TranslationAPIHandlers{
+		translations: translations,
+		responder:    responder{logger: logger},
+	}
+}
+
+// EditTranslationRequest is the author's text for one translated field
+type EditTranslationRequest struct {
+	Text string `json:"text"`
+}
+
+// RecipeLanguages handles GET /api/v1/recipes/{id}/translations
+func (h *TranslationAPIHandlers) RecipeLanguages(w http.ResponseWriter, r *http.Request) {
+	languages, err := h.translations.Languages(r.Context(), h.query(r))
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    languages,
+		Message: "Recipe translations retrieved successfully",
+	})
+}
+
+// RecipeTranslation handles GET /api/v1/recipes/{id}/translations/{lang}
+// Fields not yet translated are returned in the recipe's own language.
+func (h *TranslationAPIHandlers) RecipeTranslation(w http.ResponseWriter, r *http.Request) {
+	translation, err := h.translations.Translation(r.Context(), h.query(r))
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    translation,
+		Message: "Recipe translation retrieved successfully",
+	})
+}
+
+// TranslateRecipe handles POST /api/v1/recipes/{id}/translations/{lang}
+// Machine-translates new and changed fields; human edits are kept.
+func (h *TranslationAPIHandlers) TranslateRecipe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	translation, err := h.translations.Translate(r.Context(), inbound.TranslateRecipeCommand{
+		UserID:   userID,
+		RecipeID: chi.URLParam(r, "id"),
+		Language: chi.URLParam(r, "lang"),
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    translation,
+		Message: "Recipe translated",
+	})
+}
+
+// EditTranslationField handles PUT /api/v1/recipes/{id}/translations/{lang}/fields/{field}
+// Body: {"text": "..."}. Fields are title, description, ingredients.N and
+// steps.N, numbered from 1.
+func (h *TranslationAPIHandlers) EditTranslationField(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req EditTranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	translation, err := h.translations.EditField(r.Context(), inbound.EditTranslationCommand{
+		UserID:   userID,
+		RecipeID: chi.URLParam(r, "id"),
+		Language: chi.URLParam(r, "lang"),
+		Field:    chi.URLParam(r, "field"),
+		Text:     req.Text,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    translation,
+		Message: "Translation updated",
+	})
+}
+
+// query reads the recipe, language and optional requester
+func (h *TranslationAPIHandlers) query(r *http.Request) inbound.TranslationQuery {
+	query := inbound.TranslationQuery{
+		RecipeID: chi.URLParam(r, "id"),
+		Language: chi.URLParam(r, "lang"),
+	}
+	if raw, exists := middleware.GetUserIDFromContext(r.Context()); exists {
+		if id, err := uuid.Parse(raw); err == nil {
+			query.RequesterID = id
+		}
+	}
+	return query
+}
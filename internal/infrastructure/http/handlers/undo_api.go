@@ -0,0 +1,129 @@
+// Package handlers provides destructive recipe endpoints that return undo tokens
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/application/undo"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// UndoAPIHandlers serves delete/unpublish actions and redeems their undo tokens
+type UndoAPIHandlers struct {
+	responder
+	recipeService inbound.RecipeService
+	undo          *undo.Service
+}
+
+// NewUndoAPIHandlers creates the handlers and registers the recipe restorers
+func NewUndoAPIHandlers(
+	recipeService inbound.RecipeService,
+	undoService *undo.Service,
+	logger *zap.Logger,
+) *UndoAPIHandlers {
+	undoService.Register(undo.KindRecipeDelete, func(ctx context.Context, action undo.Action) error {
+		return recipeService.RestoreRecipe(ctx, action.SubjectID, action.UserID)
+	})
+	undoService.Register(undo.KindRecipeUnpublish, func(ctx context.Context, action undo.Action) error {
+		return recipeService.UnarchiveRecipe(ctx, action.SubjectID, action.UserID)
+	})
+
+	return &UndoAPIHandlers{
+		recipeService: recipeService,
+		undo:          undoService,
+		responder:     responder{logger: logger},
+	}
+}
+
+// UndoResponse tells the client how to revert the action it just performed
+type UndoResponse struct {
+	Token     string    `json:"undo_token"`
+	Kind      undo.Kind `json:"kind"`
+	SubjectID string    `json:"subject_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// TTLSeconds lets clients size the toast countdown without clock sync
+	TTLSeconds int `json:"ttl_seconds"`
+}
+
+// DeleteRecipe handles DELETE /api/v1/recipes/{id}
+func (h *UndoAPIHandlers) DeleteRecipe(w http.ResponseWriter, r *http.Request) {
+	h.perform(w, r, undo.KindRecipeDelete, "Recipe deleted", h.recipeService.DeleteRecipe)
+}
+
+// UnpublishRecipe handles POST /api/v1/recipes/{id}/unpublish
+func (h *UndoAPIHandlers) UnpublishRecipe(w http.ResponseWriter, r *http.Request) {
+	h.perform(w, r, undo.KindRecipeUnpublish, "Recipe unpublished", h.recipeService.ArchiveRecipe)
+}
+
+// Undo handles POST /api/v1/undo/{token}
+func (h *UndoAPIHandlers) Undo(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	action, err := h.undo.Undo(r.Context(), chi.URLParam(r, "token"), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"kind":       action.Kind,
+			"subject_id": action.SubjectID.String(),
+		},
+		Message: "Action undone",
+	})
+}
+
+// perform runs a destructive recipe command and records it for undo
+func (h *UndoAPIHandlers) perform(
+	w http.ResponseWriter,
+	r *http.Request,
+	kind undo.Kind,
+	message string,
+	command func(ctx context.Context, recipeID, userID uuid.UUID) error,
+) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	recipeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	if err := command(r.Context(), recipeID, userID); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	action, err := h.undo.Record(kind, userID, recipeID)
+	if err != nil {
+		// The action itself succeeded; the client just can't offer undo
+		h.logger.Error("Failed to record undo action", zap.String("kind", string(kind)), zap.Error(err))
+		h.writeJSON(w, http.StatusOK, APIResponse{Success: true, Message: message})
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data: UndoResponse{
+			Token:      action.Token,
+			Kind:       action.Kind,
+			SubjectID:  recipeID.String(),
+			ExpiresAt:  action.ExpiresAt,
+			TTLSeconds: int(h.undo.TTL().Seconds()),
+		},
+		Message: message,
+	})
+}
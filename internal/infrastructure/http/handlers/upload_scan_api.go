@@ -0,0 +1,51 @@
+// Package handlers provides the upload virus scan endpoints
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"go.uber.org/zap"
+)
+
+// UploadScanAPIHandlers lists the virus scans of uploads
+type UploadScanAPIHandlers struct {
+	responder
+	scans inbound.UploadScanService
+}
+
+// NewUploadScanAPIHandlers creates the upload scan handlers
+func NewUploadScanAPIHandlers(scans inbound.UploadScanService, logger *zap.Logger) *UploadScanAPIHandlers {
+	return &UploadScanAPIHandlers{
+		scans:     scans,
+		responder: responder{logger: logger},
+	}
+}
+
+// ListScans handles GET /api/v1/admin/upload-scans?verdict=&limit=
+func (h *UploadScanAPIHandlers) ListScans(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scans, err := h.scans.ListUploadScans(r.Context(), userID, inbound.UploadScanQuery{
+		Verdict: r.URL.Query().Get("verdict"),
+		Limit:   limit,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    scans,
+		Message: "Upload scans retrieved successfully",
+	})
+}
@@ -0,0 +1,92 @@
+// Package handlers provides recipe import from web page addresses
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ImportURLRequest is the payload for POST /api/v1/recipes/import. With
+// preview set the draft is returned without being saved.
+type ImportURLRequest struct {
+	URL     string `json:"url"`
+	Preview bool   `json:"preview"`
+}
+
+// URLImportAPIHandlers serves recipe import from URLs
+type URLImportAPIHandlers struct {
+	responder
+	imports inbound.URLImportService
+}
+
+// NewURLImportAPIHandlers creates the URL import handlers
+func NewURLImportAPIHandlers(imports inbound.URLImportService, logger *zap.Logger) *URLImportAPIHandlers {
+	return &URLImportAPIHandlers{
+		imports:   imports,
+		responder: responder{logger: logger},
+	}
+}
+
+// ImportRecipeURL handles POST /api/v1/recipes/import. The page is fetched
+// and read for schema.org JSON-LD or microdata, falling back to its
+// headings and lists, then previewed or saved as a draft.
+func (h *URLImportAPIHandlers) ImportRecipeURL(w http.ResponseWriter, r *http.Request) {
+	rawUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 8<<10)
+	var req ImportURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if strings.TrimSpace(req.URL) == "" {
+		h.writeErrorJSON(w, http.StatusBadRequest, "url is required")
+		return
+	}
+	cmd := inbound.ImportURLCommand{UserID: userID, URL: req.URL}
+
+	if req.Preview {
+		preview, err := h.imports.Preview(r.Context(), cmd)
+		if err != nil {
+			h.writeServiceError(w, err)
+			return
+		}
+		h.writeJSON(w, http.StatusOK, APIResponse{
+			Success: true,
+			Data:    preview,
+			Message: "Recipe read from the page",
+		})
+		return
+	}
+
+	result, err := h.imports.Import(r.Context(), cmd)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	status, message := http.StatusCreated, "Recipe imported as a draft"
+	if result.Status == "duplicate" {
+		status, message = http.StatusOK, "You already have this recipe"
+	}
+	h.writeJSON(w, status, APIResponse{
+		Success: true,
+		Data:    result,
+		Message: message,
+	})
+}
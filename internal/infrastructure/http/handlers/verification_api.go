@@ -0,0 +1,296 @@
+// Package handlers provides the author verification endpoints
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// VerificationAPIHandlers serves verification claims, badges and fake
+// claim reports
+type VerificationAPIHandlers struct {
+	responder
+	verification inbound.VerificationService
+}
+
+// NewVerificationAPIHandlers creates the verification handlers
+func NewVerificationAPIHandlers(verification inbound.VerificationService, logger *zap.Logger) *VerificationAPIHandlers {
+	return &VerificationAPIHandlers{
+		verification: verification,
+		responder:    responder{logger: logger},
+	}
+}
+
+// VerificationClaimRequest is an author's claim to a badge
+type VerificationClaimRequest struct {
+	Kind        string   `json:"kind"`
+	DisplayName string   `json:"display_name"`
+	Statement   string   `json:"statement"`
+	Evidence    []string `json:"evidence"`
+}
+
+// VerificationReportRequest reports a badge as fake
+type VerificationReportRequest struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+}
+
+// VerificationReviewRequest is an admin's decision on a claim
+type VerificationReviewRequest struct {
+	Decision string `json:"decision"`
+	Note     string `json:"note"`
+}
+
+// VerificationResolveRequest is an admin's decision on a report
+type VerificationResolveRequest struct {
+	Uphold bool   `json:"uphold"`
+	Note   string `json:"note"`
+}
+
+// AuthorVerification is an author's public verification status
+type AuthorVerification struct {
+	UserID   string                     `json:"user_id"`
+	Verified bool                       `json:"verified"`
+	Badge    *inbound.VerificationBadge `json:"badge,omitempty"`
+}
+
+// SubmitClaim handles POST /api/v1/verification/claims
+func (h *VerificationAPIHandlers) SubmitClaim(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req VerificationClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	claim, err := h.verification.SubmitClaim(r.Context(), inbound.SubmitVerificationClaimCommand{
+		UserID:      userID,
+		Kind:        req.Kind,
+		DisplayName: req.DisplayName,
+		Statement:   req.Statement,
+		Evidence:    req.Evidence,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    claim,
+		Message: "Verification claim submitted for review",
+	})
+}
+
+// MyClaim handles GET /api/v1/verification/claims/mine
+func (h *VerificationAPIHandlers) MyClaim(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	claim, err := h.verification.MyClaim(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	if claim == nil {
+		h.writeErrorJSON(w, http.StatusNotFound, "You have not claimed verification")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    claim,
+		Message: "Verification claim retrieved successfully",
+	})
+}
+
+// AuthorBadge handles GET /api/v1/users/{id}/verification
+func (h *VerificationAPIHandlers) AuthorBadge(w http.ResponseWriter, r *http.Request) {
+	authorID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	badge, err := h.verification.Badge(r.Context(), authorID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data: AuthorVerification{
+			UserID:   authorID.String(),
+			Verified: badge != nil,
+			Badge:    badge,
+		},
+		Message: "Verification status retrieved successfully",
+	})
+}
+
+// ReportAuthor handles POST /api/v1/users/{id}/verification/reports
+func (h *VerificationAPIHandlers) ReportAuthor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	authorID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req VerificationReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	report, err := h.verification.ReportClaim(r.Context(), inbound.ReportVerificationCommand{
+		ReporterID: userID,
+		AuthorID:   authorID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    report,
+		Message: "Report received; an admin will review it",
+	})
+}
+
+// ListClaims handles GET /api/v1/admin/verification/claims?status=&limit=
+func (h *VerificationAPIHandlers) ListClaims(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claims, err := h.verification.ListClaims(r.Context(), userID, inbound.VerificationClaimQuery{
+		Status: r.URL.Query().Get("status"),
+		Limit:  limit,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    claims,
+		Message: "Verification claims retrieved successfully",
+	})
+}
+
+// ReviewClaim handles POST /api/v1/admin/verification/claims/{id}/review
+func (h *VerificationAPIHandlers) ReviewClaim(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req VerificationReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	claim, err := h.verification.ReviewClaim(r.Context(), inbound.ReviewVerificationClaimCommand{
+		ReviewerID: userID,
+		ClaimID:    chi.URLParam(r, "id"),
+		Decision:   req.Decision,
+		Note:       req.Note,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    claim,
+		Message: "Verification claim " + claim.Status,
+	})
+}
+
+// ListReports handles GET /api/v1/admin/verification/reports?status=&limit=
+func (h *VerificationAPIHandlers) ListReports(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	limit, err := parseIntParam(r, "limit", 0)
+	if err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reports, err := h.verification.ListReports(r.Context(), userID, inbound.VerificationReportQuery{
+		Status: r.URL.Query().Get("status"),
+		Limit:  limit,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    reports,
+		Message: "Verification reports retrieved successfully",
+	})
+}
+
+// ResolveReport handles POST /api/v1/admin/verification/reports/{id}/resolve
+// Upholding a report revokes the author's badge.
+func (h *VerificationAPIHandlers) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req VerificationResolveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorJSON(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	report, err := h.verification.ResolveReport(r.Context(), inbound.ResolveVerificationReportCommand{
+		ResolverID: userID,
+		ReportID:   chi.URLParam(r, "id"),
+		Uphold:     req.Uphold,
+		Note:       req.Note,
+	})
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data:    report,
+		Message: "Verification report " + report.Status,
+	})
+}
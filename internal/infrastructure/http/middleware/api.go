@@ -8,7 +8,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/alchemorsel/v3/internal/domain/user"
 	"github.com/alchemorsel/v3/internal/infrastructure/security"
+	"github.com/alchemorsel/v3/pkg/svcauth"
 	"go.uber.org/zap"
 )
 
@@ -76,7 +78,7 @@ func CORS() func(next http.Handler) http.Handler {
 			// Set CORS headers
 			w.Header().Set("Access-Control-Allow-Origin", "*") // Configure appropriately for production
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Guest-Token")
 			w.Header().Set("Access-Control-Max-Age", "86400")
 			
 			// Handle preflight requests
@@ -97,10 +99,11 @@ func JSONOnly() func(next http.Handler) http.Handler {
 			// Force JSON content type for all API responses
 			w.Header().Set("Content-Type", "application/json")
 			
-			// Only accept JSON requests for POST/PUT
+			// Only accept JSON requests for POST/PUT, and multipart forms
+			// for the upload endpoints
 			if r.Method == "POST" || r.Method == "PUT" || r.Method == "PATCH" {
 				contentType := r.Header.Get("Content-Type")
-				if !strings.Contains(contentType, "application/json") {
+				if !strings.Contains(contentType, "application/json") && !strings.HasPrefix(contentType, "multipart/form-data") {
 					w.WriteHeader(http.StatusUnsupportedMediaType)
 					fmt.Fprint(w, `{"error":"Content-Type must be application/json"}`)
 					return
@@ -112,10 +115,83 @@ func JSONOnly() func(next http.Handler) http.Handler {
 	}
 }
 
-// AuthenticateAPI provides JWT authentication for API endpoints
+// ExceptPaths applies mw to every request but those whose path starts with
+// one of prefixes. Streamed exports use it to skip the request timeout and
+// response compression, which would cut them off or buffer them.
+func ExceptPaths(mw func(http.Handler) http.Handler, prefixes ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ServiceAuth verifies requests signed by another Alchemorsel service and
+// adds the user they were made for to the context. Unsigned requests pass
+// through to the usual token checks; badly signed ones are rejected.
+func ServiceAuth(verifier *svcauth.Verifier, logger *zap.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !svcauth.Signed(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			
+			caller, err := verifier.Verify(r)
+			if err != nil {
+				logger.Warn("Rejected service request",
+					zap.String("service", r.Header.Get(svcauth.HeaderService)),
+					zap.String("path", r.URL.Path),
+					zap.String("remote_addr", r.RemoteAddr),
+					zap.Error(err),
+				)
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error":"Invalid service signature"}`)
+				return
+			}
+			
+			ctx := context.WithValue(r.Context(), serviceCallerKey{}, caller)
+			if caller.Identity != nil {
+				ctx = addUserToContext(ctx, caller.Identity.UserID, caller.Identity.Email, caller.Identity.Roles)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Internal guards routes meant for other services, such as the batch reads
+// behind the web frontend. Signed service calls pass; direct calls need a
+// valid user token.
+func Internal(authService *security.AuthService) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		authenticated := AuthenticateAPI(authService)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := GetServiceCaller(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			authenticated.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AuthenticateAPI provides JWT authentication for API endpoints. A signed
+// service call that names a user is authenticated as that user.
 func AuthenticateAPI(authService *security.AuthService) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if caller, ok := GetServiceCaller(r.Context()); ok && caller.Identity != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			
 			// Extract JWT token from Authorization header
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
@@ -144,7 +220,7 @@ func AuthenticateAPI(authService *security.AuthService) func(next http.Handler)
 			
 			// Add user info to request context
 			ctx := r.Context()
-			ctx = addUserToContext(ctx, claims.UserID, claims.Email)
+			ctx = addUserToContext(ctx, claims.UserID, claims.Email, claims.Roles)
 			r = r.WithContext(ctx)
 			
 			next.ServeHTTP(w, r)
@@ -152,6 +228,43 @@ func AuthenticateAPI(authService *security.AuthService) func(next http.Handler)
 	}
 }
 
+// AuthenticateAdmin authenticates like AuthenticateAPI and then turns away
+// callers whose token or service identity lacks the admin role. The services
+// behind admin routes check the account's role as well, so a token issued
+// before a demotion gets no further than the service.
+func AuthenticateAdmin(authService *security.AuthService) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return AuthenticateAPI(authService)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !HasRole(r.Context(), string(user.UserRoleAdmin)) {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"error":"Admin role required"}`)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
+// OptionalAuthenticateAPI adds the caller to the context when a valid bearer
+// token is present and otherwise serves the request anonymously
+func OptionalAuthenticateAPI(authService *security.AuthService) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := GetServiceCaller(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+			if len(parts) == 2 && parts[0] == "Bearer" {
+				if claims, err := authService.ValidateToken(parts[1], security.AccessToken); err == nil {
+					r = r.WithContext(addUserToContext(r.Context(), claims.UserID, claims.Email, claims.Roles))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // Performance adds performance headers and optimizations
 func Performance() func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -269,13 +382,43 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Flush passes through to the underlying writer so streamed responses, such
+// as server-sent events, reach the client as they are written
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the connection, so streamed
+// exports can extend their write deadline
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// serviceCallerKey holds the verified *svcauth.Caller of a service request
+type serviceCallerKey struct{}
+
+// GetServiceCaller returns the verified service behind a request, if any
+func GetServiceCaller(ctx context.Context) (*svcauth.Caller, bool) {
+	caller, ok := ctx.Value(serviceCallerKey{}).(*svcauth.Caller)
+	return caller, ok
+}
+
 // Helper function to add user info to context
-func addUserToContext(ctx context.Context, userID, email string) context.Context {
+func addUserToContext(ctx context.Context, userID, email string, roles []string) context.Context {
 	ctx = context.WithValue(ctx, "user_id", userID)
 	ctx = context.WithValue(ctx, "user_email", email)
+	ctx = context.WithValue(ctx, "user_roles", roles)
 	return ctx
 }
 
+// ContextWithUser adds an authenticated user to ctx the way the
+// authentication middleware does, for handlers called without it
+func ContextWithUser(ctx context.Context, userID, email string, roles []string) context.Context {
+	return addUserToContext(ctx, userID, email, roles)
+}
+
 // GetUserIDFromContext extracts user ID from request context
 func GetUserIDFromContext(ctx context.Context) (string, bool) {
 	userID, ok := ctx.Value("user_id").(string)
@@ -286,4 +429,15 @@ func GetUserIDFromContext(ctx context.Context) (string, bool) {
 func GetUserEmailFromContext(ctx context.Context) (string, bool) {
 	email, ok := ctx.Value("user_email").(string)
 	return email, ok
-}
\ No newline at end of file
+}
+
+// HasRole reports whether the authenticated caller holds role
+func HasRole(ctx context.Context, role string) bool {
+	roles, _ := ctx.Value("user_roles").([]string)
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/pkg/shadow"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// NewShadowMirror starts a mirror for the shadow config, or returns nil
+// when shadowing is off. eligible further limits the mirrored requests and
+// may be nil.
+func NewShadowMirror(cfg config.ShadowConfig, eligible func(*http.Request) bool, logger *zap.Logger) *shadow.Mirror {
+	if !cfg.Enabled {
+		return nil
+	}
+	mirror := shadow.New(shadow.Options{
+		Target:     cfg.TargetURL,
+		SampleRate: cfg.SampleRate,
+		Timeout:    cfg.Timeout,
+		MaxBody:    cfg.MaxBodyBytes,
+		Workers:    cfg.Workers,
+		Ignore:     cfg.IgnoreFields,
+		Eligible:   eligible,
+	}, shadow.NewMetrics("alchemorsel", prometheus.DefaultRegisterer), logger)
+	mirror.Start()
+	logger.Info("Shadowing reads",
+		zap.String("target", cfg.TargetURL),
+		zap.Float64("sample_rate", cfg.SampleRate),
+	)
+	return mirror
+}
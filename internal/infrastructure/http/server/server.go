@@ -16,6 +16,7 @@ import (
 	"github.com/alchemorsel/v3/internal/infrastructure/config"
 	"github.com/alchemorsel/v3/internal/infrastructure/http/handlers"
 	"github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/infrastructure/observability/metrics"
 	"github.com/alchemorsel/v3/internal/infrastructure/security"
 	"github.com/alchemorsel/v3/internal/ports/inbound"
 	"github.com/alchemorsel/v3/internal/ports/outbound"
@@ -204,6 +205,7 @@ func (s *Server) setupRouter() *chi.Mux {
 	// Global middleware
 	r.Use(chimiddleware.RequestID)
 	r.Use(chimiddleware.RealIP)
+	r.Use(metrics.Middleware)
 	r.Use(middleware.Logger(s.logger))
 	r.Use(chimiddleware.Recoverer)
 	r.Use(middleware.Security())
@@ -229,6 +231,11 @@ func (s *Server) setupRouter() *chi.Mux {
 	// Performance measurement endpoint
 	r.Get("/performance", s.handlePerformanceMetrics)
 
+	// Prometheus scrape endpoint
+	if s.config.Monitoring.EnableMetrics {
+		r.Handle(metrics.Path, metrics.Handler())
+	}
+
 	// Frontend routes
 	s.setupFrontendRoutes(r)
 
@@ -285,7 +292,7 @@ func (s *Server) setupFrontendRoutes(r chi.Router) {
 
 // setupAPIRoutes configures REST API routes
 func (s *Server) setupAPIRoutes(r chi.Router) {
-	h := handlers.NewAPIHandlers(s.recipeService, s.logger)
+	h := handlers.NewAPIHandlers(s.recipeService, nil, nil, s.logger)
 
 	// Recipe CRUD
 	r.Route("/recipes", func(r chi.Router) {
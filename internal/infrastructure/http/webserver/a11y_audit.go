@@ -0,0 +1,107 @@
+// Package webserver provides the dev-mode accessibility audit
+package webserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/a11y"
+	"github.com/alchemorsel/v3/internal/infrastructure/performance"
+	"go.uber.org/zap"
+)
+
+// a11yStartPaths seeds the in-process crawl
+var a11yStartPaths = []string{"/", "/login", "/register", "/recipes", "/dev/fragments"}
+
+// runA11yAudit crawls the app in-process (reusing the caller's cookies so
+// protected pages render), audits every fragment sample and checks the
+// contrast of the generated theme palettes
+func (s *WebServer) runA11yAudit(ctx context.Context, r *http.Request) (*a11y.Report, error) {
+	fetch := func(ctx context.Context, path string) (int, []byte, error) {
+		req := httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx)
+		for _, cookie := range r.Cookies() {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec.Code, rec.Body.Bytes(), nil
+	}
+
+	crawler := a11y.NewCrawler(fetch, a11y.CrawlConfig{
+		StartPaths: a11yStartPaths,
+		MaxPages:   50,
+		Exclude:    []string{"/logout", "/dev/a11y", "/static/", "/api/"},
+	})
+	report, err := crawler.Crawl(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, name := range s.fragments.Names() {
+		spec, _ := s.fragments.Spec(name)
+		for i, sample := range spec.Samples() {
+			var buf bytes.Buffer
+			page := fmt.Sprintf("fragment:%s/%d", name, i+1)
+			if err := s.fragments.RenderSample(&buf, name, sample); err != nil {
+				return report, fmt.Errorf("render %s: %w", page, err)
+			}
+			issues, err := a11y.CheckHTML(page, &buf)
+			if err != nil {
+				return report, err
+			}
+			report.Add(page, issues)
+		}
+	}
+
+	report.Add("theme:light", a11y.CheckPalette("light", performance.LightTheme, a11y.DefaultPalettePairs))
+	report.Add("theme:dark", a11y.CheckPalette("dark", performance.DarkTheme, a11y.DefaultPalettePairs))
+
+	return report, nil
+}
+
+// handleA11yAudit serves /dev/a11y as HTML, or JSON with ?format=json
+func (s *WebServer) handleA11yAudit(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	report, err := s.runA11yAudit(ctx, r)
+	if err != nil {
+		s.logger.Error("Accessibility audit failed", zap.Error(err))
+		http.Error(w, "Accessibility audit failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+		return
+	}
+
+	var page bytes.Buffer
+	page.WriteString(`<!DOCTYPE html><html lang="en"><head><meta charset="UTF-8"><title>Accessibility Audit | Alchemorsel</title></head>` +
+		`<body style="font-family: sans-serif; padding: 2rem;"><h1>Accessibility Audit</h1>`)
+	fmt.Fprintf(&page, `<p>%d pages audited, %d issues (%d errors). <a href="/dev/a11y?format=json">JSON</a></p>`,
+		len(report.Pages), len(report.Issues), report.Errors())
+
+	if len(report.Issues) > 0 {
+		page.WriteString(`<table style="border-collapse: collapse;"><thead><tr><th scope="col">Page</th><th scope="col">Severity</th>` +
+			`<th scope="col">Rule</th><th scope="col">Element</th><th scope="col">Message</th></tr></thead><tbody>`)
+		for _, issue := range report.Issues {
+			fmt.Fprintf(&page, `<tr><td>%s</td><td>%s</td><td>%s</td><td><code>%s</code></td><td>%s</td></tr>`,
+				template.HTMLEscapeString(issue.Page), template.HTMLEscapeString(string(issue.Severity)),
+				template.HTMLEscapeString(issue.Rule), template.HTMLEscapeString(issue.Element),
+				template.HTMLEscapeString(issue.Message))
+		}
+		page.WriteString(`</tbody></table>`)
+	}
+	page.WriteString(`</body></html>`)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page.Bytes())
+}
@@ -0,0 +1,71 @@
+// Package webserver provides the delete account form on the profile page
+package webserver
+
+import (
+	"bytes"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// handleHTMXDeleteAccount asks the API to delete the account after its
+// grace period. The API signs out every session, so this one is cleared
+// and the form is swapped for the date with a note to sign in to cancel.
+func (s *WebServer) handleHTMXDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	if err := r.ParseForm(); err != nil {
+		s.writeToastOnly(w, "We couldn't read that form. Please try again.")
+		return
+	}
+	csrfToken := s.generateCSRFToken(session.ID)
+	if r.PostForm.Get("confirm") != "yes" {
+		s.renderFragment(w, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderAccountDeletion(buf, AccountDeletionView{Error: "Tick the box to confirm", CSRFToken: csrfToken})
+		})
+		return
+	}
+
+	deletion, err := s.apiClient.DeleteAccount(r.Context(), session.AccessToken, r.PostForm.Get("password"))
+	if isAPIStatus(err, http.StatusForbidden) || isAPIStatus(err, http.StatusBadRequest) {
+		s.renderFragment(w, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderAccountDeletion(buf, AccountDeletionView{Error: "That password is incorrect", CSRFToken: csrfToken})
+		})
+		return
+	}
+	if err != nil || deletion.ScheduledFor == nil {
+		s.logger.Warn("Requesting account deletion failed", zap.Error(err))
+		s.writeToastOnly(w, "We couldn't delete your account. Please try again.")
+		return
+	}
+
+	session.Clear()
+	session.Save(w)
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		return s.fragments.RenderAccountDeletion(buf, AccountDeletionView{Pending: true, ScheduledFor: *deletion.ScheduledFor, SignedOut: true})
+	})
+}
+
+// handleHTMXCancelAccountDeletion keeps the account, swapping the pending
+// deletion back for the form
+func (s *WebServer) handleHTMXCancelAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	if err := s.apiClient.CancelAccountDeletion(r.Context(), session.AccessToken); err != nil && !isAPIStatus(err, http.StatusNotFound) {
+		s.logger.Warn("Cancelling account deletion failed", zap.Error(err))
+		s.writeToastOnly(w, "We couldn't cancel the deletion. Please try again.")
+		return
+	}
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		return s.fragments.RenderAccountDeletion(buf, AccountDeletionView{Cancelled: true, CSRFToken: s.generateCSRFToken(session.ID)})
+	})
+}
+
+// accountDeletionView is the profile page's delete account section
+func accountDeletionView(deletion *AccountDeletion, csrfToken string) AccountDeletionView {
+	view := AccountDeletionView{CSRFToken: csrfToken}
+	if deletion != nil && deletion.Pending && deletion.ScheduledFor != nil {
+		view.Pending = true
+		view.ScheduledFor = *deletion.ScheduledFor
+	}
+	return view
+}
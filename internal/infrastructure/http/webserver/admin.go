@@ -0,0 +1,162 @@
+// Package webserver provides the admin section: system totals, user
+// suspension and review of AI-generated recipes
+package webserver
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// adminPageSize is how many users or recipes an admin list shows at once
+const adminPageSize = 25
+
+// notAdmin is shown to signed-in users who open the admin section
+const notAdmin = "The admin section is only open to admins."
+
+// handleAdmin serves /admin. Totals are rendered in place, which also
+// checks the role; the user and AI content lists load after the page.
+func (s *WebServer) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	stats, err := s.apiClient.GetSystemStats(r.Context(), session.AccessToken)
+	if err != nil {
+		if isAPIStatus(err, http.StatusForbidden) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusForbidden)
+			s.renderTemplate(w, "error", map[string]interface{}{
+				"Title":   "Admin - Alchemorsel",
+				"Message": notAdmin,
+			})
+			return
+		}
+		s.renderError(w, "System stats are unavailable", err)
+		return
+	}
+
+	var statsHTML bytes.Buffer
+	if err := s.fragments.RenderAdminStats(&statsHTML, NewAdminStatsView(*stats)); err != nil {
+		s.renderError(w, "Failed to render system stats", err)
+		return
+	}
+	s.renderTemplate(w, "admin", map[string]interface{}{
+		"Title": "Admin - Alchemorsel",
+		"Theme": sessionTheme(session),
+		"Stats": template.HTML(statsHTML.String()),
+	})
+}
+
+// handleAdminUsers serves /admin/users, filtered by ?q= and ?status=
+func (s *WebServer) handleAdminUsers(w http.ResponseWriter, r *http.Request) {
+	s.renderAdminUsers(w, r, r.URL.Query())
+}
+
+// handleHTMXSuspendUser suspends a user and swaps in the refreshed list.
+// The reason comes from the hx-prompt answer.
+func (s *WebServer) handleHTMXSuspendUser(w http.ResponseWriter, r *http.Request) {
+	s.setUserSuspended(w, r, true)
+}
+
+// handleHTMXReinstateUser reinstates a suspended user
+func (s *WebServer) handleHTMXReinstateUser(w http.ResponseWriter, r *http.Request) {
+	s.setUserSuspended(w, r, false)
+}
+
+func (s *WebServer) setUserSuspended(w http.ResponseWriter, r *http.Request, suspended bool) {
+	session := r.Context().Value("session").(*Session)
+	userID := chi.URLParam(r, "id")
+
+	_, err := s.apiClient.SetUserSuspended(r.Context(), session.AccessToken, userID, suspended, r.Header.Get("HX-Prompt"))
+	if err != nil {
+		s.logger.Warn("Admin user update failed", zap.String("user_id", userID), zap.Bool("suspend", suspended), zap.Error(err))
+		s.writeToastOnly(w, "We couldn't update that user. Admins cannot be suspended.")
+		return
+	}
+	s.renderAdminUsers(w, r, url.Values{
+		"q":      {r.FormValue("q")},
+		"status": {r.FormValue("status")},
+		"page":   {r.FormValue("page")},
+	})
+}
+
+func (s *WebServer) renderAdminUsers(w http.ResponseWriter, r *http.Request, filters url.Values) {
+	session := r.Context().Value("session").(*Session)
+	page := adminPage(filters.Get("page"))
+
+	query := url.Values{
+		"page":  {strconv.Itoa(page)},
+		"limit": {strconv.Itoa(adminPageSize)},
+	}
+	for _, key := range []string{"q", "status"} {
+		if v := filters.Get(key); v != "" {
+			query.Set(key, v)
+		}
+	}
+	users, err := s.apiClient.ListAdminUsers(r.Context(), session.AccessToken, query)
+	if err != nil {
+		s.adminUnavailable(w, r, "Users unavailable", err)
+		return
+	}
+
+	view := NewAdminUsersView(*users, filters.Get("q"), filters.Get("status"), page, s.generateCSRFToken(session.ID))
+	s.renderGraph(w, r, "Users - Admin - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderAdminUsers(buf, view)
+	})
+}
+
+// handleAdminAIContent serves /admin/ai-content
+func (s *WebServer) handleAdminAIContent(w http.ResponseWriter, r *http.Request) {
+	s.renderAIContent(w, r, adminPage(r.URL.Query().Get("page")))
+}
+
+// handleHTMXAdminUnpublish unpublishes a recipe and swaps in the refreshed
+// AI content list
+func (s *WebServer) handleHTMXAdminUnpublish(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+
+	if err := s.apiClient.AdminUnpublishRecipe(r.Context(), session.AccessToken, recipeID, r.Header.Get("HX-Prompt")); err != nil {
+		s.logger.Warn("Admin unpublish failed", zap.String("recipe_id", recipeID), zap.Error(err))
+		s.writeToastOnly(w, "We couldn't unpublish that recipe. Please try again.")
+		return
+	}
+	s.renderAIContent(w, r, adminPage(r.FormValue("page")))
+}
+
+func (s *WebServer) renderAIContent(w http.ResponseWriter, r *http.Request, page int) {
+	session := r.Context().Value("session").(*Session)
+
+	content, err := s.apiClient.ListAIContent(r.Context(), session.AccessToken, page, adminPageSize)
+	if err != nil {
+		s.adminUnavailable(w, r, "AI content unavailable", err)
+		return
+	}
+
+	view := NewAIContentView(*content, page, s.generateCSRFToken(session.ID))
+	s.renderGraph(w, r, "AI-generated recipes - Admin - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderAIContent(buf, view)
+	})
+}
+
+// adminPage reads a 1-based page number, falling back to the first page
+func adminPage(raw string) int {
+	page, err := strconv.Atoi(raw)
+	if err != nil || page < 1 {
+		return 1
+	}
+	return page
+}
+
+func (s *WebServer) adminUnavailable(w http.ResponseWriter, r *http.Request, message string, err error) {
+	if isAPIStatus(err, http.StatusForbidden) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<div class=\"error\">" + notAdmin + "</div>"))
+		return
+	}
+	s.techniqueUnavailable(w, r, message, err)
+}
@@ -5,38 +5,170 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/alchemorsel/v3/internal/domain/recipe/printout"
 	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/pkg/svcauth"
 	"go.uber.org/zap"
 )
 
 // APIClient handles communication with the backend API
 type APIClient struct {
-	baseURL    string
 	httpClient *http.Client
 	logger     *zap.Logger
+
+	// API instances, found by resolver and spread across by balancer
+	resolver        resolver
+	balancer        *balancer
+	maxAttempts     int
+	refreshInterval time.Duration
+	healthInterval  time.Duration
+	stop            chan struct{}
+	done            chan struct{}
+
+	// With service auth on, calls name the signed-in user in a signed
+	// header instead of forwarding their access token. Only users who
+	// signed in through this client are vouched for.
+	signer  *svcauth.Signer
+	mu      sync.Mutex
+	vouched map[string]vouchedUser
+
+	// Coalescing loaders so per-item lookups during a page render
+	// collapse into a single :batchGet call
+	recipeLoader *batchLoader[RecipeResponse]
+	userLoader   *batchLoader[UserSummary]
+}
+
+// vouchedUser is a user signed in through this client, known by the access
+// token their session holds
+type vouchedUser struct {
+	identity  svcauth.Identity
+	expiresAt time.Time
 }
 
+// defaultVouchTTL applies when the API does not say when a token expires
+const defaultVouchTTL = time.Hour
+
+// batchWindow is how long loaders wait for more IDs before dispatching
+const batchWindow = 2 * time.Millisecond
+
+// maxBatchIDs mirrors the API's per-call cap for :batchGet endpoints
+const maxBatchIDs = 100
+
 // NewAPIClient creates a new API client instance
 func NewAPIClient(cfg *config.Config, logger *zap.Logger) *APIClient {
-	// Get API URL from environment or config
-	apiURL := os.Getenv("API_URL")
-	if apiURL == "" {
-		apiURL = fmt.Sprintf("http://localhost:3000")
+	apiCfg := cfg.Web.API
+	httpClient := &http.Client{
+		Timeout: apiCfg.Timeout,
+	}
+
+	client := &APIClient{
+		httpClient:      httpClient,
+		logger:          logger,
+		resolver:        newResolver(apiCfg, os.Getenv("API_URL"), httpClient),
+		balancer:        newBalancer(apiCfg.FailureThreshold, logger),
+		maxAttempts:     apiCfg.MaxAttempts,
+		refreshInterval: apiCfg.RefreshInterval,
+		healthInterval:  apiCfg.HealthInterval,
+		vouched:         make(map[string]vouchedUser),
+	}
+	if svc := cfg.Auth.Service; svc.Enabled {
+		client.signer = svcauth.NewSigner(svc.Name, svc.Secret)
+	}
+	client.recipeLoader = newBatchLoader(client.BatchGetRecipes, batchWindow, maxBatchIDs)
+	client.userLoader = newBatchLoader(client.BatchGetUsers, batchWindow, maxBatchIDs)
+
+	// Static lists are usable before Start
+	if static, ok := client.resolver.(staticResolver); ok {
+		urls, _ := static.resolve(context.Background())
+		client.balancer.update(urls)
+	}
+
+	return client
+}
+
+// Start resolves the API instances and keeps them fresh and probed until
+// Stop. A failed first lookup is logged, not returned, so the frontend can
+// come up before the backends do.
+func (c *APIClient) Start(ctx context.Context) error {
+	c.refresh(ctx)
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.run()
+	return nil
+}
+
+// Stop ends discovery and health probing
+func (c *APIClient) Stop() {
+	if c.stop == nil {
+		return
+	}
+	close(c.stop)
+	<-c.done
+}
+
+// Backends reports the known API instances and their health
+func (c *APIClient) Backends() []BackendStatus {
+	return c.balancer.statuses()
+}
+
+func (c *APIClient) run() {
+	defer close(c.done)
+
+	refresh := time.NewTicker(c.refreshInterval)
+	defer refresh.Stop()
+	probe := time.NewTicker(c.healthInterval)
+	defer probe.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-refresh.C:
+			c.refresh(context.Background())
+		case <-probe.C:
+			ctx, cancel := context.WithTimeout(context.Background(), c.healthInterval)
+			c.balancer.probe(ctx, c.healthy)
+			cancel()
+		}
+	}
+}
+
+func (c *APIClient) refresh(ctx context.Context) {
+	urls, err := c.resolver.resolve(ctx)
+	if err != nil {
+		c.logger.Warn("Failed to resolve API backends", zap.Error(err))
+		return
+	}
+	if len(urls) == 0 {
+		c.logger.Warn("No API backends found; keeping the previous list")
+		return
 	}
+	c.balancer.update(urls)
+}
 
-	return &APIClient{
-		baseURL: apiURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		logger: logger,
+// healthy probes one instance's health endpoint
+func (c *APIClient) healthy(ctx context.Context, baseURL string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/health", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false
 	}
+	resp.Body.Close()
+	return resp.StatusCode < 500
 }
 
 // Authentication
@@ -79,26 +211,81 @@ type UserResponse struct {
 	Email     string    `json:"email"`
 	Role      string    `json:"role"`
 	IsActive  bool      `json:"is_active"`
+	Theme     string    `json:"theme,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
 // RecipeResponse represents recipe data
 type RecipeResponse struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	AuthorID    string    `json:"author_id"`
-	AuthorName  string    `json:"author_name"`
-	CookTime    int       `json:"cook_time"`
-	PrepTime    int       `json:"prep_time"`
-	Servings    int       `json:"servings"`
-	Difficulty  string    `json:"difficulty"`
-	Cuisine     string    `json:"cuisine"`
-	Category    string    `json:"category"`
-	ImageURL    string    `json:"image_url"`
-	Likes       int       `json:"likes"`
-	Rating      float64   `json:"rating"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	AuthorID    string `json:"author_id"`
+	AuthorName  string `json:"author_name"`
+	// AuthorBadge is set when the author is verified
+	AuthorBadge *AuthorBadge `json:"author_badge,omitempty"`
+	CookTime    int          `json:"cook_time"`
+	PrepTime    int          `json:"prep_time"`
+	Servings    int          `json:"servings"`
+	Difficulty  string       `json:"difficulty"`
+	Cuisine     string       `json:"cuisine"`
+	Category    string       `json:"category"`
+	ImageURL    string       `json:"image_url"`
+	Likes       int          `json:"likes"`
+	Rating      float64      `json:"rating"`
+	Status      string       `json:"status"`
+	CreatedAt   time.Time    `json:"created_at"`
+
+	// Nutrition is per serving, computed from the ingredients
+	Nutrition *RecipeNutrition `json:"nutrition,omitempty"`
+
+	// Allergens are the major allergens in the ingredients
+	Allergens *AllergenLabel `json:"allergens,omitempty"`
+
+	// Forks counts the copies made with "Make it my own"; ForkedFrom is
+	// the original of a copy, set on single recipe reads
+	Forks      int           `json:"forks"`
+	ForkedFrom *RecipeOrigin `json:"forked_from,omitempty"`
+
+	// AllergyWarnings are the signed-in user's allergies the recipe
+	// contains or may contain, set in recipe lists
+	AllergyWarnings []string `json:"allergy_warnings,omitempty"`
+}
+
+// RecipeOrigin is the recipe a fork was copied from
+type RecipeOrigin struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	AuthorName string `json:"author_name"`
+}
+
+// RecipeNutrition is a recipe's nutrition per serving, in grams with
+// sodium and cholesterol in milligrams. Coverage is the share of required
+// ingredients the figures count, from 0 to 1.
+type RecipeNutrition struct {
+	Calories      int     `json:"calories"`
+	Protein       float64 `json:"protein"`
+	Carbohydrates float64 `json:"carbohydrates"`
+	Fat           float64 `json:"fat"`
+	Fiber         float64 `json:"fiber"`
+	Sugar         float64 `json:"sugar"`
+	Sodium        float64 `json:"sodium"`
+	Cholesterol   float64 `json:"cholesterol"`
+	Coverage      float64 `json:"coverage"`
+}
+
+// AllergenLabel is the major allergens a recipe's ingredients contain or
+// may contain, by allergen name such as "tree-nuts"
+type AllergenLabel struct {
+	Contains   []string `json:"contains"`
+	MayContain []string `json:"may_contain"`
+}
+
+// AuthorBadge is a verified author's badge
+type AuthorBadge struct {
+	Kind        string `json:"kind"`
+	DisplayName string `json:"display_name"`
+	VerifiedAt  string `json:"verified_at"`
 }
 
 // Login authenticates a user with the API
@@ -117,6 +304,7 @@ func (c *APIClient) Login(ctx context.Context, email, password string) (*LoginRe
 	if !resp.Success {
 		return nil, fmt.Errorf("login failed: %s", resp.Error)
 	}
+	c.vouch(resp.AccessToken, resp.User, time.Duration(resp.ExpiresIn)*time.Second)
 
 	return &resp, nil
 }
@@ -149,15 +337,7 @@ func (c *APIClient) VerifyToken(ctx context.Context, token string) bool {
 	}
 
 	// Call profile endpoint to verify token
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/v1/auth/profile", nil)
-	if err != nil {
-		return false
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(ctx, "GET", "/api/v1/auth/profile", headers(token), nil)
 	if err != nil {
 		c.logger.Debug("Token verification failed", zap.Error(err))
 		return false
@@ -170,15 +350,7 @@ func (c *APIClient) VerifyToken(ctx context.Context, token string) bool {
 // VerifyConnection checks if the API backend is reachable
 func (c *APIClient) VerifyConnection(ctx context.Context) bool {
 	// Call health endpoint to verify connection
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/health", nil)
-	if err != nil {
-		c.logger.Debug("Connection verification request creation failed", zap.Error(err))
-		return false
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(ctx, "GET", "/health", headers(""), nil)
 	if err != nil {
 		c.logger.Debug("Connection verification failed", zap.Error(err))
 		return false
@@ -210,21 +382,170 @@ func (c *APIClient) GetProfile(ctx context.Context, token string) (*UserResponse
 
 // Recipes
 
-// GetRecipes fetches the list of recipes
-func (c *APIClient) GetRecipes(ctx context.Context, token string) ([]RecipeResponse, error) {
+// RecipePage is one page of a recipe listing and the cursor of the next
+type RecipePage struct {
+	Recipes    []RecipeResponse `json:"recipes"`
+	NextCursor string           `json:"next_cursor"`
+}
+
+// GetRecipes fetches a page of recipes in a listing order such as trending
+// or rating, starting after the cursor of the previous page when set
+func (c *APIClient) GetRecipes(ctx context.Context, token, sort, after string) (*RecipePage, error) {
+	var resp struct {
+		Success bool       `json:"success"`
+		Data    RecipePage `json:"data"`
+		Error   string     `json:"error,omitempty"`
+	}
+
+	query := url.Values{}
+	if sort != "" {
+		query.Set("sort", sort)
+	}
+	if after != "" {
+		query.Set("after", after)
+	}
+	path := "/api/v1/recipes"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	err := c.getWithAuth(ctx, path, token, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get recipes: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// GetTrendingRecipes fetches the first page of trending recipes
+func (c *APIClient) GetTrendingRecipes(ctx context.Context) ([]RecipeResponse, error) {
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Recipes []RecipeResponse `json:"recipes"`
+		} `json:"data"`
+		Error string `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/recipes/trending", "", &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get trending recipes: %s", resp.Error)
+	}
+
+	return resp.Data.Recipes, nil
+}
+
+// GetFavoriteRecipes fetches the first page of the user's saved recipes,
+// most recently saved first
+func (c *APIClient) GetFavoriteRecipes(ctx context.Context, token string) ([]RecipeResponse, error) {
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Recipes []RecipeResponse `json:"recipes"`
+		} `json:"data"`
+		Error string `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/users/me/favorites", token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get saved recipes: %s", resp.Error)
+	}
+
+	return resp.Data.Recipes, nil
+}
+
+// GetRecentlyViewedRecipes fetches the recipes the user opened, most
+// recent first
+func (c *APIClient) GetRecentlyViewedRecipes(ctx context.Context, token string, limit int) ([]RecipeResponse, error) {
+	return c.getRecipeList(ctx, token, fmt.Sprintf("/api/v1/recipes/recently-viewed?limit=%d", limit), "recently viewed recipes")
+}
+
+// GetChefActivity fetches new recipes by the authors of recipes the user
+// liked
+func (c *APIClient) GetChefActivity(ctx context.Context, token string, limit int) ([]RecipeResponse, error) {
+	return c.getRecipeList(ctx, token, fmt.Sprintf("/api/v1/recipes/chef-activity?limit=%d", limit), "chef activity")
+}
+
+// RecommendedRecipes is the first page of a user's recommendations or the
+// recipes similar to one. Reasons holds, by recipe ID, why each was picked;
+// trending recipes shown before the user has any recommendations have none.
+type RecommendedRecipes struct {
+	Recipes []RecipeResponse
+	Reasons map[string]string
+}
+
+// GetRecommendedRecipes fetches the first page of "Recipes you might like"
+func (c *APIClient) GetRecommendedRecipes(ctx context.Context, token string) (*RecommendedRecipes, error) {
+	return c.getExplainedRecipes(ctx, token, "/api/v1/recipes/recommended", "recommended recipes")
+}
+
+// GetSimilarRecipes fetches the published recipes sharing the most with a
+// recipe
+func (c *APIClient) GetSimilarRecipes(ctx context.Context, recipeID string, limit int) (*RecommendedRecipes, error) {
+	path := fmt.Sprintf("/api/v1/recipes/%s/similar?limit=%d", url.PathEscape(recipeID), limit)
+	return c.getExplainedRecipes(ctx, "", path, "similar recipes")
+}
+
+func (c *APIClient) getExplainedRecipes(ctx context.Context, token, path, what string) (*RecommendedRecipes, error) {
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Recipes      []RecipeResponse `json:"recipes"`
+			Explanations []struct {
+				RecipeID string `json:"recipe_id"`
+				Factors  []struct {
+					Detail string  `json:"detail"`
+					Weight float64 `json:"weight"`
+				} `json:"factors"`
+			} `json:"explanations"`
+		} `json:"data"`
+		Error string `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, path, token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get %s: %s", what, resp.Error)
+	}
+
+	// The strongest factor is the reason given
+	recommended := &RecommendedRecipes{Recipes: resp.Data.Recipes, Reasons: make(map[string]string)}
+	for _, explanation := range resp.Data.Explanations {
+		strongest := -1.0
+		for _, factor := range explanation.Factors {
+			if factor.Weight > strongest {
+				strongest = factor.Weight
+				recommended.Reasons[explanation.RecipeID] = factor.Detail
+			}
+		}
+	}
+	return recommended, nil
+}
+
+func (c *APIClient) getRecipeList(ctx context.Context, token, path, what string) ([]RecipeResponse, error) {
 	var resp struct {
 		Success bool             `json:"success"`
 		Data    []RecipeResponse `json:"data"`
 		Error   string           `json:"error,omitempty"`
 	}
 
-	err := c.getWithAuth(ctx, "/api/v1/recipes", token, &resp)
-	if err != nil {
+	if err := c.getWithAuth(ctx, path, token, &resp); err != nil {
 		return nil, err
 	}
 
 	if !resp.Success {
-		return nil, fmt.Errorf("failed to get recipes: %s", resp.Error)
+		return nil, fmt.Errorf("failed to get %s: %s", what, resp.Error)
 	}
 
 	return resp.Data, nil
@@ -250,127 +571,2078 @@ func (c *APIClient) GetRecipe(ctx context.Context, token, recipeID string) (*Rec
 	return &resp.Data, nil
 }
 
-// CreateRecipe creates a new recipe
-func (c *APIClient) CreateRecipe(ctx context.Context, token string, recipe RecipeResponse) (*RecipeResponse, error) {
+// ForkRecipe copies a published recipe into a draft owned by the user
+func (c *APIClient) ForkRecipe(ctx context.Context, token, recipeID string) (*RecipeResponse, error) {
 	var resp struct {
 		Success bool           `json:"success"`
 		Data    RecipeResponse `json:"data"`
 		Error   string         `json:"error,omitempty"`
 	}
 
-	err := c.postWithAuth(ctx, "/api/v1/recipes", token, recipe, &resp)
-	if err != nil {
+	if err := c.postWithAuth(ctx, "/api/v1/recipes/"+url.PathEscape(recipeID)+"/fork", token, struct{}{}, &resp); err != nil {
 		return nil, err
 	}
 
 	if !resp.Success {
-		return nil, fmt.Errorf("failed to create recipe: %s", resp.Error)
+		return nil, fmt.Errorf("failed to fork recipe: %s", resp.Error)
 	}
 
 	return &resp.Data, nil
 }
 
-// AI Features
-
-// GenerateRecipe generates a recipe using AI
-func (c *APIClient) GenerateRecipe(ctx context.Context, token, prompt string) (*RecipeResponse, error) {
-	req := map[string]interface{}{
-		"prompt": prompt,
-	}
+// LikeStatus is whether the user likes a recipe, with the recipe's likes
+type LikeStatus struct {
+	RecipeID string `json:"recipe_id"`
+	Liked    bool   `json:"liked"`
+	Likes    int    `json:"likes"`
+}
 
+// LikeRecipe likes a recipe for the user and returns its new like count
+func (c *APIClient) LikeRecipe(ctx context.Context, token, recipeID string) (*LikeStatus, error) {
 	var resp struct {
-		Success bool           `json:"success"`
-		Data    RecipeResponse `json:"data"`
-		Error   string         `json:"error,omitempty"`
+		Success bool       `json:"success"`
+		Data    LikeStatus `json:"data"`
+		Error   string     `json:"error,omitempty"`
 	}
 
-	err := c.postWithAuth(ctx, "/api/v1/ai/generate-recipe", token, req, &resp)
-	if err != nil {
+	if err := c.postWithAuth(ctx, "/api/v1/recipes/"+url.PathEscape(recipeID)+"/like", token, struct{}{}, &resp); err != nil {
 		return nil, err
 	}
 
 	if !resp.Success {
-		return nil, fmt.Errorf("failed to generate recipe: %s", resp.Error)
+		return nil, fmt.Errorf("failed to like recipe: %s", resp.Error)
 	}
 
 	return &resp.Data, nil
 }
 
-// Helper methods
+// searchResultFields is the sparse fieldset needed to render search result cards
+const searchResultFields = "id,title,description,author_name,author_badge,prep_time,cook_time,rating,allergens,allergy_warnings"
 
-func (c *APIClient) post(ctx context.Context, path string, body interface{}, response interface{}) error {
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+// SearchFallback is the API's help for a search that matched nothing
+type SearchFallback struct {
+	Query          string   `json:"query"`
+	Suggestions    []string `json:"suggestions"`
+	GeneratePrompt string   `json:"generate_prompt"`
+}
+
+// SearchResult is one page of recipe search results
+type SearchResult struct {
+	Recipes  []RecipeResponse `json:"recipes"`
+	Total    int              `json:"total"`
+	Fallback *SearchFallback  `json:"fallback,omitempty"`
+	Facets   *SearchFacets    `json:"facets,omitempty"`
+	// DefaultDiets are the user's own diets a search without a diet
+	// filter was narrowed to
+	DefaultDiets []string `json:"default_diets,omitempty"`
+}
+
+// SearchFilters narrow a recipe search. Empty fields do not filter.
+type SearchFilters struct {
+	Cuisines     []string
+	Difficulties []string
+	MaxTime      int
+	Diets        []string
+	// DietsChosen is set once the diet filter was submitted, so that no
+	// diet means any rather than the user's own diets
+	DietsChosen bool
+	AIGenerated string // "true", "false" or "" for either
+	// Semantic searches by meaning rather than keyword; it ranks rather
+	// than narrows, so it is not a filter for Active
+	Semantic bool
+}
+
+// Active reports whether any filter is set
+func (f SearchFilters) Active() bool {
+	return len(f.Cuisines) > 0 || len(f.Difficulties) > 0 || f.MaxTime > 0 || len(f.Diets) > 0 || f.AIGenerated != ""
+}
+
+// SearchFacets count the results of a search per filter value
+type SearchFacets struct {
+	Cuisines     []FacetCount `json:"cuisines"`
+	Difficulties []FacetCount `json:"difficulties"`
+	MaxTime      []FacetCount `json:"max_time"`
+	Diets        []FacetCount `json:"diets"`
+	AIGenerated  []FacetCount `json:"ai_generated"`
+}
+
+// FacetCount is one filter value and how many results have it
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchRecipes runs a text search narrowed by filters, with the result
+// counts per filter value. Fallback is set when nothing matched.
+func (c *APIClient) SearchRecipes(ctx context.Context, token, query string, filters SearchFilters) (*SearchResult, error) {
+	var resp struct {
+		Success bool         `json:"success"`
+		Data    SearchResult `json:"data"`
+		Error   string       `json:"error,omitempty"`
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewReader(jsonBody))
+	params := url.Values{"search": {query}, "fields": {searchResultFields}, "facets": {"true"}}
+	if len(filters.Cuisines) > 0 {
+		params.Set("cuisine", strings.Join(filters.Cuisines, ","))
+	}
+	if len(filters.Difficulties) > 0 {
+		params.Set("difficulty", strings.Join(filters.Difficulties, ","))
+	}
+	if filters.MaxTime > 0 {
+		params.Set("max_time", strconv.Itoa(filters.MaxTime))
+	}
+	if len(filters.Diets) > 0 {
+		params.Set("diet", strings.Join(filters.Diets, ","))
+	} else if filters.DietsChosen {
+		params.Set("diet", "any")
+	}
+	if filters.AIGenerated != "" {
+		params.Set("ai_generated", filters.AIGenerated)
+	}
+	if filters.Semantic {
+		params.Set("mode", "semantic")
+	}
+	err := c.getWithAuth(ctx, "/api/v1/recipes?"+params.Encode(), token, &resp)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to search recipes: %s", resp.Error)
+	}
 
-	return c.doRequest(req, response)
+	return &resp.Data, nil
 }
 
-func (c *APIClient) postWithAuth(ctx context.Context, path, token string, body interface{}, response interface{}) error {
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
+// RecipeAnalytics is the author stats for one recipe
+type RecipeAnalytics struct {
+	RecipeID           string           `json:"recipe_id"`
+	Title              string           `json:"title"`
+	Since              string           `json:"since"`
+	Until              string           `json:"until"`
+	Views              int              `json:"views"`
+	UniqueViewers      int              `json:"unique_viewers"`
+	Likes              int              `json:"likes"`
+	Bookmarks          int              `json:"bookmarks"`
+	LikeConversion     float64          `json:"like_conversion"`
+	BookmarkConversion float64          `json:"bookmark_conversion"`
+	AverageScrollDepth *float64         `json:"average_scroll_depth"`
+	ScrollSamples      int              `json:"scroll_samples"`
+	Daily              []DailyViews     `json:"daily"`
+	SearchTerms        []AnalyticsCount `json:"search_terms"`
+	Referrers          []AnalyticsCount `json:"referrers"`
+}
+
+// DailyViews is one day of a recipe's views
+type DailyViews struct {
+	Date          string `json:"date"`
+	Views         int    `json:"views"`
+	UniqueViewers int    `json:"unique_viewers"`
+}
+
+// AnalyticsCount is a search term or referrer with its view count
+type AnalyticsCount struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// GetRecipeAnalytics fetches the author stats of a recipe for the last days
+func (c *APIClient) GetRecipeAnalytics(ctx context.Context, token, recipeID string, days int) (*RecipeAnalytics, error) {
+	var resp struct {
+		Success bool            `json:"success"`
+		Data    RecipeAnalytics `json:"data"`
+		Error   string          `json:"error,omitempty"`
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewReader(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	path := fmt.Sprintf("/api/v1/recipes/%s/analytics?days=%d", url.PathEscape(recipeID), days)
+	if err := c.getWithAuth(ctx, path, token, &resp); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get recipe analytics: %s", resp.Error)
+	}
 
-	return c.doRequest(req, response)
+	return &resp.Data, nil
 }
 
-func (c *APIClient) getWithAuth(ctx context.Context, path, token string, response interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+// AuthorAnalytics is the activity across an author's recipes
+type AuthorAnalytics struct {
+	Since         string              `json:"since"`
+	Until         string              `json:"until"`
+	Views         int                 `json:"views"`
+	Likes         int                 `json:"likes"`
+	Impressions   int                 `json:"impressions"`
+	Ratings       int                 `json:"ratings"`
+	AverageRating *float64            `json:"average_rating"`
+	Daily         []AuthorDailyStats  `json:"daily"`
+	Recipes       []AuthorRecipeStats `json:"recipes"`
+}
+
+// AuthorDailyStats is one day of an author's activity
+type AuthorDailyStats struct {
+	Date          string   `json:"date"`
+	Views         int      `json:"views"`
+	Likes         int      `json:"likes"`
+	Impressions   int      `json:"impressions"`
+	Ratings       int      `json:"ratings"`
+	AverageRating *float64 `json:"average_rating"`
+}
+
+// AuthorRecipeStats is one recipe's totals over the window
+type AuthorRecipeStats struct {
+	RecipeID      string   `json:"recipe_id"`
+	Title         string   `json:"title"`
+	Views         int      `json:"views"`
+	Likes         int      `json:"likes"`
+	Impressions   int      `json:"impressions"`
+	Ratings       int      `json:"ratings"`
+	AverageRating *float64 `json:"average_rating"`
+	Rating        float64  `json:"rating"`
+}
+
+// GetAuthorAnalytics fetches the activity across the user's recipes for
+// the last days
+func (c *APIClient) GetAuthorAnalytics(ctx context.Context, token string, days int) (*AuthorAnalytics, error) {
+	var resp struct {
+		Success bool            `json:"success"`
+		Data    AuthorAnalytics `json:"data"`
+		Error   string          `json:"error,omitempty"`
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
+	if err := c.getWithAuth(ctx, fmt.Sprintf("/api/v1/recipes/analytics?days=%d", days), token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get author analytics: %s", resp.Error)
+	}
 
-	return c.doRequest(req, response)
+	return &resp.Data, nil
 }
 
-func (c *APIClient) doRequest(req *http.Request, response interface{}) error {
-	c.logger.Debug("API request",
-		zap.String("method", req.Method),
-		zap.String("url", req.URL.String()),
-	)
+// GetRecipeAnalyticsCSV downloads the author stats of a recipe as CSV
+func (c *APIClient) GetRecipeAnalyticsCSV(ctx context.Context, token, recipeID string, days int) ([]byte, error) {
+	path := fmt.Sprintf("/api/v1/recipes/%s/analytics?days=%d&format=csv", url.PathEscape(recipeID), days)
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(ctx, "GET", path, header, nil)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
-
 	if resp.StatusCode >= 400 {
-		c.logger.Error("API error response",
-			zap.Int("status", resp.StatusCode),
-			zap.String("body", string(body)),
-		)
-		return fmt.Errorf("API error: status %d", resp.StatusCode)
+		return nil, fmt.Errorf("API error: status %d", resp.StatusCode)
 	}
 
-	if err := json.Unmarshal(body, response); err != nil {
-		return fmt.Errorf("failed to unmarshal response: %w", err)
+	return body, nil
+}
+
+// GraphNode is an ingredient, technique or cuisine of the recipe graph
+type GraphNode struct {
+	Kind    string `json:"kind"`
+	Key     string `json:"key"`
+	Label   string `json:"label"`
+	Recipes int    `json:"recipes"`
+}
+
+// RecipeGraph is what a recipe links to in the recipe graph
+type RecipeGraph struct {
+	RecipeID    string      `json:"recipe_id"`
+	Ingredients []GraphNode `json:"ingredients"`
+	Techniques  []GraphNode `json:"techniques"`
+	Cuisines    []GraphNode `json:"cuisines"`
+}
+
+// GraphRecipe is a recipe card reached through the recipe graph
+type GraphRecipe struct {
+	ID            string      `json:"id"`
+	Title         string      `json:"title"`
+	AverageRating float64     `json:"average_rating"`
+	Likes         int         `json:"likes"`
+	Shared        []GraphNode `json:"shared"`
+}
+
+// GraphNodeRecipes are the recipes linked to one graph node
+type GraphNodeRecipes struct {
+	Node    GraphNode     `json:"node"`
+	Recipes []GraphRecipe `json:"recipes"`
+}
+
+// GetRecipeGraph fetches the ingredients, techniques and cuisine of a
+// recipe. The graph is public, so no token is sent.
+func (c *APIClient) GetRecipeGraph(ctx context.Context, recipeID string) (*RecipeGraph, error) {
+	var resp struct {
+		Success bool        `json:"success"`
+		Data    RecipeGraph `json:"data"`
+		Error   string      `json:"error,omitempty"`
 	}
 
-	return nil
-}
\ No newline at end of file
+	if err := c.getWithAuth(ctx, "/api/v1/recipes/"+url.PathEscape(recipeID)+"/graph", "", &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get recipe graph: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// GetRelatedRecipes fetches the recipes sharing the most with a recipe
+func (c *APIClient) GetRelatedRecipes(ctx context.Context, recipeID string, limit int) ([]GraphRecipe, error) {
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Recipes []GraphRecipe `json:"recipes"`
+		} `json:"data"`
+		Error string `json:"error,omitempty"`
+	}
+
+	path := fmt.Sprintf("/api/v1/recipes/%s/related?limit=%d", url.PathEscape(recipeID), limit)
+	if err := c.getWithAuth(ctx, path, "", &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get related recipes: %s", resp.Error)
+	}
+
+	return resp.Data.Recipes, nil
+}
+
+// GetGraphNodeRecipes fetches the recipes using an ingredient, technique or
+// cuisine
+func (c *APIClient) GetGraphNodeRecipes(ctx context.Context, kind, key string, limit int) (*GraphNodeRecipes, error) {
+	var resp struct {
+		Success bool             `json:"success"`
+		Data    GraphNodeRecipes `json:"data"`
+		Error   string           `json:"error,omitempty"`
+	}
+
+	path := fmt.Sprintf("/api/v1/graph/%s/%s/recipes?limit=%d", url.PathEscape(kind), url.PathEscape(key), limit)
+	if err := c.getWithAuth(ctx, path, "", &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get recipes for %s: %s", kind, resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// TechniqueVideo is an embeddable how-to video
+type TechniqueVideo struct {
+	Provider string `json:"provider"`
+	Title    string `json:"title"`
+	EmbedURL string `json:"embed_url"`
+	WatchURL string `json:"watch_url"`
+	Duration int    `json:"duration_seconds"`
+}
+
+// Technique is a technique library page
+type Technique struct {
+	Slug    string          `json:"slug"`
+	Name    string          `json:"name"`
+	Summary string          `json:"summary"`
+	Steps   []string        `json:"steps"`
+	Video   *TechniqueVideo `json:"video"`
+}
+
+// StepTechnique is a technique linked from a recipe step
+type StepTechnique struct {
+	Slug    string          `json:"slug"`
+	Name    string          `json:"name"`
+	Summary string          `json:"summary"`
+	Source  string          `json:"source"`
+	Video   *TechniqueVideo `json:"video"`
+}
+
+// RecipeStep is one numbered recipe step with its techniques
+type RecipeStep struct {
+	Number     int             `json:"number"`
+	Text       string          `json:"text"`
+	Techniques []StepTechnique `json:"techniques"`
+}
+
+// RecipeSteps are a recipe's steps as cook mode shows them
+type RecipeSteps struct {
+	RecipeID string       `json:"recipe_id"`
+	Title    string       `json:"title"`
+	Steps    []RecipeStep `json:"steps"`
+}
+
+// GetTechnique fetches a technique library page
+func (c *APIClient) GetTechnique(ctx context.Context, slug string) (*Technique, error) {
+	var resp struct {
+		Success bool      `json:"success"`
+		Data    Technique `json:"data"`
+		Error   string    `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/techniques/"+url.PathEscape(slug), "", &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get technique: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// GetRecipeSteps fetches a recipe's steps with their techniques, with
+// temperatures in units, blank for the user's preference. Without a token
+// only published recipes are found.
+func (c *APIClient) GetRecipeSteps(ctx context.Context, token, recipeID, units string) (*RecipeSteps, error) {
+	var resp struct {
+		Success bool        `json:"success"`
+		Data    RecipeSteps `json:"data"`
+		Error   string      `json:"error,omitempty"`
+	}
+
+	path := "/api/v1/recipes/" + url.PathEscape(recipeID) + "/steps"
+	if units != "" {
+		path += "?units=" + url.QueryEscape(units)
+	}
+	if err := c.getWithAuth(ctx, path, token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get recipe steps: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// FoodSafetyIssue is a food safety rule a recipe breaks; Step is 0 for the
+// whole recipe
+type FoodSafetyIssue struct {
+	Step    int    `json:"step"`
+	Rule    string `json:"rule"`
+	Food    string `json:"food"`
+	Message string `json:"message"`
+}
+
+// GetFoodSafety checks a recipe's steps against food safety rules. Without
+// a token only published recipes are found.
+func (c *APIClient) GetFoodSafety(ctx context.Context, token, recipeID string) ([]FoodSafetyIssue, error) {
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Issues []FoodSafetyIssue `json:"issues"`
+		} `json:"data"`
+		Error string `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/recipes/"+url.PathEscape(recipeID)+"/food-safety", token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to check food safety: %s", resp.Error)
+	}
+
+	return resp.Data.Issues, nil
+}
+
+// RecipeAllergens is a recipe's allergen disclosure
+type RecipeAllergens struct {
+	RecipeID  string            `json:"recipe_id"`
+	Title     string            `json:"title"`
+	Panel     string            `json:"panel"`
+	Allergens []AllergenFinding `json:"allergens"`
+}
+
+// AllergenFinding is an allergen a recipe contains or may contain
+type AllergenFinding struct {
+	Allergen string           `json:"allergen"`
+	Label    string           `json:"label"`
+	Status   string           `json:"status"`
+	Sources  []AllergenSource `json:"sources"`
+}
+
+// AllergenSource is an ingredient bringing an allergen in, and why
+type AllergenSource struct {
+	Ingredient string `json:"ingredient"`
+	Reason     string `json:"reason"`
+}
+
+// GetRecipeAllergens fetches a recipe's allergen panel. Without a token
+// only published recipes are found.
+func (c *APIClient) GetRecipeAllergens(ctx context.Context, token, recipeID string) (*RecipeAllergens, error) {
+	var resp struct {
+		Success bool            `json:"success"`
+		Data    RecipeAllergens `json:"data"`
+		Error   string          `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/recipes/"+url.PathEscape(recipeID)+"/allergens", token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get recipe allergens: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// ScaledRecipe is a recipe's ingredients recomputed for a serving count
+type ScaledRecipe struct {
+	RecipeID         string             `json:"recipe_id"`
+	Title            string             `json:"title"`
+	Servings         int                `json:"servings"`
+	OriginalServings int                `json:"original_servings"`
+	Units            string             `json:"units"`
+	Ingredients      []ScaledIngredient `json:"ingredients"`
+}
+
+// ScaledIngredient is one ingredient at the requested servings
+type ScaledIngredient struct {
+	Name     string `json:"name"`
+	Quantity string `json:"quantity"`
+	Optional bool   `json:"optional"`
+	Notes    string `json:"notes"`
+}
+
+// ScaleRecipe fetches a recipe's ingredients for servings, zero for the
+// recipe's own, in units, blank for the user's preference. Without a token
+// only published recipes are found.
+func (c *APIClient) ScaleRecipe(ctx context.Context, token, recipeID string, servings int, units string) (*ScaledRecipe, error) {
+	var resp struct {
+		Success bool         `json:"success"`
+		Data    ScaledRecipe `json:"data"`
+		Error   string       `json:"error,omitempty"`
+	}
+
+	query := url.Values{}
+	if servings > 0 {
+		query.Set("servings", strconv.Itoa(servings))
+	}
+	if units != "" {
+		query.Set("units", units)
+	}
+	path := "/api/v1/recipes/" + url.PathEscape(recipeID) + "/scaled"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	if err := c.getWithAuth(ctx, path, token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to scale recipe: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// IngredientSubstitutes are what to use in place of an ingredient
+type IngredientSubstitutes struct {
+	Ingredient  string                 `json:"ingredient"`
+	Matched     string                 `json:"matched"`
+	Substitutes []IngredientSubstitute `json:"substitutes"`
+	Diets       []string               `json:"diets"`
+	Hidden      int                    `json:"hidden"`
+}
+
+// IngredientSubstitute is one suggested substitute
+type IngredientSubstitute struct {
+	Name      string `json:"name"`
+	Ratio     string `json:"ratio"`
+	Notes     string `json:"notes"`
+	Suggested bool   `json:"suggested"`
+}
+
+// GetSubstitutes fetches the substitutes for an ingredient. With a token
+// they suit the user's dietary preferences.
+func (c *APIClient) GetSubstitutes(ctx context.Context, token, ingredient string) (*IngredientSubstitutes, error) {
+	var resp struct {
+		Success bool                  `json:"success"`
+		Data    IngredientSubstitutes `json:"data"`
+		Error   string                `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/ingredients/"+url.PathEscape(ingredient)+"/substitutes", token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get substitutes: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// AdminUser is a user as the admin section lists them
+type AdminUser struct {
+	ID          string     `json:"id"`
+	Email       string     `json:"email"`
+	Name        string     `json:"name"`
+	Role        string     `json:"role"`
+	Status      string     `json:"status"`
+	Verified    bool       `json:"verified"`
+	Recipes     int        `json:"recipes"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+// AdminUserPage is one page of the admin user list
+type AdminUserPage struct {
+	Users  []AdminUser `json:"users"`
+	Total  int         `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+// SystemStats are the admin dashboard totals
+type SystemStats struct {
+	Users struct {
+		Total     int `json:"total"`
+		Active    int `json:"active"`
+		Suspended int `json:"suspended"`
+		Admins    int `json:"admins"`
+		New       int `json:"new"`
+	} `json:"users"`
+	Recipes struct {
+		Total       int `json:"total"`
+		Draft       int `json:"draft"`
+		Published   int `json:"published"`
+		Archived    int `json:"archived"`
+		AIGenerated int `json:"ai_generated"`
+		New         int `json:"new"`
+	} `json:"recipes"`
+	Comments struct {
+		Total  int `json:"total"`
+		Hidden int `json:"hidden"`
+	} `json:"comments"`
+	Runtime struct {
+		StartedAt  time.Time `json:"started_at"`
+		Uptime     string    `json:"uptime"`
+		GoVersion  string    `json:"go_version"`
+		Goroutines int       `json:"goroutines"`
+		HeapMB     float64   `json:"heap_mb"`
+	} `json:"runtime"`
+}
+
+// AIContent is an AI-generated recipe with the prompt behind it
+type AIContent struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	AuthorID    string    `json:"author_id"`
+	AuthorEmail string    `json:"author_email"`
+	Status      string    `json:"status"`
+	Model       string    `json:"model"`
+	Prompt      string    `json:"prompt"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AIContentPage is one page of AI-generated recipes
+type AIContentPage struct {
+	Recipes []AIContent `json:"recipes"`
+	Total   int         `json:"total"`
+	Limit   int         `json:"limit"`
+	Offset  int         `json:"offset"`
+}
+
+// GetSystemStats reads the admin dashboard totals
+func (c *APIClient) GetSystemStats(ctx context.Context, token string) (*SystemStats, error) {
+	var resp struct {
+		Success bool        `json:"success"`
+		Data    SystemStats `json:"data"`
+		Error   string      `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/admin/stats", token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get system stats: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// ListAdminUsers pages through users; query carries q, status, role, page
+// and limit
+func (c *APIClient) ListAdminUsers(ctx context.Context, token string, query url.Values) (*AdminUserPage, error) {
+	var resp struct {
+		Success bool          `json:"success"`
+		Data    AdminUserPage `json:"data"`
+		Error   string        `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/admin/users?"+query.Encode(), token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to list users: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// SetUserSuspended suspends a user, or reinstates them
+func (c *APIClient) SetUserSuspended(ctx context.Context, token, userID string, suspended bool, reason string) (*AdminUser, error) {
+	var resp struct {
+		Success bool      `json:"success"`
+		Data    AdminUser `json:"data"`
+		Error   string    `json:"error,omitempty"`
+	}
+
+	action := "reinstate"
+	if suspended {
+		action = "suspend"
+	}
+	req := map[string]string{"reason": reason}
+	if err := c.postWithAuth(ctx, "/api/v1/admin/users/"+url.PathEscape(userID)+"/"+action, token, req, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to %s user: %s", action, resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// ListAIContent pages through AI-generated recipes
+func (c *APIClient) ListAIContent(ctx context.Context, token string, page, limit int) (*AIContentPage, error) {
+	var resp struct {
+		Success bool          `json:"success"`
+		Data    AIContentPage `json:"data"`
+		Error   string        `json:"error,omitempty"`
+	}
+
+	path := fmt.Sprintf("/api/v1/admin/ai-content?page=%d&limit=%d", page, limit)
+	if err := c.getWithAuth(ctx, path, token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to list AI content: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// AdminUnpublishRecipe archives any author's recipe
+func (c *APIClient) AdminUnpublishRecipe(ctx context.Context, token, recipeID, reason string) error {
+	var resp struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	req := map[string]string{"reason": reason}
+	if err := c.postWithAuth(ctx, "/api/v1/admin/recipes/"+url.PathEscape(recipeID)+"/unpublish", token, req, &resp); err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to unpublish recipe: %s", resp.Error)
+	}
+
+	return nil
+}
+
+// RecipeTimeline is a recipe's steps scheduled backwards from a serve time
+type RecipeTimeline struct {
+	RecipeID       string         `json:"recipe_id"`
+	Title          string         `json:"title"`
+	ServeAt        time.Time      `json:"serve_at"`
+	RequestedAt    time.Time      `json:"requested_serve_at"`
+	StartAt        time.Time      `json:"start_at"`
+	TotalMinutes   int            `json:"total_minutes"`
+	ActiveMinutes  int            `json:"active_minutes"`
+	BehindMinutes  int            `json:"behind_minutes"`
+	CompletedSteps int            `json:"completed_steps"`
+	Steps          []TimelineStep `json:"steps"`
+}
+
+// TimelineStep is when one step of a recipe timeline runs
+type TimelineStep struct {
+	Number   int       `json:"number"`
+	Text     string    `json:"text"`
+	StartAt  time.Time `json:"start_at"`
+	EndAt    time.Time `json:"end_at"`
+	Minutes  int       `json:"minutes"`
+	HandsOff bool      `json:"hands_off"`
+	Parallel bool      `json:"parallel"`
+}
+
+// GetRecipeTimeline plans a recipe's steps so it is ready at serveAt, or as
+// soon as possible when serveAt is zero, with the first completed steps done
+func (c *APIClient) GetRecipeTimeline(ctx context.Context, token, recipeID string, serveAt time.Time, completed int) (*RecipeTimeline, error) {
+	var resp struct {
+		Success bool           `json:"success"`
+		Data    RecipeTimeline `json:"data"`
+		Error   string         `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, timelinePath(recipeID, "timeline", serveAt, completed), token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get recipe timeline: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// GetRecipeTimelineICS downloads a recipe timeline as an iCalendar file
+func (c *APIClient) GetRecipeTimelineICS(ctx context.Context, token, recipeID string, serveAt time.Time, completed int) ([]byte, error) {
+	resp, err := c.send(ctx, "GET", timelinePath(recipeID, "timeline.ics", serveAt, completed), headers(token), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("API error: status %d", resp.StatusCode)
+	}
+
+	return body, nil
+}
+
+func timelinePath(recipeID, resource string, serveAt time.Time, completed int) string {
+	query := url.Values{}
+	if !serveAt.IsZero() {
+		query.Set("serve_at", serveAt.Format(time.RFC3339))
+	}
+	if completed > 0 {
+		query.Set("completed", strconv.Itoa(completed))
+	}
+	path := "/api/v1/recipes/" + url.PathEscape(recipeID) + "/" + resource
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path
+}
+
+// RecipeDownload is a recipe exported as a file
+type RecipeDownload struct {
+	ContentType        string
+	ContentDisposition string
+	Content            []byte
+}
+
+// DownloadRecipe exports a recipe as pdf, json or markdown
+func (c *APIClient) DownloadRecipe(ctx context.Context, token, recipeID, format string) (*RecipeDownload, error) {
+	path := "/api/v1/recipes/" + url.PathEscape(recipeID) + "/export?format=" + url.QueryEscape(format)
+	resp, err := c.send(ctx, "GET", path, headers(token), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, &APIStatusError{Status: resp.StatusCode}
+	}
+
+	return &RecipeDownload{
+		ContentType:        resp.Header.Get("Content-Type"),
+		ContentDisposition: resp.Header.Get("Content-Disposition"),
+		Content:            body,
+	}, nil
+}
+
+// GetRecipePrintout reads a recipe's print layout from its JSON export
+func (c *APIClient) GetRecipePrintout(ctx context.Context, token, recipeID string) (*printout.Printout, error) {
+	download, err := c.DownloadRecipe(ctx, token, recipeID, "json")
+	if err != nil {
+		return nil, err
+	}
+
+	var p printout.Printout
+	if err := json.Unmarshal(download.Content, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode recipe printout: %w", err)
+	}
+	return &p, nil
+}
+
+// Battle is a remix battle between the AI and a community chef. Entries
+// only carry their side and votes once voting has ended.
+type Battle struct {
+	ID           string        `json:"id"`
+	Prompt       string        `json:"prompt"`
+	ChefID       string        `json:"chef_id"`
+	Status       string        `json:"status"`
+	VotingEndsAt time.Time     `json:"voting_ends_at"`
+	Entries      []BattleEntry `json:"entries"`
+	YourVote     string        `json:"your_vote"`
+	Winner       string        `json:"winner"`
+	Template     string        `json:"template"`
+}
+
+// BattleEntry is one side's recipe in a battle, labelled "a" or "b"
+type BattleEntry struct {
+	Label       string   `json:"label"`
+	Side        string   `json:"side"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Ingredients []string `json:"ingredients"`
+	Steps       []string `json:"steps"`
+	Votes       *int     `json:"votes"`
+}
+
+// GetBattle reads a battle as the signed-in user, or a visitor, sees it
+func (c *APIClient) GetBattle(ctx context.Context, token, battleID string) (*Battle, error) {
+	var resp struct {
+		Success bool   `json:"success"`
+		Data    Battle `json:"data"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/battles/"+url.PathEscape(battleID), token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get battle: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// VoteBattle votes for entry "a" or "b" of a battle
+func (c *APIClient) VoteBattle(ctx context.Context, token, battleID, entry string) (*Battle, error) {
+	var resp struct {
+		Success bool   `json:"success"`
+		Data    Battle `json:"data"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	req := map[string]string{"entry": entry}
+	if err := c.postWithAuth(ctx, "/api/v1/battles/"+url.PathEscape(battleID)+"/votes", token, req, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to vote: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// UserSummary represents the public author data returned by users:batchGet
+type UserSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// BatchGetRecipes fetches several recipes in one call, keyed by ID
+func (c *APIClient) BatchGetRecipes(ctx context.Context, token string, ids []string) (map[string]RecipeResponse, error) {
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Items    []RecipeResponse `json:"items"`
+			NotFound []string         `json:"not_found"`
+		} `json:"data"`
+		Error string `json:"error,omitempty"`
+	}
+
+	path := "/api/v1/recipes:batchGet?fields=" + url.QueryEscape(recipeCardFields) + "&ids=" + url.QueryEscape(strings.Join(ids, ","))
+	if err := c.getWithAuth(ctx, path, token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to batch get recipes: %s", resp.Error)
+	}
+
+	results := make(map[string]RecipeResponse, len(resp.Data.Items))
+	for _, item := range resp.Data.Items {
+		results[item.ID] = item
+	}
+	return results, nil
+}
+
+// BatchGetUsers fetches several user summaries in one call, keyed by ID
+func (c *APIClient) BatchGetUsers(ctx context.Context, token string, ids []string) (map[string]UserSummary, error) {
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Items    []UserSummary `json:"items"`
+			NotFound []string      `json:"not_found"`
+		} `json:"data"`
+		Error string `json:"error,omitempty"`
+	}
+
+	path := "/api/v1/users:batchGet?ids=" + url.QueryEscape(strings.Join(ids, ","))
+	if err := c.getWithAuth(ctx, path, token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to batch get users: %s", resp.Error)
+	}
+
+	results := make(map[string]UserSummary, len(resp.Data.Items))
+	for _, item := range resp.Data.Items {
+		results[item.ID] = item
+	}
+	return results, nil
+}
+
+// LoadRecipe resolves a recipe through the coalescing loader
+func (c *APIClient) LoadRecipe(ctx context.Context, token, recipeID string) (*RecipeResponse, error) {
+	recipe, ok, err := c.recipeLoader.Load(ctx, token, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("recipe %s not found", recipeID)
+	}
+	return &recipe, nil
+}
+
+// LoadUsers resolves user summaries through the coalescing loader
+func (c *APIClient) LoadUsers(ctx context.Context, token string, userIDs []string) (map[string]UserSummary, error) {
+	return c.userLoader.LoadMany(ctx, token, userIDs)
+}
+
+// recipeCardFields is the sparse fieldset needed to render a recipe card
+const recipeCardFields = "id,title,description,author_id,cook_time,prep_time,servings,difficulty,cuisine,category,likes,rating,allergens,created_at"
+
+// UpdateTheme persists the user's theme preference
+func (c *APIClient) UpdateTheme(ctx context.Context, token, theme string) error {
+	var resp struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	if err := c.putWithAuth(ctx, "/api/v1/auth/profile/theme", token, map[string]string{"theme": theme}, &resp); err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to update theme: %s", resp.Error)
+	}
+
+	return nil
+}
+
+// UpdateUnits persists the units the user reads recipes in
+func (c *APIClient) UpdateUnits(ctx context.Context, token, units string) error {
+	var resp struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	if err := c.putWithAuth(ctx, "/api/v1/auth/profile/units", token, map[string]string{"units": units}, &resp); err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to update units: %s", resp.Error)
+	}
+
+	return nil
+}
+
+// DietaryProfile is the diets a user follows, the allergens they avoid and
+// the ingredients they would rather not see
+type DietaryProfile struct {
+	DietaryRestrictions []string `json:"dietary_restrictions"`
+	Allergies           []string `json:"allergies"`
+	DislikedIngredients []string `json:"disliked_ingredients"`
+}
+
+// GetDietaryProfile fetches the signed-in user's dietary profile
+func (c *APIClient) GetDietaryProfile(ctx context.Context, token string) (*DietaryProfile, error) {
+	var resp struct {
+		Success bool           `json:"success"`
+		Data    DietaryProfile `json:"data"`
+		Error   string         `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/auth/profile/diet", token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get dietary profile: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// UpdateDietaryProfile replaces the signed-in user's dietary profile and
+// returns it as saved
+func (c *APIClient) UpdateDietaryProfile(ctx context.Context, token string, profile DietaryProfile) (*DietaryProfile, error) {
+	var resp struct {
+		Success bool           `json:"success"`
+		Data    DietaryProfile `json:"data"`
+		Error   string         `json:"error,omitempty"`
+	}
+
+	if err := c.putWithAuth(ctx, "/api/v1/auth/profile/diet", token, profile, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to update dietary profile: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// AccountDeletion is where the signed-in user's account deletion stands
+type AccountDeletion struct {
+	Pending      bool       `json:"pending"`
+	RequestedAt  *time.Time `json:"requested_at,omitempty"`
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+}
+
+// GetAccountDeletion fetches the signed-in user's pending account deletion
+func (c *APIClient) GetAccountDeletion(ctx context.Context, token string) (*AccountDeletion, error) {
+	var resp struct {
+		Success bool            `json:"success"`
+		Data    AccountDeletion `json:"data"`
+		Error   string          `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/me/deletion", token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get account deletion: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// DeleteAccount schedules the signed-in user's account for erasure. A
+// wrong password is an APIStatusError with status 403.
+func (c *APIClient) DeleteAccount(ctx context.Context, token, password string) (*AccountDeletion, error) {
+	var resp struct {
+		Success bool            `json:"success"`
+		Data    AccountDeletion `json:"data"`
+		Error   string          `json:"error,omitempty"`
+	}
+
+	body := map[string]string{"password": password}
+	if err := c.sendWithAuth(ctx, "DELETE", "/api/v1/me", token, body, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to delete account: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// CancelAccountDeletion keeps the signed-in user's account
+func (c *APIClient) CancelAccountDeletion(ctx context.Context, token string) error {
+	var resp struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	if err := c.sendWithAuth(ctx, "DELETE", "/api/v1/me/deletion", token, struct{}{}, &resp); err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to cancel account deletion: %s", resp.Error)
+	}
+
+	return nil
+}
+
+// Notification is one in-app notification
+type Notification struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Link      string    `json:"link"`
+	Read      bool      `json:"read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NotificationPage is a page of notifications with the unread total
+type NotificationPage struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int            `json:"unread"`
+}
+
+// NotificationPreference is whether the user receives one notification type
+type NotificationPreference struct {
+	Type    string `json:"type"`
+	Label   string `json:"label"`
+	Enabled bool   `json:"enabled"`
+}
+
+// GetNotifications fetches the newest notifications
+func (c *APIClient) GetNotifications(ctx context.Context, token string, limit int) (*NotificationPage, error) {
+	var resp struct {
+		Success bool             `json:"success"`
+		Data    NotificationPage `json:"data"`
+		Error   string           `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, fmt.Sprintf("/api/v1/notifications?limit=%d", limit), token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get notifications: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// GetUnreadNotificationCount fetches the number behind the nav badge
+func (c *APIClient) GetUnreadNotificationCount(ctx context.Context, token string) (int, error) {
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Unread int `json:"unread"`
+		} `json:"data"`
+		Error string `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/notifications/unread-count", token, &resp); err != nil {
+		return 0, err
+	}
+
+	if !resp.Success {
+		return 0, fmt.Errorf("failed to count notifications: %s", resp.Error)
+	}
+
+	return resp.Data.Unread, nil
+}
+
+// MarkAllNotificationsRead clears the unread badge
+func (c *APIClient) MarkAllNotificationsRead(ctx context.Context, token string) error {
+	var resp struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	if err := c.postWithAuth(ctx, "/api/v1/notifications/read-all", token, struct{}{}, &resp); err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to mark notifications read: %s", resp.Error)
+	}
+
+	return nil
+}
+
+// GetNotificationPreferences fetches which notification types are on
+func (c *APIClient) GetNotificationPreferences(ctx context.Context, token string) ([]NotificationPreference, error) {
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Preferences []NotificationPreference `json:"preferences"`
+		} `json:"data"`
+		Error string `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/notifications/preferences", token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get notification preferences: %s", resp.Error)
+	}
+
+	return resp.Data.Preferences, nil
+}
+
+// UpdateNotificationPreferences turns notification types on or off
+func (c *APIClient) UpdateNotificationPreferences(ctx context.Context, token string, enabled map[string]bool) ([]NotificationPreference, error) {
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Preferences []NotificationPreference `json:"preferences"`
+		} `json:"data"`
+		Error string `json:"error,omitempty"`
+	}
+
+	req := map[string]interface{}{"enabled": enabled}
+	if err := c.putWithAuth(ctx, "/api/v1/notifications/preferences", token, req, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to update notification preferences: %s", resp.Error)
+	}
+
+	return resp.Data.Preferences, nil
+}
+
+// Comment is a recipe comment with its replies
+type Comment struct {
+	ID         string     `json:"id"`
+	AuthorID   string     `json:"author_id"`
+	AuthorName string     `json:"author_name"`
+	Content    string     `json:"content"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	EditedAt   *time.Time `json:"edited_at"`
+	Replies    []Comment  `json:"replies"`
+}
+
+// FlaggedComment is a comment in the admin flag queue
+type FlaggedComment struct {
+	Comment        Comment        `json:"comment"`
+	Flags          int            `json:"flags"`
+	Reasons        map[string]int `json:"reasons"`
+	FirstFlaggedAt time.Time      `json:"first_flagged_at"`
+}
+
+// GetComments fetches a recipe's comment threads
+func (c *APIClient) GetComments(ctx context.Context, token, recipeID string) ([]Comment, error) {
+	var resp struct {
+		Success bool      `json:"success"`
+		Data    []Comment `json:"data"`
+		Error   string    `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/recipes/"+url.PathEscape(recipeID)+"/comments", token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get comments: %s", resp.Error)
+	}
+
+	return resp.Data, nil
+}
+
+// AddComment posts a comment, answering parentID when it is set
+func (c *APIClient) AddComment(ctx context.Context, token, recipeID, parentID, content string) error {
+	req := map[string]interface{}{"content": content}
+	if parentID != "" {
+		req["parent_id"] = parentID
+	}
+	return c.commentAction(ctx, "POST", "/api/v1/recipes/"+url.PathEscape(recipeID)+"/comments", token, req, "post comment")
+}
+
+// EditComment replaces the text of the user's comment
+func (c *APIClient) EditComment(ctx context.Context, token, recipeID, commentID, content string) error {
+	return c.commentAction(ctx, "PUT", commentPath(recipeID, commentID), token, map[string]string{"content": content}, "edit comment")
+}
+
+// DeleteComment deletes a comment
+func (c *APIClient) DeleteComment(ctx context.Context, token, recipeID, commentID string) error {
+	return c.commentAction(ctx, "DELETE", commentPath(recipeID, commentID), token, struct{}{}, "delete comment")
+}
+
+// FlagComment asks moderators to look at a comment
+func (c *APIClient) FlagComment(ctx context.Context, token, recipeID, commentID, reason string) error {
+	return c.commentAction(ctx, "POST", commentPath(recipeID, commentID)+"/flag", token, map[string]string{"reason": reason}, "flag comment")
+}
+
+// ListFlaggedComments fetches the admin flag queue
+func (c *APIClient) ListFlaggedComments(ctx context.Context, token string, limit int) ([]FlaggedComment, error) {
+	var resp struct {
+		Success bool             `json:"success"`
+		Data    []FlaggedComment `json:"data"`
+		Error   string           `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, fmt.Sprintf("/api/v1/admin/comments/flagged?limit=%d", limit), token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to list flagged comments: %s", resp.Error)
+	}
+
+	return resp.Data, nil
+}
+
+// ReviewFlaggedComment hides a flagged comment or dismisses its flags
+func (c *APIClient) ReviewFlaggedComment(ctx context.Context, token, commentID string, hide bool, reason string) error {
+	req := map[string]interface{}{"hide": hide, "reason": reason}
+	return c.commentAction(ctx, "POST", "/api/v1/admin/comments/"+url.PathEscape(commentID)+"/flags/review", token, req, "review flagged comment")
+}
+
+// ContentReport is an open report of a recipe or comment in the admin
+// report queue
+type ContentReport struct {
+	ID          string    `json:"id"`
+	TargetType  string    `json:"target_type"`
+	TargetID    string    `json:"target_id"`
+	RecipeID    string    `json:"recipe_id"`
+	Reason      string    `json:"reason"`
+	Note        string    `json:"note"`
+	Unpublished bool      `json:"unpublished"`
+	OpenReports int       `json:"open_reports"`
+	Summary     string    `json:"summary"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReportPage is one page of the admin report queue
+type ReportPage struct {
+	Reports []ContentReport `json:"reports"`
+	Total   int64           `json:"total"`
+}
+
+// ListReports fetches the oldest open reports
+func (c *APIClient) ListReports(ctx context.Context, token string, limit int) (*ReportPage, error) {
+	var resp struct {
+		Success bool       `json:"success"`
+		Data    ReportPage `json:"data"`
+		Error   string     `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, fmt.Sprintf("/api/v1/admin/reports?limit=%d", limit), token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to list reports: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// ReviewReport resolves a report, taking the content down, or dismisses it
+func (c *APIClient) ReviewReport(ctx context.Context, token, reportID string, resolve bool, note string) error {
+	verdict := "dismiss"
+	if resolve {
+		verdict = "resolve"
+	}
+	var resp struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	path := "/api/v1/admin/reports/" + url.PathEscape(reportID) + "/" + verdict
+	if err := c.postWithAuth(ctx, path, token, map[string]string{"note": note}, &resp); err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to %s report: %s", verdict, resp.Error)
+	}
+
+	return nil
+}
+
+func (c *APIClient) commentAction(ctx context.Context, method, path, token string, body interface{}, action string) error {
+	var resp struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	if err := c.sendWithAuth(ctx, method, path, token, body, &resp); err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to %s: %s", action, resp.Error)
+	}
+
+	return nil
+}
+
+func commentPath(recipeID, commentID string) string {
+	return "/api/v1/recipes/" + url.PathEscape(recipeID) + "/comments/" + url.PathEscape(commentID)
+}
+
+// CreateRecipe creates a new recipe
+func (c *APIClient) CreateRecipe(ctx context.Context, token string, recipe CreateRecipeRequest) (*RecipeResponse, error) {
+	var resp struct {
+		Success bool           `json:"success"`
+		Data    RecipeResponse `json:"data"`
+		Error   string         `json:"error,omitempty"`
+	}
+
+	err := c.postWithAuth(ctx, "/api/v1/recipes", token, recipe, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to create recipe: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// EditableRecipe is what the edit form needs of a recipe
+type EditableRecipe struct {
+	ID           string               `json:"id"`
+	Title        string               `json:"title"`
+	Description  string               `json:"description"`
+	AuthorID     string               `json:"author_id"`
+	Servings     int                  `json:"servings"`
+	PrepTime     int                  `json:"prep_time"`
+	CookTime     int                  `json:"cook_time"`
+	Difficulty   string               `json:"difficulty"`
+	Tags         []string             `json:"tags"`
+	Ingredients  []EditableIngredient `json:"ingredients"`
+	Instructions []EditableStep       `json:"instructions"`
+}
+
+// EditableIngredient is one ingredient of an EditableRecipe
+type EditableIngredient struct {
+	Name     string  `json:"name"`
+	Amount   float64 `json:"amount"`
+	Unit     string  `json:"unit"`
+	Optional bool    `json:"optional"`
+	Notes    string  `json:"notes"`
+}
+
+// EditableStep is one step of an EditableRecipe
+type EditableStep struct {
+	Description string `json:"description"`
+}
+
+// UpdateRecipeRequest is an edit of a recipe; ingredients, instructions
+// and tags replace the whole list
+type UpdateRecipeRequest struct {
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Servings     int      `json:"servings"`
+	PrepTime     int      `json:"prep_time"`
+	CookTime     int      `json:"cook_time"`
+	Difficulty   string   `json:"difficulty"`
+	Tags         []string `json:"tags"`
+	Ingredients  []string `json:"ingredients"`
+	Instructions []string `json:"instructions"`
+}
+
+// GetEditableRecipe fetches a recipe with its ingredients and steps
+func (c *APIClient) GetEditableRecipe(ctx context.Context, token, recipeID string) (*EditableRecipe, error) {
+	var resp struct {
+		Success bool           `json:"success"`
+		Data    EditableRecipe `json:"data"`
+		Error   string         `json:"error,omitempty"`
+	}
+
+	if err := c.getWithAuth(ctx, "/api/v1/recipes/"+url.PathEscape(recipeID), token, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to get recipe: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// URLImportPreview is the draft a recipe page would be imported as.
+// SourceURL is the page after redirects; Method is how the recipe was
+// read: schema.org, microdata or heuristic. DuplicateOf is the user's
+// recipe with the same title, if any.
+type URLImportPreview struct {
+	SourceURL   string         `json:"source_url"`
+	Method      string         `json:"method"`
+	Recipe      EditableRecipe `json:"recipe"`
+	Warnings    []string       `json:"warnings"`
+	DuplicateOf string         `json:"duplicate_of"`
+}
+
+// URLImportResult is the draft created from a page. Status is created, or
+// duplicate when the user already had the recipe.
+type URLImportResult struct {
+	RecipeID string `json:"recipe_id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+}
+
+// PreviewRecipeImport reads the recipe on a page without saving it. The
+// API answers 400 for addresses it cannot fetch or pages without a recipe.
+func (c *APIClient) PreviewRecipeImport(ctx context.Context, token, pageURL string) (*URLImportPreview, error) {
+	var resp struct {
+		Success bool             `json:"success"`
+		Data    URLImportPreview `json:"data"`
+		Error   string           `json:"error,omitempty"`
+	}
+
+	body := map[string]interface{}{"url": pageURL, "preview": true}
+	if err := c.postWithAuth(ctx, "/api/v1/recipes/import", token, body, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to preview import: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// ImportRecipeURL saves the recipe on a page as a draft
+func (c *APIClient) ImportRecipeURL(ctx context.Context, token, pageURL string) (*URLImportResult, error) {
+	var resp struct {
+		Success bool            `json:"success"`
+		Data    URLImportResult `json:"data"`
+		Error   string          `json:"error,omitempty"`
+	}
+
+	if err := c.postWithAuth(ctx, "/api/v1/recipes/import", token, map[string]string{"url": pageURL}, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to import recipe: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// UpdateRecipe saves an edit. Only the author or an admin may edit; the
+// API answers anyone else with 403.
+func (c *APIClient) UpdateRecipe(ctx context.Context, token, recipeID string, recipe UpdateRecipeRequest) (*RecipeResponse, error) {
+	var resp struct {
+		Success bool           `json:"success"`
+		Data    RecipeResponse `json:"data"`
+		Error   string         `json:"error,omitempty"`
+	}
+
+	if err := c.putWithAuth(ctx, "/api/v1/recipes/"+url.PathEscape(recipeID), token, recipe, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to update recipe: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// UndoInfo is the undo token returned by destructive API actions
+type UndoInfo struct {
+	Token      string    `json:"undo_token"`
+	Kind       string    `json:"kind"`
+	SubjectID  string    `json:"subject_id"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	TTLSeconds int       `json:"ttl_seconds"`
+}
+
+// DeleteRecipe soft-deletes a recipe and returns its undo token
+func (c *APIClient) DeleteRecipe(ctx context.Context, token, recipeID string) (*UndoInfo, error) {
+	return c.undoableAction(ctx, "DELETE", "/api/v1/recipes/"+url.PathEscape(recipeID), token, "delete recipe")
+}
+
+// UnpublishRecipe archives a recipe and returns its undo token
+func (c *APIClient) UnpublishRecipe(ctx context.Context, token, recipeID string) (*UndoInfo, error) {
+	return c.undoableAction(ctx, "POST", "/api/v1/recipes/"+url.PathEscape(recipeID)+"/unpublish", token, "unpublish recipe")
+}
+
+// Undo redeems an undo token
+func (c *APIClient) Undo(ctx context.Context, token, undoToken string) error {
+	var resp struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	if err := c.postWithAuth(ctx, "/api/v1/undo/"+url.PathEscape(undoToken), token, struct{}{}, &resp); err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to undo: %s", resp.Error)
+	}
+
+	return nil
+}
+
+func (c *APIClient) undoableAction(ctx context.Context, method, path, token, action string) (*UndoInfo, error) {
+	var resp struct {
+		Success bool      `json:"success"`
+		Data    *UndoInfo `json:"data"`
+		Error   string    `json:"error,omitempty"`
+	}
+
+	if err := c.sendWithAuth(ctx, method, path, token, struct{}{}, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to %s: %s", action, resp.Error)
+	}
+
+	// Data is nil when the API could not issue an undo token
+	return resp.Data, nil
+}
+
+// AI Features
+
+// GenerateRecipe generates a recipe using AI, written in language when it
+// is set
+func (c *APIClient) GenerateRecipe(ctx context.Context, token, prompt, language string) (*RecipeResponse, error) {
+	req := map[string]interface{}{
+		"prompt": prompt,
+	}
+	if language != "" {
+		req["language"] = language
+	}
+
+	var resp struct {
+		Success bool           `json:"success"`
+		Data    RecipeResponse `json:"data"`
+		Error   string         `json:"error,omitempty"`
+	}
+
+	err := c.postWithAuth(ctx, "/api/v1/ai/generate-recipe", token, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to generate recipe: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// GuestSession is a guest's token and limits
+type GuestSession struct {
+	Token       string    `json:"guest_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	MaxRecipes  int       `json:"max_recipes"`
+	MaxMessages int       `json:"max_messages"`
+}
+
+// GuestRecipeDraft is a recipe the AI chef suggested to a guest
+type GuestRecipeDraft struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Ingredients []string `json:"ingredients"`
+	Directions  []string `json:"directions"`
+	Servings    int      `json:"servings,omitempty"`
+	PrepTime    int      `json:"prep_time,omitempty"`
+	CookTime    int      `json:"cook_time,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// GuestChatReply is the AI chef's answer to a guest
+type GuestChatReply struct {
+	Reply        string            `json:"reply"`
+	Suggestion   *GuestRecipeDraft `json:"suggestion,omitempty"`
+	MessagesLeft int               `json:"messages_left"`
+}
+
+// GuestClaim names the drafts made from a guest's recipes
+type GuestClaim struct {
+	RecipeIDs []string `json:"recipe_ids"`
+	Claimed   int      `json:"claimed"`
+}
+
+// StartGuestSession starts a guest session for the visitor at clientIP,
+// which the API limits sessions by
+func (c *APIClient) StartGuestSession(ctx context.Context, clientIP string) (*GuestSession, error) {
+	var resp struct {
+		Success bool         `json:"success"`
+		Data    GuestSession `json:"data"`
+		Error   string       `json:"error,omitempty"`
+	}
+
+	header := headers("")
+	if clientIP != "" {
+		header.Set("X-Forwarded-For", clientIP)
+	}
+	if err := c.doRequest(ctx, "POST", "/api/v1/guest/sessions", header, []byte("{}"), &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to start guest session: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// GuestChat asks the AI chef for a recipe as a guest
+func (c *APIClient) GuestChat(ctx context.Context, guestToken, message string) (*GuestChatReply, error) {
+	var resp struct {
+		Success bool           `json:"success"`
+		Data    GuestChatReply `json:"data"`
+		Error   string         `json:"error,omitempty"`
+	}
+
+	body, err := json.Marshal(map[string]string{"message": message})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if err := c.doRequest(ctx, "POST", "/api/v1/guest/chat", guestHeaders(guestToken), body, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to chat as guest: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// SaveGuestRecipe keeps a recipe in the guest session
+func (c *APIClient) SaveGuestRecipe(ctx context.Context, guestToken string, recipe GuestRecipeDraft) error {
+	var resp struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	body, err := json.Marshal(recipe)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if err := c.doRequest(ctx, "POST", "/api/v1/guest/recipes", guestHeaders(guestToken), body, &resp); err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("failed to save guest recipe: %s", resp.Error)
+	}
+
+	return nil
+}
+
+// ClaimGuestRecipes moves a guest's recipes to the signed-in user
+func (c *APIClient) ClaimGuestRecipes(ctx context.Context, token, guestToken string) (*GuestClaim, error) {
+	var resp struct {
+		Success bool       `json:"success"`
+		Data    GuestClaim `json:"data"`
+		Error   string     `json:"error,omitempty"`
+	}
+
+	req := map[string]string{"guest_token": guestToken}
+	if err := c.postWithAuth(ctx, "/api/v1/guest/claim", token, req, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("failed to claim guest recipes: %s", resp.Error)
+	}
+
+	return &resp.Data, nil
+}
+
+// Helper methods
+
+func (c *APIClient) post(ctx context.Context, path string, body interface{}, response interface{}) error {
+	return c.sendWithAuth(ctx, "POST", path, "", body, response)
+}
+
+func (c *APIClient) postWithAuth(ctx context.Context, path, token string, body interface{}, response interface{}) error {
+	return c.sendWithAuth(ctx, "POST", path, token, body, response)
+}
+
+func (c *APIClient) putWithAuth(ctx context.Context, path, token string, body interface{}, response interface{}) error {
+	return c.sendWithAuth(ctx, "PUT", path, token, body, response)
+}
+
+func (c *APIClient) sendWithAuth(ctx context.Context, method, path, token string, body interface{}, response interface{}) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	return c.doRequest(ctx, method, path, headers(token), jsonBody, response)
+}
+
+func (c *APIClient) getWithAuth(ctx context.Context, path, token string, response interface{}) error {
+	return c.doRequest(ctx, "GET", path, headers(token), nil, response)
+}
+
+// headers are the JSON headers, with the bearer token when there is one
+func headers(token string) http.Header {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return header
+}
+
+// guestHeaders are the JSON headers with a guest token
+func guestHeaders(guestToken string) http.Header {
+	header := headers("")
+	header.Set("X-Guest-Token", guestToken)
+	return header
+}
+
+// vouch remembers the user a fresh access token belongs to, with their role
+// so admin routes accept the signed call, and forgets tokens that have
+// expired
+func (c *APIClient) vouch(token string, user UserResponse, ttl time.Duration) {
+	if c.signer == nil || token == "" {
+		return
+	}
+	if ttl <= 0 {
+		ttl = defaultVouchTTL
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for t, v := range c.vouched {
+		if now.After(v.expiresAt) {
+			delete(c.vouched, t)
+		}
+	}
+	identity := svcauth.Identity{UserID: user.ID, Email: user.Email}
+	if user.Role != "" {
+		identity.Roles = []string{user.Role}
+	}
+	c.vouched[token] = vouchedUser{
+		identity:  identity,
+		expiresAt: now.Add(ttl),
+	}
+}
+
+// authorize signs req when service auth is on, replacing the bearer token
+// with the identity it was vouched for. Unknown or expired tokens are sent
+// as anonymous calls, which the API answers like an expired token.
+func (c *APIClient) authorize(req *http.Request, body []byte) error {
+	if c.signer == nil {
+		return nil
+	}
+
+	var identity *svcauth.Identity
+	if token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "); token != "" {
+		req.Header.Del("Authorization")
+		c.mu.Lock()
+		if v, ok := c.vouched[token]; ok && time.Now().Before(v.expiresAt) {
+			identity = &v.identity
+		}
+		c.mu.Unlock()
+	}
+	return c.signer.Sign(req, body, identity)
+}
+
+func (c *APIClient) doRequest(ctx context.Context, method, path string, header http.Header, body []byte, response interface{}) error {
+	resp, err := c.send(ctx, method, path, header, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		c.logger.Error("API error response",
+			zap.Int("status", resp.StatusCode),
+			zap.String("body", string(respBody)),
+		)
+		return &APIStatusError{Status: resp.StatusCode}
+	}
+
+	if err := json.Unmarshal(respBody, response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
+
+// APIStatusError is an API answer with an error status
+type APIStatusError struct {
+	Status int
+}
+
+func (e *APIStatusError) Error() string {
+	return fmt.Sprintf("API error: status %d", e.Status)
+}
+
+// isAPIStatus reports whether err is an API answer with status
+func isAPIStatus(err error, status int) bool {
+	var statusErr *APIStatusError
+	return errors.As(err, &statusErr) && statusErr.Status == status
+}
+
+// send issues a request to an API instance. Calls that are safe to repeat
+// move on to another instance when one is unreachable or answers 502-504;
+// other calls only move on when the connection was never made.
+func (c *APIClient) send(ctx context.Context, method, path string, header http.Header, body []byte) (*http.Response, error) {
+	tried := make(map[string]bool)
+	var lastErr error
+	var lastResp *http.Response
+
+	for attempt := 0; attempt < c.maxAttempts; attempt++ {
+		baseURL, ok := c.balancer.pick(tried)
+		if !ok {
+			break
+		}
+		tried[baseURL] = true
+
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, baseURL+path, reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header = header.Clone()
+		if err := c.authorize(req, body); err != nil {
+			return nil, err
+		}
+
+		c.logger.Debug("API request",
+			zap.String("method", method),
+			zap.String("url", req.URL.String()),
+		)
+
+		if lastResp != nil {
+			lastResp.Body.Close()
+			lastResp = nil
+		}
+		resp, err := c.httpClient.Do(req)
+		if err == nil && !unavailable(resp.StatusCode) {
+			c.balancer.report(baseURL, true)
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			return nil, fmt.Errorf("request failed: %w", ctx.Err())
+		}
+
+		c.balancer.report(baseURL, false)
+		if err != nil {
+			lastErr = fmt.Errorf("request failed: %w", err)
+		} else {
+			lastResp = resp
+		}
+		if !repeatable(method) && !neverSent(err) {
+			break
+		}
+		c.logger.Debug("API instance failed, trying another",
+			zap.String("url", baseURL),
+			zap.Error(err),
+		)
+	}
+
+	if lastResp != nil {
+		return lastResp, nil
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no API backends available")
+	}
+	return nil, lastErr
+}
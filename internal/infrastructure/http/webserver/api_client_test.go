@@ -0,0 +1,91 @@
+package webserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/pkg/svcauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testServiceSecret = "a-shared-secret-of-at-least-32-bytes"
+
+func TestSignedCallsCarryIdentityInsteadOfToken(t *testing.T) {
+	verifier := svcauth.NewVerifier(30*time.Second, testServiceSecret)
+	var seen []*svcauth.Caller
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"), "access tokens stay in the frontend")
+		caller, err := verifier.Verify(r)
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		seen = append(seen, caller)
+		if r.URL.Path == "/api/v1/auth/login" {
+			w.Write([]byte(`{"success":true,"access_token":"tok-1","expires_in":3600,"user":{"id":"u-1","email":"ada@example.com","role":"admin"}}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"data":{"id":"u-1"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv("API_URL", "")
+	client := NewAPIClient(&config.Config{
+		Auth: config.AuthConfig{Service: config.ServiceAuthConfig{
+			Enabled: true,
+			Name:    "web",
+			Secret:  testServiceSecret,
+		}},
+		Web: config.WebConfig{API: config.WebAPIConfig{
+			Discovery:        "static",
+			URLs:             []string{srv.URL},
+			FailureThreshold: 2,
+			MaxAttempts:      1,
+			Timeout:          time.Second,
+		}},
+	}, zap.NewNop())
+	ctx := context.Background()
+
+	login, err := client.Login(ctx, "ada@example.com", "secret")
+	require.NoError(t, err)
+	_, err = client.GetProfile(ctx, login.AccessToken)
+	require.NoError(t, err)
+	_, err = client.GetProfile(ctx, "a-token-this-frontend-never-issued")
+	require.NoError(t, err)
+
+	require.Len(t, seen, 3)
+	assert.Nil(t, seen[0].Identity, "login is an anonymous call")
+	assert.Equal(t, &svcauth.Identity{UserID: "u-1", Email: "ada@example.com", Roles: []string{"admin"}}, seen[1].Identity)
+	assert.Nil(t, seen[2].Identity, "unknown tokens are not vouched for")
+}
+
+func TestLikeRecipeReturnsTheAPICount(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/recipes/r-1/like", r.URL.Path)
+		assert.Equal(t, "Bearer tok-1", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"success":true,"data":{"recipe_id":"r-1","liked":true,"likes":16}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv("API_URL", "")
+	client := NewAPIClient(&config.Config{
+		Web: config.WebConfig{API: config.WebAPIConfig{
+			Discovery:        "static",
+			URLs:             []string{srv.URL},
+			FailureThreshold: 2,
+			MaxAttempts:      1,
+			Timeout:          time.Second,
+		}},
+	}, zap.NewNop())
+
+	status, err := client.LikeRecipe(context.Background(), "tok-1", "r-1")
+	require.NoError(t, err)
+	assert.Equal(t, &LikeStatus{RecipeID: "r-1", Liked: true, Likes: 16}, status)
+}
@@ -0,0 +1,47 @@
+// Package webserver provides the analytics page across an author's recipes
+package webserver
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// handleAuthorAnalytics serves /dashboard/analytics. HTMX requests from
+// the period links get just the author-analytics fragment.
+func (s *WebServer) handleAuthorAnalytics(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	days := statsDays(r)
+
+	analytics, err := s.apiClient.GetAuthorAnalytics(r.Context(), session.AccessToken, days)
+	if err != nil {
+		if r.Header.Get("HX-Request") == "true" {
+			s.logger.Error("Author analytics unavailable", zap.Error(err))
+			w.Write([]byte("<div class=\"error\">Analytics are unavailable right now. Please try again.</div>"))
+			return
+		}
+		s.renderError(w, "Analytics are unavailable right now", err)
+		return
+	}
+
+	view := NewAuthorAnalyticsView(*analytics, days)
+	if r.Header.Get("HX-Request") == "true" {
+		s.renderFragment(w, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderAuthorAnalytics(buf, view)
+		})
+		return
+	}
+
+	var analyticsHTML bytes.Buffer
+	if err := s.fragments.RenderAuthorAnalytics(&analyticsHTML, view); err != nil {
+		s.renderError(w, "Failed to render analytics", err)
+		return
+	}
+	s.renderTemplate(w, "author-analytics", map[string]interface{}{
+		"Title":     "Recipe analytics - Alchemorsel",
+		"Theme":     sessionTheme(session),
+		"Analytics": template.HTML(analyticsHTML.String()),
+	})
+}
@@ -0,0 +1,294 @@
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"go.uber.org/zap"
+)
+
+// BackendStatus describes one API instance for health reporting
+type BackendStatus struct {
+	URL      string `json:"url"`
+	Healthy  bool   `json:"healthy"`
+	Failures int    `json:"failures"`
+}
+
+// resolver finds the base URLs of the API instances
+type resolver interface {
+	resolve(ctx context.Context) ([]string, error)
+}
+
+// newResolver picks the discovery method. API_URL, when set, still names the
+// instances directly, as a comma-separated list.
+func newResolver(cfg config.WebAPIConfig, apiURL string, client *http.Client) resolver {
+	if apiURL != "" {
+		return staticResolver(strings.Split(apiURL, ","))
+	}
+	switch cfg.Discovery {
+	case "dns":
+		return &srvResolver{name: cfg.SRVName, scheme: cfg.Scheme, lookup: net.DefaultResolver.LookupSRV}
+	case "consul":
+		return &consulResolver{address: cfg.ConsulAddress, service: cfg.ConsulService, scheme: cfg.Scheme, client: client}
+	default:
+		return staticResolver(cfg.URLs)
+	}
+}
+
+// staticResolver always returns the configured URLs
+type staticResolver []string
+
+func (s staticResolver) resolve(ctx context.Context) ([]string, error) {
+	urls := make([]string, 0, len(s))
+	for _, u := range s {
+		if u = strings.TrimRight(strings.TrimSpace(u), "/"); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls, nil
+}
+
+// srvResolver reads the instances from a DNS SRV record, keeping only the
+// targets of the most preferred priority
+type srvResolver struct {
+	name   string
+	scheme string
+	lookup func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+func (s *srvResolver) resolve(ctx context.Context) ([]string, error) {
+	_, records, err := s.lookup(ctx, "", "", s.name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up SRV %s: %w", s.name, err)
+	}
+
+	var urls []string
+	for _, record := range records {
+		if record.Priority != records[0].Priority {
+			break
+		}
+		host := strings.TrimSuffix(record.Target, ".")
+		urls = append(urls, s.scheme+"://"+net.JoinHostPort(host, strconv.Itoa(int(record.Port))))
+	}
+	return urls, nil
+}
+
+// consulResolver asks a Consul agent for the passing instances of a service
+type consulResolver struct {
+	address string
+	service string
+	scheme  string
+	client  *http.Client
+}
+
+func (c *consulResolver) resolve(ctx context.Context) ([]string, error) {
+	endpoint := strings.TrimRight(c.address, "/") + "/v1/health/service/" + url.PathEscape(c.service) + "?passing=true"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("consul request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul error: status %d", resp.StatusCode)
+	}
+
+	var entries []struct {
+		Node struct {
+			Address string `json:"Address"`
+		} `json:"Node"`
+		Service struct {
+			Address string `json:"Address"`
+			Port    int    `json:"Port"`
+		} `json:"Service"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode consul response: %w", err)
+	}
+
+	urls := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		host := entry.Service.Address
+		if host == "" {
+			host = entry.Node.Address
+		}
+		urls = append(urls, c.scheme+"://"+net.JoinHostPort(host, strconv.Itoa(entry.Service.Port)))
+	}
+	return urls, nil
+}
+
+// backend is one API instance and its recent health
+type backend struct {
+	url      string
+	healthy  bool
+	failures int
+}
+
+// balancer hands out API instances round robin, skipping ones that keep
+// failing until a health probe brings them back
+type balancer struct {
+	mu        sync.Mutex
+	backends  []*backend
+	next      int
+	threshold int
+	logger    *zap.Logger
+}
+
+func newBalancer(threshold int, logger *zap.Logger) *balancer {
+	return &balancer{threshold: threshold, logger: logger}
+}
+
+// update replaces the instance list, keeping the health of known instances.
+// An empty list is ignored so a discovery hiccup does not drop every backend.
+func (b *balancer) update(urls []string) {
+	if len(urls) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	known := make(map[string]*backend, len(b.backends))
+	for _, be := range b.backends {
+		known[be.url] = be
+	}
+	backends := make([]*backend, 0, len(urls))
+	for _, u := range urls {
+		be, ok := known[u]
+		if !ok {
+			be = &backend{url: u, healthy: true}
+		}
+		backends = append(backends, be)
+	}
+	b.backends = backends
+	b.next %= len(backends)
+}
+
+// pick returns the next healthy instance not yet tried. When every instance
+// is out of rotation it falls back to the untried ones, since a stale health
+// mark is better than refusing the request outright.
+func (b *balancer) pick(tried map[string]bool) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := len(b.backends)
+	for _, wantHealthy := range []bool{true, false} {
+		for i := 0; i < n; i++ {
+			be := b.backends[(b.next+i)%n]
+			if tried[be.url] || (wantHealthy && !be.healthy) {
+				continue
+			}
+			b.next = (b.next + i + 1) % n
+			return be.url, true
+		}
+	}
+	return "", false
+}
+
+// report records the outcome of a call. An instance leaves rotation after
+// threshold consecutive failures.
+func (b *balancer) report(u string, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, be := range b.backends {
+		if be.url != u {
+			continue
+		}
+		if ok {
+			be.failures = 0
+			return
+		}
+		be.failures++
+		if be.healthy && be.failures >= b.threshold {
+			be.healthy = false
+			b.logger.Warn("API backend taken out of rotation",
+				zap.String("url", u),
+				zap.Int("failures", be.failures),
+			)
+		}
+		return
+	}
+}
+
+// probe checks every instance and puts recovered ones back in rotation
+func (b *balancer) probe(ctx context.Context, check func(ctx context.Context, u string) bool) {
+	for _, u := range b.urls() {
+		ok := check(ctx, u)
+
+		b.mu.Lock()
+		for _, be := range b.backends {
+			if be.url != u {
+				continue
+			}
+			if ok && !be.healthy {
+				b.logger.Info("API backend back in rotation", zap.String("url", u))
+			}
+			if ok {
+				be.healthy, be.failures = true, 0
+			} else if be.healthy {
+				be.healthy = false
+				b.logger.Warn("API backend failed health probe", zap.String("url", u))
+			}
+		}
+		b.mu.Unlock()
+	}
+}
+
+func (b *balancer) urls() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	urls := make([]string, len(b.backends))
+	for i, be := range b.backends {
+		urls[i] = be.url
+	}
+	return urls
+}
+
+func (b *balancer) statuses() []BackendStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statuses := make([]BackendStatus, len(b.backends))
+	for i, be := range b.backends {
+		statuses[i] = BackendStatus{URL: be.url, Healthy: be.healthy, Failures: be.failures}
+	}
+	return statuses
+}
+
+// repeatable reports whether a call may be sent to another instance after
+// the first one failed mid-request
+func repeatable(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut:
+		return true
+	default:
+		return false
+	}
+}
+
+// neverSent reports whether the request failed before reaching the instance,
+// which makes any call safe to send elsewhere
+func neverSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// unavailable reports statuses that mean the instance, not the call, failed
+func unavailable(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
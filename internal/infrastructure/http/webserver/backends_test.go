@@ -0,0 +1,125 @@
+package webserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// countingServer answers with status and counts the requests it saw
+func countingServer(t *testing.T, status int) (*httptest.Server, *int32) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(status)
+		w.Write([]byte(`{"success":true}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func newTestAPIClient(t *testing.T, urls ...string) *APIClient {
+	t.Setenv("API_URL", "")
+	return NewAPIClient(&config.Config{Web: config.WebConfig{API: config.WebAPIConfig{
+		Discovery:        "static",
+		URLs:             urls,
+		FailureThreshold: 2,
+		MaxAttempts:      2,
+		Timeout:          time.Second,
+	}}}, zap.NewNop())
+}
+
+func TestAPIClientRoundRobins(t *testing.T) {
+	a, aHits := countingServer(t, http.StatusOK)
+	b, bHits := countingServer(t, http.StatusOK)
+	client := newTestAPIClient(t, a.URL, b.URL)
+
+	var out map[string]interface{}
+	for i := 0; i < 4; i++ {
+		require.NoError(t, client.getWithAuth(context.Background(), "/api/v1/recipes", "tok", &out))
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(aHits))
+	assert.Equal(t, int32(2), atomic.LoadInt32(bHits))
+}
+
+func TestAPIClientFailsOverIdempotentCalls(t *testing.T) {
+	down, downHits := countingServer(t, http.StatusServiceUnavailable)
+	up, upHits := countingServer(t, http.StatusOK)
+	client := newTestAPIClient(t, down.URL, up.URL)
+
+	var out map[string]interface{}
+	for i := 0; i < 4; i++ {
+		require.NoError(t, client.getWithAuth(context.Background(), "/api/v1/recipes", "tok", &out))
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(upHits))
+	assert.Equal(t, int32(2), atomic.LoadInt32(downHits), "the failing instance leaves rotation after the threshold")
+
+	statuses := client.Backends()
+	assert.False(t, statuses[0].Healthy)
+	assert.True(t, statuses[1].Healthy)
+
+	// A probe brings the instance back once it recovers
+	client.balancer.probe(context.Background(), func(ctx context.Context, u string) bool { return true })
+	assert.True(t, client.Backends()[0].Healthy)
+}
+
+func TestAPIClientRetriesWritesOnlyWhenNeverSent(t *testing.T) {
+	down, downHits := countingServer(t, http.StatusServiceUnavailable)
+	up, upHits := countingServer(t, http.StatusOK)
+
+	var out map[string]interface{}
+	client := newTestAPIClient(t, down.URL, up.URL)
+	err := client.postWithAuth(context.Background(), "/api/v1/recipes", "tok", struct{}{}, &out)
+	require.Error(t, err, "a POST the instance may have acted on is not repeated")
+	assert.Equal(t, int32(1), atomic.LoadInt32(downHits))
+	assert.Equal(t, int32(0), atomic.LoadInt32(upHits))
+
+	// Nothing listens on a closed port, so the POST never left
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closed := "http://" + listener.Addr().String()
+	listener.Close()
+
+	client = newTestAPIClient(t, closed, up.URL)
+	require.NoError(t, client.postWithAuth(context.Background(), "/api/v1/recipes", "tok", struct{}{}, &out))
+	assert.Equal(t, int32(1), atomic.LoadInt32(upHits))
+}
+
+func TestConsulResolver(t *testing.T) {
+	consul := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/health/service/alchemorsel-api", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("passing"))
+		w.Write([]byte(`[
+			{"Node": {"Address": "10.0.0.1"}, "Service": {"Address": "", "Port": 3000}},
+			{"Node": {"Address": "10.0.0.2"}, "Service": {"Address": "10.1.0.2", "Port": 3001}}
+		]`))
+	}))
+	defer consul.Close()
+
+	r := &consulResolver{address: consul.URL, service: "alchemorsel-api", scheme: "http", client: consul.Client()}
+	urls, err := r.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://10.0.0.1:3000", "http://10.1.0.2:3001"}, urls)
+}
+
+func TestSRVResolverKeepsPreferredPriority(t *testing.T) {
+	r := &srvResolver{name: "_http._tcp.api", scheme: "https", lookup: func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		return "", []*net.SRV{
+			{Target: "api-1.internal.", Port: 3000, Priority: 10},
+			{Target: "api-2.internal.", Port: 3000, Priority: 10},
+			{Target: "api-backup.internal.", Port: 3000, Priority: 20},
+		}, nil
+	}}
+	urls, err := r.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://api-1.internal:3000", "https://api-2.internal:3000"}, urls)
+}
@@ -0,0 +1,52 @@
+// Package webserver provides the remix battle page and its blind voting
+package webserver
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// handleBattle serves /battles/{id}: both entries side by side, with vote
+// buttons for signed-in readers while voting is open
+func (s *WebServer) handleBattle(w http.ResponseWriter, r *http.Request) {
+	battle, err := s.apiClient.GetBattle(r.Context(), sessionToken(r), chi.URLParam(r, "id"))
+	if err != nil {
+		s.techniqueUnavailable(w, r, "Battle unavailable", err)
+		return
+	}
+
+	view := s.battleView(r, *battle)
+	s.renderGraph(w, r, "Remix battle - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderBattle(buf, view)
+	})
+}
+
+// handleHTMXBattleVote votes for entry a or b and swaps in the battle
+// showing the reader's vote
+func (s *WebServer) handleHTMXBattleVote(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	battleID := chi.URLParam(r, "id")
+
+	battle, err := s.apiClient.VoteBattle(r.Context(), session.AccessToken, battleID, r.FormValue("entry"))
+	if err != nil {
+		s.logger.Warn("Battle vote failed", zap.String("battle_id", battleID), zap.Error(err))
+		w.Write([]byte("<div class=\"error\">We couldn't record your vote. You may have voted already.</div>"))
+		return
+	}
+
+	view := s.battleView(r, *battle)
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		return s.fragments.RenderBattle(buf, view)
+	})
+}
+
+func (s *WebServer) battleView(r *http.Request, battle Battle) BattleView {
+	session, _ := r.Context().Value("session").(*Session)
+	if session == nil || session.UserID == "" {
+		return NewBattleView(battle, "", "")
+	}
+	return NewBattleView(battle, session.UserID, s.generateCSRFToken(session.ID))
+}
@@ -0,0 +1,141 @@
+// Package webserver provides the comment thread on recipe pages and the
+// admin queue of flagged comments
+package webserver
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// flaggedPageSize is how many flagged comments the admin queue shows
+const flaggedPageSize = 50
+
+// handleHTMXComments swaps in a recipe's comment thread
+func (s *WebServer) handleHTMXComments(w http.ResponseWriter, r *http.Request) {
+	s.renderComments(w, r, chi.URLParam(r, "id"), false)
+}
+
+// handleHTMXAddComment posts a comment or, with parent_id, a reply
+func (s *WebServer) handleHTMXAddComment(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+
+	err := s.apiClient.AddComment(r.Context(), session.AccessToken, recipeID, r.FormValue("parent_id"), strings.TrimSpace(r.FormValue("content")))
+	if err != nil {
+		s.commentFailed(w, recipeID, "post", err)
+		return
+	}
+	s.renderComments(w, r, recipeID, false)
+}
+
+// handleHTMXEditComment saves an edited comment
+func (s *WebServer) handleHTMXEditComment(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+
+	err := s.apiClient.EditComment(r.Context(), session.AccessToken, recipeID, chi.URLParam(r, "commentID"), strings.TrimSpace(r.FormValue("content")))
+	if err != nil {
+		s.commentFailed(w, recipeID, "edit", err)
+		return
+	}
+	s.renderComments(w, r, recipeID, false)
+}
+
+// handleHTMXDeleteComment deletes the user's comment
+func (s *WebServer) handleHTMXDeleteComment(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+
+	if err := s.apiClient.DeleteComment(r.Context(), session.AccessToken, recipeID, chi.URLParam(r, "commentID")); err != nil {
+		s.commentFailed(w, recipeID, "delete", err)
+		return
+	}
+	s.renderComments(w, r, recipeID, false)
+}
+
+// handleHTMXFlagComment flags a comment and thanks the reader
+func (s *WebServer) handleHTMXFlagComment(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+
+	if err := s.apiClient.FlagComment(r.Context(), session.AccessToken, recipeID, chi.URLParam(r, "commentID"), r.FormValue("reason")); err != nil {
+		s.commentFailed(w, recipeID, "flag", err)
+		return
+	}
+	s.renderComments(w, r, recipeID, true)
+}
+
+func (s *WebServer) renderComments(w http.ResponseWriter, r *http.Request, recipeID string, flagged bool) {
+	session := r.Context().Value("session").(*Session)
+
+	comments, err := s.apiClient.GetComments(r.Context(), session.AccessToken, recipeID)
+	if err != nil {
+		s.logger.Error("Comments unavailable", zap.String("recipe_id", recipeID), zap.Error(err))
+		w.Write([]byte("<div class=\"error\">Comments are unavailable right now. Please try again.</div>"))
+		return
+	}
+
+	var csrfToken string
+	if session.UserID != "" {
+		csrfToken = s.generateCSRFToken(session.ID)
+	}
+	view := NewCommentThreadView(recipeID, comments, session.UserID, csrfToken)
+	view.Flagged = flagged
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		return s.fragments.RenderCommentThread(buf, view)
+	})
+}
+
+// commentFailed leaves the thread as it is and explains why in a toast
+func (s *WebServer) commentFailed(w http.ResponseWriter, recipeID, action string, err error) {
+	s.logger.Warn("Comment action failed", zap.String("recipe_id", recipeID), zap.String("action", action), zap.Error(err))
+	switch {
+	case isAPIStatus(err, http.StatusBadRequest):
+		s.writeToastOnly(w, "Comments must be between 1 and 2000 characters.")
+	case isAPIStatus(err, http.StatusForbidden):
+		s.writeToastOnly(w, "You can't do that here. Comments may be locked.")
+	case isAPIStatus(err, http.StatusConflict), isAPIStatus(err, http.StatusNotFound):
+		s.writeToastOnly(w, "That comment has changed. Reload the page to see the latest.")
+	default:
+		s.writeToastOnly(w, "We couldn't "+action+" that comment. Please try again.")
+	}
+}
+
+// handleAdminComments serves /admin/comments, the flag queue
+func (s *WebServer) handleAdminComments(w http.ResponseWriter, r *http.Request) {
+	s.renderFlaggedComments(w, r)
+}
+
+// handleHTMXReviewFlaggedComment hides a flagged comment, with the reason
+// from the hx-prompt answer, or dismisses its flags
+func (s *WebServer) handleHTMXReviewFlaggedComment(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	commentID := chi.URLParam(r, "id")
+	hide := r.FormValue("hide") == "true"
+
+	if err := s.apiClient.ReviewFlaggedComment(r.Context(), session.AccessToken, commentID, hide, r.Header.Get("HX-Prompt")); err != nil {
+		s.logger.Warn("Flag review failed", zap.String("comment_id", commentID), zap.Bool("hide", hide), zap.Error(err))
+		s.writeToastOnly(w, "We couldn't update that comment. It may already be hidden.")
+		return
+	}
+	s.renderFlaggedComments(w, r)
+}
+
+func (s *WebServer) renderFlaggedComments(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	flagged, err := s.apiClient.ListFlaggedComments(r.Context(), session.AccessToken, flaggedPageSize)
+	if err != nil {
+		s.adminUnavailable(w, r, "Flagged comments unavailable", err)
+		return
+	}
+
+	view := NewFlaggedCommentsView(flagged, s.generateCSRFToken(session.ID))
+	s.renderGraph(w, r, "Flagged comments - Admin - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderFlaggedComments(buf, view)
+	})
+}
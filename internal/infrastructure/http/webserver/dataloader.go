@@ -0,0 +1,160 @@
+// Package webserver provides request coalescing for backend batch reads
+package webserver
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// batchFetchFunc resolves a set of IDs for one access token in a single call
+type batchFetchFunc[T any] func(ctx context.Context, token string, ids []string) (map[string]T, error)
+
+// batchLoader coalesces individual lookups issued within a short window into
+// one batchGet round trip. Lookups are grouped per access token so that
+// results are never shared across users with different visibility.
+type batchLoader[T any] struct {
+	fetch    batchFetchFunc[T]
+	wait     time.Duration
+	maxBatch int
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingBatch[T]
+}
+
+// pendingBatch collects the IDs waiting for the next dispatch
+type pendingBatch[T any] struct {
+	ids     []string
+	seen    map[string]bool
+	done    chan struct{}
+	results map[string]T
+	err     error
+}
+
+// newBatchLoader creates a loader that waits up to wait for more IDs before
+// dispatching, or dispatches early once maxBatch IDs are queued
+func newBatchLoader[T any](fetch batchFetchFunc[T], wait time.Duration, maxBatch int) *batchLoader[T] {
+	return &batchLoader[T]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		timeout:  10 * time.Second,
+		pending:  make(map[string]*pendingBatch[T]),
+	}
+}
+
+// Load resolves a single ID, returning false when the backend did not find it
+func (l *batchLoader[T]) Load(ctx context.Context, token, id string) (T, bool, error) {
+	var zero T
+
+	results, err := l.LoadMany(ctx, token, []string{id})
+	if err != nil {
+		return zero, false, err
+	}
+
+	value, ok := results[id]
+	return value, ok, nil
+}
+
+// LoadMany resolves several IDs; IDs already queued by concurrent callers
+// are shared rather than fetched twice
+func (l *batchLoader[T]) LoadMany(ctx context.Context, token string, ids []string) (map[string]T, error) {
+	if len(ids) == 0 {
+		return map[string]T{}, nil
+	}
+
+	batches := l.enqueue(token, ids)
+
+	results := make(map[string]T, len(ids))
+	for _, batch := range batches {
+		select {
+		case <-batch.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if batch.err != nil {
+			return nil, batch.err
+		}
+		for _, id := range ids {
+			if value, ok := batch.results[id]; ok {
+				results[id] = value
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// enqueue adds IDs to the pending batch for token, splitting into new
+// batches when maxBatch is reached, and returns every batch touched
+func (l *batchLoader[T]) enqueue(token string, ids []string) []*pendingBatch[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var touched []*pendingBatch[T]
+	for _, id := range ids {
+		batch := l.pending[token]
+		if batch == nil {
+			batch = &pendingBatch[T]{
+				seen: make(map[string]bool),
+				done: make(chan struct{}),
+			}
+			l.pending[token] = batch
+			time.AfterFunc(l.wait, func() { l.dispatch(token, batch) })
+		}
+
+		if !batch.seen[id] {
+			batch.seen[id] = true
+			batch.ids = append(batch.ids, id)
+		}
+		if len(touched) == 0 || touched[len(touched)-1] != batch {
+			touched = append(touched, batch)
+		}
+
+		if len(batch.ids) >= l.maxBatch {
+			delete(l.pending, token)
+			go l.dispatch(token, batch)
+		}
+	}
+
+	return touched
+}
+
+// dispatch performs the backend call for a batch exactly once
+func (l *batchLoader[T]) dispatch(token string, batch *pendingBatch[T]) {
+	l.mu.Lock()
+	if l.pending[token] == batch {
+		delete(l.pending, token)
+	}
+	select {
+	case <-batch.done:
+		l.mu.Unlock()
+		return
+	default:
+	}
+	if batch.results != nil || batch.err != nil {
+		l.mu.Unlock()
+		return
+	}
+	// Mark as in flight so a concurrent timer/early dispatch is a no-op
+	batch.results = map[string]T{}
+	ids := batch.ids
+	l.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	results, err := l.fetch(ctx, token, ids)
+
+	l.mu.Lock()
+	batch.results = results
+	batch.err = err
+	if batch.results == nil {
+		batch.results = map[string]T{}
+	}
+	l.mu.Unlock()
+
+	close(batch.done)
+}
@@ -0,0 +1,67 @@
+package webserver
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchLoaderCoalescesConcurrentLoads(t *testing.T) {
+	var calls int32
+	fetch := func(ctx context.Context, token string, ids []string) (map[string]string, error) {
+		atomic.AddInt32(&calls, 1)
+		out := make(map[string]string, len(ids))
+		for _, id := range ids {
+			if id != "missing" {
+				out[id] = token + ":" + id
+			}
+		}
+		return out, nil
+	}
+
+	loader := newBatchLoader(fetch, 20*time.Millisecond, 100)
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"a", "b", "a", "missing"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			value, ok, err := loader.Load(context.Background(), "tok", id)
+			require.NoError(t, err)
+			if id == "missing" {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, "tok:"+id, value)
+		}(id)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestBatchLoaderSplitsAtMaxBatch(t *testing.T) {
+	var calls int32
+	fetch := func(ctx context.Context, token string, ids []string) (map[string]int, error) {
+		atomic.AddInt32(&calls, 1)
+		assert.LessOrEqual(t, len(ids), 2)
+		out := make(map[string]int, len(ids))
+		for i, id := range ids {
+			out[id] = i
+		}
+		return out, nil
+	}
+
+	loader := newBatchLoader(fetch, time.Millisecond, 2)
+
+	results, err := loader.LoadMany(context.Background(), "tok", []string{"a", "b", "c", "d", "e"})
+	require.NoError(t, err)
+	assert.Len(t, results, 5)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,44 @@
+// Package webserver provides the dietary settings on the profile page
+package webserver
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// handleHTMXDietaryPrefs saves the dietary settings form. Diets and
+// allergens are posted once per checked box; disliked ingredients as one
+// comma separated field.
+func (s *WebServer) handleHTMXDietaryPrefs(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	if err := r.ParseForm(); err != nil {
+		s.writeToastOnly(w, "We couldn't read those settings. Please try again.")
+		return
+	}
+
+	profile := DietaryProfile{
+		DietaryRestrictions: append([]string{}, r.PostForm["diet"]...),
+		Allergies:           append([]string{}, r.PostForm["allergy"]...),
+		DislikedIngredients: []string{},
+	}
+	for _, ingredient := range strings.Split(r.PostForm.Get("disliked"), ",") {
+		if ingredient = strings.TrimSpace(ingredient); ingredient != "" {
+			profile.DislikedIngredients = append(profile.DislikedIngredients, ingredient)
+		}
+	}
+
+	saved, err := s.apiClient.UpdateDietaryProfile(r.Context(), session.AccessToken, profile)
+	if err != nil {
+		s.logger.Warn("Updating dietary profile failed", zap.Error(err))
+		s.writeToastOnly(w, "We couldn't save your diet and allergies. Keep to 30 short disliked ingredients and try again.")
+		return
+	}
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		view := NewDietaryPrefsView(*saved, s.generateCSRFToken(session.ID))
+		view.Saved = true
+		return s.fragments.RenderDietaryPrefs(buf, view)
+	})
+}
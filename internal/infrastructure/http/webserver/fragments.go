@@ -0,0 +1,2943 @@
+// Package webserver provides the HTMX fragment contract registry
+package webserver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/allergens"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+	"github.com/alchemorsel/v3/internal/domain/user"
+)
+
+// Fragment names. Each name is a contract: handlers that swap one of these
+// regions must render it through the matching Render* function below.
+const (
+	FragmentRecipeCard  = "recipe-card"
+	FragmentLikeButton  = "like-button"
+	FragmentChatMessage = "chat-message"
+	FragmentDashStats   = "dashboard-stats"
+	FragmentNotifyBadge = "notification-badge"
+	FragmentThemeToggle = "theme-toggle"
+	FragmentWizardStep  = "wizard-step"
+	FragmentUndoToast   = "undo-toast"
+	FragmentSearchMiss  = "search-fallback"
+	FragmentFacets      = "search-facets"
+	FragmentIngredients = "ingredient-preview"
+	FragmentRecipeStats = "recipe-stats"
+	FragmentRelated     = "recipe-related"
+	FragmentSimilar     = "recipe-similar"
+	FragmentGraphList   = "graph-recipes"
+	FragmentTechnique   = "technique"
+	FragmentCookSteps   = "cook-steps"
+	FragmentTimeline    = "recipe-timeline"
+	FragmentBattle      = "remix-battle"
+	FragmentHomeSection = "home-section"
+	FragmentRecipeEdit  = "recipe-edit"
+	FragmentAllergens   = "recipe-allergens"
+	FragmentNutrition   = "recipe-nutrition"
+	FragmentRecipeItems = "recipe-ingredients"
+	FragmentSubstitutes = "ingredient-substitutes"
+	FragmentAdminStats  = "admin-stats"
+	FragmentAdminUsers  = "admin-users"
+	FragmentAIContent   = "admin-ai-content"
+	FragmentSuggestion  = "guest-suggestion"
+	FragmentNotifyList  = "notification-list"
+	FragmentNotifyPrefs = "notification-prefs"
+	FragmentDietPrefs   = "dietary-prefs"
+	FragmentURLImport   = "url-import-preview"
+	FragmentComments    = "comment-thread"
+	FragmentFlagged     = "admin-flagged-comments"
+	FragmentReports     = "admin-reports"
+	FragmentRecipePage  = "recipe-page"
+	FragmentSparkline   = "sparkline"
+	FragmentAuthorStats = "author-analytics"
+	FragmentDeletion    = "account-deletion"
+)
+
+// RecipeCardView is the view model for the recipe-card fragment
+type RecipeCardView struct {
+	ID           string
+	Title        string
+	Description  string
+	AuthorName   string
+	AuthorBadge  *AuthorBadge
+	Emoji        string
+	Rating       float64
+	TotalMinutes int
+	// Reason says why a recommended recipe was picked
+	Reason string
+	// AllergyWarnings are the reader's allergies the recipe contains or
+	// may contain
+	AllergyWarnings []string
+	Allergens       *AllergenLabel
+}
+
+// Stars renders the rating as a five star string
+func (v RecipeCardView) Stars() string {
+	full := int(v.Rating + 0.5)
+	if full < 0 {
+		full = 0
+	}
+	if full > 5 {
+		full = 5
+	}
+	return strings.Repeat("★", full) + strings.Repeat("☆", 5-full)
+}
+
+// NewRecipeCardView builds a card view model from an API recipe
+func NewRecipeCardView(recipe RecipeResponse) RecipeCardView {
+	return RecipeCardView{
+		ID:              recipe.ID,
+		Title:           recipe.Title,
+		Description:     recipe.Description,
+		AuthorName:      recipe.AuthorName,
+		AuthorBadge:     recipe.AuthorBadge,
+		Rating:          recipe.Rating,
+		TotalMinutes:    recipe.PrepTime + recipe.CookTime,
+		AllergyWarnings: recipe.AllergyWarnings,
+		Allergens:       recipe.Allergens,
+	}
+}
+
+// Allergies lists the allergy warnings for the card badge, with the
+// allergen names readable: "tree nuts, milk"
+func (v RecipeCardView) Allergies() string {
+	return strings.ReplaceAll(strings.Join(v.AllergyWarnings, ", "), "-", " ")
+}
+
+// ContainsLabels are the allergens the recipe contains, as declared on a
+// label: "Milk", "Nuts"
+func (a *AllergenLabel) ContainsLabels() []string {
+	return allergenLabels(a.Contains)
+}
+
+// MayContainLabels are the allergens the recipe may contain
+func (a *AllergenLabel) MayContainLabels() []string {
+	return allergenLabels(a.MayContain)
+}
+
+func allergenLabels(names []string) []string {
+	labels := make([]string, 0, len(names))
+	for _, name := range names {
+		labels = append(labels, allergens.LabelOf(name))
+	}
+	return labels
+}
+
+// LikeButtonView is the view model for the like-button fragment
+type LikeButtonView struct {
+	RecipeID string
+	Liked    bool
+	Count    int
+}
+
+// Label is the accessible name for the toggle
+func (v LikeButtonView) Label() string {
+	if v.Liked {
+		return "Unlike this recipe"
+	}
+	return "Like this recipe"
+}
+
+// ChatMessageView is the view model for the chat-message fragment.
+// Text is split into lines so the template can escape each line and
+// join them with <br> without trusting any markup in the message.
+type ChatMessageView struct {
+	FromUser  bool
+	Author    string
+	Lines     []string
+	Timestamp string
+}
+
+// NewChatMessageView builds a chat message view from raw text
+func NewChatMessageView(fromUser bool, author, text, timestamp string) ChatMessageView {
+	return ChatMessageView{
+		FromUser:  fromUser,
+		Author:    author,
+		Lines:     strings.Split(text, "\n"),
+		Timestamp: timestamp,
+	}
+}
+
+// GuestSuggestionView is the view model for the guest-suggestion fragment:
+// the recipe the AI chef suggested to a guest, with a button to keep it
+type GuestSuggestionView struct {
+	Title        string
+	Ingredients  []string
+	MessagesLeft int
+	CSRFToken    string
+}
+
+// SaveLabel names the save button for screen readers
+func (v GuestSuggestionView) SaveLabel() string {
+	return "Save " + v.Title + " to your guest recipes"
+}
+
+// MessagesLeftText tells the guest how many questions remain
+func (v GuestSuggestionView) MessagesLeftText() string {
+	switch v.MessagesLeft {
+	case 0:
+		return "That was your last guest message; sign up to keep chatting."
+	case 1:
+		return "1 guest message left"
+	}
+	return fmt.Sprintf("%d guest messages left", v.MessagesLeft)
+}
+
+// DashboardStatsView is the view model for the dashboard-stats fragment
+type DashboardStatsView struct {
+	Recipes   int
+	Liked     int
+	Favorites int
+}
+
+// NotificationBadgeView is the view model for the notification-badge fragment
+type NotificationBadgeView struct {
+	Unread int
+}
+
+// Label caps the visible count so the badge keeps a fixed width
+func (v NotificationBadgeView) Label() string {
+	if v.Unread > 99 {
+		return "99+"
+	}
+	return fmt.Sprintf("%d", v.Unread)
+}
+
+// AriaLabel is the accessible name, spelling out the uncapped count
+func (v NotificationBadgeView) AriaLabel() string {
+	if v.Unread == 0 {
+		return "No unread notifications"
+	}
+	return fmt.Sprintf("%d unread notifications", v.Unread)
+}
+
+// NotificationListView is the view model for the notification-list
+// fragment: the newest notifications and a button that marks them read
+type NotificationListView struct {
+	Items     []NotificationItem
+	Unread    int
+	CSRFToken string
+}
+
+// NotificationItem is one notification in the list
+type NotificationItem struct {
+	Title   string
+	Message string
+	Link    string
+	Unread  bool
+	When    string
+}
+
+// NewNotificationListView builds the view from one API page
+func NewNotificationListView(p NotificationPage, csrfToken string) NotificationListView {
+	view := NotificationListView{Unread: p.Unread, CSRFToken: csrfToken}
+	for _, n := range p.Notifications {
+		view.Items = append(view.Items, NotificationItem{
+			Title:   n.Title,
+			Message: n.Message,
+			Link:    n.Link,
+			Unread:  !n.Read,
+			When:    n.CreatedAt.UTC().Format("Jan 2, 15:04"),
+		})
+	}
+	return view
+}
+
+// NotificationPrefsView is the view model for the notification-prefs
+// fragment: a checkbox per notification type on the profile page
+type NotificationPrefsView struct {
+	Preferences []NotificationPreference
+	// Saved confirms the form after an update
+	Saved     bool
+	CSRFToken string
+}
+
+// DietaryPrefsView is the view model for the dietary-prefs fragment: the
+// diets, allergens and disliked ingredients on the profile page
+type DietaryPrefsView struct {
+	Diets     []DietOption
+	Allergens []DietOption
+	// Disliked is the disliked ingredients as one comma separated field
+	Disliked string
+	// Saved confirms the form after an update
+	Saved     bool
+	CSRFToken string
+}
+
+// AccountDeletionView is the view model for the account-deletion
+// fragment: the delete account form on the profile page, or the date a
+// requested deletion happens on with the button that cancels it
+type AccountDeletionView struct {
+	Pending      bool
+	ScheduledFor time.Time
+	// SignedOut replaces the cancel button with a note to sign in again,
+	// since asking for deletion ends every session
+	SignedOut bool
+	// Cancelled confirms the form after a cancellation
+	Cancelled bool
+	Error     string
+	CSRFToken string
+}
+
+// DietOption is one diet or allergen checkbox
+type DietOption struct {
+	Value   string
+	Label   string
+	Checked bool
+}
+
+// NewDietaryPrefsView offers every diet and major allergen, checking the
+// ones in the profile
+func NewDietaryPrefsView(profile DietaryProfile, csrfToken string) DietaryPrefsView {
+	view := DietaryPrefsView{Disliked: strings.Join(profile.DislikedIngredients, ", "), CSRFToken: csrfToken}
+	chosen := make(map[string]bool)
+	for _, value := range append(append([]string{}, profile.DietaryRestrictions...), profile.Allergies...) {
+		chosen[value] = true
+	}
+	for _, diet := range user.DietaryRestrictions {
+		view.Diets = append(view.Diets, DietOption{Value: string(diet), Label: facetLabel(string(diet)), Checked: chosen[string(diet)]})
+	}
+	for _, def := range allergens.Definitions {
+		view.Allergens = append(view.Allergens, DietOption{Value: string(def.Allergen), Label: def.Label, Checked: chosen[string(def.Allergen)]})
+	}
+	return view
+}
+
+// URLImportPreviewView is the view model for the url-import-preview
+// fragment: the draft a recipe page would become, with the button that
+// saves it. URL is the address as the cook entered it; saving reads the
+// page again.
+type URLImportPreviewView struct {
+	URL         string
+	SourceURL   string
+	Method      string
+	Title       string
+	Description string
+	Servings    int
+	PrepTime    int
+	CookTime    int
+	Ingredients []string
+	Steps       []string
+	Warnings    []string
+	DuplicateOf string
+	CSRFToken   string
+}
+
+// NewURLImportPreviewView writes the previewed ingredients back as the
+// lines the editor shows
+func NewURLImportPreviewView(pageURL string, preview URLImportPreview, csrfToken string) URLImportPreviewView {
+	values := recipeEditValues(&preview.Recipe)
+	return URLImportPreviewView{
+		URL:         pageURL,
+		SourceURL:   preview.SourceURL,
+		Method:      preview.Method,
+		Title:       preview.Recipe.Title,
+		Description: preview.Recipe.Description,
+		Servings:    preview.Recipe.Servings,
+		PrepTime:    preview.Recipe.PrepTime,
+		CookTime:    preview.Recipe.CookTime,
+		Ingredients: splitLines(values.Get("ingredients")),
+		Steps:       splitLines(values.Get("instructions")),
+		Warnings:    preview.Warnings,
+		DuplicateOf: preview.DuplicateOf,
+		CSRFToken:   csrfToken,
+	}
+}
+
+// Site is the host the recipe was read from: "example.com"
+func (v URLImportPreviewView) Site() string {
+	u, err := url.Parse(v.SourceURL)
+	if err != nil || u.Host == "" {
+		return v.SourceURL
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
+
+// MethodLabel says how the recipe was read, for the preview's byline
+func (v URLImportPreviewView) MethodLabel() string {
+	switch v.Method {
+	case "schema.org", "microdata":
+		return "the page's recipe data"
+	}
+	return "the page layout; check it closely"
+}
+
+// CommentThreadView is the view model for the comment-thread fragment: a
+// recipe's comments with replies one level deep and the form to post one
+type CommentThreadView struct {
+	RecipeID string
+	Comments []CommentItem
+	Count    int
+	// Flagged thanks the reader after a flag; CSRFToken is empty for
+	// signed-out readers, who get no forms
+	Flagged   bool
+	CSRFToken string
+}
+
+// Reasons lists the reasons offered when flagging
+func (v CommentThreadView) Reasons() []flagReason {
+	return flagReasons
+}
+
+// CommentItem is one comment. Mine offers edit and delete; others' visible
+// comments can be flagged, and top-level ones answered.
+type CommentItem struct {
+	ID         string
+	AuthorName string
+	Content    string
+	When       string
+	Edited     bool
+	// Notice replaces the content of comments that are not visible
+	Notice   string
+	Mine     bool
+	CanReply bool
+	CanFlag  bool
+	Replies  []CommentItem
+}
+
+type flagReason struct{ Value, Label string }
+
+// flagReasons are the reasons offered when flagging a comment, in order
+var flagReasons = []flagReason{
+	{"spam", "Spam"},
+	{"abuse", "Abusive"},
+	{"off_topic", "Off topic"},
+	{"other", "Something else"},
+}
+
+// NewCommentThreadView builds the view from the API threads as viewerID
+// sees them; an empty viewerID is a signed-out reader
+func NewCommentThreadView(recipeID string, comments []Comment, viewerID, csrfToken string) CommentThreadView {
+	view := CommentThreadView{RecipeID: recipeID, CSRFToken: csrfToken}
+	for _, c := range comments {
+		item := newCommentItem(c, viewerID)
+		item.CanReply = viewerID != "" && c.Status == "visible"
+		for _, reply := range c.Replies {
+			item.Replies = append(item.Replies, newCommentItem(reply, viewerID))
+		}
+		view.Count += 1 + len(item.Replies)
+		view.Comments = append(view.Comments, item)
+	}
+	return view
+}
+
+func newCommentItem(c Comment, viewerID string) CommentItem {
+	item := CommentItem{
+		ID:         c.ID,
+		AuthorName: c.AuthorName,
+		Content:    c.Content,
+		When:       c.CreatedAt.UTC().Format("Jan 2, 15:04"),
+		Edited:     c.EditedAt != nil,
+	}
+	if item.AuthorName == "" {
+		item.AuthorName = "Someone"
+	}
+	switch c.Status {
+	case "visible", "":
+		item.Mine = viewerID != "" && c.AuthorID == viewerID
+		item.CanFlag = viewerID != "" && !item.Mine
+	case "deleted":
+		item.Notice = "This comment was deleted."
+	default:
+		item.Notice = "This comment was hidden by a moderator."
+	}
+	return item
+}
+
+// FlaggedCommentsView is the view model for the admin-flagged-comments
+// fragment: the comments readers flagged, with hide and dismiss buttons
+type FlaggedCommentsView struct {
+	Items     []FlaggedCommentItem
+	CSRFToken string
+}
+
+// FlaggedCommentItem is one comment in the flag queue
+type FlaggedCommentItem struct {
+	ID         string
+	AuthorName string
+	Content    string
+	Flags      int
+	Reasons    string
+	Since      string
+}
+
+// NewFlaggedCommentsView builds the view from the API queue
+func NewFlaggedCommentsView(flagged []FlaggedComment, csrfToken string) FlaggedCommentsView {
+	view := FlaggedCommentsView{CSRFToken: csrfToken}
+	for _, f := range flagged {
+		var reasons []string
+		for _, r := range flagReasons {
+			if n := f.Reasons[r.Value]; n > 0 {
+				reasons = append(reasons, fmt.Sprintf("%s %d", r.Label, n))
+			}
+		}
+		view.Items = append(view.Items, FlaggedCommentItem{
+			ID:         f.Comment.ID,
+			AuthorName: f.Comment.AuthorName,
+			Content:    f.Comment.Content,
+			Flags:      f.Flags,
+			Reasons:    strings.Join(reasons, ", "),
+			Since:      f.FirstFlaggedAt.UTC().Format("Jan 2, 15:04"),
+		})
+	}
+	return view
+}
+
+// ReportsView is the view model for the admin-reports fragment: open
+// reports of recipes and comments, with resolve and dismiss buttons
+type ReportsView struct {
+	Items     []ReportItem
+	Total     int64
+	CSRFToken string
+}
+
+// ReportItem is one open report; Kind is recipe or comment. Unpublished
+// content was taken down by the report threshold and waits for the
+// verdict.
+type ReportItem struct {
+	ID          string
+	Kind        string
+	Summary     string
+	Link        string
+	Reason      string
+	Note        string
+	OpenReports int
+	Unpublished bool
+	Since       string
+}
+
+// reportReasons label the reasons readers give for a report
+var reportReasons = map[string]string{
+	"spam":          "Spam",
+	"abuse":         "Abusive",
+	"inappropriate": "Inappropriate",
+	"copyright":     "Copyright",
+	"other":         "Something else",
+}
+
+// NewReportsView builds the view from a page of the API queue
+func NewReportsView(page *ReportPage, csrfToken string) ReportsView {
+	view := ReportsView{CSRFToken: csrfToken}
+	if page == nil {
+		return view
+	}
+	view.Total = page.Total
+	for _, r := range page.Reports {
+		item := ReportItem{
+			ID:          r.ID,
+			Kind:        r.TargetType,
+			Summary:     r.Summary,
+			Link:        "/recipes/" + r.RecipeID,
+			Reason:      reportReasons[r.Reason],
+			Note:        r.Note,
+			OpenReports: r.OpenReports,
+			Unpublished: r.Unpublished,
+			Since:       r.CreatedAt.UTC().Format("Jan 2, 15:04"),
+		}
+		if r.TargetType == "comment" {
+			item.Link += "#recipe-comments"
+		}
+		if item.Reason == "" {
+			item.Reason = r.Reason
+		}
+		view.Items = append(view.Items, item)
+	}
+	return view
+}
+
+// ThemeToggleView is the view model for the theme-toggle fragment
+type ThemeToggleView struct {
+	Theme string
+}
+
+// Next returns the theme the toggle switches to: system → dark → light → system
+func (v ThemeToggleView) Next() string {
+	switch v.Theme {
+	case ThemeDark:
+		return ThemeLight
+	case ThemeLight:
+		return ThemeSystem
+	default:
+		return ThemeDark
+	}
+}
+
+// Icon shows the current theme
+func (v ThemeToggleView) Icon() string {
+	switch v.Theme {
+	case ThemeDark:
+		return "🌙"
+	case ThemeLight:
+		return "☀️"
+	default:
+		return "🌓"
+	}
+}
+
+// Label describes what activating the toggle does
+func (v ThemeToggleView) Label() string {
+	return "Switch to " + v.Next() + " theme"
+}
+
+// UndoToastView is the view model for the undo-toast fragment. Without a
+// Token it renders a plain confirmation toast.
+type UndoToastView struct {
+	Message   string
+	Token     string
+	Seconds   int
+	CSRFToken string
+}
+
+// UndoLabel names the undo button for screen readers
+func (v UndoToastView) UndoLabel() string {
+	return fmt.Sprintf("Undo: %s (%d seconds)", v.Message, v.Seconds)
+}
+
+// SearchFallbackView is the view model for the search-fallback fragment shown
+// when a search matches nothing
+type SearchFallbackView struct {
+	Query          string
+	Suggestions    []string
+	GeneratePrompt string
+	CSRFToken      string
+}
+
+// NewSearchFallbackView builds the view from the API fallback, which may be
+// nil when the API could not offer one
+func NewSearchFallbackView(query string, fallback *SearchFallback, csrfToken string) SearchFallbackView {
+	view := SearchFallbackView{Query: query, GeneratePrompt: query, CSRFToken: csrfToken}
+	if fallback != nil {
+		view.Suggestions = fallback.Suggestions
+		if fallback.GeneratePrompt != "" {
+			view.GeneratePrompt = fallback.GeneratePrompt
+		}
+	}
+	return view
+}
+
+// SuggestionLabel names a suggested-query button for screen readers
+func (v SearchFallbackView) SuggestionLabel(suggestion string) string {
+	return "Search for " + suggestion
+}
+
+// GenerateLabel names the generate button for screen readers
+func (v SearchFallbackView) GenerateLabel() string {
+	return fmt.Sprintf("Generate a new recipe for %s with AI", v.GeneratePrompt)
+}
+
+// SearchFacetsView is the view model for the search-facets fragment, the
+// filters above the search results. Changing one re-runs the search.
+type SearchFacetsView struct {
+	Query     string
+	CSRFToken string
+	Semantic  bool // searching by meaning
+	Groups    []FacetGroup
+}
+
+// FacetGroup is one filter and its values. Multiple groups are checkboxes;
+// the others are radio buttons with an Any choice.
+type FacetGroup struct {
+	Name     string // form field
+	Legend   string
+	Multiple bool
+	Options  []FacetOption
+}
+
+// AnyChecked reports whether no value of the group is chosen
+func (g FacetGroup) AnyChecked() bool {
+	for _, option := range g.Options {
+		if option.Checked {
+			return false
+		}
+	}
+	return true
+}
+
+// FacetOption is one filter value with how many results have it
+type FacetOption struct {
+	Value   string
+	Label   string
+	Count   int
+	Checked bool
+}
+
+// NewSearchFacetsView builds the filters from the API result counts.
+// Chosen values stay offered even when no result has them, so they can be
+// cleared.
+func NewSearchFacetsView(query string, facets *SearchFacets, filters SearchFilters, csrfToken string) SearchFacetsView {
+	view := SearchFacetsView{Query: query, CSRFToken: csrfToken, Semantic: filters.Semantic}
+	if facets == nil {
+		facets = &SearchFacets{}
+	}
+	maxTime := ""
+	if filters.MaxTime > 0 {
+		maxTime = strconv.Itoa(filters.MaxTime)
+	}
+	groups := []FacetGroup{
+		{Name: "cuisine", Legend: "Cuisine", Multiple: true, Options: facetOptions(facets.Cuisines, filters.Cuisines, facetLabel)},
+		{Name: "difficulty", Legend: "Difficulty", Multiple: true, Options: facetOptions(facets.Difficulties, filters.Difficulties, facetLabel)},
+		{Name: "max_time", Legend: "Total time", Options: facetOptions(facets.MaxTime, nonEmpty(maxTime), func(v string) string {
+			return v + " min or less"
+		})},
+		{Name: "diet", Legend: "Diet", Multiple: true, Options: facetOptions(facets.Diets, filters.Diets, facetLabel)},
+		{Name: "ai_generated", Legend: "Made by", Options: facetOptions(facets.AIGenerated, nonEmpty(filters.AIGenerated), func(v string) string {
+			if v == "true" {
+				return "AI"
+			}
+			return "Chefs"
+		})},
+	}
+	for _, group := range groups {
+		if len(group.Options) > 0 {
+			view.Groups = append(view.Groups, group)
+		}
+	}
+	return view
+}
+
+// facetOptions lists the counted values, then any chosen value no result
+// has
+func facetOptions(counts []FacetCount, chosen []string, label func(string) string) []FacetOption {
+	checked := make(map[string]bool, len(chosen))
+	for _, value := range chosen {
+		checked[value] = true
+	}
+	options := make([]FacetOption, 0, len(counts)+len(chosen))
+	for _, count := range counts {
+		options = append(options, FacetOption{Value: count.Value, Label: label(count.Value), Count: count.Count, Checked: checked[count.Value]})
+		delete(checked, count.Value)
+	}
+	for _, value := range chosen {
+		if checked[value] {
+			options = append(options, FacetOption{Value: value, Label: label(value), Checked: true})
+		}
+	}
+	return options
+}
+
+// facetLabel turns a filter value such as gluten_free into Gluten free
+func facetLabel(value string) string {
+	label := strings.ReplaceAll(value, "_", " ")
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// nonEmpty is value as a one-item list, or nil when it is empty
+func nonEmpty(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// IngredientPreviewView is the view model for the ingredient-preview fragment
+// shown under the wizard's ingredients textarea while pasting
+type IngredientPreviewView struct {
+	Rows    []IngredientPreviewRow
+	Skipped int
+}
+
+// IngredientPreviewRow is one pasted line as the parser understood it. Section
+// rows carry only the header text; Error rows could not be parsed.
+type IngredientPreviewRow struct {
+	Line     string
+	Amount   string
+	Unit     string
+	Name     string
+	Notes    string
+	Optional bool
+	Section  bool
+	Error    string
+}
+
+// NewIngredientPreviewView parses each non-blank line of pasted text
+func NewIngredientPreviewView(text string) IngredientPreviewView {
+	var view IngredientPreviewView
+	for _, line := range splitLines(text) {
+		row := IngredientPreviewRow{Line: line}
+		parsed, err := ingredients.Parse(line)
+		switch {
+		case errors.Is(err, ingredients.ErrSectionHeader):
+			row.Section = true
+		case err != nil:
+			row.Error = "Couldn't find an ingredient name"
+			view.Skipped++
+		default:
+			row.Amount = previewAmount(parsed)
+			row.Unit = string(parsed.Unit)
+			row.Name = parsed.Name
+			row.Notes = parsed.Notes
+			row.Optional = parsed.Optional
+		}
+		view.Rows = append(view.Rows, row)
+	}
+	return view
+}
+
+// previewAmount shows ranges as "2–3" and omits missing amounts
+func previewAmount(p ingredients.Parsed) string {
+	if p.Amount <= 0 {
+		return ""
+	}
+	if p.AmountMax > p.Amount {
+		return ingredients.FormatAmount(p.Amount) + "–" + ingredients.FormatAmount(p.AmountMax)
+	}
+	return ingredients.FormatAmount(p.Amount)
+}
+
+// statsWindows are the periods offered on the recipe stats page, in days
+var statsWindows = []int{7, 30, 90}
+
+// RecipeStatsView is the view model for the recipe-stats fragment shown to
+// an author on a recipe's stats page
+type RecipeStatsView struct {
+	RecipeID      string
+	Title         string
+	Days          int
+	Since         string
+	Until         string
+	Views         int
+	UniqueViewers int
+	Likes         int
+	Bookmarks     int
+	LikeRate      string
+	BookmarkRate  string
+	// ScrollDepth is empty until a visitor has reported one
+	ScrollDepth string
+	Bars        []StatsBar
+	SearchTerms []StatsShare
+	Referrers   []StatsShare
+	Windows     []int
+}
+
+// StatsBar is one day of the views chart. Height is relative to the
+// busiest day of the window.
+type StatsBar struct {
+	Date   string
+	Views  int
+	Height int
+}
+
+// StatsShare is a search term or referrer with its share of all views
+type StatsShare struct {
+	Key     string
+	Count   int
+	Percent int
+}
+
+// NewRecipeStatsView builds the view from the API stats
+func NewRecipeStatsView(a RecipeAnalytics, days int) RecipeStatsView {
+	view := RecipeStatsView{
+		RecipeID:      a.RecipeID,
+		Title:         a.Title,
+		Days:          days,
+		Since:         a.Since,
+		Until:         a.Until,
+		Views:         a.Views,
+		UniqueViewers: a.UniqueViewers,
+		Likes:         a.Likes,
+		Bookmarks:     a.Bookmarks,
+		LikeRate:      fmt.Sprintf("%.1f%%", a.LikeConversion*100),
+		BookmarkRate:  fmt.Sprintf("%.1f%%", a.BookmarkConversion*100),
+		SearchTerms:   statsShares(a.SearchTerms, a.Views),
+		Referrers:     statsShares(a.Referrers, a.Views),
+		Windows:       statsWindows,
+	}
+	if a.AverageScrollDepth != nil {
+		view.ScrollDepth = fmt.Sprintf("%.0f%%", *a.AverageScrollDepth)
+	}
+
+	busiest := 0
+	for _, day := range a.Daily {
+		if day.Views > busiest {
+			busiest = day.Views
+		}
+	}
+	for _, day := range a.Daily {
+		bar := StatsBar{Date: day.Date, Views: day.Views}
+		if busiest > 0 {
+			bar.Height = day.Views * 100 / busiest
+		}
+		view.Bars = append(view.Bars, bar)
+	}
+	return view
+}
+
+func statsShares(counts []AnalyticsCount, views int) []StatsShare {
+	shares := make([]StatsShare, len(counts))
+	for i, count := range counts {
+		shares[i] = StatsShare{Key: count.Key, Count: count.Count}
+		if views > 0 {
+			shares[i].Percent = count.Count * 100 / views
+		}
+	}
+	return shares
+}
+
+// CSVURL downloads the stats of the current window
+func (v RecipeStatsView) CSVURL() string {
+	return fmt.Sprintf("/recipes/%s/stats.csv?days=%d", v.RecipeID, v.Days)
+}
+
+// WindowLabel names a period link for screen readers
+func (v RecipeStatsView) WindowLabel(days int) string {
+	return fmt.Sprintf("Show stats for the last %d days", days)
+}
+
+// DownloadLabel names the CSV link for screen readers
+func (v RecipeStatsView) DownloadLabel() string {
+	return fmt.Sprintf("Download stats for %s as CSV", v.Title)
+}
+
+// BarLabel describes one day of the chart for screen readers
+func (v RecipeStatsView) BarLabel(bar StatsBar) string {
+	if bar.Views == 1 {
+		return bar.Date + ": 1 view"
+	}
+	return fmt.Sprintf("%s: %d views", bar.Date, bar.Views)
+}
+
+// Sparkline drawing area, in SVG user units
+const (
+	sparklineWidth  = 240
+	sparklineHeight = 48
+)
+
+// SparklineView is the view model for the sparkline fragment, a small line
+// chart of one figure per day
+type SparklineView struct {
+	Label  string
+	Total  string
+	Points string
+	// Last is where the line ends, marked with a dot
+	LastX, LastY string
+	Width        int
+	Height       int
+	Summary      string
+}
+
+// NewSparklineView scales one value per day into the chart. Nil values are
+// days without data and are left out of the line. The y axis runs from lo
+// to the largest value, or to hi when no value is larger.
+func NewSparklineView(label, total string, values []*float64, lo, hi float64) SparklineView {
+	view := SparklineView{Label: label, Total: total, Width: sparklineWidth, Height: sparklineHeight}
+	for _, v := range values {
+		if v != nil && *v > hi {
+			hi = *v
+		}
+	}
+	step := float64(sparklineWidth)
+	if len(values) > 1 {
+		step = float64(sparklineWidth) / float64(len(values)-1)
+	}
+	// A 2 unit margin keeps the stroke inside the box at either extreme
+	scale := float64(sparklineHeight-4) / (hi - lo)
+	var points []string
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		view.LastX = fmt.Sprintf("%.1f", float64(i)*step)
+		view.LastY = fmt.Sprintf("%.1f", float64(sparklineHeight-2)-(*v-lo)*scale)
+		points = append(points, view.LastX+","+view.LastY)
+	}
+	view.Points = strings.Join(points, " ")
+	view.Summary = fmt.Sprintf("%s: %s over %d days", label, total, len(values))
+	return view
+}
+
+// AuthorAnalyticsView is the view model for the author-analytics fragment,
+// the activity across an author's recipes
+type AuthorAnalyticsView struct {
+	Days    int
+	Since   string
+	Until   string
+	Windows []int
+	Charts  []SparklineView
+	Recipes []AuthorRecipeRow
+}
+
+// AuthorRecipeRow is one recipe in the author analytics table
+type AuthorRecipeRow struct {
+	ID            string
+	Title         string
+	Views         int
+	Likes         int
+	Impressions   int
+	Ratings       int
+	AverageRating string
+	Rating        string
+}
+
+// NewAuthorAnalyticsView builds the view from the API analytics, charting
+// views, likes, search impressions and the average of each day's ratings
+func NewAuthorAnalyticsView(a AuthorAnalytics, days int) AuthorAnalyticsView {
+	view := AuthorAnalyticsView{
+		Days:    days,
+		Since:   a.Since,
+		Until:   a.Until,
+		Windows: statsWindows,
+	}
+
+	views := make([]*float64, len(a.Daily))
+	likes := make([]*float64, len(a.Daily))
+	impressions := make([]*float64, len(a.Daily))
+	ratings := make([]*float64, len(a.Daily))
+	for i, day := range a.Daily {
+		views[i] = dayValue(day.Views)
+		likes[i] = dayValue(day.Likes)
+		impressions[i] = dayValue(day.Impressions)
+		ratings[i] = day.AverageRating
+	}
+	view.Charts = []SparklineView{
+		NewSparklineView("Views", fmt.Sprint(a.Views), views, 0, 1),
+		NewSparklineView("Likes", fmt.Sprint(a.Likes), likes, 0, 1),
+		NewSparklineView("Search impressions", fmt.Sprint(a.Impressions), impressions, 0, 1),
+		NewSparklineView("Average rating", ratingText(a.AverageRating), ratings, 1, 5),
+	}
+
+	for _, r := range a.Recipes {
+		view.Recipes = append(view.Recipes, AuthorRecipeRow{
+			ID:            r.RecipeID,
+			Title:         r.Title,
+			Views:         r.Views,
+			Likes:         r.Likes,
+			Impressions:   r.Impressions,
+			Ratings:       r.Ratings,
+			AverageRating: ratingText(r.AverageRating),
+			Rating:        fmt.Sprintf("%.1f", r.Rating),
+		})
+	}
+	return view
+}
+
+// dayValue charts a daily count
+func dayValue(n int) *float64 {
+	v := float64(n)
+	return &v
+}
+
+// ratingText shows an average rating, or a dash without ratings
+func ratingText(rating *float64) string {
+	if rating == nil {
+		return "–"
+	}
+	return fmt.Sprintf("%.1f", *rating)
+}
+
+// WindowLabel names a period link for screen readers
+func (v AuthorAnalyticsView) WindowLabel(days int) string {
+	return fmt.Sprintf("Show analytics for the last %d days", days)
+}
+
+// GraphCardView is a recipe reached through the recipe graph, with the
+// labels of the nodes it shares when it is a related recipe
+type GraphCardView struct {
+	ID     string
+	Title  string
+	Rating float64
+	Likes  int
+	Shared string
+}
+
+// Stars renders the rating as a five star string
+func (v GraphCardView) Stars() string {
+	return RecipeCardView{Rating: v.Rating}.Stars()
+}
+
+func newGraphCards(recipes []GraphRecipe) []GraphCardView {
+	cards := make([]GraphCardView, len(recipes))
+	for i, recipe := range recipes {
+		labels := make([]string, len(recipe.Shared))
+		for j, node := range recipe.Shared {
+			labels[j] = node.Label
+		}
+		cards[i] = GraphCardView{
+			ID:     recipe.ID,
+			Title:  recipe.Title,
+			Rating: recipe.AverageRating,
+			Likes:  recipe.Likes,
+			Shared: strings.Join(labels, ", "),
+		}
+	}
+	return cards
+}
+
+// graphNodeURL is the page listing the recipes linked to node
+func graphNodeURL(node GraphNode) string {
+	return "/graph/" + url.PathEscape(node.Kind) + "/" + url.PathEscape(node.Key)
+}
+
+// RecipeRelatedView is the view model for the recipe-related fragment: the
+// techniques and cuisine a recipe uses and the recipes sharing the most
+// with it
+type RecipeRelatedView struct {
+	RecipeID   string
+	Techniques []GraphNode
+	Cuisines   []GraphNode
+	Related    []GraphCardView
+}
+
+// NewRecipeRelatedView builds the view from the API graph and related
+// recipes
+func NewRecipeRelatedView(graph RecipeGraph, related []GraphRecipe) RecipeRelatedView {
+	return RecipeRelatedView{
+		RecipeID:   graph.RecipeID,
+		Techniques: graph.Techniques,
+		Cuisines:   graph.Cuisines,
+		Related:    newGraphCards(related),
+	}
+}
+
+// NodeURL links a technique or cuisine to its recipes
+func (v RecipeRelatedView) NodeURL(node GraphNode) string {
+	return graphNodeURL(node)
+}
+
+// NodeLabel names a technique or cuisine link for screen readers
+func (v RecipeRelatedView) NodeLabel(node GraphNode) string {
+	return GraphRecipesView{Node: node}.Heading()
+}
+
+// GraphRecipesView is the view model for the graph-recipes fragment
+type GraphRecipesView struct {
+	Node    GraphNode
+	Recipes []GraphCardView
+}
+
+// NewGraphRecipesView builds the view from the API node recipes
+func NewGraphRecipesView(n GraphNodeRecipes) GraphRecipesView {
+	return GraphRecipesView{Node: n.Node, Recipes: newGraphCards(n.Recipes)}
+}
+
+// Heading says what the recipes have in common
+func (v GraphRecipesView) Heading() string {
+	switch v.Node.Kind {
+	case "technique":
+		return "Recipes using " + strings.ToLower(v.Node.Label)
+	case "cuisine":
+		return v.Node.Label + " recipes"
+	default:
+		return "Recipes with " + strings.ToLower(v.Node.Label)
+	}
+}
+
+// TechniqueView is the view model for the technique fragment: a
+// technique library page with its video
+type TechniqueView struct {
+	Slug    string
+	Name    string
+	Summary string
+	Steps   []string
+	Video   *TechniqueVideo
+}
+
+// NewTechniqueView builds the view from an API technique
+func NewTechniqueView(t Technique) TechniqueView {
+	return TechniqueView{Slug: t.Slug, Name: t.Name, Summary: t.Summary, Steps: t.Steps, Video: t.Video}
+}
+
+// VideoTitle names the embedded player for screen readers
+func (v TechniqueView) VideoTitle() string {
+	return techniqueVideoTitle(v.Name, v.Video)
+}
+
+func techniqueVideoTitle(name string, video *TechniqueVideo) string {
+	if video != nil && video.Title != "" {
+		return video.Title
+	}
+	return "How to: " + name
+}
+
+// CookStepView is one step in cook mode
+type CookStepView struct {
+	Number     int
+	Text       string
+	Techniques []CookTechniqueView
+	// Safety are the food safety warnings for the step
+	Safety []string
+}
+
+// CookTechniqueView is a technique shown beside a cook mode step
+type CookTechniqueView struct {
+	Slug      string
+	Name      string
+	Summary   string
+	Suggested bool
+	Video     *TechniqueVideo
+}
+
+// URL is the technique's page
+func (v CookTechniqueView) URL() string {
+	return "/techniques/" + url.PathEscape(v.Slug)
+}
+
+// LinkLabel names the technique link for screen readers
+func (v CookTechniqueView) LinkLabel() string {
+	return "How to: " + v.Name
+}
+
+// VideoTitle names the embedded player for screen readers
+func (v CookTechniqueView) VideoTitle() string {
+	return techniqueVideoTitle(v.Name, v.Video)
+}
+
+// CookStepsView is the view model for the cook-steps fragment: a recipe's
+// numbered steps with the techniques each uses and food safety warnings
+type CookStepsView struct {
+	RecipeID string
+	Title    string
+	Steps    []CookStepView
+	// Safety are the food safety warnings for the whole recipe
+	Safety []string
+	// Embedded renders the steps as a section of the recipe page, under
+	// the page's own title
+	Embedded bool
+}
+
+// NewCookStepsView builds the view from the API recipe steps, placing each
+// food safety warning on its step
+func NewCookStepsView(r RecipeSteps, safety []FoodSafetyIssue) CookStepsView {
+	view := CookStepsView{RecipeID: r.RecipeID, Title: r.Title, Steps: make([]CookStepView, len(r.Steps))}
+	for i, step := range r.Steps {
+		techniques := make([]CookTechniqueView, len(step.Techniques))
+		for j, t := range step.Techniques {
+			techniques[j] = CookTechniqueView{
+				Slug:      t.Slug,
+				Name:      t.Name,
+				Summary:   t.Summary,
+				Suggested: t.Source == "suggested",
+				Video:     t.Video,
+			}
+		}
+		view.Steps[i] = CookStepView{Number: step.Number, Text: step.Text, Techniques: techniques}
+	}
+	for _, issue := range safety {
+		if issue.Step >= 1 && issue.Step <= len(view.Steps) {
+			view.Steps[issue.Step-1].Safety = append(view.Steps[issue.Step-1].Safety, issue.Message)
+		} else {
+			view.Safety = append(view.Safety, issue.Message)
+		}
+	}
+	return view
+}
+
+// TimelineStepView is one step of a recipe timeline, placed on a bar
+// spanning the whole plan
+type TimelineStepView struct {
+	Number   int
+	Text     string
+	Start    string
+	End      string
+	Minutes  int
+	HandsOff bool
+	Parallel bool
+	// Offset and Width place the step's bar, in percent of the plan
+	Offset int
+	Width  int
+}
+
+// TimelineView is the view model for the recipe-timeline fragment: when to
+// start each remaining step so the food is ready at the serve time
+type TimelineView struct {
+	RecipeID      string
+	Title         string
+	Serve         string
+	Start         string
+	TotalMinutes  int
+	ActiveMinutes int
+	BehindMinutes int
+	Completed     int
+	Steps         []TimelineStepView
+	// ServeInput fills the datetime-local field; requested keeps the cook's
+	// serve time when they mark steps done
+	ServeInput string
+	requested  time.Time
+}
+
+// NewTimelineView builds the view from an API timeline, showing times in loc
+func NewTimelineView(t RecipeTimeline, loc *time.Location) TimelineView {
+	view := TimelineView{
+		RecipeID:      t.RecipeID,
+		Title:         t.Title,
+		Serve:         t.ServeAt.In(loc).Format("Mon 15:04"),
+		Start:         t.StartAt.In(loc).Format("Mon 15:04"),
+		TotalMinutes:  t.TotalMinutes,
+		ActiveMinutes: t.ActiveMinutes,
+		BehindMinutes: t.BehindMinutes,
+		Completed:     t.CompletedSteps,
+		Steps:         make([]TimelineStepView, len(t.Steps)),
+		ServeInput:    t.RequestedAt.In(loc).Format("2006-01-02T15:04"),
+		requested:     t.RequestedAt,
+	}
+	total := t.ServeAt.Sub(t.StartAt)
+	for i, step := range t.Steps {
+		view.Steps[i] = TimelineStepView{
+			Number:   step.Number,
+			Text:     step.Text,
+			Start:    step.StartAt.In(loc).Format("15:04"),
+			End:      step.EndAt.In(loc).Format("15:04"),
+			Minutes:  step.Minutes,
+			HandsOff: step.HandsOff,
+			Parallel: step.Parallel,
+		}
+		if total > 0 {
+			view.Steps[i].Offset = int(step.StartAt.Sub(t.StartAt) * 100 / total)
+			view.Steps[i].Width = int(step.EndAt.Sub(step.StartAt) * 100 / total)
+		}
+	}
+	return view
+}
+
+// URL reloads the timeline, replanning it from the current time
+func (v TimelineView) URL() string {
+	return v.url("timeline", v.Completed)
+}
+
+// CalendarURL downloads the timeline as calendar events
+func (v TimelineView) CalendarURL() string {
+	return v.url("timeline.ics", v.Completed)
+}
+
+// DoneURL replans the timeline with every step up to number finished
+func (v TimelineView) DoneURL(number int) string {
+	return v.url("timeline", number)
+}
+
+func (v TimelineView) url(resource string, completed int) string {
+	query := url.Values{}
+	query.Set("serve_at", v.requested.Format(time.RFC3339))
+	if completed > 0 {
+		query.Set("completed", strconv.Itoa(completed))
+	}
+	return "/recipes/" + url.PathEscape(v.RecipeID) + "/" + resource + "?" + query.Encode()
+}
+
+// DoneLabel names a step's done button for screen readers
+func (v TimelineView) DoneLabel(number int) string {
+	return fmt.Sprintf("Mark step %d done and replan", number)
+}
+
+// BattleEntryView is one entry of a remix battle. Side and Votes are only
+// set once voting has ended.
+type BattleEntryView struct {
+	Label       string
+	Side        string
+	Title       string
+	Description string
+	Ingredients []string
+	Steps       []string
+	Votes       int
+	Winner      bool
+}
+
+// BattleView is the view model for the remix-battle fragment: both
+// entries side by side, voted on without knowing which the AI wrote
+type BattleView struct {
+	ID         string
+	Prompt     string
+	Status     string
+	VotingEnds string
+	Entries    []BattleEntryView
+	YourVote   string
+	Template   string
+	// CanVote is true for signed-in readers other than the chef who have
+	// not voted yet
+	CanVote   bool
+	CSRFToken string
+}
+
+// NewBattleView builds the view from an API battle for the signed-in user,
+// empty for visitors
+func NewBattleView(b Battle, userID, csrfToken string) BattleView {
+	view := BattleView{
+		ID:        b.ID,
+		Prompt:    b.Prompt,
+		Status:    b.Status,
+		YourVote:  strings.ToUpper(b.YourVote),
+		Template:  b.Template,
+		CanVote:   b.Status == "voting" && userID != "" && userID != b.ChefID && b.YourVote == "",
+		CSRFToken: csrfToken,
+	}
+	if !b.VotingEndsAt.IsZero() {
+		view.VotingEnds = b.VotingEndsAt.UTC().Format("Jan 2, 15:04 MST")
+	}
+	for _, entry := range b.Entries {
+		e := BattleEntryView{
+			Label:       strings.ToUpper(entry.Label),
+			Title:       entry.Title,
+			Description: entry.Description,
+			Ingredients: entry.Ingredients,
+			Steps:       entry.Steps,
+			Winner:      entry.Side != "" && entry.Side == b.Winner,
+		}
+		switch entry.Side {
+		case "ai":
+			e.Side = "AI"
+		case "chef":
+			e.Side = "Community chef"
+		}
+		if entry.Votes != nil {
+			e.Votes = *entry.Votes
+		}
+		view.Entries = append(view.Entries, e)
+	}
+	return view
+}
+
+// VoteLabel names an entry's vote button for screen readers
+func (v BattleView) VoteLabel(label string) string {
+	return "Vote for entry " + label
+}
+
+// HomeSectionView is the view model for the home-section fragment: one
+// lazily loaded block of the home page
+type HomeSectionView struct {
+	Name    string
+	Title   string
+	Recipes []RecipeCardView
+	// Ingredients are the in-season ingredients of the seasonal hub
+	Ingredients []GraphNode
+	// Empty is shown when the section has no recipes
+	Empty string
+}
+
+// NewHomeSectionView builds a section listing API recipes
+func NewHomeSectionView(name, title, empty string, recipes []RecipeResponse) HomeSectionView {
+	view := HomeSectionView{Name: name, Title: title, Empty: empty, Recipes: make([]RecipeCardView, len(recipes))}
+	for i, recipe := range recipes {
+		view.Recipes[i] = NewRecipeCardView(recipe)
+	}
+	return view
+}
+
+// NewRecommendedSectionView builds the "Recipes you might like" section,
+// giving each recipe's reason
+func NewRecommendedSectionView(recommended *RecommendedRecipes) HomeSectionView {
+	recipes := recommended.Recipes
+	if len(recipes) > homeSectionRecipes {
+		recipes = recipes[:homeSectionRecipes]
+	}
+	view := NewHomeSectionView(HomeRecommended, "Recipes you might like", "Like or save a few recipes to get suggestions.", recipes)
+	for i := range view.Recipes {
+		view.Recipes[i].Reason = capitalize(recommended.Reasons[view.Recipes[i].ID])
+	}
+	return view
+}
+
+// RecipePageView is the view model for the recipe-page fragment: one page
+// of the recipe listing, ending with a load more item while more follow
+type RecipePageView struct {
+	Recipes []RecipeCardView
+	MoreURL string
+}
+
+// NewRecipePageView builds a page of the listing in the given order
+func NewRecipePageView(sort string, page *RecipePage) RecipePageView {
+	view := RecipePageView{Recipes: make([]RecipeCardView, len(page.Recipes))}
+	for i, recipe := range page.Recipes {
+		view.Recipes[i] = NewRecipeCardView(recipe)
+	}
+	if page.NextCursor != "" {
+		view.MoreURL = "/recipes?" + url.Values{"sort": {sort}, "after": {page.NextCursor}}.Encode()
+	}
+	return view
+}
+
+// RecipeSimilarView is the view model for the recipe-similar fragment: the
+// recipes sharing the most cuisine, tags and ingredients with a recipe
+type RecipeSimilarView struct {
+	RecipeID string
+	Recipes  []RecipeCardView
+}
+
+// NewRecipeSimilarView builds the sidebar, giving what each recipe shares
+func NewRecipeSimilarView(recipeID string, similar *RecommendedRecipes) RecipeSimilarView {
+	view := RecipeSimilarView{RecipeID: recipeID, Recipes: make([]RecipeCardView, len(similar.Recipes))}
+	for i, recipe := range similar.Recipes {
+		view.Recipes[i] = NewRecipeCardView(recipe)
+		view.Recipes[i].Reason = capitalize(similar.Reasons[recipe.ID])
+	}
+	return view
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// IngredientURL links an in-season ingredient to its recipes
+func (v HomeSectionView) IngredientURL(node GraphNode) string {
+	return graphNodeURL(node)
+}
+
+// AllergenPanelView is the view model for the recipe-allergens fragment:
+// the standard allergen statement and the ingredients behind each allergen
+type AllergenPanelView struct {
+	RecipeID   string
+	Panel      string
+	Contains   []AllergenLine
+	MayContain []AllergenLine
+}
+
+// AllergenLine is one allergen with where it comes from
+type AllergenLine struct {
+	Label string
+	From  string
+}
+
+// allergenReasons say why an ingredient only may bring an allergen in
+var allergenReasons = map[string]string{
+	"optional":      "if added",
+	"substitute":    "as a substitute",
+	"cross-contact": "often made alongside it",
+}
+
+// NewAllergenPanelView builds the view from the API disclosure
+func NewAllergenPanelView(a RecipeAllergens) AllergenPanelView {
+	view := AllergenPanelView{RecipeID: a.RecipeID, Panel: a.Panel}
+	for _, f := range a.Allergens {
+		from := make([]string, 0, len(f.Sources))
+		for _, src := range f.Sources {
+			if f.Status == "contains" && src.Reason != "ingredient" {
+				continue
+			}
+			if reason, ok := allergenReasons[src.Reason]; ok {
+				from = append(from, src.Ingredient+" ("+reason+")")
+				continue
+			}
+			from = append(from, src.Ingredient)
+		}
+		line := AllergenLine{Label: f.Label, From: strings.Join(from, ", ")}
+		if f.Status == "contains" {
+			view.Contains = append(view.Contains, line)
+		} else {
+			view.MayContain = append(view.MayContain, line)
+		}
+	}
+	return view
+}
+
+// NutritionLabelView is the view model for the recipe-nutrition fragment:
+// a nutrition facts label for one serving
+type NutritionLabelView struct {
+	RecipeID string
+	Servings int
+	Calories int
+	Rows     []NutritionRow
+	// Coverage is the percentage of required ingredients counted; below
+	// 100 the label says the figures are a lower bound
+	Coverage  int
+	Available bool
+}
+
+// NutritionRow is one nutrient on the label. Sub rows, like fiber under
+// carbohydrates, are indented. DailyValue is blank without a reference.
+type NutritionRow struct {
+	Label      string
+	Amount     string
+	DailyValue string
+	Sub        bool
+}
+
+// Partial reports whether some ingredients were left out of the figures
+func (v NutritionLabelView) Partial() bool {
+	return v.Available && v.Coverage < 100
+}
+
+// NewNutritionLabelView builds the label from the recipe's per-serving
+// nutrition. Daily values follow the FDA reference amounts for adults.
+func NewNutritionLabelView(r RecipeResponse) NutritionLabelView {
+	view := NutritionLabelView{RecipeID: r.ID, Servings: r.Servings}
+	n := r.Nutrition
+	if n == nil {
+		return view
+	}
+	view.Available = true
+	view.Calories = n.Calories
+	view.Coverage = int(math.Round(n.Coverage * 100))
+	view.Rows = []NutritionRow{
+		nutrientRow("Total fat", n.Fat, "g", 78, false),
+		nutrientRow("Cholesterol", n.Cholesterol, "mg", 300, false),
+		nutrientRow("Sodium", n.Sodium, "mg", 2300, false),
+		nutrientRow("Total carbohydrate", n.Carbohydrates, "g", 275, false),
+		nutrientRow("Dietary fiber", n.Fiber, "g", 28, true),
+		nutrientRow("Total sugars", n.Sugar, "g", 0, true),
+		nutrientRow("Protein", n.Protein, "g", 50, false),
+	}
+	return view
+}
+
+func nutrientRow(label string, amount float64, unit string, daily float64, sub bool) NutritionRow {
+	row := NutritionRow{Label: label, Amount: strconv.FormatFloat(amount, 'f', -1, 64) + unit, Sub: sub}
+	if daily > 0 {
+		row.DailyValue = fmt.Sprintf("%.0f%%", amount/daily*100)
+	}
+	return row
+}
+
+// RecipeIngredientsView is the view model for the recipe-ingredients
+// fragment: the ingredient list at a serving count and in a measuring
+// system, with selectors that swap in the list for another
+type RecipeIngredientsView struct {
+	RecipeID         string
+	Servings         int
+	OriginalServings int
+	Options          []int
+	Units            string
+	UnitOptions      []UnitOption
+	CSRFToken        string
+	Lines            []ScaledIngredient
+}
+
+// UnitOption is one choice in the units selector
+type UnitOption struct {
+	Value string
+	Label string
+}
+
+// unitOptions are the measuring systems readers can pick
+var unitOptions = []UnitOption{
+	{Value: UnitsOriginal, Label: "As written"},
+	{Value: UnitsMetric, Label: "Metric"},
+	{Value: UnitsImperial, Label: "Imperial"},
+}
+
+// Scaled reports whether the list differs from the recipe as written
+func (v RecipeIngredientsView) Scaled() bool {
+	return v.Servings != v.OriginalServings
+}
+
+// SubstitutesURL is where the list's "no X?" toggle loads substitutes for
+// an ingredient from
+func (v RecipeIngredientsView) SubstitutesURL(name string) string {
+	return "/ingredients/" + url.PathEscape(name) + "/substitutes"
+}
+
+// IngredientSubstitutesView is the view model for the
+// ingredient-substitutes fragment: what to use in place of an ingredient,
+// and how many were left out for the reader's diet
+type IngredientSubstitutesView struct {
+	Ingredient  string
+	Substitutes []IngredientSubstitute
+	Hidden      int
+	Diets       []string
+}
+
+// NewIngredientSubstitutesView builds the suggestions for an ingredient
+func NewIngredientSubstitutesView(s IngredientSubstitutes) IngredientSubstitutesView {
+	view := IngredientSubstitutesView{
+		Ingredient:  s.Ingredient,
+		Substitutes: s.Substitutes,
+		Hidden:      s.Hidden,
+	}
+	for _, diet := range s.Diets {
+		view.Diets = append(view.Diets, strings.ReplaceAll(diet, "_", "-"))
+	}
+	return view
+}
+
+// maxServingOption matches the most servings the API scales to
+const maxServingOption = 100
+
+// NewRecipeIngredientsView builds the list from the scaled recipe. The
+// selector offers 1 to 12 servings, double and triple the recipe, and the
+// current choice.
+func NewRecipeIngredientsView(r ScaledRecipe) RecipeIngredientsView {
+	view := RecipeIngredientsView{
+		RecipeID:         r.RecipeID,
+		Servings:         r.Servings,
+		OriginalServings: r.OriginalServings,
+		Units:            r.Units,
+		UnitOptions:      unitOptions,
+		Lines:            r.Ingredients,
+	}
+	if !validUnits(view.Units) {
+		view.Units = UnitsOriginal
+	}
+	seen := map[int]bool{}
+	add := func(n int) {
+		if n > 0 && n <= maxServingOption && !seen[n] {
+			seen[n] = true
+			view.Options = append(view.Options, n)
+		}
+	}
+	for n := 1; n <= 12; n++ {
+		add(n)
+	}
+	add(r.OriginalServings)
+	add(r.OriginalServings * 2)
+	add(r.OriginalServings * 3)
+	add(r.Servings)
+	sort.Ints(view.Options)
+	return view
+}
+
+// AdminStatsView is the view model for the admin-stats fragment: system
+// totals and the replica that answered
+type AdminStatsView struct {
+	Stats     SystemStats
+	StartedAt string
+}
+
+// NewAdminStatsView builds the view from the API totals
+func NewAdminStatsView(s SystemStats) AdminStatsView {
+	return AdminStatsView{Stats: s, StartedAt: s.Runtime.StartedAt.UTC().Format("Jan 2, 15:04 MST")}
+}
+
+// AdminUsersView is the view model for the admin-users fragment: the
+// filter form and one page of users with suspend and reinstate buttons
+type AdminUsersView struct {
+	Query     string
+	Status    string
+	Page      int
+	Total     int
+	Users     []AdminUserRow
+	HasNext   bool
+	CSRFToken string
+}
+
+// AdminUserRow is one user in the admin list
+type AdminUserRow struct {
+	ID        string
+	Email     string
+	Name      string
+	Role      string
+	Recipes   int
+	Joined    string
+	LastLogin string
+	Suspended bool
+	// CanAct is false for admins, whom no one suspends here
+	CanAct bool
+}
+
+// NewAdminUsersView builds the view from one API page
+func NewAdminUsersView(p AdminUserPage, query, status string, page int, csrfToken string) AdminUsersView {
+	view := AdminUsersView{
+		Query:     query,
+		Status:    status,
+		Page:      page,
+		Total:     p.Total,
+		HasNext:   p.Offset+len(p.Users) < p.Total,
+		CSRFToken: csrfToken,
+	}
+	for _, u := range p.Users {
+		row := AdminUserRow{
+			ID:        u.ID,
+			Email:     u.Email,
+			Name:      u.Name,
+			Role:      u.Role,
+			Recipes:   u.Recipes,
+			Joined:    u.CreatedAt.UTC().Format("Jan 2, 2006"),
+			LastLogin: "never",
+			Suspended: u.Status == "suspended",
+			CanAct:    u.Role != "admin",
+		}
+		if u.LastLoginAt != nil {
+			row.LastLogin = u.LastLoginAt.UTC().Format("Jan 2, 2006")
+		}
+		view.Users = append(view.Users, row)
+	}
+	return view
+}
+
+// ActionLabel names a user's suspend or reinstate button for screen readers
+func (v AdminUsersView) ActionLabel(u AdminUserRow) string {
+	if u.Suspended {
+		return "Reinstate " + u.Email
+	}
+	return "Suspend " + u.Email
+}
+
+// PrevURL links the previous page of the list with the same filters
+func (v AdminUsersView) PrevURL() string {
+	return v.pageURL(v.Page - 1)
+}
+
+// NextURL links the next page of the list with the same filters
+func (v AdminUsersView) NextURL() string {
+	return v.pageURL(v.Page + 1)
+}
+
+func (v AdminUsersView) pageURL(page int) string {
+	q := url.Values{"page": {strconv.Itoa(page)}}
+	if v.Query != "" {
+		q.Set("q", v.Query)
+	}
+	if v.Status != "" {
+		q.Set("status", v.Status)
+	}
+	return "/admin/users?" + q.Encode()
+}
+
+// AIContentView is the view model for the admin-ai-content fragment:
+// AI-generated recipes with their prompts, for review
+type AIContentView struct {
+	Page      int
+	Total     int
+	Recipes   []AIContentRow
+	HasNext   bool
+	CSRFToken string
+}
+
+// AIContentRow is one AI-generated recipe
+type AIContentRow struct {
+	ID          string
+	Title       string
+	AuthorEmail string
+	Status      string
+	Model       string
+	Prompt      string
+	Created     string
+}
+
+// NewAIContentView builds the view from one API page
+func NewAIContentView(p AIContentPage, page int, csrfToken string) AIContentView {
+	view := AIContentView{
+		Page:      page,
+		Total:     p.Total,
+		HasNext:   p.Offset+len(p.Recipes) < p.Total,
+		CSRFToken: csrfToken,
+	}
+	for _, r := range p.Recipes {
+		view.Recipes = append(view.Recipes, AIContentRow{
+			ID:          r.ID,
+			Title:       r.Title,
+			AuthorEmail: r.AuthorEmail,
+			Status:      r.Status,
+			Model:       r.Model,
+			Prompt:      r.Prompt,
+			Created:     r.CreatedAt.UTC().Format("Jan 2, 2006"),
+		})
+	}
+	return view
+}
+
+// UnpublishLabel names a recipe's unpublish button for screen readers
+func (v AIContentView) UnpublishLabel(r AIContentRow) string {
+	return "Unpublish " + r.Title
+}
+
+// RecipeEditView is the view model for the recipe-edit fragment: the edit
+// form, with the recipe's fields rendered by the wizard step templates
+type RecipeEditView struct {
+	RecipeID  string
+	Body      template.HTML
+	Tags      string
+	FormError string
+	CSRFToken string
+}
+
+// FragmentSpec describes one registered fragment
+type FragmentSpec struct {
+	Name        string
+	Template    string
+	Description string
+	// Interactive fragments must name their controls through the ariaLabel helper
+	Interactive bool
+	// Samples returns representative view models used by the gallery and golden tests
+	Samples func() []interface{}
+}
+
+// FragmentRegistry renders named fragments from the parsed template set
+type FragmentRegistry struct {
+	templates *TemplateRenderer
+	specs     map[string]FragmentSpec
+}
+
+// NewFragmentRegistry registers the built-in fragments and verifies that
+// every referenced template exists
+func NewFragmentRegistry(templates *TemplateRenderer) (*FragmentRegistry, error) {
+	registry := &FragmentRegistry{
+		templates: templates,
+		specs:     make(map[string]FragmentSpec),
+	}
+
+	for _, spec := range builtinFragments() {
+		tmpl := templates.Lookup(spec.Template)
+		if tmpl == nil {
+			return nil, fmt.Errorf("fragment %s: template %s not found", spec.Name, spec.Template)
+		}
+		if spec.Interactive && !strings.Contains(tmpl.Tree.Root.String(), "ariaLabel") {
+			return nil, fmt.Errorf("fragment %s: interactive fragments must set aria-label via ariaLabel", spec.Name)
+		}
+		registry.specs[spec.Name] = spec
+	}
+
+	return registry, nil
+}
+
+// builtinFragments lists the fragment contracts shipped with the web frontend
+func builtinFragments() []FragmentSpec {
+	return []FragmentSpec{
+		{
+			Name:        FragmentRecipeCard,
+			Template:    "fragments/recipe-card",
+			Description: "Recipe summary card used in listings and search results",
+			Samples: func() []interface{} {
+				return []interface{}{
+					RecipeCardView{ID: "sample-1", Title: "Chicken Stir-Fry", Description: "Quick and healthy chicken with vegetables", AuthorName: "Sam", AuthorBadge: &AuthorBadge{Kind: "chef", DisplayName: "Head chef, Lupa"}, Emoji: "🍗", Rating: 4.8, TotalMinutes: 20, Allergens: &AllergenLabel{Contains: []string{"soy"}, MayContain: []string{"gluten"}}},
+					RecipeCardView{ID: "sample-2", Title: "<Garden> Salad", Rating: 3.2, AllergyWarnings: []string{"tree-nuts", "sesame"}, Allergens: &AllergenLabel{Contains: []string{"tree-nuts", "sesame"}}},
+				}
+			},
+		},
+		{
+			Name:        FragmentRecipePage,
+			Template:    "fragments/recipe-page",
+			Description: "A page of the recipe listing, with a load more item that swaps in the next page",
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewRecipePageView(RecipeSortNew, &RecipePage{
+						Recipes: []RecipeResponse{
+							{ID: "9b1d", Title: "Braised <Leeks>", Rating: 4.6, PrepTime: 10, CookTime: 45},
+							{ID: "7c4e", Title: "Leek Gratin", Rating: 3.9},
+						},
+						NextCursor: "eyJpZCI6IjdjNGUifQ",
+					}),
+					NewRecipePageView(RecipeSortRating, &RecipePage{
+						Recipes: []RecipeResponse{{ID: "5e8f", Title: "Pot-au-feu", Rating: 4.1}},
+					}),
+				}
+			},
+		},
+		{
+			Name:        FragmentLikeButton,
+			Template:    "fragments/like-button",
+			Description: "Toggle button swapped in place after liking a recipe",
+			Interactive: true,
+			Samples: func() []interface{} {
+				return []interface{}{
+					LikeButtonView{RecipeID: "sample-1", Liked: false},
+					LikeButtonView{RecipeID: "sample-1", Liked: true, Count: 42},
+				}
+			},
+		},
+		{
+			Name:        FragmentChatMessage,
+			Template:    "fragments/chat-message",
+			Description: "Single AI chat bubble, either from the user or the AI chef",
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewChatMessageView(true, "You", "What can I cook with <chicken>?", "Just now"),
+					NewChatMessageView(false, "AI Chef", "Try a stir-fry.\nServe over rice.", "Just now"),
+				}
+			},
+		},
+		{
+			Name:        FragmentDashStats,
+			Template:    "fragments/dashboard-stats",
+			Description: "Per-user counters shown on the dashboard, refreshed out-of-band",
+			Samples: func() []interface{} {
+				return []interface{}{
+					DashboardStatsView{Recipes: 12, Liked: 3, Favorites: 5},
+				}
+			},
+		},
+		{
+			Name:        FragmentThemeToggle,
+			Template:    "fragments/theme-toggle",
+			Description: "Header button cycling system, dark and light themes",
+			Interactive: true,
+			Samples: func() []interface{} {
+				return []interface{}{
+					ThemeToggleView{Theme: ThemeSystem},
+					ThemeToggleView{Theme: ThemeDark},
+				}
+			},
+		},
+		{
+			Name:        FragmentWizardStep,
+			Template:    "fragments/wizard-step",
+			Description: "One step of a server-driven wizard with progress and navigation",
+			Interactive: true,
+			Samples: func() []interface{} {
+				progress := []WizardProgressItem{
+					{Index: 1, Title: "Basics", Reached: true},
+					{Index: 2, Title: "Ingredients", Current: true, Reached: true},
+					{Index: 3, Title: "Publish"},
+				}
+				return []interface{}{
+					WizardStepView{
+						WizardTitle: "Create a Recipe",
+						BasePath:    "/recipes/wizard",
+						StepTitle:   "Ingredients",
+						StepNumber:  2,
+						StepCount:   3,
+						Progress:    progress,
+						Body:        template.HTML(`<label for="ingredients">Ingredients</label><textarea id="ingredients" name="ingredients"></textarea>`),
+						FormError:   "We couldn't finish this just now.",
+						CSRFToken:   "sample-token",
+					},
+					WizardStepView{
+						WizardTitle: "Create a Recipe",
+						BasePath:    "/recipes/wizard",
+						StepTitle:   "Basics",
+						StepNumber:  1,
+						StepCount:   1,
+						Progress:    []WizardProgressItem{{Index: 1, Title: "Basics", Current: true, Reached: true}},
+						Body:        template.HTML(`<label for="title">Title</label><input id="title" name="title">`),
+						CSRFToken:   "sample-token",
+						IsFirst:     true,
+						IsLast:      true,
+					},
+				}
+			},
+		},
+		{
+			Name:        FragmentUndoToast,
+			Template:    "fragments/undo-toast",
+			Description: "Toast offering a time-limited undo after a destructive action",
+			Interactive: true,
+			Samples: func() []interface{} {
+				return []interface{}{
+					UndoToastView{Message: "Recipe deleted", Token: "3f2a9c", Seconds: 10, CSRFToken: "sample-token"},
+					UndoToastView{Message: "Recipe restored"},
+				}
+			},
+		},
+		{
+			Name:        FragmentSearchMiss,
+			Template:    "fragments/search-fallback",
+			Description: "Zero-result search state with suggested queries and on-the-spot AI generation",
+			Interactive: true,
+			Samples: func() []interface{} {
+				return []interface{}{
+					SearchFallbackView{Query: "vegn tacos", Suggestions: []string{"vegan tacos", "tofu tacos"}, GeneratePrompt: "vegn tacos", CSRFToken: "sample-token"},
+					SearchFallbackView{Query: "<blini>", GeneratePrompt: "<blini>", CSRFToken: "sample-token"},
+				}
+			},
+		},
+		{
+			Name:        FragmentFacets,
+			Template:    "fragments/search-facets",
+			Description: "Search filters with result counts per value that re-run the search on change",
+			Interactive: true,
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewSearchFacetsView(`soup "<b>"`, &SearchFacets{
+						Cuisines:    []FacetCount{{Value: "thai", Count: 4}, {Value: "french", Count: 1}},
+						MaxTime:     []FacetCount{{Value: "15", Count: 0}, {Value: "30", Count: 2}, {Value: "60", Count: 5}},
+						Diets:       []FacetCount{{Value: "gluten_free", Count: 2}},
+						AIGenerated: []FacetCount{{Value: "true", Count: 1}, {Value: "false", Count: 4}},
+					}, SearchFilters{Cuisines: []string{"thai"}, Difficulties: []string{"hard"}, MaxTime: 60, Semantic: true}, "sample-token"),
+					NewSearchFacetsView("soup", nil, SearchFilters{}, "sample-token"),
+				}
+			},
+		},
+		{
+			Name:        FragmentIngredients,
+			Template:    "fragments/ingredient-preview",
+			Description: "Live table of pasted ingredient lines as amount, unit, name and notes",
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewIngredientPreviewView("For the dough:\n2 1/2 cups finely chopped yellow onion (about 1 large)\n1-2 tsp salt, to taste\n<script>\n()"),
+					IngredientPreviewView{},
+				}
+			},
+		},
+		{
+			Name:        FragmentRecipeStats,
+			Template:    "fragments/recipe-stats",
+			Description: "Author stats for one recipe: views chart, conversions, search terms, scroll depth and referrers",
+			Interactive: true,
+			Samples: func() []interface{} {
+				depth := 64.5
+				return []interface{}{
+					NewRecipeStatsView(RecipeAnalytics{
+						RecipeID:           "3f2a9c",
+						Title:              "Lemon <Bars>",
+						Since:              "2026-10-12",
+						Until:              "2026-10-18",
+						Views:              40,
+						UniqueViewers:      32,
+						Likes:              3,
+						Bookmarks:          2,
+						LikeConversion:     0.0938,
+						BookmarkConversion: 0.0625,
+						AverageScrollDepth: &depth,
+						ScrollSamples:      25,
+						Daily: []DailyViews{
+							{Date: "2026-10-12", Views: 4}, {Date: "2026-10-13", Views: 0}, {Date: "2026-10-14", Views: 8},
+							{Date: "2026-10-15", Views: 1}, {Date: "2026-10-16", Views: 6}, {Date: "2026-10-17", Views: 16},
+							{Date: "2026-10-18", Views: 5},
+						},
+						SearchTerms: []AnalyticsCount{{Key: "lemon bars", Count: 12}, {Key: "<script>", Count: 1}},
+						Referrers:   []AnalyticsCount{{Key: "google.com", Count: 20}, {Key: "direct", Count: 14}},
+					}, 7),
+					NewRecipeStatsView(RecipeAnalytics{RecipeID: "9b1d", Title: "Plain Toast", Since: "2026-09-19", Until: "2026-10-18"}, 30),
+				}
+			},
+		},
+		{
+			Name:        FragmentSparkline,
+			Template:    "fragments/sparkline",
+			Description: "Small line chart of one figure per day, with its total",
+			Samples: func() []interface{} {
+				high, low := 4.5, 3.0
+				return []interface{}{
+					NewSparklineView("Views", "31", []*float64{dayValue(4), dayValue(0), dayValue(12), dayValue(15)}, 0, 1),
+					NewSparklineView("Average <rating>", "3.8", []*float64{nil, &high, nil, &low}, 1, 5),
+				}
+			},
+		},
+		{
+			Name:        FragmentAuthorStats,
+			Template:    "fragments/author-analytics",
+			Description: "Views, likes, search impressions and ratings across an author's recipes, charted per day and totalled per recipe",
+			Interactive: true,
+			Samples: func() []interface{} {
+				rating := 4.5
+				return []interface{}{
+					NewAuthorAnalyticsView(AuthorAnalytics{
+						Since:         "2026-10-16",
+						Until:         "2026-10-18",
+						Views:         42,
+						Likes:         5,
+						Impressions:   310,
+						Ratings:       2,
+						AverageRating: &rating,
+						Daily: []AuthorDailyStats{
+							{Date: "2026-10-16", Views: 10, Likes: 1, Impressions: 90},
+							{Date: "2026-10-17", Views: 20, Likes: 4, Impressions: 120, Ratings: 2, AverageRating: &rating},
+							{Date: "2026-10-18", Views: 12, Impressions: 100},
+						},
+						Recipes: []AuthorRecipeStats{
+							{RecipeID: "3f2a9c", Title: "Lemon <Bars>", Views: 40, Likes: 5, Impressions: 300, Ratings: 2, AverageRating: &rating, Rating: 4.25},
+							{RecipeID: "9b1d", Title: "Plain Toast", Views: 2, Impressions: 10, Rating: 0},
+						},
+					}, 7),
+					NewAuthorAnalyticsView(AuthorAnalytics{Since: "2026-09-19", Until: "2026-10-18"}, 30),
+				}
+			},
+		},
+		{
+			Name:        FragmentRelated,
+			Template:    "fragments/recipe-related",
+			Description: "Techniques and cuisine a recipe uses, linking to their other recipes, and the recipes sharing the most with it",
+			Interactive: true,
+			Samples: func() []interface{} {
+				braise := GraphNode{Kind: "technique", Key: "braise", Label: "Braising", Recipes: 14}
+				leek := GraphNode{Kind: "ingredient", Key: "leek", Label: "Leeks", Recipes: 9}
+				return []interface{}{
+					NewRecipeRelatedView(RecipeGraph{
+						RecipeID:   "3f2a9c",
+						Techniques: []GraphNode{braise, {Kind: "technique", Key: "sear", Label: "Searing", Recipes: 31}},
+						Cuisines:   []GraphNode{{Kind: "cuisine", Key: "french", Label: "French", Recipes: 22}},
+					}, []GraphRecipe{
+						{ID: "9b1d", Title: "Braised <Leeks>", AverageRating: 4.6, Likes: 12, Shared: []GraphNode{braise, leek}},
+						{ID: "7c4e", Title: "Leek Gratin", AverageRating: 3.9, Likes: 3, Shared: []GraphNode{leek}},
+					}),
+					NewRecipeRelatedView(RecipeGraph{RecipeID: "5e8f"}, nil),
+				}
+			},
+		},
+		{
+			Name:        FragmentSimilar,
+			Template:    "fragments/recipe-similar",
+			Description: "Sidebar of the recipes sharing the most cuisine, tags and ingredients with a recipe",
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewRecipeSimilarView("3f2a9c", &RecommendedRecipes{
+						Recipes: []RecipeResponse{
+							{ID: "9b1d", Title: "Braised <Leeks>", Rating: 4.6, PrepTime: 10, CookTime: 45},
+							{ID: "7c4e", Title: "Leek Gratin", Rating: 3.9},
+						},
+						Reasons: map[string]string{"9b1d": "shares leek and french cuisine", "7c4e": "shares leek"},
+					}),
+					NewRecipeSimilarView("5e8f", &RecommendedRecipes{}),
+				}
+			},
+		},
+		{
+			Name:        FragmentGraphList,
+			Template:    "fragments/graph-recipes",
+			Description: "Other recipes using one technique, cuisine or ingredient, most liked first",
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewGraphRecipesView(GraphNodeRecipes{
+						Node: GraphNode{Kind: "technique", Key: "braise", Label: "Braising", Recipes: 14},
+						Recipes: []GraphRecipe{
+							{ID: "9b1d", Title: "Braised <Leeks>", AverageRating: 4.6, Likes: 12},
+							{ID: "2a6b", Title: "Coq au Vin", AverageRating: 4.9, Likes: 8},
+						},
+					}),
+					NewGraphRecipesView(GraphNodeRecipes{Node: GraphNode{Kind: "cuisine", Key: "french", Label: "French"}}),
+				}
+			},
+		},
+		{
+			Name:        FragmentTechnique,
+			Template:    "fragments/technique",
+			Description: "Technique library page: summary, how-to steps and an embedded video",
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewTechniqueView(Technique{
+						Slug:    "julienne",
+						Name:    "Julienne",
+						Summary: "Cut vegetables into thin <even> matchsticks.",
+						Steps:   []string{"Square off the carrot.", "Slice into 3 mm planks, then matchsticks."},
+						Video: &TechniqueVideo{
+							Provider: "youtube",
+							EmbedURL: "https://www.youtube-nocookie.com/embed/AbCdEfGhIjK?start=30",
+							WatchURL: "https://www.youtube.com/watch?v=AbCdEfGhIjK&t=30s",
+						},
+					}),
+					NewTechniqueView(Technique{Slug: "whisking", Name: "Whisking", Summary: "Beat until smooth."}),
+				}
+			},
+		},
+		{
+			Name:        FragmentCookSteps,
+			Template:    "fragments/cook-steps",
+			Description: "Cook mode steps with the techniques each step uses, their summaries and videos, and food safety warnings",
+			Interactive: true,
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewCookStepsView(RecipeSteps{
+						RecipeID: "3f2a9c",
+						Title:    "Carrot <Salad>",
+						Steps: []RecipeStep{
+							{Number: 1, Text: "Cut the carrots into matchsticks.", Techniques: []StepTechnique{{
+								Slug:    "julienne",
+								Name:    "Julienne",
+								Summary: "Cut vegetables into thin matchsticks.",
+								Source:  "author",
+								Video: &TechniqueVideo{
+									Provider: "vimeo",
+									Title:    "Julienne in 60 seconds",
+									EmbedURL: "https://player.vimeo.com/video/76979871",
+									WatchURL: "https://vimeo.com/76979871",
+								},
+							}}},
+							{Number: 2, Text: "Whisk the dressing.", Techniques: []StepTechnique{{
+								Slug: "whisking", Name: "Whisking", Summary: "Beat until smooth.", Source: "suggested",
+							}}},
+							{Number: 3, Text: "Toss and serve."},
+						},
+					}, nil),
+					NewCookStepsView(RecipeSteps{RecipeID: "5e8f", Title: "Plain Toast"}, nil),
+					NewCookStepsView(RecipeSteps{
+						RecipeID: "7b1d",
+						Title:    "Grilled Chicken",
+						Steps: []RecipeStep{
+							{Number: 1, Text: "Marinate the chicken on the counter for 3 hours."},
+							{Number: 2, Text: "Grill until golden."},
+						},
+					}, []FoodSafetyIssue{
+						{Step: 1, Rule: "danger-zone", Message: "By the end of step 1 the chicken has been out of the fridge for 3 hours."},
+						{Rule: "missing-temperature", Message: "No step says how hot the chicken must get."},
+					}),
+				}
+			},
+		},
+		{
+			Name:        FragmentTimeline,
+			Template:    "fragments/recipe-timeline",
+			Description: "Recipe timeline scheduled back from a serve time, replanned as steps are marked done",
+			Interactive: true,
+			Samples: func() []interface{} {
+				serve := time.Date(2026, 10, 18, 19, 0, 0, 0, time.UTC)
+				return []interface{}{
+					NewTimelineView(RecipeTimeline{
+						RecipeID:      "3f2a9c",
+						Title:         "Fusion <Tacos>",
+						ServeAt:       serve,
+						RequestedAt:   serve,
+						StartAt:       serve.Add(-45 * time.Minute),
+						TotalMinutes:  45,
+						ActiveMinutes: 15,
+						Steps: []TimelineStep{
+							{Number: 1, Text: "Marinate the beef for 30 minutes", StartAt: serve.Add(-45 * time.Minute), EndAt: serve.Add(-15 * time.Minute), Minutes: 30, HandsOff: true},
+							{Number: 2, Text: "Grill the beef", StartAt: serve.Add(-15 * time.Minute), EndAt: serve.Add(-7 * time.Minute), Minutes: 8},
+							{Number: 3, Text: "Meanwhile, warm the tortillas", StartAt: serve.Add(-7 * time.Minute), EndAt: serve.Add(-5 * time.Minute), Minutes: 2, Parallel: true},
+							{Number: 4, Text: "Assemble the tacos", StartAt: serve.Add(-5 * time.Minute), EndAt: serve, Minutes: 5},
+						},
+					}, time.UTC),
+					NewTimelineView(RecipeTimeline{
+						RecipeID:       "5e8f",
+						Title:          "Plain Toast",
+						ServeAt:        serve.Add(5 * time.Minute),
+						RequestedAt:    serve,
+						StartAt:        serve.Add(-5 * time.Minute),
+						TotalMinutes:   10,
+						ActiveMinutes:  10,
+						BehindMinutes:  5,
+						CompletedSteps: 1,
+						Steps: []TimelineStep{
+							{Number: 2, Text: "Butter the toast", StartAt: serve.Add(-5 * time.Minute), EndAt: serve.Add(5 * time.Minute), Minutes: 10},
+						},
+					}, time.UTC),
+				}
+			},
+		},
+		{
+			Name:        FragmentBattle,
+			Template:    "fragments/remix-battle",
+			Description: "Remix battle entries side by side with blind voting, revealed once voting ends",
+			Interactive: true,
+			Samples: func() []interface{} {
+				ends := time.Date(2026, 10, 25, 18, 0, 0, 0, time.UTC)
+				ai := BattleEntry{Label: "a", Title: "Miso <Salmon>", Ingredients: []string{"2 salmon fillets", "1 tbsp miso"}, Steps: []string{"Brush with miso.", "Roast at 220C for 12 minutes."}}
+				chef := BattleEntry{Label: "b", Title: "Salmon en Papillote", Description: "Steamed in parchment.", Ingredients: []string{"2 salmon fillets", "1 lemon"}, Steps: []string{"Fold into parchment and bake."}}
+				aiVotes, chefVotes := 12, 9
+				closedAI, closedChef := ai, chef
+				closedAI.Side, closedAI.Votes = "ai", &aiVotes
+				closedChef.Side, closedChef.Votes = "chef", &chefVotes
+				return []interface{}{
+					NewBattleView(Battle{ID: "7c1d", Prompt: "A weeknight salmon dinner", ChefID: "u1", Status: "voting", VotingEndsAt: ends, Entries: []BattleEntry{ai, chef}}, "u2", "sample-token"),
+					NewBattleView(Battle{ID: "7c1d", Prompt: "A weeknight salmon dinner", ChefID: "u1", Status: "closed", VotingEndsAt: ends, Entries: []BattleEntry{closedAI, closedChef}, YourVote: "b", Winner: "ai", Template: "chef-notes"}, "u2", ""),
+				}
+			},
+		},
+		{
+			Name:        FragmentHomeSection,
+			Template:    "fragments/home-section",
+			Description: "Home page section swapped in when it scrolls into view",
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewHomeSectionView(HomeTrending, "Trending now", "Nothing is trending yet.", []RecipeResponse{
+						{ID: "sample-1", Title: "Chicken Stir-Fry", AuthorName: "Sam", Rating: 4.8, PrepTime: 5, CookTime: 15},
+						{ID: "sample-2", Title: "<Garden> Salad", Rating: 3.2},
+					}),
+					NewRecommendedSectionView(&RecommendedRecipes{
+						Recipes: []RecipeResponse{
+							{ID: "sample-3", Title: "Beef Stew", AuthorName: "Ana", Rating: 4.5, PrepTime: 20, CookTime: 120},
+							{ID: "sample-4", Title: "Lentil Soup", Rating: 4.1, CookTime: 40},
+						},
+						Reasons: map[string]string{"sample-3": "often enjoyed by the same people as Coq au Vin"},
+					}),
+					HomeSectionView{
+						Name:        HomeSeasonal,
+						Title:       "In season in October",
+						Ingredients: []GraphNode{{Kind: "ingredient", Key: "pumpkin", Label: "Pumpkin"}, {Kind: "ingredient", Key: "brussels sprout", Label: "Brussels sprouts"}},
+						Empty:       "No recipes with these yet.",
+					},
+				}
+			},
+		},
+		{
+			Name:        FragmentRecipeEdit,
+			Template:    "fragments/recipe-edit",
+			Description: "Recipe edit form with save and delete",
+			Interactive: true,
+			Samples: func() []interface{} {
+				return []interface{}{
+					RecipeEditView{
+						RecipeID:  "sample-1",
+						Body:      template.HTML(`<label for="title">Title</label><input id="title" name="title" value="Lemon Bars">`),
+						Tags:      "baking, citrus",
+						CSRFToken: "sample-token",
+					},
+					RecipeEditView{
+						RecipeID:  "sample-2",
+						Body:      template.HTML(`<label for="title">Title</label><input id="title" name="title" value="">`),
+						FormError: "Only the author or an admin can edit this recipe.",
+						CSRFToken: "sample-token",
+					},
+				}
+			},
+		},
+		{
+			Name:        FragmentAllergens,
+			Template:    "fragments/recipe-allergens",
+			Description: "Allergen panel: the allergens a recipe contains and may contain, with the ingredients behind them",
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewAllergenPanelView(RecipeAllergens{
+						RecipeID: "3f2a9c",
+						Panel:    "Contains: Cereals containing gluten, Milk. May contain: Nuts.",
+						Allergens: []AllergenFinding{
+							{Label: "Cereals containing gluten", Status: "contains", Sources: []AllergenSource{{Ingredient: "plain flour", Reason: "ingredient"}, {Ingredient: "rolled oats", Reason: "cross-contact"}}},
+							{Label: "Milk", Status: "contains", Sources: []AllergenSource{{Ingredient: "butter <unsalted>", Reason: "ingredient"}}},
+							{Label: "Nuts", Status: "may-contain", Sources: []AllergenSource{{Ingredient: "almond milk", Reason: "substitute"}, {Ingredient: "toasted pecans", Reason: "optional"}}},
+						},
+					}),
+					NewAllergenPanelView(RecipeAllergens{RecipeID: "5e8f", Panel: "No major allergens found in the ingredients."}),
+				}
+			},
+		},
+		{
+			Name:        FragmentNutrition,
+			Template:    "fragments/recipe-nutrition",
+			Description: "Nutrition facts label for one serving, computed from the ingredients, with a note when some were left out",
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewNutritionLabelView(RecipeResponse{
+						ID:       "3f2a9c",
+						Servings: 4,
+						Nutrition: &RecipeNutrition{
+							Calories: 412, Protein: 28.4, Carbohydrates: 36.2, Fat: 17.5, Fiber: 6.1,
+							Sugar: 4.8, Sodium: 690, Cholesterol: 75, Coverage: 1,
+						},
+					}),
+					NewNutritionLabelView(RecipeResponse{
+						ID:        "7b1d",
+						Servings:  2,
+						Nutrition: &RecipeNutrition{Calories: 180, Protein: 4.2, Carbohydrates: 38, Fat: 0.6, Coverage: 0.67},
+					}),
+					NewNutritionLabelView(RecipeResponse{ID: "5e8f", Servings: 6}),
+				}
+			},
+		},
+		{
+			Name:        FragmentRecipeItems,
+			Template:    "fragments/recipe-ingredients",
+			Description: "Ingredient list at a serving count, with selectors that re-render it scaled or in other units",
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewRecipeIngredientsView(ScaledRecipe{
+						RecipeID:         "3f2a9c",
+						Servings:         4,
+						OriginalServings: 4,
+						Ingredients: []ScaledIngredient{
+							{Name: "plain flour", Quantity: "2 cup"},
+							{Name: "butter <unsalted>", Quantity: "6 tbsp", Notes: "softened"},
+							{Name: "toasted pecans", Quantity: "1/3 cup", Optional: true},
+							{Name: "salt", Notes: "to taste"},
+						},
+					}),
+					NewRecipeIngredientsView(ScaledRecipe{
+						RecipeID:         "7b1d",
+						Servings:         18,
+						OriginalServings: 6,
+						Units:            UnitsImperial,
+						Ingredients:      []ScaledIngredient{{Name: "beef chuck", Quantity: "6 lb"}},
+					}),
+					NewRecipeIngredientsView(ScaledRecipe{RecipeID: "5e8f", Servings: 2, OriginalServings: 2}),
+				}
+			},
+		},
+		{
+			Name:        FragmentSubstitutes,
+			Template:    "fragments/ingredient-substitutes",
+			Description: "Substitutes for an ingredient that suit the reader's diet, loaded by the ingredient list's toggle",
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewIngredientSubstitutesView(IngredientSubstitutes{
+						Ingredient: "eggs",
+						Substitutes: []IngredientSubstitute{
+							{Name: "flax egg", Ratio: "1 tbsp ground flaxseed plus 3 tbsp water per egg", Notes: "Rest 5 minutes to gel; binds but does not lift"},
+							{Name: "aquafaba", Ratio: "3 tbsp per egg"},
+							{Name: "black salt & tofu <scramble>", Suggested: true},
+						},
+						Hidden: 2,
+						Diets:  []string{"vegan", "gluten_free"},
+					}),
+					NewIngredientSubstitutesView(IngredientSubstitutes{Ingredient: "saffron"}),
+				}
+			},
+		},
+		{
+			Name:        FragmentAdminStats,
+			Template:    "fragments/admin-stats",
+			Description: "Admin dashboard totals for users, recipes and comments, with runtime figures",
+			Samples: func() []interface{} {
+				var busy SystemStats
+				busy.Users.Total, busy.Users.Active, busy.Users.Suspended, busy.Users.Admins, busy.Users.New = 1280, 1271, 9, 3, 42
+				busy.Recipes.Total, busy.Recipes.Draft, busy.Recipes.Published, busy.Recipes.Archived, busy.Recipes.AIGenerated, busy.Recipes.New = 5120, 610, 4380, 130, 920, 75
+				busy.Comments.Total, busy.Comments.Hidden = 8800, 4
+				busy.Runtime.StartedAt = time.Date(2026, 10, 18, 6, 0, 0, 0, time.UTC)
+				busy.Runtime.Uptime, busy.Runtime.GoVersion, busy.Runtime.Goroutines, busy.Runtime.HeapMB = "3h12m5s", "go1.23.4", 48, 37.5
+				return []interface{}{NewAdminStatsView(busy), NewAdminStatsView(SystemStats{})}
+			},
+		},
+		{
+			Name:        FragmentAdminUsers,
+			Template:    "fragments/admin-users",
+			Description: "Admin user list with search, status filter and suspend or reinstate buttons",
+			Interactive: true,
+			Samples: func() []interface{} {
+				joined := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+				return []interface{}{
+					NewAdminUsersView(AdminUserPage{Total: 60, Limit: 25, Users: []AdminUser{
+						{ID: "u1", Email: "ada@example.com", Name: "Ada", Role: "chef", Status: "active", Recipes: 12, CreatedAt: joined, LastLoginAt: &joined},
+						{ID: "u2", Email: "spam@example.com", Name: "<Spammer>", Role: "user", Status: "suspended", CreatedAt: joined},
+						{ID: "u3", Email: "grace@example.com", Name: "Grace", Role: "admin", Status: "active", Recipes: 2, CreatedAt: joined},
+					}}, "", "", 1, "sample-token"),
+					NewAdminUsersView(AdminUserPage{}, "nobody", "suspended", 1, "sample-token"),
+				}
+			},
+		},
+		{
+			Name:        FragmentAIContent,
+			Template:    "fragments/admin-ai-content",
+			Description: "AI-generated recipes with the prompt and model behind them, for admin review",
+			Interactive: true,
+			Samples: func() []interface{} {
+				created := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+				return []interface{}{
+					NewAIContentView(AIContentPage{Total: 2, Limit: 25, Recipes: []AIContent{
+						{ID: "r1", Title: "Robot Risotto", AuthorEmail: "ada@example.com", Status: "published", Model: "llama3.2:3b", Prompt: "A <creamy> risotto for two", CreatedAt: created},
+						{ID: "r2", Title: "Midnight Ramen", AuthorEmail: "sam@example.com", Status: "draft", Model: "mock", Prompt: "Ramen from pantry staples", CreatedAt: created},
+					}}, 1, "sample-token"),
+					NewAIContentView(AIContentPage{}, 1, "sample-token"),
+				}
+			},
+		},
+		{
+			Name:        FragmentSuggestion,
+			Template:    "fragments/guest-suggestion",
+			Description: "Recipe the AI chef suggested to a guest, with a button to save it to the guest session",
+			Interactive: true,
+			Samples: func() []interface{} {
+				return []interface{}{
+					GuestSuggestionView{
+						Title:        "Leek & Potato <Soup>",
+						Ingredients:  []string{"2 leeks, sliced", "500 g potatoes, diced", "1 l vegetable stock"},
+						MessagesLeft: 19,
+						CSRFToken:    "sample-token",
+					},
+					GuestSuggestionView{Title: "Plain Toast", MessagesLeft: 0, CSRFToken: "sample-token"},
+				}
+			},
+		},
+		{
+			Name:        FragmentNotifyList,
+			Template:    "fragments/notification-list",
+			Description: "Newest notifications, unread ones highlighted, with a mark all read button",
+			Interactive: true,
+			Samples: func() []interface{} {
+				created := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+				return []interface{}{
+					NewNotificationListView(NotificationPage{Unread: 1, Notifications: []Notification{
+						{ID: "n1", Type: "recipe_commented", Title: "Ada commented on <Lemon> tart", Message: "Lovely crust!", Link: "/recipes/r1", CreatedAt: created},
+						{ID: "n2", Type: "new_follower", Title: "Bo started following you", Read: true, CreatedAt: created.Add(-time.Hour)},
+					}}, "sample-token"),
+					NewNotificationListView(NotificationPage{}, "sample-token"),
+				}
+			},
+		},
+		{
+			Name:        FragmentNotifyPrefs,
+			Template:    "fragments/notification-prefs",
+			Description: "Profile form turning each notification type on or off",
+			Interactive: true,
+			Samples: func() []interface{} {
+				return []interface{}{
+					NotificationPrefsView{Preferences: []NotificationPreference{
+						{Type: "new_follower", Label: "New followers", Enabled: true},
+						{Type: "recipe_liked", Label: "Likes on my recipes", Enabled: false},
+					}, CSRFToken: "sample-token"},
+					NotificationPrefsView{Preferences: []NotificationPreference{
+						{Type: "ai_recipe_ready", Label: "AI recipes ready", Enabled: true},
+					}, Saved: true, CSRFToken: "sample-token"},
+				}
+			},
+		},
+		{
+			Name:        FragmentDietPrefs,
+			Template:    "fragments/dietary-prefs",
+			Description: "Profile form for the diets, allergens and disliked ingredients that shape search, AI recipes and card warnings",
+			Interactive: true,
+			Samples: func() []interface{} {
+				return []interface{}{
+					NewDietaryPrefsView(DietaryProfile{
+						DietaryRestrictions: []string{"vegan"},
+						Allergies:           []string{"peanuts", "sesame"},
+						DislikedIngredients: []string{"olives", "blue cheese"},
+					}, "sample-token"),
+					func() DietaryPrefsView {
+						view := NewDietaryPrefsView(DietaryProfile{}, "sample-token")
+						view.Saved = true
+						return view
+					}(),
+				}
+			},
+		},
+		{
+			Name:        FragmentDeletion,
+			Template:    "fragments/account-deletion",
+			Description: "Profile form that deletes the account after a grace period, and the pending deletion with its cancel button",
+			Interactive: true,
+			Samples: func() []interface{} {
+				due := time.Date(2026, 11, 17, 9, 30, 0, 0, time.UTC)
+				return []interface{}{
+					AccountDeletionView{CSRFToken: "sample-token"},
+					AccountDeletionView{Error: "That password is incorrect", CSRFToken: "sample-token"},
+					AccountDeletionView{Pending: true, ScheduledFor: due, CSRFToken: "sample-token"},
+					AccountDeletionView{Pending: true, ScheduledFor: due, SignedOut: true},
+					AccountDeletionView{Cancelled: true, CSRFToken: "sample-token"},
+				}
+			},
+		},
+		{
+			Name:        FragmentURLImport,
+			Template:    "fragments/url-import-preview",
+			Description: "Preview of the draft a recipe page would be imported as, with the button that saves it",
+			Interactive: true,
+			Samples: func() []interface{} {
+				preview := URLImportPreview{
+					SourceURL: "https://www.example.com/recipes/weeknight-dal",
+					Method:    "schema.org",
+					Recipe: EditableRecipe{
+						Title:        "Weeknight <Dal>",
+						Description:  "Red lentils simmered with turmeric",
+						Servings:     4,
+						PrepTime:     10,
+						CookTime:     25,
+						Ingredients:  []EditableIngredient{{Name: "red lentils", Amount: 1, Unit: "cup"}, {Name: "turmeric", Amount: 1, Unit: "tsp"}},
+						Instructions: []EditableStep{{Description: "Rinse the lentils."}, {Description: "Simmer with turmeric for 20 minutes."}},
+					},
+					Warnings: []string{`Skipped ingredient "a handful of love"`},
+				}
+				duplicate := preview
+				duplicate.Method = "heuristic"
+				duplicate.Warnings = nil
+				duplicate.DuplicateOf = "3f2a9c"
+				return []interface{}{
+					NewURLImportPreviewView("https://example.com/dal", preview, "sample-token"),
+					NewURLImportPreviewView("https://example.com/dal", duplicate, "sample-token"),
+				}
+			},
+		},
+		{
+			Name:        FragmentComments,
+			Template:    "fragments/comment-thread",
+			Description: "Recipe comments with one level of replies, inline post, reply, edit, delete and flag forms",
+			Interactive: true,
+			Samples: func() []interface{} {
+				created := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+				edited := created.Add(time.Hour)
+				return []interface{}{
+					NewCommentThreadView("r1", []Comment{
+						{ID: "c1", AuthorID: "u2", AuthorName: "Bo", Content: "Too <sour>?", Status: "visible", CreatedAt: created, Replies: []Comment{
+							{ID: "c2", AuthorID: "u1", AuthorName: "Ada", Content: "Add sugar.", Status: "visible", CreatedAt: created, EditedAt: &edited},
+							{ID: "c3", AuthorID: "u3", Status: "hidden", CreatedAt: created},
+						}},
+						{ID: "c4", AuthorID: "u2", Status: "deleted", CreatedAt: created, Replies: []Comment{
+							{ID: "c5", AuthorID: "u2", AuthorName: "Bo", Content: "Never mind.", Status: "visible", CreatedAt: created},
+						}},
+					}, "u1", "sample-token"),
+					NewCommentThreadView("r1", nil, "", ""),
+				}
+			},
+		},
+		{
+			Name:        FragmentFlagged,
+			Template:    "fragments/admin-flagged-comments",
+			Description: "Admin queue of flagged comments with hide and dismiss buttons",
+			Interactive: true,
+			Samples: func() []interface{} {
+				since := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+				return []interface{}{
+					NewFlaggedCommentsView([]FlaggedComment{
+						{Comment: Comment{ID: "c1", AuthorName: "<Spammer>", Content: "Buy pills"}, Flags: 3, Reasons: map[string]int{"spam": 2, "abuse": 1}, FirstFlaggedAt: since},
+					}, "sample-token"),
+					NewFlaggedCommentsView(nil, "sample-token"),
+				}
+			},
+		},
+		{
+			Name:        FragmentReports,
+			Template:    "fragments/admin-reports",
+			Description: "Admin queue of open recipe and comment reports with resolve and dismiss buttons",
+			Interactive: true,
+			Samples: func() []interface{} {
+				since := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+				return []interface{}{
+					NewReportsView(&ReportPage{Total: 2, Reports: []ContentReport{
+						{ID: "rp1", TargetType: "recipe", TargetID: "r1", RecipeID: "r1", Reason: "copyright", Note: "Copied from <a book>", OpenReports: 5, Unpublished: true, Summary: "Lemon tart", CreatedAt: since},
+						{ID: "rp2", TargetType: "comment", TargetID: "c1", RecipeID: "r1", Reason: "spam", OpenReports: 1, Summary: "Buy pills", CreatedAt: since},
+					}}, "sample-token"),
+					NewReportsView(nil, "sample-token"),
+				}
+			},
+		},
+		{
+			Name:        FragmentNotifyBadge,
+			Template:    "fragments/notification-badge",
+			Description: "Header bell with unread count, refreshed out-of-band",
+			Interactive: true,
+			Samples: func() []interface{} {
+				return []interface{}{
+					NotificationBadgeView{Unread: 0},
+					NotificationBadgeView{Unread: 120},
+				}
+			},
+		},
+	}
+}
+
+// Names returns registered fragment names in stable order
+func (fr *FragmentRegistry) Names() []string {
+	names := make([]string, 0, len(fr.specs))
+	for name := range fr.specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Spec returns the spec for a fragment name
+func (fr *FragmentRegistry) Spec(name string) (FragmentSpec, bool) {
+	spec, ok := fr.specs[name]
+	return spec, ok
+}
+
+// render executes a registered fragment; unexported so callers go through
+// the typed Render* functions
+func (fr *FragmentRegistry) render(w io.Writer, name string, data interface{}) error {
+	spec, ok := fr.specs[name]
+	if !ok {
+		return fmt.Errorf("unknown fragment %s", name)
+	}
+	return fr.templates.ExecuteTemplate(w, spec.Template, data)
+}
+
+// RenderRecipeCard renders the recipe-card fragment
+func (fr *FragmentRegistry) RenderRecipeCard(w io.Writer, v RecipeCardView) error {
+	return fr.render(w, FragmentRecipeCard, v)
+}
+
+// RenderLikeButton renders the like-button fragment
+func (fr *FragmentRegistry) RenderLikeButton(w io.Writer, v LikeButtonView) error {
+	return fr.render(w, FragmentLikeButton, v)
+}
+
+// RenderChatMessage renders the chat-message fragment
+func (fr *FragmentRegistry) RenderChatMessage(w io.Writer, v ChatMessageView) error {
+	return fr.render(w, FragmentChatMessage, v)
+}
+
+// RenderDashboardStats renders the dashboard-stats fragment
+func (fr *FragmentRegistry) RenderDashboardStats(w io.Writer, v DashboardStatsView) error {
+	return fr.render(w, FragmentDashStats, v)
+}
+
+// RenderNotificationBadge renders the notification-badge fragment
+func (fr *FragmentRegistry) RenderNotificationBadge(w io.Writer, v NotificationBadgeView) error {
+	return fr.render(w, FragmentNotifyBadge, v)
+}
+
+// RenderNotificationList renders the notification-list fragment
+func (fr *FragmentRegistry) RenderNotificationList(w io.Writer, v NotificationListView) error {
+	return fr.render(w, FragmentNotifyList, v)
+}
+
+// RenderNotificationPrefs renders the notification-prefs fragment
+func (fr *FragmentRegistry) RenderNotificationPrefs(w io.Writer, v NotificationPrefsView) error {
+	return fr.render(w, FragmentNotifyPrefs, v)
+}
+
+// RenderDietaryPrefs renders the dietary-prefs fragment
+func (fr *FragmentRegistry) RenderDietaryPrefs(w io.Writer, v DietaryPrefsView) error {
+	return fr.render(w, FragmentDietPrefs, v)
+}
+
+// RenderAccountDeletion renders the account-deletion fragment
+func (fr *FragmentRegistry) RenderAccountDeletion(w io.Writer, v AccountDeletionView) error {
+	return fr.render(w, FragmentDeletion, v)
+}
+
+// RenderURLImportPreview renders the url-import-preview fragment
+func (fr *FragmentRegistry) RenderURLImportPreview(w io.Writer, v URLImportPreviewView) error {
+	return fr.render(w, FragmentURLImport, v)
+}
+
+// RenderCommentThread renders the comment-thread fragment
+func (fr *FragmentRegistry) RenderCommentThread(w io.Writer, v CommentThreadView) error {
+	return fr.render(w, FragmentComments, v)
+}
+
+// RenderFlaggedComments renders the admin-flagged-comments fragment
+func (fr *FragmentRegistry) RenderFlaggedComments(w io.Writer, v FlaggedCommentsView) error {
+	return fr.render(w, FragmentFlagged, v)
+}
+
+// RenderReports renders the admin-reports fragment
+func (fr *FragmentRegistry) RenderReports(w io.Writer, v ReportsView) error {
+	return fr.render(w, FragmentReports, v)
+}
+
+// RenderThemeToggle renders the theme-toggle fragment
+func (fr *FragmentRegistry) RenderThemeToggle(w io.Writer, v ThemeToggleView) error {
+	return fr.render(w, FragmentThemeToggle, v)
+}
+
+// RenderWizardStep renders the wizard-step fragment
+func (fr *FragmentRegistry) RenderWizardStep(w io.Writer, v WizardStepView) error {
+	return fr.render(w, FragmentWizardStep, v)
+}
+
+// RenderUndoToast renders the undo-toast fragment
+func (fr *FragmentRegistry) RenderUndoToast(w io.Writer, v UndoToastView) error {
+	return fr.render(w, FragmentUndoToast, v)
+}
+
+// RenderSearchFallback renders the search-fallback fragment
+func (fr *FragmentRegistry) RenderSearchFallback(w io.Writer, v SearchFallbackView) error {
+	return fr.render(w, FragmentSearchMiss, v)
+}
+
+// RenderSearchFacets renders the search-facets fragment
+func (fr *FragmentRegistry) RenderSearchFacets(w io.Writer, v SearchFacetsView) error {
+	return fr.render(w, FragmentFacets, v)
+}
+
+// RenderIngredientPreview renders the ingredient-preview fragment
+func (fr *FragmentRegistry) RenderIngredientPreview(w io.Writer, v IngredientPreviewView) error {
+	return fr.render(w, FragmentIngredients, v)
+}
+
+// RenderSparkline renders the sparkline fragment
+func (fr *FragmentRegistry) RenderSparkline(w io.Writer, v SparklineView) error {
+	return fr.render(w, FragmentSparkline, v)
+}
+
+// RenderAuthorAnalytics renders the author-analytics fragment
+func (fr *FragmentRegistry) RenderAuthorAnalytics(w io.Writer, v AuthorAnalyticsView) error {
+	return fr.render(w, FragmentAuthorStats, v)
+}
+
+// RenderRecipeStats renders the recipe-stats fragment
+func (fr *FragmentRegistry) RenderRecipeStats(w io.Writer, v RecipeStatsView) error {
+	return fr.render(w, FragmentRecipeStats, v)
+}
+
+// RenderRecipeRelated renders the recipe-related fragment
+func (fr *FragmentRegistry) RenderRecipeRelated(w io.Writer, v RecipeRelatedView) error {
+	return fr.render(w, FragmentRelated, v)
+}
+
+// RenderGraphRecipes renders the graph-recipes fragment
+func (fr *FragmentRegistry) RenderGraphRecipes(w io.Writer, v GraphRecipesView) error {
+	return fr.render(w, FragmentGraphList, v)
+}
+
+// RenderTechnique renders the technique fragment
+func (fr *FragmentRegistry) RenderTechnique(w io.Writer, v TechniqueView) error {
+	return fr.render(w, FragmentTechnique, v)
+}
+
+// RenderCookSteps renders the cook-steps fragment
+func (fr *FragmentRegistry) RenderCookSteps(w io.Writer, v CookStepsView) error {
+	return fr.render(w, FragmentCookSteps, v)
+}
+
+// RenderTimeline renders the recipe-timeline fragment
+func (fr *FragmentRegistry) RenderTimeline(w io.Writer, v TimelineView) error {
+	return fr.render(w, FragmentTimeline, v)
+}
+
+// RenderBattle renders the remix-battle fragment
+func (fr *FragmentRegistry) RenderBattle(w io.Writer, v BattleView) error {
+	return fr.render(w, FragmentBattle, v)
+}
+
+// RenderRecipePage renders the recipe-page fragment
+func (fr *FragmentRegistry) RenderRecipePage(w io.Writer, v RecipePageView) error {
+	return fr.render(w, FragmentRecipePage, v)
+}
+
+// RenderRecipeSimilar renders the recipe-similar fragment
+func (fr *FragmentRegistry) RenderRecipeSimilar(w io.Writer, v RecipeSimilarView) error {
+	return fr.render(w, FragmentSimilar, v)
+}
+
+// RenderHomeSection renders the home-section fragment
+func (fr *FragmentRegistry) RenderHomeSection(w io.Writer, v HomeSectionView) error {
+	return fr.render(w, FragmentHomeSection, v)
+}
+
+// RenderRecipeEdit renders the recipe-edit fragment
+func (fr *FragmentRegistry) RenderRecipeEdit(w io.Writer, v RecipeEditView) error {
+	return fr.render(w, FragmentRecipeEdit, v)
+}
+
+// RenderAllergens renders the recipe-allergens fragment
+func (fr *FragmentRegistry) RenderAllergens(w io.Writer, v AllergenPanelView) error {
+	return fr.render(w, FragmentAllergens, v)
+}
+
+// RenderNutrition renders the recipe-nutrition fragment
+func (fr *FragmentRegistry) RenderNutrition(w io.Writer, v NutritionLabelView) error {
+	return fr.render(w, FragmentNutrition, v)
+}
+
+// RenderRecipeIngredients renders the recipe-ingredients fragment
+func (fr *FragmentRegistry) RenderRecipeIngredients(w io.Writer, v RecipeIngredientsView) error {
+	return fr.render(w, FragmentRecipeItems, v)
+}
+
+// RenderSubstitutes renders the ingredient-substitutes fragment
+func (fr *FragmentRegistry) RenderSubstitutes(w io.Writer, v IngredientSubstitutesView) error {
+	return fr.render(w, FragmentSubstitutes, v)
+}
+
+// RenderAdminStats renders the admin-stats fragment
+func (fr *FragmentRegistry) RenderAdminStats(w io.Writer, v AdminStatsView) error {
+	return fr.render(w, FragmentAdminStats, v)
+}
+
+// RenderAdminUsers renders the admin-users fragment
+func (fr *FragmentRegistry) RenderAdminUsers(w io.Writer, v AdminUsersView) error {
+	return fr.render(w, FragmentAdminUsers, v)
+}
+
+// RenderAIContent renders the admin-ai-content fragment
+func (fr *FragmentRegistry) RenderAIContent(w io.Writer, v AIContentView) error {
+	return fr.render(w, FragmentAIContent, v)
+}
+
+// RenderGuestSuggestion renders the guest-suggestion fragment
+func (fr *FragmentRegistry) RenderGuestSuggestion(w io.Writer, v GuestSuggestionView) error {
+	return fr.render(w, FragmentSuggestion, v)
+}
+
+// RenderSample renders a sample view model by fragment name (gallery/tests)
+func (fr *FragmentRegistry) RenderSample(w io.Writer, name string, sample interface{}) error {
+	return fr.render(w, name, sample)
+}
+
+// handleFragmentGallery serves /dev/fragments: every registered fragment
+// rendered with its sample view models so markup changes are reviewable
+func (s *WebServer) handleFragmentGallery(w http.ResponseWriter, r *http.Request) {
+	var page bytes.Buffer
+	page.WriteString(`<!DOCTYPE html><html lang="en"><head><meta charset="UTF-8"><title>Fragment Gallery | Alchemorsel</title>` +
+		`<link rel="stylesheet" href="/static/css/main.css"></head><body style="font-family: sans-serif; padding: 2rem;">` +
+		`<h1>HTMX Fragment Gallery</h1>`)
+
+	for _, name := range s.fragments.Names() {
+		spec, _ := s.fragments.Spec(name)
+		fmt.Fprintf(&page, `<section id="%s" style="margin-bottom: 2rem;"><h2>%s</h2><p>%s</p>`,
+			template.HTMLEscapeString(name), template.HTMLEscapeString(name), template.HTMLEscapeString(spec.Description))
+
+		for i, sample := range spec.Samples() {
+			fmt.Fprintf(&page, `<div style="border: 1px dashed #cbd5e0; padding: 1rem; margin: 0.5rem 0;"><small>sample %d</small>`, i+1)
+			if err := s.fragments.RenderSample(&page, name, sample); err != nil {
+				fmt.Fprintf(&page, `<pre class="error">%s</pre>`, template.HTMLEscapeString(err.Error()))
+			}
+			page.WriteString(`</div>`)
+		}
+		page.WriteString(`</section>`)
+	}
+	page.WriteString(`</body></html>`)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page.Bytes())
+}
+
+// ariaLabel renders an aria-label attribute and fails template execution when
+// the label is blank, so interactive fragments can't ship unnamed controls
+func ariaLabel(label string) (template.HTMLAttr, error) {
+	if strings.TrimSpace(label) == "" {
+		return "", fmt.Errorf("aria-label is required")
+	}
+	return template.HTMLAttr(`aria-label="` + template.HTMLEscapeString(label) + `"`), nil
+}
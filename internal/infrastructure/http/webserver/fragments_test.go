@@ -0,0 +1,85 @@
+package webserver
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/a11y"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite fragment golden files")
+
+// TestFragmentGolden renders every registered fragment sample and compares it
+// with testdata/fragments. Run with -update after an intentional markup change.
+func TestFragmentGolden(t *testing.T) {
+	templates, err := parseTemplates()
+	require.NoError(t, err)
+
+	registry, err := NewFragmentRegistry(NewTemplateRenderer(templates, 0, false, zap.NewNop()))
+	require.NoError(t, err)
+
+	for _, name := range registry.Names() {
+		spec, _ := registry.Spec(name)
+		for i, sample := range spec.Samples() {
+			t.Run(fmt.Sprintf("%s/%d", name, i+1), func(t *testing.T) {
+				var buf bytes.Buffer
+				require.NoError(t, registry.RenderSample(&buf, name, sample))
+
+				golden := filepath.Join("testdata", "fragments", fmt.Sprintf("%s-%d.golden", name, i+1))
+				if *updateGolden {
+					require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755))
+					require.NoError(t, os.WriteFile(golden, buf.Bytes(), 0o644))
+				}
+
+				want, err := os.ReadFile(golden)
+				require.NoError(t, err, "missing golden file, run go test -run TestFragmentGolden -update")
+				assert.Equal(t, string(want), buf.String())
+			})
+		}
+	}
+}
+
+func TestFragmentsEscapeUserContent(t *testing.T) {
+	templates, err := parseTemplates()
+	require.NoError(t, err)
+
+	registry, err := NewFragmentRegistry(NewTemplateRenderer(templates, 0, false, zap.NewNop()))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.RenderChatMessage(&buf, NewChatMessageView(true, "You", "<script>alert(1)</script>", "now")))
+	assert.NotContains(t, buf.String(), "<script>")
+	assert.Contains(t, buf.String(), "&lt;script&gt;")
+}
+
+// TestFragmentsAccessible is the template lint pass: every sample must be
+// free of WCAG findings, and blank aria labels must fail rendering
+func TestFragmentsAccessible(t *testing.T) {
+	templates, err := parseTemplates()
+	require.NoError(t, err)
+
+	registry, err := NewFragmentRegistry(NewTemplateRenderer(templates, 0, false, zap.NewNop()))
+	require.NoError(t, err)
+
+	for _, name := range registry.Names() {
+		spec, _ := registry.Spec(name)
+		for i, sample := range spec.Samples() {
+			var buf bytes.Buffer
+			require.NoError(t, registry.RenderSample(&buf, name, sample))
+
+			issues, err := a11y.CheckHTML(fmt.Sprintf("%s/%d", name, i+1), &buf)
+			require.NoError(t, err)
+			assert.Empty(t, issues)
+		}
+	}
+
+	_, err = ariaLabel("  ")
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+// Package webserver provides the related recipe sections built from the
+// recipe graph
+package webserver
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	// relatedLimit is the related recipes shown under a recipe
+	relatedLimit = 6
+	// similarLimit is the similar recipes shown beside a recipe
+	similarLimit = 6
+	// graphRecipesLimit is the recipes listed for a technique or cuisine
+	graphRecipesLimit = 12
+)
+
+// handleRecipeRelated serves /recipes/{id}/related. The recipe page loads
+// it with hx-get; without HTMX it is a page of its own.
+func (s *WebServer) handleRecipeRelated(w http.ResponseWriter, r *http.Request) {
+	recipeID := chi.URLParam(r, "id")
+
+	graph, err := s.apiClient.GetRecipeGraph(r.Context(), recipeID)
+	var related []GraphRecipe
+	if err == nil {
+		related, err = s.apiClient.GetRelatedRecipes(r.Context(), recipeID, relatedLimit)
+	}
+	if err != nil {
+		s.graphUnavailable(w, r, "Related recipes unavailable", err)
+		return
+	}
+
+	view := NewRecipeRelatedView(*graph, related)
+	s.renderGraph(w, r, "Related recipes - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderRecipeRelated(buf, view)
+	})
+}
+
+// handleRecipeSimilar serves /recipes/{id}/similar, the sidebar of the
+// recipe page
+func (s *WebServer) handleRecipeSimilar(w http.ResponseWriter, r *http.Request) {
+	recipeID := chi.URLParam(r, "id")
+
+	similar, err := s.apiClient.GetSimilarRecipes(r.Context(), recipeID, similarLimit)
+	if err != nil {
+		s.graphUnavailable(w, r, "Similar recipes unavailable", err)
+		return
+	}
+
+	view := NewRecipeSimilarView(recipeID, similar)
+	s.renderGraph(w, r, "Similar recipes - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderRecipeSimilar(buf, view)
+	})
+}
+
+// handleGraphRecipes serves /graph/{kind}/{key}: the other recipes using a
+// technique, cuisine or ingredient
+func (s *WebServer) handleGraphRecipes(w http.ResponseWriter, r *http.Request) {
+	recipes, err := s.apiClient.GetGraphNodeRecipes(r.Context(), chi.URLParam(r, "kind"), chi.URLParam(r, "key"), graphRecipesLimit)
+	if err != nil {
+		s.graphUnavailable(w, r, "Graph recipes unavailable", err)
+		return
+	}
+
+	view := NewGraphRecipesView(*recipes)
+	s.renderGraph(w, r, view.Heading()+" - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderGraphRecipes(buf, view)
+	})
+}
+
+// renderGraph sends just the fragment to HTMX and wraps it in a page
+// otherwise
+func (s *WebServer) renderGraph(w http.ResponseWriter, r *http.Request, title string, render func(buf *bytes.Buffer) error) {
+	if r.Header.Get("HX-Request") == "true" {
+		s.renderFragment(w, render)
+		return
+	}
+
+	var content bytes.Buffer
+	if err := render(&content); err != nil {
+		s.renderError(w, "Failed to render recipes", err)
+		return
+	}
+	session, _ := r.Context().Value("session").(*Session)
+	s.renderTemplate(w, "graph", map[string]interface{}{
+		"Title":   title,
+		"Theme":   sessionTheme(session),
+		"Content": template.HTML(content.String()),
+	})
+}
+
+func (s *WebServer) graphUnavailable(w http.ResponseWriter, r *http.Request, message string, err error) {
+	if r.Header.Get("HX-Request") == "true" {
+		s.logger.Error(message, zap.String("path", r.URL.Path), zap.Error(err))
+		w.Write([]byte("<div class=\"error\">These recipes are unavailable right now. Please try again.</div>"))
+		return
+	}
+	s.renderError(w, message, err)
+}
@@ -0,0 +1,169 @@
+// Package webserver provides guest mode: the AI chef for visitors without
+// an account, and moving their saved recipes over when they sign up
+package webserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Session data keys for guest mode. The suggestion is kept as JSON so the
+// save button only ever saves what the AI chef suggested.
+const (
+	sessionKeyGuestToken      = "guest_token"
+	sessionKeyGuestSuggestion = "guest_suggestion"
+)
+
+// handleGuestChat answers a guest's question on /ai/chat, starting their
+// guest session on the first message
+func (s *WebServer) handleGuestChat(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	message := strings.TrimSpace(r.FormValue("message"))
+	if message == "" || len(message) > 1000 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`<div class="error">Ask a question of at most 1000 characters</div>`))
+		return
+	}
+
+	reply, err := s.guestChat(r, session, message)
+	if err != nil {
+		text := "The AI chef is unavailable right now. Please try again."
+		switch {
+		case isAPIStatus(err, http.StatusTooManyRequests):
+			text = "You've used your guest messages. Sign up to keep cooking with the AI chef."
+		case isAPIStatus(err, http.StatusForbidden):
+			text = "Guest mode is off. Sign in to chat with the AI chef."
+		default:
+			s.logger.Error("Guest chat failed", zap.Error(err))
+		}
+		s.renderFragment(w, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderChatMessage(buf, NewChatMessageView(false, "AI Chef", text, "Just now"))
+		})
+		return
+	}
+
+	if reply.Suggestion != nil {
+		if suggestion, err := json.Marshal(reply.Suggestion); err == nil {
+			session.SetValue(sessionKeyGuestSuggestion, string(suggestion))
+		}
+	}
+	session.Save(w)
+
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		if err := s.fragments.RenderChatMessage(buf, NewChatMessageView(true, "You", message, "Just now")); err != nil {
+			return err
+		}
+		if err := s.fragments.RenderChatMessage(buf, NewChatMessageView(false, "AI Chef", reply.Reply, "Just now")); err != nil {
+			return err
+		}
+		if reply.Suggestion == nil {
+			return nil
+		}
+		return s.fragments.RenderGuestSuggestion(buf, GuestSuggestionView{
+			Title:        reply.Suggestion.Title,
+			Ingredients:  reply.Suggestion.Ingredients,
+			MessagesLeft: reply.MessagesLeft,
+			CSRFToken:    s.generateCSRFToken(session.ID),
+		})
+	})
+}
+
+// guestChat sends the message with the session's guest token, starting a
+// new guest session when there is none or the old one expired
+func (s *WebServer) guestChat(r *http.Request, session *Session, message string) (*GuestChatReply, error) {
+	token, _ := session.GetValue(sessionKeyGuestToken)
+	if guestToken, ok := token.(string); ok && guestToken != "" {
+		reply, err := s.apiClient.GuestChat(r.Context(), guestToken, message)
+		if !isAPIStatus(err, http.StatusUnauthorized) {
+			return reply, err
+		}
+	}
+
+	started, err := s.apiClient.StartGuestSession(r.Context(), visitorIP(r))
+	if err != nil {
+		return nil, err
+	}
+	session.SetValue(sessionKeyGuestToken, started.Token)
+	return s.apiClient.GuestChat(r.Context(), started.Token, message)
+}
+
+// handleGuestSaveRecipe keeps the AI chef's last suggestion in the guest
+// session and answers with a toast
+func (s *WebServer) handleGuestSaveRecipe(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	toast := func(message string) {
+		s.renderFragment(w, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderUndoToast(buf, UndoToastView{Message: message})
+		})
+	}
+
+	token, _ := session.GetValue(sessionKeyGuestToken)
+	guestToken, _ := token.(string)
+	raw, _ := session.GetValue(sessionKeyGuestSuggestion)
+	suggestion, _ := raw.(string)
+	var recipe GuestRecipeDraft
+	if guestToken == "" || suggestion == "" || json.Unmarshal([]byte(suggestion), &recipe) != nil {
+		toast("Ask the AI chef for a recipe first")
+		return
+	}
+
+	err := s.apiClient.SaveGuestRecipe(r.Context(), guestToken, recipe)
+	switch {
+	case err == nil:
+		delete(session.Data, sessionKeyGuestSuggestion)
+		session.Save(w)
+		toast("Saved! Sign up to keep it for good.")
+	case isAPIStatus(err, http.StatusTooManyRequests):
+		toast("Your guest recipe box is full. Sign up to save more.")
+	case isAPIStatus(err, http.StatusUnauthorized):
+		toast("Your guest session has expired")
+	default:
+		s.logger.Error("Failed to save guest recipe", zap.Error(err))
+		toast("The recipe could not be saved. Please try again.")
+	}
+}
+
+// claimGuestRecipes moves the session's guest recipes to the account that
+// just signed in. A failure never blocks signing in; the token is kept
+// only when the API could not be reached, so the next sign-in retries.
+func (s *WebServer) claimGuestRecipes(ctx context.Context, session *Session) {
+	token, _ := session.GetValue(sessionKeyGuestToken)
+	guestToken, _ := token.(string)
+	if guestToken == "" {
+		return
+	}
+
+	claimed, err := s.apiClient.ClaimGuestRecipes(ctx, session.AccessToken, guestToken)
+	if err != nil {
+		s.logger.Warn("Failed to claim guest recipes", zap.String("user_id", session.UserID), zap.Error(err))
+		var statusErr *APIStatusError
+		if !errors.As(err, &statusErr) || statusErr.Status >= http.StatusInternalServerError {
+			return
+		}
+	} else if claimed.Claimed > 0 {
+		s.logger.Info("Guest recipes claimed", zap.String("user_id", session.UserID), zap.Int("recipes", claimed.Claimed))
+	}
+	delete(session.Data, sessionKeyGuestToken)
+	delete(session.Data, sessionKeyGuestSuggestion)
+}
+
+// visitorIP is the address the visitor connected from, as the rate limiter
+// sees it
+func visitorIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		return strings.TrimSpace(strings.Split(xff, ",")[0])
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,370 @@
+// Package webserver provides the home page, composed from sections
+package webserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/graph"
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/pkg/canary"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Home page sections. web.home.sections lists them in page order.
+const (
+	HomeHero            = "hero"
+	HomeContinueCooking = "continue-cooking"
+	HomeTrending        = "trending"
+	HomeChefActivity    = "chef-activity"
+	HomeSeasonal        = "seasonal"
+	HomeSaved           = "saved"
+	HomeRecommended     = "recommended"
+)
+
+const (
+	// homeSectionRecipes is the recipes listed in a section
+	homeSectionRecipes = 8
+	// seasonalLookups bounds the ingredients whose recipes fill the
+	// seasonal hub
+	seasonalLookups = 3
+	// maxHomeCacheEntries bounds the rendered sections kept, most of
+	// which are personal
+	maxHomeCacheEntries = 10000
+)
+
+// homeSection is one block of the home page. The hero is drawn by the
+// shell so the page's first paint needs no API call; every other section is
+// loaded when it scrolls into view.
+type homeSection struct {
+	// personal sections are built for the signed-in user, so anonymous
+	// visitors never see them and they are cached per user
+	personal bool
+	// enabled gates the section on feature flags; nil means always on
+	enabled func(config.FeatureFlags) bool
+	load    func(s *WebServer, ctx context.Context, token string) (HomeSectionView, error)
+}
+
+var homeSections = map[string]homeSection{
+	HomeHero:            {},
+	HomeTrending:        {load: (*WebServer).loadTrending},
+	HomeSeasonal:        {load: (*WebServer).loadSeasonal},
+	HomeContinueCooking: {personal: true, load: (*WebServer).loadContinueCooking},
+	HomeSaved:           {personal: true, load: (*WebServer).loadSaved},
+	HomeRecommended:     {personal: true, load: (*WebServer).loadRecommended},
+	HomeChefActivity: {
+		personal: true,
+		enabled:  func(f config.FeatureFlags) bool { return f.EnableSocialFeatures },
+		load:     (*WebServer).loadChefActivity,
+	},
+}
+
+// HomeLayout decides which sections a visitor sees, in which order, and
+// keeps each rendered section until it expires
+type HomeLayout struct {
+	order       []string
+	flags       config.FeatureFlags
+	experiments map[string]canary.Rule
+	sharedTTL   time.Duration
+	personalTTL time.Duration
+	now         func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedSection
+}
+
+// cachedSection is a rendered section and when it goes stale
+type cachedSection struct {
+	html      []byte
+	expiresAt time.Time
+}
+
+// NewHomeLayout builds the layout from web.home and the feature flags
+func NewHomeLayout(cfg *config.Config) *HomeLayout {
+	layout := &HomeLayout{
+		flags:       cfg.Features,
+		experiments: make(map[string]canary.Rule),
+		sharedTTL:   cfg.Web.Home.CacheTTL,
+		personalTTL: cfg.Web.Home.PersonalCacheTTL,
+		now:         time.Now,
+		cache:       make(map[string]cachedSection),
+	}
+	for _, name := range cfg.Web.Home.Sections {
+		if _, ok := homeSections[name]; ok {
+			layout.order = append(layout.order, name)
+		}
+	}
+	for _, experiment := range cfg.Web.Home.Experiments {
+		layout.experiments[experiment.Section] = canary.Rule{Percent: experiment.Percent, Cohorts: experiment.Cohorts}
+	}
+	return layout
+}
+
+// Sections returns the sections userID sees, in page order. userID is
+// empty for anonymous visitors.
+func (l *HomeLayout) Sections(userID string) []string {
+	var names []string
+	for _, name := range l.order {
+		if l.Visible(name, userID) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Visible reports whether userID sees a section. Sections under an
+// experiment are shown only to the signed-in users its rule picks.
+func (l *HomeLayout) Visible(name, userID string) bool {
+	section, ok := homeSections[name]
+	if !ok || !l.listed(name) {
+		return false
+	}
+	if section.personal && userID == "" {
+		return false
+	}
+	if section.enabled != nil && !section.enabled(l.flags) {
+		return false
+	}
+	if rule, ok := l.experiments[name]; ok {
+		return userID != "" && rule.Includes("home-"+name, userID)
+	}
+	return true
+}
+
+func (l *HomeLayout) listed(name string) bool {
+	for _, listed := range l.order {
+		if listed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheKey scopes personal sections to their user
+func (l *HomeLayout) cacheKey(name, userID string) string {
+	if homeSections[name].personal {
+		return name + ":" + userID
+	}
+	return name
+}
+
+// cached returns a rendered section that has not expired
+func (l *HomeLayout) cached(name, userID string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, ok := l.cache[l.cacheKey(name, userID)]
+	if !ok || !l.now().Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.html, true
+}
+
+// store keeps a rendered section for its TTL. When the cache is full,
+// expired entries are dropped first; if none are, the section is not kept.
+func (l *HomeLayout) store(name, userID string, html []byte) {
+	ttl := l.sharedTTL
+	if homeSections[name].personal {
+		ttl = l.personalTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.cache) >= maxHomeCacheEntries {
+		for key, entry := range l.cache {
+			if !now.Before(entry.expiresAt) {
+				delete(l.cache, key)
+			}
+		}
+		if len(l.cache) >= maxHomeCacheEntries {
+			return
+		}
+	}
+	l.cache[l.cacheKey(name, userID)] = cachedSection{html: html, expiresAt: now.Add(ttl)}
+}
+
+// handleHome serves the home page shell: the hero, and a placeholder for
+// each other section the visitor sees
+func (s *WebServer) handleHome(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	userID := ""
+	if session.UserID != "" && session.AccessToken != "" {
+		if s.apiClient.VerifyToken(r.Context(), session.AccessToken) {
+			userID = session.UserID
+		} else {
+			// Token invalid, clear session
+			session.Clear()
+			session.Save(w)
+		}
+	}
+
+	s.renderTemplate(w, "home", map[string]interface{}{
+		"Title":    "Welcome to Alchemorsel",
+		"Theme":    sessionTheme(session),
+		"SignedIn": userID != "",
+		"Sections": s.home.Sections(userID),
+	})
+}
+
+// handleHomeSection serves /home/sections/{name}. The home page loads each
+// section with hx-get; without HTMX it is a page of its own. A section
+// the visitor does not see renders empty, so its placeholder goes away.
+func (s *WebServer) handleHomeSection(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	session, _ := r.Context().Value("session").(*Session)
+	userID := ""
+	if session != nil && session.AccessToken != "" {
+		userID = session.UserID
+	}
+
+	section := homeSections[name]
+	if section.load == nil || !s.home.Visible(name, userID) {
+		if r.Header.Get("HX-Request") == "true" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	html, ok := s.home.cached(name, userID)
+	if !ok {
+		view, err := section.load(s, r.Context(), sessionToken(r))
+		if err != nil {
+			s.graphUnavailable(w, r, "Home section unavailable", err)
+			return
+		}
+		var buf bytes.Buffer
+		if err := s.fragments.RenderHomeSection(&buf, view); err != nil {
+			s.logger.Error("Failed to render home section", zap.String("section", name), zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`<div class="error">Something went wrong. Please try again.</div>`))
+			return
+		}
+		html = buf.Bytes()
+		s.home.store(name, userID, html)
+	}
+
+	s.renderGraph(w, r, "Alchemorsel", func(buf *bytes.Buffer) error {
+		_, err := buf.Write(html)
+		return err
+	})
+}
+
+func (s *WebServer) loadTrending(ctx context.Context, token string) (HomeSectionView, error) {
+	recipes, err := s.apiClient.GetTrendingRecipes(ctx)
+	if err != nil {
+		return HomeSectionView{}, err
+	}
+	if len(recipes) > homeSectionRecipes {
+		recipes = recipes[:homeSectionRecipes]
+	}
+	s.hydrateAuthors(ctx, token, recipes)
+	return NewHomeSectionView(HomeTrending, "Trending now", "Nothing is trending yet.", recipes), nil
+}
+
+func (s *WebServer) loadContinueCooking(ctx context.Context, token string) (HomeSectionView, error) {
+	recipes, err := s.apiClient.GetRecentlyViewedRecipes(ctx, token, homeSectionRecipes)
+	if err != nil {
+		return HomeSectionView{}, err
+	}
+	s.hydrateAuthors(ctx, token, recipes)
+	return NewHomeSectionView(HomeContinueCooking, "Continue cooking", "Recipes you open will show up here.", recipes), nil
+}
+
+func (s *WebServer) loadSaved(ctx context.Context, token string) (HomeSectionView, error) {
+	recipes, err := s.apiClient.GetFavoriteRecipes(ctx, token)
+	if err != nil {
+		return HomeSectionView{}, err
+	}
+	if len(recipes) > homeSectionRecipes {
+		recipes = recipes[:homeSectionRecipes]
+	}
+	s.hydrateAuthors(ctx, token, recipes)
+	return NewHomeSectionView(HomeSaved, "Saved recipes", "Save a recipe to find it here.", recipes), nil
+}
+
+func (s *WebServer) loadRecommended(ctx context.Context, token string) (HomeSectionView, error) {
+	recommended, err := s.apiClient.GetRecommendedRecipes(ctx, token)
+	if err != nil {
+		return HomeSectionView{}, err
+	}
+	if len(recommended.Recipes) > homeSectionRecipes {
+		recommended.Recipes = recommended.Recipes[:homeSectionRecipes]
+	}
+	s.hydrateAuthors(ctx, token, recommended.Recipes)
+	return NewRecommendedSectionView(recommended), nil
+}
+
+func (s *WebServer) loadChefActivity(ctx context.Context, token string) (HomeSectionView, error) {
+	recipes, err := s.apiClient.GetChefActivity(ctx, token, homeSectionRecipes)
+	if err != nil {
+		return HomeSectionView{}, err
+	}
+	s.hydrateAuthors(ctx, token, recipes)
+	return NewHomeSectionView(HomeChefActivity, "New from chefs you like", "Like a few recipes to hear what their chefs cook next.", recipes), nil
+}
+
+// loadSeasonal links this month's produce to its recipes and lists recipes
+// using the first few of them
+func (s *WebServer) loadSeasonal(ctx context.Context, token string) (HomeSectionView, error) {
+	month := time.Now().Month()
+	view := HomeSectionView{
+		Name:  HomeSeasonal,
+		Title: fmt.Sprintf("In season in %s", month),
+		Empty: "No recipes with these yet.",
+	}
+	for _, name := range inSeason[month] {
+		view.Ingredients = append(view.Ingredients, GraphNode{Kind: string(graph.Ingredient), Key: graph.IngredientKey(name), Label: name})
+	}
+
+	seen := make(map[string]bool)
+	var lastErr error
+	for i, node := range view.Ingredients {
+		if i == seasonalLookups || len(view.Recipes) >= homeSectionRecipes {
+			break
+		}
+		found, err := s.apiClient.GetGraphNodeRecipes(ctx, node.Kind, node.Key, homeSectionRecipes)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, recipe := range found.Recipes {
+			if seen[recipe.ID] || len(view.Recipes) >= homeSectionRecipes {
+				continue
+			}
+			seen[recipe.ID] = true
+			view.Recipes = append(view.Recipes, RecipeCardView{ID: recipe.ID, Title: recipe.Title, Rating: recipe.AverageRating})
+		}
+	}
+	if len(view.Recipes) == 0 && lastErr != nil {
+		return HomeSectionView{}, lastErr
+	}
+	return view, nil
+}
+
+// inSeason is produce at its best each month in temperate northern
+// kitchens
+var inSeason = map[time.Month][]string{
+	time.January:   {"Leeks", "Kale", "Blood oranges", "Parsnips", "Brussels sprouts"},
+	time.February:  {"Leeks", "Cauliflower", "Blood oranges", "Rhubarb", "Celeriac"},
+	time.March:     {"Rhubarb", "Spring onions", "Purple sprouting broccoli", "Leeks", "Spinach"},
+	time.April:     {"Asparagus", "Wild garlic", "Rhubarb", "Radishes", "Spinach"},
+	time.May:       {"Asparagus", "Peas", "New potatoes", "Radishes", "Strawberries"},
+	time.June:      {"Strawberries", "Peas", "Broad beans", "Courgettes", "Cherries"},
+	time.July:      {"Tomatoes", "Courgettes", "Cherries", "Raspberries", "Sweetcorn"},
+	time.August:    {"Tomatoes", "Sweetcorn", "Aubergines", "Peaches", "Blackberries"},
+	time.September: {"Plums", "Blackberries", "Apples", "Sweetcorn", "Figs"},
+	time.October:   {"Pumpkins", "Apples", "Pears", "Squash", "Mushrooms"},
+	time.November:  {"Pumpkins", "Parsnips", "Brussels sprouts", "Pears", "Cranberries"},
+	time.December:  {"Brussels sprouts", "Parsnips", "Chestnuts", "Clementines", "Red cabbage"},
+}
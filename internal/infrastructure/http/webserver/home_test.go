@@ -0,0 +1,114 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHomeLayoutComposesSectionsPerVisitor(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Web.Home = config.WebHomeConfig{
+		Sections:         []string{HomeHero, HomeContinueCooking, HomeTrending, HomeChefActivity, HomeSeasonal},
+		CacheTTL:         5 * time.Minute,
+		PersonalCacheTTL: time.Minute,
+		Experiments:      []config.WebHomeExperimentConfig{{Section: HomeSeasonal, Cohorts: []string{"tester"}}},
+	}
+
+	layout := NewHomeLayout(cfg)
+	assert.Equal(t, []string{HomeHero, HomeTrending}, layout.Sections(""), "anonymous visitors get no personal or experimental sections")
+	assert.Equal(t, []string{HomeHero, HomeContinueCooking, HomeTrending}, layout.Sections("user-1"), "chef activity needs social features")
+	assert.Equal(t, []string{HomeHero, HomeContinueCooking, HomeTrending, HomeSeasonal}, layout.Sections("tester"))
+
+	cfg.Features.EnableSocialFeatures = true
+	cfg.Web.Home.Sections = []string{HomeTrending, HomeChefActivity}
+	layout = NewHomeLayout(cfg)
+	assert.Equal(t, []string{HomeTrending, HomeChefActivity}, layout.Sections("user-1"))
+	assert.False(t, layout.Visible(HomeHero, "user-1"), "sections left out of the config are hidden")
+}
+
+func TestHomeLayoutCachesSectionsPerScope(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Web.Home = config.WebHomeConfig{
+		Sections:         []string{HomeTrending, HomeContinueCooking},
+		CacheTTL:         5 * time.Minute,
+		PersonalCacheTTL: time.Minute,
+	}
+	layout := NewHomeLayout(cfg)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	layout.now = func() time.Time { return now }
+
+	layout.store(HomeTrending, "user-1", []byte("trending"))
+	layout.store(HomeContinueCooking, "user-1", []byte("ada's recipes"))
+
+	html, ok := layout.cached(HomeTrending, "user-2")
+	assert.True(t, ok, "shared sections are cached once for everyone")
+	assert.Equal(t, "trending", string(html))
+	_, ok = layout.cached(HomeContinueCooking, "user-2")
+	assert.False(t, ok, "personal sections are cached per user")
+
+	now = now.Add(2 * time.Minute)
+	_, ok = layout.cached(HomeContinueCooking, "user-1")
+	assert.False(t, ok)
+	_, ok = layout.cached(HomeTrending, "user-1")
+	assert.True(t, ok)
+}
+
+func TestHomeSectionLoadsLazilyAndOnce(t *testing.T) {
+	calls := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/api/v1/recipes/trending", r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": map[string]interface{}{
+			"recipes": []RecipeResponse{{ID: "3f2a9c", Title: "Carrot <Salad>", AuthorName: "Ada", Rating: 4}},
+		}})
+	}))
+	defer api.Close()
+
+	templates, err := parseTemplates()
+	require.NoError(t, err)
+	renderer := NewTemplateRenderer(templates, 0, false, zap.NewNop())
+	fragments, err := NewFragmentRegistry(renderer)
+	require.NoError(t, err)
+	cfg := &config.Config{}
+	cfg.Web.Home = config.WebHomeConfig{Sections: []string{HomeHero, HomeTrending, HomeContinueCooking}, CacheTTL: time.Minute}
+	s := &WebServer{
+		templates: renderer,
+		fragments: fragments,
+		home:      NewHomeLayout(cfg),
+		apiClient: newTestAPIClient(t, api.URL),
+		logger:    zap.NewNop(),
+	}
+	router := chi.NewRouter()
+	router.Get("/home/sections/{name}", s.handleHomeSection)
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/home/sections/trending", nil)
+		req.Header.Set("HX-Request", "true")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `id="home-trending"`)
+		assert.Contains(t, rec.Body.String(), "Carrot &lt;Salad&gt;")
+	}
+	assert.Equal(t, 1, calls, "the second request is served from the section cache")
+
+	req := httptest.NewRequest(http.MethodGet, "/home/sections/continue-cooking", nil)
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String(), "a section the visitor does not see clears its placeholder")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/home/sections/hero", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,108 @@
+// Package webserver provides HTMX response composition with out-of-band swaps
+package webserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+// OOBSwap is an hx-swap-oob strategy
+type OOBSwap string
+
+const (
+	// OOBInnerHTML replaces the children of the target element
+	OOBInnerHTML OOBSwap = "innerHTML"
+	// OOBOuterHTML replaces the target element itself
+	OOBOuterHTML OOBSwap = "outerHTML"
+	// OOBBeforeEnd appends to the target element
+	OOBBeforeEnd OOBSwap = "beforeend"
+)
+
+// fragmentFunc renders one region into the response buffer
+type fragmentFunc func(buf *bytes.Buffer) error
+
+// oobRegion is a fragment swapped into a region other than the request target
+type oobRegion struct {
+	selector string
+	swap     OOBSwap
+	render   fragmentFunc
+}
+
+// HTMXResponse composes a primary fragment for the hx-target plus any number
+// of out-of-band fragments so one action can refresh several page regions
+// (e.g. like button, dashboard stats and notification badge) in one round trip.
+type HTMXResponse struct {
+	main     fragmentFunc
+	oob      []oobRegion
+	triggers map[string]interface{}
+}
+
+// NewHTMXResponse creates an empty composed response
+func NewHTMXResponse() *HTMXResponse {
+	return &HTMXResponse{}
+}
+
+// Main sets the fragment swapped into the request's hx-target
+func (hr *HTMXResponse) Main(render fragmentFunc) *HTMXResponse {
+	hr.main = render
+	return hr
+}
+
+// OOB adds a fragment swapped into the element matched by selector.
+// The fragment is wrapped in a div carrying hx-swap-oob="<swap>:<selector>",
+// so fragments don't need to know they are being sent out-of-band.
+func (hr *HTMXResponse) OOB(selector string, swap OOBSwap, render fragmentFunc) *HTMXResponse {
+	hr.oob = append(hr.oob, oobRegion{selector: selector, swap: swap, render: render})
+	return hr
+}
+
+// Trigger adds a client-side event emitted through the HX-Trigger header
+func (hr *HTMXResponse) Trigger(event string, detail interface{}) *HTMXResponse {
+	if hr.triggers == nil {
+		hr.triggers = make(map[string]interface{})
+	}
+	hr.triggers[event] = detail
+	return hr
+}
+
+// Render writes the composed markup into buf. The main fragment is rendered
+// first; OOB regions follow in the order they were added.
+func (hr *HTMXResponse) Render(buf *bytes.Buffer) error {
+	if hr.main != nil {
+		if err := hr.main(buf); err != nil {
+			return err
+		}
+	}
+
+	for _, region := range hr.oob {
+		fmt.Fprintf(buf, `<div hx-swap-oob="%s">`, template.HTMLEscapeString(string(region.swap)+":"+region.selector))
+		if err := region.render(buf); err != nil {
+			return fmt.Errorf("oob region %s: %w", region.selector, err)
+		}
+		buf.WriteString(`</div>`)
+	}
+
+	return nil
+}
+
+// writeHTMX renders a composed response, falling back to a single error
+// fragment when any region fails so the page never receives partial swaps
+func (s *WebServer) writeHTMX(w http.ResponseWriter, hr *HTMXResponse) {
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		if err := hr.Render(buf); err != nil {
+			return err
+		}
+		// Only announce events once every region rendered
+		if len(hr.triggers) > 0 {
+			header, err := json.Marshal(hr.triggers)
+			if err != nil {
+				return fmt.Errorf("encode HX-Trigger: %w", err)
+			}
+			w.Header().Set("HX-Trigger", string(header))
+		}
+		return nil
+	})
+}
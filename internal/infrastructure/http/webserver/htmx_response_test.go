@@ -0,0 +1,56 @@
+package webserver
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHTMXResponseComposesOOBRegions(t *testing.T) {
+	resp := NewHTMXResponse().
+		Main(func(buf *bytes.Buffer) error {
+			buf.WriteString("<button>main</button>")
+			return nil
+		}).
+		OOB("#dashboard-stats", OOBInnerHTML, func(buf *bytes.Buffer) error {
+			buf.WriteString("<dl>stats</dl>")
+			return nil
+		}).
+		OOB("#notification-badge", OOBOuterHTML, func(buf *bytes.Buffer) error {
+			buf.WriteString("<a>badge</a>")
+			return nil
+		})
+
+	var buf bytes.Buffer
+	require.NoError(t, resp.Render(&buf))
+	assert.Equal(t,
+		`<button>main</button>`+
+			`<div hx-swap-oob="innerHTML:#dashboard-stats"><dl>stats</dl></div>`+
+			`<div hx-swap-oob="outerHTML:#notification-badge"><a>badge</a></div>`,
+		buf.String())
+}
+
+func TestWriteHTMXFailsWhole(t *testing.T) {
+	s := &WebServer{logger: zap.NewNop()}
+	resp := NewHTMXResponse().
+		Main(func(buf *bytes.Buffer) error {
+			buf.WriteString("<button>main</button>")
+			return nil
+		}).
+		OOB("#dashboard-stats", OOBInnerHTML, func(buf *bytes.Buffer) error {
+			return errors.New("boom")
+		}).
+		Trigger("recipeLiked", true)
+
+	rec := httptest.NewRecorder()
+	s.writeHTMX(rec, resp)
+
+	assert.Equal(t, 500, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "main")
+	assert.Empty(t, rec.Header().Get("HX-Trigger"))
+}
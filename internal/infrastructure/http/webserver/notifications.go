@@ -0,0 +1,188 @@
+// Package webserver provides the notifications page, the polled nav badge
+// and the notification settings on the profile page
+package webserver
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// notificationPageSize is how many notifications the list shows
+const notificationPageSize = 30
+
+// handleNotifications serves /notifications
+func (s *WebServer) handleNotifications(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	page, err := s.apiClient.GetNotifications(r.Context(), session.AccessToken, notificationPageSize)
+	if err != nil {
+		s.renderError(w, "Notifications are unavailable", err)
+		return
+	}
+	session.SetValue(sessionKeyUnreadCount, page.Unread)
+
+	var content, badge bytes.Buffer
+	if err := s.fragments.RenderNotificationList(&content, NewNotificationListView(*page, s.generateCSRFToken(session.ID))); err != nil {
+		s.renderError(w, "Failed to render notifications", err)
+		return
+	}
+	if err := s.fragments.RenderNotificationBadge(&badge, NotificationBadgeView{Unread: page.Unread}); err != nil {
+		s.renderError(w, "Failed to render notifications", err)
+		return
+	}
+	s.renderTemplate(w, "notifications", map[string]interface{}{
+		"Title":   "Notifications - Alchemorsel",
+		"Theme":   sessionTheme(session),
+		"Content": template.HTML(content.String()),
+		"Badge":   template.HTML(badge.String()),
+	})
+}
+
+// handleHTMXNotifications swaps in the notification list
+func (s *WebServer) handleHTMXNotifications(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	page, err := s.apiClient.GetNotifications(r.Context(), session.AccessToken, notificationPageSize)
+	if err != nil {
+		s.logger.Error("Notifications unavailable", zap.Error(err))
+		w.Write([]byte("<div class=\"error\">Notifications are unavailable right now. Please try again.</div>"))
+		return
+	}
+	session.SetValue(sessionKeyUnreadCount, page.Unread)
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		return s.fragments.RenderNotificationList(buf, NewNotificationListView(*page, s.generateCSRFToken(session.ID)))
+	})
+}
+
+// handleHTMXNotificationBadge renders the nav bell, which polls this every
+// 30 seconds. When the API is down the last known count is shown rather
+// than an error.
+func (s *WebServer) handleHTMXNotificationBadge(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	unread, err := s.apiClient.GetUnreadNotificationCount(r.Context(), session.AccessToken)
+	if err != nil {
+		s.logger.Debug("Unread count unavailable", zap.Error(err))
+		last, _ := session.GetValue(sessionKeyUnreadCount)
+		unread, _ = last.(int)
+	} else {
+		session.SetValue(sessionKeyUnreadCount, unread)
+	}
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		return s.fragments.RenderNotificationBadge(buf, NotificationBadgeView{Unread: unread})
+	})
+}
+
+// handleHTMXMarkNotificationsRead marks everything read, swapping in the
+// refreshed list and clearing the nav badge
+func (s *WebServer) handleHTMXMarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	if err := s.apiClient.MarkAllNotificationsRead(r.Context(), session.AccessToken); err != nil {
+		s.logger.Warn("Marking notifications read failed", zap.Error(err))
+		s.writeToastOnly(w, "We couldn't mark your notifications read. Please try again.")
+		return
+	}
+	page, err := s.apiClient.GetNotifications(r.Context(), session.AccessToken, notificationPageSize)
+	if err != nil {
+		s.logger.Warn("Notifications unavailable", zap.Error(err))
+		page = &NotificationPage{}
+	}
+	session.SetValue(sessionKeyUnreadCount, page.Unread)
+
+	csrfToken := s.generateCSRFToken(session.ID)
+	s.writeHTMX(w, NewHTMXResponse().
+		Main(func(buf *bytes.Buffer) error {
+			return s.fragments.RenderNotificationList(buf, NewNotificationListView(*page, csrfToken))
+		}).
+		OOB("#notification-badge", OOBInnerHTML, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderNotificationBadge(buf, NotificationBadgeView{Unread: page.Unread})
+		}))
+}
+
+// handleProfile serves /profile with the dietary and notification
+// settings and the delete account form
+func (s *WebServer) handleProfile(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	profile, err := s.apiClient.GetProfile(r.Context(), session.AccessToken)
+	if err != nil {
+		s.renderError(w, "Your profile is unavailable", err)
+		return
+	}
+	prefs, err := s.apiClient.GetNotificationPreferences(r.Context(), session.AccessToken)
+	if err != nil {
+		s.renderError(w, "Your notification settings are unavailable", err)
+		return
+	}
+	diet, err := s.apiClient.GetDietaryProfile(r.Context(), session.AccessToken)
+	if err != nil {
+		s.renderError(w, "Your dietary settings are unavailable", err)
+		return
+	}
+	deletion, err := s.apiClient.GetAccountDeletion(r.Context(), session.AccessToken)
+	if err != nil {
+		s.renderError(w, "Your account settings are unavailable", err)
+		return
+	}
+
+	csrfToken := s.generateCSRFToken(session.ID)
+	var prefsHTML, dietHTML, deletionHTML bytes.Buffer
+	view := NotificationPrefsView{Preferences: prefs, CSRFToken: csrfToken}
+	if err := s.fragments.RenderNotificationPrefs(&prefsHTML, view); err != nil {
+		s.renderError(w, "Failed to render notification settings", err)
+		return
+	}
+	if err := s.fragments.RenderDietaryPrefs(&dietHTML, NewDietaryPrefsView(*diet, csrfToken)); err != nil {
+		s.renderError(w, "Failed to render dietary settings", err)
+		return
+	}
+	if err := s.fragments.RenderAccountDeletion(&deletionHTML, accountDeletionView(deletion, csrfToken)); err != nil {
+		s.renderError(w, "Failed to render account settings", err)
+		return
+	}
+	s.renderTemplate(w, "profile", map[string]interface{}{
+		"Title":       "Profile - Alchemorsel",
+		"Theme":       sessionTheme(session),
+		"User":        profile,
+		"Preferences": template.HTML(prefsHTML.String()),
+		"Diet":        template.HTML(dietHTML.String()),
+		"Deletion":    template.HTML(deletionHTML.String()),
+	})
+}
+
+// handleHTMXNotificationPrefs saves the notification settings form. Every
+// type is posted as "types"; the checked ones also as "enabled".
+func (s *WebServer) handleHTMXNotificationPrefs(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	if err := r.ParseForm(); err != nil {
+		s.writeToastOnly(w, "We couldn't read those settings. Please try again.")
+		return
+	}
+
+	checked := make(map[string]bool)
+	for _, t := range r.PostForm["enabled"] {
+		checked[t] = true
+	}
+	enabled := make(map[string]bool)
+	for _, t := range r.PostForm["types"] {
+		enabled[t] = checked[t]
+	}
+
+	prefs, err := s.apiClient.UpdateNotificationPreferences(r.Context(), session.AccessToken, enabled)
+	if err != nil {
+		s.logger.Warn("Updating notification preferences failed", zap.Error(err))
+		s.writeToastOnly(w, "We couldn't save your notification settings. Please try again.")
+		return
+	}
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		return s.fragments.RenderNotificationPrefs(buf, NotificationPrefsView{
+			Preferences: prefs,
+			Saved:       true,
+			CSRFToken:   s.generateCSRFToken(session.ID),
+		})
+	})
+}
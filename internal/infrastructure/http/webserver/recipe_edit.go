@@ -0,0 +1,203 @@
+// Package webserver provides the recipe edit form
+package webserver
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// recipeEditSteps are the wizard step templates the edit form reuses
+var recipeEditSteps = []string{"wizard/recipe-basics", "wizard/recipe-ingredients", "wizard/recipe-steps"}
+
+const notRecipeEditor = "Only the author or an admin can edit this recipe."
+
+// handleEditRecipePage serves /recipes/{id}/edit, the form pre-filled with
+// the recipe. Visitors who may not edit it get a 403.
+func (s *WebServer) handleEditRecipePage(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+
+	editable, err := s.apiClient.GetEditableRecipe(r.Context(), session.AccessToken, recipeID)
+	if err != nil {
+		if isAPIStatus(err, http.StatusNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		s.renderError(w, "Recipe is unavailable", err)
+		return
+	}
+	if !s.canEditRecipe(r.Context(), session, editable.AuthorID) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusForbidden)
+		s.renderTemplate(w, "error", map[string]interface{}{
+			"Title":   "Edit Recipe - Alchemorsel",
+			"Message": notRecipeEditor,
+		})
+		return
+	}
+
+	s.renderRecipeEdit(w, r, recipeID, recipeEditValues(editable), nil)
+}
+
+// handleUpdateRecipe saves the edit form. HTMX submits it with PUT and is
+// redirected to the recipe; browsers without HTMX POST to /edit. Invalid
+// input re-renders the form with a 200 so HTMX swaps it in.
+func (s *WebServer) handleUpdateRecipe(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	values := r.PostForm
+
+	errs := validateRecipeBasics(values)
+	for field, message := range validateLines(values, "ingredients", "Add at least one ingredient") {
+		errs[field] = message
+	}
+	for field, message := range validateLines(values, "instructions", "Add at least one step") {
+		errs[field] = message
+	}
+	if len(errs) > 0 {
+		s.renderRecipeEdit(w, r, recipeID, values, errs)
+		return
+	}
+
+	_, err := s.apiClient.UpdateRecipe(r.Context(), session.AccessToken, recipeID, recipeUpdateRequest(values))
+	switch {
+	case isAPIStatus(err, http.StatusForbidden):
+		s.renderRecipeEdit(w, r, recipeID, values, FieldErrors{"_form": notRecipeEditor})
+		return
+	case isAPIStatus(err, http.StatusBadRequest):
+		s.renderRecipeEdit(w, r, recipeID, values, FieldErrors{"_form": "We couldn't read some of these changes. Check the ingredients and steps."})
+		return
+	case err != nil:
+		s.logger.Error("Failed to update recipe", zap.String("recipe_id", recipeID), zap.Error(err))
+		s.renderRecipeEdit(w, r, recipeID, values, FieldErrors{"_form": "We couldn't save your changes just now. Please try again."})
+		return
+	}
+
+	redirect := "/recipes/" + url.PathEscape(recipeID)
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", redirect)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
+
+// canEditRecipe mirrors the API's rule so the form is only offered to
+// people who can save it: the author, or an admin
+func (s *WebServer) canEditRecipe(ctx context.Context, session *Session, authorID string) bool {
+	if authorID != "" && authorID == session.UserID {
+		return true
+	}
+	profile, err := s.apiClient.GetProfile(ctx, session.AccessToken)
+	if err != nil {
+		s.logger.Warn("Failed to load profile for recipe edit", zap.Error(err))
+		return false
+	}
+	return profile.Role == "admin"
+}
+
+// renderRecipeEdit renders the edit form: as a fragment for HTMX requests,
+// otherwise wrapped in the edit page
+func (s *WebServer) renderRecipeEdit(w http.ResponseWriter, r *http.Request, recipeID string, values url.Values, errs FieldErrors) {
+	session := r.Context().Value("session").(*Session)
+
+	var body bytes.Buffer
+	for _, step := range recipeEditSteps {
+		if err := s.templates.ExecuteTemplate(&body, step, WizardBodyView{Values: values, Errors: errs}); err != nil {
+			s.renderError(w, "Failed to render form", err)
+			return
+		}
+	}
+
+	view := RecipeEditView{
+		RecipeID:  recipeID,
+		Body:      template.HTML(body.String()),
+		Tags:      values.Get("tags"),
+		FormError: errs["_form"],
+		CSRFToken: s.generateCSRFToken(session.ID),
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		s.renderFragment(w, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderRecipeEdit(buf, view)
+		})
+		return
+	}
+
+	var formHTML bytes.Buffer
+	if err := s.fragments.RenderRecipeEdit(&formHTML, view); err != nil {
+		s.renderError(w, "Failed to render form", err)
+		return
+	}
+	s.renderTemplate(w, "recipe-edit", map[string]interface{}{
+		"Title": "Edit Recipe - Alchemorsel",
+		"Theme": sessionTheme(session),
+		"Form":  template.HTML(formHTML.String()),
+	})
+}
+
+// recipeEditValues fills the form fields from a recipe, writing each
+// ingredient back as the line the parser reads
+func recipeEditValues(editable *EditableRecipe) url.Values {
+	lines := make([]string, len(editable.Ingredients))
+	for i, ingredient := range editable.Ingredients {
+		lines[i] = ingredients.Parsed{
+			Amount:   ingredient.Amount,
+			Unit:     recipe.MeasurementUnit(ingredient.Unit),
+			Name:     ingredient.Name,
+			Notes:    ingredient.Notes,
+			Optional: ingredient.Optional,
+		}.String()
+	}
+	steps := make([]string, len(editable.Instructions))
+	for i, step := range editable.Instructions {
+		steps[i] = step.Description
+	}
+
+	values := url.Values{}
+	values.Set("title", editable.Title)
+	values.Set("description", editable.Description)
+	values.Set("servings", strconv.Itoa(editable.Servings))
+	values.Set("prep_time", strconv.Itoa(editable.PrepTime))
+	values.Set("cook_time", strconv.Itoa(editable.CookTime))
+	values.Set("difficulty", editable.Difficulty)
+	values.Set("ingredients", strings.Join(lines, "\n"))
+	values.Set("instructions", strings.Join(steps, "\n"))
+	values.Set("tags", strings.Join(editable.Tags, ", "))
+	return values
+}
+
+// recipeUpdateRequest reads a validated edit form
+func recipeUpdateRequest(values url.Values) UpdateRecipeRequest {
+	tags := []string{}
+	for _, tag := range strings.Split(values.Get("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return UpdateRecipeRequest{
+		Title:        values.Get("title"),
+		Description:  values.Get("description"),
+		Servings:     atoiOrZero(values.Get("servings")),
+		PrepTime:     atoiOrZero(values.Get("prep_time")),
+		CookTime:     atoiOrZero(values.Get("cook_time")),
+		Difficulty:   values.Get("difficulty"),
+		Tags:         tags,
+		Ingredients:  splitLines(values.Get("ingredients")),
+		Instructions: splitLines(values.Get("instructions")),
+	}
+}
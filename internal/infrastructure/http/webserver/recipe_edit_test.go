@@ -0,0 +1,109 @@
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRecipeEditFlow(t *testing.T) {
+	var saved *UpdateRecipeRequest
+	updateStatus := http.StatusOK
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/auth/profile":
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": UserResponse{ID: "user-2", Role: "user"}})
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": map[string]interface{}{
+				"id": "3f2a9c", "title": "Lemon Bars", "author_id": "user-1", "servings": 9, "difficulty": "easy",
+				"tags":         []string{"baking", "citrus"},
+				"ingredients":  []map[string]interface{}{{"name": "lemons", "amount": 3}, {"name": "butter", "amount": 200, "unit": "g"}},
+				"instructions": []map[string]interface{}{{"description": "Make the crust."}, {"description": "Bake."}},
+			}})
+		case r.Method == http.MethodPut:
+			saved = &UpdateRecipeRequest{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(saved))
+			w.WriteHeader(updateStatus)
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": updateStatus == http.StatusOK})
+		}
+	}))
+	defer api.Close()
+
+	templates, err := parseTemplates()
+	require.NoError(t, err)
+	renderer := NewTemplateRenderer(templates, 0, false, zap.NewNop())
+	fragments, err := NewFragmentRegistry(renderer)
+	require.NoError(t, err)
+	s := &WebServer{
+		templates:  renderer,
+		fragments:  fragments,
+		apiClient:  newTestAPIClient(t, api.URL),
+		csrfSecret: []byte("test-secret"),
+		logger:     zap.NewNop(),
+	}
+	session := &Session{ID: "session-1", UserID: "user-1", AccessToken: "token"}
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), "session", session)))
+		})
+	})
+	router.Get("/recipes/{id}/edit", s.handleEditRecipePage)
+	router.Put("/recipes/{id}", s.handleUpdateRecipe)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recipes/3f2a9c/edit", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `value="Lemon Bars"`)
+	assert.Contains(t, rec.Body.String(), "3 lemons\n200 g butter")
+	assert.Contains(t, rec.Body.String(), `value="baking, citrus"`)
+
+	put := func(form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/recipes/3f2a9c", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("HX-Request", "true")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	form := url.Values{
+		"title":        {"Grandma's Lemon Bars"},
+		"servings":     {"12"},
+		"ingredients":  {"4 lemons\n200 g butter"},
+		"instructions": {"Make the crust.\nBake."},
+		"tags":         {"baking, , citrus"},
+	}
+
+	rec = put(form)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "/recipes/3f2a9c", rec.Header().Get("HX-Redirect"))
+	require.NotNil(t, saved)
+	assert.Equal(t, []string{"4 lemons", "200 g butter"}, saved.Ingredients)
+	assert.Equal(t, []string{"baking", "citrus"}, saved.Tags)
+
+	saved = nil
+	empty := url.Values{"title": {"Lemon Bars"}, "servings": {"12"}, "instructions": {"Bake."}}
+	rec = put(empty)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Add at least one ingredient")
+	assert.Nil(t, saved, "invalid forms are not sent to the API")
+
+	updateStatus = http.StatusForbidden
+	rec = put(form)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), notRecipeEditor)
+
+	session.UserID = "user-2"
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recipes/3f2a9c/edit", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code, "only the author or an admin is offered the form")
+}
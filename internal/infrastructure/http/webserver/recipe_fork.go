@@ -0,0 +1,23 @@
+// Package webserver provides "Make it my own", forking a recipe into a
+// draft the user can edit
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// handleForkRecipe copies the recipe into a draft owned by the user and
+// opens it in the editor
+func (s *WebServer) handleForkRecipe(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+
+	fork, err := s.apiClient.ForkRecipe(r.Context(), session.AccessToken, recipeID)
+	if err != nil {
+		s.renderError(w, "Failed to copy the recipe", err)
+		return
+	}
+	http.Redirect(w, r, "/recipes/"+fork.ID+"/edit", http.StatusSeeOther)
+}
@@ -0,0 +1,79 @@
+// Package webserver provides the recipe listing with its trending, top,
+// new, rating and prep time orders
+package webserver
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// Recipe listing orders, passed to the API as its sort parameter
+const (
+	RecipeSortTrending = "trending"
+	RecipeSortTop      = "top"
+	RecipeSortNew      = "new"
+	RecipeSortRating   = "rating"
+	RecipeSortPrepTime = "prep_time"
+)
+
+// RecipeSortOption is one tab of the recipe listing
+type RecipeSortOption struct {
+	Value   string
+	Label   string
+	Current bool
+}
+
+// recipeSortOptions lists the tabs in page order, marking the current one
+func recipeSortOptions(current string) []RecipeSortOption {
+	options := []RecipeSortOption{
+		{Value: RecipeSortTrending, Label: "Trending"},
+		{Value: RecipeSortTop, Label: "Top"},
+		{Value: RecipeSortNew, Label: "Newest"},
+		{Value: RecipeSortRating, Label: "Best rated"},
+		{Value: RecipeSortPrepTime, Label: "Quickest"},
+	}
+	for i := range options {
+		options[i].Current = options[i].Value == current
+	}
+	return options
+}
+
+// handleRecipeList serves /recipes, trending first unless ?sort= picks
+// another order. An unknown order falls back to trending. ?after= starts
+// after a page already shown; HTMX asks for just that page, which replaces
+// the load more item it came from.
+func (s *WebServer) handleRecipeList(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	sort := r.URL.Query().Get("sort")
+	switch sort {
+	case RecipeSortTrending, RecipeSortTop, RecipeSortNew, RecipeSortRating, RecipeSortPrepTime:
+	case "newest":
+		sort = RecipeSortNew
+	default:
+		sort = RecipeSortTrending
+	}
+
+	page, err := s.apiClient.GetRecipes(r.Context(), session.AccessToken, sort, r.URL.Query().Get("after"))
+	if err != nil {
+		s.renderError(w, "Failed to load recipes", err)
+		return
+	}
+
+	// Resolve all authors with one batched call instead of one per card
+	s.hydrateAuthors(r.Context(), session.AccessToken, page.Recipes)
+
+	view := NewRecipePageView(sort, page)
+	if r.Header.Get("HX-Request") == "true" {
+		s.renderFragment(w, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderRecipePage(buf, view)
+		})
+		return
+	}
+	s.renderTemplate(w, "recipes", map[string]interface{}{
+		"Title": "Recipes - Alchemorsel",
+		"Theme": sessionTheme(session),
+		"Sorts": recipeSortOptions(sort),
+		"Page":  view,
+	})
+}
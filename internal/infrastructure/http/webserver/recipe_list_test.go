@@ -0,0 +1,101 @@
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRecipeListPassesTheSortAndMarksItsTab(t *testing.T) {
+	var sorts []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sorts = append(sorts, r.URL.Query().Get("sort"))
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": map[string]interface{}{
+			"recipes": []RecipeResponse{{ID: "9b1d", Title: "Braised <Leeks>", AuthorName: "Ada", Rating: 4.6}},
+		}})
+	}))
+	defer api.Close()
+
+	s := newRecipeListServer(t, api.URL)
+	get := func(target string) string {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req = req.WithContext(context.WithValue(req.Context(), "session", &Session{AccessToken: "token"}))
+		rec := httptest.NewRecorder()
+		s.handleRecipeList(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	body := get("/recipes?sort=top")
+	assert.Contains(t, body, `<a href="/recipes?sort=top" class="btn btn-primary" aria-current="page">Top</a>`)
+	assert.Contains(t, body, `<a href="/recipes?sort=trending" class="btn btn-secondary">Trending</a>`)
+	assert.Contains(t, body, "Braised &lt;Leeks&gt;")
+
+	body = get("/recipes?sort=oldest")
+	assert.Contains(t, body, `aria-current="page">Trending</a>`)
+	assert.Equal(t, []string{"top", "trending"}, sorts)
+}
+
+func TestRecipeListLoadsMoreAfterTheCursor(t *testing.T) {
+	var afters []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "rating", r.URL.Query().Get("sort"))
+		after := r.URL.Query().Get("after")
+		afters = append(afters, after)
+		data := map[string]interface{}{
+			"recipes":     []RecipeResponse{{ID: "9b1d", Title: "Braised Leeks", AuthorName: "Ada", Rating: 4.6}},
+			"next_cursor": "c2",
+		}
+		if after == "c2" {
+			data = map[string]interface{}{
+				"recipes": []RecipeResponse{{ID: "7c4e", Title: "Leek Gratin", AuthorName: "Ada", Rating: 3.9}},
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": data})
+	}))
+	defer api.Close()
+
+	s := newRecipeListServer(t, api.URL)
+	get := func(target string, htmx bool) string {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if htmx {
+			req.Header.Set("HX-Request", "true")
+		}
+		req = req.WithContext(context.WithValue(req.Context(), "session", &Session{AccessToken: "token"}))
+		rec := httptest.NewRecorder()
+		s.handleRecipeList(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	body := get("/recipes?sort=rating", false)
+	assert.Contains(t, body, `<ul class="recipe-grid"`)
+	assert.Contains(t, body, `hx-get="/recipes?after=c2&amp;sort=rating"`)
+
+	body = get("/recipes?sort=rating&after=c2", true)
+	assert.NotContains(t, body, "<html")
+	assert.NotContains(t, body, "<ul")
+	assert.Contains(t, body, "Leek Gratin")
+	assert.NotContains(t, body, "Load more", "the last page has nothing more to load")
+	assert.Equal(t, []string{"", "c2"}, afters)
+}
+
+func newRecipeListServer(t *testing.T, apiURL string) *WebServer {
+	templates, err := parseTemplates()
+	require.NoError(t, err)
+	renderer := NewTemplateRenderer(templates, 0, false, zap.NewNop())
+	fragments, err := NewFragmentRegistry(renderer)
+	require.NoError(t, err)
+	return &WebServer{
+		templates: renderer,
+		fragments: fragments,
+		apiClient: newTestAPIClient(t, apiURL),
+		logger:    zap.NewNop(),
+	}
+}
@@ -0,0 +1,70 @@
+// Package webserver provides the streamed recipe page shell
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/performance"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// shellBudgetBytes is what the recipe page shell may weigh: the first
+// round trip the LCP optimizer plans for. It is checked uncompressed, so a
+// shell within it fits with room to spare once compressed.
+const shellBudgetBytes = performance.MaxFirstPacketSize
+
+var recipeShellOverBudget = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "alchemorsel",
+	Subsystem: "web",
+	Name:      "recipe_shell_over_budget_total",
+	Help:      "Recipe page shells larger than the first packet budget",
+})
+
+// handleRecipeDetail serves /recipes/{id}. Only the shell is rendered
+// here: header, hero and skeleton cards, flushed as soon as the recipe
+// summary arrives. The steps, related recipes and comments replace their
+// skeletons as HTMX fragments, so their API calls never hold up the hero
+// image, which is the page's LCP element.
+func (s *WebServer) handleRecipeDetail(w http.ResponseWriter, r *http.Request) {
+	session, _ := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+
+	recipe, err := s.apiClient.GetRecipe(r.Context(), sessionToken(r), recipeID)
+	if err != nil {
+		s.renderError(w, "Recipe is unavailable", err)
+		return
+	}
+
+	csrfToken := ""
+	if session != nil {
+		csrfToken = s.generateCSRFToken(session.ID)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	out := &countingWriter{w: w}
+	if err := s.templates.ExecuteTemplate(out, "recipe-detail", map[string]interface{}{
+		"Title":     recipe.Title + " - Alchemorsel",
+		"Theme":     sessionTheme(session),
+		"Recipe":    recipe,
+		"Sandbox":   s.sandboxBanner(),
+		"CSRFToken": csrfToken,
+	}); err != nil {
+		s.logger.Error("Failed to render recipe shell", zap.String("recipe_id", recipeID), zap.Error(err))
+		return
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	if out.n > shellBudgetBytes {
+		recipeShellOverBudget.Inc()
+		s.logger.Warn("Recipe shell over the first packet budget",
+			zap.String("recipe_id", recipeID),
+			zap.Int("bytes", out.n),
+			zap.Int("budget", shellBudgetBytes),
+		)
+	}
+}
@@ -0,0 +1,130 @@
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRecipeShellStaysWithinFirstPacket(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": RecipeResponse{
+			ID:          "3f2a9c",
+			Title:       "Carrot <Salad>",
+			Description: strings.Repeat("Crunchy, bright and ready in minutes. ", 400),
+			AuthorName:  "Ada",
+			PrepTime:    10,
+			Servings:    2,
+			ImageURL:    "https://images.example.com/carrot.jpg",
+		}})
+	}))
+	defer api.Close()
+
+	templates, err := parseTemplates()
+	require.NoError(t, err)
+	s := &WebServer{
+		templates: NewTemplateRenderer(templates, 0, false, zap.NewNop()),
+		apiClient: newTestAPIClient(t, api.URL),
+		logger:    zap.NewNop(),
+	}
+	router := chi.NewRouter()
+	router.Get("/recipes/{id}", s.handleRecipeDetail)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recipes/3f2a9c", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+
+	assert.LessOrEqual(t, len(body), shellBudgetBytes)
+	assert.True(t, rec.Flushed)
+	assert.Contains(t, body, "Carrot &lt;Salad&gt;")
+	assert.Contains(t, body, `<link rel="preload" as="image" href="https://images.example.com/carrot.jpg" fetchpriority="high">`)
+	assert.Contains(t, body, `hx-get="/recipes/3f2a9c/steps?embed=1" hx-trigger="load"`)
+	assert.Contains(t, body, `hx-get="/htmx/recipes/3f2a9c/comments" hx-trigger="revealed"`)
+	assert.Contains(t, body, `class="card skeleton"`)
+}
+
+func TestRecipeShellCreditsTheOriginalOfAFork(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": RecipeResponse{
+			ID:         "7b1e40",
+			Title:      "Carrot Salad, Spicier",
+			Status:     "published",
+			Forks:      2,
+			ForkedFrom: &RecipeOrigin{ID: "3f2a9c", Title: "Carrot Salad", AuthorName: "Ada"},
+		}})
+	}))
+	defer api.Close()
+
+	templates, err := parseTemplates()
+	require.NoError(t, err)
+	s := &WebServer{
+		templates:  NewTemplateRenderer(templates, 0, false, zap.NewNop()),
+		apiClient:  newTestAPIClient(t, api.URL),
+		csrfSecret: []byte("secret"),
+		logger:     zap.NewNop(),
+	}
+	router := chi.NewRouter()
+	router.Get("/recipes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), "session", &Session{ID: "session_1"})
+		s.handleRecipeDetail(w, r.WithContext(ctx))
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recipes/7b1e40", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+
+	assert.Contains(t, body, `Adapted from <a href="/recipes/3f2a9c">Carrot Salad</a> by Ada`)
+	assert.Contains(t, body, "Adapted 2 times")
+	assert.Contains(t, body, `action="/recipes/7b1e40/fork"`)
+	assert.Contains(t, body, `value="`+s.generateCSRFToken("session_1")+`"`)
+}
+
+func TestRecipePrintViewLaysOutTheExport(t *testing.T) {
+	var asked string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asked = r.URL.RequestURI()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"3f2a9c1e-8b7d-4c6a-9e5f-1a2b3c4d5e6f","title":"Carrot <Salad>","servings":2,"prep_minutes":10,
+			"ingredients":[{"text":"2 carrots","name":"carrots"}],
+			"steps":[{"number":1,"text":"Grate the carrots.","minutes":5}],
+			"nutrition":{"calories":120,"protein_g":2,"coverage":0.5},
+			"allergens":["tree-nuts"],
+			"attribution":{"author":"Ada","url":"https://alchemorsel.example/recipes/3f2a9c1e-8b7d-4c6a-9e5f-1a2b3c4d5e6f"}}`))
+	}))
+	defer api.Close()
+
+	templates, err := parseTemplates()
+	require.NoError(t, err)
+	s := &WebServer{
+		templates: NewTemplateRenderer(templates, 0, false, zap.NewNop()),
+		apiClient: newTestAPIClient(t, api.URL),
+		logger:    zap.NewNop(),
+	}
+	router := chi.NewRouter()
+	router.Get("/recipes/{id}/print", s.handleRecipePrint)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recipes/3f2a9c1e-8b7d-4c6a-9e5f-1a2b3c4d5e6f/print", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+
+	assert.Equal(t, "/api/v1/recipes/3f2a9c1e-8b7d-4c6a-9e5f-1a2b3c4d5e6f/export?format=json", asked)
+	assert.Contains(t, body, `<h1 id="print-title">Carrot &lt;Salad&gt;</h1>`)
+	assert.Contains(t, body, "<li>Serves 2</li><li>Prep 10 min</li>")
+	assert.Contains(t, body, `<li value="1">Grate the carrots.<span class="cue">5 min</span></li>`)
+	assert.Contains(t, body, "Contains: Nuts")
+	assert.Contains(t, body, "lower bound")
+	assert.Contains(t, body, "From Alchemorsel: https://alchemorsel.example/recipes/3f2a9c1e-8b7d-4c6a-9e5f-1a2b3c4d5e6f")
+	assert.NotContains(t, body, `aria-label="Main"`)
+	assert.NotContains(t, body, "/ai/chat")
+}
@@ -0,0 +1,60 @@
+// Package webserver provides the printable recipe view and recipe
+// downloads as PDF, Markdown or JSON
+package webserver
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// handleRecipePrint serves /recipes/{id}/print: the recipe on a plain page
+// with no navigation or chat, laid out from the same export as the PDF
+func (s *WebServer) handleRecipePrint(w http.ResponseWriter, r *http.Request) {
+	session, _ := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+
+	p, err := s.apiClient.GetRecipePrintout(r.Context(), sessionToken(r), recipeID)
+	if isAPIStatus(err, http.StatusNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		s.renderError(w, "Recipe is unavailable", err)
+		return
+	}
+
+	contains, mayContain := p.AllergenLabels()
+	s.renderTemplate(w, "recipe-print", map[string]interface{}{
+		"Title":      p.Title + " - Alchemorsel",
+		"Theme":      sessionTheme(session),
+		"Recipe":     p,
+		"Contains":   contains,
+		"MayContain": mayContain,
+	})
+}
+
+// handleRecipeExport serves /recipes/{id}/export?format= as a download
+func (s *WebServer) handleRecipeExport(w http.ResponseWriter, r *http.Request) {
+	recipeID := chi.URLParam(r, "id")
+	format := r.URL.Query().Get("format")
+
+	download, err := s.apiClient.DownloadRecipe(r.Context(), sessionToken(r), recipeID, format)
+	switch {
+	case isAPIStatus(err, http.StatusNotFound):
+		http.NotFound(w, r)
+		return
+	case isAPIStatus(err, http.StatusBadRequest):
+		http.Error(w, "Recipes download as pdf, markdown or json", http.StatusBadRequest)
+		return
+	case err != nil:
+		s.logger.Error("Recipe export failed", zap.String("recipe_id", recipeID), zap.String("format", format), zap.Error(err))
+		http.Error(w, "The recipe cannot be downloaded right now", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", download.ContentType)
+	w.Header().Set("Content-Disposition", download.ContentDisposition)
+	w.Write(download.Content)
+}
@@ -0,0 +1,88 @@
+// Package webserver provides the author stats page for a recipe
+package webserver
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// defaultStatsDays is the stats window when none is chosen
+const defaultStatsDays = 30
+
+// statsDays reads the ?days= window, falling back to the default for
+// anything that is not an offered period
+func statsDays(r *http.Request) int {
+	days, err := strconv.Atoi(r.URL.Query().Get("days"))
+	if err != nil {
+		return defaultStatsDays
+	}
+	for _, window := range statsWindows {
+		if days == window {
+			return days
+		}
+	}
+	return defaultStatsDays
+}
+
+// handleRecipeStats serves /recipes/{id}/stats. HTMX requests from the
+// period links get just the recipe-stats fragment.
+func (s *WebServer) handleRecipeStats(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+	days := statsDays(r)
+
+	analytics, err := s.apiClient.GetRecipeAnalytics(r.Context(), session.AccessToken, recipeID, days)
+	if err != nil {
+		if r.Header.Get("HX-Request") == "true" {
+			s.logger.Error("Recipe stats unavailable", zap.String("recipe_id", recipeID), zap.Error(err))
+			w.Write([]byte("<div class=\"error\">Stats are unavailable right now. Please try again.</div>"))
+			return
+		}
+		s.renderError(w, "Stats are unavailable for this recipe", err)
+		return
+	}
+
+	view := NewRecipeStatsView(*analytics, days)
+	if r.Header.Get("HX-Request") == "true" {
+		s.renderFragment(w, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderRecipeStats(buf, view)
+		})
+		return
+	}
+
+	var statsHTML bytes.Buffer
+	if err := s.fragments.RenderRecipeStats(&statsHTML, view); err != nil {
+		s.renderError(w, "Failed to render stats", err)
+		return
+	}
+	s.renderTemplate(w, "recipe-stats", map[string]interface{}{
+		"Title": analytics.Title + " stats - Alchemorsel",
+		"Theme": sessionTheme(session),
+		"Stats": template.HTML(statsHTML.String()),
+	})
+}
+
+// handleRecipeStatsCSV serves /recipes/{id}/stats.csv as a download
+func (s *WebServer) handleRecipeStatsCSV(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+	days := statsDays(r)
+
+	data, err := s.apiClient.GetRecipeAnalyticsCSV(r.Context(), session.AccessToken, recipeID, days)
+	if err != nil {
+		s.logger.Error("Recipe stats export failed", zap.String("recipe_id", recipeID), zap.Error(err))
+		http.Error(w, "Stats are unavailable for this recipe", http.StatusBadGateway)
+		return
+	}
+
+	filename := fmt.Sprintf("recipe-stats-%dd.csv", days)
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Write(data)
+}
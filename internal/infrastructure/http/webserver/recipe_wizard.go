@@ -0,0 +1,201 @@
+// Package webserver provides the guided recipe creation wizard
+package webserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CreateRecipeRequest is the payload sent to POST /api/v1/recipes
+type CreateRecipeRequest struct {
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Servings     int      `json:"servings"`
+	PrepTime     int      `json:"prep_time"`
+	CookTime     int      `json:"cook_time"`
+	Difficulty   string   `json:"difficulty"`
+	Ingredients  []string `json:"ingredients"`
+	Instructions []string `json:"instructions"`
+	Images       []string `json:"images,omitempty"`
+}
+
+// Limits applied by the recipe wizard
+const (
+	maxRecipeTitle       = 200
+	maxRecipeDescription = 1000
+	maxRecipeLines       = 100
+	maxRecipeLineLength  = 500
+	maxRecipePhotos      = 10
+	maxRecipeMinutes     = 24 * 60
+)
+
+var recipeDifficulties = map[string]bool{"easy": true, "medium": true, "hard": true, "expert": true}
+
+// newRecipeWizard builds the guided creation flow:
+// basics → ingredients → steps → photos → publish
+func (s *WebServer) newRecipeWizard() *Wizard {
+	return &Wizard{
+		ID:       "recipe-create",
+		Title:    "Create a Recipe",
+		BasePath: "/recipes/wizard",
+		Steps: []WizardStep{
+			{
+				ID:       "basics",
+				Title:    "Basics",
+				Template: "wizard/recipe-basics",
+				Fields:   []string{"title", "description", "servings", "prep_time", "cook_time", "difficulty"},
+				Validate: validateRecipeBasics,
+			},
+			{
+				ID:       "ingredients",
+				Title:    "Ingredients",
+				Template: "wizard/recipe-ingredients",
+				Fields:   []string{"ingredients"},
+				Validate: func(values url.Values) FieldErrors {
+					return validateLines(values, "ingredients", "Add at least one ingredient")
+				},
+			},
+			{
+				ID:       "steps",
+				Title:    "Steps",
+				Template: "wizard/recipe-steps",
+				Fields:   []string{"instructions"},
+				Validate: func(values url.Values) FieldErrors {
+					return validateLines(values, "instructions", "Add at least one step")
+				},
+			},
+			{
+				ID:       "photos",
+				Title:    "Photos",
+				Template: "wizard/recipe-photos",
+				Fields:   []string{"photo_urls"},
+				Validate: validateRecipePhotos,
+			},
+			{
+				ID:       "publish",
+				Title:    "Publish",
+				Template: "wizard/recipe-publish",
+			},
+		},
+		Complete: s.publishWizardRecipe,
+	}
+}
+
+// publishWizardRecipe creates the recipe through the API
+func (s *WebServer) publishWizardRecipe(ctx context.Context, session *Session, values url.Values) (string, error) {
+	req := CreateRecipeRequest{
+		Title:        values.Get("title"),
+		Description:  values.Get("description"),
+		Servings:     atoiOrZero(values.Get("servings")),
+		PrepTime:     atoiOrZero(values.Get("prep_time")),
+		CookTime:     atoiOrZero(values.Get("cook_time")),
+		Difficulty:   values.Get("difficulty"),
+		Ingredients:  splitLines(values.Get("ingredients")),
+		Instructions: splitLines(values.Get("instructions")),
+		Images:       splitLines(values.Get("photo_urls")),
+	}
+
+	created, err := s.apiClient.CreateRecipe(ctx, session.AccessToken, req)
+	if err != nil {
+		return "", err
+	}
+
+	if created.ID != "" {
+		return "/recipes/" + url.PathEscape(created.ID), nil
+	}
+	return "/recipes", nil
+}
+
+// handleIngredientPreview renders how the ingredients step will read pasted
+// lines, so cooks can fix unparseable ones before publishing
+func (s *WebServer) handleIngredientPreview(w http.ResponseWriter, r *http.Request) {
+	lines := r.FormValue("ingredients")
+	if len(splitLines(lines)) > maxRecipeLines {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		w.Write([]byte(fmt.Sprintf(`<div class="error">Use at most %d lines</div>`, maxRecipeLines)))
+		return
+	}
+
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		return s.fragments.RenderIngredientPreview(buf, NewIngredientPreviewView(lines))
+	})
+}
+
+func validateRecipeBasics(values url.Values) FieldErrors {
+	errs := FieldErrors{}
+
+	title := values.Get("title")
+	switch {
+	case len(title) < 3:
+		errs["title"] = "Title must be at least 3 characters"
+	case len(title) > maxRecipeTitle:
+		errs["title"] = fmt.Sprintf("Title must be at most %d characters", maxRecipeTitle)
+	}
+
+	if len(values.Get("description")) > maxRecipeDescription {
+		errs["description"] = fmt.Sprintf("Description must be at most %d characters", maxRecipeDescription)
+	}
+
+	if n, err := strconv.Atoi(values.Get("servings")); err != nil || n < 1 || n > 100 {
+		errs["servings"] = "Servings must be a number between 1 and 100"
+	}
+
+	for _, field := range []string{"prep_time", "cook_time"} {
+		if v := values.Get(field); v != "" {
+			if n, err := strconv.Atoi(v); err != nil || n < 0 || n > maxRecipeMinutes {
+				errs[field] = fmt.Sprintf("Enter minutes between 0 and %d", maxRecipeMinutes)
+			}
+		}
+	}
+
+	if d := values.Get("difficulty"); d != "" && !recipeDifficulties[d] {
+		errs["difficulty"] = "Choose easy, medium, hard or expert"
+	}
+
+	return errs
+}
+
+// validateLines requires at least one line and bounds count and length
+func validateLines(values url.Values, field, emptyMessage string) FieldErrors {
+	lines := splitLines(values.Get(field))
+	switch {
+	case len(lines) == 0:
+		return FieldErrors{field: emptyMessage}
+	case len(lines) > maxRecipeLines:
+		return FieldErrors{field: fmt.Sprintf("Use at most %d lines", maxRecipeLines)}
+	}
+	for i, line := range lines {
+		if len(line) > maxRecipeLineLength {
+			return FieldErrors{field: fmt.Sprintf("Line %d is longer than %d characters", i+1, maxRecipeLineLength)}
+		}
+	}
+	return nil
+}
+
+// validateRecipePhotos accepts up to maxRecipePhotos absolute http(s) URLs
+func validateRecipePhotos(values url.Values) FieldErrors {
+	photos := splitLines(values.Get("photo_urls"))
+	if len(photos) > maxRecipePhotos {
+		return FieldErrors{"photo_urls": fmt.Sprintf("Add at most %d photos", maxRecipePhotos)}
+	}
+	for _, photo := range photos {
+		u, err := url.Parse(photo)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return FieldErrors{"photo_urls": fmt.Sprintf("%q is not a valid image URL", photo)}
+		}
+		if strings.ContainsAny(photo, " \"'<>") {
+			return FieldErrors{"photo_urls": fmt.Sprintf("%q is not a valid image URL", photo)}
+		}
+	}
+	return nil
+}
+
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,244 @@
+// Package webserver provides template precompilation and the render budget
+package webserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+const (
+	// budgetWarnInterval stops a slow template from flooding the log; every
+	// overrun is still counted
+	budgetWarnInterval = time.Minute
+	// largeRenderBytes is the output size past which a slow render is more
+	// likely too much markup than a slow template
+	largeRenderBytes = 256 << 10
+)
+
+var (
+	templateRenderSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "alchemorsel",
+		Subsystem: "web",
+		Name:      "template_render_seconds",
+		Help:      "Time spent executing each HTML template",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5},
+	}, []string{"template"})
+	templateOverBudget = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "alchemorsel",
+		Subsystem: "web",
+		Name:      "template_render_over_budget_total",
+		Help:      "Template renders that took longer than the render budget",
+	}, []string{"template"})
+)
+
+// precompileTemplates runs html/template's contextual escaping for every
+// template now rather than on its first request, so markup the escaper
+// rejects stops the server at startup. Execution errors from the empty data
+// are expected and ignored; only escaping errors are reported.
+func precompileTemplates(templates *template.Template) error {
+	for _, tmpl := range templates.Templates() {
+		if tmpl.Tree == nil {
+			continue
+		}
+		var escapeErr *template.Error
+		if err := tmpl.Execute(io.Discard, nil); errors.As(err, &escapeErr) {
+			return fmt.Errorf("template %s: %w", tmpl.Name(), err)
+		}
+	}
+	return nil
+}
+
+// TemplateRenderStats is the render time of one template since startup
+type TemplateRenderStats struct {
+	Template   string  `json:"template"`
+	Renders    int64   `json:"renders"`
+	OverBudget int64   `json:"over_budget"`
+	AverageMS  float64 `json:"average_ms"`
+	MaxMS      float64 `json:"max_ms"`
+	MaxBytes   int     `json:"max_bytes"`
+}
+
+type templateStats struct {
+	renders    int64
+	overBudget int64
+	total      time.Duration
+	max        time.Duration
+	maxBytes   int
+	lastWarned time.Time
+}
+
+// TemplateRenderer executes the precompiled templates and times every render
+// against the budget. A zero budget records timings without enforcing one.
+type TemplateRenderer struct {
+	templates *template.Template
+	budget    time.Duration
+	pprof     bool
+	logger    *zap.Logger
+
+	mu    sync.Mutex
+	stats map[string]*templateStats
+	now   func() time.Time
+}
+
+// NewTemplateRenderer wraps a parsed template set. pprof says whether
+// /debug/pprof is mounted, which changes the profiling hint in budget logs.
+func NewTemplateRenderer(templates *template.Template, budget time.Duration, pprof bool, logger *zap.Logger) *TemplateRenderer {
+	return &TemplateRenderer{
+		templates: templates,
+		budget:    budget,
+		pprof:     pprof,
+		logger:    logger,
+		stats:     make(map[string]*templateStats),
+		now:       time.Now,
+	}
+}
+
+// Lookup returns the named template, or nil
+func (tr *TemplateRenderer) Lookup(name string) *template.Template {
+	return tr.templates.Lookup(name)
+}
+
+// ExecuteTemplate renders the named template to w and records how long it
+// took
+func (tr *TemplateRenderer) ExecuteTemplate(w io.Writer, name string, data interface{}) error {
+	out := &countingWriter{w: w}
+	started := tr.now()
+	err := tr.templates.ExecuteTemplate(out, name, data)
+	tr.record(name, tr.now().Sub(started), out.n)
+	return err
+}
+
+// Stats lists every rendered template, slowest in total first
+func (tr *TemplateRenderer) Stats() []TemplateRenderStats {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	stats := make([]TemplateRenderStats, 0, len(tr.stats))
+	totals := make(map[string]time.Duration, len(tr.stats))
+	for name, s := range tr.stats {
+		totals[name] = s.total
+		stats = append(stats, TemplateRenderStats{
+			Template:   name,
+			Renders:    s.renders,
+			OverBudget: s.overBudget,
+			AverageMS:  milliseconds(s.total / time.Duration(s.renders)),
+			MaxMS:      milliseconds(s.max),
+			MaxBytes:   s.maxBytes,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if totals[stats[i].Template] != totals[stats[j].Template] {
+			return totals[stats[i].Template] > totals[stats[j].Template]
+		}
+		return stats[i].Template < stats[j].Template
+	})
+	return stats
+}
+
+// Budget is the render time allowed per template; zero means unlimited
+func (tr *TemplateRenderer) Budget() time.Duration {
+	return tr.budget
+}
+
+func (tr *TemplateRenderer) record(name string, elapsed time.Duration, bytes int) {
+	templateRenderSeconds.WithLabelValues(name).Observe(elapsed.Seconds())
+	over := tr.budget > 0 && elapsed > tr.budget
+	if over {
+		templateOverBudget.WithLabelValues(name).Inc()
+	}
+
+	tr.mu.Lock()
+	s, ok := tr.stats[name]
+	if !ok {
+		s = &templateStats{}
+		tr.stats[name] = s
+	}
+	s.renders++
+	s.total += elapsed
+	if elapsed > s.max {
+		s.max = elapsed
+	}
+	if bytes > s.maxBytes {
+		s.maxBytes = bytes
+	}
+	warn := false
+	if over {
+		s.overBudget++
+		if now := tr.now(); now.Sub(s.lastWarned) >= budgetWarnInterval {
+			s.lastWarned = now
+			warn = true
+		}
+	}
+	renders, overBudget := s.renders, s.overBudget
+	tr.mu.Unlock()
+
+	if warn {
+		tr.logger.Warn("Template render over budget",
+			zap.String("template", name),
+			zap.Duration("elapsed", elapsed),
+			zap.Duration("budget", tr.budget),
+			zap.Int("bytes", bytes),
+			zap.Int64("renders", renders),
+			zap.Int64("over_budget", overBudget),
+			zap.Strings("hints", tr.profilingHints(name, bytes)),
+		)
+	}
+}
+
+// profilingHints suggests where to look next for a slow template
+func (tr *TemplateRenderer) profilingHints(name string, bytes int) []string {
+	var hints []string
+	if bytes > largeRenderBytes {
+		hints = append(hints, fmt.Sprintf("rendered %d KB; paginate long lists or load them as HTMX fragments", bytes>>10))
+	}
+	if tr.pprof {
+		hints = append(hints, "capture a CPU profile while reproducing: go tool pprof http://<host>/debug/pprof/profile?seconds=30")
+	} else {
+		hints = append(hints, "set server.enable_pprof outside production to capture a CPU profile from /debug/pprof")
+	}
+	hints = append(hints, "benchmark locally: go test ./internal/infrastructure/http/webserver -run '^$' -bench RenderFragments -cpuprofile cpu.out")
+	return hints
+}
+
+// handleTemplateStats serves /dev/templates: render times per template,
+// slowest first
+func (s *WebServer) handleTemplateStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"budget_ms": milliseconds(s.templates.Budget()),
+		"templates": s.templates.Stats(),
+	})
+}
+
+// pprofEnabled mounts /debug/pprof only outside production
+func pprofEnabled(cfg *config.Config) bool {
+	return cfg.Server.EnablePprof && !cfg.IsProduction()
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// countingWriter counts the bytes a template writes
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += n
+	return n, err
+}
@@ -0,0 +1,80 @@
+package webserver
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestPrecompileTemplatesFailsOnEscapingErrors(t *testing.T) {
+	broken := template.Must(template.New("broken").Parse(`{{if .}}<a href="{{end}}`))
+	err := precompileTemplates(broken)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "template broken")
+
+	// Missing data only fails at execution; the escaping still succeeds
+	valid := template.Must(template.New("valid").Parse(`<p>{{index . 3}}</p>`))
+	assert.NoError(t, precompileTemplates(valid))
+}
+
+func TestTemplateRendererLogsOverBudget(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	tmpl := template.Must(template.New("slow").Parse(`<p>{{.}}</p>`))
+	renderer := NewTemplateRenderer(tmpl, 50*time.Millisecond, false, zap.New(core))
+
+	clock := time.Now()
+	step := 80 * time.Millisecond
+	renderer.now = func() time.Time {
+		clock = clock.Add(step)
+		return clock
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderer.ExecuteTemplate(&buf, "slow", "toast"))
+	require.NoError(t, renderer.ExecuteTemplate(&buf, "slow", "toast"))
+	step = 10 * time.Millisecond
+	require.NoError(t, renderer.ExecuteTemplate(&buf, "slow", "toast"))
+
+	require.Equal(t, 1, logs.Len(), "repeat offenders are logged once per interval")
+	entry := logs.All()[0]
+	assert.Equal(t, "slow", entry.ContextMap()["template"])
+	assert.NotEmpty(t, entry.ContextMap()["hints"])
+
+	stats := renderer.Stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(3), stats[0].Renders)
+	assert.Equal(t, int64(2), stats[0].OverBudget)
+	assert.Equal(t, 80.0, stats[0].MaxMS)
+	assert.Equal(t, len("<p>toast</p>"), stats[0].MaxBytes)
+}
+
+// BenchmarkRenderFragments renders every fragment sample; compare ns/op with
+// server.template_render_budget when changing fragment markup
+func BenchmarkRenderFragments(b *testing.B) {
+	templates, err := parseTemplates()
+	require.NoError(b, err)
+	registry, err := NewFragmentRegistry(NewTemplateRenderer(templates, 0, false, zap.NewNop()))
+	require.NoError(b, err)
+
+	for _, name := range registry.Names() {
+		spec, _ := registry.Spec(name)
+		samples := spec.Samples()
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, sample := range samples {
+					if err := registry.RenderSample(io.Discard, name, sample); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Package webserver provides the admin queue of reported recipes and
+// comments
+package webserver
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// reportsPageSize is how many open reports the admin queue shows
+const reportsPageSize = 50
+
+// handleAdminReports serves the report queue, a fragment of /admin
+func (s *WebServer) handleAdminReports(w http.ResponseWriter, r *http.Request) {
+	s.renderReports(w, r)
+}
+
+// handleHTMXReviewReport resolves a report, with the note from the
+// hx-prompt answer, or dismisses it
+func (s *WebServer) handleHTMXReviewReport(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	reportID := chi.URLParam(r, "id")
+	resolve := r.FormValue("resolve") == "true"
+
+	if err := s.apiClient.ReviewReport(r.Context(), session.AccessToken, reportID, resolve, r.Header.Get("HX-Prompt")); err != nil {
+		s.logger.Warn("Report review failed", zap.String("report_id", reportID), zap.Bool("resolve", resolve), zap.Error(err))
+		s.writeToastOnly(w, "We couldn't update that report. Another admin may have handled it.")
+		return
+	}
+	s.renderReports(w, r)
+}
+
+func (s *WebServer) renderReports(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	page, err := s.apiClient.ListReports(r.Context(), session.AccessToken, reportsPageSize)
+	if err != nil {
+		s.adminUnavailable(w, r, "Reports unavailable", err)
+		return
+	}
+
+	view := NewReportsView(page, s.generateCSRFToken(session.ID))
+	s.renderGraph(w, r, "Reports - Admin - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderReports(buf, view)
+	})
+}
@@ -2,9 +2,13 @@
 package webserver
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"crypto/subtle"
 	"embed"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"html"
@@ -13,13 +17,18 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/alchemorsel/v3/internal/domain/recipe/translation"
 	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	appmiddleware "github.com/alchemorsel/v3/internal/infrastructure/http/middleware"
+	"github.com/alchemorsel/v3/internal/infrastructure/observability/metrics"
 	"github.com/alchemorsel/v3/internal/infrastructure/performance"
 	"github.com/alchemorsel/v3/pkg/healthcheck"
+	"github.com/alchemorsel/v3/pkg/shadow"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
@@ -39,13 +48,16 @@ type WebServer struct {
 	router         *chi.Mux
 	apiClient      *APIClient
 	sessionStore   *SessionStore
-	templates      *template.Template
+	templates      *TemplateRenderer
+	fragments      *FragmentRegistry
+	home           *HomeLayout
 	healthCheck    *healthcheck.EnterpriseHealthCheck
 	rateLimitStore *sync.Map // For rate limiting
 	csrfSecret     []byte    // For CSRF protection
 	// 14KB Optimization Components
 	orchestrator   *performance.OptimizationOrchestrator
 	httpIntegration *performance.HTTPIntegration
+	mirror          *shadow.Mirror
 }
 
 // NewWebServer creates a new web frontend server instance
@@ -56,14 +68,21 @@ func NewWebServer(
 	sessionStore *SessionStore,
 	healthCheck *healthcheck.EnterpriseHealthCheck,
 ) (*WebServer, error) {
-	// Parse templates
+	// Parse and precompile templates
 	log.Info("Parsing templates...")
-	templates, err := parseTemplates()
+	parsed, err := parseTemplates()
 	if err != nil {
 		log.Error("Failed to parse templates", zap.Error(err))
 		return nil, fmt.Errorf("failed to parse templates: %w", err)
 	}
-	log.Info("Templates parsed successfully")
+	templates := NewTemplateRenderer(parsed, cfg.Server.TemplateRenderBudget, pprofEnabled(cfg), log.Named("templates"))
+	log.Info("Templates parsed successfully", zap.Duration("render_budget", cfg.Server.TemplateRenderBudget))
+
+	fragments, err := NewFragmentRegistry(templates)
+	if err != nil {
+		log.Error("Failed to register HTMX fragments", zap.Error(err))
+		return nil, fmt.Errorf("failed to register fragments: %w", err)
+	}
 
 	// Initialize 14KB optimization system
 	log.Info("Initializing 14KB optimization system...")
@@ -95,12 +114,15 @@ func NewWebServer(
 		apiClient:      apiClient,
 		sessionStore:   sessionStore,
 		templates:      templates,
+		fragments:      fragments,
+		home:           NewHomeLayout(cfg),
 		healthCheck:    healthCheck,
 		rateLimitStore: &sync.Map{},
 		csrfSecret:     []byte("secure-csrf-secret-key-32-chars"), // TODO: Generate from config
 		orchestrator:   orchestrator,
 		httpIntegration: httpIntegration,
 	}
+	server.mirror = appmiddleware.NewShadowMirror(cfg.Shadow, anonymousSession, log)
 
 	server.router = server.setupRoutes()
 	server.server = &http.Server{
@@ -121,6 +143,7 @@ func (s *WebServer) setupRoutes() *chi.Mux {
 	// SECURITY ENHANCEMENT: Enhanced middleware stack with security headers
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
+	r.Use(metrics.Middleware)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	// 14KB OPTIMIZATION: Apply advanced compression middleware instead of basic compress
@@ -128,6 +151,9 @@ func (s *WebServer) setupRoutes() *chi.Mux {
 	r.Use(s.securityHeadersMiddleware)
 	r.Use(s.sessionMiddleware)
 	r.Use(s.rateLimitMiddleware)
+	if s.mirror != nil {
+		r.Use(s.mirror.Middleware)
+	}
 
 	// Static files - serve with 14KB optimization
 	optimizedStaticHandler := s.httpIntegration.StaticOptimizationHandler("web/static")
@@ -138,21 +164,72 @@ func (s *WebServer) setupRoutes() *chi.Mux {
 	
 	// Development tools (only in non-production)
 	if !s.config.IsProduction() {
+		r.Get("/dev/fragments", s.handleFragmentGallery)
+		r.Get("/dev/a11y", s.handleA11yAudit)
+		r.Get("/dev/templates", s.handleTemplateStats)
 		r.Mount("/dev", s.httpIntegration.DevModeHandler())
 	}
 	
+	// Profiling for slow renders reported against the template budget
+	if pprofEnabled(s.config) {
+		r.Mount("/debug", middleware.Profiler())
+	}
+	
 	// Health check endpoints
 	r.Get("/health", s.handleHealthCheck)
 	r.Get("/ready", s.handleReadinessCheck)
 	r.Get("/live", s.handleLivenessCheck)
 
+	// Prometheus scrape endpoint
+	if s.config.Monitoring.EnableMetrics {
+		r.Handle(metrics.Path, metrics.Handler())
+	}
+
 	// Public pages
 	r.Get("/", s.handleHome)
+	r.Get("/home/sections/{name}", s.handleHomeSection)
 	r.Get("/login", s.handleLoginPage)
 	r.Post("/login", s.handleLogin)
 	r.Get("/register", s.handleRegisterPage)
 	r.Post("/register", s.handleRegister)
 	r.Post("/logout", s.handleLogout)
+	r.With(s.csrfMiddleware).Post("/theme", s.handleThemeToggle)
+	r.With(s.csrfMiddleware).Post("/units", s.handleUnits)
+
+	// Related recipe sections from the recipe graph, public like the
+	// recipes they link to
+	r.Get("/recipes/{id}/related", s.handleRecipeRelated)
+	r.Get("/recipes/{id}/similar", s.handleRecipeSimilar)
+	r.Get("/graph/{kind}/{key}", s.handleGraphRecipes)
+
+	// Technique pages and cook mode steps linking to them
+	r.Get("/techniques/{slug}", s.handleTechnique)
+	r.Get("/recipes/{id}/steps", s.handleCookSteps)
+	r.Get("/recipes/{id}/allergens", s.handleRecipeAllergens)
+	r.Get("/recipes/{id}/nutrition", s.handleRecipeNutrition)
+	r.Get("/recipes/{id}/ingredients", s.handleRecipeIngredients)
+	r.Get("/ingredients/{name}/substitutes", s.handleSubstitutes)
+
+	// Recipe timelines planned back from a serve time
+	r.Get("/recipes/{id}/timeline", s.handleRecipeTimeline)
+	r.Get("/recipes/{id}/timeline.ics", s.handleRecipeTimelineICS)
+
+	// Printable recipes and downloads, as public as the recipe
+	r.Get("/recipes/{id}/print", s.handleRecipePrint)
+	r.Get("/recipes/{id}/export", s.handleRecipeExport)
+
+	// Remix battles; voting itself goes through /htmx
+	r.Get("/battles/{id}", s.handleBattle)
+
+	// The AI chef is open to guests, who chat and save recipes through
+	// their own guest session until they sign up
+	r.Get("/ai/chat", s.handleAIChatPage)
+	r.Route("/guest", func(r chi.Router) {
+		r.Use(s.csrfMiddleware)
+		r.Use(s.inputValidationMiddleware)
+		r.Post("/chat", s.handleGuestChat)
+		r.Post("/recipes", s.handleGuestSaveRecipe)
+	})
 
 	// Protected pages (require authentication)
 	r.Group(func(r chi.Router) {
@@ -161,14 +238,24 @@ func (s *WebServer) setupRoutes() *chi.Mux {
 		// Recipe pages
 		r.Get("/recipes", s.handleRecipeList)
 		r.Get("/recipes/new", s.handleNewRecipePage)
+		r.Get("/recipes/import", s.handleImportRecipePage)
+		r.Route("/recipes/wizard", func(r chi.Router) {
+			s.mountWizard(r, s.newRecipeWizard())
+			r.With(s.csrfMiddleware).Post("/ingredients/preview", s.handleIngredientPreview)
+		})
 		r.Post("/recipes", s.handleCreateRecipe)
 		r.Get("/recipes/{id}", s.handleRecipeDetail)
 		r.Get("/recipes/{id}/edit", s.handleEditRecipePage)
-		r.Put("/recipes/{id}", s.handleUpdateRecipe)
-		r.Delete("/recipes/{id}", s.handleDeleteRecipe)
+		r.Get("/recipes/{id}/stats", s.handleRecipeStats)
+		r.Get("/recipes/{id}/stats.csv", s.handleRecipeStatsCSV)
+		r.With(s.csrfMiddleware).Put("/recipes/{id}", s.handleUpdateRecipe)
+		r.With(s.csrfMiddleware).Delete("/recipes/{id}", s.handleDeleteRecipe)
+		// Form posts for browsers without HTMX
+		r.With(s.csrfMiddleware).Post("/recipes/{id}/edit", s.handleUpdateRecipe)
+		r.With(s.csrfMiddleware).Post("/recipes/{id}/delete", s.handleDeleteRecipe)
+		r.With(s.csrfMiddleware).Post("/recipes/{id}/fork", s.handleForkRecipe)
 		
 		// AI features
-		r.Get("/ai/chat", s.handleAIChatPage)
 		r.Post("/ai/generate", s.handleAIGenerate)
 		r.Post("/ai/suggest", s.handleAISuggest)
 		
@@ -176,6 +263,15 @@ func (s *WebServer) setupRoutes() *chi.Mux {
 		r.Get("/profile", s.handleProfile)
 		r.Put("/profile", s.handleUpdateProfile)
 		r.Get("/favorites", s.handleFavorites)
+		r.Get("/dashboard/analytics", s.handleAuthorAnalytics)
+		r.Get("/notifications", s.handleNotifications)
+		
+		// Admin section; the API checks the role
+		r.Get("/admin", s.handleAdmin)
+		r.Get("/admin/users", s.handleAdminUsers)
+		r.Get("/admin/ai-content", s.handleAdminAIContent)
+		r.Get("/admin/comments", s.handleAdminComments)
+		r.Get("/admin/reports", s.handleAdminReports)
 	})
 
 	// HTMX endpoints (partial templates) - ALL require authentication
@@ -190,14 +286,36 @@ func (s *WebServer) setupRoutes() *chi.Mux {
 		
 		r.Post("/search", s.handleHTMXSearch)
 		r.Post("/recipes/{id}/like", s.handleHTMXLike)
+		r.Post("/recipes/{id}/unpublish", s.handleHTMXUnpublish)
+		r.Post("/undo/{token}", s.handleHTMXUndo)
 		r.Post("/recipes/{id}/rate", s.handleHTMXRate)
 		r.Get("/recipes/{id}/comments", s.handleHTMXComments)
 		r.Post("/recipes/{id}/comments", s.handleHTMXAddComment)
+		r.Put("/recipes/{id}/comments/{commentID}", s.handleHTMXEditComment)
+		r.Delete("/recipes/{id}/comments/{commentID}", s.handleHTMXDeleteComment)
+		r.Post("/recipes/{id}/comments/{commentID}/flag", s.handleHTMXFlagComment)
 		r.Get("/notifications", s.handleHTMXNotifications)
+		r.Get("/notifications/badge", s.handleHTMXNotificationBadge)
+		r.Post("/notifications/read-all", s.handleHTMXMarkNotificationsRead)
+		r.Put("/profile/notifications", s.handleHTMXNotificationPrefs)
+		r.Put("/profile/diet", s.handleHTMXDietaryPrefs)
+		r.Post("/profile/deletion", s.handleHTMXDeleteAccount)
+		r.Delete("/profile/deletion", s.handleHTMXCancelAccountDeletion)
 		
 		// AI Chat endpoints - Now properly secured
 		r.Post("/ai/chat", s.handleHTMXAIChat)
 		r.Post("/recipes/search", s.handleHTMXRecipeSearch)
+		r.Post("/recipes/generate", s.handleHTMXGenerateFromSearch)
+		r.Post("/recipes/import/preview", s.handleHTMXImportPreview)
+		r.Post("/recipes/import", s.handleHTMXImportRecipe)
+		r.Post("/battles/{id}/votes", s.handleHTMXBattleVote)
+		
+		// Admin actions
+		r.Post("/admin/users/{id}/suspend", s.handleHTMXSuspendUser)
+		r.Post("/admin/users/{id}/reinstate", s.handleHTMXReinstateUser)
+		r.Post("/admin/recipes/{id}/unpublish", s.handleHTMXAdminUnpublish)
+		r.Post("/admin/comments/{id}/flags", s.handleHTMXReviewFlaggedComment)
+		r.Post("/admin/reports/{id}", s.handleHTMXReviewReport)
 	})
 
 	return r
@@ -216,7 +334,18 @@ func (s *WebServer) Start() error {
 // Shutdown gracefully shuts down the web server
 func (s *WebServer) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down Web Frontend server...")
-	return s.server.Shutdown(ctx)
+	err := s.server.Shutdown(ctx)
+	if s.mirror != nil {
+		s.mirror.Stop(ctx)
+	}
+	return err
+}
+
+// anonymousSession limits shadowing to visitors who are not signed in, so
+// no page rendered for a user leaves production
+func anonymousSession(r *http.Request) bool {
+	session, ok := r.Context().Value("session").(*Session)
+	return ok && session.UserID == ""
 }
 
 // parseTemplates parses all HTML templates from the embedded filesystem
@@ -243,6 +372,7 @@ func parseTemplates() (*template.Template, error) {
 				return t.Format("Jan 2")
 			}
 		},
+		"themeCSS": themeCSS,
 		"truncate": func(s string, n int) string {
 			if len(s) <= n {
 				return s
@@ -332,6 +462,7 @@ func parseTemplates() (*template.Template, error) {
 		"contains": func(substr, str string) bool {
 			return strings.Contains(str, substr)
 		},
+		"ariaLabel": ariaLabel,
 		"seq": func(start, end int) []int {
 			var result []int
 			for i := start; i <= end; i++ {
@@ -377,6 +508,10 @@ func parseTemplates() (*template.Template, error) {
 		return nil, fmt.Errorf("failed to walk templates: %w", err)
 	}
 
+	if err := precompileTemplates(tmpl); err != nil {
+		return nil, fmt.Errorf("failed to precompile templates: %w", err)
+	}
+
 	// Debug: Log template names that were loaded
 	fmt.Printf("Loaded templates: ")
 	for _, t := range tmpl.Templates() {
@@ -525,37 +660,6 @@ func (s *WebServer) handleLivenessCheck(w http.ResponseWriter, r *http.Request)
 	})
 }
 
-func (s *WebServer) handleHome(w http.ResponseWriter, r *http.Request) {
-	// Get session to check authentication state
-	session := r.Context().Value("session").(*Session)
-	
-	// Determine if user is authenticated and token is valid
-	var user interface{}
-	isAuthenticated := false
-	
-	if session.UserID != "" && session.AccessToken != "" {
-		// Verify token is still valid with API
-		if s.apiClient.VerifyToken(r.Context(), session.AccessToken) {
-			isAuthenticated = true
-			user = map[string]interface{}{
-				"ID":   session.UserID,
-				"Name": "User", // Could fetch from API later
-			}
-		} else {
-			// Token invalid, clear session
-			session.Clear()
-			session.Save(w)
-		}
-	}
-	
-	// Render home page with user context
-	s.renderTemplate(w, "home", map[string]interface{}{
-		"Title":           "Welcome to Alchemorsel",
-		"User":            user,
-		"IsAuthenticated": isAuthenticated,
-	})
-}
-
 func (s *WebServer) handleLoginPage(w http.ResponseWriter, r *http.Request) {
 	s.renderTemplate(w, "login", map[string]interface{}{
 		"Title": "Login - Alchemorsel",
@@ -582,6 +686,10 @@ func (s *WebServer) handleLogin(w http.ResponseWriter, r *http.Request) {
 	session.UserID = resp.User.ID
 	session.AccessToken = resp.AccessToken
 	session.RefreshToken = resp.RefreshToken
+	if validTheme(resp.User.Theme) {
+		session.SetValue(sessionKeyTheme, resp.User.Theme)
+	}
+	s.claimGuestRecipes(r.Context(), session)
 	session.Save(w)
 
 	// Redirect to home or requested page
@@ -621,6 +729,7 @@ func (s *WebServer) handleRegister(w http.ResponseWriter, r *http.Request) {
 		session.UserID = resp.User.ID
 		session.AccessToken = loginResp.AccessToken
 		session.RefreshToken = loginResp.RefreshToken
+		s.claimGuestRecipes(r.Context(), session)
 		session.Save(w)
 	}
 
@@ -638,22 +747,6 @@ func (s *WebServer) handleLogout(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
 
-func (s *WebServer) handleRecipeList(w http.ResponseWriter, r *http.Request) {
-	session := r.Context().Value("session").(*Session)
-	
-	// Get recipes from API
-	recipes, err := s.apiClient.GetRecipes(r.Context(), session.AccessToken)
-	if err != nil {
-		s.renderError(w, "Failed to load recipes", err)
-		return
-	}
-
-	s.renderTemplate(w, "recipes", map[string]interface{}{
-		"Title":   "Recipes - Alchemorsel",
-		"Recipes": recipes,
-	})
-}
-
 func (s *WebServer) handleNewRecipePage(w http.ResponseWriter, r *http.Request) {
 	s.renderTemplate(w, "recipe-new", map[string]interface{}{
 		"Title": "New Recipe - Alchemorsel",
@@ -665,33 +758,28 @@ func (s *WebServer) handleCreateRecipe(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/recipes", http.StatusSeeOther)
 }
 
-func (s *WebServer) handleRecipeDetail(w http.ResponseWriter, r *http.Request) {
-	// TODO: Get recipe ID and fetch from API
-	s.renderTemplate(w, "recipe-detail", map[string]interface{}{
-		"Title": "Recipe - Alchemorsel",
-	})
-}
-
-func (s *WebServer) handleEditRecipePage(w http.ResponseWriter, r *http.Request) {
-	// TODO: Get recipe and render edit form
-	s.renderTemplate(w, "recipe-edit", map[string]interface{}{
-		"Title": "Edit Recipe - Alchemorsel",
-	})
-}
-
-func (s *WebServer) handleUpdateRecipe(w http.ResponseWriter, r *http.Request) {
-	// TODO: Update recipe via API
-	http.Redirect(w, r, "/recipes", http.StatusSeeOther)
-}
-
-func (s *WebServer) handleDeleteRecipe(w http.ResponseWriter, r *http.Request) {
-	// TODO: Delete recipe via API
-	http.Redirect(w, r, "/recipes", http.StatusSeeOther)
-}
-
+// handleAIChatPage serves the AI chef. Visitors who are not signed in chat
+// as guests when guest mode is on.
 func (s *WebServer) handleAIChatPage(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	guest := session.UserID == "" || session.AccessToken == ""
+	if guest && !s.config.Guest.Enabled {
+		http.Redirect(w, r, "/login?redirect="+r.URL.Path, http.StatusSeeOther)
+		return
+	}
+
+	chatURL := "/htmx/ai/chat"
+	if guest {
+		chatURL = "/guest/chat"
+	}
 	s.renderTemplate(w, "ai-chat", map[string]interface{}{
-		"Title": "AI Chef - Alchemorsel",
+		"Title":        "AI Chef - Alchemorsel",
+		"Theme":        sessionTheme(session),
+		"Guest":        guest,
+		"GuestRecipes": s.config.Guest.MaxRecipes,
+		"GuestHours":   int(s.config.Guest.TTL.Hours()),
+		"ChatURL":      chatURL,
+		"CSRFToken":    s.generateCSRFToken(session.ID),
 	})
 }
 
@@ -705,21 +793,14 @@ func (s *WebServer) handleAISuggest(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("<div>AI suggestions</div>"))
 }
 
-func (s *WebServer) handleProfile(w http.ResponseWriter, r *http.Request) {
-	s.renderTemplate(w, "profile", map[string]interface{}{
-		"Title": "Profile - Alchemorsel",
-	})
-}
-
 func (s *WebServer) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
 	// TODO: Update profile via API
 	http.Redirect(w, r, "/profile", http.StatusSeeOther)
 }
 
+// handleFavorites sends /favorites to the saved recipes section
 func (s *WebServer) handleFavorites(w http.ResponseWriter, r *http.Request) {
-	s.renderTemplate(w, "favorites", map[string]interface{}{
-		"Title": "Favorites - Alchemorsel",
-	})
+	http.Redirect(w, r, "/home/sections/"+HomeSaved, http.StatusSeeOther)
 }
 
 // HTMX handlers (return partial HTML)
@@ -730,28 +811,35 @@ func (s *WebServer) handleHTMXSearch(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *WebServer) handleHTMXLike(w http.ResponseWriter, r *http.Request) {
-	// TODO: Like recipe and return updated button
-	w.Write([]byte("<button>Liked</button>"))
-}
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
 
-func (s *WebServer) handleHTMXRate(w http.ResponseWriter, r *http.Request) {
-	// TODO: Rate recipe and return updated rating
-	w.Write([]byte("<div>Rating updated</div>"))
-}
+	status, err := s.apiClient.LikeRecipe(r.Context(), session.AccessToken, recipeID)
+	if err != nil {
+		s.logger.Warn("Liking recipe failed", zap.String("recipe_id", recipeID), zap.Error(err))
+		s.writeToastOnly(w, "We couldn't like that recipe. Please try again.")
+		return
+	}
 
-func (s *WebServer) handleHTMXComments(w http.ResponseWriter, r *http.Request) {
-	// TODO: Get comments and return HTML
-	w.Write([]byte("<div>Comments</div>"))
-}
+	unread, _ := session.GetValue(sessionKeyUnreadCount)
+	unreadCount, _ := unread.(int)
 
-func (s *WebServer) handleHTMXAddComment(w http.ResponseWriter, r *http.Request) {
-	// TODO: Add comment and return updated comments
-	w.Write([]byte("<div>Comment added</div>"))
+	// One response refreshes the button plus the regions that depend on it
+	resp := NewHTMXResponse().
+		Main(func(buf *bytes.Buffer) error {
+			return s.fragments.RenderLikeButton(buf, LikeButtonView{RecipeID: recipeID, Liked: status.Liked, Count: status.Likes})
+		}).
+		OOB("#notification-badge", OOBInnerHTML, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderNotificationBadge(buf, NotificationBadgeView{Unread: unreadCount})
+		}).
+		Trigger("recipeLiked", map[string]interface{}{"id": recipeID, "liked": status.Liked, "likes": status.Likes})
+
+	s.writeHTMX(w, resp)
 }
 
-func (s *WebServer) handleHTMXNotifications(w http.ResponseWriter, r *http.Request) {
-	// TODO: Get notifications and return HTML
-	w.Write([]byte("<div>Notifications</div>"))
+func (s *WebServer) handleHTMXRate(w http.ResponseWriter, r *http.Request) {
+	// TODO: Rate recipe and return updated rating
+	w.Write([]byte("<div>Rating updated</div>"))
 }
 
 func (s *WebServer) handleHTMXAIChat(w http.ResponseWriter, r *http.Request) {
@@ -778,51 +866,25 @@ func (s *WebServer) handleHTMXAIChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// SECURITY: Sanitize user input to prevent XSS
-	message = html.EscapeString(message)
-
+	// SECURITY: The chat-message fragment escapes message text, so no
+	// markup from user input reaches the response
 	s.logger.Debug("AI Chat request", zap.String("message", message), zap.String("user_id", session.UserID))
 
 	// TODO: Call AI service to get response
-	// SECURITY NOTE: User message is now properly sanitized above
-	// For now, return a mock response with sanitized input
-	aiResponse := `<div class="chat-message user-message" style="margin-bottom: 1rem;">
-		<div style="display: flex; justify-content: flex-end; gap: 0.75rem;">
-			<div class="message-content" style="flex: 1; background: linear-gradient(135deg, #4f46e5 0%, #7c3aed 100%); color: white; padding: 1rem; border-radius: 1rem; max-width: 80%;">
-				<div style="font-weight: 600; margin-bottom: 0.5rem;">You</div>
-				<div style="line-height: 1.6;">` + message + `</div>
-				<div style="font-size: 0.75rem; opacity: 0.8; margin-top: 0.5rem;">Just now</div>
-			</div>
-			<div class="avatar user-avatar" style="width: 2.5rem; height: 2.5rem; border-radius: 50%; background: linear-gradient(135deg, #4f46e5 0%, #7c3aed 100%); display: flex; align-items: center; justify-content: center; color: white; font-weight: bold; flex-shrink: 0;">
-				👤
-			</div>
-		</div>
-	</div>
-	<div class="chat-message ai-message" style="margin-bottom: 1rem;">
-		<div style="display: flex; align-items: flex-start; gap: 0.75rem;">
-			<div class="avatar ai-avatar" style="width: 2.5rem; height: 2.5rem; border-radius: 50%; background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); display: flex; align-items: center; justify-content: center; color: white; font-weight: bold; flex-shrink: 0;">
-				👨‍🍳
-			</div>
-			<div class="message-content" style="flex: 1; background: #ffffff; padding: 1rem; border-radius: 1rem; border: 1px solid #e2e8f0; box-shadow: 0 1px 3px rgba(0,0,0,0.1);">
-				<div style="font-weight: 600; color: #4f46e5; margin-bottom: 0.5rem;">AI Chef</div>
-				<div style="line-height: 1.6;">
-					Great question! For chicken and vegetables, I recommend trying a delicious **Chicken Stir-Fry**. Here's what you can make:
-					<br><br>
-					🍗 **Quick Chicken & Veggie Stir-Fry** (20 mins)<br>
-					• 1 lb chicken breast, sliced<br>
-					• 2 cups mixed vegetables (bell peppers, broccoli, carrots)<br>
-					• 2 tbsp soy sauce, 1 tbsp garlic, ginger<br>
-					• Serve over rice or noodles
-					<br><br>
-					Would you like the full recipe with step-by-step instructions?
-				</div>
-				<div style="font-size: 0.75rem; color: #9ca3af; margin-top: 0.5rem;">Just now</div>
-			</div>
-		</div>
-	</div>`
-
-	w.Header().Set("Content-Type", "text/html")
-	w.Write([]byte(aiResponse))
+	// For now, return a mock response
+	aiResponse := "Great question! For chicken and vegetables, I recommend a quick Chicken & Veggie Stir-Fry (20 mins):\n" +
+		"• 1 lb chicken breast, sliced\n" +
+		"• 2 cups mixed vegetables (bell peppers, broccoli, carrots)\n" +
+		"• 2 tbsp soy sauce, 1 tbsp garlic, ginger\n" +
+		"• Serve over rice or noodles\n" +
+		"Would you like the full recipe with step-by-step instructions?"
+
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		if err := s.fragments.RenderChatMessage(buf, NewChatMessageView(true, "You", message, "Just now")); err != nil {
+			return err
+		}
+		return s.fragments.RenderChatMessage(buf, NewChatMessageView(false, "AI Chef", aiResponse, "Just now"))
+	})
 }
 
 func (s *WebServer) handleHTMXRecipeSearch(w http.ResponseWriter, r *http.Request) {
@@ -848,47 +910,130 @@ func (s *WebServer) handleHTMXRecipeSearch(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// SECURITY: Sanitize search query to prevent XSS and injection attacks
-	query = html.EscapeString(query)
-
 	s.logger.Debug("Recipe search", zap.String("query", query), zap.String("user_id", session.UserID))
 
-	// TODO: Call API to search recipes
-	// For now, return mock search results
-	searchResults := `<div class="search-results">
-		<h3 style="margin-bottom: 1rem;">Search Results for "` + query + `"</h3>
-		<div class="recipe-grid" style="display: grid; grid-template-columns: repeat(auto-fill, minmax(250px, 1fr)); gap: 1rem;">
-			<div class="recipe-card" style="background: white; border-radius: 0.5rem; box-shadow: 0 1px 3px rgba(0,0,0,0.1); overflow: hidden;">
-				<div style="height: 120px; background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); display: flex; align-items: center; justify-content: center; color: white; font-size: 2rem;">🍗</div>
-				<div style="padding: 1rem;">
-					<h4 style="margin-bottom: 0.5rem;">Chicken Stir-Fry</h4>
-					<p style="color: #718096; font-size: 0.875rem; margin-bottom: 1rem;">Quick and healthy chicken with vegetables</p>
-					<div style="display: flex; justify-content: space-between; align-items: center;">
-						<span style="color: #f39c12;">★★★★★</span>
-						<span style="color: #718096; font-size: 0.875rem;">20 min</span>
-					</div>
-				</div>
-			</div>
-			<div class="recipe-card" style="background: white; border-radius: 0.5rem; box-shadow: 0 1px 3px rgba(0,0,0,0.1); overflow: hidden;">
-				<div style="height: 120px; background: linear-gradient(135deg, #f093fb 0%, #f5576c 100%); display: flex; align-items: center; justify-content: center; color: white; font-size: 2rem;">🥗</div>
-				<div style="padding: 1rem;">
-					<h4 style="margin-bottom: 0.5rem;">Garden Salad</h4>
-					<p style="color: #718096; font-size: 0.875rem; margin-bottom: 1rem;">Fresh vegetables with herb dressing</p>
-					<div style="display: flex; justify-content: space-between; align-items: center;">
-						<span style="color: #f39c12;">★★★★☆</span>
-						<span style="color: #718096; font-size: 0.875rem;">10 min</span>
-					</div>
-				</div>
-			</div>
-		</div>
-	</div>`
-
-	w.Header().Set("Content-Type", "text/html")
-	w.Write([]byte(searchResults))
+	filters := searchFiltersFromForm(r)
+	result, err := s.apiClient.SearchRecipes(r.Context(), session.AccessToken, query, filters)
+	if err != nil {
+		s.logger.Error("Recipe search failed", zap.String("query", query), zap.Error(err))
+		w.Write([]byte("<div class=\"error\">Search is unavailable right now. Please try again.</div>"))
+		return
+	}
+	// The user's own diets narrowed the search; they show as chosen
+	// filters so they can be lifted
+	if len(result.DefaultDiets) > 0 {
+		filters.Diets, filters.DietsChosen = result.DefaultDiets, true
+	}
+
+	// Nothing matched: offer corrected queries and on-the-spot generation.
+	// With filters set the facets are kept so they can be loosened.
+	if len(result.Recipes) == 0 && !filters.Active() {
+		view := NewSearchFallbackView(query, result.Fallback, s.generateCSRFToken(session.ID))
+		s.renderFragment(w, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderSearchFallback(buf, view)
+		})
+		return
+	}
+
+	facets := NewSearchFacetsView(query, result.Facets, filters, s.generateCSRFToken(session.ID))
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		// SECURITY: query is escaped before being written into the heading
+		fmt.Fprintf(buf, `<div class="search-results"><h3 style="margin-bottom: 1rem;">Search Results for "%s"</h3>`, html.EscapeString(query))
+		if err := s.fragments.RenderSearchFacets(buf, facets); err != nil {
+			return err
+		}
+		if len(result.Recipes) == 0 {
+			buf.WriteString(`<p role="status" style="color: #4a5568;">No recipes match these filters.</p></div>`)
+			return nil
+		}
+		buf.WriteString(`<div class="recipe-grid" style="display: grid; grid-template-columns: repeat(auto-fill, minmax(250px, 1fr)); gap: 1rem;">`)
+		for _, recipe := range result.Recipes {
+			if err := s.fragments.RenderRecipeCard(buf, NewRecipeCardView(recipe)); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(`</div></div>`)
+		return nil
+	})
+}
+
+// searchFilterValue is the shape of a cuisine, difficulty or diet filter
+var searchFilterValue = regexp.MustCompile(`^[a-z_]{1,30}$`)
+
+// searchFiltersFromForm reads the search-facets fields, dropping values
+// that cannot be filters
+func searchFiltersFromForm(r *http.Request) SearchFilters {
+	values := func(name string) []string {
+		var kept []string
+		for _, value := range r.Form[name] {
+			if searchFilterValue.MatchString(value) {
+				kept = append(kept, value)
+			}
+		}
+		return kept
+	}
+	filters := SearchFilters{
+		Cuisines:     values("cuisine"),
+		Difficulties: values("difficulty"),
+		Diets:        values("diet"),
+		DietsChosen:  r.FormValue("diets_chosen") != "",
+	}
+	if minutes, err := strconv.Atoi(r.FormValue("max_time")); err == nil && minutes > 0 {
+		filters.MaxTime = minutes
+	}
+	if ai := r.FormValue("ai_generated"); ai == "true" || ai == "false" {
+		filters.AIGenerated = ai
+	}
+	filters.Semantic = r.FormValue("mode") == "semantic"
+	return filters
+}
+
+// handleHTMXGenerateFromSearch generates a recipe for a search that found
+// nothing and shows it in place of the empty results
+func (s *WebServer) handleHTMXGenerateFromSearch(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	prompt := strings.TrimSpace(r.FormValue("prompt"))
+	if prompt == "" || len(prompt) > 100 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("<div class=\"error\">Enter a search term between 1 and 100 characters</div>"))
+		return
+	}
+
+	// Write the recipe in the browser's language when it is supported
+	language := ""
+	if preferred, ok := translation.FromAcceptLanguage(r.Header.Get("Accept-Language")); ok {
+		language = preferred.Tag
+	}
+
+	generated, err := s.apiClient.GenerateRecipe(r.Context(), session.AccessToken, prompt, language)
+	if err != nil {
+		s.logger.Error("Recipe generation from search failed", zap.String("prompt", prompt), zap.Error(err))
+		w.Write([]byte("<div class=\"error\">We couldn't generate a recipe right now. Please try again.</div>"))
+		return
+	}
+
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		fmt.Fprintf(buf, `<div class="search-results"><h3 style="margin-bottom: 1rem;">Generated for "%s"</h3>`, html.EscapeString(prompt))
+		buf.WriteString(`<div class="recipe-grid" style="display: grid; grid-template-columns: repeat(auto-fill, minmax(250px, 1fr)); gap: 1rem;">`)
+		if err := s.fragments.RenderRecipeCard(buf, NewRecipeCardView(*generated)); err != nil {
+			return err
+		}
+		buf.WriteString(`</div></div>`)
+		return nil
+	})
 }
 
 // Helper methods
 
+// sandboxBanner is the notice pages show in sandbox mode, or ""
+func (s *WebServer) sandboxBanner() string {
+	if s.config == nil || !s.config.Sandbox.Enabled {
+		return ""
+	}
+	return s.config.Sandbox.Banner
+}
+
 func (s *WebServer) renderTemplate(w http.ResponseWriter, name string, data interface{}) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	
@@ -907,6 +1052,9 @@ func (s *WebServer) renderTemplate(w http.ResponseWriter, name string, data inte
 	if templateData["BaseURL"] == nil {
 		templateData["BaseURL"] = "http://localhost:8080"
 	}
+	if banner := s.sandboxBanner(); banner != "" {
+		templateData["Sandbox"] = banner
+	}
 	
 	// Debug: Log template execution
 	s.logger.Debug("Executing template", 
@@ -939,6 +1087,46 @@ func (s *WebServer) renderTemplate(w http.ResponseWriter, name string, data inte
 	}
 }
 
+// hydrateAuthors fills in missing author names using the batching user loader
+func (s *WebServer) hydrateAuthors(ctx context.Context, token string, recipes []RecipeResponse) {
+	var authorIDs []string
+	for _, recipe := range recipes {
+		if recipe.AuthorName == "" && recipe.AuthorID != "" {
+			authorIDs = append(authorIDs, recipe.AuthorID)
+		}
+	}
+	if len(authorIDs) == 0 {
+		return
+	}
+
+	authors, err := s.apiClient.LoadUsers(ctx, token, authorIDs)
+	if err != nil {
+		s.logger.Warn("Failed to load recipe authors", zap.Error(err))
+		return
+	}
+
+	for i := range recipes {
+		if author, ok := authors[recipes[i].AuthorID]; ok && recipes[i].AuthorName == "" {
+			recipes[i].AuthorName = author.Name
+		}
+	}
+}
+
+// renderFragment buffers fragment output so a template failure never leaves
+// a half-written swap target in the page
+func (s *WebServer) renderFragment(w http.ResponseWriter, render func(buf *bytes.Buffer) error) {
+	var buf bytes.Buffer
+	if err := render(&buf); err != nil {
+		s.logger.Error("Failed to render fragment", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`<div class="error">Something went wrong. Please try again.</div>`))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
 func (s *WebServer) renderError(w http.ResponseWriter, message string, err error) {
 	s.logger.Error(message, zap.Error(err))
 	w.WriteHeader(http.StatusInternalServerError)
@@ -971,6 +1159,7 @@ func (s *WebServer) securityHeadersMiddleware(next http.Handler) http.Handler {
 			"img-src 'self' data: https:; " +
 			"font-src 'self' data:; " +
 			"connect-src 'self'; " +
+			"frame-src https://www.youtube-nocookie.com https://player.vimeo.com; " +
 			"frame-ancestors 'none'; " +
 			"base-uri 'none'; " +
 			"object-src 'none';"
@@ -1148,10 +1337,13 @@ type rateLimitEntry struct {
 	requests []time.Time
 }
 
-// generateCSRFToken generates a CSRF token for the given session
+// generateCSRFToken generates a CSRF token for the given session.
+// The token is an HMAC of the session ID so it stays valid for the whole
+// session; embedding a timestamp made tokens expire within the same second.
 func (s *WebServer) generateCSRFToken(sessionID string) string {
-	// Simple CSRF token generation (should use HMAC in production)
-	return fmt.Sprintf("%s:%d", sessionID, time.Now().Unix())
+	mac := hmac.New(sha256.New, s.csrfSecret)
+	mac.Write([]byte(sessionID))
+	return hex.EncodeToString(mac.Sum(nil))
 }
 
 // validateCSRFToken validates a CSRF token
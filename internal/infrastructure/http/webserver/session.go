@@ -196,6 +196,13 @@ func (session *Session) SetValue(key string, value interface{}) {
 	session.Data[key] = value
 }
 
+// Session data keys shared by handlers
+const (
+	sessionKeyUnreadCount = "unread_notifications"
+	sessionKeyTheme       = "theme"
+	sessionKeyUnits       = "units"
+)
+
 // ToJSON serializes session to JSON
 func (session *Session) ToJSON() ([]byte, error) {
 	return json.Marshal(session)
@@ -0,0 +1,125 @@
+// Package webserver provides the technique pages, cook mode steps and the
+// recipe allergen panel
+package webserver
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// handleTechnique serves /techniques/{slug}, the page cook mode steps link to
+func (s *WebServer) handleTechnique(w http.ResponseWriter, r *http.Request) {
+	technique, err := s.apiClient.GetTechnique(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		s.techniqueUnavailable(w, r, "Technique unavailable", err)
+		return
+	}
+
+	view := NewTechniqueView(*technique)
+	s.renderGraph(w, r, view.Name+" - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderTechnique(buf, view)
+	})
+}
+
+// handleCookSteps serves /recipes/{id}/steps: the numbered steps with the
+// techniques each uses and food safety warnings. Signed-in authors see
+// their drafts too.
+func (s *WebServer) handleCookSteps(w http.ResponseWriter, r *http.Request) {
+	session, _ := r.Context().Value("session").(*Session)
+	steps, err := s.apiClient.GetRecipeSteps(r.Context(), sessionToken(r), chi.URLParam(r, "id"), sessionUnits(session))
+	if err != nil {
+		s.techniqueUnavailable(w, r, "Recipe steps unavailable", err)
+		return
+	}
+
+	// Warnings are advice; cook mode works without them
+	safety, err := s.apiClient.GetFoodSafety(r.Context(), sessionToken(r), steps.RecipeID)
+	if err != nil {
+		s.logger.Warn("Food safety check unavailable", zap.String("recipe_id", steps.RecipeID), zap.Error(err))
+	}
+
+	view := NewCookStepsView(*steps, safety)
+	view.Embedded = r.URL.Query().Get("embed") == "1"
+	s.renderGraph(w, r, view.Title+" - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderCookSteps(buf, view)
+	})
+}
+
+// handleRecipeAllergens serves /recipes/{id}/allergens, the allergen panel
+// the recipe page loads next to the steps
+func (s *WebServer) handleRecipeAllergens(w http.ResponseWriter, r *http.Request) {
+	allergens, err := s.apiClient.GetRecipeAllergens(r.Context(), sessionToken(r), chi.URLParam(r, "id"))
+	if err != nil {
+		s.techniqueUnavailable(w, r, "Allergens unavailable", err)
+		return
+	}
+
+	view := NewAllergenPanelView(*allergens)
+	s.renderGraph(w, r, "Allergens - "+allergens.Title+" - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderAllergens(buf, view)
+	})
+}
+
+// handleRecipeNutrition serves /recipes/{id}/nutrition, the nutrition
+// label the recipe page loads under the allergens
+func (s *WebServer) handleRecipeNutrition(w http.ResponseWriter, r *http.Request) {
+	recipe, err := s.apiClient.GetRecipe(r.Context(), sessionToken(r), chi.URLParam(r, "id"))
+	if err != nil {
+		s.techniqueUnavailable(w, r, "Nutrition unavailable", err)
+		return
+	}
+
+	view := NewNutritionLabelView(*recipe)
+	s.renderGraph(w, r, "Nutrition - "+recipe.Title+" - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderNutrition(buf, view)
+	})
+}
+
+// handleRecipeIngredients serves /recipes/{id}/ingredients, the ingredient
+// list the recipe page loads, scaled to ?servings= when the reader picks
+// another serving count and in the units they chose
+func (s *WebServer) handleRecipeIngredients(w http.ResponseWriter, r *http.Request) {
+	session, _ := r.Context().Value("session").(*Session)
+	servings, _ := strconv.Atoi(r.URL.Query().Get("servings"))
+	scaled, err := s.apiClient.ScaleRecipe(r.Context(), sessionToken(r), chi.URLParam(r, "id"), servings, sessionUnits(session))
+	if err != nil {
+		s.techniqueUnavailable(w, r, "Ingredients unavailable", err)
+		return
+	}
+
+	view := NewRecipeIngredientsView(*scaled)
+	if session != nil {
+		view.CSRFToken = s.generateCSRFToken(session.ID)
+	}
+	s.renderGraph(w, r, "Ingredients - "+scaled.Title+" - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderRecipeIngredients(buf, view)
+	})
+}
+
+// handleSubstitutes serves /ingredients/{name}/substitutes, the
+// suggestions an ingredient's "no X?" toggle opens onto
+func (s *WebServer) handleSubstitutes(w http.ResponseWriter, r *http.Request) {
+	found, err := s.apiClient.GetSubstitutes(r.Context(), sessionToken(r), chi.URLParam(r, "name"))
+	if err != nil {
+		s.techniqueUnavailable(w, r, "Substitutes unavailable", err)
+		return
+	}
+
+	view := NewIngredientSubstitutesView(*found)
+	s.renderGraph(w, r, "Substitutes for "+found.Ingredient+" - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderSubstitutes(buf, view)
+	})
+}
+
+func (s *WebServer) techniqueUnavailable(w http.ResponseWriter, r *http.Request, message string, err error) {
+	if r.Header.Get("HX-Request") == "true" {
+		s.logger.Error(message, zap.String("path", r.URL.Path), zap.Error(err))
+		w.Write([]byte("<div class=\"error\">This is unavailable right now. Please try again.</div>"))
+		return
+	}
+	s.renderError(w, message, err)
+}
@@ -0,0 +1,77 @@
+// Package webserver provides the theme preference and toggle endpoint
+package webserver
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/performance"
+	"go.uber.org/zap"
+)
+
+// Theme names; these mirror the user domain's theme values
+const (
+	ThemeSystem = "system"
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+)
+
+// validTheme reports whether name is a known theme
+func validTheme(name string) bool {
+	return name == ThemeSystem || name == ThemeLight || name == ThemeDark
+}
+
+// sessionTheme returns the session's theme, defaulting to system so
+// anonymous visitors follow prefers-color-scheme
+func sessionTheme(session *Session) string {
+	if session == nil {
+		return ThemeSystem
+	}
+	value, _ := session.GetValue(sessionKeyTheme)
+	if theme, ok := value.(string); ok && validTheme(theme) {
+		return theme
+	}
+	return ThemeSystem
+}
+
+// themeCSS exposes the critical theme custom properties to templates
+func themeCSS() template.CSS {
+	return template.CSS(performance.ThemeCSS())
+}
+
+// handleThemeToggle handles POST /theme?theme=dark. Without a theme
+// parameter it advances to the next theme in the toggle cycle. The choice is
+// kept in the session and, for signed-in users, persisted through the API.
+func (s *WebServer) handleThemeToggle(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	theme := r.FormValue("theme")
+	if theme == "" {
+		theme = ThemeToggleView{Theme: sessionTheme(session)}.Next()
+	}
+	if !validTheme(theme) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`<div class="error">Unknown theme</div>`))
+		return
+	}
+
+	session.SetValue(sessionKeyTheme, theme)
+
+	if session.AccessToken != "" {
+		if err := s.apiClient.UpdateTheme(r.Context(), session.AccessToken, theme); err != nil {
+			// The session still carries the choice; it is retried on next toggle
+			s.logger.Warn("Failed to persist theme preference",
+				zap.String("user_id", session.UserID),
+				zap.Error(err))
+		}
+	}
+
+	resp := NewHTMXResponse().
+		Main(func(buf *bytes.Buffer) error {
+			return s.fragments.RenderThemeToggle(buf, ThemeToggleView{Theme: theme})
+		}).
+		Trigger("themeChanged", map[string]string{"theme": theme})
+
+	s.writeHTMX(w, resp)
+}
@@ -0,0 +1,71 @@
+// Package webserver provides the recipe timeline page and calendar export
+package webserver
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// timelineQuery reads the serve time and finished steps. The serve form
+// sends datetime-local values without a zone, read in the server's zone
+// like the times the timeline shows; anything unreadable plans for as
+// soon as possible.
+func timelineQuery(r *http.Request) (serveAt time.Time, completed int) {
+	raw := r.URL.Query().Get("serve_at")
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		serveAt = t
+	} else if t, err := time.ParseInLocation("2006-01-02T15:04", raw, time.Local); err == nil {
+		serveAt = t
+	}
+	if n, err := strconv.Atoi(r.URL.Query().Get("completed")); err == nil && n > 0 {
+		completed = n
+	}
+	return serveAt, completed
+}
+
+// handleRecipeTimeline serves /recipes/{id}/timeline. The fragment polls
+// itself so the plan moves on when the cook falls behind, and its done
+// buttons replan from the next step.
+func (s *WebServer) handleRecipeTimeline(w http.ResponseWriter, r *http.Request) {
+	serveAt, completed := timelineQuery(r)
+	timeline, err := s.apiClient.GetRecipeTimeline(r.Context(), sessionToken(r), chi.URLParam(r, "id"), serveAt, completed)
+	if err != nil {
+		s.techniqueUnavailable(w, r, "Recipe timeline unavailable", err)
+		return
+	}
+
+	view := NewTimelineView(*timeline, time.Local)
+	s.renderGraph(w, r, view.Title+" timeline - Alchemorsel", func(buf *bytes.Buffer) error {
+		return s.fragments.RenderTimeline(buf, view)
+	})
+}
+
+// handleRecipeTimelineICS serves /recipes/{id}/timeline.ics as a download
+func (s *WebServer) handleRecipeTimelineICS(w http.ResponseWriter, r *http.Request) {
+	recipeID := chi.URLParam(r, "id")
+	serveAt, completed := timelineQuery(r)
+
+	data, err := s.apiClient.GetRecipeTimelineICS(r.Context(), sessionToken(r), recipeID, serveAt, completed)
+	if err != nil {
+		s.logger.Error("Recipe timeline export failed", zap.String("recipe_id", recipeID), zap.Error(err))
+		http.Error(w, "The timeline is unavailable for this recipe", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="recipe-timeline.ics"`)
+	w.Write(data)
+}
+
+// sessionToken is the signed-in user's access token, empty for visitors
+func sessionToken(r *http.Request) string {
+	if session, _ := r.Context().Value("session").(*Session); session != nil {
+		return session.AccessToken
+	}
+	return ""
+}
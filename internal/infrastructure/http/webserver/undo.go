@@ -0,0 +1,98 @@
+// Package webserver provides undoable destructive actions with HTMX toasts
+package webserver
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// toastRegion is the page container toasts are appended to out-of-band
+const toastRegion = "#toasts"
+
+// handleDeleteRecipe deletes a recipe. HTMX callers get an empty main swap
+// (removing the card they targeted) plus an undo toast.
+func (s *WebServer) handleDeleteRecipe(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+
+	info, err := s.apiClient.DeleteRecipe(r.Context(), session.AccessToken, recipeID)
+	if r.Header.Get("HX-Request") != "true" {
+		if err != nil {
+			s.renderError(w, "Failed to delete recipe", err)
+			return
+		}
+		http.Redirect(w, r, "/recipes", http.StatusSeeOther)
+		return
+	}
+
+	if err != nil {
+		s.logger.Error("Failed to delete recipe", zap.String("recipe_id", recipeID), zap.Error(err))
+		s.writeToastOnly(w, "We couldn't delete that recipe. Please try again.")
+		return
+	}
+	s.writeUndoable(w, session, "Recipe deleted", info, "recipeDeleted", recipeID)
+}
+
+// handleHTMXUnpublish archives a recipe and offers an undo toast
+func (s *WebServer) handleHTMXUnpublish(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	recipeID := chi.URLParam(r, "id")
+
+	info, err := s.apiClient.UnpublishRecipe(r.Context(), session.AccessToken, recipeID)
+	if err != nil {
+		s.logger.Error("Failed to unpublish recipe", zap.String("recipe_id", recipeID), zap.Error(err))
+		s.writeToastOnly(w, "We couldn't unpublish that recipe. Please try again.")
+		return
+	}
+	s.writeUndoable(w, session, "Recipe unpublished", info, "recipeUnpublished", recipeID)
+}
+
+// handleHTMXUndo redeems an undo token and replaces the toast with the outcome
+func (s *WebServer) handleHTMXUndo(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	token := chi.URLParam(r, "token")
+
+	resp := NewHTMXResponse()
+	if err := s.apiClient.Undo(r.Context(), session.AccessToken, token); err != nil {
+		s.logger.Warn("Undo failed", zap.Error(err))
+		resp.Main(func(buf *bytes.Buffer) error {
+			return s.fragments.RenderUndoToast(buf, UndoToastView{Message: "Undo is no longer available"})
+		})
+	} else {
+		// Lists listen for actionUndone to re-fetch the restored item
+		resp.Main(func(buf *bytes.Buffer) error {
+			return s.fragments.RenderUndoToast(buf, UndoToastView{Message: "Restored"})
+		}).Trigger("actionUndone", true)
+	}
+	s.writeHTMX(w, resp)
+}
+
+// writeUndoable answers a successful destructive action: the hx-target is
+// cleared and a toast carrying the undo token is appended to the toast region
+func (s *WebServer) writeUndoable(w http.ResponseWriter, session *Session, message string, info *UndoInfo, event, subjectID string) {
+	toast := UndoToastView{Message: message}
+	if info != nil && info.Token != "" {
+		toast.Token = info.Token
+		toast.Seconds = info.TTLSeconds
+		toast.CSRFToken = s.generateCSRFToken(session.ID)
+	}
+
+	resp := NewHTMXResponse().
+		OOB(toastRegion, OOBBeforeEnd, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderUndoToast(buf, toast)
+		}).
+		Trigger(event, map[string]interface{}{"id": subjectID})
+	s.writeHTMX(w, resp)
+}
+
+// writeToastOnly reports a failure without touching the hx-target
+func (s *WebServer) writeToastOnly(w http.ResponseWriter, message string) {
+	w.Header().Set("HX-Reswap", "none")
+	s.writeHTMX(w, NewHTMXResponse().
+		OOB(toastRegion, OOBBeforeEnd, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderUndoToast(buf, UndoToastView{Message: message})
+		}))
+}
@@ -0,0 +1,84 @@
+// Package webserver provides the measurement units preference readers pick
+// beside a recipe's ingredients
+package webserver
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// Measurement units; these mirror the user domain's measurement systems
+const (
+	UnitsOriginal = "original"
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
+)
+
+// validUnits reports whether name is a known measurement system
+func validUnits(name string) bool {
+	return name == UnitsOriginal || name == UnitsMetric || name == UnitsImperial
+}
+
+// sessionUnits returns the units chosen in this session, blank when the
+// reader has not chosen so the API falls back to their saved preference
+func sessionUnits(session *Session) string {
+	if session == nil {
+		return ""
+	}
+	value, _ := session.GetValue(sessionKeyUnits)
+	if units, ok := value.(string); ok && validUnits(units) {
+		return units
+	}
+	return ""
+}
+
+// handleUnits handles POST /units from the ingredient list's units picker.
+// The choice is kept in the session and, for signed-in users, persisted
+// through the API. The list re-renders in the new units and the steps
+// reload on the unitsChanged event to convert their temperatures.
+func (s *WebServer) handleUnits(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	units := r.FormValue("units")
+	if !validUnits(units) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`<div class="error">Unknown units</div>`))
+		return
+	}
+
+	session.SetValue(sessionKeyUnits, units)
+
+	if session.AccessToken != "" {
+		if err := s.apiClient.UpdateUnits(r.Context(), session.AccessToken, units); err != nil {
+			// The session still carries the choice for this visit
+			s.logger.Warn("Failed to persist units preference",
+				zap.String("user_id", session.UserID),
+				zap.Error(err))
+		}
+	}
+
+	recipeID := r.FormValue("recipe_id")
+	if r.Header.Get("HX-Request") != "true" {
+		http.Redirect(w, r, "/recipes/"+recipeID, http.StatusSeeOther)
+		return
+	}
+
+	servings, _ := strconv.Atoi(r.FormValue("servings"))
+	scaled, err := s.apiClient.ScaleRecipe(r.Context(), session.AccessToken, recipeID, servings, units)
+	if err != nil {
+		s.techniqueUnavailable(w, r, "Ingredients unavailable", err)
+		return
+	}
+
+	view := NewRecipeIngredientsView(*scaled)
+	view.CSRFToken = s.generateCSRFToken(session.ID)
+	resp := NewHTMXResponse().
+		Main(func(buf *bytes.Buffer) error {
+			return s.fragments.RenderRecipeIngredients(buf, view)
+		}).
+		Trigger("unitsChanged", map[string]string{"units": units})
+
+	s.writeHTMX(w, resp)
+}
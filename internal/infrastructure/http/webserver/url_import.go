@@ -0,0 +1,73 @@
+// Package webserver provides the "Import from a URL" page, which previews
+// the recipe read off a page before saving it as a draft
+package webserver
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// handleImportRecipePage serves /recipes/import
+func (s *WebServer) handleImportRecipePage(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+
+	s.renderTemplate(w, "recipe-import", map[string]interface{}{
+		"Title":     "Import from a URL - Alchemorsel",
+		"Theme":     sessionTheme(session),
+		"CSRFToken": s.generateCSRFToken(session.ID),
+	})
+}
+
+// handleHTMXImportPreview reads the page and shows what would be imported
+func (s *WebServer) handleHTMXImportPreview(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	pageURL := strings.TrimSpace(r.FormValue("url"))
+	if pageURL == "" {
+		s.writeToastOnly(w, "Paste the address of a recipe page.")
+		return
+	}
+
+	preview, err := s.apiClient.PreviewRecipeImport(r.Context(), session.AccessToken, pageURL)
+	switch {
+	case isAPIStatus(err, http.StatusBadRequest):
+		s.writeToastOnly(w, "We couldn't find a recipe on that page. Check the address, or try the recipe's own page rather than a list.")
+		return
+	case err != nil:
+		s.logger.Error("Failed to preview recipe import", zap.String("url", pageURL), zap.Error(err))
+		s.writeToastOnly(w, "We couldn't read that page just now. Please try again.")
+		return
+	}
+
+	s.renderFragment(w, func(buf *bytes.Buffer) error {
+		return s.fragments.RenderURLImportPreview(buf, NewURLImportPreviewView(pageURL, *preview, s.generateCSRFToken(session.ID)))
+	})
+}
+
+// handleHTMXImportRecipe saves the page's recipe as a draft and opens it in
+// the editor. A recipe the user already has opens that one instead.
+func (s *WebServer) handleHTMXImportRecipe(w http.ResponseWriter, r *http.Request) {
+	session := r.Context().Value("session").(*Session)
+	pageURL := strings.TrimSpace(r.FormValue("url"))
+
+	result, err := s.apiClient.ImportRecipeURL(r.Context(), session.AccessToken, pageURL)
+	switch {
+	case isAPIStatus(err, http.StatusBadRequest):
+		s.writeToastOnly(w, "We couldn't import a recipe from that page.")
+		return
+	case err != nil:
+		s.logger.Error("Failed to import recipe", zap.String("url", pageURL), zap.Error(err))
+		s.writeToastOnly(w, "We couldn't save the recipe just now. Please try again.")
+		return
+	}
+
+	redirect := "/recipes/" + url.PathEscape(result.RecipeID)
+	if result.Status != "duplicate" {
+		redirect += "/edit"
+	}
+	w.Header().Set("HX-Redirect", redirect)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,323 @@
+// Package webserver provides a server-driven multi-step form engine
+package webserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// FieldErrors maps form field names to validation messages
+type FieldErrors map[string]string
+
+// WizardStep is one page of a wizard. Fields lists the form fields the step
+// owns; only those are read from a submission so steps can't overwrite each
+// other's answers.
+type WizardStep struct {
+	ID       string
+	Title    string
+	Template string
+	Fields   []string
+	Validate func(values url.Values) FieldErrors
+}
+
+// Wizard describes a multi-step form. Complete runs after the last step
+// validates and returns the URL to redirect to.
+type Wizard struct {
+	ID       string
+	Title    string
+	BasePath string
+	Steps    []WizardStep
+	Complete func(ctx context.Context, session *Session, values url.Values) (string, error)
+}
+
+// WizardState is the server-side progress of one wizard in one session
+type WizardState struct {
+	Step      int        `json:"step"`
+	Reached   int        `json:"reached"`
+	Values    url.Values `json:"values"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// sessionKey is where the wizard's state is kept in session data
+func (wz *Wizard) sessionKey() string {
+	return "wizard:" + wz.ID
+}
+
+// LoadState returns the saved state, or a fresh state at the first step.
+// State is stored as JSON so it survives persistent session stores.
+func (wz *Wizard) LoadState(session *Session) *WizardState {
+	state := &WizardState{Values: url.Values{}}
+	if raw, ok := session.GetValue(wz.sessionKey()); ok {
+		if data, ok := raw.(string); ok {
+			json.Unmarshal([]byte(data), state)
+		}
+	}
+	if state.Values == nil {
+		state.Values = url.Values{}
+	}
+	if state.Step < 0 || state.Step >= len(wz.Steps) {
+		state.Step = 0
+	}
+	return state
+}
+
+// SaveState persists state in the session
+func (wz *Wizard) SaveState(session *Session, state *WizardState) {
+	state.UpdatedAt = time.Now()
+	data, _ := json.Marshal(state)
+	session.SetValue(wz.sessionKey(), string(data))
+}
+
+// ClearState discards progress, e.g. after completion
+func (wz *Wizard) ClearState(session *Session) {
+	delete(session.Data, wz.sessionKey())
+}
+
+// capture copies the current step's fields from a submission into state
+func (wz *Wizard) capture(state *WizardState, form url.Values) {
+	for _, field := range wz.Steps[state.Step].Fields {
+		values := form[field]
+		if len(values) == 0 {
+			state.Values.Del(field)
+			continue
+		}
+		trimmed := make([]string, len(values))
+		for i, v := range values {
+			trimmed[i] = strings.TrimSpace(v)
+		}
+		state.Values[field] = trimmed
+	}
+}
+
+// Next saves the submitted fields and advances when they validate.
+// It reports done when the last step validated.
+func (wz *Wizard) Next(state *WizardState, form url.Values) (errs FieldErrors, done bool) {
+	wz.capture(state, form)
+
+	if validate := wz.Steps[state.Step].Validate; validate != nil {
+		if errs = validate(state.Values); len(errs) > 0 {
+			return errs, false
+		}
+	}
+
+	if state.Step == len(wz.Steps)-1 {
+		return nil, true
+	}
+
+	state.Step++
+	if state.Step > state.Reached {
+		state.Reached = state.Step
+	}
+	return nil, false
+}
+
+// Back saves the submitted fields without validating and moves back a step
+func (wz *Wizard) Back(state *WizardState, form url.Values) {
+	wz.capture(state, form)
+	if state.Step > 0 {
+		state.Step--
+	}
+}
+
+// GoTo jumps to a step that has already been reached
+func (wz *Wizard) GoTo(state *WizardState, step int) bool {
+	if step < 0 || step > state.Reached || step >= len(wz.Steps) {
+		return false
+	}
+	state.Step = step
+	return true
+}
+
+// WizardBodyView is passed to each step's template
+type WizardBodyView struct {
+	Values url.Values
+	Errors FieldErrors
+}
+
+// Get returns the first saved value of a field
+func (v WizardBodyView) Get(name string) string {
+	return v.Values.Get(name)
+}
+
+// Lines returns the non-empty lines of a multi-line field
+func (v WizardBodyView) Lines(name string) []string {
+	return splitLines(v.Values.Get(name))
+}
+
+// Error returns the validation message for a field
+func (v WizardBodyView) Error(name string) string {
+	return v.Errors[name]
+}
+
+// WizardProgressItem is one entry of the progress indicator
+type WizardProgressItem struct {
+	Index   int
+	Title   string
+	Current bool
+	Reached bool
+}
+
+// WizardStepView is the view model for the wizard-step fragment
+type WizardStepView struct {
+	WizardTitle string
+	BasePath    string
+	StepTitle   string
+	StepNumber  int
+	StepCount   int
+	Progress    []WizardProgressItem
+	Body        template.HTML
+	FormError   string
+	CSRFToken   string
+	IsFirst     bool
+	IsLast      bool
+}
+
+// mountWizard registers the wizard's routes on r:
+//
+//	GET  /           resume at the saved step
+//	GET  /step/{n}   jump back to a reached step
+//	POST /next       validate and advance (or complete on the last step)
+//	POST /back       save and go back without validating
+//	POST /reset      discard progress
+func (s *WebServer) mountWizard(r chi.Router, wz *Wizard) {
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		session := r.Context().Value("session").(*Session)
+		state := wz.LoadState(session)
+		s.renderWizard(w, r, wz, state, nil)
+	})
+
+	r.Get("/step/{n}", func(w http.ResponseWriter, r *http.Request) {
+		session := r.Context().Value("session").(*Session)
+		state := wz.LoadState(session)
+		n, err := strconv.Atoi(chi.URLParam(r, "n"))
+		if err != nil || !wz.GoTo(state, n-1) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`<div class="error">That step isn't available yet</div>`))
+			return
+		}
+		wz.SaveState(session, state)
+		s.renderWizard(w, r, wz, state, nil)
+	})
+
+	r.With(s.csrfMiddleware).Post("/next", func(w http.ResponseWriter, r *http.Request) {
+		session := r.Context().Value("session").(*Session)
+		state := wz.LoadState(session)
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		errs, done := wz.Next(state, r.PostForm)
+		wz.SaveState(session, state)
+
+		if done {
+			redirect, err := wz.Complete(r.Context(), session, state.Values)
+			if err != nil {
+				s.logger.Error("Wizard completion failed", zap.String("wizard", wz.ID), zap.Error(err))
+				s.renderWizard(w, r, wz, state, FieldErrors{"_form": "We couldn't finish this just now. Your answers are saved; please try again."})
+				return
+			}
+			wz.ClearState(session)
+			if r.Header.Get("HX-Request") == "true" {
+				w.Header().Set("HX-Redirect", redirect)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			http.Redirect(w, r, redirect, http.StatusSeeOther)
+			return
+		}
+
+		// Validation errors re-render the same step with a 200 so HTMX swaps it
+		s.renderWizard(w, r, wz, state, errs)
+	})
+
+	r.With(s.csrfMiddleware).Post("/back", func(w http.ResponseWriter, r *http.Request) {
+		session := r.Context().Value("session").(*Session)
+		state := wz.LoadState(session)
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		wz.Back(state, r.PostForm)
+		wz.SaveState(session, state)
+		s.renderWizard(w, r, wz, state, nil)
+	})
+
+	r.With(s.csrfMiddleware).Post("/reset", func(w http.ResponseWriter, r *http.Request) {
+		session := r.Context().Value("session").(*Session)
+		wz.ClearState(session)
+		s.renderWizard(w, r, wz, wz.LoadState(session), nil)
+	})
+}
+
+// renderWizard renders the current step: as a fragment for HTMX requests,
+// otherwise wrapped in the full wizard page
+func (s *WebServer) renderWizard(w http.ResponseWriter, r *http.Request, wz *Wizard, state *WizardState, errs FieldErrors) {
+	session := r.Context().Value("session").(*Session)
+	step := wz.Steps[state.Step]
+
+	var body bytes.Buffer
+	if err := s.templates.ExecuteTemplate(&body, step.Template, WizardBodyView{Values: state.Values, Errors: errs}); err != nil {
+		s.renderError(w, "Failed to render form", fmt.Errorf("wizard step %s: %w", step.ID, err))
+		return
+	}
+
+	progress := make([]WizardProgressItem, len(wz.Steps))
+	for i, st := range wz.Steps {
+		progress[i] = WizardProgressItem{Index: i + 1, Title: st.Title, Current: i == state.Step, Reached: i <= state.Reached}
+	}
+
+	view := WizardStepView{
+		WizardTitle: wz.Title,
+		BasePath:    wz.BasePath,
+		StepTitle:   step.Title,
+		StepNumber:  state.Step + 1,
+		StepCount:   len(wz.Steps),
+		Progress:    progress,
+		Body:        template.HTML(body.String()),
+		FormError:   errs["_form"],
+		CSRFToken:   s.generateCSRFToken(session.ID),
+		IsFirst:     state.Step == 0,
+		IsLast:      state.Step == len(wz.Steps)-1,
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		s.renderFragment(w, func(buf *bytes.Buffer) error {
+			return s.fragments.RenderWizardStep(buf, view)
+		})
+		return
+	}
+
+	var stepHTML bytes.Buffer
+	if err := s.fragments.RenderWizardStep(&stepHTML, view); err != nil {
+		s.renderError(w, "Failed to render form", err)
+		return
+	}
+	s.renderTemplate(w, "wizard", map[string]interface{}{
+		"Title": wz.Title + " - Alchemorsel",
+		"Theme": sessionTheme(session),
+		"Step":  template.HTML(stepHTML.String()),
+	})
+}
+
+// splitLines returns trimmed, non-empty lines
+func splitLines(s string) []string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
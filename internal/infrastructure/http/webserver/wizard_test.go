@@ -0,0 +1,90 @@
+package webserver
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWizard() *Wizard {
+	required := func(field string) func(url.Values) FieldErrors {
+		return func(values url.Values) FieldErrors {
+			if values.Get(field) == "" {
+				return FieldErrors{field: "required"}
+			}
+			return nil
+		}
+	}
+	return &Wizard{
+		ID: "test",
+		Steps: []WizardStep{
+			{ID: "one", Fields: []string{"a"}, Validate: required("a")},
+			{ID: "two", Fields: []string{"b"}, Validate: required("b")},
+			{ID: "three"},
+		},
+	}
+}
+
+func TestWizardNavigation(t *testing.T) {
+	wz := testWizard()
+	state := &WizardState{Values: url.Values{}}
+
+	errs, done := wz.Next(state, url.Values{"a": {"  "}})
+	assert.Equal(t, FieldErrors{"a": "required"}, errs)
+	assert.False(t, done)
+	assert.Equal(t, 0, state.Step)
+
+	// Fields owned by other steps are ignored
+	errs, _ = wz.Next(state, url.Values{"a": {" x "}, "b": {"sneaky"}})
+	assert.Empty(t, errs)
+	assert.Equal(t, 1, state.Step)
+	assert.Equal(t, "x", state.Values.Get("a"))
+	assert.Empty(t, state.Values.Get("b"))
+
+	// Back keeps unvalidated input
+	wz.Back(state, url.Values{"b": {"draft"}})
+	assert.Equal(t, 0, state.Step)
+	assert.Equal(t, "draft", state.Values.Get("b"))
+
+	assert.False(t, wz.GoTo(state, 2), "step three not reached yet")
+	assert.True(t, wz.GoTo(state, 1))
+
+	wz.Next(state, url.Values{"b": {"y"}})
+	assert.Equal(t, 2, state.Reached)
+
+	_, done = wz.Next(state, url.Values{})
+	assert.True(t, done)
+}
+
+func TestWizardStateRoundTrip(t *testing.T) {
+	wz := testWizard()
+	session := &Session{Data: map[string]interface{}{}}
+
+	state := wz.LoadState(session)
+	wz.Next(state, url.Values{"a": {"x"}})
+	wz.SaveState(session, state)
+
+	loaded := wz.LoadState(session)
+	assert.Equal(t, 1, loaded.Step)
+	assert.Equal(t, "x", loaded.Values.Get("a"))
+
+	wz.ClearState(session)
+	assert.Equal(t, 0, wz.LoadState(session).Step)
+}
+
+func TestRecipeWizardValidation(t *testing.T) {
+	errs := validateRecipeBasics(url.Values{"title": {"ab"}, "servings": {"0"}, "difficulty": {"extreme"}})
+	assert.Contains(t, errs, "title")
+	assert.Contains(t, errs, "servings")
+	assert.Contains(t, errs, "difficulty")
+
+	assert.Empty(t, validateRecipeBasics(url.Values{"title": {"Soup"}, "servings": {"4"}, "prep_time": {"10"}}))
+
+	require.Contains(t, validateLines(url.Values{"ingredients": {"\n \n"}}, "ingredients", "empty"), "ingredients")
+	assert.Empty(t, validateLines(url.Values{"ingredients": {"flour\nwater"}}, "ingredients", "empty"))
+
+	assert.Contains(t, validateRecipePhotos(url.Values{"photo_urls": {"javascript:alert(1)"}}), "photo_urls")
+	assert.Empty(t, validateRecipePhotos(url.Values{"photo_urls": {"https://example.com/a.jpg"}}))
+}
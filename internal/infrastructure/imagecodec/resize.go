@@ -0,0 +1,92 @@
+package imagecodec
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// contribution is how much one source pixel adds to a destination pixel
+type contribution struct {
+	index  int
+	weight float64
+}
+
+// resize scales the image down with an area average: each destination
+// pixel is the mean of the source pixels it covers, weighted by how much of
+// each it covers. Rows and columns are scaled in separate passes over
+// premultiplied RGBA, so transparent edges do not darken.
+func resize(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return rgba
+	}
+
+	rows := image.NewRGBA(image.Rect(0, 0, width, bounds.Dy()))
+	resample(rgba, rows, contributions(bounds.Dx(), width), true)
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	resample(rows, out, contributions(bounds.Dy(), height), false)
+	return out
+}
+
+// contributions lists, for each of dstLen pixels, the source pixels it
+// covers along one axis and their weights, which sum to one
+func contributions(srcLen, dstLen int) [][]contribution {
+	scale := float64(srcLen) / float64(dstLen)
+	all := make([][]contribution, dstLen)
+	for i := range all {
+		start := float64(i) * scale
+		end := start + scale
+		for s := int(start); float64(s) < end && s < srcLen; s++ {
+			overlap := math.Min(end, float64(s+1)) - math.Max(start, float64(s))
+			if overlap > 0 {
+				all[i] = append(all[i], contribution{index: s, weight: overlap / scale})
+			}
+		}
+	}
+	return all
+}
+
+// resample scales src into dst along rows when horizontal, else along
+// columns; the other axis must already match
+func resample(src, dst *image.RGBA, weights [][]contribution, horizontal bool) {
+	bounds := dst.Bounds()
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			along := y
+			if horizontal {
+				along = x
+			}
+			var r, g, b, a float64
+			for _, c := range weights[along] {
+				sx, sy := x, c.index
+				if horizontal {
+					sx, sy = c.index, y
+				}
+				i := src.PixOffset(sx, sy)
+				r += float64(src.Pix[i]) * c.weight
+				g += float64(src.Pix[i+1]) * c.weight
+				b += float64(src.Pix[i+2]) * c.weight
+				a += float64(src.Pix[i+3]) * c.weight
+			}
+			o := dst.PixOffset(x, y)
+			dst.Pix[o] = clamp(r)
+			dst.Pix[o+1] = clamp(g)
+			dst.Pix[o+2] = clamp(b)
+			dst.Pix[o+3] = clamp(a)
+		}
+	}
+}
+
+func clamp(v float64) uint8 {
+	v = math.Round(v)
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
@@ -0,0 +1,181 @@
+// Package imagecodec resizes and re-encodes images. JPEG, PNG and GIF are
+// decoded and JPEG and PNG encoded with the standard library; WebP and AVIF
+// are encoded by running cwebp and avifenc, when they are installed.
+package imagecodec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register decoder
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// Formats the transcoder encodes
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+	FormatAVIF = "avif"
+)
+
+// ErrTooManyPixels is returned for images larger than the pixel limit,
+// before they are decoded
+var ErrTooManyPixels = errors.New("image has too many pixels")
+
+// Transcoder implements outbound.ImageTranscoder
+type Transcoder struct {
+	encoders  map[string]string // format to encoder command path
+	maxPixels int
+	logger    *zap.Logger
+}
+
+// NewTranscoder creates a transcoder. The WebP and AVIF encoders are looked
+// up on PATH; a blank or missing command leaves that format unsupported.
+func NewTranscoder(webpEncoder, avifEncoder string, maxPixels int, logger *zap.Logger) *Transcoder {
+	t := &Transcoder{
+		encoders:  make(map[string]string),
+		maxPixels: maxPixels,
+		logger:    logger.Named("imagecodec"),
+	}
+	for format, command := range map[string]string{FormatWebP: webpEncoder, FormatAVIF: avifEncoder} {
+		if command == "" {
+			continue
+		}
+		path, err := exec.LookPath(command)
+		if err != nil {
+			t.logger.Info("Image encoder not found, format disabled", zap.String("format", format), zap.String("command", command))
+			continue
+		}
+		t.encoders[format] = path
+	}
+	return t
+}
+
+// Supports reports whether the format can be encoded
+func (t *Transcoder) Supports(format string) bool {
+	switch format {
+	case FormatJPEG, FormatPNG:
+		return true
+	default:
+		return t.encoders[format] != ""
+	}
+}
+
+// Probe reads the image header
+func (t *Transcoder) Probe(content []byte) (*outbound.ImageInfo, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("read image header: %w", err)
+	}
+	return &outbound.ImageInfo{Width: config.Width, Height: config.Height, ContentType: "image/" + format}, nil
+}
+
+// Transcode decodes the image, scales it to fit the box and encodes it
+func (t *Transcoder) Transcode(ctx context.Context, content []byte, opts outbound.TranscodeOptions) ([]byte, error) {
+	info, err := t.Probe(content)
+	if err != nil {
+		return nil, err
+	}
+	if t.maxPixels > 0 && info.Width*info.Height > t.maxPixels {
+		return nil, ErrTooManyPixels
+	}
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	width, height := fit(src.Bounds().Dx(), src.Bounds().Dy(), opts.Width, opts.Height)
+	img := resize(src, width, height)
+
+	var out bytes.Buffer
+	switch opts.Format {
+	case FormatJPEG:
+		err = jpeg.Encode(&out, flatten(img), &jpeg.Options{Quality: opts.Quality})
+	case FormatPNG:
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&out, img)
+	case FormatWebP, FormatAVIF:
+		return t.encodeExternal(ctx, img, opts)
+	default:
+		return nil, fmt.Errorf("unsupported image format %q", opts.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", opts.Format, err)
+	}
+	return out.Bytes(), nil
+}
+
+// encodeExternal hands the resized image to the format's encoder as a PNG
+func (t *Transcoder) encodeExternal(ctx context.Context, img image.Image, opts outbound.TranscodeOptions) ([]byte, error) {
+	command := t.encoders[opts.Format]
+	if command == "" {
+		return nil, fmt.Errorf("no encoder for %s", opts.Format)
+	}
+
+	dir, err := os.MkdirTemp("", "imagecodec-*")
+	if err != nil {
+		return nil, fmt.Errorf("create work directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "in.png")
+	output := filepath.Join(dir, "out."+opts.Format)
+	var staged bytes.Buffer
+	if err := (&png.Encoder{CompressionLevel: png.BestSpeed}).Encode(&staged, img); err != nil {
+		return nil, fmt.Errorf("stage image: %w", err)
+	}
+	if err := os.WriteFile(input, staged.Bytes(), 0o600); err != nil {
+		return nil, fmt.Errorf("stage image: %w", err)
+	}
+
+	quality := fmt.Sprint(opts.Quality)
+	args := []string{"-quiet", "-q", quality, input, "-o", output}
+	if opts.Format == FormatAVIF {
+		args = []string{"-q", quality, input, output}
+	}
+	if combined, err := exec.CommandContext(ctx, command, args...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", filepath.Base(command), err, bytes.TrimSpace(combined))
+	}
+	return os.ReadFile(output)
+}
+
+// fit scales width and height down to fit inside the box, keeping the
+// aspect ratio. A zero box side does not constrain; images are never
+// enlarged.
+func fit(width, height, maxWidth, maxHeight int) (int, int) {
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight > 0 && float64(height)*scale > float64(maxHeight) {
+		scale = float64(maxHeight) / float64(height)
+	}
+	w := int(float64(width)*scale + 0.5)
+	h := int(float64(height)*scale + 0.5)
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	return w, h
+}
+
+// flatten draws the image over white, since JPEG has no transparency
+func flatten(img *image.RGBA) image.Image {
+	opaque := image.NewRGBA(img.Bounds())
+	draw.Draw(opaque, opaque.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(opaque, opaque.Bounds(), img, img.Bounds().Min, draw.Over)
+	return opaque
+}
@@ -0,0 +1,78 @@
+package imagecodec
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stripes is a PNG whose left half is red and right half blue
+func stripes(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.NRGBA{B: 255, A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestTranscodeFitsTheBoxWithoutEnlarging(t *testing.T) {
+	transcoder := NewTranscoder("", "no-such-encoder", 1000000, zap.NewNop())
+	ctx := context.Background()
+	original := stripes(t, 400, 200)
+
+	small, err := transcoder.Transcode(ctx, original, outbound.TranscodeOptions{Width: 100, Format: FormatJPEG, Quality: 70})
+	require.NoError(t, err)
+	info, err := transcoder.Probe(small)
+	require.NoError(t, err)
+	assert.Equal(t, outbound.ImageInfo{Width: 100, Height: 50, ContentType: "image/jpeg"}, *info)
+
+	boxed, err := transcoder.Transcode(ctx, original, outbound.TranscodeOptions{Width: 300, Height: 60, Format: FormatPNG})
+	require.NoError(t, err)
+	info, err = transcoder.Probe(boxed)
+	require.NoError(t, err)
+	assert.Equal(t, 120, info.Width)
+	assert.Equal(t, 60, info.Height)
+
+	same, err := transcoder.Transcode(ctx, original, outbound.TranscodeOptions{Width: 1600, Format: FormatPNG})
+	require.NoError(t, err)
+	info, err = transcoder.Probe(same)
+	require.NoError(t, err)
+	assert.Equal(t, 400, info.Width)
+
+	assert.False(t, transcoder.Supports(FormatAVIF))
+	_, err = transcoder.Transcode(ctx, original, outbound.TranscodeOptions{Width: 100, Format: FormatAVIF, Quality: 70})
+	assert.Error(t, err)
+
+	tight := NewTranscoder("", "", 100, zap.NewNop())
+	_, err = tight.Transcode(ctx, original, outbound.TranscodeOptions{Width: 100, Format: FormatPNG})
+	assert.ErrorIs(t, err, ErrTooManyPixels)
+}
+
+func TestResizeAveragesCoveredPixels(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	img.SetRGBA(0, 0, color.RGBA{R: 255, A: 255})
+	img.SetRGBA(1, 0, color.RGBA{B: 255, A: 255})
+	img.SetRGBA(2, 0, color.RGBA{B: 255, A: 255})
+
+	out := resize(img, 2, 1)
+
+	// The first output pixel covers the red pixel and half a blue one
+	assert.Equal(t, color.RGBA{R: 170, B: 85, A: 255}, out.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, out.RGBAAt(1, 0))
+}
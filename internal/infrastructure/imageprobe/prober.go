@@ -0,0 +1,113 @@
+// Package imageprobe fetches just enough of a remote image to read its
+// dimensions, for the structured data validator
+package imageprobe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif"  // register decoder
+	_ "image/jpeg" // register decoder
+	_ "image/png"  // register decoder
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/publichttp"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// headerBytes is how much of an image is read; JPEG headers can sit behind
+// large EXIF blocks, so this is generous
+const headerBytes = 256 << 10
+
+// ErrPrivateAddress is returned for image URLs that resolve to loopback or
+// private networks
+var ErrPrivateAddress = publichttp.ErrPrivateAddress
+
+// HTTPProber reads image dimensions over HTTP
+type HTTPProber struct {
+	client *http.Client
+	logger *zap.Logger
+}
+
+// NewHTTPProber creates a prober that refuses private network addresses
+func NewHTTPProber(logger *zap.Logger) outbound.ImageProber {
+	return &HTTPProber{
+		client: publichttp.NewClient(10 * time.Second),
+		logger: logger.Named("image-probe"),
+	}
+}
+
+// ProbeImage downloads the start of the image and decodes its header. Formats
+// it cannot decode, such as AVIF, come back with zero dimensions.
+func (p *HTTPProber) ProbeImage(ctx context.Context, url string) (*outbound.ImageInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", headerBytes-1))
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return nil, fmt.Errorf("fetch image: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, headerBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read image: %w", err)
+	}
+
+	info, err := Dimensions(data)
+	if err != nil {
+		p.logger.Debug("Could not read image dimensions", zap.String("url", url), zap.Error(err))
+		return &outbound.ImageInfo{ContentType: resp.Header.Get("Content-Type")}, nil
+	}
+	return info, nil
+}
+
+// Dimensions decodes the size of a JPEG, PNG, GIF or WebP image from its
+// leading bytes
+func Dimensions(data []byte) (*outbound.ImageInfo, error) {
+	if info, ok := webpDimensions(data); ok {
+		return info, nil
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported or truncated image: %w", err)
+	}
+	return &outbound.ImageInfo{Width: config.Width, Height: config.Height, ContentType: "image/" + format}, nil
+}
+
+// webpDimensions reads the canvas size from the VP8, VP8L or VP8X chunk;
+// the standard library has no WebP decoder
+func webpDimensions(data []byte) (*outbound.ImageInfo, bool) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, false
+	}
+	var width, height int
+	switch string(data[12:16]) {
+	case "VP8 ":
+		width = int(binary.LittleEndian.Uint16(data[26:28]) & 0x3fff)
+		height = int(binary.LittleEndian.Uint16(data[28:30]) & 0x3fff)
+	case "VP8L":
+		bits := binary.LittleEndian.Uint32(data[21:25])
+		width = int(bits&0x3fff) + 1
+		height = int(bits>>14&0x3fff) + 1
+	case "VP8X":
+		width = int(uint32(data[24])|uint32(data[25])<<8|uint32(data[26])<<16) + 1
+		height = int(uint32(data[27])|uint32(data[28])<<8|uint32(data[29])<<16) + 1
+	default:
+		return nil, false
+	}
+	return &outbound.ImageInfo{Width: width, Height: height, ContentType: "image/webp"}, true
+}
@@ -0,0 +1,46 @@
+package imageprobe
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDimensions(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 320, 180))))
+	info, err := Dimensions(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, 320, info.Width)
+	assert.Equal(t, 180, info.Height)
+	assert.Equal(t, "image/png", info.ContentType)
+
+	// VP8X header for a 1200x900 canvas
+	webp := []byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00\x00\x00\x00\x00")
+	webp = append(webp, 0xaf, 0x04, 0x00, 0x83, 0x03, 0x00)
+	info, err = Dimensions(webp)
+	require.NoError(t, err)
+	assert.Equal(t, 1200, info.Width)
+	assert.Equal(t, 900, info.Height)
+
+	_, err = Dimensions([]byte("<html>not an image</html>"))
+	assert.Error(t, err)
+}
+
+func TestProbeRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("prober reached a loopback server")
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPProber(zap.NewNop()).ProbeImage(context.Background(), server.URL+"/photo.png")
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+}
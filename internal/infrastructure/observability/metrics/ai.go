@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+)
+
+// InstrumentAI counts and times the calls made through ai, labelled with
+// the configured provider
+func InstrumentAI(ai outbound.AIService, provider string) outbound.AIService {
+	return &instrumentedAI{next: ai, provider: provider}
+}
+
+type instrumentedAI struct {
+	next     outbound.AIService
+	provider string
+}
+
+func (a *instrumentedAI) observe(operation string, start time.Time, err error) {
+	aiCalls.WithLabelValues(a.provider, operation, outcome(err)).Inc()
+	aiCallDuration.WithLabelValues(a.provider, operation).Observe(time.Since(start).Seconds())
+}
+
+func (a *instrumentedAI) GenerateRecipe(ctx context.Context, prompt string, constraints outbound.AIConstraints) (*outbound.AIRecipeResponse, error) {
+	start := time.Now()
+	resp, err := a.next.GenerateRecipe(ctx, prompt, constraints)
+	a.observe("generate_recipe", start, err)
+	return resp, err
+}
+
+func (a *instrumentedAI) SuggestIngredients(ctx context.Context, partial []string) ([]string, error) {
+	start := time.Now()
+	suggestions, err := a.next.SuggestIngredients(ctx, partial)
+	a.observe("suggest_ingredients", start, err)
+	return suggestions, err
+}
+
+func (a *instrumentedAI) AnalyzeNutrition(ctx context.Context, ingredients []string) (*outbound.NutritionInfo, error) {
+	start := time.Now()
+	info, err := a.next.AnalyzeNutrition(ctx, ingredients)
+	a.observe("analyze_nutrition", start, err)
+	return info, err
+}
+
+func (a *instrumentedAI) GenerateDescription(ctx context.Context, r *recipe.Recipe) (string, error) {
+	start := time.Now()
+	description, err := a.next.GenerateDescription(ctx, r)
+	a.observe("generate_description", start, err)
+	return description, err
+}
+
+func (a *instrumentedAI) ClassifyRecipe(ctx context.Context, r *recipe.Recipe) (*outbound.RecipeClassification, error) {
+	start := time.Now()
+	classification, err := a.next.ClassifyRecipe(ctx, r)
+	a.observe("classify_recipe", start, err)
+	return classification, err
+}
+
+func (a *instrumentedAI) SuggestSearchQueries(ctx context.Context, query string) ([]string, error) {
+	start := time.Now()
+	queries, err := a.next.SuggestSearchQueries(ctx, query)
+	a.observe("suggest_search_queries", start, err)
+	return queries, err
+}
+
+func (a *instrumentedAI) SuggestStepTechniques(ctx context.Context, steps []string, techniques []string) ([][]string, error) {
+	start := time.Now()
+	picks, err := a.next.SuggestStepTechniques(ctx, steps, techniques)
+	a.observe("suggest_step_techniques", start, err)
+	return picks, err
+}
+
+func (a *instrumentedAI) Translate(ctx context.Context, texts []string, from, to string) (*outbound.AITranslation, error) {
+	start := time.Now()
+	translation, err := a.next.Translate(ctx, texts, from, to)
+	a.observe("translate", start, err)
+	return translation, err
+}
+
+func (a *instrumentedAI) Embed(ctx context.Context, texts []string) (*outbound.AIEmbeddings, error) {
+	start := time.Now()
+	embeddings, err := a.next.Embed(ctx, texts)
+	a.observe("embed", start, err)
+	return embeddings, err
+}
+
+func (a *instrumentedAI) SuggestSubstitutes(ctx context.Context, ingredient string) (*outbound.AISubstitutes, error) {
+	start := time.Now()
+	suggested, err := a.next.SuggestSubstitutes(ctx, ingredient)
+	a.observe("suggest_substitutes", start, err)
+	return suggested, err
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+)
+
+// InstrumentCache counts the hits and misses of lookups through cache,
+// labelled with the part of the key before the first colon. The cache
+// port reports a miss as an error, so a failed lookup counts as a miss.
+func InstrumentCache(cache outbound.CacheRepository) outbound.CacheRepository {
+	return &instrumentedCache{CacheRepository: cache}
+}
+
+type instrumentedCache struct {
+	outbound.CacheRepository
+}
+
+func (c *instrumentedCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.CacheRepository.Get(ctx, key)
+	result := "hit"
+	if err != nil {
+		result = "miss"
+	}
+	cacheLookups.WithLabelValues(keyspace(key), result).Inc()
+	return value, err
+}
+
+func (c *instrumentedCache) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values, err := c.CacheRepository.MGet(ctx, keys)
+	for _, key := range keys {
+		result := "miss"
+		if _, ok := values[key]; ok {
+			result = "hit"
+		}
+		cacheLookups.WithLabelValues(keyspace(key), result).Inc()
+	}
+	return values, err
+}
+
+// keyspace is the prefix of key before the first colon
+func keyspace(key string) string {
+	if i := strings.IndexByte(key, ':'); i > 0 {
+		return key[:i]
+	}
+	return "other"
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startKey = "metrics:start"
+
+// InstrumentGORM counts and times every query run through db
+func InstrumentGORM(db *gorm.DB) error {
+	return db.Use(gormPlugin{})
+}
+
+// gormPlugin hooks around each of GORM's callback chains
+type gormPlugin struct{}
+
+func (gormPlugin) Name() string {
+	return "alchemorsel:metrics"
+}
+
+func (gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		operation     string
+		before, after func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, hook := range hooks {
+		if err := hook.before("metrics:before_"+hook.operation, startQuery); err != nil {
+			return err
+		}
+		if err := hook.after("metrics:after_"+hook.operation, finishQuery(hook.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func finishQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		err := db.Error
+		// A lookup that finds nothing is an answer, not a failure
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+		dbQueries.WithLabelValues(operation, db.Statement.Table, outcome(err)).Inc()
+		if start, ok := db.InstanceGet(startKey); ok {
+			dbQueryDuration.WithLabelValues(operation).Observe(time.Since(start.(time.Time)).Seconds())
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Package metrics exposes Prometheus metrics for the Alchemorsel servers.
+//
+// Middleware times HTTP requests by route, InstrumentGORM counts database
+// queries, InstrumentAI counts calls to the AI provider and InstrumentCache
+// counts cache hits and misses. Everything is registered with the default
+// registry, so Handler also serves the metrics other packages register
+// there.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "alchemorsel"
+
+// Path is where the servers serve their metrics
+const Path = "/metrics"
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests, by route pattern and status code",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "queries_total",
+		Help:      "Database queries run, by operation, table and outcome",
+	}, []string{"operation", "table", "outcome"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Time taken by database queries, by operation",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+
+	aiCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ai",
+		Name:      "calls_total",
+		Help:      "Calls to the AI provider, by operation and outcome",
+	}, []string{"provider", "operation", "outcome"})
+
+	aiCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "ai",
+		Name:      "call_duration_seconds",
+		Help:      "Time taken by calls to the AI provider, by operation",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 20, 40, 60},
+	}, []string{"provider", "operation"})
+
+	cacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "lookups_total",
+		Help:      "Cache lookups by key prefix and result; the hit ratio is hits over all lookups",
+	}, []string{"keyspace", "result"})
+)
+
+// Handler serves the default registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// outcome labels an error as ok or error
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
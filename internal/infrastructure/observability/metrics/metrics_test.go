@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/persistence/memory"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func sampleCount(t *testing.T, o prometheus.Observer) uint64 {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, o.(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestMiddlewareLabelsRequestsByRoutePattern(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/metrics-test/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	for _, path := range []string{"/metrics-test/1", "/metrics-test/2", "/nowhere/3"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, uint64(2), sampleCount(t, httpRequestDuration.WithLabelValues("GET", "/metrics-test/{id}", "404")))
+	assert.Equal(t, uint64(1), sampleCount(t, httpRequestDuration.WithLabelValues("GET", unmatchedRoute, "404")))
+}
+
+func TestInstrumentCacheCountsHitsAndMisses(t *testing.T) {
+	ctx := context.Background()
+	cache := InstrumentCache(memory.NewCacheRepository())
+	require.NoError(t, cache.Set(ctx, "metricstest:a", []byte("1"), time.Minute))
+
+	_, err := cache.Get(ctx, "metricstest:a")
+	require.NoError(t, err)
+	_, err = cache.Get(ctx, "metricstest:b")
+	require.Error(t, err)
+	_, err = cache.MGet(ctx, []string{"metricstest:a", "metricstest:c"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(cacheLookups.WithLabelValues("metricstest", "hit")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(cacheLookups.WithLabelValues("metricstest", "miss")))
+}
+
+type metricsTestRow struct {
+	ID   uint
+	Name string
+}
+
+func TestInstrumentGORMCountsQueries(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, InstrumentGORM(db))
+	require.NoError(t, db.AutoMigrate(&metricsTestRow{}))
+
+	require.NoError(t, db.Create(&metricsTestRow{Name: "a"}).Error)
+	var row metricsTestRow
+	require.NoError(t, db.First(&row).Error)
+	assert.ErrorIs(t, db.First(&row, 99).Error, gorm.ErrRecordNotFound)
+	assert.Error(t, db.Table("missing_table").Find(&[]metricsTestRow{}).Error)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(dbQueries.WithLabelValues("create", "metrics_test_rows", "ok")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(dbQueries.WithLabelValues("query", "metrics_test_rows", "ok")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(dbQueries.WithLabelValues("query", "missing_table", "error")))
+}
@@ -0,0 +1,34 @@
+// Package ocr provides text recognition adapters for recipe photo import
+package ocr
+
+import (
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// Supported providers
+const (
+	ProviderTesseract = "tesseract"
+	ProviderOllama    = "ollama"
+	ProviderNone      = "none"
+)
+
+// NewService returns the configured OCR provider, or nil when photo import
+// is disabled
+func NewService(cfg config.OCRConfig, logger *zap.Logger) outbound.OCRService {
+	switch strings.ToLower(cfg.Provider) {
+	case ProviderOllama:
+		return NewOllamaVision(cfg, logger)
+	case ProviderNone:
+		logger.Info("OCR disabled, recipe photo import unavailable")
+		return nil
+	case ProviderTesseract, "":
+		return NewTesseract(cfg, logger)
+	default:
+		logger.Warn("Unknown OCR provider, falling back to tesseract", zap.String("provider", cfg.Provider))
+		return NewTesseract(cfg, logger)
+	}
+}
@@ -0,0 +1,42 @@
+package ocr
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTSVGroupsLinesAndParagraphs(t *testing.T) {
+	tsv := "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+		"1\t1\t0\t0\t0\t0\t0\t0\t800\t600\t-1\t\n" +
+		"5\t1\t1\t1\t1\t1\t10\t10\t50\t20\t96\tLemon\n" +
+		"5\t1\t1\t1\t1\t2\t70\t10\t50\t20\t90\tBars\n" +
+		"5\t1\t2\t1\t1\t1\t10\t50\t20\t20\t80\t2\n" +
+		"5\t1\t2\t1\t1\t2\t40\t50\t40\t20\t40\tcups\n" +
+		"5\t1\t2\t1\t2\t1\t10\t80\t40\t20\t-1\t \n" +
+		"5\t1\t2\t1\t3\t1\t10\t110\t40\t20\t70\tsugar\n"
+
+	lines, err := parseTSV(bytes.NewBufferString(tsv))
+	require.NoError(t, err)
+	require.Len(t, lines, 4)
+
+	assert.Equal(t, "Lemon Bars", lines[0].Text)
+	assert.InDelta(t, 0.93, lines[0].Confidence, 0.001)
+	assert.Empty(t, lines[1].Text)
+	assert.Equal(t, "2 cups", lines[2].Text)
+	assert.InDelta(t, 0.6, lines[2].Confidence, 0.001)
+	assert.Equal(t, "sugar", lines[3].Text)
+}
+
+func TestParseTranscriptionFlagsUnsureWords(t *testing.T) {
+	lines := parseTranscription("Pancakes\n\n1 cup [?buttermilk]\n2 eggs\n")
+	require.Len(t, lines, 4)
+
+	assert.Equal(t, "Pancakes", lines[0].Text)
+	assert.Empty(t, lines[1].Text)
+	assert.Equal(t, "1 cup buttermilk", lines[2].Text)
+	assert.Equal(t, visionUnsureConfidence, lines[2].Confidence)
+	assert.Equal(t, visionConfidence, lines[3].Confidence)
+}
@@ -0,0 +1,157 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// Vision models report no scores, so confidence comes from the model
+// flagging words it could not read
+const (
+	visionConfidence        = 0.85
+	visionUnsureConfidence  = 0.4
+	visionUnsureMarkerStart = "[?"
+)
+
+const visionPrompt = `Transcribe all text in this photo of a printed recipe exactly as written.
+Keep the original line breaks and leave an empty line between sections.
+Do not add, translate, summarize or reformat anything.
+Wrap any word you cannot read with certainty as [?word].
+Reply with the transcription only.`
+
+// OllamaVision recognizes text with a multimodal model served by Ollama
+type OllamaVision struct {
+	baseURL string
+	model   string
+	client  *http.Client
+	logger  *zap.Logger
+}
+
+// NewOllamaVision creates an Ollama vision OCR adapter
+func NewOllamaVision(cfg config.OCRConfig, logger *zap.Logger) *OllamaVision {
+	baseURL := cfg.OllamaHost
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	model := cfg.OllamaModel
+	if model == "" {
+		model = "llava:7b"
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+
+	return &OllamaVision{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		model:   model,
+		client:  &http.Client{Timeout: timeout},
+		logger:  logger.Named("ocr-ollama"),
+	}
+}
+
+type visionRequest struct {
+	Model   string                 `json:"model"`
+	Prompt  string                 `json:"prompt"`
+	Images  []string               `json:"images"`
+	Stream  bool                   `json:"stream"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+type visionResponse struct {
+	Response string `json:"response"`
+	Done     bool   `json:"done"`
+}
+
+// ExtractText asks the vision model for a verbatim transcription
+func (o *OllamaVision) ExtractText(ctx context.Context, image []byte, contentType string) (*outbound.OCRResult, error) {
+	body, err := json.Marshal(visionRequest{
+		Model:   o.model,
+		Prompt:  visionPrompt,
+		Images:  []string{base64.StdEncoding.EncodeToString(image)},
+		Stream:  false,
+		Options: map[string]interface{}{"temperature": 0},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/api/generate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama vision request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama vision error %d: %s", resp.StatusCode, string(raw))
+	}
+
+	var out visionResponse
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if !out.Done {
+		return nil, fmt.Errorf("incomplete response from ollama vision")
+	}
+
+	lines := parseTranscription(out.Response)
+	o.logger.Debug("Ollama vision recognition complete", zap.String("model", o.model), zap.Int("lines", len(lines)))
+
+	return &outbound.OCRResult{Provider: ProviderOllama, Lines: lines}, nil
+}
+
+// parseTranscription splits the model reply into lines, lowering the
+// confidence of lines with [?word] markers and stripping the markers
+func parseTranscription(text string) []outbound.OCRLine {
+	var lines []outbound.OCRLine
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			lines = append(lines, outbound.OCRLine{Confidence: 1})
+			continue
+		}
+
+		confidence := visionConfidence
+		if strings.Contains(line, visionUnsureMarkerStart) {
+			confidence = visionUnsureConfidence
+			line = stripUnsureMarkers(line)
+		}
+		lines = append(lines, outbound.OCRLine{Text: line, Confidence: confidence})
+	}
+	return lines
+}
+
+func stripUnsureMarkers(line string) string {
+	for {
+		start := strings.Index(line, visionUnsureMarkerStart)
+		if start < 0 {
+			return line
+		}
+		end := strings.Index(line[start:], "]")
+		if end < 0 {
+			return line[:start] + line[start+len(visionUnsureMarkerStart):]
+		}
+		line = line[:start] + line[start+len(visionUnsureMarkerStart):start+end] + line[start+end+1:]
+	}
+}
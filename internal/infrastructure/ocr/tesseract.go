@@ -0,0 +1,137 @@
+package ocr
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// Tesseract runs the tesseract CLI and reads per-word confidences from its
+// TSV output
+type Tesseract struct {
+	path      string
+	languages string
+	timeout   time.Duration
+	logger    *zap.Logger
+}
+
+// NewTesseract creates a tesseract OCR adapter
+func NewTesseract(cfg config.OCRConfig, logger *zap.Logger) *Tesseract {
+	path := cfg.TesseractPath
+	if path == "" {
+		path = "tesseract"
+	}
+	languages := cfg.Languages
+	if languages == "" {
+		languages = "eng"
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+
+	return &Tesseract{
+		path:      path,
+		languages: languages,
+		timeout:   timeout,
+		logger:    logger.Named("ocr-tesseract"),
+	}
+}
+
+// ExtractText recognizes the image piped through stdin
+func (t *Tesseract) ExtractText(ctx context.Context, image []byte, contentType string) (*outbound.OCRResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.path, "stdin", "stdout", "-l", t.languages, "tsv")
+	cmd.Stdin = bytes.NewReader(image)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("tesseract failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	lines, err := parseTSV(&stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	t.logger.Debug("Tesseract recognition complete",
+		zap.Int("lines", len(lines)),
+		zap.Duration("duration", time.Since(start)),
+	)
+
+	return &outbound.OCRResult{Provider: ProviderTesseract, Lines: lines}, nil
+}
+
+// parseTSV groups word rows into lines. Line confidence is the mean word
+// confidence, and a paragraph change inserts an empty line.
+func parseTSV(r io.Reader) ([]outbound.OCRLine, error) {
+	var (
+		lines     []outbound.OCRLine
+		words     []string
+		confSum   float64
+		lineKey   string
+		paraKey   string
+		seenWords bool
+	)
+
+	flush := func() {
+		if len(words) > 0 {
+			lines = append(lines, outbound.OCRLine{
+				Text:       strings.Join(words, " "),
+				Confidence: confSum / float64(len(words)) / 100,
+			})
+		}
+		words, confSum = nil, 0
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		cols := strings.Split(scanner.Text(), "\t")
+		// level page block par line word left top width height conf text
+		if len(cols) < 12 || cols[0] != "5" {
+			continue
+		}
+		text := strings.TrimSpace(cols[11])
+		if text == "" {
+			continue
+		}
+		conf, err := strconv.ParseFloat(cols[10], 64)
+		if err != nil || conf < 0 {
+			continue
+		}
+
+		para := strings.Join(cols[1:4], ".")
+		line := para + "." + cols[4]
+		if line != lineKey {
+			flush()
+			if seenWords && para != paraKey {
+				lines = append(lines, outbound.OCRLine{Confidence: 1})
+			}
+			lineKey, paraKey = line, para
+		}
+		words = append(words, text)
+		confSum += conf
+		seenWords = true
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tesseract output: %w", err)
+	}
+	return lines, nil
+}
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/alchemorsel/v3/internal/infrastructure/cache"
-	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
@@ -62,9 +61,9 @@ func (c *CacheManager) Get(ctx context.Context, key string, dest interface{}) er
 		return json.Unmarshal(data, dest)
 	}
 
-	// Try Redis cache (L2)
-	data, err := c.redis.Get(ctx, key).Bytes()
-	if err == redis.Nil {
+	// Try the cache service (L2)
+	data, err := c.cacheService.Get(ctx, key)
+	if err == cache.ErrKeyNotFound {
 		c.metrics.Misses++
 		return ErrCacheKeyNotFound
 	}
@@ -98,8 +97,8 @@ func (c *CacheManager) Set(ctx context.Context, key string, value interface{}, t
 	// Store in local cache (L1)
 	c.localCache.Set(key, data, ttl)
 
-	// Store in Redis cache (L2)
-	err = c.redis.Set(ctx, key, data, ttl).Err()
+	// Store in the cache service (L2)
+	err = c.cacheService.Set(ctx, key, data, ttl)
 	if err != nil {
 		c.metrics.Errors++
 		c.logger.Error("Redis cache set error", zap.String("key", key), zap.Error(err))
@@ -114,8 +113,8 @@ func (c *CacheManager) Delete(ctx context.Context, key string) error {
 	// Remove from local cache
 	c.localCache.Delete(key)
 
-	// Remove from Redis cache
-	err := c.redis.Del(ctx, key).Err()
+	// Remove from the cache service
+	err := c.cacheService.Delete(ctx, key)
 	if err != nil {
 		c.metrics.Errors++
 		c.logger.Error("Redis cache delete error", zap.String("key", key), zap.Error(err))
@@ -146,29 +145,19 @@ func (c *CacheManager) GetMulti(ctx context.Context, keys []string) (map[string]
 		}
 	}
 
-	// Fetch missing keys from Redis
+	// Fetch missing keys from the cache service
 	if len(missingKeys) > 0 {
-		pipe := c.redis.Pipeline()
-		cmds := make(map[string]*redis.StringCmd)
-		
-		for _, key := range missingKeys {
-			cmds[key] = pipe.Get(ctx, key)
-		}
-		
-		_, err := pipe.Exec(ctx)
-		if err != nil && err != redis.Nil {
+		found, err := c.cacheService.MGet(ctx, missingKeys)
+		if err != nil {
 			c.metrics.Errors++
 			return nil, err
 		}
 
-		for key, cmd := range cmds {
-			if data, err := cmd.Bytes(); err == nil {
+		for _, key := range missingKeys {
+			if data, ok := found[key]; ok {
 				results[key] = data
 				c.localCache.Set(key, data, c.config.DefaultTTL)
 				c.metrics.Hits++
-			} else if err != redis.Nil {
-				c.metrics.Errors++
-				c.logger.Error("Redis multi-get error", zap.String("key", key), zap.Error(err))
 			} else {
 				c.metrics.Misses++
 			}
@@ -186,8 +175,7 @@ func (c *CacheManager) SetMulti(ctx context.Context, items map[string]interface{
 		c.metrics.TotalTime += time.Since(start)
 	}()
 
-	pipe := c.redis.Pipeline()
-	
+	encoded := make(map[string][]byte, len(items))
 	for key, value := range items {
 		data, err := json.Marshal(value)
 		if err != nil {
@@ -197,12 +185,11 @@ func (c *CacheManager) SetMulti(ctx context.Context, items map[string]interface{
 
 		// Store in local cache
 		c.localCache.Set(key, data, ttl)
-		
-		// Store in Redis cache
-		pipe.Set(ctx, key, data, ttl)
+		encoded[key] = data
 	}
 
-	_, err := pipe.Exec(ctx)
+	// Store in the cache service
+	err := c.cacheService.MSet(ctx, encoded, ttl)
 	if err != nil {
 		c.metrics.Errors++
 		return err
@@ -216,27 +203,12 @@ func (c *CacheManager) Invalidate(ctx context.Context, pattern string) error {
 	// Clear local cache entries matching pattern
 	c.localCache.InvalidatePattern(pattern)
 
-	// Use Redis SCAN to find and delete matching keys
-	iter := c.redis.Scan(ctx, 0, pattern, 0).Iterator()
-	keysToDelete := make([]string, 0)
-
-	for iter.Next(ctx) {
-		keysToDelete = append(keysToDelete, iter.Val())
-	}
-
-	if err := iter.Err(); err != nil {
+	// Delete matching keys from the cache service
+	if err := c.cacheService.InvalidateByPattern(ctx, pattern); err != nil {
 		c.metrics.Errors++
 		return err
 	}
 
-	if len(keysToDelete) > 0 {
-		err := c.redis.Del(ctx, keysToDelete...).Err()
-		if err != nil {
-			c.metrics.Errors++
-			return err
-		}
-	}
-
 	return nil
 }
 
@@ -338,25 +310,18 @@ func (c *CacheManager) SetSession(ctx context.Context, session *Session) error {
 
 // Rate limiting cache methods
 func (c *CacheManager) GetRateLimit(ctx context.Context, key string) (int, error) {
-	var count int
-	err := c.Get(ctx, fmt.Sprintf("ratelimit:%s", key), &count)
-	return count, err
+	count, err := c.cacheService.Counter(ctx, fmt.Sprintf("ratelimit:%s", key))
+	return int(count), err
 }
 
 func (c *CacheManager) IncrementRateLimit(ctx context.Context, key string, window time.Duration) (int, error) {
-	redisKey := fmt.Sprintf("ratelimit:%s", key)
-	
-	// Use Redis INCR with expiration
-	pipe := c.redis.Pipeline()
-	incr := pipe.Incr(ctx, redisKey)
-	pipe.Expire(ctx, redisKey, window)
-	_, err := pipe.Exec(ctx)
-	
+	// Counted in Redis alone so every replica sees the same count
+	count, err := c.cacheService.Increment(ctx, fmt.Sprintf("ratelimit:%s", key), window)
 	if err != nil {
 		return 0, err
 	}
 	
-	return int(incr.Val()), nil
+	return int(count), nil
 }
 
 // Errors
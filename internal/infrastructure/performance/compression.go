@@ -1,10 +1,8 @@
 package performance
 
 import (
-	"bufio"
 	"bytes"
 	"compress/gzip"
-	"fmt"
 	"io"
 	"net/http"
 	"strconv"
@@ -13,17 +11,17 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// CompressionConfig holds compression configuration
-type CompressionConfig struct {
+// GzipConfig holds compression configuration
+type GzipConfig struct {
 	Level            int      // Compression level (1-9)
 	MinSize          int      // Minimum size to compress (bytes)
 	ExcludedMimeTypes []string // MIME types to exclude from compression
 	IncludedMimeTypes []string // MIME types to include for compression
 }
 
-// DefaultCompressionConfig returns default compression settings
-func DefaultCompressionConfig() CompressionConfig {
-	return CompressionConfig{
+// DefaultGzipConfig returns default compression settings
+func DefaultGzipConfig() GzipConfig {
+	return GzipConfig{
 		Level:   6, // Good balance between compression ratio and speed
 		MinSize: 1024, // Don't compress files smaller than 1KB
 		ExcludedMimeTypes: []string{
@@ -54,7 +52,7 @@ func DefaultCompressionConfig() CompressionConfig {
 }
 
 // GzipMiddleware creates a Gin middleware for gzip compression
-func GzipMiddleware(config CompressionConfig) gin.HandlerFunc {
+func GzipMiddleware(config GzipConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check if client accepts gzip
 		if !strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") {
@@ -78,7 +76,7 @@ func GzipMiddleware(config CompressionConfig) gin.HandlerFunc {
 // gzipResponseWriter wraps gin.ResponseWriter to provide gzip compression
 type gzipResponseWriter struct {
 	gin.ResponseWriter
-	config     CompressionConfig
+	config     GzipConfig
 	c          *gin.Context
 	gzipWriter *gzip.Writer
 	buffer     *bytes.Buffer
@@ -197,11 +195,11 @@ func BrotliMiddleware() gin.HandlerFunc {
 
 // StaticFileCompressor handles compression of static files
 type StaticFileCompressor struct {
-	config CompressionConfig
+	config GzipConfig
 }
 
 // NewStaticFileCompressor creates a new static file compressor
-func NewStaticFileCompressor(config CompressionConfig) *StaticFileCompressor {
+func NewStaticFileCompressor(config GzipConfig) *StaticFileCompressor {
 	return &StaticFileCompressor{
 		config: config,
 	}
@@ -301,7 +299,7 @@ func DecompressionHandler() gin.HandlerFunc {
 
 // CompressionStatsMiddleware tracks compression statistics
 func CompressionStatsMiddleware() gin.HandlerFunc {
-	stats := &CompressionStats{}
+	stats := &GzipStats{}
 
 	return func(c *gin.Context) {
 		// Wrap the response writer to track statistics
@@ -315,8 +313,8 @@ func CompressionStatsMiddleware() gin.HandlerFunc {
 	}
 }
 
-// CompressionStats tracks compression performance metrics
-type CompressionStats struct {
+// GzipStats tracks compression performance metrics
+type GzipStats struct {
 	TotalRequests     int64
 	CompressedBytes   int64
 	UncompressedBytes int64
@@ -326,7 +324,7 @@ type CompressionStats struct {
 // statsResponseWriter wraps ResponseWriter to collect compression statistics
 type statsResponseWriter struct {
 	gin.ResponseWriter
-	stats           *CompressionStats
+	stats           *GzipStats
 	originalSize    int
 	compressedSize  int
 }
@@ -339,7 +337,7 @@ func (w *statsResponseWriter) Write(data []byte) (int, error) {
 }
 
 // GetCompressionStats returns current compression statistics
-func (s *CompressionStats) GetStats() map[string]interface{} {
+func (s *GzipStats) GetStats() map[string]interface{} {
 	ratio := float64(0)
 	if s.UncompressedBytes > 0 {
 		ratio = float64(s.CompressedBytes) / float64(s.UncompressedBytes)
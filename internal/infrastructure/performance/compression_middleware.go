@@ -2,7 +2,6 @@
 package performance
 
 import (
-	"bufio"
 	"bytes"
 	"compress/gzip"
 	"fmt"
@@ -228,8 +228,10 @@ func (cce *CriticalCSSExtractor) buildCriticalCSS(selectors []string, rules []*C
 		return rule1.Priority > rule2.Priority
 	})
 	
+	// Theme custom properties are always inlined, so reserve their space
+	themeCSS := ThemeCSS()
 	currentSize := 0
-	maxSize := MaxCriticalCSS
+	maxSize := MaxCriticalCSS - len(themeCSS)
 	
 	// Add rules while staying within size limit
 	for _, selector := range selectors {
@@ -250,6 +252,8 @@ func (cce *CriticalCSSExtractor) buildCriticalCSS(selectors []string, rules []*C
 	// Build final CSS
 	var cssBuilder strings.Builder
 	cssBuilder.WriteString("/* Critical CSS - Inlined for 14KB optimization */\n")
+	cssBuilder.WriteString(themeCSS)
+	cssBuilder.WriteString("\n")
 	
 	for _, rule := range criticalRules {
 		cssBuilder.WriteString(cce.formatCSSRule(rule))
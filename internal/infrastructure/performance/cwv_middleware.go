@@ -9,8 +9,6 @@ import (
 	"regexp"
 	"strings"
 	"time"
-
-	"github.com/alchemorsel/v3/internal/infrastructure/cache"
 )
 
 // CoreWebVitalsMiddleware provides automatic Core Web Vitals optimization for HTTP responses
@@ -294,12 +292,10 @@ func (m *CoreWebVitalsMiddleware) optimizeHTML(ctx context.Context, html string,
 	
 	// Inject RUM script if enabled
 	if m.config.EnableRUM {
-		optimized = m.injectRUMScript([]byte(optimized), r)
-	} else {
-		optimized = []byte(optimized)
+		return m.injectRUMScript([]byte(optimized), r), nil
 	}
 	
-	return optimized, nil
+	return []byte(optimized), nil
 }
 
 // injectRUMScript injects the RUM (Real User Monitoring) script into HTML
@@ -364,12 +360,11 @@ func (m *CoreWebVitalsMiddleware) recordMetrics(r *http.Request, originalSize, o
 		Timestamp: time.Now(),
 		URL:       r.URL.String(),
 		UserAgent: r.UserAgent(),
-		CustomData: map[string]interface{}{
-			"optimization_type":     "middleware",
-			"original_size":         originalSize,
-			"optimized_size":        optimizedSize,
-			"optimization_duration": duration.Milliseconds(),
-			"size_reduction":        originalSize - optimizedSize,
+		Metrics: map[string]float64{
+			"original_size":         float64(originalSize),
+			"optimized_size":        float64(optimizedSize),
+			"optimization_duration": float64(duration.Milliseconds()),
+			"size_reduction":        float64(originalSize - optimizedSize),
 			"size_reduction_pct":    float64(originalSize-optimizedSize) / float64(originalSize) * 100,
 		},
 	}
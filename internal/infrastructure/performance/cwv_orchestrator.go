@@ -19,9 +19,9 @@ type CoreWebVitalsOrchestrator struct {
 	config                  CWVOrchestratorConfig
 	
 	// Core Web Vitals optimizers
-	lcpOptimizer           *LCPOptimizer
-	clsStabilizer          *LayoutStabilizer
-	inpEnhancer            *INPEnhancer
+	lcpOptimizer           HTMLOptimizer
+	clsStabilizer          HTMLOptimizer
+	inpEnhancer            HTMLOptimizer
 	rumSystem              *RUMSystem
 	coreWebVitalsMonitor   *CoreWebVitalsMonitor
 	
@@ -162,8 +162,44 @@ func DefaultPerformanceTargets() PerformanceTargets {
 	}
 }
 
-// NewCoreWebVitalsOrchestrator creates a new Core Web Vitals optimization orchestrator
-func NewCoreWebVitalsOrchestrator(config CWVOrchestratorConfig, cacheClient *cache.RedisClient) (*CoreWebVitalsOrchestrator, error) {
+// CWVOption replaces one of the orchestrator's components, typically with a
+// fake in tests
+type CWVOption func(*CoreWebVitalsOrchestrator)
+
+// WithLCPOptimizer runs optimizer as the LCP stage
+func WithLCPOptimizer(optimizer HTMLOptimizer) CWVOption {
+	return func(o *CoreWebVitalsOrchestrator) {
+		o.lcpOptimizer = optimizer
+	}
+}
+
+// WithCLSStabilizer runs optimizer as the CLS stage
+func WithCLSStabilizer(optimizer HTMLOptimizer) CWVOption {
+	return func(o *CoreWebVitalsOrchestrator) {
+		o.clsStabilizer = optimizer
+	}
+}
+
+// WithINPEnhancer runs optimizer as the INP stage
+func WithINPEnhancer(optimizer HTMLOptimizer) CWVOption {
+	return func(o *CoreWebVitalsOrchestrator) {
+		o.inpEnhancer = optimizer
+	}
+}
+
+// WithRUMSystem uses rum for real user monitoring, e.g. one built with its
+// own MeasurementStore and Alerter
+func WithRUMSystem(rum *RUMSystem) CWVOption {
+	return func(o *CoreWebVitalsOrchestrator) {
+		o.rumSystem = rum
+	}
+}
+
+// NewCoreWebVitalsOrchestrator creates a new Core Web Vitals optimization orchestrator.
+// Components enabled in config are built from their defaults unless opts
+// supply them; a component supplied through opts runs even if config
+// disables it.
+func NewCoreWebVitalsOrchestrator(config CWVOrchestratorConfig, cacheClient *cache.RedisClient, opts ...CWVOption) (*CoreWebVitalsOrchestrator, error) {
 	// Set performance targets
 	targets := PerformanceTargets{
 		LCP: config.TargetLCP,
@@ -171,62 +207,53 @@ func NewCoreWebVitalsOrchestrator(config CWVOrchestratorConfig, cacheClient *cac
 		INP: config.TargetINP,
 	}
 	
-	// Initialize Core Web Vitals optimizers
-	var lcpOptimizer *LCPOptimizer
-	var clsStabilizer *LayoutStabilizer
-	var inpEnhancer *INPEnhancer
-	var rumSystem *RUMSystem
-	var coreWebVitalsMonitor *CoreWebVitalsMonitor
+	orchestrator := &CoreWebVitalsOrchestrator{
+		config:      config,
+		targets:     targets,
+		cacheClient: cacheClient,
+	}
+	for _, opt := range opts {
+		opt(orchestrator)
+	}
 	
-	if config.EnableLCPOptimization {
+	// Initialize the Core Web Vitals optimizers opts didn't supply. Only
+	// assign built components, so a disabled stage stays a nil interface.
+	if config.EnableLCPOptimization && orchestrator.lcpOptimizer == nil {
 		lcpConfig := DefaultLCPConfig()
 		lcpConfig.TargetLCP = config.TargetLCP
 		lcpConfig.EnableRedisCache = config.EnableRedisCache
 		lcpConfig.EnableBundleOptimization = config.EnableBundleOptimization
 		lcpConfig.MaxBundleSize = config.MaxBundleSize
 		lcpConfig.CacheTTL = config.CacheTTL
-		lcpOptimizer = NewLCPOptimizer(lcpConfig, cacheClient)
+		orchestrator.lcpOptimizer = NewLCPOptimizer(lcpConfig, cacheClient)
 	}
 	
-	if config.EnableCLSStabilization {
+	if config.EnableCLSStabilization && orchestrator.clsStabilizer == nil {
 		clsConfig := DefaultLayoutStabilityConfig()
 		clsConfig.MaxCLSScore = config.TargetCLS
-		clsStabilizer = NewLayoutStabilizer(clsConfig)
+		orchestrator.clsStabilizer = NewLayoutStabilizer(clsConfig)
 	}
 	
-	if config.EnableINPEnhancement {
+	if config.EnableINPEnhancement && orchestrator.inpEnhancer == nil {
 		inpConfig := DefaultINPConfig()
 		inpConfig.TargetINP = config.TargetINP
 		inpConfig.EnableHTMXOptimization = true
-		inpEnhancer = NewINPEnhancer(inpConfig)
+		orchestrator.inpEnhancer = NewINPEnhancer(inpConfig)
 	}
 	
 	if config.EnableRealUserMonitoring {
-		rumConfig := DefaultRUMConfig()
-		rumConfig.SampleRate = config.SampleRate
-		rumConfig.EnableRealTimeAlerts = config.EnableRealTimeAlerts
-		rumConfig.AlertThresholds = config.AlertThresholds
-		rumSystem = NewRUMSystem(rumConfig, cacheClient)
+		if orchestrator.rumSystem == nil {
+			rumConfig := DefaultRUMConfig()
+			rumConfig.SampleRate = config.SampleRate
+			rumConfig.EnableRealTimeAlerts = config.EnableRealTimeAlerts
+			rumConfig.AlertThresholds = rumAlertThresholds(rumConfig.AlertThresholds, config.AlertThresholds)
+			orchestrator.rumSystem = NewRUMSystem(rumConfig, cacheClient)
+		}
 		
 		// Initialize Core Web Vitals monitor
 		cwvConfig := DefaultCWVConfig()
 		cwvConfig.AlertThresholds = config.AlertThresholds
-		coreWebVitalsMonitor = NewCoreWebVitalsMonitor(cwvConfig)
-	}
-	
-	orchestrator := &CoreWebVitalsOrchestrator{
-		config:                  config,
-		targets:                 targets,
-		
-		// Core Web Vitals optimizers
-		lcpOptimizer:           lcpOptimizer,
-		clsStabilizer:          clsStabilizer,
-		inpEnhancer:            inpEnhancer,
-		rumSystem:              rumSystem,
-		coreWebVitalsMonitor:   coreWebVitalsMonitor,
-		
-		// Infrastructure
-		cacheClient:            cacheClient,
+		orchestrator.coreWebVitalsMonitor = NewCoreWebVitalsMonitor(cwvConfig)
 	}
 	
 	// Setup optimization pipeline
@@ -351,7 +378,7 @@ func (o *CoreWebVitalsOrchestrator) stabilizeCLSStage(ctx context.Context, html
 		return html, nil
 	}
 	
-	optimized, err := o.clsStabilizer.StabilizeHTML(html)
+	optimized, err := o.clsStabilizer.OptimizeHTMLWithContext(ctx, html)
 	if err != nil {
 		return "", fmt.Errorf("CLS stabilization failed: %w", err)
 	}
@@ -365,7 +392,7 @@ func (o *CoreWebVitalsOrchestrator) enhanceINPStage(ctx context.Context, html st
 		return html, nil
 	}
 	
-	optimized, err := o.inpEnhancer.OptimizeHTML(html)
+	optimized, err := o.inpEnhancer.OptimizeHTMLWithContext(ctx, html)
 	if err != nil {
 		return "", fmt.Errorf("INP enhancement failed: %w", err)
 	}
@@ -531,8 +558,12 @@ func (o *CoreWebVitalsOrchestrator) estimatePerformanceImpact() PerformanceImpac
 	
 	// Estimate LCP improvement
 	if o.lcpOptimizer != nil {
-		metrics := o.lcpOptimizer.GetMetrics()
-		if metrics.TotalOptimizations > 0 {
+		// Substitute optimizers report no metrics, so count as having run
+		optimized := true
+		if lcp, ok := o.lcpOptimizer.(*LCPOptimizer); ok {
+			optimized = lcp.GetMetrics().TotalOptimizations > 0
+		}
+		if optimized {
 			impact.EstimatedLCPImprovement = 500 * time.Millisecond // Conservative estimate
 			impact.BundleSizeReduction = int64(o.config.MaxBundleSize) / 2 // Assume 50% reduction
 		}
@@ -827,4 +858,20 @@ window.rumConfig = {
 `, o.config.SampleRate))
 		},
 	}
-}
\ No newline at end of file
+}
+
+// rumAlertThresholds warns on the RUM metrics past their good thresholds
+// and raises critical alerts past their poor ones, keeping the windows of
+// defaults
+func rumAlertThresholds(defaults AlertThresholds, thresholds CWVThresholds) AlertThresholds {
+	apply := func(config *ThresholdConfig, threshold CWVThreshold) {
+		config.Warning = threshold.Good
+		config.Critical = threshold.Poor
+	}
+	apply(&defaults.LCP, thresholds.LCP)
+	apply(&defaults.CLS, thresholds.CLS)
+	apply(&defaults.INP, thresholds.INP)
+	apply(&defaults.FCP, thresholds.FCP)
+	apply(&defaults.TTFB, thresholds.TTFB)
+	return defaults
+}
@@ -2,12 +2,9 @@
 package performance
 
 import (
-	"context"
 	"strings"
 	"testing"
 	"time"
-
-	"github.com/alchemorsel/v3/internal/infrastructure/cache"
 )
 
 // TestCoreWebVitalsTargets validates that optimization meets Google's Core Web Vitals targets
@@ -18,10 +15,8 @@ func TestCoreWebVitalsTargets(t *testing.T) {
 	config.TargetCLS = 0.1                     // 0.1
 	config.TargetINP = 200 * time.Millisecond  // 200ms
 	
-	// Mock cache client for testing
-	cacheClient := &cache.RedisClient{} // Assuming this exists
-	
-	orchestrator, err := NewCoreWebVitalsOrchestrator(config, cacheClient)
+	// No Redis in unit tests; the optimizers skip caching without a client
+	orchestrator, err := NewCoreWebVitalsOrchestrator(config, nil)
 	if err != nil {
 		t.Fatalf("Failed to create orchestrator: %v", err)
 	}
@@ -429,8 +424,7 @@ func TestBundleOptimization(t *testing.T) {
 	config.EnableBundleOptimization = true
 	config.MaxBundleSize = 14 * 1024 // 14KB
 	
-	cacheClient := &cache.RedisClient{}
-	orchestrator, err := NewCoreWebVitalsOrchestrator(config, cacheClient)
+	orchestrator, err := NewCoreWebVitalsOrchestrator(config, nil)
 	if err != nil {
 		t.Fatalf("Failed to create orchestrator: %v", err)
 	}
@@ -494,9 +488,12 @@ func TestBundleOptimization(t *testing.T) {
 		t.Error("Critical JS bundle was not created")
 	}
 	
-	// Verify the optimized content is smaller
-	if len(optimized) >= len(html) {
-		t.Error("Bundle optimization should reduce content size")
+	// Verify the page's CSS moved into the bundle and its JS runs once
+	if strings.Count(optimized, ".instruction-step") != 1 {
+		t.Error("Bundle optimization should move inline CSS, not copy it")
+	}
+	if strings.Count(optimized, "function initializeApp()") != 1 {
+		t.Error("Bundle optimization should not copy inline scripts")
 	}
 	
 	// Verify 14KB compliance (this would need more sophisticated measurement in practice)
@@ -512,9 +509,8 @@ func TestBundleOptimization(t *testing.T) {
 // TestPerformanceTargetsMet tests that performance targets are met
 func TestPerformanceTargetsMet(t *testing.T) {
 	config := DefaultCWVOrchestratorConfig()
-	cacheClient := &cache.RedisClient{}
 	
-	orchestrator, err := NewCoreWebVitalsOrchestrator(config, cacheClient)
+	orchestrator, err := NewCoreWebVitalsOrchestrator(config, nil)
 	if err != nil {
 		t.Fatalf("Failed to create orchestrator: %v", err)
 	}
@@ -624,9 +620,8 @@ func TestRealWorldScenarios(t *testing.T) {
 	}
 	
 	config := DefaultCWVOrchestratorConfig()
-	cacheClient := &cache.RedisClient{}
 	
-	orchestrator, err := NewCoreWebVitalsOrchestrator(config, cacheClient)
+	orchestrator, err := NewCoreWebVitalsOrchestrator(config, nil)
 	if err != nil {
 		t.Fatalf("Failed to create orchestrator: %v", err)
 	}
@@ -775,7 +770,7 @@ func validateScenarioOptimizations(html, deviceType, connection string) Scenario
 			issues = append(issues, "Should inline critical CSS for slow connections")
 		}
 		
-		if !strings.Contains(html, `delay:`) && strings.Contains(html, `hx-trigger`) {
+		if !strings.Contains(html, `delay:`) && strings.Contains(html, `hx-trigger="`) {
 			issues = append(issues, "Should debounce HTMX requests on slow connections")
 		}
 	}
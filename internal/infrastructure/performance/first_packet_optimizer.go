@@ -4,10 +4,8 @@ package performance
 import (
 	"bytes"
 	"compress/gzip"
-	"context"
 	"fmt"
 	"html/template"
-	"io"
 	"log"
 	"strings"
 	"time"
@@ -241,7 +239,7 @@ func (fpo *FirstPacketOptimizer) generateRecommendations(result *CompressionResu
 			"Poor compression ratio - consider removing redundant content")
 	}
 
-	if result.Brotli > MaxFirstPacketSize*0.9 {
+	if result.Brotli > MaxFirstPacketSize*9/10 {
 		recommendations = append(recommendations, 
 			"Template is close to 14KB limit - monitor for future additions")
 	}
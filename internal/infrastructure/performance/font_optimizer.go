@@ -57,7 +57,7 @@ type FallbackManager struct {
 type PreloadManager struct {
 	criticalFonts      []CriticalFont
 	preloadHints       []FontPreloadHint
-	resourceHints      []ResourceHint
+	resourceHints      []FontResourceHint
 	crossOriginPolicy  string
 }
 
@@ -132,8 +132,8 @@ type FontPreloadHint struct {
 	Type        string
 }
 
-// ResourceHint represents a resource hint for fonts
-type ResourceHint struct {
+// FontResourceHint represents a resource hint for fonts
+type FontResourceHint struct {
 	Type        string // preconnect, dns-prefetch, preload
 	URL         string
 	CrossOrigin bool
@@ -1,7 +1,6 @@
 package performance
 
 import (
-	"context"
 	"crypto/tls"
 	"fmt"
 	"net/http"
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"html/template"
 	"net/url"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -510,9 +509,8 @@ func (io *ImageOptimizer) addFormatOptimization(imgTag string, attrs map[string]
 func (io *ImageOptimizer) generatePictureElement(imgTag string, attrs map[string]string, src string) string {
 	var sources []string
 
-	// Get image strategy and dimensions
+	// Get image strategy
 	strategy := io.determineImageStrategy(attrs)
-	width, height := io.extractDimensions(attrs)
 
 	// Generate sources for modern formats
 	for _, format := range io.config.Formats {
@@ -1194,26 +1192,12 @@ func (io *ImageOptimizer) TemplateFunction() template.FuncMap {
 	return template.FuncMap{
 		"optimizeImage": func(src, alt string, width, height int, strategy string) template.HTML {
 			// Generate optimized image HTML
-			attrs := map[string]string{
-				"src":    src,
-				"alt":    alt,
-				"width":  strconv.Itoa(width),
-				"height": strconv.Itoa(height),
-				"class":  strategy,
-			}
-			
 			imgTag := fmt.Sprintf(`<img src="%s" alt="%s" width="%d" height="%d" class="%s">`,
 				src, alt, width, height, strategy)
 			
 			return template.HTML(io.optimizeImageTag(imgTag))
 		},
 		"responsiveImage": func(src, alt string, strategy string) template.HTML {
-			attrs := map[string]string{
-				"src":   src,
-				"alt":   alt,
-				"class": strategy,
-			}
-			
 			imgTag := fmt.Sprintf(`<img src="%s" alt="%s" class="%s">`, src, alt, strategy)
 			optimized := io.optimizeImageTag(imgTag)
 			
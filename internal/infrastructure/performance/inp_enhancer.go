@@ -791,6 +791,9 @@ document.addEventListener('htmx:afterSwap', function(event) {
 func (inp *INPEnhancer) addVirtualScrolling(html string) string {
 	// Find long lists that would benefit from virtual scrolling
 	listRegex := regexp.MustCompile(`<(?:ul|ol|div)\s+class="[^"]*(?:recipe-list|search-results|infinite-list)[^"]*"[^>]*>`)
+	if !listRegex.MatchString(html) {
+		return html
+	}
 	
 	virtualScrollJS := `
 <script>
@@ -5,7 +5,6 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"html/template"
 	"net/url"
@@ -16,8 +15,12 @@ import (
 	"time"
 
 	"github.com/alchemorsel/v3/internal/infrastructure/cache"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
 )
 
+// inlineStyleRegex matches the page's inline style blocks
+var inlineStyleRegex = regexp.MustCompile(`<style[^>]*>([\s\S]*?)</style>`)
+
 // LCPOptimizer optimizes Largest Contentful Paint performance
 type LCPOptimizer struct {
 	config              LCPConfig
@@ -33,18 +36,18 @@ type LCPOptimizer struct {
 // LCPConfig configures LCP optimization
 type LCPConfig struct {
 	EnableResourcePrioritization bool          // Enable resource prioritization
-	EnableImageOptimization     bool          // Enable image optimization
-	EnableFontOptimization      bool          // Enable font optimization
-	EnableServerOptimization    bool          // Enable server-side optimizations
-	TargetLCP                   time.Duration // Target LCP time (2.5s for "Good")
-	CriticalResourcesMaxSize    int           // Max size for critical resources
-	PreloadCriticalResources    bool          // Preload critical resources
-	EnableHeroPrioritization    bool          // Prioritize hero content
-	CDNEnabled                  bool          // Use CDN for static assets
-	EnableBundleOptimization    bool          // Enable 14KB bundle optimization
-	MaxBundleSize              int           // Maximum initial bundle size (14KB)
-	EnableRedisCache           bool          // Enable Redis caching for optimizations
-	CacheTTL                   time.Duration // Cache TTL for optimization results
+	EnableImageOptimization      bool          // Enable image optimization
+	EnableFontOptimization       bool          // Enable font optimization
+	EnableServerOptimization     bool          // Enable server-side optimizations
+	TargetLCP                    time.Duration // Target LCP time (2.5s for "Good")
+	CriticalResourcesMaxSize     int           // Max size for critical resources
+	PreloadCriticalResources     bool          // Preload critical resources
+	EnableHeroPrioritization     bool          // Prioritize hero content
+	CDNEnabled                   bool          // Use CDN for static assets
+	EnableBundleOptimization     bool          // Enable 14KB bundle optimization
+	MaxBundleSize                int           // Maximum initial bundle size (14KB)
+	EnableRedisCache             bool          // Enable Redis caching for optimizations
+	CacheTTL                     time.Duration // Cache TTL for optimization results
 }
 
 // ResourcePrioritizer manages resource loading priorities
@@ -66,41 +69,41 @@ type LCPImageOptimizer struct {
 
 // LCPFontOptimizer optimizes fonts for LCP
 type LCPFontOptimizer struct {
-	criticalFonts     []CriticalFont
-	preloadFonts      []string
-	fontDisplayStyle  string
-	fontSwapStrategy  string
-	fallbackFonts     map[string]string
+	criticalFonts    []LCPCriticalFont
+	preloadFonts     []string
+	fontDisplayStyle string
+	fontSwapStrategy string
+	fallbackFonts    map[string]string
 }
 
 // ServerOptimizer handles server-side LCP optimizations
 type ServerOptimizer struct {
-	enableGzip          bool
-	enableBrotli        bool
-	enableHTTP2Push     bool
-	maxResourceSize     int
-	compressionLevel    int
-	cacheStrategies     map[string]CacheStrategy
+	enableGzip       bool
+	enableBrotli     bool
+	enableHTTP2Push  bool
+	maxResourceSize  int
+	compressionLevel int
+	cacheStrategies  map[string]CacheStrategy
 }
 
 // BundleOptimizer handles 14KB critical bundle optimization
 type BundleOptimizer struct {
-	criticalCSS         string
-	criticalJS          string
-	inlineThreshold     int
-	bundleCache         map[string]CachedBundle
-	resourceAnalyzer    *ResourceAnalyzer
-	treeShaker          *TreeShaker
+	criticalCSS      string
+	criticalJS       string
+	inlineThreshold  int
+	bundleCache      map[string]CachedBundle
+	resourceAnalyzer *ResourceAnalyzer
+	treeShaker       *TreeShaker
 }
 
 // CachedBundle represents a cached optimization bundle
 type CachedBundle struct {
-	Content     string            `json:"content"`
-	Resources   []string          `json:"resources"`
-	Size        int               `json:"size"`
-	Hash        string            `json:"hash"`
-	Timestamp   time.Time         `json:"timestamp"`
-	Metadata    map[string]string `json:"metadata"`
+	Content   string            `json:"content"`
+	Resources []string          `json:"resources"`
+	Size      int               `json:"size"`
+	Hash      string            `json:"hash"`
+	Timestamp time.Time         `json:"timestamp"`
+	Metadata  map[string]string `json:"metadata"`
 }
 
 // ResourceAnalyzer analyzes resource dependencies
@@ -112,21 +115,21 @@ type ResourceAnalyzer struct {
 
 // TreeShaker removes unused code from bundles
 type TreeShaker struct {
-	usedSelectors   map[string]bool
-	usedFunctions   map[string]bool
-	deadCodeRules   []string
+	usedSelectors map[string]bool
+	usedFunctions map[string]bool
+	deadCodeRules []string
 }
 
 // CriticalResource represents a critical resource for LCP
 type CriticalResource struct {
-	URL          string
-	Type         string // css, js, image, font
-	Priority     int    // 1-10, higher = more critical
-	Size         int
-	LoadTime     time.Duration
-	IsAboveFold  bool
-	IsHeroImage  bool
-	MediaQuery   string
+	URL         string
+	Type        string // css, js, image, font
+	Priority    int    // 1-10, higher = more critical
+	Size        int
+	LoadTime    time.Duration
+	IsAboveFold bool
+	IsHeroImage bool
+	MediaQuery  string
 }
 
 // PreloadHint represents a resource preload hint
@@ -140,27 +143,27 @@ type PreloadHint struct {
 
 // PriorityHint represents a resource priority hint
 type PriorityHint struct {
-	URL      string
-	Priority string // high, low, auto
+	URL           string
+	Priority      string // high, low, auto
 	FetchPriority string
 }
 
 // DeferredResource represents a resource that can be deferred
 type DeferredResource struct {
-	URL         string
-	Type        string
-	DeferUntil  string // load, interaction, visible
-	Importance  string // low, high
+	URL        string
+	Type       string
+	DeferUntil string // load, interaction, visible
+	Importance string // low, high
 }
 
 // ImageSize represents an image size variant
 type ImageSize struct {
-	Width     int
-	Height    int
-	Format    string
-	Quality   int
-	URL       string
-	IsHero    bool
+	Width   int
+	Height  int
+	Format  string
+	Quality int
+	URL     string
+	IsHero  bool
 }
 
 // ResponsiveSize represents responsive image sizing
@@ -170,23 +173,23 @@ type ResponsiveSize struct {
 	Density    string
 }
 
-// CriticalFont represents a font critical for LCP
-type CriticalFont struct {
-	Family      string
-	Weight      string
-	Style       string
-	URL         string
-	Format      string
-	IsHeroFont  bool
-	LoadTime    time.Duration
+// LCPCriticalFont represents a font critical for LCP
+type LCPCriticalFont struct {
+	Family     string
+	Weight     string
+	Style      string
+	URL        string
+	Format     string
+	IsHeroFont bool
+	LoadTime   time.Duration
 }
 
 // CacheStrategy represents a caching strategy
 type CacheStrategy struct {
-	Type        string        // browser, cdn, server
-	Duration    time.Duration
-	Conditions  []string
-	Priority    int
+	Type       string // browser, cdn, server
+	Duration   time.Duration
+	Conditions []string
+	Priority   int
 }
 
 // LCPMetrics tracks LCP optimization performance
@@ -207,18 +210,18 @@ type LCPMetrics struct {
 func DefaultLCPConfig() LCPConfig {
 	return LCPConfig{
 		EnableResourcePrioritization: true,
-		EnableImageOptimization:     true,
-		EnableFontOptimization:      true,
-		EnableServerOptimization:    true,
-		TargetLCP:                   2500 * time.Millisecond, // 2.5s Google "Good" threshold
-		CriticalResourcesMaxSize:    100 * 1024,             // 100KB limit for critical resources
-		PreloadCriticalResources:    true,
-		EnableHeroPrioritization:    true,
-		CDNEnabled:                  true,
-		EnableBundleOptimization:    true,
-		MaxBundleSize:              14 * 1024,               // 14KB initial bundle
-		EnableRedisCache:           true,
-		CacheTTL:                   1 * time.Hour,           // Cache optimizations for 1 hour
+		EnableImageOptimization:      true,
+		EnableFontOptimization:       true,
+		EnableServerOptimization:     true,
+		TargetLCP:                    2500 * time.Millisecond, // 2.5s Google "Good" threshold
+		CriticalResourcesMaxSize:     100 * 1024,              // 100KB limit for critical resources
+		PreloadCriticalResources:     true,
+		EnableHeroPrioritization:     true,
+		CDNEnabled:                   true,
+		EnableBundleOptimization:     true,
+		MaxBundleSize:                14 * 1024, // 14KB initial bundle
+		EnableRedisCache:             true,
+		CacheTTL:                     1 * time.Hour, // Cache optimizations for 1 hour
 	}
 }
 
@@ -243,22 +246,22 @@ func NewLCPOptimizer(config LCPConfig, cacheClient *cache.RedisClient) *LCPOptim
 			{MediaQuery: "(min-width: 768px)", Size: "800px", Density: "1x"},
 			{MediaQuery: "(max-width: 767px)", Size: "400px", Density: "1x"},
 		},
-		formatPreferences: []string{"avif", "webp", "jpg", "png"},
+		formatPreferences: outbound.ImageFormatPreferences,
 		lazyLoadThreshold: 600, // pixels below fold
 		placeholderType:   "blur",
 	}
 
 	fontOptimizer := &LCPFontOptimizer{
-		criticalFonts: []CriticalFont{},
-		preloadFonts:  []string{},
+		criticalFonts:    []LCPCriticalFont{},
+		preloadFonts:     []string{},
 		fontDisplayStyle: "swap",
 		fontSwapStrategy: "immediate",
 		fallbackFonts: map[string]string{
-			"Inter":          "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
-			"Roboto":         "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
-			"Open Sans":      "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
+			"Inter":            "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
+			"Roboto":           "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
+			"Open Sans":        "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
 			"Playfair Display": "Georgia, serif",
-			"Source Sans Pro": "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
+			"Source Sans Pro":  "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
 		},
 	}
 
@@ -270,29 +273,29 @@ func NewLCPOptimizer(config LCPConfig, cacheClient *cache.RedisClient) *LCPOptim
 		compressionLevel: 6,
 		cacheStrategies: map[string]CacheStrategy{
 			"images": {
-				Type:     "browser",
-				Duration: 7 * 24 * time.Hour, // 1 week
+				Type:       "browser",
+				Duration:   7 * 24 * time.Hour, // 1 week
 				Conditions: []string{"public", "immutable"},
-				Priority: 1,
+				Priority:   1,
 			},
 			"fonts": {
-				Type:     "browser",
-				Duration: 30 * 24 * time.Hour, // 30 days
+				Type:       "browser",
+				Duration:   30 * 24 * time.Hour, // 30 days
 				Conditions: []string{"public", "immutable"},
-				Priority: 1,
+				Priority:   1,
 			},
 			"css": {
-				Type:     "browser",
-				Duration: 24 * time.Hour, // 1 day
+				Type:       "browser",
+				Duration:   24 * time.Hour, // 1 day
 				Conditions: []string{"public"},
-				Priority: 2,
+				Priority:   2,
 			},
 		},
 	}
 
 	bundleOptimizer := &BundleOptimizer{
-		inlineThreshold:  config.MaxBundleSize,
-		bundleCache:      make(map[string]CachedBundle),
+		inlineThreshold: config.MaxBundleSize,
+		bundleCache:     make(map[string]CachedBundle),
 		resourceAnalyzer: &ResourceAnalyzer{
 			dependencyGraph: make(map[string][]string),
 			usageStats:      make(map[string]int),
@@ -385,13 +388,13 @@ func (lcp *LCPOptimizer) getCachedOptimization(ctx context.Context, html string)
 	if err != nil {
 		return "", err
 	}
-	return result, nil
+	return string(result), nil
 }
 
 // cacheOptimization stores optimization result in cache
 func (lcp *LCPOptimizer) cacheOptimization(ctx context.Context, original, optimized string) {
 	cacheKey := lcp.generateCacheKey(original)
-	lcp.cacheClient.Set(ctx, "lcp:opt:"+cacheKey, optimized, lcp.config.CacheTTL)
+	lcp.cacheClient.Set(ctx, "lcp:opt:"+cacheKey, []byte(optimized), lcp.config.CacheTTL)
 }
 
 // generateCacheKey generates a cache key for HTML content
@@ -402,14 +405,12 @@ func (lcp *LCPOptimizer) generateCacheKey(html string) string {
 
 // optimizeCriticalBundle creates a 14KB critical resource bundle
 func (lcp *LCPOptimizer) optimizeCriticalBundle(html string, lcpElement *LCPElement) (string, error) {
-	// Analyze critical resources
-	criticalResources := lcp.identifyCriticalResources(html, lcpElement)
-
-	// Extract critical CSS
+	// Extract critical CSS; the page's inline styles move into the bundle
 	criticalCSS, err := lcp.extractCriticalCSS(html, lcpElement)
 	if err != nil {
 		return html, err
 	}
+	html = inlineStyleRegex.ReplaceAllString(html, "")
 
 	// Extract critical JavaScript
 	criticalJS, err := lcp.extractCriticalJS(html, lcpElement)
@@ -452,8 +453,7 @@ img { max-width: 100%; height: auto; }
 `)
 
 	// Extract inline styles
-	styleRegex := regexp.MustCompile(`<style[^>]*>([\s\S]*?)</style>`)
-	styleMatches := styleRegex.FindAllStringSubmatch(html, -1)
+	styleMatches := inlineStyleRegex.FindAllStringSubmatch(html, -1)
 	for _, match := range styleMatches {
 		if len(match) > 1 {
 			criticalCSS.WriteString(match[1])
@@ -496,14 +496,8 @@ window.lcpOptimization = {
 window.lcpOptimization.measureLCP();
 `)
 
-	// Extract critical inline scripts
-	scriptRegex := regexp.MustCompile(`<script[^>]*>([\s\S]*?)</script>`)
-	scriptMatches := scriptRegex.FindAllStringSubmatch(html, 2) // Limit to first 2
-	for _, match := range scriptMatches {
-		if len(match) > 1 && !strings.Contains(match[1], "async") && !strings.Contains(match[1], "defer") {
-			criticalJS.WriteString(match[1])
-		}
-	}
+	// The page's own scripts stay where they are: copied here they would
+	// run twice, and before the elements they expect
 
 	return criticalJS.String(), nil
 }
@@ -547,553 +541,7 @@ func (lcp *LCPOptimizer) compressCSS(css string) string {
 // compressJS compresses JavaScript by removing whitespace and comments
 func (lcp *LCPOptimizer) compressJS(js string) string {
 	// Remove single-line comments
-	singleCommentRegex := regexp.MustCompile(`//.*// Package performance provides Largest Contentful Paint (LCP) optimization
-package performance
-
-import (
-	"context"
-	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
-	"fmt"
-	"html/template"
-	"net/url"
-	"regexp"
-	"sort"
-	"strconv"
-	"strings"
-	"time"
-
-	"github.com/alchemorsel/v3/internal/infrastructure/cache"
-)
-
-// LCPOptimizer optimizes Largest Contentful Paint performance
-type LCPOptimizer struct {
-	config              LCPConfig
-	resourcePrioritizer *ResourcePrioritizer
-	imageOptimizer      *LCPImageOptimizer
-	fontOptimizer       *LCPFontOptimizer
-	serverOptimizer     *ServerOptimizer
-	cacheClient         *cache.RedisClient
-	bundleOptimizer     *BundleOptimizer
-	performanceMetrics  LCPMetrics
-}
-
-// LCPConfig configures LCP optimization
-type LCPConfig struct {
-	EnableResourcePrioritization bool          // Enable resource prioritization
-	EnableImageOptimization     bool          // Enable image optimization
-	EnableFontOptimization      bool          // Enable font optimization
-	EnableServerOptimization    bool          // Enable server-side optimizations
-	TargetLCP                   time.Duration // Target LCP time (2.5s for "Good")
-	CriticalResourcesMaxSize    int           // Max size for critical resources
-	PreloadCriticalResources    bool          // Preload critical resources
-	EnableHeroPrioritization    bool          // Prioritize hero content
-	CDNEnabled                  bool          // Use CDN for static assets
-	EnableBundleOptimization    bool          // Enable 14KB bundle optimization
-	MaxBundleSize              int           // Maximum initial bundle size (14KB)
-	EnableRedisCache           bool          // Enable Redis caching for optimizations
-	CacheTTL                   time.Duration // Cache TTL for optimization results
-}
-
-// ResourcePrioritizer manages resource loading priorities
-type ResourcePrioritizer struct {
-	criticalResources []CriticalResource
-	preloadHints      []PreloadHint
-	priorityHints     []PriorityHint
-	deferredResources []DeferredResource
-}
-
-// LCPImageOptimizer optimizes images for LCP
-type LCPImageOptimizer struct {
-	heroImageSizes    []ImageSize
-	responsiveSizes   []ResponsiveSize
-	formatPreferences []string
-	lazyLoadThreshold int
-	placeholderType   string
-}
-
-// LCPFontOptimizer optimizes fonts for LCP
-type LCPFontOptimizer struct {
-	criticalFonts     []CriticalFont
-	preloadFonts      []string
-	fontDisplayStyle  string
-	fontSwapStrategy  string
-	fallbackFonts     map[string]string
-}
-
-// ServerOptimizer handles server-side LCP optimizations
-type ServerOptimizer struct {
-	enableGzip          bool
-	enableBrotli        bool
-	enableHTTP2Push     bool
-	maxResourceSize     int
-	compressionLevel    int
-	cacheStrategies     map[string]CacheStrategy
-}
-
-// BundleOptimizer handles 14KB critical bundle optimization
-type BundleOptimizer struct {
-	criticalCSS         string
-	criticalJS          string
-	inlineThreshold     int
-	bundleCache         map[string]CachedBundle
-	resourceAnalyzer    *ResourceAnalyzer
-	treeShaker          *TreeShaker
-}
-
-// CachedBundle represents a cached optimization bundle
-type CachedBundle struct {
-	Content     string            `json:"content"`
-	Resources   []string          `json:"resources"`
-	Size        int               `json:"size"`
-	Hash        string            `json:"hash"`
-	Timestamp   time.Time         `json:"timestamp"`
-	Metadata    map[string]string `json:"metadata"`
-}
-
-// ResourceAnalyzer analyzes resource dependencies
-type ResourceAnalyzer struct {
-	dependencyGraph map[string][]string
-	usageStats      map[string]int
-	criticalPath    []string
-}
-
-// TreeShaker removes unused code from bundles
-type TreeShaker struct {
-	usedSelectors   map[string]bool
-	usedFunctions   map[string]bool
-	deadCodeRules   []string
-}
-
-// CriticalResource represents a critical resource for LCP
-type CriticalResource struct {
-	URL          string
-	Type         string // css, js, image, font
-	Priority     int    // 1-10, higher = more critical
-	Size         int
-	LoadTime     time.Duration
-	IsAboveFold  bool
-	IsHeroImage  bool
-	MediaQuery   string
-}
-
-// PreloadHint represents a resource preload hint
-type PreloadHint struct {
-	URL         string
-	Type        string
-	CrossOrigin string
-	MediaQuery  string
-	As          string
-}
-
-// PriorityHint represents a resource priority hint
-type PriorityHint struct {
-	URL      string
-	Priority string // high, low, auto
-	FetchPriority string
-}
-
-// DeferredResource represents a resource that can be deferred
-type DeferredResource struct {
-	URL         string
-	Type        string
-	DeferUntil  string // load, interaction, visible
-	Importance  string // low, high
-}
-
-// ImageSize represents an image size variant
-type ImageSize struct {
-	Width     int
-	Height    int
-	Format    string
-	Quality   int
-	URL       string
-	IsHero    bool
-}
-
-// ResponsiveSize represents responsive image sizing
-type ResponsiveSize struct {
-	MediaQuery string
-	Size       string
-	Density    string
-}
-
-// CriticalFont represents a font critical for LCP
-type CriticalFont struct {
-	Family      string
-	Weight      string
-	Style       string
-	URL         string
-	Format      string
-	IsHeroFont  bool
-	LoadTime    time.Duration
-}
-
-// CacheStrategy represents a caching strategy
-type CacheStrategy struct {
-	Type        string        // browser, cdn, server
-	Duration    time.Duration
-	Conditions  []string
-	Priority    int
-}
-
-// LCPMetrics tracks LCP optimization performance
-type LCPMetrics struct {
-	TotalOptimizations     int
-	ResourcesOptimized     int
-	ImagesOptimized        int
-	FontsOptimized         int
-	AverageLCPImprovement  time.Duration
-	CriticalResourceCount  int
-	PreloadedResourceCount int
-	LastOptimization       time.Time
-	LCPElementType         string
-	LCPElementSelector     string
-}
-
-// DefaultLCPConfig returns sensible LCP optimization defaults
-func DefaultLCPConfig() LCPConfig {
-	return LCPConfig{
-		EnableResourcePrioritization: true,
-		EnableImageOptimization:     true,
-		EnableFontOptimization:      true,
-		EnableServerOptimization:    true,
-		TargetLCP:                   2500 * time.Millisecond, // 2.5s Google "Good" threshold
-		CriticalResourcesMaxSize:    100 * 1024,             // 100KB limit for critical resources
-		PreloadCriticalResources:    true,
-		EnableHeroPrioritization:    true,
-		CDNEnabled:                  true,
-		EnableBundleOptimization:    true,
-		MaxBundleSize:              14 * 1024,               // 14KB initial bundle
-		EnableRedisCache:           true,
-		CacheTTL:                   1 * time.Hour,           // Cache optimizations for 1 hour
-	}
-}
-
-// NewLCPOptimizer creates a new LCP optimizer
-func NewLCPOptimizer(config LCPConfig, cacheClient *cache.RedisClient) *LCPOptimizer {
-	resourcePrioritizer := &ResourcePrioritizer{
-		criticalResources: []CriticalResource{},
-		preloadHints:      []PreloadHint{},
-		priorityHints:     []PriorityHint{},
-		deferredResources: []DeferredResource{},
-	}
-
-	imageOptimizer := &LCPImageOptimizer{
-		heroImageSizes: []ImageSize{
-			{Width: 1920, Height: 1080, Format: "webp", Quality: 85, IsHero: true},
-			{Width: 1200, Height: 675, Format: "webp", Quality: 85, IsHero: true},
-			{Width: 800, Height: 450, Format: "webp", Quality: 80, IsHero: true},
-			{Width: 400, Height: 225, Format: "webp", Quality: 75, IsHero: true},
-		},
-		responsiveSizes: []ResponsiveSize{
-			{MediaQuery: "(min-width: 1200px)", Size: "1200px", Density: "1x"},
-			{MediaQuery: "(min-width: 768px)", Size: "800px", Density: "1x"},
-			{MediaQuery: "(max-width: 767px)", Size: "400px", Density: "1x"},
-		},
-		formatPreferences: []string{"avif", "webp", "jpg", "png"},
-		lazyLoadThreshold: 600, // pixels below fold
-		placeholderType:   "blur",
-	}
-
-	fontOptimizer := &LCPFontOptimizer{
-		criticalFonts: []CriticalFont{},
-		preloadFonts:  []string{},
-		fontDisplayStyle: "swap",
-		fontSwapStrategy: "immediate",
-		fallbackFonts: map[string]string{
-			"Inter":          "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
-			"Roboto":         "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
-			"Open Sans":      "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
-			"Playfair Display": "Georgia, serif",
-			"Source Sans Pro": "system-ui, -apple-system, BlinkMacSystemFont, sans-serif",
-		},
-	}
-
-	serverOptimizer := &ServerOptimizer{
-		enableGzip:       true,
-		enableBrotli:     true,
-		enableHTTP2Push:  true,
-		maxResourceSize:  1024 * 1024, // 1MB
-		compressionLevel: 6,
-		cacheStrategies: map[string]CacheStrategy{
-			"images": {
-				Type:     "browser",
-				Duration: 7 * 24 * time.Hour, // 1 week
-				Conditions: []string{"public", "immutable"},
-				Priority: 1,
-			},
-			"fonts": {
-				Type:     "browser",
-				Duration: 30 * 24 * time.Hour, // 30 days
-				Conditions: []string{"public", "immutable"},
-				Priority: 1,
-			},
-			"css": {
-				Type:     "browser",
-				Duration: 24 * time.Hour, // 1 day
-				Conditions: []string{"public"},
-				Priority: 2,
-			},
-		},
-	}
-
-	bundleOptimizer := &BundleOptimizer{
-		inlineThreshold:  config.MaxBundleSize,
-		bundleCache:      make(map[string]CachedBundle),
-		resourceAnalyzer: &ResourceAnalyzer{
-			dependencyGraph: make(map[string][]string),
-			usageStats:      make(map[string]int),
-			criticalPath:    []string{},
-		},
-		treeShaker: &TreeShaker{
-			usedSelectors: make(map[string]bool),
-			usedFunctions: make(map[string]bool),
-			deadCodeRules: []string{
-				".unused", "[data-test]", ".debug",
-			},
-		},
-	}
-
-	return &LCPOptimizer{
-		config:              config,
-		resourcePrioritizer: resourcePrioritizer,
-		imageOptimizer:      imageOptimizer,
-		fontOptimizer:       fontOptimizer,
-		serverOptimizer:     serverOptimizer,
-		cacheClient:         cacheClient,
-		bundleOptimizer:     bundleOptimizer,
-		performanceMetrics:  LCPMetrics{},
-	}
-}
-
-// OptimizeHTML optimizes HTML for LCP performance with caching support
-func (lcp *LCPOptimizer) OptimizeHTML(html string) (string, error) {
-	return lcp.OptimizeHTMLWithContext(context.Background(), html)
-}
-
-// OptimizeHTMLWithContext optimizes HTML for LCP performance with context and caching
-func (lcp *LCPOptimizer) OptimizeHTMLWithContext(ctx context.Context, html string) (string, error) {
-	// Check cache first if enabled
-	if lcp.config.EnableRedisCache && lcp.cacheClient != nil {
-		if cached, err := lcp.getCachedOptimization(ctx, html); err == nil {
-			return cached, nil
-		}
-	}
-
-	optimized := html
-
-	// Step 1: Identify LCP element
-	lcpElement, err := lcp.identifyLCPElement(html)
-	if err != nil {
-		return "", fmt.Errorf("failed to identify LCP element: %w", err)
-	}
-
-	// Step 2: Create 14KB critical bundle
-	if lcp.config.EnableBundleOptimization {
-		optimized, err = lcp.optimizeCriticalBundle(optimized, lcpElement)
-		if err != nil {
-			return "", fmt.Errorf("failed to optimize critical bundle: %w", err)
-		}
-	}
-
-	// Step 3: Prioritize critical resources
-	if lcp.config.EnableResourcePrioritization {
-		optimized = lcp.prioritizeResources(optimized, lcpElement)
-	}
-
-	// Step 4: Optimize images for LCP
-	if lcp.config.EnableImageOptimization {
-		optimized = lcp.optimizeImages(optimized, lcpElement)
-	}
-
-	// Step 5: Optimize fonts for LCP
-	if lcp.config.EnableFontOptimization {
-		optimized = lcp.optimizeFonts(optimized, lcpElement)
-	}
-
-	// Step 6: Add resource hints
-	optimized = lcp.addResourceHints(optimized)
-
-	// Cache the result if enabled
-	if lcp.config.EnableRedisCache && lcp.cacheClient != nil {
-		go lcp.cacheOptimization(context.Background(), html, optimized)
-	}
-
-	// Update metrics
-	lcp.updateMetrics(lcpElement)
-
-	return optimized, nil
-}
-
-// getCachedOptimization retrieves cached optimization result
-func (lcp *LCPOptimizer) getCachedOptimization(ctx context.Context, html string) (string, error) {
-	cacheKey := lcp.generateCacheKey(html)
-	result, err := lcp.cacheClient.Get(ctx, "lcp:opt:"+cacheKey)
-	if err != nil {
-		return "", err
-	}
-	return result, nil
-}
-
-// cacheOptimization stores optimization result in cache
-func (lcp *LCPOptimizer) cacheOptimization(ctx context.Context, original, optimized string) {
-	cacheKey := lcp.generateCacheKey(original)
-	lcp.cacheClient.Set(ctx, "lcp:opt:"+cacheKey, optimized, lcp.config.CacheTTL)
-}
-
-// generateCacheKey generates a cache key for HTML content
-func (lcp *LCPOptimizer) generateCacheKey(html string) string {
-	hash := sha256.Sum256([]byte(html))
-	return hex.EncodeToString(hash[:])
-}
-
-// optimizeCriticalBundle creates a 14KB critical resource bundle
-func (lcp *LCPOptimizer) optimizeCriticalBundle(html string, lcpElement *LCPElement) (string, error) {
-	// Analyze critical resources
-	criticalResources := lcp.identifyCriticalResources(html, lcpElement)
-
-	// Extract critical CSS
-	criticalCSS, err := lcp.extractCriticalCSS(html, lcpElement)
-	if err != nil {
-		return html, err
-	}
-
-	// Extract critical JavaScript
-	criticalJS, err := lcp.extractCriticalJS(html, lcpElement)
-	if err != nil {
-		return html, err
-	}
-
-	// Create inline bundle if under 14KB
-	bundleSize := len(criticalCSS) + len(criticalJS)
-	if bundleSize <= lcp.config.MaxBundleSize {
-		return lcp.inlineCriticalBundle(html, criticalCSS, criticalJS), nil
-	}
-
-	// Optimize to fit 14KB limit
-	optimizedCSS := lcp.compressCSS(criticalCSS)
-	optimizedJS := lcp.compressJS(criticalJS)
-
-	bundleSize = len(optimizedCSS) + len(optimizedJS)
-	if bundleSize <= lcp.config.MaxBundleSize {
-		return lcp.inlineCriticalBundle(html, optimizedCSS, optimizedJS), nil
-	}
-
-	// If still too large, tree shake
-	shakeCSS := lcp.treeShakeCSS(optimizedCSS, html)
-	shakeJS := lcp.treeShakeJS(optimizedJS, html)
-
-	return lcp.inlineCriticalBundle(html, shakeCSS, shakeJS), nil
-}
-
-// extractCriticalCSS extracts CSS critical for above-the-fold content
-func (lcp *LCPOptimizer) extractCriticalCSS(html string, lcpElement *LCPElement) (string, error) {
-	var criticalCSS strings.Builder
-
-	// Base critical styles for layout stability
-	criticalCSS.WriteString(`
-/* Critical styles for LCP optimization */
-body { margin: 0; font-display: swap; }
-img { max-width: 100%; height: auto; }
-.hero, .banner { min-height: 400px; }
-`)
-
-	// Extract inline styles
-	styleRegex := regexp.MustCompile(`<style[^>]*>([\s\S]*?)</style>`)
-	styleMatches := styleRegex.FindAllStringSubmatch(html, -1)
-	for _, match := range styleMatches {
-		if len(match) > 1 {
-			criticalCSS.WriteString(match[1])
-		}
-	}
-
-	// Extract critical external CSS (first 2 stylesheets)
-	linkRegex := regexp.MustCompile(`<link[^>]*rel="stylesheet"[^>]*href="([^"]+)"[^>]*>`)
-	linkMatches := linkRegex.FindAllStringSubmatch(html, 2) // Limit to first 2
-	for _, match := range linkMatches {
-		if len(match) > 1 {
-			// In production, this would fetch and inline the CSS
-			criticalCSS.WriteString(fmt.Sprintf("/* Critical CSS from %s */\n", match[1]))
-		}
-	}
-
-	return criticalCSS.String(), nil
-}
-
-// extractCriticalJS extracts JavaScript critical for LCP
-func (lcp *LCPOptimizer) extractCriticalJS(html string, lcpElement *LCPElement) (string, error) {
-	var criticalJS strings.Builder
-
-	// Essential performance monitoring
-	criticalJS.WriteString(`
-// Critical JS for LCP optimization
-window.lcpOptimization = {
-	start: performance.now(),
-	lcpElement: null,
-	measureLCP: function() {
-		new PerformanceObserver((list) => {
-			for (const entry of list.getEntries()) {
-				if (entry.element) {
-					this.lcpElement = entry.element;
-				}
-			}
-		}).observe({entryTypes: ['largest-contentful-paint']});
-	}
-};
-window.lcpOptimization.measureLCP();
-`)
-
-	// Extract critical inline scripts
-	scriptRegex := regexp.MustCompile(`<script[^>]*>([\s\S]*?)</script>`)
-	scriptMatches := scriptRegex.FindAllStringSubmatch(html, 2) // Limit to first 2
-	for _, match := range scriptMatches {
-		if len(match) > 1 && !strings.Contains(match[1], "async") && !strings.Contains(match[1], "defer") {
-			criticalJS.WriteString(match[1])
-		}
-	}
-
-	return criticalJS.String(), nil
-}
-
-// inlineCriticalBundle inlines critical CSS and JS into HTML
-func (lcp *LCPOptimizer) inlineCriticalBundle(html, css, js string) string {
-	optimized := html
-
-	// Inline critical CSS in head
-	criticalStyleTag := fmt.Sprintf(`<style data-critical="true">%s</style>`, css)
-	headEndRegex := regexp.MustCompile(`</head>`)
-	optimized = headEndRegex.ReplaceAllString(optimized, "    "+criticalStyleTag+"\n</head>")
-
-	// Inline critical JS after body start
-	criticalScriptTag := fmt.Sprintf(`<script data-critical="true">%s</script>`, js)
-	bodyStartRegex := regexp.MustCompile(`<body[^>]*>`)
-	optimized = bodyStartRegex.ReplaceAllStringFunc(optimized, func(match string) string {
-		return match + "\n    " + criticalScriptTag
-	})
-
-	return optimized
-}
-
-// compressCSS compresses CSS by removing whitespace and comments
-func (lcp *LCPOptimizer) compressCSS(css string) string {
-	// Remove comments
-	commentRegex := regexp.MustCompile(`/\*[\s\S]*?\*/`)
-	compressed := commentRegex.ReplaceAllString(css, "")
-
-	// Remove excess whitespace
-	whitespaceRegex := regexp.MustCompile(`\s+`)
-	compressed = whitespaceRegex.ReplaceAllString(compressed, " ")
-
-	// Remove spaces around special characters
-	spaceRegex := regexp.MustCompile(`\s*([{}:;,>+~])\s*`)
-	compressed = spaceRegex.ReplaceAllString(compressed, "$1")
-
-	return strings.TrimSpace(compressed)
-}
-
-)
+	singleCommentRegex := regexp.MustCompile(`//.*$`)
 	compressed := singleCommentRegex.ReplaceAllString(js, "")
 
 	// Remove multi-line comments
@@ -1109,10 +557,6 @@ func (lcp *LCPOptimizer) compressCSS(css string) string {
 
 // treeShakeCSS removes unused CSS selectors
 func (lcp *LCPOptimizer) treeShakeCSS(css, html string) string {
-	// Extract all CSS selectors
-	selectorRegex := regexp.MustCompile(`([.#]?[a-zA-Z][a-zA-Z0-9_-]*|\[[^\]]+\])\s*{`)
-	selectorMatches := selectorRegex.FindAllStringSubmatch(css, -1)
-
 	usedCSS := strings.Builder{}
 	cssRules := strings.Split(css, "}")
 
@@ -1127,7 +571,7 @@ func (lcp *LCPOptimizer) treeShakeCSS(css, html string) string {
 		}
 
 		selector := strings.TrimSpace(parts[0])
-		
+
 		// Keep rule if selector is found in HTML or is a critical selector
 		if lcp.isCriticalSelector(selector) || strings.Contains(html, selector) {
 			usedCSS.WriteString(rule + "}")
@@ -1192,6 +636,13 @@ func (lcp *LCPOptimizer) identifyLCPElement(html string) (*LCPElement, error) {
 	// Hero images
 	heroImageRegex := regexp.MustCompile(`<img[^>]*?(?:class="[^"]*(?:hero|banner|featured)[^"]*"|id="[^"]*(?:hero|banner|featured)[^"]*")[^>]*?>`)
 	heroImages := heroImageRegex.FindAllString(html, -1)
+
+	// Images that open a hero, banner or featured container
+	heroContainerRegex := regexp.MustCompile(`<(?:div|section|header|figure)[^>]*?class="[^"]*(?:hero|banner|featured)[^"]*"[^>]*>\s*(<img[^>]*?>)`)
+	for _, match := range heroContainerRegex.FindAllStringSubmatch(html, -1) {
+		heroImages = append(heroImages, match[1])
+	}
+
 	for i, img := range heroImages {
 		candidates = append(candidates, LCPCandidate{
 			Element:  img,
@@ -1443,15 +894,15 @@ func (lcp *LCPOptimizer) generatePreloadHint(resource CriticalResource) PreloadH
 // insertPreloadHint inserts a preload hint into the HTML head
 func (lcp *LCPOptimizer) insertPreloadHint(html string, hint PreloadHint) string {
 	preloadTag := fmt.Sprintf(`<link rel="preload" href="%s" as="%s"`, hint.URL, hint.As)
-	
+
 	if hint.CrossOrigin != "" {
 		preloadTag += fmt.Sprintf(` crossorigin="%s"`, hint.CrossOrigin)
 	}
-	
+
 	if hint.MediaQuery != "" {
 		preloadTag += fmt.Sprintf(` media="%s"`, hint.MediaQuery)
 	}
-	
+
 	preloadTag += ">"
 
 	// Insert before closing head tag
@@ -1544,10 +995,14 @@ func (lcp *LCPOptimizer) optimizeImages(html string, lcpElement *LCPElement) str
 	return optimized
 }
 
-// optimizeLCPImage optimizes the specific LCP image
+// optimizeLCPImage optimizes the specific LCP image, found by its src since
+// earlier steps may have added attributes to the tag
 func (lcp *LCPOptimizer) optimizeLCPImage(html string, lcpElement *LCPElement) string {
 	imgRegex := regexp.MustCompile(regexp.QuoteMeta(lcpElement.Element))
-	
+	if srcMatch := regexp.MustCompile(`src="([^"]+)"`).FindStringSubmatch(lcpElement.Element); len(srcMatch) > 1 {
+		imgRegex = regexp.MustCompile(`<img[^>]*?src="` + regexp.QuoteMeta(srcMatch[1]) + `"[^>]*>`)
+	}
+
 	return imgRegex.ReplaceAllStringFunc(html, func(match string) string {
 		optimized := match
 
@@ -1580,17 +1035,17 @@ func (lcp *LCPOptimizer) addResponsiveImages(imgTag string, isLCP bool) string {
 	}
 
 	originalSrc := srcMatch[1]
-	
+
 	// Generate srcset for responsive images
 	var srcsetParts []string
 	var sizesParts []string
-	
+
 	for _, size := range lcp.imageOptimizer.heroImageSizes {
 		// Generate optimized URL (in practice, this would call an image service)
 		optimizedURL := lcp.generateOptimizedImageURL(originalSrc, size)
 		srcsetParts = append(srcsetParts, fmt.Sprintf("%s %dw", optimizedURL, size.Width))
 	}
-	
+
 	for _, respSize := range lcp.imageOptimizer.responsiveSizes {
 		sizesParts = append(sizesParts, fmt.Sprintf("%s %s", respSize.MediaQuery, respSize.Size))
 	}
@@ -1622,7 +1077,7 @@ func (lcp *LCPOptimizer) generateOptimizedImageURL(originalURL string, size Imag
 	query.Set("h", strconv.Itoa(size.Height))
 	query.Set("f", size.Format)
 	query.Set("q", strconv.Itoa(size.Quality))
-	
+
 	parsedURL.RawQuery = query.Encode()
 	return parsedURL.String()
 }
@@ -1630,7 +1085,7 @@ func (lcp *LCPOptimizer) generateOptimizedImageURL(originalURL string, size Imag
 // optimizeRegularImages optimizes non-LCP images
 func (lcp *LCPOptimizer) optimizeRegularImages(html string) string {
 	imgRegex := regexp.MustCompile(`<img([^>]*?)>`)
-	
+
 	return imgRegex.ReplaceAllStringFunc(html, func(match string) string {
 		optimized := match
 
@@ -1687,7 +1142,7 @@ func (lcp *LCPOptimizer) preloadCriticalFonts(html string) string {
 func (lcp *LCPOptimizer) optimizeFontDisplay(html string) string {
 	// Add font-display: swap to CSS
 	fontFaceRegex := regexp.MustCompile(`(@font-face\s*{[^}]*?})`)
-	
+
 	return fontFaceRegex.ReplaceAllStringFunc(html, func(match string) string {
 		if !strings.Contains(match, "font-display") {
 			return strings.Replace(match, "}", "  font-display: swap;\n}", 1)
@@ -1710,7 +1165,7 @@ func (lcp *LCPOptimizer) addResourceHints(html string) string {
 	// DNS prefetch for external domains
 	hints.WriteString(`    <link rel="dns-prefetch" href="//fonts.googleapis.com">` + "\n")
 	hints.WriteString(`    <link rel="dns-prefetch" href="//fonts.gstatic.com">` + "\n")
-	
+
 	// Preconnect for critical external resources
 	hints.WriteString(`    <link rel="preconnect" href="https://fonts.googleapis.com">` + "\n")
 	hints.WriteString(`    <link rel="preconnect" href="https://fonts.gstatic.com" crossorigin>` + "\n")
@@ -1724,7 +1179,7 @@ func (lcp *LCPOptimizer) addResourceHints(html string) string {
 func (lcp *LCPOptimizer) updateMetrics(lcpElement *LCPElement) {
 	lcp.performanceMetrics.TotalOptimizations++
 	lcp.performanceMetrics.LastOptimization = time.Now()
-	
+
 	if lcpElement != nil {
 		lcp.performanceMetrics.LCPElementType = lcpElement.Type
 		lcp.performanceMetrics.LCPElementSelector = lcpElement.Selector
@@ -1741,7 +1196,7 @@ func (lcp *LCPOptimizer) updateMetrics(lcpElement *LCPElement) {
 // GenerateReport generates an LCP optimization report
 func (lcp *LCPOptimizer) GenerateReport() string {
 	metrics := lcp.performanceMetrics
-	
+
 	return fmt.Sprintf(`=== LCP Optimization Report ===
 Last Optimization: %s
 Total Optimizations: %d
@@ -1806,26 +1261,26 @@ func (lcp *LCPOptimizer) TemplateFunction() template.FuncMap {
 			// Generate optimized hero image HTML
 			sizes := lcp.imageOptimizer.heroImageSizes
 			var srcsetParts []string
-			
+
 			for _, size := range sizes {
 				optimizedURL := lcp.generateOptimizedImageURL(src, size)
 				srcsetParts = append(srcsetParts, fmt.Sprintf("%s %dw", optimizedURL, size.Width))
 			}
-			
+
 			srcset := strings.Join(srcsetParts, ", ")
 			sizesAttr := "(min-width: 1200px) 1200px, (min-width: 768px) 800px, 400px"
-			
+
 			return template.HTML(fmt.Sprintf(
 				`<img src="%s" alt="%s" width="%d" height="%d" srcset="%s" sizes="%s" loading="eager" fetchpriority="high" decoding="async">`,
 				src, alt, width, height, srcset, sizesAttr))
 		},
 		"criticalFont": func(family, weight string) template.HTML {
 			// Generate critical font preload
-			fontURL := fmt.Sprintf("/static/fonts/%s-%s.woff2", 
+			fontURL := fmt.Sprintf("/static/fonts/%s-%s.woff2",
 				strings.ToLower(strings.ReplaceAll(family, " ", "-")), weight)
 			return template.HTML(fmt.Sprintf(
 				`<link rel="preload" href="%s" as="font" type="font/woff2" crossorigin="anonymous">`,
 				fontURL))
 		},
 	}
-}
\ No newline at end of file
+}
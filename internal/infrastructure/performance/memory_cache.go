@@ -2,9 +2,12 @@ package performance
 
 import (
 	"container/list"
+	"context"
 	"strings"
 	"sync"
 	"time"
+
+	"go.uber.org/zap"
 )
 
 // MemoryCache implements an in-memory LRU cache with TTL support
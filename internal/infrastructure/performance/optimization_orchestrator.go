@@ -21,7 +21,7 @@ type OptimizationOrchestrator struct {
 	resourceBundler        *ResourceBundler
 	performanceMonitor     *PerformanceMonitor
 	buildCache             map[string]BuildCacheEntry
-	optimizationPipeline   []OptimizationStage
+	optimizationPipeline   []BuildStage
 	mutex                  sync.RWMutex
 	lastBuildTime          time.Time
 	buildResults           BuildResults
@@ -50,8 +50,8 @@ type BuildCacheEntry struct {
 	FilePath     string
 }
 
-// OptimizationStage represents a stage in the optimization pipeline
-type OptimizationStage struct {
+// BuildStage represents a stage in the build optimization pipeline
+type BuildStage struct {
 	Name        string
 	Function    func(context.Context) error
 	Parallel    bool
@@ -143,7 +143,7 @@ func NewOptimizationOrchestrator(config OrchestratorConfig) (*OptimizationOrches
 
 // initializePipeline sets up the optimization pipeline
 func (oo *OptimizationOrchestrator) initializePipeline() {
-	oo.optimizationPipeline = []OptimizationStage{
+	oo.optimizationPipeline = []BuildStage{
 		{
 			Name:     "scan_assets",
 			Function: oo.scanAssets,
@@ -261,7 +261,7 @@ func (oo *OptimizationOrchestrator) executeSequentialPipeline(ctx context.Contex
 
 // executeParallelPipeline executes stages with parallelization where possible
 func (oo *OptimizationOrchestrator) executeParallelPipeline(ctx context.Context) error {
-	var parallelStages []OptimizationStage
+	var parallelStages []BuildStage
 	
 	for _, stage := range oo.optimizationPipeline {
 		if stage.Parallel && len(parallelStages) == 0 {
@@ -299,13 +299,13 @@ func (oo *OptimizationOrchestrator) executeParallelPipeline(ctx context.Context)
 }
 
 // executeParallelStages executes multiple stages in parallel
-func (oo *OptimizationOrchestrator) executeParallelStages(ctx context.Context, stages []OptimizationStage) error {
+func (oo *OptimizationOrchestrator) executeParallelStages(ctx context.Context, stages []BuildStage) error {
 	var wg sync.WaitGroup
 	errors := make(chan error, len(stages))
 	
 	for _, stage := range stages {
 		wg.Add(1)
-		go func(s OptimizationStage) {
+		go func(s BuildStage) {
 			defer wg.Done()
 			if err := oo.executeStage(ctx, s); err != nil {
 				if s.Critical {
@@ -332,7 +332,7 @@ func (oo *OptimizationOrchestrator) executeParallelStages(ctx context.Context, s
 }
 
 // executeStage executes a single optimization stage with retries
-func (oo *OptimizationOrchestrator) executeStage(ctx context.Context, stage OptimizationStage) error {
+func (oo *OptimizationOrchestrator) executeStage(ctx context.Context, stage BuildStage) error {
 	stageCtx, cancel := context.WithTimeout(ctx, stage.Timeout)
 	defer cancel()
 	
@@ -17,7 +17,7 @@ type PerformanceMonitor struct {
 	config              MonitorConfig
 	metrics             *PerformanceMetrics
 	measurements        []Measurement
-	alerts              []Alert
+	alerts              []MonitorAlert
 	thresholds          Thresholds
 	mutex               sync.RWMutex
 	firstPacketOptimizer *FirstPacketOptimizer
@@ -150,8 +150,8 @@ type Measurement struct {
 	ConnectionType string
 }
 
-// Alert represents a performance alert
-type Alert struct {
+// MonitorAlert represents an alert raised by the performance monitor
+type MonitorAlert struct {
 	ID          string
 	Type        string
 	Severity    string
@@ -353,29 +353,11 @@ func (pm *PerformanceMonitor) recalculatePercentiles(dist *MetricDistribution) {
 	dist.P99 = percentile(values, 0.99)
 }
 
-// percentile calculates the percentile value from sorted slice
-func percentile(sortedValues []float64, p float64) float64 {
-	if len(sortedValues) == 0 {
-		return 0
-	}
-	
-	index := p * float64(len(sortedValues)-1)
-	lower := int(index)
-	upper := lower + 1
-	
-	if upper >= len(sortedValues) {
-		return sortedValues[len(sortedValues)-1]
-	}
-	
-	weight := index - float64(lower)
-	return sortedValues[lower]*(1-weight) + sortedValues[upper]*weight
-}
-
 // checkAlerts checks if any thresholds are exceeded
 func (pm *PerformanceMonitor) checkAlerts(measurement Measurement) {
 	// First packet size violation
 	if measurement.FirstPacketSize > pm.thresholds.FirstPacketSize {
-		alert := Alert{
+		alert := MonitorAlert{
 			ID:        fmt.Sprintf("first-packet-%d", time.Now().UnixNano()),
 			Type:      "first_packet_violation",
 			Severity:  "warning",
@@ -390,7 +372,7 @@ func (pm *PerformanceMonitor) checkAlerts(measurement Measurement) {
 
 	// Compliance rate violation
 	if pm.metrics.FirstPacketCompliance.ComplianceRate < pm.thresholds.ComplianceRate {
-		alert := Alert{
+		alert := MonitorAlert{
 			ID:        fmt.Sprintf("compliance-rate-%d", time.Now().UnixNano()),
 			Type:      "compliance_rate_low",
 			Severity:  "critical",
@@ -406,7 +388,7 @@ func (pm *PerformanceMonitor) checkAlerts(measurement Measurement) {
 	// Core Web Vitals violations
 	if vitals := measurement.CoreWebVitals; len(vitals) > 0 {
 		if fcp, exists := vitals["FCP"]; exists && fcp > pm.thresholds.FirstContentfulPaint {
-			alert := Alert{
+			alert := MonitorAlert{
 				ID:        fmt.Sprintf("fcp-%d", time.Now().UnixNano()),
 				Type:      "core_web_vitals",
 				Severity:  "warning",
@@ -577,7 +559,7 @@ func (pm *PerformanceMonitor) GetMetrics() *PerformanceMetrics {
 }
 
 // GetAlerts returns current alerts
-func (pm *PerformanceMonitor) GetAlerts() []Alert {
+func (pm *PerformanceMonitor) GetAlerts() []MonitorAlert {
 	pm.mutex.RLock()
 	defer pm.mutex.RUnlock()
 	return pm.alerts
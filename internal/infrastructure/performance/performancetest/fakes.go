@@ -0,0 +1,89 @@
+// Package performancetest provides in-memory fakes of the performance
+// package's interfaces for unit tests
+package performancetest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/performance"
+)
+
+var (
+	_ performance.HTMLOptimizer = (*FakeOptimizer)(nil)
+	_ performance.Alerter       = (*RecordingAlerter)(nil)
+)
+
+// NewMeasurementStore returns an empty in-memory measurement store
+func NewMeasurementStore() *performance.MemoryMeasurementStore {
+	return performance.NewMemoryMeasurementStore()
+}
+
+// FakeOptimizer records the pages it is given and returns them with Marker
+// appended, or Err if it is set
+type FakeOptimizer struct {
+	Marker string
+	Err    error
+
+	mu    sync.Mutex
+	calls []string
+}
+
+// NewFakeOptimizer creates an optimizer that appends marker to every page
+func NewFakeOptimizer(marker string) *FakeOptimizer {
+	return &FakeOptimizer{Marker: marker}
+}
+
+// OptimizeHTMLWithContext records html and returns it with Marker appended
+func (f *FakeOptimizer) OptimizeHTMLWithContext(ctx context.Context, html string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, html)
+	if f.Err != nil {
+		return "", f.Err
+	}
+	return html + f.Marker, nil
+}
+
+// Calls returns the pages the optimizer was given, in order
+func (f *FakeOptimizer) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.calls...)
+}
+
+// RecordingAlerter keeps every alert it is sent, or fails with Err if it is
+// set
+type RecordingAlerter struct {
+	Err error
+
+	mu     sync.Mutex
+	alerts []performance.Alert
+}
+
+// NewRecordingAlerter creates an alerter with no alerts recorded
+func NewRecordingAlerter() *RecordingAlerter {
+	return &RecordingAlerter{}
+}
+
+// Alert records alert
+func (a *RecordingAlerter) Alert(ctx context.Context, alert performance.Alert) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.Err != nil {
+		return a.Err
+	}
+	a.alerts = append(a.alerts, alert)
+	return nil
+}
+
+// Alerts returns the alerts sent so far, in order
+func (a *RecordingAlerter) Alerts() []performance.Alert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return append([]performance.Alert(nil), a.alerts...)
+}
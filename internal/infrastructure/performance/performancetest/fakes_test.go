@@ -0,0 +1,63 @@
+package performancetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/performance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrchestratorRunsStagesInOrder(t *testing.T) {
+	lcp, cls, inp := NewFakeOptimizer("<!--lcp-->"), NewFakeOptimizer("<!--cls-->"), NewFakeOptimizer("<!--inp-->")
+	config := performance.DefaultCWVOrchestratorConfig()
+	config.EnableRealUserMonitoring = false
+
+	orchestrator, err := performance.NewCoreWebVitalsOrchestrator(config, nil,
+		performance.WithLCPOptimizer(lcp),
+		performance.WithCLSStabilizer(cls),
+		performance.WithINPEnhancer(inp),
+	)
+	require.NoError(t, err)
+
+	optimized, err := orchestrator.OptimizeHTML("<p>page</p>")
+	require.NoError(t, err)
+	assert.Equal(t, "<p>page</p><!--lcp--><!--cls--><!--inp-->", optimized)
+	assert.Equal(t, []string{"<p>page</p><!--lcp-->"}, cls.Calls())
+
+	lcp.Err = errors.New("boom")
+	_, err = orchestrator.OptimizeHTML("<p>page</p>")
+	assert.Error(t, err, "LCP is a critical stage")
+	assert.Len(t, lcp.Calls(), 3, "one retry after the first failure")
+}
+
+func TestRUMSystemStoresFlushedMeasurementsAndAlerts(t *testing.T) {
+	store, alerter := NewMeasurementStore(), NewRecordingAlerter()
+	config := performance.DefaultRUMConfig()
+	config.BatchSize = 1
+	rum := performance.NewRUMSystem(config, nil,
+		performance.WithMeasurementStore(store),
+		performance.WithAlerter(alerter),
+		performance.WithSampler(func() bool { return true }),
+	)
+
+	now := time.Now()
+	require.NoError(t, rum.CollectMeasurement(performance.RUMMeasurement{
+		ID:              "m1",
+		SessionID:       "s1",
+		URL:             "/recipes/1",
+		Timestamp:       now,
+		PerformanceData: performance.PerformanceData{LCP: 9000},
+	}))
+
+	assert.Equal(t, 1, store.Len(), "a full batch is flushed to the store")
+	require.Len(t, alerter.Alerts(), 1)
+	assert.Equal(t, "LCP", alerter.Alerts()[0].Metric)
+	assert.Equal(t, "critical", alerter.Alerts()[0].Severity)
+
+	analytics, err := rum.GetAnalytics(now.Add(-time.Minute), now.Add(time.Minute), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, analytics.TotalSamples)
+}
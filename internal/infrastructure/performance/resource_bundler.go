@@ -622,7 +622,7 @@ func (rb *ResourceBundler) analyzeDependencies() error {
 // Basic CSS minification
 func (rb *ResourceBundler) minifyCSS(css string) string {
 	// Remove comments
-	css = strings.ReplaceAll(css, "/*", "").ReplaceAll(css, "*/", "")
+	css = strings.ReplaceAll(strings.ReplaceAll(css, "/*", ""), "*/", "")
 	
 	// Remove extra whitespace
 	css = strings.ReplaceAll(css, "\n", "")
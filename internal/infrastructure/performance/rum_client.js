@@ -8,6 +8,7 @@ class AlchemorselRUM {
   constructor(config = {}) {
     this.config = {
       endpoint: '/api/rum/collect',
+      recipeViewsEndpoint: '/api/v1/recipes',
       sampleRate: 0.05, // 5% sampling rate
       batchSize: 10,
       flushInterval: 30000, // 30 seconds
@@ -36,6 +37,9 @@ class AlchemorselRUM {
   }
 
   init() {
+    // Author stats need every recipe view, so this runs before sampling
+    this.setupRecipeViewTracking();
+
     if (!this.shouldSample()) {
       return;
     }
@@ -320,6 +324,52 @@ class AlchemorselRUM {
     window.addEventListener('scroll', this.throttle(trackScrollDepth, 250), { passive: true });
   }
 
+  // Records the view of a recipe page (marked with data-recipe-page and
+  // data-recipe-id) and reports scroll depth and time on page on leaving
+  setupRecipeViewTracking() {
+    const page = document.querySelector('[data-recipe-page][data-recipe-id]');
+    if (!page) return;
+
+    const base = `${this.config.recipeViewsEndpoint}/${encodeURIComponent(page.dataset.recipeId)}/views`;
+    const openedAt = Date.now();
+    let maxScrollDepth = 0;
+    let viewId = null;
+    let reported = false;
+
+    window.addEventListener('scroll', this.throttle(() => {
+      const scrollable = document.documentElement.scrollHeight - window.innerHeight;
+      const depth = scrollable > 0 ? Math.round((window.scrollY / scrollable) * 100) : 100;
+      maxScrollDepth = Math.min(100, Math.max(maxScrollDepth, depth));
+    }, 250), { passive: true });
+
+    fetch(base, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      credentials: 'include',
+      body: JSON.stringify({
+        session_id: this.sessionId,
+        referrer: document.referrer,
+        search_term: page.dataset.searchTerm || ''
+      })
+    })
+      .then(response => response.ok ? response.json() : null)
+      .then(body => { viewId = body?.data?.view_id || null; })
+      .catch(error => console.warn('Failed to record recipe view:', error));
+
+    const reportEngagement = () => {
+      if (!viewId || reported || !('sendBeacon' in navigator)) return;
+      reported = true;
+      navigator.sendBeacon(`${base}/${viewId}/engagement`, JSON.stringify({
+        scroll_depth: maxScrollDepth,
+        engaged_ms: Date.now() - openedAt
+      }));
+    };
+    window.addEventListener('pagehide', reportEngagement);
+    document.addEventListener('visibilitychange', () => {
+      if (document.visibilityState === 'hidden') reportEngagement();
+    });
+  }
+
   setupBusinessMetrics() {
     if (!this.config.enableBusinessMetrics) return;
 
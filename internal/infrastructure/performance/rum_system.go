@@ -3,14 +3,10 @@ package performance
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"sort"
-	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -25,10 +21,40 @@ type RUMSystem struct {
 	alertingSystem       *AlertingSystem
 	sessionManager       *SessionManager
 	cacheClient          *cache.RedisClient
+	store                MeasurementStore
+	alerter              Alerter
+	sample               func() bool
 	performanceMetrics   RUMMetrics
 	mutex               sync.RWMutex
 }
 
+// RUMOption overrides one of the RUM system's collaborators
+type RUMOption func(*RUMSystem)
+
+// WithMeasurementStore keeps flushed measurements in store instead of
+// memory
+func WithMeasurementStore(store MeasurementStore) RUMOption {
+	return func(rum *RUMSystem) {
+		rum.store = store
+	}
+}
+
+// WithAlerter delivers real-time alerts through alerter instead of the
+// configured alert channels
+func WithAlerter(alerter Alerter) RUMOption {
+	return func(rum *RUMSystem) {
+		rum.alerter = alerter
+	}
+}
+
+// WithSampler decides which measurements are collected, replacing the
+// configured sample rate
+func WithSampler(sample func() bool) RUMOption {
+	return func(rum *RUMSystem) {
+		rum.sample = sample
+	}
+}
+
 // RUMConfig configures Real User Monitoring
 type RUMConfig struct {
 	EnableRUM                bool              // Enable RUM collection
@@ -318,6 +344,7 @@ type RUMMetrics struct {
 	DataVolume           int64
 	ProcessingLatency    time.Duration
 	AlertsTriggered      int64
+	AlertFailures        int64
 	LastUpdate           time.Time
 }
 
@@ -363,8 +390,9 @@ func DefaultRUMConfig() RUMConfig {
 	}
 }
 
-// NewRUMSystem creates a new RUM system
-func NewRUMSystem(config RUMConfig) *RUMSystem {
+// NewRUMSystem creates a new RUM system. Flushed measurements are kept in
+// memory and alerts go to the configured channels unless opts say otherwise.
+func NewRUMSystem(config RUMConfig, cacheClient *cache.RedisClient, opts ...RUMOption) *RUMSystem {
 	dataCollector := &DataCollector{
 		measurementQueue: []RUMMeasurement{},
 		batchProcessor: &BatchProcessor{
@@ -419,14 +447,22 @@ func NewRUMSystem(config RUMConfig) *RUMSystem {
 		sessionCleanup: 5 * time.Minute,
 	}
 
-	return &RUMSystem{
+	rum := &RUMSystem{
 		config:             config,
 		dataCollector:      dataCollector,
 		analyticsProcessor: analyticsProcessor,
 		alertingSystem:     alertingSystem,
 		sessionManager:     sessionManager,
+		cacheClient:        cacheClient,
+		store:              NewMemoryMeasurementStore(),
+		alerter:            &channelAlerter{channels: alertingSystem.alertChannels},
 		performanceMetrics: RUMMetrics{},
 	}
+	rum.sample = rum.shouldSample
+	for _, opt := range opts {
+		opt(rum)
+	}
+	return rum
 }
 
 // CollectMeasurement collects a RUM measurement
@@ -436,7 +472,7 @@ func (rum *RUMSystem) CollectMeasurement(measurement RUMMeasurement) error {
 	}
 
 	// Apply sampling
-	if !rum.shouldSample() {
+	if !rum.sample() {
 		return nil
 	}
 
@@ -619,9 +655,9 @@ func (rum *RUMSystem) triggerAlert(metric string, severity string, value float64
 		SessionID: measurement.SessionID,
 	}
 
-	// Send alert through configured channels
-	for _, channel := range rum.alertingSystem.alertChannels {
-		rum.sendAlert(alert, channel)
+	if err := rum.alerter.Alert(context.Background(), alert); err != nil {
+		rum.performanceMetrics.AlertFailures++
+		return
 	}
 
 	rum.performanceMetrics.AlertsTriggered++
@@ -663,19 +699,6 @@ func (rum *RUMSystem) getThresholdValue(metric string, severity string) float64
 	}
 }
 
-// sendAlert sends an alert through the specified channel
-func (rum *RUMSystem) sendAlert(alert Alert, channel AlertChannel) {
-	// Implementation would depend on channel type (webhook, email, slack, etc.)
-	switch channel.Type {
-	case "webhook":
-		// Send webhook
-	case "email":
-		// Send email
-	case "slack":
-		// Send Slack message
-	}
-}
-
 // flushMeasurements flushes pending measurements
 func (rum *RUMSystem) flushMeasurements() error {
 	if len(rum.dataCollector.measurementQueue) == 0 {
@@ -686,33 +709,18 @@ func (rum *RUMSystem) flushMeasurements() error {
 	measurements := make([]RUMMeasurement, len(rum.dataCollector.measurementQueue))
 	copy(measurements, rum.dataCollector.measurementQueue)
 
-	// Clear queue
+	// Keep them for analytics before clearing the queue; GetAnalytics
+	// aggregates the stored measurements when it is asked
+	startTime := time.Now()
+	if err := rum.store.Append(context.Background(), measurements); err != nil {
+		rum.performanceMetrics.DroppedMeasurements += int64(len(measurements))
+		return fmt.Errorf("failed to store measurements: %w", err)
+	}
 	rum.dataCollector.measurementQueue = rum.dataCollector.measurementQueue[:0]
 
-	// Process asynchronously
-	go func() {
-		rum.processMeasurements(measurements)
-	}()
-
 	rum.performanceMetrics.ProcessedMeasurements += int64(len(measurements))
-	return nil
-}
-
-// processMeasurements processes a batch of measurements
-func (rum *RUMSystem) processMeasurements(measurements []RUMMeasurement) {
-	startTime := time.Now()
-
-	// Aggregate data
-	rum.analyticsProcessor.aggregationEngine.aggregate(measurements)
-
-	// Analyze trends
-	rum.analyticsProcessor.trendAnalyzer.analyze(measurements)
-
-	// Detect anomalies
-	rum.analyticsProcessor.anomalyDetector.detect(measurements)
-
-	// Update processing latency
 	rum.performanceMetrics.ProcessingLatency = time.Since(startTime)
+	return nil
 }
 
 // GetAnalytics returns analytics data for a time period
@@ -720,9 +728,14 @@ func (rum *RUMSystem) GetAnalytics(start, end time.Time, filters map[string]inte
 	rum.mutex.RLock()
 	defer rum.mutex.RUnlock()
 
-	// Filter measurements by time range and criteria
+	stored, err := rum.store.Range(context.Background(), start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read measurements: %w", err)
+	}
+
+	// Filter stored and still-queued measurements by time range and criteria
 	var filteredMeasurements []RUMMeasurement
-	for _, measurement := range rum.dataCollector.measurementQueue {
+	for _, measurement := range append(stored, rum.dataCollector.measurementQueue...) {
 		if measurement.Timestamp.After(start) && measurement.Timestamp.Before(end) {
 			if rum.matchesFilters(measurement, filters) {
 				filteredMeasurements = append(filteredMeasurements, measurement)
@@ -884,24 +897,6 @@ func (rum *RUMSystem) calculateMetricData(measurements []RUMMeasurement, metric
 	}
 }
 
-// percentile calculates percentile from sorted values
-func percentile(sortedValues []float64, p float64) float64 {
-	if len(sortedValues) == 0 {
-		return 0
-	}
-	
-	index := p * float64(len(sortedValues)-1)
-	lower := int(index)
-	upper := lower + 1
-	
-	if upper >= len(sortedValues) {
-		return sortedValues[len(sortedValues)-1]
-	}
-	
-	weight := index - float64(lower)
-	return sortedValues[lower]*(1-weight) + sortedValues[upper]*weight
-}
-
 // mean calculates the mean of values
 func mean(values []float64) float64 {
 	if len(values) == 0 {
@@ -1006,13 +1001,8 @@ func (rum *RUMSystem) updateSystemMetrics() {
 		}
 	}
 
-	// Calculate data volume
-	dataSize := int64(0)
-	for _, measurement := range rum.dataCollector.measurementQueue {
-		// Rough estimate of measurement size
-		dataSize += 1024 // 1KB per measurement estimate
-	}
-	rum.performanceMetrics.DataVolume = dataSize
+	// Calculate data volume, roughly 1KB per queued measurement
+	rum.performanceMetrics.DataVolume = int64(len(rum.dataCollector.measurementQueue)) * 1024
 }
 
 // HTTPHandler returns HTTP handlers for RUM APIs
@@ -0,0 +1,76 @@
+// Package performance provides theme custom properties for critical CSS
+package performance
+
+import (
+	"sort"
+	"strings"
+)
+
+// ThemePalette maps CSS custom property names (without the leading --) to values
+type ThemePalette map[string]string
+
+// LightTheme is the default palette, based on main.css with the muted and
+// status colours darkened to meet WCAG AA contrast
+var LightTheme = ThemePalette{
+	"color-bg":         "#f8fafc",
+	"color-surface":    "#ffffff",
+	"color-text":       "#2d3748",
+	"color-text-muted": "#4a5568",
+	"color-border":     "#e2e8f0",
+	"color-primary":    "#4f46e5",
+	"color-primary-fg": "#ffffff",
+	"color-danger":     "#c53030",
+	"color-success":    "#047857",
+}
+
+// DarkTheme keeps WCAG AA contrast against the dark surfaces
+var DarkTheme = ThemePalette{
+	"color-bg":         "#0f172a",
+	"color-surface":    "#1e293b",
+	"color-text":       "#e2e8f0",
+	"color-text-muted": "#94a3b8",
+	"color-border":     "#334155",
+	"color-primary":    "#818cf8",
+	"color-primary-fg": "#0f172a",
+	"color-danger":     "#f87171",
+	"color-success":    "#34d399",
+}
+
+// declarations renders the palette as sorted custom property declarations
+func (p ThemePalette) declarations() string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString("--")
+		b.WriteString(name)
+		b.WriteString(":")
+		b.WriteString(p[name])
+		b.WriteString(";")
+	}
+	return b.String()
+}
+
+// ThemeCSS returns the custom property block that is always inlined ahead of
+// the extracted critical rules. The html element's data-theme attribute pins a
+// theme; without it (anonymous visitors, or "system") prefers-color-scheme decides.
+func ThemeCSS() string {
+	dark := DarkTheme.declarations()
+
+	var b strings.Builder
+	b.WriteString(":root{color-scheme:light dark;")
+	b.WriteString(LightTheme.declarations())
+	b.WriteString("}")
+	b.WriteString(`:root[data-theme="dark"]{color-scheme:dark;`)
+	b.WriteString(dark)
+	b.WriteString("}")
+	b.WriteString(`@media (prefers-color-scheme:dark){:root:not([data-theme="light"]){`)
+	b.WriteString(dark)
+	b.WriteString("}}")
+	b.WriteString("body{background-color:var(--color-bg);color:var(--color-text)}")
+	return b.String()
+}
@@ -0,0 +1,136 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AccountDeletionRepository implements outbound.AccountDeletionRepository using GORM
+type AccountDeletionRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountDeletionRepository creates a new account deletion repository
+func NewAccountDeletionRepository(db *gorm.DB) outbound.AccountDeletionRepository {
+	return &AccountDeletionRepository{db: db}
+}
+
+// Save schedules the user's deletion, replacing a pending one
+func (r *AccountDeletionRepository) Save(ctx context.Context, deletion outbound.AccountDeletion) error {
+	model := AccountDeletionModel{
+		UserID:       deletion.UserID,
+		RequestedAt:  deletion.RequestedAt,
+		ScheduledFor: deletion.ScheduledFor,
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"requested_at", "scheduled_for"}),
+		}).
+		Create(&model).Error
+}
+
+// Find returns the user's pending deletion, or nil when there is none
+func (r *AccountDeletionRepository) Find(ctx context.Context, userID uuid.UUID) (*outbound.AccountDeletion, error) {
+	var model AccountDeletionModel
+	err := r.db.WithContext(ctx).First(&model, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	deletion := toAccountDeletion(model)
+	return &deletion, nil
+}
+
+// Delete drops the user's pending deletion
+func (r *AccountDeletionRepository) Delete(ctx context.Context, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&AccountDeletionModel{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListDue lists the deletions scheduled at or before now, soonest first
+func (r *AccountDeletionRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]outbound.AccountDeletion, error) {
+	var models []AccountDeletionModel
+	err := r.db.WithContext(ctx).
+		Where("scheduled_for <= ?", now).
+		Order("scheduled_for ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	deletions := make([]outbound.AccountDeletion, len(models))
+	for i, model := range models {
+		deletions[i] = toAccountDeletion(model)
+	}
+	return deletions, nil
+}
+
+func toAccountDeletion(model AccountDeletionModel) outbound.AccountDeletion {
+	return outbound.AccountDeletion{
+		UserID:       model.UserID,
+		RequestedAt:  model.RequestedAt,
+		ScheduledFor: model.ScheduledFor,
+	}
+}
+
+// AccountAuditRepository implements outbound.AccountAuditRepository using GORM
+type AccountAuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountAuditRepository creates a new account audit repository
+func NewAccountAuditRepository(db *gorm.DB) outbound.AccountAuditRepository {
+	return &AccountAuditRepository{db: db}
+}
+
+// Record appends an event
+func (r *AccountAuditRepository) Record(ctx context.Context, event outbound.AccountAuditEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.Detail == "" {
+		event.Detail = "{}"
+	}
+	model := AccountAuditEventModel{
+		ID:        event.ID,
+		UserID:    event.UserID,
+		ActorID:   event.ActorID,
+		Action:    event.Action,
+		Detail:    event.Detail,
+		CreatedAt: event.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// ListByUser lists the user's events, oldest first
+func (r *AccountAuditRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]outbound.AccountAuditEvent, error) {
+	var models []AccountAuditEventModel
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	events := make([]outbound.AccountAuditEvent, len(models))
+	for i, model := range models {
+		events[i] = outbound.AccountAuditEvent{
+			ID:        model.ID,
+			UserID:    model.UserID,
+			ActorID:   model.ActorID,
+			Action:    model.Action,
+			Detail:    model.Detail,
+			CreatedAt: model.CreatedAt,
+		}
+	}
+	return events, nil
+}
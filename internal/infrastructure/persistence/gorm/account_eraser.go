@@ -0,0 +1,169 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccountEraser implements outbound.AccountEraser using GORM.
+//
+// The user's recipes, collections, saved recipes, follows, pantry, cook
+// log, notifications, sync state, uploads and tokens are deleted. Their
+// comments, likes, ratings, reactions and votes stay with the user row,
+// which keeps its ID but loses its email, name, password, profile and
+// dietary settings, so other readers' threads and counts are unchanged.
+// The moderation and report logs are left as they are.
+type AccountEraser struct {
+	db *gorm.DB
+}
+
+// NewAccountEraser creates a new account eraser
+func NewAccountEraser(db *gorm.DB) outbound.AccountEraser {
+	return &AccountEraser{db: db}
+}
+
+// Erase removes the user's personal data in one transaction
+func (r *AccountEraser) Erase(ctx context.Context, userID uuid.UUID, now time.Time) (map[string]int64, error) {
+	rows := make(map[string]int64)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Subqueries are built on a fresh session so they do not pick up
+		// the conditions of the statement they are used in
+		sub := func(model interface{}, column string) *gorm.DB {
+			return tx.Session(&gorm.Session{NewDB: true}).Model(model).Select("id").Where(column+" = ?", userID)
+		}
+		steps := []struct {
+			table string
+			run   func() *gorm.DB
+		}{
+			{"collection_recipes", func() *gorm.DB {
+				return tx.Where("collection_id IN (?)", sub(&CollectionModel{}, "user_id")).Delete(&CollectionRecipeModel{})
+			}},
+			{"collections", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&CollectionModel{}) }},
+			{"shopping_list_items", func() *gorm.DB {
+				return tx.Where("list_id IN (?)", sub(&ShoppingListModel{}, "owner_id")).Delete(&ShoppingListItemModel{})
+			}},
+			{"shopping_list_changes", func() *gorm.DB {
+				return tx.Where("list_id IN (?)", sub(&ShoppingListModel{}, "owner_id")).Delete(&ShoppingListChangeModel{})
+			}},
+			{"shopping_list_members", func() *gorm.DB {
+				return tx.Where("user_id = ? OR list_id IN (?)", userID, sub(&ShoppingListModel{}, "owner_id")).Delete(&ShoppingListMemberModel{})
+			}},
+			{"shopping_lists", func() *gorm.DB { return tx.Where("owner_id = ?", userID).Delete(&ShoppingListModel{}) }},
+			{"battle_votes", func() *gorm.DB {
+				return tx.Where("battle_id IN (?)", sub(&BattleModel{}, "chef_id")).Delete(&BattleVoteModel{})
+			}},
+			{"battles", func() *gorm.DB { return tx.Where("chef_id = ?", userID).Delete(&BattleModel{}) }},
+			{"recipes", func() *gorm.DB { return tx.Unscoped().Where("author_id = ?", userID).Delete(&RecipeModel{}) }},
+			{"favorites", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&FavoriteModel{}) }},
+			{"user_follows", func() *gorm.DB {
+				return tx.Where("follower_id = ? OR following_id = ?", userID, userID).Delete(&UserFollowModel{})
+			}},
+			{"notifications", func() *gorm.DB {
+				return tx.Where("user_id = ? OR actor_id = ?", userID, userID).Delete(&NotificationModel{})
+			}},
+			{"activities", func() *gorm.DB {
+				return tx.Where("user_id = ? OR actor_id = ?", userID, userID).Delete(&ActivityModel{})
+			}},
+			{"ai_requests", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&AIRequestModel{}) }},
+			{"recipe_clips", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&RecipeClipModel{}) }},
+			{"pantry_consumption", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&PantryConsumptionModel{}) }},
+			{"cook_logs", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&CookLogModel{}) }},
+			{"pantry_items", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&PantryItemModel{}) }},
+			{"sync_records", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&SyncRecordModel{}) }},
+			{"sync_counters", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&SyncCounterModel{}) }},
+			{"sync_devices", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&SyncDeviceModel{}) }},
+			{"upload_scans", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&UploadScanModel{}) }},
+			{"images", func() *gorm.DB { return tx.Where("owner_id = ?", userID).Delete(&ImageModel{}) }},
+			{"verification_claims", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&VerificationClaimModel{}) }},
+			{"comment_flags", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&CommentFlagModel{}) }},
+			{"comment_blocks", func() *gorm.DB {
+				return tx.Where("author_id = ? OR user_id = ?", userID, userID).Delete(&CommentBlockModel{})
+			}},
+			{"comment_reply_tokens", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&CommentReplyTokenModel{}) }},
+			{"password_reset_tokens", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&PasswordResetTokenModel{}) }},
+			{"recipe_views", func() *gorm.DB {
+				return tx.Model(&RecipeViewModel{}).Where("user_id = ?", userID).
+					Updates(map[string]interface{}{"user_id": nil, "session_id": nil, "ip_address": nil})
+			}},
+			{"zero_result_searches", func() *gorm.DB {
+				return tx.Model(&ZeroResultSearchModel{}).Where("user_id = ?", userID).Update("user_id", nil)
+			}},
+			{"guest_sessions", func() *gorm.DB {
+				return tx.Model(&GuestSessionModel{}).Where("claimed_by = ?", userID).Update("claimed_by", nil)
+			}},
+			// Comments stay in their threads; the address replies were
+			// mailed from goes
+			{"comments", func() *gorm.DB {
+				return tx.Model(&CommentModel{}).Where("user_id = ?", userID).Update("email_message_id", nil)
+			}},
+			{"users", func() *gorm.DB {
+				return tx.Model(&UserModel{}).Where("id = ?", userID).Updates(map[string]interface{}{
+					"email":                     fmt.Sprintf("deleted-%s@deleted.invalid", userID),
+					"name":                      "Deleted user",
+					"password_hash":             "",
+					"is_active":                 false,
+					"is_verified":               false,
+					"last_login_at":             nil,
+					"updated_at":                now,
+					"profile_first_name":        "",
+					"profile_last_name":         "",
+					"profile_avatar":            "",
+					"profile_bio":               "",
+					"profile_location":          "",
+					"profile_website":           "",
+					"profile_birthday":          nil,
+					"profile_cooking_level":     "",
+					"pref_dietary_restrictions": StringSlice{},
+					"pref_allergies":            StringSlice{},
+					"pref_preferred_cuisines":   StringSlice{},
+					"pref_disliked_ingredients": StringSlice{},
+					"pref_timezone":             "",
+					"pref_muted_notifications":  StringSlice{},
+				})
+			}},
+			{"account_deletions", func() *gorm.DB { return tx.Where("user_id = ?", userID).Delete(&AccountDeletionModel{}) }},
+		}
+
+		for _, step := range steps {
+			result := step.run()
+			if result.Error != nil {
+				return fmt.Errorf("erase %s: %w", step.table, result.Error)
+			}
+			if result.RowsAffected > 0 {
+				rows[step.table] = result.RowsAffected
+			}
+		}
+
+		// Likes, ratings and reactions are kept as they are, now attached
+		// to the anonymous user; count them for the audit log
+		kept := []struct {
+			table string
+			model interface{}
+			where string
+		}{
+			{"recipe_likes", &RecipeLikeModel{}, "user_id = ?"},
+			{"ratings", &RatingModel{}, "user_id = ?"},
+			{"comment_reactions", &CommentReactionModel{}, "user_id = ?"},
+			{"review_votes", &ReviewVoteModel{}, "voter_id = ?"},
+		}
+		for _, k := range kept {
+			var count int64
+			if err := tx.Model(k.model).Where(k.where, userID).Count(&count).Error; err != nil {
+				return fmt.Errorf("count %s: %w", k.table, err)
+			}
+			if count > 0 {
+				rows[k.table] = count
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,82 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEraseAnonymizesWhatOthersSeeAndDeletesTheRest(t *testing.T) {
+	db, otherRecipeID := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(
+		&CollectionModel{}, &CollectionRecipeModel{}, &FavoriteModel{}, &UserFollowModel{},
+		&CommentModel{}, &RecipeLikeModel{}, &NotificationModel{}, &RecipeViewModel{},
+		&AccountDeletionModel{}, &AccountAuditEventModel{},
+		&ShoppingListModel{}, &ShoppingListItemModel{}, &ShoppingListChangeModel{}, &ShoppingListMemberModel{},
+		&BattleModel{}, &BattleVoteModel{}, &ActivityModel{}, &AIRequestModel{}, &RecipeClipModel{},
+		&PantryConsumptionModel{}, &CookLogModel{}, &PantryItemModel{}, &SyncRecordModel{}, &SyncCounterModel{},
+		&SyncDeviceModel{}, &UploadScanModel{}, &ImageModel{}, &VerificationClaimModel{}, &CommentFlagModel{},
+		&CommentBlockModel{}, &CommentReplyTokenModel{}, &PasswordResetTokenModel{}, &ZeroResultSearchModel{},
+		&GuestSessionModel{}, &RatingModel{}, &CommentReactionModel{}, &ReviewVoteModel{},
+	))
+	var other UserModel
+	require.NoError(t, db.First(&other).Error)
+	ctx := context.Background()
+	now := time.Now()
+
+	leaving := UserModel{
+		ID: uuid.New(), Email: "sam@example.com", Name: "Sam Cook", PasswordHash: "hash", IsActive: true,
+		Profile:     &UserProfileModel{FirstName: "Sam", Bio: "Bakes on Sundays", Location: "Leeds"},
+		Preferences: &UserPreferencesModel{Allergies: StringSlice{"peanuts"}, Theme: "dark"},
+	}
+	require.NoError(t, db.Create(&leaving).Error)
+	own := RecipeModel{ID: uuid.New(), Title: "Lemon Bars", AuthorID: leaving.ID, Status: "published"}
+	require.NoError(t, db.Create(&own).Error)
+	comment := CommentModel{RecipeID: otherRecipeID, UserID: leaving.ID, Content: "Lovely", Status: "visible"}
+	require.NoError(t, db.Create(&comment).Error)
+	require.NoError(t, db.Create(&RecipeLikeModel{RecipeID: otherRecipeID, UserID: leaving.ID, CreatedAt: now}).Error)
+	require.NoError(t, db.Create(&FavoriteModel{UserID: leaving.ID, RecipeID: otherRecipeID, CreatedAt: now}).Error)
+	require.NoError(t, db.Create(&UserFollowModel{FollowerID: other.ID, FollowingID: leaving.ID, CreatedAt: now}).Error)
+	require.NoError(t, NewCollectionRepository(db).Create(ctx, &outbound.Collection{UserID: leaving.ID, Name: "Bakes", RecipeIDs: []uuid.UUID{otherRecipeID}}))
+	ip := "203.0.113.9"
+	require.NoError(t, db.Create(&RecipeViewModel{RecipeID: otherRecipeID, UserID: &leaving.ID, IPAddress: &ip}).Error)
+	require.NoError(t, NewAccountDeletionRepository(db).Save(ctx, outbound.AccountDeletion{UserID: leaving.ID, RequestedAt: now, ScheduledFor: now}))
+
+	rows, err := NewAccountEraser(db).Erase(ctx, leaving.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		"collection_recipes": 1, "collections": 1, "recipes": 1, "favorites": 1, "user_follows": 1,
+		"recipe_views": 1, "comments": 1, "users": 1, "account_deletions": 1, "recipe_likes": 1,
+	}, rows)
+
+	var erased UserModel
+	require.NoError(t, db.First(&erased, "id = ?", leaving.ID).Error)
+	assert.Equal(t, "Deleted user", erased.Name)
+	assert.Equal(t, "deleted-"+leaving.ID.String()+"@deleted.invalid", erased.Email)
+	assert.Empty(t, erased.PasswordHash)
+	assert.False(t, erased.IsActive)
+	require.NotNil(t, erased.Profile)
+	assert.Empty(t, erased.Profile.Bio)
+	assert.Empty(t, erased.Profile.Location)
+	assert.Empty(t, erased.Preferences.Allergies)
+
+	var kept CommentModel
+	require.NoError(t, db.First(&kept, "id = ?", comment.ID).Error)
+	assert.Equal(t, "Lovely", kept.Content, "comments stay in the thread")
+	var view RecipeViewModel
+	require.NoError(t, db.First(&view).Error)
+	assert.Nil(t, view.UserID)
+	assert.Nil(t, view.IPAddress)
+
+	var count int64
+	require.NoError(t, db.Unscoped().Model(&RecipeModel{}).Where("author_id = ?", leaving.ID).Count(&count).Error)
+	assert.Zero(t, count)
+	pending, err := NewAccountDeletionRepository(db).Find(ctx, leaving.ID)
+	require.NoError(t, err)
+	assert.Nil(t, pending)
+}
@@ -0,0 +1,170 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/sqlsafe"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AdminRepository implements outbound.AdminRepository using GORM
+type AdminRepository struct {
+	db *gorm.DB
+}
+
+// NewAdminRepository creates a new admin repository
+func NewAdminRepository(db *gorm.DB) outbound.AdminRepository {
+	return &AdminRepository{db: db}
+}
+
+// adminUserScan is a users row with its recipe count
+type adminUserScan struct {
+	ID          uuid.UUID
+	Email       string
+	Name        string
+	Role        string
+	IsActive    bool
+	IsVerified  bool
+	Recipes     int
+	CreatedAt   time.Time
+	LastLoginAt *time.Time
+}
+
+// ListUsers pages through the users matching filter, newest first
+func (r *AdminRepository) ListUsers(ctx context.Context, filter outbound.AdminUserFilter) ([]outbound.AdminUserRow, int64, error) {
+	query := r.db.WithContext(ctx).Model(&UserModel{})
+	if filter.Search != "" {
+		pattern := sqlsafe.Contains(filter.Search)
+		query = query.Where(sqlsafe.Like("users.email")+" OR "+sqlsafe.Like("users.name"), pattern, pattern)
+	}
+	if filter.Active != nil {
+		query = query.Where("users.is_active = ?", *filter.Active)
+	}
+	if filter.Role != "" {
+		query = query.Where("users.role = ?", filter.Role)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var scans []adminUserScan
+	err := query.
+		Select(`users.id, users.email, users.name, users.role, users.is_active, users.is_verified,
+			users.created_at, users.last_login_at,
+			(SELECT COUNT(*) FROM recipes WHERE recipes.author_id = users.id AND recipes.deleted_at IS NULL) AS recipes`).
+		Order("users.created_at DESC, users.id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Scan(&scans).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows := make([]outbound.AdminUserRow, len(scans))
+	for i, scan := range scans {
+		rows[i] = outbound.AdminUserRow{
+			ID:          scan.ID,
+			Email:       scan.Email,
+			Name:        scan.Name,
+			Role:        scan.Role,
+			Active:      scan.IsActive,
+			Verified:    scan.IsVerified,
+			Recipes:     scan.Recipes,
+			CreatedAt:   scan.CreatedAt,
+			LastLoginAt: scan.LastLoginAt,
+		}
+	}
+	return rows, total, nil
+}
+
+// Stats counts users, recipes and comments
+func (r *AdminRepository) Stats(ctx context.Context, since time.Time) (*outbound.AdminStatsRow, error) {
+	db := r.db.WithContext(ctx)
+	stats := &outbound.AdminStatsRow{RecipesByState: make(map[string]int64)}
+
+	var users struct {
+		Total  int64
+		Active int64
+		Admins int64
+		New    int64
+	}
+	err := db.Model(&UserModel{}).
+		Select(`COUNT(*) AS total,
+			COALESCE(SUM(CASE WHEN is_active THEN 1 ELSE 0 END), 0) AS active,
+			COALESCE(SUM(CASE WHEN role = 'admin' THEN 1 ELSE 0 END), 0) AS admins,
+			COALESCE(SUM(CASE WHEN created_at >= ? THEN 1 ELSE 0 END), 0) AS new`, since).
+		Scan(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.Users, stats.ActiveUsers, stats.Admins, stats.NewUsers = users.Total, users.Active, users.Admins, users.New
+
+	var states []struct {
+		Status string
+		Count  int64
+	}
+	if err := db.Model(&RecipeModel{}).Select("status, COUNT(*) AS count").Group("status").Scan(&states).Error; err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		stats.RecipesByState[state.Status] = state.Count
+	}
+	if err := db.Model(&RecipeModel{}).Where("ai_generated = ?", true).Count(&stats.AIRecipes).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&RecipeModel{}).Where("created_at >= ?", since).Count(&stats.NewRecipes).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&CommentModel{}).Count(&stats.Comments).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&CommentModel{}).Where("status = ?", string(comment.StatusHidden)).Count(&stats.HiddenComments).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ListAIRecipes pages through AI-generated recipes, newest first
+func (r *AdminRepository) ListAIRecipes(ctx context.Context, limit, offset int) ([]outbound.AIRecipeRow, int64, error) {
+	query := r.db.WithContext(ctx).Model(&RecipeModel{}).Where("recipes.ai_generated = ?", true)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Select by field so gorm names the columns
+	var models []RecipeModel
+	err := query.
+		Select("ID", "Title", "AuthorID", "Status", "AIModel", "AIPrompt", "CreatedAt").
+		Preload("Author", func(db *gorm.DB) *gorm.DB { return db.Select("id", "email") }).
+		Order("recipes.created_at DESC, recipes.id").
+		Limit(limit).
+		Offset(offset).
+		Find(&models).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows := make([]outbound.AIRecipeRow, len(models))
+	for i, m := range models {
+		rows[i] = outbound.AIRecipeRow{
+			ID:          m.ID,
+			Title:       m.Title,
+			AuthorID:    m.AuthorID,
+			AuthorEmail: m.Author.Email,
+			Status:      m.Status,
+			AIModel:     m.AIModel,
+			AIPrompt:    m.AIPrompt,
+			CreatedAt:   m.CreatedAt,
+		}
+	}
+	return rows, total, nil
+}
@@ -0,0 +1,59 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminRepositoryListsAndCounts(t *testing.T) {
+	db, lemonBars := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&CommentModel{}))
+	repo := NewAdminRepository(db)
+	ctx := context.Background()
+
+	grace := UserModel{ID: uuid.New(), Email: "grace@example.com", Name: "Grace", PasswordHash: "x", Role: "admin"}
+	require.NoError(t, db.Create(&grace).Error)
+	spam := UserModel{ID: uuid.New(), Email: "spam@example.com", Name: "Spammer", PasswordHash: "x"}
+	require.NoError(t, db.Create(&spam).Error)
+	require.NoError(t, db.Model(&spam).Update("is_active", false).Error)
+	require.NoError(t, db.Create(&RecipeModel{
+		ID: uuid.New(), Title: "Robot Risotto", AuthorID: grace.ID, Status: "draft",
+		AIGenerated: true, AIModel: "llama3.2:3b", AIPrompt: "a risotto for two",
+	}).Error)
+
+	suspended := false
+	rows, total, err := repo.ListUsers(ctx, outbound.AdminUserFilter{Active: &suspended, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "spam@example.com", rows[0].Email)
+
+	rows, total, err = repo.ListUsers(ctx, outbound.AdminUserFilter{Search: "ADA", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, rows, 1)
+	assert.Equal(t, 1, rows[0].Recipes)
+	assert.True(t, rows[0].Active)
+
+	stats, err := repo.Stats(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.Users)
+	assert.Equal(t, int64(2), stats.ActiveUsers)
+	assert.Equal(t, int64(1), stats.Admins)
+	assert.Equal(t, map[string]int64{"published": 1, "draft": 1}, stats.RecipesByState)
+	assert.Equal(t, int64(1), stats.AIRecipes)
+
+	ai, total, err := repo.ListAIRecipes(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, ai, 1)
+	assert.Equal(t, "grace@example.com", ai[0].AuthorEmail)
+	assert.Equal(t, "a risotto for two", ai[0].AIPrompt)
+	assert.NotEqual(t, lemonBars, ai[0].ID)
+}
@@ -0,0 +1,112 @@
+// Package gorm provides GORM-based repository implementations
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AnnouncementRepository implements the announcement queue using GORM
+type AnnouncementRepository struct {
+	db *gorm.DB
+}
+
+// NewAnnouncementRepository creates a new announcement repository
+func NewAnnouncementRepository(db *gorm.DB) outbound.AnnouncementRepository {
+	return &AnnouncementRepository{db: db}
+}
+
+// Enqueue stores pending deliveries. A recipe is announced at most once per
+// channel, so existing deliveries are left as they are.
+func (r *AnnouncementRepository) Enqueue(ctx context.Context, deliveries []outbound.AnnouncementDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	models := make([]RecipeAnnouncementModel, len(deliveries))
+	for i, delivery := range deliveries {
+		models[i] = announcementToModel(delivery)
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models).Error
+}
+
+// FindDue finds pending deliveries whose next attempt is due, oldest first
+func (r *AnnouncementRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]outbound.AnnouncementDelivery, error) {
+	var models []RecipeAnnouncementModel
+
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", outbound.AnnouncementPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	deliveries := make([]outbound.AnnouncementDelivery, len(models))
+	for i, model := range models {
+		deliveries[i] = modelToAnnouncement(model)
+	}
+
+	return deliveries, nil
+}
+
+// Update records the outcome of a delivery attempt
+func (r *AnnouncementRepository) Update(ctx context.Context, delivery outbound.AnnouncementDelivery) error {
+	return r.db.WithContext(ctx).
+		Model(&RecipeAnnouncementModel{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"last_error":      delivery.LastError,
+			"sent_at":         delivery.SentAt,
+		}).Error
+}
+
+// CountPending counts deliveries still waiting to be sent
+func (r *AnnouncementRepository) CountPending(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&RecipeAnnouncementModel{}).
+		Where("status = ?", outbound.AnnouncementPending).
+		Count(&count).Error
+	return count, err
+}
+
+func announcementToModel(delivery outbound.AnnouncementDelivery) RecipeAnnouncementModel {
+	return RecipeAnnouncementModel{
+		ID:            delivery.ID,
+		RecipeID:      delivery.RecipeID,
+		Channel:       delivery.Channel,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: delivery.NextAttemptAt,
+		LastError:     delivery.LastError,
+		SentAt:        delivery.SentAt,
+		CreatedAt:     delivery.CreatedAt,
+	}
+}
+
+func modelToAnnouncement(model RecipeAnnouncementModel) outbound.AnnouncementDelivery {
+	return outbound.AnnouncementDelivery{
+		ID:            model.ID,
+		RecipeID:      model.RecipeID,
+		Channel:       model.Channel,
+		Status:        model.Status,
+		Attempts:      model.Attempts,
+		NextAttemptAt: model.NextAttemptAt,
+		LastError:     model.LastError,
+		SentAt:        model.SentAt,
+		CreatedAt:     model.CreatedAt,
+	}
+}
@@ -0,0 +1,201 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ArchiveRepository implements RUM and audit archiving using GORM
+type ArchiveRepository struct {
+	db *gorm.DB
+}
+
+// NewArchiveRepository creates a new archive repository
+func NewArchiveRepository(db *gorm.DB) outbound.ArchiveRepository {
+	return &ArchiveRepository{db: db}
+}
+
+// archivable scopes a dataset's table to the rows the archive may take
+func (r *ArchiveRepository) archivable(ctx context.Context, dataset string) (*gorm.DB, string, error) {
+	db := r.db.WithContext(ctx)
+	switch dataset {
+	case outbound.ArchiveRecipeViews:
+		return db.Model(&RecipeViewModel{}), "viewed_at", nil
+	case outbound.ArchiveProfileCaptures:
+		return db.Model(&ProfileCaptureModel{}).
+			Where("status <> ?", outbound.ProfileCaptureRunning), "started_at", nil
+	default:
+		return nil, "", fmt.Errorf("unknown archive dataset %q", dataset)
+	}
+}
+
+// OldestBefore returns when the dataset's earliest archivable row older than
+// before was recorded
+func (r *ArchiveRepository) OldestBefore(ctx context.Context, dataset string, before time.Time) (*time.Time, error) {
+	query, column, err := r.archivable(ctx, dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the row rather than MIN(), which SQLite returns as text
+	var times []time.Time
+	result := query.
+		Where(column+" < ?", before).
+		Order(column).
+		Limit(1).
+		Pluck(column, &times)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(times) == 0 {
+		return nil, nil
+	}
+	return &times[0], nil
+}
+
+// RollupRecipeViews aggregates the views in [from, to) per recipe and UTC
+// day. Unique viewers use the same viewer identity as the stats page.
+func (r *ArchiveRepository) RollupRecipeViews(ctx context.Context, from, to time.Time) ([]outbound.RecipeViewRollup, int, error) {
+	db := r.db.WithContext(ctx)
+	rows, err := db.Model(&RecipeViewModel{}).
+		Select("id, recipe_id, user_id, session_id, referrer_host, scroll_depth, engaged_ms, viewed_at").
+		Where("viewed_at >= ? AND viewed_at < ?", from, to).
+		Order("recipe_id, viewed_at").
+		Rows()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var rollups []outbound.RecipeViewRollup
+	var current *outbound.RecipeViewRollup
+	var viewers map[string]bool
+	read := 0
+	for rows.Next() {
+		var view RecipeViewModel
+		if err := db.ScanRows(rows, &view); err != nil {
+			return nil, 0, err
+		}
+		read++
+
+		day := view.ViewedAt.UTC().Truncate(24 * time.Hour)
+		if current == nil || current.RecipeID != view.RecipeID || !current.Day.Equal(day) {
+			rollups = append(rollups, outbound.RecipeViewRollup{RecipeID: view.RecipeID, Day: day})
+			current = &rollups[len(rollups)-1]
+			viewers = make(map[string]bool)
+		}
+
+		current.Views++
+		if viewer := viewerKey(view); !viewers[viewer] {
+			viewers[viewer] = true
+			current.UniqueViewers++
+		}
+		if view.ScrollDepth != nil {
+			current.ScrollDepthSum += *view.ScrollDepth
+			current.ScrollSamples++
+		}
+		if view.EngagedMS != nil {
+			current.EngagedMSSum += int64(*view.EngagedMS)
+			current.EngagedSamples++
+		}
+		if view.ReferrerHost != "" {
+			if current.ReferrerHosts == nil {
+				current.ReferrerHosts = make(map[string]int)
+			}
+			current.ReferrerHosts[view.ReferrerHost]++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return rollups, read, nil
+}
+
+// ProfileCapturesBetween returns the finished captures started in [from, to)
+func (r *ArchiveRepository) ProfileCapturesBetween(ctx context.Context, from, to time.Time) ([]*outbound.ProfileCapture, error) {
+	var models []ProfileCaptureModel
+
+	result := r.db.WithContext(ctx).
+		Where("status <> ? AND started_at >= ? AND started_at < ?", outbound.ProfileCaptureRunning, from, to).
+		Order("started_at").
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	captures := make([]*outbound.ProfileCapture, len(models))
+	for i, model := range models {
+		captures[i] = modelToProfileCapture(model)
+	}
+
+	return captures, nil
+}
+
+// DeleteBetween removes the dataset's archivable rows in [from, to)
+func (r *ArchiveRepository) DeleteBetween(ctx context.Context, dataset string, from, to time.Time) (int64, error) {
+	query, column, err := r.archivable(ctx, dataset)
+	if err != nil {
+		return 0, err
+	}
+
+	result := query.
+		Where(column+" >= ? AND "+column+" < ?", from, to).
+		Delete(query.Statement.Model)
+	return result.RowsAffected, result.Error
+}
+
+// SavePartition lists an archived day
+func (r *ArchiveRepository) SavePartition(ctx context.Context, partition *outbound.ArchivePartition) error {
+	model := ArchivePartitionModel{
+		ID:         partition.ID,
+		Dataset:    partition.Dataset,
+		Day:        partition.Day,
+		BlobKey:    partition.BlobKey,
+		Records:    partition.Records,
+		SourceRows: partition.SourceRows,
+		SizeBytes:  partition.SizeBytes,
+		ArchivedAt: partition.ArchivedAt,
+	}
+	if model.ID == uuid.Nil {
+		model.ID = uuid.New()
+		partition.ID = model.ID
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// FindPartitions returns the partitions of days in [from, to), oldest first
+func (r *ArchiveRepository) FindPartitions(ctx context.Context, dataset string, from, to time.Time) ([]*outbound.ArchivePartition, error) {
+	var models []ArchivePartitionModel
+
+	result := r.db.WithContext(ctx).
+		Where("dataset = ? AND day >= ? AND day < ?", dataset, from, to).
+		Order("day, archived_at").
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	partitions := make([]*outbound.ArchivePartition, len(models))
+	for i, model := range models {
+		partitions[i] = &outbound.ArchivePartition{
+			ID:         model.ID,
+			Dataset:    model.Dataset,
+			Day:        model.Day,
+			BlobKey:    model.BlobKey,
+			Records:    model.Records,
+			SourceRows: model.SourceRows,
+			SizeBytes:  model.SizeBytes,
+			ArchivedAt: model.ArchivedAt,
+		}
+	}
+
+	return partitions, nil
+}
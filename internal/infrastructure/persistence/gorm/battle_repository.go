@@ -0,0 +1,215 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/battle"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BattleRepository stores remix battles using GORM
+type BattleRepository struct {
+	db *gorm.DB
+}
+
+// NewBattleRepository creates a new battle repository
+func NewBattleRepository(db *gorm.DB) outbound.BattleRepository {
+	return &BattleRepository{db: db}
+}
+
+// Create inserts a new battle with the AI's entry
+func (r *BattleRepository) Create(ctx context.Context, b *battle.Battle) error {
+	return r.db.WithContext(ctx).Create(battleToModel(b)).Error
+}
+
+// FindByID returns nil when the battle does not exist
+func (r *BattleRepository) FindByID(ctx context.Context, id uuid.UUID) (*battle.Battle, error) {
+	var model BattleModel
+	err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return modelToBattle(&model), nil
+}
+
+// SubmitChef saves the chef's entry and the end of voting
+func (r *BattleRepository) SubmitChef(ctx context.Context, b *battle.Battle) error {
+	model := battleToModel(b)
+	return r.db.WithContext(ctx).Model(&BattleModel{}).
+		Where("id = ?", b.ID).
+		Select("chef_title", "chef_description", "chef_ingredients", "chef_steps", "voting_ends_at", "updated_at").
+		Updates(model).Error
+}
+
+// List returns battles newest first
+func (r *BattleRepository) List(ctx context.Context, filter outbound.BattleFilter) ([]*battle.Battle, error) {
+	query := r.db.WithContext(ctx).Model(&BattleModel{})
+	switch filter.Status {
+	case battle.StatusOpen:
+		query = query.Where("voting_ends_at IS NULL")
+	case battle.StatusVoting:
+		query = query.Where("voting_ends_at > ?", filter.Now)
+	case battle.StatusClosed:
+		query = query.Where("voting_ends_at <= ?", filter.Now)
+	}
+	if filter.ChefID != uuid.Nil {
+		query = query.Where("chef_id = ?", filter.ChefID)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var models []BattleModel
+	if err := query.Order("created_at DESC").Offset(filter.Offset).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	battles := make([]*battle.Battle, len(models))
+	for i := range models {
+		battles[i] = modelToBattle(&models[i])
+	}
+	return battles, nil
+}
+
+// RecordVote inserts the vote and bumps the side's count in one
+// transaction; a second vote by the same user is ignored
+func (r *BattleRepository) RecordVote(ctx context.Context, vote outbound.BattleVote) (bool, error) {
+	column := "chef_votes"
+	if vote.Side == battle.SideAI {
+		column = "ai_votes"
+	}
+
+	recorded := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&BattleVoteModel{
+			BattleID:  vote.BattleID,
+			UserID:    vote.UserID,
+			Side:      string(vote.Side),
+			CreatedAt: vote.CreatedAt,
+		})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		recorded = true
+		return tx.Model(&BattleModel{}).
+			Where("id = ?", vote.BattleID).
+			Update(column, gorm.Expr(column+" + 1")).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return recorded, nil
+}
+
+// FindVote returns nil when the user has not voted in the battle
+func (r *BattleRepository) FindVote(ctx context.Context, battleID, userID uuid.UUID) (*outbound.BattleVote, error) {
+	var model BattleVoteModel
+	err := r.db.WithContext(ctx).First(&model, "battle_id = ? AND user_id = ?", battleID, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &outbound.BattleVote{
+		BattleID:  model.BattleID,
+		UserID:    model.UserID,
+		Side:      battle.Side(model.Side),
+		CreatedAt: model.CreatedAt,
+	}, nil
+}
+
+// TemplateResults tallies the AI's record by template over closed battles
+func (r *BattleRepository) TemplateResults(ctx context.Context, now time.Time) ([]battle.TemplateResult, error) {
+	var rows []struct {
+		Template string
+		Wins     int
+		Losses   int
+		Ties     int
+	}
+	err := r.db.WithContext(ctx).Model(&BattleModel{}).
+		Select(`template,
+			SUM(CASE WHEN ai_votes > chef_votes THEN 1 ELSE 0 END) AS wins,
+			SUM(CASE WHEN ai_votes < chef_votes THEN 1 ELSE 0 END) AS losses,
+			SUM(CASE WHEN ai_votes = chef_votes THEN 1 ELSE 0 END) AS ties`).
+		Where("voting_ends_at <= ?", now).
+		Group("template").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]battle.TemplateResult, len(rows))
+	for i, row := range rows {
+		results[i] = battle.TemplateResult{
+			Template: row.Template,
+			Wins:     row.Wins,
+			Losses:   row.Losses,
+			Ties:     row.Ties,
+		}
+	}
+	return results, nil
+}
+
+func battleToModel(b *battle.Battle) *BattleModel {
+	model := &BattleModel{
+		ID:            b.ID,
+		Prompt:        b.Prompt,
+		ChefID:        b.ChefID,
+		Template:      b.Template,
+		AITitle:       b.AI.Title,
+		AIDescription: b.AI.Description,
+		AIIngredients: StringSlice(b.AI.Ingredients),
+		AISteps:       StringSlice(b.AI.Steps),
+		AIFirst:       b.AIFirst,
+		VotingEndsAt:  b.VotingEndsAt,
+		AIVotes:       b.AIVotes,
+		ChefVotes:     b.ChefVotes,
+		CreatedAt:     b.CreatedAt,
+		UpdatedAt:     b.UpdatedAt,
+	}
+	if b.Chef != nil {
+		model.ChefTitle = b.Chef.Title
+		model.ChefDescription = b.Chef.Description
+		model.ChefIngredients = StringSlice(b.Chef.Ingredients)
+		model.ChefSteps = StringSlice(b.Chef.Steps)
+	}
+	return model
+}
+
+func modelToBattle(model *BattleModel) *battle.Battle {
+	b := &battle.Battle{
+		ID:       model.ID,
+		Prompt:   model.Prompt,
+		ChefID:   model.ChefID,
+		Template: model.Template,
+		AI: battle.Entry{
+			Title:       model.AITitle,
+			Description: model.AIDescription,
+			Ingredients: []string(model.AIIngredients),
+			Steps:       []string(model.AISteps),
+		},
+		AIFirst:      model.AIFirst,
+		VotingEndsAt: model.VotingEndsAt,
+		AIVotes:      model.AIVotes,
+		ChefVotes:    model.ChefVotes,
+		CreatedAt:    model.CreatedAt,
+		UpdatedAt:    model.UpdatedAt,
+	}
+	if model.VotingEndsAt != nil {
+		b.Chef = &battle.Entry{
+			Title:       model.ChefTitle,
+			Description: model.ChefDescription,
+			Ingredients: []string(model.ChefIngredients),
+			Steps:       []string(model.ChefSteps),
+		}
+	}
+	return b
+}
@@ -0,0 +1,59 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/battle"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBattleRepositoryCountsOneVotePerReader(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&BattleModel{}, &BattleVoteModel{}))
+	repo := NewBattleRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	chefID := uuid.New()
+	entry := battle.Entry{Title: "Dal", Ingredients: []string{"lentils"}, Steps: []string{"Simmer."}}
+	b, err := battle.New(chefID, "a cosy lentil dish", "seasonal", entry, true, now)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, b))
+
+	open, err := repo.List(ctx, outbound.BattleFilter{Status: battle.StatusOpen, Now: now})
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Nil(t, open[0].Chef)
+
+	require.NoError(t, b.SubmitChef(chefID, entry, now))
+	require.NoError(t, repo.SubmitChef(ctx, b))
+
+	voter := uuid.New()
+	recorded, err := repo.RecordVote(ctx, outbound.BattleVote{BattleID: b.ID, UserID: voter, Side: battle.SideAI, CreatedAt: now})
+	require.NoError(t, err)
+	assert.True(t, recorded)
+	recorded, err = repo.RecordVote(ctx, outbound.BattleVote{BattleID: b.ID, UserID: voter, Side: battle.SideChef, CreatedAt: now})
+	require.NoError(t, err)
+	assert.False(t, recorded, "readers vote once")
+
+	found, err := repo.FindByID(ctx, b.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.Chef)
+	assert.Equal(t, 1, found.AIVotes)
+	assert.Equal(t, 0, found.ChefVotes)
+	vote, err := repo.FindVote(ctx, b.ID, voter)
+	require.NoError(t, err)
+	assert.Equal(t, battle.SideAI, vote.Side)
+
+	results, err := repo.TemplateResults(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, results, "battles still voting do not count")
+	results, err = repo.TemplateResults(ctx, now.Add(battle.VotingPeriod))
+	require.NoError(t, err)
+	assert.Equal(t, []battle.TemplateResult{{Template: "seasonal", Wins: 1}}, results)
+}
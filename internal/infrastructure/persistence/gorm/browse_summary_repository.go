@@ -0,0 +1,250 @@
+package gorm
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"gorm.io/gorm"
+)
+
+// maxBrowseTagLength matches the browse_tag_counts.tag column
+const maxBrowseTagLength = 100
+
+// BrowseSummaryRepository implements the browse page summaries using
+// GORM. The summaries are plain tables rebuilt in one transaction rather
+// than materialized views, so they work the same on PostgreSQL and SQLite.
+type BrowseSummaryRepository struct {
+	db *gorm.DB
+}
+
+// NewBrowseSummaryRepository creates a new browse summary repository
+func NewBrowseSummaryRepository(db *gorm.DB) outbound.BrowseSummaryRepository {
+	return &BrowseSummaryRepository{db: db}
+}
+
+// Refresh recomputes the summaries from the published recipes and swaps
+// them in together
+func (r *BrowseSummaryRepository) Refresh(ctx context.Context, topPerCuisine int) (*outbound.BrowseSummary, error) {
+	db := r.db.WithContext(ctx)
+	now := time.Now().UTC()
+
+	cuisines, err := r.countCuisines(db, now)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := r.countTags(db, now)
+	if err != nil {
+		return nil, err
+	}
+	topRated, err := r.rankByCuisine(db, topPerCuisine, now)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		all := tx.Session(&gorm.Session{AllowGlobalUpdate: true})
+		for _, model := range []interface{}{&BrowseTagCountModel{}, &BrowseCuisineCountModel{}, &BrowseTopRatedModel{}} {
+			if err := all.Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if len(tags) > 0 {
+			if err := tx.CreateInBatches(tags, inBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(cuisines) > 0 {
+			if err := tx.CreateInBatches(cuisines, inBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(topRated) > 0 {
+			if err := tx.CreateInBatches(topRated, inBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &outbound.BrowseSummary{
+		Tags:        len(tags),
+		Cuisines:    len(cuisines),
+		TopRated:    len(topRated),
+		RefreshedAt: now,
+	}, nil
+}
+
+// countCuisines groups the published recipes by cuisine
+func (r *BrowseSummaryRepository) countCuisines(db *gorm.DB, now time.Time) ([]BrowseCuisineCountModel, error) {
+	var rows []struct {
+		Cuisine string
+		Count   int
+	}
+	result := db.Model(&RecipeModel{}).
+		Select("cuisine, COUNT(*) AS count").
+		Where("status = ? AND cuisine <> ''", "published").
+		Group("cuisine").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	models := make([]BrowseCuisineCountModel, len(rows))
+	for i, row := range rows {
+		models[i] = BrowseCuisineCountModel{Cuisine: row.Cuisine, RecipeCount: row.Count, RefreshedAt: now}
+	}
+	return models, nil
+}
+
+// countTags counts the published recipes per tag. Tags are stored as JSON,
+// so they are counted here rather than in SQL, normalized the same way as
+// the search facets.
+func (r *BrowseSummaryRepository) countTags(db *gorm.DB, now time.Time) ([]BrowseTagCountModel, error) {
+	var tagLists []StringSlice
+	result := db.Model(&RecipeModel{}).
+		Where("status = ?", "published").
+		Pluck("tags", &tagLists)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	counts := make(map[string]int)
+	for _, tags := range tagLists {
+		seen := make(map[string]bool, len(tags))
+		for _, tag := range tags {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" || len(tag) > maxBrowseTagLength || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			counts[tag]++
+		}
+	}
+
+	models := make([]BrowseTagCountModel, 0, len(counts))
+	for tag, count := range counts {
+		models = append(models, BrowseTagCountModel{Tag: tag, RecipeCount: count, RefreshedAt: now})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Tag < models[j].Tag })
+	return models, nil
+}
+
+// rankByCuisine keeps the best rated published recipes of each cuisine.
+// The recipes are streamed in rank order, so only the kept rows are held.
+func (r *BrowseSummaryRepository) rankByCuisine(db *gorm.DB, perCuisine int, now time.Time) ([]BrowseTopRatedModel, error) {
+	rows, err := db.Model(&RecipeModel{}).
+		Select("id, title, cuisine, average_rating, likes_count").
+		Where("status = ? AND cuisine <> ''", "published").
+		Order("cuisine, average_rating DESC, likes_count DESC, id").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var models []BrowseTopRatedModel
+	ranks := make(map[string]int)
+	for rows.Next() {
+		var recipe RecipeModel
+		if err := db.ScanRows(rows, &recipe); err != nil {
+			return nil, err
+		}
+		if ranks[recipe.Cuisine] >= perCuisine {
+			continue
+		}
+		ranks[recipe.Cuisine]++
+		models = append(models, BrowseTopRatedModel{
+			Cuisine:       recipe.Cuisine,
+			Rank:          ranks[recipe.Cuisine],
+			RecipeID:      recipe.ID,
+			Title:         recipe.Title,
+			AverageRating: recipe.AverageRating,
+			LikesCount:    recipe.Likes,
+			RefreshedAt:   now,
+		})
+	}
+	return models, rows.Err()
+}
+
+// TagCounts returns the most used tags
+func (r *BrowseSummaryRepository) TagCounts(ctx context.Context, limit int) ([]outbound.FacetCount, error) {
+	var models []BrowseTagCountModel
+	result := r.db.WithContext(ctx).
+		Order("recipe_count DESC, tag").
+		Limit(limit).
+		Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	counts := make([]outbound.FacetCount, len(models))
+	for i, model := range models {
+		counts[i] = outbound.FacetCount{Value: model.Tag, Count: model.RecipeCount}
+	}
+	return counts, nil
+}
+
+// CuisineCounts returns the most used cuisines
+func (r *BrowseSummaryRepository) CuisineCounts(ctx context.Context, limit int) ([]outbound.FacetCount, error) {
+	var models []BrowseCuisineCountModel
+	result := r.db.WithContext(ctx).
+		Order("recipe_count DESC, cuisine").
+		Limit(limit).
+		Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	counts := make([]outbound.FacetCount, len(models))
+	for i, model := range models {
+		counts[i] = outbound.FacetCount{Value: model.Cuisine, Count: model.RecipeCount}
+	}
+	return counts, nil
+}
+
+// TopRatedByCuisine returns a cuisine's best rated recipes
+func (r *BrowseSummaryRepository) TopRatedByCuisine(ctx context.Context, cuisine string, limit int) ([]outbound.BrowseRecipe, error) {
+	var models []BrowseTopRatedModel
+	result := r.db.WithContext(ctx).
+		Where("cuisine = ?", cuisine).
+		Order("rank").
+		Limit(limit).
+		Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	recipes := make([]outbound.BrowseRecipe, len(models))
+	for i, model := range models {
+		recipes[i] = outbound.BrowseRecipe{
+			RecipeID:      model.RecipeID,
+			Title:         model.Title,
+			Cuisine:       model.Cuisine,
+			AverageRating: model.AverageRating,
+			Likes:         model.LikesCount,
+			Rank:          model.Rank,
+		}
+	}
+	return recipes, nil
+}
+
+// RefreshedAt returns when the summaries were last rebuilt. Every table is
+// rebuilt together, so the cuisine counts stand for all of them; an empty
+// refresh leaves no rows and reads as never refreshed.
+func (r *BrowseSummaryRepository) RefreshedAt(ctx context.Context) (*time.Time, error) {
+	var models []BrowseCuisineCountModel
+	result := r.db.WithContext(ctx).Limit(1).Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+	return &models[0].RefreshedAt, nil
+}
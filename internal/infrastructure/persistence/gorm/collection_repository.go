@@ -0,0 +1,101 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CollectionRepository implements outbound.CollectionRepository using GORM
+type CollectionRepository struct {
+	db *gorm.DB
+}
+
+// NewCollectionRepository creates a new collection repository
+func NewCollectionRepository(db *gorm.DB) outbound.CollectionRepository {
+	return &CollectionRepository{db: db}
+}
+
+// ListByUser returns the user's collections, oldest first, with their
+// recipes in order
+func (r *CollectionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]outbound.Collection, error) {
+	var models []CollectionModel
+	err := r.db.WithContext(ctx).
+		Preload("Recipes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_index ASC, added_at ASC")
+		}).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	collections := make([]outbound.Collection, len(models))
+	for i, model := range models {
+		collection := outbound.Collection{
+			ID:          model.ID,
+			UserID:      model.UserID,
+			Name:        model.Name,
+			Description: model.Description,
+			IsPublic:    model.IsPublic,
+			RecipeIDs:   make([]uuid.UUID, len(model.Recipes)),
+			CreatedAt:   model.CreatedAt,
+		}
+		for j, entry := range model.Recipes {
+			collection.RecipeIDs[j] = entry.RecipeID
+		}
+		collections[i] = collection
+	}
+	return collections, nil
+}
+
+// Create stores the collection and its recipes in one transaction. A
+// recipe listed twice is kept at its first position.
+func (r *CollectionRepository) Create(ctx context.Context, collection *outbound.Collection) error {
+	model := CollectionModel{
+		ID:          collection.ID,
+		UserID:      collection.UserID,
+		Name:        collection.Name,
+		Description: collection.Description,
+		IsPublic:    collection.IsPublic,
+		CreatedAt:   collection.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(&model).Error; err != nil {
+			return err
+		}
+		// is_public defaults to true, which GORM applies to a false value
+		if !collection.IsPublic {
+			if err := tx.Model(&model).Update("is_public", false).Error; err != nil {
+				return err
+			}
+		}
+		collection.ID = model.ID
+		collection.CreatedAt = model.CreatedAt
+
+		now := time.Now()
+		seen := make(map[uuid.UUID]bool, len(collection.RecipeIDs))
+		entries := make([]CollectionRecipeModel, 0, len(collection.RecipeIDs))
+		for _, recipeID := range collection.RecipeIDs {
+			if seen[recipeID] {
+				continue
+			}
+			seen[recipeID] = true
+			entries = append(entries, CollectionRecipeModel{
+				CollectionID: model.ID,
+				RecipeID:     recipeID,
+				OrderIndex:   len(entries) + 1,
+				AddedAt:      now,
+			})
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.Omit(clause.Associations).Create(&entries).Error
+	})
+}
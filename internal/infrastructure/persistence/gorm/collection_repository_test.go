@@ -0,0 +1,43 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionsKeepTheirRecipesInOrder(t *testing.T) {
+	db, recipeID := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&CollectionModel{}, &CollectionRecipeModel{}))
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	other := RecipeModel{ID: uuid.New(), Title: "Shortbread", AuthorID: author.ID, Status: "draft"}
+	require.NoError(t, db.Create(&other).Error)
+	repo := NewCollectionRepository(db)
+	ctx := context.Background()
+
+	bakes := &outbound.Collection{
+		UserID:    author.ID,
+		Name:      "Bakes",
+		IsPublic:  true,
+		RecipeIDs: []uuid.UUID{other.ID, recipeID, other.ID},
+	}
+	require.NoError(t, repo.Create(ctx, bakes))
+	assert.NotEqual(t, uuid.Nil, bakes.ID)
+	require.NoError(t, repo.Create(ctx, &outbound.Collection{UserID: author.ID, Name: "Someday"}))
+	require.NoError(t, repo.Create(ctx, &outbound.Collection{UserID: uuid.New(), Name: "Not mine"}))
+
+	collections, err := repo.ListByUser(ctx, author.ID)
+	require.NoError(t, err)
+	require.Len(t, collections, 2)
+	assert.Equal(t, "Bakes", collections[0].Name)
+	assert.True(t, collections[0].IsPublic)
+	assert.Equal(t, []uuid.UUID{other.ID, recipeID}, collections[0].RecipeIDs, "repeats are kept at their first position")
+	assert.Equal(t, "Someday", collections[1].Name)
+	assert.False(t, collections[1].IsPublic)
+	assert.Empty(t, collections[1].RecipeIDs)
+}
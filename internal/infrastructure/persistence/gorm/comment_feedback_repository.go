@@ -0,0 +1,239 @@
+package gorm
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommentFeedbackRepository implements comment reaction and review vote
+// storage using GORM
+type CommentFeedbackRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentFeedbackRepository creates a new comment feedback repository
+func NewCommentFeedbackRepository(db *gorm.DB) outbound.CommentFeedbackRepository {
+	return &CommentFeedbackRepository{db: db}
+}
+
+// AddReaction stores a reaction, ignoring one the user already gave
+func (r *CommentFeedbackRepository) AddReaction(ctx context.Context, reaction *comment.Reaction) error {
+	model := CommentReactionModel{
+		CommentID: reaction.CommentID,
+		UserID:    reaction.UserID,
+		Kind:      string(reaction.Kind),
+		RecipeID:  reaction.RecipeID,
+		CreatedAt: reaction.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model).Error
+}
+
+// RemoveReaction deletes a reaction, if it exists
+func (r *CommentFeedbackRepository) RemoveReaction(ctx context.Context, commentID, userID uuid.UUID, kind comment.ReactionKind) error {
+	return r.db.WithContext(ctx).
+		Where("comment_id = ? AND user_id = ? AND kind = ?", commentID, userID, string(kind)).
+		Delete(&CommentReactionModel{}).Error
+}
+
+// CountReactions tallies the reactions to each comment by kind
+func (r *CommentFeedbackRepository) CountReactions(ctx context.Context, commentIDs []uuid.UUID) (map[uuid.UUID]map[comment.ReactionKind]int, error) {
+	counts := make(map[uuid.UUID]map[comment.ReactionKind]int)
+	if len(commentIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		CommentID uuid.UUID
+		Kind      string
+		Count     int
+	}
+	err := r.db.WithContext(ctx).Model(&CommentReactionModel{}).
+		Select("comment_id, kind, COUNT(*) AS count").
+		Where("comment_id IN ?", commentIDs).
+		Group("comment_id, kind").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if counts[row.CommentID] == nil {
+			counts[row.CommentID] = make(map[comment.ReactionKind]int)
+		}
+		counts[row.CommentID][comment.ReactionKind(row.Kind)] = row.Count
+	}
+	return counts, nil
+}
+
+// FindUserReactions returns the reactions a user gave each comment
+func (r *CommentFeedbackRepository) FindUserReactions(ctx context.Context, commentIDs []uuid.UUID, userID uuid.UUID) (map[uuid.UUID][]comment.ReactionKind, error) {
+	given := make(map[uuid.UUID][]comment.ReactionKind)
+	if len(commentIDs) == 0 {
+		return given, nil
+	}
+
+	var models []CommentReactionModel
+	err := r.db.WithContext(ctx).
+		Where("comment_id IN ? AND user_id = ?", commentIDs, userID).
+		Order("created_at").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, model := range models {
+		given[model.CommentID] = append(given[model.CommentID], comment.ReactionKind(model.Kind))
+	}
+	return given, nil
+}
+
+// SaveHelpfulVote inserts a vote or replaces the voter's earlier one
+func (r *CommentFeedbackRepository) SaveHelpfulVote(ctx context.Context, vote *comment.HelpfulVote) error {
+	model := ReviewVoteModel{
+		RecipeID:   vote.RecipeID,
+		ReviewerID: vote.ReviewerID,
+		VoterID:    vote.VoterID,
+		Helpful:    vote.Helpful,
+		CreatedAt:  vote.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "recipe_id"}, {Name: "reviewer_id"}, {Name: "voter_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"helpful", "created_at"}),
+	}).Create(&model).Error
+}
+
+// RemoveHelpfulVote deletes a vote, if it exists
+func (r *CommentFeedbackRepository) RemoveHelpfulVote(ctx context.Context, recipeID, reviewerID, voterID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("recipe_id = ? AND reviewer_id = ? AND voter_id = ?", recipeID, reviewerID, voterID).
+		Delete(&ReviewVoteModel{}).Error
+}
+
+// HelpfulTallies counts the votes on each review of a recipe
+func (r *CommentFeedbackRepository) HelpfulTallies(ctx context.Context, recipeID uuid.UUID) (map[uuid.UUID]outbound.HelpfulTally, error) {
+	var rows []struct {
+		ReviewerID uuid.UUID
+		Helpful    bool
+		Count      int
+	}
+	err := r.db.WithContext(ctx).Model(&ReviewVoteModel{}).
+		Select("reviewer_id, helpful, COUNT(*) AS count").
+		Where("recipe_id = ?", recipeID).
+		Group("reviewer_id, helpful").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tallies := make(map[uuid.UUID]outbound.HelpfulTally)
+	for _, row := range rows {
+		tally := tallies[row.ReviewerID]
+		if row.Helpful {
+			tally.Helpful = row.Count
+		} else {
+			tally.NotHelpful = row.Count
+		}
+		tallies[row.ReviewerID] = tally
+	}
+	return tallies, nil
+}
+
+// FindHelpfulVotes returns a voter's votes on a recipe's reviews
+func (r *CommentFeedbackRepository) FindHelpfulVotes(ctx context.Context, recipeID, voterID uuid.UUID) (map[uuid.UUID]bool, error) {
+	var models []ReviewVoteModel
+	err := r.db.WithContext(ctx).
+		Where("recipe_id = ? AND voter_id = ?", recipeID, voterID).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	votes := make(map[uuid.UUID]bool, len(models))
+	for _, model := range models {
+		votes[model.ReviewerID] = model.Helpful
+	}
+	return votes, nil
+}
+
+// FindReviews returns the ratings on a recipe that have a written review
+func (r *CommentFeedbackRepository) FindReviews(ctx context.Context, recipeID uuid.UUID) ([]recipe.Rating, error) {
+	var models []RatingModel
+	err := r.db.WithContext(ctx).
+		Where("recipe_id = ? AND comment IS NOT NULL AND comment <> ''", recipeID).
+		Order("created_at DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := make([]recipe.Rating, len(models))
+	for i, model := range models {
+		reviews[i] = recipe.Rating{
+			UserID:    model.UserID,
+			Value:     model.Value,
+			Comment:   model.Comment,
+			CreatedAt: model.CreatedAt,
+		}
+	}
+	return reviews, nil
+}
+
+// engagementScan is how many rows per wanted recipe FindEngagedRecipes
+// reads, since a user often reacts several times on one recipe
+const engagementScan = 4
+
+// FindEngagedRecipes merges the recipes a user reacted to comments on with
+// those whose reviews they found helpful, most recent first
+func (r *CommentFeedbackRepository) FindEngagedRecipes(ctx context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	var reactions []CommentReactionModel
+	err := r.db.WithContext(ctx).
+		Select("recipe_id", "created_at").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit * engagementScan).
+		Find(&reactions).Error
+	if err != nil {
+		return nil, err
+	}
+	var votes []ReviewVoteModel
+	err = r.db.WithContext(ctx).
+		Select("recipe_id", "created_at").
+		Where("voter_id = ? AND helpful = ?", userID, true).
+		Order("created_at DESC").
+		Limit(limit * engagementScan).
+		Find(&votes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[uuid.UUID]time.Time)
+	note := func(recipeID uuid.UUID, at time.Time) {
+		if at.After(latest[recipeID]) {
+			latest[recipeID] = at
+		}
+	}
+	for _, reaction := range reactions {
+		note(reaction.RecipeID, reaction.CreatedAt)
+	}
+	for _, vote := range votes {
+		note(vote.RecipeID, vote.CreatedAt)
+	}
+
+	ids := make([]uuid.UUID, 0, len(latest))
+	for id := range latest {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return latest[ids[i]].After(latest[ids[j]]) })
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
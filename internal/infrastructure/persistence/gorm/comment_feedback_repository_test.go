@@ -0,0 +1,52 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentFeedbackRepositoryReplacesVotesAndTalliesReactions(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&CommentReactionModel{}, &ReviewVoteModel{}))
+	repo := NewCommentFeedbackRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	recipeID, otherRecipe, reviewer, reader := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	commentID := uuid.New()
+	heart := &comment.Reaction{CommentID: commentID, RecipeID: recipeID, UserID: reader, Kind: comment.ReactionHeart, CreatedAt: now}
+	require.NoError(t, repo.AddReaction(ctx, heart))
+	require.NoError(t, repo.AddReaction(ctx, heart))
+	require.NoError(t, repo.AddReaction(ctx, &comment.Reaction{CommentID: commentID, RecipeID: recipeID, UserID: uuid.New(), Kind: comment.ReactionHeart, CreatedAt: now}))
+
+	counts, err := repo.CountReactions(ctx, []uuid.UUID{commentID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, counts[commentID][comment.ReactionHeart])
+
+	vote := &comment.HelpfulVote{RecipeID: otherRecipe, ReviewerID: reviewer, VoterID: reader, Helpful: true, CreatedAt: now.Add(time.Hour)}
+	require.NoError(t, repo.SaveHelpfulVote(ctx, vote))
+	vote.Helpful = false
+	require.NoError(t, repo.SaveHelpfulVote(ctx, vote))
+
+	tallies, err := repo.HelpfulTallies(ctx, otherRecipe)
+	require.NoError(t, err)
+	assert.Equal(t, outbound.HelpfulTally{NotHelpful: 1}, tallies[reviewer])
+
+	// Only helpful votes count as engagement
+	engaged, err := repo.FindEngagedRecipes(ctx, reader, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{recipeID}, engaged)
+
+	vote.Helpful = true
+	require.NoError(t, repo.SaveHelpfulVote(ctx, vote))
+	engaged, err = repo.FindEngagedRecipes(ctx, reader, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{otherRecipe, recipeID}, engaged)
+}
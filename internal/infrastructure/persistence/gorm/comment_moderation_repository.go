@@ -0,0 +1,231 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommentModerationRepository implements discussion locks, comment blocks,
+// reader flags and the moderation audit log using GORM
+type CommentModerationRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentModerationRepository creates a new comment moderation repository
+func NewCommentModerationRepository(db *gorm.DB) outbound.CommentModerationRepository {
+	return &CommentModerationRepository{db: db}
+}
+
+// FindDiscussion returns a recipe's discussion settings, or nil when none
+// were saved
+func (r *CommentModerationRepository) FindDiscussion(ctx context.Context, recipeID uuid.UUID) (*comment.Discussion, error) {
+	var model RecipeDiscussionModel
+	err := r.db.WithContext(ctx).First(&model, "recipe_id = ?", recipeID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &comment.Discussion{
+		RecipeID: model.RecipeID,
+		Locked:   model.Locked,
+		LockedBy: model.LockedBy,
+		LockedAt: model.LockedAt,
+	}, nil
+}
+
+// SaveDiscussion inserts or replaces a recipe's discussion settings
+func (r *CommentModerationRepository) SaveDiscussion(ctx context.Context, discussion *comment.Discussion) error {
+	model := RecipeDiscussionModel{
+		RecipeID:  discussion.RecipeID,
+		Locked:    discussion.Locked,
+		LockedBy:  discussion.LockedBy,
+		LockedAt:  discussion.LockedAt,
+		UpdatedAt: time.Now(),
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "recipe_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"locked", "locked_by", "locked_at", "updated_at"}),
+	}).Create(&model).Error
+}
+
+// SaveBlock stores a block, replacing the reason of an existing one
+func (r *CommentModerationRepository) SaveBlock(ctx context.Context, block *comment.Block) error {
+	model := CommentBlockModel{
+		AuthorID:  block.AuthorID,
+		UserID:    block.UserID,
+		Reason:    block.Reason,
+		CreatedAt: block.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "author_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason"}),
+	}).Create(&model).Error
+}
+
+// DeleteBlock removes a block, reporting whether there was one
+func (r *CommentModerationRepository) DeleteBlock(ctx context.Context, authorID, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("author_id = ? AND user_id = ?", authorID, userID).
+		Delete(&CommentBlockModel{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// IsBlocked reports whether the author blocked the user
+func (r *CommentModerationRepository) IsBlocked(ctx context.Context, authorID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&CommentBlockModel{}).
+		Where("author_id = ? AND user_id = ?", authorID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindBlocks returns an author's blocks, newest first
+func (r *CommentModerationRepository) FindBlocks(ctx context.Context, authorID uuid.UUID) ([]comment.Block, error) {
+	var models []CommentBlockModel
+	err := r.db.WithContext(ctx).
+		Where("author_id = ?", authorID).
+		Order("created_at DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]comment.Block, len(models))
+	for i, model := range models {
+		blocks[i] = comment.Block{
+			AuthorID:  model.AuthorID,
+			UserID:    model.UserID,
+			Reason:    model.Reason,
+			CreatedAt: model.CreatedAt,
+		}
+	}
+	return blocks, nil
+}
+
+// RecordEvent appends to the audit log
+func (r *CommentModerationRepository) RecordEvent(ctx context.Context, event *comment.ModerationEvent) error {
+	model := CommentModerationEventModel{
+		ID:        event.ID,
+		ActorID:   event.ActorID,
+		Action:    string(event.Action),
+		RecipeID:  event.RecipeID,
+		CommentID: event.CommentID,
+		SubjectID: event.SubjectID,
+		Reason:    event.Reason,
+		CreatedAt: event.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// FindEvents returns matching audit events, newest first
+func (r *CommentModerationRepository) FindEvents(ctx context.Context, filter outbound.ModerationEventFilter) ([]comment.ModerationEvent, error) {
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.RecipeID != nil {
+		query = query.Where("recipe_id = ?", *filter.RecipeID)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var models []CommentModerationEventModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]comment.ModerationEvent, len(models))
+	for i, model := range models {
+		events[i] = comment.ModerationEvent{
+			ID:        model.ID,
+			ActorID:   model.ActorID,
+			Action:    comment.Action(model.Action),
+			RecipeID:  model.RecipeID,
+			CommentID: model.CommentID,
+			SubjectID: model.SubjectID,
+			Reason:    model.Reason,
+			CreatedAt: model.CreatedAt,
+		}
+	}
+	return events, nil
+}
+
+// SaveFlag stores a flag, keeping the first one when the user already
+// flagged the comment
+func (r *CommentModerationRepository) SaveFlag(ctx context.Context, flag *comment.Flag) error {
+	model := CommentFlagModel{
+		CommentID: flag.CommentID,
+		UserID:    flag.UserID,
+		Reason:    string(flag.Reason),
+		Note:      flag.Note,
+		CreatedAt: flag.CreatedAt,
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model).Error
+}
+
+// FindFlagged returns visible comments with flags, most flagged first and
+// then longest waiting
+func (r *CommentModerationRepository) FindFlagged(ctx context.Context, limit int) ([]outbound.FlaggedComment, error) {
+	var rows []struct {
+		CommentID uuid.UUID
+		Flags     int
+	}
+	err := r.db.WithContext(ctx).Model(&CommentFlagModel{}).
+		Select("comment_flags.comment_id, COUNT(*) AS flags").
+		Joins("JOIN comments ON comments.id = comment_flags.comment_id").
+		Where("comments.status = ? AND comments.deleted_at IS NULL", string(comment.StatusVisible)).
+		Group("comment_flags.comment_id").
+		Order("flags DESC, MIN(comment_flags.created_at)").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	flagged := make([]outbound.FlaggedComment, len(rows))
+	index := make(map[uuid.UUID]int, len(rows))
+	for i, row := range rows {
+		ids[i] = row.CommentID
+		index[row.CommentID] = i
+		flagged[i] = outbound.FlaggedComment{
+			CommentID: row.CommentID,
+			Flags:     row.Flags,
+			Reasons:   make(map[comment.FlagReason]int),
+		}
+	}
+
+	// Tally reasons from the rows rather than MIN(), which SQLite returns
+	// as text
+	var models []CommentFlagModel
+	if err := r.db.WithContext(ctx).Where("comment_id IN ?", ids).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	for _, model := range models {
+		f := &flagged[index[model.CommentID]]
+		f.Reasons[comment.FlagReason(model.Reason)]++
+		if f.FirstFlaggedAt.IsZero() || model.CreatedAt.Before(f.FirstFlaggedAt) {
+			f.FirstFlaggedAt = model.CreatedAt
+		}
+	}
+	return flagged, nil
+}
+
+// ClearFlags deletes a comment's flags
+func (r *CommentModerationRepository) ClearFlags(ctx context.Context, commentID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("comment_id = ?", commentID).
+		Delete(&CommentFlagModel{}).Error
+}
@@ -0,0 +1,120 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentModerationRepositoryStoresHiddenCommentsBlocksAndEvents(t *testing.T) {
+	db, recipeID := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&CommentModel{}, &RecipeDiscussionModel{}, &CommentBlockModel{}, &CommentModerationEventModel{}))
+	comments := NewCommentRepository(db)
+	repo := NewCommentModerationRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	c, err := comment.NewComment(recipeID, author.ID, "Rude remark", comment.SourceWeb)
+	require.NoError(t, err)
+	require.NoError(t, comments.Create(ctx, c))
+	require.NoError(t, c.Hide(author.ID, "off-topic", now))
+	require.NoError(t, comments.UpdateModeration(ctx, c))
+
+	hidden, err := comments.FindByModerationStatus(ctx, comment.StatusHidden, 10)
+	require.NoError(t, err)
+	require.Len(t, hidden, 1)
+	assert.Equal(t, "off-topic", hidden[0].HideReason())
+	require.NoError(t, hidden[0].Remove())
+	require.NoError(t, comments.UpdateModeration(ctx, hidden[0]))
+	stored, err := comments.FindByID(ctx, c.ID())
+	require.NoError(t, err)
+	assert.Equal(t, comment.StatusRemoved, stored.Status())
+
+	lockedAt := now
+	require.NoError(t, repo.SaveDiscussion(ctx, &comment.Discussion{RecipeID: recipeID, Locked: true, LockedBy: &author.ID, LockedAt: &lockedAt}))
+	require.NoError(t, repo.SaveDiscussion(ctx, &comment.Discussion{RecipeID: recipeID}))
+	discussion, err := repo.FindDiscussion(ctx, recipeID)
+	require.NoError(t, err)
+	assert.False(t, discussion.Locked)
+
+	blocked := uuid.New()
+	require.NoError(t, repo.SaveBlock(ctx, &comment.Block{AuthorID: author.ID, UserID: blocked, Reason: "spam", CreatedAt: now}))
+	require.NoError(t, repo.SaveBlock(ctx, &comment.Block{AuthorID: author.ID, UserID: blocked, Reason: "still spam", CreatedAt: now}))
+	blocks, err := repo.FindBlocks(ctx, author.ID)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, "still spam", blocks[0].Reason)
+	deleted, err := repo.DeleteBlock(ctx, author.ID, blocked)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	isBlocked, err := repo.IsBlocked(ctx, author.ID, blocked)
+	require.NoError(t, err)
+	assert.False(t, isBlocked)
+
+	for i, action := range []comment.Action{comment.ActionLock, comment.ActionBlock} {
+		event := comment.ModerationEvent{ID: uuid.New(), ActorID: author.ID, Action: action, CreatedAt: now.Add(time.Duration(i) * time.Minute)}
+		if action == comment.ActionLock {
+			event.RecipeID = &recipeID
+		}
+		require.NoError(t, repo.RecordEvent(ctx, &event))
+	}
+	events, err := repo.FindEvents(ctx, outbound.ModerationEventFilter{ActorID: &author.ID})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, comment.ActionBlock, events[0].Action)
+	events, err = repo.FindEvents(ctx, outbound.ModerationEventFilter{RecipeID: &recipeID})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, comment.ActionLock, events[0].Action)
+}
+
+func TestCommentModerationRepositoryTalliesFlags(t *testing.T) {
+	db, recipeID := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&CommentModel{}, &CommentFlagModel{}))
+	comments := NewCommentRepository(db)
+	repo := NewCommentModerationRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	quiet, err := comment.NewComment(recipeID, author.ID, "Lovely", comment.SourceWeb)
+	require.NoError(t, err)
+	loud, err := comment.NewComment(recipeID, author.ID, "Buy pills", comment.SourceWeb)
+	require.NoError(t, err)
+	require.NoError(t, comments.Create(ctx, quiet))
+	require.NoError(t, comments.Create(ctx, loud))
+
+	first, second := uuid.New(), uuid.New()
+	require.NoError(t, repo.SaveFlag(ctx, &comment.Flag{CommentID: quiet.ID(), UserID: first, Reason: comment.FlagOffTopic, CreatedAt: now}))
+	require.NoError(t, repo.SaveFlag(ctx, &comment.Flag{CommentID: loud.ID(), UserID: first, Reason: comment.FlagSpam, CreatedAt: now}))
+	require.NoError(t, repo.SaveFlag(ctx, &comment.Flag{CommentID: loud.ID(), UserID: first, Reason: comment.FlagAbuse, CreatedAt: now}))
+	require.NoError(t, repo.SaveFlag(ctx, &comment.Flag{CommentID: loud.ID(), UserID: second, Reason: comment.FlagSpam, CreatedAt: now.Add(time.Hour)}))
+
+	flagged, err := repo.FindFlagged(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, flagged, 2)
+	assert.Equal(t, loud.ID(), flagged[0].CommentID)
+	assert.Equal(t, 2, flagged[0].Flags)
+	assert.Equal(t, map[comment.FlagReason]int{comment.FlagSpam: 2}, flagged[0].Reasons, "a second flag from the same user is ignored")
+
+	require.NoError(t, loud.Delete())
+	require.NoError(t, comments.UpdateContent(ctx, loud))
+	require.NoError(t, repo.ClearFlags(ctx, quiet.ID()))
+	flagged, err = repo.FindFlagged(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, flagged, "only visible comments are queued")
+
+	stored, err := comments.FindByID(ctx, loud.ID())
+	require.NoError(t, err)
+	assert.Equal(t, comment.StatusDeleted, stored.Status())
+	assert.Empty(t, stored.Content())
+}
@@ -0,0 +1,241 @@
+// Package gorm provides GORM-based repository implementations
+package gorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommentRepository implements comment persistence using GORM
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository creates a new comment repository
+func NewCommentRepository(db *gorm.DB) outbound.CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// Create stores a new comment
+func (r *CommentRepository) Create(ctx context.Context, c *comment.Comment) error {
+	model := commentToModel(c)
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// FindByID finds a comment, returning nil when it does not exist
+func (r *CommentRepository) FindByID(ctx context.Context, id uuid.UUID) (*comment.Comment, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+// FindByRecipe returns the recipe's comments, oldest first
+func (r *CommentRepository) FindByRecipe(ctx context.Context, recipeID uuid.UUID) ([]*comment.Comment, error) {
+	var models []CommentModel
+
+	result := r.db.WithContext(ctx).
+		Where("recipe_id = ?", recipeID).
+		Order("created_at").
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	comments := make([]*comment.Comment, len(models))
+	for i, model := range models {
+		comments[i] = modelToComment(model)
+	}
+
+	return comments, nil
+}
+
+// UpdateModeration saves a comment's moderation status
+func (r *CommentRepository) UpdateModeration(ctx context.Context, c *comment.Comment) error {
+	return r.db.WithContext(ctx).Model(&CommentModel{}).
+		Where("id = ?", c.ID()).
+		Updates(map[string]interface{}{
+			"status":      string(c.Status()),
+			"hidden_by":   c.HiddenBy(),
+			"hide_reason": c.HideReason(),
+			"hidden_at":   c.HiddenAt(),
+		}).Error
+}
+
+// UpdateContent saves an edited or deleted comment's text and status
+func (r *CommentRepository) UpdateContent(ctx context.Context, c *comment.Comment) error {
+	return r.db.WithContext(ctx).Model(&CommentModel{}).
+		Where("id = ?", c.ID()).
+		Updates(map[string]interface{}{
+			"content":   c.Content(),
+			"status":    string(c.Status()),
+			"edited_at": c.EditedAt(),
+		}).Error
+}
+
+// FindByModerationStatus returns comments in a status, oldest first
+func (r *CommentRepository) FindByModerationStatus(ctx context.Context, status comment.ModerationStatus, limit int) ([]*comment.Comment, error) {
+	var models []CommentModel
+	err := r.db.WithContext(ctx).
+		Where("status = ?", string(status)).
+		Order("hidden_at, created_at").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]*comment.Comment, len(models))
+	for i, model := range models {
+		comments[i] = modelToComment(model)
+	}
+	return comments, nil
+}
+
+// FindByEmailMessageID finds the comment posted from an email, returning nil
+// when that email has not been posted
+func (r *CommentRepository) FindByEmailMessageID(ctx context.Context, messageID string) (*comment.Comment, error) {
+	return r.findOne(ctx, "email_message_id = ?", messageID)
+}
+
+func (r *CommentRepository) findOne(ctx context.Context, query string, arg interface{}) (*comment.Comment, error) {
+	var model CommentModel
+
+	result := r.db.WithContext(ctx).First(&model, query, arg)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return modelToComment(model), nil
+}
+
+// ReplyTokenRepository implements reply-to token persistence using GORM
+type ReplyTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewReplyTokenRepository creates a new reply token repository
+func NewReplyTokenRepository(db *gorm.DB) outbound.ReplyTokenRepository {
+	return &ReplyTokenRepository{db: db}
+}
+
+// Create stores a reply token
+func (r *ReplyTokenRepository) Create(ctx context.Context, token outbound.ReplyToken) error {
+	model := CommentReplyTokenModel{
+		ID:           token.ID,
+		UserID:       token.UserID,
+		RecipeID:     token.RecipeID,
+		ParentID:     token.ParentID,
+		ReviewUserID: token.ReviewUserID,
+		ExpiresAt:    token.ExpiresAt,
+		CreatedAt:    token.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// FindByID finds a reply token, returning nil when it does not exist
+func (r *ReplyTokenRepository) FindByID(ctx context.Context, id uuid.UUID) (*outbound.ReplyToken, error) {
+	var model CommentReplyTokenModel
+
+	result := r.db.WithContext(ctx).First(&model, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return &outbound.ReplyToken{
+		ID:           model.ID,
+		UserID:       model.UserID,
+		RecipeID:     model.RecipeID,
+		ParentID:     model.ParentID,
+		ReviewUserID: model.ReviewUserID,
+		ExpiresAt:    model.ExpiresAt,
+		CreatedAt:    model.CreatedAt,
+	}, nil
+}
+
+// EmailSuppressionRepository implements the email suppression list using GORM
+type EmailSuppressionRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailSuppressionRepository creates a new email suppression repository
+func NewEmailSuppressionRepository(db *gorm.DB) outbound.EmailSuppressionRepository {
+	return &EmailSuppressionRepository{db: db}
+}
+
+// Suppress adds an address to the list, keeping the first reason recorded
+func (r *EmailSuppressionRepository) Suppress(ctx context.Context, email, reason, detail string) error {
+	model := EmailSuppressionModel{
+		Email:  strings.ToLower(strings.TrimSpace(email)),
+		Reason: reason,
+		Detail: detail,
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model).Error
+}
+
+// IsSuppressed reports whether an address is on the list
+func (r *EmailSuppressionRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&EmailSuppressionModel{}).
+		Where("email = ?", strings.ToLower(strings.TrimSpace(email))).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func commentToModel(c *comment.Comment) CommentModel {
+	model := CommentModel{
+		ID:           c.ID(),
+		RecipeID:     c.RecipeID(),
+		UserID:       c.AuthorID(),
+		ParentID:     c.ParentID(),
+		ReviewUserID: c.ReviewUserID(),
+		Content:      c.Content(),
+		Source:       string(c.Source()),
+		Status:       string(c.Status()),
+		HiddenBy:     c.HiddenBy(),
+		HideReason:   c.HideReason(),
+		HiddenAt:     c.HiddenAt(),
+		EditedAt:     c.EditedAt(),
+		CreatedAt:    c.CreatedAt(),
+		UpdatedAt:    c.CreatedAt(),
+	}
+	if id := c.EmailMessageID(); id != "" {
+		model.EmailMessageID = &id
+	}
+	return model
+}
+
+func modelToComment(model CommentModel) *comment.Comment {
+	var messageID string
+	if model.EmailMessageID != nil {
+		messageID = *model.EmailMessageID
+	}
+	c := comment.Reconstruct(
+		model.ID,
+		model.RecipeID,
+		model.UserID,
+		model.ParentID,
+		model.ReviewUserID,
+		model.Content,
+		comment.Source(model.Source),
+		messageID,
+		model.CreatedAt,
+	)
+	c.SetModeration(comment.ModerationStatus(model.Status), model.HiddenBy, model.HideReason, model.HiddenAt)
+	c.SetEditedAt(model.EditedAt)
+	return c
+}
@@ -0,0 +1,157 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/duplicate"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DuplicateRepository implements outbound.DuplicateRepository using GORM
+type DuplicateRepository struct {
+	db *gorm.DB
+}
+
+// NewDuplicateRepository creates a new duplicate repository
+func NewDuplicateRepository(db *gorm.DB) outbound.DuplicateRepository {
+	return &DuplicateRepository{db: db}
+}
+
+// EnqueueCheck queues a recipe for the duplicate check, keeping the place
+// of one already queued
+func (r *DuplicateRepository) EnqueueCheck(ctx context.Context, recipeID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&DuplicateCheckModel{RecipeID: recipeID, QueuedAt: at}).Error
+}
+
+// DueChecks returns queued recipes, longest waiting first
+func (r *DuplicateRepository) DueChecks(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&DuplicateCheckModel{}).
+		Order("queued_at ASC, recipe_id ASC").
+		Limit(limit).
+		Pluck("recipe_id", &ids).Error
+	return ids, err
+}
+
+// CompleteCheck takes a recipe off the queue
+func (r *DuplicateRepository) CompleteCheck(ctx context.Context, recipeID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("recipe_id = ?", recipeID).Delete(&DuplicateCheckModel{}).Error
+}
+
+// Create stores a new flag
+func (r *DuplicateRepository) Create(ctx context.Context, f *duplicate.Flag) error {
+	m := duplicateFlagToModel(f)
+	return r.db.WithContext(ctx).Create(&m).Error
+}
+
+// FindByID returns a flag, or nil when there is none
+func (r *DuplicateRepository) FindByID(ctx context.Context, id uuid.UUID) (*duplicate.Flag, error) {
+	return r.first(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// FindLatestForRecipe returns the recipe's newest flag, or nil
+func (r *DuplicateRepository) FindLatestForRecipe(ctx context.Context, recipeID uuid.UUID) (*duplicate.Flag, error) {
+	return r.first(r.db.WithContext(ctx).Where("recipe_id = ?", recipeID).Order("created_at DESC, id DESC"))
+}
+
+// HasFlag reports whether recipeID was flagged as a copy of originalID
+func (r *DuplicateRepository) HasFlag(ctx context.Context, recipeID, originalID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&DuplicateFlagModel{}).
+		Where("recipe_id = ? AND original_id = ?", recipeID, originalID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindAwaitingReview pages through open flags and flags with a pending
+// appeal, oldest first
+func (r *DuplicateRepository) FindAwaitingReview(ctx context.Context, offset, limit int) ([]*duplicate.Flag, int64, error) {
+	query := r.db.WithContext(ctx).Model(&DuplicateFlagModel{}).
+		Where("status = ? OR appeal = ?", string(duplicate.StatusOpen), string(duplicate.AppealPending))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var models []DuplicateFlagModel
+	if err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	flags := make([]*duplicate.Flag, 0, len(models))
+	for _, m := range models {
+		flags = append(flags, duplicateFlagFromModel(m))
+	}
+	return flags, total, nil
+}
+
+// Update saves a flag's verdict and appeal
+func (r *DuplicateRepository) Update(ctx context.Context, f *duplicate.Flag) error {
+	m := duplicateFlagToModel(f)
+	return r.db.WithContext(ctx).Save(&m).Error
+}
+
+func (r *DuplicateRepository) first(query *gorm.DB) (*duplicate.Flag, error) {
+	var m DuplicateFlagModel
+	err := query.First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return duplicateFlagFromModel(m), nil
+}
+
+func duplicateFlagToModel(f *duplicate.Flag) DuplicateFlagModel {
+	return DuplicateFlagModel{
+		ID:                f.ID,
+		RecipeID:          f.RecipeID,
+		OriginalID:        f.OriginalID,
+		AuthorID:          f.AuthorID,
+		Similarity:        f.Similarity,
+		SharedIngredients: f.SharedIngredients,
+		SharedSteps:       f.SharedSteps,
+		Archived:          f.Archived,
+		Status:            string(f.Status),
+		ResolvedBy:        f.ResolvedBy,
+		Resolution:        f.Resolution,
+		ResolvedAt:        f.ResolvedAt,
+		Appeal:            string(f.Appeal),
+		AppealNote:        f.AppealNote,
+		AppealedAt:        f.AppealedAt,
+		AppealDecidedBy:   f.AppealDecidedBy,
+		AppealDecidedAt:   f.AppealDecidedAt,
+		CreatedAt:         f.CreatedAt,
+	}
+}
+
+func duplicateFlagFromModel(m DuplicateFlagModel) *duplicate.Flag {
+	return &duplicate.Flag{
+		ID:                m.ID,
+		RecipeID:          m.RecipeID,
+		OriginalID:        m.OriginalID,
+		AuthorID:          m.AuthorID,
+		Similarity:        m.Similarity,
+		SharedIngredients: m.SharedIngredients,
+		SharedSteps:       m.SharedSteps,
+		Archived:          m.Archived,
+		Status:            duplicate.Status(m.Status),
+		ResolvedBy:        m.ResolvedBy,
+		Resolution:        m.Resolution,
+		ResolvedAt:        m.ResolvedAt,
+		Appeal:            duplicate.Appeal(m.Appeal),
+		AppealNote:        m.AppealNote,
+		AppealedAt:        m.AppealedAt,
+		AppealDecidedBy:   m.AppealDecidedBy,
+		AppealDecidedAt:   m.AppealDecidedAt,
+		CreatedAt:         m.CreatedAt,
+	}
+}
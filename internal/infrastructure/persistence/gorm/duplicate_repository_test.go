@@ -0,0 +1,84 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/duplicate"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateRepositoryQueuesEachRecipeOnce(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&DuplicateCheckModel{}))
+	repo := NewDuplicateRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	first, second := uuid.New(), uuid.New()
+
+	require.NoError(t, repo.EnqueueCheck(ctx, second, now.Add(time.Minute)))
+	require.NoError(t, repo.EnqueueCheck(ctx, first, now))
+	require.NoError(t, repo.EnqueueCheck(ctx, second, now.Add(-time.Hour)), "a queued recipe is queued again without error")
+
+	due, err := repo.DueChecks(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{first, second}, due, "longest waiting first, keeping the first place")
+
+	require.NoError(t, repo.CompleteCheck(ctx, first))
+	due, err = repo.DueChecks(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{second}, due)
+}
+
+func TestDuplicateRepositoryListsFlagsAwaitingReview(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&DuplicateFlagModel{}))
+	repo := NewDuplicateRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	author, moderator := uuid.New(), uuid.New()
+
+	newFlag := func(at time.Time) *duplicate.Flag {
+		f := duplicate.NewFlag(uuid.New(), uuid.New(), author, 0.95, []string{"Flour"}, []string{"Bake for 20 minutes"}, false, at)
+		require.NoError(t, repo.Create(ctx, f))
+		return f
+	}
+	appealed := newFlag(now)
+	dismissed := newFlag(now.Add(time.Minute))
+	open := newFlag(now.Add(2 * time.Minute))
+
+	require.NoError(t, appealed.Close(duplicate.StatusUpheld, moderator, "Copied", now))
+	require.NoError(t, appealed.FileAppeal(author, "My grandmother's recipe", now))
+	require.NoError(t, repo.Update(ctx, appealed))
+	require.NoError(t, dismissed.Close(duplicate.StatusDismissed, moderator, "", now))
+	require.NoError(t, repo.Update(ctx, dismissed))
+
+	flags, total, err := repo.FindAwaitingReview(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	require.Len(t, flags, 2)
+	assert.Equal(t, appealed.ID, flags[0].ID, "oldest first")
+	assert.Equal(t, duplicate.AppealPending, flags[0].Appeal)
+	assert.Equal(t, "My grandmother's recipe", flags[0].AppealNote)
+	assert.Equal(t, []string{"Flour"}, flags[0].SharedIngredients)
+	assert.Equal(t, []string{"Bake for 20 minutes"}, flags[0].SharedSteps)
+	assert.Equal(t, open.ID, flags[1].ID)
+
+	has, err := repo.HasFlag(ctx, open.RecipeID, open.OriginalID)
+	require.NoError(t, err)
+	assert.True(t, has)
+	has, err = repo.HasFlag(ctx, open.RecipeID, appealed.OriginalID)
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	latest, err := repo.FindLatestForRecipe(ctx, dismissed.RecipeID)
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, duplicate.StatusDismissed, latest.Status)
+	missing, err := repo.FindLatestForRecipe(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
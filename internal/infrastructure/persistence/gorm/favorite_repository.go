@@ -0,0 +1,60 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FavoriteRepository implements outbound.FavoriteRepository using GORM
+type FavoriteRepository struct {
+	db *gorm.DB
+}
+
+// NewFavoriteRepository creates a new favorite repository
+func NewFavoriteRepository(db *gorm.DB) outbound.FavoriteRepository {
+	return &FavoriteRepository{db: db}
+}
+
+// Add saves a recipe for the user, once
+func (r *FavoriteRepository) Add(ctx context.Context, userID, recipeID uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&FavoriteModel{UserID: userID, RecipeID: recipeID, CreatedAt: at})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Remove drops a saved recipe
+func (r *FavoriteRepository) Remove(ctx context.Context, userID, recipeID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND recipe_id = ?", userID, recipeID).
+		Delete(&FavoriteModel{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Count is how many recipes the user saved
+func (r *FavoriteRepository) Count(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&FavoriteModel{}).Where("user_id = ?", userID).Count(&count).Error
+	return int(count), err
+}
+
+// List returns the user's saved recipes, oldest first
+func (r *FavoriteRepository) List(ctx context.Context, userID uuid.UUID) ([]outbound.Favorite, error) {
+	var models []FavoriteModel
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	favorites := make([]outbound.Favorite, len(models))
+	for i, model := range models {
+		favorites[i] = outbound.Favorite{RecipeID: model.RecipeID, SavedAt: model.CreatedAt}
+	}
+	return favorites, nil
+}
@@ -0,0 +1,63 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFavoritesFilterSearchMostRecentlySavedFirst(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&FavoriteModel{}))
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	repo := NewFavoriteRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	ids := make([]uuid.UUID, 3)
+	for i, title := range []string{"Soup", "Bread", "Salad"} {
+		ids[i] = uuid.New()
+		require.NoError(t, db.Create(&RecipeModel{ID: ids[i], Title: title, AuthorID: author.ID, Status: "published", CreatedAt: now}).Error)
+	}
+
+	added, err := repo.Add(ctx, author.ID, ids[0], now)
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = repo.Add(ctx, author.ID, ids[0], now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, added, "saving twice keeps the first")
+	_, err = repo.Add(ctx, author.ID, ids[1], now.Add(time.Minute))
+	require.NoError(t, err)
+	_, err = repo.Add(ctx, uuid.New(), ids[2], now)
+	require.NoError(t, err)
+
+	count, err := repo.Count(ctx, author.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	recipes, total, err := NewRecipeRepository(db).Search(ctx, outbound.SearchCriteria{FavoritedBy: &author.ID, OrderBy: "saved", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, recipes, 2)
+	assert.Equal(t, "Bread", recipes[0].Title())
+	assert.Equal(t, "Soup", recipes[1].Title())
+
+	saved, err := repo.List(ctx, author.ID)
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	assert.Equal(t, ids[0], saved[0].RecipeID, "oldest first")
+	assert.Equal(t, ids[1], saved[1].RecipeID)
+
+	removed, err := repo.Remove(ctx, author.ID, ids[1])
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = repo.Remove(ctx, author.ID, ids[1])
+	require.NoError(t, err)
+	assert.False(t, removed)
+}
@@ -0,0 +1,81 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FollowRepository implements outbound.FollowRepository using GORM
+type FollowRepository struct {
+	db *gorm.DB
+}
+
+// NewFollowRepository creates a new follow repository
+func NewFollowRepository(db *gorm.DB) outbound.FollowRepository {
+	return &FollowRepository{db: db}
+}
+
+// Follow records a follow, once
+func (r *FollowRepository) Follow(ctx context.Context, followerID, followingID uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&UserFollowModel{FollowerID: followerID, FollowingID: followingID, CreatedAt: at})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Unfollow drops a follow
+func (r *FollowRepository) Unfollow(ctx context.Context, followerID, followingID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("follower_id = ? AND following_id = ?", followerID, followingID).
+		Delete(&UserFollowModel{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// IsFollowing reports whether followerID follows followingID
+func (r *FollowRepository) IsFollowing(ctx context.Context, followerID, followingID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&UserFollowModel{}).
+		Where("follower_id = ? AND following_id = ?", followerID, followingID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Counts returns the follower and following counts in one query
+func (r *FollowRepository) Counts(ctx context.Context, userID uuid.UUID) (int, int, error) {
+	var counts struct {
+		Followers int
+		Following int
+	}
+	err := r.db.WithContext(ctx).Model(&UserFollowModel{}).
+		Select("COALESCE(SUM(CASE WHEN following_id = ? THEN 1 ELSE 0 END), 0) AS followers, "+
+			"COALESCE(SUM(CASE WHEN follower_id = ? THEN 1 ELSE 0 END), 0) AS following", userID, userID).
+		Where("following_id = ? OR follower_id = ?", userID, userID).
+		Scan(&counts).Error
+	return counts.Followers, counts.Following, err
+}
+
+// Followers lists who follows userID, most recent first
+func (r *FollowRepository) Followers(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
+	return r.list(ctx, "follower_id", "following_id", userID, offset, limit)
+}
+
+// Following lists whom userID follows, most recent first
+func (r *FollowRepository) Following(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
+	return r.list(ctx, "following_id", "follower_id", userID, offset, limit)
+}
+
+func (r *FollowRepository) list(ctx context.Context, column, by string, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&UserFollowModel{}).
+		Where(by+" = ?", userID).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Pluck(column, &ids).Error
+	return ids, err
+}
@@ -0,0 +1,53 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowCountsAndListsMostRecentFirst(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&UserFollowModel{}))
+	repo := NewFollowRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+	chef, ada, bo := uuid.New(), uuid.New(), uuid.New()
+
+	followed, err := repo.Follow(ctx, ada, chef, now)
+	require.NoError(t, err)
+	assert.True(t, followed)
+	followed, err = repo.Follow(ctx, ada, chef, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, followed, "following twice keeps the first")
+	_, err = repo.Follow(ctx, bo, chef, now.Add(time.Minute))
+	require.NoError(t, err)
+	_, err = repo.Follow(ctx, chef, ada, now)
+	require.NoError(t, err)
+
+	followers, following, err := repo.Counts(ctx, chef)
+	require.NoError(t, err)
+	assert.Equal(t, 2, followers)
+	assert.Equal(t, 1, following)
+
+	ids, err := repo.Followers(ctx, chef, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{bo, ada}, ids)
+	ids, err = repo.Following(ctx, chef, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{ada}, ids)
+
+	removed, err := repo.Unfollow(ctx, ada, chef)
+	require.NoError(t, err)
+	assert.True(t, removed)
+	is, err := repo.IsFollowing(ctx, ada, chef)
+	require.NoError(t, err)
+	assert.False(t, is)
+	followers, _, err = repo.Counts(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Zero(t, followers)
+}
@@ -0,0 +1,203 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GuestRepository implements outbound.GuestRepository using GORM
+type GuestRepository struct {
+	db *gorm.DB
+}
+
+// NewGuestRepository creates a new guest repository
+func NewGuestRepository(db *gorm.DB) outbound.GuestRepository {
+	return &GuestRepository{db: db}
+}
+
+// CreateSession stores a session
+func (r *GuestRepository) CreateSession(ctx context.Context, session outbound.GuestSession) error {
+	model := GuestSessionModel{
+		ID:        session.ID,
+		TokenHash: session.TokenHash,
+		IPHash:    session.IPHash,
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: session.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// FindSession finds a session, returning nil when it does not exist
+func (r *GuestRepository) FindSession(ctx context.Context, tokenHash string) (*outbound.GuestSession, error) {
+	var model GuestSessionModel
+
+	result := r.db.WithContext(ctx).First(&model, "token_hash = ?", tokenHash)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return &outbound.GuestSession{
+		ID:        model.ID,
+		TokenHash: model.TokenHash,
+		IPHash:    model.IPHash,
+		Messages:  model.Messages,
+		Recipes:   model.Recipes,
+		ExpiresAt: model.ExpiresAt,
+		ClaimedBy: model.ClaimedBy,
+		ClaimedAt: model.ClaimedAt,
+		CreatedAt: model.CreatedAt,
+	}, nil
+}
+
+// CountSessionsSince counts the sessions started from an address since a time
+func (r *GuestRepository) CountSessionsSince(ctx context.Context, ipHash string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&GuestSessionModel{}).
+		Where("ip_hash = ? AND created_at > ?", ipHash, since).
+		Count(&count).Error
+	return count, err
+}
+
+// RecordMessage counts a chat message in one statement, so concurrent
+// messages cannot pass the limit together
+func (r *GuestRepository) RecordMessage(ctx context.Context, guestID uuid.UUID, max int) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&GuestSessionModel{}).
+		Where("id = ? AND messages < ?", guestID, max).
+		Update("messages", gorm.Expr("messages + 1"))
+	return result.RowsAffected == 1, result.Error
+}
+
+// SaveRecipe reserves a slot on the session and stores the recipe in one
+// transaction
+func (r *GuestRepository) SaveRecipe(ctx context.Context, guestRecipe outbound.GuestRecipe, max int) (bool, error) {
+	saved := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&GuestSessionModel{}).
+			Where("id = ? AND recipes < ? AND claimed_by IS NULL", guestRecipe.GuestID, max).
+			Update("recipes", gorm.Expr("recipes + 1"))
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		model := GuestRecipeModel{
+			ID:          guestRecipe.ID,
+			GuestID:     guestRecipe.GuestID,
+			Title:       guestRecipe.Title,
+			Description: guestRecipe.Description,
+			Ingredients: StringSlice(guestRecipe.Ingredients),
+			Directions:  StringSlice(guestRecipe.Directions),
+			Servings:    guestRecipe.Servings,
+			PrepTime:    guestRecipe.PrepTime,
+			CookTime:    guestRecipe.CookTime,
+			Tags:        StringSlice(guestRecipe.Tags),
+			CreatedAt:   guestRecipe.CreatedAt,
+		}
+		if err := tx.Create(&model).Error; err != nil {
+			return err
+		}
+		saved = true
+		return nil
+	})
+	return saved, err
+}
+
+// ListRecipes lists a guest's recipes, oldest first
+func (r *GuestRepository) ListRecipes(ctx context.Context, guestID uuid.UUID) ([]outbound.GuestRecipe, error) {
+	var models []GuestRecipeModel
+	if err := r.db.WithContext(ctx).
+		Where("guest_id = ?", guestID).
+		Order("created_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	recipes := make([]outbound.GuestRecipe, 0, len(models))
+	for _, m := range models {
+		recipes = append(recipes, outbound.GuestRecipe{
+			ID:          m.ID,
+			GuestID:     m.GuestID,
+			Title:       m.Title,
+			Description: m.Description,
+			Ingredients: []string(m.Ingredients),
+			Directions:  []string(m.Directions),
+			Servings:    m.Servings,
+			PrepTime:    m.PrepTime,
+			CookTime:    m.CookTime,
+			Tags:        []string(m.Tags),
+			CreatedAt:   m.CreatedAt,
+		})
+	}
+	return recipes, nil
+}
+
+// DeleteRecipe deletes a recipe and frees its slot
+func (r *GuestRepository) DeleteRecipe(ctx context.Context, guestID, recipeID uuid.UUID) (bool, error) {
+	deleted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND guest_id = ?", recipeID, guestID).Delete(&GuestRecipeModel{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Model(&GuestSessionModel{}).
+			Where("id = ? AND recipes > 0", guestID).
+			Update("recipes", gorm.Expr("recipes - 1")).Error; err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
+// Claim moves a guest's recipes to a user. The conditional update on the
+// session decides a race between two claims; the loser's transaction
+// stores nothing.
+func (r *GuestRepository) Claim(ctx context.Context, guestID, userID uuid.UUID, recipes []*recipe.Recipe, now time.Time) (bool, error) {
+	claimed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&GuestSessionModel{}).
+			Where("id = ? AND claimed_by IS NULL AND expires_at > ?", guestID, now).
+			Updates(map[string]interface{}{"claimed_by": userID, "claimed_at": now, "recipes": 0})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		for _, entity := range recipes {
+			if err := tx.Create(RecipeToModel(entity)).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("guest_id = ?", guestID).Delete(&GuestRecipeModel{}).Error; err != nil {
+			return err
+		}
+		claimed = true
+		return nil
+	})
+	return claimed, err
+}
+
+// DeleteExpired deletes expired sessions and their recipes
+func (r *GuestRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&GuestSessionModel{}).Select("id").Where("expires_at < ?", before)
+		if err := tx.Where("guest_id IN (?)", expired).Delete(&GuestRecipeModel{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("expires_at < ?", before).Delete(&GuestSessionModel{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
@@ -0,0 +1,67 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuestRepositoryCapsAndClaimsOnce(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&GuestSessionModel{}, &GuestRecipeModel{}))
+	repo := NewGuestRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	session := outbound.GuestSession{ID: uuid.New(), TokenHash: "hash", IPHash: "ip", ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+	require.NoError(t, repo.CreateSession(ctx, session))
+
+	for i, want := range []bool{true, true, false} {
+		saved, err := repo.SaveRecipe(ctx, outbound.GuestRecipe{
+			ID: uuid.New(), GuestID: session.ID, Title: "Soup", Ingredients: []string{"1 onion"}, CreatedAt: now,
+		}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, want, saved, "recipe %d", i)
+	}
+	saved, err := repo.ListRecipes(ctx, session.ID)
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	assert.Equal(t, []string{"1 onion"}, saved[0].Ingredients)
+
+	var owner UserModel
+	require.NoError(t, db.First(&owner).Error)
+	entity, err := recipe.NewRecipe("Soup", "", owner.ID)
+	require.NoError(t, err)
+
+	claimed, err := repo.Claim(ctx, session.ID, owner.ID, []*recipe.Recipe{entity}, now)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = repo.Claim(ctx, session.ID, uuid.New(), nil, now)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	var stored RecipeModel
+	require.NoError(t, db.First(&stored, "id = ?", entity.ID()).Error)
+	assert.Equal(t, owner.ID, stored.AuthorID)
+	left, err := repo.ListRecipes(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Empty(t, left)
+
+	found, err := repo.FindSession(ctx, "hash")
+	require.NoError(t, err)
+	require.NotNil(t, found.ClaimedBy)
+	assert.Equal(t, owner.ID, *found.ClaimedBy)
+
+	expired, err := repo.DeleteExpired(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), expired)
+	found, err = repo.FindSession(ctx, "hash")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
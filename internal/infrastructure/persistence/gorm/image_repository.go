@@ -0,0 +1,61 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ImageRepository implements outbound.ImageRepository using GORM
+type ImageRepository struct {
+	db *gorm.DB
+}
+
+// NewImageRepository creates a new image repository
+func NewImageRepository(db *gorm.DB) outbound.ImageRepository {
+	return &ImageRepository{db: db}
+}
+
+// Create stores an image record
+func (r *ImageRepository) Create(ctx context.Context, image *outbound.StoredImage) error {
+	model := ImageModel{
+		ID:          image.ID,
+		OwnerID:     image.OwnerID,
+		BlobKey:     image.BlobKey,
+		ContentType: image.ContentType,
+		Width:       image.Width,
+		Height:      image.Height,
+		SizeBytes:   image.SizeBytes,
+		SHA256:      image.SHA256,
+		CreatedAt:   image.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// FindByID finds an image, returning nil when it does not exist
+func (r *ImageRepository) FindByID(ctx context.Context, id uuid.UUID) (*outbound.StoredImage, error) {
+	var model ImageModel
+
+	result := r.db.WithContext(ctx).First(&model, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return &outbound.StoredImage{
+		ID:          model.ID,
+		OwnerID:     model.OwnerID,
+		BlobKey:     model.BlobKey,
+		ContentType: model.ContentType,
+		Width:       model.Width,
+		Height:      model.Height,
+		SizeBytes:   model.SizeBytes,
+		SHA256:      model.SHA256,
+		CreatedAt:   model.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/nutrition"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IngredientNutritionRepository implements
+// outbound.IngredientNutritionRepository using GORM
+type IngredientNutritionRepository struct {
+	db *gorm.DB
+}
+
+// NewIngredientNutritionRepository creates a new ingredient nutrition repository
+func NewIngredientNutritionRepository(db *gorm.DB) outbound.IngredientNutritionRepository {
+	return &IngredientNutritionRepository{db: db}
+}
+
+// List returns the stored foods in match order
+func (r *IngredientNutritionRepository) List(ctx context.Context) ([]nutrition.Food, error) {
+	var models []IngredientNutritionModel
+	if err := r.db.WithContext(ctx).Order("position, name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	foods := make([]nutrition.Food, 0, len(models))
+	for _, m := range models {
+		food, err := nutrition.NewFood(m.Name, m.Keywords, nutrition.Facts{
+			Calories:      m.Calories,
+			Protein:       m.Protein,
+			Carbohydrates: m.Carbohydrates,
+			Fat:           m.Fat,
+			Fiber:         m.Fiber,
+			Sugar:         m.Sugar,
+			Sodium:        m.Sodium,
+			Cholesterol:   m.Cholesterol,
+		}, m.PricePerKg)
+		if err != nil {
+			return nil, fmt.Errorf("ingredient nutrition %q: %w", m.Name, err)
+		}
+		foods = append(foods, food)
+	}
+	return foods, nil
+}
+
+// SeedMissing inserts the foods whose names are not stored yet, keeping
+// their order as positions
+func (r *IngredientNutritionRepository) SeedMissing(ctx context.Context, foods []nutrition.Food, source string) (int, error) {
+	now := time.Now().UTC()
+	models := make([]IngredientNutritionModel, len(foods))
+	for i, food := range foods {
+		models[i] = IngredientNutritionModel{
+			Name:          food.Name,
+			Keywords:      food.Keywords(),
+			Position:      (i + 1) * 10,
+			Calories:      food.Per100g.Calories,
+			Protein:       food.Per100g.Protein,
+			Carbohydrates: food.Per100g.Carbohydrates,
+			Fat:           food.Per100g.Fat,
+			Fiber:         food.Per100g.Fiber,
+			Sugar:         food.Per100g.Sugar,
+			Sodium:        food.Per100g.Sodium,
+			Cholesterol:   food.Per100g.Cholesterol,
+			PricePerKg:    food.PricePerKg,
+			Source:        source,
+			UpdatedAt:     now,
+		}
+	}
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(&models)
+	return int(result.RowsAffected), result.Error
+}
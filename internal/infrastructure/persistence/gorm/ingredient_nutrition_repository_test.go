@@ -0,0 +1,39 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/nutrition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngredientNutritionSeedKeepsEditsAndOrder(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&IngredientNutritionModel{}))
+	repo := NewIngredientNutritionRepository(db)
+	ctx := context.Background()
+
+	reference := nutrition.Reference()
+	added, err := repo.SeedMissing(ctx, reference, "USDA SR Legacy")
+	require.NoError(t, err)
+	assert.Equal(t, len(reference), added)
+
+	require.NoError(t, db.Model(&IngredientNutritionModel{}).Where("name = ?", "salt").Update("price_per_kg", 2.5).Error)
+	added, err = repo.SeedMissing(ctx, reference, "USDA SR Legacy")
+	require.NoError(t, err)
+	assert.Zero(t, added)
+
+	foods, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, foods, len(reference))
+	table := nutrition.NewTable(foods)
+
+	stock, ok := table.Lookup("chicken stock")
+	require.True(t, ok)
+	assert.Equal(t, "stock", stock.Name, "stock is matched before chicken")
+	salt, ok := table.Lookup("sea salt")
+	require.True(t, ok)
+	assert.Equal(t, 2.5, salt.PricePerKg)
+}
@@ -0,0 +1,93 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/substitutes"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IngredientSubstituteRepository implements
+// outbound.IngredientSubstituteRepository using GORM
+type IngredientSubstituteRepository struct {
+	db *gorm.DB
+}
+
+// NewIngredientSubstituteRepository creates a new ingredient substitute repository
+func NewIngredientSubstituteRepository(db *gorm.DB) outbound.IngredientSubstituteRepository {
+	return &IngredientSubstituteRepository{db: db}
+}
+
+// List returns the stored substitutes, each ingredient's in position order
+func (r *IngredientSubstituteRepository) List(ctx context.Context) ([]substitutes.Substitute, error) {
+	var models []IngredientSubstituteModel
+	if err := r.db.WithContext(ctx).Order("ingredient, position, name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	subs := make([]substitutes.Substitute, 0, len(models))
+	for _, m := range models {
+		contains := make([]substitutes.Trait, 0, len(m.Contains))
+		for _, name := range m.Contains {
+			if trait, ok := substitutes.ParseTrait(name); ok {
+				contains = append(contains, trait)
+			}
+		}
+		s, err := substitutes.NewSubstitute(m.Ingredient, m.Name, m.Ratio, m.Notes, contains...)
+		if err != nil {
+			continue
+		}
+		s.Source = m.Source
+		subs = append(subs, s)
+	}
+	return subs, nil
+}
+
+// SeedMissing inserts the substitutes not stored for their ingredient yet,
+// after those already there
+func (r *IngredientSubstituteRepository) SeedMissing(ctx context.Context, subs []substitutes.Substitute, source string) (int, error) {
+	if len(subs) == 0 {
+		return 0, nil
+	}
+
+	var counts []struct {
+		Ingredient string
+		Count      int
+	}
+	if err := r.db.WithContext(ctx).Model(&IngredientSubstituteModel{}).
+		Select("ingredient, COUNT(*) AS count").Group("ingredient").Scan(&counts).Error; err != nil {
+		return 0, err
+	}
+	next := make(map[string]int, len(counts))
+	for _, c := range counts {
+		next[c.Ingredient] = c.Count
+	}
+
+	now := time.Now().UTC()
+	models := make([]IngredientSubstituteModel, len(subs))
+	for i, s := range subs {
+		next[s.Ingredient]++
+		contains := make(StringSlice, len(s.Contains))
+		for j, trait := range s.Contains {
+			contains[j] = string(trait)
+		}
+		models[i] = IngredientSubstituteModel{
+			Ingredient: s.Ingredient,
+			Name:       s.Name,
+			Ratio:      s.Ratio,
+			Notes:      s.Notes,
+			Contains:   contains,
+			Position:   next[s.Ingredient] * 10,
+			Source:     source,
+			UpdatedAt:  now,
+		}
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "ingredient"}, {Name: "name"}}, DoNothing: true}).
+		Create(&models)
+	return int(result.RowsAffected), result.Error
+}
@@ -0,0 +1,44 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/substitutes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngredientSubstituteSeedKeepsOrderAndTraits(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&IngredientSubstituteModel{}))
+	repo := NewIngredientSubstituteRepository(db)
+	ctx := context.Background()
+
+	reference := substitutes.Reference()
+	added, err := repo.SeedMissing(ctx, reference, "built-in")
+	require.NoError(t, err)
+	assert.Equal(t, len(reference), added)
+
+	added, err = repo.SeedMissing(ctx, reference, "built-in")
+	require.NoError(t, err)
+	assert.Zero(t, added)
+
+	lard, err := substitutes.NewSubstitute("butter", "lard", "3/4 cup per cup", "", substitutes.TraitPork)
+	require.NoError(t, err)
+	added, err = repo.SeedMissing(ctx, []substitutes.Substitute{lard}, "llama3.2")
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	subs, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, subs, len(reference)+1)
+
+	_, butter, ok := substitutes.NewTable(subs).Lookup("butter")
+	require.True(t, ok)
+	assert.Equal(t, "olive oil", butter[0].Name, "seeded order is kept")
+	last := butter[len(butter)-1]
+	assert.Equal(t, "lard", last.Name, "later additions come last")
+	assert.Equal(t, []substitutes.Trait{substitutes.TraitPork}, last.Contains)
+	assert.Equal(t, "llama3.2", last.Source)
+}
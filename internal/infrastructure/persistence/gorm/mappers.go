@@ -2,9 +2,14 @@
 package gorm
 
 import (
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"github.com/alchemorsel/v3/internal/domain/ai"
 	"github.com/alchemorsel/v3/internal/domain/recipe"
 	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/google/uuid"
 )
 
 // UserToModel converts a domain user to a GORM model
@@ -13,6 +18,7 @@ func UserToModel(u *user.User) *UserModel {
 		ID:           u.ID(),
 		Email:        u.Email(),
 		Name:         u.Name(),
+		PasswordHash: u.PasswordHash(),
 		IsActive:     u.IsActive(),
 		IsVerified:   u.IsVerified(),
 		Role:         string(u.Role()),
@@ -43,15 +49,19 @@ func UserToModel(u *user.User) *UserModel {
 		}
 
 		model.Preferences = &UserPreferencesModel{
-			DietaryRestrictions: dietaryRestrictions,
-			Allergies:           prefs.Allergies,
-			PreferredCuisines:   prefs.PreferredCuisines,
-			DislikedIngredients: prefs.DislikedIngredients,
-			MeasurementSystem:   string(prefs.MeasurementSystem),
-			Language:            prefs.Language,
-			Timezone:            prefs.Timezone,
-			EmailNotifications:  prefs.EmailNotifications,
-			PushNotifications:   prefs.PushNotifications,
+			DietaryRestrictions:    dietaryRestrictions,
+			Allergies:              prefs.Allergies,
+			PreferredCuisines:      prefs.PreferredCuisines,
+			DislikedIngredients:    prefs.DislikedIngredients,
+			MeasurementSystem:      string(prefs.MeasurementSystem),
+			Language:               prefs.Language,
+			Timezone:               prefs.Timezone,
+			EmailNotifications:     prefs.EmailNotifications,
+			PushNotifications:      prefs.PushNotifications,
+			Theme:                  string(prefs.Theme),
+			DisablePersonalization: prefs.DisablePersonalization,
+			PrivateProfile:         prefs.PrivateProfile,
+			MutedNotifications:     prefs.MutedNotifications,
 		}
 	}
 
@@ -60,16 +70,64 @@ func UserToModel(u *user.User) *UserModel {
 
 // ModelToUser converts a GORM model to a domain user
 func ModelToUser(model *UserModel) (*user.User, error) {
-	// Create new user - we need to access private fields via reflection or constructor
-	// For now, we'll use the constructor and then manually set fields
-	u, err := user.NewUser(model.Email, model.Name, "dummy") // Password won't be used
+	u := user.ReconstructUser(
+		model.ID,
+		model.Email,
+		model.Name,
+		model.PasswordHash,
+		model.IsActive,
+		model.IsVerified,
+		user.UserRole(model.Role),
+		model.CreatedAt,
+		model.UpdatedAt,
+		model.LastLoginAt,
+	)
+
+	if model.Profile != nil {
+		u.UpdateProfile(&user.UserProfile{
+			FirstName:    model.Profile.FirstName,
+			LastName:     model.Profile.LastName,
+			Avatar:       model.Profile.Avatar,
+			Bio:          model.Profile.Bio,
+			Location:     model.Profile.Location,
+			Website:      model.Profile.Website,
+			Birthday:     model.Profile.Birthday,
+			CookingLevel: user.CookingLevel(model.Profile.CookingLevel),
+		})
+	}
+	if model.Preferences != nil {
+		u.UpdatePreferences(modelToPreferences(model.Preferences))
+	}
+	return u, nil
+}
+
+// modelToPreferences maps stored preferences back onto the domain type
+func modelToPreferences(model *UserPreferencesModel) *user.UserPreferences {
+	dietaryRestrictions := make([]user.DietaryRestriction, len(model.DietaryRestrictions))
+	for i, dr := range model.DietaryRestrictions {
+		dietaryRestrictions[i] = user.DietaryRestriction(dr)
+	}
+
+	theme, err := user.ParseTheme(model.Theme)
 	if err != nil {
-		return nil, err
+		theme = user.ThemeSystem
 	}
 
-	// We would need setters or reflection to properly map back
-	// For now, this is a simplified approach
-	return u, nil
+	return &user.UserPreferences{
+		DietaryRestrictions:    dietaryRestrictions,
+		Allergies:              model.Allergies,
+		PreferredCuisines:      model.PreferredCuisines,
+		DislikedIngredients:    model.DislikedIngredients,
+		MeasurementSystem:      user.MeasurementSystem(model.MeasurementSystem),
+		Language:               model.Language,
+		Timezone:               model.Timezone,
+		EmailNotifications:     model.EmailNotifications,
+		PushNotifications:      model.PushNotifications,
+		Theme:                  theme,
+		DisablePersonalization: model.DisablePersonalization,
+		PrivateProfile:         model.PrivateProfile,
+		MutedNotifications:     model.MutedNotifications,
+	}
 }
 
 // RecipeToModel converts a domain recipe to a GORM model
@@ -86,58 +144,214 @@ func RecipeToModel(r *recipe.Recipe) *RecipeModel {
 	}
 
 	return &RecipeModel{
-		ID:               r.ID(),
-		Version:          r.Version(),
-		Title:            r.Title(),
-		Description:      r.Description(),
-		AuthorID:         r.AuthorID(),
-		Ingredients:      JSONField(map[string]interface{}{"data": ingredientsJSON}),
-		Instructions:     JSONField(map[string]interface{}{"data": instructionsJSON}),
-		NutritionInfo:    JSONField(nutritionJSON),
-		Cuisine:          string(r.Cuisine()),
-		Category:         string(r.Category()),
-		Difficulty:       string(r.Difficulty()),
-		Tags:             tags,
-		PrepTimeMinutes:  int(r.PrepTime().Minutes()),
-		CookTimeMinutes:  int(r.CookTime().Minutes()),
-		TotalTimeMinutes: int(r.TotalTime().Minutes()),
-		Servings:         r.Servings(),
-		Calories:         r.Calories(),
-		AIGenerated:      r.IsAIGenerated(),
-		AIPrompt:         r.AIPrompt(),
-		AIModel:          r.AIModel(),
-		Likes:            r.Likes(),
-		Views:            r.Views(),
-		AverageRating:    r.AverageRating(),
-		Images:           JSONField(map[string]interface{}{"data": imagesJSON}),
-		Videos:           JSONField(map[string]interface{}{"data": videosJSON}),
-		Status:           string(r.Status()),
-		PublishedAt:      r.PublishedAt(),
-		CreatedAt:        r.CreatedAt(),
-		UpdatedAt:        r.UpdatedAt(),
+		ID:                 r.ID(),
+		Version:            r.Version(),
+		Title:              r.Title(),
+		Description:        r.Description(),
+		AuthorID:           r.AuthorID(),
+		Language:           r.Language(),
+		Ingredients:        JSONField(map[string]interface{}{"data": ingredientsJSON}),
+		Instructions:       JSONField(map[string]interface{}{"data": instructionsJSON}),
+		NutritionInfo:      JSONField(nutritionJSON),
+		Allergens:          JSONField(convertAllergensToJSON(r.Allergens())),
+		Cuisine:            string(r.Cuisine()),
+		Category:           string(r.Category()),
+		Difficulty:         string(r.Difficulty()),
+		Tags:               tags,
+		SearchVector:       recipeSearchDocument(r),
+		PrepTimeMinutes:    int(r.PrepTime().Minutes()),
+		CookTimeMinutes:    int(r.CookTime().Minutes()),
+		TotalTimeMinutes:   int(r.TotalTime().Minutes()),
+		Servings:           r.Servings(),
+		Calories:           r.Calories(),
+		AIGenerated:        r.IsAIGenerated(),
+		AIPrompt:           r.AIPrompt(),
+		AIModel:            r.AIModel(),
+		Likes:              r.Likes(),
+		Views:              r.Views(),
+		Forks:              r.Forks(),
+		ForkedFromID:       r.ForkedFromID(),
+		AverageRating:      r.AverageRating(),
+		Images:             JSONField(map[string]interface{}{"data": imagesJSON}),
+		Videos:             JSONField(map[string]interface{}{"data": videosJSON}),
+		Status:             string(r.Status()),
+		PublishedAt:        r.PublishedAt(),
+		ScheduledPublishAt: r.ScheduledPublishAt(),
+		CreatedAt:          r.CreatedAt(),
+		UpdatedAt:          r.UpdatedAt(),
 	}
 }
 
 // ModelToRecipe converts a GORM model to a domain recipe
 func ModelToRecipe(model *RecipeModel) (*recipe.Recipe, error) {
-	// Create a basic recipe first
-	r, err := recipe.NewRecipe(model.Title, model.Description, model.AuthorID)
-	if err != nil {
-		return nil, err
-	}
-
-	// For a proper implementation, we would need to either:
-	// 1. Add setters to the domain entity, or
-	// 2. Use reflection to set private fields, or  
-	// 3. Create a constructor that accepts all fields
-	//
-	// For now, this simplified approach creates a basic recipe
-	// The main issue would be in updating existing recipes where
-	// we lose the social metrics (likes, views, ratings, etc.)
-	//
-	// TODO: Implement proper field mapping when domain entity allows it
-	
-	return r, nil
+	snapshot := recipe.Snapshot{
+		ID:                 model.ID,
+		Version:            model.Version,
+		Title:              model.Title,
+		Description:        model.Description,
+		AuthorID:           model.AuthorID,
+		Language:           model.Language,
+		NutritionInfo:      nutritionFromJSON(model.NutritionInfo),
+		Allergens:          allergensFromJSON(model.Allergens),
+		Cuisine:            recipe.CuisineType(model.Cuisine),
+		Category:           recipe.CategoryType(model.Category),
+		Difficulty:         recipe.DifficultyLevel(model.Difficulty),
+		Tags:               []string(model.Tags),
+		PrepTime:           time.Duration(model.PrepTimeMinutes) * time.Minute,
+		CookTime:           time.Duration(model.CookTimeMinutes) * time.Minute,
+		TotalTime:          time.Duration(model.TotalTimeMinutes) * time.Minute,
+		Servings:           model.Servings,
+		Calories:           model.Calories,
+		AIGenerated:        model.AIGenerated,
+		AIPrompt:           model.AIPrompt,
+		AIModel:            model.AIModel,
+		Likes:              model.Likes,
+		Views:              model.Views,
+		Forks:              model.Forks,
+		ForkedFromID:       model.ForkedFromID,
+		AverageRating:      model.AverageRating,
+		Status:             recipe.RecipeStatus(model.Status),
+		PublishedAt:        model.PublishedAt,
+		ScheduledPublishAt: model.ScheduledPublishAt,
+		CreatedAt:          model.CreatedAt,
+		UpdatedAt:          model.UpdatedAt,
+	}
+	if model.DeletedAt.Valid {
+		deletedAt := model.DeletedAt.Time
+		snapshot.DeletedAt = &deletedAt
+	}
+
+	var ingredients []storedIngredient
+	if err := decodeJSONList(model.Ingredients, &ingredients, "data", "ingredients"); err != nil {
+		return nil, fmt.Errorf("ingredients of recipe %s: %w", model.ID, err)
+	}
+	for _, ing := range ingredients {
+		snapshot.Ingredients = append(snapshot.Ingredients, recipe.Ingredient{
+			ID:       ing.ID,
+			Name:     ing.Name,
+			Amount:   ing.Amount,
+			Unit:     recipe.MeasurementUnit(ing.Unit),
+			Optional: ing.Optional,
+			Notes:    ing.Notes,
+		})
+	}
+
+	var instructions []storedInstruction
+	if err := decodeJSONList(model.Instructions, &instructions, "data", "instructions"); err != nil {
+		return nil, fmt.Errorf("instructions of recipe %s: %w", model.ID, err)
+	}
+	for _, inst := range instructions {
+		instruction := recipe.Instruction{
+			StepNumber:  inst.StepNumber,
+			Description: inst.Description,
+			Duration:    time.Duration(inst.Duration * float64(time.Minute)),
+			Images:      inst.Images,
+		}
+		if inst.Temperature != nil {
+			instruction.Temperature = &recipe.Temperature{
+				Value: inst.Temperature.Value,
+				Unit:  recipe.TemperatureUnit(inst.Temperature.Unit),
+			}
+		}
+		snapshot.Instructions = append(snapshot.Instructions, instruction)
+	}
+
+	var images []storedImage
+	if err := decodeJSONList(model.Images, &images, "data"); err != nil {
+		return nil, fmt.Errorf("images of recipe %s: %w", model.ID, err)
+	}
+	for _, img := range images {
+		snapshot.Images = append(snapshot.Images, recipe.Image{
+			ID:           img.ID,
+			URL:          img.URL,
+			ThumbnailURL: img.ThumbnailURL,
+			Caption:      img.Caption,
+			IsPrimary:    img.IsPrimary,
+			UploadedAt:   img.UploadedAt,
+		})
+	}
+
+	var videos []storedVideo
+	if err := decodeJSONList(model.Videos, &videos, "data"); err != nil {
+		return nil, fmt.Errorf("videos of recipe %s: %w", model.ID, err)
+	}
+	for _, video := range videos {
+		snapshot.Videos = append(snapshot.Videos, recipe.Video{
+			ID:           video.ID,
+			URL:          video.URL,
+			ThumbnailURL: video.ThumbnailURL,
+			Duration:     time.Duration(video.Duration * float64(time.Second)),
+			Caption:      video.Caption,
+			UploadedAt:   video.UploadedAt,
+		})
+	}
+
+	for _, rating := range model.Ratings {
+		snapshot.Ratings = append(snapshot.Ratings, recipe.Rating{
+			UserID:    rating.UserID,
+			Value:     rating.Value,
+			Comment:   rating.Comment,
+			CreatedAt: rating.CreatedAt,
+		})
+	}
+
+	return recipe.Reconstruct(snapshot), nil
+}
+
+// The stored forms of the lists RecipeToModel keeps under "data"; seeded
+// recipes keep them under the list's own name
+type storedIngredient struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Amount   float64   `json:"amount"`
+	Unit     string    `json:"unit"`
+	Optional bool      `json:"optional"`
+	Notes    string    `json:"notes"`
+}
+
+type storedInstruction struct {
+	StepNumber  int     `json:"step_number"`
+	Description string  `json:"description"`
+	Duration    float64 `json:"duration"`
+	Temperature *struct {
+		Value float64 `json:"value"`
+		Unit  string  `json:"unit"`
+	} `json:"temperature"`
+	Images []string `json:"images"`
+}
+
+type storedImage struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	Caption      string    `json:"caption"`
+	IsPrimary    bool      `json:"is_primary"`
+	UploadedAt   time.Time `json:"uploaded_at"`
+}
+
+type storedVideo struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	Duration     float64   `json:"duration"`
+	Caption      string    `json:"caption"`
+	UploadedAt   time.Time `json:"uploaded_at"`
+}
+
+// decodeJSONList reads the first list found under keys into out
+func decodeJSONList(field JSONField, out interface{}, keys ...string) error {
+	for _, key := range keys {
+		list, ok := field[key]
+		if !ok || list == nil {
+			continue
+		}
+		raw, err := json.Marshal(list)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(raw, out)
+	}
+	return nil
 }
 
 // AIRequestToModel converts a domain AI request to a GORM model
@@ -214,7 +428,61 @@ func convertNutritionToJSON(nutrition *recipe.NutritionInfo) map[string]interfac
 		"sugar":         nutrition.Sugar,
 		"sodium":        nutrition.Sodium,
 		"cholesterol":   nutrition.Cholesterol,
+		"coverage":      nutrition.Coverage,
+	}
+}
+
+// nutritionFromJSON reads what convertNutritionToJSON stores; recipes
+// saved before nutrition was computed have none
+func nutritionFromJSON(value JSONField) *recipe.NutritionInfo {
+	calories, ok := value["calories"].(float64)
+	if !ok {
+		return nil
+	}
+	number := func(key string) float64 {
+		v, _ := value[key].(float64)
+		return v
+	}
+	return &recipe.NutritionInfo{
+		Calories:      int(calories),
+		Protein:       number("protein"),
+		Carbohydrates: number("carbohydrates"),
+		Fat:           number("fat"),
+		Fiber:         number("fiber"),
+		Sugar:         number("sugar"),
+		Sodium:        number("sodium"),
+		Cholesterol:   number("cholesterol"),
+		Coverage:      number("coverage"),
+	}
+}
+
+func convertAllergensToJSON(label *recipe.AllergenLabel) map[string]interface{} {
+	if label == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"contains":    label.Contains,
+		"may_contain": label.MayContain,
+	}
+}
+
+// allergensFromJSON reads what convertAllergensToJSON stores; recipes
+// saved before labelling have none
+func allergensFromJSON(value JSONField) *recipe.AllergenLabel {
+	if _, ok := value["contains"]; !ok {
+		return nil
+	}
+	names := func(key string) []string {
+		list := []string{}
+		raw, _ := value[key].([]interface{})
+		for _, v := range raw {
+			if name, ok := v.(string); ok {
+				list = append(list, name)
+			}
+		}
+		return list
 	}
+	return &recipe.AllergenLabel{Contains: names("contains"), MayContain: names("may_contain")}
 }
 
 func convertImagesToJSON(images []recipe.Image) []map[string]interface{} {
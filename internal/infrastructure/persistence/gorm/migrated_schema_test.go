@@ -0,0 +1,158 @@
+//go:build integration
+// +build integration
+
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/infrastructure/persistence/migrations"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// withSearchPath points every connection of the DSN at schema
+func withSearchPath(dsn, schema string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + schema
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&search_path=" + schema
+	}
+	return dsn + "?search_path=" + schema
+}
+
+// TestRepositoriesAgainstMigratedSchema applies the SQL migrations, not
+// AutoMigrate, and then reads and writes users, recipes and the tables
+// account erasure clears, so the models and the migrations cannot drift
+// apart unnoticed. It runs against the PostgreSQL, with pgvector, named by
+// ALCHEMORSEL_TEST_POSTGRES_DSN in a throwaway schema.
+func TestRepositoriesAgainstMigratedSchema(t *testing.T) {
+	dsn := os.Getenv("ALCHEMORSEL_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("ALCHEMORSEL_TEST_POSTGRES_DSN not set")
+	}
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	schema := fmt.Sprintf("migrated_test_%d", time.Now().UnixNano())
+	require.NoError(t, admin.Exec("CREATE SCHEMA "+schema).Error)
+	defer admin.Exec("DROP SCHEMA IF EXISTS " + schema + " CASCADE")
+
+	db, err := gorm.Open(postgres.Open(withSearchPath(dsn, schema+",public")), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	migrator, err := migrations.New(sqlDB, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up())
+	require.NoError(t, migrator.Check())
+	require.NoError(t, migrator.Close())
+
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	recipes := NewRecipeRepository(db)
+
+	t.Run("users", func(t *testing.T) {
+		account, err := user.NewUser("sam@example.com", "Sam", "a-long-password")
+		require.NoError(t, err)
+		require.NoError(t, users.Create(ctx, account))
+
+		loaded, err := users.FindByEmail(ctx, "sam@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "Sam", loaded.Name())
+
+		loaded.UpdateProfile(&user.UserProfile{FirstName: "Sam", Bio: "Bakes bread", CookingLevel: user.CookingLevelBeginner})
+		loaded.SetTheme(user.ThemeDark)
+		loaded.SetDietaryProfile([]user.DietaryRestriction{user.DietaryRestrictionVegetarian}, []string{"peanuts"}, nil)
+		loaded.SetPrivateProfile(true)
+		loaded.SetNotificationMuted("new_follower", true)
+		require.NoError(t, users.Update(ctx, loaded))
+
+		reloaded, err := users.FindByID(ctx, account.ID())
+		require.NoError(t, err)
+		assert.Equal(t, "Bakes bread", reloaded.Profile().Bio)
+		assert.Equal(t, user.ThemeDark, reloaded.Preferences().Theme)
+		assert.Equal(t, []string{"peanuts"}, reloaded.Preferences().Allergies)
+		assert.True(t, reloaded.IsPrivate())
+		assert.True(t, reloaded.NotificationMuted("new_follower"))
+
+		require.NoError(t, users.Delete(ctx, account.ID()))
+		_, err = users.FindByID(ctx, account.ID())
+		assert.Error(t, err)
+	})
+
+	t.Run("recipes", func(t *testing.T) {
+		author, err := user.NewUser("ada@example.com", "Ada", "a-long-password")
+		require.NoError(t, err)
+		require.NoError(t, users.Create(ctx, author))
+
+		entity, err := recipe.NewRecipe("Tomato soup", "Quick and warming", author.ID())
+		require.NoError(t, err)
+		require.NoError(t, entity.SetClassification(recipe.CuisineTypeItalian, recipe.CategoryTypeLunch, recipe.DifficultyLevelEasy))
+		require.NoError(t, entity.SetServings(2))
+		entity.SetTiming(10*time.Minute, 20*time.Minute)
+		entity.SetTags([]string{"soup", "vegetarian"})
+		require.NoError(t, entity.AddIngredient(recipe.Ingredient{ID: uuid.New(), Name: "tomatoes", Amount: 2, Unit: recipe.MeasurementUnitCan}))
+		require.NoError(t, entity.AddInstruction(recipe.Instruction{Description: "Simmer the tomatoes", Duration: 20 * time.Minute}))
+		require.NoError(t, recipes.Create(ctx, entity))
+
+		loaded, err := recipes.FindByID(ctx, entity.ID())
+		require.NoError(t, err)
+		assert.Equal(t, recipe.CuisineTypeItalian, loaded.Cuisine())
+		assert.Equal(t, 30*time.Minute, loaded.TotalTime())
+		assert.Equal(t, []string{"soup", "vegetarian"}, loaded.Tags())
+		require.Len(t, loaded.Ingredients(), 1)
+		assert.Equal(t, recipe.MeasurementUnitCan, loaded.Ingredients()[0].Unit)
+		require.Len(t, loaded.Instructions(), 1)
+
+		require.NoError(t, loaded.Publish())
+		require.NoError(t, recipes.Update(ctx, loaded))
+		published, _, err := recipes.FindPublished(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, published, 1)
+		assert.Equal(t, entity.ID(), published[0].ID())
+
+		require.NoError(t, recipes.Delete(ctx, entity.ID()))
+		_, err = recipes.FindByID(ctx, entity.ID())
+		assert.Error(t, err)
+		require.NoError(t, recipes.Restore(ctx, entity.ID()))
+	})
+
+	t.Run("account erasure", func(t *testing.T) {
+		account, err := user.NewUser("lee@example.com", "Lee", "a-long-password")
+		require.NoError(t, err)
+		require.NoError(t, users.Create(ctx, account))
+		var recipeID uuid.UUID
+		require.NoError(t, db.Model(&RecipeModel{}).Select("id").Limit(1).Scan(&recipeID).Error)
+
+		require.NoError(t, db.Create(&RatingModel{ID: uuid.New(), RecipeID: recipeID, UserID: account.ID(), Value: 4, CreatedAt: time.Now()}).Error)
+		require.NoError(t, db.Create(&AIRequestModel{ID: uuid.New(), UserID: account.ID(), Prompt: "soup", Provider: "ollama", Model: "llama3", CreatedAt: time.Now()}).Error)
+		require.NoError(t, db.Create(&ActivityModel{
+			ID: uuid.New(), UserID: account.ID(), ActorID: account.ID(), Type: "recipe_liked",
+			EntityType: "recipe", EntityID: recipeID, Title: "Liked", CreatedAt: time.Now(),
+		}).Error)
+
+		_, err = NewAccountEraser(db).Erase(ctx, account.ID(), time.Now())
+		require.NoError(t, err)
+
+		var left int64
+		require.NoError(t, db.Model(&AIRequestModel{}).Where("user_id = ?", account.ID()).Count(&left).Error)
+		assert.Zero(t, left)
+		require.NoError(t, db.Model(&ActivityModel{}).Where("user_id = ?", account.ID()).Count(&left).Error)
+		assert.Zero(t, left)
+	})
+}
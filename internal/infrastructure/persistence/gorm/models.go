@@ -53,6 +53,10 @@ type UserPreferencesModel struct {
 	Timezone           string      `gorm:"type:varchar(50)"`
 	EmailNotifications bool        `gorm:"default:true"`
 	PushNotifications  bool        `gorm:"default:true"`
+	Theme              string      `gorm:"type:varchar(10);default:'system'"`
+	DisablePersonalization bool    `gorm:"default:false"`
+	PrivateProfile         bool    `gorm:"default:false"`
+	MutedNotifications     StringSlice `gorm:"type:json"`
 }
 
 // RecipeModel represents the GORM model for recipes
@@ -62,11 +66,13 @@ type RecipeModel struct {
 	Title       string    `gorm:"type:varchar(255);not null;index"`
 	Description string    `gorm:"type:text"`
 	AuthorID    uuid.UUID `gorm:"type:char(36);not null;index"`
+	Language    string    `gorm:"type:varchar(16);not null;default:'en';index"`
 	
 	// Recipe details
 	Ingredients   JSONField `gorm:"type:json"`
 	Instructions  JSONField `gorm:"type:json"`
 	NutritionInfo JSONField `gorm:"type:json"`
+	Allergens     JSONField `gorm:"type:json"`
 	
 	// Categorization
 	Cuisine    string      `gorm:"type:varchar(50);index"`
@@ -74,6 +80,9 @@ type RecipeModel struct {
 	Difficulty string      `gorm:"type:varchar(20);index"`
 	Tags       StringSlice `gorm:"type:json"`
 	
+	// Search, written with every save and never read
+	SearchVector SearchDocument `gorm:"column:search_vector;type:tsvector;<-;->:false"`
+	
 	// Timing (stored in minutes)
 	PrepTimeMinutes  int `gorm:"column:prep_time_minutes;default:0"`
 	CookTimeMinutes  int `gorm:"column:cook_time_minutes;default:0"`
@@ -85,24 +94,29 @@ type RecipeModel struct {
 	
 	// AI-generated content
 	AIGenerated bool   `gorm:"default:false"`
-	AIPrompt    string `gorm:"type:text"`
+	AIPrompt    string `gorm:"column:ai_prompt;type:text"`
 	AIModel     string `gorm:"type:varchar(100)"`
 	
 	// Social features
 	Likes         int     `gorm:"column:likes_count;default:0;index"`
 	Views         int     `gorm:"column:views_count;default:0"`
+	Forks         int     `gorm:"column:forks_count;default:0"`
 	AverageRating float64 `gorm:"column:average_rating;default:0;index"`
 	
+	// Lineage: the recipe this one was forked from
+	ForkedFromID *uuid.UUID `gorm:"type:char(36);index"`
+	
 	// Media
 	Images JSONField `gorm:"type:json"`
 	Videos JSONField `gorm:"type:json"`
 	
 	// Metadata
-	Status      string     `gorm:"type:varchar(20);default:'draft';index"`
-	PublishedAt *time.Time `gorm:"index"`
-	CreatedAt   time.Time  `gorm:"index"`
-	UpdatedAt   time.Time
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	Status             string     `gorm:"type:varchar(20);default:'draft';index"`
+	PublishedAt        *time.Time `gorm:"index"`
+	ScheduledPublishAt *time.Time `gorm:"index"`
+	CreatedAt          time.Time  `gorm:"index"`
+	UpdatedAt          time.Time
+	DeletedAt          gorm.DeletedAt `gorm:"index"`
 	
 	// Relationships
 	Author  UserModel     `gorm:"foreignKey:AuthorID"`
@@ -194,14 +208,23 @@ type CollectionRecipeModel struct {
 
 // CommentModel represents the GORM model for recipe comments
 type CommentModel struct {
-	ID        uuid.UUID `gorm:"type:char(36);primaryKey"`
-	RecipeID  uuid.UUID `gorm:"type:char(36);not null;index"`
-	UserID    uuid.UUID `gorm:"type:char(36);not null;index"`
-	ParentID  *uuid.UUID `gorm:"type:char(36);index"` // For nested comments
-	Content   string     `gorm:"type:text;not null"`
-	CreatedAt time.Time  `gorm:"index"`
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID             uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	RecipeID       uuid.UUID  `gorm:"type:char(36);not null;index"`
+	UserID         uuid.UUID  `gorm:"type:char(36);not null;index"`
+	ParentID       *uuid.UUID `gorm:"type:char(36);index"` // For nested comments
+	ReviewUserID   *uuid.UUID `gorm:"type:char(36);index"` // For responses to a review
+	Content        string     `gorm:"type:text;not null"`
+	Source         string     `gorm:"type:varchar(10);not null;default:'web'"`
+	EmailMessageID *string    `gorm:"type:varchar(255);uniqueIndex"`
+	// Moderation by the recipe's author and site moderators
+	Status         string     `gorm:"type:varchar(10);not null;default:'visible';index"`
+	HiddenBy       *uuid.UUID `gorm:"type:char(36)"`
+	HideReason     string     `gorm:"type:text"`
+	HiddenAt       *time.Time
+	EditedAt       *time.Time
+	CreatedAt      time.Time  `gorm:"index"`
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
 	
 	// Relationships
 	Recipe   RecipeModel    `gorm:"foreignKey:RecipeID"`
@@ -231,19 +254,680 @@ type ActivityModel struct {
 
 // RecipeViewModel represents the GORM model for recipe views
 type RecipeViewModel struct {
-	ID        uuid.UUID `gorm:"type:char(36);primaryKey"`
-	RecipeID  uuid.UUID `gorm:"type:char(36);not null;index"`
-	UserID    *uuid.UUID `gorm:"type:char(36);index"` // Nullable for anonymous views
-	IPAddress string     `gorm:"type:varchar(45)"`
-	UserAgent string     `gorm:"type:text"`
-	Referrer  string     `gorm:"type:text"`
-	CreatedAt time.Time  `gorm:"index"`
+	ID           uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	RecipeID     uuid.UUID  `gorm:"type:char(36);not null;index:idx_recipe_views_recipe_date,priority:1"`
+	UserID       *uuid.UUID `gorm:"type:char(36);index"` // Nullable for anonymous views
+	SessionID    *string    `gorm:"type:varchar(64)"`
+	IPAddress    *string    `gorm:"type:varchar(45)"`
+	UserAgent    string     `gorm:"type:text"`
+	Referrer     string     `gorm:"type:text"`
+	ReferrerHost string     `gorm:"type:varchar(255)"`
+	SearchTerm   *string    `gorm:"type:varchar(255)"`
+	ScrollDepth  *int       `gorm:"type:smallint"` // Set by the engagement beacon
+	EngagedMS    *int
+	ViewedAt     time.Time `gorm:"not null;index:idx_recipe_views_recipe_date,priority:2"`
 	
 	// Relationships
 	Recipe RecipeModel `gorm:"foreignKey:RecipeID"`
 	User   *UserModel  `gorm:"foreignKey:UserID"`
 }
 
+// ZeroResultSearchModel represents the GORM model for searches that found nothing
+type ZeroResultSearchModel struct {
+	ID              uuid.UUID   `gorm:"type:char(36);primaryKey"`
+	Query           string      `gorm:"type:varchar(255);not null"`
+	NormalizedQuery string      `gorm:"type:varchar(255);not null;index"`
+	UserID          *uuid.UUID  `gorm:"type:char(36);index"` // Nullable for anonymous searches
+	Suggestions     StringSlice `gorm:"type:json"`
+	CreatedAt       time.Time   `gorm:"index"`
+}
+
+// RecipeAnnouncementModel represents the GORM model for queued recipe announcements
+type RecipeAnnouncementModel struct {
+	ID            uuid.UUID `gorm:"type:char(36);primaryKey"`
+	RecipeID      uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_recipe_announcements_channel,priority:1"`
+	Channel       string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_recipe_announcements_channel,priority:2"`
+	Status        string    `gorm:"type:varchar(20);not null;default:'pending'"`
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	LastError     string    `gorm:"type:text"`
+	SentAt        *time.Time
+	CreatedAt     time.Time
+}
+
+// CommentReplyTokenModel represents the GORM model for email reply-to tokens
+type CommentReplyTokenModel struct {
+	ID           uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	UserID       uuid.UUID  `gorm:"type:char(36);not null"`
+	RecipeID     uuid.UUID  `gorm:"type:char(36);not null"`
+	ParentID     *uuid.UUID `gorm:"type:char(36)"`
+	ReviewUserID *uuid.UUID `gorm:"type:char(36)"`
+	ExpiresAt    time.Time  `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+// EmailSuppressionModel represents the GORM model for suppressed addresses
+type EmailSuppressionModel struct {
+	Email     string `gorm:"type:varchar(255);primaryKey"`
+	Reason    string `gorm:"type:varchar(20);not null"`
+	Detail    string `gorm:"type:text"`
+	CreatedAt time.Time
+}
+
+// PasswordResetTokenModel represents the GORM model for password reset
+// tokens, keyed by the token's SHA-256
+type PasswordResetTokenModel struct {
+	TokenHash string    `gorm:"type:char(64);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;index"`
+	Email     string    `gorm:"type:varchar(255);not null;index:idx_password_reset_tokens_email_created"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"index:idx_password_reset_tokens_email_created"`
+}
+
+// RecipeClipModel represents the GORM model for pages clipped with the
+// browser extension
+type RecipeClipModel struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;index:idx_recipe_clips_user_created"`
+	RecipeID  uuid.UUID `gorm:"type:char(36);not null;index"`
+	PageURL   string    `gorm:"type:text;not null"`
+	Method    string    `gorm:"type:varchar(20);not null"`
+	CreatedAt time.Time `gorm:"index:idx_recipe_clips_user_created"`
+}
+
+// GuestSessionModel represents the GORM model for guest sessions, keyed
+// by id and found by the SHA-256 of their token
+type GuestSessionModel struct {
+	ID        uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex"`
+	IPHash    string     `gorm:"type:char(64);not null;index:idx_guest_sessions_ip_created"`
+	Messages  int        `gorm:"not null;default:0"`
+	Recipes   int        `gorm:"not null;default:0"`
+	ExpiresAt time.Time  `gorm:"not null;index"`
+	ClaimedBy *uuid.UUID `gorm:"type:char(36)"`
+	ClaimedAt *time.Time
+	CreatedAt time.Time `gorm:"index:idx_guest_sessions_ip_created"`
+}
+
+// GuestRecipeModel represents the GORM model for recipes guests kept
+type GuestRecipeModel struct {
+	ID          uuid.UUID   `gorm:"type:char(36);primaryKey"`
+	GuestID     uuid.UUID   `gorm:"type:char(36);not null;index"`
+	Title       string      `gorm:"type:varchar(255);not null"`
+	Description string      `gorm:"type:text"`
+	Ingredients StringSlice `gorm:"type:json"`
+	Directions  StringSlice `gorm:"type:json"`
+	Servings    int
+	PrepTime    int
+	CookTime    int
+	Tags        StringSlice `gorm:"type:json"`
+	CreatedAt   time.Time
+}
+
+// ImageModel represents the GORM model for uploaded images
+type ImageModel struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey"`
+	OwnerID     uuid.UUID `gorm:"type:char(36);not null;index"`
+	BlobKey     string    `gorm:"type:varchar(255);not null"`
+	ContentType string    `gorm:"type:varchar(50);not null"`
+	Width       int       `gorm:"not null"`
+	Height      int       `gorm:"not null"`
+	SizeBytes   int64     `gorm:"not null"`
+	SHA256      string    `gorm:"column:sha256;type:char(64);not null"`
+	CreatedAt   time.Time
+}
+
+// FavoriteModel represents the GORM model for recipes a user saved
+type FavoriteModel struct {
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	RecipeID  uuid.UUID `gorm:"type:char(36);primaryKey;index"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// NotificationModel represents the GORM model for in-app notifications
+type NotificationModel struct {
+	ID        uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID  `gorm:"type:char(36);not null;index:idx_notifications_user_created,priority:1"`
+	ActorID   *uuid.UUID `gorm:"type:char(36)"`
+	Type      string     `gorm:"type:varchar(50);not null"`
+	Title     string     `gorm:"type:varchar(200);not null"`
+	Message   string     `gorm:"type:text"`
+	Link      string     `gorm:"type:varchar(500)"`
+	IsRead    bool       `gorm:"not null;default:false"`
+	ReadAt    *time.Time
+	CreatedAt time.Time `gorm:"not null;index:idx_notifications_user_created,priority:2"`
+}
+
+// IngredientNutritionModel represents the GORM model for the reference
+// foods recipe nutrition is computed from. Nutrients are per 100 g.
+type IngredientNutritionModel struct {
+	Name          string  `gorm:"type:varchar(100);primaryKey"`
+	Keywords      string  `gorm:"type:varchar(500);not null"`
+	Position      int     `gorm:"not null;index"`
+	Calories      float64 `gorm:"not null"`
+	Protein       float64 `gorm:"not null"`
+	Carbohydrates float64 `gorm:"not null"`
+	Fat           float64 `gorm:"not null"`
+	Fiber         float64 `gorm:"not null"`
+	Sugar         float64 `gorm:"not null"`
+	Sodium        float64 `gorm:"not null"`
+	Cholesterol   float64 `gorm:"not null"`
+	PricePerKg    float64 `gorm:"not null"`
+	Source        string  `gorm:"type:varchar(100);not null"`
+	UpdatedAt     time.Time
+}
+
+// IngredientSubstituteModel represents the GORM model for the substitutes
+// knowledge base, one row per ingredient and substitute
+type IngredientSubstituteModel struct {
+	Ingredient string      `gorm:"type:varchar(100);primaryKey"`
+	Name       string      `gorm:"type:varchar(100);primaryKey"`
+	Ratio      string      `gorm:"type:varchar(200);not null"`
+	Notes      string      `gorm:"type:varchar(500);not null"`
+	Contains   StringSlice `gorm:"type:json"`
+	Position   int         `gorm:"not null"`
+	Source     string      `gorm:"type:varchar(100);not null"`
+	UpdatedAt  time.Time
+}
+
+// ShoppingListModel represents the GORM model for shared shopping lists
+type ShoppingListModel struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	OwnerID   uuid.UUID `gorm:"type:char(36);not null;index"`
+	Name      string    `gorm:"type:varchar(200);not null"`
+	Version   int64     `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ShoppingListMemberModel represents the GORM model for users a list is shared with
+type ShoppingListMemberModel struct {
+	ListID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey;index"`
+	CreatedAt time.Time
+}
+
+// ShoppingListItemModel represents the GORM model for shopping list items
+type ShoppingListItemModel struct {
+	ID               uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	ListID           uuid.UUID  `gorm:"type:char(36);not null;index:idx_shopping_list_items_list,priority:1"`
+	Name             string     `gorm:"type:varchar(200);not null"`
+	Quantity         string     `gorm:"type:varchar(50);not null;default:''"`
+	Checked          bool       `gorm:"not null;default:false"`
+	CheckedBy        *uuid.UUID `gorm:"type:char(36)"`
+	CheckedChangedAt time.Time  `gorm:"not null"`
+	EditedAt         time.Time  `gorm:"not null"`
+	Removed          bool       `gorm:"not null;default:false"` // Kept so late offline edits see the removal
+	AddedBy          uuid.UUID  `gorm:"type:char(36);not null"`
+	Version          int64      `gorm:"not null"`
+	CreatedAt        time.Time  `gorm:"index:idx_shopping_list_items_list,priority:2"`
+}
+
+// ShoppingListChangeModel represents the GORM model for the shopping list change log
+type ShoppingListChangeModel struct {
+	ListID    uuid.UUID  `gorm:"type:char(36);primaryKey;uniqueIndex:idx_shopping_list_changes_op,priority:1"`
+	Version   int64      `gorm:"primaryKey;autoIncrement:false"`
+	OpID      string     `gorm:"type:varchar(64);not null;uniqueIndex:idx_shopping_list_changes_op,priority:2"`
+	Type      string     `gorm:"type:varchar(10);not null"`
+	UserID    uuid.UUID  `gorm:"type:char(36);not null"`
+	ItemID    uuid.UUID  `gorm:"type:char(36);not null"`
+	Name      string     `gorm:"type:varchar(200);not null"`
+	Quantity  string     `gorm:"type:varchar(50);not null;default:''"`
+	Checked   bool       `gorm:"not null"`
+	CheckedBy *uuid.UUID `gorm:"type:char(36)"`
+	Removed   bool       `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// ProfileCaptureModel represents the GORM model for audited profiling captures
+type ProfileCaptureModel struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Kind        string    `gorm:"type:varchar(40);not null"`
+	Seconds     int       `gorm:"not null;default:0"`
+	Status      string    `gorm:"type:varchar(20);not null"`
+	RequestedBy uuid.UUID `gorm:"type:char(36);not null"`
+	IPAddress   string    `gorm:"type:varchar(45)"`
+	Reason      string    `gorm:"type:text"`
+	BlobKey     string    `gorm:"type:varchar(255)"`
+	SizeBytes   int64     `gorm:"not null;default:0"`
+	Error       string    `gorm:"type:text"`
+	StartedAt   time.Time `gorm:"not null;index"`
+	FinishedAt  *time.Time
+}
+
+// BrowseTagCountModel is the precomputed recipe count of one tag
+type BrowseTagCountModel struct {
+	Tag         string    `gorm:"type:varchar(100);primaryKey"`
+	RecipeCount int       `gorm:"not null;index"`
+	RefreshedAt time.Time `gorm:"not null"`
+}
+
+// BrowseCuisineCountModel is the precomputed recipe count of one cuisine
+type BrowseCuisineCountModel struct {
+	Cuisine     string    `gorm:"type:varchar(50);primaryKey"`
+	RecipeCount int       `gorm:"not null;index"`
+	RefreshedAt time.Time `gorm:"not null"`
+}
+
+// BrowseTopRatedModel is one ranked recipe on a cuisine's top rated list
+type BrowseTopRatedModel struct {
+	Cuisine       string    `gorm:"type:varchar(50);primaryKey"`
+	Rank          int       `gorm:"primaryKey"`
+	RecipeID      uuid.UUID `gorm:"type:char(36);not null"`
+	Title         string    `gorm:"type:varchar(255);not null"`
+	AverageRating float64   `gorm:"not null"`
+	LikesCount    int       `gorm:"not null"`
+	RefreshedAt   time.Time `gorm:"not null"`
+}
+
+// RecipePopularityModel is a recipe's decayed popularity score
+type RecipePopularityModel struct {
+	RecipeID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Score       float64   `gorm:"not null;index"`
+	RefreshedAt time.Time `gorm:"not null"`
+}
+
+// RecipeRankingModel is a published recipe's position in the trending and
+// top listings
+type RecipeRankingModel struct {
+	RecipeID      uuid.UUID `gorm:"type:char(36);primaryKey"`
+	TrendingScore float64   `gorm:"not null"`
+	TrendingRank  int       `gorm:"not null;index"`
+	TopScore      float64   `gorm:"not null"`
+	TopRank       int       `gorm:"not null;index"`
+	RankedAt      time.Time `gorm:"not null"`
+}
+
+// RecipeDailyViewModel is a recipe's deduplicated views on a UTC day
+type RecipeDailyViewModel struct {
+	RecipeID uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Day      time.Time `gorm:"type:date;primaryKey;index"`
+	Views    int       `gorm:"not null"`
+}
+
+// RecipeDailyImpressionModel counts how often a recipe was listed in text
+// search results on a UTC day
+type RecipeDailyImpressionModel struct {
+	RecipeID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Day         time.Time `gorm:"type:date;primaryKey;index"`
+	Impressions int       `gorm:"not null"`
+}
+
+// HiddenGemModel records when a recipe was last featured as a hidden gem.
+// The rows featured at the latest time are the current rotation.
+type HiddenGemModel struct {
+	RecipeID   uuid.UUID `gorm:"type:char(36);primaryKey"`
+	FeaturedAt time.Time `gorm:"not null;index"`
+}
+
+// ArchivePartitionModel lists one archived day of RUM or audit rows in
+// blob storage
+type ArchivePartitionModel struct {
+	ID         uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Dataset    string    `gorm:"type:varchar(40);not null;index:idx_archive_partitions_dataset_day,priority:1"`
+	Day        time.Time `gorm:"not null;index:idx_archive_partitions_dataset_day,priority:2"`
+	BlobKey    string    `gorm:"type:varchar(255);not null"`
+	Records    int       `gorm:"not null"`
+	SourceRows int       `gorm:"not null"`
+	SizeBytes  int64     `gorm:"not null"`
+	ArchivedAt time.Time `gorm:"not null"`
+}
+
+// RecipeCounterShardModel holds increments to one recipe counter that have
+// not been folded into the recipe row yet
+type RecipeCounterShardModel struct {
+	RecipeID uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Counter  string    `gorm:"type:varchar(20);primaryKey"`
+	Shard    int       `gorm:"primaryKey;autoIncrement:false"`
+	Value    int64     `gorm:"not null;default:0"`
+}
+
+// RecipeGraphNodeModel is an ingredient, technique or cuisine in the
+// recipe knowledge graph
+type RecipeGraphNodeModel struct {
+	NodeKind    string    `gorm:"type:varchar(20);primaryKey"`
+	NodeKey     string    `gorm:"type:varchar(100);primaryKey"`
+	Label       string    `gorm:"type:varchar(255);not null"`
+	RecipeCount int       `gorm:"not null"`
+	Weight      float64   `gorm:"not null"`
+	RefreshedAt time.Time `gorm:"not null"`
+}
+
+// RecipeGraphEdgeModel links a published recipe to a graph node
+type RecipeGraphEdgeModel struct {
+	RecipeID uuid.UUID `gorm:"type:char(36);primaryKey;index:idx_recipe_graph_edges_node,priority:3"`
+	NodeKind string    `gorm:"type:varchar(20);primaryKey;index:idx_recipe_graph_edges_node,priority:1"`
+	NodeKey  string    `gorm:"type:varchar(100);primaryKey;index:idx_recipe_graph_edges_node,priority:2"`
+}
+
+// TechniqueModel is one entry of the technique library. Videos are kept
+// as provider and ID only.
+type TechniqueModel struct {
+	Slug          string      `gorm:"type:varchar(80);primaryKey"`
+	Name          string      `gorm:"type:varchar(120);not null;uniqueIndex"`
+	Summary       string      `gorm:"type:text;not null"`
+	Steps         StringSlice `gorm:"type:json"`
+	Aliases       StringSlice `gorm:"type:json"`
+	VideoProvider string      `gorm:"type:varchar(20)"`
+	VideoID       string      `gorm:"type:varchar(32)"`
+	VideoTitle    string      `gorm:"type:varchar(255)"`
+	VideoDuration int         `gorm:"not null;default:0"`
+	VideoStart    int         `gorm:"not null;default:0"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// RecipeStepTechniqueModel links one step of a recipe to a technique
+type RecipeStepTechniqueModel struct {
+	RecipeID      uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Step          int       `gorm:"primaryKey"`
+	TechniqueSlug string    `gorm:"type:varchar(80);primaryKey;index"`
+	Source        string    `gorm:"type:varchar(20);not null"`
+	Position      int       `gorm:"not null"`
+}
+
+// RecipeTranslationModel is one translated field of a recipe, with where
+// the text came from and the hash of the source text it translates
+type RecipeTranslationModel struct {
+	RecipeID   uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	Language   string     `gorm:"type:varchar(16);primaryKey"`
+	Field      string     `gorm:"type:varchar(40);primaryKey"`
+	Text       string     `gorm:"type:text;not null"`
+	SourceHash string     `gorm:"type:varchar(64);not null"`
+	Provenance string     `gorm:"type:varchar(20);not null"`
+	Model      string     `gorm:"type:varchar(100)"`
+	EditorID   *uuid.UUID `gorm:"type:char(36)"`
+	UpdatedAt  time.Time
+}
+
+// BattleModel is a remix battle with both entries. The chef's columns are
+// empty and voting_ends_at null until the chef submits.
+type BattleModel struct {
+	ID              uuid.UUID   `gorm:"type:char(36);primaryKey"`
+	Prompt          string      `gorm:"type:text;not null"`
+	ChefID          uuid.UUID   `gorm:"type:char(36);not null;index"`
+	Template        string      `gorm:"type:varchar(40);not null;index"`
+	AITitle         string      `gorm:"type:varchar(255);not null"`
+	AIDescription   string      `gorm:"column:ai_description;type:text"`
+	AIIngredients   StringSlice `gorm:"type:json"`
+	AISteps         StringSlice `gorm:"type:json"`
+	ChefTitle       string      `gorm:"type:varchar(255)"`
+	ChefDescription string      `gorm:"type:text"`
+	ChefIngredients StringSlice `gorm:"type:json"`
+	ChefSteps       StringSlice `gorm:"type:json"`
+	AIFirst         bool        `gorm:"not null"`
+	VotingEndsAt    *time.Time  `gorm:"index"`
+	AIVotes         int         `gorm:"not null;default:0"`
+	ChefVotes       int         `gorm:"not null;default:0"`
+	CreatedAt       time.Time   `gorm:"index"`
+	UpdatedAt       time.Time
+}
+
+// BattleVoteModel is one reader's vote in a battle
+type BattleVoteModel struct {
+	BattleID  uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Side      string    `gorm:"type:varchar(10);not null"`
+	CreatedAt time.Time
+}
+
+// RecipeChangeModel is one revision in a recipe's change log. Fields and
+// Snapshot are JSON objects keyed by synced field name.
+type RecipeChangeModel struct {
+	RecipeID  uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Revision  int64     `gorm:"primaryKey;autoIncrement:false"`
+	Fields    string    `gorm:"type:text;not null"`
+	Snapshot  string    `gorm:"type:text;not null"`
+	ChangedAt time.Time `gorm:"not null"`
+}
+
+// SyncRecordModel is one record a user syncs across devices. Fields is a
+// JSON object of each field's value and the write that set it; Seq is the
+// user's change sequence number of the last save.
+type SyncRecordModel struct {
+	UserID    uuid.UUID  `gorm:"type:char(36);primaryKey;index:idx_sync_records_user_seq,priority:1"`
+	Kind      string     `gorm:"type:varchar(20);primaryKey"`
+	Key       string     `gorm:"type:varchar(64);primaryKey"`
+	Fields    string     `gorm:"type:text;not null"`
+	ClearedAt *time.Time `gorm:"index:idx_sync_records_tombstones,priority:2"`
+	ClearedBy string     `gorm:"type:varchar(64)"`
+	Deleted   bool       `gorm:"not null;default:false;index:idx_sync_records_tombstones,priority:1"`
+	Seq       int64      `gorm:"not null;index:idx_sync_records_user_seq,priority:2"`
+	UpdatedAt time.Time
+}
+
+// SyncCounterModel holds a user's change sequence and the highest
+// sequence number purged
+type SyncCounterModel struct {
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Seq       int64     `gorm:"not null;default:0"`
+	PurgedSeq int64     `gorm:"not null;default:0"`
+}
+
+// SyncDeviceModel is how far one of a user's devices has pulled
+type SyncDeviceModel struct {
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	DeviceID  string    `gorm:"type:varchar(64);primaryKey"`
+	PulledSeq int64     `gorm:"not null"`
+	SeenAt    time.Time `gorm:"not null"`
+}
+
+// PantryItemModel is something a user keeps in stock
+type PantryItemModel struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;index"`
+	Name      string    `gorm:"type:varchar(200);not null"`
+	Quantity  float64   `gorm:"not null;default:0"`
+	Unit      string    `gorm:"type:varchar(20);not null;default:''"`
+	Par       float64   `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CookLogModel records a user cooking a recipe
+type CookLogModel struct {
+	ID       uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID   uuid.UUID `gorm:"type:char(36);not null;index:idx_cook_logs_user_cooked,priority:1"`
+	RecipeID uuid.UUID `gorm:"type:char(36);not null;index"`
+	Portions int       `gorm:"not null"`
+	CookedAt time.Time `gorm:"not null;index:idx_cook_logs_user_cooked,priority:2"`
+}
+
+// PantryConsumptionModel is what one cook took from one pantry item. The
+// item's name and unit are copied so history outlives the item.
+type PantryConsumptionModel struct {
+	ID       uint      `gorm:"primaryKey;autoIncrement"`
+	CookID   uuid.UUID `gorm:"type:char(36);not null;index"`
+	UserID   uuid.UUID `gorm:"type:char(36);not null;index:idx_pantry_consumption_user_cooked,priority:1"`
+	RecipeID uuid.UUID `gorm:"type:char(36);not null"`
+	ItemID   uuid.UUID `gorm:"type:char(36);not null"`
+	ItemName string    `gorm:"type:varchar(200);not null"`
+	Amount   float64   `gorm:"not null"`
+	Unit     string    `gorm:"type:varchar(20);not null;default:''"`
+	CookedAt time.Time `gorm:"not null;index:idx_pantry_consumption_user_cooked,priority:2"`
+}
+
+// UploadScanModel records the virus scan of one uploaded file
+type UploadScanModel struct {
+	ID            uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID        uuid.UUID `gorm:"type:char(36);not null;index"`
+	Source        string    `gorm:"type:varchar(40);not null"`
+	FileName      string    `gorm:"type:varchar(255)"`
+	ContentType   string    `gorm:"type:varchar(100)"`
+	SizeBytes     int64     `gorm:"not null;default:0"`
+	SHA256        string    `gorm:"column:sha256;type:char(64);not null;index"`
+	Scanner       string    `gorm:"type:varchar(40);not null"`
+	Verdict       string    `gorm:"type:varchar(20);not null;index:idx_upload_scans_verdict_scanned,priority:1"`
+	Signature     string    `gorm:"type:varchar(255)"`
+	QuarantineKey string    `gorm:"type:varchar(255)"`
+	Error         string    `gorm:"type:text"`
+	DurationMS    int64     `gorm:"column:duration_ms;not null;default:0"`
+	ScannedAt     time.Time `gorm:"not null;index;index:idx_upload_scans_verdict_scanned,priority:2"`
+}
+
+// VerificationClaimModel is an author's claim to a verified badge
+type VerificationClaimModel struct {
+	ID          uuid.UUID   `gorm:"type:char(36);primaryKey"`
+	UserID      uuid.UUID   `gorm:"type:char(36);not null;index"`
+	Kind        string      `gorm:"type:varchar(20);not null"`
+	DisplayName string      `gorm:"type:varchar(100);not null"`
+	Statement   string      `gorm:"type:text"`
+	Evidence    StringSlice `gorm:"type:json"`
+	Status      string      `gorm:"type:varchar(20);not null;index:idx_verification_claims_status_submitted,priority:1"`
+	ReviewerID  *uuid.UUID  `gorm:"type:char(36)"`
+	ReviewNote  string      `gorm:"type:text"`
+	SubmittedAt time.Time   `gorm:"not null;index:idx_verification_claims_status_submitted,priority:2"`
+	ReviewedAt  *time.Time
+}
+
+// VerificationReportModel is a reader's report that a badge is fake
+type VerificationReportModel struct {
+	ID         uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	ClaimID    uuid.UUID  `gorm:"type:char(36);not null;index"`
+	SubjectID  uuid.UUID  `gorm:"type:char(36);not null"`
+	ReporterID uuid.UUID  `gorm:"type:char(36);not null;index"`
+	Reason     string     `gorm:"type:varchar(30);not null"`
+	Details    string     `gorm:"type:text"`
+	Status     string     `gorm:"type:varchar(20);not null;index:idx_verification_reports_status_created,priority:1"`
+	ResolverID *uuid.UUID `gorm:"type:char(36)"`
+	Resolution string     `gorm:"type:text"`
+	CreatedAt  time.Time  `gorm:"not null;index:idx_verification_reports_status_created,priority:2"`
+	ResolvedAt *time.Time
+}
+
+// CommentReactionModel is one user's reaction to a comment
+type CommentReactionModel struct {
+	CommentID uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey;index:idx_comment_reactions_user_created,priority:1"`
+	Kind      string    `gorm:"type:varchar(20);primaryKey"`
+	RecipeID  uuid.UUID `gorm:"type:char(36);not null"`
+	CreatedAt time.Time `gorm:"not null;index:idx_comment_reactions_user_created,priority:2"`
+}
+
+// ReviewVoteModel is one reader's helpfulness vote on a review. Reviews
+// are ratings with a written comment, keyed by recipe and reviewer.
+type ReviewVoteModel struct {
+	RecipeID   uuid.UUID `gorm:"type:char(36);primaryKey"`
+	ReviewerID uuid.UUID `gorm:"type:char(36);primaryKey"`
+	VoterID    uuid.UUID `gorm:"type:char(36);primaryKey;index:idx_review_votes_voter_created,priority:1"`
+	Helpful    bool      `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"not null;index:idx_review_votes_voter_created,priority:2"`
+}
+
+// RecipeDiscussionModel holds whether a recipe's comments are locked
+type RecipeDiscussionModel struct {
+	RecipeID  uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	Locked    bool       `gorm:"not null;default:false"`
+	LockedBy  *uuid.UUID `gorm:"type:char(36)"`
+	LockedAt  *time.Time
+	UpdatedAt time.Time
+}
+
+// CommentBlockModel stops a user commenting on an author's recipes
+type CommentBlockModel struct {
+	AuthorID  uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Reason    string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// CommentModerationEventModel is an audited moderation action
+type CommentModerationEventModel struct {
+	ID        uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	ActorID   uuid.UUID  `gorm:"type:char(36);not null;index:idx_comment_moderation_events_actor_created,priority:1"`
+	Action    string     `gorm:"type:varchar(10);not null"`
+	RecipeID  *uuid.UUID `gorm:"type:char(36);index:idx_comment_moderation_events_recipe_created,priority:1"`
+	CommentID *uuid.UUID `gorm:"type:char(36)"`
+	SubjectID *uuid.UUID `gorm:"type:char(36)"`
+	Reason    string     `gorm:"type:text"`
+	CreatedAt time.Time  `gorm:"not null;index:idx_comment_moderation_events_actor_created,priority:2;index:idx_comment_moderation_events_recipe_created,priority:2"`
+}
+
+// CommentFlagModel is one reader's flag on a comment
+type CommentFlagModel struct {
+	CommentID uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Reason    string    `gorm:"type:varchar(20);not null"`
+	Note      string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// ReportModel represents a reader's report of a recipe or comment.
+// RecipeID is the reported recipe or the recipe of the reported comment.
+type ReportModel struct {
+	ID          uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	TargetType  string     `gorm:"type:varchar(20);not null;index:idx_reports_target,priority:1"`
+	TargetID    uuid.UUID  `gorm:"type:char(36);not null;index:idx_reports_target,priority:2"`
+	RecipeID    uuid.UUID  `gorm:"type:char(36);not null;index"`
+	ReporterID  uuid.UUID  `gorm:"type:char(36);not null"`
+	Reason      string     `gorm:"type:varchar(20);not null"`
+	Note        string     `gorm:"type:text"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'open';index:idx_reports_status_created,priority:1"`
+	Unpublished bool       `gorm:"not null;default:false"`
+	ResolvedBy  *uuid.UUID `gorm:"type:char(36)"`
+	Resolution  string     `gorm:"type:text"`
+	CreatedAt   time.Time  `gorm:"not null;index:idx_reports_status_created,priority:2"`
+	ResolvedAt  *time.Time
+}
+
+// DuplicateCheckModel is a published recipe waiting for the duplicate check
+type DuplicateCheckModel struct {
+	RecipeID uuid.UUID `gorm:"type:char(36);primaryKey"`
+	QueuedAt time.Time `gorm:"not null;index"`
+}
+
+// DuplicateFlagModel is a recipe found to be a near copy of another
+// author's recipe
+type DuplicateFlagModel struct {
+	ID                uuid.UUID   `gorm:"type:char(36);primaryKey"`
+	RecipeID          uuid.UUID   `gorm:"type:char(36);not null;index:idx_duplicate_flags_recipe_original,priority:1"`
+	OriginalID        uuid.UUID   `gorm:"type:char(36);not null;index:idx_duplicate_flags_recipe_original,priority:2"`
+	AuthorID          uuid.UUID   `gorm:"type:char(36);not null"`
+	Similarity        float64     `gorm:"not null"`
+	SharedIngredients StringSlice `gorm:"type:json"`
+	SharedSteps       StringSlice `gorm:"type:json"`
+	Archived          bool        `gorm:"not null;default:false"`
+	Status            string      `gorm:"type:varchar(20);not null;default:'open';index:idx_duplicate_flags_status_created,priority:1"`
+	ResolvedBy        *uuid.UUID  `gorm:"type:char(36)"`
+	Resolution        string      `gorm:"type:text"`
+	ResolvedAt        *time.Time
+	Appeal            string `gorm:"type:varchar(20);not null;default:''"`
+	AppealNote        string `gorm:"type:text"`
+	AppealedAt        *time.Time
+	AppealDecidedBy   *uuid.UUID `gorm:"type:char(36)"`
+	AppealDecidedAt   *time.Time
+	CreatedAt         time.Time `gorm:"not null;index:idx_duplicate_flags_status_created,priority:2"`
+}
+
+// RecipeEmbeddingModel is a recipe's vector from one embedding model
+type RecipeEmbeddingModel struct {
+	RecipeID        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Model           string    `gorm:"type:varchar(100);primaryKey"`
+	Dimensions      int       `gorm:"not null"`
+	Embedding       Vector    `gorm:"type:vector;not null"`
+	RecipeUpdatedAt time.Time `gorm:"not null"`
+	EmbeddedAt      time.Time `gorm:"not null"`
+}
+
+// AccountDeletionModel is an account waiting out the grace period before
+// it is erased
+type AccountDeletionModel struct {
+	UserID       uuid.UUID `gorm:"type:char(36);primaryKey"`
+	RequestedAt  time.Time `gorm:"not null"`
+	ScheduledFor time.Time `gorm:"not null;index"`
+}
+
+// AccountAuditEventModel is an audited account deletion event
+type AccountAuditEventModel struct {
+	ID        uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	UserID    uuid.UUID  `gorm:"type:char(36);not null;index:idx_account_audit_events_user_created,priority:1"`
+	ActorID   *uuid.UUID `gorm:"type:char(36)"`
+	Action    string     `gorm:"type:varchar(20);not null"`
+	Detail    string     `gorm:"type:text;not null;default:'{}'"`
+	CreatedAt time.Time  `gorm:"not null;index:idx_account_audit_events_user_created,priority:2"`
+}
+
 // StringSlice custom type for handling string slices in JSON
 type StringSlice []string
 
@@ -407,4 +1091,232 @@ func (ActivityModel) TableName() string {
 
 func (RecipeViewModel) TableName() string {
 	return "recipe_views"
-}
\ No newline at end of file
+}
+
+func (ZeroResultSearchModel) TableName() string {
+	return "zero_result_searches"
+}
+
+func (RecipeAnnouncementModel) TableName() string {
+	return "recipe_announcements"
+}
+
+func (CommentReplyTokenModel) TableName() string {
+	return "comment_reply_tokens"
+}
+
+func (EmailSuppressionModel) TableName() string {
+	return "email_suppressions"
+}
+
+func (PasswordResetTokenModel) TableName() string {
+	return "password_reset_tokens"
+}
+
+func (RecipeClipModel) TableName() string {
+	return "recipe_clips"
+}
+
+func (GuestSessionModel) TableName() string {
+	return "guest_sessions"
+}
+
+func (GuestRecipeModel) TableName() string {
+	return "guest_recipes"
+}
+
+func (ImageModel) TableName() string {
+	return "images"
+}
+
+func (FavoriteModel) TableName() string {
+	return "favorites"
+}
+
+func (NotificationModel) TableName() string {
+	return "notifications"
+}
+
+func (IngredientNutritionModel) TableName() string {
+	return "ingredient_nutrition"
+}
+
+func (IngredientSubstituteModel) TableName() string {
+	return "ingredient_substitutes"
+}
+
+func (ShoppingListModel) TableName() string {
+	return "shopping_lists"
+}
+
+func (ShoppingListMemberModel) TableName() string {
+	return "shopping_list_members"
+}
+
+func (ShoppingListItemModel) TableName() string {
+	return "shopping_list_items"
+}
+
+func (ShoppingListChangeModel) TableName() string {
+	return "shopping_list_changes"
+}
+
+func (ProfileCaptureModel) TableName() string {
+	return "profile_captures"
+}
+
+func (BrowseTagCountModel) TableName() string {
+	return "browse_tag_counts"
+}
+
+func (BrowseCuisineCountModel) TableName() string {
+	return "browse_cuisine_counts"
+}
+
+func (BrowseTopRatedModel) TableName() string {
+	return "browse_top_rated"
+}
+
+func (RecipePopularityModel) TableName() string {
+	return "recipe_popularity"
+}
+
+func (RecipeRankingModel) TableName() string {
+	return "recipe_rankings"
+}
+
+func (RecipeDailyViewModel) TableName() string {
+	return "recipe_daily_views"
+}
+
+func (RecipeDailyImpressionModel) TableName() string {
+	return "recipe_daily_impressions"
+}
+
+func (HiddenGemModel) TableName() string {
+	return "hidden_gems"
+}
+
+func (ArchivePartitionModel) TableName() string {
+	return "archive_partitions"
+}
+
+func (RecipeCounterShardModel) TableName() string {
+	return "recipe_counter_shards"
+}
+
+func (RecipeGraphNodeModel) TableName() string {
+	return "recipe_graph_nodes"
+}
+
+func (RecipeGraphEdgeModel) TableName() string {
+	return "recipe_graph_edges"
+}
+
+func (TechniqueModel) TableName() string {
+	return "techniques"
+}
+
+func (RecipeStepTechniqueModel) TableName() string {
+	return "recipe_step_techniques"
+}
+
+func (RecipeTranslationModel) TableName() string {
+	return "recipe_translations"
+}
+
+func (BattleModel) TableName() string {
+	return "battles"
+}
+
+func (BattleVoteModel) TableName() string {
+	return "battle_votes"
+}
+
+func (RecipeChangeModel) TableName() string {
+	return "recipe_changes"
+}
+
+func (SyncRecordModel) TableName() string {
+	return "sync_records"
+}
+
+func (SyncCounterModel) TableName() string {
+	return "sync_counters"
+}
+
+func (SyncDeviceModel) TableName() string {
+	return "sync_devices"
+}
+
+func (PantryItemModel) TableName() string {
+	return "pantry_items"
+}
+
+func (CookLogModel) TableName() string {
+	return "cook_logs"
+}
+
+func (PantryConsumptionModel) TableName() string {
+	return "pantry_consumption"
+}
+
+func (UploadScanModel) TableName() string {
+	return "upload_scans"
+}
+
+func (VerificationClaimModel) TableName() string {
+	return "verification_claims"
+}
+
+func (VerificationReportModel) TableName() string {
+	return "verification_reports"
+}
+
+func (CommentReactionModel) TableName() string {
+	return "comment_reactions"
+}
+
+func (ReviewVoteModel) TableName() string {
+	return "review_votes"
+}
+
+func (RecipeDiscussionModel) TableName() string {
+	return "recipe_discussions"
+}
+
+func (CommentBlockModel) TableName() string {
+	return "comment_blocks"
+}
+
+func (CommentModerationEventModel) TableName() string {
+	return "comment_moderation_events"
+}
+
+func (CommentFlagModel) TableName() string {
+	return "comment_flags"
+}
+
+func (ReportModel) TableName() string {
+	return "reports"
+}
+
+func (DuplicateCheckModel) TableName() string {
+	return "duplicate_checks"
+}
+
+func (DuplicateFlagModel) TableName() string {
+	return "duplicate_flags"
+}
+
+func (RecipeEmbeddingModel) TableName() string {
+	return "recipe_embeddings"
+}
+
+func (AccountDeletionModel) TableName() string {
+	return "account_deletions"
+}
+
+func (AccountAuditEventModel) TableName() string {
+	return "account_audit_events"
+}
@@ -0,0 +1,93 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/notification"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationRepository implements outbound.NotificationRepository using GORM
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *gorm.DB) outbound.NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Save stores a notification
+func (r *NotificationRepository) Save(ctx context.Context, n *notification.Notification) error {
+	return r.db.WithContext(ctx).Create(&NotificationModel{
+		ID:        n.ID,
+		UserID:    n.UserID,
+		ActorID:   n.ActorID,
+		Type:      string(n.Type),
+		Title:     n.Title,
+		Message:   n.Message,
+		Link:      n.Link,
+		IsRead:    n.ReadAt != nil,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}).Error
+}
+
+// List returns a user's notifications newest first
+func (r *NotificationRepository) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, offset, limit int) ([]*notification.Notification, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+	var models []NotificationModel
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	notifications := make([]*notification.Notification, 0, len(models))
+	for _, m := range models {
+		notifications = append(notifications, &notification.Notification{
+			ID:        m.ID,
+			UserID:    m.UserID,
+			ActorID:   m.ActorID,
+			Type:      notification.Type(m.Type),
+			Title:     m.Title,
+			Message:   m.Message,
+			Link:      m.Link,
+			ReadAt:    m.ReadAt,
+			CreatedAt: m.CreatedAt,
+		})
+	}
+	return notifications, nil
+}
+
+// CountUnread counts a user's unread notifications
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&NotificationModel{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Count(&count).Error
+	return int(count), err
+}
+
+// MarkRead marks some of a user's notifications read
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Model(&NotificationModel{}).
+		Where("user_id = ? AND is_read = ? AND id IN ?", userID, false, ids).
+		Updates(map[string]interface{}{"is_read": true, "read_at": at})
+	return int(result.RowsAffected), result.Error
+}
+
+// MarkAllRead marks all of a user's notifications read
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID, at time.Time) (int, error) {
+	result := r.db.WithContext(ctx).Model(&NotificationModel{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Updates(map[string]interface{}{"is_read": true, "read_at": at})
+	return int(result.RowsAffected), result.Error
+}
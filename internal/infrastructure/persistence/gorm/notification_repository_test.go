@@ -0,0 +1,55 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/notification"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationsListNewestFirstAndMarkRead(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&NotificationModel{}))
+	repo := NewNotificationRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	chef, other := uuid.New(), uuid.New()
+
+	var saved []*notification.Notification
+	for i, owner := range []uuid.UUID{chef, chef, chef, other} {
+		n, err := notification.New(owner, nil, notification.TypeRecipeLiked, "Your recipe was liked", "", "/recipes/1", now.Add(time.Duration(i)*time.Minute))
+		require.NoError(t, err)
+		require.NoError(t, repo.Save(ctx, n))
+		saved = append(saved, n)
+	}
+
+	list, err := repo.List(ctx, chef, false, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, saved[2].ID, list[0].ID)
+	assert.Equal(t, "/recipes/1", list[0].Link)
+
+	marked, err := repo.MarkRead(ctx, chef, []uuid.UUID{saved[0].ID, saved[3].ID}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, marked, "another user's notification is left alone")
+	unread, err := repo.CountUnread(ctx, chef)
+	require.NoError(t, err)
+	assert.Equal(t, 2, unread)
+	list, err = repo.List(ctx, chef, true, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	marked, err = repo.MarkAllRead(ctx, chef, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, marked)
+	unread, err = repo.CountUnread(ctx, chef)
+	require.NoError(t, err)
+	assert.Zero(t, unread)
+	unread, err = repo.CountUnread(ctx, other)
+	require.NoError(t, err)
+	assert.Equal(t, 1, unread)
+}
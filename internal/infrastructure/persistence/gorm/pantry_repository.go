@@ -0,0 +1,184 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/pantry"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PantryRepository stores pantry items and cook logs using GORM
+type PantryRepository struct {
+	db *gorm.DB
+}
+
+// NewPantryRepository creates a new pantry repository
+func NewPantryRepository(db *gorm.DB) outbound.PantryRepository {
+	return &PantryRepository{db: db}
+}
+
+// FindItems returns the user's items by name
+func (r *PantryRepository) FindItems(ctx context.Context, userID uuid.UUID) ([]*pantry.Item, error) {
+	var models []PantryItemModel
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*pantry.Item, len(models))
+	for i := range models {
+		items[i] = modelToPantryItem(&models[i])
+	}
+	return items, nil
+}
+
+// FindItem returns nil when the user has no such item
+func (r *PantryRepository) FindItem(ctx context.Context, userID, itemID uuid.UUID) (*pantry.Item, error) {
+	var model PantryItemModel
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", itemID, userID).
+		First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return modelToPantryItem(&model), nil
+}
+
+// CountItems counts the user's items
+func (r *PantryRepository) CountItems(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&PantryItemModel{}).Where("user_id = ?", userID).Count(&count).Error
+	return int(count), err
+}
+
+// SaveItem inserts the item or updates its details
+func (r *PantryRepository) SaveItem(ctx context.Context, item *pantry.Item) error {
+	model := &PantryItemModel{
+		ID:        item.ID,
+		UserID:    item.UserID,
+		Name:      item.Name,
+		Quantity:  item.Quantity,
+		Unit:      string(item.Unit),
+		Par:       item.Par,
+		CreatedAt: item.UpdatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "quantity", "unit", "par", "updated_at"}),
+		}).
+		Create(model).Error
+}
+
+// DeleteItem returns false when the user has no such item
+func (r *PantryRepository) DeleteItem(ctx context.Context, userID, itemID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", itemID, userID).
+		Delete(&PantryItemModel{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RecordCook logs the cook and takes what it used from each item. The
+// decrement is done in SQL so cooks saved at the same time both count.
+func (r *PantryRepository) RecordCook(ctx context.Context, cook *pantry.Cook) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&CookLogModel{
+			ID:       cook.ID,
+			UserID:   cook.UserID,
+			RecipeID: cook.RecipeID,
+			Portions: cook.Portions,
+			CookedAt: cook.CookedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, used := range cook.Used {
+			err := tx.Create(&PantryConsumptionModel{
+				CookID:   cook.ID,
+				UserID:   cook.UserID,
+				RecipeID: cook.RecipeID,
+				ItemID:   used.ItemID,
+				ItemName: used.ItemName,
+				Amount:   used.Amount,
+				Unit:     string(used.Unit),
+				CookedAt: used.CookedAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Model(&PantryItemModel{}).
+				Where("id = ? AND user_id = ?", used.ItemID, cook.UserID).
+				Updates(map[string]interface{}{
+					"quantity":   gorm.Expr("CASE WHEN quantity > ? THEN quantity - ? ELSE 0 END", used.Amount, used.Amount),
+					"updated_at": cook.CookedAt,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindConsumption returns what the user's cooks used since the time,
+// newest first
+func (r *PantryRepository) FindConsumption(ctx context.Context, userID uuid.UUID, since time.Time) ([]pantry.Consumption, error) {
+	var models []PantryConsumptionModel
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND cooked_at >= ?", userID, since).
+		Order("cooked_at DESC, id DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	consumption := make([]pantry.Consumption, len(models))
+	for i, model := range models {
+		consumption[i] = pantry.Consumption{
+			CookID:   model.CookID,
+			RecipeID: model.RecipeID,
+			ItemID:   model.ItemID,
+			ItemName: model.ItemName,
+			Amount:   model.Amount,
+			Unit:     recipe.MeasurementUnit(model.Unit),
+			CookedAt: model.CookedAt,
+		}
+	}
+	return consumption, nil
+}
+
+// CountCooks counts the user's cooks since the time
+func (r *PantryRepository) CountCooks(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&CookLogModel{}).
+		Where("user_id = ? AND cooked_at >= ?", userID, since).
+		Count(&count).Error
+	return int(count), err
+}
+
+func modelToPantryItem(model *PantryItemModel) *pantry.Item {
+	return &pantry.Item{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Name:      model.Name,
+		Quantity:  model.Quantity,
+		Unit:      recipe.MeasurementUnit(model.Unit),
+		Par:       model.Par,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
@@ -0,0 +1,58 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/pantry"
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPantryRepositoryRecordsCooksWithoutGoingNegative(t *testing.T) {
+	db, recipeID := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&PantryItemModel{}, &CookLogModel{}, &PantryConsumptionModel{}))
+	repo := NewPantryRepository(db)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	flour, err := pantry.NewItem(userID, "flour", 1000, recipe.MeasurementUnitGram, 2000)
+	require.NoError(t, err)
+	eggs, err := pantry.NewItem(userID, "eggs", 2, "", 12)
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveItem(ctx, flour))
+	require.NoError(t, repo.SaveItem(ctx, eggs))
+
+	require.NoError(t, flour.Update("plain flour", 900, recipe.MeasurementUnitGram, 2000))
+	require.NoError(t, repo.SaveItem(ctx, flour))
+
+	cookedAt := time.Date(2026, 10, 18, 18, 0, 0, 0, time.UTC)
+	cook := pantry.NewCook(userID, recipeID, 4, cookedAt)
+	require.NoError(t, cook.Take(flour, 250))
+	require.NoError(t, cook.Take(eggs, 3))
+	require.NoError(t, repo.RecordCook(ctx, cook))
+
+	items, err := repo.FindItems(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "eggs", items[0].Name)
+	assert.Zero(t, items[0].Quantity, "a cook never takes an item below zero")
+	assert.Equal(t, "plain flour", items[1].Name)
+	assert.Equal(t, 650.0, items[1].Quantity)
+
+	used, err := repo.FindConsumption(ctx, userID, cookedAt.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, used, 2)
+	assert.Equal(t, 3.0, used[0].Amount, "history keeps what the cook confirmed")
+
+	cooks, err := repo.CountCooks(ctx, userID, cookedAt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cooks)
+
+	deleted, err := repo.DeleteItem(ctx, uuid.New(), flour.ID)
+	require.NoError(t, err)
+	assert.False(t, deleted, "only the owner deletes an item")
+}
@@ -0,0 +1,85 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PasswordResetTokenRepository implements outbound.PasswordResetTokenRepository using GORM
+type PasswordResetTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetTokenRepository creates a new password reset token repository
+func NewPasswordResetTokenRepository(db *gorm.DB) outbound.PasswordResetTokenRepository {
+	return &PasswordResetTokenRepository{db: db}
+}
+
+// Create stores a token
+func (r *PasswordResetTokenRepository) Create(ctx context.Context, token outbound.PasswordResetToken) error {
+	model := PasswordResetTokenModel{
+		TokenHash: token.Hash,
+		UserID:    token.UserID,
+		Email:     strings.ToLower(strings.TrimSpace(token.Email)),
+		ExpiresAt: token.ExpiresAt,
+		UsedAt:    token.UsedAt,
+		CreatedAt: token.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// FindByHash finds a token, returning nil when it does not exist
+func (r *PasswordResetTokenRepository) FindByHash(ctx context.Context, hash string) (*outbound.PasswordResetToken, error) {
+	var model PasswordResetTokenModel
+
+	result := r.db.WithContext(ctx).First(&model, "token_hash = ?", hash)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return &outbound.PasswordResetToken{
+		Hash:      model.TokenHash,
+		UserID:    model.UserID,
+		Email:     model.Email,
+		ExpiresAt: model.ExpiresAt,
+		UsedAt:    model.UsedAt,
+		CreatedAt: model.CreatedAt,
+	}, nil
+}
+
+// Consume marks an unused, unexpired token used in one statement, so two
+// requests racing with the same token cannot both succeed
+func (r *PasswordResetTokenRepository) Consume(ctx context.Context, hash string, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&PasswordResetTokenModel{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hash, now).
+		Update("used_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+// CountSince counts the tokens issued to an address since a time
+func (r *PasswordResetTokenRepository) CountSince(ctx context.Context, email string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&PasswordResetTokenModel{}).
+		Where("email = ? AND created_at > ?", strings.ToLower(strings.TrimSpace(email)), since).
+		Count(&count).Error
+	return count, err
+}
+
+// RevokeForUser marks the user's unused tokens used
+func (r *PasswordResetTokenRepository) RevokeForUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&PasswordResetTokenModel{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", now).Error
+}
@@ -0,0 +1,75 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepositoryRoundTripsPasswordChanges(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	account, err := user.NewUser("sam@example.com", "Sam", "old-password")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, account))
+
+	loaded, err := repo.FindByEmail(ctx, "Sam@Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, account.ID(), loaded.ID())
+	require.NoError(t, loaded.CheckPassword("old-password"))
+
+	require.NoError(t, loaded.UpdatePassword("new-password"))
+	require.NoError(t, repo.Update(ctx, loaded))
+
+	reloaded, err := repo.FindByID(ctx, account.ID())
+	require.NoError(t, err)
+	assert.NoError(t, reloaded.CheckPassword("new-password"))
+	assert.Error(t, reloaded.CheckPassword("old-password"))
+}
+
+func TestPasswordResetTokensAreSingleUse(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&PasswordResetTokenModel{}))
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	repo := NewPasswordResetTokenRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, hash := range []string{"a", "b", "c"} {
+		require.NoError(t, repo.Create(ctx, outbound.PasswordResetToken{
+			Hash: hash, UserID: author.ID, Email: "Ada@Example.com",
+			ExpiresAt: now.Add(time.Hour), CreatedAt: now,
+		}))
+	}
+
+	issued, err := repo.CountSince(ctx, "ada@example.com", now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), issued)
+
+	consumed, err := repo.Consume(ctx, "a", now)
+	require.NoError(t, err)
+	assert.True(t, consumed)
+	consumed, err = repo.Consume(ctx, "a", now)
+	require.NoError(t, err)
+	assert.False(t, consumed, "a token works once")
+	consumed, err = repo.Consume(ctx, "b", now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.False(t, consumed, "expired tokens do not work")
+
+	require.NoError(t, repo.RevokeForUser(ctx, author.ID, now))
+	token, err := repo.FindByHash(ctx, "c")
+	require.NoError(t, err)
+	require.NotNil(t, token.UsedAt)
+
+	missing, err := repo.FindByHash(ctx, "nope")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
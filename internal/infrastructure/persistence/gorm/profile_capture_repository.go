@@ -0,0 +1,110 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProfileCaptureRepository implements profiling capture storage using GORM
+type ProfileCaptureRepository struct {
+	db *gorm.DB
+}
+
+// NewProfileCaptureRepository creates a new profile capture repository
+func NewProfileCaptureRepository(db *gorm.DB) outbound.ProfileCaptureRepository {
+	return &ProfileCaptureRepository{db: db}
+}
+
+// Create records a new capture
+func (r *ProfileCaptureRepository) Create(ctx context.Context, capture *outbound.ProfileCapture) error {
+	model := profileCaptureToModel(capture)
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// Update records the outcome of a capture
+func (r *ProfileCaptureRepository) Update(ctx context.Context, capture *outbound.ProfileCapture) error {
+	return r.db.WithContext(ctx).
+		Model(&ProfileCaptureModel{}).
+		Where("id = ?", capture.ID).
+		Updates(map[string]interface{}{
+			"status":      capture.Status,
+			"blob_key":    capture.BlobKey,
+			"size_bytes":  capture.SizeBytes,
+			"error":       capture.Error,
+			"finished_at": capture.FinishedAt,
+		}).Error
+}
+
+// FindByID finds a capture by ID
+func (r *ProfileCaptureRepository) FindByID(ctx context.Context, id uuid.UUID) (*outbound.ProfileCapture, error) {
+	var model ProfileCaptureModel
+
+	result := r.db.WithContext(ctx).First(&model, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	return modelToProfileCapture(model), nil
+}
+
+// FindRecent returns the latest captures, newest first
+func (r *ProfileCaptureRepository) FindRecent(ctx context.Context, limit int) ([]*outbound.ProfileCapture, error) {
+	var models []ProfileCaptureModel
+
+	result := r.db.WithContext(ctx).
+		Order("started_at DESC").
+		Limit(limit).
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	captures := make([]*outbound.ProfileCapture, len(models))
+	for i, model := range models {
+		captures[i] = modelToProfileCapture(model)
+	}
+
+	return captures, nil
+}
+
+func profileCaptureToModel(capture *outbound.ProfileCapture) ProfileCaptureModel {
+	return ProfileCaptureModel{
+		ID:          capture.ID,
+		Kind:        capture.Kind,
+		Seconds:     capture.Seconds,
+		Status:      capture.Status,
+		RequestedBy: capture.RequestedBy,
+		IPAddress:   capture.IPAddress,
+		Reason:      capture.Reason,
+		BlobKey:     capture.BlobKey,
+		SizeBytes:   capture.SizeBytes,
+		Error:       capture.Error,
+		StartedAt:   capture.StartedAt,
+		FinishedAt:  capture.FinishedAt,
+	}
+}
+
+func modelToProfileCapture(model ProfileCaptureModel) *outbound.ProfileCapture {
+	return &outbound.ProfileCapture{
+		ID:          model.ID,
+		Kind:        model.Kind,
+		Seconds:     model.Seconds,
+		Status:      model.Status,
+		RequestedBy: model.RequestedBy,
+		IPAddress:   model.IPAddress,
+		Reason:      model.Reason,
+		BlobKey:     model.BlobKey,
+		SizeBytes:   model.SizeBytes,
+		Error:       model.Error,
+		StartedAt:   model.StartedAt,
+		FinishedAt:  model.FinishedAt,
+	}
+}
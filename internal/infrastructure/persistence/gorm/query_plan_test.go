@@ -0,0 +1,182 @@
+//go:build integration
+// +build integration
+
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// planNode is one node of an EXPLAIN (FORMAT JSON) plan
+type planNode struct {
+	NodeType     string     `json:"Node Type"`
+	RelationName string     `json:"Relation Name"`
+	IndexName    string     `json:"Index Name"`
+	Plans        []planNode `json:"Plans"`
+}
+
+// capturedQuery is a statement the repository built in dry-run mode
+type capturedQuery struct {
+	SQL  string
+	Vars []interface{}
+}
+
+// TestHotPathQueryPlans checks that the recipe listing and search queries
+// are served by the hot path indexes. It runs against the PostgreSQL named
+// by ALCHEMORSEL_TEST_POSTGRES_DSN in a throwaway schema.
+func TestHotPathQueryPlans(t *testing.T) {
+	dsn := os.Getenv("ALCHEMORSEL_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("ALCHEMORSEL_TEST_POSTGRES_DSN not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+
+	indexes, err := os.ReadFile("../migrations/sql/000010_hot_path_indexes.up.sql")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("plan_test_%d", time.Now().UnixNano())
+
+	err = db.Connection(func(conn *gorm.DB) error {
+		defer conn.Exec("DROP SCHEMA IF EXISTS " + schema + " CASCADE")
+
+		require.NoError(t, conn.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error)
+		require.NoError(t, conn.Exec("CREATE SCHEMA "+schema).Error)
+		require.NoError(t, conn.Exec("SET search_path TO "+schema+", public").Error)
+		require.NoError(t, conn.AutoMigrate(&UserModel{}, &RecipeModel{}, &RecipeLikeModel{}, &RecipeCounterShardModel{}))
+		require.NoError(t, conn.Exec(string(indexes)).Error)
+		// As in 000037_recipe_search_vector, which also backfills tables
+		// the models do not have
+		require.NoError(t, conn.Exec("CREATE INDEX idx_recipes_search_vector ON recipes USING gin(search_vector) WHERE deleted_at IS NULL").Error)
+		// Empty tables are cheapest to scan, so plans are only meaningful
+		// once sequential scans are priced out
+		require.NoError(t, conn.Exec("SET enable_seqscan = off").Error)
+
+		var captured []capturedQuery
+		dry := conn.Session(&gorm.Session{DryRun: true})
+		require.NoError(t, dry.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+			captured = append(captured, capturedQuery{
+				SQL:  tx.Statement.SQL.String(),
+				Vars: append([]interface{}(nil), tx.Statement.Vars...),
+			})
+		}))
+		repo := &RecipeRepository{db: dry, hot: dry}
+
+		cases := []struct {
+			name    string
+			run     func() error
+			indexes []string
+		}{
+			{
+				name: "by author",
+				run: func() error {
+					_, _, err := repo.FindByUserID(ctx, uuid.New(), 0, 20)
+					return err
+				},
+				indexes: []string{"idx_recipes_author_created"},
+			},
+			{
+				name: "published",
+				run: func() error {
+					_, _, err := repo.FindPublished(ctx, 0, 20)
+					return err
+				},
+				indexes: []string{"idx_recipes_published_created"},
+			},
+			{
+				name: "search",
+				run: func() error {
+					_, _, err := repo.Search(ctx, outbound.SearchCriteria{Query: "pasta", Limit: 20})
+					return err
+				},
+				indexes: []string{"idx_recipes_search_vector", "idx_recipes_published_created"},
+			},
+			{
+				name: "liked by user",
+				run: func() error {
+					_, err := repo.FindLikedByUser(ctx, uuid.New(), 20)
+					return err
+				},
+				indexes: []string{"idx_recipe_likes_user_created"},
+			},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				captured = nil
+				require.NoError(t, tc.run())
+				require.NotEmpty(t, captured)
+
+				// Counts may use any index on the filter column; the page query
+				// must use one that also serves its order
+				var all []string
+				for _, q := range captured {
+					used := explainIndexes(t, ctx, conn, q)
+					assert.NotContains(t, used, "seq:recipes", "%s scans recipes sequentially", q.SQL)
+					all = append(all, used...)
+				}
+				assert.True(t, containsAny(all, tc.indexes), "plans used %v, want one of %v", all, tc.indexes)
+			})
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// explainIndexes returns the indexes a statement's plan reads, with
+// sequential scans reported as seq:<table>
+func explainIndexes(t *testing.T, ctx context.Context, conn *gorm.DB, q capturedQuery) []string {
+	t.Helper()
+
+	var raw string
+	row := conn.Statement.ConnPool.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+q.SQL, q.Vars...)
+	require.NoError(t, row.Scan(&raw))
+
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(raw), &plans))
+
+	var used []string
+	var walk func(n planNode)
+	walk = func(n planNode) {
+		if n.IndexName != "" {
+			used = append(used, n.IndexName)
+		}
+		if n.NodeType == "Seq Scan" {
+			used = append(used, "seq:"+n.RelationName)
+		}
+		for _, child := range n.Plans {
+			walk(child)
+		}
+	}
+	for _, p := range plans {
+		walk(p.Plan)
+	}
+	return used
+}
+
+func containsAny(values, wanted []string) bool {
+	for _, v := range values {
+		for _, w := range wanted {
+			if v == w {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecipeAllergenRepository reads recipe ingredients for allergen
+// disclosure using GORM
+type RecipeAllergenRepository struct {
+	db *gorm.DB
+}
+
+// NewRecipeAllergenRepository creates a new recipe allergen repository
+func NewRecipeAllergenRepository(db *gorm.DB) outbound.RecipeAllergenRepository {
+	return &RecipeAllergenRepository{db: db}
+}
+
+// Recipe reads a recipe's ingredients
+func (r *RecipeAllergenRepository) Recipe(ctx context.Context, recipeID uuid.UUID) (*outbound.AllergenRecipe, error) {
+	var models []RecipeModel
+	err := r.db.WithContext(ctx).
+		Select("id, author_id, title, status, ingredients").
+		Where("id = ?", recipeID).
+		Limit(1).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	model := models[0]
+	return &outbound.AllergenRecipe{
+		ID:          model.ID,
+		AuthorID:    model.AuthorID,
+		Title:       model.Title,
+		Status:      model.Status,
+		Ingredients: ingredientLines(model.Ingredients),
+	}, nil
+}
+
+// Unlabelled reads the recipes whose allergens are unset, or the empty
+// object a recipe without a label was saved with
+func (r *RecipeAllergenRepository) Unlabelled(ctx context.Context, limit int) ([]*outbound.AllergenRecipe, error) {
+	var models []RecipeModel
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Select("id, author_id, title, status, ingredients").
+		Where("allergens IS NULL OR CAST(allergens AS TEXT) = '{}'").
+		Order("id").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make([]*outbound.AllergenRecipe, len(models))
+	for i, model := range models {
+		recipes[i] = &outbound.AllergenRecipe{
+			ID:          model.ID,
+			AuthorID:    model.AuthorID,
+			Title:       model.Title,
+			Status:      model.Status,
+			Ingredients: ingredientLines(model.Ingredients),
+		}
+	}
+	return recipes, nil
+}
+
+// SetLabel stores the label without moving the recipe's updated_at
+func (r *RecipeAllergenRepository) SetLabel(ctx context.Context, recipeID uuid.UUID, label *recipe.AllergenLabel) error {
+	return r.db.WithContext(ctx).
+		Unscoped().
+		Model(&RecipeModel{}).
+		Where("id = ?", recipeID).
+		UpdateColumn("allergens", JSONField(convertAllergensToJSON(label))).Error
+}
+
+// ingredientLines reads the ingredient objects the recipe mapper stores
+func ingredientLines(value JSONField) []outbound.IngredientLine {
+	for _, key := range []string{"data", "ingredients"} {
+		list, ok := value[key].([]interface{})
+		if !ok {
+			continue
+		}
+		lines := make([]outbound.IngredientLine, 0, len(list))
+		for _, item := range list {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := object["name"].(string)
+			if name == "" {
+				continue
+			}
+			optional, _ := object["optional"].(bool)
+			notes, _ := object["notes"].(string)
+			lines = append(lines, outbound.IngredientLine{Name: name, Optional: optional, Notes: notes})
+		}
+		return lines
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeAllergenRepositoryFindsAndLabelsUnlabelledRecipes(t *testing.T) {
+	db, id := newCounterFixture(t)
+	repo := NewRecipeAllergenRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, db.Model(&RecipeModel{}).Where("id = ?", id).
+		UpdateColumn("ingredients", JSONField{"data": []interface{}{map[string]interface{}{"name": "peanut butter"}}}).Error)
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	labelled := RecipeModel{
+		ID: uuid.New(), Title: "Plain Rice", AuthorID: author.ID, Status: "published",
+		Allergens: JSONField(convertAllergensToJSON(&recipe.AllergenLabel{Contains: []string{}, MayContain: []string{}})),
+	}
+	require.NoError(t, db.Create(&labelled).Error)
+
+	unlabelled, err := repo.Unlabelled(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, unlabelled, 1)
+	assert.Equal(t, id, unlabelled[0].ID)
+	require.Len(t, unlabelled[0].Ingredients, 1)
+	assert.Equal(t, "peanut butter", unlabelled[0].Ingredients[0].Name)
+
+	require.NoError(t, repo.SetLabel(ctx, id, &recipe.AllergenLabel{Contains: []string{"peanuts"}, MayContain: []string{}}))
+
+	unlabelled, err = repo.Unlabelled(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, unlabelled)
+
+	var model RecipeModel
+	require.NoError(t, db.Select(recipeColumns).First(&model, "id = ?", id).Error)
+	label := allergensFromJSON(model.Allergens)
+	require.NotNil(t, label)
+	assert.Equal(t, []string{"peanuts"}, label.Contains)
+}
@@ -0,0 +1,379 @@
+// Package gorm provides GORM-based repository implementations
+package gorm
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecipeAnalyticsRepository implements the recipe analytics interface using GORM
+type RecipeAnalyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewRecipeAnalyticsRepository creates a new recipe analytics repository
+func NewRecipeAnalyticsRepository(db *gorm.DB) outbound.RecipeAnalyticsRepository {
+	return &RecipeAnalyticsRepository{db: db}
+}
+
+// RecordView stores one recipe page view
+func (r *RecipeAnalyticsRepository) RecordView(ctx context.Context, view outbound.RecipeView) error {
+	model := RecipeViewModel{
+		ID:           view.ID,
+		RecipeID:     view.RecipeID,
+		UserID:       view.UserID,
+		SessionID:    optionalString(view.SessionID),
+		UserAgent:    view.UserAgent,
+		Referrer:     view.Referrer,
+		ReferrerHost: view.ReferrerHost,
+		SearchTerm:   optionalString(view.SearchTerm),
+		ViewedAt:     view.ViewedAt,
+	}
+
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// RecordEngagement stores the scroll depth and time on page reported when the
+// visitor leaves. Only a deeper scroll replaces an earlier report.
+func (r *RecipeAnalyticsRepository) RecordEngagement(ctx context.Context, recipeID, viewID uuid.UUID, scrollDepth int, engaged time.Duration) error {
+	result := r.db.WithContext(ctx).
+		Model(&RecipeViewModel{}).
+		Where("id = ? AND recipe_id = ?", viewID, recipeID).
+		Where("scroll_depth IS NULL OR scroll_depth <= ?", scrollDepth).
+		Updates(map[string]interface{}{
+			"scroll_depth": scrollDepth,
+			"engaged_ms":   int(engaged / time.Millisecond),
+		})
+
+	return result.Error
+}
+
+// RecipeViewStats aggregates views, likes, bookmarks and attribution for a
+// recipe. Days are bucketed and scroll depth averaged here rather than in
+// SQL, so the query runs unchanged on PostgreSQL and SQLite and the views
+// are read in a single pass.
+func (r *RecipeAnalyticsRepository) RecipeViewStats(ctx context.Context, recipeID uuid.UUID, since time.Time, limit int) (*outbound.RecipeViewStats, error) {
+	db := r.db.WithContext(ctx)
+	stats := &outbound.RecipeViewStats{}
+
+	rows, err := db.Model(&RecipeViewModel{}).
+		Select("id, user_id, session_id, viewed_at, scroll_depth").
+		Where("recipe_id = ? AND viewed_at >= ?", recipeID, since).
+		Order("viewed_at").
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	viewers := make(map[string]bool)
+	dayViewers := make(map[string]bool)
+	var day *outbound.DailyViewStat
+	var scrollTotal int
+	for rows.Next() {
+		var view RecipeViewModel
+		if err := db.ScanRows(rows, &view); err != nil {
+			return nil, err
+		}
+
+		viewer := viewerKey(view)
+		viewedOn := view.ViewedAt.UTC().Truncate(24 * time.Hour)
+		if day == nil || !day.Day.Equal(viewedOn) {
+			stats.Daily = append(stats.Daily, outbound.DailyViewStat{Day: viewedOn})
+			day = &stats.Daily[len(stats.Daily)-1]
+			dayViewers = make(map[string]bool)
+		}
+
+		day.Views++
+		stats.Views++
+		if !dayViewers[viewer] {
+			dayViewers[viewer] = true
+			day.UniqueViewers++
+		}
+		if !viewers[viewer] {
+			viewers[viewer] = true
+			stats.UniqueViewers++
+		}
+		if view.ScrollDepth != nil {
+			scrollTotal += *view.ScrollDepth
+			stats.ScrollSamples++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var likes int64
+	if err := db.Model(&RecipeLikeModel{}).
+		Where("recipe_id = ? AND created_at >= ?", recipeID, since).
+		Count(&likes).Error; err != nil {
+		return nil, err
+	}
+	stats.Likes = int(likes)
+
+	var bookmarks int64
+	if err := db.Table("collection_recipes AS cr").
+		Joins("JOIN collections AS c ON c.id = cr.collection_id").
+		Where("cr.recipe_id = ? AND cr.added_at >= ?", recipeID, since).
+		Distinct("c.user_id").
+		Count(&bookmarks).Error; err != nil {
+		return nil, err
+	}
+	stats.Bookmarks = int(bookmarks)
+
+	if stats.SearchTerms, err = r.topCounts(ctx, "search_term", recipeID, since, limit); err != nil {
+		return nil, err
+	}
+	if stats.Referrers, err = r.topCounts(ctx, "COALESCE(NULLIF(referrer_host, ''), 'direct')", recipeID, since, limit); err != nil {
+		return nil, err
+	}
+
+	if stats.ScrollSamples > 0 {
+		stats.AvgScrollDepth = float64(scrollTotal) / float64(stats.ScrollSamples)
+	}
+
+	return stats, nil
+}
+
+// RecentlyViewed returns the recipes a user viewed, each once, by when
+// they last opened it
+func (r *RecipeAnalyticsRepository) RecentlyViewed(ctx context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	var rows []struct {
+		RecipeID uuid.UUID
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&RecipeViewModel{}).
+		Select("recipe_id, MAX(viewed_at) AS last_viewed").
+		Where("user_id = ?", userID).
+		Group("recipe_id").
+		Order("last_viewed DESC").
+		Limit(limit).
+		Scan(&rows)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.RecipeID
+	}
+
+	return ids, nil
+}
+
+// AddDailyViews adds each count to its recipe's total for the day
+func (r *RecipeAnalyticsRepository) AddDailyViews(ctx context.Context, counts []outbound.ViewCount) error {
+	db := r.db.WithContext(ctx)
+	merged, err := mergeDailyCounts(db, counts)
+	if err != nil || len(merged) == 0 {
+		return err
+	}
+	models := make([]RecipeDailyViewModel, 0, len(merged))
+	for key, views := range merged {
+		models = append(models, RecipeDailyViewModel{RecipeID: key.recipeID, Day: key.day, Views: views})
+	}
+	return db.Clauses(dailyUpsert("recipe_daily_views", "views")).CreateInBatches(models, 500).Error
+}
+
+// AddDailyImpressions adds each count to its recipe's search listings for
+// the day
+func (r *RecipeAnalyticsRepository) AddDailyImpressions(ctx context.Context, counts []outbound.ViewCount) error {
+	db := r.db.WithContext(ctx)
+	merged, err := mergeDailyCounts(db, counts)
+	if err != nil || len(merged) == 0 {
+		return err
+	}
+	models := make([]RecipeDailyImpressionModel, 0, len(merged))
+	for key, impressions := range merged {
+		models = append(models, RecipeDailyImpressionModel{RecipeID: key.recipeID, Day: key.day, Impressions: impressions})
+	}
+	return db.Clauses(dailyUpsert("recipe_daily_impressions", "impressions")).CreateInBatches(models, 500).Error
+}
+
+// dailyKey is a recipe on a UTC day
+type dailyKey struct {
+	recipeID uuid.UUID
+	day      time.Time
+}
+
+// mergeDailyCounts sums the counts per recipe and day, as an upsert may not
+// touch a row twice, and drops counts of recipes deleted since
+func mergeDailyCounts(db *gorm.DB, counts []outbound.ViewCount) (map[dailyKey]int, error) {
+	var ids []uuid.UUID
+	listed := make(map[uuid.UUID]bool, len(counts))
+	for _, count := range counts {
+		if !listed[count.RecipeID] {
+			listed[count.RecipeID] = true
+			ids = append(ids, count.RecipeID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var existing []uuid.UUID
+	if err := db.Unscoped().Model(&RecipeModel{}).Where("id IN ?", ids).Pluck("id", &existing).Error; err != nil {
+		return nil, err
+	}
+	exists := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		exists[id] = true
+	}
+
+	merged := make(map[dailyKey]int, len(counts))
+	for _, count := range counts {
+		if exists[count.RecipeID] {
+			merged[dailyKey{recipeID: count.RecipeID, day: count.Day.UTC().Truncate(24 * time.Hour)}] += count.Views
+		}
+	}
+	return merged, nil
+}
+
+// dailyUpsert adds to the count column of an existing recipe and day row
+func dailyUpsert(table, column string) clause.OnConflict {
+	return clause.OnConflict{
+		Columns:   []clause.Column{{Name: "recipe_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{column: gorm.Expr(table + "." + column + " + excluded." + column)}),
+	}
+}
+
+// AuthorStats reads the views, likes, search listings and ratings of an
+// author's recipes per day. Likes and ratings are bucketed into days here,
+// as on the recipe stats page, so the queries run unchanged on PostgreSQL
+// and SQLite.
+func (r *RecipeAnalyticsRepository) AuthorStats(ctx context.Context, authorID uuid.UUID, since time.Time) (*outbound.AuthorStats, error) {
+	db := r.db.WithContext(ctx)
+	stats := &outbound.AuthorStats{}
+
+	var recipes []RecipeModel
+	if err := db.Select("id, title, average_rating").
+		Where("author_id = ?", authorID).
+		Order("title, id").
+		Find(&recipes).Error; err != nil {
+		return nil, err
+	}
+	if len(recipes) == 0 {
+		return stats, nil
+	}
+	ids := make([]uuid.UUID, len(recipes))
+	for i, model := range recipes {
+		ids[i] = model.ID
+		stats.Recipes = append(stats.Recipes, outbound.AuthorRecipe{ID: model.ID, Title: model.Title, AverageRating: model.AverageRating})
+	}
+
+	days := make(map[dailyKey]*outbound.RecipeDayStat)
+	day := func(recipeID uuid.UUID, at time.Time) *outbound.RecipeDayStat {
+		key := dailyKey{recipeID: recipeID, day: at.UTC().Truncate(24 * time.Hour)}
+		if days[key] == nil {
+			days[key] = &outbound.RecipeDayStat{RecipeID: key.recipeID, Day: key.day}
+		}
+		return days[key]
+	}
+
+	var views []RecipeDailyViewModel
+	if err := db.Where("recipe_id IN ? AND day >= ?", ids, since).Find(&views).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range views {
+		day(row.RecipeID, row.Day).Views += row.Views
+	}
+
+	var impressions []RecipeDailyImpressionModel
+	if err := db.Where("recipe_id IN ? AND day >= ?", ids, since).Find(&impressions).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range impressions {
+		day(row.RecipeID, row.Day).Impressions += row.Impressions
+	}
+
+	var likes []RecipeLikeModel
+	if err := db.Select("recipe_id, created_at").
+		Where("recipe_id IN ? AND created_at >= ?", ids, since).
+		Find(&likes).Error; err != nil {
+		return nil, err
+	}
+	for _, like := range likes {
+		day(like.RecipeID, like.CreatedAt).Likes++
+	}
+
+	var ratings []RatingModel
+	if err := db.Select("recipe_id, value, created_at").
+		Where("recipe_id IN ? AND created_at >= ?", ids, since).
+		Find(&ratings).Error; err != nil {
+		return nil, err
+	}
+	for _, rating := range ratings {
+		stat := day(rating.RecipeID, rating.CreatedAt)
+		stat.Ratings++
+		stat.RatingTotal += rating.Value
+	}
+
+	for _, stat := range days {
+		stats.Daily = append(stats.Daily, *stat)
+	}
+	sort.Slice(stats.Daily, func(i, j int) bool {
+		a, b := stats.Daily[i], stats.Daily[j]
+		if !a.Day.Equal(b.Day) {
+			return a.Day.Before(b.Day)
+		}
+		return a.RecipeID.String() < b.RecipeID.String()
+	})
+	return stats, nil
+}
+
+// topCounts groups the recipe's views by a column expression, skipping
+// blank values
+func (r *RecipeAnalyticsRepository) topCounts(ctx context.Context, expr string, recipeID uuid.UUID, since time.Time, limit int) ([]outbound.CountStat, error) {
+	var rows []struct {
+		Label string
+		Count int
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&RecipeViewModel{}).
+		Select(expr+" AS label, COUNT(*) AS count").
+		Where("recipe_id = ? AND viewed_at >= ?", recipeID, since).
+		Where(expr + " IS NOT NULL AND " + expr + " <> ''").
+		Group(expr).
+		Order("count DESC, label").
+		Limit(limit).
+		Scan(&rows)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	stats := make([]outbound.CountStat, len(rows))
+	for i, row := range rows {
+		stats[i] = outbound.CountStat{Key: row.Label, Count: row.Count}
+	}
+
+	return stats, nil
+}
+
+// viewerKey identifies who viewed: the user when signed in, else the
+// session, else the view itself
+func viewerKey(view RecipeViewModel) string {
+	switch {
+	case view.UserID != nil:
+		return "u:" + view.UserID.String()
+	case view.SessionID != nil && *view.SessionID != "":
+		return "s:" + *view.SessionID
+	default:
+		return "v:" + view.ID.String()
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,72 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddDailyViewsAddsToEachDaysTotal(t *testing.T) {
+	db, id := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&RecipeDailyViewModel{}))
+	repo := NewRecipeAnalyticsRepository(db)
+	ctx := context.Background()
+	monday := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+
+	require.NoError(t, repo.AddDailyViews(ctx, []outbound.ViewCount{
+		{RecipeID: id, Day: monday, Views: 3},
+		{RecipeID: id, Day: monday.Add(20 * time.Hour), Views: 2},
+		{RecipeID: uuid.New(), Day: monday, Views: 7}, // deleted since it was viewed
+	}))
+	require.NoError(t, repo.AddDailyViews(ctx, []outbound.ViewCount{
+		{RecipeID: id, Day: monday, Views: 1},
+		{RecipeID: id, Day: tuesday, Views: 4},
+	}))
+
+	var rows []RecipeDailyViewModel
+	require.NoError(t, db.Order("day").Find(&rows).Error)
+	require.Len(t, rows, 2)
+	assert.Equal(t, id, rows[0].RecipeID)
+	assert.Equal(t, 6, rows[0].Views)
+	assert.Equal(t, 4, rows[1].Views)
+}
+
+func TestAuthorStatsBucketsActivityPerRecipeAndDay(t *testing.T) {
+	db, id := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&RecipeDailyViewModel{}, &RecipeDailyImpressionModel{}, &RecipeLikeModel{}, &RatingModel{}))
+	repo := NewRecipeAnalyticsRepository(db)
+	ctx := context.Background()
+	var recipe RecipeModel
+	require.NoError(t, db.First(&recipe, "id = ?", id).Error)
+	fan := UserModel{ID: uuid.New(), Email: "bo@example.com", Name: "Bo", PasswordHash: "x"}
+	require.NoError(t, db.Create(&fan).Error)
+	other := RecipeModel{ID: uuid.New(), Title: "Not Ada's", AuthorID: fan.ID, Status: "published"}
+	require.NoError(t, db.Create(&other).Error)
+
+	monday := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+	require.NoError(t, repo.AddDailyViews(ctx, []outbound.ViewCount{
+		{RecipeID: id, Day: monday.AddDate(0, 0, -1), Views: 50}, // before the window
+		{RecipeID: id, Day: monday, Views: 3},
+		{RecipeID: other.ID, Day: monday, Views: 9},
+	}))
+	require.NoError(t, repo.AddDailyImpressions(ctx, []outbound.ViewCount{{RecipeID: id, Day: tuesday, Views: 20}}))
+	require.NoError(t, db.Create(&RecipeLikeModel{RecipeID: id, UserID: fan.ID, CreatedAt: tuesday.Add(9 * time.Hour)}).Error)
+	require.NoError(t, db.Create(&RatingModel{ID: uuid.New(), RecipeID: id, UserID: fan.ID, Value: 4, CreatedAt: tuesday.Add(10 * time.Hour)}).Error)
+
+	stats, err := repo.AuthorStats(ctx, recipe.AuthorID, monday)
+	require.NoError(t, err)
+	require.Len(t, stats.Recipes, 1)
+	assert.Equal(t, "Lemon Bars", stats.Recipes[0].Title)
+	require.Len(t, stats.Daily, 2)
+	assert.True(t, stats.Daily[0].Day.Equal(monday))
+	assert.Equal(t, 3, stats.Daily[0].Views)
+	assert.True(t, stats.Daily[1].Day.Equal(tuesday))
+	assert.Equal(t, outbound.RecipeDayStat{RecipeID: id, Day: stats.Daily[1].Day, Likes: 1, Impressions: 20, Ratings: 1, RatingTotal: 4}, stats.Daily[1])
+}
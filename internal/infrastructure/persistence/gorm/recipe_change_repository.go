@@ -0,0 +1,100 @@
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecipeChangeRepository stores the recipe change log using GORM
+type RecipeChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewRecipeChangeRepository creates a new recipe change repository
+func NewRecipeChangeRepository(db *gorm.DB) outbound.RecipeChangeRepository {
+	return &RecipeChangeRepository{db: db}
+}
+
+// Latest returns nil when the recipe has no change sets yet
+func (r *RecipeChangeRepository) Latest(ctx context.Context, recipeID uuid.UUID) (*outbound.RecipeChangeSet, error) {
+	var model RecipeChangeModel
+	err := r.db.WithContext(ctx).
+		Where("recipe_id = ?", recipeID).
+		Order("revision DESC").
+		First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return modelToChangeSet(&model)
+}
+
+// Append inserts the change set unless its revision is already taken
+func (r *RecipeChangeRepository) Append(ctx context.Context, set outbound.RecipeChangeSet) (bool, error) {
+	fields, err := json.Marshal(set.Fields)
+	if err != nil {
+		return false, err
+	}
+	snapshot, err := json.Marshal(set.Snapshot)
+	if err != nil {
+		return false, err
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&RecipeChangeModel{
+		RecipeID:  set.RecipeID,
+		Revision:  set.Revision,
+		Fields:    string(fields),
+		Snapshot:  string(snapshot),
+		ChangedAt: set.ChangedAt,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindSince returns change sets after the revision, oldest first
+func (r *RecipeChangeRepository) FindSince(ctx context.Context, recipeID uuid.UUID, revision int64, limit int) ([]outbound.RecipeChangeSet, error) {
+	var models []RecipeChangeModel
+	err := r.db.WithContext(ctx).
+		Where("recipe_id = ? AND revision > ?", recipeID, revision).
+		Order("revision ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make([]outbound.RecipeChangeSet, len(models))
+	for i := range models {
+		set, err := modelToChangeSet(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = *set
+	}
+	return sets, nil
+}
+
+func modelToChangeSet(model *RecipeChangeModel) (*outbound.RecipeChangeSet, error) {
+	set := &outbound.RecipeChangeSet{
+		RecipeID:  model.RecipeID,
+		Revision:  model.Revision,
+		ChangedAt: model.ChangedAt,
+	}
+	if err := json.Unmarshal([]byte(model.Fields), &set.Fields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(model.Snapshot), &set.Snapshot); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
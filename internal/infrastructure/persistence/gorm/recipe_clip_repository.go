@@ -0,0 +1,43 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecipeClipRepository implements outbound.RecipeClipRepository using GORM
+type RecipeClipRepository struct {
+	db *gorm.DB
+}
+
+// NewRecipeClipRepository creates a new recipe clip repository
+func NewRecipeClipRepository(db *gorm.DB) outbound.RecipeClipRepository {
+	return &RecipeClipRepository{db: db}
+}
+
+// Create stores a clip
+func (r *RecipeClipRepository) Create(ctx context.Context, clip outbound.RecipeClip) error {
+	model := RecipeClipModel{
+		ID:        clip.ID,
+		UserID:    clip.UserID,
+		RecipeID:  clip.RecipeID,
+		PageURL:   clip.PageURL,
+		Method:    clip.Method,
+		CreatedAt: clip.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// CountSince counts the user's clips since a time
+func (r *RecipeClipRepository) CountSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&RecipeClipModel{}).
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
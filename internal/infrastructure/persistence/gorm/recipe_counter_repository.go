@@ -0,0 +1,101 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// incrementShardSQL adds to one shard row, creating it on first use. It
+// runs unchanged on PostgreSQL and SQLite.
+const incrementShardSQL = `
+INSERT INTO recipe_counter_shards (recipe_id, counter, shard, value) VALUES (?, ?, ?, ?)
+ON CONFLICT (recipe_id, counter, shard) DO UPDATE SET value = recipe_counter_shards.value + excluded.value`
+
+// recipeCounterColumns maps each counter to its column on recipes
+var recipeCounterColumns = map[string]string{
+	outbound.RecipeCounterLikes: "likes_count",
+	outbound.RecipeCounterViews: "views_count",
+	outbound.RecipeCounterForks: "forks_count",
+}
+
+// RecipeCounterRepository implements sharded recipe counters using GORM
+type RecipeCounterRepository struct {
+	db     *gorm.DB
+	shards int
+}
+
+// NewRecipeCounterRepository creates a counter repository spreading each
+// counter over the given number of shard rows
+func NewRecipeCounterRepository(db *gorm.DB, shards int) outbound.RecipeCounterRepository {
+	if shards < 1 {
+		shards = 1
+	}
+	return &RecipeCounterRepository{db: db, shards: shards}
+}
+
+// Increment adds delta to a random shard of the counter
+func (r *RecipeCounterRepository) Increment(ctx context.Context, recipeID uuid.UUID, counter string, delta int) error {
+	if _, ok := recipeCounterColumns[counter]; !ok {
+		return fmt.Errorf("unknown recipe counter %q", counter)
+	}
+	return r.db.WithContext(ctx).
+		Exec(incrementShardSQL, recipeID, counter, rand.Intn(r.shards), delta).Error
+}
+
+// Fold moves the shards of up to limit recipes into their recipe rows. The
+// shards are deleted and added in one transaction, so readers summing the
+// recipe row and its shards never see an increment twice or not at all.
+func (r *RecipeCounterRepository) Fold(ctx context.Context, limit int) (int, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&RecipeCounterShardModel{}).
+		Distinct("recipe_id").
+		Limit(limit).
+		Pluck("recipe_id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var shards []RecipeCounterShardModel
+		err := tx.Raw("DELETE FROM recipe_counter_shards WHERE recipe_id IN ? RETURNING recipe_id, counter, value", ids).
+			Scan(&shards).Error
+		if err != nil {
+			return err
+		}
+
+		totals := make(map[uuid.UUID]map[string]int64)
+		for _, shard := range shards {
+			column, ok := recipeCounterColumns[shard.Counter]
+			if !ok {
+				continue
+			}
+			if totals[shard.RecipeID] == nil {
+				totals[shard.RecipeID] = map[string]int64{}
+			}
+			totals[shard.RecipeID][column] += shard.Value
+		}
+
+		for id, sums := range totals {
+			columns := make(map[string]interface{}, len(sums))
+			for column, sum := range sums {
+				columns[column] = gorm.Expr(column+" + ?", sum)
+			}
+			// Unscoped so soft-deleted recipes keep their counts if restored
+			err := tx.Unscoped().Model(&RecipeModel{}).Where("id = ?", id).UpdateColumns(columns).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,143 @@
+package gorm
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newCounterFixture opens a file database, so concurrent connections share
+// it, with one recipe to count on
+func newCounterFixture(t *testing.T) (*gorm.DB, uuid.UUID) {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "counters.db") + "?_journal_mode=WAL&_busy_timeout=10000&_txlock=immediate"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&UserModel{}, &RecipeModel{}, &RecipeCounterShardModel{}))
+
+	author := UserModel{ID: uuid.New(), Email: "ada@example.com", Name: "Ada", PasswordHash: "x"}
+	require.NoError(t, db.Create(&author).Error)
+	model := RecipeModel{ID: uuid.New(), Title: "Lemon Bars", AuthorID: author.ID, Status: "published"}
+	require.NoError(t, db.Create(&model).Error)
+	return db, model.ID
+}
+
+// exactCounts reads the counters the way recipe pages do
+func exactCounts(t *testing.T, db *gorm.DB, id uuid.UUID) (likes, views int) {
+	t.Helper()
+	var model RecipeModel
+	require.NoError(t, db.Select(recipeColumns).First(&model, "id = ?", id).Error)
+	return model.Likes, model.Views
+}
+
+func TestRecipeCountersStayExactUnderConcurrentLoad(t *testing.T) {
+	db, id := newCounterFixture(t)
+	counters := NewRecipeCounterRepository(db, 8)
+	ctx := context.Background()
+
+	const writers, perWriter = 8, 50
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				assert.NoError(t, counters.Increment(ctx, id, outbound.RecipeCounterLikes, 1))
+				assert.NoError(t, counters.Increment(ctx, id, outbound.RecipeCounterViews, 2))
+			}
+		}()
+	}
+
+	// Fold and read while the writers run. A fold moves shards into the
+	// recipe row in one transaction, so the exact count may never go down.
+	stop := make(chan struct{})
+	var background sync.WaitGroup
+	background.Add(2)
+	go func() {
+		defer background.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			_, err := counters.Fold(ctx, 10)
+			assert.NoError(t, err)
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	go func() {
+		defer background.Done()
+		last := 0
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			likes, _ := exactCounts(t, db, id)
+			assert.GreaterOrEqual(t, likes, last, "a fold must not hide increments")
+			last = likes
+		}
+	}()
+
+	wg.Wait()
+	close(stop)
+	background.Wait()
+
+	likes, views := exactCounts(t, db, id)
+	assert.Equal(t, writers*perWriter, likes)
+	assert.Equal(t, 2*writers*perWriter, views)
+
+	folded, err := counters.Fold(ctx, 10)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, folded, 1)
+
+	var pending int64
+	require.NoError(t, db.Model(&RecipeCounterShardModel{}).Count(&pending).Error)
+	assert.Zero(t, pending, "folding drains the shards")
+
+	var row RecipeModel
+	require.NoError(t, db.First(&row, "id = ?", id).Error)
+	assert.Equal(t, writers*perWriter, row.Likes, "the recipe row holds the folded total")
+	assert.Equal(t, 2*writers*perWriter, row.Views)
+}
+
+func TestRecipeSaveKeepsCounters(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	recipes := NewRecipeRepository(db)
+	counters := NewRecipeCounterRepository(db, 4)
+	ctx := context.Background()
+
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	entity, err := recipe.NewRecipe("Lemon Bars", "", author.ID)
+	require.NoError(t, err)
+	require.NoError(t, recipes.Create(ctx, entity))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, counters.Increment(ctx, entity.ID(), outbound.RecipeCounterLikes, 1))
+	}
+	_, err = counters.Fold(ctx, 10)
+	require.NoError(t, err)
+
+	// entity was read before the likes and still counts none
+	require.NoError(t, entity.UpdateTitle("Lemon Squares"))
+	require.NoError(t, recipes.Update(ctx, entity))
+
+	likes, _ := exactCounts(t, db, entity.ID())
+	assert.Equal(t, 3, likes, "saving a stale recipe keeps its likes")
+
+	assert.Error(t, counters.Increment(ctx, entity.ID(), "shares", 1))
+}
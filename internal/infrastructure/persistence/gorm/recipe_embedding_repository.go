@@ -0,0 +1,182 @@
+package gorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Vector is an embedding in the pgvector text form, [1,2,3]. PostgreSQL
+// stores it in a vector column; other databases keep the text.
+type Vector []float32
+
+// Value writes the vector as text
+func (v Vector) Value() (driver.Value, error) {
+	parts := make([]string, len(v))
+	for i, x := range v {
+		parts[i] = strconv.FormatFloat(float64(x), 'g', -1, 32)
+	}
+	return "[" + strings.Join(parts, ",") + "]", nil
+}
+
+// Scan reads a vector in text form
+func (v *Vector) Scan(value interface{}) error {
+	var text string
+	switch t := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case string:
+		text = t
+	case []byte:
+		text = string(t)
+	default:
+		return fmt.Errorf("cannot scan %T into Vector", value)
+	}
+
+	text = strings.Trim(strings.TrimSpace(text), "[]")
+	if text == "" {
+		*v = Vector{}
+		return nil
+	}
+	parts := strings.Split(text, ",")
+	vector := make(Vector, len(parts))
+	for i, part := range parts {
+		x, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return fmt.Errorf("invalid vector component %q: %w", part, err)
+		}
+		vector[i] = float32(x)
+	}
+	*v = vector
+	return nil
+}
+
+// cosineSimilarity is 1 for vectors pointing the same way and 0 for
+// unrelated ones; vectors of different lengths are unrelated
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// RecipeEmbeddingRepository implements outbound.RecipeEmbeddingRepository
+// using GORM. PostgreSQL ranks with the pgvector cosine distance; other
+// databases compare the filtered recipes' vectors in Go, which is fine at
+// the size of a local catalogue.
+type RecipeEmbeddingRepository struct {
+	db      *gorm.DB
+	recipes *RecipeRepository
+}
+
+// NewRecipeEmbeddingRepository creates a new recipe embedding repository
+func NewRecipeEmbeddingRepository(db *gorm.DB) outbound.RecipeEmbeddingRepository {
+	return &RecipeEmbeddingRepository{
+		db:      db,
+		recipes: NewRecipeRepository(db).(*RecipeRepository),
+	}
+}
+
+// FindStale returns published recipes without an up to date vector from
+// model
+func (r *RecipeEmbeddingRepository) FindStale(ctx context.Context, model string, limit int) ([]*recipe.Recipe, error) {
+	current := r.db.Model(&RecipeEmbeddingModel{}).
+		Select("1").
+		Where("recipe_embeddings.recipe_id = recipes.id AND recipe_embeddings.model = ?", model).
+		Where("recipe_embeddings.recipe_updated_at >= recipes.updated_at")
+
+	var models []RecipeModel
+	err := r.db.WithContext(ctx).
+		Select(recipeColumns).
+		Where("recipes.status = ?", string(recipe.RecipeStatusPublished)).
+		Where("NOT EXISTS (?)", current).
+		Order("recipes.updated_at").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make([]*recipe.Recipe, 0, len(models))
+	for i := range models {
+		rec, err := ModelToRecipe(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, rec)
+	}
+	return recipes, nil
+}
+
+// Save inserts or replaces a recipe's vector
+func (r *RecipeEmbeddingRepository) Save(ctx context.Context, embedding outbound.RecipeEmbedding) error {
+	model := RecipeEmbeddingModel{
+		RecipeID:        embedding.RecipeID,
+		Model:           embedding.Model,
+		Dimensions:      len(embedding.Vector),
+		Embedding:       Vector(embedding.Vector),
+		RecipeUpdatedAt: embedding.RecipeUpdatedAt,
+		EmbeddedAt:      time.Now().UTC(),
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "recipe_id"}, {Name: "model"}},
+		DoUpdates: clause.AssignmentColumns([]string{"dimensions", "embedding", "recipe_updated_at", "embedded_at"}),
+	}).Create(&model).Error
+}
+
+// FindNearest ranks the filtered recipes by the similarity of their
+// vectors to vector
+func (r *RecipeEmbeddingRepository) FindNearest(ctx context.Context, criteria outbound.SearchCriteria, model string, vector []float32, limit int) ([]outbound.EmbeddingMatch, error) {
+	criteria.Query = ""
+	query := r.recipes.searchFilter(ctx, criteria).
+		Joins("JOIN recipe_embeddings ON recipe_embeddings.recipe_id = recipes.id AND recipe_embeddings.model = ?", model)
+
+	if r.db.Dialector.Name() == "postgres" {
+		target := Vector(vector)
+		var rows []outbound.EmbeddingMatch
+		err := query.
+			Select("recipes.id AS recipe_id, 1 - (recipe_embeddings.embedding <=> CAST(? AS vector)) AS similarity", target).
+			Order(clause.OrderBy{Expression: clause.Expr{SQL: "recipe_embeddings.embedding <=> CAST(? AS vector)", Vars: []interface{}{target}}}).
+			Limit(limit).
+			Scan(&rows).Error
+		return rows, err
+	}
+
+	var rows []struct {
+		RecipeID  uuid.UUID
+		Embedding Vector
+	}
+	if err := query.Select("recipes.id AS recipe_id, recipe_embeddings.embedding").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	matches := make([]outbound.EmbeddingMatch, len(rows))
+	for i, row := range rows {
+		matches[i] = outbound.EmbeddingMatch{RecipeID: row.RecipeID, Similarity: cosineSimilarity(vector, row.Embedding)}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Similarity > matches[j].Similarity })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
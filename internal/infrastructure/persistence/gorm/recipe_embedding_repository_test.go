@@ -0,0 +1,96 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeEmbeddingsGoStaleAndRankBySimilarity(t *testing.T) {
+	db, lemonBars := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&RecipeEmbeddingModel{}))
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	repo := NewRecipeEmbeddingRepository(db)
+	ctx := context.Background()
+
+	ids := map[string]uuid.UUID{"Lemon Bars": lemonBars}
+	for _, r := range []struct {
+		title, cuisine, status string
+	}{
+		{"Beef Stew", "french", "published"},
+		{"Lentil Soup", "indian", "published"},
+		{"Secret Chili", "mexican", "draft"},
+	} {
+		ids[r.title] = uuid.New()
+		require.NoError(t, db.Create(&RecipeModel{ID: ids[r.title], Title: r.title, Cuisine: r.cuisine, AuthorID: author.ID, Status: r.status}).Error)
+	}
+	vectors := map[string][]float32{
+		"Beef Stew":   {0.9, 0.1, 0},
+		"Lentil Soup": {0.7, 0.3, 0},
+		"Lemon Bars":  {0, 0.2, 0.9},
+	}
+
+	stale, err := repo.FindStale(ctx, "test-model", 10)
+	require.NoError(t, err)
+	require.Len(t, stale, 3, "drafts are not embedded")
+	for _, r := range stale {
+		require.NoError(t, repo.Save(ctx, outbound.RecipeEmbedding{
+			RecipeID:        r.ID(),
+			Model:           "test-model",
+			Vector:          vectors[r.Title()],
+			RecipeUpdatedAt: r.UpdatedAt(),
+		}))
+	}
+	stale, err = repo.FindStale(ctx, "test-model", 10)
+	require.NoError(t, err)
+	assert.Empty(t, stale)
+	stale, err = repo.FindStale(ctx, "other-model", 10)
+	require.NoError(t, err)
+	assert.Len(t, stale, 3, "vectors are kept per model")
+
+	require.NoError(t, db.Model(&RecipeModel{}).Where("id = ?", ids["Lentil Soup"]).Update("title", "Red Lentil Soup").Error)
+	stale, err = repo.FindStale(ctx, "test-model", 10)
+	require.NoError(t, err)
+	require.Len(t, stale, 1)
+	assert.Equal(t, "Red Lentil Soup", stale[0].Title())
+	require.NoError(t, repo.Save(ctx, outbound.RecipeEmbedding{
+		RecipeID: ids["Lentil Soup"], Model: "test-model", Vector: []float32{0.6, 0.4, 0}, RecipeUpdatedAt: stale[0].UpdatedAt(),
+	}))
+
+	winter := []float32{1, 0, 0}
+	nearest, err := repo.FindNearest(ctx, outbound.SearchCriteria{Query: "ignored"}, "test-model", winter, 10)
+	require.NoError(t, err)
+	require.Len(t, nearest, 3)
+	assert.Equal(t, ids["Beef Stew"], nearest[0].RecipeID)
+	assert.Equal(t, ids["Lentil Soup"], nearest[1].RecipeID)
+	assert.Equal(t, ids["Lemon Bars"], nearest[2].RecipeID)
+	assert.InDelta(t, 0.994, nearest[0].Similarity, 0.001)
+
+	nearest, err = repo.FindNearest(ctx, outbound.SearchCriteria{Cuisines: []recipe.CuisineType{"french"}}, "test-model", winter, 1)
+	require.NoError(t, err)
+	require.Len(t, nearest, 1)
+	assert.Equal(t, ids["Beef Stew"], nearest[0].RecipeID)
+
+	nearest, err = repo.FindNearest(ctx, outbound.SearchCriteria{}, "other-model", winter, 10)
+	require.NoError(t, err)
+	assert.Empty(t, nearest)
+}
+
+func TestVectorRoundTripsThroughText(t *testing.T) {
+	value, err := Vector{0.25, -1, 3e-5}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "[0.25,-1,3e-05]", value)
+
+	var v Vector
+	require.NoError(t, v.Scan([]byte("[0.25, -1, 3e-05]")))
+	assert.Equal(t, Vector{0.25, -1, 3e-5}, v)
+	assert.Error(t, v.Scan("[1,x]"))
+	assert.InDelta(t, 0, cosineSimilarity([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	assert.InDelta(t, 0, cosineSimilarity([]float32{1, 0}, []float32{1, 0, 0}), 1e-9)
+}
@@ -0,0 +1,79 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"gorm.io/gorm"
+)
+
+// recipeExportColumns are the columns of RecipeModel an export reads
+const recipeExportColumns = `id, title, author_id, status, language, cuisine, category, difficulty,
+	prep_time_minutes, cook_time_minutes, servings, calories,
+	likes_count, views_count, average_rating, ai_generated,
+	created_at, updated_at, published_at, ingredients`
+
+// RecipeExportRepository implements outbound.RecipeExportRepository using
+// GORM
+type RecipeExportRepository struct {
+	db *gorm.DB
+}
+
+// NewRecipeExportRepository creates a new recipe export repository
+func NewRecipeExportRepository(db *gorm.DB) outbound.RecipeExportRepository {
+	return &RecipeExportRepository{db: db}
+}
+
+// StreamRecipes scans one row at a time off the cursor, oldest first
+func (r *RecipeExportRepository) StreamRecipes(ctx context.Context, filter outbound.RecipeExportFilter, fn func(*outbound.RecipeExportRow) error) error {
+	db := r.db.WithContext(ctx)
+	query := db.Model(&RecipeModel{}).Select(recipeExportColumns).Order("created_at, id")
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.UpdatedSince != nil {
+		query = query.Where("updated_at >= ?", *filter.UpdatedSince)
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model RecipeModel
+		if err := db.ScanRows(rows, &model); err != nil {
+			return err
+		}
+		if err := fn(toRecipeExportRow(&model)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func toRecipeExportRow(model *RecipeModel) *outbound.RecipeExportRow {
+	return &outbound.RecipeExportRow{
+		ID:              model.ID,
+		Title:           model.Title,
+		AuthorID:        model.AuthorID,
+		Status:          model.Status,
+		Language:        model.Language,
+		Cuisine:         model.Cuisine,
+		Category:        model.Category,
+		Difficulty:      model.Difficulty,
+		PrepTimeMinutes: model.PrepTimeMinutes,
+		CookTimeMinutes: model.CookTimeMinutes,
+		Servings:        model.Servings,
+		Calories:        model.Calories,
+		Likes:           model.Likes,
+		Views:           model.Views,
+		AverageRating:   model.AverageRating,
+		AIGenerated:     model.AIGenerated,
+		CreatedAt:       model.CreatedAt,
+		UpdatedAt:       model.UpdatedAt,
+		PublishedAt:     model.PublishedAt,
+		Ingredients:     ingredientLines(model.Ingredients),
+	}
+}
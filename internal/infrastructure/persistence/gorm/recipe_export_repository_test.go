@@ -0,0 +1,56 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeExportStreamsRowsOldestFirst(t *testing.T) {
+	db, lemonBars := newCounterFixture(t)
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	require.NoError(t, db.Model(&RecipeModel{}).Where("id = ?", lemonBars).Updates(map[string]interface{}{
+		"created_at": time.Now().Add(-time.Hour), "likes_count": 7, "views_count": 90,
+		"ingredients": JSONField{"data": []interface{}{
+			map[string]interface{}{"name": "butter", "optional": false, "notes": ""},
+			map[string]interface{}{"name": "pecans", "optional": true, "notes": "toasted"},
+		}},
+	}).Error)
+	draft := RecipeModel{ID: uuid.New(), Title: "Draft Soup", AuthorID: author.ID, Status: "draft"}
+	require.NoError(t, db.Create(&draft).Error)
+
+	repo := NewRecipeExportRepository(db)
+	ctx := context.Background()
+
+	var rows []outbound.RecipeExportRow
+	collect := func(row *outbound.RecipeExportRow) error {
+		rows = append(rows, *row)
+		return nil
+	}
+	require.NoError(t, repo.StreamRecipes(ctx, outbound.RecipeExportFilter{}, collect))
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Lemon Bars", rows[0].Title)
+	assert.Equal(t, author.ID, rows[0].AuthorID)
+	assert.Equal(t, 7, rows[0].Likes)
+	assert.Equal(t, 90, rows[0].Views)
+	assert.Equal(t, []outbound.IngredientLine{
+		{Name: "butter"},
+		{Name: "pecans", Optional: true, Notes: "toasted"},
+	}, rows[0].Ingredients)
+	assert.Equal(t, "Draft Soup", rows[1].Title)
+
+	rows = nil
+	require.NoError(t, repo.StreamRecipes(ctx, outbound.RecipeExportFilter{Status: "draft"}, collect))
+	require.Len(t, rows, 1)
+
+	stop := errors.New("client went away")
+	err := repo.StreamRecipes(ctx, outbound.RecipeExportFilter{}, func(*outbound.RecipeExportRow) error { return stop })
+	assert.ErrorIs(t, err, stop)
+}
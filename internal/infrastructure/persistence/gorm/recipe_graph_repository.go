@@ -0,0 +1,278 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// publishedRecipe limits a graph traversal joined to recipes to the ones
+// still published
+const publishedRecipe = "recipes.status = 'published' AND recipes.deleted_at IS NULL"
+
+// RecipeGraphRepository implements the recipe knowledge graph using GORM.
+// Like the browse summaries it is a pair of plain tables rebuilt in one
+// transaction, so it works the same on PostgreSQL and SQLite.
+type RecipeGraphRepository struct {
+	db *gorm.DB
+}
+
+// NewRecipeGraphRepository creates a new recipe graph repository
+func NewRecipeGraphRepository(db *gorm.DB) outbound.RecipeGraphRepository {
+	return &RecipeGraphRepository{db: db}
+}
+
+// EachPublished streams the ingredients, instructions and cuisine of the
+// published recipes
+func (r *RecipeGraphRepository) EachPublished(ctx context.Context, fn func(outbound.GraphSource) error) error {
+	db := r.db.WithContext(ctx)
+	rows, err := db.Model(&RecipeModel{}).
+		Select("id, ingredients, instructions, cuisine").
+		Where("status = ?", "published").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipe RecipeModel
+		if err := db.ScanRows(rows, &recipe); err != nil {
+			return err
+		}
+		err := fn(outbound.GraphSource{
+			RecipeID:     recipe.ID,
+			Ingredients:  jsonListField(recipe.Ingredients, "name", "data", "ingredients"),
+			Instructions: jsonListField(recipe.Instructions, "description", "data", "instructions"),
+			Cuisine:      recipe.Cuisine,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// jsonListField reads field from each object of the first list found
+// under keys. Recipes written by the app keep their lists under "data";
+// seeded ones under the list's own name.
+func jsonListField(value JSONField, field string, keys ...string) []string {
+	for _, key := range keys {
+		list, ok := value[key].([]interface{})
+		if !ok {
+			continue
+		}
+		values := make([]string, 0, len(list))
+		for _, item := range list {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if s, ok := object[field].(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// Replace swaps the graph for nodes and edges
+func (r *RecipeGraphRepository) Replace(ctx context.Context, nodes []outbound.GraphNode, edges []outbound.GraphEdge) error {
+	now := time.Now().UTC()
+	nodeModels := make([]RecipeGraphNodeModel, len(nodes))
+	for i, node := range nodes {
+		nodeModels[i] = RecipeGraphNodeModel{
+			NodeKind:    node.Kind,
+			NodeKey:     node.Key,
+			Label:       node.Label,
+			RecipeCount: node.Recipes,
+			Weight:      node.Weight,
+			RefreshedAt: now,
+		}
+	}
+	edgeModels := make([]RecipeGraphEdgeModel, len(edges))
+	for i, edge := range edges {
+		edgeModels[i] = RecipeGraphEdgeModel{RecipeID: edge.RecipeID, NodeKind: edge.Kind, NodeKey: edge.Key}
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		all := tx.Session(&gorm.Session{AllowGlobalUpdate: true})
+		if err := all.Delete(&RecipeGraphEdgeModel{}).Error; err != nil {
+			return err
+		}
+		if err := all.Delete(&RecipeGraphNodeModel{}).Error; err != nil {
+			return err
+		}
+		if len(nodeModels) > 0 {
+			if err := tx.CreateInBatches(nodeModels, inBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(edgeModels) > 0 {
+			if err := tx.CreateInBatches(edgeModels, inBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// NodesOf returns the nodes a published recipe links to
+func (r *RecipeGraphRepository) NodesOf(ctx context.Context, recipeID uuid.UUID) ([]outbound.GraphNode, error) {
+	var models []RecipeGraphNodeModel
+	result := r.db.WithContext(ctx).
+		Table("recipe_graph_edges AS edges").
+		Select("nodes.*").
+		Joins("JOIN recipe_graph_nodes AS nodes ON nodes.node_kind = edges.node_kind AND nodes.node_key = edges.node_key").
+		Joins("JOIN recipes ON recipes.id = edges.recipe_id").
+		Where("edges.recipe_id = ? AND "+publishedRecipe, recipeID).
+		Order("nodes.node_kind, nodes.recipe_count DESC, nodes.label").
+		Scan(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return graphNodes(models), nil
+}
+
+// Node returns one node, or nil if no recipe links to it
+func (r *RecipeGraphRepository) Node(ctx context.Context, kind, key string) (*outbound.GraphNode, error) {
+	var models []RecipeGraphNodeModel
+	result := r.db.WithContext(ctx).
+		Where("node_kind = ? AND node_key = ?", kind, key).
+		Limit(1).
+		Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+	return &graphNodes(models)[0], nil
+}
+
+// graphRecipeRow is a recipe card read alongside a traversal
+type graphRecipeRow struct {
+	RecipeID      uuid.UUID
+	Title         string
+	AverageRating float64
+	LikesCount    int
+	Score         float64
+}
+
+// RecipesFor returns the published recipes linked to a node, most liked
+// first
+func (r *RecipeGraphRepository) RecipesFor(ctx context.Context, kind, key string, limit int) ([]outbound.GraphRecipe, error) {
+	var rows []graphRecipeRow
+	result := r.db.WithContext(ctx).
+		Table("recipe_graph_edges AS edges").
+		Select("recipes.id AS recipe_id, recipes.title, recipes.average_rating, "+exactCounter("likes_count", outbound.RecipeCounterLikes)).
+		Joins("JOIN recipes ON recipes.id = edges.recipe_id").
+		Where("edges.node_kind = ? AND edges.node_key = ? AND "+publishedRecipe, kind, key).
+		Order("likes_count DESC, recipes.average_rating DESC, recipes.id").
+		Limit(limit).
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return graphRecipes(rows), nil
+}
+
+// Related walks from a recipe to its nodes and back out to the other
+// recipes linked to them, scoring each by the weight of the nodes shared
+func (r *RecipeGraphRepository) Related(ctx context.Context, recipeID uuid.UUID, limit int) ([]outbound.GraphRecipe, error) {
+	db := r.db.WithContext(ctx)
+
+	var rows []graphRecipeRow
+	result := db.Table("recipe_graph_edges AS source").
+		Select("recipes.id AS recipe_id, recipes.title, recipes.average_rating, "+
+			exactCounter("likes_count", outbound.RecipeCounterLikes)+", SUM(nodes.weight) AS score").
+		Joins("JOIN recipe_graph_edges AS target ON target.node_kind = source.node_kind AND target.node_key = source.node_key AND target.recipe_id <> source.recipe_id").
+		Joins("JOIN recipe_graph_nodes AS nodes ON nodes.node_kind = source.node_kind AND nodes.node_key = source.node_key").
+		Joins("JOIN recipes ON recipes.id = target.recipe_id").
+		Where("source.recipe_id = ? AND "+publishedRecipe, recipeID).
+		Group("recipes.id, recipes.title, recipes.average_rating, recipes.likes_count").
+		Order("score DESC, likes_count DESC, recipes.id").
+		Limit(limit).
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	recipes := graphRecipes(rows)
+	if len(recipes) == 0 {
+		return recipes, nil
+	}
+
+	ids := make([]uuid.UUID, len(recipes))
+	byID := make(map[uuid.UUID]*outbound.GraphRecipe, len(recipes))
+	for i := range recipes {
+		ids[i] = recipes[i].RecipeID
+		byID[recipes[i].RecipeID] = &recipes[i]
+	}
+
+	var shared []struct {
+		RecipeID uuid.UUID
+		RecipeGraphNodeModel
+	}
+	result = db.Table("recipe_graph_edges AS target").
+		Select("target.recipe_id, nodes.*").
+		Joins("JOIN recipe_graph_edges AS source ON source.node_kind = target.node_kind AND source.node_key = target.node_key").
+		Joins("JOIN recipe_graph_nodes AS nodes ON nodes.node_kind = target.node_kind AND nodes.node_key = target.node_key").
+		Where("source.recipe_id = ? AND target.recipe_id IN ?", recipeID, ids).
+		Order("nodes.weight DESC, nodes.label").
+		Scan(&shared)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, row := range shared {
+		if recipe := byID[row.RecipeID]; recipe != nil {
+			recipe.Shared = append(recipe.Shared, graphNodes([]RecipeGraphNodeModel{row.RecipeGraphNodeModel})...)
+		}
+	}
+	return recipes, nil
+}
+
+// RefreshedAt returns when the graph was last rebuilt; an empty rebuild
+// leaves no rows and reads as never rebuilt
+func (r *RecipeGraphRepository) RefreshedAt(ctx context.Context) (*time.Time, error) {
+	var models []RecipeGraphNodeModel
+	result := r.db.WithContext(ctx).Limit(1).Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+	return &models[0].RefreshedAt, nil
+}
+
+func graphNodes(models []RecipeGraphNodeModel) []outbound.GraphNode {
+	nodes := make([]outbound.GraphNode, len(models))
+	for i, model := range models {
+		nodes[i] = outbound.GraphNode{
+			Kind:    model.NodeKind,
+			Key:     model.NodeKey,
+			Label:   model.Label,
+			Recipes: model.RecipeCount,
+			Weight:  model.Weight,
+		}
+	}
+	return nodes
+}
+
+func graphRecipes(rows []graphRecipeRow) []outbound.GraphRecipe {
+	recipes := make([]outbound.GraphRecipe, len(rows))
+	for i, row := range rows {
+		recipes[i] = outbound.GraphRecipe{
+			RecipeID:      row.RecipeID,
+			Title:         row.Title,
+			AverageRating: row.AverageRating,
+			Likes:         row.LikesCount,
+			Score:         row.Score,
+		}
+	}
+	return recipes
+}
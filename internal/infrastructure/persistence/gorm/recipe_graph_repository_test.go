@@ -0,0 +1,81 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeGraphRelatedScoresSharedNodes(t *testing.T) {
+	db, lemonBars := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&RecipeGraphNodeModel{}, &RecipeGraphEdgeModel{}))
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	require.NoError(t, db.Model(&RecipeModel{}).Where("id = ?", lemonBars).Updates(map[string]interface{}{
+		"ingredients": JSONField{"data": []interface{}{map[string]interface{}{"name": "Lemons"}}},
+		"cuisine":     "american",
+	}).Error)
+
+	lemonCake := RecipeModel{ID: uuid.New(), Title: "Lemon Cake", AuthorID: author.ID, Status: "published", Likes: 4}
+	curd := RecipeModel{ID: uuid.New(), Title: "Lemon Curd", AuthorID: author.ID, Status: "published", Likes: 9}
+	draft := RecipeModel{ID: uuid.New(), Title: "Lemon Draft", AuthorID: author.ID, Status: "draft"}
+	for _, m := range []*RecipeModel{&lemonCake, &curd, &draft} {
+		require.NoError(t, db.Create(m).Error)
+	}
+
+	repo := NewRecipeGraphRepository(db)
+	ctx := context.Background()
+
+	var sources []outbound.GraphSource
+	require.NoError(t, repo.EachPublished(ctx, func(s outbound.GraphSource) error {
+		sources = append(sources, s)
+		return nil
+	}))
+	require.Len(t, sources, 3)
+	for _, s := range sources {
+		if s.RecipeID == lemonBars {
+			assert.Equal(t, []string{"Lemons"}, s.Ingredients)
+			assert.Equal(t, "american", s.Cuisine)
+		}
+	}
+
+	lemon := outbound.GraphNode{Kind: "ingredient", Key: "lemon", Label: "Lemons", Recipes: 4, Weight: 1}
+	bake := outbound.GraphNode{Kind: "technique", Key: "bake", Label: "Baking", Recipes: 2, Weight: 3}
+	edges := []outbound.GraphEdge{
+		{RecipeID: lemonBars, Kind: "ingredient", Key: "lemon"},
+		{RecipeID: lemonBars, Kind: "technique", Key: "bake"},
+		{RecipeID: lemonCake.ID, Kind: "ingredient", Key: "lemon"},
+		{RecipeID: lemonCake.ID, Kind: "technique", Key: "bake"},
+		{RecipeID: curd.ID, Kind: "ingredient", Key: "lemon"},
+		{RecipeID: draft.ID, Kind: "ingredient", Key: "lemon"},
+	}
+	require.NoError(t, repo.Replace(ctx, []outbound.GraphNode{lemon, bake}, edges))
+
+	related, err := repo.Related(ctx, lemonBars, 10)
+	require.NoError(t, err)
+	require.Len(t, related, 2)
+	assert.Equal(t, "Lemon Cake", related[0].Title)
+	assert.Equal(t, 4.0, related[0].Score)
+	assert.Equal(t, []outbound.GraphNode{bake, lemon}, related[0].Shared)
+	assert.Equal(t, "Lemon Curd", related[1].Title)
+	assert.Equal(t, 9, related[1].Likes)
+	assert.Equal(t, []outbound.GraphNode{lemon}, related[1].Shared)
+
+	recipes, err := repo.RecipesFor(ctx, "ingredient", "lemon", 2)
+	require.NoError(t, err)
+	require.Len(t, recipes, 2)
+	assert.Equal(t, "Lemon Curd", recipes[0].Title)
+	assert.Equal(t, "Lemon Cake", recipes[1].Title)
+
+	nodes, err := repo.NodesOf(ctx, draft.ID)
+	require.NoError(t, err)
+	assert.Empty(t, nodes)
+
+	refreshed, err := repo.RefreshedAt(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, refreshed)
+}
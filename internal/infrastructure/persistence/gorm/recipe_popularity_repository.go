@@ -0,0 +1,221 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecipePopularityRepository implements the decayed popularity scores and
+// the hidden gem rotation using GORM
+type RecipePopularityRepository struct {
+	db *gorm.DB
+}
+
+// NewRecipePopularityRepository creates a new recipe popularity repository
+func NewRecipePopularityRepository(db *gorm.DB) outbound.RecipePopularityRepository {
+	return &RecipePopularityRepository{db: db}
+}
+
+// Activity counts the views and likes of published recipes in [since, until)
+func (r *RecipePopularityRepository) Activity(ctx context.Context, since, until time.Time) ([]outbound.PopularityActivity, error) {
+	db := r.db.WithContext(ctx)
+	published := db.Model(&RecipeModel{}).Select("id").Where("status = ?", "published")
+
+	var views []struct {
+		RecipeID uuid.UUID
+		Count    int
+	}
+	result := db.Model(&RecipeViewModel{}).
+		Select("recipe_id, COUNT(*) AS count").
+		Where("viewed_at >= ? AND viewed_at < ? AND recipe_id IN (?)", since, until, published).
+		Group("recipe_id").
+		Scan(&views)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var likes []struct {
+		RecipeID uuid.UUID
+		Count    int
+	}
+	result = db.Model(&RecipeLikeModel{}).
+		Select("recipe_id, COUNT(*) AS count").
+		Where("created_at >= ? AND created_at < ? AND recipe_id IN (?)", since, until, published).
+		Group("recipe_id").
+		Scan(&likes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	index := make(map[uuid.UUID]int, len(views)+len(likes))
+	activity := make([]outbound.PopularityActivity, 0, len(views)+len(likes))
+	for _, row := range views {
+		index[row.RecipeID] = len(activity)
+		activity = append(activity, outbound.PopularityActivity{RecipeID: row.RecipeID, Views: row.Count})
+	}
+	for _, row := range likes {
+		if i, ok := index[row.RecipeID]; ok {
+			activity[i].Likes = row.Count
+			continue
+		}
+		activity = append(activity, outbound.PopularityActivity{RecipeID: row.RecipeID, Likes: row.Count})
+	}
+	return activity, nil
+}
+
+// Decay ages every score, adds the new activity and prunes scores below
+// floor along with those of recipes no longer published
+func (r *RecipePopularityRepository) Decay(ctx context.Context, factor float64, activity []outbound.PopularityActivity, floor float64, at time.Time) (int, error) {
+	var kept int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		all := tx.Session(&gorm.Session{AllowGlobalUpdate: true})
+		err := all.Model(&RecipePopularityModel{}).Updates(map[string]interface{}{
+			"score":        gorm.Expr("score * ?", factor),
+			"refreshed_at": at,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if len(activity) > 0 {
+			models := make([]RecipePopularityModel, len(activity))
+			for i, a := range activity {
+				models[i] = RecipePopularityModel{RecipeID: a.RecipeID, Score: a.Score, RefreshedAt: at}
+			}
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "recipe_id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"score":        gorm.Expr("recipe_popularity.score + excluded.score"),
+					"refreshed_at": at,
+				}),
+			}).CreateInBatches(models, inBatchSize).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		published := tx.Model(&RecipeModel{}).Select("id").Where("status = ?", "published")
+		err = tx.Where("score < ? OR recipe_id NOT IN (?)", floor, published).
+			Delete(&RecipePopularityModel{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&RecipePopularityModel{}).Count(&kept).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(kept), nil
+}
+
+// RefreshedAt returns when the scores were last decayed. Every row is
+// stamped on each refresh, so any one stands for all of them.
+func (r *RecipePopularityRepository) RefreshedAt(ctx context.Context) (*time.Time, error) {
+	var models []RecipePopularityModel
+	result := r.db.WithContext(ctx).Limit(1).Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+	return &models[0].RefreshedAt, nil
+}
+
+// hiddenGemColumns are read for every hidden gem card
+const hiddenGemColumns = "recipes.id AS recipe_id, recipes.title, recipes.cuisine, recipes.average_rating, recipes.views_count AS views"
+
+// RotateGems features the next recipes matching criteria. Recipes never
+// featured come first, then those featured longest ago, so every eligible
+// recipe gets its turn before any repeats.
+func (r *RecipePopularityRepository) RotateGems(ctx context.Context, criteria outbound.GemCriteria, count int, at time.Time) ([]outbound.HiddenGem, error) {
+	var gems []outbound.HiddenGem
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&RecipeModel{}).
+			Select(hiddenGemColumns).
+			Joins("LEFT JOIN hidden_gems ON hidden_gems.recipe_id = recipes.id").
+			Where("recipes.status = ? AND recipes.average_rating >= ? AND recipes.views_count <= ? AND recipes.published_at < ?",
+				"published", criteria.MinRating, criteria.MaxViews, criteria.PublishedBefore).
+			Order("hidden_gems.featured_at IS NOT NULL, hidden_gems.featured_at, recipes.average_rating DESC, recipes.id").
+			Limit(count).
+			Scan(&gems)
+		if result.Error != nil || len(gems) == 0 {
+			return result.Error
+		}
+
+		models := make([]HiddenGemModel, len(gems))
+		for i := range gems {
+			gems[i].FeaturedAt = at
+			models[i] = HiddenGemModel{RecipeID: gems[i].RecipeID, FeaturedAt: at}
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "recipe_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"featured_at"}),
+		}).Create(&models).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return gems, nil
+}
+
+// HiddenGems returns the latest rotation, leaving out recipes unpublished
+// since
+func (r *RecipePopularityRepository) HiddenGems(ctx context.Context, limit int) ([]outbound.HiddenGem, error) {
+	var gems []outbound.HiddenGem
+	result := r.db.WithContext(ctx).Model(&RecipeModel{}).
+		Select(hiddenGemColumns+", hidden_gems.featured_at").
+		Joins("JOIN hidden_gems ON hidden_gems.recipe_id = recipes.id").
+		Where("recipes.status = ? AND hidden_gems.featured_at = (SELECT MAX(featured_at) FROM hidden_gems)", "published").
+		Order("recipes.average_rating DESC, recipes.id").
+		Limit(limit).
+		Scan(&gems)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return gems, nil
+}
+
+// RankingCandidates lists the published recipes with their lifetime likes
+// and views and decayed score
+func (r *RecipePopularityRepository) RankingCandidates(ctx context.Context) ([]outbound.RankingCandidate, error) {
+	var candidates []outbound.RankingCandidate
+	result := r.db.WithContext(ctx).Model(&RecipeModel{}).
+		Select("recipes.id AS recipe_id, recipes.published_at, recipes.likes_count AS likes, recipes.views_count AS views, "+
+			"COALESCE(recipe_popularity.score, 0) AS popularity").
+		Joins("LEFT JOIN recipe_popularity ON recipe_popularity.recipe_id = recipes.id").
+		Where("recipes.status = ?", "published").
+		Scan(&candidates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return candidates, nil
+}
+
+// ReplaceRankings deletes the previous rankings and writes the new ones, so
+// readers see one set or the other
+func (r *RecipePopularityRepository) ReplaceRankings(ctx context.Context, rankings []outbound.RecipeRanking, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&RecipeRankingModel{}).Error
+		if err != nil || len(rankings) == 0 {
+			return err
+		}
+		models := make([]RecipeRankingModel, len(rankings))
+		for i, ranking := range rankings {
+			models[i] = RecipeRankingModel{
+				RecipeID:      ranking.RecipeID,
+				TrendingScore: ranking.TrendingScore,
+				TrendingRank:  ranking.TrendingRank,
+				TopScore:      ranking.TopScore,
+				TopRank:       ranking.TopRank,
+				RankedAt:      at,
+			}
+		}
+		return tx.CreateInBatches(models, inBatchSize).Error
+	})
+}
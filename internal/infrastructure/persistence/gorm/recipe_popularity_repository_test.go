@@ -0,0 +1,139 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipePopularityDecaysAndRotatesGems(t *testing.T) {
+	db, lemonBars := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&RecipeViewModel{}, &RecipeLikeModel{}, &RecipePopularityModel{}, &HiddenGemModel{}))
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	monthAgo := now.AddDate(0, -1, 0)
+	require.NoError(t, db.Model(&RecipeModel{}).Where("id = ?", lemonBars).Updates(map[string]interface{}{
+		"published_at": monthAgo, "average_rating": 3.9, "views_count": 5000,
+	}).Error)
+
+	soup := RecipeModel{ID: uuid.New(), Title: "Sorrel Soup", AuthorID: author.ID, Status: "published", AverageRating: 4.8, Views: 40, PublishedAt: &monthAgo}
+	stew := RecipeModel{ID: uuid.New(), Title: "Nettle Stew", AuthorID: author.ID, Status: "published", AverageRating: 4.5, Views: 90, PublishedAt: &monthAgo}
+	fresh := RecipeModel{ID: uuid.New(), Title: "Brand New Pie", AuthorID: author.ID, Status: "published", AverageRating: 5, PublishedAt: &now}
+	for _, m := range []*RecipeModel{&soup, &stew, &fresh} {
+		require.NoError(t, db.Create(m).Error)
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, db.Create(&RecipeViewModel{ID: uuid.New(), RecipeID: lemonBars, ViewedAt: now.Add(-time.Hour)}).Error)
+	}
+	require.NoError(t, db.Create(&RecipeViewModel{ID: uuid.New(), RecipeID: soup.ID, ViewedAt: now.AddDate(0, 0, -10)}).Error)
+	require.NoError(t, db.Create(&RecipeLikeModel{RecipeID: soup.ID, UserID: author.ID, CreatedAt: now.Add(-time.Hour)}).Error)
+
+	repo := NewRecipePopularityRepository(db)
+	ctx := context.Background()
+
+	activity, err := repo.Activity(ctx, now.AddDate(0, 0, -1), now)
+	require.NoError(t, err)
+	counts := map[uuid.UUID]outbound.PopularityActivity{}
+	for _, a := range activity {
+		counts[a.RecipeID] = a
+	}
+	assert.Equal(t, 3, counts[lemonBars].Views)
+	assert.Equal(t, 0, counts[soup.ID].Views, "views before the window are already in the score")
+	assert.Equal(t, 1, counts[soup.ID].Likes)
+
+	kept, err := repo.Decay(ctx, 1, []outbound.PopularityActivity{{RecipeID: lemonBars, Score: 3}, {RecipeID: soup.ID, Score: 5}}, 0.01, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, kept)
+	kept, err = repo.Decay(ctx, 0.5, []outbound.PopularityActivity{{RecipeID: soup.ID, Score: 1}}, 2, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, kept, "lemon bars decayed below the floor")
+	var score RecipePopularityModel
+	require.NoError(t, db.First(&score, "recipe_id = ?", soup.ID).Error)
+	assert.Equal(t, 3.5, score.Score)
+
+	criteria := outbound.GemCriteria{MinRating: 4.2, MaxViews: 200, PublishedBefore: now.AddDate(0, 0, -7)}
+	first, err := repo.RotateGems(ctx, criteria, 1, now)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	assert.Equal(t, soup.ID, first[0].RecipeID, "best rated eligible recipe first")
+	second, err := repo.RotateGems(ctx, criteria, 1, now.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.Equal(t, stew.ID, second[0].RecipeID, "recipes never featured go before repeats")
+
+	gems, err := repo.HiddenGems(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, gems, 1, "only the latest rotation is current")
+	assert.Equal(t, "Nettle Stew", gems[0].Title)
+
+	require.NoError(t, db.Model(&RecipeModel{}).Where("id = ?", stew.ID).Update("status", "draft").Error)
+	gems, err = repo.HiddenGems(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, gems)
+}
+
+func TestRecipeRankingsOrderTheTrendingAndTopListings(t *testing.T) {
+	db, lemonBars := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&RecipePopularityModel{}, &RecipeRankingModel{}))
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	yearAgo, weekAgo := now.AddDate(-1, 0, 0), now.AddDate(0, 0, -7)
+	require.NoError(t, db.Model(&RecipeModel{}).Where("id = ?", lemonBars).Updates(map[string]interface{}{
+		"published_at": yearAgo, "likes_count": 900, "views_count": 20000,
+	}).Error)
+	soup := RecipeModel{ID: uuid.New(), Title: "Sorrel Soup", AuthorID: author.ID, Status: "published", Likes: 40, Views: 300, PublishedAt: &weekAgo}
+	fresh := RecipeModel{ID: uuid.New(), Title: "Brand New Pie", AuthorID: author.ID, Status: "published", PublishedAt: &now}
+	draft := RecipeModel{ID: uuid.New(), Title: "Unfinished", AuthorID: author.ID, Status: "draft"}
+	for _, m := range []*RecipeModel{&soup, &fresh, &draft} {
+		require.NoError(t, db.Create(m).Error)
+	}
+	require.NoError(t, db.Create(&RecipePopularityModel{RecipeID: soup.ID, Score: 120, RefreshedAt: now}).Error)
+
+	popularity := NewRecipePopularityRepository(db)
+	ctx := context.Background()
+	candidates, err := popularity.RankingCandidates(ctx)
+	require.NoError(t, err)
+	require.Len(t, candidates, 3, "drafts are not ranked")
+	byID := map[uuid.UUID]outbound.RankingCandidate{}
+	for _, c := range candidates {
+		byID[c.RecipeID] = c
+	}
+	assert.Equal(t, 120.0, byID[soup.ID].Popularity)
+	assert.Equal(t, 900, byID[lemonBars].Likes)
+	assert.True(t, byID[fresh.ID].PublishedAt.Equal(now))
+
+	require.NoError(t, popularity.ReplaceRankings(ctx, []outbound.RecipeRanking{{RecipeID: uuid.New(), TrendingRank: 1, TopRank: 1}}, now))
+	require.NoError(t, popularity.ReplaceRankings(ctx, []outbound.RecipeRanking{
+		{RecipeID: soup.ID, TrendingScore: 120, TrendingRank: 1, TopScore: 500, TopRank: 2},
+		{RecipeID: lemonBars, TrendingRank: 2, TopScore: 24500, TopRank: 1},
+	}, now))
+	var kept int64
+	require.NoError(t, db.Model(&RecipeRankingModel{}).Count(&kept).Error)
+	assert.Equal(t, int64(2), kept, "the previous rankings are replaced")
+
+	titles := func(orderBy string) []string {
+		recipes, total, err := NewRecipeRepository(db).Search(ctx, outbound.SearchCriteria{OrderBy: orderBy, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		names := make([]string, len(recipes))
+		for i, r := range recipes {
+			names[i] = r.Title()
+		}
+		return names
+	}
+	assert.Equal(t, []string{"Sorrel Soup", "Lemon Bars", "Brand New Pie"}, titles("trending"), "recipes not ranked yet come last")
+	assert.Equal(t, []string{"Lemon Bars", "Sorrel Soup", "Brand New Pie"}, titles("top"))
+	assert.Equal(t, []string{"Brand New Pie", "Sorrel Soup", "Lemon Bars"}, titles("new"))
+
+	popular, _, err := NewRecipeRepository(db).FindPopular(ctx, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, popular, 1)
+	assert.Equal(t, "Sorrel Soup", popular[0].Title())
+}
@@ -5,23 +5,99 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/alchemorsel/v3/internal/domain/recipe"
 	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/sqlsafe"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 )
 
+// inBatchSize caps the values bound to one IN list, well under the
+// PostgreSQL limit of 65535 parameters per statement
+const inBatchSize = 500
+
+// recipeListColumns are the columns list pages read. The ingredient,
+// instruction, nutrition and video documents are only needed on the recipe
+// page, so list queries leave them out; the small allergen label is kept
+// for card badges.
+var recipeListColumns = []string{
+	"recipes.id", "recipes.version", "recipes.title", "recipes.description", "recipes.author_id",
+	"recipes.cuisine", "recipes.category", "recipes.difficulty", "recipes.tags",
+	"recipes.prep_time_minutes", "recipes.cook_time_minutes", "recipes.total_time_minutes",
+	"recipes.servings", "recipes.calories", "recipes.ai_generated",
+	exactCounter("likes_count", outbound.RecipeCounterLikes),
+	exactCounter("views_count", outbound.RecipeCounterViews),
+	"recipes.average_rating", "recipes.images", "recipes.allergens",
+	"recipes.status", "recipes.published_at", "recipes.scheduled_publish_at",
+	"recipes.created_at", "recipes.updated_at",
+}
+
+// recipeColumns are all recipe columns, with the counters read exactly
+var recipeColumns = func() []string {
+	s, err := schema.Parse(&RecipeModel{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		panic(err)
+	}
+	columns := make([]string, 0, len(s.DBNames))
+	for _, name := range s.DBNames {
+		switch name {
+		case "likes_count":
+			columns = append(columns, exactCounter(name, outbound.RecipeCounterLikes))
+		case "views_count":
+			columns = append(columns, exactCounter(name, outbound.RecipeCounterViews))
+		case "forks_count":
+			columns = append(columns, exactCounter(name, outbound.RecipeCounterForks))
+		default:
+			columns = append(columns, "recipes."+name)
+		}
+	}
+	return columns
+}()
+
+// exactCounter selects a counter as its folded total plus the shards not
+// folded yet. Rankings order by the folded column instead, which keeps its
+// index and lags by at most one fold.
+func exactCounter(column, counter string) string {
+	return fmt.Sprintf("recipes.%s + COALESCE((SELECT SUM(recipe_counter_shards.value) FROM recipe_counter_shards"+
+		" WHERE recipe_counter_shards.recipe_id = recipes.id AND recipe_counter_shards.counter = '%s'), 0) AS %s",
+		column, counter, column)
+}
+
+// counterColumns are left out of recipe saves. Counters only change through
+// RecipeCounterRepository, so a save from a stale read cannot undo them.
+var counterColumns = []string{"likes_count", "views_count", "forks_count"}
+
 // RecipeRepository implements the recipe repository interface using GORM
 type RecipeRepository struct {
 	db *gorm.DB
+	// hot runs the listing and search queries as cached prepared
+	// statements, so they are parsed and planned once per connection
+	hot *gorm.DB
 }
 
 // NewRecipeRepository creates a new recipe repository
 func NewRecipeRepository(db *gorm.DB) outbound.RecipeRepository {
-	return &RecipeRepository{db: db}
+	return &RecipeRepository{
+		db:  db,
+		hot: db.Session(&gorm.Session{PrepareStmt: true}),
+	}
+}
+
+// listQuery selects the list columns and loads each page's authors in one
+// batched IN query, reading only what a recipe card shows
+func (r *RecipeRepository) listQuery(ctx context.Context) *gorm.DB {
+	return r.hot.WithContext(ctx).
+		Select(recipeListColumns).
+		Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "name", "profile_avatar")
+		})
 }
 
 // Create creates a new recipe
@@ -40,7 +116,7 @@ func (r *RecipeRepository) Create(ctx context.Context, recipe *recipe.Recipe) er
 func (r *RecipeRepository) Update(ctx context.Context, recipe *recipe.Recipe) error {
 	model := RecipeToModel(recipe)
 	
-	result := r.db.WithContext(ctx).Save(model)
+	result := r.db.WithContext(ctx).Omit(counterColumns...).Save(model)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -52,7 +128,10 @@ func (r *RecipeRepository) Update(ctx context.Context, recipe *recipe.Recipe) er
 	return nil
 }
 
-// Delete deletes a recipe by ID (soft delete)
+// Delete deletes a recipe by ID (soft delete). Ingredients, instructions
+// and tags are columns of the recipe row, so they go, and come back on
+// Restore, with it; the graph, browse and technique tables only read
+// recipes that are not deleted.
 func (r *RecipeRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	result := r.db.WithContext(ctx).Delete(&RecipeModel{}, "id = ?", id)
 	if result.Error != nil {
@@ -66,11 +145,51 @@ func (r *RecipeRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// FindDeletedByID finds a soft-deleted recipe by ID
+func (r *RecipeRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error) {
+	var model RecipeModel
+	
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Select(recipeColumns).
+		Preload("Author").
+		Where("deleted_at IS NOT NULL").
+		First(&model, "id = ?", id)
+		
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("recipe not found")
+		}
+		return nil, result.Error
+	}
+	
+	return ModelToRecipe(&model)
+}
+
+// Restore clears the soft-delete marker on a recipe
+func (r *RecipeRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&RecipeModel{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	
+	if result.RowsAffected == 0 {
+		return errors.New("recipe not found")
+	}
+	
+	return nil
+}
+
 // FindByID finds a recipe by ID
 func (r *RecipeRepository) FindByID(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error) {
 	var model RecipeModel
 	
 	result := r.db.WithContext(ctx).
+		Select(recipeColumns).
 		Preload("Author").
 		Preload("Ratings").
 		First(&model, "id = ?", id)
@@ -91,7 +210,7 @@ func (r *RecipeRepository) FindByUserID(ctx context.Context, userID uuid.UUID, o
 	var total int64
 	
 	// Count total
-	countResult := r.db.WithContext(ctx).Model(&RecipeModel{}).
+	countResult := r.hot.WithContext(ctx).Model(&RecipeModel{}).
 		Where("author_id = ?", userID).
 		Count(&total)
 	if countResult.Error != nil {
@@ -99,8 +218,7 @@ func (r *RecipeRepository) FindByUserID(ctx context.Context, userID uuid.UUID, o
 	}
 	
 	// Get recipes
-	result := r.db.WithContext(ctx).
-		Preload("Author").
+	result := r.listQuery(ctx).
 		Where("author_id = ?", userID).
 		Order("created_at DESC").
 		Offset(offset).
@@ -129,7 +247,7 @@ func (r *RecipeRepository) FindPublished(ctx context.Context, offset, limit int)
 	var total int64
 	
 	// Count total
-	countResult := r.db.WithContext(ctx).Model(&RecipeModel{}).
+	countResult := r.hot.WithContext(ctx).Model(&RecipeModel{}).
 		Where("status = ?", "published").
 		Count(&total)
 	if countResult.Error != nil {
@@ -137,8 +255,7 @@ func (r *RecipeRepository) FindPublished(ctx context.Context, offset, limit int)
 	}
 	
 	// Get recipes
-	result := r.db.WithContext(ctx).
-		Preload("Author").
+	result := r.listQuery(ctx).
 		Where("status = ?", "published").
 		Order("published_at DESC").
 		Offset(offset).
@@ -169,7 +286,7 @@ func (r *RecipeRepository) FindByStatus(ctx context.Context, status recipe.Recip
 	statusStr := string(status)
 	
 	// Count total
-	countResult := r.db.WithContext(ctx).Model(&RecipeModel{}).
+	countResult := r.hot.WithContext(ctx).Model(&RecipeModel{}).
 		Where("status = ?", statusStr).
 		Count(&total)
 	if countResult.Error != nil {
@@ -177,8 +294,7 @@ func (r *RecipeRepository) FindByStatus(ctx context.Context, status recipe.Recip
 	}
 	
 	// Get recipes
-	result := r.db.WithContext(ctx).
-		Preload("Author").
+	result := r.listQuery(ctx).
 		Where("status = ?", statusStr).
 		Order("created_at DESC").
 		Offset(offset).
@@ -201,20 +317,89 @@ func (r *RecipeRepository) FindByStatus(ctx context.Context, status recipe.Recip
 	return recipes, int(total), nil
 }
 
+// FindScheduledDue finds drafts whose scheduled publish time has passed,
+// oldest first
+func (r *RecipeRepository) FindScheduledDue(ctx context.Context, before time.Time, limit int) ([]*recipe.Recipe, error) {
+	var models []RecipeModel
+	
+	result := r.db.WithContext(ctx).
+		Preload("Author").
+		Where("status = ? AND scheduled_publish_at IS NOT NULL AND scheduled_publish_at <= ?", string(recipe.RecipeStatusDraft), before).
+		Order("scheduled_publish_at").
+		Limit(limit).
+		Find(&models)
+		
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	
+	recipes := make([]*recipe.Recipe, len(models))
+	for i, model := range models {
+		r, err := ModelToRecipe(&model)
+		if err != nil {
+			return nil, err
+		}
+		recipes[i] = r
+	}
+	
+	return recipes, nil
+}
+
 // Search searches for recipes based on criteria
 func (r *RecipeRepository) Search(ctx context.Context, criteria outbound.SearchCriteria) ([]*recipe.Recipe, int, error) {
-	query := r.db.WithContext(ctx).Model(&RecipeModel{}).Preload("Author")
+	query := r.searchFilter(ctx, criteria)
+	
+	// Count total
+	var total int64
+	countResult := query.Session(&gorm.Session{}).Count(&total)
+	if countResult.Error != nil {
+		return nil, 0, countResult.Error
+	}
+	
+	// Get recipes
+	var models []RecipeModel
+	result := r.searchPage(query, criteria).Find(&models)
+		
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	
+	recipes := make([]*recipe.Recipe, len(models))
+	for i, model := range models {
+		r, err := ModelToRecipe(&model)
+		if err != nil {
+			return nil, 0, err
+		}
+		recipes[i] = r
+	}
+	
+	return recipes, int(total), nil
+}
+
+// searchFilter applies the search criteria to published recipes. On
+// PostgreSQL the search text is matched against the search vector,
+// elsewhere each term is a substring match.
+func (r *RecipeRepository) searchFilter(ctx context.Context, criteria outbound.SearchCriteria) *gorm.DB {
+	query := r.hot.WithContext(ctx).Model(&RecipeModel{})
 	
 	// Apply filters
-	if criteria.Query != "" {
-		searchTerm := "%" + strings.ToLower(criteria.Query) + "%"
-		query = query.Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ?", searchTerm, searchTerm)
+	if terms := parseSearchTerms(criteria.Query); len(terms) > 0 {
+		query = r.matchSearchTerms(query, terms)
 	}
 	
 	if criteria.AuthorID != nil {
 		query = query.Where("author_id = ?", *criteria.AuthorID)
 	}
 	
+	if criteria.ExcludeAuthorID != nil {
+		query = query.Where("author_id <> ?", *criteria.ExcludeAuthorID)
+	}
+	
+	if criteria.FavoritedBy != nil {
+		saved := r.hot.WithContext(ctx).Model(&FavoriteModel{}).Select("recipe_id").Where("user_id = ?", *criteria.FavoritedBy)
+		query = query.Where("recipes.id IN (?)", saved)
+	}
+	
 	if len(criteria.Cuisines) > 0 {
 		cuisines := make([]string, len(criteria.Cuisines))
 		for i, c := range criteria.Cuisines {
@@ -247,20 +432,36 @@ func (r *RecipeRepository) Search(ctx context.Context, criteria outbound.SearchC
 		query = query.Where("total_time_minutes <= ?", *criteria.MaxTime)
 	}
 	
-	// Only show published recipes for search
-	query = query.Where("status = ?", "published")
+	for _, diet := range criteria.Dietary {
+		query = query.Where(sqlsafe.Like("CAST(recipes.tags AS TEXT)"), jsonStringPattern(diet))
+	}
 	
-	// Count total
-	var total int64
-	countQuery := query
-	countResult := countQuery.Count(&total)
-	if countResult.Error != nil {
-		return nil, 0, countResult.Error
+	if criteria.AIGenerated != nil {
+		query = query.Where("ai_generated = ?", *criteria.AIGenerated)
 	}
 	
+	// Only show published recipes for search
+	return query.Where("status = ?", "published")
+}
+
+// searchPage orders the filtered recipes and selects one page of them
+func (r *RecipeRepository) searchPage(query *gorm.DB, criteria outbound.SearchCriteria) *gorm.DB {
 	// Apply ordering
-	orderBy := "created_at DESC"
-	if criteria.OrderBy != "" {
+	var orderBy interface{} = "created_at DESC"
+	var rankings bool
+	if rank, ok := rankingOrders[criteria.OrderBy]; ok {
+		rankings, orderBy = true, rank
+	} else if criteria.OrderBy == "new" {
+		orderBy = keysetOrders["new"]
+	} else if criteria.OrderBy == "relevance" {
+		orderBy = r.relevanceOrder(parseSearchTerms(criteria.Query))
+	} else if criteria.OrderBy == "saved" && criteria.FavoritedBy != nil {
+		// Most recently saved first
+		orderBy = clause.OrderBy{Expression: clause.Expr{
+			SQL:  "(SELECT favorites.created_at FROM favorites WHERE favorites.recipe_id = recipes.id AND favorites.user_id = ?) DESC",
+			Vars: []interface{}{*criteria.FavoritedBy},
+		}}
+	} else if criteria.OrderBy != "" {
 		direction := "ASC"
 		if criteria.OrderDir == "desc" {
 			direction = "DESC"
@@ -272,44 +473,181 @@ func (r *RecipeRepository) Search(ctx context.Context, criteria outbound.SearchC
 		case "rating":
 			orderBy = fmt.Sprintf("average_rating %s", direction)
 		case "likes":
-			orderBy = fmt.Sprintf("likes_count %s", direction)
+			orderBy = fmt.Sprintf("recipes.likes_count %s", direction)
 		case "created_at":
 			orderBy = fmt.Sprintf("created_at %s", direction)
 		}
 	}
 	
-	// Get recipes
-	var models []RecipeModel
-	result := query.
+	page := query.Session(&gorm.Session{})
+	if rankings {
+		page = page.Joins("LEFT JOIN recipe_rankings ON recipe_rankings.recipe_id = recipes.id")
+	}
+	return page.
+		Select(recipeListColumns).
+		Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "name", "profile_avatar")
+		}).
 		Order(orderBy).
 		Offset(criteria.Offset).
-		Limit(criteria.Limit).
+		Limit(criteria.Limit)
+}
+
+// rankingOrders sort by a precomputed rank. Recipes published since the
+// last ranking have none yet and follow the ranked ones, newest first.
+var rankingOrders = map[string]string{
+	"trending": "recipe_rankings.trending_rank IS NULL, recipe_rankings.trending_rank, " + publishedAt + " DESC, recipes.id",
+	"top":      "recipe_rankings.top_rank IS NULL, recipe_rankings.top_rank, " + publishedAt + " DESC, recipes.id",
+}
+
+// publishedAt is when a recipe was published, its creation for recipes
+// published before the time was recorded
+const publishedAt = "COALESCE(recipes.published_at, recipes.created_at)"
+
+// keysetOrders sort the listings paged through by SearchAfter. Each ends
+// with the ID so the recipe a cursor names has a single successor.
+var keysetOrders = map[string]string{
+	"trending":  rankingOrders["trending"],
+	"top":       rankingOrders["top"],
+	"new":       publishedAt + " DESC, recipes.id",
+	"rating":    "recipes.average_rating DESC, recipes.id",
+	"prep_time": "recipes.prep_time_minutes, recipes.id",
+}
+
+// SearchAfter pages through a search by keyset, so a page costs the same
+// however deep it is and recipes published meanwhile do not shift it
+func (r *RecipeRepository) SearchAfter(ctx context.Context, criteria outbound.SearchCriteria) ([]*recipe.Recipe, *outbound.RecipeCursor, int, error) {
+	order, ok := keysetOrders[criteria.OrderBy]
+	if !ok {
+		return nil, nil, 0, fmt.Errorf("recipes cannot be paged by %q", criteria.OrderBy)
+	}
+	query := r.searchFilter(ctx, criteria)
+	
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, nil, 0, err
+	}
+	
+	page := query.Session(&gorm.Session{})
+	rank := ""
+	if _, ranked := rankingOrders[criteria.OrderBy]; ranked {
+		rank = "recipe_rankings." + criteria.OrderBy + "_rank"
+		page = page.Joins("LEFT JOIN recipe_rankings ON recipe_rankings.recipe_id = recipes.id")
+	}
+	if after := criteria.After; after != nil {
+		page = keysetAfter(page, criteria.OrderBy, rank, after)
+	}
+	
+	// One more than a page tells whether another follows
+	var models []RecipeModel
+	result := page.
+		Select(recipeListColumns).
+		Preload("Author", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "name", "profile_avatar")
+		}).
+		Order(order).
+		Limit(criteria.Limit + 1).
 		Find(&models)
-		
 	if result.Error != nil {
-		return nil, 0, result.Error
+		return nil, nil, 0, result.Error
+	}
+	
+	var next *outbound.RecipeCursor
+	if len(models) > criteria.Limit {
+		models = models[:criteria.Limit]
+		last := models[len(models)-1]
+		next = &outbound.RecipeCursor{ID: last.ID, PublishedAt: last.CreatedAt}
+		if last.PublishedAt != nil {
+			next.PublishedAt = *last.PublishedAt
+		}
+		switch criteria.OrderBy {
+		case "rating":
+			next.Value = last.AverageRating
+		case "prep_time":
+			next.Value = float64(last.PrepTimeMinutes)
+		case "trending", "top":
+			var ranks []int
+			err := r.hot.WithContext(ctx).Model(&RecipeRankingModel{}).
+				Where("recipe_id = ?", last.ID).
+				Pluck(criteria.OrderBy+"_rank", &ranks).Error
+			if err != nil {
+				return nil, nil, 0, err
+			}
+			if len(ranks) > 0 {
+				next.Value = float64(ranks[0])
+			}
+		}
 	}
 	
 	recipes := make([]*recipe.Recipe, len(models))
 	for i, model := range models {
 		r, err := ModelToRecipe(&model)
 		if err != nil {
-			return nil, 0, err
+			return nil, nil, 0, err
 		}
 		recipes[i] = r
 	}
-	
-	return recipes, int(total), nil
+	return recipes, next, int(total), nil
+}
+
+// keysetAfter keeps the recipes sorted after the cursor. Ranked recipes
+// come before those not ranked yet, which follow newest first.
+func keysetAfter(query *gorm.DB, orderBy, rank string, after *outbound.RecipeCursor) *gorm.DB {
+	newer := "(" + publishedAt + " < ? OR (" + publishedAt + " = ? AND recipes.id > ?))"
+	switch orderBy {
+	case "trending", "top":
+		if after.Value > 0 {
+			return query.Where("("+rank+" > ? OR "+rank+" IS NULL)", int(after.Value))
+		}
+		return query.Where(rank+" IS NULL AND "+newer, after.PublishedAt, after.PublishedAt, after.ID)
+	case "new":
+		return query.Where(newer, after.PublishedAt, after.PublishedAt, after.ID)
+	case "rating":
+		return query.Where("(recipes.average_rating < ? OR (recipes.average_rating = ? AND recipes.id > ?))",
+			after.Value, after.Value, after.ID)
+	default:
+		minutes := int(after.Value)
+		return query.Where("(recipes.prep_time_minutes > ? OR (recipes.prep_time_minutes = ? AND recipes.id > ?))",
+			minutes, minutes, after.ID)
+	}
+}
+
+// relevanceOrder ranks by ts_rank on PostgreSQL. Elsewhere titles starting
+// with the search text come first, then titles containing it, then other
+// matches. Ties go to the most liked. Without search text it is the
+// newest-first default.
+func (r *RecipeRepository) relevanceOrder(terms []searchTerm) interface{} {
+	if len(terms) == 0 {
+		return "created_at DESC"
+	}
+	if r.fullText() {
+		if match, vars := tsQuery(terms); match != "" {
+			return clause.OrderBy{Expression: clause.Expr{
+				SQL:  "ts_rank(recipes.search_vector, " + match + ") DESC, recipes.likes_count DESC, created_at DESC",
+				Vars: vars,
+			}}
+		}
+	}
+	words := make([]string, len(terms))
+	for i, term := range terms {
+		words[i] = term.text
+	}
+	text := strings.Join(words, " ")
+	title := sqlsafe.Like("recipes.title")
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL: "CASE WHEN " + title + " THEN 0 WHEN " + title + " THEN 1 ELSE 2 END, " +
+			"recipes.likes_count DESC, created_at DESC",
+		Vars: []interface{}{sqlsafe.Prefix(text), sqlsafe.Contains(text)},
+	}}
 }
 
 // FindTrending finds trending recipes since a given time
 func (r *RecipeRepository) FindTrending(ctx context.Context, since time.Time, limit int) ([]*recipe.Recipe, error) {
 	var models []RecipeModel
 	
-	result := r.db.WithContext(ctx).
-		Preload("Author").
+	result := r.listQuery(ctx).
 		Where("status = ? AND created_at >= ?", "published", since).
-		Order("likes_count DESC, views_count DESC, average_rating DESC").
+		Order("recipes.likes_count DESC, recipes.views_count DESC, average_rating DESC").
 		Limit(limit).
 		Find(&models)
 		
@@ -334,10 +672,9 @@ func (r *RecipeRepository) FindRecommended(ctx context.Context, userID uuid.UUID
 	// Simple recommendation: highest rated recipes the user hasn't created
 	var models []RecipeModel
 	
-	result := r.db.WithContext(ctx).
-		Preload("Author").
+	result := r.listQuery(ctx).
 		Where("status = ? AND author_id != ?", "published", userID).
-		Order("average_rating DESC, likes_count DESC").
+		Order("average_rating DESC, recipes.likes_count DESC").
 		Limit(limit).
 		Find(&models)
 		
@@ -357,13 +694,169 @@ func (r *RecipeRepository) FindRecommended(ctx context.Context, userID uuid.UUID
 	return recipes, nil
 }
 
-// FindByIDs finds recipes by multiple IDs
-func (r *RecipeRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*recipe.Recipe, error) {
+// FindPopular pages through the published recipes in trending order
+func (r *RecipeRepository) FindPopular(ctx context.Context, offset, limit int) ([]*recipe.Recipe, int, error) {
+	var total int64
+	countResult := r.hot.WithContext(ctx).Model(&RecipeModel{}).
+		Where("status = ?", "published").
+		Count(&total)
+	if countResult.Error != nil {
+		return nil, 0, countResult.Error
+	}
+
 	var models []RecipeModel
+	result := r.listQuery(ctx).
+		Joins("LEFT JOIN recipe_rankings ON recipe_rankings.recipe_id = recipes.id").
+		Where("recipes.status = ?", "published").
+		Order(rankingOrders["trending"]).
+		Offset(offset).
+		Limit(limit).
+		Find(&models)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	recipes := make([]*recipe.Recipe, len(models))
+	for i, model := range models {
+		r, err := ModelToRecipe(&model)
+		if err != nil {
+			return nil, 0, err
+		}
+		recipes[i] = r
+	}
+	return recipes, int(total), nil
+}
+
+// FindSearchFacets counts published recipes per cuisine, category,
+// difficulty and tag. Tags are stored as JSON, so they are counted here
+// rather than in SQL.
+func (r *RecipeRepository) FindSearchFacets(ctx context.Context, limit int) (*outbound.SearchFacets, error) {
+	facets := &outbound.SearchFacets{}
+	columns := []struct {
+		name   string
+		counts *[]outbound.FacetCount
+	}{
+		{"cuisine", &facets.Cuisines},
+		{"category", &facets.Categories},
+		{"difficulty", &facets.Difficulties},
+	}
+	for _, column := range columns {
+		var rows []struct {
+			Value string
+			Count int
+		}
+		result := r.db.WithContext(ctx).
+			Model(&RecipeModel{}).
+			Select(column.name+" AS value, COUNT(*) AS count").
+			Where("status = ? AND "+column.name+" <> ''", "published").
+			Group(column.name).
+			Order("count DESC, value").
+			Limit(limit).
+			Scan(&rows)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+
+		counts := make([]outbound.FacetCount, len(rows))
+		for i, row := range rows {
+			counts[i] = outbound.FacetCount{Value: row.Value, Count: row.Count}
+		}
+		*column.counts = counts
+	}
+
+	var tagLists []StringSlice
+	result := r.db.WithContext(ctx).
+		Model(&RecipeModel{}).
+		Where("status = ?", "published").
+		Pluck("tags", &tagLists)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	facets.Tags = countTags(tagLists)
+	if len(facets.Tags) > limit {
+		facets.Tags = facets.Tags[:limit]
+	}
+
+	return facets, nil
+}
+
+// countTags counts the recipes carrying each lower-cased tag, most common
+// first
+func countTags(tagLists []StringSlice) []outbound.FacetCount {
+	tagCounts := make(map[string]int)
+	for _, tags := range tagLists {
+		for _, tag := range tags {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				tagCounts[tag]++
+			}
+		}
+	}
+	counts := make([]outbound.FacetCount, 0, len(tagCounts))
+	for tag, count := range tagCounts {
+		counts = append(counts, outbound.FacetCount{Value: tag, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	return counts
+}
+
+// AddLike records that a user liked a recipe; repeated likes are ignored
+func (r *RecipeRepository) AddLike(ctx context.Context, recipeID, userID uuid.UUID) error {
+	like := RecipeLikeModel{RecipeID: recipeID, UserID: userID, CreatedAt: time.Now()}
 	
 	result := r.db.WithContext(ctx).
-		Preload("Author").
-		Where("id IN ?", ids).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&like)
+	return result.Error
+}
+
+// FindLikedByUser returns the recipes a user liked, most recent first
+func (r *RecipeRepository) FindLikedByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*recipe.Recipe, error) {
+	var models []RecipeModel
+	
+	result := r.listQuery(ctx).
+		Joins("JOIN recipe_likes ON recipe_likes.recipe_id = recipes.id").
+		Where("recipe_likes.user_id = ?", userID).
+		Order("recipe_likes.created_at DESC").
+		Limit(limit).
+		Find(&models)
+		
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	
+	recipes := make([]*recipe.Recipe, len(models))
+	for i, model := range models {
+		r, err := ModelToRecipe(&model)
+		if err != nil {
+			return nil, err
+		}
+		recipes[i] = r
+	}
+	
+	return recipes, nil
+}
+
+// FindLatestByAuthors returns the newest published recipes of any of the
+// authors
+func (r *RecipeRepository) FindLatestByAuthors(ctx context.Context, authorIDs []uuid.UUID, limit int) ([]*recipe.Recipe, error) {
+	if len(authorIDs) == 0 {
+		return nil, nil
+	}
+	if len(authorIDs) > inBatchSize {
+		authorIDs = authorIDs[:inBatchSize]
+	}
+	
+	var models []RecipeModel
+	result := r.listQuery(ctx).
+		Where("status = ? AND author_id IN ?", "published", authorIDs).
+		Order("recipes.created_at DESC").
+		Limit(limit).
 		Find(&models)
 		
 	if result.Error != nil {
@@ -382,6 +875,42 @@ func (r *RecipeRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*r
 	return recipes, nil
 }
 
+// FindByIDs finds recipes by multiple IDs, querying in IN batches so a
+// long list cannot exceed the statement parameter limit
+func (r *RecipeRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*recipe.Recipe, error) {
+	var models []RecipeModel
+	
+	for start := 0; start < len(ids); start += inBatchSize {
+		end := start + inBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		
+		var batch []RecipeModel
+		result := r.hot.WithContext(ctx).
+			Select(recipeColumns).
+			Preload("Author").
+			Where("id IN ?", ids[start:end]).
+			Find(&batch)
+		
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		models = append(models, batch...)
+	}
+	
+	recipes := make([]*recipe.Recipe, len(models))
+	for i, model := range models {
+		r, err := ModelToRecipe(&model)
+		if err != nil {
+			return nil, err
+		}
+		recipes[i] = r
+	}
+	
+	return recipes, nil
+}
+
 // BulkCreate creates multiple recipes
 func (r *RecipeRepository) BulkCreate(ctx context.Context, recipes []*recipe.Recipe) error {
 	models := make([]*RecipeModel, len(recipes))
@@ -403,6 +932,7 @@ func (r *RecipeRepository) UpdateWithVersion(ctx context.Context, recipe *recipe
 	
 	result := r.db.WithContext(ctx).
 		Model(model).
+		Omit(counterColumns...).
 		Where("id = ? AND version = ?", model.ID, expectedVersion).
 		Updates(model)
 		
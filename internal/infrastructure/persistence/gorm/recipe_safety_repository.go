@@ -0,0 +1,46 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecipeSafetyRepository reads recipe ingredients and steps for the food
+// safety check using GORM
+type RecipeSafetyRepository struct {
+	db *gorm.DB
+}
+
+// NewRecipeSafetyRepository creates a new recipe safety repository
+func NewRecipeSafetyRepository(db *gorm.DB) outbound.RecipeSafetyRepository {
+	return &RecipeSafetyRepository{db: db}
+}
+
+// Recipe reads a recipe's ingredient names and instructions
+func (r *RecipeSafetyRepository) Recipe(ctx context.Context, recipeID uuid.UUID) (*outbound.SafetyRecipe, error) {
+	var models []RecipeModel
+	err := r.db.WithContext(ctx).
+		Select("id, author_id, title, status, ingredients, instructions").
+		Where("id = ?", recipeID).
+		Limit(1).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	model := models[0]
+	return &outbound.SafetyRecipe{
+		ID:          model.ID,
+		AuthorID:    model.AuthorID,
+		Title:       model.Title,
+		Status:      model.Status,
+		Ingredients: jsonListField(model.Ingredients, "name", "data", "ingredients"),
+		Steps:       timelineSteps(model.Instructions),
+	}, nil
+}
@@ -0,0 +1,256 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchRelevanceRanksTitleMatchesFirst(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+
+	now := time.Now()
+	for i, r := range []struct{ title, description string }{
+		{"Soup Dumplings", ""},
+		{"Tomato Soup", ""},
+		{"Crusty Bread", "Great with soup"},
+	} {
+		require.NoError(t, db.Create(&RecipeModel{
+			ID:          uuid.New(),
+			Title:       r.title,
+			Description: r.description,
+			AuthorID:    author.ID,
+			Status:      "published",
+			CreatedAt:   now.Add(time.Duration(i) * time.Minute),
+		}).Error)
+	}
+
+	titles := func(orderBy string) []string {
+		recipes, total, err := NewRecipeRepository(db).Search(context.Background(), outbound.SearchCriteria{
+			Query:   "soup",
+			Limit:   10,
+			OrderBy: orderBy,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		var out []string
+		for _, r := range recipes {
+			out = append(out, r.Title())
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"Crusty Bread", "Tomato Soup", "Soup Dumplings"}, titles(""))
+	assert.Equal(t, []string{"Soup Dumplings", "Tomato Soup", "Crusty Bread"}, titles("relevance"))
+}
+
+func TestSearchTreatsInputAsText(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+	for _, title := range []string{"100% Rye Bread", "Brown_Butter Cookies", "Brownie Bites"} {
+		require.NoError(t, db.Create(&RecipeModel{ID: uuid.New(), Title: title, AuthorID: author.ID, Status: "published"}).Error)
+	}
+	require.NoError(t, db.Create(&RecipeModel{ID: uuid.New(), Title: "Secret Draft", AuthorID: author.ID, Status: "draft"}).Error)
+
+	search := func(criteria outbound.SearchCriteria) []string {
+		criteria.Limit = 10
+		recipes, total, err := NewRecipeRepository(db).Search(context.Background(), criteria)
+		require.NoError(t, err)
+		assert.Equal(t, len(recipes), total)
+		var out []string
+		for _, r := range recipes {
+			out = append(out, r.Title())
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"100% Rye Bread"}, search(outbound.SearchCriteria{Query: "%"}))
+	assert.Equal(t, []string{"Brown_Butter Cookies"}, search(outbound.SearchCriteria{Query: "brown_", OrderBy: "relevance"}))
+	for _, attempt := range []string{
+		"' OR 1=1 --",
+		"%' OR status = 'draft",
+		"x'); DROP TABLE recipes; --",
+		`\`,
+	} {
+		assert.Empty(t, search(outbound.SearchCriteria{Query: attempt}), attempt)
+		assert.Empty(t, search(outbound.SearchCriteria{Query: attempt, OrderBy: "relevance"}), attempt)
+		assert.Empty(t, search(outbound.SearchCriteria{Cuisines: []recipe.CuisineType{recipe.CuisineType(attempt)}}), attempt)
+	}
+	assert.Len(t, search(outbound.SearchCriteria{}), 4, "the recipes table is intact")
+}
+
+func TestSearchMatchesPhrasesPrefixesIngredientsAndTags(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	repo := NewRecipeRepository(db)
+	ctx := context.Background()
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+
+	for _, r := range []struct {
+		title, description, ingredient string
+		tags                           []string
+	}{
+		{"Tomato Soup", "Slow roasted tomatoes", "Basil", []string{"vegetarian"}},
+		{"Pesto Pasta", "Bright and green", "Olive oil", []string{"quick"}},
+		{"Oil Cake", "Olive grove inspired", "Flour", nil},
+	} {
+		entity, err := recipe.NewRecipe(r.title, r.description, author.ID)
+		require.NoError(t, err)
+		require.NoError(t, entity.AddIngredient(recipe.Ingredient{ID: uuid.New(), Name: r.ingredient, Amount: 1, Unit: recipe.MeasurementUnitCup}))
+		entity.SetTags(r.tags)
+		require.NoError(t, repo.Create(ctx, entity))
+	}
+	require.NoError(t, db.Model(&RecipeModel{}).Where("1 = 1").Update("status", "published").Error)
+
+	search := func(text string) []string {
+		recipes, _, err := repo.Search(ctx, outbound.SearchCriteria{Query: text, Limit: 10, OrderBy: "title"})
+		require.NoError(t, err)
+		var out []string
+		for _, r := range recipes {
+			out = append(out, r.Title())
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"Tomato Soup"}, search("basil"), "ingredient names are searched")
+	assert.Equal(t, []string{"Pesto Pasta"}, search("QUICK"), "tags are searched")
+	assert.Equal(t, []string{"Oil Cake", "Pesto Pasta"}, search("olive oil"))
+	assert.Equal(t, []string{"Pesto Pasta"}, search(`"olive oil"`))
+	assert.Equal(t, []string{"Tomato Soup"}, search("tomat* soup"))
+	assert.Empty(t, search(`"soup tomato"`))
+}
+
+func TestParseSearchTerms(t *testing.T) {
+	assert.Equal(t, []searchTerm{
+		{text: "olive oil", phrase: true},
+		{text: "tom", prefix: true},
+		{text: "basil"},
+		{text: "lemon"},
+		{text: "slow cooked", phrase: true},
+	}, parseSearchTerms(`  "Olive   Oil" Tom* * "basil" lemon "" "slow  cooked`))
+
+	match, vars := tsQuery(parseSearchTerms(`"olive oil" tom* bas:il* !*`))
+	assert.Equal(t, "phraseto_tsquery('english', ?) && to_tsquery('english', ?) && to_tsquery('english', ?)", match)
+	assert.Equal(t, []interface{}{"olive oil", "tom:*", "basil:*"}, vars)
+}
+
+func TestResultFacetsLeaveOutTheirOwnFilter(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	repo := NewRecipeRepository(db)
+	ctx := context.Background()
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+
+	for _, r := range []struct {
+		title, cuisine, difficulty string
+		minutes                    int
+		ai                         bool
+		tags                       []string
+	}{
+		{"Green Curry", "thai", "medium", 40, false, []string{"vegan", "spicy"}},
+		{"Pad Thai", "thai", "easy", 25, true, []string{"vegetarian"}},
+		{"Tom Yum", "thai", "easy", 30, false, []string{"vegan_ish"}},
+		{"Ratatouille", "french", "medium", 90, false, []string{"vegan"}},
+	} {
+		require.NoError(t, db.Create(&RecipeModel{
+			ID: uuid.New(), Title: r.title, AuthorID: author.ID, Status: "published",
+			Cuisine: r.cuisine, Difficulty: r.difficulty, TotalTimeMinutes: r.minutes, AIGenerated: r.ai, Tags: r.tags,
+		}).Error)
+	}
+
+	vegan, chefs := []string{"vegan"}, false
+	criteria := outbound.SearchCriteria{Cuisines: []recipe.CuisineType{"thai"}, Dietary: vegan, AIGenerated: &chefs, Limit: 10}
+	recipes, total, err := repo.Search(ctx, criteria)
+	require.NoError(t, err)
+	require.Equal(t, 1, total, "vegan_ish is not vegan")
+	assert.Equal(t, "Green Curry", recipes[0].Title())
+
+	facets, err := repo.FindResultFacets(ctx, criteria, []int{30, 60})
+	require.NoError(t, err)
+	assert.Equal(t, []outbound.FacetCount{{Value: "french", Count: 1}, {Value: "thai", Count: 1}}, facets.Cuisines)
+	assert.Equal(t, []outbound.FacetCount{{Value: "medium", Count: 1}}, facets.Difficulties)
+	assert.Equal(t, []outbound.FacetCount{{Value: "30", Count: 0}, {Value: "60", Count: 1}}, facets.MaxTimes)
+	assert.Equal(t, []outbound.FacetCount{{Value: "false", Count: 1}}, facets.AIGenerated)
+	assert.Contains(t, facets.Tags, outbound.FacetCount{Value: "vegan_ish", Count: 1}, "tags are counted without the dietary filter")
+
+	facets, err = repo.FindResultFacets(ctx, outbound.SearchCriteria{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []outbound.FacetCount{{Value: "true", Count: 1}, {Value: "false", Count: 4}}, facets.AIGenerated, "with the fixture's recipe")
+	assert.Empty(t, facets.MaxTimes)
+}
+
+func TestSearchAfterPagesEachListingByCursor(t *testing.T) {
+	db, lemonBars := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&RecipeRankingModel{}))
+	var author UserModel
+	require.NoError(t, db.First(&author).Error)
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, db.Model(&RecipeModel{}).Where("id = ?", lemonBars).Updates(map[string]interface{}{
+		"published_at": now.AddDate(0, -1, 0), "average_rating": 4.5, "prep_time_minutes": 30,
+	}).Error)
+	ids := map[string]uuid.UUID{"Lemon Bars": lemonBars}
+	for i, r := range []struct {
+		title  string
+		rating float64
+		prep   int
+	}{
+		{"Sorrel Soup", 4.5, 10},
+		{"Brand New Pie", 3.0, 45},
+		{"Leek Gratin", 4.8, 10},
+	} {
+		published := now.AddDate(0, 0, -i)
+		model := RecipeModel{ID: uuid.New(), Title: r.title, AuthorID: author.ID, Status: "published",
+			AverageRating: r.rating, PrepTimeMinutes: r.prep, PublishedAt: &published}
+		require.NoError(t, db.Create(&model).Error)
+		ids[r.title] = model.ID
+	}
+	// Brand New Pie and Leek Gratin are not ranked yet
+	require.NoError(t, NewRecipePopularityRepository(db).ReplaceRankings(context.Background(), []outbound.RecipeRanking{
+		{RecipeID: lemonBars, TrendingRank: 1, TopRank: 2},
+		{RecipeID: ids["Sorrel Soup"], TrendingRank: 2, TopRank: 1},
+	}, now))
+
+	repo := NewRecipeRepository(db)
+	pages := func(orderBy string) []string {
+		var titles []string
+		criteria := outbound.SearchCriteria{OrderBy: orderBy, Limit: 1}
+		for {
+			recipes, next, total, err := repo.SearchAfter(context.Background(), criteria)
+			require.NoError(t, err)
+			assert.Equal(t, 4, total)
+			require.Len(t, recipes, 1)
+			titles = append(titles, recipes[0].Title())
+			if next == nil {
+				return titles
+			}
+			require.Less(t, len(titles), 4, "the last page has no cursor")
+			criteria.After = next
+		}
+	}
+
+	assert.Equal(t, []string{"Lemon Bars", "Sorrel Soup", "Brand New Pie", "Leek Gratin"}, pages("trending"))
+	assert.Equal(t, []string{"Sorrel Soup", "Lemon Bars", "Brand New Pie", "Leek Gratin"}, pages("top"))
+	assert.Equal(t, []string{"Sorrel Soup", "Brand New Pie", "Leek Gratin", "Lemon Bars"}, pages("new"))
+	// Ties go to the lower ID
+	byID := func(a, b string) []string {
+		if ids[a].String() > ids[b].String() {
+			return []string{b, a}
+		}
+		return []string{a, b}
+	}
+	assert.Equal(t, append(append([]string{"Leek Gratin"}, byID("Lemon Bars", "Sorrel Soup")...), "Brand New Pie"), pages("rating"))
+	assert.Equal(t, append(byID("Sorrel Soup", "Leek Gratin"), "Lemon Bars", "Brand New Pie"), pages("prep_time"))
+
+	_, _, _, err := repo.SearchAfter(context.Background(), outbound.SearchCriteria{OrderBy: "title", Limit: 1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecipeTimelineRepository reads recipe steps and their durations using GORM
+type RecipeTimelineRepository struct {
+	db *gorm.DB
+}
+
+// NewRecipeTimelineRepository creates a new recipe timeline repository
+func NewRecipeTimelineRepository(db *gorm.DB) outbound.RecipeTimelineRepository {
+	return &RecipeTimelineRepository{db: db}
+}
+
+// Steps reads a recipe's instructions. Durations are stored in minutes.
+func (r *RecipeTimelineRepository) Steps(ctx context.Context, recipeID uuid.UUID) (*outbound.TimelineRecipe, error) {
+	var models []RecipeModel
+	err := r.db.WithContext(ctx).
+		Select("id, author_id, title, status, instructions").
+		Where("id = ?", recipeID).
+		Limit(1).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	model := models[0]
+	return &outbound.TimelineRecipe{
+		ID:       model.ID,
+		AuthorID: model.AuthorID,
+		Title:    model.Title,
+		Status:   model.Status,
+		Steps:    timelineSteps(model.Instructions),
+	}, nil
+}
+
+// timelineSteps reads the steps of an instructions column
+func timelineSteps(instructions JSONField) []outbound.TimelineStep {
+	var steps []outbound.TimelineStep
+	for _, key := range []string{"data", "instructions"} {
+		list, ok := instructions[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range list {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			text, _ := object["description"].(string)
+			if text == "" {
+				continue
+			}
+			minutes, _ := object["duration"].(float64)
+			steps = append(steps, outbound.TimelineStep{
+				Text:     text,
+				Duration: time.Duration(minutes * float64(time.Minute)),
+			})
+		}
+		break
+	}
+	return steps
+}
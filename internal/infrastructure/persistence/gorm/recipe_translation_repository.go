@@ -0,0 +1,119 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecipeTranslationRepository stores per-field recipe translations using
+// GORM
+type RecipeTranslationRepository struct {
+	db *gorm.DB
+}
+
+// NewRecipeTranslationRepository creates a new recipe translation repository
+func NewRecipeTranslationRepository(db *gorm.DB) outbound.RecipeTranslationRepository {
+	return &RecipeTranslationRepository{db: db}
+}
+
+// Source reads a recipe's title, description, ingredient names and steps
+func (r *RecipeTranslationRepository) Source(ctx context.Context, recipeID uuid.UUID) (*outbound.TranslationSource, error) {
+	var models []RecipeModel
+	err := r.db.WithContext(ctx).
+		Select("id, author_id, status, language, title, description, ingredients, instructions").
+		Where("id = ?", recipeID).
+		Limit(1).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	model := models[0]
+	source := &outbound.TranslationSource{
+		ID:          model.ID,
+		AuthorID:    model.AuthorID,
+		Status:      model.Status,
+		Language:    model.Language,
+		Title:       model.Title,
+		Description: model.Description,
+		Ingredients: jsonListField(model.Ingredients, "name", "data", "ingredients"),
+	}
+	for _, step := range timelineSteps(model.Instructions) {
+		source.Steps = append(source.Steps, step.Text)
+	}
+	return source, nil
+}
+
+// Fields lists a recipe's translated fields in one language
+func (r *RecipeTranslationRepository) Fields(ctx context.Context, recipeID uuid.UUID, language string) ([]outbound.TranslatedField, error) {
+	var models []RecipeTranslationModel
+	err := r.db.WithContext(ctx).
+		Where("recipe_id = ? AND language = ?", recipeID, language).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]outbound.TranslatedField, len(models))
+	for i, model := range models {
+		fields[i] = outbound.TranslatedField{
+			RecipeID:   model.RecipeID,
+			Language:   model.Language,
+			Field:      model.Field,
+			Text:       model.Text,
+			SourceHash: model.SourceHash,
+			Provenance: model.Provenance,
+			Model:      model.Model,
+			EditorID:   model.EditorID,
+			UpdatedAt:  model.UpdatedAt,
+		}
+	}
+	return fields, nil
+}
+
+// Languages lists the languages a recipe has translations in
+func (r *RecipeTranslationRepository) Languages(ctx context.Context, recipeID uuid.UUID) ([]string, error) {
+	var languages []string
+	err := r.db.WithContext(ctx).
+		Model(&RecipeTranslationModel{}).
+		Where("recipe_id = ?", recipeID).
+		Distinct().
+		Order("language").
+		Pluck("language", &languages).Error
+	return languages, err
+}
+
+// Save upserts translated fields by recipe, language and field
+func (r *RecipeTranslationRepository) Save(ctx context.Context, fields []outbound.TranslatedField) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	models := make([]RecipeTranslationModel, len(fields))
+	for i, field := range fields {
+		models[i] = RecipeTranslationModel{
+			RecipeID:   field.RecipeID,
+			Language:   field.Language,
+			Field:      field.Field,
+			Text:       field.Text,
+			SourceHash: field.SourceHash,
+			Provenance: field.Provenance,
+			Model:      field.Model,
+			EditorID:   field.EditorID,
+			UpdatedAt:  field.UpdatedAt,
+		}
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "recipe_id"}, {Name: "language"}, {Name: "field"}},
+			DoUpdates: clause.AssignmentColumns([]string{"text", "source_hash", "provenance", "model", "editor_id", "updated_at"}),
+		}).
+		Create(&models).Error
+}
@@ -0,0 +1,97 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecommendationSignalRepository implements
+// outbound.RecommendationSignalRepository using GORM
+type RecommendationSignalRepository struct {
+	db *gorm.DB
+}
+
+// NewRecommendationSignalRepository creates a new recommendation signal
+// repository
+func NewRecommendationSignalRepository(db *gorm.DB) outbound.RecommendationSignalRepository {
+	return &RecommendationSignalRepository{db: db}
+}
+
+// interactionRow is a user and a recipe they engaged with
+type interactionRow struct {
+	UserID   uuid.UUID
+	RecipeID uuid.UUID
+}
+
+// Interactions reads the likes, favorites and views since a time. Each
+// kind is bounded separately so a flood of views cannot crowd out likes.
+func (r *RecommendationSignalRepository) Interactions(ctx context.Context, since time.Time, limit int) ([]outbound.Interaction, error) {
+	db := r.db.WithContext(ctx)
+	queries := []struct {
+		kind  outbound.InteractionKind
+		query *gorm.DB
+	}{
+		{outbound.InteractionLike, db.Model(&RecipeLikeModel{}).
+			Select("user_id, recipe_id").
+			Where("created_at >= ?", since).
+			Order("created_at DESC")},
+		{outbound.InteractionFavorite, db.Model(&FavoriteModel{}).
+			Select("user_id, recipe_id").
+			Where("created_at >= ?", since).
+			Order("created_at DESC")},
+		{outbound.InteractionView, db.Model(&RecipeViewModel{}).
+			Select("user_id, recipe_id").
+			Where("user_id IS NOT NULL AND viewed_at >= ?", since).
+			Group("user_id, recipe_id").
+			Order("MAX(viewed_at) DESC")},
+	}
+
+	var interactions []outbound.Interaction
+	for _, q := range queries {
+		var rows []interactionRow
+		if err := q.query.Limit(limit).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			interactions = append(interactions, outbound.Interaction{UserID: row.UserID, RecipeID: row.RecipeID, Kind: q.kind})
+		}
+	}
+	return interactions, nil
+}
+
+// EachPublished streams the cuisine, tags and ingredient names of the
+// published recipes
+func (r *RecommendationSignalRepository) EachPublished(ctx context.Context, fn func(outbound.RecipeFeatures) error) error {
+	db := r.db.WithContext(ctx)
+	rows, err := db.Model(&RecipeModel{}).
+		Select("id, author_id, title, cuisine, tags, ingredients").
+		Where("status = ?", "published").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipe RecipeModel
+		if err := db.ScanRows(rows, &recipe); err != nil {
+			return err
+		}
+		err := fn(outbound.RecipeFeatures{
+			RecipeID:    recipe.ID,
+			AuthorID:    recipe.AuthorID,
+			Title:       recipe.Title,
+			Cuisine:     recipe.Cuisine,
+			Tags:        []string(recipe.Tags),
+			Ingredients: jsonListField(recipe.Ingredients, "name", "data", "ingredients"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,43 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecommendationInteractionsAreRecentAndViewedOnce(t *testing.T) {
+	db, lemonBars := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&RecipeLikeModel{}, &FavoriteModel{}, &RecipeViewModel{}))
+	repo := NewRecommendationSignalRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	reader, since := uuid.New(), now.Add(-24*time.Hour)
+
+	require.NoError(t, db.Create(&RecipeLikeModel{RecipeID: lemonBars, UserID: reader, CreatedAt: now}).Error)
+	require.NoError(t, db.Create(&FavoriteModel{RecipeID: lemonBars, UserID: uuid.New(), CreatedAt: now.Add(-48 * time.Hour)}).Error)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, db.Create(&RecipeViewModel{ID: uuid.New(), RecipeID: lemonBars, UserID: &reader, ViewedAt: now.Add(-time.Duration(i) * time.Hour)}).Error)
+	}
+	require.NoError(t, db.Create(&RecipeViewModel{ID: uuid.New(), RecipeID: lemonBars, ViewedAt: now}).Error)
+
+	interactions, err := repo.Interactions(ctx, since, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []outbound.Interaction{
+		{UserID: reader, RecipeID: lemonBars, Kind: outbound.InteractionLike},
+		{UserID: reader, RecipeID: lemonBars, Kind: outbound.InteractionView},
+	}, interactions, "old favorites and anonymous views are left out")
+
+	var features []outbound.RecipeFeatures
+	require.NoError(t, repo.EachPublished(ctx, func(f outbound.RecipeFeatures) error {
+		features = append(features, f)
+		return nil
+	}))
+	require.Len(t, features, 1)
+	assert.Equal(t, "Lemon Bars", features[0].Title)
+}
@@ -0,0 +1,134 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alchemorsel/v3/internal/domain/report"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReportRepository implements outbound.ReportRepository using GORM
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db *gorm.DB) outbound.ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// Create stores a new report
+func (r *ReportRepository) Create(ctx context.Context, rep *report.Report) error {
+	m := reportToModel(rep)
+	return r.db.WithContext(ctx).Create(&m).Error
+}
+
+// FindByID returns a report, or nil when there is none
+func (r *ReportRepository) FindByID(ctx context.Context, id uuid.UUID) (*report.Report, error) {
+	var m ReportModel
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reportFromModel(m), nil
+}
+
+// HasOpen reports whether a reader has an open report on the target
+func (r *ReportRepository) HasOpen(ctx context.Context, target report.TargetType, targetID, reporterID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.openOn(ctx, target, targetID).
+		Where("reporter_id = ?", reporterID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountOpen counts the open reports on the target
+func (r *ReportRepository) CountOpen(ctx context.Context, target report.TargetType, targetID uuid.UUID) (int, error) {
+	var count int64
+	err := r.openOn(ctx, target, targetID).Count(&count).Error
+	return int(count), err
+}
+
+// FindOpen pages through open reports, oldest first
+func (r *ReportRepository) FindOpen(ctx context.Context, offset, limit int) ([]*report.Report, int64, error) {
+	query := r.db.WithContext(ctx).Model(&ReportModel{}).Where("status = ?", string(report.StatusOpen))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var models []ReportModel
+	if err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	reports := make([]*report.Report, 0, len(models))
+	for _, m := range models {
+		reports = append(reports, reportFromModel(m))
+	}
+	return reports, total, nil
+}
+
+// MarkUnpublished flags the target's open reports as having taken it down
+func (r *ReportRepository) MarkUnpublished(ctx context.Context, target report.TargetType, targetID uuid.UUID) error {
+	return r.openOn(ctx, target, targetID).Update("unpublished", true).Error
+}
+
+// CloseOpen closes the target's open reports with the verdict's status,
+// moderator and resolution
+func (r *ReportRepository) CloseOpen(ctx context.Context, target report.TargetType, targetID uuid.UUID, verdict *report.Report) (int, error) {
+	result := r.openOn(ctx, target, targetID).Updates(map[string]interface{}{
+		"status":      string(verdict.Status),
+		"resolved_by": verdict.ResolvedBy,
+		"resolution":  verdict.Resolution,
+		"resolved_at": verdict.ResolvedAt,
+	})
+	return int(result.RowsAffected), result.Error
+}
+
+func (r *ReportRepository) openOn(ctx context.Context, target report.TargetType, targetID uuid.UUID) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&ReportModel{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", string(target), targetID, string(report.StatusOpen))
+}
+
+func reportToModel(rep *report.Report) ReportModel {
+	return ReportModel{
+		ID:          rep.ID,
+		TargetType:  string(rep.TargetType),
+		TargetID:    rep.TargetID,
+		RecipeID:    rep.RecipeID,
+		ReporterID:  rep.ReporterID,
+		Reason:      string(rep.Reason),
+		Note:        rep.Note,
+		Status:      string(rep.Status),
+		Unpublished: rep.Unpublished,
+		ResolvedBy:  rep.ResolvedBy,
+		Resolution:  rep.Resolution,
+		CreatedAt:   rep.CreatedAt,
+		ResolvedAt:  rep.ResolvedAt,
+	}
+}
+
+func reportFromModel(m ReportModel) *report.Report {
+	return &report.Report{
+		ID:          m.ID,
+		TargetType:  report.TargetType(m.TargetType),
+		TargetID:    m.TargetID,
+		RecipeID:    m.RecipeID,
+		ReporterID:  m.ReporterID,
+		Reason:      report.Reason(m.Reason),
+		Note:        m.Note,
+		Status:      report.Status(m.Status),
+		Unpublished: m.Unpublished,
+		ResolvedBy:  m.ResolvedBy,
+		Resolution:  m.Resolution,
+		CreatedAt:   m.CreatedAt,
+		ResolvedAt:  m.ResolvedAt,
+	}
+}
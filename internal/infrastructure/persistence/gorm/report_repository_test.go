@@ -0,0 +1,71 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/report"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportRepositoryClosesEveryOpenReportOnTheTarget(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&ReportModel{}))
+	repo := NewReportRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	author, recipeID, commentID := uuid.New(), uuid.New(), uuid.New()
+
+	newReport := func(target report.TargetType, targetID uuid.UUID, at time.Time) *report.Report {
+		r, err := report.NewReport(target, targetID, recipeID, author, uuid.New(), report.ReasonSpam, "", at)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, r))
+		return r
+	}
+	first := newReport(report.TargetRecipe, recipeID, now)
+	newReport(report.TargetRecipe, recipeID, now.Add(time.Minute))
+	onComment := newReport(report.TargetComment, commentID, now.Add(-time.Minute))
+
+	open, err := repo.HasOpen(ctx, report.TargetRecipe, recipeID, first.ReporterID)
+	require.NoError(t, err)
+	assert.True(t, open)
+	open, err = repo.HasOpen(ctx, report.TargetComment, recipeID, first.ReporterID)
+	require.NoError(t, err)
+	assert.False(t, open, "reports are per target type")
+	count, err := repo.CountOpen(ctx, report.TargetRecipe, recipeID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	require.NoError(t, repo.MarkUnpublished(ctx, report.TargetRecipe, recipeID))
+	found, err := repo.FindByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.True(t, found.Unpublished)
+
+	reports, total, err := repo.FindOpen(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, total)
+	require.Len(t, reports, 3)
+	assert.Equal(t, onComment.ID, reports[0].ID, "oldest first")
+	assert.False(t, reports[0].Unpublished)
+
+	require.NoError(t, found.Close(report.StatusDismissed, uuid.New(), "Fine", now))
+	closed, err := repo.CloseOpen(ctx, report.TargetRecipe, recipeID, found)
+	require.NoError(t, err)
+	assert.Equal(t, 2, closed)
+
+	found, err = repo.FindByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, report.StatusDismissed, found.Status)
+	assert.Equal(t, "Fine", found.Resolution)
+	require.NotNil(t, found.ResolvedAt)
+	_, total, err = repo.FindOpen(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+
+	missing, err := repo.FindByID(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
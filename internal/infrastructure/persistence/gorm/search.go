@@ -0,0 +1,262 @@
+package gorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/sqlsafe"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SearchDocument is the text a recipe is found by. On PostgreSQL it is
+// written as a tsvector weighing the title above the description above
+// ingredient names and tags; other databases store the lower-cased text
+// for substring matching. It is never read back.
+type SearchDocument struct {
+	Title       string
+	Description string
+	Keywords    string // ingredient names and tags
+}
+
+// recipeSearchDocument is the search document of r
+func recipeSearchDocument(r *recipe.Recipe) SearchDocument {
+	keywords := make([]string, 0, len(r.Ingredients())+len(r.Tags()))
+	for _, ing := range r.Ingredients() {
+		keywords = append(keywords, ing.Name)
+	}
+	keywords = append(keywords, r.Tags()...)
+	return SearchDocument{
+		Title:       r.Title(),
+		Description: r.Description(),
+		Keywords:    strings.Join(keywords, " "),
+	}
+}
+
+// GormValue writes the document in the form the database searches
+func (d SearchDocument) GormValue(ctx context.Context, db *gorm.DB) clause.Expr {
+	if db.Dialector.Name() == "postgres" {
+		return clause.Expr{
+			SQL: "setweight(to_tsvector('english', ?), 'A') || setweight(to_tsvector('english', ?), 'B') || " +
+				"setweight(to_tsvector('english', ?), 'C')",
+			Vars: []interface{}{d.Title, d.Description, d.Keywords},
+		}
+	}
+	return clause.Expr{SQL: "?", Vars: []interface{}{d.text()}}
+}
+
+// Value is the lower-cased text, for writes that bypass GormValue
+func (d SearchDocument) Value() (driver.Value, error) {
+	return d.text(), nil
+}
+
+// Scan ignores the stored document
+func (d *SearchDocument) Scan(value interface{}) error {
+	return nil
+}
+
+func (d SearchDocument) text() string {
+	return strings.ToLower(strings.Join([]string{d.Title, d.Description, d.Keywords}, " "))
+}
+
+// searchTerm is one part of a search: a word, a "quoted phrase" or a
+// prefix* that matches any word starting with it
+type searchTerm struct {
+	text   string
+	phrase bool
+	prefix bool
+}
+
+// parseSearchTerms splits search text into lower-cased terms, all of
+// which a recipe must match. An unclosed quote runs to the end.
+func parseSearchTerms(text string) []searchTerm {
+	var terms []searchTerm
+	for i, part := range strings.Split(strings.ToLower(text), `"`) {
+		if i%2 == 1 {
+			words := strings.Fields(part)
+			switch len(words) {
+			case 0:
+			case 1:
+				terms = append(terms, searchTerm{text: words[0]})
+			default:
+				terms = append(terms, searchTerm{text: strings.Join(words, " "), phrase: true})
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			if prefix := strings.TrimRight(word, "*"); prefix != word {
+				if prefix != "" {
+					terms = append(terms, searchTerm{text: prefix, prefix: true})
+				}
+				continue
+			}
+			terms = append(terms, searchTerm{text: word})
+		}
+	}
+	return terms
+}
+
+// tsQuery is the PostgreSQL text search query matching every term. Prefix
+// terms are reduced to letters and digits because to_tsquery parses its
+// input as query syntax.
+func tsQuery(terms []searchTerm) (string, []interface{}) {
+	var parts []string
+	var vars []interface{}
+	for _, term := range terms {
+		switch {
+		case term.phrase:
+			parts = append(parts, "phraseto_tsquery('english', ?)")
+			vars = append(vars, term.text)
+		case term.prefix:
+			lexeme := strings.Map(func(r rune) rune {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) {
+					return r
+				}
+				return -1
+			}, term.text)
+			if lexeme == "" {
+				continue
+			}
+			parts = append(parts, "to_tsquery('english', ?)")
+			vars = append(vars, lexeme+":*")
+		default:
+			parts = append(parts, "plainto_tsquery('english', ?)")
+			vars = append(vars, term.text)
+		}
+	}
+	return strings.Join(parts, " && "), vars
+}
+
+// fullText reports whether searches use the PostgreSQL search vector
+// rather than substring matching
+func (r *RecipeRepository) fullText() bool {
+	return r.db.Dialector.Name() == "postgres"
+}
+
+// matchSearchTerms keeps the recipes matching every term of the search
+func (r *RecipeRepository) matchSearchTerms(query *gorm.DB, terms []searchTerm) *gorm.DB {
+	if r.fullText() {
+		if match, vars := tsQuery(terms); match != "" {
+			query = query.Where("recipes.search_vector @@ ("+match+")", vars...)
+		}
+		return query
+	}
+	for _, term := range terms {
+		pattern := sqlsafe.Contains(term.text)
+		query = query.Where(sqlsafe.Like("recipes.title")+" OR "+sqlsafe.Like("recipes.description")+" OR "+sqlsafe.Like("recipes.search_vector"),
+			pattern, pattern, pattern)
+	}
+	return query
+}
+
+// jsonStringPattern is the Like pattern matching s as a whole string in a
+// JSON array, such as a tag in the tags column
+func jsonStringPattern(s string) string {
+	encoded, _ := json.Marshal(strings.ToLower(strings.TrimSpace(s)))
+	return "%" + sqlsafe.EscapeLike(string(encoded)) + "%"
+}
+
+// FindResultFacets counts the results of a search per filter value. Each
+// facet is counted with the other filters of the search but not its own.
+func (r *RecipeRepository) FindResultFacets(ctx context.Context, criteria outbound.SearchCriteria, timeLimits []int) (*outbound.ResultFacets, error) {
+	facets := &outbound.ResultFacets{}
+
+	without := criteria
+	without.Cuisines = nil
+	cuisines, err := r.countResultsBy(r.searchFilter(ctx, without), "cuisine")
+	if err != nil {
+		return nil, err
+	}
+	facets.Cuisines = cuisines
+
+	without = criteria
+	without.Difficulty = nil
+	difficulties, err := r.countResultsBy(r.searchFilter(ctx, without), "difficulty")
+	if err != nil {
+		return nil, err
+	}
+	facets.Difficulties = difficulties
+
+	without = criteria
+	without.AIGenerated = nil
+	var aiRows []struct {
+		Value bool
+		Count int
+	}
+	result := r.searchFilter(ctx, without).
+		Select("ai_generated AS value, COUNT(*) AS count").
+		Group("ai_generated").
+		Scan(&aiRows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	for _, row := range aiRows {
+		facets.AIGenerated = append(facets.AIGenerated, outbound.FacetCount{Value: strconv.FormatBool(row.Value), Count: row.Count})
+	}
+	sort.Slice(facets.AIGenerated, func(i, j int) bool { return facets.AIGenerated[i].Value > facets.AIGenerated[j].Value })
+
+	if len(timeLimits) > 0 {
+		without = criteria
+		without.MaxTime = nil
+		columns := make([]string, len(timeLimits))
+		vars := make([]interface{}, len(timeLimits))
+		for i, limit := range timeLimits {
+			columns[i] = fmt.Sprintf("COALESCE(SUM(CASE WHEN total_time_minutes <= ? THEN 1 ELSE 0 END), 0) AS within_%d", i)
+			vars[i] = limit
+		}
+		within := make([]int64, len(timeLimits))
+		dest := make([]interface{}, len(within))
+		for i := range within {
+			dest[i] = &within[i]
+		}
+		row := r.searchFilter(ctx, without).Select(strings.Join(columns, ", "), vars...).Row()
+		if err := row.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, limit := range timeLimits {
+			facets.MaxTimes = append(facets.MaxTimes, outbound.FacetCount{Value: strconv.Itoa(limit), Count: int(within[i])})
+		}
+	}
+
+	without = criteria
+	without.Dietary = nil
+	var tagLists []StringSlice
+	if result := r.searchFilter(ctx, without).Pluck("tags", &tagLists); result.Error != nil {
+		return nil, result.Error
+	}
+	facets.Tags = countTags(tagLists)
+
+	return facets, nil
+}
+
+// countResultsBy counts the filtered recipes per value of column, most
+// common first. column must be a constant, never input.
+func (r *RecipeRepository) countResultsBy(query *gorm.DB, column string) ([]outbound.FacetCount, error) {
+	var rows []struct {
+		Value string
+		Count int
+	}
+	result := query.
+		Select(column + " AS value, COUNT(*) AS count").
+		Where(column + " <> ''").
+		Group(column).
+		Order("count DESC, value").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	counts := make([]outbound.FacetCount, len(rows))
+	for i, row := range rows {
+		counts[i] = outbound.FacetCount{Value: row.Value, Count: row.Count}
+	}
+	return counts, nil
+}
@@ -0,0 +1,68 @@
+// Package gorm provides GORM-based repository implementations
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SearchAnalyticsRepository implements the search analytics interface using GORM
+type SearchAnalyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewSearchAnalyticsRepository creates a new search analytics repository
+func NewSearchAnalyticsRepository(db *gorm.DB) outbound.SearchAnalyticsRepository {
+	return &SearchAnalyticsRepository{db: db}
+}
+
+// RecordZeroResult stores a search that matched no recipes
+func (r *SearchAnalyticsRepository) RecordZeroResult(ctx context.Context, search outbound.ZeroResultSearch) error {
+	model := ZeroResultSearchModel{
+		ID:              uuid.New(),
+		Query:           search.Query,
+		NormalizedQuery: search.NormalizedQuery,
+		UserID:          search.UserID,
+		Suggestions:     StringSlice(search.Suggestions),
+		CreatedAt:       search.SearchedAt,
+	}
+
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// TopZeroResultQueries returns the most frequent zero-result queries since a point in time
+func (r *SearchAnalyticsRepository) TopZeroResultQueries(ctx context.Context, since time.Time, limit int) ([]outbound.ZeroResultQueryStat, error) {
+	var rows []struct {
+		Query    string
+		Count    int
+		LastSeen time.Time
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&ZeroResultSearchModel{}).
+		Select("normalized_query AS query, COUNT(*) AS count, MAX(created_at) AS last_seen").
+		Where("created_at >= ?", since).
+		Group("normalized_query").
+		Order("count DESC, last_seen DESC").
+		Limit(limit).
+		Scan(&rows)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	stats := make([]outbound.ZeroResultQueryStat, len(rows))
+	for i, row := range rows {
+		stats[i] = outbound.ZeroResultQueryStat{
+			Query:    row.Query,
+			Count:    row.Count,
+			LastSeen: row.LastSeen,
+		}
+	}
+
+	return stats, nil
+}
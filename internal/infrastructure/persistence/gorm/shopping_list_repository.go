@@ -0,0 +1,280 @@
+// Package gorm provides GORM-based repository implementations
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alchemorsel/v3/internal/domain/shoppinglist"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ShoppingListRepository implements shopping list persistence using GORM
+type ShoppingListRepository struct {
+	db *gorm.DB
+}
+
+// NewShoppingListRepository creates a new shopping list repository
+func NewShoppingListRepository(db *gorm.DB) outbound.ShoppingListRepository {
+	return &ShoppingListRepository{db: db}
+}
+
+// Create stores a new, empty list
+func (r *ShoppingListRepository) Create(ctx context.Context, list *shoppinglist.List) error {
+	model := ShoppingListModel{
+		ID:        list.ID(),
+		OwnerID:   list.OwnerID(),
+		Name:      list.Name(),
+		Version:   list.Version(),
+		CreatedAt: list.CreatedAt(),
+		UpdatedAt: list.UpdatedAt(),
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// FindByID finds a list with its members and items, returning nil when it
+// does not exist
+func (r *ShoppingListRepository) FindByID(ctx context.Context, id uuid.UUID) (*shoppinglist.List, error) {
+	var model ShoppingListModel
+
+	result := r.db.WithContext(ctx).First(&model, "id = ?", id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	lists, err := r.load(ctx, []ShoppingListModel{model})
+	if err != nil {
+		return nil, err
+	}
+	return lists[0], nil
+}
+
+// FindByUser returns the lists the user owns or shares, most recently
+// changed first
+func (r *ShoppingListRepository) FindByUser(ctx context.Context, userID uuid.UUID) ([]*shoppinglist.List, error) {
+	var models []ShoppingListModel
+
+	shared := r.db.Model(&ShoppingListMemberModel{}).Select("list_id").Where("user_id = ?", userID)
+	result := r.db.WithContext(ctx).
+		Where("owner_id = ? OR id IN (?)", userID, shared).
+		Order("updated_at DESC").
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return r.load(ctx, models)
+}
+
+// AddMember shares a list with a user
+func (r *ShoppingListRepository) AddMember(ctx context.Context, listID, userID uuid.UUID) error {
+	model := ShoppingListMemberModel{ListID: listID, UserID: userID}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model).Error
+}
+
+// SaveChanges stores the applied changes, the items they touched and the
+// new list version in one transaction. The version check makes concurrent
+// writers of the same list retry instead of overwriting each other.
+func (r *ShoppingListRepository) SaveChanges(ctx context.Context, list *shoppinglist.List, expectedVersion int64, changes []shoppinglist.Change) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&ShoppingListModel{}).
+			Where("id = ? AND version = ?", list.ID(), expectedVersion).
+			Updates(map[string]interface{}{
+				"version":    list.Version(),
+				"updated_at": list.UpdatedAt(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return shoppinglist.ErrStaleVersion
+		}
+
+		touched := make(map[uuid.UUID]bool, len(changes))
+		items := make([]ShoppingListItemModel, 0, len(changes))
+		logged := make([]ShoppingListChangeModel, len(changes))
+		for i, change := range changes {
+			logged[i] = changeToModel(list.ID(), change)
+			if touched[change.Item.ID] {
+				continue
+			}
+			touched[change.Item.ID] = true
+			if item, ok := list.Item(change.Item.ID); ok {
+				items = append(items, itemToModel(list.ID(), item))
+			}
+		}
+
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&items).Error; err != nil {
+			return err
+		}
+		return tx.Create(&logged).Error
+	})
+}
+
+// FindChangesSince returns the changes after the version, oldest first
+func (r *ShoppingListRepository) FindChangesSince(ctx context.Context, listID uuid.UUID, version int64, limit int) ([]shoppinglist.Change, error) {
+	var models []ShoppingListChangeModel
+
+	result := r.db.WithContext(ctx).
+		Where("list_id = ? AND version > ?", listID, version).
+		Order("version").
+		Limit(limit).
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	changes := make([]shoppinglist.Change, len(models))
+	for i, model := range models {
+		changes[i] = modelToChange(model)
+	}
+	return changes, nil
+}
+
+// FindAppliedOps returns the logged changes for the given operation IDs
+func (r *ShoppingListRepository) FindAppliedOps(ctx context.Context, listID uuid.UUID, opIDs []string) (map[string]shoppinglist.Change, error) {
+	applied := make(map[string]shoppinglist.Change)
+	if len(opIDs) == 0 {
+		return applied, nil
+	}
+
+	var models []ShoppingListChangeModel
+	result := r.db.WithContext(ctx).
+		Where("list_id = ? AND op_id IN ?", listID, opIDs).
+		Find(&models)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, model := range models {
+		applied[model.OpID] = modelToChange(model)
+	}
+	return applied, nil
+}
+
+// load adds members and items to the list rows
+func (r *ShoppingListRepository) load(ctx context.Context, models []ShoppingListModel) ([]*shoppinglist.List, error) {
+	if len(models) == 0 {
+		return []*shoppinglist.List{}, nil
+	}
+	ids := make([]uuid.UUID, len(models))
+	for i, model := range models {
+		ids[i] = model.ID
+	}
+
+	var members []ShoppingListMemberModel
+	if err := r.db.WithContext(ctx).Where("list_id IN ?", ids).Order("created_at").Find(&members).Error; err != nil {
+		return nil, err
+	}
+	var items []ShoppingListItemModel
+	if err := r.db.WithContext(ctx).Where("list_id IN ?", ids).Order("created_at, name").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	memberIDs := make(map[uuid.UUID][]uuid.UUID)
+	for _, member := range members {
+		memberIDs[member.ListID] = append(memberIDs[member.ListID], member.UserID)
+	}
+	listItems := make(map[uuid.UUID][]*shoppinglist.Item)
+	for _, item := range items {
+		listItems[item.ListID] = append(listItems[item.ListID], modelToItem(item))
+	}
+
+	lists := make([]*shoppinglist.List, len(models))
+	for i, model := range models {
+		lists[i] = shoppinglist.Reconstruct(
+			model.ID,
+			model.OwnerID,
+			model.Name,
+			memberIDs[model.ID],
+			listItems[model.ID],
+			model.Version,
+			model.CreatedAt,
+			model.UpdatedAt,
+		)
+	}
+	return lists, nil
+}
+
+func itemToModel(listID uuid.UUID, item shoppinglist.Item) ShoppingListItemModel {
+	return ShoppingListItemModel{
+		ID:               item.ID,
+		ListID:           listID,
+		Name:             item.Name,
+		Quantity:         item.Quantity,
+		Checked:          item.Checked,
+		CheckedBy:        item.CheckedBy,
+		CheckedChangedAt: item.CheckedChangedAt,
+		EditedAt:         item.EditedAt,
+		Removed:          item.Removed,
+		AddedBy:          item.AddedBy,
+		Version:          item.Version,
+		CreatedAt:        item.CreatedAt,
+	}
+}
+
+func modelToItem(model ShoppingListItemModel) *shoppinglist.Item {
+	return &shoppinglist.Item{
+		ID:               model.ID,
+		Name:             model.Name,
+		Quantity:         model.Quantity,
+		Checked:          model.Checked,
+		CheckedBy:        model.CheckedBy,
+		CheckedChangedAt: model.CheckedChangedAt,
+		EditedAt:         model.EditedAt,
+		Removed:          model.Removed,
+		AddedBy:          model.AddedBy,
+		Version:          model.Version,
+		CreatedAt:        model.CreatedAt,
+	}
+}
+
+func changeToModel(listID uuid.UUID, change shoppinglist.Change) ShoppingListChangeModel {
+	return ShoppingListChangeModel{
+		ListID:    listID,
+		Version:   change.Version,
+		OpID:      change.OpID,
+		Type:      string(change.Type),
+		UserID:    change.UserID,
+		ItemID:    change.Item.ID,
+		Name:      change.Item.Name,
+		Quantity:  change.Item.Quantity,
+		Checked:   change.Item.Checked,
+		CheckedBy: change.Item.CheckedBy,
+		Removed:   change.Item.Removed,
+		CreatedAt: change.CreatedAt,
+	}
+}
+
+// modelToChange rebuilds a logged change. The item carries what clients
+// render; the ordering timestamps are only kept on the item row.
+func modelToChange(model ShoppingListChangeModel) shoppinglist.Change {
+	return shoppinglist.Change{
+		OpID:    model.OpID,
+		Type:    shoppinglist.OpType(model.Type),
+		UserID:  model.UserID,
+		Outcome: shoppinglist.OutcomeApplied,
+		Item: shoppinglist.Item{
+			ID:        model.ItemID,
+			Name:      model.Name,
+			Quantity:  model.Quantity,
+			Checked:   model.Checked,
+			CheckedBy: model.CheckedBy,
+			Removed:   model.Removed,
+			Version:   model.Version,
+		},
+		Version:   model.Version,
+		CreatedAt: model.CreatedAt,
+	}
+}
@@ -0,0 +1,265 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/offline"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncRepository stores device sync records using GORM
+type SyncRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncRepository creates a new sync repository
+func NewSyncRepository(db *gorm.DB) outbound.SyncRepository {
+	return &SyncRepository{db: db}
+}
+
+// syncField is how a field is stored in SyncRecordModel.Fields
+type syncField struct {
+	Value  json.RawMessage `json:"value"`
+	At     time.Time       `json:"at"`
+	Device string          `json:"device"`
+}
+
+// FindRecord returns nil when the user has no such record
+func (r *SyncRepository) FindRecord(ctx context.Context, userID uuid.UUID, kind offline.Kind, key string) (*offline.Record, error) {
+	var model SyncRecordModel
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND kind = ? AND key = ?", userID, string(kind), key).
+		First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return modelToSyncRecord(&model)
+}
+
+// SaveRecord takes the user's next sequence number and stores the record
+// under it in one transaction. The sequence update locks the user's
+// counter row, so saves for one user take their numbers in commit order.
+func (r *SyncRepository) SaveRecord(ctx context.Context, record *offline.Record, expectedSeq int64) error {
+	model, err := syncRecordToModel(record)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		seq, err := nextSyncSeq(tx, record.UserID)
+		if err != nil {
+			return err
+		}
+		model.Seq = seq
+
+		var result *gorm.DB
+		if expectedSeq == 0 {
+			result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+		} else {
+			result = tx.Model(&SyncRecordModel{}).
+				Where("user_id = ? AND kind = ? AND key = ? AND seq = ?", model.UserID, model.Kind, model.Key, expectedSeq).
+				Updates(map[string]interface{}{
+					"fields":     model.Fields,
+					"cleared_at": model.ClearedAt,
+					"cleared_by": model.ClearedBy,
+					"deleted":    model.Deleted,
+					"seq":        model.Seq,
+					"updated_at": model.UpdatedAt,
+				})
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return offline.ErrStaleRecord
+		}
+		record.Seq = seq
+		return nil
+	})
+}
+
+// nextSyncSeq bumps the user's counter, creating it on the first save. Two
+// first saves racing to create it leave one to retry as stale.
+func nextSyncSeq(tx *gorm.DB, userID uuid.UUID) (int64, error) {
+	result := tx.Model(&SyncCounterModel{}).
+		Where("user_id = ?", userID).
+		Update("seq", gorm.Expr("seq + 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		created := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&SyncCounterModel{UserID: userID, Seq: 1})
+		if created.Error != nil {
+			return 0, created.Error
+		}
+		if created.RowsAffected == 0 {
+			return 0, offline.ErrStaleRecord
+		}
+		return 1, nil
+	}
+
+	var counter SyncCounterModel
+	if err := tx.First(&counter, "user_id = ?", userID).Error; err != nil {
+		return 0, err
+	}
+	return counter.Seq, nil
+}
+
+// FindSince returns the records saved after the cursor, oldest first
+func (r *SyncRepository) FindSince(ctx context.Context, userID uuid.UUID, cursor int64, limit int) ([]*offline.Record, error) {
+	var models []SyncRecordModel
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND seq > ?", userID, cursor).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*offline.Record, len(models))
+	for i := range models {
+		record, err := modelToSyncRecord(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// Horizon reads the user's counter; users who never synced are at zero
+func (r *SyncRepository) Horizon(ctx context.Context, userID uuid.UUID) (int64, int64, error) {
+	var counter SyncCounterModel
+	err := r.db.WithContext(ctx).First(&counter, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return counter.Seq, counter.PurgedSeq, nil
+}
+
+// SaveDeviceCursor records how far the device has pulled
+func (r *SyncRepository) SaveDeviceCursor(ctx context.Context, userID uuid.UUID, deviceID string, cursor int64, seenAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "device_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"pulled_seq", "seen_at"}),
+		}).
+		Create(&SyncDeviceModel{UserID: userID, DeviceID: deviceID, PulledSeq: cursor, SeenAt: seenAt}).Error
+}
+
+// PurgeTombstones deletes, user by user, the tombstones cleared before the
+// time that every device seen since then has pulled, and raises the user's
+// purged sequence so devices behind it know to start again
+func (r *SyncRepository) PurgeTombstones(ctx context.Context, before time.Time) (int, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&SyncRecordModel{}).
+		Where("deleted = ? AND cleared_at < ?", true, before).
+		Distinct().
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, userID := range userIDs {
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var pulled sql.NullInt64
+			err := tx.Model(&SyncDeviceModel{}).
+				Select("MIN(pulled_seq)").
+				Where("user_id = ? AND seen_at >= ?", userID, before).
+				Scan(&pulled).Error
+			if err != nil {
+				return err
+			}
+			expired := func(db *gorm.DB) *gorm.DB {
+				db = db.Where("user_id = ? AND deleted = ? AND cleared_at < ?", userID, true, before)
+				if pulled.Valid {
+					db = db.Where("seq <= ?", pulled.Int64)
+				}
+				return db
+			}
+
+			var highest sql.NullInt64
+			if err := tx.Model(&SyncRecordModel{}).Scopes(expired).Select("MAX(seq)").Scan(&highest).Error; err != nil {
+				return err
+			}
+			if !highest.Valid {
+				return nil
+			}
+
+			result := tx.Scopes(expired).Delete(&SyncRecordModel{})
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += int(result.RowsAffected)
+			return tx.Model(&SyncCounterModel{}).
+				Where("user_id = ? AND purged_seq < ?", userID, highest.Int64).
+				Update("purged_seq", highest.Int64).Error
+		})
+		if err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+func syncRecordToModel(record *offline.Record) (*SyncRecordModel, error) {
+	fields := make(map[string]syncField, len(record.Fields))
+	for name, field := range record.Fields {
+		fields[name] = syncField{Value: field.Value, At: field.At, Device: field.Device}
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &SyncRecordModel{
+		UserID:    record.UserID,
+		Kind:      string(record.Kind),
+		Key:       record.Key,
+		Fields:    string(raw),
+		Deleted:   record.Deleted(),
+		UpdatedAt: record.UpdatedAt,
+	}
+	if record.Cleared != nil {
+		at := record.Cleared.At
+		model.ClearedAt = &at
+		model.ClearedBy = record.Cleared.Device
+	}
+	return model, nil
+}
+
+func modelToSyncRecord(model *SyncRecordModel) (*offline.Record, error) {
+	var fields map[string]syncField
+	if err := json.Unmarshal([]byte(model.Fields), &fields); err != nil {
+		return nil, err
+	}
+
+	record := offline.NewRecord(model.UserID, offline.Kind(model.Kind), model.Key)
+	for name, field := range fields {
+		record.Fields[name] = offline.Field{
+			Value: field.Value,
+			Stamp: offline.Stamp{At: field.At, Device: field.Device},
+		}
+	}
+	if model.ClearedAt != nil {
+		record.Cleared = &offline.Stamp{At: *model.ClearedAt, Device: model.ClearedBy}
+	}
+	record.Seq = model.Seq
+	record.UpdatedAt = model.UpdatedAt
+	return record, nil
+}
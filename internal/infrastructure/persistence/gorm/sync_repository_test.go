@@ -0,0 +1,74 @@
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/offline"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncRepositorySequencesSavesAndPurgesPulledTombstones(t *testing.T) {
+	db, _ := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&SyncRecordModel{}, &SyncCounterModel{}, &SyncDeviceModel{}))
+	repo := NewSyncRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	save := func(key string, change offline.Change) *offline.Record {
+		record, err := repo.FindRecord(ctx, userID, offline.KindNote, key)
+		require.NoError(t, err)
+		if record == nil {
+			record = offline.NewRecord(userID, offline.KindNote, key)
+		}
+		expected := record.Seq
+		record.Merge("phone", change, now.Add(time.Minute))
+		require.NoError(t, repo.SaveRecord(ctx, record, expected))
+		return record
+	}
+	text := map[string]json.RawMessage{"text": json.RawMessage(`"Brine overnight"`)}
+
+	first := save("a", offline.Change{Fields: text, At: now})
+	save("b", offline.Change{Fields: text, At: now})
+	assert.Equal(t, int64(1), first.Seq)
+
+	stale := offline.NewRecord(userID, offline.KindNote, "a")
+	stale.Merge("tablet", offline.Change{Fields: text, At: now}, now)
+	assert.ErrorIs(t, repo.SaveRecord(ctx, stale, 0), offline.ErrStaleRecord)
+
+	deleted := save("a", offline.Change{Delete: true, At: now.Add(time.Minute)})
+	assert.Equal(t, int64(3), deleted.Seq)
+
+	found, err := repo.FindRecord(ctx, userID, offline.KindNote, "a")
+	require.NoError(t, err)
+	assert.True(t, found.Deleted())
+	assert.True(t, found.Cleared.At.Equal(now.Add(time.Minute)))
+
+	records, err := repo.FindSince(ctx, userID, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "b", records[0].Key)
+	assert.JSONEq(t, `"Brine overnight"`, string(records[0].Fields["text"].Value))
+
+	// A device still behind the delete holds it back
+	purgeAt := now.Add(time.Hour)
+	require.NoError(t, repo.SaveDeviceCursor(ctx, userID, "tablet", 2, purgeAt))
+	purged, err := repo.PurgeTombstones(ctx, purgeAt)
+	require.NoError(t, err)
+	assert.Equal(t, 0, purged)
+
+	require.NoError(t, repo.SaveDeviceCursor(ctx, userID, "tablet", 3, purgeAt))
+	purged, err = repo.PurgeTombstones(ctx, purgeAt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	latest, horizon, err := repo.Horizon(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), latest)
+	assert.Equal(t, int64(3), horizon)
+}
@@ -0,0 +1,189 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alchemorsel/v3/internal/domain/technique"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TechniqueRepository implements the technique library using GORM
+type TechniqueRepository struct {
+	db *gorm.DB
+}
+
+// NewTechniqueRepository creates a new technique repository
+func NewTechniqueRepository(db *gorm.DB) outbound.TechniqueRepository {
+	return &TechniqueRepository{db: db}
+}
+
+// List returns the library in name order
+func (r *TechniqueRepository) List(ctx context.Context) ([]*technique.Technique, error) {
+	var models []TechniqueModel
+	if err := r.db.WithContext(ctx).Order("name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	techniques := make([]*technique.Technique, len(models))
+	for i := range models {
+		techniques[i] = modelToTechnique(&models[i])
+	}
+	return techniques, nil
+}
+
+// FindBySlug returns nil when the technique does not exist
+func (r *TechniqueRepository) FindBySlug(ctx context.Context, slug string) (*technique.Technique, error) {
+	var model TechniqueModel
+	err := r.db.WithContext(ctx).First(&model, "slug = ?", slug).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return modelToTechnique(&model), nil
+}
+
+// Save creates the technique or replaces the one with its slug, keeping
+// the original creation time
+func (r *TechniqueRepository) Save(ctx context.Context, t *technique.Technique) error {
+	model := TechniqueModel{
+		Slug:      t.Slug,
+		Name:      t.Name,
+		Summary:   t.Summary,
+		Steps:     StringSlice(t.Steps),
+		Aliases:   StringSlice(t.Aliases),
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+	if t.Video != nil {
+		model.VideoProvider = t.Video.Provider
+		model.VideoID = t.Video.ID
+		model.VideoTitle = t.Video.Title
+		model.VideoDuration = t.Video.Duration
+		model.VideoStart = t.Video.Start
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "summary", "steps", "aliases", "video_provider", "video_id",
+			"video_title", "video_duration", "video_start", "updated_at",
+		}),
+	}).Create(&model).Error
+}
+
+// Delete removes a technique and its step links
+func (r *TechniqueRepository) Delete(ctx context.Context, slug string) (bool, error) {
+	var deleted bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("technique_slug = ?", slug).Delete(&RecipeStepTechniqueModel{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("slug = ?", slug).Delete(&TechniqueModel{})
+		deleted = result.RowsAffected > 0
+		return result.Error
+	})
+	return deleted, err
+}
+
+// RecipeSteps reads the author, status and step texts of a recipe
+func (r *TechniqueRepository) RecipeSteps(ctx context.Context, recipeID uuid.UUID) (*outbound.TechniqueRecipe, error) {
+	var models []RecipeModel
+	err := r.db.WithContext(ctx).
+		Select("id, author_id, title, status, instructions").
+		Where("id = ?", recipeID).
+		Limit(1).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+	model := models[0]
+	return &outbound.TechniqueRecipe{
+		ID:       model.ID,
+		AuthorID: model.AuthorID,
+		Title:    model.Title,
+		Status:   model.Status,
+		Steps:    jsonListField(model.Instructions, "description", "data", "instructions"),
+	}, nil
+}
+
+// StepLinks returns a recipe's links in step then position order
+func (r *TechniqueRepository) StepLinks(ctx context.Context, recipeID uuid.UUID) ([]outbound.StepTechniqueLink, error) {
+	var models []RecipeStepTechniqueModel
+	err := r.db.WithContext(ctx).
+		Where("recipe_id = ?", recipeID).
+		Order("step, position").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	links := make([]outbound.StepTechniqueLink, len(models))
+	for i, model := range models {
+		links[i] = outbound.StepTechniqueLink{
+			RecipeID: model.RecipeID,
+			Step:     model.Step,
+			Slug:     model.TechniqueSlug,
+			Source:   technique.Source(model.Source),
+			Position: model.Position,
+		}
+	}
+	return links, nil
+}
+
+// ReplaceStepLinks swaps the links of steps for links in one transaction
+func (r *TechniqueRepository) ReplaceStepLinks(ctx context.Context, recipeID uuid.UUID, steps []int, links []outbound.StepTechniqueLink) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	models := make([]RecipeStepTechniqueModel, len(links))
+	for i, link := range links {
+		models[i] = RecipeStepTechniqueModel{
+			RecipeID:      recipeID,
+			Step:          link.Step,
+			TechniqueSlug: link.Slug,
+			Source:        string(link.Source),
+			Position:      link.Position,
+		}
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("recipe_id = ? AND step IN ?", recipeID, steps).
+			Delete(&RecipeStepTechniqueModel{}).Error
+		if err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			return nil
+		}
+		return tx.Create(&models).Error
+	})
+}
+
+func modelToTechnique(model *TechniqueModel) *technique.Technique {
+	t := &technique.Technique{
+		Slug:      model.Slug,
+		Name:      model.Name,
+		Summary:   model.Summary,
+		Steps:     []string(model.Steps),
+		Aliases:   []string(model.Aliases),
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+	if model.VideoProvider != "" {
+		t.Video = &technique.Video{
+			Provider: model.VideoProvider,
+			ID:       model.VideoID,
+			Title:    model.VideoTitle,
+			Duration: model.VideoDuration,
+			Start:    model.VideoStart,
+		}
+	}
+	return t
+}
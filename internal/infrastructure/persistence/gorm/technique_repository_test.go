@@ -0,0 +1,61 @@
+package gorm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/technique"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTechniqueRepositoryLinksSteps(t *testing.T) {
+	db, lemonBars := newCounterFixture(t)
+	require.NoError(t, db.AutoMigrate(&TechniqueModel{}, &RecipeStepTechniqueModel{}))
+	require.NoError(t, db.Model(&RecipeModel{}).Where("id = ?", lemonBars).Update("instructions", JSONField{
+		"data": []interface{}{
+			map[string]interface{}{"step_number": 1, "description": "Whisk the eggs."},
+			map[string]interface{}{"step_number": 2, "description": "Bake until set."},
+		},
+	}).Error)
+
+	repo := NewTechniqueRepository(db)
+	ctx := context.Background()
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	whisk := &technique.Technique{Slug: "whisking", Name: "Whisking", Summary: "Beat.", CreatedAt: created, UpdatedAt: created}
+	require.NoError(t, repo.Save(ctx, whisk))
+
+	// Saving again replaces the technique in place
+	whisk.Summary = "Beat until smooth."
+	whisk.Video = &technique.Video{Provider: technique.ProviderVimeo, ID: "76979871", Start: 5}
+	whisk.UpdatedAt = created.Add(time.Hour)
+	require.NoError(t, repo.Save(ctx, whisk))
+	found, err := repo.FindBySlug(ctx, "whisking")
+	require.NoError(t, err)
+	assert.Equal(t, "Beat until smooth.", found.Summary)
+	require.NotNil(t, found.Video)
+	assert.Equal(t, 5, found.Video.Start)
+
+	recipe, err := repo.RecipeSteps(ctx, lemonBars)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Whisk the eggs.", "Bake until set."}, recipe.Steps)
+	assert.Equal(t, "published", recipe.Status)
+
+	link := outbound.StepTechniqueLink{Step: 1, Slug: "whisking", Source: technique.SourceSuggested}
+	require.NoError(t, repo.ReplaceStepLinks(ctx, lemonBars, []int{1, 2}, []outbound.StepTechniqueLink{link}))
+	link.Source = technique.SourceAuthor
+	require.NoError(t, repo.ReplaceStepLinks(ctx, lemonBars, []int{1}, []outbound.StepTechniqueLink{link}))
+	links, err := repo.StepLinks(ctx, lemonBars)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, technique.SourceAuthor, links[0].Source)
+
+	deleted, err := repo.Delete(ctx, "whisking")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	links, err = repo.StepLinks(ctx, lemonBars)
+	require.NoError(t, err)
+	assert.Empty(t, links, "deleting a technique unlinks it")
+}
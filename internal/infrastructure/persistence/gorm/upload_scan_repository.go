@@ -0,0 +1,74 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"gorm.io/gorm"
+)
+
+// UploadScanRepository implements upload scan records using GORM
+type UploadScanRepository struct {
+	db *gorm.DB
+}
+
+// NewUploadScanRepository creates a new upload scan repository
+func NewUploadScanRepository(db *gorm.DB) outbound.UploadScanRepository {
+	return &UploadScanRepository{db: db}
+}
+
+// Save records a scan
+func (r *UploadScanRepository) Save(ctx context.Context, scan *outbound.UploadScan) error {
+	model := UploadScanModel{
+		ID:            scan.ID,
+		UserID:        scan.UserID,
+		Source:        scan.Source,
+		FileName:      scan.FileName,
+		ContentType:   scan.ContentType,
+		SizeBytes:     scan.SizeBytes,
+		SHA256:        scan.SHA256,
+		Scanner:       scan.Scanner,
+		Verdict:       scan.Verdict,
+		Signature:     scan.Signature,
+		QuarantineKey: scan.QuarantineKey,
+		Error:         scan.Error,
+		DurationMS:    scan.Duration.Milliseconds(),
+		ScannedAt:     scan.ScannedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// FindRecent returns the latest scans, newest first
+func (r *UploadScanRepository) FindRecent(ctx context.Context, verdict string, limit int) ([]*outbound.UploadScan, error) {
+	var models []UploadScanModel
+
+	query := r.db.WithContext(ctx).Order("scanned_at DESC").Limit(limit)
+	if verdict != "" {
+		query = query.Where("verdict = ?", verdict)
+	}
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	scans := make([]*outbound.UploadScan, len(models))
+	for i, model := range models {
+		scans[i] = &outbound.UploadScan{
+			ID:            model.ID,
+			UserID:        model.UserID,
+			Source:        model.Source,
+			FileName:      model.FileName,
+			ContentType:   model.ContentType,
+			SizeBytes:     model.SizeBytes,
+			SHA256:        model.SHA256,
+			Scanner:       model.Scanner,
+			Verdict:       model.Verdict,
+			Signature:     model.Signature,
+			QuarantineKey: model.QuarantineKey,
+			Error:         model.Error,
+			Duration:      time.Duration(model.DurationMS) * time.Millisecond,
+			ScannedAt:     model.ScannedAt,
+		}
+	}
+	return scans, nil
+}
@@ -83,6 +83,27 @@ func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User
 	return ModelToUser(&model)
 }
 
+// FindByIDs finds users by multiple IDs in a single query
+func (r *UserRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*user.User, error) {
+	var models []UserModel
+	
+	result := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	
+	users := make([]*user.User, 0, len(models))
+	for i := range models {
+		u, err := ModelToUser(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	
+	return users, nil
+}
+
 // FindByEmail finds a user by email
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
 	var model UserModel
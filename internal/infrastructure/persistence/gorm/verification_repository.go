@@ -0,0 +1,184 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alchemorsel/v3/internal/domain/verification"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VerificationRepository implements author verification storage using GORM
+type VerificationRepository struct {
+	db *gorm.DB
+}
+
+// NewVerificationRepository creates a new verification repository
+func NewVerificationRepository(db *gorm.DB) outbound.VerificationRepository {
+	return &VerificationRepository{db: db}
+}
+
+// SaveClaim inserts or updates a claim
+func (r *VerificationRepository) SaveClaim(ctx context.Context, claim *verification.Claim) error {
+	return r.db.WithContext(ctx).Save(claimToModel(claim)).Error
+}
+
+// FindClaim returns nil when the claim does not exist
+func (r *VerificationRepository) FindClaim(ctx context.Context, id uuid.UUID) (*verification.Claim, error) {
+	return r.firstClaim(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// FindLatestClaim returns the author's most recent claim
+func (r *VerificationRepository) FindLatestClaim(ctx context.Context, userID uuid.UUID) (*verification.Claim, error) {
+	return r.firstClaim(r.db.WithContext(ctx).Where("user_id = ?", userID).Order("submitted_at DESC"))
+}
+
+// FindClaims returns claims oldest first
+func (r *VerificationRepository) FindClaims(ctx context.Context, status verification.Status, limit int) ([]*verification.Claim, error) {
+	query := r.db.WithContext(ctx).Order("submitted_at ASC").Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	return r.findClaims(query)
+}
+
+// FindVerified returns the approved claims of the given authors
+func (r *VerificationRepository) FindVerified(ctx context.Context, userIDs []uuid.UUID) ([]*verification.Claim, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	return r.findClaims(r.db.WithContext(ctx).
+		Where("user_id IN ? AND status = ?", userIDs, string(verification.StatusApproved)))
+}
+
+// SaveReport inserts or updates a report
+func (r *VerificationRepository) SaveReport(ctx context.Context, report *verification.Report) error {
+	model := VerificationReportModel{
+		ID:         report.ID,
+		ClaimID:    report.ClaimID,
+		SubjectID:  report.SubjectID,
+		ReporterID: report.ReporterID,
+		Reason:     string(report.Reason),
+		Details:    report.Details,
+		Status:     string(report.Status),
+		ResolverID: report.ResolverID,
+		Resolution: report.Resolution,
+		CreatedAt:  report.CreatedAt,
+		ResolvedAt: report.ResolvedAt,
+	}
+	return r.db.WithContext(ctx).Save(&model).Error
+}
+
+// FindReport returns nil when the report does not exist
+func (r *VerificationRepository) FindReport(ctx context.Context, id uuid.UUID) (*verification.Report, error) {
+	var model VerificationReportModel
+	err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return modelToReport(&model), nil
+}
+
+// FindReports returns reports oldest first
+func (r *VerificationRepository) FindReports(ctx context.Context, filter outbound.VerificationReportFilter) ([]*verification.Report, error) {
+	query := r.db.WithContext(ctx).Order("created_at ASC")
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.ClaimID != uuid.Nil {
+		query = query.Where("claim_id = ?", filter.ClaimID)
+	}
+	if filter.ReporterID != uuid.Nil {
+		query = query.Where("reporter_id = ?", filter.ReporterID)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var models []VerificationReportModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	reports := make([]*verification.Report, len(models))
+	for i := range models {
+		reports[i] = modelToReport(&models[i])
+	}
+	return reports, nil
+}
+
+func (r *VerificationRepository) firstClaim(query *gorm.DB) (*verification.Claim, error) {
+	var model VerificationClaimModel
+	err := query.First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return modelToClaim(&model), nil
+}
+
+func (r *VerificationRepository) findClaims(query *gorm.DB) ([]*verification.Claim, error) {
+	var models []VerificationClaimModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	claims := make([]*verification.Claim, len(models))
+	for i := range models {
+		claims[i] = modelToClaim(&models[i])
+	}
+	return claims, nil
+}
+
+func claimToModel(claim *verification.Claim) *VerificationClaimModel {
+	return &VerificationClaimModel{
+		ID:          claim.ID,
+		UserID:      claim.UserID,
+		Kind:        string(claim.Kind),
+		DisplayName: claim.DisplayName,
+		Statement:   claim.Statement,
+		Evidence:    StringSlice(claim.Evidence),
+		Status:      string(claim.Status),
+		ReviewerID:  claim.ReviewerID,
+		ReviewNote:  claim.ReviewNote,
+		SubmittedAt: claim.SubmittedAt,
+		ReviewedAt:  claim.ReviewedAt,
+	}
+}
+
+func modelToClaim(model *VerificationClaimModel) *verification.Claim {
+	return &verification.Claim{
+		ID:          model.ID,
+		UserID:      model.UserID,
+		Kind:        verification.Kind(model.Kind),
+		DisplayName: model.DisplayName,
+		Statement:   model.Statement,
+		Evidence:    []string(model.Evidence),
+		Status:      verification.Status(model.Status),
+		ReviewerID:  model.ReviewerID,
+		ReviewNote:  model.ReviewNote,
+		SubmittedAt: model.SubmittedAt,
+		ReviewedAt:  model.ReviewedAt,
+	}
+}
+
+func modelToReport(model *VerificationReportModel) *verification.Report {
+	return &verification.Report{
+		ID:         model.ID,
+		ClaimID:    model.ClaimID,
+		SubjectID:  model.SubjectID,
+		ReporterID: model.ReporterID,
+		Reason:     verification.Reason(model.Reason),
+		Details:    model.Details,
+		Status:     verification.ReportStatus(model.Status),
+		ResolverID: model.ResolverID,
+		Resolution: model.Resolution,
+		CreatedAt:  model.CreatedAt,
+		ResolvedAt: model.ResolvedAt,
+	}
+}
@@ -1,11 +1,25 @@
 // Package migrations provides database migration functionality
-// using golang-migrate for schema versioning
+// using golang-migrate for schema versioning.
+//
+// The schema lives in sql/ as numbered pairs, 000027_name.up.sql and
+// 000027_name.down.sql, embedded into the binary. Postgres deployments
+// apply them with `api-pure migrate up` or, when database.auto_migrate is
+// set, at startup; a server refuses to boot while any are unapplied.
 package migrations
 
 import (
+	"context"
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
@@ -17,6 +31,14 @@ import (
 //go:embed sql/*.sql
 var sqlFiles embed.FS
 
+var (
+	// ErrPending means the binary carries migrations the database lacks
+	ErrPending = errors.New("database has unapplied migrations")
+	// ErrDirty means a migration failed part way. Fix the schema by hand,
+	// then force the version it is now at.
+	ErrDirty = errors.New("database is dirty after a failed migration")
+)
+
 // Migrator handles database migrations
 type Migrator struct {
 	db      *sql.DB
@@ -24,7 +46,8 @@ type Migrator struct {
 	logger  *zap.Logger
 }
 
-// New creates a new migrator instance
+// New creates a new migrator instance. It holds one connection from db,
+// which Close releases; db itself stays open.
 func New(db *sql.DB, logger *zap.Logger) (*Migrator, error) {
 	// Create source from embedded files
 	source, err := iofs.New(sqlFiles, "sql")
@@ -33,11 +56,17 @@ func New(db *sql.DB, logger *zap.Logger) (*Migrator, error) {
 	}
 	
 	// Create database driver
-	driver, err := postgres.WithInstance(db, &postgres.Config{
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migration connection: %w", err)
+	}
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{
 		MigrationsTable: "schema_migrations",
 		DatabaseName:    "alchemorsel",
 	})
 	if err != nil {
+		conn.Close()
 		return nil, fmt.Errorf("failed to create migration driver: %w", err)
 	}
 	
@@ -121,6 +150,27 @@ func (m *Migrator) Version() (uint, bool, error) {
 	return version, dirty, err
 }
 
+// Check returns an error wrapping ErrDirty or ErrPending unless the
+// database is at the newest embedded migration
+func (m *Migrator) Check() error {
+	version, dirty, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("failed to get version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("%w at version %d", ErrDirty, version)
+	}
+	pending, err := pendingAfter(version)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: at version %d, %d to apply up to %d",
+			ErrPending, version, len(pending), pending[len(pending)-1].Version)
+	}
+	return nil
+}
+
 // Force sets a specific migration version
 func (m *Migrator) Force(version int) error {
 	m.logger.Warn("Forcing migration version",
@@ -233,45 +283,87 @@ func (m *Migrator) Status() (*MigrationStatus, error) {
 		})
 	}
 	
-	// TODO: Determine pending migrations by comparing with embedded files
+	pending, err := pendingAfter(version)
+	if err != nil {
+		return nil, err
+	}
+	status.Pending = pending
 	
 	return status, nil
 }
 
-// CreateMigration creates a new migration file template
-func CreateMigration(name string) error {
-	timestamp := time.Now().Format("20060102150405")
-	upFile := fmt.Sprintf("%s_%s.up.sql", timestamp, name)
-	downFile := fmt.Sprintf("%s_%s.down.sql", timestamp, name)
-	
-	upContent := fmt.Sprintf(`-- Migration: %s (UP)
--- Description: Add description here
--- Author: Generated
--- Date: %s
-
-BEGIN;
+// Available lists the embedded migrations in version order
+func Available() ([]Pending, error) {
+	entries, err := fs.ReadDir(sqlFiles, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+	return parseMigrations(entries)
+}
 
--- Add your UP migration SQL here
+// pendingAfter lists the embedded migrations newer than version
+func pendingAfter(version uint) ([]Pending, error) {
+	available, err := Available()
+	if err != nil {
+		return nil, err
+	}
+	pending := []Pending{}
+	for _, p := range available {
+		if p.Version > version {
+			pending = append(pending, p)
+		}
+	}
+	return pending, nil
+}
 
-COMMIT;
-`, name, time.Now().Format(time.RFC3339))
-	
-	downContent := fmt.Sprintf(`-- Migration: %s (DOWN)
--- Description: Rollback for %s
--- Author: Generated
--- Date: %s
+// parseMigrations reads the version and name from each NNNNNN_name.up.sql
+func parseMigrations(entries []fs.DirEntry) ([]Pending, error) {
+	var migrations []Pending
+	for _, e := range entries {
+		base, ok := strings.CutSuffix(e.Name(), ".up.sql")
+		if !ok {
+			continue
+		}
+		number, name, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseUint(number, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has no version number", e.Name())
+		}
+		migrations = append(migrations, Pending{Version: uint(version), Name: name})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
 
-BEGIN;
+var migrationName = regexp.MustCompile(`^[a-z0-9_]+$`)
 
--- Add your DOWN migration SQL here
+// CreateMigration writes an empty up and down pair numbered after the
+// newest migration in dir and returns their paths
+func CreateMigration(dir, name string) ([]string, error) {
+	if !migrationName.MatchString(name) {
+		return nil, fmt.Errorf("migration name %q must be lower case letters, digits and underscores", name)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	existing, err := parseMigrations(entries)
+	if err != nil {
+		return nil, err
+	}
+	var version uint = 1
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
 
-COMMIT;
-`, name, name, time.Now().Format(time.RFC3339))
-	
-	// In a real implementation, write these to files
-	fmt.Printf("Created migration files:\n- %s\n- %s\n", upFile, downFile)
-	fmt.Printf("\nUP Migration:\n%s\n", upContent)
-	fmt.Printf("\nDOWN Migration:\n%s\n", downContent)
-	
-	return nil
-}
\ No newline at end of file
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%06d_%s.%s.sql", version, name, direction))
+		content := fmt.Sprintf("-- Migration: %s (%s)\n", name, strings.ToUpper(direction))
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write migration: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedMigrationsAreNumberedInOrder(t *testing.T) {
+	available, err := Available()
+	require.NoError(t, err)
+	require.NotEmpty(t, available)
+	for i, m := range available {
+		assert.Equal(t, uint(i+1), m.Version, "migration %s leaves a gap", m.Name)
+	}
+
+	pending, err := pendingAfter(available[len(available)-2].Version)
+	require.NoError(t, err)
+	assert.Equal(t, available[len(available)-1:], pending)
+}
+
+func TestCreateMigrationNumbersAfterTheNewest(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "000009_tags.up.sql"), nil, 0o644))
+
+	paths, err := CreateMigration(dir, "recipe_notes")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "000010_recipe_notes.up.sql"),
+		filepath.Join(dir, "000010_recipe_notes.down.sql"),
+	}, paths)
+
+	_, err = CreateMigration(dir, "Recipe Notes")
+	assert.Error(t, err)
+}
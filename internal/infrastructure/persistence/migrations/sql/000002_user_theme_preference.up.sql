@@ -0,0 +1,4 @@
+-- Theme preference (system follows prefers-color-scheme)
+ALTER TABLE users
+    ADD COLUMN pref_theme VARCHAR(10) NOT NULL DEFAULT 'system'
+    CHECK (pref_theme IN ('system', 'light', 'dark'));
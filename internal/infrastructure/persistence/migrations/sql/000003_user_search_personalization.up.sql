@@ -0,0 +1,3 @@
+-- Opt-out flag for personalized search ranking
+ALTER TABLE users
+    ADD COLUMN pref_disable_personalization BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,12 @@
+-- Searches that matched no recipes, aggregated to find taxonomy gaps
+CREATE TABLE zero_result_searches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    query VARCHAR(255) NOT NULL,
+    normalized_query VARCHAR(255) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    suggestions JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_zero_result_searches_query ON zero_result_searches(normalized_query);
+CREATE INDEX idx_zero_result_searches_created ON zero_result_searches(created_at DESC);
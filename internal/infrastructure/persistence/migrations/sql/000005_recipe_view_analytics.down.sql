@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_recipe_views_recipe_date;
+
+ALTER TABLE recipe_views
+    DROP COLUMN IF EXISTS engaged_ms,
+    DROP COLUMN IF EXISTS scroll_depth,
+    DROP COLUMN IF EXISTS search_term,
+    DROP COLUMN IF EXISTS referrer_host,
+    DROP COLUMN IF EXISTS referrer,
+    DROP COLUMN IF EXISTS session_id;
@@ -0,0 +1,10 @@
+-- Per-view attribution and engagement for the author stats page
+ALTER TABLE recipe_views
+    ADD COLUMN session_id VARCHAR(64),
+    ADD COLUMN referrer TEXT,
+    ADD COLUMN referrer_host VARCHAR(255),
+    ADD COLUMN search_term VARCHAR(255),
+    ADD COLUMN scroll_depth SMALLINT CHECK (scroll_depth BETWEEN 0 AND 100),
+    ADD COLUMN engaged_ms INTEGER CHECK (engaged_ms >= 0);
+
+CREATE INDEX idx_recipe_views_recipe_date ON recipe_views(recipe_id, viewed_at DESC);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS recipe_announcements;
+
+DROP INDEX IF EXISTS idx_recipes_scheduled_publish;
+
+ALTER TABLE recipes DROP COLUMN IF EXISTS scheduled_publish_at;
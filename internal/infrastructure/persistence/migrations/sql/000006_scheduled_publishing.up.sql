@@ -0,0 +1,22 @@
+-- Drafts that publish themselves at a chosen time
+ALTER TABLE recipes ADD COLUMN scheduled_publish_at TIMESTAMPTZ;
+
+CREATE INDEX idx_recipes_scheduled_publish ON recipes(scheduled_publish_at)
+    WHERE scheduled_publish_at IS NOT NULL AND status = 'draft';
+
+-- One delivery per recipe and announcement channel, retried until sent
+CREATE TABLE recipe_announcements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    channel VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(recipe_id, channel)
+);
+
+CREATE INDEX idx_recipe_announcements_due ON recipe_announcements(next_attempt_at)
+    WHERE status = 'pending';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS email_suppressions;
+
+DROP TABLE IF EXISTS comment_reply_tokens;
+
+DROP TABLE IF EXISTS comments;
@@ -0,0 +1,40 @@
+-- Comments on recipes, threaded under another comment or under a review
+CREATE TABLE comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    review_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    source VARCHAR(10) NOT NULL DEFAULT 'web' CHECK (source IN ('web', 'email')),
+    email_message_id VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_comments_recipe ON comments(recipe_id, created_at);
+CREATE UNIQUE INDEX idx_comments_email_message ON comments(email_message_id)
+    WHERE email_message_id IS NOT NULL;
+
+-- Reply-to addresses handed out in notification emails. The address carries
+-- the token id and a signature; this row says who may answer what.
+CREATE TABLE comment_reply_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    review_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_comment_reply_tokens_expires ON comment_reply_tokens(expires_at);
+
+-- Addresses we stop emailing after a hard bounce or spam complaint
+CREATE TABLE email_suppressions (
+    email VARCHAR(255) PRIMARY KEY,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('bounce', 'complaint')),
+    detail TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS shopping_list_changes;
+
+DROP TABLE IF EXISTS shopping_list_items;
+
+DROP TABLE IF EXISTS shopping_list_members;
+
+DROP TABLE IF EXISTS shopping_lists;
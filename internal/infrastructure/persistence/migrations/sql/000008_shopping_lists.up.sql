@@ -0,0 +1,58 @@
+-- Shopping lists shared between users and edited live
+CREATE TABLE shopping_lists (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    -- Number of changes applied; writers check it to detect concurrent saves
+    version BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_shopping_lists_owner ON shopping_lists(owner_id);
+
+CREATE TABLE shopping_list_members (
+    list_id UUID NOT NULL REFERENCES shopping_lists(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (list_id, user_id)
+);
+
+CREATE INDEX idx_shopping_list_members_user ON shopping_list_members(user_id);
+
+-- Removed items are kept so late offline edits resolve against the removal
+CREATE TABLE shopping_list_items (
+    id UUID PRIMARY KEY,
+    list_id UUID NOT NULL REFERENCES shopping_lists(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    quantity VARCHAR(50) NOT NULL DEFAULT '',
+    checked BOOLEAN NOT NULL DEFAULT FALSE,
+    checked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    checked_changed_at TIMESTAMPTZ NOT NULL,
+    edited_at TIMESTAMPTZ NOT NULL,
+    removed BOOLEAN NOT NULL DEFAULT FALSE,
+    added_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    version BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_shopping_list_items_list ON shopping_list_items(list_id, created_at);
+
+-- Applied changes in list version order, replayed to clients that
+-- reconnect. The operation id makes resent offline queues idempotent.
+CREATE TABLE shopping_list_changes (
+    list_id UUID NOT NULL REFERENCES shopping_lists(id) ON DELETE CASCADE,
+    version BIGINT NOT NULL,
+    op_id VARCHAR(64) NOT NULL,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('add', 'edit', 'check', 'uncheck', 'remove')),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    quantity VARCHAR(50) NOT NULL DEFAULT '',
+    checked BOOLEAN NOT NULL,
+    checked_by UUID,
+    removed BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (list_id, version),
+    UNIQUE (list_id, op_id)
+);
@@ -0,0 +1,18 @@
+-- Profiling captures and raw pprof access. Rows are the audit trail, so
+-- they are kept when the requesting user is deleted.
+CREATE TABLE profile_captures (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind VARCHAR(40) NOT NULL,
+    seconds INTEGER NOT NULL DEFAULT 0 CHECK (seconds >= 0),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('running', 'completed', 'failed', 'denied')),
+    requested_by UUID NOT NULL,
+    ip_address VARCHAR(45),
+    reason TEXT,
+    blob_key VARCHAR(255),
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_profile_captures_started ON profile_captures(started_at DESC);
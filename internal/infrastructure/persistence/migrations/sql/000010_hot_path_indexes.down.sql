@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_recipe_likes_user_created;
+DROP INDEX IF EXISTS idx_recipes_published_created;
+DROP INDEX IF EXISTS idx_recipes_author_created;
+DROP INDEX IF EXISTS idx_recipes_description_trgm;
+DROP INDEX IF EXISTS idx_recipes_title_trgm;
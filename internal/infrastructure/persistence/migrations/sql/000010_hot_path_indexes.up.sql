@@ -0,0 +1,9 @@
+-- Indexes behind the hot list and search queries. Search matches
+-- LOWER(title|description) LIKE '%term%', which only trigram indexes serve;
+-- the listing indexes cover the filter and sort order together so a page
+-- is read without sorting.
+CREATE INDEX idx_recipes_title_trgm ON recipes USING gin(LOWER(title) gin_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX idx_recipes_description_trgm ON recipes USING gin(LOWER(description) gin_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX idx_recipes_author_created ON recipes(author_id, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_recipes_published_created ON recipes(created_at DESC) WHERE status = 'published' AND deleted_at IS NULL;
+CREATE INDEX idx_recipe_likes_user_created ON recipe_likes(user_id, created_at DESC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS browse_top_rated;
+DROP TABLE IF EXISTS browse_cuisine_counts;
+DROP TABLE IF EXISTS browse_tag_counts;
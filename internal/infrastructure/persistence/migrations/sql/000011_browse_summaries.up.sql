@@ -0,0 +1,27 @@
+-- Summaries behind the tag and cuisine browse pages. They are rebuilt on a
+-- schedule by the application, in one transaction, so browse requests read
+-- a few rows instead of aggregating the recipes table.
+CREATE TABLE browse_tag_counts (
+    tag VARCHAR(100) PRIMARY KEY,
+    recipe_count INTEGER NOT NULL,
+    refreshed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_browse_tag_counts_count ON browse_tag_counts(recipe_count DESC, tag);
+
+CREATE TABLE browse_cuisine_counts (
+    cuisine VARCHAR(50) PRIMARY KEY,
+    recipe_count INTEGER NOT NULL,
+    refreshed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE browse_top_rated (
+    cuisine VARCHAR(50) NOT NULL,
+    rank INTEGER NOT NULL CHECK (rank > 0),
+    recipe_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    average_rating DECIMAL(2,1) NOT NULL DEFAULT 0.0,
+    likes_count INTEGER NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (cuisine, rank)
+);
@@ -0,0 +1,15 @@
+-- Days of RUM and audit rows moved to blob storage. The hot rows are
+-- deleted once their partition is listed here; a day can have several
+-- partitions when late rows are archived after it.
+CREATE TABLE archive_partitions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    dataset VARCHAR(40) NOT NULL,
+    day TIMESTAMPTZ NOT NULL,
+    blob_key VARCHAR(255) NOT NULL,
+    records INTEGER NOT NULL CHECK (records >= 0),
+    source_rows INTEGER NOT NULL CHECK (source_rows >= 0),
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_archive_partitions_dataset_day ON archive_partitions(dataset, day);
@@ -0,0 +1,8 @@
+-- Expiring leases for distributed locks and leader election. A released or
+-- expired lease keeps its row so the next holder's fencing token grows.
+CREATE TABLE leases (
+    name VARCHAR(128) PRIMARY KEY,
+    owner VARCHAR(255) NOT NULL,
+    token BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
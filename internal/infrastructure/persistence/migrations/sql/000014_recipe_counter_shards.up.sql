@@ -0,0 +1,10 @@
+-- Pending likes and views increments, spread over several rows per recipe
+-- so concurrent writers to a popular recipe do not queue on one row lock.
+-- A leader-only job folds them into recipes.likes_count and views_count.
+CREATE TABLE recipe_counter_shards (
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    counter VARCHAR(20) NOT NULL,
+    shard INTEGER NOT NULL,
+    value BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (recipe_id, counter, shard)
+);
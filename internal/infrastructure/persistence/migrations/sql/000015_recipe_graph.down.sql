@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS recipe_graph_edges;
+DROP TABLE IF EXISTS recipe_graph_nodes;
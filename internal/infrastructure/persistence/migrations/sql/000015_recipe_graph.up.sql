@@ -0,0 +1,23 @@
+-- Adjacency tables of the recipe knowledge graph: published recipes linked
+-- to the ingredients, techniques and cuisines they use. Rebuilt on a
+-- schedule by the application in one transaction.
+CREATE TABLE recipe_graph_nodes (
+    node_kind VARCHAR(20) NOT NULL,
+    node_key VARCHAR(100) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    recipe_count INTEGER NOT NULL,
+    weight DOUBLE PRECISION NOT NULL,
+    refreshed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (node_kind, node_key)
+);
+
+-- No foreign keys: edges of deleted recipes are dropped on the next rebuild
+-- and traversals join recipes to skip them until then
+CREATE TABLE recipe_graph_edges (
+    recipe_id UUID NOT NULL,
+    node_kind VARCHAR(20) NOT NULL,
+    node_key VARCHAR(100) NOT NULL,
+    PRIMARY KEY (recipe_id, node_kind, node_key)
+);
+
+CREATE INDEX idx_recipe_graph_edges_node ON recipe_graph_edges(node_kind, node_key, recipe_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS recipe_step_techniques;
+DROP TABLE IF EXISTS techniques;
@@ -0,0 +1,28 @@
+-- Technique library, curated by admins, and the techniques recipe steps
+-- link to. Links are chosen by the author or suggested for steps the
+-- author has not linked.
+CREATE TABLE techniques (
+    slug VARCHAR(80) PRIMARY KEY,
+    name VARCHAR(120) NOT NULL UNIQUE,
+    summary TEXT NOT NULL,
+    steps JSONB,
+    aliases JSONB,
+    video_provider VARCHAR(20),
+    video_id VARCHAR(32),
+    video_title VARCHAR(255),
+    video_duration INTEGER NOT NULL DEFAULT 0,
+    video_start INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE recipe_step_techniques (
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    step INTEGER NOT NULL,
+    technique_slug VARCHAR(80) NOT NULL REFERENCES techniques(slug) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (recipe_id, step, technique_slug)
+);
+
+CREATE INDEX idx_recipe_step_techniques_technique_slug ON recipe_step_techniques(technique_slug);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS recipe_translations;
+DROP INDEX IF EXISTS idx_recipes_language;
+ALTER TABLE recipes DROP COLUMN IF EXISTS language;
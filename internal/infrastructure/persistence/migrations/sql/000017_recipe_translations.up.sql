@@ -0,0 +1,20 @@
+-- The language each recipe is written in, and translations of its title,
+-- description, ingredients and steps. Every translated field records
+-- whether a machine or a person wrote it and a hash of the source text it
+-- translates, so edits to the recipe show which translations are stale.
+ALTER TABLE recipes ADD COLUMN language VARCHAR(16) NOT NULL DEFAULT 'en';
+
+CREATE INDEX idx_recipes_language ON recipes(language);
+
+CREATE TABLE recipe_translations (
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    language VARCHAR(16) NOT NULL,
+    field VARCHAR(40) NOT NULL,
+    text TEXT NOT NULL,
+    source_hash VARCHAR(64) NOT NULL,
+    provenance VARCHAR(20) NOT NULL,
+    model VARCHAR(100),
+    editor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (recipe_id, language, field)
+);
@@ -0,0 +1,36 @@
+-- Remix battles: the AI and a community chef each write a recipe for the
+-- same prompt and readers vote blind. template records the prompt
+-- template the AI entry was written with, so results can tune it.
+CREATE TABLE battles (
+    id UUID PRIMARY KEY,
+    prompt TEXT NOT NULL,
+    chef_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    template VARCHAR(40) NOT NULL,
+    ai_title VARCHAR(255) NOT NULL,
+    ai_description TEXT,
+    ai_ingredients JSONB,
+    ai_steps JSONB,
+    chef_title VARCHAR(255),
+    chef_description TEXT,
+    chef_ingredients JSONB,
+    chef_steps JSONB,
+    ai_first BOOLEAN NOT NULL,
+    voting_ends_at TIMESTAMPTZ,
+    ai_votes INTEGER NOT NULL DEFAULT 0,
+    chef_votes INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_battles_chef_id ON battles(chef_id);
+CREATE INDEX idx_battles_template ON battles(template);
+CREATE INDEX idx_battles_voting_ends_at ON battles(voting_ends_at);
+CREATE INDEX idx_battles_created_at ON battles(created_at);
+
+CREATE TABLE battle_votes (
+    battle_id UUID NOT NULL REFERENCES battles(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    side VARCHAR(10) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (battle_id, user_id)
+);
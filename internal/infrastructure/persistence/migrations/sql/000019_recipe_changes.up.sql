@@ -0,0 +1,12 @@
+-- Field-level change log for recipes. Sync clients ask for the revisions
+-- after the one they hold and apply only the fields that changed. Each row
+-- also keeps every synced field as of the revision, so the next save can
+-- work out what it changed.
+CREATE TABLE recipe_changes (
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    revision BIGINT NOT NULL,
+    fields TEXT NOT NULL,
+    snapshot TEXT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (recipe_id, revision)
+);
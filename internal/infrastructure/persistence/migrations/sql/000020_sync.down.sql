@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sync_devices;
+DROP TABLE IF EXISTS sync_counters;
+DROP TABLE IF EXISTS sync_records;
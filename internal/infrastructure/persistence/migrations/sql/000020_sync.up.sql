@@ -0,0 +1,38 @@
+-- Records a user's devices sync: bookmarks and notes written by the devices,
+-- and pointers to shopping lists and drafts written by the server. Fields
+-- holds each field's value with the time and device of the write that set
+-- it, so concurrent offline edits merge field by field. Deletes leave a
+-- tombstone until every device has pulled it or the retention passes.
+CREATE TABLE sync_records (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    key VARCHAR(64) NOT NULL,
+    fields TEXT NOT NULL,
+    cleared_at TIMESTAMPTZ,
+    cleared_by VARCHAR(64),
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    seq BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, kind, key)
+);
+
+CREATE INDEX idx_sync_records_user_seq ON sync_records(user_id, seq);
+CREATE INDEX idx_sync_records_tombstones ON sync_records(deleted, cleared_at) WHERE deleted;
+
+-- Each user's change sequence; every save takes the next number, which is
+-- the cursor devices pull from. purged_seq is the highest purged tombstone.
+CREATE TABLE sync_counters (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    seq BIGINT NOT NULL DEFAULT 0,
+    purged_seq BIGINT NOT NULL DEFAULT 0
+);
+
+-- How far each device has pulled, which holds back purging tombstones the
+-- device has yet to see
+CREATE TABLE sync_devices (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_id VARCHAR(64) NOT NULL,
+    pulled_seq BIGINT NOT NULL,
+    seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, device_id)
+);
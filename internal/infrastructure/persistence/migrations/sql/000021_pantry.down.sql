@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pantry_consumption;
+DROP TABLE IF EXISTS cook_logs;
+DROP TABLE IF EXISTS pantry_items;
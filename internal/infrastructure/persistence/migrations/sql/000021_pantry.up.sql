@@ -0,0 +1,43 @@
+-- A user's pantry inventory. Quantity is in the item's unit; par is how much
+-- the user likes to keep on hand, zero when they do not track it.
+CREATE TABLE pantry_items (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    quantity DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    unit VARCHAR(20) NOT NULL DEFAULT '',
+    par DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (par >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_pantry_items_user_id ON pantry_items(user_id);
+
+-- Every time a user marks a recipe as cooked
+CREATE TABLE cook_logs (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    portions INTEGER NOT NULL,
+    cooked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_cook_logs_user_cooked ON cook_logs(user_id, cooked_at);
+CREATE INDEX idx_cook_logs_recipe_id ON cook_logs(recipe_id);
+
+-- What each cook took from the pantry. Item name and unit are copied so
+-- consumption history outlives the item.
+CREATE TABLE pantry_consumption (
+    id BIGSERIAL PRIMARY KEY,
+    cook_id UUID NOT NULL REFERENCES cook_logs(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipe_id UUID NOT NULL,
+    item_id UUID NOT NULL,
+    item_name VARCHAR(200) NOT NULL,
+    amount DOUBLE PRECISION NOT NULL,
+    unit VARCHAR(20) NOT NULL DEFAULT '',
+    cooked_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_pantry_consumption_cook_id ON pantry_consumption(cook_id);
+CREATE INDEX idx_pantry_consumption_user_cooked ON pantry_consumption(user_id, cooked_at);
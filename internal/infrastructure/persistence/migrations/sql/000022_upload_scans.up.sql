@@ -0,0 +1,23 @@
+-- The virus scan of every uploaded photo and imported file. Infected files
+-- are kept in blob storage under quarantine_key for review.
+CREATE TABLE upload_scans (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(40) NOT NULL,
+    file_name VARCHAR(255),
+    content_type VARCHAR(100),
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    sha256 CHAR(64) NOT NULL,
+    scanner VARCHAR(40) NOT NULL,
+    verdict VARCHAR(20) NOT NULL CHECK (verdict IN ('clean', 'infected', 'failed', 'skipped')),
+    signature VARCHAR(255),
+    quarantine_key VARCHAR(255),
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    scanned_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_upload_scans_user_id ON upload_scans(user_id);
+CREATE INDEX idx_upload_scans_sha256 ON upload_scans(sha256);
+CREATE INDEX idx_upload_scans_scanned_at ON upload_scans(scanned_at);
+CREATE INDEX idx_upload_scans_verdict_scanned ON upload_scans(verdict, scanned_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS verification_reports;
+DROP TABLE IF EXISTS verification_claims;
@@ -0,0 +1,40 @@
+-- Author verification. Chefs and brands claim a verified badge with proof
+-- links; an approved claim is the badge. Readers report badges they believe
+-- are fake, and an upheld report revokes the claim.
+CREATE TABLE verification_claims (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('chef', 'brand')),
+    display_name VARCHAR(100) NOT NULL,
+    statement TEXT,
+    evidence JSONB,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'approved', 'rejected', 'revoked')),
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    review_note TEXT,
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_verification_claims_user_id ON verification_claims(user_id);
+CREATE INDEX idx_verification_claims_status_submitted ON verification_claims(status, submitted_at);
+-- An author holds at most one pending or approved claim
+CREATE UNIQUE INDEX idx_verification_claims_open ON verification_claims(user_id)
+    WHERE status IN ('pending', 'approved');
+
+CREATE TABLE verification_reports (
+    id UUID PRIMARY KEY,
+    claim_id UUID NOT NULL REFERENCES verification_claims(id) ON DELETE CASCADE,
+    subject_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('impersonation', 'fake_credentials', 'other')),
+    details TEXT,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('open', 'dismissed', 'upheld')),
+    resolver_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolution TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_verification_reports_claim_id ON verification_reports(claim_id);
+CREATE INDEX idx_verification_reports_reporter_id ON verification_reports(reporter_id);
+CREATE INDEX idx_verification_reports_status_created ON verification_reports(status, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS review_votes;
+DROP TABLE IF EXISTS comment_reactions;
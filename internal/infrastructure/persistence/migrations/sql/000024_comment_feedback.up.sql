@@ -0,0 +1,24 @@
+-- Reactions to comments and helpfulness votes on reviews. A review is a
+-- rating with a written comment, so votes are keyed by recipe and reviewer
+-- rather than by a review id.
+CREATE TABLE comment_reactions (
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('thumbs_up', 'heart', 'laugh', 'insightful')),
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (comment_id, user_id, kind)
+);
+
+CREATE INDEX idx_comment_reactions_user_created ON comment_reactions(user_id, created_at);
+
+CREATE TABLE review_votes (
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    voter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    helpful BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (recipe_id, reviewer_id, voter_id)
+);
+
+CREATE INDEX idx_review_votes_voter_created ON review_votes(voter_id, created_at);
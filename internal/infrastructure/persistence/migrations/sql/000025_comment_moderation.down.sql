@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS comment_moderation_events;
+DROP TABLE IF EXISTS comment_blocks;
+DROP TABLE IF EXISTS recipe_discussions;
+DROP INDEX IF EXISTS idx_comments_status;
+ALTER TABLE comments
+    DROP COLUMN IF EXISTS hidden_at,
+    DROP COLUMN IF EXISTS hide_reason,
+    DROP COLUMN IF EXISTS hidden_by,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,43 @@
+-- Moderation tools recipe authors have over comments on their recipes:
+-- hiding comments pending moderator review, locking a recipe's comments
+-- and blocking users from commenting on all of their recipes. Every action
+-- is written to the audit log.
+ALTER TABLE comments
+    ADD COLUMN status VARCHAR(10) NOT NULL DEFAULT 'visible' CHECK (status IN ('visible', 'hidden', 'removed')),
+    ADD COLUMN hidden_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN hide_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN hidden_at TIMESTAMPTZ;
+
+CREATE INDEX idx_comments_status ON comments(status, hidden_at) WHERE status <> 'visible';
+
+CREATE TABLE recipe_discussions (
+    recipe_id UUID PRIMARY KEY REFERENCES recipes(id) ON DELETE CASCADE,
+    locked BOOLEAN NOT NULL DEFAULT FALSE,
+    locked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    locked_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE comment_blocks (
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (author_id, user_id),
+    CHECK (author_id <> user_id)
+);
+
+-- The audit log outlives the recipes, comments and users it mentions
+CREATE TABLE comment_moderation_events (
+    id UUID PRIMARY KEY,
+    actor_id UUID NOT NULL,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('lock', 'unlock', 'hide', 'restore', 'remove', 'block', 'unblock')),
+    recipe_id UUID,
+    comment_id UUID,
+    subject_id UUID,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_comment_moderation_events_actor_created ON comment_moderation_events(actor_id, created_at);
+CREATE INDEX idx_comment_moderation_events_recipe_created ON comment_moderation_events(recipe_id, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS hidden_gems;
+DROP TABLE IF EXISTS recipe_popularity;
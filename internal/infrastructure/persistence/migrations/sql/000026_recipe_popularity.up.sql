@@ -0,0 +1,17 @@
+-- Decayed popularity behind the trending list, and the rotation of hidden
+-- gems: well rated recipes few people have seen. Both are maintained on a
+-- schedule by the application.
+CREATE TABLE recipe_popularity (
+    recipe_id UUID PRIMARY KEY REFERENCES recipes(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL CHECK (score >= 0),
+    refreshed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_recipe_popularity_score ON recipe_popularity(score DESC);
+
+CREATE TABLE hidden_gems (
+    recipe_id UUID PRIMARY KEY REFERENCES recipes(id) ON DELETE CASCADE,
+    featured_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_hidden_gems_featured_at ON hidden_gems(featured_at);
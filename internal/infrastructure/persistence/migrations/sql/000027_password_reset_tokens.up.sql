@@ -0,0 +1,14 @@
+-- Tokens mailed by POST /auth/forgot-password. Only the token's SHA-256 is
+-- stored; each works once and until expires_at. The (email, created_at)
+-- index backs the per-address rate limit.
+CREATE TABLE password_reset_tokens (
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+CREATE INDEX idx_password_reset_tokens_email_created ON password_reset_tokens(email, created_at);
@@ -0,0 +1,13 @@
+-- Pages saved as draft recipes through POST /recipes/clip. The
+-- (user_id, created_at) index backs the per-user clip limit.
+CREATE TABLE recipe_clips (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    page_url TEXT NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_recipe_clips_user_created ON recipe_clips(user_id, created_at);
+CREATE INDEX idx_recipe_clips_recipe_id ON recipe_clips(recipe_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS guest_recipes;
+DROP TABLE IF EXISTS guest_sessions;
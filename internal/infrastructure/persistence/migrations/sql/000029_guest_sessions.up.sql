@@ -0,0 +1,34 @@
+-- Guest mode: anonymous visitors chat with the AI chef and keep a few
+-- recipes until expires_at. Only the token's SHA-256 is stored, and the
+-- client address only as a hash for the per-address session limit.
+-- Registering moves the recipes to the account and sets claimed_by.
+CREATE TABLE guest_sessions (
+    id UUID PRIMARY KEY,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    ip_hash CHAR(64) NOT NULL,
+    messages INTEGER NOT NULL DEFAULT 0,
+    recipes INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    claimed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    claimed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_guest_sessions_ip_created ON guest_sessions(ip_hash, created_at);
+CREATE INDEX idx_guest_sessions_expires_at ON guest_sessions(expires_at);
+
+CREATE TABLE guest_recipes (
+    id UUID PRIMARY KEY,
+    guest_id UUID NOT NULL REFERENCES guest_sessions(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    ingredients JSONB NOT NULL DEFAULT '[]',
+    directions JSONB NOT NULL DEFAULT '[]',
+    servings INTEGER,
+    prep_time INTEGER,
+    cook_time INTEGER,
+    tags JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_guest_recipes_guest_id ON guest_recipes(guest_id);
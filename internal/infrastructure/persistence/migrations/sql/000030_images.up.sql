@@ -0,0 +1,16 @@
+-- Uploaded images. The original is kept in blob storage under blob_key;
+-- resized and re-encoded variants are served from /img/{id} and cached
+-- outside the database.
+CREATE TABLE images (
+    id UUID PRIMARY KEY,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blob_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_images_owner_id ON images(owner_id);
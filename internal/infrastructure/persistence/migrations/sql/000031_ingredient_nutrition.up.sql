@@ -0,0 +1,22 @@
+-- Reference foods recipe nutrition is computed from, nutrients per 100 g.
+-- Ingredient names are matched against keywords in position order. The
+-- USDA SR Legacy values built into the app are inserted at startup for any
+-- name not already here, so rows edited by hand are kept.
+CREATE TABLE ingredient_nutrition (
+    name VARCHAR(100) PRIMARY KEY,
+    keywords VARCHAR(500) NOT NULL,
+    position INTEGER NOT NULL,
+    calories DOUBLE PRECISION NOT NULL,
+    protein DOUBLE PRECISION NOT NULL,
+    carbohydrates DOUBLE PRECISION NOT NULL,
+    fat DOUBLE PRECISION NOT NULL,
+    fiber DOUBLE PRECISION NOT NULL,
+    sugar DOUBLE PRECISION NOT NULL,
+    sodium DOUBLE PRECISION NOT NULL,
+    cholesterol DOUBLE PRECISION NOT NULL,
+    price_per_kg DOUBLE PRECISION NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ingredient_nutrition_position ON ingredient_nutrition(position);
@@ -0,0 +1,10 @@
+-- Recipes users saved for later. Favorites are private to the user and,
+-- unlike likes, are not counted on the recipe.
+CREATE TABLE favorites (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, recipe_id)
+);
+
+CREATE INDEX idx_favorites_recipe_id ON favorites(recipe_id);
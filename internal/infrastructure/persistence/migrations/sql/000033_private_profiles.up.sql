@@ -0,0 +1,3 @@
+-- Private profiles are shown only to the users their owner follows
+ALTER TABLE users
+    ADD COLUMN pref_private_profile BOOLEAN NOT NULL DEFAULT FALSE;
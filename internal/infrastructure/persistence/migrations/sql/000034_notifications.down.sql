@@ -0,0 +1,9 @@
+ALTER TABLE users DROP COLUMN IF EXISTS pref_muted_notifications;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS link,
+    DROP COLUMN IF EXISTS actor_id;
+
+DELETE FROM notifications
+WHERE type NOT IN ('recipe_liked', 'recipe_commented', 'new_follower', 'recipe_published');
+ALTER TABLE notifications ALTER COLUMN type TYPE notification_type USING type::notification_type;
@@ -0,0 +1,11 @@
+-- Notification types are validated by the application so new kinds, such
+-- as a finished AI recipe, need no enum change
+ALTER TABLE notifications ALTER COLUMN type TYPE VARCHAR(50) USING type::text;
+
+ALTER TABLE notifications
+    ADD COLUMN actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN link VARCHAR(500);
+
+-- Notification types the user has turned off; every type is on by default
+ALTER TABLE users
+    ADD COLUMN pref_muted_notifications JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS comment_flags;
+
+DELETE FROM comment_moderation_events WHERE action IN ('delete', 'dismiss');
+ALTER TABLE comment_moderation_events
+    DROP CONSTRAINT IF EXISTS comment_moderation_events_action_check,
+    ADD CONSTRAINT comment_moderation_events_action_check
+        CHECK (action IN ('lock', 'unlock', 'hide', 'restore', 'remove', 'block', 'unblock'));
+
+UPDATE comments SET status = 'removed' WHERE status = 'deleted';
+ALTER TABLE comments
+    DROP CONSTRAINT IF EXISTS comments_status_check,
+    ADD CONSTRAINT comments_status_check CHECK (status IN ('visible', 'hidden', 'removed')),
+    DROP COLUMN IF EXISTS edited_at;
@@ -0,0 +1,23 @@
+-- Comment authors can edit and delete their comments, and readers can flag
+-- comments for the moderators' queue. Deleted comments keep their row so
+-- replies stay threaded under them.
+ALTER TABLE comments
+    ADD COLUMN edited_at TIMESTAMPTZ,
+    DROP CONSTRAINT IF EXISTS comments_status_check,
+    ADD CONSTRAINT comments_status_check CHECK (status IN ('visible', 'hidden', 'removed', 'deleted'));
+
+ALTER TABLE comment_moderation_events
+    DROP CONSTRAINT IF EXISTS comment_moderation_events_action_check,
+    ADD CONSTRAINT comment_moderation_events_action_check
+        CHECK (action IN ('lock', 'unlock', 'hide', 'restore', 'remove', 'block', 'unblock', 'delete', 'dismiss'));
+
+CREATE TABLE comment_flags (
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('spam', 'abuse', 'off_topic', 'other')),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE INDEX idx_comment_flags_created ON comment_flags(created_at);
@@ -0,0 +1,24 @@
+-- Readers report recipes and comments that break the rules. Content with
+-- moderation.report_threshold open reports is taken down until an admin
+-- resolves or dismisses them; unpublished marks the reports that did so.
+CREATE TABLE reports (
+    id UUID PRIMARY KEY,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('recipe', 'comment')),
+    target_id UUID NOT NULL,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('spam', 'abuse', 'inappropriate', 'copyright', 'other')),
+    note TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved', 'dismissed')),
+    unpublished BOOLEAN NOT NULL DEFAULT FALSE,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolution TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_reports_target ON reports(target_type, target_id);
+CREATE INDEX idx_reports_recipe_id ON reports(recipe_id);
+CREATE INDEX idx_reports_status_created ON reports(status, created_at);
+-- A reader has at most one open report on the same content
+CREATE UNIQUE INDEX idx_reports_open_reporter ON reports(target_type, target_id, reporter_id) WHERE status = 'open';
@@ -0,0 +1,3 @@
+CREATE INDEX idx_recipes_search ON recipes USING gin(to_tsvector('english', title || ' ' || COALESCE(description, ''))) WHERE deleted_at IS NULL;
+DROP INDEX IF EXISTS idx_recipes_search_vector;
+ALTER TABLE recipes DROP COLUMN IF EXISTS search_vector;
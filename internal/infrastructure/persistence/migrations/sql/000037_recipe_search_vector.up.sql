@@ -0,0 +1,14 @@
+-- Full-text search over recipes. search_vector weighs the title above the
+-- description above ingredient names and tags; the repository writes it
+-- with every save of the recipe, so only existing rows are filled here.
+-- It supersedes the title and description expression index.
+ALTER TABLE recipes ADD COLUMN search_vector TSVECTOR;
+
+UPDATE recipes SET search_vector =
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE((SELECT string_agg(name, ' ') FROM ingredients WHERE ingredients.recipe_id = recipes.id), '') || ' ' ||
+        COALESCE((SELECT string_agg(tag, ' ') FROM recipe_tags WHERE recipe_tags.recipe_id = recipes.id), '')), 'C');
+
+CREATE INDEX idx_recipes_search_vector ON recipes USING gin(search_vector) WHERE deleted_at IS NULL;
+DROP INDEX IF EXISTS idx_recipes_search;
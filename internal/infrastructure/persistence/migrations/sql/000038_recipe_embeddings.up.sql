@@ -0,0 +1,17 @@
+-- Recipe vectors behind search by meaning, one per recipe and embedding
+-- model. Models differ in dimensions, so the column is unsized and
+-- searches compare vectors of one model exactly rather than through an
+-- approximate index.
+CREATE EXTENSION IF NOT EXISTS vector;
+
+CREATE TABLE recipe_embeddings (
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    model VARCHAR(100) NOT NULL,
+    dimensions INTEGER NOT NULL,
+    embedding VECTOR NOT NULL,
+    -- The recipe's updated_at when it was embedded; a later change makes
+    -- the vector stale
+    recipe_updated_at TIMESTAMPTZ NOT NULL,
+    embedded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (recipe_id, model)
+);
@@ -0,0 +1,15 @@
+-- Positions of the published recipes in the trending and top listings,
+-- rebuilt with the popularity scores so both sorts page by a precomputed
+-- rank. Trending follows the decayed score; top follows all-time likes and
+-- views.
+CREATE TABLE recipe_rankings (
+    recipe_id UUID PRIMARY KEY REFERENCES recipes(id) ON DELETE CASCADE,
+    trending_score DOUBLE PRECISION NOT NULL CHECK (trending_score >= 0),
+    trending_rank INTEGER NOT NULL CHECK (trending_rank > 0),
+    top_score DOUBLE PRECISION NOT NULL CHECK (top_score >= 0),
+    top_rank INTEGER NOT NULL CHECK (top_rank > 0),
+    ranked_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_recipe_rankings_trending_rank ON recipe_rankings(trending_rank);
+CREATE INDEX idx_recipe_rankings_top_rank ON recipe_rankings(top_rank);
@@ -0,0 +1,11 @@
+-- Views of each recipe per UTC day, counting a viewer once per recipe
+-- within the dedupe window. Filled from the buffered counts every flush
+-- interval, so the current day trails by at most that long.
+CREATE TABLE recipe_daily_views (
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views INTEGER NOT NULL CHECK (views >= 0),
+    PRIMARY KEY (recipe_id, day)
+);
+
+CREATE INDEX idx_recipe_daily_views_day ON recipe_daily_views(day);
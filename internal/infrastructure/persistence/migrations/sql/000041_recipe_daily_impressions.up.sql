@@ -0,0 +1,10 @@
+-- Times each recipe was listed in the results of a text search, per UTC
+-- day. Filled from buffered counts with the daily views.
+CREATE TABLE recipe_daily_impressions (
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    impressions INTEGER NOT NULL CHECK (impressions >= 0),
+    PRIMARY KEY (recipe_id, day)
+);
+
+CREATE INDEX idx_recipe_daily_impressions_day ON recipe_daily_impressions(day);
@@ -0,0 +1,8 @@
+-- Recipes forked from another recipe link back to it. Deleting the
+-- original keeps the forks and drops the link. forks_count is folded from
+-- recipe_counter_shards like likes_count and views_count.
+ALTER TABLE recipes
+    ADD COLUMN forked_from_id UUID REFERENCES recipes(id) ON DELETE SET NULL,
+    ADD COLUMN forks_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_recipes_forked_from_id ON recipes(forked_from_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS account_audit_events;
+DROP TABLE IF EXISTS account_deletions;
@@ -0,0 +1,22 @@
+-- Accounts waiting out the grace period before DELETE /api/v1/me erases
+-- them. Erasure keeps the user row, stripped of personal data, so the
+-- comments and likes it left stay in place.
+CREATE TABLE account_deletions (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    scheduled_for TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_account_deletions_scheduled_for ON account_deletions(scheduled_for);
+
+-- The audit log outlives the accounts it mentions
+CREATE TABLE account_audit_events (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    actor_id UUID,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('deletion_requested', 'deletion_cancelled', 'erased')),
+    detail TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_account_audit_events_user_created ON account_audit_events(user_id, created_at);
@@ -0,0 +1,101 @@
+-- Substitutes knowledge base, one row per ingredient and substitute. Each
+-- ingredient's substitutes are suggested in position order. The built-in
+-- swaps are inserted here and again at startup for any not already stored,
+-- so rows edited by hand are kept.
+CREATE TABLE ingredient_substitutes (
+    ingredient VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    ratio VARCHAR(200) NOT NULL,
+    notes VARCHAR(500) NOT NULL,
+    contains JSONB NOT NULL DEFAULT '[]',
+    position INTEGER NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (ingredient, name)
+);
+
+CREATE INDEX idx_ingredient_substitutes_position ON ingredient_substitutes(ingredient, position);
+
+INSERT INTO ingredient_substitutes (ingredient, name, ratio, notes, contains, position, source) VALUES
+    ('butter', 'olive oil', '3/4 cup per cup', 'For sautéing and savory bakes, not for creaming', '[]', 10, 'built-in'),
+    ('butter', 'coconut oil', '1 cup per cup', 'Solid at room temperature, so it creams like butter', '[]', 20, 'built-in'),
+    ('butter', 'unsweetened applesauce', '1/2 cup per cup', 'In cakes and muffins; the crumb is moister and denser', '["sugar"]', 30, 'built-in'),
+    ('butter', 'ghee', '1 cup per cup', 'Clarified butter, kept by most who avoid lactose', '["dairy"]', 40, 'built-in'),
+    ('milk', 'oat milk', '1 cup per cup', 'Neutral and creamy; thickens sauces well', '["grain"]', 10, 'built-in'),
+    ('milk', 'soy milk', '1 cup per cup', 'Highest in protein, closest in baking', '["legume"]', 20, 'built-in'),
+    ('milk', 'almond milk', '1 cup per cup', 'Thinner; pick unsweetened for savory dishes', '[]', 30, 'built-in'),
+    ('milk', 'water and butter', '1 cup water plus 1 tbsp butter per cup', 'A fallback for baking', '["dairy"]', 40, 'built-in'),
+    ('buttermilk', 'milk and lemon juice', '1 cup milk plus 1 tbsp lemon juice per cup', 'Stand 5 minutes until it curdles', '["dairy"]', 10, 'built-in'),
+    ('buttermilk', 'plant milk and vinegar', '1 cup plant milk plus 1 tbsp vinegar per cup', 'Soy milk curdles best', '[]', 20, 'built-in'),
+    ('buttermilk', 'yogurt thinned with milk', '3/4 cup yogurt plus 1/4 cup milk per cup', '', '["dairy"]', 30, 'built-in'),
+    ('heavy cream', 'coconut cream', '1 cup per cup', 'Whips when chilled; tastes of coconut', '[]', 10, 'built-in'),
+    ('heavy cream', 'milk and butter', '3/4 cup milk plus 1/4 cup melted butter per cup', 'For cooking, not whipping', '["dairy"]', 20, 'built-in'),
+    ('heavy cream', 'cashew cream', '1 cup per cup', 'Blend soaked cashews with water until smooth', '[]', 30, 'built-in'),
+    ('sour cream', 'greek yogurt', '1 cup per cup', 'Tangier and lighter', '["dairy"]', 10, 'built-in'),
+    ('sour cream', 'cashew cream and lemon juice', '1 cup plus 1 tsp lemon juice per cup', '', '[]', 20, 'built-in'),
+    ('egg', 'flax egg', '1 tbsp ground flaxseed plus 3 tbsp water per egg', 'Rest 5 minutes to gel; binds but does not lift', '[]', 10, 'built-in'),
+    ('egg', 'chia egg', '1 tbsp chia seeds plus 3 tbsp water per egg', 'Rest 10 minutes; leaves specks', '[]', 20, 'built-in'),
+    ('egg', 'mashed banana', '1/4 cup per egg', 'In sweet bakes; tastes of banana', '["sugar"]', 30, 'built-in'),
+    ('egg', 'aquafaba', '3 tbsp per egg', 'Chickpea cooking liquid; whips like egg white', '["legume"]', 40, 'built-in'),
+    ('egg', 'unsweetened applesauce', '1/4 cup per egg', 'In cakes and muffins', '["sugar"]', 50, 'built-in'),
+    ('cheese', 'nutritional yeast', '1/4 cup per 1/2 cup grated', 'Savory and nutty in sauces and on pasta', '[]', 10, 'built-in'),
+    ('parmesan', 'nutritional yeast', '3 tbsp per 1/4 cup', 'Savory and nutty; does not melt', '[]', 10, 'built-in'),
+    ('parmesan', 'pecorino', '1 cup per cup', 'Saltier and sharper', '["dairy"]', 20, 'built-in'),
+    ('all purpose flour', 'gluten-free flour blend', '1 cup per cup', 'Pick one with xanthan gum for cakes and breads', '[]', 10, 'built-in'),
+    ('all purpose flour', 'whole wheat flour', '3/4 cup per cup', 'Denser and nuttier; add a splash more liquid', '["gluten","grain"]', 20, 'built-in'),
+    ('all purpose flour', 'almond flour', '1 cup per cup', 'For cookies and quick breads; browns quickly', '[]', 30, 'built-in'),
+    ('flour', 'gluten-free flour blend', '1 cup per cup', 'Pick one with xanthan gum for cakes and breads', '[]', 10, 'built-in'),
+    ('flour', 'almond flour', '1 cup per cup', 'For cookies and quick breads; browns quickly', '[]', 20, 'built-in'),
+    ('cornstarch', 'arrowroot', '1 tbsp per tbsp', 'Thickens at lower heat and stays clear', '[]', 10, 'built-in'),
+    ('cornstarch', 'all-purpose flour', '2 tbsp per tbsp', 'Cook a few minutes longer to lose the raw taste', '["gluten","grain"]', 20, 'built-in'),
+    ('breadcrumbs', 'crushed crackers', '1 cup per cup', '', '["gluten","grain"]', 10, 'built-in'),
+    ('breadcrumbs', 'rolled oats', '1 cup per cup', 'Pulse briefly for binding meatballs and burgers', '["grain"]', 20, 'built-in'),
+    ('breadcrumbs', 'almond meal', '1 cup per cup', 'For coatings; browns quickly', '[]', 30, 'built-in'),
+    ('sugar', 'honey', '3/4 cup per cup', 'Cut the liquid by 1/4 cup and the oven by 25°F', '["honey","sugar"]', 10, 'built-in'),
+    ('sugar', 'maple syrup', '3/4 cup per cup', 'Cut the liquid by 3 tbsp', '["sugar"]', 20, 'built-in'),
+    ('sugar', 'erythritol', '1 cup per cup', 'Sugar-free; can taste cool in large amounts', '[]', 30, 'built-in'),
+    ('brown sugar', 'white sugar and molasses', '1 cup sugar plus 1 tbsp molasses per cup', '', '["sugar"]', 10, 'built-in'),
+    ('brown sugar', 'coconut sugar', '1 cup per cup', '', '["sugar"]', 20, 'built-in'),
+    ('honey', 'maple syrup', '1 cup per cup', 'Thinner and less floral', '["sugar"]', 10, 'built-in'),
+    ('honey', 'agave syrup', '1 cup per cup', 'Sweeter; use a little less', '["sugar"]', 20, 'built-in'),
+    ('baking powder', 'baking soda and cream of tartar', '1/4 tsp soda plus 1/2 tsp cream of tartar per tsp', '', '[]', 10, 'built-in'),
+    ('baking soda', 'baking powder', '3 tsp per tsp', 'Reduce the salt; the bake is less browned', '[]', 10, 'built-in'),
+    ('vanilla extract', 'maple syrup', '1 tsp per tsp', '', '["sugar"]', 10, 'built-in'),
+    ('vanilla extract', 'almond extract', '1/2 tsp per tsp', 'Much stronger', '[]', 20, 'built-in'),
+    ('soy sauce', 'tamari', '1 tbsp per tbsp', 'Usually wheat-free; check the label', '["legume"]', 10, 'built-in'),
+    ('soy sauce', 'coconut aminos', '1 tbsp per tbsp', 'Sweeter and less salty; add a pinch of salt', '[]', 20, 'built-in'),
+    ('fish sauce', 'soy sauce and lime juice', '1 tbsp soy sauce plus a squeeze of lime per tbsp', '', '["gluten","legume"]', 10, 'built-in'),
+    ('fish sauce', 'seaweed broth', '2 tbsp per tbsp', 'Simmer kombu with a little salt', '[]', 20, 'built-in'),
+    ('worcestershire sauce', 'soy sauce and vinegar', '1 tbsp soy sauce plus 1/4 tsp vinegar per tbsp', '', '["gluten","legume"]', 10, 'built-in'),
+    ('white wine', 'chicken stock and lemon juice', '1 cup stock plus 1 tsp lemon juice per cup', '', '["meat"]', 10, 'built-in'),
+    ('white wine', 'vegetable stock and white wine vinegar', '1 cup stock plus 1 tbsp vinegar per cup', '', '[]', 20, 'built-in'),
+    ('red wine', 'beef stock and red wine vinegar', '1 cup stock plus 1 tbsp vinegar per cup', '', '["meat"]', 10, 'built-in'),
+    ('red wine', 'pomegranate juice', '1 cup per cup', 'Fruity; add a splash of vinegar for bite', '["sugar"]', 20, 'built-in'),
+    ('chicken stock', 'vegetable stock', '1 cup per cup', '', '[]', 10, 'built-in'),
+    ('beef stock', 'mushroom stock', '1 cup per cup', 'Rich and savory', '[]', 10, 'built-in'),
+    ('chicken', 'extra-firm tofu', '1 lb per lb', 'Press and dry it first for a good sear', '["legume"]', 10, 'built-in'),
+    ('chicken', 'chickpeas', '1 1/2 cups per lb', 'In curries and stews', '["legume"]', 20, 'built-in'),
+    ('chicken', 'turkey', '1 lb per lb', 'Leaner; cook a little less', '["meat"]', 30, 'built-in'),
+    ('ground beef', 'lentils', '1 1/2 cups cooked per lb', 'In sauces, chili and tacos', '["legume"]', 10, 'built-in'),
+    ('ground beef', 'crumbled tempeh', '1 lb per lb', 'Steam 10 minutes first to soften the taste', '["legume"]', 20, 'built-in'),
+    ('ground beef', 'ground turkey', '1 lb per lb', 'Leaner; add a little oil', '["meat"]', 30, 'built-in'),
+    ('bacon', 'smoked tempeh', '1 lb per lb', '', '["legume"]', 10, 'built-in'),
+    ('bacon', 'turkey bacon', '1 lb per lb', '', '["meat"]', 20, 'built-in'),
+    ('pancetta', 'smoked mushrooms', '1 cup chopped per 4 oz', 'Sauté shiitake with smoked paprika', '[]', 10, 'built-in'),
+    ('shrimp', 'king oyster mushrooms', '1 lb per lb', 'Slice the stems into rounds and sear', '[]', 10, 'built-in'),
+    ('anchovies', 'capers', '1 tbsp chopped per 2 fillets', 'Briny and salty', '[]', 10, 'built-in'),
+    ('pasta', 'zucchini noodles', '2 cups per 2 oz dry', 'Cook briefly or serve raw', '[]', 10, 'built-in'),
+    ('pasta', 'rice noodles', '2 oz per 2 oz', '', '["grain"]', 20, 'built-in'),
+    ('rice', 'cauliflower rice', '1 cup per cup cooked', 'Cook 5 minutes; do not boil', '[]', 10, 'built-in'),
+    ('rice', 'quinoa', '1 cup per cup cooked', 'More protein; rinse before cooking', '[]', 20, 'built-in'),
+    ('lemon juice', 'lime juice', '1 tbsp per tbsp', '', '[]', 10, 'built-in'),
+    ('lemon juice', 'white wine vinegar', '1/2 tbsp per tbsp', 'Sharper', '[]', 20, 'built-in'),
+    ('shallot', 'red onion', '1/2 small onion per shallot', 'Stronger; chop finely', '[]', 10, 'built-in'),
+    ('garlic', 'garlic powder', '1/8 tsp per clove', '', '[]', 10, 'built-in'),
+    ('fresh herbs', 'dried herbs', '1 tsp per tbsp', 'Add early so they soften', '[]', 10, 'built-in'),
+    ('peanut butter', 'sunflower seed butter', '1 tbsp per tbsp', 'Nut-free; may turn green in baking, which is harmless', '[]', 10, 'built-in'),
+    ('peanut butter', 'tahini', '1 tbsp per tbsp', 'Less sweet; add a little honey or syrup', '[]', 20, 'built-in'),
+    ('mayonnaise', 'greek yogurt', '1 cup per cup', 'Tangier and lighter', '["dairy"]', 10, 'built-in'),
+    ('mayonnaise', 'mashed avocado', '1 cup per cup', 'In sandwiches and dressings', '[]', 20, 'built-in'),
+    ('gelatin', 'agar agar', '1 tsp powder per tbsp', 'Sets firmer and at room temperature', '[]', 10, 'built-in')
+ON CONFLICT (ingredient, name) DO NOTHING;
@@ -0,0 +1,78 @@
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS activities;
+DROP TABLE IF EXISTS ai_requests;
+DROP TABLE IF EXISTS ratings;
+
+-- Values the enums never had are cleared on the way back
+UPDATE ingredients SET unit = NULL
+WHERE unit NOT IN ('tsp', 'tbsp', 'cup', 'oz', 'ml', 'l', 'g', 'kg', 'lb', 'piece', 'dash', 'pinch');
+ALTER TABLE ingredients ALTER COLUMN unit TYPE measurement_unit USING unit::measurement_unit;
+
+UPDATE instructions SET temperature_unit = NULL WHERE temperature_unit NOT IN ('C', 'F', 'K');
+ALTER TABLE instructions ALTER COLUMN temperature_unit TYPE temperature_unit USING temperature_unit::temperature_unit;
+
+DROP INDEX IF EXISTS idx_recipes_published_at;
+DROP INDEX IF EXISTS idx_recipes_likes;
+DROP INDEX IF EXISTS idx_recipes_rating;
+DROP INDEX IF EXISTS idx_recipes_published_created;
+DROP INDEX IF EXISTS idx_recipes_scheduled_publish;
+
+UPDATE recipes SET cuisine = 'other'
+WHERE cuisine NOT IN ('italian', 'french', 'chinese', 'japanese', 'indian', 'mexican', 'american', 'mediterranean', 'thai', 'other');
+UPDATE recipes SET category = NULL
+WHERE category NOT IN ('appetizer', 'main_course', 'side_dish', 'dessert', 'beverage', 'breakfast', 'lunch', 'dinner', 'snack');
+UPDATE recipes SET difficulty = NULL WHERE difficulty NOT IN ('easy', 'medium', 'hard', 'expert');
+UPDATE recipes SET status = 'draft' WHERE status NOT IN ('draft', 'published', 'archived', 'deleted');
+
+ALTER TABLE recipes
+    DROP COLUMN IF EXISTS videos,
+    DROP COLUMN IF EXISTS images,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS nutrition_info,
+    DROP COLUMN IF EXISTS instructions,
+    DROP COLUMN IF EXISTS ingredients,
+    DROP COLUMN IF EXISTS total_time_minutes,
+    ALTER COLUMN cuisine TYPE cuisine_type USING cuisine::cuisine_type,
+    ALTER COLUMN category TYPE category_type USING category::category_type,
+    ALTER COLUMN difficulty TYPE difficulty_level USING difficulty::difficulty_level,
+    ALTER COLUMN status DROP DEFAULT,
+    ALTER COLUMN status TYPE recipe_status USING status::recipe_status,
+    ALTER COLUMN status SET DEFAULT 'draft';
+
+ALTER TABLE recipes
+    ADD COLUMN total_time_minutes INTEGER GENERATED ALWAYS AS (prep_time_minutes + cook_time_minutes) STORED;
+
+CREATE INDEX idx_recipes_published_at ON recipes(published_at DESC) WHERE status = 'published' AND deleted_at IS NULL;
+CREATE INDEX idx_recipes_likes ON recipes(likes_count DESC) WHERE status = 'published' AND deleted_at IS NULL;
+CREATE INDEX idx_recipes_rating ON recipes(average_rating DESC, ratings_count DESC) WHERE status = 'published' AND deleted_at IS NULL;
+CREATE INDEX idx_recipes_published_created ON recipes(created_at DESC) WHERE status = 'published' AND deleted_at IS NULL;
+CREATE INDEX idx_recipes_scheduled_publish ON recipes(scheduled_publish_at)
+    WHERE scheduled_publish_at IS NOT NULL AND status = 'draft';
+
+-- Users created since have no username; their id stands in for one
+UPDATE users SET username = LEFT(id::text, 50) WHERE username IS NULL;
+UPDATE users SET role = 'user' WHERE role NOT IN ('user', 'premium', 'moderator', 'admin');
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS pref_push_notifications,
+    DROP COLUMN IF EXISTS pref_email_notifications,
+    DROP COLUMN IF EXISTS pref_timezone,
+    DROP COLUMN IF EXISTS pref_language,
+    DROP COLUMN IF EXISTS pref_measurement_system,
+    DROP COLUMN IF EXISTS pref_disliked_ingredients,
+    DROP COLUMN IF EXISTS pref_preferred_cuisines,
+    DROP COLUMN IF EXISTS pref_allergies,
+    DROP COLUMN IF EXISTS pref_dietary_restrictions,
+    DROP COLUMN IF EXISTS profile_cooking_level,
+    DROP COLUMN IF EXISTS profile_birthday,
+    DROP COLUMN IF EXISTS profile_website,
+    DROP COLUMN IF EXISTS profile_location,
+    DROP COLUMN IF EXISTS profile_bio,
+    DROP COLUMN IF EXISTS profile_avatar,
+    DROP COLUMN IF EXISTS profile_last_name,
+    DROP COLUMN IF EXISTS profile_first_name,
+    DROP COLUMN IF EXISTS name,
+    ALTER COLUMN username SET NOT NULL,
+    ALTER COLUMN role DROP DEFAULT,
+    ALTER COLUMN role TYPE user_role USING role::user_role,
+    ALTER COLUMN role SET DEFAULT 'user';
@@ -0,0 +1,173 @@
+-- Brings users and recipes in line with the GORM models, which until now
+-- only matched the AutoMigrate schema, and adds the tables the models use
+-- that no migration created.
+
+-- Users are named rather than given a username, and keep their profile and
+-- preferences in pref_ and profile_ columns. Roles, like every other set
+-- of values below, are validated by the application, so chefs need no
+-- enum change.
+ALTER TABLE users
+    ADD COLUMN name VARCHAR(255),
+    ADD COLUMN profile_first_name VARCHAR(100),
+    ADD COLUMN profile_last_name VARCHAR(100),
+    ADD COLUMN profile_avatar TEXT,
+    ADD COLUMN profile_bio TEXT,
+    ADD COLUMN profile_location VARCHAR(255),
+    ADD COLUMN profile_website VARCHAR(255),
+    ADD COLUMN profile_birthday TIMESTAMPTZ,
+    ADD COLUMN profile_cooking_level VARCHAR(50),
+    ADD COLUMN pref_dietary_restrictions JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN pref_allergies JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN pref_preferred_cuisines JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN pref_disliked_ingredients JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN pref_measurement_system VARCHAR(20) DEFAULT 'metric',
+    ADD COLUMN pref_language VARCHAR(10) DEFAULT 'en',
+    ADD COLUMN pref_timezone VARCHAR(50),
+    ADD COLUMN pref_email_notifications BOOLEAN DEFAULT true,
+    ADD COLUMN pref_push_notifications BOOLEAN DEFAULT true,
+    ALTER COLUMN username DROP NOT NULL,
+    ALTER COLUMN role DROP DEFAULT,
+    ALTER COLUMN role TYPE VARCHAR(50) USING role::text,
+    ALTER COLUMN role SET DEFAULT 'user';
+
+UPDATE users SET
+    name = COALESCE(NULLIF(full_name, ''), username),
+    profile_bio = bio,
+    profile_avatar = avatar_url;
+
+ALTER TABLE users ALTER COLUMN name SET NOT NULL;
+
+-- Recipes carry their ingredients, steps, tags and media as JSON. The
+-- status indexes are rebuilt around the type change, and the total time is
+-- written by the application like the prep and cook times.
+DROP INDEX IF EXISTS idx_recipes_published_at;
+DROP INDEX IF EXISTS idx_recipes_likes;
+DROP INDEX IF EXISTS idx_recipes_rating;
+DROP INDEX IF EXISTS idx_recipes_published_created;
+DROP INDEX IF EXISTS idx_recipes_scheduled_publish;
+
+ALTER TABLE recipes
+    ADD COLUMN ingredients JSONB,
+    ADD COLUMN instructions JSONB,
+    ADD COLUMN nutrition_info JSONB,
+    ADD COLUMN tags JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN images JSONB,
+    ADD COLUMN videos JSONB,
+    ALTER COLUMN cuisine TYPE VARCHAR(50) USING cuisine::text,
+    ALTER COLUMN category TYPE VARCHAR(50) USING category::text,
+    ALTER COLUMN difficulty TYPE VARCHAR(20) USING difficulty::text,
+    ALTER COLUMN status DROP DEFAULT,
+    ALTER COLUMN status TYPE VARCHAR(20) USING status::text,
+    ALTER COLUMN status SET DEFAULT 'draft',
+    ALTER COLUMN ai_model TYPE VARCHAR(100),
+    ALTER COLUMN total_time_minutes DROP EXPRESSION,
+    ALTER COLUMN total_time_minutes SET DEFAULT 0;
+
+CREATE INDEX idx_recipes_published_at ON recipes(published_at DESC) WHERE status = 'published' AND deleted_at IS NULL;
+CREATE INDEX idx_recipes_likes ON recipes(likes_count DESC) WHERE status = 'published' AND deleted_at IS NULL;
+CREATE INDEX idx_recipes_rating ON recipes(average_rating DESC, ratings_count DESC) WHERE status = 'published' AND deleted_at IS NULL;
+CREATE INDEX idx_recipes_published_created ON recipes(created_at DESC) WHERE status = 'published' AND deleted_at IS NULL;
+CREATE INDEX idx_recipes_scheduled_publish ON recipes(scheduled_publish_at)
+    WHERE scheduled_publish_at IS NOT NULL AND status = 'draft';
+
+ALTER TABLE ingredients ALTER COLUMN unit TYPE VARCHAR(20) USING unit::text;
+ALTER TABLE instructions ALTER COLUMN temperature_unit TYPE VARCHAR(10) USING temperature_unit::text;
+
+-- Existing recipes keep the ingredients, steps and tags stored in their
+-- own tables, in the shape the repository writes
+UPDATE recipes SET
+    ingredients = jsonb_build_object('data', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object(
+            'id', i.id,
+            'name', i.name,
+            'amount', COALESCE(i.amount, 0),
+            'unit', COALESCE(i.unit, ''),
+            'optional', COALESCE(i.optional, false),
+            'notes', COALESCE(i.notes, '')
+        ) ORDER BY i.order_index)
+        FROM ingredients i WHERE i.recipe_id = recipes.id), '[]'::jsonb)),
+    instructions = jsonb_build_object('data', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object(
+            'step_number', s.step_number,
+            'description', s.description,
+            'duration', COALESCE(s.duration_minutes, 0),
+            'temperature', CASE WHEN s.temperature_value IS NULL THEN NULL
+                ELSE jsonb_build_object('value', s.temperature_value, 'unit', s.temperature_unit) END,
+            'images', '[]'::jsonb
+        ) ORDER BY s.step_number)
+        FROM instructions s WHERE s.recipe_id = recipes.id), '[]'::jsonb)),
+    tags = COALESCE((
+        SELECT jsonb_agg(t.tag ORDER BY t.created_at)
+        FROM recipe_tags t WHERE t.recipe_id = recipes.id), '[]'::jsonb);
+
+-- One rating per row, averaged by the application
+CREATE TABLE ratings (
+    id UUID PRIMARY KEY,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    value INTEGER NOT NULL CHECK (value >= 1 AND value <= 5),
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ratings_recipe_id ON ratings(recipe_id);
+CREATE INDEX idx_ratings_user_id ON ratings(user_id);
+
+-- Requests made to the AI providers, with their cost
+CREATE TABLE ai_requests (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    prompt TEXT NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    parameters JSONB,
+    status VARCHAR(20) DEFAULT 'pending',
+    response JSONB,
+    tokens_used INTEGER DEFAULT 0,
+    cost_cents INTEGER DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    error_message TEXT
+);
+
+CREATE INDEX idx_ai_requests_user_id ON ai_requests(user_id);
+CREATE INDEX idx_ai_requests_status ON ai_requests(status);
+CREATE INDEX idx_ai_requests_created_at ON ai_requests(created_at);
+
+-- The activity feed: what an actor did that a user should hear about
+CREATE TABLE activities (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    data JSONB,
+    is_read BOOLEAN DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_activities_user_id ON activities(user_id);
+CREATE INDEX idx_activities_actor_id ON activities(actor_id);
+CREATE INDEX idx_activities_type ON activities(type);
+CREATE INDEX idx_activities_is_read ON activities(is_read);
+CREATE INDEX idx_activities_created_at ON activities(created_at);
+
+-- Signed-in devices of the cmd/app prototype. token is the SHA-256 of the
+-- current refresh token and previous_token the one it replaced.
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    token TEXT NOT NULL UNIQUE,
+    previous_token TEXT,
+    user_agent TEXT,
+    expires_at TIMESTAMPTZ NOT NULL,
+    rotated_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_previous_token ON sessions(previous_token);
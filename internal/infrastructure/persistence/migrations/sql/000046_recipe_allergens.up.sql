@@ -0,0 +1,5 @@
+-- The major allergens a recipe's ingredients contain or may contain,
+-- written with every save of the recipe. Recipes saved before this are
+-- left NULL here and labelled from their ingredients by the application
+-- on its next start.
+ALTER TABLE recipes ADD COLUMN allergens JSONB;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS duplicate_flags;
+DROP TABLE IF EXISTS duplicate_checks;
@@ -0,0 +1,36 @@
+-- Publishing queues each recipe for the duplicate-check job, which compares
+-- it with the nearest published recipe by another author.
+CREATE TABLE duplicate_checks (
+    recipe_id UUID PRIMARY KEY REFERENCES recipes(id) ON DELETE CASCADE,
+    queued_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_duplicate_checks_queued_at ON duplicate_checks(queued_at);
+
+-- Near copies the check found, with the similarity and the ingredients and
+-- steps the two recipes shared when it ran. archived marks the flags whose
+-- recipe the check took down at once. The author may appeal an upheld flag
+-- once; another admin decides.
+CREATE TABLE duplicate_flags (
+    id UUID PRIMARY KEY,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    original_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    similarity DOUBLE PRECISION NOT NULL,
+    shared_ingredients JSONB,
+    shared_steps JSONB,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'upheld', 'dismissed')),
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolution TEXT NOT NULL DEFAULT '',
+    resolved_at TIMESTAMPTZ,
+    appeal VARCHAR(20) NOT NULL DEFAULT '' CHECK (appeal IN ('', 'pending', 'granted', 'denied')),
+    appeal_note TEXT NOT NULL DEFAULT '',
+    appealed_at TIMESTAMPTZ,
+    appeal_decided_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    appeal_decided_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_duplicate_flags_recipe_original ON duplicate_flags(recipe_id, original_id);
+CREATE INDEX idx_duplicate_flags_status_created ON duplicate_flags(status, created_at);
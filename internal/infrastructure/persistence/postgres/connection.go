@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/pkg/sqlsafe"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -15,6 +16,13 @@ import (
 	"gorm.io/plugin/dbresolver"
 )
 
+// The prepared statement cache is bounded so ad-hoc queries cannot grow it
+// without limit; idle statements are evicted after preparedStmtTTL
+const (
+	preparedStmtCacheSize = 500
+	preparedStmtTTL       = time.Hour
+)
+
 // ConnectionManager manages PostgreSQL database connections with optimized pooling
 type ConnectionManager struct {
 	config         *config.Config
@@ -148,6 +156,8 @@ func (cm *ConnectionManager) initializePrimaryConnection(config *ConnectionConfi
 		Logger:                                   gormLogger,
 		SkipDefaultTransaction:                   true, // Improve performance
 		PrepareStmt:                              true, // Enable prepared statements
+		PrepareStmtMaxSize:                       preparedStmtCacheSize,
+		PrepareStmtTTL:                           preparedStmtTTL,
 		DisableForeignKeyConstraintWhenMigrating: false,
 	})
 	if err != nil {
@@ -181,6 +191,9 @@ func (cm *ConnectionManager) initializePrimaryConnection(config *ConnectionConfi
 	if err := cm.installQueryMonitoring(); err != nil {
 		cm.logger.Warn("Failed to install query monitoring", zap.Error(err))
 	}
+	if err := db.Use(sqlsafe.NewAuditor(cm.logger)); err != nil {
+		cm.logger.Warn("Failed to install query audit", zap.Error(err))
+	}
 
 	return nil
 }
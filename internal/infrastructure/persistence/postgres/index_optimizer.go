@@ -298,8 +298,8 @@ func (io *IndexOptimizer) findUnusedIndexes(ctx context.Context) ([]UnusedIndex,
 			idx_scan
 		FROM pg_stat_user_indexes
 		WHERE schemaname = 'public'
-		AND idx_scan < 10  -- Rarely used
-		AND indexname NOT LIKE '%_pkey'  -- Exclude primary keys
+		AND idx_scan < 10
+		AND indexname NOT LIKE '%_pkey'
 		ORDER BY index_size DESC`
 
 	rows, err := io.db.Raw(query).Rows()
@@ -46,6 +46,39 @@ func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User
 	return nil, nil
 }
 
+// FindByIDs retrieves multiple users by ID in a single round trip
+func (r *UserRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*user.User, error) {
+	query := `SELECT id, name, email, password_hash, is_active, is_verified, role, created_at, updated_at, last_login_at FROM users WHERE id = ANY($1)`
+
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		r.logger.Error("Failed to find users by IDs",
+			zap.Int("count", len(ids)),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*user.User, 0, len(ids))
+	for rows.Next() {
+		var id uuid.UUID
+		var name, email, passwordHash string
+		var isActive, isVerified bool
+		var role user.UserRole
+		var createdAt, updatedAt time.Time
+		var lastLoginAt *time.Time
+
+		if err := rows.Scan(&id, &name, &email, &passwordHash, &isActive, &isVerified, &role, &createdAt, &updatedAt, &lastLoginAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+
+		users = append(users, user.ReconstructUser(id, email, name, passwordHash, isActive, isVerified, role, createdAt, updatedAt, lastLoginAt))
+	}
+
+	return users, rows.Err()
+}
+
 // GetByEmail retrieves a user by email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	// Implementation would go here
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	gormModels "github.com/alchemorsel/v3/internal/infrastructure/persistence/gorm"
+	"github.com/alchemorsel/v3/pkg/lease"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -37,6 +38,63 @@ func SetupDatabase(dbPath string, logLevel logger.LogLevel) (*gorm.DB, error) {
 		&gormModels.CommentModel{},
 		&gormModels.ActivityModel{},
 		&gormModels.RecipeViewModel{},
+		&gormModels.RecipeAnnouncementModel{},
+		&gormModels.CommentReplyTokenModel{},
+		&gormModels.EmailSuppressionModel{},
+		&gormModels.PasswordResetTokenModel{},
+		&gormModels.RecipeClipModel{},
+		&gormModels.GuestSessionModel{},
+		&gormModels.GuestRecipeModel{},
+		&gormModels.ImageModel{},
+		&gormModels.IngredientNutritionModel{},
+		&gormModels.IngredientSubstituteModel{},
+		&gormModels.FavoriteModel{},
+		&gormModels.NotificationModel{},
+		&gormModels.ShoppingListModel{},
+		&gormModels.ShoppingListMemberModel{},
+		&gormModels.ShoppingListItemModel{},
+		&gormModels.ShoppingListChangeModel{},
+		&gormModels.ProfileCaptureModel{},
+		&gormModels.BrowseTagCountModel{},
+		&gormModels.BrowseCuisineCountModel{},
+		&gormModels.BrowseTopRatedModel{},
+		&gormModels.RecipePopularityModel{},
+		&gormModels.RecipeRankingModel{},
+		&gormModels.RecipeDailyViewModel{},
+		&gormModels.RecipeDailyImpressionModel{},
+		&gormModels.HiddenGemModel{},
+		&gormModels.ArchivePartitionModel{},
+		&gormModels.RecipeCounterShardModel{},
+		&gormModels.RecipeGraphNodeModel{},
+		&gormModels.RecipeGraphEdgeModel{},
+		&gormModels.TechniqueModel{},
+		&gormModels.RecipeStepTechniqueModel{},
+		&gormModels.RecipeTranslationModel{},
+		&gormModels.BattleModel{},
+		&gormModels.BattleVoteModel{},
+		&gormModels.RecipeChangeModel{},
+		&gormModels.SyncRecordModel{},
+		&gormModels.SyncCounterModel{},
+		&gormModels.SyncDeviceModel{},
+		&gormModels.PantryItemModel{},
+		&gormModels.CookLogModel{},
+		&gormModels.PantryConsumptionModel{},
+		&gormModels.UploadScanModel{},
+		&gormModels.VerificationClaimModel{},
+		&gormModels.VerificationReportModel{},
+		&gormModels.CommentReactionModel{},
+		&gormModels.ReviewVoteModel{},
+		&gormModels.RecipeDiscussionModel{},
+		&gormModels.CommentBlockModel{},
+		&gormModels.CommentModerationEventModel{},
+		&gormModels.CommentFlagModel{},
+		&gormModels.ReportModel{},
+		&gormModels.DuplicateCheckModel{},
+		&gormModels.DuplicateFlagModel{},
+		&gormModels.RecipeEmbeddingModel{},
+		&gormModels.AccountDeletionModel{},
+		&gormModels.AccountAuditEventModel{},
+		&lease.Record{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
@@ -103,8 +161,8 @@ func SeedDatabase(db *gorm.DB) error {
 	}
 
 	// Create users
-	for _, user := range demoUsers {
-		if err := db.Create(&user).Error; err != nil {
+	for i := range demoUsers {
+		if err := db.Create(&demoUsers[i]).Error; err != nil {
 			return fmt.Errorf("failed to create demo user: %w", err)
 		}
 	}
@@ -327,8 +385,8 @@ func SeedDatabase(db *gorm.DB) error {
 	}
 
 	// Create recipes
-	for _, recipe := range demoRecipes {
-		if err := db.Create(&recipe).Error; err != nil {
+	for i := range demoRecipes {
+		if err := db.Create(&demoRecipes[i]).Error; err != nil {
 			return fmt.Errorf("failed to create demo recipe: %w", err)
 		}
 	}
@@ -389,8 +447,8 @@ func SeedDatabase(db *gorm.DB) error {
 		},
 	}
 	
-	for _, collection := range collections {
-		if err := db.Create(&collection).Error; err != nil {
+	for i := range collections {
+		if err := db.Create(&collections[i]).Error; err != nil {
 			return fmt.Errorf("failed to create collection: %w", err)
 		}
 	}
@@ -514,5 +572,70 @@ func SeedDatabase(db *gorm.DB) error {
 		}
 	}
 
+	// Create demo techniques; steps link to them once their author asks for
+	// suggestions
+	techniques := []gormModels.TechniqueModel{
+		{
+			Slug:    "julienne",
+			Name:    "Julienne",
+			Summary: "Cut vegetables into thin, even matchsticks so they cook quickly and evenly.",
+			Steps: gormModels.StringSlice{
+				"Square off the vegetable so it sits flat on the board.",
+				"Slice it lengthwise into planks about 3 mm thick.",
+				"Stack the planks and cut them into 3 mm matchsticks.",
+			},
+			Aliases: gormModels.StringSlice{"matchsticks", "julienned"},
+		},
+		{
+			Slug:    "proofing-dough",
+			Name:    "Proofing dough",
+			Summary: "Let shaped yeast dough rise until it has nearly doubled and springs back slowly when pressed.",
+			Steps: gormModels.StringSlice{
+				"Cover the dough so the surface does not dry out.",
+				"Leave it somewhere warm and draft-free.",
+				"Press it gently with a floured finger: a slow spring back means it is ready.",
+			},
+			Aliases: gormModels.StringSlice{"proof", "let rise", "rise until doubled"},
+		},
+		{
+			Slug:    "roasting",
+			Name:    "Roasting",
+			Summary: "Cook in a hot oven with dry heat until the outside browns and caramelizes.",
+			Steps: gormModels.StringSlice{
+				"Cut pieces to the same size and toss them lightly in oil.",
+				"Spread them out on the tray so they do not steam.",
+				"Turn them halfway through for even browning.",
+			},
+			Aliases: gormModels.StringSlice{"roast", "roasted"},
+		},
+		{
+			Slug:    "marinating",
+			Name:    "Marinating",
+			Summary: "Soak food in a seasoned liquid to flavor and tenderize it before cooking.",
+			Steps: gormModels.StringSlice{
+				"Coat the food completely in the marinade.",
+				"Cover and refrigerate for the time the recipe gives.",
+				"Pat dry before cooking so it browns.",
+			},
+			Aliases: gormModels.StringSlice{"marinate", "marinade"},
+		},
+		{
+			Slug:    "whisking",
+			Name:    "Whisking",
+			Summary: "Beat ingredients with a whisk to combine them smoothly or work in air.",
+			Steps: gormModels.StringSlice{
+				"Steady the bowl on a damp towel.",
+				"Move the whisk in quick loops, lifting through the mixture.",
+			},
+			Aliases: gormModels.StringSlice{"whisk", "whisked"},
+		},
+	}
+
+	for i := range techniques {
+		if err := db.Create(&techniques[i]).Error; err != nil {
+			return fmt.Errorf("failed to create technique: %w", err)
+		}
+	}
+
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,68 @@
+// Package profiling captures runtime profiles of the running process
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/pprof"
+	"runtime/trace"
+	"time"
+)
+
+// Profile kinds
+const (
+	KindCPU       = "cpu"
+	KindHeap      = "heap"
+	KindGoroutine = "goroutine"
+	KindTrace     = "trace"
+)
+
+// Runtime captures profiles with runtime/pprof and runtime/trace. Only one
+// CPU profile or trace can run per process, so a second concurrent capture
+// of the same kind fails.
+type Runtime struct{}
+
+// NewRuntime creates a runtime profiler
+func NewRuntime() *Runtime {
+	return &Runtime{}
+}
+
+// Kinds lists the supported profile kinds
+func (p *Runtime) Kinds() []string {
+	return []string{KindCPU, KindHeap, KindGoroutine, KindTrace}
+}
+
+// Capture writes the profile to w. CPU profiles and traces stop early when
+// ctx is cancelled and keep what was sampled so far.
+func (p *Runtime) Capture(ctx context.Context, kind string, duration time.Duration, w io.Writer) error {
+	switch kind {
+	case KindCPU:
+		if err := pprof.StartCPUProfile(w); err != nil {
+			return err
+		}
+		sleep(ctx, duration)
+		pprof.StopCPUProfile()
+		return nil
+	case KindTrace:
+		if err := trace.Start(w); err != nil {
+			return err
+		}
+		sleep(ctx, duration)
+		trace.Stop()
+		return nil
+	case KindHeap, KindGoroutine:
+		return pprof.Lookup(kind).WriteTo(w, 0)
+	default:
+		return fmt.Errorf("unsupported profile kind %q", kind)
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
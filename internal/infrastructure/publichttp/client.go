@@ -0,0 +1,48 @@
+// Package publichttp builds HTTP clients for fetching addresses that users
+// supply, such as recipe pages and image URLs. The clients only dial public
+// addresses, so neither a URL nor a redirect from one can reach services
+// on loopback or private networks.
+package publichttp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// dialTimeout bounds connecting to a host, within the client's timeout
+const dialTimeout = 5 * time.Second
+
+// ErrPrivateAddress is returned for addresses that resolve to loopback,
+// private, link-local or unspecified networks
+var ErrPrivateAddress = errors.New("the address points to a private network")
+
+// NewClient creates a client that refuses private network addresses and
+// gives up on a request after timeout. The address is checked after DNS
+// resolution, for every connection, so redirects and rebinding are covered.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !public(net.ParseIP(host)) {
+				return ErrPrivateAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+	}
+}
+
+// public reports whether ip may be dialled
+func public(ip net.IP) bool {
+	return ip != nil && !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsUnspecified()
+}
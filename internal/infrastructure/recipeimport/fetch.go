@@ -0,0 +1,130 @@
+package recipeimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/publichttp"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// maxPageBytes bounds a fetched page at 5 MB, room for recipe pages that
+// inline their scripts, styles and images
+const maxPageBytes = 5 << 20
+
+// maxRedirects bounds how many redirects a page fetch follows
+const maxRedirects = 5
+
+var (
+	// ErrPrivateAddress is returned for pages on loopback or private
+	// networks
+	ErrPrivateAddress = publichttp.ErrPrivateAddress
+	// ErrNotHTML is returned for responses that are not web pages
+	ErrNotHTML = errors.New("the address is not a web page")
+	// ErrPageUnavailable is returned when the page cannot be fetched
+	ErrPageUnavailable = errors.New("the page could not be fetched")
+)
+
+// PageReader fetches recipe pages over HTTP for import from a URL
+type PageReader struct {
+	client *http.Client
+	logger *zap.Logger
+}
+
+// NewPageReader creates a page reader that refuses private network
+// addresses, including ones reached through redirects
+func NewPageReader(logger *zap.Logger) outbound.RecipePageReader {
+	client := publichttp.NewClient(15 * time.Second)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+
+	return &PageReader{
+		client: client,
+		logger: logger.Named("recipe-page"),
+	}
+}
+
+// ReadRecipePage fetches the page and reads its recipe with FromHTML
+func (p *PageReader) ReadRecipePage(ctx context.Context, pageURL string) (*outbound.RecipePage, error) {
+	page, finalURL, err := p.fetch(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe, method, err := FromHTML(finalURL, page)
+	if err != nil {
+		return nil, ErrNoRecipeOnPage
+	}
+	return &outbound.RecipePage{
+		URL:         finalURL,
+		Method:      method,
+		Title:       recipe.Title,
+		Description: recipe.Description,
+		Ingredients: recipe.Ingredients,
+		Directions:  recipe.Directions,
+		Servings:    recipe.Servings,
+		PrepTime:    recipe.PrepTime,
+		CookTime:    recipe.CookTime,
+		Tags:        recipe.Tags,
+	}, nil
+}
+
+// fetch downloads an HTML page, returning its body and address after
+// redirects
+func (p *PageReader) fetch(ctx context.Context, pageURL string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "Alchemorsel recipe importer")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrPrivateAddress) {
+			return nil, "", ErrPrivateAddress
+		}
+		p.logger.Info("Recipe page fetch failed", zap.String("url", pageURL), zap.Error(err))
+		return nil, "", ErrPageUnavailable
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%w: the site answered %s", ErrPageUnavailable, resp.Status)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "" &&
+		mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, "", ErrNotHTML
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxPageBytes+1))
+	if err != nil {
+		return nil, "", ErrPageUnavailable
+	}
+	if len(page) > maxPageBytes {
+		return nil, "", fmt.Errorf("the page is larger than %d MB", maxPageBytes>>20)
+	}
+	return page, finalURL(resp.Request.URL, pageURL), nil
+}
+
+// finalURL is the address the page was served from, without any fragment
+func finalURL(served *url.URL, requested string) string {
+	if served == nil {
+		return requested
+	}
+	u := *served
+	u.Fragment = ""
+	return strings.TrimSpace(u.String())
+}
@@ -0,0 +1,241 @@
+package recipeimport
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Ways a recipe was read from a page
+const (
+	MethodSchemaOrg = "schema.org"
+	MethodMicrodata = "microdata"
+	MethodHeuristic = "heuristic"
+)
+
+// ErrNoRecipeOnPage is returned when a page has neither schema.org recipe
+// data nor an ingredients or method section
+var ErrNoRecipeOnPage = errors.New("no recipe found on the page")
+
+var (
+	ingredientsHeading = regexp.MustCompile(`(?i)^ingredients?\s*:?$`)
+	stepsHeading       = regexp.MustCompile(`(?i)^(?:instructions|directions|method|steps|preparation)\s*:?$`)
+	// Recipe card plugins mark each line with a class such as
+	// wprm-recipe-ingredient or tasty-recipes-instructions
+	ingredientClass = regexp.MustCompile(`(?i)ingredient`)
+	stepClass       = regexp.MustCompile(`(?i)instruction|direction|step`)
+)
+
+// FromHTML reads one recipe from a web page or a snippet of one. JSON-LD
+// schema.org Recipe data is preferred, then schema.org microdata; without
+// either the title, ingredients and steps are read from the page's
+// headings and lists. The method used is returned with the recipe.
+func FromHTML(pageURL string, page []byte) (inbound.ImportedRecipe, string, error) {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return inbound.ImportedRecipe{}, "", err
+	}
+
+	recipe, method := fromJSONLD(doc), MethodSchemaOrg
+	if recipe == nil {
+		recipe, method = fromMicrodata(doc), MethodMicrodata
+	}
+	if recipe == nil {
+		recipe, method = fromLayout(doc), MethodHeuristic
+	}
+	if recipe == nil {
+		return inbound.ImportedRecipe{}, "", ErrNoRecipeOnPage
+	}
+	if recipe.SourceURL == "" {
+		recipe.SourceURL = pageURL
+	}
+	return *recipe, method, nil
+}
+
+// fromJSONLD returns the first recipe in the page's JSON-LD blocks
+func fromJSONLD(doc *html.Node) *inbound.ImportedRecipe {
+	for _, script := range findAll(doc, func(n *html.Node) bool {
+		return n.DataAtom == atom.Script && strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json")
+	}) {
+		recipes, err := decodeSchemaJSON([]byte(textOf(script, false)))
+		if err == nil && len(recipes) > 0 {
+			return &recipes[0]
+		}
+	}
+	return nil
+}
+
+// fromLayout reads a recipe from the page layout: lines carrying recipe
+// card classes, or else the lists under "Ingredients" and "Method"
+// headings. A recipe needs a title and ingredients or steps.
+func fromLayout(doc *html.Node) *inbound.ImportedRecipe {
+	recipe := inbound.ImportedRecipe{
+		Title:       pageTitle(doc),
+		Description: meta(doc, "description", "og:description"),
+	}
+
+	recipe.Ingredients = classedLines(doc, ingredientClass)
+	recipe.Directions = classedLines(doc, stepClass)
+
+	nodes := findAll(doc, func(n *html.Node) bool { return n.Type == html.ElementNode })
+	if len(recipe.Ingredients) == 0 {
+		recipe.Ingredients = sectionLines(nodes, ingredientsHeading, false)
+	}
+	if len(recipe.Directions) == 0 {
+		recipe.Directions = sectionLines(nodes, stepsHeading, true)
+	}
+
+	if recipe.Title == "" || (len(recipe.Ingredients) == 0 && len(recipe.Directions) == 0) {
+		return nil
+	}
+	return &recipe
+}
+
+// pageTitle is the first h1, else og:title, else the <title>
+func pageTitle(doc *html.Node) string {
+	if h1 := findAll(doc, func(n *html.Node) bool { return n.DataAtom == atom.H1 }); len(h1) > 0 {
+		if title := textOf(h1[0], true); title != "" {
+			return title
+		}
+	}
+	if title := meta(doc, "og:title"); title != "" {
+		return title
+	}
+	if title := findAll(doc, func(n *html.Node) bool { return n.DataAtom == atom.Title }); len(title) > 0 {
+		return textOf(title[0], true)
+	}
+	return ""
+}
+
+// meta returns the content of the first <meta> with one of the names or
+// properties
+func meta(doc *html.Node, names ...string) string {
+	for _, name := range names {
+		for _, m := range findAll(doc, func(n *html.Node) bool { return n.DataAtom == atom.Meta }) {
+			if strings.EqualFold(attr(m, "name"), name) || strings.EqualFold(attr(m, "property"), name) {
+				if content := strings.TrimSpace(attr(m, "content")); content != "" {
+					return content
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// classedLines reads the list items whose class matches
+func classedLines(doc *html.Node, class *regexp.Regexp) []string {
+	var lines []string
+	for _, li := range findAll(doc, func(n *html.Node) bool {
+		return n.DataAtom == atom.Li && class.MatchString(attr(n, "class"))
+	}) {
+		if text := textOf(li, true); text != "" {
+			lines = append(lines, text)
+		}
+	}
+	return lines
+}
+
+// sectionLines finds a heading matching the pattern and reads the first
+// list after it. Steps written as paragraphs are read up to the next
+// heading when paragraphs is set.
+func sectionLines(nodes []*html.Node, heading *regexp.Regexp, paragraphs bool) []string {
+	for i, n := range nodes {
+		if !isHeading(n) || !heading.MatchString(textOf(n, true)) {
+			continue
+		}
+
+		var lines, paras []string
+		for _, next := range nodes[i+1:] {
+			if isHeading(next) {
+				break
+			}
+			switch next.DataAtom {
+			case atom.Ul, atom.Ol:
+				for c := next.FirstChild; c != nil; c = c.NextSibling {
+					if c.DataAtom == atom.Li {
+						if text := textOf(c, true); text != "" {
+							lines = append(lines, text)
+						}
+					}
+				}
+				return lines
+			case atom.P:
+				if text := textOf(next, true); text != "" {
+					paras = append(paras, text)
+				}
+			}
+		}
+		if paragraphs && len(paras) > 0 {
+			return paras
+		}
+	}
+	return nil
+}
+
+// isHeading reports whether an element titles a section: a heading, or a
+// bold line on its own such as <p><strong>Ingredients</strong></p>
+func isHeading(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return true
+	case atom.Strong, atom.B:
+		return n.Parent != nil && textOf(n, true) == textOf(n.Parent, true)
+	}
+	return false
+}
+
+// findAll lists the nodes under n that match, in document order
+func findAll(n *html.Node, match func(*html.Node) bool) []*html.Node {
+	var found []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if match(n) {
+			found = append(found, n)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return found
+}
+
+// textOf joins the text under n. With collapse, as for display, scripts
+// and styles are skipped and whitespace is collapsed; without it a
+// script's body comes back as written.
+func textOf(n *html.Node, collapse bool) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+			return
+		}
+		if collapse && (n.DataAtom == atom.Script || n.DataAtom == atom.Style) {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	if !collapse {
+		return b.String()
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,166 @@
+package recipeimport
+
+import (
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// fromMicrodata reads the first schema.org Recipe marked up with itemscope
+// and itemprop attributes, as older recipe plugins write it
+func fromMicrodata(doc *html.Node) *inbound.ImportedRecipe {
+	scopes := findAll(doc, func(n *html.Node) bool {
+		return hasAttr(n, "itemscope") && isRecipeType(attr(n, "itemtype"))
+	})
+	if len(scopes) == 0 {
+		return nil
+	}
+	props := itemProps(scopes[0])
+
+	recipe := inbound.ImportedRecipe{
+		Title:       firstValue(props, "name"),
+		Description: firstValue(props, "description"),
+		Servings:    parseServings(firstValue(props, "recipeYield")),
+		PrepTime:    parseMinutes(firstValue(props, "prepTime")),
+		CookTime:    parseMinutes(firstValue(props, "cookTime")),
+		SourceURL:   firstValue(props, "url"),
+	}
+	if recipe.PrepTime == 0 && recipe.CookTime == 0 {
+		recipe.CookTime = parseMinutes(firstValue(props, "totalTime"))
+	}
+
+	for _, name := range []string{"recipeIngredient", "ingredients"} {
+		for _, n := range props[name] {
+			if line := itemValue(n); line != "" {
+				recipe.Ingredients = append(recipe.Ingredients, line)
+			}
+		}
+	}
+	for _, n := range props["recipeInstructions"] {
+		recipe.Directions = append(recipe.Directions, microdataSteps(n)...)
+	}
+	for _, name := range []string{"recipeCategory", "keywords"} {
+		for _, n := range props[name] {
+			for _, tag := range strings.Split(itemValue(n), ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					recipe.Tags = append(recipe.Tags, tag)
+				}
+			}
+		}
+	}
+
+	if recipe.Title == "" && len(recipe.Ingredients) == 0 {
+		return nil
+	}
+	return &recipe
+}
+
+// isRecipeType matches itemtype values such as https://schema.org/Recipe;
+// an item may list several types
+func isRecipeType(itemtype string) bool {
+	for _, t := range strings.Fields(itemtype) {
+		if strings.HasSuffix(strings.ToLower(strings.TrimRight(t, "/")), "schema.org/recipe") {
+			return true
+		}
+	}
+	return false
+}
+
+// itemProps collects the properties of an item by name. Properties of
+// nested items belong to those items and are left out.
+func itemProps(scope *html.Node) map[string][]*html.Node {
+	props := make(map[string][]*html.Node)
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			for _, name := range strings.Fields(attr(c, "itemprop")) {
+				props[name] = append(props[name], c)
+			}
+			if !hasAttr(c, "itemscope") {
+				walk(c)
+			}
+		}
+	}
+	walk(scope)
+	return props
+}
+
+func firstValue(props map[string][]*html.Node, name string) string {
+	for _, n := range props[name] {
+		if value := itemValue(n); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// itemValue is a property's value: the attribute microdata reads for
+// meta, links, media and time elements, otherwise the element's text
+func itemValue(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.Meta:
+		return strings.TrimSpace(attr(n, "content"))
+	case atom.A, atom.Link, atom.Area:
+		return strings.TrimSpace(attr(n, "href"))
+	case atom.Img, atom.Audio, atom.Video, atom.Source, atom.Iframe, atom.Embed:
+		return strings.TrimSpace(attr(n, "src"))
+	case atom.Time:
+		if datetime := strings.TrimSpace(attr(n, "datetime")); datetime != "" {
+			return datetime
+		}
+	case atom.Data, atom.Meter:
+		return strings.TrimSpace(attr(n, "value"))
+	}
+	if content := strings.TrimSpace(attr(n, "content")); content != "" {
+		return content
+	}
+	return textOf(n, true)
+}
+
+// microdataSteps reads recipeInstructions written as a HowToStep or
+// HowToSection item, a list, paragraphs, or a single block of text
+func microdataSteps(n *html.Node) []string {
+	if hasAttr(n, "itemscope") {
+		props := itemProps(n)
+		if items := props["itemListElement"]; len(items) > 0 {
+			var steps []string
+			for _, item := range items {
+				steps = append(steps, microdataSteps(item)...)
+			}
+			return steps
+		}
+		if text := firstValue(props, "text"); text != "" {
+			return []string{text}
+		}
+	}
+
+	for _, block := range []atom.Atom{atom.Li, atom.P} {
+		var steps []string
+		for _, c := range findAll(n, func(c *html.Node) bool { return c != n && c.DataAtom == block }) {
+			if text := textOf(c, true); text != "" {
+				steps = append(steps, text)
+			}
+		}
+		if len(steps) > 0 {
+			return steps
+		}
+	}
+	if text := itemValue(n); text != "" {
+		return []string{text}
+	}
+	return nil
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
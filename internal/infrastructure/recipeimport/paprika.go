@@ -0,0 +1,96 @@
+package recipeimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+)
+
+// paprikaRecipe is one entry of a .paprikarecipes export. Ingredients and
+// directions are newline separated blocks.
+type paprikaRecipe struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Ingredients string     `json:"ingredients"`
+	Directions  string     `json:"directions"`
+	Notes       string     `json:"notes"`
+	Servings    flexString `json:"servings"`
+	PrepTime    string     `json:"prep_time"`
+	CookTime    string     `json:"cook_time"`
+	Categories  []string   `json:"categories"`
+	SourceURL   string     `json:"source_url"`
+}
+
+// decodePaprika reads a .paprikarecipes archive (a zip of gzipped JSON
+// files) or a single gzipped .paprikarecipe
+func decodePaprika(data []byte) ([]inbound.ImportedRecipe, error) {
+	if !isZip(data) {
+		r, err := decodePaprikaEntry(data)
+		if err != nil {
+			return nil, err
+		}
+		return []inbound.ImportedRecipe{r}, nil
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid paprika archive: %w", err)
+	}
+
+	var recipes []inbound.ImportedRecipe
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.HasSuffix(strings.ToLower(f.Name), ".paprikarecipe") {
+			continue
+		}
+		raw, err := readEntry(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		r, err := decodePaprikaEntry(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		recipes = append(recipes, r)
+	}
+	return recipes, nil
+}
+
+func decodePaprikaEntry(raw []byte) (inbound.ImportedRecipe, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return inbound.ImportedRecipe{}, fmt.Errorf("invalid paprika recipe: %w", err)
+	}
+	defer gz.Close()
+
+	data, err := readLimited(gz)
+	if err != nil {
+		return inbound.ImportedRecipe{}, err
+	}
+
+	var p paprikaRecipe
+	if err := json.Unmarshal(data, &p); err != nil {
+		return inbound.ImportedRecipe{}, fmt.Errorf("invalid paprika recipe: %w", err)
+	}
+
+	description := p.Description
+	if notes := strings.TrimSpace(p.Notes); notes != "" {
+		description = strings.TrimSpace(description + "\n\nNotes: " + notes)
+	}
+
+	return inbound.ImportedRecipe{
+		Title:       strings.TrimSpace(p.Name),
+		Description: description,
+		Ingredients: splitLines(p.Ingredients),
+		Directions:  splitLines(p.Directions),
+		Servings:    parseServings(string(p.Servings)),
+		PrepTime:    parseMinutes(p.PrepTime),
+		CookTime:    parseMinutes(p.CookTime),
+		Tags:        p.Categories,
+		SourceURL:   p.SourceURL,
+	}, nil
+}
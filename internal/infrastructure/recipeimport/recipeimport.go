@@ -0,0 +1,219 @@
+// Package recipeimport decodes recipe manager exports (Paprika, Mealie,
+// Nextcloud Cookbook) and recipe web pages into format-neutral recipes, and
+// fetches pages for import by URL
+package recipeimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+)
+
+// Supported formats
+const (
+	FormatPaprika   = "paprika"
+	FormatMealie    = "mealie"
+	FormatNextcloud = "nextcloud"
+)
+
+// maxEntryBytes bounds one decompressed recipe file so a crafted archive
+// cannot exhaust memory
+const maxEntryBytes = 5 << 20
+
+var (
+	// ErrUnknownFormat is returned when the format cannot be detected
+	ErrUnknownFormat = errors.New("unrecognized export format")
+	// ErrNoRecipes is returned when an export holds no readable recipes
+	ErrNoRecipes = errors.New("no recipes found in export")
+)
+
+// Decode reads an export file. An empty format is detected from the file
+// name and contents.
+func Decode(format, filename string, data []byte) ([]inbound.ImportedRecipe, string, error) {
+	if format == "" {
+		format = Detect(filename, data)
+	}
+
+	var (
+		recipes []inbound.ImportedRecipe
+		err     error
+	)
+	switch format {
+	case FormatPaprika:
+		recipes, err = decodePaprika(data)
+	case FormatMealie, FormatNextcloud:
+		recipes, err = decodeSchemaExport(data)
+	default:
+		return nil, "", ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, format, err
+	}
+	if len(recipes) == 0 {
+		return nil, format, ErrNoRecipes
+	}
+	return recipes, format, nil
+}
+
+// Detect guesses the export format: Paprika archives by extension or entry
+// names, Nextcloud by its per-folder recipe.json, and any other JSON as Mealie
+func Detect(filename string, data []byte) string {
+	lower := strings.ToLower(filename)
+	if strings.HasSuffix(lower, ".paprikarecipes") || strings.HasSuffix(lower, ".paprikarecipe") {
+		return FormatPaprika
+	}
+
+	if isZip(data) {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return ""
+		}
+		format := ""
+		for _, f := range zr.File {
+			name := strings.ToLower(f.Name)
+			switch {
+			case strings.HasSuffix(name, ".paprikarecipe"):
+				return FormatPaprika
+			case path.Base(name) == "recipe.json":
+				format = FormatNextcloud
+			case format == "" && strings.HasSuffix(name, ".json"):
+				format = FormatMealie
+			}
+		}
+		return format
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		if bytes.Contains(trimmed, []byte(`"@context"`)) || bytes.Contains(trimmed, []byte(`"@type"`)) {
+			return FormatNextcloud
+		}
+		return FormatMealie
+	}
+	return ""
+}
+
+func isZip(data []byte) bool {
+	return len(data) > 4 && bytes.Equal(data[:4], []byte("PK\x03\x04"))
+}
+
+// readEntry reads one archive entry up to maxEntryBytes
+func readEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return readLimited(rc)
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxEntryBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxEntryBytes {
+		return nil, fmt.Errorf("recipe file larger than %d MB", maxEntryBytes>>20)
+	}
+	return data, nil
+}
+
+// splitLines turns a newline separated block into trimmed non-empty lines
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+var (
+	isoDuration  = regexp.MustCompile(`(?i)^P(?:(\d+)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	clockTime    = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+	textDuration = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(days?|d|hours?|hrs?|h|minutes?|mins?|m)\b`)
+	firstNumber  = regexp.MustCompile(`\d+`)
+)
+
+// parseMinutes reads durations written as ISO 8601 ("PT1H30M"), a clock
+// ("1:30") or text ("1 hr 10 mins", "45 minutes")
+func parseMinutes(s string) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+
+	if m := isoDuration.FindStringSubmatch(s); m != nil {
+		days, _ := strconv.ParseFloat(m[1], 64)
+		hours, _ := strconv.ParseFloat(m[2], 64)
+		minutes, _ := strconv.ParseFloat(m[3], 64)
+		seconds, _ := strconv.ParseFloat(m[4], 64)
+		return int(days*24*60 + hours*60 + minutes + seconds/60)
+	}
+	if m := clockTime.FindStringSubmatch(s); m != nil {
+		hours, _ := strconv.Atoi(m[1])
+		minutes, _ := strconv.Atoi(m[2])
+		return hours*60 + minutes
+	}
+
+	total := 0.0
+	for _, m := range textDuration.FindAllStringSubmatch(s, -1) {
+		value, _ := strconv.ParseFloat(m[1], 64)
+		switch unit := strings.ToLower(m[2]); {
+		case strings.HasPrefix(unit, "d"):
+			total += value * 24 * 60
+		case strings.HasPrefix(unit, "h"):
+			total += value * 60
+		default:
+			total += value
+		}
+	}
+	if total == 0 {
+		// A bare number is minutes
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+	}
+	return int(total)
+}
+
+// parseServings reads the first number of a yield such as "Serves 4-6"
+func parseServings(s string) int {
+	n, _ := strconv.Atoi(firstNumber.FindString(s))
+	return n
+}
+
+// flexString decodes JSON values that exports write as either a string or a
+// number ("recipeYield": 4 or "4 servings")
+type flexString string
+
+func (f *flexString) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*f = flexString(s)
+		return nil
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err == nil {
+		if len(list) == 0 {
+			*f = ""
+			return nil
+		}
+		return f.UnmarshalJSON(list[0])
+	}
+	*f = flexString(strings.Trim(string(data), `"`))
+	if *f == "null" {
+		*f = ""
+	}
+	return nil
+}
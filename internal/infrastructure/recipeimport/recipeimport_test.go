@@ -0,0 +1,254 @@
+package recipeimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// buildZip writes the given files into an in-memory zip archive
+func buildZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func gzipJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	require.NoError(t, json.NewEncoder(gz).Encode(v))
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestDecodePaprikaArchive(t *testing.T) {
+	archive := buildZip(t, map[string][]byte{
+		"Banana Bread.paprikarecipe": gzipJSON(t, map[string]interface{}{
+			"name":        "Banana Bread",
+			"ingredients": "3 ripe bananas\n2 cups flour\n\n1 tsp baking soda",
+			"directions":  "Mash the bananas.\nFold in the flour and soda.\nBake 1 hr at 350°F.",
+			"notes":       "Freezes well.",
+			"servings":    "1 loaf (10 slices)",
+			"prep_time":   "15 min",
+			"cook_time":   "1 hr 5 mins",
+			"categories":  []string{"Baking", "Breakfast"},
+			"source_url":  "https://example.com/banana-bread",
+			"photo_data":  "aGVsbG8=",
+		}),
+	})
+
+	recipes, format, err := Decode("", "My Recipes.paprikarecipes", archive)
+	require.NoError(t, err)
+	assert.Equal(t, FormatPaprika, format)
+	require.Len(t, recipes, 1)
+
+	r := recipes[0]
+	assert.Equal(t, "Banana Bread", r.Title)
+	assert.Equal(t, "Notes: Freezes well.", r.Description)
+	assert.Equal(t, []string{"3 ripe bananas", "2 cups flour", "1 tsp baking soda"}, r.Ingredients)
+	assert.Len(t, r.Directions, 3)
+	assert.Equal(t, 1, r.Servings)
+	assert.Equal(t, 15, r.PrepTime)
+	assert.Equal(t, 65, r.CookTime)
+	assert.Equal(t, []string{"Baking", "Breakfast"}, r.Tags)
+	assert.Equal(t, "https://example.com/banana-bread", r.SourceURL)
+}
+
+func TestDecodeMealieJSON(t *testing.T) {
+	data, err := os.ReadFile("testdata/mealie.json")
+	require.NoError(t, err)
+
+	recipes, format, err := Decode("", "chana.json", data)
+	require.NoError(t, err)
+	assert.Equal(t, FormatMealie, format)
+	require.Len(t, recipes, 1)
+
+	r := recipes[0]
+	assert.Equal(t, "Weeknight Chana Masala", r.Title)
+	assert.Equal(t, []string{
+		"Gravy:",
+		"2 tablespoon oil",
+		"1 onion, finely chopped",
+		"1 (14 oz) can crushed tomatoes",
+		"2 cans chickpeas, drained",
+	}, r.Ingredients)
+	assert.Len(t, r.Directions, 2)
+	assert.Equal(t, 4, r.Servings)
+	assert.Equal(t, 15, r.PrepTime)
+	assert.Equal(t, 25, r.CookTime)
+	assert.Equal(t, []string{"Dinner", "Vegan", "Indian"}, r.Tags)
+	assert.Equal(t, "https://example.com/chana-masala", r.SourceURL)
+}
+
+func TestDecodeNextcloudArchive(t *testing.T) {
+	data, err := os.ReadFile("testdata/nextcloud-recipe.json")
+	require.NoError(t, err)
+
+	archive := buildZip(t, map[string][]byte{
+		"Grandma's Apple Crumble/recipe.json": data,
+		"Grandma's Apple Crumble/full.jpg":    []byte("jpeg"),
+		"Other/notes.json":                    []byte(`{"not": "a recipe"}`),
+	})
+
+	recipes, format, err := Decode("", "cookbook.zip", archive)
+	require.NoError(t, err)
+	assert.Equal(t, FormatNextcloud, format)
+	require.Len(t, recipes, 1)
+
+	r := recipes[0]
+	assert.Equal(t, "Grandma's Apple Crumble", r.Title)
+	assert.Len(t, r.Ingredients, 4)
+	assert.Equal(t, []string{
+		"Heat the oven to 375°F.",
+		"Rub the butter into the oats and sugar.",
+		"Scatter over the apples and bake 45 minutes.",
+	}, r.Directions)
+	assert.Equal(t, 6, r.Servings)
+	assert.Equal(t, 20, r.PrepTime)
+	assert.Equal(t, 45, r.CookTime)
+	assert.Equal(t, []string{"Dessert", "apples", "baking", "autumn"}, r.Tags)
+}
+
+func TestDecodeRejectsUnknownAndEmpty(t *testing.T) {
+	_, _, err := Decode("", "recipes.txt", []byte("just some text"))
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+
+	_, _, err = Decode("", "empty.json", []byte(`[]`))
+	assert.ErrorIs(t, err, ErrNoRecipes)
+}
+
+func TestParseMinutes(t *testing.T) {
+	cases := map[string]int{
+		"PT1H30M":      90,
+		"PT0H20M0S":    20,
+		"P0DT2H":       120,
+		"1:15":         75,
+		"1 hr 10 mins": 70,
+		"45 minutes":   45,
+		"1.5 hours":    90,
+		"20":           20,
+		"overnight":    0,
+	}
+	for input, want := range cases {
+		assert.Equal(t, want, parseMinutes(input), input)
+	}
+}
+
+func TestFromHTMLPrefersJSONLD(t *testing.T) {
+	page := []byte(`<html><head><script type="application/ld+json">
+{"@context": "https://schema.org", "@graph": [
+  {"@type": "WebPage", "name": "Shakshuka | Example Kitchen"},
+  {"@type": "Recipe", "name": "Shakshuka", "recipeYield": "4 servings",
+   "recipeIngredient": ["6 eggs", "1 can crushed tomatoes"],
+   "recipeInstructions": [{"@type": "HowToStep", "text": "Simmer the tomatoes."}, {"@type": "HowToStep", "text": "Crack in the eggs."}]}
+]}</script></head><body><h1>Shakshuka | Example Kitchen</h1></body></html>`)
+
+	r, method, err := FromHTML("https://example.com/shakshuka", page)
+	require.NoError(t, err)
+	assert.Equal(t, MethodSchemaOrg, method)
+	assert.Equal(t, "Shakshuka", r.Title)
+	assert.Equal(t, []string{"6 eggs", "1 can crushed tomatoes"}, r.Ingredients)
+	assert.Equal(t, []string{"Simmer the tomatoes.", "Crack in the eggs."}, r.Directions)
+	assert.Equal(t, 4, r.Servings)
+	assert.Equal(t, "https://example.com/shakshuka", r.SourceURL)
+}
+
+func TestFromHTMLReadsMicrodata(t *testing.T) {
+	page := []byte(`<html><body><div itemscope itemtype="http://schema.org/Recipe">
+  <h1 itemprop="name">Lemon Posset</h1>
+  <meta itemprop="prepTime" content="PT10M"><time itemprop="cookTime" datetime="PT5M">5 minutes</time>
+  <span itemprop="recipeYield">Serves 4</span>
+  <div itemprop="author" itemscope itemtype="http://schema.org/Person"><span itemprop="name">Ada</span></div>
+  <ul><li itemprop="recipeIngredient">600 ml double cream</li><li itemprop="recipeIngredient">150 g caster sugar</li></ul>
+  <ol itemprop="recipeInstructions"><li>Boil the cream and sugar.</li><li>Stir in the lemon juice and chill.</li></ol>
+  <meta itemprop="keywords" content="dessert, easy">
+</div></body></html>`)
+
+	r, method, err := FromHTML("https://example.com/posset", page)
+	require.NoError(t, err)
+	assert.Equal(t, MethodMicrodata, method)
+	assert.Equal(t, "Lemon Posset", r.Title)
+	assert.Equal(t, []string{"600 ml double cream", "150 g caster sugar"}, r.Ingredients)
+	assert.Equal(t, []string{"Boil the cream and sugar.", "Stir in the lemon juice and chill."}, r.Directions)
+	assert.Equal(t, 4, r.Servings)
+	assert.Equal(t, 10, r.PrepTime)
+	assert.Equal(t, 5, r.CookTime)
+	assert.Equal(t, []string{"dessert", "easy"}, r.Tags)
+	assert.Equal(t, "https://example.com/posset", r.SourceURL)
+}
+
+func TestFromHTMLReadsSnippetLayout(t *testing.T) {
+	snippet := []byte(`<article>
+  <h1>Weeknight  Dal</h1>
+  <h2>Ingredients</h2>
+  <ul><li>1 cup red lentils</li><li>1 tsp <b>turmeric</b></li></ul>
+  <p><strong>Method:</strong></p>
+  <p>Rinse the lentils.</p>
+  <p><strong>2.</strong> Simmer with turmeric for 20 minutes.</p>
+  <h3>Notes</h3>
+  <p>Keeps for three days.</p>
+</article>`)
+
+	r, method, err := FromHTML("https://example.com/dal", snippet)
+	require.NoError(t, err)
+	assert.Equal(t, MethodHeuristic, method)
+	assert.Equal(t, "Weeknight Dal", r.Title)
+	assert.Equal(t, []string{"1 cup red lentils", "1 tsp turmeric"}, r.Ingredients)
+	assert.Equal(t, []string{"Rinse the lentils.", "2. Simmer with turmeric for 20 minutes."}, r.Directions)
+
+	_, _, err = FromHTML("https://example.com/about", []byte(`<h1>About us</h1><p>We love food.</p>`))
+	assert.ErrorIs(t, err, ErrNoRecipeOnPage)
+}
+
+func TestPageReaderFollowsRedirectsAndRefusesPrivateAddresses(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/r/dal", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/recipes/dal#top", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/recipes/dal", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<h1>Dal</h1><h2>Ingredients</h2><ul><li>1 cup lentils</li></ul>`))
+	})
+	mux.HandleFunc("/feed.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	// The test server listens on loopback, which the real dialer refuses
+	reader := &PageReader{client: srv.Client(), logger: zap.NewNop()}
+
+	page, err := reader.ReadRecipePage(context.Background(), srv.URL+"/r/dal")
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/recipes/dal", page.URL)
+	assert.Equal(t, MethodHeuristic, page.Method)
+	assert.Equal(t, []string{"1 cup lentils"}, page.Ingredients)
+
+	_, err = reader.ReadRecipePage(context.Background(), srv.URL+"/feed.json")
+	assert.ErrorIs(t, err, ErrNotHTML)
+	_, err = reader.ReadRecipePage(context.Background(), srv.URL+"/missing")
+	assert.ErrorIs(t, err, ErrPageUnavailable)
+
+	_, err = NewPageReader(zap.NewNop()).ReadRecipePage(context.Background(), srv.URL+"/recipes/dal")
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+}
@@ -0,0 +1,320 @@
+package recipeimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/domain/recipe/ingredients"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+)
+
+// schemaRecipe covers schema.org Recipe JSON as written by Nextcloud
+// Cookbook and Mealie's recipe JSON, which extends it with structured
+// ingredients, tag objects and performTime
+type schemaRecipe struct {
+	Name         string            `json:"name"`
+	Description  flexString        `json:"description"`
+	Yield        flexString        `json:"recipeYield"`
+	PrepTime     flexString        `json:"prepTime"`
+	CookTime     flexString        `json:"cookTime"`
+	PerformTime  flexString        `json:"performTime"`
+	TotalTime    flexString        `json:"totalTime"`
+	Ingredients  []json.RawMessage `json:"recipeIngredient"`
+	Instructions json.RawMessage   `json:"recipeInstructions"`
+	Keywords     json.RawMessage   `json:"keywords"`
+	Category     json.RawMessage   `json:"recipeCategory"`
+	Tags         json.RawMessage   `json:"tags"`
+	URL          string            `json:"url"`
+	OrgURL       string            `json:"orgURL"`
+}
+
+// mealieIngredient is Mealie's structured ingredient; display and
+// originalText already hold the full line when present
+type mealieIngredient struct {
+	Title        string          `json:"title"`
+	Display      string          `json:"display"`
+	OriginalText string          `json:"originalText"`
+	Note         string          `json:"note"`
+	Quantity     float64         `json:"quantity"`
+	Unit         json.RawMessage `json:"unit"`
+	Food         json.RawMessage `json:"food"`
+}
+
+// schemaStep is a HowToStep, or a HowToSection holding more steps
+type schemaStep struct {
+	Text  string            `json:"text"`
+	Name  string            `json:"name"`
+	Items []json.RawMessage `json:"itemListElement"`
+}
+
+// decodeSchemaExport reads one JSON document or a zip of them. In
+// Nextcloud archives only the per-recipe recipe.json files are read.
+func decodeSchemaExport(data []byte) ([]inbound.ImportedRecipe, error) {
+	if !isZip(data) {
+		return decodeSchemaJSON(data)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+
+	nextcloud := false
+	for _, f := range zr.File {
+		if path.Base(strings.ToLower(f.Name)) == "recipe.json" {
+			nextcloud = true
+			break
+		}
+	}
+
+	var recipes []inbound.ImportedRecipe
+	for _, f := range zr.File {
+		name := strings.ToLower(f.Name)
+		if f.FileInfo().IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		if nextcloud && path.Base(name) != "recipe.json" {
+			continue
+		}
+		raw, err := readEntry(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		decoded, err := decodeSchemaJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		recipes = append(recipes, decoded...)
+	}
+	return recipes, nil
+}
+
+// decodeSchemaJSON accepts a recipe object, an array of them, a JSON-LD
+// @graph, or a wrapper with an "items" or "recipes" array
+func decodeSchemaJSON(data []byte) ([]inbound.ImportedRecipe, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		var list []json.RawMessage
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		var recipes []inbound.ImportedRecipe
+		for _, item := range list {
+			decoded, err := decodeSchemaJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			recipes = append(recipes, decoded...)
+		}
+		return recipes, nil
+	}
+
+	var wrapper struct {
+		Graph   json.RawMessage `json:"@graph"`
+		Items   json.RawMessage `json:"items"`
+		Recipes json.RawMessage `json:"recipes"`
+		Type    flexString      `json:"@type"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	for _, nested := range []json.RawMessage{wrapper.Graph, wrapper.Items, wrapper.Recipes} {
+		if len(nested) > 0 && nested[0] == '[' {
+			return decodeSchemaJSON(nested)
+		}
+	}
+	if wrapper.Type != "" && wrapper.Type != "Recipe" {
+		// Other JSON-LD nodes in a @graph (WebPage, Person, ...)
+		return nil, nil
+	}
+
+	var r schemaRecipe
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid recipe: %w", err)
+	}
+	if strings.TrimSpace(r.Name) == "" && len(r.Ingredients) == 0 {
+		return nil, nil
+	}
+	return []inbound.ImportedRecipe{r.toImported()}, nil
+}
+
+func (r schemaRecipe) toImported() inbound.ImportedRecipe {
+	imported := inbound.ImportedRecipe{
+		Title:       strings.TrimSpace(r.Name),
+		Description: strings.TrimSpace(string(r.Description)),
+		Servings:    parseServings(string(r.Yield)),
+		PrepTime:    parseMinutes(string(r.PrepTime)),
+		CookTime:    parseMinutes(string(r.CookTime)),
+		SourceURL:   r.URL,
+	}
+	if imported.CookTime == 0 {
+		imported.CookTime = parseMinutes(string(r.PerformTime))
+	}
+	if imported.PrepTime == 0 && imported.CookTime == 0 {
+		imported.CookTime = parseMinutes(string(r.TotalTime))
+	}
+	if imported.SourceURL == "" {
+		imported.SourceURL = r.OrgURL
+	}
+
+	for _, raw := range r.Ingredients {
+		imported.Ingredients = append(imported.Ingredients, ingredientLines(raw)...)
+	}
+	imported.Directions = stepTexts(r.Instructions)
+
+	for _, raw := range []json.RawMessage{r.Category, r.Keywords, r.Tags} {
+		imported.Tags = append(imported.Tags, names(raw)...)
+	}
+	return imported
+}
+
+// ingredientLines renders a string or Mealie ingredient as text lines. A
+// Mealie section title becomes a "Title:" header line.
+func ingredientLines(raw json.RawMessage) []string {
+	var line string
+	if err := json.Unmarshal(raw, &line); err == nil {
+		return splitLines(line)
+	}
+
+	var m mealieIngredient
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+
+	var lines []string
+	if title := strings.TrimSpace(m.Title); title != "" {
+		lines = append(lines, title+":")
+	}
+
+	text := strings.TrimSpace(m.Display)
+	if text == "" {
+		text = strings.TrimSpace(m.OriginalText)
+	}
+	if text == "" {
+		var parts []string
+		if m.Quantity > 0 {
+			parts = append(parts, ingredients.FormatAmount(m.Quantity))
+		}
+		parts = append(parts, firstName(m.Unit), firstName(m.Food))
+		text = strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+		if note := strings.TrimSpace(m.Note); note != "" {
+			if text == "" {
+				text = note
+			} else {
+				text += ", " + note
+			}
+		}
+	}
+	if text != "" {
+		lines = append(lines, text)
+	}
+	return lines
+}
+
+// stepTexts flattens recipeInstructions written as a text block, a list of
+// strings, or HowToStep/HowToSection objects
+func stepTexts(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var block string
+	if err := json.Unmarshal(raw, &block); err == nil {
+		return splitLines(block)
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil
+	}
+
+	var steps []string
+	for _, item := range list {
+		var text string
+		if err := json.Unmarshal(item, &text); err == nil {
+			if text = strings.TrimSpace(text); text != "" {
+				steps = append(steps, text)
+			}
+			continue
+		}
+
+		var step schemaStep
+		if err := json.Unmarshal(item, &step); err != nil {
+			continue
+		}
+		if len(step.Items) > 0 {
+			nested, _ := json.Marshal(step.Items)
+			steps = append(steps, stepTexts(nested)...)
+			continue
+		}
+		text = strings.TrimSpace(step.Text)
+		if text == "" {
+			text = strings.TrimSpace(step.Name)
+		}
+		if text != "" {
+			steps = append(steps, text)
+		}
+	}
+	return steps
+}
+
+// names reads tags written as "a, b", ["a", "b"] or [{"name": "a"}]
+func names(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		var out []string
+		for _, part := range strings.Split(text, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+		return out
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil
+	}
+	var out []string
+	for _, item := range list {
+		if name := firstName(item); name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// firstName reads a JSON string, number or an object's "name"
+func firstName(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.TrimSpace(text)
+	}
+	var number float64
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	var obj struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		return strings.TrimSpace(obj.Name)
+	}
+	return ""
+}
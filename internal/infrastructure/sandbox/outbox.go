@@ -0,0 +1,68 @@
+// Package sandbox holds the adapters that make a deployment safe to open to
+// the public: an outbox that keeps emails and announcements instead of
+// sending them, and the nightly wipe of the sandbox data
+package sandbox
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// Outbox keeps the most recent captured messages in memory. It implements
+// outbound.SandboxOutbox and the email package's Capturer.
+type Outbox struct {
+	mu       sync.Mutex
+	messages []outbound.CapturedMessage
+	size     int
+	logger   *zap.Logger
+}
+
+// NewOutbox creates an outbox holding at most size messages
+func NewOutbox(size int, logger *zap.Logger) *Outbox {
+	if size <= 0 {
+		size = 200
+	}
+	return &Outbox{size: size, logger: logger.Named("sandbox")}
+}
+
+// Add records a message, dropping the oldest once the outbox is full
+func (o *Outbox) Add(m outbound.CapturedMessage) {
+	if m.CapturedAt.IsZero() {
+		m.CapturedAt = time.Now()
+	}
+
+	o.mu.Lock()
+	o.messages = append([]outbound.CapturedMessage{m}, o.messages...)
+	if len(o.messages) > o.size {
+		o.messages = o.messages[:o.size]
+	}
+	o.mu.Unlock()
+
+	o.logger.Info("Message captured instead of sent",
+		zap.String("kind", m.Kind),
+		zap.String("to", m.To),
+		zap.String("subject", m.Subject),
+	)
+}
+
+// CaptureEmail records an email that would have been sent
+func (o *Outbox) CaptureEmail(to, subject, body string) {
+	o.Add(outbound.CapturedMessage{Kind: "email", To: to, Subject: subject, Body: body})
+}
+
+// Messages returns the captured messages, newest first
+func (o *Outbox) Messages() []outbound.CapturedMessage {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]outbound.CapturedMessage(nil), o.messages...)
+}
+
+// Clear empties the outbox
+func (o *Outbox) Clear() {
+	o.mu.Lock()
+	o.messages = nil
+	o.mu.Unlock()
+}
@@ -0,0 +1,53 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+)
+
+// NewPosters stands in for announce.NewPosters: every configured channel
+// keeps its name, so announcement records look the same, but posts land in
+// the outbox instead of on the webhook or social network
+func NewPosters(cfg config.PublishingConfig, outbox *Outbox) []outbound.RecipePoster {
+	siteURL := strings.TrimRight(cfg.SiteURL, "/")
+	posters := make([]outbound.RecipePoster, 0, len(cfg.Channels))
+	seen := make(map[string]bool, len(cfg.Channels))
+	for _, channel := range cfg.Channels {
+		name := strings.TrimSpace(channel.Name)
+		if name == "" {
+			name = channel.Type
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		posters = append(posters, &capturingPoster{name: name, siteURL: siteURL, outbox: outbox})
+	}
+	return posters
+}
+
+// capturingPoster records announcements in the outbox
+type capturingPoster struct {
+	name    string
+	siteURL string
+	outbox  *Outbox
+}
+
+func (p *capturingPoster) Channel() string {
+	return p.name
+}
+
+func (p *capturingPoster) Post(ctx context.Context, a outbound.RecipeAnnouncement) error {
+	url := p.siteURL + "/recipes/" + a.RecipeID.String()
+	p.outbox.Add(outbound.CapturedMessage{
+		Kind:    "announcement",
+		To:      p.name,
+		Subject: a.Title,
+		Body:    fmt.Sprintf("New recipe: %s by %s %s", a.Title, a.AuthorName, url),
+	})
+	return nil
+}
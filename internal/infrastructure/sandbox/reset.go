@@ -0,0 +1,71 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/persistence/sqlite"
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// keptTables survive a reset: the migration history, so the schema is not
+// rebuilt, and the leases, so the leader running the reset keeps its lease
+var keptTables = map[string]bool{
+	"schema_migrations": true,
+	"leases":            true,
+}
+
+// PrepareSchema creates the PostgreSQL schema sandbox writes go to. The
+// connection's search_path already points there, so migrations and queries
+// land in it without further changes.
+func PrepareSchema(db *gorm.DB, schema string) error {
+	if err := db.Exec("CREATE SCHEMA IF NOT EXISTS " + pgx.Identifier{schema}.Sanitize()).Error; err != nil {
+		return fmt.Errorf("create sandbox schema: %w", err)
+	}
+	return nil
+}
+
+// Reset empties every table in the sandbox and puts the sample data back
+func Reset(ctx context.Context, db *gorm.DB) error {
+	db = db.WithContext(ctx)
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		return fmt.Errorf("list sandbox tables: %w", err)
+	}
+
+	wiped := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if !keptTables[table] && !strings.HasPrefix(table, "sqlite_") {
+			wiped = append(wiped, table)
+		}
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if len(wiped) == 0 {
+			return nil
+		}
+		if tx.Dialector.Name() == "postgres" {
+			quoted := make([]string, len(wiped))
+			for i, table := range wiped {
+				quoted[i] = pgx.Identifier{table}.Sanitize()
+			}
+			return tx.Exec("TRUNCATE TABLE " + strings.Join(quoted, ", ") + " RESTART IDENTITY CASCADE").Error
+		}
+		for _, table := range wiped {
+			if err := tx.Exec("DELETE FROM " + pgx.Identifier{table}.Sanitize()).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("wipe sandbox tables: %w", err)
+	}
+
+	if err := sqlite.SeedDatabase(db); err != nil {
+		return fmt.Errorf("seed sandbox: %w", err)
+	}
+	return nil
+}
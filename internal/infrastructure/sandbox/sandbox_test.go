@@ -0,0 +1,53 @@
+package sandbox
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gormModels "github.com/alchemorsel/v3/internal/infrastructure/persistence/gorm"
+	"github.com/alchemorsel/v3/internal/infrastructure/persistence/sqlite"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/alchemorsel/v3/pkg/lease"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm/logger"
+)
+
+func TestOutboxKeepsTheNewestMessages(t *testing.T) {
+	outbox := NewOutbox(2, zap.NewNop())
+	outbox.CaptureEmail("a@example.com", "first", "1")
+	outbox.CaptureEmail("b@example.com", "second", "2")
+	outbox.Add(outbound.CapturedMessage{Kind: "announcement", To: "fediverse", Subject: "third"})
+
+	messages := outbox.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "third", messages[0].Subject)
+	assert.Equal(t, "second", messages[1].Subject)
+	assert.False(t, messages[0].CapturedAt.IsZero())
+
+	outbox.Clear()
+	assert.Empty(t, outbox.Messages())
+}
+
+func TestResetRestoresTheSampleDataAndKeepsLeases(t *testing.T) {
+	db, err := sqlite.SetupDatabase(filepath.Join(t.TempDir(), "sandbox.db"), logger.Silent)
+	require.NoError(t, err)
+	require.NoError(t, sqlite.SeedDatabase(db))
+
+	var seeded int64
+	require.NoError(t, db.Model(&gormModels.UserModel{}).Count(&seeded).Error)
+	require.NoError(t, db.Create(&gormModels.UserModel{Email: "visitor@example.com", Name: "Visitor", PasswordHash: "x"}).Error)
+	require.NoError(t, db.Create(&lease.Record{Name: "sandbox-reset", Owner: "replica-1", Token: 1, ExpiresAt: time.Now().Add(time.Minute)}).Error)
+
+	require.NoError(t, Reset(context.Background(), db))
+
+	var users, leases int64
+	require.NoError(t, db.Model(&gormModels.UserModel{}).Count(&users).Error)
+	require.NoError(t, db.Model(&lease.Record{}).Count(&leases).Error)
+	assert.Equal(t, seeded, users)
+	assert.Equal(t, int64(1), leases)
+	assert.ErrorContains(t, db.Where("email = ?", "visitor@example.com").First(&gormModels.UserModel{}).Error, "not found")
+}
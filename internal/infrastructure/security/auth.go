@@ -223,16 +223,27 @@ func (a *AuthService) RevokeToken(tokenID string) error {
 	return a.redisClient.Set(ctx, key, "revoked", a.config.Auth.RefreshExpiration).Err()
 }
 
-// RevokeAllUserTokens revokes all tokens for a specific user
+// RevokeAllUserTokens revokes every token issued to a user, signing them
+// out of all devices
 func (a *AuthService) RevokeAllUserTokens(userID string) error {
+	if a.redisClient == nil {
+		return fmt.Errorf("token revocation needs redis")
+	}
 	ctx := context.Background()
-	pattern := fmt.Sprintf("token:%s:*", userID)
+	prefix := fmt.Sprintf("token:%s:", userID)
 	
-	keys, err := a.redisClient.Keys(ctx, pattern).Result()
+	keys, err := a.redisClient.Keys(ctx, prefix+"*").Result()
 	if err != nil {
 		return fmt.Errorf("failed to find user tokens: %w", err)
 	}
 
+	// Tokens are checked against the revocation list, so dropping the
+	// tracking keys alone would leave them valid
+	for _, key := range keys {
+		if err := a.RevokeToken(strings.TrimPrefix(key, prefix)); err != nil {
+			return fmt.Errorf("failed to revoke token: %w", err)
+		}
+	}
 	if len(keys) > 0 {
 		return a.redisClient.Del(ctx, keys...).Err()
 	}
@@ -315,6 +326,9 @@ func (a *AuthService) ValidateSession(sessionID, userID, ipAddress string) (*Ses
 
 // HashPassword securely hashes a password using bcrypt
 func (a *AuthService) HashPassword(password string) (string, error) {
+	if password == "" {
+		return "", fmt.Errorf("password is required")
+	}
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), a.config.Auth.BCryptCost)
 	if err != nil {
 		return "", fmt.Errorf("failed to hash password: %w", err)
@@ -41,7 +41,7 @@ func (suite *AuthServiceTestSuite) SetupSuite() {
 			RefreshExpiration: 24 * time.Hour,
 			BCryptCost:        4, // Lower cost for faster tests
 		},
-		Environment: "test",
+		App: config.AppConfig{Environment: "testing"},
 	}
 
 	// Setup logger (silent for tests)
@@ -53,6 +53,11 @@ func (suite *AuthServiceTestSuite) SetupSuite() {
 		DB:   1, // Use different DB for tests
 	})
 
+	// Token revocation and sessions live in Redis
+	if err := suite.redisClient.Ping(context.Background()).Err(); err != nil {
+		suite.T().Skipf("Redis is not reachable on localhost:6379: %v", err)
+	}
+
 	// Clear Redis before tests
 	suite.redisClient.FlushDB(context.Background())
 
@@ -288,10 +293,11 @@ func (suite *AuthServiceTestSuite) TestTokenRevocation() {
 		// Assert
 		require.NoError(suite.T(), err)
 
-		// Verify tokens are no longer valid (should fail validation due to missing Redis entries)
-		// Note: This test depends on how token storage is implemented
-		_ = token1
-		_ = token2
+		// Verify both tokens are rejected
+		_, err = suite.authService.ValidateToken(token1, AccessToken)
+		assert.ErrorContains(suite.T(), err, "revoked")
+		_, err = suite.authService.ValidateToken(token2, RefreshToken)
+		assert.ErrorContains(suite.T(), err, "revoked")
 	})
 }
 
@@ -757,6 +763,23 @@ func BenchmarkPasswordHashing(b *testing.B) {
 	}
 }
 
+// TestHashPasswordRejectsEmptyPassword runs without Redis, which the suite
+// needs and skips without
+func TestHashPasswordRejectsEmptyPassword(t *testing.T) {
+	cfg := &config.Config{Auth: config.AuthConfig{BCryptCost: 4}}
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer redisClient.Close()
+	authService := NewAuthService(cfg, zap.NewNop(), redisClient)
+
+	hash, err := authService.HashPassword("")
+	assert.Error(t, err)
+	assert.Empty(t, hash)
+
+	hash, err = authService.HashPassword("TestPassword123!")
+	require.NoError(t, err)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("TestPassword123!")))
+}
+
 // TestAuthServiceTestSuite runs the auth service test suite
 func TestAuthServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(AuthServiceTestSuite))
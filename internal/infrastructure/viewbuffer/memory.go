@@ -0,0 +1,83 @@
+// Package viewbuffer holds the recipe views counted since the last flush,
+// in process for a single replica or in Redis for several
+package viewbuffer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+)
+
+// dayLayout names a UTC day in buffer keys
+const dayLayout = "2006-01-02"
+
+type dayKey struct {
+	recipeID uuid.UUID
+	day      string
+}
+
+// Memory buffers views in process. Each replica dedupes and counts its own
+// visitors, so a viewer moving between replicas may count more than once.
+type Memory struct {
+	mu     sync.Mutex
+	seen   map[string]time.Time // viewer and recipe to when the window ends
+	counts map[dayKey]int
+	now    func() time.Time
+}
+
+// NewMemory creates an empty in-process buffer
+func NewMemory() *Memory {
+	return &Memory{
+		seen:   make(map[string]time.Time),
+		counts: make(map[dayKey]int),
+		now:    time.Now,
+	}
+}
+
+// FirstView marks a recipe as seen by a viewer for the window
+func (m *Memory) FirstView(ctx context.Context, recipeID uuid.UUID, viewer string, window time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := recipeID.String() + ":" + viewer
+	now := m.now()
+	if until, ok := m.seen[key]; ok && now.Before(until) {
+		return false, nil
+	}
+	m.seen[key] = now.Add(window)
+	return true, nil
+}
+
+// Add counts views of a recipe on a UTC day
+func (m *Memory) Add(ctx context.Context, recipeID uuid.UUID, day time.Time, views int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[dayKey{recipeID: recipeID, day: day.UTC().Format(dayLayout)}] += views
+	return nil
+}
+
+// Drain returns the buffered counts and empties the buffer. Viewers whose
+// window has passed are forgotten at the same time.
+func (m *Memory) Drain(ctx context.Context) ([]outbound.ViewCount, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := make([]outbound.ViewCount, 0, len(m.counts))
+	for key, views := range m.counts {
+		day, err := time.Parse(dayLayout, key.day)
+		if err != nil {
+			return nil, err
+		}
+		counts = append(counts, outbound.ViewCount{RecipeID: key.recipeID, Day: day, Views: views})
+	}
+	m.counts = make(map[dayKey]int)
+
+	now := m.now()
+	for key, until := range m.seen {
+		if !now.Before(until) {
+			delete(m.seen, key)
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,82 @@
+package viewbuffer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis buffers views in Redis, so every replica dedupes against the same
+// viewers and the counts of all of them are drained together. A viewer is
+// a key expiring with the window; the counts are one hash keyed by recipe
+// and day.
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedis creates a buffer whose keys start with prefix
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+func (r *Redis) pendingKey() string {
+	return r.prefix + "pending"
+}
+
+// FirstView marks a recipe as seen by a viewer for the window
+func (r *Redis) FirstView(ctx context.Context, recipeID uuid.UUID, viewer string, window time.Duration) (bool, error) {
+	first, err := r.client.SetNX(ctx, r.prefix+"seen:"+recipeID.String()+":"+viewer, 1, window).Result()
+	if err != nil {
+		return false, fmt.Errorf("mark recipe view: %w", err)
+	}
+	return first, nil
+}
+
+// Add counts views of a recipe on a UTC day
+func (r *Redis) Add(ctx context.Context, recipeID uuid.UUID, day time.Time, views int) error {
+	field := recipeID.String() + "|" + day.UTC().Format(dayLayout)
+	if err := r.client.HIncrBy(ctx, r.pendingKey(), field, int64(views)).Err(); err != nil {
+		return fmt.Errorf("buffer recipe view: %w", err)
+	}
+	return nil
+}
+
+// Drain reads and deletes the counts in one transaction, so views added
+// meanwhile land in the next drain
+func (r *Redis) Drain(ctx context.Context) ([]outbound.ViewCount, error) {
+	var fields *redis.MapStringStringCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		fields = pipe.HGetAll(ctx, r.pendingKey())
+		pipe.Del(ctx, r.pendingKey())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("drain recipe views: %w", err)
+	}
+
+	counts := make([]outbound.ViewCount, 0, len(fields.Val()))
+	for field, value := range fields.Val() {
+		id, day, ok := strings.Cut(field, "|")
+		recipeID, err := uuid.Parse(id)
+		if !ok || err != nil {
+			continue
+		}
+		date, err := time.Parse(dayLayout, day)
+		if err != nil {
+			continue
+		}
+		views, err := strconv.Atoi(value)
+		if err != nil || views <= 0 {
+			continue
+		}
+		counts = append(counts, outbound.ViewCount{RecipeID: recipeID, Day: date, Views: views})
+	}
+	return counts, nil
+}
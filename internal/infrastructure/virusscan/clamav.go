@@ -0,0 +1,131 @@
+package virusscan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+)
+
+// clamdChunkSize is the most sent in one INSTREAM chunk
+const clamdChunkSize = 64 << 10
+
+// ClamAV scans with clamd over its INSTREAM command, so files are never
+// written to disk on this side
+type ClamAV struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamAV creates a clamd adapter for tcp://host:port or unix:///path
+func NewClamAV(cfg config.VirusScanConfig) (*ClamAV, error) {
+	raw := cfg.ClamAVAddress
+	if raw == "" {
+		raw = "tcp://localhost:3310"
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clamd address %q: %w", raw, err)
+	}
+	c := &ClamAV{network: u.Scheme, timeout: cfg.Timeout}
+	switch u.Scheme {
+	case "tcp":
+		c.address = u.Host
+	case "unix":
+		c.address = u.Path
+	default:
+		return nil, fmt.Errorf("invalid clamd address %q, expected tcp:// or unix://", raw)
+	}
+	if c.address == "" {
+		return nil, fmt.Errorf("invalid clamd address %q", raw)
+	}
+	if c.timeout <= 0 {
+		c.timeout = defaultTimeout
+	}
+	return c, nil
+}
+
+// Name implements outbound.VirusScanner
+func (c *ClamAV) Name() string {
+	return ProviderClamAV
+}
+
+// Ping implements outbound.VirusScanner
+func (c *ClamAV) Ping(ctx context.Context) error {
+	reply, err := c.command(ctx, "zPING\x00", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("clamd: unexpected reply %q", reply)
+	}
+	return nil
+}
+
+// Scan implements outbound.VirusScanner. clamd replies "stream: OK" or
+// "stream: <signature> FOUND"; anything else, such as a size limit, is an
+// error.
+func (c *ClamAV) Scan(ctx context.Context, content []byte) (*outbound.VirusScanResult, error) {
+	reply, err := c.command(ctx, "zINSTREAM\x00", content)
+	if err != nil {
+		return nil, err
+	}
+	reply = strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case reply == "OK":
+		return &outbound.VirusScanResult{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return &outbound.VirusScanResult{
+			Infected:  true,
+			Signature: strings.TrimSuffix(reply, " FOUND"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("clamd: %s", reply)
+	}
+}
+
+// command sends one null-terminated command, with the content as INSTREAM
+// chunks when it is not nil, and returns the reply
+func (c *ClamAV) command(ctx context.Context, cmd string, content []byte) (string, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return "", fmt.Errorf("connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(deadline(ctx, c.timeout)); err != nil {
+		return "", err
+	}
+
+	w := bufio.NewWriter(conn)
+	w.WriteString(cmd)
+	if content != nil {
+		var size [4]byte
+		for len(content) > 0 {
+			n := min(len(content), clamdChunkSize)
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			w.Write(size[:])
+			w.Write(content[:n])
+			content = content[n:]
+		}
+		// A zero-length chunk ends the stream
+		w.Write([]byte{0, 0, 0, 0})
+	}
+	if err := w.Flush(); err != nil {
+		return "", fmt.Errorf("send to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return "", fmt.Errorf("read clamd reply: %w", err)
+	}
+	return strings.TrimSpace(strings.TrimSuffix(reply, "\x00")), nil
+}
@@ -0,0 +1,150 @@
+package virusscan
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+)
+
+// infectionHeaders are where ICAP servers name what they found:
+// X-Infection-Found is the draft standard, the others are common variants
+var infectionHeaders = []string{"X-Infection-Found", "X-Virus-Id", "X-Violations-Found"}
+
+// ICAP scans with an ICAP server (RFC 3507), such as c-icap with ClamAV or
+// a commercial gateway, by sending each file as a RESPMOD response body
+type ICAP struct {
+	serviceURL string
+	host       string
+	timeout    time.Duration
+}
+
+// NewICAP creates an ICAP adapter for icap://host[:port]/service
+func NewICAP(cfg config.VirusScanConfig) (*ICAP, error) {
+	u, err := url.Parse(cfg.ICAPURL)
+	if err != nil || u.Scheme != "icap" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid ICAP URL %q, expected icap://host[:port]/service", cfg.ICAPURL)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "1344")
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &ICAP{serviceURL: u.String(), host: host, timeout: timeout}, nil
+}
+
+// Name implements outbound.VirusScanner
+func (c *ICAP) Name() string {
+	return ProviderICAP
+}
+
+// Ping implements outbound.VirusScanner with an OPTIONS request
+func (c *ICAP) Ping(ctx context.Context) error {
+	status, _, err := c.do(ctx, "OPTIONS", "Encapsulated: null-body=0\r\n", "")
+	if err != nil {
+		return err
+	}
+	if status != 200 {
+		return fmt.Errorf("icap: OPTIONS returned %d", status)
+	}
+	return nil
+}
+
+// Scan implements outbound.VirusScanner. 204 means the server left the
+// file alone; a 200 naming an infection means it blocked it.
+func (c *ICAP) Scan(ctx context.Context, content []byte) (*outbound.VirusScanResult, error) {
+	httpHeader := "HTTP/1.1 200 OK\r\n" +
+		"Content-Type: application/octet-stream\r\n" +
+		"Content-Length: " + strconv.Itoa(len(content)) + "\r\n\r\n"
+	var body strings.Builder
+	body.WriteString(httpHeader)
+	if len(content) > 0 {
+		fmt.Fprintf(&body, "%x\r\n", len(content))
+		body.Write(content)
+		body.WriteString("\r\n")
+	}
+	body.WriteString("0\r\n\r\n")
+
+	headers := "Allow: 204\r\n" +
+		fmt.Sprintf("Encapsulated: res-hdr=0, res-body=%d\r\n", len(httpHeader))
+	status, header, err := c.do(ctx, "RESPMOD", headers, body.String())
+	if err != nil {
+		return nil, err
+	}
+
+	switch status {
+	case 204:
+		return &outbound.VirusScanResult{}, nil
+	case 200:
+		for _, name := range infectionHeaders {
+			if value := header.Get(name); value != "" {
+				return &outbound.VirusScanResult{Infected: true, Signature: threat(value)}, nil
+			}
+		}
+		// Servers that ignore Allow: 204 echo clean files back unchanged
+		return &outbound.VirusScanResult{}, nil
+	default:
+		return nil, fmt.Errorf("icap: RESPMOD returned %d", status)
+	}
+}
+
+// do sends one ICAP request and reads the status and headers of the reply.
+// The encapsulated reply body is not needed and is not read.
+func (c *ICAP) do(ctx context.Context, method, headers, body string) (int, textproto.MIMEHeader, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.host)
+	if err != nil {
+		return 0, nil, fmt.Errorf("connect to ICAP server: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(deadline(ctx, c.timeout)); err != nil {
+		return 0, nil, err
+	}
+
+	w := bufio.NewWriter(conn)
+	fmt.Fprintf(w, "%s %s ICAP/1.0\r\nHost: %s\r\n%s\r\n%s", method, c.serviceURL, c.host, headers, body)
+	if err := w.Flush(); err != nil {
+		return 0, nil, fmt.Errorf("send to ICAP server: %w", err)
+	}
+
+	reader := textproto.NewReader(bufio.NewReader(conn))
+	line, err := reader.ReadLine()
+	if err != nil {
+		return 0, nil, fmt.Errorf("read ICAP reply: %w", err)
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "ICAP/") {
+		return 0, nil, fmt.Errorf("icap: malformed status line %q", line)
+	}
+	status, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, nil, fmt.Errorf("icap: malformed status line %q", line)
+	}
+	header, err := reader.ReadMIMEHeader()
+	if err != nil {
+		return 0, nil, fmt.Errorf("read ICAP headers: %w", err)
+	}
+	return status, header, nil
+}
+
+// threat picks the name out of "Type=0; Resolution=2; Threat=Eicar;"
+// style values, falling back to the whole value
+func threat(value string) string {
+	for _, part := range strings.Split(value, ";") {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(part), "Threat="); ok && name != "" {
+			return name
+		}
+	}
+	return strings.TrimSpace(value)
+}
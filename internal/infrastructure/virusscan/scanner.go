@@ -0,0 +1,45 @@
+// Package virusscan provides malware scanner adapters for uploads
+package virusscan
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/outbound"
+	"go.uber.org/zap"
+)
+
+// Supported providers
+const (
+	ProviderClamAV = "clamav"
+	ProviderICAP   = "icap"
+	ProviderNone   = "none"
+)
+
+// defaultTimeout bounds one scan when none is configured
+const defaultTimeout = 30 * time.Second
+
+// NewScanner returns the configured scanner, or nil when scanning is
+// disabled
+func NewScanner(cfg config.VirusScanConfig, logger *zap.Logger) (outbound.VirusScanner, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case ProviderNone:
+		logger.Warn("Virus scanning disabled, uploads are processed unscanned")
+		return nil, nil
+	case ProviderICAP:
+		return NewICAP(cfg)
+	default:
+		return NewClamAV(cfg)
+	}
+}
+
+// deadline is the earlier of the context's deadline and the timeout
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	d := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(d) {
+		return ctxDeadline
+	}
+	return d
+}
@@ -0,0 +1,147 @@
+package virusscan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// eicar is the industry test file every scanner reports
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// serve accepts connections on a loopback port and hands each to handle
+func serve(t *testing.T, handle func(conn net.Conn)) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// fakeClamd answers PING and INSTREAM like clamd
+func fakeClamd(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	cmd, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+	switch cmd {
+	case "zPING\x00":
+		conn.Write([]byte("PONG\x00"))
+	case "zINSTREAM\x00":
+		var content bytes.Buffer
+		for {
+			var size uint32
+			if binary.Read(r, binary.BigEndian, &size) != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			io.CopyN(&content, r, int64(size))
+		}
+		if strings.Contains(content.String(), "EICAR-STANDARD") {
+			conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+			return
+		}
+		conn.Write([]byte("stream: OK\x00"))
+	}
+}
+
+func TestClamAV(t *testing.T) {
+	addr := serve(t, fakeClamd)
+	scanner, err := NewScanner(config.VirusScanConfig{Provider: "clamav", ClamAVAddress: "tcp://" + addr, Timeout: 5 * time.Second}, zap.NewNop())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, scanner.Ping(ctx))
+
+	result, err := scanner.Scan(ctx, bytes.Repeat([]byte("flour "), 50000))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	result, err = scanner.Scan(ctx, []byte(eicar))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+
+	_, err = NewClamAV(config.VirusScanConfig{ClamAVAddress: "localhost:3310"})
+	assert.Error(t, err, "the scheme is required")
+}
+
+func TestICAP(t *testing.T) {
+	addr := serve(t, fakeICAP)
+	scanner, err := NewScanner(config.VirusScanConfig{Provider: "icap", ICAPURL: "icap://" + addr + "/avscan", Timeout: 5 * time.Second}, zap.NewNop())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, scanner.Ping(ctx))
+
+	result, err := scanner.Scan(ctx, []byte("a photo of grandma's recipe card"))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	result, err = scanner.Scan(ctx, []byte(eicar))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+}
+
+// fakeICAP answers OPTIONS with 200 and RESPMOD with 204, or with 200 and
+// an infection header when the body holds the test file
+func fakeICAP(conn net.Conn) {
+	tp := textproto.NewReader(bufio.NewReader(conn))
+	line, err := tp.ReadLine()
+	if err != nil {
+		return
+	}
+	if _, err := tp.ReadMIMEHeader(); err != nil {
+		return
+	}
+	if strings.HasPrefix(line, "OPTIONS ") {
+		conn.Write([]byte("ICAP/1.0 200 OK\r\nMethods: RESPMOD\r\nEncapsulated: null-body=0\r\n\r\n"))
+		return
+	}
+	// Read the encapsulated HTTP headers and the chunked body up to the
+	// terminating zero chunk
+	var body strings.Builder
+	for {
+		chunk, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		body.WriteString(chunk)
+		if chunk == "0" {
+			break
+		}
+	}
+	if strings.Contains(body.String(), "EICAR-STANDARD") {
+		conn.Write([]byte("ICAP/1.0 200 OK\r\nX-Infection-Found: Type=0; Resolution=2; Threat=Eicar-Test-Signature;\r\nEncapsulated: null-body=0\r\n\r\n"))
+		return
+	}
+	conn.Write([]byte("ICAP/1.0 204 No Content\r\nEncapsulated: null-body=0\r\n\r\n"))
+}
@@ -0,0 +1,260 @@
+// Package watchdog samples goroutines, heap and in-memory buffers and
+// alerts when one keeps growing or passes its limit, which usually means a
+// leak. Alerts are logged and counted in Prometheus, and can store a heap
+// or goroutine profile for later inspection.
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/alchemorsel/v3/internal/ports/inbound"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// Alert reasons
+const (
+	ReasonGrowth    = "growth"
+	ReasonThreshold = "threshold"
+)
+
+// Defaults used when the config leaves them unset
+const (
+	DefaultInterval  = 30 * time.Second
+	DefaultWindow    = 10
+	DefaultMinGrowth = 0.25
+	DefaultCooldown  = 15 * time.Minute
+)
+
+var (
+	watchdogSample = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "alchemorsel",
+		Subsystem: "watchdog",
+		Name:      "sample",
+		Help:      "Latest value sampled by the leak watchdog",
+	}, []string{"gauge"})
+	watchdogAlerts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "alchemorsel",
+		Subsystem: "watchdog",
+		Name:      "alerts_total",
+		Help:      "Leak watchdog alerts by gauge and reason",
+	}, []string{"gauge", "reason"})
+)
+
+// Gauge is one value the watchdog samples. Limit zero means the gauge is
+// only checked for growth. ProfileKind is the profile stored when it
+// alerts; leave it empty to store none.
+type Gauge struct {
+	Name        string
+	Limit       float64
+	ProfileKind string
+	Read        func(ctx context.Context) (float64, error)
+}
+
+// Alert describes a gauge that grew on every sample of the window or
+// passed its limit
+type Alert struct {
+	Gauge   string
+	Reason  string
+	Value   float64
+	Limit   float64
+	Samples []float64
+}
+
+// Watchdog samples its gauges on an interval
+type Watchdog struct {
+	gauges    []Gauge
+	profiling inbound.ProfilingService
+	cfg       config.WatchdogConfig
+	now       func() time.Time
+	logger    *zap.Logger
+
+	mu        sync.Mutex
+	samples   map[string][]float64
+	lastAlert map[string]time.Time
+}
+
+// New creates a watchdog. profiling may be nil, in which case alerts never
+// store profiles.
+func New(cfg config.WatchdogConfig, gauges []Gauge, profiling inbound.ProfilingService, logger *zap.Logger) *Watchdog {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.Window < 2 {
+		cfg.Window = DefaultWindow
+	}
+	if cfg.MinGrowth <= 0 {
+		cfg.MinGrowth = DefaultMinGrowth
+	}
+	if cfg.AlertCooldown <= 0 {
+		cfg.AlertCooldown = DefaultCooldown
+	}
+	return &Watchdog{
+		gauges:    gauges,
+		profiling: profiling,
+		cfg:       cfg,
+		now:       time.Now,
+		logger:    logger.Named("watchdog"),
+		samples:   make(map[string][]float64),
+		lastAlert: make(map[string]time.Time),
+	}
+}
+
+// RuntimeGauges samples the goroutine count and the live heap
+func RuntimeGauges(cfg config.WatchdogConfig) []Gauge {
+	return []Gauge{
+		{
+			Name:        "goroutines",
+			Limit:       float64(cfg.GoroutineLimit),
+			ProfileKind: "goroutine",
+			Read: func(ctx context.Context) (float64, error) {
+				return float64(runtime.NumGoroutine()), nil
+			},
+		},
+		{
+			Name:        "heap_bytes",
+			Limit:       float64(cfg.HeapLimitMB) * (1 << 20),
+			ProfileKind: "heap",
+			Read: func(ctx context.Context) (float64, error) {
+				var stats runtime.MemStats
+				runtime.ReadMemStats(&stats)
+				return float64(stats.HeapAlloc), nil
+			},
+		},
+	}
+}
+
+// Run samples until ctx is cancelled
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	w.logger.Info("Leak watchdog started",
+		zap.Duration("interval", w.cfg.Interval),
+		zap.Int("window", w.cfg.Window),
+		zap.Int("gauges", len(w.gauges)),
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, alert := range w.Check(ctx) {
+				w.raise(ctx, alert)
+			}
+		}
+	}
+}
+
+// Check takes one sample of every gauge and returns the alerts that are
+// not in their cooldown
+func (w *Watchdog) Check(ctx context.Context) []Alert {
+	var alerts []Alert
+	for _, gauge := range w.gauges {
+		value, err := gauge.Read(ctx)
+		if err != nil {
+			w.logger.Warn("Watchdog gauge unavailable", zap.String("gauge", gauge.Name), zap.Error(err))
+			continue
+		}
+		watchdogSample.WithLabelValues(gauge.Name).Set(value)
+
+		w.mu.Lock()
+		samples := append(w.samples[gauge.Name], value)
+		if len(samples) > w.cfg.Window {
+			samples = samples[len(samples)-w.cfg.Window:]
+		}
+		w.samples[gauge.Name] = samples
+
+		var reason string
+		switch {
+		case gauge.Limit > 0 && value > gauge.Limit:
+			reason = ReasonThreshold
+		case len(samples) == w.cfg.Window && growing(samples, w.cfg.MinGrowth):
+			reason = ReasonGrowth
+		}
+		if reason != "" && w.cool(gauge.Name+"/"+reason) {
+			alerts = append(alerts, Alert{
+				Gauge:   gauge.Name,
+				Reason:  reason,
+				Value:   value,
+				Limit:   gauge.Limit,
+				Samples: append([]float64(nil), samples...),
+			})
+		}
+		w.mu.Unlock()
+	}
+	return alerts
+}
+
+// raise logs and counts an alert, and stores a profile when enabled
+func (w *Watchdog) raise(ctx context.Context, alert Alert) {
+	watchdogAlerts.WithLabelValues(alert.Gauge, alert.Reason).Inc()
+	w.logger.Error("Possible leak detected",
+		zap.String("gauge", alert.Gauge),
+		zap.String("reason", alert.Reason),
+		zap.Float64("value", alert.Value),
+		zap.Float64("limit", alert.Limit),
+		zap.Float64s("samples", alert.Samples),
+	)
+
+	kind := w.profileKind(alert.Gauge)
+	if !w.cfg.CaptureProfiles || w.profiling == nil || kind == "" {
+		return
+	}
+	reason := fmt.Sprintf("watchdog: %s %s at %.0f", alert.Gauge, alert.Reason, alert.Value)
+	capture, err := w.profiling.StartSystemCapture(ctx, kind, reason)
+	if err != nil {
+		w.logger.Warn("Watchdog profile capture not started", zap.String("gauge", alert.Gauge), zap.Error(err))
+		return
+	}
+	w.logger.Info("Watchdog profile capture started",
+		zap.String("gauge", alert.Gauge),
+		zap.String("capture_id", capture.ID.String()),
+	)
+}
+
+// cool reports whether an alert may fire now and starts its cooldown.
+// Callers hold w.mu.
+func (w *Watchdog) cool(key string) bool {
+	now := w.now()
+	if last, ok := w.lastAlert[key]; ok && now.Sub(last) < w.cfg.AlertCooldown {
+		return false
+	}
+	w.lastAlert[key] = now
+	return true
+}
+
+func (w *Watchdog) profileKind(name string) string {
+	for _, gauge := range w.gauges {
+		if gauge.Name == name {
+			return gauge.ProfileKind
+		}
+	}
+	return ""
+}
+
+// growing reports whether the samples never fell, rose on most steps and
+// rose by at least minGrowth of the first one. Flat stretches with the
+// odd step up, or a noisy series, are normal load.
+func growing(samples []float64, minGrowth float64) bool {
+	rises := 0
+	for i := 1; i < len(samples); i++ {
+		if samples[i] < samples[i-1] {
+			return false
+		}
+		if samples[i] > samples[i-1] {
+			rises++
+		}
+	}
+	if rises*2 <= len(samples)-1 {
+		return false
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	return first == 0 || (last-first)/first >= minGrowth
+}
@@ -0,0 +1,54 @@
+package watchdog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGrowing(t *testing.T) {
+	assert.True(t, growing([]float64{100, 110, 120, 130, 140}, 0.25))
+	assert.True(t, growing([]float64{100, 100, 120, 130, 140}, 0.25), "a flat step is allowed")
+	assert.False(t, growing([]float64{100, 110, 105, 130, 140}, 0.25), "any drop means memory was released")
+	assert.False(t, growing([]float64{100, 101, 102, 103, 104}, 0.25), "too little growth")
+	assert.False(t, growing([]float64{0, 0, 0, 0, 1}, 0.25), "one step up is not a trend")
+	assert.True(t, growing([]float64{0, 2, 4, 6, 8}, 0.25))
+}
+
+func TestCheckAlertsOnGrowthAndThresholdWithCooldown(t *testing.T) {
+	values := []float64{10, 20, 30, 40, 30, 500}
+	next := 0
+	w := New(config.WatchdogConfig{Window: 3, MinGrowth: 0.5, AlertCooldown: time.Hour}, []Gauge{{
+		Name:  "buffered",
+		Limit: 100,
+		Read: func(ctx context.Context) (float64, error) {
+			v := values[next]
+			next++
+			return v, nil
+		},
+	}}, nil, zap.NewNop())
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	assert.Empty(t, w.Check(ctx))
+	assert.Empty(t, w.Check(ctx), "the window is not full yet")
+
+	alerts := w.Check(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, ReasonGrowth, alerts[0].Reason)
+	assert.Equal(t, []float64{10, 20, 30}, alerts[0].Samples)
+
+	assert.Empty(t, w.Check(ctx), "still growing but in cooldown")
+	assert.Empty(t, w.Check(ctx), "a drop ends the trend")
+
+	alerts = w.Check(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, ReasonThreshold, alerts[0].Reason)
+	assert.Equal(t, 500.0, alerts[0].Value)
+}
@@ -0,0 +1,38 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountDeletionService erases accounts at their owner's request. A
+// request signs the user out everywhere and waits out a grace period, in
+// which signing in again and cancelling keeps the account.
+type AccountDeletionService interface {
+	// RequestDeletion schedules the user's erasure after checking their
+	// password. Asking again keeps the original date.
+	RequestDeletion(ctx context.Context, cmd RequestAccountDeletionCommand) (*AccountDeletionStatus, error)
+	// CancelDeletion keeps the account, failing with not found when no
+	// deletion is pending
+	CancelDeletion(ctx context.Context, userID uuid.UUID) (*AccountDeletionStatus, error)
+	// GetDeletionStatus reports whether the user's deletion is pending
+	GetDeletionStatus(ctx context.Context, userID uuid.UUID) (*AccountDeletionStatus, error)
+	// EraseDueAccounts erases the accounts whose grace period has passed
+	// and returns how many were erased
+	EraseDueAccounts(ctx context.Context) (int, error)
+}
+
+// RequestAccountDeletionCommand asks for a user's account to be erased
+type RequestAccountDeletionCommand struct {
+	UserID   uuid.UUID
+	Password string
+}
+
+// AccountDeletionStatus is where a user's deletion stands
+type AccountDeletionStatus struct {
+	Pending      bool       `json:"pending"`
+	RequestedAt  *time.Time `json:"requested_at,omitempty"`
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+}
@@ -0,0 +1,130 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdminService runs the admin section: managing users and recipes,
+// system totals, and review of AI-generated content. Every method needs
+// the admin role.
+type AdminService interface {
+	ListUsers(ctx context.Context, requesterID uuid.UUID, query AdminUserQuery) (*AdminUserPage, error)
+	// SuspendUser deactivates an account so it can no longer sign in
+	SuspendUser(ctx context.Context, cmd AdminUserCommand) (*AdminUser, error)
+	ReinstateUser(ctx context.Context, cmd AdminUserCommand) (*AdminUser, error)
+	// UnpublishRecipe archives a published recipe of any author
+	UnpublishRecipe(ctx context.Context, cmd AdminRecipeCommand) error
+	SystemStats(ctx context.Context, requesterID uuid.UUID) (*SystemStats, error)
+	ListAIContent(ctx context.Context, requesterID uuid.UUID, limit, offset int) (*AIContentPage, error)
+}
+
+// AdminUserQuery filters the user list. Status is active or suspended,
+// Role is user, chef or admin; empty matches all.
+type AdminUserQuery struct {
+	Search string
+	Status string
+	Role   string
+	Limit  int
+	Offset int
+}
+
+// AdminUserCommand is an admin acting on a user
+type AdminUserCommand struct {
+	RequesterID uuid.UUID
+	UserID      uuid.UUID
+	Reason      string
+}
+
+// AdminRecipeCommand is an admin acting on a recipe
+type AdminRecipeCommand struct {
+	RequesterID uuid.UUID
+	RecipeID    uuid.UUID
+	Reason      string
+}
+
+// AdminUserPage is one page of the user list
+type AdminUserPage struct {
+	Users  []AdminUser `json:"users"`
+	Total  int64       `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+// AdminUser is a user as admins see them
+type AdminUser struct {
+	ID          uuid.UUID  `json:"id"`
+	Email       string     `json:"email"`
+	Name        string     `json:"name"`
+	Role        string     `json:"role"`
+	Status      string     `json:"status"`
+	Verified    bool       `json:"verified"`
+	Recipes     int        `json:"recipes"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// SystemStats are the admin dashboard totals. New counts cover the last
+// seven days.
+type SystemStats struct {
+	Users    UserStats    `json:"users"`
+	Recipes  RecipeStats  `json:"recipes"`
+	Comments CommentStats `json:"comments"`
+	Runtime  RuntimeStats `json:"runtime"`
+}
+
+// UserStats counts accounts
+type UserStats struct {
+	Total     int64 `json:"total"`
+	Active    int64 `json:"active"`
+	Suspended int64 `json:"suspended"`
+	Admins    int64 `json:"admins"`
+	New       int64 `json:"new"`
+}
+
+// RecipeStats counts recipes
+type RecipeStats struct {
+	Total       int64 `json:"total"`
+	Draft       int64 `json:"draft"`
+	Published   int64 `json:"published"`
+	Archived    int64 `json:"archived"`
+	AIGenerated int64 `json:"ai_generated"`
+	New         int64 `json:"new"`
+}
+
+// CommentStats counts comments
+type CommentStats struct {
+	Total  int64 `json:"total"`
+	Hidden int64 `json:"hidden"`
+}
+
+// RuntimeStats describe the replica that answered
+type RuntimeStats struct {
+	StartedAt  time.Time `json:"started_at"`
+	Uptime     string    `json:"uptime"`
+	GoVersion  string    `json:"go_version"`
+	Goroutines int       `json:"goroutines"`
+	HeapMB     float64   `json:"heap_mb"`
+}
+
+// AIContentPage is one page of AI-generated recipes
+type AIContentPage struct {
+	Recipes []AIContent `json:"recipes"`
+	Total   int64       `json:"total"`
+	Limit   int         `json:"limit"`
+	Offset  int         `json:"offset"`
+}
+
+// AIContent is an AI-generated recipe with the prompt and model behind it
+type AIContent struct {
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	AuthorID    uuid.UUID `json:"author_id"`
+	AuthorEmail string    `json:"author_email"`
+	Status      string    `json:"status"`
+	Model       string    `json:"model"`
+	Prompt      string    `json:"prompt"`
+	CreatedAt   time.Time `json:"created_at"`
+}
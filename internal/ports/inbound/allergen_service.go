@@ -0,0 +1,48 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// AllergenService discloses the major allergens a recipe contains or may
+// contain
+type AllergenService interface {
+	Disclose(ctx context.Context, query AllergenQuery) (*AllergenDisclosure, error)
+}
+
+// AllergenQuery asks for a recipe's allergen disclosure
+type AllergenQuery struct {
+	// RequesterID is uuid.Nil for anonymous readers, who only see
+	// published recipes
+	RequesterID uuid.UUID
+	RecipeID    string
+}
+
+// AllergenDisclosure is a recipe's allergen panel. Panel is the standard
+// statement, "Contains: Milk, Eggs. May contain: Nuts."
+type AllergenDisclosure struct {
+	RecipeID   string            `json:"recipe_id"`
+	Title      string            `json:"title"`
+	Contains   []string          `json:"contains"`
+	MayContain []string          `json:"may_contain"`
+	Panel      string            `json:"panel"`
+	Allergens  []AllergenFinding `json:"allergens"`
+}
+
+// AllergenFinding is one allergen with the ingredients that bring it in.
+// Status is contains or may-contain.
+type AllergenFinding struct {
+	Allergen string           `json:"allergen"`
+	Label    string           `json:"label"`
+	Status   string           `json:"status"`
+	Sources  []AllergenSource `json:"sources"`
+}
+
+// AllergenSource is an ingredient behind a finding. Reason is ingredient,
+// optional, substitute or cross-contact.
+type AllergenSource struct {
+	Ingredient string `json:"ingredient"`
+	Reason     string `json:"reason"`
+}
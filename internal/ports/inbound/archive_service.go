@@ -0,0 +1,80 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArchiveService keeps the RUM and audit tables small by moving days past
+// their retention to blob storage, and reads those days back for long-term
+// trends and audits
+type ArchiveService interface {
+	// RunTiering archives the days past retention, oldest first, up to the
+	// per-run limit. It fails with a conflict while another run is in
+	// progress.
+	RunTiering(ctx context.Context) (*ArchiveRunReport, error)
+	// TriggerTiering runs tiering on an admin's request
+	TriggerTiering(ctx context.Context, requesterID uuid.UUID) (*ArchiveRunReport, error)
+	// RecipeViewHistory reads a recipe's archived daily views. Days still
+	// in the database are served by the recipe analytics endpoint.
+	RecipeViewHistory(ctx context.Context, query RecipeViewHistoryQuery) (*RecipeViewHistory, error)
+	// AuditHistory reads archived profiling audit rows for admins
+	AuditHistory(ctx context.Context, requesterID uuid.UUID, from, to time.Time) ([]ArchivedCapture, error)
+}
+
+// ArchiveRunReport lists the partitions one tiering run wrote
+type ArchiveRunReport struct {
+	StartedAt  string        `json:"started_at"`
+	FinishedAt string        `json:"finished_at"`
+	Partitions []ArchivedDay `json:"partitions"`
+}
+
+// ArchivedDay is one day of a dataset moved to blob storage
+type ArchivedDay struct {
+	Dataset    string `json:"dataset"`
+	Day        string `json:"day"`
+	Records    int    `json:"records"`
+	SourceRows int    `json:"source_rows"`
+	SizeBytes  int64  `json:"size_bytes"`
+}
+
+// RecipeViewHistoryQuery selects archived days from From through To, in
+// UTC. Zero values read the longest allowed range ending today.
+type RecipeViewHistoryQuery struct {
+	RequesterID uuid.UUID
+	RecipeID    uuid.UUID
+	From        time.Time
+	To          time.Time
+}
+
+// RecipeViewHistory is a recipe's archived daily views. Unique viewers are
+// counted per day, so the total is the sum of daily uniques.
+type RecipeViewHistory struct {
+	RecipeID              uuid.UUID        `json:"recipe_id"`
+	From                  string           `json:"from"`
+	To                    string           `json:"to"`
+	Views                 int              `json:"views"`
+	UniqueViewers         int              `json:"unique_viewers"`
+	AverageScrollDepth    *float64         `json:"average_scroll_depth"`
+	AverageEngagedSeconds *float64         `json:"average_engaged_seconds"`
+	Daily                 []DailyViews     `json:"daily"`
+	Referrers             []AnalyticsCount `json:"referrers"`
+}
+
+// ArchivedCapture is a profiling audit row read back from the archive
+type ArchivedCapture struct {
+	ID          uuid.UUID `json:"id"`
+	Kind        string    `json:"kind"`
+	Seconds     int       `json:"seconds"`
+	Status      string    `json:"status"`
+	RequestedBy uuid.UUID `json:"requested_by"`
+	IPAddress   string    `json:"ip_address,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	BlobKey     string    `json:"blob_key,omitempty"`
+	SizeBytes   int64     `json:"size_bytes"`
+	Error       string    `json:"error,omitempty"`
+	StartedAt   string    `json:"started_at"`
+	FinishedAt  string    `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,106 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// BattleService runs remix battles: the AI and a community chef each write
+// a recipe for the same prompt, and readers vote blind between them
+type BattleService interface {
+	// Start has the AI write its entry for a chef's prompt. The chef's
+	// entry is due next.
+	Start(ctx context.Context, cmd StartBattleCommand) (*BattleView, error)
+	// SubmitEntry records the chef's entry and opens voting
+	SubmitEntry(ctx context.Context, cmd SubmitBattleEntryCommand) (*BattleView, error)
+	// Get reads a battle as the requester may see it
+	Get(ctx context.Context, query BattleQuery) (*BattleView, error)
+	// List returns battles newest first
+	List(ctx context.Context, query ListBattlesQuery) ([]BattleView, error)
+	// Vote counts a reader's vote for entry "a" or "b"
+	Vote(ctx context.Context, cmd BattleVoteCommand) (*BattleView, error)
+	// Templates reports how the AI has fared with each prompt template
+	Templates(ctx context.Context) ([]BattleTemplateStats, error)
+}
+
+// StartBattleCommand starts a battle
+type StartBattleCommand struct {
+	ChefID uuid.UUID
+	Prompt string
+}
+
+// SubmitBattleEntryCommand is the chef's recipe for their battle
+type SubmitBattleEntryCommand struct {
+	ChefID      uuid.UUID
+	BattleID    string
+	Title       string
+	Description string
+	Ingredients []string
+	Steps       []string
+}
+
+// BattleQuery asks for one battle
+type BattleQuery struct {
+	// RequesterID is uuid.Nil for anonymous readers
+	RequesterID uuid.UUID
+	BattleID    string
+}
+
+// ListBattlesQuery asks for battles by status: voting by default, closed,
+// or open for the requester's own battles awaiting their entry
+type ListBattlesQuery struct {
+	RequesterID uuid.UUID
+	Status      string
+	Limit       int
+	Offset      int
+}
+
+// BattleVoteCommand votes for one of a battle's entries
+type BattleVoteCommand struct {
+	UserID   uuid.UUID
+	BattleID string
+	Entry    string
+}
+
+// BattleView is a battle as a reader sees it. While voting the entries are
+// only labelled "a" and "b"; once voting ends each entry shows its side
+// and votes, and the battle its winner and the AI's prompt template.
+type BattleView struct {
+	ID           string        `json:"id"`
+	Prompt       string        `json:"prompt"`
+	ChefID       string        `json:"chef_id"`
+	Status       string        `json:"status"`
+	VotingEndsAt string        `json:"voting_ends_at,omitempty"`
+	Entries      []BattleEntry `json:"entries"`
+	// YourVote is the label the requester voted for
+	YourVote  string `json:"your_vote,omitempty"`
+	Winner    string `json:"winner,omitempty"`
+	Template  string `json:"template,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// BattleEntry is one side's recipe
+type BattleEntry struct {
+	Label       string   `json:"label"`
+	Side        string   `json:"side,omitempty"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Ingredients []string `json:"ingredients"`
+	Steps       []string `json:"steps"`
+	Votes       *int     `json:"votes,omitempty"`
+}
+
+// BattleTemplateStats is the AI's record with one prompt template over
+// closed battles
+type BattleTemplateStats struct {
+	Name        string  `json:"name"`
+	Instruction string  `json:"instruction,omitempty"`
+	Wins        int     `json:"wins"`
+	Losses      int     `json:"losses"`
+	Ties        int     `json:"ties"`
+	Score       float64 `json:"score"`
+	// Exploring is true until the template has enough battles to be
+	// judged on
+	Exploring bool `json:"exploring"`
+}
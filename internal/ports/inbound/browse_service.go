@@ -0,0 +1,38 @@
+package inbound
+
+import (
+	"context"
+)
+
+// BrowseService serves the tag and cuisine browse pages from precomputed
+// summaries, so no page view runs an aggregate over the recipes
+type BrowseService interface {
+	ListTags(ctx context.Context, limit int) (*BrowseCounts, error)
+	ListCuisines(ctx context.Context, limit int) (*BrowseCounts, error)
+	TopRatedByCuisine(ctx context.Context, cuisine string, limit int) (*BrowseTopRated, error)
+	// Refresh recomputes the summaries; it does nothing if a refresh is
+	// already running
+	Refresh(ctx context.Context) error
+}
+
+// BrowseCounts are the values of one browse dimension, most used first
+type BrowseCounts struct {
+	Items       []FacetCount `json:"items"`
+	RefreshedAt string       `json:"refreshed_at,omitempty"`
+}
+
+// BrowseTopRated is the best rated published recipes of one cuisine
+type BrowseTopRated struct {
+	Cuisine     string         `json:"cuisine"`
+	Recipes     []BrowseRecipe `json:"recipes"`
+	RefreshedAt string         `json:"refreshed_at,omitempty"`
+}
+
+// BrowseRecipe is a recipe card on a browse page
+type BrowseRecipe struct {
+	ID            string  `json:"id"`
+	Title         string  `json:"title"`
+	AverageRating float64 `json:"average_rating"`
+	Likes         int     `json:"likes"`
+	Rank          int     `json:"rank"`
+}
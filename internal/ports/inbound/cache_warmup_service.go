@@ -0,0 +1,56 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CacheWarmupService fills the query caches after a deploy so the first
+// visitors do not pay for cold caches
+type CacheWarmupService interface {
+	// Start warms the caches in the background; it does nothing if a warmup
+	// is already running
+	Start(trigger string) bool
+	// WarmCaches starts a warmup on an admin's request
+	WarmCaches(ctx context.Context, requesterID uuid.UUID) (*CacheWarmupReport, error)
+	// WarmupReport describes the latest warmup to an admin
+	WarmupReport(ctx context.Context, requesterID uuid.UUID) (*CacheWarmupReport, error)
+	// Report describes the latest warmup
+	Report() CacheWarmupReport
+}
+
+// Cache warmup and warmup task states
+const (
+	WarmupIdle    = "idle"
+	WarmupPending = "pending"
+	WarmupRunning = "running"
+	WarmupDone    = "done"
+	WarmupFailed  = "failed"
+)
+
+// What started a warmup
+const (
+	WarmupTriggerBoot   = "boot"
+	WarmupTriggerManual = "manual"
+)
+
+// CacheWarmupReport is the progress of the latest warmup
+type CacheWarmupReport struct {
+	Status     string            `json:"status"`
+	Trigger    string            `json:"trigger,omitempty"`
+	StartedAt  string            `json:"started_at,omitempty"`
+	FinishedAt string            `json:"finished_at,omitempty"`
+	Completed  int               `json:"completed"`
+	Total      int               `json:"total"`
+	Tasks      []CacheWarmupTask `json:"tasks"`
+}
+
+// CacheWarmupTask is the progress of warming one cache
+type CacheWarmupTask struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Entries    int    `json:"entries"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
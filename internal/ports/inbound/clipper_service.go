@@ -0,0 +1,37 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ClipperService saves recipes clipped from web pages with the browser
+// extension as drafts for their owner to review
+type ClipperService interface {
+	// Clip creates a draft from a recipe read off a page. Clipping a recipe
+	// whose title the user already has returns that recipe instead.
+	Clip(ctx context.Context, cmd ClipCommand) (*ClipResult, error)
+}
+
+// ClipCommand is a recipe read from the page at PageURL. Method is how it
+// was read: schema.org or heuristic.
+type ClipCommand struct {
+	UserID  uuid.UUID
+	PageURL string
+	Method  string
+	Recipe  ImportedRecipe
+}
+
+// ClipResult names the draft and where to review it. Status is created or
+// duplicate; Remaining is how many more clips the user may send in the
+// current window.
+type ClipResult struct {
+	RecipeID  uuid.UUID `json:"recipe_id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	Method    string    `json:"method"`
+	ReviewURL string    `json:"review_url"`
+	Warnings  []string  `json:"warnings,omitempty"`
+	Remaining int       `json:"remaining"`
+}
@@ -0,0 +1,321 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentService defines the use cases for recipe comments, including the
+// email notifications authors can answer by replying
+type CommentService interface {
+	AddComment(ctx context.Context, cmd AddCommentCommand) (*CommentDTO, error)
+	// EditComment changes the text of the user's own visible comment
+	EditComment(ctx context.Context, cmd EditCommentCommand) (*CommentDTO, error)
+	// DeleteComment deletes the user's own comment; admins may delete any
+	DeleteComment(ctx context.Context, cmd DeleteCommentCommand) error
+	// FlagComment asks moderators to look at someone else's comment
+	FlagComment(ctx context.Context, cmd FlagCommentCommand) error
+	// ListComments returns a recipe's comment threads with their reactions.
+	// viewerID, uuid.Nil for anonymous readers, marks the viewer's own.
+	ListComments(ctx context.Context, recipeID, viewerID uuid.UUID) ([]CommentDTO, error)
+	// NotifyReview emails the recipe author about a review that was saved
+	NotifyReview(ctx context.Context, cmd RateRecipeCommand) error
+
+	// React adds a reaction to someone else's comment; Unreact takes it
+	// back. Both return the comment's reactions afterwards.
+	React(ctx context.Context, cmd ReactCommand) (*CommentReactions, error)
+	Unreact(ctx context.Context, cmd ReactCommand) (*CommentReactions, error)
+
+	// ListReviews returns a recipe's written reviews, most helpful first
+	// unless another order is asked for
+	ListReviews(ctx context.Context, query ListReviewsQuery) ([]ReviewDTO, error)
+	// VoteReview records whether a reader found a review helpful, replacing
+	// their earlier vote. Reviewers cannot vote on their own review, nor
+	// authors on reviews of their recipe.
+	VoteReview(ctx context.Context, cmd ReviewVoteCommand) (*ReviewDTO, error)
+	// ClearReviewVote withdraws a reader's vote
+	ClearReviewVote(ctx context.Context, cmd ReviewVoteCommand) (*ReviewDTO, error)
+
+	// Discussion returns whether a recipe's comments are locked
+	Discussion(ctx context.Context, recipeID, viewerID uuid.UUID) (*DiscussionDTO, error)
+	// LockDiscussion locks or unlocks commenting on a recipe. Only the
+	// recipe's author may, and they can still comment while it is locked.
+	LockDiscussion(ctx context.Context, cmd LockDiscussionCommand) (*DiscussionDTO, error)
+	// HideComment lets a recipe's author hide a comment on it until a
+	// moderator restores or removes it
+	HideComment(ctx context.Context, cmd HideCommentCommand) (*CommentDTO, error)
+	// ListHiddenComments returns the comments waiting for moderator
+	// review, oldest first. Admins only.
+	ListHiddenComments(ctx context.Context, requesterID uuid.UUID, limit int) ([]CommentDTO, error)
+	// ReviewHiddenComment restores or removes a hidden comment. Admins only.
+	ReviewHiddenComment(ctx context.Context, cmd ReviewHiddenCommentCommand) (*CommentDTO, error)
+	// ListFlaggedComments returns the comments readers flagged, most
+	// flagged first. Admins only.
+	ListFlaggedComments(ctx context.Context, requesterID uuid.UUID, limit int) ([]FlaggedCommentDTO, error)
+	// ReviewFlaggedComment hides a flagged comment or dismisses its flags.
+	// Admins only.
+	ReviewFlaggedComment(ctx context.Context, cmd ReviewFlaggedCommentCommand) (*CommentDTO, error)
+	// BlockCommenter stops a user from commenting on any of the author's
+	// recipes; UnblockCommenter lifts it
+	BlockCommenter(ctx context.Context, cmd BlockCommenterCommand) (*BlockedCommenterDTO, error)
+	UnblockCommenter(ctx context.Context, cmd BlockCommenterCommand) error
+	ListBlockedCommenters(ctx context.Context, authorID uuid.UUID) ([]BlockedCommenterDTO, error)
+	// ModerationLog returns the audit log of moderation actions. Authors
+	// see their own actions and those on their recipes; admins see all.
+	ModerationLog(ctx context.Context, query ModerationLogQuery) ([]ModerationEventDTO, error)
+
+	// Inbound email webhooks
+	HandleInboundReply(ctx context.Context, email InboundEmail) (*InboundReplyResult, error)
+	HandleDeliveryEvent(ctx context.Context, event EmailDeliveryEvent) error
+}
+
+// AddCommentCommand for commenting on a recipe, optionally answering
+// another comment
+type AddCommentCommand struct {
+	RecipeID uuid.UUID
+	UserID   uuid.UUID
+	ParentID *uuid.UUID
+	Content  string
+}
+
+// EditCommentCommand replaces the text of the user's comment
+type EditCommentCommand struct {
+	RecipeID  uuid.UUID
+	CommentID uuid.UUID
+	UserID    uuid.UUID
+	Content   string
+}
+
+// DeleteCommentCommand deletes a comment on a recipe
+type DeleteCommentCommand struct {
+	RecipeID  uuid.UUID
+	CommentID uuid.UUID
+	UserID    uuid.UUID
+}
+
+// FlagCommentCommand flags a comment. Reason is spam, abuse, off_topic or
+// other.
+type FlagCommentCommand struct {
+	RecipeID  uuid.UUID
+	CommentID uuid.UUID
+	UserID    uuid.UUID
+	Reason    string
+	Note      string
+}
+
+// ReviewFlaggedCommentCommand is a moderator's verdict on a flagged comment
+type ReviewFlaggedCommentCommand struct {
+	CommentID   uuid.UUID
+	ModeratorID uuid.UUID
+	// Hide takes the comment out of view; otherwise its flags are dismissed
+	Hide   bool
+	Reason string
+}
+
+// FlaggedCommentDTO is a comment in the flag queue with its flag counts
+// by reason
+type FlaggedCommentDTO struct {
+	Comment        CommentDTO     `json:"comment"`
+	Flags          int            `json:"flags"`
+	Reasons        map[string]int `json:"reasons"`
+	FirstFlaggedAt time.Time      `json:"first_flagged_at"`
+}
+
+// CommentDTO represents a comment with its replies. Responses to a review
+// are listed at the top level with ReviewUserID naming the reviewer.
+// Replies are one level deep.
+type CommentDTO struct {
+	ID           uuid.UUID    `json:"id"`
+	RecipeID     uuid.UUID    `json:"recipe_id"`
+	AuthorID     uuid.UUID    `json:"author_id"`
+	AuthorName   string       `json:"author_name,omitempty"`
+	ParentID     *uuid.UUID   `json:"parent_id,omitempty"`
+	ReviewUserID *uuid.UUID   `json:"review_user_id,omitempty"`
+	Content      string       `json:"content"`
+	Source       string       `json:"source"`
+	CreatedAt    time.Time    `json:"created_at"`
+	EditedAt     *time.Time   `json:"edited_at,omitempty"`
+	Replies      []CommentDTO `json:"replies,omitempty"`
+	// Reactions counts each reaction the comment got; MyReactions lists
+	// the viewer's. Only comment lists fill them in.
+	Reactions   map[string]int `json:"reactions,omitempty"`
+	MyReactions []string       `json:"my_reactions,omitempty"`
+	// Status is visible, hidden, removed or deleted. Only the recipe's
+	// author, the comment's author and moderators see the content of a
+	// hidden or removed comment, and only they get the reason it was
+	// hidden. Deleted comments have no content.
+	Status     string     `json:"status"`
+	HideReason string     `json:"hide_reason,omitempty"`
+	HiddenAt   *time.Time `json:"hidden_at,omitempty"`
+}
+
+// DiscussionDTO is whether a recipe takes new comments
+type DiscussionDTO struct {
+	RecipeID uuid.UUID  `json:"recipe_id"`
+	Locked   bool       `json:"locked"`
+	LockedAt *time.Time `json:"locked_at,omitempty"`
+}
+
+// LockDiscussionCommand locks or unlocks a recipe's comments
+type LockDiscussionCommand struct {
+	RecipeID uuid.UUID
+	UserID   uuid.UUID
+	Locked   bool
+	Reason   string
+}
+
+// HideCommentCommand hides a comment on the user's recipe
+type HideCommentCommand struct {
+	RecipeID  uuid.UUID
+	CommentID uuid.UUID
+	UserID    uuid.UUID
+	Reason    string
+}
+
+// ReviewHiddenCommentCommand is a moderator's verdict on a hidden comment
+type ReviewHiddenCommentCommand struct {
+	CommentID   uuid.UUID
+	ModeratorID uuid.UUID
+	// Restore shows the comment again; otherwise it is removed
+	Restore bool
+	Reason  string
+}
+
+// BlockCommenterCommand blocks or unblocks UserID from commenting on
+// AuthorID's recipes
+type BlockCommenterCommand struct {
+	AuthorID uuid.UUID
+	UserID   uuid.UUID
+	Reason   string
+}
+
+// BlockedCommenterDTO is a user an author blocked
+type BlockedCommenterDTO struct {
+	UserID    uuid.UUID `json:"user_id"`
+	UserName  string    `json:"user_name,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ModerationLogQuery asks for audit events, optionally on one recipe
+type ModerationLogQuery struct {
+	RequesterID uuid.UUID
+	RecipeID    *uuid.UUID
+	Limit       int
+}
+
+// ModerationEventDTO is one audited moderation action. Action is lock,
+// unlock, hide, restore, remove, block, unblock, delete or dismiss.
+type ModerationEventDTO struct {
+	ID        uuid.UUID  `json:"id"`
+	ActorID   uuid.UUID  `json:"actor_id"`
+	Action    string     `json:"action"`
+	RecipeID  *uuid.UUID `json:"recipe_id,omitempty"`
+	CommentID *uuid.UUID `json:"comment_id,omitempty"`
+	SubjectID *uuid.UUID `json:"subject_id,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ReactCommand is a reaction to a comment on a recipe. Kind is thumbs_up,
+// heart, laugh or insightful.
+type ReactCommand struct {
+	RecipeID  uuid.UUID
+	CommentID uuid.UUID
+	UserID    uuid.UUID
+	Kind      string
+}
+
+// CommentReactions is a comment's reactions after a change
+type CommentReactions struct {
+	CommentID   uuid.UUID      `json:"comment_id"`
+	Reactions   map[string]int `json:"reactions"`
+	MyReactions []string       `json:"my_reactions"`
+}
+
+// Review orders
+const (
+	ReviewSortHelpful = "helpful"
+	ReviewSortNewest  = "newest"
+)
+
+// ListReviewsQuery asks for a recipe's reviews. ViewerID is uuid.Nil for
+// anonymous readers.
+type ListReviewsQuery struct {
+	RecipeID uuid.UUID
+	ViewerID uuid.UUID
+	Sort     string
+}
+
+// ReviewVoteCommand is a reader's vote on the review ReviewerID left on a
+// recipe
+type ReviewVoteCommand struct {
+	RecipeID   uuid.UUID
+	ReviewerID uuid.UUID
+	VoterID    uuid.UUID
+	Helpful    bool
+}
+
+// ReviewDTO is a written review with its helpfulness votes. Helpfulness is
+// the score reviews are ordered by, from 0 to 1; MyVote is "helpful" or
+// "not_helpful" when the viewer voted.
+type ReviewDTO struct {
+	RecipeID     uuid.UUID `json:"recipe_id"`
+	ReviewerID   uuid.UUID `json:"reviewer_id"`
+	ReviewerName string    `json:"reviewer_name,omitempty"`
+	Rating       int       `json:"rating"`
+	Content      string    `json:"content"`
+	CreatedAt    time.Time `json:"created_at"`
+	Helpful      int       `json:"helpful"`
+	NotHelpful   int       `json:"not_helpful"`
+	Helpfulness  float64   `json:"helpfulness"`
+	MyVote       string    `json:"my_vote,omitempty"`
+}
+
+// InboundEmail is a received email as parsed by the mail provider
+type InboundEmail struct {
+	From      string
+	To        []string
+	Subject   string
+	MessageID string
+	// Text is the plain text body, including any quoted original
+	Text    string
+	Headers map[string]string
+	// SpamScore and SpamVerdict are the provider's spam filter results
+	SpamScore   *float64
+	SpamVerdict string
+}
+
+// Inbound reply outcomes. Only errors make the provider retry, so every
+// outcome here is final.
+const (
+	InboundReplyPosted    = "posted"
+	InboundReplyDuplicate = "duplicate"
+	InboundReplyIgnored   = "ignored"
+	InboundReplyRejected  = "rejected"
+	InboundReplyBounced   = "bounced"
+)
+
+// InboundReplyResult says what became of an inbound email
+type InboundReplyResult struct {
+	Status  string      `json:"status"`
+	Reason  string      `json:"reason,omitempty"`
+	Comment *CommentDTO `json:"comment,omitempty"`
+}
+
+// Email delivery event types reported by the mail provider
+const (
+	EmailEventBounce    = "bounce"
+	EmailEventComplaint = "complaint"
+)
+
+// EmailDeliveryEvent is a bounce or spam complaint about a sent email
+type EmailDeliveryEvent struct {
+	Type  string
+	Email string
+	// Permanent marks hard bounces; soft bounces are retried by the provider
+	Permanent bool
+	Detail    string
+}
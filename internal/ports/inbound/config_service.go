@@ -0,0 +1,32 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ConfigService shows admins the configuration the server is running with
+type ConfigService interface {
+	// EffectiveConfig lists every key with its value, where the value came
+	// from and the schema it was checked against. Secrets are redacted.
+	EffectiveConfig(ctx context.Context, requesterID uuid.UUID) (*EffectiveConfig, error)
+}
+
+// EffectiveConfig is the configuration reference at the time it was read
+type EffectiveConfig struct {
+	GeneratedAt string          `json:"generated_at"`
+	Settings    []ConfigSetting `json:"settings"`
+}
+
+// ConfigSetting is one configuration key. Source is default, file or env.
+type ConfigSetting struct {
+	Key     string      `json:"key"`
+	Value   interface{} `json:"value"`
+	Source  string      `json:"source"`
+	Type    string      `json:"type"`
+	Default string      `json:"default"`
+	Rules   string      `json:"rules,omitempty"`
+	Env     string      `json:"env"`
+	Secret  bool        `json:"secret,omitempty"`
+}
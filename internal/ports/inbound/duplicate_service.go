@@ -0,0 +1,83 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DuplicateService checks published recipes against other authors' recipes
+// and lets admins review the near copies it flags. Authors can appeal an
+// upheld flag once.
+type DuplicateService interface {
+	// CheckQueued runs the check on the recipes queued by publishing and
+	// returns how many it checked
+	CheckQueued(ctx context.Context) (int, error)
+	// ListFlags pages through open flags and flags with a pending appeal,
+	// oldest first. Admins only.
+	ListFlags(ctx context.Context, requesterID uuid.UUID, limit, offset int) (*DuplicateFlagPage, error)
+	// ResolveFlag upholds a flag, taking the recipe down, or dismisses
+	// it, putting back a recipe the check took down
+	ResolveFlag(ctx context.Context, cmd ResolveDuplicateCommand) (*DuplicateFlagDTO, error)
+	// AppealFlag files the author's appeal of the recipe's upheld flag
+	AppealFlag(ctx context.Context, cmd AppealDuplicateCommand) (*DuplicateFlagDTO, error)
+	// DecideAppeal grants an appeal, restoring the recipe, or denies it.
+	// The admin who upheld the flag cannot decide.
+	DecideAppeal(ctx context.Context, cmd DecideDuplicateAppealCommand) (*DuplicateFlagDTO, error)
+}
+
+// ResolveDuplicateCommand is an admin's verdict on a flag
+type ResolveDuplicateCommand struct {
+	FlagID      uuid.UUID
+	ModeratorID uuid.UUID
+	Uphold      bool
+	Note        string
+}
+
+// AppealDuplicateCommand is an author appealing their recipe's flag
+type AppealDuplicateCommand struct {
+	RecipeID uuid.UUID
+	AuthorID uuid.UUID
+	Note     string
+}
+
+// DecideDuplicateAppealCommand is an admin's decision on an appeal
+type DecideDuplicateAppealCommand struct {
+	FlagID      uuid.UUID
+	ModeratorID uuid.UUID
+	Grant       bool
+}
+
+// DuplicateFlagDTO is a flag with the evidence it was opened on. The titles
+// are only set for admins.
+type DuplicateFlagDTO struct {
+	ID                uuid.UUID  `json:"id"`
+	RecipeID          uuid.UUID  `json:"recipe_id"`
+	RecipeTitle       string     `json:"recipe_title,omitempty"`
+	OriginalID        uuid.UUID  `json:"original_id"`
+	OriginalTitle     string     `json:"original_title,omitempty"`
+	AuthorID          uuid.UUID  `json:"author_id"`
+	Similarity        float64    `json:"similarity"`
+	SharedIngredients []string   `json:"shared_ingredients"`
+	SharedSteps       []string   `json:"shared_steps"`
+	Archived          bool       `json:"archived"`
+	Status            string     `json:"status"`
+	ResolvedBy        *uuid.UUID `json:"resolved_by,omitempty"`
+	Resolution        string     `json:"resolution,omitempty"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+	Appeal            string     `json:"appeal,omitempty"`
+	AppealNote        string     `json:"appeal_note,omitempty"`
+	AppealedAt        *time.Time `json:"appealed_at,omitempty"`
+	AppealDecidedBy   *uuid.UUID `json:"appeal_decided_by,omitempty"`
+	AppealDecidedAt   *time.Time `json:"appeal_decided_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// DuplicateFlagPage is one page of flags awaiting review
+type DuplicateFlagPage struct {
+	Flags  []DuplicateFlagDTO `json:"flags"`
+	Total  int64              `json:"total"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+}
@@ -0,0 +1,14 @@
+package inbound
+
+import (
+	"context"
+)
+
+// EmbeddingService keeps the recipe vectors behind search by meaning up
+// to date
+type EmbeddingService interface {
+	// Refresh embeds the published recipes that are new or changed since
+	// their vector was made; it does nothing if a refresh is already
+	// running
+	Refresh(ctx context.Context) error
+}
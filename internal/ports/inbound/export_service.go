@@ -0,0 +1,76 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportService streams bulk exports to admins and renders single recipes
+// as files. Rows are handed over as they are read, so an export never
+// sits in memory whole.
+type ExportService interface {
+	// ExportRecipes calls fn with each recipe matching query, oldest
+	// first; admins only. An error from fn stops the export.
+	ExportRecipes(ctx context.Context, requesterID uuid.UUID, query RecipeExportQuery, fn func(*ExportedRecipe) error) error
+	// ExportRecipe renders one recipe, with its nutrition and credits, as
+	// a PDF, JSON or Markdown file
+	ExportRecipe(ctx context.Context, query RecipeFileQuery) (*RecipeFile, error)
+}
+
+// Single recipe export formats
+const (
+	RecipeFileFormatPDF      = "pdf"
+	RecipeFileFormatJSON     = "json"
+	RecipeFileFormatMarkdown = "markdown"
+)
+
+// RecipeFileQuery asks for one recipe as a file. Without a requester only
+// published recipes are found.
+type RecipeFileQuery struct {
+	RequesterID uuid.UUID
+	RecipeID    uuid.UUID
+	Format      string
+}
+
+// RecipeFile is a recipe rendered for download
+type RecipeFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// RecipeExportQuery filters a recipe export. Status is draft, published
+// or archived; empty exports them all.
+type RecipeExportQuery struct {
+	Status       string
+	UpdatedSince *time.Time
+}
+
+// ExportedRecipe is one recipe in an export
+type ExportedRecipe struct {
+	ID              uuid.UUID  `json:"id"`
+	Title           string     `json:"title"`
+	AuthorID        uuid.UUID  `json:"author_id"`
+	Status          string     `json:"status"`
+	Language        string     `json:"language"`
+	Cuisine         string     `json:"cuisine,omitempty"`
+	Category        string     `json:"category,omitempty"`
+	Difficulty      string     `json:"difficulty,omitempty"`
+	PrepTimeMinutes int        `json:"prep_time_minutes"`
+	CookTimeMinutes int        `json:"cook_time_minutes"`
+	Servings        int        `json:"servings"`
+	Calories        int        `json:"calories"`
+	Likes           int        `json:"likes"`
+	Views           int        `json:"views"`
+	AverageRating   float64    `json:"average_rating"`
+	AIGenerated     bool       `json:"ai_generated"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	// Allergens and MayContain are the labels of the recipe's allergen
+	// panel
+	Allergens  []string `json:"allergens"`
+	MayContain []string `json:"may_contain"`
+}
@@ -0,0 +1,63 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FollowService lets users follow each other and shows their profiles
+// with follower and following counts. A private profile shows its details
+// and follow lists only to its owner and the users the owner follows;
+// everyone else sees the name and the counts.
+type FollowService interface {
+	// Follow is a no-op when followerID already follows userID
+	Follow(ctx context.Context, followerID, userID uuid.UUID) (*FollowStatus, error)
+	Unfollow(ctx context.Context, followerID, userID uuid.UUID) (*FollowStatus, error)
+	// Profile returns userID's profile as viewerID sees it; viewerID is
+	// nil for anonymous visitors
+	Profile(ctx context.Context, viewerID *uuid.UUID, userID uuid.UUID) (*PublicProfile, error)
+	// Followers lists who follows userID, most recent first
+	Followers(ctx context.Context, viewerID *uuid.UUID, userID uuid.UUID, params PaginationParams) (*FollowList, error)
+	// Following lists whom userID follows, most recent first
+	Following(ctx context.Context, viewerID *uuid.UUID, userID uuid.UUID, params PaginationParams) (*FollowList, error)
+}
+
+// FollowStatus is the follow state after a follow or unfollow
+type FollowStatus struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Following bool      `json:"following"`
+	Followers int       `json:"followers"`
+}
+
+// PublicProfile is a user's profile page. Restricted profiles are private
+// ones the viewer may not see, so only the name and counts are filled.
+type PublicProfile struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	Private      bool       `json:"private"`
+	Restricted   bool       `json:"restricted"`
+	Followers    int        `json:"followers"`
+	Following    int        `json:"following"`
+	IsFollowing  bool       `json:"is_following"`
+	Avatar       string     `json:"avatar,omitempty"`
+	Bio          string     `json:"bio,omitempty"`
+	Location     string     `json:"location,omitempty"`
+	Website      string     `json:"website,omitempty"`
+	CookingLevel string     `json:"cooking_level,omitempty"`
+	JoinedAt     *time.Time `json:"joined_at,omitempty"`
+}
+
+// FollowList is a page of followers or followed users
+type FollowList struct {
+	Users    []FollowUser `json:"users"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+}
+
+// FollowUser is one entry of a follow list
+type FollowUser struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
@@ -0,0 +1,39 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// FoodSafetyService checks a recipe's steps against food safety rules for
+// its raw ingredients
+type FoodSafetyService interface {
+	Check(ctx context.Context, query FoodSafetyQuery) (*FoodSafetyReport, error)
+}
+
+// FoodSafetyQuery asks for a recipe's food safety check
+type FoodSafetyQuery struct {
+	// RequesterID is uuid.Nil for anonymous readers, who only see
+	// published recipes
+	RequesterID uuid.UUID
+	RecipeID    string
+}
+
+// FoodSafetyReport lists the food safety rules a recipe breaks
+type FoodSafetyReport struct {
+	RecipeID string            `json:"recipe_id"`
+	Title    string            `json:"title"`
+	Safe     bool              `json:"safe"`
+	Issues   []FoodSafetyIssue `json:"issues"`
+}
+
+// FoodSafetyIssue is one broken rule. Step is left out for issues with
+// the whole recipe.
+type FoodSafetyIssue struct {
+	Step       int    `json:"step,omitempty"`
+	Rule       string `json:"rule"`
+	Food       string `json:"food"`
+	Ingredient string `json:"ingredient"`
+	Message    string `json:"message"`
+}
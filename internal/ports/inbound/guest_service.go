@@ -0,0 +1,79 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GuestService lets visitors without an account chat with the AI chef and
+// keep a few recipes for a limited time. Guests are known by the token
+// StartSession returns; registering or signing in claims their recipes.
+type GuestService interface {
+	// StartSession starts a guest session for a visitor at clientIP
+	StartSession(ctx context.Context, clientIP string) (*GuestSessionResult, error)
+	// Chat asks the AI chef for a recipe. The suggestion is not kept until
+	// the guest saves it.
+	Chat(ctx context.Context, token, message string) (*GuestChatResult, error)
+	SaveRecipe(ctx context.Context, token string, recipe GuestRecipeDraft) (*GuestRecipeDTO, error)
+	ListRecipes(ctx context.Context, token string) (*GuestRecipesResult, error)
+	DeleteRecipe(ctx context.Context, token string, recipeID uuid.UUID) error
+	// Claim moves the guest's recipes to the user as drafts, all or none.
+	// Claiming again as the same user succeeds without moving anything.
+	Claim(ctx context.Context, token string, userID uuid.UUID) (*GuestClaimResult, error)
+	// ExpireGuests deletes sessions past their expiry with their recipes,
+	// returning how many sessions went
+	ExpireGuests(ctx context.Context) (int64, error)
+}
+
+// GuestSessionResult is a new guest's token and limits. The token is only
+// ever returned here.
+type GuestSessionResult struct {
+	Token       string    `json:"guest_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	MaxRecipes  int       `json:"max_recipes"`
+	MaxMessages int       `json:"max_messages"`
+}
+
+// GuestRecipeDraft is a recipe as the AI chef suggested it or a guest
+// saves it. Ingredients are free text lines; each direction is one step.
+type GuestRecipeDraft struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Ingredients []string `json:"ingredients"`
+	Directions  []string `json:"directions"`
+	Servings    int      `json:"servings,omitempty"`
+	PrepTime    int      `json:"prep_time,omitempty"` // minutes
+	CookTime    int      `json:"cook_time,omitempty"` // minutes
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// GuestRecipeDTO is a recipe a guest saved
+type GuestRecipeDTO struct {
+	ID uuid.UUID `json:"id"`
+	GuestRecipeDraft
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GuestChatResult is the AI chef's reply and the recipe it suggests
+type GuestChatResult struct {
+	Reply        string            `json:"reply"`
+	Suggestion   *GuestRecipeDraft `json:"suggestion,omitempty"`
+	MessagesLeft int               `json:"messages_left"`
+}
+
+// GuestRecipesResult lists a guest's recipes with the room left and when
+// they expire
+type GuestRecipesResult struct {
+	Recipes   []GuestRecipeDTO `json:"recipes"`
+	Remaining int              `json:"remaining"`
+	ExpiresAt time.Time        `json:"expires_at"`
+}
+
+// GuestClaimResult names the drafts created from a guest's recipes
+type GuestClaimResult struct {
+	RecipeIDs []uuid.UUID `json:"recipe_ids"`
+	Claimed   int         `json:"claimed"`
+	Warnings  []string    `json:"warnings,omitempty"`
+}
@@ -0,0 +1,61 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImageService stores uploaded images and serves them resized and
+// re-encoded from /img/{id}
+type ImageService interface {
+	// UploadImage stores an original; JPEG, PNG and GIF are accepted
+	UploadImage(ctx context.Context, cmd UploadImageCommand) (*UploadedImage, error)
+	// GetVariant returns the image encoded for the request, from the cache
+	// when it was encoded before
+	GetVariant(ctx context.Context, id uuid.UUID, req ImageVariantRequest) (*ImageVariant, error)
+}
+
+// UploadImageCommand is one uploaded original
+type UploadImageCommand struct {
+	UserID      uuid.UUID
+	ContentType string
+	Content     []byte
+}
+
+// UploadedImage is a stored original. SrcSet lists its variants at each
+// configured width up to the original's, ready for an img srcset attribute.
+type UploadedImage struct {
+	ID          uuid.UUID `json:"id"`
+	URL         string    `json:"url"`
+	SrcSet      string    `json:"srcset"`
+	ContentType string    `json:"content_type"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	SizeBytes   int64     `json:"size_bytes"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ImageVariantRequest is what /img/{id} was asked for: the w, h, f and q
+// query parameters the image optimizers put in srcset URLs. Zero values and
+// an empty or auto Format leave the choice to the service; Accept is the
+// request's Accept header, used to pick a format.
+type ImageVariantRequest struct {
+	Width   int
+	Height  int
+	Format  string
+	Quality int
+	Accept  string
+}
+
+// ImageVariant is an encoded variant. ETag changes whenever the bytes can;
+// UploadedAt is when the original was stored.
+type ImageVariant struct {
+	Content     []byte
+	ContentType string
+	Format      string
+	Quality     int
+	ETag        string
+	UploadedAt  time.Time
+}
@@ -0,0 +1,69 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/alchemorsel/v3/internal/domain/notification"
+	"github.com/google/uuid"
+)
+
+// NotificationService delivers in-app notifications: new followers, likes
+// and comments on a user's recipes, and finished AI recipes. Users turn
+// each type off or on from their profile.
+type NotificationService interface {
+	// Notify stores a notification unless the recipient is the actor,
+	// is not an active user, or muted the type
+	Notify(ctx context.Context, cmd NotifyCommand) error
+	// List returns userID's notifications newest first
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, params PaginationParams) (*NotificationList, error)
+	UnreadCount(ctx context.Context, userID uuid.UUID) (int, error)
+	// MarkRead returns the unread count left
+	MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+	// Preferences lists every type with whether the user receives it
+	Preferences(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error)
+	// SetPreferences turns the given types on or off; types left out keep
+	// their setting
+	SetPreferences(ctx context.Context, userID uuid.UUID, enabled map[string]bool) ([]NotificationPreference, error)
+}
+
+// NotifyCommand describes something that happened to UserID. Subject is
+// the recipe it happened to; the service writes the title from the type,
+// the actor's name and the subject. Message is optional detail such as the
+// comment's text.
+type NotifyCommand struct {
+	UserID  uuid.UUID
+	ActorID *uuid.UUID
+	Type    notification.Type
+	Subject string
+	Message string
+	Link    string
+}
+
+// NotificationList is a page of notifications with the unread total
+type NotificationList struct {
+	Notifications []NotificationDTO `json:"notifications"`
+	Unread        int               `json:"unread"`
+	Page          int               `json:"page"`
+	PageSize      int               `json:"page_size"`
+}
+
+// NotificationDTO is one notification
+type NotificationDTO struct {
+	ID        uuid.UUID  `json:"id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message,omitempty"`
+	Link      string     `json:"link,omitempty"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty"`
+	Read      bool       `json:"read"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NotificationPreference is whether a user receives one notification type
+type NotificationPreference struct {
+	Type    string `json:"type"`
+	Label   string `json:"label"`
+	Enabled bool   `json:"enabled"`
+}
@@ -0,0 +1,147 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PantryService keeps a user's pantry inventory and takes from it what the
+// recipes they cook use. Marking a recipe as cooked is two steps: the
+// preview proposes what to deduct, and the cook confirms or edits it.
+type PantryService interface {
+	ListItems(ctx context.Context, userID uuid.UUID) ([]PantryItemDTO, error)
+	// SaveItem creates an item, or updates one when ItemID is set
+	SaveItem(ctx context.Context, cmd SavePantryItemCommand) (*PantryItemDTO, error)
+	DeleteItem(ctx context.Context, userID, itemID uuid.UUID) error
+
+	// PreviewCook proposes what cooking the recipe takes from the pantry
+	PreviewCook(ctx context.Context, query CookPreviewQuery) (*CookPreview, error)
+	// CompleteCook marks the recipe as cooked, deducts the confirmed
+	// amounts and suggests restocks, adding them to a shopping list if
+	// one is given
+	CompleteCook(ctx context.Context, cmd CompleteCookCommand) (*CookResult, error)
+	// ConsumptionHistory totals what the user's cooks used per item
+	ConsumptionHistory(ctx context.Context, query ConsumptionQuery) (*ConsumptionReport, error)
+}
+
+// SavePantryItemCommand creates or updates a pantry item. Unit is a recipe
+// measurement unit such as "g" or "ml", empty for a count; Par is how much
+// the user likes to keep on hand.
+type SavePantryItemCommand struct {
+	UserID   uuid.UUID
+	ItemID   *uuid.UUID
+	Name     string
+	Quantity float64
+	Unit     string
+	Par      float64
+}
+
+// PantryItemDTO is one pantry item; Low is set when it has run out or
+// fallen below a quarter of its par
+type PantryItemDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Quantity  float64   `json:"quantity"`
+	Unit      string    `json:"unit"`
+	Par       float64   `json:"par"`
+	Low       bool      `json:"low"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CookPreviewQuery asks what cooking a recipe would take from the pantry.
+// Portions is zero for the recipe's own servings.
+type CookPreviewQuery struct {
+	UserID   uuid.UUID
+	RecipeID uuid.UUID
+	Portions int
+}
+
+// CookPreview is the proposed depletion for a cook
+type CookPreview struct {
+	RecipeID   uuid.UUID              `json:"recipe_id"`
+	Portions   int                    `json:"portions"`
+	Deductions []PantryDeductionDTO   `json:"deductions"`
+	Unmatched  []UnmatchedIngredient  `json:"unmatched"`
+	Restock    []RestockSuggestionDTO `json:"restock"`
+}
+
+// PantryDeductionDTO is what a cook takes from one item, in the item's
+// unit. Short is how much more the recipe needs than the pantry holds.
+type PantryDeductionDTO struct {
+	ItemID     uuid.UUID `json:"item_id"`
+	ItemName   string    `json:"item_name"`
+	Ingredient string    `json:"ingredient"`
+	Amount     float64   `json:"amount"`
+	Unit       string    `json:"unit"`
+	Short      float64   `json:"short,omitempty"`
+}
+
+// UnmatchedIngredient is an ingredient the preview does not deduct:
+// not_in_pantry, no_amount, or no_conversion to the item's unit
+type UnmatchedIngredient struct {
+	Ingredient string     `json:"ingredient"`
+	ItemID     *uuid.UUID `json:"item_id,omitempty"`
+	Reason     string     `json:"reason"`
+}
+
+// RestockSuggestionDTO is an item to buy more of after cooking
+type RestockSuggestionDTO struct {
+	ItemID   uuid.UUID `json:"item_id"`
+	Name     string    `json:"name"`
+	Quantity float64   `json:"quantity"`
+	Unit     string    `json:"unit"`
+}
+
+// CompleteCookCommand marks a recipe as cooked. Deductions are the amounts
+// the cook confirmed, usually the preview's; leaving them out marks the
+// recipe cooked without touching the pantry.
+type CompleteCookCommand struct {
+	UserID         uuid.UUID
+	RecipeID       uuid.UUID
+	Portions       int
+	Deductions     []ConfirmedDeduction
+	ShoppingListID *uuid.UUID
+}
+
+// ConfirmedDeduction is an amount to take from an item, in its unit
+type ConfirmedDeduction struct {
+	ItemID uuid.UUID `json:"item_id"`
+	Amount float64   `json:"amount"`
+}
+
+// CookResult is the logged cook with the items it changed
+type CookResult struct {
+	CookID      uuid.UUID              `json:"cook_id"`
+	RecipeID    uuid.UUID              `json:"recipe_id"`
+	Portions    int                    `json:"portions"`
+	CookedAt    time.Time              `json:"cooked_at"`
+	Items       []PantryItemDTO        `json:"items"`
+	Restock     []RestockSuggestionDTO `json:"restock"`
+	AddedToList int                    `json:"added_to_list"`
+}
+
+// ConsumptionQuery covers the last Days days, 30 when zero
+type ConsumptionQuery struct {
+	UserID uuid.UUID
+	Days   int
+}
+
+// ConsumptionReport totals what the user's cooks used
+type ConsumptionReport struct {
+	Since time.Time         `json:"since"`
+	Cooks int               `json:"cooks"`
+	Items []ItemConsumption `json:"items"`
+}
+
+// ItemConsumption is how much of one item was used, per unit it was
+// tracked in, and by how many cooks
+type ItemConsumption struct {
+	ItemID   uuid.UUID `json:"item_id"`
+	Name     string    `json:"name"`
+	Amount   float64   `json:"amount"`
+	Unit     string    `json:"unit"`
+	Cooks    int       `json:"cooks"`
+	LastUsed time.Time `json:"last_used"`
+}
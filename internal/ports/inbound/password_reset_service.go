@@ -0,0 +1,13 @@
+package inbound
+
+import "context"
+
+// PasswordResetService lets users who forgot their password choose a new
+// one through a token sent to their email address
+type PasswordResetService interface {
+	// RequestReset mails a reset token when the address has an account. It
+	// succeeds either way so callers cannot probe for accounts.
+	RequestReset(ctx context.Context, email string) error
+	// Reset sets a new password; each token works once
+	Reset(ctx context.Context, token, newPassword string) error
+}
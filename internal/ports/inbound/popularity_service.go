@@ -0,0 +1,35 @@
+package inbound
+
+import (
+	"context"
+)
+
+// PopularityService decays recipe popularity so trending favours recent
+// engagement, and rotates hidden gems, well rated recipes few people have
+// seen, into the browse and recommendation slots
+type PopularityService interface {
+	// HiddenGems returns the recipes featured by the current rotation
+	HiddenGems(ctx context.Context, limit int) (*HiddenGems, error)
+	// Refresh decays the scores, adds the activity since the last refresh
+	// and starts a new rotation when the current one is due; it does
+	// nothing if a refresh is already running
+	Refresh(ctx context.Context) error
+}
+
+// HiddenGems is the current hidden gem rotation
+type HiddenGems struct {
+	Recipes []HiddenGem `json:"recipes"`
+	// FeaturedAt is when the rotation started, NextRotationAt when it is
+	// replaced; both are empty before the first rotation
+	FeaturedAt     string `json:"featured_at,omitempty"`
+	NextRotationAt string `json:"next_rotation_at,omitempty"`
+}
+
+// HiddenGem is a recipe card in the hidden gem rotation
+type HiddenGem struct {
+	ID            string  `json:"id"`
+	Title         string  `json:"title"`
+	Cuisine       string  `json:"cuisine,omitempty"`
+	AverageRating float64 `json:"average_rating"`
+	Views         int     `json:"views"`
+}
@@ -0,0 +1,61 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// PortabilityService hands users their data to take elsewhere: an archive
+// of their recipes, collections and preferences, and the import that
+// restores one into an account
+type PortabilityService interface {
+	// ExportAccount packs everything the user keeps on the site into a
+	// zip or JSON archive
+	ExportAccount(ctx context.Context, query AccountExportQuery) (*AccountExport, error)
+	// ImportAccount restores an archive into the user's account. Recipes
+	// come back as drafts beside the ones already there, and the archive's
+	// preferences replace the current ones.
+	ImportAccount(ctx context.Context, cmd AccountImportCommand) (*AccountImportResult, error)
+}
+
+// Account archive formats
+const (
+	AccountArchiveFormatZip  = "zip"
+	AccountArchiveFormatJSON = "json"
+)
+
+// AccountExportQuery asks for a user's archive. Format defaults to zip.
+type AccountExportQuery struct {
+	UserID uuid.UUID
+	Format string
+}
+
+// AccountExport is an archive ready for download
+type AccountExport struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// AccountImportCommand carries an archive made by ExportAccount, zipped
+// or as its JSON document
+type AccountImportCommand struct {
+	UserID  uuid.UUID
+	Content []byte
+	// KeepDuplicates imports recipes whose title already exists instead
+	// of skipping them
+	KeepDuplicates bool
+}
+
+// AccountImportResult reports what an import restored
+type AccountImportResult struct {
+	// Recipes is nil when the archive holds no recipes
+	Recipes            *LibraryImportResult `json:"recipes,omitempty"`
+	Collections        int                  `json:"collections"`
+	CollectionsSkipped int                  `json:"collections_skipped"`
+	Favorites          int                  `json:"favorites"`
+	Preferences        bool                 `json:"preferences"`
+	// Warnings lists what could not be restored as it was
+	Warnings []string `json:"warnings,omitempty"`
+}
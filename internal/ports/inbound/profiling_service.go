@@ -0,0 +1,56 @@
+package inbound
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// ProfilingService lets admins profile the running server. Every request,
+// allowed or not, is recorded.
+type ProfilingService interface {
+	// StartCapture begins a capture in the background; poll GetCapture
+	// until it is no longer running
+	StartCapture(ctx context.Context, requesterID uuid.UUID, req ProfileCaptureRequest) (*ProfileCapture, error)
+	// StartSystemCapture begins a capture the server asked for itself, such
+	// as the leak watchdog's; it is audited without a requesting user
+	StartSystemCapture(ctx context.Context, kind, reason string) (*ProfileCapture, error)
+	ListCaptures(ctx context.Context, requesterID uuid.UUID) ([]*ProfileCapture, error)
+	GetCapture(ctx context.Context, requesterID, captureID uuid.UUID) (*ProfileCapture, error)
+	// OpenCapture streams a completed capture from blob storage
+	OpenCapture(ctx context.Context, requesterID, captureID uuid.UUID) (io.ReadCloser, *ProfileCapture, error)
+	// AuthorizeDebug checks and records access to the raw pprof endpoints
+	AuthorizeDebug(ctx context.Context, requesterID uuid.UUID, req DebugAccessRequest) error
+}
+
+// ProfileCaptureRequest asks for one profile. Seconds defaults to the
+// configured duration and is capped at the configured maximum.
+type ProfileCaptureRequest struct {
+	Kind      string `json:"kind"`
+	Seconds   int    `json:"seconds,omitempty"`
+	Reason    string `json:"reason"`
+	IPAddress string `json:"-"`
+}
+
+// DebugAccessRequest describes one call to a raw pprof endpoint
+type DebugAccessRequest struct {
+	Endpoint  string
+	Seconds   int
+	IPAddress string
+}
+
+// ProfileCapture is the state of one capture
+type ProfileCapture struct {
+	ID          uuid.UUID `json:"id"`
+	Kind        string    `json:"kind"`
+	Seconds     int       `json:"seconds"`
+	Status      string    `json:"status"`
+	RequestedBy uuid.UUID `json:"requested_by"`
+	Reason      string    `json:"reason,omitempty"`
+	FileName    string    `json:"file_name,omitempty"`
+	SizeBytes   int64     `json:"size_bytes"`
+	Error       string    `json:"error,omitempty"`
+	StartedAt   string    `json:"started_at"`
+	FinishedAt  string    `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,59 @@
+package inbound
+
+import (
+	"context"
+)
+
+// RecipeGraphService traverses the recipe knowledge graph: the
+// ingredients, techniques and cuisine a recipe uses, the other recipes
+// using one of them, and the recipes most related to a recipe
+type RecipeGraphService interface {
+	RecipeNodes(ctx context.Context, recipeID string) (*GraphNodes, error)
+	NodeRecipes(ctx context.Context, kind, key string, limit int) (*GraphNodeRecipes, error)
+	Related(ctx context.Context, recipeID string, limit int) (*RelatedRecipes, error)
+	// Refresh rebuilds the graph from the published recipes; it does
+	// nothing if a refresh is already running
+	Refresh(ctx context.Context) error
+}
+
+// GraphNode is an ingredient, technique or cuisine
+type GraphNode struct {
+	Kind    string `json:"kind"`
+	Key     string `json:"key"`
+	Label   string `json:"label"`
+	Recipes int    `json:"recipes"`
+}
+
+// GraphNodes are the nodes a recipe links to, by kind
+type GraphNodes struct {
+	RecipeID    string      `json:"recipe_id"`
+	Ingredients []GraphNode `json:"ingredients"`
+	Techniques  []GraphNode `json:"techniques"`
+	Cuisines    []GraphNode `json:"cuisines"`
+	RefreshedAt string      `json:"refreshed_at,omitempty"`
+}
+
+// GraphNodeRecipes are the recipes linked to one node, most liked first
+type GraphNodeRecipes struct {
+	Node        GraphNode     `json:"node"`
+	Recipes     []GraphRecipe `json:"recipes"`
+	RefreshedAt string        `json:"refreshed_at,omitempty"`
+}
+
+// RelatedRecipes are the recipes sharing the most with a recipe
+type RelatedRecipes struct {
+	RecipeID    string        `json:"recipe_id"`
+	Recipes     []GraphRecipe `json:"recipes"`
+	RefreshedAt string        `json:"refreshed_at,omitempty"`
+}
+
+// GraphRecipe is a recipe card reached through the graph. Score and Shared
+// are only set on related recipes.
+type GraphRecipe struct {
+	ID            string      `json:"id"`
+	Title         string      `json:"title"`
+	AverageRating float64     `json:"average_rating"`
+	Likes         int         `json:"likes"`
+	Score         float64     `json:"score,omitempty"`
+	Shared        []GraphNode `json:"shared,omitempty"`
+}
@@ -4,6 +4,8 @@ package inbound
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/alchemorsel/v3/internal/domain/recipe"
 	"github.com/google/uuid"
@@ -19,17 +21,83 @@ type RecipeService interface {
 	ArchiveRecipe(ctx context.Context, recipeID, userID uuid.UUID) error
 	DeleteRecipe(ctx context.Context, recipeID, userID uuid.UUID) error
 	
+	// Scheduled publishing and the announcements sent when a recipe goes live
+	SchedulePublish(ctx context.Context, cmd SchedulePublishCommand) (*RecipeDTO, error)
+	CancelScheduledPublish(ctx context.Context, recipeID, userID uuid.UUID) (*RecipeDTO, error)
+	PublishDueRecipes(ctx context.Context) (int, error)
+	DeliverAnnouncements(ctx context.Context) (int, error)
+	
+	// Undo operations for destructive commands
+	RestoreRecipe(ctx context.Context, recipeID, userID uuid.UUID) error
+	UnarchiveRecipe(ctx context.Context, recipeID, userID uuid.UUID) error
+	
+	// Take a recipe down or put it back on the moderators' behalf, e.g.
+	// when readers report it; callers authorize
+	TakeDownRecipe(ctx context.Context, recipeID uuid.UUID) error
+	ReinstateRecipe(ctx context.Context, recipeID uuid.UUID) error
+	
 	// Recipe interactions
 	LikeRecipe(ctx context.Context, recipeID, userID uuid.UUID) error
 	UnlikeRecipe(ctx context.Context, recipeID, userID uuid.UUID) error
 	RateRecipe(ctx context.Context, cmd RateRecipeCommand) error
+	// ForkRecipe copies a published recipe into a draft owned by the user
+	ForkRecipe(ctx context.Context, recipeID, userID uuid.UUID) (*RecipeDTO, error)
+	
+	// Favorites: recipes a user saves privately, apart from likes
+	FavoriteRecipe(ctx context.Context, recipeID, userID uuid.UUID) (*FavoriteStatus, error)
+	UnfavoriteRecipe(ctx context.Context, recipeID, userID uuid.UUID) (*FavoriteStatus, error)
+	GetFavoriteRecipes(ctx context.Context, userID uuid.UUID, params PaginationParams) (*RecipeList, error)
 	
 	// Queries - operations that read state
 	GetRecipeByID(ctx context.Context, recipeID uuid.UUID) (*RecipeDTO, error)
+	GetRecipesByIDs(ctx context.Context, recipeIDs []uuid.UUID) ([]RecipeDTO, error)
 	GetRecipesByUser(ctx context.Context, userID uuid.UUID, params PaginationParams) (*RecipeList, error)
 	SearchRecipes(ctx context.Context, query SearchQuery) (*RecipeList, error)
 	GetTrendingRecipes(ctx context.Context, params PaginationParams) (*RecipeList, error)
 	GetRecommendedRecipes(ctx context.Context, userID uuid.UUID, params PaginationParams) (*RecipeList, error)
+	GetSimilarRecipes(ctx context.Context, recipeID uuid.UUID, limit int) (*RecipeList, error)
+	GetSearchFacets(ctx context.Context) (*SearchFacets, error)
+	
+	// Personal lists on the home page: recipes to get back to and new
+	// recipes from the chefs the user liked
+	GetRecentlyViewedRecipes(ctx context.Context, userID uuid.UUID, limit int) ([]RecipeDTO, error)
+	GetChefActivity(ctx context.Context, userID uuid.UUID, limit int) ([]RecipeDTO, error)
+	
+	// Search analytics
+	GetZeroResultQueries(ctx context.Context, requesterID uuid.UUID, days, limit int) ([]ZeroResultQuery, error)
+	
+	// Recipe page analytics: view beacons from the page and the author stats
+	RecordRecipeView(ctx context.Context, cmd RecordRecipeViewCommand) (uuid.UUID, error)
+	RecordRecipeEngagement(ctx context.Context, cmd RecordRecipeEngagementCommand) error
+	GetRecipeAnalytics(ctx context.Context, query RecipeAnalyticsQuery) (*RecipeAnalytics, error)
+	// Views, likes, ratings and search listings across the author's recipes
+	GetAuthorAnalytics(ctx context.Context, query AuthorAnalyticsQuery) (*AuthorAnalytics, error)
+	
+	// Import a printed recipe from a photo or scan as an editable draft
+	ImportRecipeFromImage(ctx context.Context, cmd ImportRecipeImageCommand) (*RecipeImport, error)
+	// Import a library exported from another recipe manager as drafts
+	ImportRecipeLibrary(ctx context.Context, cmd ImportLibraryCommand) (*LibraryImportResult, error)
+	// Map an imported recipe onto a draft the way a library import would,
+	// without saving it
+	PreviewImportedRecipe(ctx context.Context, userID uuid.UUID, imported ImportedRecipe) (*ImportPreview, error)
+	
+	// Check recipe JSON-LD against search engine rich result requirements
+	ValidateStructuredData(ctx context.Context, query StructuredDataQuery) (*StructuredDataReport, error)
+	
+	// Field-level changes for clients that sync recipes incrementally
+	GetRecipeChanges(ctx context.Context, query RecipeChangesQuery) (*RecipeChanges, error)
+	
+	// Kitchen tickets for chefs: a recipe scaled to a batch, weighed and
+	// split by station, as data or ready to print
+	GetKitchenTicket(ctx context.Context, query KitchenTicketQuery) (*KitchenTicket, error)
+	PrintKitchenTicket(ctx context.Context, query KitchenTicketQuery) (*PrintedKitchenTicket, error)
+	
+	// Ingredients recomputed for a serving count, in units a cook measures
+	ScaleRecipe(ctx context.Context, query ScaleRecipeQuery) (*ScaledRecipe, error)
+	
+	// Recompute nutrition and cost for proposed ingredient swaps without
+	// saving them
+	SimulateWhatIf(ctx context.Context, query WhatIfQuery) (*WhatIfResult, error)
 	
 	// AI operations
 	GenerateRecipeWithAI(ctx context.Context, cmd GenerateRecipeCommand) (*RecipeDTO, error)
@@ -44,6 +112,7 @@ type CreateRecipeCommand struct {
 	Title        string
 	Description  string
 	AuthorID     uuid.UUID
+	Language     string // BCP 47 tag; English when empty
 	Ingredients  []CreateIngredientCommand
 	Instructions []CreateInstructionCommand
 	Cuisine      recipe.CuisineType
@@ -112,6 +181,37 @@ type GenerateRecipeCommand struct {
 	Cuisine     recipe.CuisineType
 	Dietary     []string
 	MaxCalories int
+	Language    string // BCP 47 tag to write the recipe in; English when empty
+}
+
+// ImportRecipeImageCommand carries a photo or scan of a printed recipe
+type ImportRecipeImageCommand struct {
+	UserID      uuid.UUID
+	Image       []byte
+	ContentType string
+}
+
+// ImportLibraryCommand carries recipes decoded from a recipe manager export
+type ImportLibraryCommand struct {
+	UserID  uuid.UUID
+	Source  string // paprika, mealie or nextcloud
+	Recipes []ImportedRecipe
+	// KeepDuplicates imports recipes whose title already exists instead of skipping them
+	KeepDuplicates bool
+}
+
+// ImportedRecipe is one recipe from an export in a format-neutral shape.
+// Ingredient lines are free text; each direction is one step.
+type ImportedRecipe struct {
+	Title       string
+	Description string
+	Ingredients []string
+	Directions  []string
+	Servings    int
+	PrepTime    int // minutes
+	CookTime    int // minutes
+	Tags        []string
+	SourceURL   string
 }
 
 // Query objects
@@ -123,15 +223,55 @@ type SearchQuery struct {
 	Category   []recipe.CategoryType
 	Difficulty []recipe.DifficultyLevel
 	MaxTime    int // total time in minutes
-	Dietary    []string
+	Dietary    []string // tags such as vegan every result carries; "any" lifts the user's own diets
 	Tags       []string
 	Pagination PaginationParams
+	// After is the next_cursor of the previous page. The listings sorted
+	// by trending, top, new, rating or prep_time are paged by cursor, and
+	// ignore the page number.
+	After string
+	
+	// AIGenerated keeps only AI recipes when true, only chef recipes when
+	// false
+	AIGenerated *bool
+	// Facets counts the results per filter value
+	Facets bool
+	// Semantic ranks by meaning, blending how alike each recipe is to
+	// Text with its keyword rank, so "cozy winter dinner" finds stews
+	Semantic bool
+	
+	// UserID enables personalized ranking when Personalize is set
+	UserID      *uuid.UUID
+	Personalize bool
+	// Favorited keeps only recipes UserID saved; favorited:true in Text
+	// sets it too
+	Favorited bool
+	// Explain attaches per-result ranking factors to the list
+	Explain bool
+}
+
+// FavoriteStatus is whether a user has saved a recipe, with how many
+// recipes they have saved in all
+type FavoriteStatus struct {
+	RecipeID  uuid.UUID `json:"recipe_id"`
+	Favorited bool      `json:"favorited"`
+	Favorites int       `json:"favorites"`
+}
+
+// LikeStatus is whether a user likes a recipe, with how many likes the
+// recipe has
+type LikeStatus struct {
+	RecipeID uuid.UUID `json:"recipe_id"`
+	Liked    bool      `json:"liked"`
+	Likes    int       `json:"likes"`
 }
 
 // PaginationParams for paginated queries
 type PaginationParams struct {
 	Page     int
 	PageSize int
+	// OrderBy is a column such as title or likes, or one of the
+	// listings: trending, top, new, rating or prep_time
 	OrderBy  string
 	Order    string // asc or desc
 }
@@ -145,9 +285,14 @@ type RecipeDTO struct {
 	Description  string                   `json:"description"`
 	AuthorID     uuid.UUID                `json:"author_id"`
 	AuthorName   string                   `json:"author_name"`
+	// AuthorBadge is set when the author is verified; only recipe
+	// handlers that look badges up fill it in
+	AuthorBadge  *VerificationBadge       `json:"author_badge,omitempty"`
+	Language     string                   `json:"language"`
 	Ingredients  []IngredientDTO          `json:"ingredients"`
 	Instructions []InstructionDTO         `json:"instructions"`
 	Nutrition    *NutritionDTO            `json:"nutrition,omitempty"`
+	Allergens    *AllergenLabelDTO        `json:"allergens,omitempty"`
 	Cuisine      recipe.CuisineType       `json:"cuisine"`
 	Category     recipe.CategoryType      `json:"category"`
 	Difficulty   recipe.DifficultyLevel   `json:"difficulty"`
@@ -160,6 +305,7 @@ type RecipeDTO struct {
 	Images       []ImageDTO               `json:"images"`
 	Likes        int                      `json:"likes"`
 	Views        int                      `json:"views"`
+	Forks        int                      `json:"forks"`
 	Rating       float64                  `json:"rating"`
 	RatingCount  int                      `json:"rating_count"`
 	Status       recipe.RecipeStatus      `json:"status"`
@@ -167,6 +313,29 @@ type RecipeDTO struct {
 	CreatedAt    string                   `json:"created_at"`
 	UpdatedAt    string                   `json:"updated_at"`
 	PublishedAt  *string                  `json:"published_at,omitempty"`
+	
+	// Set while a draft waits to publish itself
+	ScheduledPublishAt *string `json:"scheduled_publish_at,omitempty"`
+	
+	// Latest change log revision; only set on single recipe reads
+	Revision int64 `json:"revision"`
+	
+	// Set on forks. ForkedFrom describes the original on single recipe
+	// reads and is left out once the original is gone.
+	ForkedFromID *uuid.UUID    `json:"forked_from_id,omitempty"`
+	ForkedFrom   *RecipeOrigin `json:"forked_from,omitempty"`
+	
+	// AllergyWarnings lists the signed-in user's allergies the recipe
+	// contains or may contain; only set in search results
+	AllergyWarnings []string `json:"allergy_warnings,omitempty"`
+}
+
+// RecipeOrigin is the recipe a fork was copied from
+type RecipeOrigin struct {
+	ID         uuid.UUID `json:"id"`
+	Title      string    `json:"title"`
+	AuthorID   uuid.UUID `json:"author_id"`
+	AuthorName string    `json:"author_name"`
 }
 
 // IngredientDTO for ingredient data
@@ -204,6 +373,14 @@ type NutritionDTO struct {
 	Sugar         float64 `json:"sugar"`
 	Sodium        float64 `json:"sodium"`
 	Cholesterol   float64 `json:"cholesterol"`
+	Coverage      float64 `json:"coverage,omitempty"` // share of required ingredients counted in a recipe's nutrition
+}
+
+// AllergenLabelDTO lists the major allergens a recipe's ingredients
+// contain or may contain, e.g. "milk", "tree-nuts"
+type AllergenLabelDTO struct {
+	Contains   []string `json:"contains"`
+	MayContain []string `json:"may_contain"`
 }
 
 // ImageDTO for image data
@@ -222,6 +399,466 @@ type RecipeList struct {
 	Page       int         `json:"page"`
 	PageSize   int         `json:"page_size"`
 	TotalPages int         `json:"total_pages"`
+	// NextCursor continues a listing paged by cursor; it is empty on the
+	// last page
+	NextCursor string `json:"next_cursor,omitempty"`
+	
+	// Personalized is true when the results were re-ranked for the caller
+	Personalized bool                 `json:"personalized"`
+	// Semantic is true when the results were ranked by meaning; a
+	// semantic search falls back to keywords while no vectors are available
+	Semantic bool `json:"semantic,omitempty"`
+	Explanations []RankingExplanation `json:"explanations,omitempty"`
+	// Fallback is set when a text search matched nothing
+	Fallback *SearchFallback `json:"fallback,omitempty"`
+	// Facets is set when the search asked for them
+	Facets *ResultFacets `json:"facets,omitempty"`
+	// DefaultDiets are the user's diets a search without a diet filter
+	// was narrowed to
+	DefaultDiets []string `json:"default_diets,omitempty"`
+}
+
+// SearchFallback offers ways forward from a search with no results
+type SearchFallback struct {
+	Query       string   `json:"query"`
+	Suggestions []string `json:"suggestions"`
+	// GeneratePrompt can be sent to the AI generator to create a matching recipe
+	GeneratePrompt string `json:"generate_prompt"`
+}
+
+// ZeroResultQuery is a query that repeatedly found nothing
+type ZeroResultQuery struct {
+	Query    string `json:"query"`
+	Count    int    `json:"count"`
+	LastSeen string `json:"last_seen"`
+}
+
+// SearchFacets are the filter values offered on the search page with how
+// many published recipes have each
+type SearchFacets struct {
+	Cuisines     []FacetCount `json:"cuisines"`
+	Categories   []FacetCount `json:"categories"`
+	Difficulties []FacetCount `json:"difficulties"`
+	Tags         []FacetCount `json:"tags"`
+}
+
+// ResultFacets count the results of a search per filter value. A facet
+// ignores its own filter, so the other values can still be chosen.
+type ResultFacets struct {
+	Cuisines     []FacetCount `json:"cuisines"`
+	Difficulties []FacetCount `json:"difficulties"`
+	MaxTime      []FacetCount `json:"max_time"`
+	Diets        []FacetCount `json:"diets"`
+	AIGenerated  []FacetCount `json:"ai_generated"`
+}
+
+// FacetCount is one filter value and its recipe count
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SchedulePublishCommand sets when a draft publishes itself
+type SchedulePublishCommand struct {
+	RecipeID  uuid.UUID
+	UserID    uuid.UUID
+	PublishAt time.Time
+}
+
+// RecordRecipeViewCommand describes a visit to a recipe page. UserID is nil
+// for anonymous visitors, who are told apart by SessionID.
+type RecordRecipeViewCommand struct {
+	RecipeID  uuid.UUID
+	UserID    *uuid.UUID
+	SessionID string
+	UserAgent string
+	Referrer  string
+	// SearchTerm is the on-site search that led here; when empty it is read
+	// from the referrer's query string
+	SearchTerm string
+}
+
+// RecordRecipeEngagementCommand reports how far a visitor scrolled (0-100)
+// and how long they stayed, sent when they leave the page
+type RecordRecipeEngagementCommand struct {
+	RecipeID    uuid.UUID
+	ViewID      uuid.UUID
+	ScrollDepth int
+	EngagedMS   int
+}
+
+// RecipeAnalyticsQuery selects the recipe and window of an author stats page
+type RecipeAnalyticsQuery struct {
+	RequesterID uuid.UUID
+	RecipeID    uuid.UUID
+	Days        int
+}
+
+// RecipeAnalytics is the author stats page for one recipe. Conversions are
+// likes and bookmarks per unique viewer; AverageScrollDepth is nil until a
+// visitor has reported one.
+type RecipeAnalytics struct {
+	RecipeID           uuid.UUID        `json:"recipe_id"`
+	Title              string           `json:"title"`
+	Since              string           `json:"since"`
+	Until              string           `json:"until"`
+	Views              int              `json:"views"`
+	UniqueViewers      int              `json:"unique_viewers"`
+	Likes              int              `json:"likes"`
+	Bookmarks          int              `json:"bookmarks"`
+	LikeConversion     float64          `json:"like_conversion"`
+	BookmarkConversion float64          `json:"bookmark_conversion"`
+	AverageScrollDepth *float64         `json:"average_scroll_depth"`
+	ScrollSamples      int              `json:"scroll_samples"`
+	Daily              []DailyViews     `json:"daily"`
+	SearchTerms        []AnalyticsCount `json:"search_terms"`
+	Referrers          []AnalyticsCount `json:"referrers"`
+}
+
+// DailyViews is one day of the views chart, in UTC
+type DailyViews struct {
+	Date          string `json:"date"`
+	Views         int    `json:"views"`
+	UniqueViewers int    `json:"unique_viewers"`
+}
+
+// AuthorAnalyticsQuery selects the window of an author's analytics
+type AuthorAnalyticsQuery struct {
+	AuthorID uuid.UUID
+	Days     int
+}
+
+// AuthorAnalytics is the activity on all of an author's recipes over a
+// window. Views are counted once per viewer; impressions are listings in
+// text search results. AverageRating is of the ratings given in the window
+// and nil when there were none.
+type AuthorAnalytics struct {
+	Since         string              `json:"since"`
+	Until         string              `json:"until"`
+	Views         int                 `json:"views"`
+	Likes         int                 `json:"likes"`
+	Impressions   int                 `json:"impressions"`
+	Ratings       int                 `json:"ratings"`
+	AverageRating *float64            `json:"average_rating"`
+	Daily         []AuthorDailyStats  `json:"daily"`
+	Recipes       []AuthorRecipeStats `json:"recipes"`
+}
+
+// AuthorDailyStats is one UTC day of an author's charts
+type AuthorDailyStats struct {
+	Date          string   `json:"date"`
+	Views         int      `json:"views"`
+	Likes         int      `json:"likes"`
+	Impressions   int      `json:"impressions"`
+	Ratings       int      `json:"ratings"`
+	AverageRating *float64 `json:"average_rating"`
+}
+
+// AuthorRecipeStats is one recipe's totals over the window, with the
+// recipe's overall rating
+type AuthorRecipeStats struct {
+	RecipeID      uuid.UUID `json:"recipe_id"`
+	Title         string    `json:"title"`
+	Views         int       `json:"views"`
+	Likes         int       `json:"likes"`
+	Impressions   int       `json:"impressions"`
+	Ratings       int       `json:"ratings"`
+	AverageRating *float64  `json:"average_rating"`
+	Rating        float64   `json:"rating"`
+}
+
+// AnalyticsCount is a search term or referrer host with its view count
+type AnalyticsCount struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// RecipeChangesQuery asks for a recipe's change sets after the revision the
+// client holds. Since is 0 for a client that has never synced.
+type RecipeChangesQuery struct {
+	RequesterID uuid.UUID
+	RecipeID    uuid.UUID
+	Since       int64
+	Limit       int
+}
+
+// RecipeChanges is a page of change sets. HasMore means the client should
+// ask again from the last revision returned. Reset means the client holds
+// a revision this server never issued and should download the recipe again.
+type RecipeChanges struct {
+	RecipeID uuid.UUID         `json:"recipe_id"`
+	Since    int64             `json:"since"`
+	Revision int64             `json:"revision"`
+	Changes  []RecipeChangeSet `json:"changes"`
+	HasMore  bool              `json:"has_more"`
+	Reset    bool              `json:"reset"`
+}
+
+// RecipeChangeSet holds the new value of each field a revision changed,
+// keyed and shaped as in RecipeDTO. The first revision carries every
+// synced field.
+type RecipeChangeSet struct {
+	Revision  int64                      `json:"revision"`
+	ChangedAt string                     `json:"changed_at"`
+	Fields    map[string]json.RawMessage `json:"fields"`
+}
+
+// RecipeImport is the draft created from a photo together with the
+// recognized lines the cook should double-check before publishing
+type RecipeImport struct {
+	Recipe   RecipeDTO      `json:"recipe"`
+	Provider string         `json:"provider"`
+	Review   []ImportRegion `json:"review"`
+}
+
+// ImportPreview is the draft an imported recipe would be saved as. The
+// recipe has no ID yet; DuplicateOf is set when the user already has a
+// recipe with the same title.
+type ImportPreview struct {
+	Recipe      RecipeDTO  `json:"recipe"`
+	Warnings    []string   `json:"warnings,omitempty"`
+	DuplicateOf *uuid.UUID `json:"duplicate_of,omitempty"`
+}
+
+// ImportRegion flags one recognized line. Index is the line's position in
+// the draft's ingredients or instructions, or -1 when it was left out.
+type ImportRegion struct {
+	Section    string  `json:"section"`
+	Index      int     `json:"index"`
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason"`
+}
+
+// LibraryImportResult reports what happened to each recipe of an import
+type LibraryImportResult struct {
+	Source     string              `json:"source"`
+	Created    int                 `json:"created"`
+	Duplicates int                 `json:"duplicates"`
+	Failed     int                 `json:"failed"`
+	Items      []LibraryImportItem `json:"items"`
+}
+
+// LibraryImportItem is the outcome for one imported recipe. Status is
+// created, duplicate or failed.
+type LibraryImportItem struct {
+	Title       string     `json:"title"`
+	Status      string     `json:"status"`
+	RecipeID    *uuid.UUID `json:"recipe_id,omitempty"`
+	DuplicateOf *uuid.UUID `json:"duplicate_of,omitempty"`
+	Warnings    []string   `json:"warnings,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// StructuredDataQuery selects recipes for a structured data check. Without a
+// RecipeID every published recipe the requester may see is checked: all of
+// them for admins, their own for authors.
+type StructuredDataQuery struct {
+	RequesterID uuid.UUID
+	RecipeID    *uuid.UUID
+	// CheckImages fetches each image to verify its size and aspect ratio
+	CheckImages bool
+}
+
+// StructuredDataReport lists the structured data gaps per recipe. Reports
+// over many recipes leave out recipes without issues.
+type StructuredDataReport struct {
+	GeneratedAt string                 `json:"generated_at"`
+	Checked     int                    `json:"checked"`
+	Eligible    int                    `json:"eligible"`
+	Recipes     []RecipeStructuredData `json:"recipes"`
+}
+
+// RecipeStructuredData is the check result for one recipe. Document is the
+// JSON-LD that was checked and is only included for single recipe checks.
+type RecipeStructuredData struct {
+	RecipeID uuid.UUID              `json:"recipe_id"`
+	Title    string                 `json:"title"`
+	Eligible bool                   `json:"eligible"`
+	Issues   []StructuredDataIssue  `json:"issues"`
+	Document map[string]interface{} `json:"document,omitempty"`
+}
+
+// StructuredDataIssue is a missing or invalid JSON-LD field. Severity is
+// error when it blocks rich results and warning for recommended fields.
+type StructuredDataIssue struct {
+	Field    string `json:"field"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Kitchen ticket print formats
+const (
+	KitchenTicketFormatPDF    = "pdf"
+	KitchenTicketFormatESCPOS = "escpos"
+)
+
+// KitchenTicketQuery asks for a recipe's kitchen ticket. Portions is the
+// batch size, zero for the recipe's own servings; Columns is the printer's
+// line width, zero for an 80mm printer.
+type KitchenTicketQuery struct {
+	RequesterID uuid.UUID
+	RecipeID    uuid.UUID
+	Portions    int
+	Format      string
+	Columns     int
+}
+
+// KitchenTicket is a recipe scaled to a batch with its ingredients in grams,
+// split by the station that uses them
+type KitchenTicket struct {
+	RecipeID  uuid.UUID              `json:"recipe_id"`
+	Title     string                 `json:"title"`
+	Portions  int                    `json:"portions"`
+	Scale     float64                `json:"scale"`
+	Unweighed int                    `json:"unweighed"`
+	Stations  []KitchenTicketStation `json:"stations"`
+}
+
+// KitchenTicketStation is what one station on the line needs and does
+type KitchenTicketStation struct {
+	Station     string              `json:"station"`
+	Ingredients []KitchenTicketLine `json:"ingredients"`
+	Steps       []KitchenTicketStep `json:"steps"`
+}
+
+// KitchenTicketLine is one scaled ingredient. Ingredients with no known
+// weight keep their measure instead.
+type KitchenTicketLine struct {
+	Name     string  `json:"name"`
+	Grams    float64 `json:"grams,omitempty"`
+	Weighed  bool    `json:"weighed"`
+	Measure  string  `json:"measure,omitempty"`
+	Optional bool    `json:"optional,omitempty"`
+}
+
+// KitchenTicketStep is one recipe step at its station
+type KitchenTicketStep struct {
+	Number          int      `json:"number"`
+	Text            string   `json:"text"`
+	DurationMinutes int      `json:"duration_minutes,omitempty"`
+	TemperatureC    *float64 `json:"temperature_c,omitempty"`
+}
+
+// PrintedKitchenTicket is a kitchen ticket rendered for a printer
+type PrintedKitchenTicket struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// ScaleRecipeQuery asks for a recipe's ingredients for a number of
+// servings, zero for the recipe's own. Units is metric, imperial or
+// original; blank uses the requester's preference. Without a requester
+// only published recipes are found.
+type ScaleRecipeQuery struct {
+	RequesterID uuid.UUID
+	RecipeID    uuid.UUID
+	Servings    int
+	Units       string
+}
+
+// ScaledRecipe is a recipe's ingredients recomputed for a serving count
+type ScaledRecipe struct {
+	RecipeID         uuid.UUID          `json:"recipe_id"`
+	Title            string             `json:"title"`
+	Servings         int                `json:"servings"`
+	OriginalServings int                `json:"original_servings"`
+	Scale            float64            `json:"scale"`
+	Units            string             `json:"units"`
+	Ingredients      []ScaledIngredient `json:"ingredients"`
+}
+
+// ScaledIngredient is one ingredient at the requested servings. Amount
+// and Unit are normalized, so 48 tsp comes back as 1 cup; Quantity is
+// how the recipe page shows them.
+type ScaledIngredient struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Amount   float64   `json:"amount"`
+	Unit     string    `json:"unit,omitempty"`
+	Quantity string    `json:"quantity"`
+	Optional bool      `json:"optional,omitempty"`
+	Notes    string    `json:"notes,omitempty"`
+}
+
+// WhatIfQuery proposes changes to a recipe's ingredients to estimate,
+// without saving them
+type WhatIfQuery struct {
+	RequesterID uuid.UUID
+	RecipeID    uuid.UUID
+	Changes     []IngredientChange
+}
+
+// IngredientChange swaps an ingredient, changes its quantity or removes
+// it. Without an ingredient ID it adds a new ingredient, which needs a
+// name and an amount. Fields left out keep what the recipe has.
+type IngredientChange struct {
+	IngredientID *uuid.UUID `json:"ingredient_id,omitempty"`
+	Remove       bool       `json:"remove,omitempty"`
+	Name         string     `json:"name,omitempty"`
+	Amount       *float64   `json:"amount,omitempty"`
+	Unit         *string    `json:"unit,omitempty"`
+}
+
+// WhatIfResult compares the recipe as saved with the proposed changes.
+// Difference is proposed minus original, per serving.
+type WhatIfResult struct {
+	RecipeID   uuid.UUID         `json:"recipe_id"`
+	Currency   string            `json:"currency"`
+	Original   NutritionEstimate `json:"original"`
+	Proposed   NutritionEstimate `json:"proposed"`
+	Difference WhatIfDifference  `json:"difference"`
+}
+
+// NutritionEstimate is nutrition and ingredient cost estimated from a
+// reference table. Coverage is the share of required ingredients the
+// estimate could account for.
+type NutritionEstimate struct {
+	Servings       int                   `json:"servings"`
+	Total          NutritionDTO          `json:"total"`
+	PerServing     NutritionDTO          `json:"per_serving"`
+	Cost           float64               `json:"cost"`
+	CostPerServing float64               `json:"cost_per_serving"`
+	Coverage       float64               `json:"coverage"`
+	Ingredients    []EstimatedIngredient `json:"ingredients"`
+}
+
+// EstimatedIngredient is one ingredient's share of an estimate. Reason
+// says why it was left out: optional, no_amount, no_weight or
+// unknown_food.
+type EstimatedIngredient struct {
+	IngredientID *uuid.UUID   `json:"ingredient_id,omitempty"`
+	Name         string       `json:"name"`
+	Amount       float64      `json:"amount"`
+	Unit         string       `json:"unit"`
+	Food         string       `json:"food,omitempty"`
+	Grams        float64      `json:"grams"`
+	Nutrition    NutritionDTO `json:"nutrition"`
+	Cost         float64      `json:"cost"`
+	Reason       string       `json:"reason,omitempty"`
+}
+
+// WhatIfDifference is how the proposal moves the per-serving estimate
+type WhatIfDifference struct {
+	PerServing     NutritionDTO `json:"per_serving"`
+	CostPerServing float64      `json:"cost_per_serving"`
+}
+
+// RankingExplanation describes why a search result ranked where it did
+type RankingExplanation struct {
+	RecipeID     uuid.UUID       `json:"recipe_id"`
+	OriginalRank int             `json:"original_rank"`
+	Rank         int             `json:"rank"`
+	Score        float64         `json:"score"`
+	Factors      []RankingFactor `json:"factors"`
+}
+
+// RankingFactor is one signal's contribution to a result's score
+type RankingFactor struct {
+	Signal string  `json:"signal"`
+	Detail string  `json:"detail"`
+	Weight float64 `json:"weight"`
 }
 
 // NutritionAnalysis for AI nutrition analysis
@@ -0,0 +1,57 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecipeTimelineService plans when to start each step of a recipe so it is
+// ready at a chosen time, and replans when the cook falls behind
+type RecipeTimelineService interface {
+	Plan(ctx context.Context, query TimelineQuery) (*RecipeTimeline, error)
+}
+
+// TimelineQuery asks for a recipe's timeline
+type TimelineQuery struct {
+	// RequesterID is uuid.Nil for anonymous readers, who only see
+	// published recipes
+	RequesterID uuid.UUID
+	RecipeID    string
+	// ServeAt is when the food should be ready; zero means as soon as
+	// possible
+	ServeAt time.Time
+	// CompletedSteps is how many steps the cook has finished; the rest are
+	// planned from now
+	CompletedSteps int
+}
+
+// RecipeTimeline is a recipe's steps scheduled backwards from ServeAt
+type RecipeTimeline struct {
+	RecipeID string `json:"recipe_id"`
+	Title    string `json:"title"`
+	// ServeAt is when the food will be ready, later than asked when the
+	// cook is behind
+	ServeAt        string         `json:"serve_at"`
+	RequestedAt    string         `json:"requested_serve_at"`
+	StartAt        string         `json:"start_at"`
+	TotalMinutes   int            `json:"total_minutes"`
+	ActiveMinutes  int            `json:"active_minutes"`
+	BehindMinutes  int            `json:"behind_minutes"`
+	CompletedSteps int            `json:"completed_steps"`
+	Steps          []TimelineSlot `json:"steps"`
+}
+
+// TimelineSlot is when one step runs
+type TimelineSlot struct {
+	Number  int    `json:"number"`
+	Text    string `json:"text"`
+	StartAt string `json:"start_at"`
+	EndAt   string `json:"end_at"`
+	Minutes int    `json:"minutes"`
+	// HandsOff steps leave the cook free for the steps alongside them
+	HandsOff bool `json:"hands_off"`
+	// Parallel steps run alongside the step before them
+	Parallel bool `json:"parallel"`
+}
@@ -0,0 +1,29 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RecommendationService precomputes the "Recipes you might like" list of
+// every recently active user from what people with similar tastes engaged
+// with and from what the recipes they engaged with have in common
+type RecommendationService interface {
+	// For returns a user's recommendations, best first. It is empty for
+	// users the last refresh found no activity for.
+	For(ctx context.Context, userID uuid.UUID) ([]Recommendation, error)
+	// Refresh recomputes every active user's recommendations; it does
+	// nothing if a refresh is already running
+	Refresh(ctx context.Context) error
+	// Similar returns up to limit published recipes sharing the most
+	// cuisine, tags and ingredients with a recipe, best first
+	Similar(ctx context.Context, recipeID uuid.UUID, limit int) ([]Recommendation, error)
+}
+
+// Recommendation is a recipe suggested to a user and why
+type Recommendation struct {
+	RecipeID uuid.UUID       `json:"recipe_id"`
+	Score    float64         `json:"score"`
+	Factors  []RankingFactor `json:"factors"`
+}
@@ -0,0 +1,69 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportService lets readers report recipes and comments that break the
+// rules, and admins work through the open reports. Content reported by
+// enough readers is taken down until an admin reviews it.
+type ReportService interface {
+	// ReportRecipe and ReportComment refuse a second open report by the
+	// same reader on the same content
+	ReportRecipe(ctx context.Context, cmd ReportContentCommand) (*ReportDTO, error)
+	ReportComment(ctx context.Context, cmd ReportContentCommand) (*ReportDTO, error)
+	// ListOpenReports pages through open reports, oldest first. Admins only.
+	ListOpenReports(ctx context.Context, requesterID uuid.UUID, limit, offset int) (*ReportPage, error)
+	// ResolveReport upholds a report and takes the content down;
+	// DismissReport keeps it up, restoring content taken down by the
+	// report threshold. Both close every open report on the same content.
+	ResolveReport(ctx context.Context, cmd ReviewReportCommand) (*ReportDTO, error)
+	DismissReport(ctx context.Context, cmd ReviewReportCommand) (*ReportDTO, error)
+}
+
+// ReportContentCommand is a reader reporting a recipe or comment. Reason
+// is spam, abuse, inappropriate, copyright or other.
+type ReportContentCommand struct {
+	TargetID   uuid.UUID
+	ReporterID uuid.UUID
+	Reason     string
+	Note       string
+}
+
+// ReviewReportCommand is an admin's verdict on a report
+type ReviewReportCommand struct {
+	ReportID    uuid.UUID
+	ModeratorID uuid.UUID
+	Note        string
+}
+
+// ReportDTO is a report. OpenReports and Summary describe the reported
+// content and are only set for admins.
+type ReportDTO struct {
+	ID          uuid.UUID  `json:"id"`
+	TargetType  string     `json:"target_type"`
+	TargetID    uuid.UUID  `json:"target_id"`
+	RecipeID    uuid.UUID  `json:"recipe_id"`
+	ReporterID  uuid.UUID  `json:"reporter_id"`
+	Reason      string     `json:"reason"`
+	Note        string     `json:"note,omitempty"`
+	Status      string     `json:"status"`
+	Unpublished bool       `json:"unpublished"`
+	OpenReports int        `json:"open_reports,omitempty"`
+	Summary     string     `json:"summary,omitempty"`
+	ResolvedBy  *uuid.UUID `json:"resolved_by,omitempty"`
+	Resolution  string     `json:"resolution,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
+
+// ReportPage is one page of open reports
+type ReportPage struct {
+	Reports []ReportDTO `json:"reports"`
+	Total   int64       `json:"total"`
+	Limit   int         `json:"limit"`
+	Offset  int         `json:"offset"`
+}
@@ -0,0 +1,33 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SandboxService describes the public sandbox to visitors and shows admins
+// the messages it kept instead of sending
+type SandboxService interface {
+	// Status tells clients whether to show the sandbox banner
+	Status(ctx context.Context) *SandboxStatus
+	// Outbox lists the captured emails and announcements, newest first
+	Outbox(ctx context.Context, requesterID uuid.UUID) ([]CapturedMessage, error)
+}
+
+// SandboxStatus is what clients show about the sandbox
+type SandboxStatus struct {
+	Enabled     bool       `json:"enabled"`
+	Banner      string     `json:"banner,omitempty"`
+	NextResetAt *time.Time `json:"next_reset_at,omitempty"`
+}
+
+// CapturedMessage is an email or announcement the sandbox did not send
+type CapturedMessage struct {
+	Kind       string    `json:"kind"`
+	To         string    `json:"to"`
+	Subject    string    `json:"subject,omitempty"`
+	Body       string    `json:"body"`
+	CapturedAt time.Time `json:"captured_at"`
+}
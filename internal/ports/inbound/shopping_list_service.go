@@ -0,0 +1,132 @@
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShoppingListService defines the use cases for shared shopping lists that
+// several people edit at once
+type ShoppingListService interface {
+	CreateList(ctx context.Context, userID uuid.UUID, name string) (*ShoppingListDTO, error)
+	ListLists(ctx context.Context, userID uuid.UUID) ([]ShoppingListDTO, error)
+	GetList(ctx context.Context, listID, userID uuid.UUID) (*ShoppingListDTO, error)
+	ShareList(ctx context.Context, cmd ShareShoppingListCommand) (*ShoppingListDTO, error)
+
+	// ApplyOperations applies a batch of changes, such as the queue an
+	// offline client sends on reconnect, and broadcasts them to subscribers
+	ApplyOperations(ctx context.Context, cmd ApplyShoppingListOpsCommand) (*ShoppingListSyncResult, error)
+	// Subscribe streams changes and presence for a list until closed
+	Subscribe(ctx context.Context, cmd SubscribeShoppingListCommand) (*ShoppingListSubscription, error)
+}
+
+// ShareShoppingListCommand shares a list with another user by email
+type ShareShoppingListCommand struct {
+	ListID  uuid.UUID
+	OwnerID uuid.UUID
+	Email   string
+}
+
+// ShoppingListDTO is a list with its current items. Presence is only set
+// when the list is fetched on its own.
+type ShoppingListDTO struct {
+	ID        uuid.UUID              `json:"id"`
+	Name      string                 `json:"name"`
+	OwnerID   uuid.UUID              `json:"owner_id"`
+	MemberIDs []uuid.UUID            `json:"member_ids"`
+	Version   int64                  `json:"version"`
+	Items     []ShoppingListItemDTO  `json:"items"`
+	Presence  []ShoppingListPresence `json:"presence,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// ShoppingListItemDTO is one item; Version is what clients send back as the
+// base version of their next operation on it
+type ShoppingListItemDTO struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Quantity  string     `json:"quantity,omitempty"`
+	Checked   bool       `json:"checked"`
+	CheckedBy *uuid.UUID `json:"checked_by,omitempty"`
+	Removed   bool       `json:"removed,omitempty"`
+	Version   int64      `json:"version"`
+}
+
+// ShoppingListOp is one change made by a client
+type ShoppingListOp struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	ItemID      uuid.UUID `json:"item_id"`
+	Name        string    `json:"name,omitempty"`
+	Quantity    string    `json:"quantity,omitempty"`
+	BaseVersion int64     `json:"base_version"`
+	At          time.Time `json:"at"`
+}
+
+// ApplyShoppingListOpsCommand applies a client's operations in order
+type ApplyShoppingListOpsCommand struct {
+	ListID uuid.UUID
+	UserID uuid.UUID
+	Ops    []ShoppingListOp
+}
+
+// ShoppingListChangeDTO is the outcome of one operation: applied,
+// unchanged, conflict (a later change won), duplicate or rejected
+type ShoppingListChangeDTO struct {
+	OpID    string               `json:"op_id"`
+	Type    string               `json:"type"`
+	UserID  uuid.UUID            `json:"user_id"`
+	Outcome string               `json:"outcome"`
+	Error   string               `json:"error,omitempty"`
+	Item    *ShoppingListItemDTO `json:"item,omitempty"`
+	Version int64                `json:"version"`
+}
+
+// ShoppingListSyncResult lists the outcome of each operation in a batch
+type ShoppingListSyncResult struct {
+	Version int64                   `json:"version"`
+	Changes []ShoppingListChangeDTO `json:"changes"`
+}
+
+// ShoppingListPresence is a user currently viewing the list
+type ShoppingListPresence struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Name     string    `json:"name"`
+	Sessions int       `json:"sessions"`
+}
+
+// Shopping list event types
+const (
+	ShoppingListEventChange   = "change"
+	ShoppingListEventSnapshot = "snapshot"
+	ShoppingListEventPresence = "presence"
+)
+
+// ShoppingListEvent is pushed to subscribers. Change and snapshot events
+// carry the list version as ID; presence events have none.
+type ShoppingListEvent struct {
+	ID   int64
+	Type string
+	Data interface{}
+}
+
+// SubscribeShoppingListCommand subscribes to a list. With Since set, the
+// changes after that version are replayed first; otherwise, or when too
+// much was missed, a snapshot is sent.
+type SubscribeShoppingListCommand struct {
+	ListID uuid.UUID
+	UserID uuid.UUID
+	Since  *int64
+}
+
+// ShoppingListSubscription delivers Replay, then live Events. Events is
+// closed when the subscriber falls behind; the client reconnects with the
+// last version it saw.
+type ShoppingListSubscription struct {
+	Replay []ShoppingListEvent
+	Events <-chan ShoppingListEvent
+	Close  func()
+}
@@ -0,0 +1,45 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// SubstitutionService suggests what to use in place of an ingredient, from
+// the substitutes knowledge base and, for signed-in users, a model when the
+// knowledge base has nothing
+type SubstitutionService interface {
+	// Substitutes lists the substitutes for an ingredient that suit the
+	// requester's dietary restrictions, allergies and dislikes
+	Substitutes(ctx context.Context, query SubstitutesQuery) (*IngredientSubstitutes, error)
+}
+
+// SubstitutesQuery asks for an ingredient's substitutes
+type SubstitutesQuery struct {
+	// RequesterID is uuid.Nil for anonymous readers, who get the knowledge
+	// base unfiltered
+	RequesterID uuid.UUID
+	Ingredient  string
+}
+
+// IngredientSubstitutes are the substitutes for one ingredient. Matched is
+// the knowledge base entry the name was found under, empty when there is
+// none; Hidden counts the substitutes left out for the requester's diet.
+type IngredientSubstitutes struct {
+	Ingredient  string                 `json:"ingredient"`
+	Matched     string                 `json:"matched,omitempty"`
+	Substitutes []IngredientSubstitute `json:"substitutes"`
+	Diets       []string               `json:"diets,omitempty"`
+	Hidden      int                    `json:"hidden"`
+}
+
+// IngredientSubstitute is one suggestion. Suggested is true when a model
+// proposed it rather than the curated knowledge base.
+type IngredientSubstitute struct {
+	Name      string   `json:"name"`
+	Ratio     string   `json:"ratio,omitempty"`
+	Notes     string   `json:"notes,omitempty"`
+	Contains  []string `json:"contains"`
+	Suggested bool     `json:"suggested"`
+}
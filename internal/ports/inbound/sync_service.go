@@ -0,0 +1,89 @@
+package inbound
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncService keeps a user's devices in step, including changes made while
+// offline. A device pushes the changes it queued, then pulls everything
+// saved after the cursor it holds. Bookmarks and notes sync in full;
+// shopping lists and drafts appear as pointers the device follows to their
+// own sync endpoints.
+type SyncService interface {
+	// Push merges a device's changes, last write wins per field
+	Push(ctx context.Context, cmd SyncPushCommand) (*SyncPushResult, error)
+	// Pull returns the records saved after the device's cursor
+	Pull(ctx context.Context, query SyncPullQuery) (*SyncPullResult, error)
+	// PurgeTombstones deletes tombstones every active device has pulled
+	// once they are older than the retention
+	PurgeTombstones(ctx context.Context) (int, error)
+}
+
+// SyncChange is one change a device made. Fields left out keep their
+// values; Delete removes the record.
+type SyncChange struct {
+	Kind   string                     `json:"kind"`
+	Key    string                     `json:"key"`
+	Fields map[string]json.RawMessage `json:"fields,omitempty"`
+	Delete bool                       `json:"delete,omitempty"`
+	// At is when the user made the change
+	At time.Time `json:"at"`
+}
+
+// SyncPushCommand pushes a device's queued changes
+type SyncPushCommand struct {
+	UserID   uuid.UUID
+	DeviceID string
+	Changes  []SyncChange
+}
+
+// SyncOutcome is what became of one pushed change: applied, unchanged,
+// conflict (later writes won; adopt Record) or rejected
+type SyncOutcome struct {
+	Kind    string         `json:"kind"`
+	Key     string         `json:"key"`
+	Outcome string         `json:"outcome"`
+	Error   string         `json:"error,omitempty"`
+	Record  *SyncRecordDTO `json:"record,omitempty"`
+}
+
+// SyncPushResult lists the outcome of each change in a push
+type SyncPushResult struct {
+	Outcomes []SyncOutcome `json:"outcomes"`
+}
+
+// SyncPullQuery asks for the records saved after Cursor; zero pulls
+// everything
+type SyncPullQuery struct {
+	UserID   uuid.UUID
+	DeviceID string
+	Cursor   int64
+	Limit    int
+}
+
+// SyncRecordDTO is a record as devices store it. FieldTimes are when each
+// field was written, for devices that merge locally.
+type SyncRecordDTO struct {
+	Kind       string                     `json:"kind"`
+	Key        string                     `json:"key"`
+	Fields     map[string]json.RawMessage `json:"fields"`
+	FieldTimes map[string]time.Time       `json:"field_times"`
+	Deleted    bool                       `json:"deleted,omitempty"`
+	Seq        int64                      `json:"seq"`
+	UpdatedAt  time.Time                  `json:"updated_at"`
+}
+
+// SyncPullResult is one page of records. Cursor is what the device sends
+// next time. Reset means the device's cursor is unusable, because deletes
+// it never saw were purged or the cursor was never issued; the device
+// drops its synced data and pulls again from zero.
+type SyncPullResult struct {
+	Records []SyncRecordDTO `json:"records"`
+	Cursor  int64           `json:"cursor"`
+	HasMore bool            `json:"has_more"`
+	Reset   bool            `json:"reset,omitempty"`
+}
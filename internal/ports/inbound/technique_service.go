@@ -0,0 +1,92 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TechniqueService serves the technique library and the techniques linked
+// from recipe steps. Admins curate the library; authors link their steps,
+// or ask for suggestions on the steps they have not linked.
+type TechniqueService interface {
+	List(ctx context.Context) ([]TechniqueSummary, error)
+	Get(ctx context.Context, slug string) (*TechniqueDetail, error)
+	Save(ctx context.Context, cmd SaveTechniqueCommand) (*TechniqueDetail, error)
+	Delete(ctx context.Context, requesterID uuid.UUID, slug string) error
+	// RecipeSteps returns a recipe's steps with their techniques, for cook
+	// mode, with temperatures in units (metric, imperial or original; blank
+	// for the requester's preference). Drafts are only visible to their
+	// author; requesterID is uuid.Nil for anonymous readers.
+	RecipeSteps(ctx context.Context, requesterID uuid.UUID, recipeID, units string) (*RecipeSteps, error)
+	LinkStep(ctx context.Context, requesterID uuid.UUID, recipeID string, step int, slugs []string) (*RecipeSteps, error)
+	// SuggestLinks replaces the suggestions on every step the author has
+	// not linked
+	SuggestLinks(ctx context.Context, requesterID uuid.UUID, recipeID string) (*RecipeSteps, error)
+}
+
+// SaveTechniqueCommand creates a technique or replaces the one at Slug
+type SaveTechniqueCommand struct {
+	RequesterID uuid.UUID `json:"-"`
+	Slug        string    `json:"-"`
+	Name        string    `json:"name"`
+	Summary     string    `json:"summary"`
+	Steps       []string  `json:"steps"`
+	Aliases     []string  `json:"aliases"`
+	// VideoURL is a YouTube or Vimeo link; empty removes the video
+	VideoURL      string `json:"video_url"`
+	VideoTitle    string `json:"video_title"`
+	VideoDuration int    `json:"video_duration_seconds"`
+}
+
+// TechniqueSummary is a library entry in a listing
+type TechniqueSummary struct {
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	Summary  string `json:"summary"`
+	HasVideo bool   `json:"has_video"`
+}
+
+// TechniqueDetail is a technique's standalone page
+type TechniqueDetail struct {
+	Slug      string          `json:"slug"`
+	Name      string          `json:"name"`
+	Summary   string          `json:"summary"`
+	Steps     []string        `json:"steps"`
+	Aliases   []string        `json:"aliases"`
+	Video     *TechniqueVideo `json:"video,omitempty"`
+	UpdatedAt string          `json:"updated_at"`
+}
+
+// TechniqueVideo is an embeddable how-to video
+type TechniqueVideo struct {
+	Provider string `json:"provider"`
+	Title    string `json:"title,omitempty"`
+	EmbedURL string `json:"embed_url"`
+	WatchURL string `json:"watch_url"`
+	Duration int    `json:"duration_seconds,omitempty"`
+	Start    int    `json:"start_seconds,omitempty"`
+}
+
+// RecipeSteps are a recipe's steps with the techniques each links to
+type RecipeSteps struct {
+	RecipeID string       `json:"recipe_id"`
+	Title    string       `json:"title"`
+	Steps    []RecipeStep `json:"steps"`
+}
+
+// RecipeStep is one numbered step
+type RecipeStep struct {
+	Number     int             `json:"number"`
+	Text       string          `json:"text"`
+	Techniques []StepTechnique `json:"techniques"`
+}
+
+// StepTechnique is a technique as cook mode shows it beside a step
+type StepTechnique struct {
+	Slug    string          `json:"slug"`
+	Name    string          `json:"name"`
+	Summary string          `json:"summary"`
+	Source  string          `json:"source"`
+	Video   *TechniqueVideo `json:"video,omitempty"`
+}
@@ -0,0 +1,94 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TranslationService machine-translates recipes into other languages and
+// lets authors correct the translation field by field
+type TranslationService interface {
+	// Languages lists the languages a recipe is translated into
+	Languages(ctx context.Context, query TranslationQuery) (*RecipeLanguages, error)
+	// Translation reads a recipe in another language, falling back to the
+	// source text for fields not yet translated
+	Translation(ctx context.Context, query TranslationQuery) (*RecipeTranslation, error)
+	// Translate machine-translates the fields that are missing or whose
+	// machine translation is stale. Human translations are never replaced.
+	Translate(ctx context.Context, cmd TranslateRecipeCommand) (*RecipeTranslation, error)
+	// EditField replaces one translated field with the author's text
+	EditField(ctx context.Context, cmd EditTranslationCommand) (*RecipeTranslation, error)
+}
+
+// TranslationQuery asks for a recipe's translations
+type TranslationQuery struct {
+	// RequesterID is uuid.Nil for anonymous readers, who only see
+	// published recipes
+	RequesterID uuid.UUID
+	RecipeID    string
+	// Language is the target language tag; unused by Languages
+	Language string
+}
+
+// TranslateRecipeCommand asks for a machine translation
+type TranslateRecipeCommand struct {
+	UserID   uuid.UUID
+	RecipeID string
+	Language string
+}
+
+// EditTranslationCommand replaces one translated field
+type EditTranslationCommand struct {
+	UserID   uuid.UUID
+	RecipeID string
+	Language string
+	Field    string
+	Text     string
+}
+
+// RecipeLanguages is the language a recipe is written in and the
+// languages it is translated into
+type RecipeLanguages struct {
+	RecipeID     string               `json:"recipe_id"`
+	Language     string               `json:"language"`
+	Translations []TranslationSummary `json:"translations"`
+}
+
+// TranslationSummary counts a translation's fields by provenance
+type TranslationSummary struct {
+	Language string `json:"language"`
+	Name     string `json:"name"`
+	Fields   int    `json:"fields"`
+	Machine  int    `json:"machine"`
+	Human    int    `json:"human"`
+	Stale    int    `json:"stale"`
+	Complete bool   `json:"complete"`
+}
+
+// RecipeTranslation is a recipe in another language. Complete is true when
+// every field is translated from the current source text.
+type RecipeTranslation struct {
+	RecipeID       string                  `json:"recipe_id"`
+	SourceLanguage string                  `json:"source_language"`
+	Language       string                  `json:"language"`
+	Title          string                  `json:"title"`
+	Description    string                  `json:"description"`
+	Ingredients    []string                `json:"ingredients"`
+	Steps          []string                `json:"steps"`
+	Complete       bool                    `json:"complete"`
+	Fields         []TranslatedFieldStatus `json:"fields"`
+}
+
+// TranslatedFieldStatus is one field of a recipe with its translation and
+// where it came from. Provenance is empty for untranslated fields.
+type TranslatedFieldStatus struct {
+	Field      string     `json:"field"`
+	Source     string     `json:"source"`
+	Text       string     `json:"text,omitempty"`
+	Provenance string     `json:"provenance,omitempty"`
+	Model      string     `json:"model,omitempty"`
+	EditorID   *uuid.UUID `json:"editor_id,omitempty"`
+	Stale      bool       `json:"stale"`
+	UpdatedAt  string     `json:"updated_at,omitempty"`
+}
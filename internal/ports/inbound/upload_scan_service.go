@@ -0,0 +1,60 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// UploadScanService scans uploaded files for malware before they are
+// processed. Every scan is recorded.
+type UploadScanService interface {
+	// ScanUpload returns nil when the upload may be processed. Infected
+	// files are quarantined and rejected; when the scanner is unreachable
+	// uploads are rejected unless scanning is configured to fail open.
+	ScanUpload(ctx context.Context, cmd ScanUploadCommand) error
+	// ListUploadScans returns the latest scans; admins only
+	ListUploadScans(ctx context.Context, requesterID uuid.UUID, query UploadScanQuery) ([]*UploadScan, error)
+}
+
+// Upload sources
+const (
+	UploadSourceRecipePhoto    = "recipe_photo"
+	UploadSourceRecipeLibrary  = "recipe_library"
+	UploadSourceImage          = "image"
+	UploadSourceAccountArchive = "account_archive"
+)
+
+// ScanUploadCommand is one uploaded file
+type ScanUploadCommand struct {
+	UserID      uuid.UUID
+	Source      string
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+// UploadScanQuery filters the scan list. Verdict is clean, infected,
+// failed or skipped; empty lists all of them.
+type UploadScanQuery struct {
+	Verdict string
+	Limit   int
+}
+
+// UploadScan is the recorded scan of one upload
+type UploadScan struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Source      string    `json:"source"`
+	FileName    string    `json:"file_name,omitempty"`
+	ContentType string    `json:"content_type,omitempty"`
+	SizeBytes   int64     `json:"size_bytes"`
+	SHA256      string    `json:"sha256"`
+	Scanner     string    `json:"scanner"`
+	Verdict     string    `json:"verdict"`
+	Signature   string    `json:"signature,omitempty"`
+	Quarantined bool      `json:"quarantined"`
+	Error       string    `json:"error,omitempty"`
+	DurationMS  int64     `json:"duration_ms"`
+	ScannedAt   string    `json:"scanned_at"`
+}
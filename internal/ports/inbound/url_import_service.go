@@ -0,0 +1,45 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// URLImportService imports recipes from web pages by address. The page is
+// read for schema.org JSON-LD or microdata, falling back to its headings
+// and lists.
+type URLImportService interface {
+	// Preview reads the page and shows the draft it would become, without
+	// saving anything
+	Preview(ctx context.Context, cmd ImportURLCommand) (*URLImportPreview, error)
+	// Import reads the page and saves its recipe as a draft. Importing a
+	// recipe whose title the user already has returns that recipe instead.
+	Import(ctx context.Context, cmd ImportURLCommand) (*URLImportResult, error)
+}
+
+// ImportURLCommand names the page to import from
+type ImportURLCommand struct {
+	UserID uuid.UUID
+	URL    string
+}
+
+// URLImportPreview is the draft a page would be imported as. SourceURL is
+// the page address after redirects; Method is how the recipe was read:
+// schema.org, microdata or heuristic.
+type URLImportPreview struct {
+	SourceURL string `json:"source_url"`
+	Method    string `json:"method"`
+	ImportPreview
+}
+
+// URLImportResult names the draft created from a page. Status is created
+// or duplicate.
+type URLImportResult struct {
+	RecipeID  uuid.UUID `json:"recipe_id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	Method    string    `json:"method"`
+	SourceURL string    `json:"source_url"`
+	Warnings  []string  `json:"warnings,omitempty"`
+}
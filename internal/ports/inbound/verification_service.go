@@ -0,0 +1,133 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// VerificationService runs author verification: professional chefs and
+// brands claim a verified badge with proof, admins review the claims, and
+// readers report badges they believe are fake
+type VerificationService interface {
+	// SubmitClaim files a claim for review. An author may only have one
+	// pending or approved claim, and must wait after a rejection or a
+	// revocation before claiming again.
+	SubmitClaim(ctx context.Context, cmd SubmitVerificationClaimCommand) (*VerificationClaim, error)
+	// MyClaim returns the requester's latest claim, with the reviewer's
+	// note; nil when they have never claimed
+	MyClaim(ctx context.Context, userID uuid.UUID) (*VerificationClaim, error)
+	// Badge returns an author's badge, or nil when they are not verified
+	Badge(ctx context.Context, userID uuid.UUID) (*VerificationBadge, error)
+	// Badges returns the badges of the verified authors among userIDs,
+	// keyed by author
+	Badges(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*VerificationBadge, error)
+	// ReportClaim files a reader's report that an author's badge is fake.
+	// A reader may have one open report per badge.
+	ReportClaim(ctx context.Context, cmd ReportVerificationCommand) (*VerificationReport, error)
+	// ListClaims returns claims for review; admins only
+	ListClaims(ctx context.Context, requesterID uuid.UUID, query VerificationClaimQuery) ([]*VerificationClaim, error)
+	// ReviewClaim approves or rejects a pending claim, or revokes an
+	// approved one; admins only
+	ReviewClaim(ctx context.Context, cmd ReviewVerificationClaimCommand) (*VerificationClaim, error)
+	// ListReports returns fake claim reports; admins only
+	ListReports(ctx context.Context, requesterID uuid.UUID, query VerificationReportQuery) ([]*VerificationReport, error)
+	// ResolveReport dismisses or upholds a report; admins only. Upholding
+	// revokes the badge and closes the badge's other open reports.
+	ResolveReport(ctx context.Context, cmd ResolveVerificationReportCommand) (*VerificationReport, error)
+}
+
+// Verification review decisions
+const (
+	VerificationApprove = "approve"
+	VerificationReject  = "reject"
+	VerificationRevoke  = "revoke"
+)
+
+// SubmitVerificationClaimCommand is an author's claim. Kind is chef or
+// brand; Evidence are https links a reviewer can check.
+type SubmitVerificationClaimCommand struct {
+	UserID      uuid.UUID
+	Kind        string
+	DisplayName string
+	Statement   string
+	Evidence    []string
+}
+
+// ReportVerificationCommand reports an author's badge. Reason is
+// impersonation, fake_credentials or other; other needs details.
+type ReportVerificationCommand struct {
+	ReporterID uuid.UUID
+	AuthorID   uuid.UUID
+	Reason     string
+	Details    string
+}
+
+// VerificationClaimQuery filters the claim list. Status is pending,
+// approved, rejected or revoked; empty lists pending claims.
+type VerificationClaimQuery struct {
+	Status string
+	Limit  int
+}
+
+// ReviewVerificationClaimCommand is an admin's decision on a claim. A note
+// is required to reject or revoke and is shown to the author.
+type ReviewVerificationClaimCommand struct {
+	ReviewerID uuid.UUID
+	ClaimID    string
+	Decision   string
+	Note       string
+}
+
+// VerificationReportQuery filters the report list. Status is open,
+// dismissed or upheld; empty lists open reports.
+type VerificationReportQuery struct {
+	Status string
+	Limit  int
+}
+
+// ResolveVerificationReportCommand is an admin's decision on a report
+type ResolveVerificationReportCommand struct {
+	ResolverID uuid.UUID
+	ReportID   string
+	Uphold     bool
+	Note       string
+}
+
+// VerificationBadge is what readers see on a verified author's profile
+// and recipe cards
+type VerificationBadge struct {
+	Kind        string `json:"kind"`
+	DisplayName string `json:"display_name"`
+	VerifiedAt  string `json:"verified_at"`
+}
+
+// VerificationClaim is a claim as its author and admins see it
+type VerificationClaim struct {
+	ID          string   `json:"id"`
+	UserID      string   `json:"user_id"`
+	Kind        string   `json:"kind"`
+	DisplayName string   `json:"display_name"`
+	Statement   string   `json:"statement,omitempty"`
+	Evidence    []string `json:"evidence"`
+	Status      string   `json:"status"`
+	ReviewNote  string   `json:"review_note,omitempty"`
+	SubmittedAt string   `json:"submitted_at"`
+	ReviewedAt  string   `json:"reviewed_at,omitempty"`
+	// CanResubmitAt is set on rejected and revoked claims
+	CanResubmitAt string `json:"can_resubmit_at,omitempty"`
+}
+
+// VerificationReport is a fake claim report
+type VerificationReport struct {
+	ID         string `json:"id"`
+	ClaimID    string `json:"claim_id"`
+	AuthorID   string `json:"author_id"`
+	ReporterID string `json:"reporter_id"`
+	Reason     string `json:"reason"`
+	Details    string `json:"details,omitempty"`
+	Status     string `json:"status"`
+	Resolution string `json:"resolution,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	ResolvedAt string `json:"resolved_at,omitempty"`
+}
@@ -0,0 +1,21 @@
+package inbound
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ViewService counts recipe views, each viewer once per recipe within a
+// window, and how often recipes are listed in search results. Counts are
+// buffered and written to the recipe view counters and the daily totals by
+// Flush.
+type ViewService interface {
+	// Count records a view by a signed-in user or else a session, and
+	// reports whether it counted. Views with neither always count.
+	Count(ctx context.Context, recipeID uuid.UUID, userID *uuid.UUID, sessionID string) (bool, error)
+	// CountImpressions records that recipes were listed in search results
+	CountImpressions(ctx context.Context, recipeIDs []uuid.UUID) error
+	// Flush writes the buffered counts and returns how many views it wrote
+	Flush(ctx context.Context) (int, error)
+}
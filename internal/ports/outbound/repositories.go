@@ -4,10 +4,24 @@ package outbound
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"time"
 
+	"github.com/alchemorsel/v3/internal/domain/battle"
+	"github.com/alchemorsel/v3/internal/domain/comment"
+	"github.com/alchemorsel/v3/internal/domain/duplicate"
+	"github.com/alchemorsel/v3/internal/domain/notification"
+	"github.com/alchemorsel/v3/internal/domain/offline"
+	"github.com/alchemorsel/v3/internal/domain/pantry"
 	"github.com/alchemorsel/v3/internal/domain/recipe"
+	"github.com/alchemorsel/v3/internal/domain/recipe/nutrition"
+	"github.com/alchemorsel/v3/internal/domain/recipe/substitutes"
+	"github.com/alchemorsel/v3/internal/domain/report"
+	"github.com/alchemorsel/v3/internal/domain/shoppinglist"
+	"github.com/alchemorsel/v3/internal/domain/technique"
 	"github.com/alchemorsel/v3/internal/domain/user"
+	"github.com/alchemorsel/v3/internal/domain/verification"
 	"github.com/google/uuid"
 )
 
@@ -20,15 +34,40 @@ type RecipeRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByID(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error)
 	
+	// Soft-delete recovery
+	FindDeletedByID(ctx context.Context, id uuid.UUID) (*recipe.Recipe, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	
 	// Query operations
 	FindByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*recipe.Recipe, int, error)
 	FindPublished(ctx context.Context, offset, limit int) ([]*recipe.Recipe, int, error)
 	FindByStatus(ctx context.Context, status recipe.RecipeStatus, offset, limit int) ([]*recipe.Recipe, int, error)
+	FindScheduledDue(ctx context.Context, before time.Time, limit int) ([]*recipe.Recipe, error)
 	
 	// Search operations
 	Search(ctx context.Context, criteria SearchCriteria) ([]*recipe.Recipe, int, error)
 	FindTrending(ctx context.Context, since time.Time, limit int) ([]*recipe.Recipe, error)
 	FindRecommended(ctx context.Context, userID uuid.UUID, limit int) ([]*recipe.Recipe, error)
+	// FindPopular pages through the published recipes by their trending
+	// rank, those not ranked yet last
+	FindPopular(ctx context.Context, offset, limit int) ([]*recipe.Recipe, int, error)
+	// SearchAfter pages through a search sorted by one of KeysetOrders,
+	// starting after criteria.After rather than at an offset. The cursor
+	// returned is where the next page starts, nil after the last page.
+	SearchAfter(ctx context.Context, criteria SearchCriteria) ([]*recipe.Recipe, *RecipeCursor, int, error)
+	// FindSearchFacets counts published recipes per filter value, keeping
+	// the limit most used values of each facet
+	FindSearchFacets(ctx context.Context, limit int) (*SearchFacets, error)
+	// FindResultFacets counts the results of a search per filter value,
+	// with how many take at most each of timeLimits minutes
+	FindResultFacets(ctx context.Context, criteria SearchCriteria, timeLimits []int) (*ResultFacets, error)
+	
+	// Like history
+	AddLike(ctx context.Context, recipeID, userID uuid.UUID) error
+	FindLikedByUser(ctx context.Context, userID uuid.UUID, limit int) ([]*recipe.Recipe, error)
+	// FindLatestByAuthors returns the newest published recipes of any of
+	// the authors
+	FindLatestByAuthors(ctx context.Context, authorIDs []uuid.UUID, limit int) ([]*recipe.Recipe, error)
 	
 	// Batch operations
 	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*recipe.Recipe, error)
@@ -44,6 +83,7 @@ type UserRepository interface {
 	Update(ctx context.Context, user *user.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByID(ctx context.Context, id uuid.UUID) (*user.User, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*user.User, error)
 	FindByEmail(ctx context.Context, email string) (*user.User, error)
 	FindByUsername(ctx context.Context, username string) (*user.User, error)
 	Exists(ctx context.Context, id uuid.UUID) (bool, error)
@@ -54,18 +94,1354 @@ type UserRepository interface {
 type SearchCriteria struct {
 	Query       string
 	AuthorID    *uuid.UUID
+	ExcludeAuthorID *uuid.UUID // leaves out this author's recipes
+	FavoritedBy *uuid.UUID // only recipes this user saved
 	Cuisines    []recipe.CuisineType
 	Categories  []recipe.CategoryType
 	Difficulty  []recipe.DifficultyLevel
 	Tags        []string
 	MinRating   *float64
 	MaxTime     *int
+	Dietary     []string // tags every result carries
+	AIGenerated *bool
 	Ingredients []string
 	ExcludeIngredients []string
 	Offset      int
 	Limit       int
 	OrderBy     string
 	OrderDir    string
+	After       *RecipeCursor // keyset position, used by SearchAfter
+}
+
+// KeysetOrders are the sorts a search can be paged through by cursor
+var KeysetOrders = map[string]bool{
+	"trending":  true,
+	"top":       true,
+	"new":       true,
+	"rating":    true,
+	"prep_time": true,
+}
+
+// RecipeCursor is the last recipe of a keyset page: its ID, its sort key,
+// which is a rank, rating or prep time in minutes, and when it was
+// published. An unranked recipe has a zero Value.
+type RecipeCursor struct {
+	ID          uuid.UUID `json:"id"`
+	Value       float64   `json:"v,omitempty"`
+	PublishedAt time.Time `json:"t"`
+}
+
+// ResultFacets count the results of a search per filter value. Each facet
+// is counted with the other filters of the search but not its own, so the
+// alternatives to a chosen value stay visible.
+type ResultFacets struct {
+	Cuisines     []FacetCount
+	Difficulties []FacetCount
+	MaxTimes     []FacetCount // Value is the limit in minutes
+	Tags         []FacetCount // counted without the dietary filter
+	AIGenerated  []FacetCount // Value is "true" or "false"
+}
+
+// FacetCount is how many published recipes have a filter value
+type FacetCount struct {
+	Value string
+	Count int
+}
+
+// SearchFacets are the filter values offered on the search page, most used
+// first
+type SearchFacets struct {
+	Cuisines     []FacetCount
+	Categories   []FacetCount
+	Difficulties []FacetCount
+	Tags         []FacetCount
+}
+
+// SearchAnalyticsRepository records search behaviour used to refine the
+// recipe taxonomy
+type SearchAnalyticsRepository interface {
+	RecordZeroResult(ctx context.Context, search ZeroResultSearch) error
+	TopZeroResultQueries(ctx context.Context, since time.Time, limit int) ([]ZeroResultQueryStat, error)
+}
+
+// ZeroResultSearch is a search that matched no recipes
+type ZeroResultSearch struct {
+	Query           string
+	NormalizedQuery string
+	UserID          *uuid.UUID
+	Suggestions     []string
+	SearchedAt      time.Time
+}
+
+// ZeroResultQueryStat aggregates zero-result searches by normalized query
+type ZeroResultQueryStat struct {
+	Query    string
+	Count    int
+	LastSeen time.Time
+}
+
+// RecipeAnalyticsRepository stores recipe page views and aggregates them
+// for the author stats page
+type RecipeAnalyticsRepository interface {
+	RecordView(ctx context.Context, view RecipeView) error
+	// RecordEngagement keeps the deepest scroll reported for a view
+	RecordEngagement(ctx context.Context, recipeID, viewID uuid.UUID, scrollDepth int, engaged time.Duration) error
+	RecipeViewStats(ctx context.Context, recipeID uuid.UUID, since time.Time, limit int) (*RecipeViewStats, error)
+	// RecentlyViewed returns the recipes a user opened, most recent first
+	RecentlyViewed(ctx context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error)
+	// AddDailyViews adds counted views to the per-day totals
+	AddDailyViews(ctx context.Context, counts []ViewCount) error
+	// AddDailyImpressions adds search listings to the per-day totals
+	AddDailyImpressions(ctx context.Context, counts []ViewCount) error
+	// AuthorStats reads the daily activity on an author's recipes
+	AuthorStats(ctx context.Context, authorID uuid.UUID, since time.Time) (*AuthorStats, error)
+}
+
+// AuthorStats is the activity on an author's recipes since a day
+type AuthorStats struct {
+	Recipes []AuthorRecipe
+	// Daily has a row per recipe and UTC day with any activity
+	Daily []RecipeDayStat
+}
+
+// AuthorRecipe is one of an author's recipes with its current rating
+type AuthorRecipe struct {
+	ID            uuid.UUID
+	Title         string
+	AverageRating float64
+}
+
+// RecipeDayStat is the activity on a recipe on a UTC day. RatingTotal is
+// the sum of the ratings given that day.
+type RecipeDayStat struct {
+	RecipeID    uuid.UUID
+	Day         time.Time
+	Views       int
+	Likes       int
+	Impressions int
+	Ratings     int
+	RatingTotal int
+}
+
+// ViewBuffer remembers who viewed which recipe for the dedupe window and
+// holds the counted views until they are flushed. Backed by Redis, it is
+// shared by every replica.
+type ViewBuffer interface {
+	// FirstView marks a recipe as seen by a viewer for the window and
+	// reports whether it was not already
+	FirstView(ctx context.Context, recipeID uuid.UUID, viewer string, window time.Duration) (bool, error)
+	// Add counts views of a recipe on a UTC day
+	Add(ctx context.Context, recipeID uuid.UUID, day time.Time, views int) error
+	// Drain removes every buffered count and returns them
+	Drain(ctx context.Context) ([]ViewCount, error)
+}
+
+// ViewCount is how many views a recipe had on a UTC day. Impressions are
+// buffered and flushed as the same counts.
+type ViewCount struct {
+	RecipeID uuid.UUID
+	Day      time.Time
+	Views    int
+}
+
+// RecipeView is one visit to a recipe page
+type RecipeView struct {
+	ID           uuid.UUID
+	RecipeID     uuid.UUID
+	UserID       *uuid.UUID
+	SessionID    string
+	UserAgent    string
+	Referrer     string
+	ReferrerHost string
+	SearchTerm   string
+	ViewedAt     time.Time
+}
+
+// RecipeViewStats aggregates a recipe's views since a point in time. A
+// viewer is the signed-in user, or the session for anonymous visits.
+type RecipeViewStats struct {
+	Daily         []DailyViewStat
+	Views         int
+	UniqueViewers int
+	Likes         int
+	// Bookmarks counts distinct users who added the recipe to a collection
+	Bookmarks      int
+	SearchTerms    []CountStat
+	Referrers      []CountStat
+	AvgScrollDepth float64
+	ScrollSamples  int
+}
+
+// DailyViewStat is the view count of one UTC day
+type DailyViewStat struct {
+	Day           time.Time
+	Views         int
+	UniqueViewers int
+}
+
+// CountStat is a grouped count, most frequent first
+type CountStat struct {
+	Key   string
+	Count int
+}
+
+// RecipeCounterRepository keeps the likes, views and forks counters of recipes.
+// Increments land on one of several shard rows so a popular recipe does not
+// serialize every write on its own row; Fold moves the shards back into the
+// recipe row. Recipe reads always include unfolded shards, so the counts
+// are exact at any time.
+type RecipeCounterRepository interface {
+	Increment(ctx context.Context, recipeID uuid.UUID, counter string, delta int) error
+	// Fold adds the shards of up to limit recipes to their totals and
+	// returns how many recipes it folded
+	Fold(ctx context.Context, limit int) (int, error)
+}
+
+// Recipe counters
+const (
+	RecipeCounterLikes = "likes"
+	RecipeCounterViews = "views"
+	RecipeCounterForks = "forks"
+)
+
+// AnnouncementRepository queues recipe announcements per channel so failed
+// posts can be retried
+type AnnouncementRepository interface {
+	Enqueue(ctx context.Context, deliveries []AnnouncementDelivery) error
+	FindDue(ctx context.Context, now time.Time, limit int) ([]AnnouncementDelivery, error)
+	Update(ctx context.Context, delivery AnnouncementDelivery) error
+	// CountPending counts deliveries still waiting to be sent
+	CountPending(ctx context.Context) (int64, error)
+}
+
+// Announcement delivery states
+const (
+	AnnouncementPending = "pending"
+	AnnouncementSent    = "sent"
+	AnnouncementFailed  = "failed"
+)
+
+// AnnouncementDelivery is one recipe announcement on one channel
+type AnnouncementDelivery struct {
+	ID            uuid.UUID
+	RecipeID      uuid.UUID
+	Channel       string
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	SentAt        *time.Time
+	CreatedAt     time.Time
+}
+
+// CommentRepository stores recipe comments
+type CommentRepository interface {
+	Create(ctx context.Context, c *comment.Comment) error
+	// FindByID and FindByEmailMessageID return nil when nothing matches
+	FindByID(ctx context.Context, id uuid.UUID) (*comment.Comment, error)
+	// FindByRecipe returns the recipe's comments, oldest first
+	FindByRecipe(ctx context.Context, recipeID uuid.UUID) ([]*comment.Comment, error)
+	FindByEmailMessageID(ctx context.Context, messageID string) (*comment.Comment, error)
+	// UpdateModeration saves a comment's moderation status
+	UpdateModeration(ctx context.Context, c *comment.Comment) error
+	// UpdateContent saves an edited or deleted comment's text and status
+	UpdateContent(ctx context.Context, c *comment.Comment) error
+	// FindByModerationStatus returns comments in a status, oldest first
+	FindByModerationStatus(ctx context.Context, status comment.ModerationStatus, limit int) ([]*comment.Comment, error)
+}
+
+// CommentModerationRepository stores the moderation tools recipe authors
+// have over comments on their recipes, and the audit log of their use
+type CommentModerationRepository interface {
+	// FindDiscussion returns nil when the recipe's discussion was never
+	// locked or unlocked
+	FindDiscussion(ctx context.Context, recipeID uuid.UUID) (*comment.Discussion, error)
+	SaveDiscussion(ctx context.Context, discussion *comment.Discussion) error
+	// SaveBlock stores a block, replacing the reason of an existing one
+	SaveBlock(ctx context.Context, block *comment.Block) error
+	// DeleteBlock reports whether there was a block to delete
+	DeleteBlock(ctx context.Context, authorID, userID uuid.UUID) (bool, error)
+	IsBlocked(ctx context.Context, authorID, userID uuid.UUID) (bool, error)
+	// FindBlocks returns an author's blocks, newest first
+	FindBlocks(ctx context.Context, authorID uuid.UUID) ([]comment.Block, error)
+	RecordEvent(ctx context.Context, event *comment.ModerationEvent) error
+	// FindEvents returns matching audit events, newest first
+	FindEvents(ctx context.Context, filter ModerationEventFilter) ([]comment.ModerationEvent, error)
+	// SaveFlag stores a flag unless the user already flagged the comment
+	SaveFlag(ctx context.Context, flag *comment.Flag) error
+	// FindFlagged returns visible comments with flags, most flagged first
+	FindFlagged(ctx context.Context, limit int) ([]FlaggedComment, error)
+	// ClearFlags deletes a comment's flags
+	ClearFlags(ctx context.Context, commentID uuid.UUID) error
+}
+
+// FlaggedComment tallies the flags on one comment
+type FlaggedComment struct {
+	CommentID      uuid.UUID
+	Flags          int
+	Reasons        map[comment.FlagReason]int
+	FirstFlaggedAt time.Time
+}
+
+// ModerationEventFilter narrows the moderation audit log. Zero fields
+// match everything.
+type ModerationEventFilter struct {
+	ActorID  *uuid.UUID
+	RecipeID *uuid.UUID
+	Limit    int
+}
+
+// CommentFeedbackRepository stores reactions to comments and helpfulness
+// votes on reviews
+type CommentFeedbackRepository interface {
+	// AddReaction stores a reaction unless the user already gave it
+	AddReaction(ctx context.Context, reaction *comment.Reaction) error
+	// RemoveReaction deletes a reaction, if it exists
+	RemoveReaction(ctx context.Context, commentID, userID uuid.UUID, kind comment.ReactionKind) error
+	// CountReactions tallies the reactions to each comment by kind
+	CountReactions(ctx context.Context, commentIDs []uuid.UUID) (map[uuid.UUID]map[comment.ReactionKind]int, error)
+	// FindUserReactions returns the reactions a user gave each comment
+	FindUserReactions(ctx context.Context, commentIDs []uuid.UUID, userID uuid.UUID) (map[uuid.UUID][]comment.ReactionKind, error)
+	// SaveHelpfulVote inserts a vote or replaces the voter's earlier one
+	SaveHelpfulVote(ctx context.Context, vote *comment.HelpfulVote) error
+	// RemoveHelpfulVote deletes a vote, if it exists
+	RemoveHelpfulVote(ctx context.Context, recipeID, reviewerID, voterID uuid.UUID) error
+	// HelpfulTallies counts the votes on each review of a recipe, keyed by
+	// reviewer
+	HelpfulTallies(ctx context.Context, recipeID uuid.UUID) (map[uuid.UUID]HelpfulTally, error)
+	// FindHelpfulVotes returns a voter's votes on a recipe's reviews, keyed
+	// by reviewer
+	FindHelpfulVotes(ctx context.Context, recipeID, voterID uuid.UUID) (map[uuid.UUID]bool, error)
+	// FindReviews returns the ratings on a recipe that come with a written
+	// review, one per reviewer
+	FindReviews(ctx context.Context, recipeID uuid.UUID) ([]recipe.Rating, error)
+	// FindEngagedRecipes returns the recipes whose comments the user reacted
+	// to or whose reviews they found helpful, most recent first
+	FindEngagedRecipes(ctx context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error)
+}
+
+// HelpfulTally counts the votes on one review
+type HelpfulTally struct {
+	Helpful    int
+	NotHelpful int
+}
+
+// ReplyTokenRepository stores the reply-to addresses handed out in comment
+// and review notification emails
+type ReplyTokenRepository interface {
+	Create(ctx context.Context, token ReplyToken) error
+	// FindByID returns nil when the token does not exist
+	FindByID(ctx context.Context, id uuid.UUID) (*ReplyToken, error)
+}
+
+// ReplyToken lets one user answer one comment or review by email. Exactly
+// one of ParentID and ReviewUserID is set.
+type ReplyToken struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	RecipeID     uuid.UUID
+	ParentID     *uuid.UUID
+	ReviewUserID *uuid.UUID
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// Email suppression reasons
+const (
+	SuppressionBounce    = "bounce"
+	SuppressionComplaint = "complaint"
+)
+
+// EmailSuppressionRepository remembers addresses that bounced or complained
+// so notifications stop going to them
+type EmailSuppressionRepository interface {
+	Suppress(ctx context.Context, email, reason, detail string) error
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+}
+
+// PasswordResetTokenRepository stores password reset tokens under the
+// SHA-256 of the token mailed to the user, so a leaked table cannot reset
+// anyone's password
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token PasswordResetToken) error
+	// FindByHash returns nil when no token has the hash
+	FindByHash(ctx context.Context, hash string) (*PasswordResetToken, error)
+	// Consume marks the token used, reporting false when it was already
+	// used or has expired
+	Consume(ctx context.Context, hash string, now time.Time) (bool, error)
+	// CountSince counts the tokens issued to an address since a time
+	CountSince(ctx context.Context, email string, since time.Time) (int64, error)
+	// RevokeForUser marks the user's unused tokens used
+	RevokeForUser(ctx context.Context, userID uuid.UUID, now time.Time) error
+}
+
+// PasswordResetToken lets one user choose a new password once
+type PasswordResetToken struct {
+	Hash      string
+	UserID    uuid.UUID
+	Email     string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// RecipeClipRepository records pages users clipped with the browser
+// extension
+type RecipeClipRepository interface {
+	Create(ctx context.Context, clip RecipeClip) error
+	// CountSince counts the user's clips since a time
+	CountSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+}
+
+// RecipeClip is one page saved as a draft recipe. Method is how the recipe
+// was read: schema.org or heuristic.
+type RecipeClip struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	RecipeID  uuid.UUID
+	PageURL   string
+	Method    string
+	CreatedAt time.Time
+}
+
+// GuestRepository stores guest sessions under the SHA-256 of their token,
+// with the recipes each guest saved
+type GuestRepository interface {
+	CreateSession(ctx context.Context, session GuestSession) error
+	// FindSession returns nil when no session has the hash
+	FindSession(ctx context.Context, tokenHash string) (*GuestSession, error)
+	// CountSessionsSince counts the sessions started from an address since
+	// a time
+	CountSessionsSince(ctx context.Context, ipHash string, since time.Time) (int64, error)
+	// RecordMessage counts one chat message, reporting false when the
+	// session already has max
+	RecordMessage(ctx context.Context, guestID uuid.UUID, max int) (bool, error)
+	// SaveRecipe stores a recipe, reporting false when the session already
+	// has max
+	SaveRecipe(ctx context.Context, recipe GuestRecipe, max int) (bool, error)
+	ListRecipes(ctx context.Context, guestID uuid.UUID) ([]GuestRecipe, error)
+	// DeleteRecipe reports false when the guest has no such recipe
+	DeleteRecipe(ctx context.Context, guestID, recipeID uuid.UUID) (bool, error)
+	// Claim marks an unclaimed, unexpired session claimed by the user,
+	// stores the recipes as theirs and deletes the guest copies in one
+	// transaction. It reports false, changing nothing, when the session was
+	// claimed or expired first.
+	Claim(ctx context.Context, guestID, userID uuid.UUID, recipes []*recipe.Recipe, now time.Time) (bool, error)
+	// DeleteExpired deletes sessions that expired before a time, with their
+	// recipes
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// GuestSession is an anonymous visitor's time-boxed identity
+type GuestSession struct {
+	ID        uuid.UUID
+	TokenHash string
+	IPHash    string
+	Messages  int
+	Recipes   int
+	ExpiresAt time.Time
+	ClaimedBy *uuid.UUID
+	ClaimedAt *time.Time
+	CreatedAt time.Time
+}
+
+// GuestRecipe is a recipe a guest kept from the AI chat. Ingredients are
+// free text lines; each direction is one step.
+type GuestRecipe struct {
+	ID          uuid.UUID
+	GuestID     uuid.UUID
+	Title       string
+	Description string
+	Ingredients []string
+	Directions  []string
+	Servings    int
+	PrepTime    int // minutes
+	CookTime    int // minutes
+	Tags        []string
+	CreatedAt   time.Time
+}
+
+// ImageRepository stores the records of uploaded images; the originals
+// themselves are in blob storage
+type ImageRepository interface {
+	Create(ctx context.Context, image *StoredImage) error
+	// FindByID returns nil when the image does not exist
+	FindByID(ctx context.Context, id uuid.UUID) (*StoredImage, error)
+}
+
+// StoredImage is one uploaded original. SHA256 is of the uploaded bytes.
+type StoredImage struct {
+	ID          uuid.UUID
+	OwnerID     uuid.UUID
+	BlobKey     string
+	ContentType string
+	Width       int
+	Height      int
+	SizeBytes   int64
+	SHA256      string
+	CreatedAt   time.Time
+}
+
+// FavoriteRepository stores the recipes users saved for later. Unlike
+// likes, favorites are private and are not counted on the recipe.
+type FavoriteRepository interface {
+	// Add returns false when the recipe was already saved
+	Add(ctx context.Context, userID, recipeID uuid.UUID, at time.Time) (bool, error)
+	// Remove returns false when the recipe was not saved
+	Remove(ctx context.Context, userID, recipeID uuid.UUID) (bool, error)
+	Count(ctx context.Context, userID uuid.UUID) (int, error)
+	// List returns every recipe the user saved, oldest first
+	List(ctx context.Context, userID uuid.UUID) ([]Favorite, error)
+}
+
+// Favorite is one recipe a user saved
+type Favorite struct {
+	RecipeID uuid.UUID
+	SavedAt  time.Time
+}
+
+// CollectionRepository stores the named lists users keep recipes in
+type CollectionRepository interface {
+	// ListByUser returns the user's collections, oldest first, each with
+	// its recipes in order
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]Collection, error)
+	// Create stores a collection with its recipes
+	Create(ctx context.Context, collection *Collection) error
+}
+
+// Collection is a named list of recipes. RecipeIDs are in display order.
+type Collection struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	Description string
+	IsPublic    bool
+	RecipeIDs   []uuid.UUID
+	CreatedAt   time.Time
+}
+
+// AccountDeletionRepository holds the accounts waiting out the grace
+// period before they are erased, at most one per user
+type AccountDeletionRepository interface {
+	// Save schedules the user's deletion, replacing a pending one
+	Save(ctx context.Context, deletion AccountDeletion) error
+	// Find returns nil when the user has no pending deletion
+	Find(ctx context.Context, userID uuid.UUID) (*AccountDeletion, error)
+	// Delete drops the pending deletion, reporting false when there was none
+	Delete(ctx context.Context, userID uuid.UUID) (bool, error)
+	// ListDue lists the deletions scheduled at or before now, soonest first
+	ListDue(ctx context.Context, now time.Time, limit int) ([]AccountDeletion, error)
+}
+
+// AccountDeletion is a user's pending request to have their account erased
+type AccountDeletion struct {
+	UserID       uuid.UUID
+	RequestedAt  time.Time
+	ScheduledFor time.Time
+}
+
+// AccountEraser removes a user's personal data in one transaction. The
+// user row stays behind with no personal data in it, so the comments,
+// likes and ratings they left keep their place without saying who left
+// them. The pending deletion is removed with the rest.
+type AccountEraser interface {
+	// Erase returns how many rows of each table were removed or anonymized
+	Erase(ctx context.Context, userID uuid.UUID, now time.Time) (map[string]int64, error)
+}
+
+// AccountAuditRepository is the append-only log of account deletions.
+// Events outlive the accounts they mention and hold no personal data but
+// the user's ID.
+type AccountAuditRepository interface {
+	Record(ctx context.Context, event AccountAuditEvent) error
+	// ListByUser lists the user's events, oldest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]AccountAuditEvent, error)
+}
+
+// Account audit actions
+const (
+	AccountAuditDeletionRequested = "deletion_requested"
+	AccountAuditDeletionCancelled = "deletion_cancelled"
+	AccountAuditErased            = "erased"
+)
+
+// AccountAuditEvent is one audited change to an account. ActorID is nil
+// for the scheduled erasure; Detail is a JSON object.
+type AccountAuditEvent struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	ActorID   *uuid.UUID
+	Action    string
+	Detail    string
+	CreatedAt time.Time
+}
+
+// SessionRevoker signs a user out of every device
+type SessionRevoker interface {
+	RevokeAllUserTokens(userID string) error
+}
+
+// FollowRepository stores who follows whom
+type FollowRepository interface {
+	// Follow returns false when followerID already follows followingID
+	Follow(ctx context.Context, followerID, followingID uuid.UUID, at time.Time) (bool, error)
+	// Unfollow returns false when followerID did not follow followingID
+	Unfollow(ctx context.Context, followerID, followingID uuid.UUID) (bool, error)
+	IsFollowing(ctx context.Context, followerID, followingID uuid.UUID) (bool, error)
+	// Counts returns how many users follow userID and how many it follows
+	Counts(ctx context.Context, userID uuid.UUID) (followers, following int, err error)
+	// Followers lists who follows userID, most recent first
+	Followers(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error)
+	// Following lists whom userID follows, most recent first
+	Following(ctx context.Context, userID uuid.UUID, offset, limit int) ([]uuid.UUID, error)
+}
+
+// NotificationRepository stores in-app notifications
+type NotificationRepository interface {
+	Save(ctx context.Context, n *notification.Notification) error
+	// List returns userID's notifications newest first, only the unread
+	// ones when unreadOnly is set
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, offset, limit int) ([]*notification.Notification, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	// MarkRead marks the given notifications of userID read and returns
+	// how many were unread; ids of other users are ignored
+	MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, at time.Time) (int, error)
+	// MarkAllRead marks every unread notification of userID read
+	MarkAllRead(ctx context.Context, userID uuid.UUID, at time.Time) (int, error)
+}
+
+// ReportRepository stores readers' reports of recipes and comments
+type ReportRepository interface {
+	Create(ctx context.Context, r *report.Report) error
+	// FindByID returns nil when there is no such report
+	FindByID(ctx context.Context, id uuid.UUID) (*report.Report, error)
+	// HasOpen reports whether reporterID has an open report on the target
+	HasOpen(ctx context.Context, target report.TargetType, targetID, reporterID uuid.UUID) (bool, error)
+	CountOpen(ctx context.Context, target report.TargetType, targetID uuid.UUID) (int, error)
+	// FindOpen pages through open reports, oldest first
+	FindOpen(ctx context.Context, offset, limit int) ([]*report.Report, int64, error)
+	// MarkUnpublished flags the target's open reports as having taken it down
+	MarkUnpublished(ctx context.Context, target report.TargetType, targetID uuid.UUID) error
+	// CloseOpen closes every open report on the target with the verdict
+	// and returns how many were closed
+	CloseOpen(ctx context.Context, target report.TargetType, targetID uuid.UUID, verdict *report.Report) (int, error)
+}
+
+// DuplicateRepository stores the queue of published recipes waiting for
+// the duplicate check and the flags the check opens
+type DuplicateRepository interface {
+	// EnqueueCheck queues a recipe for the check; a recipe already queued
+	// keeps its place
+	EnqueueCheck(ctx context.Context, recipeID uuid.UUID, at time.Time) error
+	// DueChecks returns queued recipes, longest waiting first
+	DueChecks(ctx context.Context, limit int) ([]uuid.UUID, error)
+	CompleteCheck(ctx context.Context, recipeID uuid.UUID) error
+	Create(ctx context.Context, f *duplicate.Flag) error
+	// FindByID returns nil when there is no such flag
+	FindByID(ctx context.Context, id uuid.UUID) (*duplicate.Flag, error)
+	// FindLatestForRecipe returns the recipe's newest flag, nil when it
+	// has none
+	FindLatestForRecipe(ctx context.Context, recipeID uuid.UUID) (*duplicate.Flag, error)
+	// HasFlag reports whether recipeID was already flagged as a copy of
+	// originalID
+	HasFlag(ctx context.Context, recipeID, originalID uuid.UUID) (bool, error)
+	// FindAwaitingReview pages through open flags and flags with a pending
+	// appeal, oldest first
+	FindAwaitingReview(ctx context.Context, offset, limit int) ([]*duplicate.Flag, int64, error)
+	Update(ctx context.Context, f *duplicate.Flag) error
+}
+
+// IngredientNutritionRepository stores the reference foods recipe
+// nutrition is computed from, in match order
+type IngredientNutritionRepository interface {
+	List(ctx context.Context) ([]nutrition.Food, error)
+	// SeedMissing adds the foods not already stored by name, leaving edited
+	// rows alone, and returns how many were added
+	SeedMissing(ctx context.Context, foods []nutrition.Food, source string) (int, error)
+}
+
+// IngredientSubstituteRepository stores the substitutes knowledge base
+type IngredientSubstituteRepository interface {
+	// List returns every substitute, each ingredient's in suggestion order
+	List(ctx context.Context) ([]substitutes.Substitute, error)
+	// SeedMissing adds the substitutes not already stored for their
+	// ingredient, leaving edited rows alone, and returns how many were
+	// added
+	SeedMissing(ctx context.Context, subs []substitutes.Substitute, source string) (int, error)
+}
+
+// ShoppingListRepository stores shared shopping lists and the log of
+// changes clients replay after reconnecting
+type ShoppingListRepository interface {
+	Create(ctx context.Context, list *shoppinglist.List) error
+	// FindByID returns nil when the list does not exist
+	FindByID(ctx context.Context, id uuid.UUID) (*shoppinglist.List, error)
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]*shoppinglist.List, error)
+	AddMember(ctx context.Context, listID, userID uuid.UUID) error
+	// SaveChanges stores the applied changes and the items they touched.
+	// It returns shoppinglist.ErrStaleVersion when the stored list is no
+	// longer at expectedVersion.
+	SaveChanges(ctx context.Context, list *shoppinglist.List, expectedVersion int64, changes []shoppinglist.Change) error
+	// FindChangesSince returns applied changes after the version, oldest first
+	FindChangesSince(ctx context.Context, listID uuid.UUID, version int64, limit int) ([]shoppinglist.Change, error)
+	// FindAppliedOps returns the stored changes for the given operation IDs
+	FindAppliedOps(ctx context.Context, listID uuid.UUID, opIDs []string) (map[string]shoppinglist.Change, error)
+}
+
+// RecipeChangeRepository keeps the log of field-level recipe changes that
+// sync clients replay instead of downloading whole recipes again
+type RecipeChangeRepository interface {
+	// Latest returns the newest change set, or nil before the first one
+	Latest(ctx context.Context, recipeID uuid.UUID) (*RecipeChangeSet, error)
+	// Append stores the change set. It returns false when another save
+	// already recorded the revision.
+	Append(ctx context.Context, set RecipeChangeSet) (bool, error)
+	// FindSince returns change sets after the revision, oldest first
+	FindSince(ctx context.Context, recipeID uuid.UUID, revision int64, limit int) ([]RecipeChangeSet, error)
+}
+
+// RecipeChangeSet is one revision of a recipe. Fields holds the JSON value
+// of each synced field that changed, Snapshot every synced field as of the
+// revision.
+type RecipeChangeSet struct {
+	RecipeID  uuid.UUID
+	Revision  int64
+	Fields    map[string]json.RawMessage
+	Snapshot  map[string]json.RawMessage
+	ChangedAt time.Time
+}
+
+// SyncRepository stores the records a user's devices sync and how far each
+// device has pulled. Every save takes the next number in the user's change
+// sequence, which is the cursor devices pull from.
+type SyncRepository interface {
+	// FindRecord returns nil when the user has no such record
+	FindRecord(ctx context.Context, userID uuid.UUID, kind offline.Kind, key string) (*offline.Record, error)
+	// SaveRecord stores the record under the user's next sequence number
+	// and sets record.Seq. It returns offline.ErrStaleRecord when the
+	// stored record is no longer at expectedSeq.
+	SaveRecord(ctx context.Context, record *offline.Record, expectedSeq int64) error
+	// FindSince returns the records saved after the cursor, oldest first
+	FindSince(ctx context.Context, userID uuid.UUID, cursor int64, limit int) ([]*offline.Record, error)
+	// Horizon returns the user's latest sequence number and the highest
+	// one purged. Devices behind the purged one missed deletes.
+	Horizon(ctx context.Context, userID uuid.UUID) (latest, purged int64, err error)
+	SaveDeviceCursor(ctx context.Context, userID uuid.UUID, deviceID string, cursor int64, seenAt time.Time) error
+	// PurgeTombstones deletes tombstones cleared before the time that every
+	// device seen since then has pulled, returning how many it deleted
+	PurgeTombstones(ctx context.Context, before time.Time) (int, error)
+}
+
+// PantryRepository stores users' pantry items and what their cooks used
+type PantryRepository interface {
+	// FindItems returns the user's items by name
+	FindItems(ctx context.Context, userID uuid.UUID) ([]*pantry.Item, error)
+	// FindItem returns nil when the user has no such item
+	FindItem(ctx context.Context, userID, itemID uuid.UUID) (*pantry.Item, error)
+	CountItems(ctx context.Context, userID uuid.UUID) (int, error)
+	// SaveItem inserts or updates the item
+	SaveItem(ctx context.Context, item *pantry.Item) error
+	// DeleteItem returns false when the user has no such item
+	DeleteItem(ctx context.Context, userID, itemID uuid.UUID) (bool, error)
+	// RecordCook logs the cook and its consumption and takes each amount
+	// from its item, never below zero, in one transaction
+	RecordCook(ctx context.Context, cook *pantry.Cook) error
+	// FindConsumption returns what the user's cooks used since the time,
+	// newest first
+	FindConsumption(ctx context.Context, userID uuid.UUID, since time.Time) ([]pantry.Consumption, error)
+	CountCooks(ctx context.Context, userID uuid.UUID, since time.Time) (int, error)
+}
+
+// ProfileCaptureRepository stores profiling captures. It doubles as the
+// audit trail, so denied requests and raw pprof access are recorded too.
+type ProfileCaptureRepository interface {
+	Create(ctx context.Context, capture *ProfileCapture) error
+	Update(ctx context.Context, capture *ProfileCapture) error
+	// FindByID returns nil when the capture does not exist
+	FindByID(ctx context.Context, id uuid.UUID) (*ProfileCapture, error)
+	// FindRecent returns the latest captures, newest first
+	FindRecent(ctx context.Context, limit int) ([]*ProfileCapture, error)
+}
+
+// Profile capture states
+const (
+	ProfileCaptureRunning   = "running"
+	ProfileCaptureCompleted = "completed"
+	ProfileCaptureFailed    = "failed"
+	ProfileCaptureDenied    = "denied"
+)
+
+// ProfileCapture is one profiling request by one user. BlobKey is empty for
+// raw pprof access and for captures that never started.
+type ProfileCapture struct {
+	ID          uuid.UUID
+	Kind        string
+	Seconds     int
+	Status      string
+	RequestedBy uuid.UUID
+	IPAddress   string
+	Reason      string
+	BlobKey     string
+	SizeBytes   int64
+	Error       string
+	StartedAt   time.Time
+	FinishedAt  *time.Time
+}
+
+// BrowseSummaryRepository keeps the precomputed counts and rankings behind
+// the tag and cuisine browse pages. Readers only see a complete refresh.
+type BrowseSummaryRepository interface {
+	// Refresh recomputes every summary from the published recipes, keeping
+	// topPerCuisine recipes per cuisine
+	Refresh(ctx context.Context, topPerCuisine int) (*BrowseSummary, error)
+	// TagCounts and CuisineCounts are most used first
+	TagCounts(ctx context.Context, limit int) ([]FacetCount, error)
+	CuisineCounts(ctx context.Context, limit int) ([]FacetCount, error)
+	// TopRatedByCuisine is best rated first
+	TopRatedByCuisine(ctx context.Context, cuisine string, limit int) ([]BrowseRecipe, error)
+	// RefreshedAt returns nil before the first refresh
+	RefreshedAt(ctx context.Context) (*time.Time, error)
+}
+
+// BrowseSummary describes one refresh
+type BrowseSummary struct {
+	Tags        int
+	Cuisines    int
+	TopRated    int
+	RefreshedAt time.Time
+}
+
+// BrowseRecipe is a recipe card on a browse page
+type BrowseRecipe struct {
+	RecipeID      uuid.UUID
+	Title         string
+	Cuisine       string
+	AverageRating float64
+	Likes         int
+	Rank          int
+}
+
+// RecipePopularityRepository keeps the decayed popularity score of each
+// published recipe and the rotation of hidden gems, well rated recipes few
+// people have seen
+type RecipePopularityRepository interface {
+	// Activity counts the views and likes each published recipe got in
+	// [since, until)
+	Activity(ctx context.Context, since, until time.Time) ([]PopularityActivity, error)
+	// Decay multiplies every score by factor, adds the activity and drops
+	// scores that fell below floor, in one transaction. It returns the
+	// number of scores kept.
+	Decay(ctx context.Context, factor float64, activity []PopularityActivity, floor float64, at time.Time) (int, error)
+	// RefreshedAt returns nil before the first refresh
+	RefreshedAt(ctx context.Context) (*time.Time, error)
+	// RotateGems features the next count recipes matching criteria, those
+	// featured longest ago (or never) first
+	RotateGems(ctx context.Context, criteria GemCriteria, count int, at time.Time) ([]HiddenGem, error)
+	// HiddenGems returns the latest rotation that is still published, best
+	// rated first
+	HiddenGems(ctx context.Context, limit int) ([]HiddenGem, error)
+	// RankingCandidates lists every published recipe with its engagement
+	// and decayed score
+	RankingCandidates(ctx context.Context) ([]RankingCandidate, error)
+	// ReplaceRankings swaps in a new set of rankings in one transaction
+	ReplaceRankings(ctx context.Context, rankings []RecipeRanking, at time.Time) error
+}
+
+// RankingCandidate is a published recipe being ranked
+type RankingCandidate struct {
+	RecipeID    uuid.UUID
+	PublishedAt time.Time
+	Likes       int
+	Views       int
+	// Popularity is the decayed score, zero without recent engagement
+	Popularity float64
+}
+
+// RecipeRanking is a recipe's score and 1-based position in the trending
+// and top listings
+type RecipeRanking struct {
+	RecipeID      uuid.UUID
+	TrendingScore float64
+	TrendingRank  int
+	TopScore      float64
+	TopRank       int
+}
+
+// PopularityActivity is the new engagement of one recipe
+type PopularityActivity struct {
+	RecipeID uuid.UUID
+	Views    int
+	Likes    int
+	// Score is the weighted engagement added to the decayed score
+	Score float64
+}
+
+// GemCriteria selects the recipes eligible as hidden gems
+type GemCriteria struct {
+	MinRating float64
+	MaxViews  int
+	// PublishedBefore keeps brand new recipes, which have few views
+	// anyway, out of the rotation
+	PublishedBefore time.Time
+}
+
+// HiddenGem is a recipe featured by the current rotation
+type HiddenGem struct {
+	RecipeID      uuid.UUID
+	Title         string
+	Cuisine       string
+	AverageRating float64
+	Views         int
+	FeaturedAt    time.Time
+}
+
+// RecipeExportRepository reads recipes for bulk exports from a database
+// cursor, so an export of any size runs in constant memory. The cursor
+// holds a connection until the walk ends.
+type RecipeExportRepository interface {
+	// StreamRecipes calls fn with each recipe matching filter, oldest
+	// first. An error from fn stops the walk and is returned.
+	StreamRecipes(ctx context.Context, filter RecipeExportFilter, fn func(*RecipeExportRow) error) error
+}
+
+// RecipeExportFilter narrows an export. Empty fields match every recipe.
+type RecipeExportFilter struct {
+	Status       string
+	UpdatedSince *time.Time
+}
+
+// RecipeExportRow is one recipe's summary in an export
+type RecipeExportRow struct {
+	ID              uuid.UUID
+	Title           string
+	AuthorID        uuid.UUID
+	Status          string
+	Language        string
+	Cuisine         string
+	Category        string
+	Difficulty      string
+	PrepTimeMinutes int
+	CookTimeMinutes int
+	Servings        int
+	Calories        int
+	Likes           int
+	Views           int
+	AverageRating   float64
+	AIGenerated     bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	PublishedAt     *time.Time
+	Ingredients     []IngredientLine
+}
+
+// RecipeGraphRepository keeps the adjacency tables of the recipe knowledge
+// graph, linking published recipes to the ingredients, techniques and
+// cuisines they use. Readers only see a complete rebuild, and recipes that
+// stopped being published since are left out of every traversal.
+type RecipeGraphRepository interface {
+	// EachPublished streams the graph source of every published recipe
+	EachPublished(ctx context.Context, fn func(GraphSource) error) error
+	// Replace swaps in a rebuilt graph
+	Replace(ctx context.Context, nodes []GraphNode, edges []GraphEdge) error
+	// NodesOf returns the nodes a recipe links to
+	NodesOf(ctx context.Context, recipeID uuid.UUID) ([]GraphNode, error)
+	// Node returns nil if no recipe links to the node
+	Node(ctx context.Context, kind, key string) (*GraphNode, error)
+	// RecipesFor returns the recipes linked to a node, most liked first
+	RecipesFor(ctx context.Context, kind, key string, limit int) ([]GraphRecipe, error)
+	// Related ranks the recipes sharing nodes with recipeID by the summed
+	// weight of the shared nodes
+	Related(ctx context.Context, recipeID uuid.UUID, limit int) ([]GraphRecipe, error)
+	// RefreshedAt returns nil before the first rebuild
+	RefreshedAt(ctx context.Context) (*time.Time, error)
+}
+
+// GraphSource is what a recipe's graph links are derived from
+type GraphSource struct {
+	RecipeID     uuid.UUID
+	Ingredients  []string
+	Instructions []string
+	Cuisine      string
+}
+
+// GraphNode is an ingredient, technique or cuisine in the recipe graph
+type GraphNode struct {
+	Kind  string
+	Key   string
+	Label string
+	// Recipes is how many published recipes link to the node
+	Recipes int
+	// Weight is how much sharing the node relates two recipes
+	Weight float64
+}
+
+// GraphEdge links a recipe to a node
+type GraphEdge struct {
+	RecipeID uuid.UUID
+	Kind     string
+	Key      string
+}
+
+// GraphRecipe is a recipe reached through the graph
+type GraphRecipe struct {
+	RecipeID      uuid.UUID
+	Title         string
+	AverageRating float64
+	Likes         int
+	// Score and Shared are set on related recipes
+	Score  float64
+	Shared []GraphNode
+}
+
+// TechniqueRepository stores the technique library and the techniques
+// recipe steps link to
+type TechniqueRepository interface {
+	// List returns the library in name order
+	List(ctx context.Context) ([]*technique.Technique, error)
+	// FindBySlug returns nil when the technique does not exist
+	FindBySlug(ctx context.Context, slug string) (*technique.Technique, error)
+	// Save creates the technique or replaces the one with its slug
+	Save(ctx context.Context, t *technique.Technique) error
+	// Delete removes a technique and its step links, reporting whether it
+	// existed
+	Delete(ctx context.Context, slug string) (bool, error)
+	// RecipeSteps returns nil when the recipe does not exist
+	RecipeSteps(ctx context.Context, recipeID uuid.UUID) (*TechniqueRecipe, error)
+	// StepLinks returns a recipe's links in step then position order
+	StepLinks(ctx context.Context, recipeID uuid.UUID) ([]StepTechniqueLink, error)
+	// ReplaceStepLinks swaps the links of the given steps for links
+	ReplaceStepLinks(ctx context.Context, recipeID uuid.UUID, steps []int, links []StepTechniqueLink) error
+}
+
+// TechniqueRecipe is what the technique library needs of a recipe: who
+// may link its steps and the step texts, numbered from 1
+type TechniqueRecipe struct {
+	ID       uuid.UUID
+	AuthorID uuid.UUID
+	Title    string
+	Status   string
+	Steps    []string
+}
+
+// StepTechniqueLink links one step of a recipe to a technique
+type StepTechniqueLink struct {
+	RecipeID uuid.UUID
+	Step     int
+	Slug     string
+	Source   technique.Source
+	Position int
+}
+
+// RecipeTimelineRepository reads the steps a recipe timeline is planned
+// from
+type RecipeTimelineRepository interface {
+	// Steps returns nil when the recipe does not exist
+	Steps(ctx context.Context, recipeID uuid.UUID) (*TimelineRecipe, error)
+}
+
+// TimelineRecipe is a recipe's steps in order with the time each takes,
+// zero when the author gave none
+type TimelineRecipe struct {
+	ID       uuid.UUID
+	AuthorID uuid.UUID
+	Title    string
+	Status   string
+	Steps    []TimelineStep
+}
+
+// TimelineStep is one recipe step with the time the author gave it
+type TimelineStep struct {
+	Text     string
+	Duration time.Duration
+}
+
+// RecipeSafetyRepository reads what the food safety check needs of a recipe
+type RecipeSafetyRepository interface {
+	// Recipe returns nil when the recipe does not exist
+	Recipe(ctx context.Context, recipeID uuid.UUID) (*SafetyRecipe, error)
+}
+
+// SafetyRecipe is a recipe's ingredient names and steps
+type SafetyRecipe struct {
+	ID          uuid.UUID
+	AuthorID    uuid.UUID
+	Title       string
+	Status      string
+	Ingredients []string
+	Steps       []TimelineStep
+}
+
+// RecipeAllergenRepository reads what allergen disclosure needs of a recipe
+type RecipeAllergenRepository interface {
+	// Recipe is nil when there is no such recipe
+	Recipe(ctx context.Context, recipeID uuid.UUID) (*AllergenRecipe, error)
+	// Unlabelled returns up to limit recipes, deleted ones included, saved
+	// before their allergen label was stored with them
+	Unlabelled(ctx context.Context, limit int) ([]*AllergenRecipe, error)
+	// SetLabel stores a recipe's allergen label and leaves the rest of the
+	// recipe alone
+	SetLabel(ctx context.Context, recipeID uuid.UUID, label *recipe.AllergenLabel) error
+}
+
+// AllergenRecipe is a recipe's ingredients with their optional flags and
+// notes
+type AllergenRecipe struct {
+	ID          uuid.UUID
+	AuthorID    uuid.UUID
+	Title       string
+	Status      string
+	Ingredients []IngredientLine
+}
+
+// IngredientLine is an ingredient as the recipe lists it
+type IngredientLine struct {
+	Name     string
+	Optional bool
+	Notes    string
+}
+
+// RecipeEmbeddingRepository stores the recipe vectors behind search by
+// meaning. Vectors are kept per model, as vectors from different models
+// cannot be compared.
+type RecipeEmbeddingRepository interface {
+	// FindStale returns published recipes with no vector from model, or
+	// changed since theirs was made, least recently changed first
+	FindStale(ctx context.Context, model string, limit int) ([]*recipe.Recipe, error)
+	// Save inserts or replaces a recipe's vector from its model
+	Save(ctx context.Context, embedding RecipeEmbedding) error
+	// FindNearest returns the recipes matching the criteria, ignoring
+	// their text query, whose vectors from model are most similar to
+	// vector, most similar first
+	FindNearest(ctx context.Context, criteria SearchCriteria, model string, vector []float32, limit int) ([]EmbeddingMatch, error)
+}
+
+// RecipeEmbedding is a recipe's vector from one model. RecipeUpdatedAt is
+// when the recipe last changed before the vector was made.
+type RecipeEmbedding struct {
+	RecipeID        uuid.UUID
+	Model           string
+	Vector          []float32
+	RecipeUpdatedAt time.Time
+}
+
+// EmbeddingMatch is a recipe and the cosine similarity of its vector to
+// the one searched for
+type EmbeddingMatch struct {
+	RecipeID   uuid.UUID
+	Similarity float64
+}
+
+// RecommendationSignalRepository reads what recipe recommendations are
+// learned from: what signed-in users liked, saved and opened, and what
+// published recipes have in common
+type RecommendationSignalRepository interface {
+	// Interactions returns the likes, favorites and views by signed-in
+	// users since a time, at most limit of each kind, newest first. Views
+	// come once per user and recipe.
+	Interactions(ctx context.Context, since time.Time, limit int) ([]Interaction, error)
+	// EachPublished streams the features of every published recipe
+	EachPublished(ctx context.Context, fn func(RecipeFeatures) error) error
+}
+
+// InteractionKind is how a user engaged with a recipe
+type InteractionKind string
+
+const (
+	InteractionLike     InteractionKind = "like"
+	InteractionFavorite InteractionKind = "favorite"
+	InteractionView     InteractionKind = "view"
+)
+
+// Interaction is one user's engagement with a recipe
+type Interaction struct {
+	UserID   uuid.UUID
+	RecipeID uuid.UUID
+	Kind     InteractionKind
+}
+
+// RecipeFeatures is what recipes are compared by for recommendations
+type RecipeFeatures struct {
+	RecipeID    uuid.UUID
+	AuthorID    uuid.UUID
+	Title       string
+	Cuisine     string
+	Tags        []string
+	Ingredients []string
+}
+
+// RecipeTranslationRepository stores translations of recipes one field at
+// a time
+type RecipeTranslationRepository interface {
+	// Source reads the recipe text to translate; nil when there is no
+	// such recipe
+	Source(ctx context.Context, recipeID uuid.UUID) (*TranslationSource, error)
+	// Fields lists the translated fields of a recipe in one language
+	Fields(ctx context.Context, recipeID uuid.UUID, language string) ([]TranslatedField, error)
+	// Languages lists the languages a recipe has translations in
+	Languages(ctx context.Context, recipeID uuid.UUID) ([]string, error)
+	// Save inserts or replaces translated fields
+	Save(ctx context.Context, fields []TranslatedField) error
+}
+
+// TranslationSource is a recipe's text in the language it is written in
+type TranslationSource struct {
+	ID          uuid.UUID
+	AuthorID    uuid.UUID
+	Status      string
+	Language    string
+	Title       string
+	Description string
+	Ingredients []string
+	Steps       []string
+}
+
+// TranslatedField is one field of a recipe in another language.
+// SourceHash identifies the source text it translates.
+type TranslatedField struct {
+	RecipeID   uuid.UUID
+	Language   string
+	Field      string
+	Text       string
+	SourceHash string
+	Provenance string // machine or human
+	Model      string // set for machine translations
+	EditorID   *uuid.UUID // set for human translations
+	UpdatedAt  time.Time
+}
+
+// BattleRepository stores remix battles and their votes
+type BattleRepository interface {
+	Create(ctx context.Context, b *battle.Battle) error
+	// FindByID returns nil when the battle does not exist
+	FindByID(ctx context.Context, id uuid.UUID) (*battle.Battle, error)
+	// SubmitChef saves the chef's entry and the end of voting
+	SubmitChef(ctx context.Context, b *battle.Battle) error
+	// List returns battles newest first
+	List(ctx context.Context, filter BattleFilter) ([]*battle.Battle, error)
+	// RecordVote counts a vote unless the user already voted in the
+	// battle, reporting whether it was counted
+	RecordVote(ctx context.Context, vote BattleVote) (bool, error)
+	// FindVote returns nil when the user has not voted in the battle
+	FindVote(ctx context.Context, battleID, userID uuid.UUID) (*BattleVote, error)
+	// TemplateResults tallies the AI's wins, losses and ties by template
+	// over battles whose voting ended before now
+	TemplateResults(ctx context.Context, now time.Time) ([]battle.TemplateResult, error)
+}
+
+// BattleFilter selects battles by status at Now
+type BattleFilter struct {
+	Status battle.Status
+	// ChefID limits the battles to one chef's when set
+	ChefID uuid.UUID
+	Now    time.Time
+	Limit  int
+	Offset int
+}
+
+// BattleVote is one reader's vote for a side
+type BattleVote struct {
+	BattleID  uuid.UUID
+	UserID    uuid.UUID
+	Side      battle.Side
+	CreatedAt time.Time
+}
+
+// VerificationRepository stores author verification claims and the
+// reports filed against verified badges
+type VerificationRepository interface {
+	// SaveClaim inserts or updates a claim
+	SaveClaim(ctx context.Context, claim *verification.Claim) error
+	// FindClaim returns nil when the claim does not exist
+	FindClaim(ctx context.Context, id uuid.UUID) (*verification.Claim, error)
+	// FindLatestClaim returns the author's most recent claim, or nil when
+	// they have never made one
+	FindLatestClaim(ctx context.Context, userID uuid.UUID) (*verification.Claim, error)
+	// FindClaims returns claims with the given status, or all of them when
+	// status is empty, oldest first so the review queue is worked in order
+	FindClaims(ctx context.Context, status verification.Status, limit int) ([]*verification.Claim, error)
+	// FindVerified returns the approved claims of the given authors
+	FindVerified(ctx context.Context, userIDs []uuid.UUID) ([]*verification.Claim, error)
+	// SaveReport inserts or updates a report
+	SaveReport(ctx context.Context, report *verification.Report) error
+	// FindReport returns nil when the report does not exist
+	FindReport(ctx context.Context, id uuid.UUID) (*verification.Report, error)
+	// FindReports returns reports oldest first
+	FindReports(ctx context.Context, filter VerificationReportFilter) ([]*verification.Report, error)
+}
+
+// VerificationReportFilter selects reports. Zero fields match every report.
+type VerificationReportFilter struct {
+	Status     verification.ReportStatus
+	ClaimID    uuid.UUID
+	ReporterID uuid.UUID
+	Limit      int
+}
+
+// ArchiveRepository moves old RUM and audit rows out of the hot tables.
+// Each archived day becomes one or more partitions in blob storage, listed
+// here so the long-term reader can find them.
+type ArchiveRepository interface {
+	// OldestBefore returns when a dataset's earliest archivable row was
+	// recorded, or nil when no row is older than before
+	OldestBefore(ctx context.Context, dataset string, before time.Time) (*time.Time, error)
+	// RollupRecipeViews aggregates the views in [from, to) per recipe and
+	// day, and returns how many view rows were read
+	RollupRecipeViews(ctx context.Context, from, to time.Time) ([]RecipeViewRollup, int, error)
+	// ProfileCapturesBetween returns the finished captures started in
+	// [from, to); running captures are never archived
+	ProfileCapturesBetween(ctx context.Context, from, to time.Time) ([]*ProfileCapture, error)
+	// DeleteBetween removes the dataset's archivable rows in [from, to)
+	DeleteBetween(ctx context.Context, dataset string, from, to time.Time) (int64, error)
+	SavePartition(ctx context.Context, partition *ArchivePartition) error
+	// FindPartitions returns the partitions of days in [from, to), oldest
+	// first
+	FindPartitions(ctx context.Context, dataset string, from, to time.Time) ([]*ArchivePartition, error)
+}
+
+// Archived datasets
+const (
+	ArchiveRecipeViews     = "recipe_views"
+	ArchiveProfileCaptures = "profile_captures"
+)
+
+// ArchivePartition is one archived day of a dataset in blob storage.
+// SourceRows counts the hot rows it replaced; Records counts the lines
+// written, which is fewer when the rows were rolled up.
+type ArchivePartition struct {
+	ID         uuid.UUID
+	Dataset    string
+	Day        time.Time
+	BlobKey    string
+	Records    int
+	SourceRows int
+	SizeBytes  int64
+	ArchivedAt time.Time
+}
+
+// RecipeViewRollup is one recipe's views on one UTC day. The sums are kept
+// rather than averages so days and partitions can be combined.
+type RecipeViewRollup struct {
+	RecipeID       uuid.UUID
+	Day            time.Time
+	Views          int
+	UniqueViewers  int
+	ScrollDepthSum int
+	ScrollSamples  int
+	EngagedMSSum   int64
+	EngagedSamples int
+	ReferrerHosts  map[string]int
+}
+
+// CacheInvalidationBus tells the other replicas which entities changed, so
+// their in-process caches drop them. Keys are named the way the caches key
+// them, such as recipe:{id} or user:{id}.
+type CacheInvalidationBus interface {
+	// Publish broadcasts keys to every other replica; the caller clears
+	// its own caches
+	Publish(ctx context.Context, keys ...string) error
+	// Subscribe calls fn with each key another replica publishes that
+	// starts with prefix
+	Subscribe(prefix string, fn func(key string))
 }
 
 // CacheRepository defines the interface for caching operations
@@ -144,6 +1520,158 @@ type StorageService interface {
 	ListObjects(ctx context.Context, prefix string) ([]string, error)
 }
 
+// BlobStorage streams large objects, such as profiles, to storage without
+// holding them in memory
+type BlobStorage interface {
+	// Put stores the reader's content under key and returns the bytes written
+	Put(ctx context.Context, key, contentType string, content io.Reader) (int64, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// VirusScanner checks uploaded files for malware, e.g. clamd or an ICAP
+// server
+type VirusScanner interface {
+	// Scan reports what the engine found in content. An error means the
+	// content could not be scanned, not that it is infected.
+	Scan(ctx context.Context, content []byte) (*VirusScanResult, error)
+	// Ping checks the scanner is reachable
+	Ping(ctx context.Context) error
+	// Name identifies the engine in scan records
+	Name() string
+}
+
+// VirusScanResult is one scanner verdict. Signature names what was found.
+type VirusScanResult struct {
+	Infected  bool
+	Signature string
+}
+
+// UploadScanRepository records the scan of every upload
+type UploadScanRepository interface {
+	Save(ctx context.Context, scan *UploadScan) error
+	// FindRecent returns the latest scans, newest first, with the given
+	// verdict or all of them when verdict is empty
+	FindRecent(ctx context.Context, verdict string, limit int) ([]*UploadScan, error)
+}
+
+// Upload scan verdicts
+const (
+	UploadScanClean    = "clean"
+	UploadScanInfected = "infected"
+	// UploadScanFailed is an upload the scanner could not check
+	UploadScanFailed = "failed"
+	// UploadScanSkipped is an upload taken while scanning is disabled
+	UploadScanSkipped = "skipped"
+)
+
+// UploadScan is the scan of one uploaded file. QuarantineKey is where an
+// infected file was kept in blob storage.
+type UploadScan struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	Source        string
+	FileName      string
+	ContentType   string
+	SizeBytes     int64
+	SHA256        string
+	Scanner       string
+	Verdict       string
+	Signature     string
+	QuarantineKey string
+	Error         string
+	Duration      time.Duration
+	ScannedAt     time.Time
+}
+
+// Profiler captures runtime profiles of this process
+type Profiler interface {
+	// Capture writes a profile of the given kind to w. Sampled kinds such
+	// as cpu and trace run for the duration; snapshots ignore it.
+	Capture(ctx context.Context, kind string, duration time.Duration, w io.Writer) error
+	// Kinds lists the profile kinds Capture supports
+	Kinds() []string
+}
+
+// ConfigReader exposes the running configuration together with its schema
+type ConfigReader interface {
+	// Settings lists every key with its effective value; secrets are redacted
+	Settings() []ConfigSetting
+}
+
+// ConfigSetting is one configuration key
+type ConfigSetting struct {
+	Key     string
+	Env     string
+	Type    string
+	Default string
+	Rules   string
+	Secret  bool
+	Value   interface{}
+	Source  string // default, file or env
+}
+
+// AdminRepository reads the users, totals and AI-generated recipes the
+// admin section lists
+type AdminRepository interface {
+	// ListUsers pages through users newest first, with the total matching
+	// the filter
+	ListUsers(ctx context.Context, filter AdminUserFilter) ([]AdminUserRow, int64, error)
+	// Stats counts users, recipes and comments; New* count those created
+	// at or after since
+	Stats(ctx context.Context, since time.Time) (*AdminStatsRow, error)
+	// ListAIRecipes pages through AI-generated recipes newest first, with
+	// their total
+	ListAIRecipes(ctx context.Context, limit, offset int) ([]AIRecipeRow, int64, error)
+}
+
+// AdminUserFilter narrows the user list. Search matches email and name;
+// empty fields match every user.
+type AdminUserFilter struct {
+	Search string
+	Active *bool
+	Role   string
+	Limit  int
+	Offset int
+}
+
+// AdminUserRow is a user as the admin list shows them
+type AdminUserRow struct {
+	ID          uuid.UUID
+	Email       string
+	Name        string
+	Role        string
+	Active      bool
+	Verified    bool
+	Recipes     int
+	CreatedAt   time.Time
+	LastLoginAt *time.Time
+}
+
+// AdminStatsRow holds the totals of the admin dashboard
+type AdminStatsRow struct {
+	Users          int64
+	ActiveUsers    int64
+	Admins         int64
+	NewUsers       int64
+	RecipesByState map[string]int64
+	AIRecipes      int64
+	NewRecipes     int64
+	Comments       int64
+	HiddenComments int64
+}
+
+// AIRecipeRow is an AI-generated recipe with the prompt behind it
+type AIRecipeRow struct {
+	ID          uuid.UUID
+	Title       string
+	AuthorID    uuid.UUID
+	AuthorEmail string
+	Status      string
+	AIModel     string
+	AIPrompt    string
+	CreatedAt   time.Time
+}
+
 // AIService defines the interface for AI operations
 type AIService interface {
 	GenerateRecipe(ctx context.Context, prompt string, constraints AIConstraints) (*AIRecipeResponse, error)
@@ -151,6 +1679,20 @@ type AIService interface {
 	AnalyzeNutrition(ctx context.Context, ingredients []string) (*NutritionInfo, error)
 	GenerateDescription(ctx context.Context, recipe *recipe.Recipe) (string, error)
 	ClassifyRecipe(ctx context.Context, recipe *recipe.Recipe) (*RecipeClassification, error)
+	SuggestSearchQueries(ctx context.Context, query string) ([]string, error)
+	// SuggestStepTechniques picks, for each recipe step, the names from
+	// techniques that the step calls for
+	SuggestStepTechniques(ctx context.Context, steps []string, techniques []string) ([][]string, error)
+	// Translate translates each text from one language to another, both
+	// BCP 47 tags, returning the translations in the same order
+	Translate(ctx context.Context, texts []string, from, to string) (*AITranslation, error)
+	// Embed maps each text to a vector, in the same order, such that texts
+	// alike in meaning have similar vectors. No texts returns no vectors
+	// and the model that would make them.
+	Embed(ctx context.Context, texts []string) (*AIEmbeddings, error)
+	// SuggestSubstitutes proposes what to use in place of an ingredient,
+	// none when the model has nothing sound to offer
+	SuggestSubstitutes(ctx context.Context, ingredient string) (*AISubstitutes, error)
 }
 
 // AIConstraints for AI recipe generation
@@ -163,6 +1705,7 @@ type AIConstraints struct {
 	SkillLevel    string
 	Equipment     []string
 	AvoidIngredients []string
+	Language      string // BCP 47 tag to write the recipe in; English when empty
 }
 
 // AIRecipeResponse from AI service
@@ -174,6 +1717,36 @@ type AIRecipeResponse struct {
 	Nutrition    *NutritionInfo
 	Tags         []string
 	Confidence   float64
+	Language     string // BCP 47 tag the recipe is written in
+}
+
+// AISubstitutes are a model's suggestions for one ingredient
+type AISubstitutes struct {
+	Substitutes []AISubstitute
+	Model       string
+}
+
+// AISubstitute is one suggested substitute. Contains names the traits,
+// such as dairy or gluten, that diets may rule out.
+type AISubstitute struct {
+	Name     string   `json:"name"`
+	Ratio    string   `json:"ratio"`
+	Notes    string   `json:"notes"`
+	Contains []string `json:"contains"`
+}
+
+// AITranslation is a batch of machine-translated texts and the model that
+// translated them
+type AITranslation struct {
+	Texts []string
+	Model string
+}
+
+// AIEmbeddings are the vectors of a batch of texts and the model that
+// made them
+type AIEmbeddings struct {
+	Vectors [][]float32
+	Model   string
 }
 
 // AIIngredient from AI service
@@ -203,6 +1776,130 @@ type RecipeClassification struct {
 	Confidence float64
 }
 
+// OCRService extracts printed text from photos and scans of recipes
+type OCRService interface {
+	ExtractText(ctx context.Context, image []byte, contentType string) (*OCRResult, error)
+}
+
+// OCRResult is the recognized text in reading order. Empty lines mark block
+// breaks the provider detected between paragraphs.
+type OCRResult struct {
+	Provider string
+	Lines    []OCRLine
+}
+
+// OCRLine is one recognized line with its confidence from 0 to 1
+type OCRLine struct {
+	Text       string
+	Confidence float64
+}
+
+// ImageProber reads the pixel dimensions of a published image so structured
+// data checks can apply the rich result size and aspect ratio rules
+type ImageProber interface {
+	ProbeImage(ctx context.Context, url string) (*ImageInfo, error)
+}
+
+// ImageInfo describes a fetched image
+type ImageInfo struct {
+	Width       int
+	Height      int
+	ContentType string
+}
+
+// RecipePageReader fetches a recipe web page and reads the recipe on it.
+// Addresses on private networks are refused.
+type RecipePageReader interface {
+	ReadRecipePage(ctx context.Context, url string) (*RecipePage, error)
+}
+
+// RecipePage is the recipe read from a web page. URL is the page address
+// after redirects; Method is how the recipe was read: schema.org,
+// microdata or heuristic. Ingredient lines are free text.
+type RecipePage struct {
+	URL         string
+	Method      string
+	Title       string
+	Description string
+	Ingredients []string
+	Directions  []string
+	Servings    int
+	PrepTime    int // minutes
+	CookTime    int // minutes
+	Tags        []string
+}
+
+// ImageTranscoder resizes and re-encodes images. Formats are jpeg, png,
+// webp and avif.
+type ImageTranscoder interface {
+	// Probe reads an image's type and dimensions without decoding it
+	Probe(content []byte) (*ImageInfo, error)
+	// Transcode scales the image down to fit the options' box, never up,
+	// and encodes it in the options' format
+	Transcode(ctx context.Context, content []byte, opts TranscodeOptions) ([]byte, error)
+	// Supports reports whether the format can be encoded
+	Supports(format string) bool
+}
+
+// ImageFormatPreferences orders the formats images are served in, best
+// first. The modern formats are offered to clients that accept them and
+// transcoders that can encode them; jpeg and png are the baseline every
+// client takes, and an image falls back to whichever its original is.
+var ImageFormatPreferences = []string{"avif", "webp", "jpeg", "png"}
+
+// TranscodeOptions is one variant. A zero Height keeps the aspect ratio
+// from Width alone; Quality is 1 to 100.
+type TranscodeOptions struct {
+	Width   int
+	Height  int
+	Format  string
+	Quality int
+}
+
+// RecipePoster announces a newly published recipe on one channel, such as
+// a webhook or a social network account
+type RecipePoster interface {
+	Channel() string
+	Post(ctx context.Context, announcement RecipeAnnouncement) error
+}
+
+// SyncFeed tells a user's devices that data with its own sync endpoint,
+// such as a shopping list or a recipe draft, changed, so they refresh it
+// after their next pull. Failures are logged, not returned: the data itself
+// is already saved.
+type SyncFeed interface {
+	Touch(ctx context.Context, userID uuid.UUID, kind offline.Kind, key string, fields map[string]interface{})
+	// Remove leaves a tombstone so devices drop their copy
+	Remove(ctx context.Context, userID uuid.UUID, kind offline.Kind, key string)
+}
+
+// ShoppingListFeed adds items to a shopping list on a user's behalf, such
+// as restocks after cooking. Each addition's OpID makes retries add it
+// only once.
+type ShoppingListFeed interface {
+	// AddItems returns how many items were added; the user must own or
+	// share the list
+	AddItems(ctx context.Context, listID, userID uuid.UUID, items []ShoppingListAddition) (int, error)
+}
+
+// ShoppingListAddition is one item to add to a shopping list
+type ShoppingListAddition struct {
+	OpID     string
+	Name     string
+	Quantity string
+}
+
+// RecipeAnnouncement is what posters know about the recipe they announce
+type RecipeAnnouncement struct {
+	RecipeID    uuid.UUID
+	Title       string
+	Description string
+	AuthorName  string
+	Tags        []string
+	ImageURL    string
+	PublishedAt time.Time
+}
+
 // EmailService defines the interface for sending emails
 type EmailService interface {
 	SendWelcome(ctx context.Context, to string, name string) error
@@ -210,6 +1907,37 @@ type EmailService interface {
 	SendRecipePublished(ctx context.Context, to string, recipeTitle string) error
 	SendNewFollower(ctx context.Context, to string, followerName string) error
 	SendBulk(ctx context.Context, recipients []string, subject string, body string) error
+	SendCommentNotification(ctx context.Context, notification CommentNotificationEmail) error
+}
+
+// CapturedMessage is an email or announcement a sandbox kept instead of
+// sending
+type CapturedMessage struct {
+	Kind       string // email or announcement
+	To         string // Recipient address or announcement channel
+	Subject    string
+	Body       string
+	CapturedAt time.Time
+}
+
+// SandboxOutbox holds the messages a sandbox captured, newest first
+type SandboxOutbox interface {
+	Messages() []CapturedMessage
+	Clear()
+}
+
+// CommentNotificationEmail tells a user about a new comment or review on
+// their recipe. Replies to ReplyTo are posted back as threaded responses.
+type CommentNotificationEmail struct {
+	To          string
+	Name        string
+	ReplyTo     string
+	RecipeTitle string
+	RecipeURL   string
+	AuthorName  string
+	// Rating is set for reviews, zero for comments
+	Rating  int
+	Content string
 }
 
 // NotificationService defines the interface for push notifications
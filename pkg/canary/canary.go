@@ -0,0 +1,256 @@
+// Package canary splits traffic on a route between its stable handler and a
+// candidate implementation, so a change can be tried on a share of users
+// and compared before it replaces the stable one.
+package canary
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Variants a request can be served by
+const (
+	Stable    = "stable"
+	Candidate = "candidate"
+)
+
+// Header forces a variant on a request while its split is live and names
+// the variant that served the response
+const Header = "X-Alchemorsel-Canary"
+
+// Rule decides which requests a split sends to its candidate
+type Rule struct {
+	// Percent of subjects, from 0 to 100
+	Percent float64
+	// Cohorts are subjects always sent to the candidate
+	Cohorts []string
+}
+
+// live reports whether any traffic may reach the candidate
+func (r Rule) live() bool {
+	return r.Percent > 0 || len(r.Cohorts) > 0
+}
+
+// SubjectFunc identifies who a request is for, such as the signed-in user.
+// The same subject always lands on the same variant for a given percent.
+// An empty subject is assigned per request.
+type SubjectFunc func(r *http.Request) string
+
+// Split serves a route from the stable or candidate handler
+type Split struct {
+	name      string
+	stable    http.Handler
+	candidate http.Handler
+	subject   SubjectFunc
+	metrics   *Metrics
+	rule      atomic.Pointer[Rule]
+}
+
+func (s *Split) setRule(rule Rule) {
+	s.rule.Store(&rule)
+}
+
+// Rule returns the rule the split currently applies
+func (s *Split) Rule() Rule {
+	return *s.rule.Load()
+}
+
+// Variant picks the handler for r
+func (s *Split) Variant(r *http.Request) string {
+	rule := s.Rule()
+	if !rule.live() {
+		return Stable
+	}
+	switch r.Header.Get(Header) {
+	case Stable:
+		return Stable
+	case Candidate:
+		return Candidate
+	}
+
+	if rule.Includes(s.name, s.subject(r)) {
+		return Candidate
+	}
+	return Stable
+}
+
+// Includes reports whether the rule picks subject for the named split, or
+// for an experiment that chooses between variants without a route of its
+// own. An empty subject is placed at random on every call.
+func (r Rule) Includes(name, subject string) bool {
+	if subject != "" {
+		for _, cohort := range r.Cohorts {
+			if cohort == subject {
+				return true
+			}
+		}
+	}
+	return bucket(name, subject) < r.Percent*100
+}
+
+// bucket places a subject in one of 10000 buckets. Hashing the split name
+// in keeps the same users from landing in every canary.
+func bucket(name, subject string) float64 {
+	if subject == "" {
+		return float64(rand.Intn(10000))
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(subject))
+	return float64(h.Sum32() % 10000)
+}
+
+// ServeHTTP serves r from the picked variant and records how it went
+func (s *Split) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	variant := s.Variant(r)
+	handler := s.stable
+	if variant == Candidate {
+		handler = s.candidate
+	}
+	if s.Rule().live() {
+		w.Header().Set(Header, variant)
+	}
+
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	start := time.Now()
+	defer func() {
+		// A panicking variant counts as a server error before the
+		// recoverer further up turns it into one
+		if p := recover(); p != nil {
+			s.metrics.observe(s.name, variant, http.StatusInternalServerError, time.Since(start))
+			panic(p)
+		}
+		s.metrics.observe(s.name, variant, recorder.status, time.Since(start))
+	}()
+	handler.ServeHTTP(recorder, r)
+}
+
+// Registry holds the splits of a server and the rules they apply. Rules
+// can be replaced at any time, so rolling a candidate back needs no
+// restart.
+type Registry struct {
+	metrics *Metrics
+	logger  *zap.Logger
+
+	mu     sync.Mutex
+	splits map[string]*Split
+	rules  map[string]Rule
+}
+
+// NewRegistry creates an empty registry. metrics may be nil.
+func NewRegistry(metrics *Metrics, logger *zap.Logger) *Registry {
+	return &Registry{
+		metrics: metrics,
+		logger:  logger.Named("canary"),
+		splits:  make(map[string]*Split),
+		rules:   make(map[string]Rule),
+	}
+}
+
+// Split returns the handler serving the named split. Until a rule names
+// it, all traffic goes to stable.
+func (g *Registry) Split(name string, stable, candidate http.Handler, subject SubjectFunc) *Split {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	split := &Split{
+		name:      name,
+		stable:    stable,
+		candidate: candidate,
+		subject:   subject,
+		metrics:   g.metrics,
+	}
+	split.setRule(g.rules[name])
+	g.splits[name] = split
+	g.metrics.setPercent(name, g.rules[name].Percent)
+	return split
+}
+
+// Apply replaces every rule. Splits left out of rules go back to stable.
+func (g *Registry) Apply(rules map[string]Rule) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for name := range g.rules {
+		if _, ok := rules[name]; !ok {
+			g.logger.Info("Canary rolled back", zap.String("split", name))
+		}
+	}
+	for name, rule := range rules {
+		if old, ok := g.rules[name]; !ok || old.Percent != rule.Percent || !sameCohorts(old.Cohorts, rule.Cohorts) {
+			g.logger.Info("Canary rule changed",
+				zap.String("split", name),
+				zap.Float64("percent", rule.Percent),
+				zap.Int("cohorts", len(rule.Cohorts)),
+			)
+		}
+	}
+
+	g.rules = make(map[string]Rule, len(rules))
+	for name, rule := range rules {
+		g.rules[name] = rule
+	}
+	for name, split := range g.splits {
+		split.setRule(g.rules[name])
+		g.metrics.setPercent(name, g.rules[name].Percent)
+	}
+}
+
+// Rules returns the current rules by split name
+func (g *Registry) Rules() map[string]Rule {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	rules := make(map[string]Rule, len(g.rules))
+	for name, rule := range g.rules {
+		rules[name] = rule
+	}
+	return rules
+}
+
+func sameCohorts(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// statusRecorder keeps the status code a handler wrote
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Flush passes through so streamed responses still stream
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// statusClass groups codes so the metric stays small
+func statusClass(code int) string {
+	return strconv.Itoa(code/100) + "xx"
+}
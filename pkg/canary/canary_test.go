@@ -0,0 +1,93 @@
+package canary
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func variantHandler(variant string, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(variant))
+	})
+}
+
+func userSubject(r *http.Request) string {
+	return r.Header.Get("X-User")
+}
+
+func serve(split http.Handler, user string, header string) string {
+	req := httptest.NewRequest("GET", "/api/v1/recipes?q=soup", nil)
+	req.Header.Set("X-User", user)
+	if header != "" {
+		req.Header.Set(Header, header)
+	}
+	rec := httptest.NewRecorder()
+	split.ServeHTTP(rec, req)
+	return rec.Body.String()
+}
+
+func TestSplitSendsPercentOfSubjectsStickily(t *testing.T) {
+	registry := NewRegistry(nil, zap.NewNop())
+	split := registry.Split("search", variantHandler(Stable, 200), variantHandler(Candidate, 200), userSubject)
+	registry.Apply(map[string]Rule{"search": {Percent: 20}})
+
+	candidates := 0
+	for i := 0; i < 2000; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		first := serve(split, user, "")
+		assert.Equal(t, first, serve(split, user, ""), "a user stays on one variant")
+		if first == Candidate {
+			candidates++
+		}
+	}
+	assert.InDelta(t, 400, candidates, 80)
+}
+
+func TestSplitCohortsAndOverride(t *testing.T) {
+	registry := NewRegistry(nil, zap.NewNop())
+	registry.Apply(map[string]Rule{"search": {Cohorts: []string{"beta-tester"}}})
+	split := registry.Split("search", variantHandler(Stable, 200), variantHandler(Candidate, 200), userSubject)
+
+	assert.Equal(t, Candidate, serve(split, "beta-tester", ""))
+	assert.Equal(t, Stable, serve(split, "someone-else", ""))
+	assert.Equal(t, Candidate, serve(split, "someone-else", Candidate))
+	assert.Equal(t, Stable, serve(split, "beta-tester", Stable))
+}
+
+func TestApplyRollsBackInstantly(t *testing.T) {
+	registry := NewRegistry(nil, zap.NewNop())
+	split := registry.Split("search", variantHandler(Stable, 200), variantHandler(Candidate, 200), userSubject)
+	registry.Apply(map[string]Rule{"search": {Percent: 100}})
+	assert.Equal(t, Candidate, serve(split, "u-1", ""))
+
+	registry.Apply(map[string]Rule{"search": {Percent: 0}})
+	assert.Equal(t, Stable, serve(split, "u-1", ""))
+	assert.Equal(t, Stable, serve(split, "u-1", Candidate), "the override only works while the canary is live")
+
+	registry.Apply(map[string]Rule{"search": {Percent: 100}})
+	registry.Apply(nil)
+	assert.Equal(t, Stable, serve(split, "u-1", ""))
+}
+
+func TestSplitRecordsComparativeMetrics(t *testing.T) {
+	metrics := NewMetrics("test", prometheus.NewRegistry())
+	registry := NewRegistry(metrics, zap.NewNop())
+	split := registry.Split("search", variantHandler(Stable, 200), variantHandler(Candidate, 500), userSubject)
+	registry.Apply(map[string]Rule{"search": {Percent: 50}})
+
+	serve(split, "u-1", Stable)
+	serve(split, "u-1", Candidate)
+	serve(split, "u-1", Candidate)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.requests.WithLabelValues("search", Stable, "2xx")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.requests.WithLabelValues("search", Candidate, "5xx")))
+	assert.Equal(t, 50.0, testutil.ToFloat64(metrics.percent.WithLabelValues("search")))
+}
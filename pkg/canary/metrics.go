@@ -0,0 +1,56 @@
+package canary
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics compares the variants of each split. A nil *Metrics records
+// nothing.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	percent  *prometheus.GaugeVec
+}
+
+// NewMetrics registers the canary metrics under namespace with reg
+func NewMetrics(namespace string, reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+	return &Metrics{
+		requests: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "canary",
+			Name:      "requests_total",
+			Help:      "Requests served by each variant of a split, by status class",
+		}, []string{"split", "variant", "status"}),
+		duration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "canary",
+			Name:      "request_duration_seconds",
+			Help:      "Time each variant of a split took to serve a request",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"split", "variant"}),
+		percent: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "canary",
+			Name:      "percent",
+			Help:      "Share of subjects a split sends to its candidate",
+		}, []string{"split"}),
+	}
+}
+
+func (m *Metrics) observe(split, variant string, status int, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.requests.WithLabelValues(split, variant, statusClass(status)).Inc()
+	m.duration.WithLabelValues(split, variant).Observe(elapsed.Seconds())
+}
+
+func (m *Metrics) setPercent(split string, percent float64) {
+	if m != nil {
+		m.percent.WithLabelValues(split).Set(percent)
+	}
+}
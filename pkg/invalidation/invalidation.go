@@ -0,0 +1,173 @@
+// Package invalidation broadcasts cache invalidations between replicas, so
+// an in-process cache on one replica drops entries changed through another.
+//
+// Keys name the changed entity the way caches key it, such as recipe:{id}
+// or user:{id}. A replica that changes an entity clears its own caches and
+// publishes the key; every other replica hands it to the subscribers whose
+// prefix matches. Delivery is best effort: a replica that misses a message
+// relies on its cache TTLs.
+package invalidation
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Message is one broadcast invalidation
+type Message struct {
+	Keys   []string  `json:"keys"`
+	Origin string    `json:"origin"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// Transport carries messages to every replica, including the sender
+type Transport interface {
+	Publish(ctx context.Context, payload []byte) error
+	// Listen hands each payload to fn until ctx ends
+	Listen(ctx context.Context, fn func(payload []byte)) error
+}
+
+// subscription is a handler for the keys starting with prefix
+type subscription struct {
+	prefix string
+	fn     func(key string)
+}
+
+// Bus publishes this replica's invalidations and applies the others'
+type Bus struct {
+	transport Transport
+	origin    string
+	metrics   *Metrics
+	logger    *zap.Logger
+	now       func() time.Time
+
+	mu            sync.RWMutex
+	subscriptions []subscription
+}
+
+// NewBus creates a bus for the replica named origin
+func NewBus(transport Transport, origin string, metrics *Metrics, logger *zap.Logger) *Bus {
+	return &Bus{
+		transport: transport,
+		origin:    origin,
+		metrics:   metrics,
+		logger:    logger.Named("invalidation"),
+		now:       time.Now,
+	}
+}
+
+// Subscribe calls fn with each key another replica invalidates that starts
+// with prefix. An empty prefix matches every key.
+func (b *Bus) Subscribe(prefix string, fn func(key string)) {
+	b.mu.Lock()
+	b.subscriptions = append(b.subscriptions, subscription{prefix: prefix, fn: fn})
+	b.mu.Unlock()
+}
+
+// Publish tells the other replicas that keys changed. The caller clears
+// its own caches; the bus does not deliver a replica's messages to itself.
+func (b *Bus) Publish(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(Message{Keys: keys, Origin: b.origin, SentAt: b.now().UTC()})
+	if err != nil {
+		return err
+	}
+	if err := b.transport.Publish(ctx, payload); err != nil {
+		b.metrics.published("error")
+		return err
+	}
+	b.metrics.published("ok")
+	return nil
+}
+
+// Run applies the other replicas' invalidations until ctx ends
+func (b *Bus) Run(ctx context.Context) error {
+	err := b.transport.Listen(ctx, b.receive)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// receive dispatches one message to the matching subscribers
+func (b *Bus) receive(payload []byte) {
+	var msg Message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		b.metrics.received("malformed")
+		b.logger.Warn("Dropped malformed invalidation", zap.Error(err))
+		return
+	}
+	if msg.Origin == b.origin {
+		return
+	}
+
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+	for _, key := range msg.Keys {
+		for _, sub := range subscriptions {
+			if strings.HasPrefix(key, sub.prefix) {
+				sub.fn(key)
+			}
+		}
+	}
+	b.metrics.received("applied")
+	b.metrics.observeLatency(b.now().Sub(msg.SentAt))
+}
+
+// Hub is an in-process Transport factory. Each Transport it hands out
+// reaches every other, which lets tests stand in for several replicas.
+type Hub struct {
+	mu        sync.Mutex
+	listeners map[int]func([]byte)
+	next      int
+}
+
+// NewHub creates an empty hub
+func NewHub() *Hub {
+	return &Hub{listeners: make(map[int]func([]byte))}
+}
+
+// Transport returns a transport on the hub
+func (h *Hub) Transport() Transport {
+	return hubTransport{hub: h}
+}
+
+type hubTransport struct {
+	hub *Hub
+}
+
+func (t hubTransport) Publish(ctx context.Context, payload []byte) error {
+	t.hub.mu.Lock()
+	listeners := make([]func([]byte), 0, len(t.hub.listeners))
+	for _, fn := range t.hub.listeners {
+		listeners = append(listeners, fn)
+	}
+	t.hub.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(payload)
+	}
+	return nil
+}
+
+func (t hubTransport) Listen(ctx context.Context, fn func([]byte)) error {
+	t.hub.mu.Lock()
+	id := t.hub.next
+	t.hub.next++
+	t.hub.listeners[id] = fn
+	t.hub.mu.Unlock()
+
+	<-ctx.Done()
+	t.hub.mu.Lock()
+	delete(t.hub.listeners, id)
+	t.hub.mu.Unlock()
+	return ctx.Err()
+}
@@ -0,0 +1,52 @@
+package invalidation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBusDeliversToOtherReplicasByPrefix(t *testing.T) {
+	hub := NewHub()
+	metrics := NewMetrics("test", prometheus.NewRegistry())
+	a := NewBus(hub.Transport(), "replica-a", nil, zap.NewNop())
+	b := NewBus(hub.Transport(), "replica-b", metrics, zap.NewNop())
+
+	var onA, recipesOnB, allOnB []string
+	a.Subscribe("", func(key string) { onA = append(onA, key) })
+	b.Subscribe("recipe:", func(key string) { recipesOnB = append(recipesOnB, key) })
+	b.Subscribe("", func(key string) { allOnB = append(allOnB, key) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan error, 2)
+	for _, bus := range []*Bus{a, b} {
+		go func(bus *Bus) { stopped <- bus.Run(ctx) }(bus)
+	}
+	require.Eventually(t, func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		return len(hub.listeners) == 2
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, a.Publish(ctx, "recipe:42", "user:7"))
+	assert.Equal(t, []string{"recipe:42"}, recipesOnB)
+	assert.Equal(t, []string{"recipe:42", "user:7"}, allOnB)
+	assert.Empty(t, onA, "a replica has already cleared its own caches")
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.latency))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.receives.WithLabelValues("applied")))
+
+	hub.Transport().Publish(ctx, []byte("not json"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.receives.WithLabelValues("malformed")))
+
+	cancel()
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, <-stopped, "a cancelled bus stops cleanly")
+	}
+}
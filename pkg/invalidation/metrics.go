@@ -0,0 +1,65 @@
+package invalidation
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics counts invalidation traffic. A nil *Metrics records nothing.
+type Metrics struct {
+	publishes *prometheus.CounterVec
+	receives  *prometheus.CounterVec
+	latency   prometheus.Histogram
+}
+
+// NewMetrics registers the invalidation metrics under namespace with reg
+func NewMetrics(namespace string, reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+	return &Metrics{
+		publishes: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache_invalidation",
+			Name:      "published_total",
+			Help:      "Invalidations this replica broadcast, by result: ok or error",
+		}, []string{"result"}),
+		receives: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache_invalidation",
+			Name:      "received_total",
+			Help:      "Invalidations received from other replicas, by result: applied or malformed",
+		}, []string{"result"}),
+		latency: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "cache_invalidation",
+			Name:      "latency_seconds",
+			Help:      "Time from another replica publishing an invalidation to this one applying it",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 5},
+		}),
+	}
+}
+
+func (m *Metrics) published(result string) {
+	if m != nil {
+		m.publishes.WithLabelValues(result).Inc()
+	}
+}
+
+func (m *Metrics) received(result string) {
+	if m != nil {
+		m.receives.WithLabelValues(result).Inc()
+	}
+}
+
+// observeLatency records how stale the other replica's cache was at most.
+// Clock skew between hosts can make it negative, which counts as zero.
+func (m *Metrics) observeLatency(latency time.Duration) {
+	if m == nil {
+		return
+	}
+	if latency < 0 {
+		latency = 0
+	}
+	m.latency.Observe(latency.Seconds())
+}
@@ -0,0 +1,48 @@
+package invalidation
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisTransport carries messages over a Redis pub/sub channel. Messages
+// sent while a replica is reconnecting are lost to it, as pub/sub keeps no
+// backlog.
+type RedisTransport struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// NewRedisTransport creates a transport on channel
+func NewRedisTransport(client redis.UniversalClient, channel string) *RedisTransport {
+	return &RedisTransport{client: client, channel: channel}
+}
+
+// Publish sends payload to every subscribed replica
+func (t *RedisTransport) Publish(ctx context.Context, payload []byte) error {
+	return t.client.Publish(ctx, t.channel, payload).Err()
+}
+
+// Listen subscribes to the channel and hands each message to fn. The
+// client resubscribes by itself after a dropped connection.
+func (t *RedisTransport) Listen(ctx context.Context, fn func([]byte)) error {
+	sub := t.client.Subscribe(ctx, t.channel)
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			fn([]byte(msg.Payload))
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Record is the database row behind a lease. Released leases keep their row
+// so the next holder's token still grows.
+type Record struct {
+	Name      string    `gorm:"primaryKey;size:128"`
+	Owner     string    `gorm:"size:255;not null"`
+	Token     int64     `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+// TableName sets the table name for GORM
+func (Record) TableName() string {
+	return "leases"
+}
+
+// acquireSQL inserts the lease or, when it has lapsed or belongs to the same
+// owner, updates it in place. No row comes back when another owner holds it.
+// It runs unchanged on PostgreSQL and SQLite 3.35+.
+const acquireSQL = `
+INSERT INTO leases (name, owner, token, expires_at) VALUES (?, ?, 1, ?)
+ON CONFLICT (name) DO UPDATE SET
+	token = CASE WHEN leases.owner = excluded.owner AND leases.expires_at > ? THEN leases.token ELSE leases.token + 1 END,
+	owner = excluded.owner,
+	expires_at = excluded.expires_at
+WHERE leases.owner = excluded.owner OR leases.expires_at <= ?
+RETURNING token`
+
+// DatabaseStore keeps leases in the leases table
+type DatabaseStore struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+// NewDatabaseStore creates a store on the given database
+func NewDatabaseStore(db *gorm.DB) *DatabaseStore {
+	return &DatabaseStore{db: db, now: time.Now}
+}
+
+// Acquire takes, extends or reports the named lease
+func (s *DatabaseStore) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (Lease, bool, error) {
+	now := s.now().UTC()
+	expiresAt := now.Add(ttl)
+
+	var tokens []int64
+	if err := s.db.WithContext(ctx).Raw(acquireSQL, name, owner, expiresAt, now, now).Scan(&tokens).Error; err != nil {
+		return Lease{}, false, fmt.Errorf("acquire lease: %w", err)
+	}
+	if len(tokens) > 0 {
+		return Lease{Name: name, Owner: owner, Token: tokens[0], ExpiresAt: expiresAt}, true, nil
+	}
+
+	var holder Record
+	err := s.db.WithContext(ctx).Where("name = ?", name).First(&holder).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Lease{}, false, fmt.Errorf("acquire lease: %s vanished", name)
+	}
+	if err != nil {
+		return Lease{}, false, fmt.Errorf("read lease holder: %w", err)
+	}
+	return Lease{Name: name, Owner: holder.Owner, Token: holder.Token, ExpiresAt: holder.ExpiresAt}, false, nil
+}
+
+// Release expires the lease if owner holds it
+func (s *DatabaseStore) Release(ctx context.Context, name, owner string) error {
+	err := s.db.WithContext(ctx).Model(&Record{}).
+		Where("name = ? AND owner = ?", name, owner).
+		Update("expires_at", s.now().UTC()).Error
+	if err != nil {
+		return fmt.Errorf("release lease: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Elector keeps trying to hold a named lease and reports whether this
+// process is the leader. Leadership is only claimed while the last renewal
+// is younger than the lease, so a stalled renew loop steps down on its own.
+type Elector struct {
+	store   Store
+	name    string
+	owner   string
+	ttl     time.Duration
+	metrics *Metrics
+	logger  *zap.Logger
+	now     func() time.Time
+
+	mu        sync.Mutex
+	lease     Lease
+	leader    bool
+	renewedAt time.Time
+	holder    string
+}
+
+// NewElector creates an elector for the named lease. metrics may be nil.
+func NewElector(store Store, name, owner string, ttl time.Duration, metrics *Metrics, logger *zap.Logger) *Elector {
+	return &Elector{
+		store:   store,
+		name:    name,
+		owner:   owner,
+		ttl:     ttl,
+		metrics: metrics,
+		logger:  logger.Named("leader-election").With(zap.String("lease", name), zap.String("owner", owner)),
+		now:     time.Now,
+	}
+}
+
+// Run campaigns for leadership every third of the lease until ctx ends, then
+// releases the lease so another replica can take over at once
+func (e *Elector) Run(ctx context.Context) {
+	e.Campaign(ctx)
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			e.resign()
+			return
+		case <-ticker.C:
+			e.Campaign(ctx)
+		}
+	}
+}
+
+// IsLeader reports whether this process holds leadership
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader && e.now().Sub(e.renewedAt) < e.ttl
+}
+
+// Leader returns the current leader as last seen, which may be this process
+func (e *Elector) Leader() Lease {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lease
+}
+
+// Owner is the identity this elector campaigns under
+func (e *Elector) Owner() string {
+	return e.owner
+}
+
+// Do runs a leader-only job under its own lease, so a job still running on
+// a former leader is not started again by the new one. It returns
+// ErrNotLeader or ErrNotAcquired without running fn.
+func (e *Elector) Do(ctx context.Context, job string, fn func(ctx context.Context) error) error {
+	if !e.IsLeader() {
+		return ErrNotLeader
+	}
+	name := e.name + ":" + job
+	err := Do(ctx, e.store, name, e.owner, e.ttl, fn)
+	switch {
+	case errors.Is(err, ErrNotAcquired):
+		e.metrics.attempt(name, "busy")
+	case errors.Is(err, ErrLost):
+		e.metrics.loss(name)
+	}
+	return err
+}
+
+// Campaign makes one attempt to gain or keep leadership
+func (e *Elector) Campaign(ctx context.Context) {
+	lease, ok, err := e.store.Acquire(ctx, e.name, e.owner, e.ttl)
+	now := e.now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch {
+	case err != nil:
+		e.metrics.attempt(e.name, "error")
+		if ctx.Err() == nil {
+			e.logger.Warn("Failed to renew leadership lease", zap.Error(err))
+		}
+		if e.leader && now.Sub(e.renewedAt) >= e.ttl {
+			e.stepDown("lease expired while the store was unreachable")
+		}
+	case !ok:
+		e.metrics.attempt(e.name, "busy")
+		if e.leader {
+			e.stepDown("lease taken by " + lease.Owner)
+		}
+		e.lease = lease
+		e.holder = lease.Owner
+	default:
+		// A new token means the lease lapsed in between, so this is a new term
+		if e.leader && lease.Token == e.lease.Token {
+			e.metrics.attempt(e.name, "renewed")
+		} else {
+			e.metrics.attempt(e.name, "acquired")
+			e.metrics.setHeld(e.name, true)
+			fields := []zap.Field{zap.Int64("token", lease.Token)}
+			if e.holder != "" && e.holder != e.owner {
+				e.metrics.takeover(e.name)
+				fields = append(fields, zap.String("previous_leader", e.holder))
+			}
+			e.logger.Info("Became leader", fields...)
+		}
+		e.leader = true
+		e.renewedAt = now
+		e.lease = lease
+		e.holder = e.owner
+	}
+}
+
+// stepDown gives up leadership; callers hold mu
+func (e *Elector) stepDown(reason string) {
+	e.leader = false
+	e.metrics.loss(e.name)
+	e.metrics.setHeld(e.name, false)
+	e.logger.Warn("Lost leadership", zap.String("reason", reason))
+}
+
+func (e *Elector) resign() {
+	e.mu.Lock()
+	wasLeader := e.leader
+	e.leader = false
+	e.mu.Unlock()
+	if !wasLeader {
+		return
+	}
+
+	e.metrics.setHeld(e.name, false)
+	ctx, cancel := context.WithTimeout(context.Background(), e.ttl)
+	defer cancel()
+	if err := e.store.Release(ctx, e.name, e.owner); err != nil {
+		e.logger.Warn("Failed to release leadership lease", zap.Error(err))
+		return
+	}
+	e.logger.Info("Resigned leadership")
+}
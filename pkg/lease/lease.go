@@ -0,0 +1,170 @@
+// Package lease provides distributed locks and leader election on top of
+// expiring leases, so scheduled work runs on one replica at a time.
+//
+// A lease has a name, an owner and a time to live. The owner keeps it by
+// acquiring it again before it expires; once it lapses any other owner may
+// take it over. Every new holder gets a larger fencing token, so work started
+// under an older token can be told apart from the current holder's.
+package lease
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotAcquired means another owner holds the lease
+	ErrNotAcquired = errors.New("lease held by another owner")
+	// ErrLost means the lease lapsed or was taken over while work ran under it
+	ErrLost = errors.New("lease lost")
+	// ErrNotLeader means the elector does not hold leadership
+	ErrNotLeader = errors.New("not the leader")
+)
+
+// Lease describes the current holder of a named lease
+type Lease struct {
+	Name      string
+	Owner     string
+	Token     int64
+	ExpiresAt time.Time
+}
+
+// Store keeps leases where every replica can see them
+type Store interface {
+	// Acquire takes the lease when it is free or expired, or extends it when
+	// owner already holds it. When another owner holds it, Acquire returns
+	// that holder and false.
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (Lease, bool, error)
+	// Release gives the lease up if owner holds it
+	Release(ctx context.Context, name, owner string) error
+}
+
+// DefaultOwner names this process: host, pid and a random suffix so two
+// processes on one host never share an identity
+func DefaultOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Do runs fn while holding the named lease, extending it every third of
+// ttl. It returns ErrNotAcquired without running fn when another owner holds
+// the lease. If the lease is lost while fn runs, fn's context is cancelled
+// and Do returns ErrLost.
+func Do(ctx context.Context, store Store, name, owner string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	held, ok, err := store.Acquire(ctx, name, owner, ttl)
+	if err != nil {
+		return fmt.Errorf("acquire lease %s: %w", name, err)
+	}
+	if !ok {
+		return ErrNotAcquired
+	}
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		keep(runCtx, store, held, ttl, cancel)
+	}()
+
+	err = fn(runCtx)
+	cancel(nil)
+	<-done
+
+	releaseCtx, stop := context.WithTimeout(context.Background(), ttl)
+	defer stop()
+	store.Release(releaseCtx, name, owner)
+
+	if lost := context.Cause(runCtx); errors.Is(lost, ErrLost) {
+		return fmt.Errorf("%s: %w", name, lost)
+	}
+	return err
+}
+
+// keep extends a held lease until ctx ends, cancelling with ErrLost when the
+// lease is taken over or cannot be renewed before it expires
+func keep(ctx context.Context, store Store, held Lease, ttl time.Duration, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewed, ok, err := store.Acquire(ctx, held.Name, held.Owner, ttl)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				if time.Now().After(held.ExpiresAt) {
+					cancel(ErrLost)
+					return
+				}
+			case !ok || renewed.Token != held.Token:
+				cancel(ErrLost)
+				return
+			default:
+				held = renewed
+			}
+		}
+	}
+}
+
+// MemoryStore keeps leases in process. It suits a single replica and tests.
+type MemoryStore struct {
+	mu     sync.Mutex
+	leases map[string]Lease
+	tokens map[string]int64
+	now    func() time.Time
+}
+
+// NewMemoryStore creates an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		leases: make(map[string]Lease),
+		tokens: make(map[string]int64),
+		now:    time.Now,
+	}
+}
+
+// Acquire takes, extends or reports the named lease
+func (s *MemoryStore) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (Lease, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	current, ok := s.leases[name]
+	live := ok && now.Before(current.ExpiresAt)
+	if live && current.Owner != owner {
+		return current, false, nil
+	}
+	if !live {
+		s.tokens[name]++
+		current = Lease{Name: name, Owner: owner, Token: s.tokens[name]}
+	}
+	current.ExpiresAt = now.Add(ttl)
+	s.leases[name] = current
+	return current, true, nil
+}
+
+// Release drops the lease if owner holds it
+func (s *MemoryStore) Release(ctx context.Context, name, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.leases[name]; ok && current.Owner == owner {
+		delete(s.leases, name)
+	}
+	return nil
+}
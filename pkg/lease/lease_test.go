@@ -0,0 +1,153 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// clock is a settable time source for stores and electors
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func testStores(t *testing.T, c *clock) map[string]Store {
+	memory := NewMemoryStore()
+	memory.now = c.now
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Record{}))
+	database := NewDatabaseStore(db)
+	database.now = c.now
+
+	return map[string]Store{"memory": memory, "database": database}
+}
+
+func TestStoresHandOverExpiredLeases(t *testing.T) {
+	c := &clock{t: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	for kind, store := range testStores(t, c) {
+		t.Run(kind, func(t *testing.T) {
+			ctx := context.Background()
+
+			first, ok, err := store.Acquire(ctx, "scheduler", "a", 10*time.Second)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			holder, ok, err := store.Acquire(ctx, "scheduler", "b", 10*time.Second)
+			require.NoError(t, err)
+			assert.False(t, ok, "a live lease is not handed over")
+			assert.Equal(t, "a", holder.Owner)
+
+			c.advance(5 * time.Second)
+			renewed, ok, err := store.Acquire(ctx, "scheduler", "a", 10*time.Second)
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, first.Token, renewed.Token, "renewal keeps the token")
+
+			c.advance(11 * time.Second)
+			taken, ok, err := store.Acquire(ctx, "scheduler", "b", 10*time.Second)
+			require.NoError(t, err)
+			require.True(t, ok, "an expired lease can be taken over")
+			assert.Greater(t, taken.Token, renewed.Token)
+
+			require.NoError(t, store.Release(ctx, "scheduler", "a"), "releasing a lease held by someone else is a no-op")
+			_, ok, err = store.Acquire(ctx, "scheduler", "a", 10*time.Second)
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			require.NoError(t, store.Release(ctx, "scheduler", "b"))
+			again, ok, err := store.Acquire(ctx, "scheduler", "a", 10*time.Second)
+			require.NoError(t, err)
+			require.True(t, ok, "a released lease is free at once")
+			assert.Greater(t, again.Token, taken.Token)
+		})
+	}
+}
+
+func TestDoRunsOnceAndReleases(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	err := Do(ctx, store, "archive", "a", time.Minute, func(ctx context.Context) error {
+		inner := Do(ctx, store, "archive", "b", time.Minute, func(context.Context) error {
+			t.Fatal("a held lease must not run twice")
+			return nil
+		})
+		assert.ErrorIs(t, inner, ErrNotAcquired)
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, ok, err := store.Acquire(ctx, "archive", "b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "Do releases the lease when fn returns")
+}
+
+// stolenStore hands the lease to someone else after the first acquisition
+type stolenStore struct {
+	*MemoryStore
+	calls int
+}
+
+func (s *stolenStore) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (Lease, bool, error) {
+	s.calls++
+	if s.calls > 1 {
+		return Lease{Name: name, Owner: "thief", Token: 99}, false, nil
+	}
+	return s.MemoryStore.Acquire(ctx, name, owner, ttl)
+}
+
+func TestDoCancelsWorkWhenLeaseIsTakenOver(t *testing.T) {
+	store := &stolenStore{MemoryStore: NewMemoryStore()}
+	err := Do(context.Background(), store, "archive", "a", 30*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.True(t, errors.Is(err, ErrLost), "got %v", err)
+}
+
+func TestElectorTakesOverFromSilentLeader(t *testing.T) {
+	c := &clock{t: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = c.now
+	ctx := context.Background()
+
+	a := NewElector(store, "scheduler", "a", 9*time.Second, nil, zap.NewNop())
+	b := NewElector(store, "scheduler", "b", 9*time.Second, nil, zap.NewNop())
+	a.now, b.now = c.now, c.now
+
+	a.Campaign(ctx)
+	b.Campaign(ctx)
+	assert.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader())
+	assert.Equal(t, "a", b.Leader().Owner)
+
+	// a stops renewing; once its lease lapses it no longer claims leadership
+	// and b takes over
+	c.advance(10 * time.Second)
+	assert.False(t, a.IsLeader())
+	b.Campaign(ctx)
+	assert.True(t, b.IsLeader())
+
+	a.Campaign(ctx)
+	assert.False(t, a.IsLeader())
+	assert.Equal(t, "b", a.Leader().Owner)
+
+	ran := false
+	require.NoError(t, b.Do(ctx, "browse-refresh", func(context.Context) error {
+		ran = true
+		return nil
+	}))
+	assert.True(t, ran)
+	assert.ErrorIs(t, a.Do(ctx, "browse-refresh", func(context.Context) error { return nil }), ErrNotLeader)
+}
@@ -0,0 +1,74 @@
+package lease
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics counts lease activity. A nil *Metrics records nothing.
+type Metrics struct {
+	held      *prometheus.GaugeVec
+	attempts  *prometheus.CounterVec
+	takeovers *prometheus.CounterVec
+	losses    *prometheus.CounterVec
+}
+
+// NewMetrics registers the lease metrics under namespace with reg
+func NewMetrics(namespace string, reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+	return &Metrics{
+		held: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "lease",
+			Name:      "held",
+			Help:      "1 while this process holds the lease",
+		}, []string{"name"}),
+		attempts: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "lease",
+			Name:      "attempts_total",
+			Help:      "Lease acquisitions and renewals by result: acquired, renewed, busy or error",
+		}, []string{"name", "result"}),
+		takeovers: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "lease",
+			Name:      "takeovers_total",
+			Help:      "Times this process took a lease over from another owner",
+		}, []string{"name"}),
+		losses: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "lease",
+			Name:      "losses_total",
+			Help:      "Times this process lost a lease it held",
+		}, []string{"name"}),
+	}
+}
+
+func (m *Metrics) attempt(name, result string) {
+	if m != nil {
+		m.attempts.WithLabelValues(name, result).Inc()
+	}
+}
+
+func (m *Metrics) setHeld(name string, held bool) {
+	if m == nil {
+		return
+	}
+	value := 0.0
+	if held {
+		value = 1
+	}
+	m.held.WithLabelValues(name).Set(value)
+}
+
+func (m *Metrics) takeover(name string) {
+	if m != nil {
+		m.takeovers.WithLabelValues(name).Inc()
+	}
+}
+
+func (m *Metrics) loss(name string) {
+	if m != nil {
+		m.losses.WithLabelValues(name).Inc()
+	}
+}
@@ -0,0 +1,85 @@
+package lease
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// acquireScript takes, extends or reports a lease kept as a hash with owner
+// and token fields. The token counter lives in its own key so it survives
+// the lease expiring.
+var acquireScript = redis.NewScript(`
+local owner = redis.call('HGET', KEYS[1], 'owner')
+if owner and owner ~= ARGV[1] then
+	return {0, owner, tonumber(redis.call('HGET', KEYS[1], 'token')), redis.call('PTTL', KEYS[1])}
+end
+local token
+if owner then
+	token = tonumber(redis.call('HGET', KEYS[1], 'token'))
+else
+	token = redis.call('INCR', KEYS[2])
+	redis.call('HSET', KEYS[1], 'owner', ARGV[1], 'token', token)
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return {1, ARGV[1], token, tonumber(ARGV[2])}
+`)
+
+// releaseScript deletes the lease only when the caller holds it
+var releaseScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// RedisStore keeps leases in Redis
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore creates a store whose keys start with prefix
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// keys returns the lease and token keys. The hash tag keeps both in one
+// cluster slot, as a script may only touch keys of one slot.
+func (s *RedisStore) keys(name string) []string {
+	key := s.prefix + "{" + name + "}"
+	return []string{key, key + ":token"}
+}
+
+// Acquire takes, extends or reports the named lease
+func (s *RedisStore) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (Lease, bool, error) {
+	now := time.Now()
+	result, err := acquireScript.Run(ctx, s.client, s.keys(name), owner, ttl.Milliseconds()).Slice()
+	if err != nil {
+		return Lease{}, false, fmt.Errorf("acquire lease: %w", err)
+	}
+	if len(result) != 4 {
+		return Lease{}, false, fmt.Errorf("acquire lease: unexpected reply %v", result)
+	}
+
+	acquired, _ := result[0].(int64)
+	holder, _ := result[1].(string)
+	token, _ := result[2].(int64)
+	remaining, _ := result[3].(int64)
+	return Lease{
+		Name:      name,
+		Owner:     holder,
+		Token:     token,
+		ExpiresAt: now.Add(time.Duration(remaining) * time.Millisecond),
+	}, acquired == 1, nil
+}
+
+// Release deletes the lease if owner holds it
+func (s *RedisStore) Release(ctx context.Context, name, owner string) error {
+	if err := releaseScript.Run(ctx, s.client, s.keys(name)[:1], owner).Err(); err != nil {
+		return fmt.Errorf("release lease: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package shadow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxDiffs stops a comparison once this many differences are found
+const maxDiffs = 50
+
+// maxValueLen truncates values in reported differences
+const maxValueLen = 80
+
+// Response is a status, content type and body to compare
+type Response struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// Difference is one place two responses disagree. The values are scrubbed
+// of personal data.
+type Difference struct {
+	Path    string `json:"path"`
+	Primary string `json:"primary"`
+	Shadow  string `json:"shadow"`
+}
+
+// Compare normalizes both responses and lists where they differ. JSON is
+// compared structurally without the ignored keys; HTML line by line after
+// dropping comments, CSRF tokens and nonces; anything else byte for byte.
+func Compare(primary, shadow Response, ignore map[string]bool) []Difference {
+	var diffs []Difference
+	if primary.Status != shadow.Status {
+		diffs = append(diffs, Difference{Path: "status", Primary: strconv.Itoa(primary.Status), Shadow: strconv.Itoa(shadow.Status)})
+	}
+	primaryType, shadowType := mediaType(primary.ContentType), mediaType(shadow.ContentType)
+	if primaryType != shadowType {
+		return append(diffs, Difference{Path: "content-type", Primary: primaryType, Shadow: shadowType})
+	}
+
+	switch {
+	case primaryType == "application/json" || strings.HasSuffix(primaryType, "+json"):
+		var a, b interface{}
+		if decodeJSON(primary.Body, &a) == nil && decodeJSON(shadow.Body, &b) == nil {
+			return diffJSON(diffs, "$", "", a, b, ignore)
+		}
+	case primaryType == "text/html":
+		return diffLines(diffs, normalizeHTML(primary.Body), normalizeHTML(shadow.Body))
+	}
+	if !bytes.Equal(primary.Body, shadow.Body) {
+		diffs = append(diffs, Difference{
+			Path:    "body",
+			Primary: fmt.Sprintf("%d bytes", len(primary.Body)),
+			Shadow:  fmt.Sprintf("%d bytes", len(shadow.Body)),
+		})
+	}
+	return diffs
+}
+
+func mediaType(contentType string) string {
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return media
+}
+
+func decodeJSON(body []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// diffJSON walks both values. key is the object key a or b sits under, so
+// scalars under sensitive keys are redacted.
+func diffJSON(diffs []Difference, path, key string, a, b interface{}, ignore map[string]bool) []Difference {
+	if len(diffs) >= maxDiffs {
+		return diffs
+	}
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make(map[string]bool, len(av)+len(bv))
+		for k := range av {
+			keys[k] = true
+		}
+		for k := range bv {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			if !ignore[k] {
+				sorted = append(sorted, k)
+			}
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			childPath := path + "." + k
+			ac, aok := av[k]
+			bc, bok := bv[k]
+			switch {
+			case !aok:
+				diffs = append(diffs, Difference{Path: childPath, Primary: "(missing)", Shadow: describe(k, bc)})
+			case !bok:
+				diffs = append(diffs, Difference{Path: childPath, Primary: describe(k, ac), Shadow: "(missing)"})
+			default:
+				diffs = diffJSON(diffs, childPath, k, ac, bc, ignore)
+			}
+			if len(diffs) >= maxDiffs {
+				return diffs
+			}
+		}
+		return diffs
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		if len(av) != len(bv) {
+			diffs = append(diffs, Difference{Path: path + ".length", Primary: strconv.Itoa(len(av)), Shadow: strconv.Itoa(len(bv))})
+		}
+		for i := 0; i < len(av) && i < len(bv); i++ {
+			diffs = diffJSON(diffs, fmt.Sprintf("%s[%d]", path, i), key, av[i], bv[i], ignore)
+			if len(diffs) >= maxDiffs {
+				return diffs
+			}
+		}
+		return diffs
+	default:
+		if fmt.Sprint(a) == fmt.Sprint(b) && fmt.Sprintf("%T", a) == fmt.Sprintf("%T", b) {
+			return diffs
+		}
+	}
+	return append(diffs, Difference{Path: path, Primary: describe(key, a), Shadow: describe(key, b)})
+}
+
+// describe renders a JSON value for a report. Objects and arrays are only
+// summarized; scalars are scrubbed.
+func describe(key string, v interface{}) string {
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		return fmt.Sprintf("object with %d keys", len(tv))
+	case []interface{}:
+		return fmt.Sprintf("array of %d", len(tv))
+	case nil:
+		return "null"
+	case string:
+		return scrubValue(key, tv)
+	default:
+		return scrubValue(key, fmt.Sprint(tv))
+	}
+}
+
+var (
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+	csrfInput   = regexp.MustCompile(`(?i)<input[^>]*csrf[^>]*>`)
+	nonceAttr   = regexp.MustCompile(`(?i)\snonce="[^"]*"`)
+	whitespace  = regexp.MustCompile(`\s+`)
+)
+
+// normalizeHTML drops what differs between any two renders of a page and
+// breaks it into one line per tag
+func normalizeHTML(body []byte) []string {
+	s := htmlComment.ReplaceAllString(string(body), "")
+	s = csrfInput.ReplaceAllString(s, "<input csrf>")
+	s = nonceAttr.ReplaceAllString(s, "")
+	s = whitespace.ReplaceAllString(s, " ")
+	s = strings.ReplaceAll(s, ">", ">\n")
+
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// diffLines reports the first differing line and the line counts; past
+// the first difference the rest usually just shifts
+func diffLines(diffs []Difference, a, b []string) []Difference {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var al, bl string
+		if i < len(a) {
+			al = a[i]
+		}
+		if i < len(b) {
+			bl = b[i]
+		}
+		if al != bl {
+			diffs = append(diffs, Difference{
+				Path:    fmt.Sprintf("line %d", i+1),
+				Primary: scrubValue("", al),
+				Shadow:  scrubValue("", bl),
+			})
+			break
+		}
+	}
+	if len(a) != len(b) {
+		diffs = append(diffs, Difference{Path: "lines", Primary: strconv.Itoa(len(a)), Shadow: strconv.Itoa(len(b))})
+	}
+	return diffs
+}
+
+// sensitiveKeys are substrings of JSON and query keys whose values are
+// personal data or credentials
+var sensitiveKeys = []string{"email", "phone", "password", "passwd", "token", "secret", "address", "name", "birth", "ssn", "session", "auth"}
+
+func sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	secretPattern = regexp.MustCompile(`[A-Za-z0-9+/_-]{32,}={0,2}`)
+	numberPattern = regexp.MustCompile(`\+?\d[\d\s().-]{6,}\d`)
+)
+
+// scrubValue redacts values under sensitive keys and masks emails, tokens
+// and phone or card numbers anywhere else, then truncates
+func scrubValue(key, v string) string {
+	if key != "" && sensitiveKey(key) {
+		return "[redacted]"
+	}
+	v = emailPattern.ReplaceAllString(v, "[email]")
+	v = jwtPattern.ReplaceAllString(v, "[token]")
+	v = secretPattern.ReplaceAllString(v, "[token]")
+	v = numberPattern.ReplaceAllString(v, "[number]")
+	if runes := []rune(v); len(runes) > maxValueLen {
+		v = string(runes[:maxValueLen]) + "…"
+	}
+	return v
+}
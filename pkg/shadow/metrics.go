@@ -0,0 +1,46 @@
+package shadow
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics count comparisons per route. A nil *Metrics records nothing.
+type Metrics struct {
+	results *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+}
+
+// NewMetrics registers the shadow metrics under namespace with reg
+func NewMetrics(namespace string, reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+	return &Metrics{
+		results: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "shadow",
+			Name:      "comparisons_total",
+			Help:      "Sampled requests by outcome: match, diff, error, dropped or skipped",
+		}, []string{"route", "result"}),
+		latency: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "shadow",
+			Name:      "target_duration_seconds",
+			Help:      "Time the shadow target took to answer a mirrored request",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route"}),
+	}
+}
+
+func (m *Metrics) result(route, result string) {
+	if m != nil {
+		m.results.WithLabelValues(route, result).Inc()
+	}
+}
+
+func (m *Metrics) duration(route string, elapsed time.Duration) {
+	if m != nil {
+		m.latency.WithLabelValues(route).Observe(elapsed.Seconds())
+	}
+}
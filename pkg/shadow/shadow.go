@@ -0,0 +1,322 @@
+// Package shadow mirrors a sample of read requests to another deployment,
+// typically staging, and compares its responses with the ones production
+// served, so regressions show up before the new version is rolled out.
+//
+// Only anonymous GET requests are mirrored. Credentials, cookies and all but
+// a few content negotiation headers stay behind, requests with personal
+// data in the query are skipped, and reported differences are scrubbed.
+package shadow
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alchemorsel/v3/pkg/svcauth"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// Header marks mirrored requests, so a deployment that also shadows does
+// not mirror them again
+const Header = "X-Alchemorsel-Shadow"
+
+// forwardedHeaders are the only request headers sent to the target
+var forwardedHeaders = []string{"Accept", "Accept-Language", "HX-Request", "HX-Target"}
+
+// maxReported caps the differences logged for one response
+const maxReported = 10
+
+// Options configure a Mirror
+type Options struct {
+	// Target is the base URL the sample is mirrored to
+	Target string
+	// SampleRate is the share of eligible requests mirrored, from 0 to 1
+	SampleRate float64
+	// Timeout bounds each mirrored request
+	Timeout time.Duration
+	// MaxBody skips responses larger than this many bytes
+	MaxBody int64
+	// Workers send mirrored requests; when all are busy new samples are dropped
+	Workers int
+	// Ignore are JSON keys left out of comparisons at any depth, such as
+	// timestamps and request IDs
+	Ignore []string
+	// Eligible, when set, further limits which requests may leave
+	// production, such as only those without a signed-in session
+	Eligible func(r *http.Request) bool
+}
+
+// Mirror records sampled responses and compares them with the target's
+type Mirror struct {
+	opts    Options
+	target  string
+	ignore  map[string]bool
+	client  *http.Client
+	metrics *Metrics
+	logger  *zap.Logger
+
+	queue  chan sample
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// sample is a served request waiting to be mirrored
+type sample struct {
+	route   string
+	uri     string
+	header  http.Header
+	primary Response
+}
+
+// New creates a mirror. metrics may be nil. Start must be called before
+// samples are compared.
+func New(opts Options, metrics *Metrics, logger *zap.Logger) *Mirror {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.MaxBody <= 0 {
+		opts.MaxBody = 1 << 20
+	}
+	ignore := make(map[string]bool, len(opts.Ignore))
+	for _, key := range opts.Ignore {
+		ignore[key] = true
+	}
+	return &Mirror{
+		opts:    opts,
+		target:  strings.TrimRight(opts.Target, "/"),
+		ignore:  ignore,
+		client:  &http.Client{Timeout: opts.Timeout},
+		metrics: metrics,
+		logger:  logger.Named("shadow"),
+		queue:   make(chan sample, opts.Workers*4),
+	}
+}
+
+// Start runs the workers until Stop
+func (m *Mirror) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	for i := 0; i < m.opts.Workers; i++ {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case s := <-m.queue:
+					m.compare(ctx, s)
+				}
+			}
+		}()
+	}
+}
+
+// Stop abandons queued samples and waits for the workers to finish
+func (m *Mirror) Stop(ctx context.Context) error {
+	if m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Middleware records the response to sampled requests and queues them for
+// comparison. It must sit inside any compression middleware so it sees the
+// plain body.
+func (m *Mirror) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.sampled(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &recorder{ResponseWriter: w, status: http.StatusOK, limit: m.opts.MaxBody}
+		next.ServeHTTP(recorder, r)
+
+		route := routePattern(r)
+		if recorder.overflow {
+			m.metrics.result(route, "skipped")
+			return
+		}
+		s := sample{
+			route:  route,
+			uri:    r.URL.RequestURI(),
+			header: forwardHeaders(r.Header),
+			primary: Response{
+				Status:      recorder.status,
+				ContentType: w.Header().Get("Content-Type"),
+				Body:        recorder.body.Bytes(),
+			},
+		}
+		select {
+		case m.queue <- s:
+		default:
+			m.metrics.result(route, "dropped")
+		}
+	})
+}
+
+// sampled picks the requests that may be mirrored
+func (m *Mirror) sampled(r *http.Request) bool {
+	if r.Method != http.MethodGet || r.Header.Get(Header) != "" {
+		return false
+	}
+	if r.Header.Get("Authorization") != "" || r.Header.Get(svcauth.HeaderIdentity) != "" {
+		return false
+	}
+	for key := range r.URL.Query() {
+		if sensitiveKey(key) {
+			return false
+		}
+	}
+	if m.opts.Eligible != nil && !m.opts.Eligible(r) {
+		return false
+	}
+	return rand.Float64() < m.opts.SampleRate
+}
+
+// compare sends a sample to the target and reports how the responses differ
+func (m *Mirror) compare(ctx context.Context, s sample) {
+	ctx, cancel := context.WithTimeout(ctx, m.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.target+s.uri, nil)
+	if err != nil {
+		m.metrics.result(s.route, "error")
+		return
+	}
+	req.Header = s.header
+
+	start := time.Now()
+	resp, err := m.client.Do(req)
+	if err != nil {
+		m.metrics.result(s.route, "error")
+		m.logger.Debug("Shadow request failed", zap.String("route", s.route), zap.Error(err))
+		return
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, m.opts.MaxBody+1))
+	m.metrics.duration(s.route, time.Since(start))
+	if err != nil || int64(len(body)) > m.opts.MaxBody {
+		m.metrics.result(s.route, "error")
+		return
+	}
+
+	diffs := Compare(s.primary, Response{
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+	}, m.ignore)
+	if len(diffs) == 0 {
+		m.metrics.result(s.route, "match")
+		return
+	}
+
+	m.metrics.result(s.route, "diff")
+	reported := diffs
+	if len(reported) > maxReported {
+		reported = reported[:maxReported]
+	}
+	m.logger.Warn("Shadow response differs",
+		zap.String("route", s.route),
+		zap.String("uri", ScrubURI(s.uri)),
+		zap.Int("primary_status", s.primary.Status),
+		zap.Int("shadow_status", resp.StatusCode),
+		zap.Int("differences", len(diffs)),
+		zap.Any("diff", reported),
+	)
+}
+
+// forwardHeaders copies the allowed headers and marks the request
+func forwardHeaders(in http.Header) http.Header {
+	out := make(http.Header, len(forwardedHeaders)+1)
+	for _, name := range forwardedHeaders {
+		if v := in.Get(name); v != "" {
+			out.Set(name, v)
+		}
+	}
+	out.Set(Header, "1")
+	return out
+}
+
+// routePattern names the matched route without its parameters
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return "unmatched"
+}
+
+// ScrubURI keeps the path and query keys of uri and scrubs the values
+func ScrubURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "[unparsable]"
+	}
+	query := u.Query()
+	for key, values := range query {
+		for i, v := range values {
+			values[i] = scrubValue(key, v)
+		}
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+// recorder passes the response through and keeps a copy of the body up to
+// a limit
+type recorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (w *recorder) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *recorder) Write(p []byte) (int, error) {
+	if !w.overflow {
+		if int64(w.body.Len()+len(p)) > w.limit {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(p)
+		}
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush passes through so streamed responses still stream
+func (w *recorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,111 @@
+package shadow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func jsonResponse(body string) Response {
+	return Response{Status: 200, ContentType: "application/json; charset=utf-8", Body: []byte(body)}
+}
+
+func TestCompareJSONIgnoresVolatileKeysAndScrubs(t *testing.T) {
+	ignore := map[string]bool{"request_id": true}
+	primary := jsonResponse(`{"request_id":"a","data":{"title":"Soup","author_email":"ada@example.com","tags":["x","y"],"note":"call +1 555 010 9999"}}`)
+
+	assert.Empty(t, Compare(primary, jsonResponse(`{"data":{"tags":["x","y"],"title":"Soup","author_email":"ada@example.com","note":"call +1 555 010 9999"},"request_id":"b"}`), ignore))
+
+	diffs := Compare(primary, jsonResponse(`{"request_id":"a","data":{"title":"Stew","author_email":"bob@example.com","tags":["x"],"note":"call +1 555 010 0000"}}`), ignore)
+	assert.Equal(t, []Difference{
+		{Path: "$.data.author_email", Primary: "[redacted]", Shadow: "[redacted]"},
+		{Path: "$.data.note", Primary: "call [number]", Shadow: "call [number]"},
+		{Path: "$.data.tags.length", Primary: "2", Shadow: "1"},
+		{Path: "$.data.title", Primary: "Soup", Shadow: "Stew"},
+	}, diffs)
+}
+
+func TestCompareHTMLIgnoresPerRenderTokens(t *testing.T) {
+	page := func(token, nonce, heading string) Response {
+		return Response{Status: 200, ContentType: "text/html; charset=utf-8", Body: []byte(`<html><!-- rendered in 3ms -->
+			<script nonce="` + nonce + `">init()</script>
+			<form><input type="hidden" name="csrf_token" value="` + token + `"></form>
+			<h1>` + heading + `</h1></html>`)}
+	}
+
+	assert.Empty(t, Compare(page("t1", "n1", "Recipes"), page("t2", "n2", "Recipes"), nil))
+	diffs := Compare(page("t1", "n1", "Recipes"), page("t2", "n2", "Recipes for ada@example.com"), nil)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, Difference{Path: "line 8", Primary: "Recipes</h1>", Shadow: "Recipes for [email]</h1>"}, diffs[0])
+
+	diffs = Compare(page("t", "n", "Recipes"), Response{Status: 500, ContentType: "application/json"}, nil)
+	assert.Equal(t, []Difference{
+		{Path: "status", Primary: "200", Shadow: "500"},
+		{Path: "content-type", Primary: "text/html", Shadow: "application/json"},
+	}, diffs)
+}
+
+func TestMirrorSendsOnlyAnonymousReadsWithoutCredentials(t *testing.T) {
+	var mu sync.Mutex
+	var mirrored []*http.Request
+	staging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		mirrored = append(mirrored, r)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"title":"Stew"}`))
+	}))
+	t.Cleanup(staging.Close)
+
+	metrics := NewMetrics("test", prometheus.NewRegistry())
+	mirror := New(Options{Target: staging.URL, SampleRate: 1, Workers: 1, Timeout: time.Second}, metrics, zap.NewNop())
+	mirror.Start()
+	t.Cleanup(func() { mirror.Stop(context.Background()) })
+
+	r := chi.NewRouter()
+	r.Use(mirror.Middleware)
+	r.Get("/recipes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"title":"Soup"}`))
+	})
+	r.Post("/recipes/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	send := func(method, target string, header http.Header) {
+		req := httptest.NewRequest(method, target, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	send("GET", "/recipes/1?lang=en", http.Header{"Cookie": {"alchemorsel-session=s"}, "Accept": {"application/json"}, "X-Forwarded-For": {"203.0.113.9"}})
+	send("GET", "/recipes/2", http.Header{"Authorization": {"Bearer t"}})
+	send("GET", "/recipes/3?email=ada@example.com", nil)
+	send("GET", "/recipes/4", http.Header{Header: {"1"}})
+	send("POST", "/recipes/5", nil)
+
+	diffs := metrics.results.WithLabelValues("/recipes/{id}", "diff")
+	require.Eventually(t, func() bool { return testutil.ToFloat64(diffs) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, mirrored, 1)
+	assert.Equal(t, "/recipes/1?lang=en", mirrored[0].URL.RequestURI())
+	assert.Equal(t, "application/json", mirrored[0].Header.Get("Accept"))
+	assert.Empty(t, mirrored[0].Header.Get("Cookie"))
+	assert.Empty(t, mirrored[0].Header.Get("X-Forwarded-For"))
+	assert.Equal(t, "1", mirrored[0].Header.Get(Header))
+}
+
+func TestScrubURI(t *testing.T) {
+	assert.Equal(t, "/api/v1/recipes?search=soup+for+%5Bemail%5D", ScrubURI("/api/v1/recipes?search=soup+for+ada@example.com"))
+}
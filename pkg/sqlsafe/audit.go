@@ -0,0 +1,122 @@
+package sqlsafe
+
+import (
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Finding is a reason a statement looks like it was built from input
+type Finding string
+
+const (
+	// FindingUnescapedLike is a LIKE on a bound pattern with no ESCAPE, so
+	// wildcards in the input act as wildcards
+	FindingUnescapedLike Finding = "unescaped_like"
+	// FindingStackedStatement is a second statement after a semicolon
+	FindingStackedStatement Finding = "stacked_statement"
+	// FindingComment is a comment in the statement text
+	FindingComment Finding = "comment"
+)
+
+var (
+	likePlaceholder  = regexp.MustCompile(`(?i)\bI?LIKE\s+(?:\?|\$\d+)(\s+ESCAPE\b)?`)
+	stackedStatement = regexp.MustCompile(`;\s*\S`)
+)
+
+// Audit reports what in sql suggests input was written into the statement
+// rather than bound. Quoted literals are ignored, so constants such as
+// NOT LIKE '%_pkey' pass.
+func Audit(sql string) []Finding {
+	text := stripLiterals(sql)
+	var findings []Finding
+	for _, m := range likePlaceholder.FindAllStringSubmatch(text, -1) {
+		if m[1] == "" {
+			findings = append(findings, FindingUnescapedLike)
+			break
+		}
+	}
+	if stackedStatement.MatchString(text) {
+		findings = append(findings, FindingStackedStatement)
+	}
+	if strings.Contains(text, "--") || strings.Contains(text, "/*") {
+		findings = append(findings, FindingComment)
+	}
+	return findings
+}
+
+// stripLiterals empties single-quoted literals, keeping the quotes
+func stripLiterals(sql string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+	inLiteral := false
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		if c != '\'' {
+			if !inLiteral {
+				b.WriteByte(c)
+			}
+			continue
+		}
+		// A doubled quote inside a literal is an escaped quote
+		if inLiteral && i+1 < len(sql) && sql[i+1] == '\'' {
+			i++
+			continue
+		}
+		inLiteral = !inLiteral
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// Auditor is a gorm plugin that logs a warning for every executed query
+// Audit has findings for. It does not block the query; the warning points
+// at the code to move onto bound parameters and the helpers in this
+// package.
+type Auditor struct {
+	logger *zap.Logger
+}
+
+// NewAuditor creates the query audit plugin
+func NewAuditor(logger *zap.Logger) *Auditor {
+	return &Auditor{logger: logger}
+}
+
+// Name implements gorm.Plugin
+func (a *Auditor) Name() string {
+	return "sqlsafe:audit"
+}
+
+// Initialize implements gorm.Plugin
+func (a *Auditor) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().After("gorm:query").Register("sqlsafe:audit", a.audit); err != nil {
+		return err
+	}
+	if err := callbacks.Row().After("gorm:row").Register("sqlsafe:audit", a.audit); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("sqlsafe:audit", a.audit)
+}
+
+func (a *Auditor) audit(db *gorm.DB) {
+	if db.Statement == nil {
+		return
+	}
+	sql := db.Statement.SQL.String()
+	findings := Audit(sql)
+	if len(findings) == 0 {
+		return
+	}
+	names := make([]string, len(findings))
+	for i, f := range findings {
+		names[i] = string(f)
+	}
+	a.logger.Warn("Query failed the safety audit",
+		zap.Strings("findings", names),
+		zap.String("table", db.Statement.Table),
+		zap.String("sql", sql),
+	)
+}
@@ -0,0 +1,36 @@
+// Package sqlsafe keeps user input out of SQL text. Pattern matches go
+// through Contains or Prefix with a Like clause, so the input is always a
+// bound parameter and its % and _ match themselves rather than anything.
+package sqlsafe
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes the LIKE wildcards in s with a backslash. Use it with
+// a clause from Like or ILike, which declare the backslash as the escape
+// character; SQLite has no default one.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// Contains is the lower-cased LIKE pattern matching s anywhere
+func Contains(s string) string {
+	return "%" + EscapeLike(strings.ToLower(s)) + "%"
+}
+
+// Prefix is the lower-cased LIKE pattern matching values starting with s
+func Prefix(s string) string {
+	return EscapeLike(strings.ToLower(s)) + "%"
+}
+
+// Like is a case-insensitive LIKE clause on column with one placeholder for
+// a pattern from Contains or Prefix. column must be a constant, never input.
+func Like(column string) string {
+	return "LOWER(" + column + `) LIKE ? ESCAPE '\'`
+}
+
+// ILike is Like for PostgreSQL-only queries
+func ILike(column string) string {
+	return column + ` ILIKE ? ESCAPE '\'`
+}
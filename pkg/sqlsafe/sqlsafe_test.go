@@ -0,0 +1,37 @@
+package sqlsafe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatternsEscapeWildcards(t *testing.T) {
+	assert.Equal(t, `%100\% rye%`, Contains("100% Rye"))
+	assert.Equal(t, `a\_b%`, Prefix("A_B"))
+	assert.Equal(t, `c:\\temp`, EscapeLike(`c:\temp`))
+	assert.Equal(t, `LOWER(title) LIKE ? ESCAPE '\'`, Like("title"))
+}
+
+func TestAudit(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		sql  string
+		want []Finding
+	}{
+		{"bound and escaped", "SELECT * FROM recipes WHERE " + Like("title") + " AND status = ?", nil},
+		{"postgres placeholder", "SELECT * FROM recipes WHERE " + ILike("title") + " OR title ILIKE $2 ESCAPE '\\'", nil},
+		{"constant pattern", "SELECT indexname FROM pg_indexes WHERE indexname NOT LIKE '%_pkey'", nil},
+		{"input in a literal", "SELECT * FROM recipes WHERE title = 'it''s; -- fine /* really */'", nil},
+		{"no escape", "SELECT * FROM recipes WHERE LOWER(title) LIKE ?", []Finding{FindingUnescapedLike}},
+		{"postgres no escape", "SELECT * FROM recipes WHERE title ILIKE $1", []Finding{FindingUnescapedLike}},
+		{"quote broken out", "SELECT * FROM users WHERE email = '' OR 1=1 --'", []Finding{FindingComment}},
+		{"stacked", "SELECT * FROM recipes WHERE id = ''; DROP TABLE recipes; '", []Finding{FindingStackedStatement}},
+		{"trailing semicolon", "SELECT 1;", nil},
+		{"block comment", "SELECT * FROM recipes WHERE id = 1 /**/ OR 1=1", []Finding{FindingComment}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Audit(tc.sql))
+		})
+	}
+}
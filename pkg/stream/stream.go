@@ -0,0 +1,251 @@
+// Package stream writes large result sets to an HTTP client row by row.
+//
+// Rows are encoded as they come off a database cursor and flushed in
+// batches, so memory stays flat whatever the size of the export. Writes
+// block while the client is slow to read, which in turn holds the cursor
+// back: the server never buffers ahead of the client. Each batch must reach
+// the client within StallTimeout, which replaces the server's write timeout
+// for the response, so a long export survives while one that stops reading
+// is cut off.
+//
+// A response cannot change its status once rows are on the wire. Writers
+// declare an X-Export-Status trailer that Close sets to complete or failed,
+// and the NDJSON writer also ends a failed export with an error line.
+package stream
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// StatusTrailer reports whether an export ran to the end
+const StatusTrailer = "X-Export-Status"
+
+// Status trailer values
+const (
+	StatusComplete = "complete"
+	StatusFailed   = "failed"
+)
+
+const (
+	defaultFlushEvery   = 500
+	defaultStallTimeout = 30 * time.Second
+	bufferSize          = 64 << 10
+)
+
+// Options tunes how often rows reach the client. Zero values pick the
+// defaults: 500 rows and 30s.
+type Options struct {
+	// FlushEvery is the number of rows between flushes
+	FlushEvery int
+	// StallTimeout is the longest one batch may take to reach the client
+	StallTimeout time.Duration
+	// Filename, when set, offers the export as a download
+	Filename string
+}
+
+// writer is the batching shared by the encoders. Nothing reaches the
+// client until the first flush or a full buffer, so a caller may still
+// answer with an error while Started is false.
+type writer struct {
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	contentType string
+	buf         *bufio.Writer
+	opts        Options
+	pending     int
+	rows        int
+	started     bool
+	now         func() time.Time
+}
+
+func newWriter(w http.ResponseWriter, contentType string, opts Options) *writer {
+	if opts.FlushEvery <= 0 {
+		opts.FlushEvery = defaultFlushEvery
+	}
+	if opts.StallTimeout <= 0 {
+		opts.StallTimeout = defaultStallTimeout
+	}
+	sw := &writer{
+		w:           w,
+		rc:          http.NewResponseController(w),
+		contentType: contentType,
+		opts:        opts,
+		now:         time.Now,
+	}
+	sw.buf = bufio.NewWriterSize(startWriter{sw}, bufferSize)
+	return sw
+}
+
+// startWriter sends the headers ahead of the first bytes to leave the buffer
+type startWriter struct {
+	sw *writer
+}
+
+func (s startWriter) Write(p []byte) (int, error) {
+	if err := s.sw.start(); err != nil {
+		return 0, err
+	}
+	return s.sw.w.Write(p)
+}
+
+func (sw *writer) start() error {
+	if sw.started {
+		return nil
+	}
+	sw.started = true
+	h := sw.w.Header()
+	h.Set("Content-Type", sw.contentType)
+	h.Set("Cache-Control", "no-store")
+	h.Set("X-Accel-Buffering", "no")
+	h.Set("Trailer", StatusTrailer)
+	if sw.opts.Filename != "" {
+		h.Set("Content-Disposition", `attachment; filename="`+sw.opts.Filename+`"`)
+	}
+	sw.w.WriteHeader(http.StatusOK)
+	return sw.extendDeadline()
+}
+
+// extendDeadline gives the next batch StallTimeout to reach the client.
+// Writers that cannot reach the connection keep the server's timeout.
+func (sw *writer) extendDeadline() error {
+	err := sw.rc.SetWriteDeadline(sw.now().Add(sw.opts.StallTimeout))
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
+
+// row counts a written row and flushes after every FlushEvery
+func (sw *writer) row() error {
+	sw.rows++
+	sw.pending++
+	if sw.pending < sw.opts.FlushEvery {
+		return nil
+	}
+	return sw.flush()
+}
+
+func (sw *writer) flush() error {
+	sw.pending = 0
+	if err := sw.buf.Flush(); err != nil {
+		return err
+	}
+	if err := sw.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return sw.extendDeadline()
+}
+
+// finish flushes what is left, sets the status trailer and hands the
+// connection back to the server's own timeouts
+func (sw *writer) finish(failed bool) error {
+	if err := sw.start(); err != nil {
+		return err
+	}
+	err := sw.flush()
+	status := StatusComplete
+	if failed {
+		status = StatusFailed
+	}
+	sw.w.Header().Set(StatusTrailer, status)
+	if clearErr := sw.rc.SetWriteDeadline(time.Time{}); err == nil && !errors.Is(clearErr, http.ErrNotSupported) {
+		err = clearErr
+	}
+	return err
+}
+
+// Started reports whether any bytes have been sent to the client
+func (sw *writer) Started() bool {
+	return sw.started
+}
+
+// Rows returns the number of rows written so far
+func (sw *writer) Rows() int {
+	return sw.rows
+}
+
+// NDJSONWriter writes one JSON document per line
+type NDJSONWriter struct {
+	*writer
+	enc *json.Encoder
+}
+
+// NewNDJSON creates an application/x-ndjson writer on w
+func NewNDJSON(w http.ResponseWriter, opts Options) *NDJSONWriter {
+	sw := newWriter(w, "application/x-ndjson", opts)
+	return &NDJSONWriter{writer: sw, enc: json.NewEncoder(sw.buf)}
+}
+
+// Write encodes v as the next line
+func (w *NDJSONWriter) Write(v interface{}) error {
+	if err := w.enc.Encode(v); err != nil {
+		return err
+	}
+	return w.row()
+}
+
+// Close ends the export. A non-nil failure adds a final error line so a
+// reader without trailer support still sees the export was cut short.
+func (w *NDJSONWriter) Close(failure error) error {
+	if failure != nil {
+		if err := w.enc.Encode(map[string]string{"error": "export interrupted"}); err != nil {
+			return err
+		}
+	}
+	return w.finish(failure != nil)
+}
+
+// CSVWriter writes a header row followed by one record per row
+type CSVWriter struct {
+	*writer
+	csv    *csv.Writer
+	header []string
+	headed bool
+}
+
+// NewCSV creates a text/csv writer on w that starts with header
+func NewCSV(w http.ResponseWriter, header []string, opts Options) *CSVWriter {
+	sw := newWriter(w, "text/csv; charset=utf-8", opts)
+	return &CSVWriter{writer: sw, csv: csv.NewWriter(sw.buf), header: header}
+}
+
+// Write adds one record
+func (w *CSVWriter) Write(record []string) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	if err := w.csv.Write(record); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.row()
+}
+
+func (w *CSVWriter) writeHeader() error {
+	if w.headed {
+		return nil
+	}
+	w.headed = true
+	return w.csv.Write(w.header)
+}
+
+// Close ends the export, marking it failed when failure is non-nil. An
+// export without rows still gets its header.
+func (w *CSVWriter) Close(failure error) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.finish(failure != nil)
+}
@@ -0,0 +1,49 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNDJSONFlushesInBatchesAndReportsCompletion(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := NewNDJSON(rec, Options{FlushEvery: 2})
+
+	require.NoError(t, w.Write(map[string]int{"n": 1}))
+	assert.False(t, w.Started(), "an error can still replace the export")
+	assert.Zero(t, rec.Body.Len())
+	require.NoError(t, w.Write(map[string]int{"n": 2}))
+	assert.True(t, w.Started())
+	assert.Equal(t, "{\"n\":1}\n{\"n\":2}\n", rec.Body.String())
+
+	require.NoError(t, w.Write(map[string]int{"n": 3}))
+	require.NoError(t, w.Close(nil))
+	assert.Equal(t, 3, w.Rows())
+
+	res := rec.Result()
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, "{\"n\":1}\n{\"n\":2}\n{\"n\":3}\n", string(body))
+	assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+	assert.Equal(t, StatusComplete, res.Trailer.Get(StatusTrailer))
+}
+
+func TestCSVMarksAFailedExport(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := NewCSV(rec, []string{"id", "title"}, Options{})
+	require.NoError(t, w.Write([]string{"1", "Lemon, Bars"}))
+	require.NoError(t, w.Close(errors.New("connection reset")))
+
+	res := rec.Result()
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, "id,title\n1,\"Lemon, Bars\"\n", string(body))
+	assert.Equal(t, StatusFailed, res.Trailer.Get(StatusTrailer))
+
+	empty := httptest.NewRecorder()
+	require.NoError(t, NewCSV(empty, []string{"id"}, Options{}).Close(nil))
+	assert.Equal(t, "id\n", empty.Body.String(), "an empty export keeps its header")
+}
@@ -0,0 +1,91 @@
+package svcauth
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "a-shared-secret-of-at-least-32-bytes"
+
+func signedRequest(t *testing.T, signer *Signer, method, target string, body []byte, identity *Identity) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	require.NoError(t, signer.Sign(req, body, identity))
+	return req
+}
+
+func TestVerifyAcceptsSignedIdentity(t *testing.T) {
+	signer := NewSigner("web", secret)
+	verifier := NewVerifier(30*time.Second, secret)
+	body := []byte(`{"title":"Lemon Bars"}`)
+
+	req := signedRequest(t, signer, "POST", "/api/v1/recipes?draft=1", body, &Identity{UserID: "u-1", Email: "ada@example.com"})
+	caller, err := verifier.Verify(req)
+	require.NoError(t, err)
+	assert.Equal(t, "web", caller.Service)
+	assert.Equal(t, &Identity{UserID: "u-1", Email: "ada@example.com"}, caller.Identity)
+
+	read, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, read, "the body is still there for the handler")
+
+	anonymous := signedRequest(t, signer, "GET", "/api/v1/recipes:batchGet?ids=a,b", nil, nil)
+	caller, err = verifier.Verify(anonymous)
+	require.NoError(t, err)
+	assert.Nil(t, caller.Identity)
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	signer := NewSigner("web", secret)
+	verifier := NewVerifier(30*time.Second, secret)
+	identity := &Identity{UserID: "u-1"}
+
+	tests := map[string]func(r *http.Request){
+		"path":  func(r *http.Request) { r.URL.Path = "/api/v1/admin/config" },
+		"query": func(r *http.Request) { r.URL.RawQuery = "ids=c" },
+		"body":  func(r *http.Request) { r.Body = io.NopCloser(bytes.NewReader([]byte(`{"title":"x"}`))) },
+		"identity": func(r *http.Request) {
+			other := signedRequest(t, signer, "GET", "/", nil, &Identity{UserID: "admin"})
+			r.Header.Set(HeaderIdentity, other.Header.Get(HeaderIdentity))
+		},
+		"identity removed": func(r *http.Request) { r.Header.Del(HeaderIdentity) },
+	}
+	for name, tamper := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := signedRequest(t, signer, "POST", "/api/v1/recipes?ids=a", []byte(`{}`), identity)
+			tamper(req)
+			_, err := verifier.Verify(req)
+			assert.ErrorIs(t, err, ErrInvalid)
+		})
+	}
+
+	_, err := NewVerifier(30*time.Second, "some-other-secret-entirely-different").
+		Verify(signedRequest(t, signer, "GET", "/", nil, identity))
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	_, err = verifier.Verify(httptest.NewRequest("GET", "/", nil))
+	assert.ErrorIs(t, err, ErrUnsigned)
+}
+
+func TestVerifyRejectsStaleSignatures(t *testing.T) {
+	signer := NewSigner("web", secret)
+	signer.now = func() time.Time { return time.Now().Add(-time.Minute) }
+
+	_, err := NewVerifier(30*time.Second, secret).Verify(signedRequest(t, signer, "GET", "/", nil, nil))
+	assert.ErrorIs(t, err, ErrExpired)
+}
+
+func TestVerifyAcceptsPreviousSecretDuringRotation(t *testing.T) {
+	old := NewSigner("web", secret)
+	verifier := NewVerifier(30*time.Second, "the-new-shared-secret-of-32-bytes!!", secret)
+
+	_, err := verifier.Verify(signedRequest(t, old, "GET", "/", nil, nil))
+	assert.NoError(t, err)
+}
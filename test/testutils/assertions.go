@@ -3,7 +3,6 @@ package testutils
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
@@ -28,49 +27,38 @@ func NewRecipeAssertions(t *testing.T) *RecipeAssertions {
 }
 
 // ValidRecipe asserts that a recipe is valid for publishing
-func (ra *RecipeAssertions) ValidRecipe(recipe *recipe.Recipe, msgAndArgs ...interface{}) {
-	require.NotNil(ra.t, recipe, "Recipe should not be nil")
-	assert.NotEqual(ra.t, uuid.Nil, recipe.ID(), "Recipe should have a valid ID")
-	assert.NotEmpty(ra.t, recipe.Title(), "Recipe should have a title")
-	
+func (ra *RecipeAssertions) ValidRecipe(r *recipe.Recipe, msgAndArgs ...interface{}) {
+	require.NotNil(ra.t, r, "Recipe should not be nil")
+	assert.NotEqual(ra.t, uuid.Nil, r.ID(), "Recipe should have a valid ID")
+	assert.NotEmpty(ra.t, r.Title(), "Recipe should have a title")
+
 	// Check that recipe can be published (has required fields)
-	err := recipe.Publish()
+	err := r.Publish()
 	if err != nil {
 		// If it fails due to status, it means it's already published or archived
 		// which is fine for this validation
 		if err != recipe.ErrInvalidStatusTransition {
-			assert.NoError(ra.t, err, "Recipe should be valid for publishing")
+			assert.NoError(ra.t, err, append([]interface{}{"Recipe should be valid for publishing"}, msgAndArgs...)...)
 		}
 	}
 }
 
-// RecipeHasIngredients asserts that a recipe has ingredients
-func (ra *RecipeAssertions) RecipeHasIngredients(recipe *recipe.Recipe, expectedCount int, msgAndArgs ...interface{}) {
-	require.NotNil(ra.t, recipe, "Recipe should not be nil")
-	// Note: We would need to add a public method to Recipe to get ingredients count
-	// For now, we'll check indirectly by trying to publish
-	err := recipe.Publish()
-	if err == recipe.ErrNoIngredients {
-		assert.Fail(ra.t, "Recipe should have ingredients", msgAndArgs...)
-	}
+// RecipeHasIngredients asserts that a recipe has at least the given number of ingredients
+func (ra *RecipeAssertions) RecipeHasIngredients(r *recipe.Recipe, expectedCount int, msgAndArgs ...interface{}) {
+	require.NotNil(ra.t, r, "Recipe should not be nil")
+	assert.GreaterOrEqual(ra.t, len(r.Ingredients()), expectedCount, msgAndArgs...)
 }
 
-// RecipeHasInstructions asserts that a recipe has instructions
-func (ra *RecipeAssertions) RecipeHasInstructions(recipe *recipe.Recipe, expectedCount int, msgAndArgs ...interface{}) {
-	require.NotNil(ra.t, recipe, "Recipe should not be nil")
-	// Note: We would need to add a public method to Recipe to get instructions count
-	// For now, we'll check indirectly by trying to publish
-	err := recipe.Publish()
-	if err == recipe.ErrNoInstructions {
-		assert.Fail(ra.t, "Recipe should have instructions", msgAndArgs...)
-	}
+// RecipeHasInstructions asserts that a recipe has at least the given number of instructions
+func (ra *RecipeAssertions) RecipeHasInstructions(r *recipe.Recipe, expectedCount int, msgAndArgs ...interface{}) {
+	require.NotNil(ra.t, r, "Recipe should not be nil")
+	assert.GreaterOrEqual(ra.t, len(r.Instructions()), expectedCount, msgAndArgs...)
 }
 
 // RecipeStatus asserts the recipe status
-func (ra *RecipeAssertions) RecipeStatus(recipe *recipe.Recipe, expectedStatus recipe.RecipeStatus, msgAndArgs ...interface{}) {
-	require.NotNil(ra.t, recipe, "Recipe should not be nil")
-	// Note: We would need a public Status() method on Recipe
-	// For demonstration, we'll use reflection or implement the interface
+func (ra *RecipeAssertions) RecipeStatus(r *recipe.Recipe, expectedStatus recipe.RecipeStatus, msgAndArgs ...interface{}) {
+	require.NotNil(ra.t, r, "Recipe should not be nil")
+	assert.Equal(ra.t, expectedStatus, r.Status(), msgAndArgs...)
 }
 
 // UserAssertions provides user-specific assertion methods
@@ -88,7 +76,7 @@ func (ua *UserAssertions) ValidUser(u *user.User, msgAndArgs ...interface{}) {
 	require.NotNil(ua.t, u, "User should not be nil")
 	assert.NotEqual(ua.t, uuid.Nil, u.ID(), "User should have a valid ID")
 	assert.NotEmpty(ua.t, u.Email(), "User should have an email")
-	assert.NotEmpty(ua.t, u.Username(), "User should have a username")
+	assert.NotEmpty(ua.t, u.Name(), "User should have a name")
 }
 
 // UserEmail asserts the user's email
@@ -97,10 +85,10 @@ func (ua *UserAssertions) UserEmail(u *user.User, expectedEmail string, msgAndAr
 	assert.Equal(ua.t, expectedEmail, u.Email(), msgAndArgs...)
 }
 
-// UserUsername asserts the user's username
-func (ua *UserAssertions) UserUsername(u *user.User, expectedUsername string, msgAndArgs ...interface{}) {
+// UserName asserts the user's display name
+func (ua *UserAssertions) UserName(u *user.User, expectedName string, msgAndArgs ...interface{}) {
 	require.NotNil(ua.t, u, "User should not be nil")
-	assert.Equal(ua.t, expectedUsername, u.Username(), msgAndArgs...)
+	assert.Equal(ua.t, expectedName, u.Name(), msgAndArgs...)
 }
 
 // HTTPAssertions provides HTTP-specific assertion methods
@@ -119,8 +107,9 @@ func (ha *HTTPAssertions) StatusCode(resp *http.Response, expectedCode int, msgA
 	assert.Equal(ha.t, expectedCode, resp.StatusCode, msgAndArgs...)
 }
 
-// JSONResponse asserts that the response is valid JSON and unmarshals it
-func (ha *HTTPAssertions) JSONResponse(resp *http.Response, target interface{}, msgAndArgs ...interface{}) {
+// JSONResponse asserts that the response is valid JSON and unmarshals it,
+// returning the decoding error
+func (ha *HTTPAssertions) JSONResponse(resp *http.Response, target interface{}, msgAndArgs ...interface{}) error {
 	require.NotNil(ha.t, resp, "Response should not be nil")
 	
 	contentType := resp.Header.Get("Content-Type")
@@ -130,6 +119,7 @@ func (ha *HTTPAssertions) JSONResponse(resp *http.Response, target interface{},
 	decoder := json.NewDecoder(resp.Body)
 	err := decoder.Decode(target)
 	assert.NoError(ha.t, err, "Response should be valid JSON")
+	return err
 }
 
 // ErrorResponse asserts that the response contains an error
@@ -5,16 +5,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/alchemorsel/v3/internal/infrastructure/config"
 	"github.com/alchemorsel/v3/internal/infrastructure/persistence/migrations"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/docker/go-connections/nat"
 	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -46,7 +44,7 @@ type DatabaseConfig struct {
 // DefaultDatabaseConfig returns the default test database configuration
 func DefaultDatabaseConfig() DatabaseConfig {
 	return DatabaseConfig{
-		Image:    "postgres:15-alpine",
+		Image:    "pgvector/pgvector:pg15",
 		Database: "alchemorsel_test",
 		Username: "test_user",
 		Password: "test_password",
@@ -64,7 +62,7 @@ func SetupTestDatabaseWithConfig(t *testing.T, cfg DatabaseConfig) *TestDatabase
 	ctx := context.Background()
 
 	// Create postgres container
-	postgres, err := testcontainers.GenericContainer(ctx,
+	container, err := testcontainers.GenericContainer(ctx,
 		testcontainers.GenericContainerRequest{
 			ContainerRequest: testcontainers.ContainerRequest{
 				Image:        cfg.Image,
@@ -79,7 +77,7 @@ func SetupTestDatabaseWithConfig(t *testing.T, cfg DatabaseConfig) *TestDatabase
 					wait.ForLog("database system is ready to accept connections").
 						WithOccurrence(2).
 						WithStartupTimeout(60*time.Second),
-					wait.ForSQL(cfg.Port+"/tcp", "postgres", func(host string, port nat.Port) string {
+					wait.ForSQL(nat.Port(cfg.Port+"/tcp"), "postgres", func(host string, port nat.Port) string {
 						return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 							cfg.Username, cfg.Password, host, port.Port(), cfg.Database)
 					}),
@@ -93,10 +91,10 @@ func SetupTestDatabaseWithConfig(t *testing.T, cfg DatabaseConfig) *TestDatabase
 	require.NoError(t, err, "Failed to start postgres container")
 
 	// Get connection details
-	host, err := postgres.Host(ctx)
+	host, err := container.Host(ctx)
 	require.NoError(t, err)
 
-	port, err := postgres.MappedPort(ctx, cfg.Port)
+	port, err := container.MappedPort(ctx, nat.Port(cfg.Port+"/tcp"))
 	require.NoError(t, err)
 
 	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
@@ -129,7 +127,7 @@ func SetupTestDatabaseWithConfig(t *testing.T, cfg DatabaseConfig) *TestDatabase
 	require.NoError(t, err, "Failed to create pgx pool")
 
 	testDB := &TestDatabase{
-		Container: postgres,
+		Container: container,
 		DB:        db,
 		GormDB:    gormDB,
 		PgxPool:   pgxPool,
@@ -145,36 +143,25 @@ func SetupTestDatabaseWithConfig(t *testing.T, cfg DatabaseConfig) *TestDatabase
 	return testDB
 }
 
-// RunMigrations runs database migrations on the test database
+// RunMigrations applies the embedded SQL migrations to the test database
 func (td *TestDatabase) RunMigrations() error {
-	driver, err := postgres.WithInstance(td.DB, &postgres.Config{})
+	migrator, err := migrations.New(td.DB, zap.NewNop())
 	if err != nil {
-		return fmt.Errorf("failed to create postgres driver: %w", err)
+		return fmt.Errorf("failed to create migrator: %w", err)
 	}
+	defer migrator.Close()
 
-	// Get migration path
-	migrationPath := "file://" + filepath.Join("../../internal/infrastructure/persistence/migrations/sql")
-	
-	m, err := migrate.NewWithDatabaseInstance(migrationPath, "postgres", driver)
-	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
-	}
-
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %w", err)
-	}
-
-	return nil
+	return migrator.Up()
 }
 
 // SeedTestData inserts test data into the database
 func (td *TestDatabase) SeedTestData() error {
 	// Insert test users
 	_, err := td.DB.Exec(`
-		INSERT INTO users (id, email, password_hash, username, created_at, updated_at)
+		INSERT INTO users (id, email, password_hash, name, created_at, updated_at)
 		VALUES 
-			('550e8400-e29b-41d4-a716-446655440001', 'test@example.com', '$2a$10$hash', 'testuser', NOW(), NOW()),
-			('550e8400-e29b-41d4-a716-446655440002', 'chef@example.com', '$2a$10$hash', 'chefuser', NOW(), NOW())
+			('550e8400-e29b-41d4-a716-446655440001', 'test@example.com', '$2a$10$hash', 'Test User', NOW(), NOW()),
+			('550e8400-e29b-41d4-a716-446655440002', 'chef@example.com', '$2a$10$hash', 'Chef User', NOW(), NOW())
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to seed users: %w", err)
@@ -256,8 +243,8 @@ func (suite *TestDBSuite) SetupSuite(t *testing.T) {
 		Database: config.DatabaseConfig{
 			Host:            "localhost",
 			Port:            5432,
-			Name:            "alchemorsel_test",
-			User:            "test_user",
+			Database:        "alchemorsel_test",
+			Username:        "test_user",
 			Password:        "test_password",
 			SSLMode:         "disable",
 			MaxOpenConns:    10,
@@ -296,13 +283,13 @@ func NewDatabaseHelper(db *TestDatabase) *DatabaseHelper {
 }
 
 // CreateTestUser creates a test user and returns the ID
-func (h *DatabaseHelper) CreateTestUser(email, username string) (string, error) {
+func (h *DatabaseHelper) CreateTestUser(email, name string) (string, error) {
 	userID := "550e8400-e29b-41d4-a716-" + fmt.Sprintf("%012d", time.Now().UnixNano()%1000000000000)
 	
 	_, err := h.db.DB.Exec(`
-		INSERT INTO users (id, email, password_hash, username, created_at, updated_at)
+		INSERT INTO users (id, email, password_hash, name, created_at, updated_at)
 		VALUES ($1, $2, '$2a$04$hash', $3, NOW(), NOW())
-	`, userID, email, username)
+	`, userID, email, name)
 	
 	return userID, err
 }
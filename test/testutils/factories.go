@@ -51,9 +51,9 @@ func NewRecipeBuilder() *RecipeBuilder {
 		authorID:    uuid.New(),
 		ingredients: []recipe.Ingredient{},
 		instructions: []recipe.Instruction{},
-		cuisine:     recipe.CuisineItalian,
-		category:    recipe.CategoryMainCourse,
-		difficulty:  recipe.DifficultyMedium,
+		cuisine:     recipe.CuisineTypeItalian,
+		category:    recipe.CategoryTypeMainCourse,
+		difficulty:  recipe.DifficultyLevelMedium,
 		prepTime:    15 * time.Minute,
 		cookTime:    30 * time.Minute,
 		servings:    4,
@@ -172,13 +172,13 @@ func (rb *RecipeBuilder) BuildValid() (*recipe.Recipe, error) {
 			{
 				ID:       uuid.New(),
 				Name:     "Spaghetti",
-				Quantity: 1.0,
+				Amount:   1.0,
 				Unit:     "lb",
 			},
 			{
 				ID:       uuid.New(),
 				Name:     "Tomato Sauce",
-				Quantity: 2.0,
+				Amount:   2.0,
 				Unit:     "cups",
 			},
 		})
@@ -187,13 +187,11 @@ func (rb *RecipeBuilder) BuildValid() (*recipe.Recipe, error) {
 	if len(rb.instructions) == 0 {
 		rb.WithInstructions([]recipe.Instruction{
 			{
-				ID:          uuid.New(),
 				StepNumber:  1,
 				Description: "Boil water in a large pot",
 				Duration:    5 * time.Minute,
 			},
 			{
-				ID:          uuid.New(),
 				StepNumber:  2,
 				Description: "Cook spaghetti according to package directions",
 				Duration:    10 * time.Minute,
@@ -234,38 +232,35 @@ func (rf *RecipeFactory) CreateItalianRecipe() (*recipe.Recipe, error) {
 		{
 			ID:       uuid.New(),
 			Name:     "Spaghetti",
-			Quantity: 1.0,
+			Amount:   1.0,
 			Unit:     "lb",
 		},
 		{
 			ID:       uuid.New(),
 			Name:     "Parmesan Cheese",
-			Quantity: 0.5,
+			Amount:   0.5,
 			Unit:     "cup",
 		},
 		{
 			ID:       uuid.New(),
 			Name:     "Extra Virgin Olive Oil",
-			Quantity: 3.0,
+			Amount:   3.0,
 			Unit:     "tbsp",
 		},
 	}
 
 	instructions := []recipe.Instruction{
 		{
-			ID:          uuid.New(),
 			StepNumber:  1,
 			Description: "Bring a large pot of salted water to boil",
 			Duration:    5 * time.Minute,
 		},
 		{
-			ID:          uuid.New(),
 			StepNumber:  2,
 			Description: "Cook spaghetti al dente",
 			Duration:    10 * time.Minute,
 		},
 		{
-			ID:          uuid.New(),
 			StepNumber:  3,
 			Description: "Toss with olive oil and cheese",
 			Duration:    2 * time.Minute,
@@ -275,10 +270,10 @@ func (rf *RecipeFactory) CreateItalianRecipe() (*recipe.Recipe, error) {
 	return NewRecipeBuilder().
 		WithTitle("Spaghetti Aglio e Olio").
 		WithDescription("A classic Italian pasta dish with garlic and olive oil").
-		WithCuisine(recipe.CuisineItalian).
+		WithCuisine(recipe.CuisineTypeItalian).
 		WithIngredients(ingredients).
 		WithInstructions(instructions).
-		WithDifficulty(recipe.DifficultyEasy).
+		WithDifficulty(recipe.DifficultyLevelEasy).
 		WithTimings(10*time.Minute, 15*time.Minute).
 		WithServings(4).
 		WithTags([]string{"italian", "pasta", "quick"}).
@@ -291,16 +286,15 @@ func (rf *RecipeFactory) CreateComplexRecipe() (*recipe.Recipe, error) {
 	for i := 0; i < 10; i++ {
 		ingredients = append(ingredients, recipe.Ingredient{
 			ID:       uuid.New(),
-			Name:     rf.faker.Food(),
-			Quantity: rf.faker.Float32Range(0.5, 3.0),
-			Unit:     rf.randomUnit(),
+			Name:     rf.faker.Vegetable(),
+			Amount:   rf.faker.Float64Range(0.5, 3.0),
+			Unit:     recipe.MeasurementUnit(rf.randomUnit()),
 		})
 	}
 
 	instructions := make([]recipe.Instruction, 0, 8)
 	for i := 0; i < 8; i++ {
 		instructions = append(instructions, recipe.Instruction{
-			ID:          uuid.New(),
 			StepNumber:  i + 1,
 			Description: rf.faker.Sentence(8),
 			Duration:    time.Duration(rf.faker.IntRange(5, 30)) * time.Minute,
@@ -312,7 +306,7 @@ func (rf *RecipeFactory) CreateComplexRecipe() (*recipe.Recipe, error) {
 		WithDescription(rf.faker.Paragraph(3, 4, 6, " ")).
 		WithIngredients(ingredients).
 		WithInstructions(instructions).
-		WithDifficulty(recipe.DifficultyHard).
+		WithDifficulty(recipe.DifficultyLevelHard).
 		WithTimings(45*time.Minute, 90*time.Minute).
 		WithServings(rf.faker.IntRange(4, 8)).
 		WithTags([]string{"complex", "gourmet", "special-occasion"}).
@@ -459,7 +453,7 @@ type TestDataSet struct {
 // CreateTestDataSet creates a related set of test data
 func CreateTestDataSet(userCount, recipeCount int) (*TestDataSet, error) {
 	userFactory := NewUserFactory(time.Now().UnixNano())
-	recipeFactory := NewRecipeFactory(time.Now().UnixNano())
+
 
 	// Create users
 	users := make([]*user.User, 0, userCount)
@@ -9,7 +9,7 @@ import (
 
 	"github.com/alchemorsel/v3/internal/domain/recipe"
 	"github.com/alchemorsel/v3/internal/domain/user"
-	"github.com/alchemorsel/v3/internal/ports/outbound"
+	apperrors "github.com/alchemorsel/v3/pkg/errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -104,7 +104,7 @@ func (m *MockRecipeRepository) SetupStandardMockBehavior() {
 	
 	// FindByID returns recipe not found by default
 	m.On("FindByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).
-		Return((*recipe.Recipe)(nil), outbound.ErrRecipeNotFound)
+		Return((*recipe.Recipe)(nil), recipe.ErrRecipeNotFound)
 	
 	// Other methods return empty results
 	m.On("FindByAuthorID", mock.Anything, mock.AnythingOfType("uuid.UUID"), mock.AnythingOfType("int"), mock.AnythingOfType("int")).
@@ -168,6 +168,12 @@ func (m *MockUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.
 	return args.Get(0).(*user.User), args.Error(1)
 }
 
+// FindByIDs finds users by multiple IDs
+func (m *MockUserRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*user.User, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
 // FindByEmail finds a user by email
 func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
 	args := m.Called(ctx, email)
@@ -201,15 +207,15 @@ func (m *MockUserRepository) SetupStandardMockBehavior() {
 	
 	// FindByID returns user not found by default
 	m.On("FindByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).
-		Return((*user.User)(nil), outbound.ErrUserNotFound)
+		Return((*user.User)(nil), apperrors.NewNotFoundError("user"))
 	
 	// FindByEmail returns user not found by default
 	m.On("FindByEmail", mock.Anything, mock.AnythingOfType("string")).
-		Return((*user.User)(nil), outbound.ErrUserNotFound)
+		Return((*user.User)(nil), apperrors.NewNotFoundError("user"))
 	
 	// FindByUsername returns user not found by default
 	m.On("FindByUsername", mock.Anything, mock.AnythingOfType("string")).
-		Return((*user.User)(nil), outbound.ErrUserNotFound)
+		Return((*user.User)(nil), apperrors.NewNotFoundError("user"))
 	
 	// Delete always succeeds
 	m.On("Delete", mock.Anything, mock.AnythingOfType("uuid.UUID")).
@@ -267,25 +273,23 @@ func (m *MockAIService) SetupStandardMockBehavior() {
 			{
 				ID:       uuid.New(),
 				Name:     "Spaghetti",
-				Quantity: 1.0,
+				Amount:   1.0,
 				Unit:     "lb",
 			},
 			{
 				ID:       uuid.New(),
 				Name:     "Tomato Sauce",
-				Quantity: 2.0,
+				Amount:   2.0,
 				Unit:     "cups",
 			},
 		},
 		Instructions: []recipe.Instruction{
 			{
-				ID:          uuid.New(),
 				StepNumber:  1,
 				Description: "Boil water",
 				Duration:    5 * time.Minute,
 			},
 			{
-				ID:          uuid.New(),
 				StepNumber:  2,
 				Description: "Cook pasta",
 				Duration:    10 * time.Minute,
@@ -294,8 +298,8 @@ func (m *MockAIService) SetupStandardMockBehavior() {
 		PrepTime:   10 * time.Minute,
 		CookTime:   15 * time.Minute,
 		Servings:   4,
-		Cuisine:    recipe.CuisineItalian,
-		Difficulty: recipe.DifficultyEasy,
+		Cuisine:    recipe.CuisineTypeItalian,
+		Difficulty: recipe.DifficultyLevelEasy,
 	}
 
 	// Standard nutrition info
@@ -308,11 +312,6 @@ func (m *MockAIService) SetupStandardMockBehavior() {
 		Sugar:           10.0,
 		Sodium:          800.0,
 		Cholesterol:     25.0,
-		ServingSize:     "1 portion",
-		VitaminA:        20.0,
-		VitaminC:        15.0,
-		Calcium:         10.0,
-		Iron:            8.0,
 	}
 
 	// Setup mock responses
@@ -327,7 +326,7 @@ func (m *MockAIService) SetupStandardMockBehavior() {
 			{
 				ID:       uuid.New(),
 				Name:     "Suggested Ingredient",
-				Quantity: 1.0,
+				Amount:   1.0,
 				Unit:     "cup",
 			},
 		}, nil)